@@ -33,7 +33,10 @@ import (
 	"tailscale.com/util/clientmetric"
 
 	"cdr.dev/slog"
+	"github.com/coder/coder/v2/agent/agentcgroup"
+	"github.com/coder/coder/v2/agent/agentcontainers"
 	"github.com/coder/coder/v2/agent/agentexec"
+	"github.com/coder/coder/v2/agent/agentrecord"
 	"github.com/coder/coder/v2/agent/agentscripts"
 	"github.com/coder/coder/v2/agent/agentssh"
 	"github.com/coder/coder/v2/agent/proto"
@@ -82,11 +85,25 @@ type Options struct {
 	ServiceBannerRefreshInterval time.Duration
 	BlockFileTransfer            bool
 	Execer                       agentexec.Execer
+	// DevcontainerDirs are project directories that may contain a
+	// devcontainer.json. Relative paths are resolved against the
+	// workspace directory of the agent.
+	DevcontainerDirs []string
+	// Cgroups places SSH sessions and scripts in separate cgroups, which
+	// may have resource limits. The usage of each group is reported in the
+	// stats of the agent. If nil, processes stay in the cgroup of the agent.
+	Cgroups *agentcgroup.Manager
+	// WorkloadIdentityTokenFile is the path the agent keeps a workload
+	// identity token in. Tokens are only fetched if it is set.
+	WorkloadIdentityTokenFile string
+	// WorkloadIdentityAudience is the audience of the workload identity
+	// token. If empty, coderd uses the issuer URL.
+	WorkloadIdentityAudience string
 }
 
 type Client interface {
-	ConnectRPC23(ctx context.Context) (
-		proto.DRPCAgentClient23, tailnetproto.DRPCTailnetClient23, error,
+	ConnectRPC24(ctx context.Context) (
+		proto.DRPCAgentClient24, tailnetproto.DRPCTailnetClient24, error,
 	)
 	RewriteDERPMap(derpMap *tailcfg.DERPMap)
 }
@@ -173,12 +190,25 @@ func New(options Options) Agent {
 		sshMaxTimeout:                      options.SSHMaxTimeout,
 		subsystems:                         options.Subsystems,
 		logSender:                          agentsdk.NewLogSender(options.Logger),
+		sessionRecordings:                  agentrecord.NewUploader(options.Logger.Named("session-recordings")),
+		networkTelemetry:                   tailnet.NewBasicTelemetryController(options.Logger.Named("net.telemetry")),
 		blockFileTransfer:                  options.BlockFileTransfer,
 
 		prometheusRegistry: prometheusRegistry,
 		metrics:            newAgentMetrics(prometheusRegistry),
 		execer:             options.Execer,
+		cgroups:            options.Cgroups,
+
+		workloadIdentityTokenFile: options.WorkloadIdentityTokenFile,
+		workloadIdentityAudience:  options.WorkloadIdentityAudience,
 	}
+	a.containers = agentcontainers.NewManager(agentcontainers.Options{
+		Logger:      options.Logger.Named("devcontainers"),
+		Filesystem:  options.Filesystem,
+		Execer:      options.Execer,
+		LogDir:      options.LogDir,
+		Directories: options.DevcontainerDirs,
+	})
 	// Initially, we have a closed channel, reflecting the fact that we are not initially connected.
 	// Each time we connect we replace the channel (while holding the closeMutex) with a new one
 	// that gets closed on disconnection.  This is used to wait for graceful disconnection from the
@@ -231,6 +261,9 @@ type agent struct {
 	sshServer                          *agentssh.Server
 	sshMaxTimeout                      time.Duration
 	blockFileTransfer                  bool
+	// containers manages the dev containers started from the
+	// devcontainer.json files in the project directories.
+	containers *agentcontainers.Manager
 
 	lifecycleUpdate            chan struct{}
 	lifecycleReported          chan codersdk.WorkspaceAgentLifecycle
@@ -240,13 +273,22 @@ type agent struct {
 
 	network       *tailnet.Conn
 	statsReporter *statsReporter
-	logSender     *agentsdk.LogSender
+	// networkTelemetry sends the network reports of the tailnet to coderd.
+	networkTelemetry *tailnet.BasicTelemetryController
+	logSender        *agentsdk.LogSender
+	// sessionRecordings uploads recordings of interactive sessions when
+	// enabled by the template.
+	sessionRecordings *agentrecord.Uploader
 
 	prometheusRegistry *prometheus.Registry
 	// metrics are prometheus registered metrics that will be collected and
 	// labeled in Coder with the agent + workspace.
 	metrics *agentMetrics
 	execer  agentexec.Execer
+	cgroups *agentcgroup.Manager
+
+	workloadIdentityTokenFile string
+	workloadIdentityAudience  string
 }
 
 func (a *agent) TailnetConn() *tailnet.Conn {
@@ -255,13 +297,17 @@ func (a *agent) TailnetConn() *tailnet.Conn {
 
 func (a *agent) init() {
 	// pass the "hard" context because we explicitly close the SSH server as part of graceful shutdown.
-	sshSrv, err := agentssh.NewServer(a.hardCtx, a.logger.Named("ssh-server"), a.prometheusRegistry, a.filesystem, a.execer, &agentssh.Config{
+	sshSrv, err := agentssh.NewServer(a.hardCtx, a.logger.Named("ssh-server"), a.prometheusRegistry, a.filesystem, a.cgroupExecer(agentcgroup.GroupSessions), &agentssh.Config{
 		MaxTimeout:          a.sshMaxTimeout,
 		MOTDFile:            func() string { return a.manifest.Load().MOTDFile },
 		AnnouncementBanners: func() *[]codersdk.BannerConfig { return a.announcementBanners.Load() },
 		UpdateEnv:           a.updateCommandEnv,
 		WorkingDirectory:    func() string { return a.manifest.Load().Directory },
 		BlockFileTransfer:   a.blockFileTransfer,
+		RecordSession: func(width, height uint16, term string) *agentrecord.Recorder {
+			return a.recordSession(proto.UploadSessionRecordingRequest_SSH, width, height, term)
+		},
+		ContainerCommand: a.containers.Command,
 	})
 	if err != nil {
 		panic(err)
@@ -276,6 +322,8 @@ func (a *agent) init() {
 		GetScriptLogger: func(logSourceID uuid.UUID) agentscripts.ScriptLogger {
 			return a.logSender.GetScriptLogger(logSourceID)
 		},
+		Execer:       a.cgroupExecer(agentcgroup.GroupScripts),
+		ScriptExited: a.moveScriptApps,
 	})
 	// Register runner metrics. If the prom registry is nil, the metrics
 	// will not report anywhere.
@@ -286,10 +334,54 @@ func (a *agent) init() {
 		a.sshServer,
 		a.metrics.connectionsTotal, a.metrics.reconnectingPTYErrors,
 		a.reconnectingPTYTimeout,
+		func(width, height uint16) *agentrecord.Recorder {
+			return a.recordSession(proto.UploadSessionRecordingRequest_RECONNECTING_PTY, width, height, "xterm-256color")
+		},
 	)
 	go a.runLoop()
 }
 
+// cgroupExecer returns an Execer that places processes in the given cgroup.
+// If cgroups are not enabled, the Execer of the agent is returned.
+func (a *agent) cgroupExecer(group string) agentexec.Execer {
+	if a.cgroups == nil {
+		return a.execer
+	}
+	execer, err := agentexec.WithCgroup(a.execer, a.cgroups.Path(group))
+	if err != nil {
+		a.logger.Warn(a.hardCtx, "unable to place processes in cgroup", slog.F("group", group), slog.Error(err))
+		return a.execer
+	}
+	return execer
+}
+
+// moveScriptApps moves the processes a script left running, such as app
+// servers, from the scripts cgroup to the apps cgroup, so that they are
+// limited and accounted for separately.
+func (a *agent) moveScriptApps(pid int) {
+	if a.cgroups == nil {
+		return
+	}
+	moved, err := a.cgroups.MoveSession(pid, agentcgroup.GroupScripts, agentcgroup.GroupApps)
+	if err != nil {
+		a.logger.Warn(a.hardCtx, "unable to move script processes to the apps cgroup", slog.F("pid", pid), slog.Error(err))
+		return
+	}
+	if moved > 0 {
+		a.logger.Debug(a.hardCtx, "moved script processes to the apps cgroup", slog.F("pid", pid), slog.F("count", moved))
+	}
+}
+
+// recordSession starts recording an interactive session if session recording
+// is enabled for the template of the workspace.
+func (a *agent) recordSession(sessionType proto.UploadSessionRecordingRequest_Type, width, height uint16, term string) *agentrecord.Recorder {
+	manifest := a.manifest.Load()
+	if manifest == nil || !manifest.SessionRecordingEnabled {
+		return nil
+	}
+	return a.sessionRecordings.Start(sessionType, width, height, term)
+}
+
 // runLoop attempts to start the agent in a retry loop.
 // Coder may be offline temporarily, a connection issue
 // may be happening, but regardless after the intermittent
@@ -399,7 +491,7 @@ func (t *trySingleflight) Do(key string, fn func()) {
 	fn()
 }
 
-func (a *agent) reportMetadata(ctx context.Context, aAPI proto.DRPCAgentClient23) error {
+func (a *agent) reportMetadata(ctx context.Context, aAPI proto.DRPCAgentClient24) error {
 	tickerDone := make(chan struct{})
 	collectDone := make(chan struct{})
 	ctx, cancel := context.WithCancel(ctx)
@@ -615,7 +707,7 @@ func (a *agent) reportMetadata(ctx context.Context, aAPI proto.DRPCAgentClient23
 
 // reportLifecycle reports the current lifecycle state once. All state
 // changes are reported in order.
-func (a *agent) reportLifecycle(ctx context.Context, aAPI proto.DRPCAgentClient23) error {
+func (a *agent) reportLifecycle(ctx context.Context, aAPI proto.DRPCAgentClient24) error {
 	for {
 		select {
 		case <-a.lifecycleUpdate:
@@ -697,7 +789,7 @@ func (a *agent) setLifecycle(state codersdk.WorkspaceAgentLifecycle) {
 // fetchServiceBannerLoop fetches the service banner on an interval.  It will
 // not be fetched immediately; the expectation is that it is primed elsewhere
 // (and must be done before the session actually starts).
-func (a *agent) fetchServiceBannerLoop(ctx context.Context, aAPI proto.DRPCAgentClient23) error {
+func (a *agent) fetchServiceBannerLoop(ctx context.Context, aAPI proto.DRPCAgentClient24) error {
 	ticker := time.NewTicker(a.announcementBannersRefreshInterval)
 	defer ticker.Stop()
 	for {
@@ -733,7 +825,7 @@ func (a *agent) run() (retErr error) {
 	a.sessionToken.Store(&sessionToken)
 
 	// ConnectRPC returns the dRPC connection we use for the Agent and Tailnet v2+ APIs
-	aAPI, tAPI, err := a.client.ConnectRPC23(a.hardCtx)
+	aAPI, tAPI, err := a.client.ConnectRPC24(a.hardCtx)
 	if err != nil {
 		return err
 	}
@@ -750,7 +842,7 @@ func (a *agent) run() (retErr error) {
 	connMan := newAPIConnRoutineManager(a.gracefulCtx, a.hardCtx, a.logger, aAPI, tAPI)
 
 	connMan.startAgentAPI("init notification banners", gracefulShutdownBehaviorStop,
-		func(ctx context.Context, aAPI proto.DRPCAgentClient23) error {
+		func(ctx context.Context, aAPI proto.DRPCAgentClient24) error {
 			bannersProto, err := aAPI.GetAnnouncementBanners(ctx, &proto.GetAnnouncementBannersRequest{})
 			if err != nil {
 				return xerrors.Errorf("fetch service banner: %w", err)
@@ -767,7 +859,7 @@ func (a *agent) run() (retErr error) {
 	// sending logs gets gracefulShutdownBehaviorRemain because we want to send logs generated by
 	// shutdown scripts.
 	connMan.startAgentAPI("send logs", gracefulShutdownBehaviorRemain,
-		func(ctx context.Context, aAPI proto.DRPCAgentClient23) error {
+		func(ctx context.Context, aAPI proto.DRPCAgentClient24) error {
 			err := a.logSender.SendLoop(ctx, aAPI)
 			if xerrors.Is(err, agentsdk.LogLimitExceededError) {
 				// we don't want this error to tear down the API connection and propagate to the
@@ -778,6 +870,13 @@ func (a *agent) run() (retErr error) {
 			return err
 		})
 
+	// session recordings get gracefulShutdownBehaviorRemain so that sessions
+	// which end during shutdown are uploaded completely.
+	connMan.startAgentAPI("send session recordings", gracefulShutdownBehaviorRemain,
+		func(ctx context.Context, aAPI proto.DRPCAgentClient24) error {
+			return a.sessionRecordings.SendLoop(ctx, aAPI)
+		})
+
 	// part of graceful shut down is reporting the final lifecycle states, e.g "ShuttingDown" so the
 	// lifecycle reporting has to be via gracefulShutdownBehaviorRemain
 	connMan.startAgentAPI("report lifecycle", gracefulShutdownBehaviorRemain, a.reportLifecycle)
@@ -807,7 +906,7 @@ func (a *agent) run() (retErr error) {
 	connMan.startAgentAPI("handle manifest", gracefulShutdownBehaviorStop, a.handleManifest(manifestOK))
 
 	connMan.startAgentAPI("app health reporter", gracefulShutdownBehaviorStop,
-		func(ctx context.Context, aAPI proto.DRPCAgentClient23) error {
+		func(ctx context.Context, aAPI proto.DRPCAgentClient24) error {
 			if err := manifestOK.wait(ctx); err != nil {
 				return xerrors.Errorf("no manifest: %w", err)
 			}
@@ -822,7 +921,7 @@ func (a *agent) run() (retErr error) {
 		a.createOrUpdateNetwork(manifestOK, networkOK))
 
 	connMan.startTailnetAPI("coordination", gracefulShutdownBehaviorStop,
-		func(ctx context.Context, tAPI tailnetproto.DRPCTailnetClient23) error {
+		func(ctx context.Context, tAPI tailnetproto.DRPCTailnetClient24) error {
 			if err := networkOK.wait(ctx); err != nil {
 				return xerrors.Errorf("no network: %w", err)
 			}
@@ -831,16 +930,27 @@ func (a *agent) run() (retErr error) {
 	)
 
 	connMan.startTailnetAPI("derp map subscriber", gracefulShutdownBehaviorStop,
-		func(ctx context.Context, tAPI tailnetproto.DRPCTailnetClient23) error {
+		func(ctx context.Context, tAPI tailnetproto.DRPCTailnetClient24) error {
 			if err := networkOK.wait(ctx); err != nil {
 				return xerrors.Errorf("no network: %w", err)
 			}
 			return a.runDERPMapSubscriber(ctx, tAPI, a.network)
 		})
 
+	connMan.startTailnetAPI("network telemetry", gracefulShutdownBehaviorStop,
+		func(ctx context.Context, tAPI tailnetproto.DRPCTailnetClient24) error {
+			a.networkTelemetry.New(tAPI)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
 	connMan.startAgentAPI("fetch service banner loop", gracefulShutdownBehaviorStop, a.fetchServiceBannerLoop)
 
-	connMan.startAgentAPI("stats report loop", gracefulShutdownBehaviorStop, func(ctx context.Context, aAPI proto.DRPCAgentClient23) error {
+	if a.workloadIdentityTokenFile != "" {
+		connMan.startAgentAPI("refresh workload identity token", gracefulShutdownBehaviorStop, a.refreshWorkloadIdentityToken)
+	}
+
+	connMan.startAgentAPI("stats report loop", gracefulShutdownBehaviorStop, func(ctx context.Context, aAPI proto.DRPCAgentClient24) error {
 		if err := networkOK.wait(ctx); err != nil {
 			return xerrors.Errorf("no network: %w", err)
 		}
@@ -851,8 +961,8 @@ func (a *agent) run() (retErr error) {
 }
 
 // handleManifest returns a function that fetches and processes the manifest
-func (a *agent) handleManifest(manifestOK *checkpoint) func(ctx context.Context, aAPI proto.DRPCAgentClient23) error {
-	return func(ctx context.Context, aAPI proto.DRPCAgentClient23) error {
+func (a *agent) handleManifest(manifestOK *checkpoint) func(ctx context.Context, aAPI proto.DRPCAgentClient24) error {
+	return func(ctx context.Context, aAPI proto.DRPCAgentClient24) error {
 		var (
 			sentResult = false
 			err        error
@@ -866,10 +976,10 @@ func (a *agent) handleManifest(manifestOK *checkpoint) func(ctx context.Context,
 		if err != nil {
 			return xerrors.Errorf("fetch metadata: %w", err)
 		}
-		a.logger.Info(ctx, "fetched manifest", slog.F("manifest", mp))
+		a.logger.Info(ctx, "fetched manifest", slog.F("manifest", redactManifest(mp)))
 		manifest, err := agentsdk.ManifestFromProto(mp)
 		if err != nil {
-			a.logger.Critical(ctx, "failed to convert manifest", slog.F("manifest", mp), slog.Error(err))
+			a.logger.Critical(ctx, "failed to convert manifest", slog.F("manifest", redactManifest(mp)), slog.Error(err))
 			return xerrors.Errorf("convert manifest: %w", err)
 		}
 		if manifest.AgentID == uuid.Nil {
@@ -922,6 +1032,10 @@ func (a *agent) handleManifest(manifestOK *checkpoint) func(ctx context.Context,
 				}
 			}
 
+			// Secret files are written before the startup scripts run, so
+			// they can be used by them.
+			a.writeSecretFiles(ctx, manifest.Secrets)
+
 			err = a.scriptRunner.Init(manifest.Scripts, aAPI.ScriptCompleted)
 			if err != nil {
 				return xerrors.Errorf("init script runner: %w", err)
@@ -933,6 +1047,16 @@ func (a *agent) handleManifest(manifestOK *checkpoint) func(ctx context.Context,
 				err := a.scriptRunner.Execute(a.gracefulCtx, agentscripts.ExecuteStartScripts)
 				// Measure the time immediately after the script has finished
 				dur := time.Since(start).Seconds()
+				// Dev containers are started after the startup scripts, which
+				// may be responsible for cloning the project directories.
+				// The agent is only ready once they are running.
+				containersCtx, containersCancel := context.WithTimeout(a.gracefulCtx, devcontainerStartTimeout(manifest.Scripts))
+				containersErr := a.containers.Start(containersCtx, devcontainerBaseDir(manifest.Directory))
+				containersTimeout := errors.Is(containersCtx.Err(), context.DeadlineExceeded)
+				containersCancel()
+				if containersErr != nil {
+					a.logger.Warn(ctx, "dev container(s) failed to start", slog.F("timeout", containersTimeout), slog.Error(containersErr))
+				}
 				if err != nil {
 					a.logger.Warn(ctx, "startup script(s) failed", slog.Error(err))
 					if errors.Is(err, agentscripts.ErrTimeout) {
@@ -940,6 +1064,10 @@ func (a *agent) handleManifest(manifestOK *checkpoint) func(ctx context.Context,
 					} else {
 						a.setLifecycle(codersdk.WorkspaceAgentLifecycleStartError)
 					}
+				} else if containersTimeout {
+					a.setLifecycle(codersdk.WorkspaceAgentLifecycleStartTimeout)
+				} else if containersErr != nil {
+					a.setLifecycle(codersdk.WorkspaceAgentLifecycleStartError)
 				} else {
 					a.setLifecycle(codersdk.WorkspaceAgentLifecycleReady)
 				}
@@ -961,8 +1089,8 @@ func (a *agent) handleManifest(manifestOK *checkpoint) func(ctx context.Context,
 
 // createOrUpdateNetwork waits for the manifest to be set using manifestOK, then creates or updates
 // the tailnet using the information in the manifest
-func (a *agent) createOrUpdateNetwork(manifestOK, networkOK *checkpoint) func(context.Context, proto.DRPCAgentClient23) error {
-	return func(ctx context.Context, _ proto.DRPCAgentClient23) (retErr error) {
+func (a *agent) createOrUpdateNetwork(manifestOK, networkOK *checkpoint) func(context.Context, proto.DRPCAgentClient24) error {
+	return func(ctx context.Context, _ proto.DRPCAgentClient24) (retErr error) {
 		if err := manifestOK.wait(ctx); err != nil {
 			return xerrors.Errorf("no manifest: %w", err)
 		}
@@ -1014,6 +1142,7 @@ func (a *agent) createOrUpdateNetwork(manifestOK, networkOK *checkpoint) func(co
 // - Predefined workspace environment variables
 // - Environment variables currently set (overriding predefined)
 // - Environment variables passed via the agent manifest (overriding predefined and current)
+// - Secrets passed via the agent manifest (overriding the manifest environment variables)
 // - Agent-level environment variables (overriding all)
 func (a *agent) updateCommandEnv(current []string) (updated []string, err error) {
 	manifest := a.manifest.Load()
@@ -1044,6 +1173,9 @@ func (a *agent) updateCommandEnv(current []string) (updated []string, err error)
 		// Hide Coder message on code-server's "Getting Started" page
 		"CS_DISABLE_GETTING_STARTED_OVERRIDE": "true",
 	}
+	if a.workloadIdentityTokenFile != "" {
+		envs["CODER_WORKLOAD_IDENTITY_TOKEN_FILE"] = a.workloadIdentityTokenFile
+	}
 
 	// This adds the ports dialog to code-server that enables
 	// proxying a port dynamically.
@@ -1073,6 +1205,13 @@ func (a *agent) updateCommandEnv(current []string) (updated []string, err error)
 		envs[k] = os.ExpandEnv(v)
 	}
 
+	// Secret values are used as-is, they must not be expanded.
+	for _, secret := range manifest.Secrets {
+		if secret.EnvName != "" {
+			envs[secret.EnvName] = string(secret.Value)
+		}
+	}
+
 	// Agent-level environment variables should take over all. This is
 	// used for setting agent-specific variables like CODER_AGENT_TOKEN
 	// and GIT_ASKPASS.
@@ -1134,6 +1273,11 @@ func (a *agent) createTailnet(ctx context.Context, agentID uuid.UUID, derpMap *t
 		Logger:              a.logger.Named("net.tailnet"),
 		ListenPort:          a.tailnetListenPort,
 		BlockEndpoints:      disableDirectConnections,
+		ClientType:          tailnetproto.TelemetryEvent_AGENT,
+		TelemetrySink:       a.networkTelemetry,
+		// Agents are long-lived, so they report their network periodically
+		// rather than when connections are made.
+		NetworkReportInterval: tailnet.DefaultNetworkReportInterval,
 	})
 	if err != nil {
 		return nil, xerrors.Errorf("create tailnet: %w", err)
@@ -1159,6 +1303,21 @@ func (a *agent) createTailnet(ctx context.Context, agentID uuid.UUID, derpMap *t
 		return nil, err
 	}
 
+	containerSSHListener, err := network.Listen("tcp", ":"+strconv.Itoa(workspacesdk.AgentContainerSSHPort))
+	if err != nil {
+		return nil, xerrors.Errorf("listen on the container ssh port: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = containerSSHListener.Close()
+		}
+	}()
+	if err = a.trackGoroutine(func() {
+		_ = a.sshServer.ServeContainers(containerSSHListener)
+	}); err != nil {
+		return nil, err
+	}
+
 	reconnectingPTYListener, err := network.Listen("tcp", ":"+strconv.Itoa(workspacesdk.AgentReconnectingPTYPort))
 	if err != nil {
 		return nil, xerrors.Errorf("listen for reconnecting pty: %w", err)
@@ -1266,7 +1425,7 @@ func (a *agent) createTailnet(ctx context.Context, agentID uuid.UUID, derpMap *t
 
 // runCoordinator runs a coordinator and returns whether a reconnect
 // should occur.
-func (a *agent) runCoordinator(ctx context.Context, tClient tailnetproto.DRPCTailnetClient23, network *tailnet.Conn) error {
+func (a *agent) runCoordinator(ctx context.Context, tClient tailnetproto.DRPCTailnetClient24, network *tailnet.Conn) error {
 	defer a.logger.Debug(ctx, "disconnected from coordination RPC")
 	// we run the RPC on the hardCtx so that we have a chance to send the disconnect message if we
 	// gracefully shut down.
@@ -1313,7 +1472,7 @@ func (a *agent) runCoordinator(ctx context.Context, tClient tailnetproto.DRPCTai
 }
 
 // runDERPMapSubscriber runs a coordinator and returns if a reconnect should occur.
-func (a *agent) runDERPMapSubscriber(ctx context.Context, tClient tailnetproto.DRPCTailnetClient23, network *tailnet.Conn) error {
+func (a *agent) runDERPMapSubscriber(ctx context.Context, tClient tailnetproto.DRPCTailnetClient24, network *tailnet.Conn) error {
 	defer a.logger.Debug(ctx, "disconnected from derp map RPC")
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -1362,6 +1521,20 @@ func (a *agent) Collect(ctx context.Context, networkStats map[netlogtype.Connect
 
 	stats.SessionCountReconnectingPty = a.reconnectingPTYServer.ConnCount()
 
+	if a.cgroups != nil {
+		for _, group := range a.cgroups.Stats(time.Now()) {
+			stats.ProcessGroups = append(stats.ProcessGroups, &proto.Stats_ProcessGroup{
+				Name:             group.Name,
+				CpuUsageUsec:     group.CPUUsage.Microseconds(),
+				CpuCores:         group.CPU,
+				CpuLimitCores:    group.CPULimit,
+				MemoryBytes:      group.Memory,
+				MemoryLimitBytes: group.MemoryLimit,
+				OomKills:         group.OOMKills,
+			})
+		}
+	}
+
 	// Compute the median connection latency!
 	a.logger.Debug(ctx, "starting peer latency measurement for stats")
 	var wg sync.WaitGroup
@@ -1604,6 +1777,12 @@ lifecycleWaitLoop:
 		a.logger.Warn(context.Background(), "timed out waiting for all logs to be sent", slog.Error(err))
 	}
 
+	// Wait for session recordings to be sent
+	err = a.sessionRecordings.WaitUntilEmpty(a.hardCtx)
+	if err != nil {
+		a.logger.Warn(context.Background(), "timed out waiting for all session recordings to be sent", slog.Error(err))
+	}
+
 	a.hardCancel()
 	if a.network != nil {
 		_ = a.network.Close()
@@ -1630,6 +1809,38 @@ func userHomeDir() (string, error) {
 	return u.HomeDir, nil
 }
 
+// devcontainerBaseDir returns the directory that relative dev container
+// project directories are resolved against.
+func devcontainerBaseDir(dir string) string {
+	if dir != "" {
+		return dir
+	}
+	home, err := userHomeDir()
+	if err != nil {
+		return ""
+	}
+	return home
+}
+
+// defaultDevcontainerStartTimeout bounds starting the dev containers when no
+// startup script has a timeout.
+const defaultDevcontainerStartTimeout = time.Hour
+
+// devcontainerStartTimeout returns how long the dev containers may take to
+// start, which is the longest timeout of the startup scripts.
+func devcontainerStartTimeout(scripts []codersdk.WorkspaceAgentScript) time.Duration {
+	var timeout time.Duration
+	for _, script := range scripts {
+		if script.RunOnStart && script.Timeout > timeout {
+			timeout = script.Timeout
+		}
+	}
+	if timeout == 0 {
+		return defaultDevcontainerStartTimeout
+	}
+	return timeout
+}
+
 // expandDirectory converts a directory path to an absolute path.
 // It primarily resolves the home directory and any environment
 // variables that may be set
@@ -1683,8 +1894,8 @@ const (
 
 type apiConnRoutineManager struct {
 	logger    slog.Logger
-	aAPI      proto.DRPCAgentClient23
-	tAPI      tailnetproto.DRPCTailnetClient23
+	aAPI      proto.DRPCAgentClient24
+	tAPI      tailnetproto.DRPCTailnetClient24
 	eg        *errgroup.Group
 	stopCtx   context.Context
 	remainCtx context.Context
@@ -1692,7 +1903,7 @@ type apiConnRoutineManager struct {
 
 func newAPIConnRoutineManager(
 	gracefulCtx, hardCtx context.Context, logger slog.Logger,
-	aAPI proto.DRPCAgentClient23, tAPI tailnetproto.DRPCTailnetClient23,
+	aAPI proto.DRPCAgentClient24, tAPI tailnetproto.DRPCTailnetClient24,
 ) *apiConnRoutineManager {
 	// routines that remain in operation during graceful shutdown use the remainCtx.  They'll still
 	// exit if the errgroup hits an error, which usually means a problem with the conn.
@@ -1725,7 +1936,7 @@ func newAPIConnRoutineManager(
 // but for Tailnet.
 func (a *apiConnRoutineManager) startAgentAPI(
 	name string, behavior gracefulShutdownBehavior,
-	f func(context.Context, proto.DRPCAgentClient23) error,
+	f func(context.Context, proto.DRPCAgentClient24) error,
 ) {
 	logger := a.logger.With(slog.F("name", name))
 	var ctx context.Context
@@ -1762,7 +1973,7 @@ func (a *apiConnRoutineManager) startAgentAPI(
 // but for the Agent API.
 func (a *apiConnRoutineManager) startTailnetAPI(
 	name string, behavior gracefulShutdownBehavior,
-	f func(context.Context, tailnetproto.DRPCTailnetClient23) error,
+	f func(context.Context, tailnetproto.DRPCTailnetClient24) error,
 ) {
 	logger := a.logger.With(slog.F("name", name))
 	var ctx context.Context
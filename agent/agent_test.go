@@ -21,6 +21,7 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -38,12 +39,14 @@ import (
 	"golang.org/x/crypto/ssh"
 	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"tailscale.com/net/speedtest"
 	"tailscale.com/tailcfg"
 
 	"cdr.dev/slog"
 	"cdr.dev/slog/sloggers/slogtest"
 	"github.com/coder/coder/v2/agent"
+	"github.com/coder/coder/v2/agent/agentcgroup"
 	"github.com/coder/coder/v2/agent/agentssh"
 	"github.com/coder/coder/v2/agent/agenttest"
 	"github.com/coder/coder/v2/agent/proto"
@@ -125,6 +128,54 @@ func TestAgent_Stats_ReconnectingPTY(t *testing.T) {
 	)
 }
 
+func TestAgent_Stats_ProcessGroups(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS != "linux" {
+		t.Skip("cgroups are only supported on Linux")
+	}
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	// Fake a cgroup v2 hierarchy with the agent in /workspace.
+	root := t.TempDir()
+	dir := filepath.Join(root, "workspace")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cgroup.controllers"), []byte("cpu memory"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cgroup.procs"), []byte("1"), 0o600))
+	procSelf := filepath.Join(t.TempDir(), "cgroup")
+	require.NoError(t, os.WriteFile(procSelf, []byte("0::/workspace\n"), 0o600))
+	cgroups, err := agentcgroup.New(ctx, agentcgroup.Options{
+		Logger:         slogtest.Make(t, nil),
+		Root:           root,
+		ProcSelfCgroup: procSelf,
+		Limits: map[string]agentcgroup.Limits{
+			agentcgroup.GroupSessions: {Memory: 1 << 30},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sessions", "memory.current"), []byte("4096"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sessions", "memory.events"), []byte("oom 1\noom_kill 1\n"), 0o600))
+
+	//nolint:dogsled
+	_, _, stats, _, _ := setupAgent(t, agentsdk.Manifest{}, 0, func(_ *agenttest.Client, o *agent.Options) {
+		o.Cgroups = cgroups
+	})
+
+	var s *proto.Stats
+	require.Eventuallyf(t, func() bool {
+		var ok bool
+		s, ok = <-stats
+		return ok && len(s.ProcessGroups) == 3
+	}, testutil.WaitLong, testutil.IntervalFast,
+		"never saw stats: %+v", s,
+	)
+	require.Equal(t, agentcgroup.GroupSessions, s.ProcessGroups[0].Name)
+	require.EqualValues(t, 4096, s.ProcessGroups[0].MemoryBytes)
+	require.EqualValues(t, 1<<30, s.ProcessGroups[0].MemoryLimitBytes)
+	require.EqualValues(t, 1, s.ProcessGroups[0].OomKills)
+	require.Equal(t, agentcgroup.GroupScripts, s.ProcessGroups[1].Name)
+	require.Equal(t, agentcgroup.GroupApps, s.ProcessGroups[2].Name)
+}
+
 func TestAgent_Stats_Magic(t *testing.T) {
 	t.Parallel()
 	t.Run("StripsEnvironmentVariable", func(t *testing.T) {
@@ -405,6 +456,55 @@ func TestAgent_SessionTTYShell(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestAgent_SessionTTYRecording(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("ConPTY appears to be inconsistent on Windows.")
+	}
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	//nolint:dogsled
+	conn, client, _, _, _ := setupAgent(t, agentsdk.Manifest{SessionRecordingEnabled: true}, 0)
+	sshClient, err := conn.SSHClient(ctx)
+	require.NoError(t, err)
+	defer sshClient.Close()
+	session, err := sshClient.NewSession()
+	require.NoError(t, err)
+	defer session.Close()
+	err = session.RequestPty("xterm", 128, 128, ssh.TerminalModes{})
+	require.NoError(t, err)
+	ptty := ptytest.New(t)
+	session.Stdout = ptty.Output()
+	session.Stderr = ptty.Output()
+	session.Stdin = ptty.Input()
+	err = session.Start("sh")
+	require.NoError(t, err)
+	_ = ptty.Peek(ctx, 1) // wait for the prompt
+	ptty.WriteLine("echo recorded-output")
+	ptty.ExpectMatch("recorded-output")
+	ptty.WriteLine("exit")
+	err = session.Wait()
+	require.NoError(t, err)
+
+	var data bytes.Buffer
+	require.Eventually(t, func() bool {
+		recordings := client.GetSessionRecordings()
+		if len(recordings) == 0 || !recordings[len(recordings)-1].Final {
+			return false
+		}
+		data.Reset()
+		for _, req := range recordings {
+			assert.Equal(t, proto.UploadSessionRecordingRequest_SSH, req.Type)
+			data.Write(req.Data)
+		}
+		return true
+	}, testutil.WaitLong, testutil.IntervalFast)
+	header, _, _ := strings.Cut(data.String(), "\n")
+	require.Contains(t, header, `"version":2`)
+	require.Contains(t, header, `"TERM":"xterm"`)
+	require.Contains(t, data.String(), "recorded-output")
+}
+
 func TestAgent_SessionTTYExitCode(t *testing.T) {
 	t.Parallel()
 	session := setupSSHSession(t, agentsdk.Manifest{}, codersdk.ServiceBannerConfig{}, nil)
@@ -1098,6 +1198,110 @@ func TestAgent_EnvironmentVariableExpansion(t *testing.T) {
 	require.Equal(t, expect, strings.TrimSpace(string(output)))
 }
 
+func TestAgent_Secrets(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	//nolint:dogsled
+	conn, _, _, fs, _ := setupAgent(t, agentsdk.Manifest{
+		EnvironmentVariables: map[string]string{
+			"GITHUB_TOKEN": "overridden",
+		},
+		Secrets: []agentsdk.Secret{
+			{
+				EnvName: "GITHUB_TOKEN",
+				Value:   []byte("$NOT_EXPANDED"),
+			},
+			{
+				FilePath: "/secrets/npmrc",
+				Value:    []byte("//registry.npmjs.org/:_authToken=token"),
+			},
+		},
+	}, 0)
+
+	// The secret file is written before the startup scripts run.
+	require.Eventually(t, func() bool {
+		content, err := afero.ReadFile(fs, "/secrets/npmrc")
+		return err == nil && string(content) == "//registry.npmjs.org/:_authToken=token"
+	}, testutil.WaitShort, testutil.IntervalFast)
+	info, err := fs.Stat("/secrets/npmrc")
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	sshClient, err := conn.SSHClient(ctx)
+	require.NoError(t, err)
+	defer sshClient.Close()
+	session, err := sshClient.NewSession()
+	require.NoError(t, err)
+	defer session.Close()
+	command := "sh -c 'echo \"$GITHUB_TOKEN\"'"
+	if runtime.GOOS == "windows" {
+		command = "cmd.exe /c echo %GITHUB_TOKEN%"
+	}
+	output, err := session.Output(command)
+	require.NoError(t, err)
+	// Secrets override the environment variables of the manifest, and are
+	// not expanded.
+	require.Equal(t, "$NOT_EXPANDED", strings.TrimSpace(string(output)))
+}
+
+func TestAgent_WorkloadIdentityToken(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	var (
+		mu        sync.Mutex
+		audiences []string
+	)
+	//nolint:dogsled
+	conn, _, _, fs, _ := setupAgent(t, agentsdk.Manifest{}, 0, func(c *agenttest.Client, o *agent.Options) {
+		o.WorkloadIdentityTokenFile = "/run/coder/token"
+		o.WorkloadIdentityAudience = "sts.amazonaws.com"
+		c.SetWorkloadIdentityTokenFunc(func(audience string) (*proto.WorkloadIdentityToken, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			audiences = append(audiences, audience)
+			// Short-lived tokens are refreshed after half of their
+			// lifetime.
+			return &proto.WorkloadIdentityToken{
+				Token:     fmt.Sprintf("token-%d", len(audiences)),
+				ExpiresAt: timestamppb.New(time.Now().Add(2 * time.Second)),
+			}, nil
+		})
+	})
+
+	var first string
+	require.Eventually(t, func() bool {
+		content, err := afero.ReadFile(fs, "/run/coder/token")
+		first = string(content)
+		return err == nil && strings.HasPrefix(first, "token-")
+	}, testutil.WaitShort, testutil.IntervalFast)
+	require.Eventually(t, func() bool {
+		content, err := afero.ReadFile(fs, "/run/coder/token")
+		return err == nil && strings.HasPrefix(string(content), "token-") && string(content) != first
+	}, testutil.WaitShort, testutil.IntervalFast)
+	info, err := fs.Stat("/run/coder/token")
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	mu.Lock()
+	require.Equal(t, "sts.amazonaws.com", audiences[0])
+	mu.Unlock()
+
+	sshClient, err := conn.SSHClient(ctx)
+	require.NoError(t, err)
+	defer sshClient.Close()
+	session, err := sshClient.NewSession()
+	require.NoError(t, err)
+	defer session.Close()
+	command := "sh -c 'echo \"$CODER_WORKLOAD_IDENTITY_TOKEN_FILE\"'"
+	if runtime.GOOS == "windows" {
+		command = "cmd.exe /c echo %CODER_WORKLOAD_IDENTITY_TOKEN_FILE%"
+	}
+	output, err := session.Output(command)
+	require.NoError(t, err)
+	require.Equal(t, "/run/coder/token", strings.TrimSpace(string(output)))
+}
+
 func TestAgent_CoderEnvVars(t *testing.T) {
 	t.Parallel()
 
@@ -1350,6 +1554,39 @@ func TestAgent_Lifecycle(t *testing.T) {
 		require.Equal(t, want, got[:len(want)])
 	})
 
+	t.Run("DevcontainerError", func(t *testing.T) {
+		t.Parallel()
+
+		conn, client, _, _, _ := setupAgent(t, agentsdk.Manifest{
+			Directory: "/home/coder",
+		}, 0, func(_ *agenttest.Client, o *agent.Options) {
+			// A configuration without an image or Dockerfile is invalid.
+			err := afero.WriteFile(o.Filesystem, "/home/coder/project/.devcontainer.json", []byte(`{"name": "invalid"}`), 0o600)
+			require.NoError(t, err)
+			o.DevcontainerDirs = []string{"project"}
+		})
+
+		want := []codersdk.WorkspaceAgentLifecycle{
+			codersdk.WorkspaceAgentLifecycleStarting,
+			codersdk.WorkspaceAgentLifecycleStartError,
+		}
+
+		var got []codersdk.WorkspaceAgentLifecycle
+		assert.Eventually(t, func() bool {
+			got = client.GetLifecycleStates()
+			return slices.Contains(got, want[len(want)-1])
+		}, testutil.WaitShort, testutil.IntervalMedium)
+
+		require.Equal(t, want, got[:len(want)])
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		resp, err := conn.ListDevcontainers(ctx)
+		require.NoError(t, err)
+		require.Len(t, resp.Devcontainers, 1)
+		require.Equal(t, "project", resp.Devcontainers[0].Name)
+		require.Equal(t, codersdk.WorkspaceAgentDevcontainerStatusError, resp.Devcontainers[0].Status)
+	})
+
 	t.Run("Ready", func(t *testing.T) {
 		t.Parallel()
 
@@ -2297,6 +2534,78 @@ done
 	}
 }
 
+func TestAgent_Files(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		//nolint:dogsled
+		conn, _, _, fs, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+		dir := filepath.Join(string(filepath.Separator), "files")
+
+		info, err := conn.MakeDirectory(ctx, filepath.Join(dir, "nested"))
+		require.NoError(t, err)
+		require.True(t, info.IsDir)
+
+		info, err = conn.UploadFile(ctx, filepath.Join(dir, "hello.txt"), "600", strings.NewReader("hello world"))
+		require.NoError(t, err)
+		require.Equal(t, "hello.txt", info.Name)
+		require.EqualValues(t, len("hello world"), info.Size)
+		require.Equal(t, "-rw-------", info.Mode)
+		content, err := afero.ReadFile(fs, filepath.Join(dir, "hello.txt"))
+		require.NoError(t, err)
+		require.Equal(t, "hello world", string(content))
+
+		list, err := conn.ListFiles(ctx, dir)
+		require.NoError(t, err)
+		require.Equal(t, dir, list.Path)
+		require.Len(t, list.Files, 2)
+		require.Equal(t, "hello.txt", list.Files[0].Name)
+		require.Equal(t, "nested", list.Files[1].Name)
+		require.True(t, list.Files[1].IsDir)
+
+		// Range requests allow resuming downloads.
+		res, err := conn.DownloadFile(ctx, filepath.Join(dir, "hello.txt"), http.Header{"Range": {"bytes=6-"}})
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusPartialContent, res.StatusCode)
+		content, err = io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.Equal(t, "world", string(content))
+
+		_, err = conn.StatFile(ctx, filepath.Join(dir, "missing"))
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusNotFound, sdkErr.StatusCode())
+
+		// Non-empty directories are only deleted recursively.
+		require.Error(t, conn.DeleteFile(ctx, dir, false))
+		require.NoError(t, conn.DeleteFile(ctx, dir, true))
+		_, err = fs.Stat(dir)
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("Blocked", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		//nolint:dogsled
+		conn, _, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0, func(_ *agenttest.Client, o *agent.Options) {
+			o.BlockFileTransfer = true
+		})
+
+		_, err := conn.ListFiles(ctx, "/")
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusForbidden, sdkErr.StatusCode())
+		_, err = conn.UploadFile(ctx, "/hello.txt", "", strings.NewReader("hello world"))
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusForbidden, sdkErr.StatusCode())
+	})
+}
+
 // setupAgentSSHClient creates an agent, dials it, and sets up an ssh.Client for it
 func setupAgentSSHClient(ctx context.Context, t *testing.T) *ssh.Client {
 	//nolint: dogsled
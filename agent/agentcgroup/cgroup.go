@@ -0,0 +1,387 @@
+// Package agentcgroup places the processes started by the agent into
+// separate cgroup v2 groups, so that they can be given resource limits and
+// their usage can be reported.
+package agentcgroup
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+)
+
+// The groups that processes of the agent are placed in.
+const (
+	// GroupSessions contains SSH sessions and web terminals.
+	GroupSessions = "sessions"
+	// GroupScripts contains the startup, shutdown and cron scripts of the
+	// agent while they run.
+	GroupScripts = "scripts"
+	// GroupApps contains the processes scripts leave running once they exit,
+	// such as IDE and other app servers started in the background.
+	GroupApps = "apps"
+
+	// groupAgent contains the agent itself, since a cgroup with child
+	// groups may not contain processes.
+	groupAgent = "agent"
+)
+
+// Groups are the names of the groups processes can be placed in.
+var Groups = []string{GroupSessions, GroupScripts, GroupApps}
+
+// cpuPeriod is the period in microseconds used for CPU limits.
+const cpuPeriod = 100000
+
+// Options are the options for a Manager.
+type Options struct {
+	Logger slog.Logger
+	// Limits are the limits of each group. Groups without limits are still
+	// created for accounting.
+	Limits map[string]Limits
+	// Root is where the cgroup v2 hierarchy is mounted. Defaults to
+	// /sys/fs/cgroup.
+	Root string
+	// ProcSelfCgroup is the file that contains the cgroup of the agent.
+	// Defaults to /proc/self/cgroup.
+	ProcSelfCgroup string
+	// Proc is where procfs is mounted. Defaults to /proc.
+	Proc string
+}
+
+// Manager owns the cgroup of the agent and the groups created below it.
+type Manager struct {
+	dir  string
+	proc string
+
+	mu   sync.Mutex
+	last map[string]cpuSample
+}
+
+type cpuSample struct {
+	usage time.Duration
+	at    time.Time
+}
+
+// Stats is the resource usage of a group.
+type Stats struct {
+	Name string
+	// CPUUsage is the total CPU time used by the group.
+	CPUUsage time.Duration
+	// CPU is the average number of CPUs used since the previous call to
+	// Stats.
+	CPU float64
+	// CPULimit is the number of CPUs the group may use, or zero if
+	// unlimited.
+	CPULimit float64
+	// Memory is the current memory usage of the group in bytes.
+	Memory int64
+	// MemoryLimit is the memory limit of the group in bytes, or zero if
+	// unlimited.
+	MemoryLimit int64
+	// OOMKills is the number of processes in the group killed by the OOM
+	// killer.
+	OOMKills int64
+}
+
+// New moves the agent into a leaf of its own cgroup and creates the groups
+// below it. The cgroup of the agent must be writable, e.g. because it was
+// delegated to the agent by the container runtime.
+func New(ctx context.Context, opts Options) (*Manager, error) {
+	if runtime.GOOS != "linux" {
+		return nil, xerrors.New("cgroups are only supported on Linux")
+	}
+	if opts.Root == "" {
+		opts.Root = "/sys/fs/cgroup"
+	}
+	if opts.ProcSelfCgroup == "" {
+		opts.ProcSelfCgroup = "/proc/self/cgroup"
+	}
+	if opts.Proc == "" {
+		opts.Proc = "/proc"
+	}
+
+	self, err := selfCgroup(opts.ProcSelfCgroup)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(opts.Root, self)
+	// The agent may already have set up the groups, e.g. if it was
+	// restarted within the same cgroup.
+	if filepath.Base(dir) == groupAgent {
+		if _, err := os.Stat(filepath.Join(filepath.Dir(dir), GroupSessions)); err == nil {
+			dir = filepath.Dir(dir)
+		}
+	}
+	controllers, err := os.ReadFile(filepath.Join(dir, "cgroup.controllers"))
+	if err != nil {
+		return nil, xerrors.Errorf("read controllers, is cgroup v2 mounted at %q? %w", opts.Root, err)
+	}
+
+	// A cgroup that delegates controllers to its children may not contain
+	// processes, so everything in the cgroup of the agent, including the
+	// agent, is moved into a leaf first.
+	err = mkdir(filepath.Join(dir, groupAgent))
+	if err != nil {
+		return nil, err
+	}
+	err = moveProcesses(dir, filepath.Join(dir, groupAgent))
+	if err != nil {
+		return nil, err
+	}
+
+	available := strings.Fields(string(controllers))
+	var enable []string
+	for _, controller := range []string{"cpu", "memory"} {
+		if !slices.Contains(available, controller) {
+			opts.Logger.Warn(ctx, "cgroup controller is not available, limits and usage will not be reported",
+				slog.F("controller", controller), slog.F("cgroup", dir))
+			continue
+		}
+		enable = append(enable, "+"+controller)
+	}
+	if len(enable) > 0 {
+		err = os.WriteFile(filepath.Join(dir, "cgroup.subtree_control"), []byte(strings.Join(enable, " ")), 0o600)
+		if err != nil {
+			return nil, xerrors.Errorf("enable controllers: %w", err)
+		}
+	}
+
+	for _, group := range Groups {
+		groupDir := filepath.Join(dir, group)
+		err = mkdir(groupDir)
+		if err != nil {
+			return nil, err
+		}
+		limits := opts.Limits[group]
+		cpuMax := "max"
+		if limits.CPU > 0 {
+			cpuMax = strconv.FormatInt(int64(limits.CPU*cpuPeriod), 10)
+		}
+		err = writeLimit(groupDir, "cpu.max", fmt.Sprintf("%s %d", cpuMax, cpuPeriod), limits.CPU > 0)
+		if err != nil {
+			return nil, err
+		}
+		memoryMax := "max"
+		if limits.Memory > 0 {
+			memoryMax = strconv.FormatInt(limits.Memory, 10)
+		}
+		err = writeLimit(groupDir, "memory.max", memoryMax, limits.Memory > 0)
+		if err != nil {
+			return nil, err
+		}
+		opts.Logger.Info(ctx, "created cgroup",
+			slog.F("group", group),
+			slog.F("path", groupDir),
+			slog.F("cpu_limit", limits.CPU),
+			slog.F("memory_limit", limits.Memory),
+		)
+	}
+
+	return &Manager{
+		dir:  dir,
+		proc: opts.Proc,
+		last: map[string]cpuSample{},
+	}, nil
+}
+
+// Path returns the directory of the group. Processes are placed in the
+// group by writing their PID to the cgroup.procs file in it.
+func (m *Manager) Path(group string) string {
+	return filepath.Join(m.dir, group)
+}
+
+// MoveSession moves the processes of the session with the given ID from one
+// group to another, and returns how many were moved. Scripts run in a session
+// of their own, so this moves what a script left running once it exits.
+func (m *Manager) MoveSession(sid int, from, to string) (int, error) {
+	procs, err := os.ReadFile(filepath.Join(m.Path(from), "cgroup.procs"))
+	if err != nil {
+		return 0, xerrors.Errorf("read processes: %w", err)
+	}
+	moved := 0
+	for _, pid := range strings.Fields(string(procs)) {
+		session, err := processSession(filepath.Join(m.proc, pid, "stat"))
+		if err != nil || session != sid {
+			// The process may have exited since the group was read.
+			continue
+		}
+		err = os.WriteFile(filepath.Join(m.Path(to), "cgroup.procs"), []byte(pid), 0o600)
+		if err != nil {
+			if processExited(err) {
+				continue
+			}
+			return moved, xerrors.Errorf("move process %s: %w", pid, err)
+		}
+		moved++
+	}
+	return moved, nil
+}
+
+// Stats returns the resource usage of every group.
+func (m *Manager) Stats(now time.Time) []Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := make([]Stats, 0, len(Groups))
+	for _, group := range Groups {
+		dir := m.Path(group)
+		s := Stats{Name: group}
+
+		usage, err := readKey(filepath.Join(dir, "cpu.stat"), "usage_usec")
+		if err == nil {
+			s.CPUUsage = time.Duration(usage) * time.Microsecond
+			if last, ok := m.last[group]; ok && now.After(last.at) {
+				s.CPU = float64(s.CPUUsage-last.usage) / float64(now.Sub(last.at))
+			}
+			m.last[group] = cpuSample{usage: s.CPUUsage, at: now}
+		}
+		if quota, period, err := readCPUMax(filepath.Join(dir, "cpu.max")); err == nil && quota > 0 && period > 0 {
+			s.CPULimit = float64(quota) / float64(period)
+		}
+		if memory, err := readInt(filepath.Join(dir, "memory.current")); err == nil {
+			s.Memory = memory
+		}
+		if limit, err := readInt(filepath.Join(dir, "memory.max")); err == nil {
+			s.MemoryLimit = limit
+		}
+		if kills, err := readKey(filepath.Join(dir, "memory.events"), "oom_kill"); err == nil {
+			s.OOMKills = kills
+		}
+		stats = append(stats, s)
+	}
+	return stats
+}
+
+// selfCgroup returns the cgroup v2 path of the agent.
+func selfCgroup(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", xerrors.Errorf("read %s: %w", path, err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		// The cgroup v2 hierarchy always has ID 0 and no controllers.
+		if cgroup, ok := strings.CutPrefix(line, "0::"); ok {
+			return cgroup, nil
+		}
+	}
+	return "", xerrors.New("the agent is not in a cgroup v2 hierarchy")
+}
+
+func mkdir(dir string) error {
+	err := os.Mkdir(dir, 0o755)
+	if err != nil && !errors.Is(err, os.ErrExist) {
+		return xerrors.Errorf("create cgroup: %w", err)
+	}
+	return nil
+}
+
+func moveProcesses(from, to string) error {
+	procs, err := os.ReadFile(filepath.Join(from, "cgroup.procs"))
+	if err != nil {
+		return xerrors.Errorf("read processes: %w", err)
+	}
+	for _, pid := range strings.Fields(string(procs)) {
+		err = os.WriteFile(filepath.Join(to, "cgroup.procs"), []byte(pid), 0o600)
+		if err != nil && !processExited(err) {
+			return xerrors.Errorf("move process %s: %w", pid, err)
+		}
+	}
+	return nil
+}
+
+// processExited reports whether moving a process failed because it exited
+// while it was being moved.
+func processExited(err error) bool {
+	return errors.Is(err, os.ErrNotExist) || strings.Contains(err.Error(), "no such process")
+}
+
+// processSession reads the session ID of a process from its stat file.
+func processSession(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	// The command name is in parentheses and may contain spaces, so the
+	// fields are counted from the last closing parenthesis.
+	i := bytes.LastIndexByte(data, ')')
+	if i < 0 {
+		return 0, xerrors.Errorf("invalid stat file %s", path)
+	}
+	// The fields after the name are state, ppid, pgrp and session.
+	fields := strings.Fields(string(data[i+1:]))
+	if len(fields) < 4 {
+		return 0, xerrors.Errorf("invalid stat file %s", path)
+	}
+	return strconv.Atoi(fields[3])
+}
+
+// writeLimit writes a limit to the interface file of a group. Resetting a
+// limit is allowed to fail, e.g. because the controller is not available.
+func writeLimit(dir, file, value string, required bool) error {
+	err := os.WriteFile(filepath.Join(dir, file), []byte(value), 0o600)
+	if err != nil && required {
+		return xerrors.Errorf("set %s: %w", file, err)
+	}
+	return nil
+}
+
+func readInt(path string) (int64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	value := strings.TrimSpace(string(data))
+	if value == "max" {
+		return 0, nil
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// readKey reads a value from a flat keyed file such as cpu.stat.
+func readKey(path, key string) (int64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == key {
+			return strconv.ParseInt(fields[1], 10, 64)
+		}
+	}
+	return 0, xerrors.Errorf("%s not found in %s", key, path)
+}
+
+func readCPUMax(path string) (quota int64, period int64, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) != 2 || fields[0] == "max" {
+		return 0, 0, nil
+	}
+	quota, err = strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	period, err = strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	return quota, period, nil
+}
@@ -0,0 +1,186 @@
+package agentcgroup_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/agent/agentcgroup"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestManager(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS != "linux" {
+		t.Skip("cgroups are only supported on Linux")
+	}
+
+	// setup creates a fake cgroup v2 hierarchy with the agent in
+	// /workspace.
+	setup := func(t *testing.T) (root, procSelf string) {
+		root = t.TempDir()
+		dir := filepath.Join(root, "workspace")
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		write(t, filepath.Join(dir, "cgroup.controllers"), "cpuset cpu io memory pids")
+		write(t, filepath.Join(dir, "cgroup.procs"), "1\n42\n")
+		procSelf = filepath.Join(t.TempDir(), "cgroup")
+		write(t, procSelf, "0::/workspace\n")
+		return root, procSelf
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		root, procSelf := setup(t)
+
+		m, err := agentcgroup.New(ctx, agentcgroup.Options{
+			Logger:         slogtest.Make(t, nil),
+			Root:           root,
+			ProcSelfCgroup: procSelf,
+			Limits: map[string]agentcgroup.Limits{
+				agentcgroup.GroupSessions: {CPU: 1.5, Memory: 4 << 30},
+			},
+		})
+		require.NoError(t, err)
+
+		dir := filepath.Join(root, "workspace")
+		require.Equal(t, "42", read(t, filepath.Join(dir, "agent", "cgroup.procs")))
+		require.Equal(t, "+cpu +memory", read(t, filepath.Join(dir, "cgroup.subtree_control")))
+		sessions := m.Path(agentcgroup.GroupSessions)
+		require.Equal(t, filepath.Join(dir, "sessions"), sessions)
+		require.Equal(t, "150000 100000", read(t, filepath.Join(sessions, "cpu.max")))
+		require.Equal(t, "4294967296", read(t, filepath.Join(sessions, "memory.max")))
+		scripts := m.Path(agentcgroup.GroupScripts)
+		require.Equal(t, "max 100000", read(t, filepath.Join(scripts, "cpu.max")))
+		require.Equal(t, "max", read(t, filepath.Join(scripts, "memory.max")))
+
+		// Simulate usage reported by the kernel.
+		write(t, filepath.Join(sessions, "cpu.stat"), "usage_usec 1000000\nuser_usec 800000\nsystem_usec 200000\n")
+		write(t, filepath.Join(sessions, "memory.current"), "1048576\n")
+		write(t, filepath.Join(sessions, "memory.events"), "low 0\nhigh 0\nmax 3\noom 1\noom_kill 2\n")
+		now := time.Now()
+		stats := m.Stats(now)
+		require.Len(t, stats, 3)
+		require.Equal(t, agentcgroup.Stats{
+			Name:        agentcgroup.GroupSessions,
+			CPUUsage:    time.Second,
+			CPULimit:    1.5,
+			Memory:      1 << 20,
+			MemoryLimit: 4 << 30,
+			OOMKills:    2,
+		}, stats[0])
+		require.Equal(t, agentcgroup.Stats{Name: agentcgroup.GroupScripts}, stats[1])
+		require.Equal(t, agentcgroup.Stats{Name: agentcgroup.GroupApps}, stats[2])
+
+		// CPU is averaged over the time since the previous sample.
+		write(t, filepath.Join(sessions, "cpu.stat"), "usage_usec 3000000\n")
+		stats = m.Stats(now.Add(4 * time.Second))
+		require.Equal(t, 3*time.Second, stats[0].CPUUsage)
+		require.InDelta(t, 0.5, stats[0].CPU, 0.0001)
+	})
+
+	t.Run("Restart", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		root, procSelf := setup(t)
+
+		_, err := agentcgroup.New(ctx, agentcgroup.Options{
+			Logger:         slogtest.Make(t, nil),
+			Root:           root,
+			ProcSelfCgroup: procSelf,
+		})
+		require.NoError(t, err)
+
+		// The restarted agent finds itself in the agent leaf.
+		write(t, procSelf, "0::/workspace/agent\n")
+		write(t, filepath.Join(root, "workspace", "agent", "cgroup.controllers"), "")
+		m, err := agentcgroup.New(ctx, agentcgroup.Options{
+			Logger:         slogtest.Make(t, nil),
+			Root:           root,
+			ProcSelfCgroup: procSelf,
+		})
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(root, "workspace", "sessions"), m.Path(agentcgroup.GroupSessions))
+	})
+
+	t.Run("MoveSession", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		root, procSelf := setup(t)
+		proc := t.TempDir()
+
+		m, err := agentcgroup.New(ctx, agentcgroup.Options{
+			Logger:         slogtest.Make(t, nil),
+			Root:           root,
+			ProcSelfCgroup: procSelf,
+			Proc:           proc,
+		})
+		require.NoError(t, err)
+
+		// 100 is a script that exited, 101 and 102 are processes it left
+		// running and 200 is another script. 300 has already exited.
+		for pid, stat := range map[string]string{
+			"101": "101 (code-server) S 1 100 100 0 -1",
+			"102": "102 (my (app) server) S 101 100 100 0 -1",
+			"200": "200 (bash) S 42 200 200 0 -1",
+		} {
+			require.NoError(t, os.MkdirAll(filepath.Join(proc, pid), 0o755))
+			write(t, filepath.Join(proc, pid, "stat"), stat)
+		}
+		write(t, filepath.Join(m.Path(agentcgroup.GroupScripts), "cgroup.procs"), "101\n102\n200\n300\n")
+
+		moved, err := m.MoveSession(100, agentcgroup.GroupScripts, agentcgroup.GroupApps)
+		require.NoError(t, err)
+		require.Equal(t, 2, moved)
+		// The fake interface file only holds the last process written.
+		require.Equal(t, "102", read(t, filepath.Join(m.Path(agentcgroup.GroupApps), "cgroup.procs")))
+	})
+
+	t.Run("NoCgroupV2", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		root, procSelf := setup(t)
+		write(t, procSelf, "12:memory:/workspace\n")
+
+		_, err := agentcgroup.New(ctx, agentcgroup.Options{
+			Logger:         slogtest.Make(t, nil),
+			Root:           root,
+			ProcSelfCgroup: procSelf,
+		})
+		require.ErrorContains(t, err, "not in a cgroup v2 hierarchy")
+	})
+}
+
+func TestParseLimits(t *testing.T) {
+	t.Parallel()
+
+	limits, err := agentcgroup.ParseLimits([]string{"sessions.cpu=2", "sessions.memory=4GiB", "scripts.memory=512MB"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]agentcgroup.Limits{
+		agentcgroup.GroupSessions: {CPU: 2, Memory: 4 << 30},
+		agentcgroup.GroupScripts:  {Memory: 512_000_000},
+	}, limits)
+
+	for _, invalid := range []string{"sessions", "sessions=1", "unknown.cpu=1", "sessions.disk=1", "sessions.cpu=-1", "sessions.memory=lots"} {
+		_, err := agentcgroup.ParseLimits([]string{invalid})
+		require.Error(t, err, invalid)
+	}
+}
+
+func write(t *testing.T, path, data string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+}
+
+func read(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
@@ -0,0 +1,57 @@
+package agentcgroup
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"golang.org/x/xerrors"
+)
+
+// Limits are the resource limits of a group. Zero values mean unlimited.
+type Limits struct {
+	// CPU is the number of CPUs the group may use, e.g. 1.5.
+	CPU float64
+	// Memory is the maximum memory of the group in bytes. Processes in the
+	// group are OOM killed when it is exceeded.
+	Memory int64
+}
+
+// ParseLimits parses limits in the form "<group>.<resource>=<value>", where
+// resource is "cpu" (a number of CPUs) or "memory" (a size such as 4GiB).
+func ParseLimits(values []string) (map[string]Limits, error) {
+	limits := map[string]Limits{}
+	for _, value := range values {
+		key, val, ok := strings.Cut(strings.TrimSpace(value), "=")
+		if !ok {
+			return nil, xerrors.Errorf("invalid limit %q: must be in the form <group>.<resource>=<value>", value)
+		}
+		group, resource, ok := strings.Cut(key, ".")
+		if !ok {
+			return nil, xerrors.Errorf("invalid limit %q: must be in the form <group>.<resource>=<value>", value)
+		}
+		if !slices.Contains(Groups, group) {
+			return nil, xerrors.Errorf("invalid limit %q: unknown group %q, must be one of %s", value, group, strings.Join(Groups, ", "))
+		}
+		l := limits[group]
+		switch resource {
+		case "cpu":
+			cpu, err := strconv.ParseFloat(val, 64)
+			if err != nil || cpu <= 0 {
+				return nil, xerrors.Errorf("invalid limit %q: cpu must be a positive number", value)
+			}
+			l.CPU = cpu
+		case "memory":
+			memory, err := humanize.ParseBytes(val)
+			if err != nil || memory == 0 {
+				return nil, xerrors.Errorf("invalid limit %q: memory must be a positive size", value)
+			}
+			l.Memory = int64(memory)
+		default:
+			return nil, xerrors.Errorf("invalid limit %q: unknown resource %q, must be cpu or memory", value, resource)
+		}
+		limits[group] = l
+	}
+	return limits, nil
+}
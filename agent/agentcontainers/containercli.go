@@ -0,0 +1,275 @@
+package agentcontainers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/agent/agentexec"
+)
+
+// Container is a container managed by the container runtime.
+type Container struct {
+	ID      string
+	Name    string
+	Running bool
+	Labels  map[string]string
+}
+
+// BuildOptions are the options for building an image.
+type BuildOptions struct {
+	Tag        string
+	Dockerfile string
+	Context    string
+	Target     string
+	Args       map[string]string
+}
+
+// RunOptions are the options for creating and starting a container.
+type RunOptions struct {
+	Name    string
+	Image   string
+	Labels  map[string]string
+	Env     map[string]string
+	User    string
+	WorkDir string
+	// Mounts are bind mounts from a host path to a container path.
+	Mounts map[string]string
+	// HostNetwork shares the network namespace of the agent with the
+	// container.
+	HostNetwork bool
+	// ExtraArgs are passed verbatim to the run command, before the image.
+	ExtraArgs []string
+}
+
+// ExecOptions are the options for executing a command in a container.
+type ExecOptions struct {
+	User    string
+	WorkDir string
+	Env     []string
+	TTY     bool
+	// Interactive keeps standard input attached.
+	Interactive bool
+}
+
+// ContainerCLI manages containers through a container runtime such as
+// Docker or Podman. Output of long-running operations is written to w.
+type ContainerCLI interface {
+	// List returns the containers, running or not, that carry all the
+	// given labels.
+	List(ctx context.Context, labels map[string]string) ([]Container, error)
+	Build(ctx context.Context, w io.Writer, opts BuildOptions) error
+	Run(ctx context.Context, w io.Writer, opts RunOptions) (string, error)
+	Start(ctx context.Context, w io.Writer, id string) error
+	Remove(ctx context.Context, w io.Writer, id string) error
+	Exec(ctx context.Context, w io.Writer, id string, opts ExecOptions, cmd ...string) error
+	// ExecCommand returns the program and arguments that execute cmd in
+	// the container, for use by callers that manage the process
+	// themselves, such as the SSH server. The values of the environment
+	// are not part of the arguments, so they don't show up in the process
+	// list: the returned environment must be added to the environment of
+	// the process.
+	ExecCommand(id string, opts ExecOptions, cmd ...string) (name string, args []string, env []string)
+}
+
+// NewDockerCLI returns a ContainerCLI that uses the docker binary, or the
+// podman binary when docker is not installed. Both talk to the local
+// container socket, which can be overridden with DOCKER_HOST or
+// CONTAINER_HOST respectively.
+func NewDockerCLI(execer agentexec.Execer) (ContainerCLI, error) {
+	for _, name := range []string{"docker", "podman"} {
+		bin, err := exec.LookPath(name)
+		if err == nil {
+			return &dockerCLI{execer: execer, binary: bin}, nil
+		}
+	}
+	return nil, xerrors.New("neither docker nor podman was found in PATH")
+}
+
+type dockerCLI struct {
+	execer agentexec.Execer
+	binary string
+}
+
+func (d *dockerCLI) run(ctx context.Context, w io.Writer, args ...string) error {
+	return d.runEnv(ctx, w, nil, args...)
+}
+
+// runEnv is like run, but adds env to the environment of the runtime.
+func (d *dockerCLI) runEnv(ctx context.Context, w io.Writer, env []string, args ...string) error {
+	var stderr bytes.Buffer
+	cmd := d.execer.CommandContext(ctx, d.binary, args...)
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	cmd.Stdout = w
+	cmd.Stderr = io.MultiWriter(w, &stderr)
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return xerrors.Errorf("%s %s: %w: %s", d.binary, args[0], err, lastLine(msg))
+		}
+		return xerrors.Errorf("%s %s: %w", d.binary, args[0], err)
+	}
+	return nil
+}
+
+func (d *dockerCLI) output(ctx context.Context, args ...string) ([]byte, error) {
+	var stdout bytes.Buffer
+	if err := d.run(ctx, &stdout, args...); err != nil {
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+func (d *dockerCLI) List(ctx context.Context, labels map[string]string) ([]Container, error) {
+	args := []string{"ps", "--all", "--quiet", "--no-trunc"}
+	for _, k := range sortedKeys(labels) {
+		args = append(args, "--filter", fmt.Sprintf("label=%s=%s", k, labels[k]))
+	}
+	out, err := d.output(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	ids := strings.Fields(string(out))
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	out, err = d.output(ctx, append([]string{"inspect", "--type", "container"}, ids...)...)
+	if err != nil {
+		return nil, err
+	}
+	var inspect []struct {
+		ID    string `json:"Id"`
+		Name  string `json:"Name"`
+		State struct {
+			Running bool `json:"Running"`
+		} `json:"State"`
+		Config struct {
+			Labels map[string]string `json:"Labels"`
+		} `json:"Config"`
+	}
+	if err := json.Unmarshal(out, &inspect); err != nil {
+		return nil, xerrors.Errorf("decode inspect output: %w", err)
+	}
+	containers := make([]Container, 0, len(inspect))
+	for _, c := range inspect {
+		containers = append(containers, Container{
+			ID:      c.ID,
+			Name:    strings.TrimPrefix(c.Name, "/"),
+			Running: c.State.Running,
+			Labels:  c.Config.Labels,
+		})
+	}
+	return containers, nil
+}
+
+func (d *dockerCLI) Build(ctx context.Context, w io.Writer, opts BuildOptions) error {
+	args := []string{"build", "--tag", opts.Tag, "--file", opts.Dockerfile}
+	if opts.Target != "" {
+		args = append(args, "--target", opts.Target)
+	}
+	for _, k := range sortedKeys(opts.Args) {
+		args = append(args, "--build-arg", k+"="+opts.Args[k])
+	}
+	args = append(args, opts.Context)
+	return d.run(ctx, w, args...)
+}
+
+func (d *dockerCLI) Run(ctx context.Context, w io.Writer, opts RunOptions) (string, error) {
+	args := []string{"run", "--detach", "--name", opts.Name}
+	for _, k := range sortedKeys(opts.Labels) {
+		args = append(args, "--label", k+"="+opts.Labels[k])
+	}
+	for _, k := range sortedKeys(opts.Env) {
+		args = append(args, "--env", k+"="+opts.Env[k])
+	}
+	for _, src := range sortedKeys(opts.Mounts) {
+		args = append(args, "--mount", fmt.Sprintf("type=bind,source=%s,target=%s", src, opts.Mounts[src]))
+	}
+	if opts.User != "" {
+		args = append(args, "--user", opts.User)
+	}
+	if opts.WorkDir != "" {
+		args = append(args, "--workdir", opts.WorkDir)
+	}
+	if opts.HostNetwork {
+		args = append(args, "--network", "host")
+	}
+	args = append(args, opts.ExtraArgs...)
+	// Keep the container alive regardless of the image's command, like the
+	// reference implementation does by default.
+	args = append(args, "--entrypoint", "/bin/sh", opts.Image, "-c",
+		`echo Container started; trap "exit 0" TERM; while sleep 1 & wait $!; do :; done`)
+
+	var stdout bytes.Buffer
+	if err := d.run(ctx, io.MultiWriter(w, &stdout), args...); err != nil {
+		return "", err
+	}
+	id := lastLine(strings.TrimSpace(stdout.String()))
+	if id == "" {
+		return "", xerrors.New("container runtime did not return a container id")
+	}
+	return id, nil
+}
+
+func (d *dockerCLI) Start(ctx context.Context, w io.Writer, id string) error {
+	return d.run(ctx, w, "start", id)
+}
+
+func (d *dockerCLI) Remove(ctx context.Context, w io.Writer, id string) error {
+	return d.run(ctx, w, "rm", "--force", id)
+}
+
+func (d *dockerCLI) Exec(ctx context.Context, w io.Writer, id string, opts ExecOptions, cmd ...string) error {
+	_, args, env := d.ExecCommand(id, opts, cmd...)
+	return d.runEnv(ctx, w, env, args...)
+}
+
+func (d *dockerCLI) ExecCommand(id string, opts ExecOptions, cmd ...string) (string, []string, []string) {
+	args := []string{"exec"}
+	if opts.Interactive {
+		args = append(args, "--interactive")
+	}
+	if opts.TTY {
+		args = append(args, "--tty")
+	}
+	if opts.User != "" {
+		args = append(args, "--user", opts.User)
+	}
+	if opts.WorkDir != "" {
+		args = append(args, "--workdir", opts.WorkDir)
+	}
+	// Only the names are passed, the runtime reads the values from its
+	// own environment.
+	for _, kv := range opts.Env {
+		k, _, _ := strings.Cut(kv, "=")
+		args = append(args, "--env", k)
+	}
+	args = append(args, id)
+	args = append(args, cmd...)
+	return d.binary, args, opts.Env
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func lastLine(s string) string {
+	if i := strings.LastIndexByte(s, '\n'); i >= 0 {
+		return strings.TrimSpace(s[i+1:])
+	}
+	return s
+}
@@ -0,0 +1,312 @@
+package agentcontainers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+	"golang.org/x/xerrors"
+)
+
+// ErrNoConfig is returned by FindConfig when a project directory does not
+// contain a dev container configuration.
+var ErrNoConfig = xerrors.New("no devcontainer.json found")
+
+// configPaths are the locations, relative to a project directory, that are
+// searched for a dev container configuration. The order matches the one
+// used by the dev container CLI.
+var configPaths = []string{
+	filepath.Join(".devcontainer", "devcontainer.json"),
+	".devcontainer.json",
+}
+
+// Config is the subset of the dev container specification that the agent
+// understands. See https://containers.dev/implementors/json_reference/.
+type Config struct {
+	Name              string            `json:"name"`
+	Image             string            `json:"image"`
+	Build             *BuildConfig      `json:"build"`
+	DockerFile        string            `json:"dockerFile"`
+	Context           string            `json:"context"`
+	DockerComposeFile json.RawMessage   `json:"dockerComposeFile"`
+	WorkspaceFolder   string            `json:"workspaceFolder"`
+	ContainerEnv      map[string]string `json:"containerEnv"`
+	RemoteEnv         map[string]string `json:"remoteEnv"`
+	ContainerUser     string            `json:"containerUser"`
+	RemoteUser        string            `json:"remoteUser"`
+	RunArgs           []string          `json:"runArgs"`
+	PostCreateCommand Command           `json:"postCreateCommand"`
+	PostStartCommand  Command           `json:"postStartCommand"`
+}
+
+// BuildConfig describes how the container image is built from a Dockerfile.
+type BuildConfig struct {
+	Dockerfile string            `json:"dockerfile"`
+	Context    string            `json:"context"`
+	Args       map[string]string `json:"args"`
+	Target     string            `json:"target"`
+}
+
+// Command is a lifecycle command. The specification allows a shell string,
+// an argument array, or an object of named commands in either form.
+type Command [][]string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (c *Command) UnmarshalJSON(data []byte) error {
+	*c = nil
+	var raw any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	parse := func(v any) ([]string, error) {
+		switch v := v.(type) {
+		case string:
+			if v == "" {
+				return nil, nil
+			}
+			return []string{"/bin/sh", "-c", v}, nil
+		case []any:
+			args := make([]string, 0, len(v))
+			for _, arg := range v {
+				s, ok := arg.(string)
+				if !ok {
+					return nil, xerrors.Errorf("command argument must be a string, got %T", arg)
+				}
+				args = append(args, s)
+			}
+			return args, nil
+		default:
+			return nil, xerrors.Errorf("command must be a string or array, got %T", v)
+		}
+	}
+	switch v := raw.(type) {
+	case nil:
+		return nil
+	case map[string]any:
+		// Named commands run in parallel in the reference implementation.
+		// We run them one by one in a stable order to keep the logs readable.
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			args, err := parse(v[name])
+			if err != nil {
+				return xerrors.Errorf("command %q: %w", name, err)
+			}
+			if len(args) > 0 {
+				*c = append(*c, args)
+			}
+		}
+	default:
+		args, err := parse(v)
+		if err != nil {
+			return err
+		}
+		if len(args) > 0 {
+			*c = append(*c, args)
+		}
+	}
+	return nil
+}
+
+// FindConfig returns the path of the dev container configuration in the
+// given project directory, or ErrNoConfig if there is none.
+func FindConfig(fs afero.Fs, dir string) (string, error) {
+	for _, p := range configPaths {
+		path := filepath.Join(dir, p)
+		_, err := fs.Stat(path)
+		if err == nil {
+			return path, nil
+		}
+		if !xerrors.Is(err, os.ErrNotExist) {
+			return "", xerrors.Errorf("stat %q: %w", path, err)
+		}
+	}
+	return "", ErrNoConfig
+}
+
+// ParseConfig parses a devcontainer.json document. Comments and trailing
+// commas are allowed, as in the reference implementation.
+func ParseConfig(data []byte) (Config, error) {
+	var config Config
+	dec := json.NewDecoder(bytes.NewReader(standardizeJSON(data)))
+	if err := dec.Decode(&config); err != nil {
+		return Config{}, xerrors.Errorf("decode devcontainer.json: %w", err)
+	}
+	if len(config.DockerComposeFile) > 0 {
+		return Config{}, xerrors.New("docker compose based dev containers are not supported")
+	}
+	if config.Build == nil && config.DockerFile != "" {
+		config.Build = &BuildConfig{
+			Dockerfile: config.DockerFile,
+			Context:    config.Context,
+		}
+	}
+	if config.Image == "" && (config.Build == nil || config.Build.Dockerfile == "") {
+		return Config{}, xerrors.New("devcontainer.json must specify an image or a Dockerfile")
+	}
+	return config, nil
+}
+
+// Hash returns a digest of the raw configuration. Containers created from
+// a different digest are recreated.
+func Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// standardizeJSON turns a JSON with comments document into standard JSON by
+// blanking out comments and dropping trailing commas. Strings are left
+// untouched.
+func standardizeJSON(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	// pendingComma is the index in out of a comma that is only kept if a
+	// value follows it.
+	pendingComma := -1
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case c == '"':
+			pendingComma = -1
+			start := i
+			for i++; i < len(data); i++ {
+				if data[i] == '\\' {
+					i++
+					continue
+				}
+				if data[i] == '"' {
+					break
+				}
+			}
+			end := i + 1
+			if end > len(data) {
+				end = len(data)
+			}
+			out = append(out, data[start:end]...)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			if i < len(data) {
+				out = append(out, '\n')
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			i += 2
+			for i+1 < len(data) && (data[i] != '*' || data[i+1] != '/') {
+				i++
+			}
+			i++
+			out = append(out, ' ')
+		case c == ',':
+			pendingComma = len(out)
+			out = append(out, c)
+		case c == '}' || c == ']':
+			if pendingComma >= 0 {
+				out[pendingComma] = ' '
+				pendingComma = -1
+			}
+			out = append(out, c)
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			out = append(out, c)
+		default:
+			pendingComma = -1
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+var variablePattern = regexp.MustCompile(`\$\{([^}:]+)(?::([^}:]+))?(?::([^}]*))?\}`)
+
+// substitute replaces the variables supported by the specification, such
+// as ${localWorkspaceFolder} and ${localEnv:NAME}. Unknown variables are
+// left as-is.
+func substitute(s string, vars map[string]string, lookupEnv func(string) (string, bool)) string {
+	return variablePattern.ReplaceAllStringFunc(s, func(match string) string {
+		parts := variablePattern.FindStringSubmatch(match)
+		name, arg, def := parts[1], parts[2], parts[3]
+		switch name {
+		case "localEnv", "env":
+			if v, ok := lookupEnv(arg); ok {
+				return v
+			}
+			return def
+		case "containerEnv":
+			// Resolved by the container itself, not supported here.
+			return match
+		}
+		if v, ok := vars[name]; ok && arg == "" {
+			return v
+		}
+		return match
+	})
+}
+
+// substituteConfig applies substitute to every string field of the
+// configuration that may reference variables.
+func substituteConfig(config *Config, localFolder string, lookupEnv func(string) (string, bool)) {
+	vars := map[string]string{
+		"localWorkspaceFolder":         localFolder,
+		"localWorkspaceFolderBasename": filepath.Base(localFolder),
+	}
+	config.WorkspaceFolder = substitute(config.WorkspaceFolder, vars, lookupEnv)
+	if config.WorkspaceFolder == "" {
+		config.WorkspaceFolder = "/workspaces/" + filepath.Base(localFolder)
+	}
+	vars["containerWorkspaceFolder"] = config.WorkspaceFolder
+	vars["containerWorkspaceFolderBasename"] = filepath.Base(config.WorkspaceFolder)
+
+	config.Image = substitute(config.Image, vars, lookupEnv)
+	for _, m := range []map[string]string{config.ContainerEnv, config.RemoteEnv} {
+		for k, v := range m {
+			m[k] = substitute(v, vars, lookupEnv)
+		}
+	}
+	if config.Build != nil {
+		for k, v := range config.Build.Args {
+			config.Build.Args[k] = substitute(v, vars, lookupEnv)
+		}
+	}
+	for i, arg := range config.RunArgs {
+		config.RunArgs[i] = substitute(arg, vars, lookupEnv)
+	}
+	for _, cmds := range []Command{config.PostCreateCommand, config.PostStartCommand} {
+		for _, args := range cmds {
+			for i, arg := range args {
+				args[i] = substitute(arg, vars, lookupEnv)
+			}
+		}
+	}
+}
+
+// sanitizeName converts a directory name into a name that is valid both as
+// a container name and as the target of "coder ssh workspace.name".
+func sanitizeName(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteRune('-')
+			}
+			dash = false
+			b.WriteRune(r)
+			continue
+		}
+		dash = true
+	}
+	name := b.String()
+	if name == "" {
+		name = "devcontainer"
+	}
+	return name
+}
@@ -0,0 +1,87 @@
+package agentcontainers_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent/agentcontainers"
+)
+
+func TestParseConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("JSONC", func(t *testing.T) {
+		t.Parallel()
+		config, err := agentcontainers.ParseConfig([]byte(`{
+			// The image to use.
+			"name": "Go // not a comment",
+			"image": "mcr.microsoft.com/devcontainers/go:1", /* inline */
+			"containerEnv": {
+				"URL": "http://example.com/*path*/",
+			},
+			"runArgs": ["--cap-add=SYS_PTRACE",],
+		}`))
+		require.NoError(t, err)
+		require.Equal(t, "Go // not a comment", config.Name)
+		require.Equal(t, "mcr.microsoft.com/devcontainers/go:1", config.Image)
+		require.Equal(t, map[string]string{"URL": "http://example.com/*path*/"}, config.ContainerEnv)
+		require.Equal(t, []string{"--cap-add=SYS_PTRACE"}, config.RunArgs)
+	})
+
+	t.Run("LegacyDockerFile", func(t *testing.T) {
+		t.Parallel()
+		config, err := agentcontainers.ParseConfig([]byte(`{"dockerFile": "Dockerfile", "context": ".."}`))
+		require.NoError(t, err)
+		require.Equal(t, &agentcontainers.BuildConfig{Dockerfile: "Dockerfile", Context: ".."}, config.Build)
+	})
+
+	t.Run("Commands", func(t *testing.T) {
+		t.Parallel()
+		config, err := agentcontainers.ParseConfig([]byte(`{
+			"image": "ubuntu",
+			"postCreateCommand": "make deps",
+			"postStartCommand": {"server": ["npm", "start"], "db": "make db"}
+		}`))
+		require.NoError(t, err)
+		require.Equal(t, agentcontainers.Command{{"/bin/sh", "-c", "make deps"}}, config.PostCreateCommand)
+		require.Equal(t, agentcontainers.Command{
+			{"/bin/sh", "-c", "make db"},
+			{"npm", "start"},
+		}, config.PostStartCommand)
+	})
+
+	t.Run("Compose", func(t *testing.T) {
+		t.Parallel()
+		_, err := agentcontainers.ParseConfig([]byte(`{"dockerComposeFile": "compose.yml", "service": "app"}`))
+		require.ErrorContains(t, err, "docker compose")
+	})
+
+	t.Run("NoImage", func(t *testing.T) {
+		t.Parallel()
+		_, err := agentcontainers.ParseConfig([]byte(`{"name": "empty"}`))
+		require.ErrorContains(t, err, "must specify an image")
+	})
+}
+
+func TestFindConfig(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/a/.devcontainer/devcontainer.json", []byte("{}"), 0o600))
+	require.NoError(t, afero.WriteFile(fs, "/a/.devcontainer.json", []byte("{}"), 0o600))
+	require.NoError(t, afero.WriteFile(fs, "/b/.devcontainer.json", []byte("{}"), 0o600))
+
+	path, err := agentcontainers.FindConfig(fs, "/a")
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join("/a", ".devcontainer", "devcontainer.json"), path)
+
+	path, err = agentcontainers.FindConfig(fs, "/b")
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join("/b", ".devcontainer.json"), path)
+
+	_, err = agentcontainers.FindConfig(fs, "/c")
+	require.ErrorIs(t, err, agentcontainers.ErrNoConfig)
+}
@@ -0,0 +1,395 @@
+// Package agentcontainers starts the dev containers defined in a workspace's
+// project directories and lets the agent run commands inside them.
+package agentcontainers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/spf13/afero"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/agent/agentexec"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// Labels set on every container created by the manager. They are used to
+// find the container again after an agent restart.
+const (
+	LabelLocalFolder = "coder.devcontainer.local_folder"
+	LabelConfigFile  = "coder.devcontainer.config_file"
+	LabelConfigHash  = "coder.devcontainer.config_hash"
+)
+
+// loginShell starts the login shell of the container user, falling back to
+// sh when the user has no passwd entry.
+var loginShell = []string{
+	"/bin/sh", "-c",
+	`shell=$(getent passwd "$(id -un)" 2>/dev/null | cut -d: -f7); exec "${shell:-/bin/sh}" -l`,
+}
+
+// Options are the options for a Manager.
+type Options struct {
+	Logger     slog.Logger
+	Filesystem afero.Fs
+	Execer     agentexec.Execer
+	// CLI manages the containers. If nil, docker or podman is looked up in
+	// PATH when the first dev container is started.
+	CLI ContainerCLI
+	// LogDir is where the output of building and starting each dev
+	// container is written.
+	LogDir string
+	// Directories are the project directories searched for a dev container
+	// configuration. Relative paths are resolved against the base
+	// directory passed to Start.
+	Directories []string
+	// LookupEnv resolves ${localEnv:NAME} variables. Defaults to
+	// os.LookupEnv.
+	LookupEnv func(string) (string, bool)
+}
+
+// Manager starts and tracks the dev containers of the agent.
+type Manager struct {
+	opts Options
+
+	mu            sync.RWMutex
+	cli           ContainerCLI
+	devcontainers []*devcontainer
+}
+
+type devcontainer struct {
+	codersdk.WorkspaceAgentDevcontainer
+
+	config Config
+	hash   string
+}
+
+// NewManager creates a manager for the given project directories.
+func NewManager(opts Options) *Manager {
+	if opts.Filesystem == nil {
+		opts.Filesystem = afero.NewOsFs()
+	}
+	if opts.Execer == nil {
+		opts.Execer = agentexec.DefaultExecer
+	}
+	if opts.LogDir == "" {
+		opts.LogDir = os.TempDir()
+	}
+	if opts.LookupEnv == nil {
+		opts.LookupEnv = os.LookupEnv
+	}
+	return &Manager{
+		opts: opts,
+		cli:  opts.CLI,
+	}
+}
+
+// Start discovers the dev containers in the project directories and builds
+// and starts them one after the other. Containers left over from a previous
+// run with the same configuration are reused. The returned error joins the
+// errors of all dev containers that failed to start.
+func (m *Manager) Start(ctx context.Context, baseDir string) error {
+	m.discover(baseDir)
+
+	m.mu.RLock()
+	devcontainers := m.devcontainers
+	m.mu.RUnlock()
+
+	var errs []error
+	for _, dc := range devcontainers {
+		if dc.Status == codersdk.WorkspaceAgentDevcontainerStatusError {
+			errs = append(errs, xerrors.Errorf("dev container %q: %s", dc.Name, dc.Error))
+			continue
+		}
+		logger := m.opts.Logger.With(slog.F("devcontainer", dc.Name), slog.F("local_folder", dc.LocalFolder))
+		logger.Info(ctx, "starting dev container")
+		id, err := m.up(ctx, dc)
+		m.mu.Lock()
+		if err != nil {
+			dc.Status = codersdk.WorkspaceAgentDevcontainerStatusError
+			dc.Error = err.Error()
+		} else {
+			dc.Status = codersdk.WorkspaceAgentDevcontainerStatusRunning
+			dc.ContainerID = id
+		}
+		m.mu.Unlock()
+		if err != nil {
+			logger.Error(ctx, "start dev container", slog.Error(err))
+			errs = append(errs, xerrors.Errorf("dev container %q: %w", dc.Name, err))
+			continue
+		}
+		logger.Info(ctx, "dev container running", slog.F("container_id", id))
+	}
+	return errors.Join(errs...)
+}
+
+// discover reads the configuration of every project directory. Directories
+// without a configuration are skipped.
+func (m *Manager) discover(baseDir string) {
+	names := make(map[string]int)
+	devcontainers := make([]*devcontainer, 0, len(m.opts.Directories))
+	for _, dir := range m.opts.Directories {
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(baseDir, dir)
+		}
+		dir = filepath.Clean(dir)
+
+		path, err := FindConfig(m.opts.Filesystem, dir)
+		if errors.Is(err, ErrNoConfig) {
+			m.opts.Logger.Warn(context.Background(), "no dev container configuration in project directory", slog.F("dir", dir))
+			continue
+		}
+
+		name := sanitizeName(filepath.Base(dir))
+		names[name]++
+		if n := names[name]; n > 1 {
+			name = fmt.Sprintf("%s-%d", name, n)
+		}
+		dc := &devcontainer{
+			WorkspaceAgentDevcontainer: codersdk.WorkspaceAgentDevcontainer{
+				Name:        name,
+				LocalFolder: dir,
+				ConfigPath:  path,
+				Status:      codersdk.WorkspaceAgentDevcontainerStatusStarting,
+			},
+		}
+		devcontainers = append(devcontainers, dc)
+
+		if err == nil {
+			err = m.load(dc)
+		}
+		if err != nil {
+			dc.Status = codersdk.WorkspaceAgentDevcontainerStatusError
+			dc.Error = err.Error()
+		}
+	}
+
+	m.mu.Lock()
+	m.devcontainers = devcontainers
+	m.mu.Unlock()
+}
+
+func (m *Manager) load(dc *devcontainer) error {
+	data, err := afero.ReadFile(m.opts.Filesystem, dc.ConfigPath)
+	if err != nil {
+		return xerrors.Errorf("read config: %w", err)
+	}
+	config, err := ParseConfig(data)
+	if err != nil {
+		return err
+	}
+	substituteConfig(&config, dc.LocalFolder, m.opts.LookupEnv)
+	dc.config = config
+	dc.hash = Hash(data)
+	dc.WorkspaceFolder = config.WorkspaceFolder
+	return nil
+}
+
+func (m *Manager) containerCLI() (ContainerCLI, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cli != nil {
+		return m.cli, nil
+	}
+	cli, err := NewDockerCLI(m.opts.Execer)
+	if err != nil {
+		return nil, err
+	}
+	m.cli = cli
+	return cli, nil
+}
+
+// up ensures the container of the dev container is running and returns its
+// ID.
+func (m *Manager) up(ctx context.Context, dc *devcontainer) (string, error) {
+	cli, err := m.containerCLI()
+	if err != nil {
+		return "", err
+	}
+
+	logPath := filepath.Join(m.opts.LogDir, fmt.Sprintf("coder-devcontainer-%s.log", dc.Name))
+	logFile, err := m.opts.Filesystem.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return "", xerrors.Errorf("open log file: %w", err)
+	}
+	defer logFile.Close()
+	w := io.Writer(logFile)
+
+	existing, err := cli.List(ctx, map[string]string{LabelLocalFolder: dc.LocalFolder})
+	if err != nil {
+		return "", xerrors.Errorf("list containers: %w", err)
+	}
+	var current *Container
+	for i, c := range existing {
+		if current == nil && c.Labels[LabelConfigHash] == dc.hash {
+			current = &existing[i]
+			continue
+		}
+		// The configuration changed since the container was created.
+		_, _ = fmt.Fprintf(w, "Removing outdated container %s\n", c.ID)
+		if err := cli.Remove(ctx, w, c.ID); err != nil {
+			return "", xerrors.Errorf("remove outdated container: %w", err)
+		}
+	}
+	if current != nil {
+		if current.Running {
+			return current.ID, nil
+		}
+		_, _ = fmt.Fprintf(w, "Starting existing container %s\n", current.ID)
+		if err := cli.Start(ctx, w, current.ID); err != nil {
+			return "", xerrors.Errorf("start container: %w", err)
+		}
+		if err := m.runCommands(ctx, cli, w, current.ID, dc, dc.config.PostStartCommand); err != nil {
+			return "", xerrors.Errorf("postStartCommand: %w", err)
+		}
+		return current.ID, nil
+	}
+
+	config := dc.config
+	image := config.Image
+	if config.Build != nil && config.Build.Dockerfile != "" {
+		configDir := filepath.Dir(dc.ConfigPath)
+		image = fmt.Sprintf("coder-devcontainer-%s:%s", dc.Name, dc.hash[:12])
+		_, _ = fmt.Fprintf(w, "Building image %s\n", image)
+		err = cli.Build(ctx, w, BuildOptions{
+			Tag:        image,
+			Dockerfile: filepath.Join(configDir, config.Build.Dockerfile),
+			Context:    filepath.Join(configDir, config.Build.Context),
+			Target:     config.Build.Target,
+			Args:       config.Build.Args,
+		})
+		if err != nil {
+			return "", xerrors.Errorf("build image: %w", err)
+		}
+	}
+
+	_, _ = fmt.Fprintf(w, "Creating container from %s\n", image)
+	id, err := cli.Run(ctx, w, RunOptions{
+		Name:  "coder-devcontainer-" + dc.Name,
+		Image: image,
+		Labels: map[string]string{
+			LabelLocalFolder: dc.LocalFolder,
+			LabelConfigFile:  dc.ConfigPath,
+			LabelConfigHash:  dc.hash,
+		},
+		Env:     config.ContainerEnv,
+		User:    config.ContainerUser,
+		WorkDir: config.WorkspaceFolder,
+		Mounts:  map[string]string{dc.LocalFolder: config.WorkspaceFolder},
+		// Sharing the agent's network makes ports opened in the container
+		// reachable by port forwarding and workspace apps.
+		HostNetwork: !hasNetworkArg(config.RunArgs),
+		ExtraArgs:   config.RunArgs,
+	})
+	if err != nil {
+		return "", xerrors.Errorf("run container: %w", err)
+	}
+	if err := m.runCommands(ctx, cli, w, id, dc, config.PostCreateCommand); err != nil {
+		return "", xerrors.Errorf("postCreateCommand: %w", err)
+	}
+	if err := m.runCommands(ctx, cli, w, id, dc, config.PostStartCommand); err != nil {
+		return "", xerrors.Errorf("postStartCommand: %w", err)
+	}
+	return id, nil
+}
+
+func (*Manager) runCommands(ctx context.Context, cli ContainerCLI, w io.Writer, id string, dc *devcontainer, cmds Command) error {
+	for _, args := range cmds {
+		_, _ = fmt.Fprintf(w, "Running %q\n", args)
+		if err := cli.Exec(ctx, w, id, dc.execOptions(nil, false), args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (dc *devcontainer) execOptions(env []string, tty bool) ExecOptions {
+	user := dc.config.RemoteUser
+	if user == "" {
+		user = dc.config.ContainerUser
+	}
+	execEnv := make([]string, 0, len(dc.config.RemoteEnv)+len(env))
+	for _, k := range sortedKeys(dc.config.RemoteEnv) {
+		execEnv = append(execEnv, k+"="+dc.config.RemoteEnv[k])
+	}
+	execEnv = append(execEnv, env...)
+	return ExecOptions{
+		User:    user,
+		WorkDir: dc.config.WorkspaceFolder,
+		Env:     execEnv,
+		TTY:     tty,
+	}
+}
+
+// List returns the dev containers known to the manager.
+func (m *Manager) List() []codersdk.WorkspaceAgentDevcontainer {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	list := make([]codersdk.WorkspaceAgentDevcontainer, 0, len(m.devcontainers))
+	for _, dc := range m.devcontainers {
+		list = append(list, dc.WorkspaceAgentDevcontainer)
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// Command returns the program and arguments that run script inside the
+// named dev container as its remote user, or a login shell if script is
+// empty. The environment is passed into the container through the returned
+// environment, which must be added to the environment of the program.
+func (m *Manager) Command(name, script string, env []string, tty bool) (string, []string, []string, error) {
+	m.mu.RLock()
+	var dc *devcontainer
+	for _, c := range m.devcontainers {
+		if c.Name == name {
+			dc = c
+			break
+		}
+	}
+	var (
+		status codersdk.WorkspaceAgentDevcontainerStatus
+		id     string
+		cli    = m.cli
+	)
+	if dc != nil {
+		status, id = dc.Status, dc.ContainerID
+	}
+	m.mu.RUnlock()
+
+	if dc == nil {
+		return "", nil, nil, xerrors.Errorf("dev container %q not found", name)
+	}
+	if status != codersdk.WorkspaceAgentDevcontainerStatusRunning {
+		return "", nil, nil, xerrors.Errorf("dev container %q is not running (%s)", name, status)
+	}
+
+	cmd := loginShell
+	if script != "" {
+		cmd = []string{"/bin/sh", "-c", script}
+	}
+	opts := dc.execOptions(env, tty)
+	opts.Interactive = true
+	bin, args, execEnv := cli.ExecCommand(id, opts, cmd...)
+	return bin, args, execEnv, nil
+}
+
+func hasNetworkArg(args []string) bool {
+	for _, arg := range args {
+		for _, flag := range []string{"--network", "--net"} {
+			if arg == flag || len(arg) > len(flag) && arg[:len(flag)+1] == flag+"=" {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,256 @@
+package agentcontainers_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/agent/agentcontainers"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m, testutil.GoleakOptions...)
+}
+
+func TestManager(t *testing.T) {
+	t.Parallel()
+
+	const config = `{
+		// Comments are allowed.
+		"image": "ubuntu:24.04",
+		"workspaceFolder": "/src/${localWorkspaceFolderBasename}",
+		"containerEnv": {"PROJECT": "${localWorkspaceFolder}", "TOKEN": "${localEnv:TOKEN}"},
+		"remoteUser": "dev",
+		"postCreateCommand": "make deps",
+	}`
+
+	newManager := func(t *testing.T, fs afero.Fs, cli agentcontainers.ContainerCLI, dirs ...string) *agentcontainers.Manager {
+		return agentcontainers.NewManager(agentcontainers.Options{
+			// Failing dev containers are logged as errors.
+			Logger:      slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}),
+			Filesystem:  fs,
+			CLI:         cli,
+			LogDir:      "/logs",
+			Directories: dirs,
+			LookupEnv: func(name string) (string, bool) {
+				if name == "TOKEN" {
+					return "secret", true
+				}
+				return "", false
+			},
+		})
+	}
+
+	t.Run("Start", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "/home/coder/My Project/.devcontainer/devcontainer.json", []byte(config), 0o600))
+		require.NoError(t, fs.MkdirAll("/home/coder/empty", 0o700))
+		cli := &fakeCLI{}
+
+		m := newManager(t, fs, cli, "My Project", "/home/coder/empty")
+		require.NoError(t, m.Start(ctx, "/home/coder"))
+
+		require.Len(t, cli.runs, 1)
+		run := cli.runs[0]
+		require.Equal(t, "coder-devcontainer-my-project", run.Name)
+		require.Equal(t, "ubuntu:24.04", run.Image)
+		require.Equal(t, "/src/My Project", run.WorkDir)
+		require.Equal(t, map[string]string{"/home/coder/My Project": "/src/My Project"}, run.Mounts)
+		require.Equal(t, map[string]string{"PROJECT": "/home/coder/My Project", "TOKEN": "secret"}, run.Env)
+		require.Equal(t, "/home/coder/My Project", run.Labels[agentcontainers.LabelLocalFolder])
+		require.True(t, run.HostNetwork)
+		require.Equal(t, []string{"container-1: /bin/sh -c make deps"}, cli.execs)
+
+		list := m.List()
+		require.Len(t, list, 1)
+		require.Equal(t, codersdk.WorkspaceAgentDevcontainer{
+			Name:            "my-project",
+			LocalFolder:     "/home/coder/My Project",
+			ConfigPath:      "/home/coder/My Project/.devcontainer/devcontainer.json",
+			WorkspaceFolder: "/src/My Project",
+			ContainerID:     "container-1",
+			Status:          codersdk.WorkspaceAgentDevcontainerStatusRunning,
+		}, list[0])
+
+		name, args, env, err := m.Command("my-project", "", []string{"TERM=xterm"}, true)
+		require.NoError(t, err)
+		require.Equal(t, "fake", name)
+		// Values are passed through the environment, not the arguments.
+		require.Equal(t, "exec -i -t -u dev -w /src/My Project -e TERM container-1", strings.Join(args[:len(args)-3], " "))
+		require.Contains(t, env, "TERM=xterm")
+
+		_, _, _, err = m.Command("unknown", "", nil, false)
+		require.ErrorContains(t, err, "not found")
+
+		// A new manager, e.g. after an agent restart, reuses the container.
+		m = newManager(t, fs, cli, "/home/coder/My Project")
+		require.NoError(t, m.Start(ctx, "/"))
+		require.Len(t, cli.runs, 1)
+		require.Equal(t, "container-1", m.List()[0].ContainerID)
+	})
+
+	t.Run("Recreate", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "/project/.devcontainer.json", []byte(config), 0o600))
+		cli := &fakeCLI{}
+		require.NoError(t, newManager(t, fs, cli, "/project").Start(ctx, "/"))
+		cli.containers[0].Running = false
+
+		// A stopped container is started again along with postStartCommand.
+		require.NoError(t, newManager(t, fs, cli, "/project").Start(ctx, "/"))
+		require.Len(t, cli.runs, 1)
+		require.True(t, cli.containers[0].Running)
+
+		// Changing the configuration replaces the container.
+		require.NoError(t, afero.WriteFile(fs, "/project/.devcontainer.json", []byte(`{"build": {"dockerfile": "Dockerfile"}}`), 0o600))
+		require.NoError(t, newManager(t, fs, cli, "/project").Start(ctx, "/"))
+		require.Len(t, cli.runs, 2)
+		require.Equal(t, []string{"container-1"}, cli.removed)
+		require.Len(t, cli.builds, 1)
+		require.Equal(t, "/project/Dockerfile", cli.builds[0].Dockerfile)
+		require.Equal(t, "/project", cli.builds[0].Context)
+		require.Equal(t, cli.builds[0].Tag, cli.runs[1].Image)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "/bad/.devcontainer.json", []byte(`{"name": "no image"}`), 0o600))
+		require.NoError(t, afero.WriteFile(fs, "/good/.devcontainer.json", []byte(config), 0o600))
+		cli := &fakeCLI{runErr: xerrors.New("image not found")}
+
+		m := newManager(t, fs, cli, "/bad", "/good")
+		err := m.Start(ctx, "/")
+		require.ErrorContains(t, err, "must specify an image")
+		require.ErrorContains(t, err, "image not found")
+
+		list := m.List()
+		require.Len(t, list, 2)
+		for _, dc := range list {
+			require.Equal(t, codersdk.WorkspaceAgentDevcontainerStatusError, dc.Status)
+			require.NotEmpty(t, dc.Error)
+		}
+		_, _, _, err = m.Command("good", "", nil, false)
+		require.ErrorContains(t, err, "not running")
+	})
+}
+
+// fakeCLI is an in-memory container runtime.
+type fakeCLI struct {
+	mu         sync.Mutex
+	runErr     error
+	containers []agentcontainers.Container
+	builds     []agentcontainers.BuildOptions
+	runs       []agentcontainers.RunOptions
+	removed    []string
+	execs      []string
+}
+
+func (f *fakeCLI) List(_ context.Context, labels map[string]string) ([]agentcontainers.Container, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var containers []agentcontainers.Container
+	for _, c := range f.containers {
+		match := true
+		for k, v := range labels {
+			match = match && c.Labels[k] == v
+		}
+		if match {
+			containers = append(containers, c)
+		}
+	}
+	return containers, nil
+}
+
+func (f *fakeCLI) Build(_ context.Context, _ io.Writer, opts agentcontainers.BuildOptions) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.builds = append(f.builds, opts)
+	return nil
+}
+
+func (f *fakeCLI) Run(_ context.Context, _ io.Writer, opts agentcontainers.RunOptions) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.runErr != nil {
+		return "", f.runErr
+	}
+	f.runs = append(f.runs, opts)
+	id := fmt.Sprintf("container-%d", len(f.runs))
+	f.containers = append(f.containers, agentcontainers.Container{
+		ID:      id,
+		Name:    opts.Name,
+		Running: true,
+		Labels:  opts.Labels,
+	})
+	return id, nil
+}
+
+func (f *fakeCLI) Start(_ context.Context, _ io.Writer, id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := range f.containers {
+		if f.containers[i].ID == id {
+			f.containers[i].Running = true
+			return nil
+		}
+	}
+	return xerrors.Errorf("no such container %q", id)
+}
+
+func (f *fakeCLI) Remove(_ context.Context, _ io.Writer, id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.removed = append(f.removed, id)
+	for i := range f.containers {
+		if f.containers[i].ID == id {
+			f.containers = append(f.containers[:i], f.containers[i+1:]...)
+			return nil
+		}
+	}
+	return xerrors.Errorf("no such container %q", id)
+}
+
+func (f *fakeCLI) Exec(_ context.Context, _ io.Writer, id string, _ agentcontainers.ExecOptions, cmd ...string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.execs = append(f.execs, id+": "+strings.Join(cmd, " "))
+	return nil
+}
+
+func (*fakeCLI) ExecCommand(id string, opts agentcontainers.ExecOptions, cmd ...string) (string, []string, []string) {
+	args := []string{"exec"}
+	if opts.Interactive {
+		args = append(args, "-i")
+	}
+	if opts.TTY {
+		args = append(args, "-t")
+	}
+	args = append(args, "-u", opts.User, "-w", opts.WorkDir)
+	for _, kv := range opts.Env {
+		k, _, _ := strings.Cut(kv, "=")
+		args = append(args, "-e", k)
+	}
+	args = append(args, id)
+	return "fake", append(args, cmd...), opts.Env
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"slices"
 	"strconv"
@@ -29,9 +30,11 @@ func CLI() error {
 	defer runtime.UnlockOSThread()
 
 	var (
-		fs   = flag.NewFlagSet("agent-exec", flag.ExitOnError)
-		nice = fs.Int("coder-nice", unset, "")
-		oom  = fs.Int("coder-oom", unset, "")
+		fs       = flag.NewFlagSet("agent-exec", flag.ExitOnError)
+		nice     = fs.Int("coder-nice", unset, "")
+		oom      = fs.Int("coder-oom", unset, "")
+		cgroup   = fs.String("coder-cgroup", "", "")
+		skipPrio = fs.Bool("coder-skip-prio", false, "")
 	)
 
 	if len(os.Args) < 3 {
@@ -50,6 +53,21 @@ func CLI() error {
 		return xerrors.Errorf("no exec command provided %+v", os.Args)
 	}
 
+	if *cgroup != "" {
+		// Move the process into the cgroup before exec'ing so that every
+		// child of the command is accounted for in the group as well.
+		err = writeCgroupProcs(*cgroup)
+		if err != nil {
+			// We alert the user instead of failing the command, the
+			// command still runs without the limits of the group.
+			printfStdErr("failed to move %+v to cgroup %q: %v", args, *cgroup, err)
+		}
+	}
+
+	if *skipPrio {
+		return execCmd(args)
+	}
+
 	if *oom == unset {
 		// If an explicit oom score isn't set, we use the default.
 		*oom, err = defaultOOMScore()
@@ -107,6 +125,10 @@ func CLI() error {
 		printfStdErr("failed to adjust niceness to %d for cmd %+v: %v", *nice, args, err)
 	}
 
+	return execCmd(args)
+}
+
+func execCmd(args []string) error {
 	path, err := exec.LookPath(args[0])
 	if err != nil {
 		return xerrors.Errorf("look path: %w", err)
@@ -170,6 +192,11 @@ func oomScoreAdj() (int, error) {
 	return strconv.Atoi(strings.TrimSpace(string(scoreStr)))
 }
 
+// writeCgroupProcs moves the current process into the cgroup v2 group at dir.
+func writeCgroupProcs(dir string) error {
+	return os.WriteFile(filepath.Join(dir, "cgroup.procs"), []byte("0"), 0o600)
+}
+
 func writeOOMScoreAdj(score int) error {
 	return os.WriteFile(fmt.Sprintf("/proc/%d/oom_score_adj", os.Getpid()), []byte(fmt.Sprintf("%d", score)), 0o600)
 }
@@ -80,6 +80,33 @@ func TestCLI(t *testing.T) {
 		requireNiceScore(t, cmd.Process.Pid, expectedNice)
 	})
 
+	t.Run("Cgroup", func(t *testing.T) {
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		cmd, path := cmd(ctx, t, 0, 0)
+		// A regular directory stands in for the cgroup, the process
+		// writes its PID to the cgroup.procs file in it.
+		dir := t.TempDir()
+		cmd.Args = slices.Insert(cmd.Args, 2, "--coder-cgroup="+dir, "--coder-skip-prio")
+		err := cmd.Start()
+		require.NoError(t, err)
+		go cmd.Wait()
+
+		waitForSentinel(ctx, t, cmd, path)
+		procs, err := os.ReadFile(filepath.Join(dir, "cgroup.procs"))
+		require.NoError(t, err)
+		require.Equal(t, "0", string(procs))
+
+		// Priority is left unchanged.
+		oom, err := os.ReadFile(fmt.Sprintf("/proc/%d/oom_score_adj", os.Getpid()))
+		require.NoError(t, err)
+		oomScore, err := strconv.Atoi(strings.TrimSpace(string(oom)))
+		require.NoError(t, err)
+		requireOOMScore(t, cmd.Process.Pid, oomScore)
+		nice, err := unix.Getpriority(unix.PRIO_PROCESS, os.Getpid())
+		require.NoError(t, err)
+		requireNiceScore(t, cmd.Process.Pid, 20-nice)
+	})
+
 	t.Run("Capabilities", func(t *testing.T) {
 		testdir := filepath.Dir(TestBin)
 		capDir := filepath.Join(testdir, "caps")
@@ -76,6 +76,34 @@ func NewExecer() (Execer, error) {
 	}, nil
 }
 
+// WithCgroup returns an Execer that places child processes in the cgroup
+// v2 group at dir before exec'ing them. Process priority is only managed if
+// it is managed by the provided Execer.
+func WithCgroup(e Execer, dir string) (Execer, error) {
+	if pe, ok := e.(priorityExecer); ok {
+		pe.cgroup = dir
+		return pe, nil
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, xerrors.Errorf("get executable: %w", err)
+	}
+
+	bin, err := filepath.EvalSymlinks(executable)
+	if err != nil {
+		return nil, xerrors.Errorf("eval symlinks: %w", err)
+	}
+
+	return priorityExecer{
+		binPath:   bin,
+		oomScore:  unset,
+		niceScore: unset,
+		cgroup:    dir,
+		skipPrio:  true,
+	}, nil
+}
+
 type execer struct{}
 
 func (execer) CommandContext(ctx context.Context, cmd string, args ...string) *exec.Cmd {
@@ -90,6 +118,10 @@ type priorityExecer struct {
 	binPath   string
 	oomScore  int
 	niceScore int
+	// cgroup is the directory of the cgroup child processes are placed in.
+	cgroup string
+	// skipPrio leaves the priority of child processes unchanged.
+	skipPrio bool
 }
 
 func (e priorityExecer) CommandContext(ctx context.Context, cmd string, args ...string) *exec.Cmd {
@@ -111,6 +143,14 @@ func (e priorityExecer) agentExecCmd(cmd string, args ...string) (string, []stri
 	if e.niceScore != unset {
 		execArgs = append(execArgs, niceScoreArg(e.niceScore))
 	}
+
+	if e.cgroup != "" {
+		execArgs = append(execArgs, cgroupArg(e.cgroup))
+	}
+
+	if e.skipPrio {
+		execArgs = append(execArgs, skipPrioArg())
+	}
 	execArgs = append(execArgs, "--", cmd)
 	execArgs = append(execArgs, args...)
 
@@ -136,8 +176,10 @@ func envValInt(key string) (int, bool) {
 // environment variables to avoid having to deal with a caller overriding the
 // environment variables.
 const (
-	niceFlag = "coder-nice"
-	oomFlag  = "coder-oom"
+	niceFlag     = "coder-nice"
+	oomFlag      = "coder-oom"
+	cgroupFlag   = "coder-cgroup"
+	skipPrioFlag = "coder-skip-prio"
 )
 
 func niceScoreArg(score int) string {
@@ -147,3 +189,11 @@ func niceScoreArg(score int) string {
 func oomScoreArg(score int) string {
 	return fmt.Sprintf("--%s=%d", oomFlag, score)
 }
+
+func cgroupArg(dir string) string {
+	return fmt.Sprintf("--%s=%s", cgroupFlag, dir)
+}
+
+func skipPrioArg() string {
+	return fmt.Sprintf("--%s", skipPrioFlag)
+}
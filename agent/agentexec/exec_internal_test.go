@@ -81,4 +81,33 @@ func TestExecer(t *testing.T) {
 			require.Equal(t, []string{e.binPath, "agent-exec", "--coder-oom=432", "--coder-nice=14", "--", "sh", "-c", "sleep"}, cmd.Args)
 		})
 	})
+
+	t.Run("Cgroup", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("Priority", func(t *testing.T) {
+			t.Parallel()
+
+			e, err := WithCgroup(priorityExecer{
+				binPath:   "/foo/bar/baz",
+				oomScore:  unset,
+				niceScore: 10,
+			}, "/sys/fs/cgroup/sessions")
+			require.NoError(t, err)
+
+			cmd := e.CommandContext(context.Background(), "sh", "-c", "sleep")
+			require.Equal(t, "/foo/bar/baz", cmd.Path)
+			require.Equal(t, []string{"/foo/bar/baz", "agent-exec", "--coder-nice=10", "--coder-cgroup=/sys/fs/cgroup/sessions", "--", "sh", "-c", "sleep"}, cmd.Args)
+		})
+
+		t.Run("Default", func(t *testing.T) {
+			t.Parallel()
+
+			e, err := WithCgroup(DefaultExecer, "/sys/fs/cgroup/scripts")
+			require.NoError(t, err)
+
+			cmd := e.PTYCommandContext(context.Background(), "sh", "-c", "sleep")
+			require.Equal(t, []string{"agent-exec", "--coder-cgroup=/sys/fs/cgroup/scripts", "--coder-skip-prio", "--", "sh", "-c", "sleep"}, cmd.Args[1:])
+		})
+	})
 }
@@ -0,0 +1,338 @@
+// Package agentrecord records the output of interactive terminal sessions in
+// the asciicast v2 format and uploads the recordings to coderd in chunks.
+//
+// See https://docs.asciinema.org/manual/asciicast/v2/ for the format.
+package agentrecord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/agent/proto"
+)
+
+const (
+	// maxChunkSize is the amount of recorded output that is buffered before
+	// it is queued for upload.
+	maxChunkSize = 64 << 10
+	// flushInterval is how often buffered output is queued for upload, so
+	// that recordings of quiet sessions are not held back indefinitely.
+	flushInterval = 5 * time.Second
+	// maxQueuedBytes limits how much recorded output is held in memory while
+	// coderd is unreachable. Chunks exceeding the limit are dropped.
+	maxQueuedBytes = 16 << 20
+)
+
+// Dest is the destination for recorded chunks, normally the Agent API.
+type Dest interface {
+	UploadSessionRecording(ctx context.Context, req *proto.UploadSessionRecordingRequest) (*proto.UploadSessionRecordingResponse, error)
+}
+
+// Uploader queues the chunks of all active recordings and sends them to
+// coderd.
+type Uploader struct {
+	*sync.Cond
+	logger    slog.Logger
+	queue     []*proto.UploadSessionRecordingRequest
+	queued    int
+	recorders map[*Recorder]struct{}
+}
+
+func NewUploader(logger slog.Logger) *Uploader {
+	return &Uploader{
+		Cond:      sync.NewCond(&sync.Mutex{}),
+		logger:    logger,
+		recorders: make(map[*Recorder]struct{}),
+	}
+}
+
+// Start starts a new recording of a terminal with the given size. The caller
+// must write the output of the terminal to the returned recorder and close it
+// when the session ends.
+func (u *Uploader) Start(sessionType proto.UploadSessionRecordingRequest_Type, width, height uint16, term string) *Recorder {
+	r := &Recorder{
+		uploader:    u,
+		id:          uuid.New(),
+		sessionType: sessionType,
+		start:       time.Now(),
+	}
+	hdr := header{
+		Version:   2,
+		Width:     width,
+		Height:    height,
+		Timestamp: r.start.Unix(),
+	}
+	if term != "" {
+		hdr.Env = map[string]string{"TERM": term}
+	}
+	// The header is always valid JSON.
+	b, _ := json.Marshal(hdr)
+	r.buf.Write(b)
+	r.buf.WriteByte('\n')
+
+	u.L.Lock()
+	u.recorders[r] = struct{}{}
+	u.L.Unlock()
+
+	// Upload the header right away so the recording shows up while the
+	// session is still running.
+	r.mu.Lock()
+	r.flushLocked(false)
+	r.mu.Unlock()
+	return r
+}
+
+func (u *Uploader) enqueue(r *Recorder, req *proto.UploadSessionRecordingRequest) {
+	u.L.Lock()
+	defer u.L.Unlock()
+	if req.Final {
+		delete(u.recorders, r)
+	}
+	if u.queued+len(req.Data) > maxQueuedBytes {
+		u.logger.Warn(context.Background(), "session recording queue is full, dropping recorded output",
+			slog.F("recording_id", r.id),
+			slog.F("sequence", req.Sequence),
+			slog.F("bytes", len(req.Data)),
+		)
+		if !req.Final {
+			return
+		}
+		// Still end the recording, just without the output.
+		req.Data = nil
+	}
+	u.queue = append(u.queue, req)
+	u.queued += len(req.Data)
+	u.Broadcast()
+}
+
+// flushAll queues the buffered output of all active recordings.
+func (u *Uploader) flushAll() {
+	u.L.Lock()
+	recorders := make([]*Recorder, 0, len(u.recorders))
+	for r := range u.recorders {
+		recorders = append(recorders, r)
+	}
+	u.L.Unlock()
+
+	for _, r := range recorders {
+		r.mu.Lock()
+		if !r.closed && r.buf.Len() > 0 {
+			r.flushLocked(false)
+		}
+		r.mu.Unlock()
+	}
+}
+
+// SendLoop sends queued chunks to dest until the context is canceled or
+// sending fails. Chunks are sent in order, and a chunk that fails to send is
+// retried by the next call, unless coderd rejected it permanently: then it is
+// dropped so it doesn't hold back the chunks after it.
+func (u *Uploader) SendLoop(ctx context.Context, dest Dest) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		tkr := time.NewTicker(flushInterval)
+		defer tkr.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				u.L.Lock()
+				u.Broadcast()
+				u.L.Unlock()
+				return
+			case <-tkr.C:
+				u.flushAll()
+			}
+		}
+	}()
+
+	u.L.Lock()
+	defer u.L.Unlock()
+	for {
+		for ctx.Err() == nil && len(u.queue) == 0 {
+			u.Wait()
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		req := u.queue[0]
+		u.L.Unlock()
+		_, err := dest.UploadSessionRecording(ctx, req)
+		u.L.Lock()
+		if err != nil && !proto.IsSessionRecordingChunkRejected(err) {
+			return xerrors.Errorf("upload session recording: %w", err)
+		}
+		if err != nil {
+			id, _ := uuid.FromBytes(req.Id)
+			u.logger.Warn(ctx, "session recording chunk was rejected, dropping it",
+				slog.F("recording_id", id),
+				slog.F("sequence", req.Sequence),
+				slog.Error(err),
+			)
+		}
+		u.queue = u.queue[1:]
+		u.queued -= len(req.Data)
+		// Might unblock WaitUntilEmpty.
+		u.Broadcast()
+	}
+}
+
+// WaitUntilEmpty waits until all queued chunks have been sent or the context
+// is canceled.
+func (u *Uploader) WaitUntilEmpty(ctx context.Context) error {
+	ctxDone := false
+	nevermind := make(chan struct{})
+	defer close(nevermind)
+	go func() {
+		select {
+		case <-ctx.Done():
+			u.L.Lock()
+			defer u.L.Unlock()
+			ctxDone = true
+			u.Broadcast()
+		case <-nevermind:
+		}
+	}()
+	u.L.Lock()
+	defer u.L.Unlock()
+	for len(u.queue) != 0 && !ctxDone {
+		u.Wait()
+	}
+	if len(u.queue) == 0 {
+		return nil
+	}
+	return ctx.Err()
+}
+
+type header struct {
+	Version   int               `json:"version"`
+	Width     uint16            `json:"width"`
+	Height    uint16            `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Recorder records the output of a single session. It is safe for concurrent
+// use, and writes never fail so that recording cannot interrupt a session.
+type Recorder struct {
+	uploader    *Uploader
+	id          uuid.UUID
+	sessionType proto.UploadSessionRecordingRequest_Type
+	start       time.Time
+
+	mu       sync.Mutex // Protects following.
+	buf      bytes.Buffer
+	partial  []byte
+	sequence int32
+	closed   bool
+}
+
+// ID returns the ID of the recording.
+func (r *Recorder) ID() uuid.UUID {
+	return r.id
+}
+
+// Write records output sent to the terminal.
+func (r *Recorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return len(p), nil
+	}
+
+	// Output events must be valid UTF-8, so hold back a multi-byte character
+	// that is split across writes until the rest of it arrives.
+	data := append(r.partial, p...)
+	r.partial = nil
+	if n := incompleteSuffix(data); n > 0 {
+		r.partial = append([]byte(nil), data[len(data)-n:]...)
+		data = data[:len(data)-n]
+	}
+	if len(data) > 0 {
+		r.writeEventLocked("o", string(data))
+	}
+	return len(p), nil
+}
+
+// Resize records a change to the size of the terminal.
+func (r *Recorder) Resize(width, height uint16) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return
+	}
+	r.writeEventLocked("r", fmt.Sprintf("%dx%d", width, height))
+}
+
+// Close ends the recording and queues any remaining output for upload.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil
+	}
+	if len(r.partial) > 0 {
+		r.writeEventLocked("o", string(r.partial))
+		r.partial = nil
+	}
+	r.closed = true
+	r.flushLocked(true)
+	return nil
+}
+
+func (r *Recorder) writeEventLocked(code, data string) {
+	elapsed := time.Since(r.start).Seconds()
+	// Strings are always valid JSON, invalid UTF-8 is replaced.
+	encoded, _ := json.Marshal(data)
+	r.buf.WriteByte('[')
+	r.buf.WriteString(strconv.FormatFloat(elapsed, 'f', 6, 64))
+	r.buf.WriteString(`, "`)
+	r.buf.WriteString(code)
+	r.buf.WriteString(`", `)
+	r.buf.Write(encoded)
+	r.buf.WriteString("]\n")
+	if r.buf.Len() >= maxChunkSize {
+		r.flushLocked(false)
+	}
+}
+
+func (r *Recorder) flushLocked(final bool) {
+	req := &proto.UploadSessionRecordingRequest{
+		Id:       r.id[:],
+		Type:     r.sessionType,
+		Sequence: r.sequence,
+		Data:     bytes.Clone(r.buf.Bytes()),
+		Final:    final,
+	}
+	r.sequence++
+	r.buf.Reset()
+	r.uploader.enqueue(r, req)
+}
+
+// incompleteSuffix returns the length of an incomplete UTF-8 encoded
+// character at the end of p.
+func incompleteSuffix(p []byte) int {
+	// Look for the start of the last character within the maximum length of
+	// an encoded character.
+	for i := 1; i <= utf8.UTFMax && i <= len(p); i++ {
+		c := p[len(p)-i]
+		if utf8.RuneStart(c) {
+			if utf8.FullRune(p[len(p)-i:]) {
+				return 0
+			}
+			return i
+		}
+	}
+	return 0
+}
@@ -0,0 +1,198 @@
+package agentrecord_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"golang.org/x/xerrors"
+	"storj.io/drpc/drpcerr"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/agent/agentrecord"
+	"github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m, testutil.GoleakOptions...)
+}
+
+type fakeDest struct {
+	mu   sync.Mutex
+	reqs []*proto.UploadSessionRecordingRequest
+	// errFn, if set, returns the error of uploading a chunk. Chunks that fail
+	// to upload are not recorded.
+	errFn func(req *proto.UploadSessionRecordingRequest) error
+}
+
+func (f *fakeDest) UploadSessionRecording(_ context.Context, req *proto.UploadSessionRecordingRequest) (*proto.UploadSessionRecordingResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.errFn != nil {
+		if err := f.errFn(req); err != nil {
+			return nil, err
+		}
+	}
+	f.reqs = append(f.reqs, req)
+	return &proto.UploadSessionRecordingResponse{}, nil
+}
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	logger := slogtest.Make(t, nil)
+	uploader := agentrecord.NewUploader(logger)
+
+	rec := uploader.Start(proto.UploadSessionRecordingRequest_SSH, 80, 24, "xterm-256color")
+	_, err := rec.Write([]byte("hello "))
+	require.NoError(t, err)
+	// Split a multi-byte character across writes.
+	_, err = rec.Write([]byte("w\xc3"))
+	require.NoError(t, err)
+	_, err = rec.Write([]byte("\xb6rld\r\n"))
+	require.NoError(t, err)
+	rec.Resize(120, 40)
+	require.NoError(t, rec.Close())
+	// Writes after closing are ignored.
+	_, err = rec.Write([]byte("ignored"))
+	require.NoError(t, err)
+
+	dest := &fakeDest{}
+	sendCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- uploader.SendLoop(sendCtx, dest)
+	}()
+	require.NoError(t, uploader.WaitUntilEmpty(ctx))
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+
+	require.Len(t, dest.reqs, 2)
+	var data bytes.Buffer
+	for i, req := range dest.reqs {
+		id, err := uuid.FromBytes(req.Id)
+		require.NoError(t, err)
+		assert.Equal(t, rec.ID(), id)
+		assert.Equal(t, proto.UploadSessionRecordingRequest_SSH, req.Type)
+		assert.EqualValues(t, i, req.Sequence)
+		assert.Equal(t, i == len(dest.reqs)-1, req.Final)
+		data.Write(req.Data)
+	}
+
+	scanner := bufio.NewScanner(&data)
+	require.True(t, scanner.Scan())
+	var header struct {
+		Version int               `json:"version"`
+		Width   int               `json:"width"`
+		Height  int               `json:"height"`
+		Env     map[string]string `json:"env"`
+	}
+	require.NoError(t, json.Unmarshal(scanner.Bytes(), &header))
+	assert.Equal(t, 2, header.Version)
+	assert.Equal(t, 80, header.Width)
+	assert.Equal(t, 24, header.Height)
+	assert.Equal(t, "xterm-256color", header.Env["TERM"])
+
+	var output string
+	var events []string
+	for scanner.Scan() {
+		var event []any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		require.Len(t, event, 3)
+		code, _ := event[1].(string)
+		payload, _ := event[2].(string)
+		events = append(events, code)
+		if code == "o" {
+			output += payload
+		} else {
+			assert.Equal(t, "120x40", payload)
+		}
+	}
+	assert.Equal(t, "hello wörld\r\n", output)
+	assert.Equal(t, []string{"o", "o", "o", "r"}, events)
+}
+
+func TestUploaderRetry(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+	uploader := agentrecord.NewUploader(logger)
+
+	rec := uploader.Start(proto.UploadSessionRecordingRequest_SSH, 80, 24, "")
+	_, err := rec.Write([]byte("hello"))
+	require.NoError(t, err)
+	require.NoError(t, rec.Close())
+
+	// Chunks that fail to upload are retried by the next send loop.
+	dest := &fakeDest{
+		errFn: func(*proto.UploadSessionRecordingRequest) error {
+			return xerrors.New("connection lost")
+		},
+	}
+	err = uploader.SendLoop(ctx, dest)
+	require.ErrorContains(t, err, "connection lost")
+	require.Empty(t, dest.reqs)
+
+	dest.errFn = nil
+	sendCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- uploader.SendLoop(sendCtx, dest)
+	}()
+	require.NoError(t, uploader.WaitUntilEmpty(ctx))
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+
+	require.Len(t, dest.reqs, 2)
+	assert.EqualValues(t, 0, dest.reqs[0].Sequence)
+	assert.EqualValues(t, 1, dest.reqs[1].Sequence)
+}
+
+func TestUploaderRejected(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+	uploader := agentrecord.NewUploader(logger)
+
+	rejected := uploader.Start(proto.UploadSessionRecordingRequest_SSH, 80, 24, "")
+	require.NoError(t, rejected.Close())
+	rec := uploader.Start(proto.UploadSessionRecordingRequest_SSH, 80, 24, "")
+	require.NoError(t, rec.Close())
+
+	// Chunks that coderd rejects permanently are dropped, so they don't hold
+	// back the chunks after them.
+	rejectedID := rejected.ID()
+	dest := &fakeDest{
+		errFn: func(req *proto.UploadSessionRecordingRequest) error {
+			if bytes.Equal(req.Id, rejectedID[:]) {
+				return drpcerr.WithCode(xerrors.New("session recording has already ended"), proto.ErrorCodeSessionRecordingChunkRejected)
+			}
+			return nil
+		},
+	}
+	sendCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- uploader.SendLoop(sendCtx, dest)
+	}()
+	require.NoError(t, uploader.WaitUntilEmpty(ctx))
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+
+	recID := rec.ID()
+	require.Len(t, dest.reqs, 2)
+	for _, req := range dest.reqs {
+		assert.Equal(t, recID[:], req.Id)
+	}
+}
@@ -23,6 +23,7 @@ import (
 
 	"cdr.dev/slog"
 
+	"github.com/coder/coder/v2/agent/agentexec"
 	"github.com/coder/coder/v2/agent/agentssh"
 	"github.com/coder/coder/v2/agent/proto"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
@@ -58,6 +59,13 @@ type Options struct {
 	SSHServer       *agentssh.Server
 	Filesystem      afero.Fs
 	GetScriptLogger func(logSourceID uuid.UUID) ScriptLogger
+	// Execer creates the script processes. Defaults to the Execer of the
+	// SSH server.
+	Execer agentexec.Execer
+	// ScriptExited is called with the PID of a script once it exits. Scripts
+	// run in a session of their own with the PID as its ID, so the processes
+	// left in the session are those the script started in the background.
+	ScriptExited func(pid int)
 }
 
 // New creates a runner for the provided scripts.
@@ -283,7 +291,11 @@ func (r *Runner) run(ctx context.Context, script codersdk.WorkspaceAgentScript,
 		cmdCtx, ctxCancel = context.WithTimeout(ctx, script.Timeout)
 		defer ctxCancel()
 	}
-	cmdPty, err := r.SSHServer.CreateCommand(cmdCtx, script.Script, nil)
+	execer := r.Execer
+	if execer == nil {
+		execer = r.SSHServer.Execer
+	}
+	cmdPty, err := r.SSHServer.CreateCommandWithExecer(cmdCtx, execer, script.Script, nil)
 	if err != nil {
 		return xerrors.Errorf("%s script: create command: %w", logPath, err)
 	}
@@ -413,6 +425,9 @@ func (r *Runner) run(ctx context.Context, script codersdk.WorkspaceAgentScript,
 		err = cmdCtx.Err()
 	case err = <-cmdDone:
 	}
+	if r.ScriptExited != nil {
+		r.ScriptExited(cmd.Process.Pid)
+	}
 	switch {
 	case errors.Is(err, exec.ErrWaitDelay):
 		err = ErrOutputPipesOpen
@@ -4,6 +4,7 @@ import (
 	"context"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"testing"
 	"time"
 
@@ -142,6 +143,37 @@ func TestScriptReportsTiming(t *testing.T) {
 	require.GreaterOrEqual(t, timing.End.AsTime(), timing.Start.AsTime())
 }
 
+func TestScriptExited(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("scripts only run in their own session on unix")
+	}
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	fLogger := newFakeScriptLogger()
+	runner := setup(t, func(uuid2 uuid.UUID) agentscripts.ScriptLogger {
+		return fLogger
+	})
+	exited := make(chan int, 1)
+	runner.ScriptExited = func(pid int) {
+		exited <- pid
+	}
+	defer runner.Close()
+
+	aAPI := agenttest.NewFakeAgentAPI(t, testutil.Logger(t), nil, nil)
+	err := runner.Init([]codersdk.WorkspaceAgentScript{{
+		LogSourceID: uuid.New(),
+		Script:      "echo $$",
+	}}, aAPI.ScriptCompleted)
+	require.NoError(t, err)
+	require.NoError(t, runner.Execute(ctx, agentscripts.ExecuteAllScripts))
+
+	// The PID of the script is the ID of its session.
+	log := testutil.RequireRecvCtx(ctx, t, fLogger.logs)
+	pid := testutil.RequireRecvCtx(ctx, t, exited)
+	require.Equal(t, strconv.Itoa(pid), log.Output)
+}
+
 // TestCronClose exists because cron.Run() can happen after cron.Close().
 // If this happens, there used to be a deadlock.
 func TestCronClose(t *testing.T) {
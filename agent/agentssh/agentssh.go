@@ -14,6 +14,7 @@ import (
 	"os/user"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -31,6 +32,7 @@ import (
 	"cdr.dev/slog"
 
 	"github.com/coder/coder/v2/agent/agentexec"
+	"github.com/coder/coder/v2/agent/agentrecord"
 	"github.com/coder/coder/v2/agent/usershell"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/pty"
@@ -53,6 +55,13 @@ const (
 	// MagicProcessCmdlineJetBrains is a string in a process's command line that
 	// uniquely identifies it as JetBrains software.
 	MagicProcessCmdlineJetBrains = "idea.vendor.name=JetBrains"
+	// MagicContainerEnvironmentVariable is set by the client to run the
+	// session inside the named dev container instead of on the agent host.
+	// This is stripped from any commands being executed.
+	MagicContainerEnvironmentVariable = "CODER_CONTAINER"
+	// maxContainerNameLength limits the name of the dev container that
+	// clients send on connections served by ServeContainers.
+	maxContainerNameLength = 256
 
 	// BlockedFileTransferErrorCode indicates that SSH server restricted the raw command from performing
 	// the file transfer.
@@ -85,6 +94,14 @@ type Config struct {
 	X11DisplayOffset *int
 	// BlockFileTransfer restricts use of file transfer applications.
 	BlockFileTransfer bool
+	// RecordSession starts recording the output of an interactive session. It
+	// returns nil if the session should not be recorded.
+	RecordSession func(width, height uint16, term string) *agentrecord.Recorder
+	// ContainerCommand returns the program, arguments and environment that
+	// run the script inside the named dev container with env, or a login
+	// shell if the script is empty. If nil, sessions that target a
+	// container fail.
+	ContainerCommand func(container, script string, env []string, tty bool) (name string, args []string, cmdEnv []string, err error)
 }
 
 type Server struct {
@@ -223,6 +240,12 @@ func NewServer(ctx context.Context, logger slog.Logger, prometheusRegistry *prom
 		SubsystemHandlers: map[string]ssh.SubsystemHandler{
 			"sftp": s.sessionHandler,
 		},
+		ConnCallback: func(ctx ssh.Context, conn net.Conn) net.Conn {
+			if c, ok := conn.(*containerConn); ok {
+				ctx.SetValue(containerContextKey{}, c.container)
+			}
+			return conn
+		},
 	}
 
 	// The MaxTimeout functionality has been substituted with the introduction
@@ -390,6 +413,20 @@ func (s *Server) sessionStart(logger slog.Logger, session ssh.Session, extraEnv
 		magicType = strings.ToLower(strings.TrimPrefix(kv, MagicSessionTypeEnvironmentVariable+"="))
 		env = append(env[:index], env[index+1:]...)
 	}
+	var container string
+	for index, kv := range env {
+		if !strings.HasPrefix(kv, MagicContainerEnvironmentVariable+"=") {
+			continue
+		}
+		container = strings.TrimPrefix(kv, MagicContainerEnvironmentVariable+"=")
+		env = append(env[:index], env[index+1:]...)
+		break
+	}
+	if container == "" {
+		// Connections served by ServeContainers name the container
+		// before the SSH protocol starts.
+		container, _ = ctx.Value(containerContextKey{}).(string)
+	}
 
 	// Always force lowercase checking to be case-insensitive.
 	switch magicType {
@@ -409,7 +446,16 @@ func (s *Server) sessionStart(logger slog.Logger, session ssh.Session, extraEnv
 	magicTypeLabel := magicTypeMetricLabel(magicType)
 	sshPty, windowSize, isPty := session.Pty()
 
-	cmd, err := s.CreateCommand(ctx, session.RawCommand(), env)
+	var cmd *pty.Cmd
+	var err error
+	if container != "" {
+		if isPty {
+			env = append(env, fmt.Sprintf("TERM=%s", sshPty.Term))
+		}
+		cmd, err = s.CreateContainerCommand(ctx, container, session.RawCommand(), env, isPty)
+	} else {
+		cmd, err = s.CreateCommand(ctx, session.RawCommand(), env)
+	}
 	if err != nil {
 		ptyLabel := "no"
 		if isPty {
@@ -540,6 +586,13 @@ func (s *Server) startPTYSession(logger slog.Logger, session ptySession, magicTy
 			}
 		}
 	}()
+	var recorder *agentrecord.Recorder
+	if s.config.RecordSession != nil {
+		recorder = s.config.RecordSession(uint16(sshPty.Window.Width), uint16(sshPty.Window.Height), sshPty.Term)
+	}
+	if recorder != nil {
+		defer recorder.Close()
+	}
 	sigs := make(chan ssh.Signal, 1)
 	session.Signals(sigs)
 	defer func() {
@@ -570,6 +623,9 @@ func (s *Server) startPTYSession(logger slog.Logger, session ptySession, magicTy
 					logger.Warn(ctx, "failed to resize tty", slog.Error(resizeErr))
 					s.metrics.sessionErrors.WithLabelValues(magicTypeLabel, "yes", "resize").Add(1)
 				}
+				if recorder != nil {
+					recorder.Resize(uint16(win.Width), uint16(win.Height))
+				}
 			}
 		}
 	}()
@@ -589,7 +645,11 @@ func (s *Server) startPTYSession(logger slog.Logger, session ptySession, magicTy
 	//    after we've Read() all the buffered data from the PTY.
 	// 2. The client hangs up, which cancels the command's Context, and go will
 	//    kill the command's process.  This then has the same effect as (1).
-	n, err := io.Copy(session, ptty.OutputReader())
+	var output io.Writer = session
+	if recorder != nil {
+		output = io.MultiWriter(session, recorder)
+	}
+	n, err := io.Copy(output, ptty.OutputReader())
 	logger.Debug(ctx, "copy output done", slog.F("bytes", n), slog.Error(err))
 	if err != nil {
 		s.metrics.sessionErrors.WithLabelValues(magicTypeLabel, "yes", "output_io_copy").Add(1)
@@ -674,6 +734,12 @@ func (s *Server) sftpHandler(logger slog.Logger, session ssh.Session) {
 // If the script provided is empty, it will default to the users shell.
 // This injects environment variables specified by the user at launch too.
 func (s *Server) CreateCommand(ctx context.Context, script string, env []string) (*pty.Cmd, error) {
+	return s.CreateCommandWithExecer(ctx, s.Execer, script, env)
+}
+
+// CreateCommandWithExecer is like CreateCommand, but creates the process
+// with the given Execer, e.g. to place it in a different cgroup.
+func (s *Server) CreateCommandWithExecer(ctx context.Context, execer agentexec.Execer, script string, env []string) (*pty.Cmd, error) {
 	currentUser, err := user.Current()
 	if err != nil {
 		return nil, xerrors.Errorf("get current user: %w", err)
@@ -728,7 +794,7 @@ func (s *Server) CreateCommand(ctx context.Context, script string, env []string)
 		}
 	}
 
-	cmd := s.Execer.PTYCommandContext(ctx, name, args...)
+	cmd := execer.PTYCommandContext(ctx, name, args...)
 	cmd.Dir = s.config.WorkingDirectory()
 
 	// If the metadata directory doesn't exist, we run the command
@@ -744,25 +810,73 @@ func (s *Server) CreateCommand(ctx context.Context, script string, env []string)
 	}
 	cmd.Env = append(os.Environ(), env...)
 	cmd.Env = append(cmd.Env, fmt.Sprintf("USER=%s", username))
+	cmd.Env = append(cmd.Env, sshConnectionEnv()...)
+
+	cmd.Env, err = s.config.UpdateEnv(cmd.Env)
+	if err != nil {
+		return nil, xerrors.Errorf("apply env: %w", err)
+	}
 
-	// Set SSH connection environment variables (these are also set by OpenSSH
-	// and thus expected to be present by SSH clients). Since the agent does
-	// networking in-memory, trying to provide accurate values here would be
-	// nonsensical. For now, we hard code these values so that they're present.
+	return cmd, nil
+}
+
+// sshConnectionEnv returns the SSH connection environment variables. These are
+// also set by OpenSSH and thus expected to be present by SSH clients. Since the
+// agent does networking in-memory, trying to provide accurate values here
+// would be nonsensical. For now, we hard code these values so that they're
+// present.
+func sshConnectionEnv() []string {
 	srcAddr, srcPort := "0.0.0.0", "0"
 	dstAddr, dstPort := "0.0.0.0", "0"
-	cmd.Env = append(cmd.Env, fmt.Sprintf("SSH_CLIENT=%s %s %s", srcAddr, srcPort, dstPort))
-	cmd.Env = append(cmd.Env, fmt.Sprintf("SSH_CONNECTION=%s %s %s %s", srcAddr, srcPort, dstAddr, dstPort))
+	return []string{
+		fmt.Sprintf("SSH_CLIENT=%s %s %s", srcAddr, srcPort, dstPort),
+		fmt.Sprintf("SSH_CONNECTION=%s %s %s %s", srcAddr, srcPort, dstAddr, dstPort),
+	}
+}
 
-	cmd.Env, err = s.config.UpdateEnv(cmd.Env)
+// CreateContainerCommand creates a command that runs the script inside the
+// named dev container. The environment of the session and the environment
+// injected by the agent, like in CreateCommand, are passed into the
+// container, while the container runtime itself runs with the environment of
+// the agent. PATH describes the agent's host, so it is left to the container.
+func (s *Server) CreateContainerCommand(ctx context.Context, container, script string, env []string, tty bool) (*pty.Cmd, error) {
+	if s.config.ContainerCommand == nil {
+		return nil, xerrors.New("dev containers are not enabled on this agent")
+	}
+	containerEnv := append(slices.Clone(env), sshConnectionEnv()...)
+	containerEnv, err := s.config.UpdateEnv(containerEnv)
 	if err != nil {
 		return nil, xerrors.Errorf("apply env: %w", err)
 	}
-
+	containerEnv = slices.DeleteFunc(containerEnv, func(kv string) bool {
+		return strings.HasPrefix(kv, "PATH=")
+	})
+	name, args, cmdEnv, err := s.config.ContainerCommand(container, script, containerEnv, tty)
+	if err != nil {
+		return nil, xerrors.Errorf("container command: %w", err)
+	}
+	cmd := s.Execer.PTYCommandContext(ctx, name, args...)
+	cmd.Dir, err = userHomeDir()
+	if err != nil {
+		return nil, xerrors.Errorf("get home dir: %w", err)
+	}
+	cmd.Env = append(os.Environ(), cmdEnv...)
 	return cmd, nil
 }
 
 func (s *Server) Serve(l net.Listener) (retErr error) {
+	return s.serve(l, s.handleConn)
+}
+
+// ServeContainers serves SSH connections into dev containers. Clients write
+// the name of the dev container followed by a newline before the SSH
+// protocol starts, which allows selecting the container when the client
+// only forwards the SSH protocol, e.g. a ProxyCommand.
+func (s *Server) ServeContainers(l net.Listener) error {
+	return s.serve(l, s.handleContainerConn)
+}
+
+func (s *Server) serve(l net.Listener, handleConn func(net.Listener, net.Conn)) (retErr error) {
 	s.logger.Info(context.Background(), "started serving listener", slog.F("listen_addr", l.Addr()))
 	defer func() {
 		s.logger.Info(context.Background(), "stopped serving listener",
@@ -777,8 +891,42 @@ func (s *Server) Serve(l net.Listener) (retErr error) {
 		if err != nil {
 			return err
 		}
-		go s.handleConn(l, conn)
+		go handleConn(l, conn)
+	}
+}
+
+// containerContextKey is the key of the dev container name in the context
+// of connections served by ServeContainers.
+type containerContextKey struct{}
+
+// containerConn is a connection into the named dev container.
+type containerConn struct {
+	net.Conn
+	r         *bufio.Reader
+	container string
+}
+
+func (c *containerConn) Read(b []byte) (int, error) {
+	return c.r.Read(b)
+}
+
+func (s *Server) handleContainerConn(l net.Listener, c net.Conn) {
+	logger := s.logger.With(
+		slog.F("remote_addr", c.RemoteAddr()),
+		slog.F("local_addr", c.LocalAddr()),
+		slog.F("listen_addr", l.Addr()))
+
+	_ = c.SetReadDeadline(time.Now().Add(10 * time.Second))
+	r := bufio.NewReaderSize(c, maxContainerNameLength+1)
+	line, err := r.ReadSlice('\n')
+	_ = c.SetReadDeadline(time.Time{})
+	container := strings.TrimSpace(string(line))
+	if err != nil || container == "" {
+		logger.Warn(context.Background(), "read dev container name", slog.Error(err))
+		_ = c.Close()
+		return
 	}
+	s.handleConn(l, &containerConn{Conn: c, r: r, container: container})
 }
 
 func (s *Server) handleConn(l net.Listener, c net.Conn) {
@@ -19,6 +19,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
 	"golang.org/x/crypto/ssh"
+	"golang.org/x/xerrors"
 
 	"cdr.dev/slog/sloggers/slogtest"
 
@@ -104,6 +105,94 @@ func TestNewServer_ExecuteShebang(t *testing.T) {
 	})
 }
 
+func TestNewServer_Container(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("sh doesn't exist on Windows")
+	}
+
+	ctx := context.Background()
+	logger := testutil.Logger(t)
+	s, err := agentssh.NewServer(ctx, logger, prometheus.NewRegistry(), afero.NewMemMapFs(), agentexec.DefaultExecer, &agentssh.Config{
+		ContainerCommand: func(container, script string, env []string, tty bool) (string, []string, []string, error) {
+			if container != "project" {
+				return "", nil, nil, xerrors.Errorf("dev container %q not found", container)
+			}
+			// Echo what would be executed instead of running a container.
+			return "sh", []string{"-c", fmt.Sprintf("echo %s %s %t [$CODER_CONTAINER] $FOO $SSH_CLIENT", container, script, tty)}, env, nil
+		},
+	})
+	require.NoError(t, err)
+	defer s.Close()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		err := s.Serve(ln)
+		assert.Error(t, err) // Server is closed.
+	}()
+
+	c := sshClient(t, ln.Addr().String())
+
+	var b bytes.Buffer
+	sess, err := c.NewSession()
+	require.NoError(t, err)
+	sess.Stdout = &b
+	require.NoError(t, sess.Setenv(agentssh.MagicContainerEnvironmentVariable, "project"))
+	require.NoError(t, sess.Setenv("FOO", "bar"))
+	require.NoError(t, sess.Run("whoami"))
+	// The environment of the session reaches the container, without the
+	// container name.
+	require.Equal(t, "project whoami false [] bar 0.0.0.0 0 0", strings.TrimSpace(b.String()))
+
+	sess, err = c.NewSession()
+	require.NoError(t, err)
+	require.NoError(t, sess.Setenv(agentssh.MagicContainerEnvironmentVariable, "unknown"))
+	err = sess.Run("whoami")
+	var exitErr *ssh.ExitError
+	require.ErrorAs(t, err, &exitErr)
+	require.Equal(t, agentssh.MagicSessionErrorCode, exitErr.ExitStatus())
+
+	// Connections to the container listener name the container before the
+	// SSH protocol, e.g. when the client is a ProxyCommand.
+	containerLn, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	containerDone := make(chan struct{})
+	go func() {
+		defer close(containerDone)
+		err := s.ServeContainers(containerLn)
+		assert.Error(t, err) // Server is closed.
+	}()
+
+	conn, err := net.Dial("tcp", containerLn.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("project\n"))
+	require.NoError(t, err)
+	sshConn, channels, requests, err := ssh.NewClientConn(conn, "localhost:22", &ssh.ClientConfig{
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(), //nolint:gosec // This is a test.
+	})
+	require.NoError(t, err)
+	c = ssh.NewClient(sshConn, channels, requests)
+	defer c.Close()
+
+	b.Reset()
+	sess, err = c.NewSession()
+	require.NoError(t, err)
+	sess.Stdout = &b
+	require.NoError(t, sess.Run("whoami"))
+	require.Equal(t, "project whoami false [] 0.0.0.0 0 0", strings.TrimSpace(b.String()))
+
+	err = s.Close()
+	require.NoError(t, err)
+	<-done
+	<-containerDone
+}
+
 func TestNewServer_CloseActiveConnections(t *testing.T) {
 	t.Parallel()
 
@@ -15,6 +15,7 @@ import (
 	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"storj.io/drpc/drpcmux"
 	"storj.io/drpc/drpcserver"
 	"tailscale.com/tailcfg"
@@ -96,8 +97,8 @@ func (c *Client) Close() {
 	c.derpMapOnce.Do(func() { close(c.derpMapUpdates) })
 }
 
-func (c *Client) ConnectRPC23(ctx context.Context) (
-	agentproto.DRPCAgentClient23, proto.DRPCTailnetClient23, error,
+func (c *Client) ConnectRPC24(ctx context.Context) (
+	agentproto.DRPCAgentClient24, proto.DRPCTailnetClient24, error,
 ) {
 	conn, lis := drpcsdk.MemTransportPipe()
 	c.LastWorkspaceAgent = func() {
@@ -131,6 +132,14 @@ func (c *Client) GetMetadata() map[string]agentsdk.Metadata {
 	return c.fakeAgentAPI.GetMetadata()
 }
 
+func (c *Client) GetSessionRecordings() []*agentproto.UploadSessionRecordingRequest {
+	return c.fakeAgentAPI.GetSessionRecordings()
+}
+
+func (c *Client) SetWorkloadIdentityTokenFunc(f func(audience string) (*agentproto.WorkloadIdentityToken, error)) {
+	c.fakeAgentAPI.SetWorkloadIdentityTokenFunc(f)
+}
+
 func (c *Client) GetStartupLogs() []agentsdk.Log {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -170,8 +179,10 @@ type FakeAgentAPI struct {
 	lifecycleStates []codersdk.WorkspaceAgentLifecycle
 	metadata        map[string]agentsdk.Metadata
 	timings         []*agentproto.Timing
+	recordings      []*agentproto.UploadSessionRecordingRequest
 
-	getAnnouncementBannersFunc func() ([]codersdk.BannerConfig, error)
+	getAnnouncementBannersFunc   func() ([]codersdk.BannerConfig, error)
+	getWorkloadIdentityTokenFunc func(audience string) (*agentproto.WorkloadIdentityToken, error)
 }
 
 func (f *FakeAgentAPI) GetManifest(context.Context, *agentproto.GetManifestRequest) (*agentproto.Manifest, error) {
@@ -315,6 +326,42 @@ func (f *FakeAgentAPI) ScriptCompleted(_ context.Context, req *agentproto.Worksp
 	return &agentproto.WorkspaceAgentScriptCompletedResponse{}, nil
 }
 
+// GetSessionRecordings returns the session recording chunks uploaded by the
+// agent, in the order they were received.
+func (f *FakeAgentAPI) GetSessionRecordings() []*agentproto.UploadSessionRecordingRequest {
+	f.Lock()
+	defer f.Unlock()
+	return slices.Clone(f.recordings)
+}
+
+func (f *FakeAgentAPI) UploadSessionRecording(_ context.Context, req *agentproto.UploadSessionRecordingRequest) (*agentproto.UploadSessionRecordingResponse, error) {
+	f.Lock()
+	f.recordings = append(f.recordings, req)
+	f.Unlock()
+
+	return &agentproto.UploadSessionRecordingResponse{}, nil
+}
+
+func (f *FakeAgentAPI) SetWorkloadIdentityTokenFunc(fn func(audience string) (*agentproto.WorkloadIdentityToken, error)) {
+	f.Lock()
+	defer f.Unlock()
+	f.getWorkloadIdentityTokenFunc = fn
+}
+
+func (f *FakeAgentAPI) GetWorkloadIdentityToken(_ context.Context, req *agentproto.GetWorkloadIdentityTokenRequest) (*agentproto.WorkloadIdentityToken, error) {
+	f.Lock()
+	fn := f.getWorkloadIdentityTokenFunc
+	f.Unlock()
+
+	if fn != nil {
+		return fn(req.Audience)
+	}
+	return &agentproto.WorkloadIdentityToken{
+		Token:     "workload-identity-token:" + req.Audience,
+		ExpiresAt: timestamppb.New(time.Now().Add(15 * time.Minute)),
+	}, nil
+}
+
 func NewFakeAgentAPI(t testing.TB, logger slog.Logger, manifest *agentproto.Manifest, statsCh chan *agentproto.Stats) *FakeAgentAPI {
 	return &FakeAgentAPI{
 		t:           t,
@@ -38,6 +38,8 @@ func (a *agent) apiHandler() http.Handler {
 	promHandler := PrometheusMetricsHandler(a.prometheusRegistry, a.logger)
 	r.Get("/api/v0/listening-ports", lp.handler)
 	r.Get("/api/v0/netcheck", a.HandleNetcheck)
+	r.Get("/api/v0/containers", a.HandleContainers)
+	r.Route("/api/v0/files", a.filesRoutes)
 	r.Get("/debug/logs", a.HandleHTTPDebugLogs)
 	r.Get("/debug/magicsock", a.HandleHTTPDebugMagicsock)
 	r.Get("/debug/magicsock/debug-logging/{state}", a.HandleHTTPMagicsockDebugLoggingState)
@@ -75,3 +77,10 @@ func (lp *listeningPortsHandler) handler(rw http.ResponseWriter, r *http.Request
 		Ports: ports,
 	})
 }
+
+// HandleContainers returns the dev containers started by the agent.
+func (a *agent) HandleContainers(rw http.ResponseWriter, r *http.Request) {
+	httpapi.Write(r.Context(), rw, http.StatusOK, codersdk.WorkspaceAgentDevcontainersResponse{
+		Devcontainers: a.containers.List(),
+	})
+}
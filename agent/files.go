@@ -0,0 +1,291 @@
+package agent
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/spf13/afero"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// filesRoutes registers the file browser and transfer API. Paths are
+// provided in the "path" query parameter and relative paths are resolved from
+// the home directory of the agent user.
+func (a *agent) filesRoutes(r chi.Router) {
+	r.Use(a.blockFileTransferMiddleware)
+	r.Get("/list", a.handleListFiles)
+	r.Get("/stat", a.handleStatFile)
+	r.Get("/download", a.handleDownloadFile)
+	r.Post("/upload", a.handleUploadFile)
+	r.Post("/mkdir", a.handleMakeDirectory)
+	r.Delete("/", a.handleDeleteFile)
+}
+
+func (a *agent) blockFileTransferMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if a.blockFileTransfer {
+			httpapi.Write(r.Context(), rw, http.StatusForbidden, codersdk.Response{
+				Message: "File transfer has been disabled.",
+			})
+			return
+		}
+		next.ServeHTTP(rw, r)
+	})
+}
+
+func (a *agent) handleListFiles(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	path, ok := a.filePathParam(rw, r)
+	if !ok {
+		return
+	}
+
+	infos, err := afero.ReadDir(a.filesystem, path)
+	if err != nil {
+		writeFileError(rw, r, "Failed to list directory.", err)
+		return
+	}
+	files := make([]codersdk.WorkspaceAgentFileInfo, 0, len(infos))
+	for _, info := range infos {
+		files = append(files, convertFileInfo(filepath.Join(path, info.Name()), info))
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name < files[j].Name
+	})
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.WorkspaceAgentListFilesResponse{
+		Path:  path,
+		Files: files,
+	})
+}
+
+func (a *agent) handleStatFile(rw http.ResponseWriter, r *http.Request) {
+	path, ok := a.filePathParam(rw, r)
+	if !ok {
+		return
+	}
+
+	info, err := a.filesystem.Stat(path)
+	if err != nil {
+		writeFileError(rw, r, "Failed to stat file.", err)
+		return
+	}
+	httpapi.Write(r.Context(), rw, http.StatusOK, convertFileInfo(path, info))
+}
+
+func (a *agent) handleDownloadFile(rw http.ResponseWriter, r *http.Request) {
+	path, ok := a.filePathParam(rw, r)
+	if !ok {
+		return
+	}
+
+	f, err := a.filesystem.Open(path)
+	if err != nil {
+		writeFileError(rw, r, "Failed to open file.", err)
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		writeFileError(rw, r, "Failed to stat file.", err)
+		return
+	}
+	if info.IsDir() {
+		httpapi.Write(r.Context(), rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Cannot download a directory.",
+			Detail:  path,
+		})
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/octet-stream")
+	rw.Header().Set("Content-Disposition", "attachment; filename="+strconv.Quote(info.Name()))
+	// ServeContent handles range requests, so interrupted downloads can
+	// be resumed.
+	http.ServeContent(rw, r, info.Name(), info.ModTime(), f)
+}
+
+func (a *agent) handleUploadFile(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	path, ok := a.filePathParam(rw, r)
+	if !ok {
+		return
+	}
+
+	mode := os.FileMode(0o644)
+	if raw := r.URL.Query().Get("mode"); raw != "" {
+		parsed, err := strconv.ParseUint(raw, 8, 32)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid file mode.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		mode = os.FileMode(parsed).Perm()
+	}
+
+	// Write to a temporary file in the same directory and rename it over the
+	// destination, so a failed upload never leaves a partial file behind.
+	dir := filepath.Dir(path)
+	tmpPath := filepath.Join(dir, "."+filepath.Base(path)+".coder-upload-"+uuid.NewString())
+	f, err := a.filesystem.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+	if err != nil {
+		writeFileError(rw, r, "Failed to create file.", err)
+		return
+	}
+	_, err = io.Copy(f, r.Body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		// The mode passed to OpenFile is subject to the umask.
+		err = a.filesystem.Chmod(tmpPath, mode)
+	}
+	if err == nil {
+		err = a.filesystem.Rename(tmpPath, path)
+	}
+	if err != nil {
+		_ = a.filesystem.Remove(tmpPath)
+		writeFileError(rw, r, "Failed to write file.", err)
+		return
+	}
+
+	info, err := a.filesystem.Stat(path)
+	if err != nil {
+		writeFileError(rw, r, "Failed to stat file.", err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusCreated, convertFileInfo(path, info))
+}
+
+func (a *agent) handleMakeDirectory(rw http.ResponseWriter, r *http.Request) {
+	path, ok := a.filePathParam(rw, r)
+	if !ok {
+		return
+	}
+
+	err := a.filesystem.MkdirAll(path, 0o755)
+	if err != nil {
+		writeFileError(rw, r, "Failed to create directory.", err)
+		return
+	}
+	info, err := a.filesystem.Stat(path)
+	if err != nil {
+		writeFileError(rw, r, "Failed to stat directory.", err)
+		return
+	}
+	httpapi.Write(r.Context(), rw, http.StatusCreated, convertFileInfo(path, info))
+}
+
+func (a *agent) handleDeleteFile(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	path, ok := a.filePathParam(rw, r)
+	if !ok {
+		return
+	}
+	if home, err := userHomeDir(); (err == nil && path == filepath.Clean(home)) || path == filepath.Dir(path) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Refusing to delete the home or root directory.",
+			Detail:  path,
+		})
+		return
+	}
+
+	info, err := a.filesystem.Stat(path)
+	if err != nil {
+		writeFileError(rw, r, "Failed to stat file.", err)
+		return
+	}
+	if info.IsDir() && r.URL.Query().Get("recursive") != "true" {
+		// Not every afero.Fs refuses to remove non-empty directories.
+		empty, err := afero.IsEmpty(a.filesystem, path)
+		if err != nil {
+			writeFileError(rw, r, "Failed to read directory.", err)
+			return
+		}
+		if !empty {
+			httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+				Message: "Directory is not empty, delete it recursively instead.",
+				Detail:  path,
+			})
+			return
+		}
+	}
+	if info.IsDir() {
+		err = a.filesystem.RemoveAll(path)
+	} else {
+		err = a.filesystem.Remove(path)
+	}
+	if err != nil {
+		writeFileError(rw, r, "Failed to delete file.", err)
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// filePathParam returns the absolute, cleaned path from the "path" query
+// parameter. Unlike expandDirectory, environment variables are not expanded.
+func (*agent) filePathParam(rw http.ResponseWriter, r *http.Request) (string, bool) {
+	path := r.URL.Query().Get("path")
+	if path == "" {
+		httpapi.Write(r.Context(), rw, http.StatusBadRequest, codersdk.Response{
+			Message: `The "path" query parameter is required.`,
+		})
+		return "", false
+	}
+	if path == "~" {
+		path = "."
+	} else if len(path) > 1 && path[0] == '~' && os.IsPathSeparator(path[1]) {
+		path = "." + path[1:]
+	}
+	if !filepath.IsAbs(path) {
+		home, err := userHomeDir()
+		if err != nil {
+			httpapi.Write(r.Context(), rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Failed to get home directory.",
+				Detail:  err.Error(),
+			})
+			return "", false
+		}
+		path = filepath.Join(home, path)
+	}
+	return filepath.Clean(path), true
+}
+
+func writeFileError(rw http.ResponseWriter, r *http.Request, message string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		status = http.StatusNotFound
+	case errors.Is(err, fs.ErrPermission):
+		status = http.StatusForbidden
+	case errors.Is(err, fs.ErrExist):
+		status = http.StatusConflict
+	}
+	httpapi.Write(r.Context(), rw, status, codersdk.Response{
+		Message: message,
+		Detail:  err.Error(),
+	})
+}
+
+func convertFileInfo(path string, info fs.FileInfo) codersdk.WorkspaceAgentFileInfo {
+	return codersdk.WorkspaceAgentFileInfo{
+		Name:    info.Name(),
+		Path:    path,
+		Size:    info.Size(),
+		Mode:    info.Mode().String(),
+		ModTime: info.ModTime(),
+		IsDir:   info.IsDir(),
+	}
+}
@@ -40,3 +40,11 @@ type DRPCAgentClient23 interface {
 	DRPCAgentClient22
 	ScriptCompleted(ctx context.Context, in *WorkspaceAgentScriptCompletedRequest) (*WorkspaceAgentScriptCompletedResponse, error)
 }
+
+// DRPCAgentClient24 is the Agent API at v2.4. It adds the UploadSessionRecording and
+// GetWorkloadIdentityToken RPCs. Compatible with Coder v2.19+
+type DRPCAgentClient24 interface {
+	DRPCAgentClient23
+	UploadSessionRecording(ctx context.Context, in *UploadSessionRecordingRequest) (*UploadSessionRecordingResponse, error)
+	GetWorkloadIdentityToken(ctx context.Context, in *GetWorkloadIdentityTokenRequest) (*WorkloadIdentityToken, error)
+}
@@ -0,0 +1,14 @@
+package proto
+
+import "storj.io/drpc/drpcerr"
+
+// ErrorCodeSessionRecordingChunkRejected is the DRPC error code of session
+// recording chunks that coderd will never accept, e.g. because the recording
+// has already ended. Agents drop these chunks instead of retrying them.
+const ErrorCodeSessionRecordingChunkRejected = 1001
+
+// IsSessionRecordingChunkRejected returns true if the error indicates that
+// coderd rejected a session recording chunk permanently.
+func IsSessionRecordingChunkRejected(err error) bool {
+	return drpcerr.Code(err) == ErrorCodeSessionRecordingChunkRejected
+}
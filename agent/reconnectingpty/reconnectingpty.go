@@ -222,5 +222,8 @@ func readConnLoop(ctx context.Context, conn net.Conn, ptty pty.PTYCmd, metrics *
 			logger.Warn(ctx, "reconnecting pty resize failed, but will continue", slog.Error(err))
 			metrics.WithLabelValues("resize").Add(1)
 		}
+		if rc, ok := conn.(*recordingConn); ok {
+			rc.recorder.Resize(req.Width, req.Height)
+		}
 	}
 }
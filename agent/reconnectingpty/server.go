@@ -14,6 +14,7 @@ import (
 	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
+	"github.com/coder/coder/v2/agent/agentrecord"
 	"github.com/coder/coder/v2/agent/agentssh"
 	"github.com/coder/coder/v2/codersdk/workspacesdk"
 )
@@ -26,12 +27,15 @@ type Server struct {
 	connCount        atomic.Int64
 	reconnectingPTYs sync.Map
 	timeout          time.Duration
+	recordSession    func(width, height uint16) *agentrecord.Recorder
 }
 
-// NewServer returns a new ReconnectingPTY server
+// NewServer returns a new ReconnectingPTY server. If recordSession is set, it
+// is called for each connection to start recording the output sent to it, and
+// may return nil to skip recording.
 func NewServer(logger slog.Logger, commandCreator *agentssh.Server,
 	connectionsTotal prometheus.Counter, errorsTotal *prometheus.CounterVec,
-	timeout time.Duration,
+	timeout time.Duration, recordSession func(width, height uint16) *agentrecord.Recorder,
 ) *Server {
 	return &Server{
 		logger:           logger,
@@ -39,6 +43,7 @@ func NewServer(logger slog.Logger, commandCreator *agentssh.Server,
 		connectionsTotal: connectionsTotal,
 		errorsTotal:      errorsTotal,
 		timeout:          timeout,
+		recordSession:    recordSession,
 	}
 }
 
@@ -192,5 +197,26 @@ func (s *Server) handleConn(ctx context.Context, logger slog.Logger, conn net.Co
 		connected = true
 		sendConnected <- rpty
 	}
+
+	// Each connection is recorded separately, starting with the output that
+	// is replayed when attaching.
+	if s.recordSession != nil {
+		if recorder := s.recordSession(msg.Width, msg.Height); recorder != nil {
+			defer recorder.Close()
+			conn = &recordingConn{Conn: conn, recorder: recorder}
+		}
+	}
 	return rpty.Attach(ctx, connectionID, conn, msg.Height, msg.Width, connLogger)
 }
+
+// recordingConn records the output written to a reconnecting pty connection.
+type recordingConn struct {
+	net.Conn
+	recorder *agentrecord.Recorder
+}
+
+func (c *recordingConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	_, _ = c.recorder.Write(p[:n])
+	return n, err
+}
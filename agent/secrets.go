@@ -0,0 +1,74 @@
+package agent
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+	"golang.org/x/xerrors"
+	gproto "google.golang.org/protobuf/proto"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+// redactManifest returns a copy of the manifest without the values of
+// secrets, so it can be logged.
+func redactManifest(mp *proto.Manifest) *proto.Manifest {
+	if len(mp.GetSecrets()) == 0 {
+		return mp
+	}
+	redacted, ok := gproto.Clone(mp).(*proto.Manifest)
+	if !ok {
+		return &proto.Manifest{}
+	}
+	for _, secret := range redacted.Secrets {
+		secret.Value = nil
+	}
+	return redacted
+}
+
+// writeSecretFiles writes the secrets that have a file path to the
+// filesystem. Files are only readable by the agent user. Failing to write a
+// secret does not prevent the workspace from starting, so errors are only
+// logged.
+func (a *agent) writeSecretFiles(ctx context.Context, secrets []agentsdk.Secret) {
+	for _, secret := range secrets {
+		if secret.FilePath == "" {
+			continue
+		}
+		path, err := expandSecretFilePath(secret.FilePath)
+		if err != nil {
+			a.logger.Warn(ctx, "failed to expand secret file path", slog.F("path", secret.FilePath), slog.Error(err))
+			continue
+		}
+		err = a.filesystem.MkdirAll(filepath.Dir(path), 0o700)
+		if err != nil {
+			a.logger.Warn(ctx, "failed to create secret file directory", slog.F("path", path), slog.Error(err))
+			continue
+		}
+		err = afero.WriteFile(a.filesystem, path, secret.Value, 0o600)
+		if err != nil {
+			a.logger.Warn(ctx, "failed to write secret file", slog.F("path", path), slog.Error(err))
+			continue
+		}
+		// WriteFile does not change the mode of existing files.
+		err = a.filesystem.Chmod(path, 0o600)
+		if err != nil {
+			a.logger.Warn(ctx, "failed to change secret file mode", slog.F("path", path), slog.Error(err))
+		}
+	}
+}
+
+func expandSecretFilePath(path string) (string, error) {
+	if !strings.HasPrefix(path, "~/") {
+		return filepath.Clean(path), nil
+	}
+	home, err := userHomeDir()
+	if err != nil {
+		return "", xerrors.Errorf("get home directory: %w", err)
+	}
+	return filepath.Join(home, path[2:]), nil
+}
@@ -0,0 +1,98 @@
+package agent
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/afero"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/agent/proto"
+)
+
+// workloadIdentityRetryInterval is how long the agent waits before fetching a
+// workload identity token again after failing to.
+const workloadIdentityRetryInterval = 30 * time.Second
+
+// refreshWorkloadIdentityToken keeps a valid workload identity token in the
+// configured file. Tokens are refreshed once half of their lifetime has passed,
+// so processes reading the file never find an expired token. Failures are
+// only logged, since they must not tear down the connection to coderd.
+func (a *agent) refreshWorkloadIdentityToken(ctx context.Context, aAPI proto.DRPCAgentClient24) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		timer.Reset(a.updateWorkloadIdentityToken(ctx, aAPI))
+	}
+}
+
+// updateWorkloadIdentityToken fetches and writes a workload identity token. It
+// returns how long to wait until the next update.
+func (a *agent) updateWorkloadIdentityToken(ctx context.Context, aAPI proto.DRPCAgentClient24) time.Duration {
+	token, err := aAPI.GetWorkloadIdentityToken(ctx, &proto.GetWorkloadIdentityTokenRequest{
+		Audience: a.workloadIdentityAudience,
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			a.logger.Error(ctx, "failed to fetch workload identity token", slog.Error(err))
+		}
+		return workloadIdentityRetryInterval
+	}
+	err = a.writeWorkloadIdentityToken(token.Token)
+	if err != nil {
+		a.logger.Error(ctx, "failed to write workload identity token",
+			slog.F("path", a.workloadIdentityTokenFile), slog.Error(err))
+		return workloadIdentityRetryInterval
+	}
+	// Guards against refreshing in a tight loop if the clocks of the agent
+	// and coderd disagree.
+	return max(time.Until(token.ExpiresAt.AsTime())/2, time.Second)
+}
+
+// writeWorkloadIdentityToken atomically replaces the token file, so processes
+// never read a partially written token. The file is only readable by the
+// agent user.
+func (a *agent) writeWorkloadIdentityToken(token string) error {
+	dir := filepath.Dir(a.workloadIdentityTokenFile)
+	err := a.filesystem.MkdirAll(dir, 0o700)
+	if err != nil {
+		return xerrors.Errorf("create directory: %w", err)
+	}
+	f, err := afero.TempFile(a.filesystem, dir, ".coder-workload-identity-*")
+	if err != nil {
+		return xerrors.Errorf("create temporary file: %w", err)
+	}
+	renamed := false
+	defer func() {
+		if !renamed {
+			_ = a.filesystem.Remove(f.Name())
+		}
+	}()
+	_, err = f.WriteString(token)
+	if err != nil {
+		_ = f.Close()
+		return xerrors.Errorf("write temporary file: %w", err)
+	}
+	err = f.Close()
+	if err != nil {
+		return xerrors.Errorf("close temporary file: %w", err)
+	}
+	err = a.filesystem.Chmod(f.Name(), 0o600)
+	if err != nil {
+		return xerrors.Errorf("change temporary file mode: %w", err)
+	}
+	err = a.filesystem.Rename(f.Name(), a.workloadIdentityTokenFile)
+	if err != nil {
+		return xerrors.Errorf("rename temporary file: %w", err)
+	}
+	renamed = true
+	return nil
+}
@@ -25,6 +25,7 @@ import (
 	"cdr.dev/slog/sloggers/slogjson"
 	"cdr.dev/slog/sloggers/slogstackdriver"
 	"github.com/coder/coder/v2/agent"
+	"github.com/coder/coder/v2/agent/agentcgroup"
 	"github.com/coder/coder/v2/agent/agentexec"
 	"github.com/coder/coder/v2/agent/agentssh"
 	"github.com/coder/coder/v2/agent/reaper"
@@ -50,8 +51,14 @@ func (r *RootCmd) workspaceAgent() *serpent.Command {
 		slogJSONPath        string
 		slogStackdriverPath string
 		blockFileTransfer   bool
+		devcontainerDirs    []string
+		cgroups             bool
+		cgroupLimits        []string
 		agentHeaderCommand  string
 		agentHeader         []string
+
+		workloadIdentityTokenFile string
+		workloadIdentityAudience  string
 	)
 	cmd := &serpent.Command{
 		Use:   "agent",
@@ -314,6 +321,25 @@ func (r *RootCmd) workspaceAgent() *serpent.Command {
 				return xerrors.Errorf("create agent execer: %w", err)
 			}
 
+			var cgroupManager *agentcgroup.Manager
+			if cgroups {
+				limits, err := agentcgroup.ParseLimits(cgroupLimits)
+				if err != nil {
+					return xerrors.Errorf("parse cgroup limits: %w", err)
+				}
+				cgroupManager, err = agentcgroup.New(ctx, agentcgroup.Options{
+					Logger: logger.Named("cgroups"),
+					Limits: limits,
+				})
+				if err != nil {
+					// Processes still run, just without limits, so
+					// the workspace remains usable.
+					logger.Error(ctx, "unable to set up cgroups, processes will not be limited", slog.Error(err))
+				}
+			} else if len(cgroupLimits) > 0 {
+				logger.Warn(ctx, "cgroup limits are ignored because cgroups are not enabled")
+			}
+
 			agnt := agent.New(agent.Options{
 				Client:            client,
 				Logger:            logger,
@@ -339,6 +365,11 @@ func (r *RootCmd) workspaceAgent() *serpent.Command {
 				PrometheusRegistry: prometheusRegistry,
 				BlockFileTransfer:  blockFileTransfer,
 				Execer:             execer,
+				DevcontainerDirs:   devcontainerDirs,
+				Cgroups:            cgroupManager,
+
+				WorkloadIdentityTokenFile: workloadIdentityTokenFile,
+				WorkloadIdentityAudience:  workloadIdentityAudience,
 			})
 
 			promHandler := agent.PrometheusMetricsHandler(prometheusRegistry, logger)
@@ -461,6 +492,37 @@ func (r *RootCmd) workspaceAgent() *serpent.Command {
 			Description: fmt.Sprintf("Block file transfer using known applications: %s.", strings.Join(agentssh.BlockedFileTransferCommands, ",")),
 			Value:       serpent.BoolOf(&blockFileTransfer),
 		},
+		{
+			Flag:        "devcontainer-dirs",
+			Env:         "CODER_AGENT_DEVCONTAINER_DIRS",
+			Description: "Project directories to search for a devcontainer.json. Each dev container is built and started with Docker or Podman after the startup scripts, and can be reached with \"coder ssh <workspace>.<directory name>\". Relative paths are resolved against the agent directory.",
+			Value:       serpent.StringArrayOf(&devcontainerDirs),
+		},
+		{
+			Flag:        "cgroups",
+			Default:     "false",
+			Env:         "CODER_AGENT_CGROUPS",
+			Description: "Place SSH sessions, scripts and the apps they leave running in separate cgroup v2 groups below the cgroup of the agent and report their resource usage. Requires a writable cgroup v2 hierarchy (linux-only).",
+			Value:       serpent.BoolOf(&cgroups),
+		},
+		{
+			Flag:        "cgroup-limits",
+			Env:         "CODER_AGENT_CGROUP_LIMITS",
+			Description: "Resource limits of the cgroups, in the form <group>.<resource>=<value>, e.g. sessions.memory=4GiB or scripts.cpu=2. Groups are sessions, scripts and apps, resources are cpu (a number of CPUs) and memory.",
+			Value:       serpent.StringArrayOf(&cgroupLimits),
+		},
+		{
+			Flag:        "workload-identity-token-file",
+			Env:         "CODER_AGENT_WORKLOAD_IDENTITY_TOKEN_FILE",
+			Description: "Path to keep a workload identity token in, which proves the identity of the workspace to cloud providers and other systems that trust the OIDC issuer of the deployment. The token is refreshed before it expires. The path is exposed to workspace processes as CODER_WORKLOAD_IDENTITY_TOKEN_FILE.",
+			Value:       serpent.StringOf(&workloadIdentityTokenFile),
+		},
+		{
+			Flag:        "workload-identity-audience",
+			Env:         "CODER_AGENT_WORKLOAD_IDENTITY_AUDIENCE",
+			Description: "Audience of the workload identity token, e.g. sts.amazonaws.com. Defaults to the issuer URL of the deployment.",
+			Value:       serpent.StringOf(&workloadIdentityAudience),
+		},
 	}
 
 	return cmd
@@ -0,0 +1,86 @@
+package cliui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/pretty"
+)
+
+var resourceChangeSymbols = map[codersdk.ProvisionerJobResourceChangeAction]string{
+	codersdk.ProvisionerJobResourceChangeActionCreate:  "+",
+	codersdk.ProvisionerJobResourceChangeActionUpdate:  "~",
+	codersdk.ProvisionerJobResourceChangeActionReplace: "-/+",
+	codersdk.ProvisionerJobResourceChangeActionDelete:  "-",
+}
+
+// WorkspaceResourceChanges displays what a build will do to each resource,
+// calling out replacements and deletions since they lose any data that only
+// lives on the resource.
+//
+//	Planned changes:
+//	  ~   update   docker_container.workspace[0]
+//	        image: "ubuntu:22.04" -> "ubuntu:24.04"
+//	  -/+ replace  docker_volume.home
+//	        name: "home" -> "home-2" (forces replacement)
+//
+//	0 to create, 1 to update, 1 to replace, 0 to delete.
+func WorkspaceResourceChanges(writer io.Writer, changes []codersdk.ProvisionerJobResourceChange) error {
+	if len(changes) == 0 {
+		_, err := fmt.Fprintln(writer, "No changes. The workspace resources match the template version.")
+		return err
+	}
+
+	counts := map[codersdk.ProvisionerJobResourceChangeAction]int{}
+	var sb strings.Builder
+	_, _ = sb.WriteString("Planned changes:\n")
+	for _, change := range changes {
+		counts[change.Action]++
+		line := fmt.Sprintf("  %-3s %-8s %s", resourceChangeSymbols[change.Action], change.Action, change.Address)
+		switch change.Action {
+		case codersdk.ProvisionerJobResourceChangeActionReplace, codersdk.ProvisionerJobResourceChangeActionDelete:
+			line = pretty.Sprint(DefaultStyles.Warn, line)
+		case codersdk.ProvisionerJobResourceChangeActionCreate:
+			line = pretty.Sprint(DefaultStyles.Keyword, line)
+		}
+		_, _ = sb.WriteString(line + "\n")
+		for _, attribute := range change.Attributes {
+			_, _ = sb.WriteString("        " + formatAttributeChange(attribute) + "\n")
+		}
+	}
+	_, _ = fmt.Fprintf(&sb, "\n%d to create, %d to update, %d to replace, %d to delete.\n",
+		counts[codersdk.ProvisionerJobResourceChangeActionCreate],
+		counts[codersdk.ProvisionerJobResourceChangeActionUpdate],
+		counts[codersdk.ProvisionerJobResourceChangeActionReplace],
+		counts[codersdk.ProvisionerJobResourceChangeActionDelete],
+	)
+	if lost := counts[codersdk.ProvisionerJobResourceChangeActionReplace] + counts[codersdk.ProvisionerJobResourceChangeActionDelete]; lost > 0 {
+		_, _ = sb.WriteString(pretty.Sprint(DefaultStyles.Warn,
+			fmt.Sprintf("%d resource(s) will be destroyed. Data stored only on them will be lost.", lost)) + "\n")
+	}
+
+	_, err := fmt.Fprint(writer, sb.String())
+	return err
+}
+
+func formatAttributeChange(attribute codersdk.ProvisionerJobResourceAttributeChange) string {
+	before, after := attribute.Before, attribute.After
+	if attribute.Sensitive {
+		before, after = "(sensitive value)", "(sensitive value)"
+	}
+	if before == "" {
+		before = "null"
+	}
+	if attribute.AfterUnknown {
+		after = "(known after apply)"
+	} else if after == "" {
+		after = "null"
+	}
+	formatted := fmt.Sprintf("%s: %s -> %s", attribute.Path, before, after)
+	if attribute.ForcesReplacement {
+		formatted += pretty.Sprint(DefaultStyles.Warn, " (forces replacement)")
+	}
+	return formatted
+}
@@ -52,6 +52,13 @@ func (r Root) DotfilesURL() File {
 	return File(filepath.Join(string(r), "dotfilesurl"))
 }
 
+// SSHOptions stores the SSH options of specific workspaces and templates,
+// written to the SSH config by "coder config-ssh".
+func (r Root) SSHOptions() File {
+	r.mustNotEmpty()
+	return File(filepath.Join(string(r), "ssh_options"))
+}
+
 func (r Root) PostgresPath() string {
 	r.mustNotEmpty()
 	return filepath.Join(string(r), "postgres")
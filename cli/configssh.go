@@ -3,6 +3,7 @@ package cli
 import (
 	"bufio"
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -23,6 +24,7 @@ import (
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/cli/config"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/serpent"
 )
@@ -40,6 +42,9 @@ const (
 	sshConfigOptionsHeader = `#
 # Last config-ssh options:
 `
+	// sshConfigDeploymentPrefix keys the coder section by deployment, so
+	// config-ssh can manage sections for multiple deployments.
+	sshConfigDeploymentPrefix = "# Deployment: "
 )
 
 // sshConfigOptions represents options that can be stored and read
@@ -135,6 +140,86 @@ func (o sshConfigOptions) asList() (list []string) {
 	return list
 }
 
+// sshConfigOverrides are SSH options for specific workspaces and templates,
+// keyed by name. They are stored in the CLI config directory, since they
+// belong to the deployment the CLI is logged in to.
+type sshConfigOverrides struct {
+	Workspaces map[string][]string `json:"workspaces,omitempty"`
+	Templates  map[string][]string `json:"templates,omitempty"`
+}
+
+func readSSHConfigOverrides(f config.File) (sshConfigOverrides, error) {
+	var o sshConfigOverrides
+	raw, err := f.Read()
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return o, nil
+		}
+		return o, xerrors.Errorf("read ssh options: %w", err)
+	}
+	if raw == "" {
+		return o, nil
+	}
+	err = json.Unmarshal([]byte(raw), &o)
+	if err != nil {
+		return o, xerrors.Errorf("parse ssh options %q: %w", f, err)
+	}
+	return o, nil
+}
+
+func (o sshConfigOverrides) write(f config.File) error {
+	raw, err := json.MarshalIndent(o, "", "  ")
+	if err != nil {
+		return err
+	}
+	return f.Write(string(raw) + "\n")
+}
+
+func (o sshConfigOverrides) empty() bool {
+	return len(o.Workspaces) == 0 && len(o.Templates) == 0
+}
+
+// options returns the SSH options of a workspace. Workspace options come
+// before template options, since SSH uses the first value of an option.
+func (o sshConfigOverrides) options(workspace codersdk.Workspace) []string {
+	var options []string
+	options = append(options, o.Workspaces[workspace.Name]...)
+	options = append(options, o.Templates[workspace.TemplateName]...)
+	return options
+}
+
+// setSSHConfigOverrides updates the options of workspaces or templates from
+// arguments in the form of "<name>:<option>". An option replaces any option of
+// the name with the same key, and an option with an empty value removes it.
+func setSSHConfigOverrides(overrides map[string][]string, args []string) (map[string][]string, error) {
+	for _, arg := range args {
+		name, option, ok := strings.Cut(arg, ":")
+		if !ok || name == "" {
+			return nil, xerrors.Errorf("invalid option %q, expected <name>:<option>", arg)
+		}
+		key, value, err := codersdk.ParseSSHConfigOption(option)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid option %q: %w", arg, err)
+		}
+		if overrides == nil {
+			overrides = make(map[string][]string)
+		}
+		options := slices.DeleteFunc(overrides[name], func(existing string) bool {
+			existingKey, _, _ := codersdk.ParseSSHConfigOption(existing)
+			return strings.EqualFold(existingKey, key)
+		})
+		if value != "" {
+			options = append(options, option)
+		}
+		if len(options) == 0 {
+			delete(overrides, name)
+			continue
+		}
+		overrides[name] = options
+	}
+	return overrides, nil
+}
+
 func (r *RootCmd) configSSH() *serpent.Command {
 	var (
 		sshConfigFile       string
@@ -144,6 +229,8 @@ func (r *RootCmd) configSSH() *serpent.Command {
 		skipProxyCommand    bool
 		forceUnixSeparators bool
 		coderCliPath        string
+		workspaceSSHOptions []string
+		templateSSHOptions  []string
 	)
 	client := new(codersdk.Client)
 	cmd := &serpent.Command{
@@ -155,10 +242,18 @@ func (r *RootCmd) configSSH() *serpent.Command {
 				Description: "You can use -o (or --ssh-option) so set SSH options to be used for all your workspaces",
 				Command:     "coder config-ssh -o ForwardAgent=yes",
 			},
+			Example{
+				Description: "You can use --template-ssh-option to set SSH options for a template",
+				Command:     "coder config-ssh --template-ssh-option docker:ForwardAgent=yes",
+			},
 			Example{
 				Description: "You can use --dry-run (or -n) to see the changes that would be made",
 				Command:     "coder config-ssh --dry-run",
 			},
+			Example{
+				Description: "You can use a config directory and host prefix for each deployment",
+				Command:     "coder --global-config ~/.staging config-ssh --ssh-host-prefix staging.",
+			},
 		),
 		Middleware: serpent.Chain(
 			serpent.RequireNArgs(0),
@@ -228,10 +323,34 @@ func (r *RootCmd) configSSH() *serpent.Command {
 			// Keep track of changes we are making.
 			var changes []string
 
+			coderdConfig, err := client.SSHConfiguration(ctx)
+			if err != nil {
+				// If the error is 404, this deployment does not support
+				// this endpoint yet. Do not error, just assume defaults.
+				// TODO: Remove this in 2 months (May 31, 2023). Just return the error
+				// 	and remove this 404 check.
+				var sdkErr *codersdk.Error
+				if !(xerrors.As(err, &sdkErr) && sdkErr.StatusCode() == http.StatusNotFound) {
+					return xerrors.Errorf("fetch coderd config failed: %w", err)
+				}
+				coderdConfig.HostnamePrefix = "coder."
+			}
+
+			// Each deployment has its own section, so running config-ssh
+			// against one deployment leaves the others untouched.
+			deployment := sshConfigDeployment{
+				url:          client.URL.String(),
+				globalConfig: escapedGlobalConfig,
+				hostPrefix:   coderdConfig.HostnamePrefix,
+			}
+			if sshConfigOpts.userHostPrefix != "" {
+				deployment.hostPrefix = sshConfigOpts.userHostPrefix
+			}
+
 			// Parse the previous configuration only if config-ssh
 			// has been run previously.
 			var lastConfig *sshConfigOptions
-			section, ok, err := sshConfigGetCoderSection(configRaw)
+			section, ok, err := sshConfigGetCoderSection(configRaw, deployment)
 			if err != nil {
 				return err
 			}
@@ -282,10 +401,36 @@ func (r *RootCmd) configSSH() *serpent.Command {
 				}
 			}
 
+			sshOptionsFile := root.SSHOptions()
+			overrides, err := readSSHConfigOverrides(sshOptionsFile)
+			if err != nil {
+				return err
+			}
+			oldOverrides, err := json.Marshal(overrides)
+			if err != nil {
+				return err
+			}
+			overrides.Workspaces, err = setSSHConfigOverrides(overrides.Workspaces, workspaceSSHOptions)
+			if err != nil {
+				return xerrors.Errorf("invalid option from flag: %w", err)
+			}
+			overrides.Templates, err = setSSHConfigOverrides(overrides.Templates, templateSSHOptions)
+			if err != nil {
+				return xerrors.Errorf("invalid option from flag: %w", err)
+			}
+			newOverrides, err := json.Marshal(overrides)
+			if err != nil {
+				return err
+			}
+			overridesChanged := !bytes.Equal(oldOverrides, newOverrides)
+			if overridesChanged {
+				changes = append(changes, fmt.Sprintf("Update the workspace and template SSH options in %s", sshOptionsFile))
+			}
+
 			configModified := configRaw
 
 			buf := &bytes.Buffer{}
-			before, _, after, err := sshConfigSplitOnCoderSection(configModified)
+			before, _, after, err := sshConfigSplitOnCoderSection(configModified, deployment)
 			if err != nil {
 				return err
 			}
@@ -295,26 +440,32 @@ func (r *RootCmd) configSSH() *serpent.Command {
 			// Write comment and store the provided options as part
 			// of the config for future (re)use.
 			newline := len(before) > 0
-			sshConfigWriteSectionHeader(buf, newline, sshConfigOpts)
-
-			coderdConfig, err := client.SSHConfiguration(ctx)
-			if err != nil {
-				// If the error is 404, this deployment does not support
-				// this endpoint yet. Do not error, just assume defaults.
-				// TODO: Remove this in 2 months (May 31, 2023). Just return the error
-				// 	and remove this 404 check.
-				var sdkErr *codersdk.Error
-				if !(xerrors.As(err, &sdkErr) && sdkErr.StatusCode() == http.StatusNotFound) {
-					return xerrors.Errorf("fetch coderd config failed: %w", err)
-				}
-				coderdConfig.HostnamePrefix = "coder."
-			}
+			sshConfigWriteSectionHeader(buf, newline, deployment.url, sshConfigOpts)
 
 			if sshConfigOpts.userHostPrefix != "" {
 				// Override with user flag.
 				coderdConfig.HostnamePrefix = sshConfigOpts.userHostPrefix
 			}
 
+			// SSH uses the first matching Host entry, so the sections of
+			// other deployments must not use the same host prefix.
+			for _, other := range [][]byte{before, after} {
+				sections, err := sshConfigCoderSections(other)
+				if err != nil {
+					return err
+				}
+				for _, s := range sections {
+					if sshConfigParseHostPrefix(other[s.start:s.end]) != coderdConfig.HostnamePrefix {
+						continue
+					}
+					owner := s.deploymentURL
+					if owner == "" {
+						owner = "another deployment"
+					}
+					return xerrors.Errorf("host prefix %q is already used by the coder section of %s, use --ssh-host-prefix to choose another one", coderdConfig.HostnamePrefix, owner)
+				}
+			}
+
 			// Write agent configuration.
 			defaultOptions := []string{
 				"ConnectTimeout=0",
@@ -377,6 +528,38 @@ func (r *RootCmd) configSSH() *serpent.Command {
 				return err
 			}
 
+			// Workspaces with their own options get a Host entry before
+			// the wildcard entry, since SSH uses the first value of an
+			// option.
+			if !overrides.empty() {
+				res, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{
+					Owner: codersdk.Me,
+				})
+				if err != nil {
+					return xerrors.Errorf("fetch workspaces failed: %w", err)
+				}
+				workspaces := res.Workspaces
+				slices.SortFunc(workspaces, func(a, b codersdk.Workspace) int {
+					return strings.Compare(a.Name, b.Name)
+				})
+				for _, workspace := range workspaces {
+					options := overrides.options(workspace)
+					if len(options) == 0 {
+						continue
+					}
+					host := coderdConfig.HostnamePrefix + workspace.Name
+					// The second pattern matches the agents of the workspace.
+					workspaceBlock := []string{
+						fmt.Sprintf("Host %s %s.*", host, host),
+					}
+					for _, v := range options {
+						workspaceBlock = append(workspaceBlock, "\t"+v)
+					}
+					_, _ = buf.WriteString(strings.Join(workspaceBlock, "\n"))
+					_ = buf.WriteByte('\n')
+				}
+			}
+
 			hostBlock := []string{
 				"Host " + coderdConfig.HostnamePrefix + "*",
 			}
@@ -433,6 +616,14 @@ func (r *RootCmd) configSSH() *serpent.Command {
 				}
 			}
 
+			if overridesChanged {
+				err = overrides.write(sshOptionsFile)
+				if err != nil {
+					return xerrors.Errorf("write ssh options failed: %w", err)
+				}
+				_, _ = fmt.Fprintf(out, "Updated %q\n", sshOptionsFile)
+			}
+
 			if !bytes.Equal(configRaw, configModified) {
 				err = atomic.WriteFile(sshConfigFile, bytes.NewReader(configModified))
 				if err != nil {
@@ -492,6 +683,22 @@ func (r *RootCmd) configSSH() *serpent.Command {
 			Description:   "Specifies additional SSH options to embed in each host stanza.",
 			Value:         serpent.StringArrayOf(&sshConfigOpts.sshOptions),
 		},
+		{
+			Flag: "workspace-ssh-option",
+			Env:  "CODER_SSH_CONFIG_WORKSPACE_OPTS",
+			Description: "Specifies an SSH option for a single workspace, in the form <workspace>:<option>. " +
+				"It takes precedence over template and global options, and is saved in the CLI config directory for later runs. " +
+				"An option with an empty value removes it, e.g. dev:ForwardAgent=.",
+			Value: serpent.StringArrayOf(&workspaceSSHOptions),
+		},
+		{
+			Flag: "template-ssh-option",
+			Env:  "CODER_SSH_CONFIG_TEMPLATE_OPTS",
+			Description: "Specifies an SSH option for the workspaces of a template, in the form <template>:<option>. " +
+				"It takes precedence over global options, and is saved in the CLI config directory for later runs. " +
+				"An option with an empty value removes it, e.g. docker:ForwardAgent=.",
+			Value: serpent.StringArrayOf(&templateSSHOptions),
+		},
 		{
 			Flag:          "dry-run",
 			FlagShorthand: "n",
@@ -552,7 +759,7 @@ func (r *RootCmd) configSSH() *serpent.Command {
 }
 
 //nolint:revive
-func sshConfigWriteSectionHeader(w io.Writer, addNewline bool, o sshConfigOptions) {
+func sshConfigWriteSectionHeader(w io.Writer, addNewline bool, deploymentURL string, o sshConfigOptions) {
 	nl := "\n"
 	if !addNewline {
 		nl = ""
@@ -584,6 +791,10 @@ func sshConfigWriteSectionHeader(w io.Writer, addNewline bool, o sshConfigOption
 		_, _ = fmt.Fprint(w, sshConfigOptionsHeader)
 		_, _ = fmt.Fprint(w, ow.String())
 	}
+	if deploymentURL != "" {
+		_, _ = fmt.Fprint(w, "#\n")
+		_, _ = fmt.Fprint(w, sshConfigDeploymentPrefix+deploymentURL+"\n")
+	}
 
 	_, _ = fmt.Fprint(w, "#\n")
 }
@@ -627,10 +838,19 @@ func sshConfigParseLastOptions(r io.Reader) (o sshConfigOptions) {
 	return o
 }
 
+// sshConfigDeployment identifies the deployment config-ssh runs against.
+type sshConfigDeployment struct {
+	url string
+	// globalConfig is the escaped global config directory passed to the
+	// ProxyCommand.
+	globalConfig string
+	hostPrefix   string
+}
+
 // sshConfigGetCoderSection is a helper function that only returns the coder
-// section of the SSH config and a boolean if it exists.
-func sshConfigGetCoderSection(data []byte) (section []byte, ok bool, err error) {
-	_, section, _, err = sshConfigSplitOnCoderSection(data)
+// section of the deployment in the SSH config and a boolean if it exists.
+func sshConfigGetCoderSection(data []byte, deployment sshConfigDeployment) (section []byte, ok bool, err error) {
+	_, section, _, err = sshConfigSplitOnCoderSection(data, deployment)
 	if err != nil {
 		return nil, false, err
 	}
@@ -639,41 +859,134 @@ func sshConfigGetCoderSection(data []byte) (section []byte, ok bool, err error)
 }
 
 // sshConfigSplitOnCoderSection splits the SSH config into 3 sections.
-// All lines before sshStartToken, the coder section, and all lines after
-// sshEndToken.
-func sshConfigSplitOnCoderSection(data []byte) (before, section []byte, after []byte, err error) {
-	startCount := bytes.Count(data, []byte(sshStartToken))
-	endCount := bytes.Count(data, []byte(sshEndToken))
-	if startCount > 1 || endCount > 1 {
-		return nil, nil, nil, xerrors.New("Malformed config: ssh config has multiple coder sections, please remove all but one")
+// All lines before the coder section of the deployment, the coder section,
+// and all lines after it. A section written before sections were keyed by
+// deployment is taken over if it belongs to the deployment, see
+// sshConfigSectionBelongsTo.
+func sshConfigSplitOnCoderSection(data []byte, deployment sshConfigDeployment) (before, section []byte, after []byte, err error) {
+	sections, err := sshConfigCoderSections(data)
+	if err != nil {
+		return nil, nil, nil, err
 	}
 
-	startIndex := bytes.Index(data, []byte(sshStartToken))
-	endIndex := bytes.Index(data, []byte(sshEndToken))
-	if startIndex == -1 && endIndex != -1 {
-		return nil, nil, nil, xerrors.New("Malformed config: ssh config has end header, but missing start header")
+	match := -1
+	for i, s := range sections {
+		if s.deploymentURL == deployment.url {
+			match = i
+			break
+		}
+		if s.deploymentURL == "" && match == -1 && sshConfigSectionBelongsTo(data[s.start:s.end], deployment) {
+			match = i
+		}
 	}
-	if startIndex != -1 && endIndex == -1 {
-		return nil, nil, nil, xerrors.New("Malformed config: ssh config has start header, but missing end header")
+	if match == -1 {
+		return data, nil, nil, nil
 	}
-	if startIndex != -1 && endIndex != -1 {
+	s := sections[match]
+	return data[:s.start], data[s.start:s.end], data[s.end:], nil
+}
+
+// sshConfigSection is the location of a coder section in the SSH config.
+type sshConfigSection struct {
+	start, end    int
+	deploymentURL string
+}
+
+// sshConfigCoderSections returns the coder sections of the SSH config, one
+// for each deployment config-ssh has been run against.
+func sshConfigCoderSections(data []byte) ([]sshConfigSection, error) {
+	var sections []sshConfigSection
+	offset := 0
+	withoutDeployment := 0
+	for {
+		rest := data[offset:]
+		startIndex := bytes.Index(rest, []byte(sshStartToken))
+		endIndex := bytes.Index(rest, []byte(sshEndToken))
+		if startIndex == -1 && endIndex == -1 {
+			// Sections without a deployment cannot be told apart.
+			if withoutDeployment > 1 {
+				return nil, xerrors.New("Malformed config: ssh config has multiple coder sections, please remove all but one")
+			}
+			return sections, nil
+		}
+		if startIndex == -1 {
+			return nil, xerrors.New("Malformed config: ssh config has end header, but missing start header")
+		}
+		if endIndex == -1 {
+			return nil, xerrors.New("Malformed config: ssh config has start header, but missing end header")
+		}
 		if startIndex > endIndex {
-			return nil, nil, nil, xerrors.New("Malformed config: ssh config has coder section, but it is malformed and the END header is before the START header")
+			return nil, xerrors.New("Malformed config: ssh config has coder section, but it is malformed and the END header is before the START header")
 		}
+		if bytes.Contains(rest[startIndex+len(sshStartToken):endIndex], []byte(sshStartToken)) {
+			return nil, xerrors.New("Malformed config: ssh config has coder section with multiple start headers, please remove all but one")
+		}
+
 		// We use -1 and +1 here to also include the preceding
 		// and trailing newline, where applicable.
-		start := startIndex
+		start := offset + startIndex
 		if start > 0 {
 			start--
 		}
-		end := endIndex + len(sshEndToken)
+		end := offset + endIndex + len(sshEndToken)
 		if end < len(data) {
 			end++
 		}
-		return data[:start], data[start:end], data[end:], nil
+		section := sshConfigSection{
+			start:         start,
+			end:           end,
+			deploymentURL: sshConfigParseDeploymentURL(data[start:end]),
+		}
+		if section.deploymentURL == "" {
+			withoutDeployment++
+		}
+		sections = append(sections, section)
+		offset = end
 	}
+}
 
-	return data, nil, nil, nil
+func sshConfigParseDeploymentURL(section []byte) string {
+	s := bufio.NewScanner(bytes.NewReader(section))
+	for s.Scan() {
+		if u, ok := strings.CutPrefix(s.Text(), sshConfigDeploymentPrefix); ok {
+			return strings.TrimSpace(u)
+		}
+	}
+	return ""
+}
+
+// sshConfigSectionBelongsTo reports whether a section without a deployment
+// belongs to the deployment, which is the case when its ProxyCommand uses the
+// same global config directory or its hosts use the same prefix. A section
+// without hosts configures nothing, so any deployment may take it over.
+func sshConfigSectionBelongsTo(section []byte, deployment sshConfigDeployment) bool {
+	hostPrefix := sshConfigParseHostPrefix(section)
+	if hostPrefix == "" || hostPrefix == deployment.hostPrefix {
+		return true
+	}
+	globalConfig := "--global-config " + deployment.globalConfig + " "
+	s := bufio.NewScanner(bytes.NewReader(section))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if strings.HasPrefix(line, "ProxyCommand ") && strings.Contains(line, globalConfig) {
+			return true
+		}
+	}
+	return false
+}
+
+// sshConfigParseHostPrefix returns the host prefix of a coder section, which
+// is the pattern of the last Host entry.
+func sshConfigParseHostPrefix(section []byte) string {
+	var prefix string
+	s := bufio.NewScanner(bytes.NewReader(section))
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 2 && strings.EqualFold(fields[0], "Host") && strings.HasSuffix(fields[1], "*") {
+			prefix = strings.TrimSuffix(fields[1], "*")
+		}
+	}
+	return prefix
 }
 
 // sshConfigExecEscape quotes the string if it contains spaces, as per
@@ -21,12 +21,13 @@ func Test_sshConfigSplitOnCoderSection(t *testing.T) {
 	t.Parallel()
 
 	testCases := []struct {
-		Name    string
-		Input   string
-		Before  string
-		Section string
-		After   string
-		Err     bool
+		Name       string
+		Input      string
+		Deployment sshConfigDeployment
+		Before     string
+		Section    string
+		After      string
+		Err        bool
 	}{
 		{
 			Name:    "Empty",
@@ -115,6 +116,138 @@ func Test_sshConfigSplitOnCoderSection(t *testing.T) {
 			}, "\n"),
 			Err: true,
 		},
+		{
+			Name: "NestedStart",
+			Input: strings.Join([]string{
+				sshStartToken,
+				sshStartToken,
+				sshEndToken,
+			}, "\n"),
+			Err: true,
+		},
+		{
+			Name: "MultipleDeployments",
+			Input: strings.Join([]string{
+				"# Content before the sections",
+				sshStartToken,
+				sshConfigDeploymentPrefix + "https://prod.example.com",
+				sshEndToken,
+				sshStartToken,
+				sshConfigDeploymentPrefix + "https://staging.example.com",
+				sshEndToken,
+				"# Content after the sections",
+			}, "\n"),
+			Deployment: sshConfigDeployment{url: "https://staging.example.com"},
+			Before: strings.Join([]string{
+				"# Content before the sections",
+				sshStartToken,
+				sshConfigDeploymentPrefix + "https://prod.example.com",
+				sshEndToken,
+			}, "\n"),
+			Section: strings.Join([]string{
+				"",
+				sshStartToken,
+				sshConfigDeploymentPrefix + "https://staging.example.com",
+				sshEndToken,
+				"",
+			}, "\n"),
+			After: "# Content after the sections",
+		},
+		{
+			Name: "NewDeployment",
+			Input: strings.Join([]string{
+				sshStartToken,
+				sshConfigDeploymentPrefix + "https://prod.example.com",
+				sshEndToken,
+			}, "\n"),
+			Deployment: sshConfigDeployment{url: "https://staging.example.com"},
+			Before: strings.Join([]string{
+				sshStartToken,
+				sshConfigDeploymentPrefix + "https://prod.example.com",
+				sshEndToken,
+			}, "\n"),
+		},
+		{
+			Name: "AdoptSectionWithoutDeployment",
+			Input: strings.Join([]string{
+				sshStartToken,
+				sshConfigDeploymentPrefix + "https://prod.example.com",
+				sshEndToken,
+				sshStartToken,
+				sshEndToken,
+			}, "\n"),
+			Deployment: sshConfigDeployment{url: "https://staging.example.com"},
+			Before: strings.Join([]string{
+				sshStartToken,
+				sshConfigDeploymentPrefix + "https://prod.example.com",
+				sshEndToken,
+			}, "\n"),
+			Section: strings.Join([]string{
+				"",
+				sshStartToken,
+				sshEndToken,
+			}, "\n"),
+		},
+		{
+			Name: "AdoptSectionWithSameGlobalConfig",
+			Input: strings.Join([]string{
+				sshStartToken,
+				"Host coder.*",
+				"\tProxyCommand coder --global-config /home/me/.config/coderv2 ssh --stdio --ssh-host-prefix coder. %h",
+				sshEndToken,
+			}, "\n"),
+			Deployment: sshConfigDeployment{
+				url:          "https://prod.example.com",
+				globalConfig: "/home/me/.config/coderv2",
+				hostPrefix:   "prod.",
+			},
+			Section: strings.Join([]string{
+				sshStartToken,
+				"Host coder.*",
+				"\tProxyCommand coder --global-config /home/me/.config/coderv2 ssh --stdio --ssh-host-prefix coder. %h",
+				sshEndToken,
+			}, "\n"),
+		},
+		{
+			Name: "AdoptSectionWithSameHostPrefix",
+			Input: strings.Join([]string{
+				sshStartToken,
+				"Host coder.*",
+				"\tProxyCommand coder --global-config /home/me/.config/coderv2 ssh --stdio --ssh-host-prefix coder. %h",
+				sshEndToken,
+			}, "\n"),
+			Deployment: sshConfigDeployment{
+				url:          "https://prod.example.com",
+				globalConfig: "/home/me/.prod",
+				hostPrefix:   "coder.",
+			},
+			Section: strings.Join([]string{
+				sshStartToken,
+				"Host coder.*",
+				"\tProxyCommand coder --global-config /home/me/.config/coderv2 ssh --stdio --ssh-host-prefix coder. %h",
+				sshEndToken,
+			}, "\n"),
+		},
+		{
+			Name: "LeaveSectionOfOtherDeployment",
+			Input: strings.Join([]string{
+				sshStartToken,
+				"Host coder.*",
+				"\tProxyCommand coder --global-config /home/me/.config/coderv2 ssh --stdio --ssh-host-prefix coder. %h",
+				sshEndToken,
+			}, "\n"),
+			Deployment: sshConfigDeployment{
+				url:          "https://staging.example.com",
+				globalConfig: "/home/me/.staging",
+				hostPrefix:   "staging.",
+			},
+			Before: strings.Join([]string{
+				sshStartToken,
+				"Host coder.*",
+				"\tProxyCommand coder --global-config /home/me/.config/coderv2 ssh --stdio --ssh-host-prefix coder. %h",
+				sshEndToken,
+			}, "\n"),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -122,7 +255,7 @@ func Test_sshConfigSplitOnCoderSection(t *testing.T) {
 		t.Run(tc.Name, func(t *testing.T) {
 			t.Parallel()
 
-			before, section, after, err := sshConfigSplitOnCoderSection([]byte(tc.Input))
+			before, section, after, err := sshConfigSplitOnCoderSection([]byte(tc.Input), tc.Deployment)
 			if tc.Err {
 				require.Error(t, err)
 				return
@@ -136,6 +269,52 @@ func Test_sshConfigSplitOnCoderSection(t *testing.T) {
 	}
 }
 
+func Test_sshConfigParseHostPrefix(t *testing.T) {
+	t.Parallel()
+
+	section := strings.Join([]string{
+		sshStartToken,
+		"Host coder.dev coder.dev.*",
+		"\tForwardAgent yes",
+		"Host staging.*",
+		"\tConnectTimeout=0",
+		sshEndToken,
+	}, "\n")
+	require.Equal(t, "staging.", sshConfigParseHostPrefix([]byte(section)))
+	require.Equal(t, "", sshConfigParseHostPrefix([]byte(sshStartToken+"\n"+sshEndToken)))
+}
+
+func Test_setSSHConfigOverrides(t *testing.T) {
+	t.Parallel()
+
+	overrides, err := setSSHConfigOverrides(nil, []string{
+		"dev:ForwardAgent=yes",
+		"dev:RemoteForward 2222 localhost:22",
+		"prod:ForwardX11 yes",
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{
+		"dev":  {"ForwardAgent=yes", "RemoteForward 2222 localhost:22"},
+		"prod": {"ForwardX11 yes"},
+	}, overrides)
+
+	// Options replace options with the same key, and empty values remove
+	// them.
+	overrides, err = setSSHConfigOverrides(overrides, []string{
+		"dev:forwardagent no",
+		"prod:ForwardX11=",
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{
+		"dev": {"RemoteForward 2222 localhost:22", "forwardagent no"},
+	}, overrides)
+
+	_, err = setSSHConfigOverrides(nil, []string{"ForwardAgent=yes"})
+	require.Error(t, err)
+	_, err = setSSHConfigOverrides(nil, []string{":ForwardAgent=yes"})
+	require.Error(t, err)
+}
+
 // This test tries to mimic the behavior of OpenSSH
 // when executing e.g. a ProxyCommand.
 // nolint:tparallel
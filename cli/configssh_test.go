@@ -674,3 +674,115 @@ func TestConfigSSH_FileWriteAndOptionsFlow(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigSSH_MultipleDeployments(t *testing.T) {
+	t.Parallel()
+
+	prod := coderdtest.New(t, nil)
+	_ = coderdtest.CreateFirstUser(t, prod)
+	staging := coderdtest.New(t, nil)
+	_ = coderdtest.CreateFirstUser(t, staging)
+
+	sshConfigFile := sshConfigFileName(t)
+	configSSH := func(client *codersdk.Client, args ...string) error {
+		inv, root := clitest.New(t, append([]string{
+			"config-ssh",
+			"--ssh-config-file", sshConfigFile,
+			"--yes",
+		}, args...)...)
+		clitest.SetupConfig(t, client, root)
+		return inv.Run()
+	}
+
+	err := configSSH(prod)
+	require.NoError(t, err)
+
+	// The default host prefix of the second deployment clashes with the
+	// first one.
+	err = configSSH(staging)
+	require.ErrorContains(t, err, `host prefix "coder." is already used`)
+
+	err = configSSH(staging, "--ssh-host-prefix", "staging.")
+	require.NoError(t, err)
+
+	// Running config-ssh against the first deployment again leaves the
+	// section of the second one untouched.
+	err = configSSH(prod, "--ssh-option", "ForwardAgent=yes")
+	require.NoError(t, err)
+
+	got := sshConfigFileRead(t, sshConfigFile)
+	require.Equal(t, 2, strings.Count(got, "# ------------START-CODER-----------"))
+	for _, want := range []string{
+		"# :ssh-option=ForwardAgent=yes",
+		"# Deployment: " + prod.URL.String(),
+		"Host coder.*",
+		"# :ssh-host-prefix=staging.",
+		"# Deployment: " + staging.URL.String(),
+		"Host staging.*",
+	} {
+		idx := strings.Index(got, want)
+		require.NotEqual(t, -1, idx, "want %q in ssh config:\n%s", want, got)
+		got = got[idx+len(want):]
+	}
+}
+
+func TestConfigSSH_WorkspaceAndTemplateOptions(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		Name:           "dev",
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        owner.UserID,
+	}).WithAgent().Do()
+	_ = dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		Name:           "other",
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        owner.UserID,
+	}).WithAgent().Do()
+
+	sshConfigFile := sshConfigFileName(t)
+	// The options are saved in the config directory, so all runs share it.
+	_, root := clitest.New(t)
+	clitest.SetupConfig(t, client, root)
+	configSSH := func(args ...string) {
+		inv, _ := clitest.New(t, append([]string{
+			"--global-config", string(root),
+			"config-ssh",
+			"--ssh-config-file", sshConfigFile,
+			"--yes",
+		}, args...)...)
+		err := inv.Run()
+		require.NoError(t, err)
+	}
+
+	configSSH(
+		"--template-ssh-option", r.Template.Name+":ForwardAgent=yes",
+		"--template-ssh-option", r.Template.Name+":ForwardX11=yes",
+		"--workspace-ssh-option", "dev:ForwardAgent=no",
+	)
+	got := sshConfigFileRead(t, sshConfigFile)
+	// Workspace options take precedence over template options, and the
+	// Host entry comes before the wildcard entry.
+	require.Contains(t, got, strings.Join([]string{
+		"Host coder.dev coder.dev.*",
+		"\tForwardAgent=no",
+		"\tForwardAgent=yes",
+		"\tForwardX11=yes",
+		"Host coder.*",
+	}, "\n"))
+	require.NotContains(t, got, "Host coder.other")
+
+	// Later runs use the saved options, and options with an empty value
+	// are removed.
+	configSSH("--template-ssh-option", r.Template.Name+":ForwardX11=")
+	got = sshConfigFileRead(t, sshConfigFile)
+	require.Contains(t, got, strings.Join([]string{
+		"Host coder.dev coder.dev.*",
+		"\tForwardAgent=no",
+		"\tForwardAgent=yes",
+		"Host coder.*",
+	}, "\n"))
+	require.Contains(t, sshConfigFileRead(t, string(root.SSHOptions())), "ForwardAgent=no")
+}
@@ -0,0 +1,266 @@
+package cli
+
+import (
+	"context"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) cp() *serpent.Command {
+	var recursive bool
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Annotations: workspaceCommand,
+		Use:         "cp <source> <destination>",
+		Short:       "Copy files to or from a workspace",
+		Long: "Remote paths are written as <workspace>[.<agent>]:<path>, and relative " +
+			"remote paths are resolved from the home directory of the workspace user. " +
+			"Exactly one of the source and destination must be remote. Files are " +
+			"transferred through the Coder deployment, so they work without SSH, but " +
+			"are subject to the block file transfer setting of the agent.\n" + FormatExamples(
+			Example{
+				Description: "Copy a local file into the home directory of a workspace",
+				Command:     "coder cp ./notes.txt my-workspace:",
+			},
+			Example{
+				Description: "Copy a directory from a specific agent of a workspace",
+				Command:     "coder cp -r my-workspace.main:~/project/dist ./dist",
+			},
+		),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Options: serpent.OptionSet{
+			{
+				Flag:          "recursive",
+				FlagShorthand: "r",
+				Description:   "Copy directories recursively.",
+				Value:         serpent.BoolOf(&recursive),
+			},
+		},
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+
+			src := parseCopyPath(inv.Args[0])
+			dst := parseCopyPath(inv.Args[1])
+			if src.workspace == "" && dst.workspace == "" || src.workspace != "" && dst.workspace != "" {
+				return xerrors.New("exactly one of the source and destination must be a remote path, e.g. my-workspace:~/file.txt")
+			}
+			remote := src
+			if src.workspace == "" {
+				remote = dst
+			}
+
+			_, workspaceAgent, err := getWorkspaceAndAgent(ctx, inv, client, false, remote.workspace)
+			if err != nil {
+				return err
+			}
+			c := &fileCopier{
+				client:    client,
+				agentID:   workspaceAgent.ID,
+				recursive: recursive,
+			}
+			if src.workspace != "" {
+				return c.download(ctx, src.path, dst.path)
+			}
+			return c.upload(ctx, src.path, dst.path)
+		},
+	}
+	return cmd
+}
+
+type copyPath struct {
+	// workspace is the workspace and optional agent of a remote path, or
+	// empty for a local path.
+	workspace string
+	path      string
+}
+
+// parseCopyPath parses a local path or a remote path in the form
+// <workspace>[.<agent>]:<path>.
+func parseCopyPath(arg string) copyPath {
+	idx := strings.Index(arg, ":")
+	if idx <= 0 {
+		return copyPath{path: arg}
+	}
+	prefix := arg[:idx]
+	// Paths such as ./a:b are local, as are Windows drive letters.
+	if strings.ContainsAny(prefix, `/\`) || runtime.GOOS == "windows" && len(prefix) == 1 {
+		return copyPath{path: arg}
+	}
+	p := arg[idx+1:]
+	if p == "" {
+		p = "."
+	}
+	return copyPath{workspace: prefix, path: p}
+}
+
+type fileCopier struct {
+	client    *codersdk.Client
+	agentID   uuid.UUID
+	recursive bool
+}
+
+func (c *fileCopier) upload(ctx context.Context, localPath, remotePath string) error {
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() && !c.recursive {
+		return xerrors.Errorf("%s is a directory, use --recursive to copy it", localPath)
+	}
+
+	// Like cp, copy into the destination if it is an existing directory.
+	target := remotePath
+	if strings.HasSuffix(remotePath, "/") {
+		target = path.Join(remotePath, filepath.Base(localPath))
+	} else {
+		remoteInfo, err := c.client.WorkspaceAgentStatFile(ctx, c.agentID, remotePath)
+		if err == nil && remoteInfo.IsDir {
+			target = path.Join(remotePath, filepath.Base(localPath))
+		} else if err != nil && !isNotFound(err) {
+			return xerrors.Errorf("stat %s: %w", remotePath, err)
+		}
+	}
+
+	if !info.IsDir() {
+		return c.uploadFile(ctx, localPath, target, info.Mode())
+	}
+	return filepath.WalkDir(localPath, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(localPath, p)
+		if err != nil {
+			return err
+		}
+		dest := path.Join(target, filepath.ToSlash(rel))
+		if d.IsDir() {
+			_, err = c.client.WorkspaceAgentMakeDirectory(ctx, c.agentID, dest)
+			if err != nil {
+				return xerrors.Errorf("create directory %s: %w", dest, err)
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			// Skip symlinks, sockets and other special files.
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return c.uploadFile(ctx, p, dest, info.Mode())
+	})
+}
+
+func (c *fileCopier) uploadFile(ctx context.Context, localPath, remotePath string, mode os.FileMode) error {
+	f, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = c.client.WorkspaceAgentUploadFile(ctx, c.agentID, remotePath, mode, f)
+	if err != nil {
+		return xerrors.Errorf("upload %s: %w", localPath, err)
+	}
+	return nil
+}
+
+func (c *fileCopier) download(ctx context.Context, remotePath, localPath string) error {
+	info, err := c.client.WorkspaceAgentStatFile(ctx, c.agentID, remotePath)
+	if err != nil {
+		return xerrors.Errorf("stat %s: %w", remotePath, err)
+	}
+	if info.IsDir && !c.recursive {
+		return xerrors.Errorf("%s is a directory, use --recursive to copy it", remotePath)
+	}
+
+	// Like cp, copy into the destination if it is an existing directory.
+	target := localPath
+	if localInfo, err := os.Stat(localPath); err == nil && localInfo.IsDir() {
+		target = filepath.Join(localPath, info.Name)
+	}
+
+	if !info.IsDir {
+		return c.downloadFile(ctx, info, target)
+	}
+	return c.downloadDirectory(ctx, info, target)
+}
+
+func (c *fileCopier) downloadDirectory(ctx context.Context, dir codersdk.WorkspaceAgentFileInfo, localPath string) error {
+	err := os.MkdirAll(localPath, 0o755)
+	if err != nil {
+		return err
+	}
+	list, err := c.client.WorkspaceAgentListFiles(ctx, c.agentID, dir.Path)
+	if err != nil {
+		return xerrors.Errorf("list %s: %w", dir.Path, err)
+	}
+	for _, file := range list.Files {
+		target := filepath.Join(localPath, file.Name)
+		if file.IsDir {
+			err = c.downloadDirectory(ctx, file, target)
+		} else if strings.HasPrefix(file.Mode, "-") {
+			err = c.downloadFile(ctx, file, target)
+		}
+		// Other files, such as symlinks, are skipped.
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *fileCopier) downloadFile(ctx context.Context, file codersdk.WorkspaceAgentFileInfo, localPath string) error {
+	rc, err := c.client.WorkspaceAgentDownloadFile(ctx, c.agentID, file.Path)
+	if err != nil {
+		return xerrors.Errorf("download %s: %w", file.Path, err)
+	}
+	defer rc.Close()
+
+	f, err := os.OpenFile(localPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, fileModePerm(file.Mode))
+	if err != nil {
+		return err
+	}
+	_, err = f.ReadFrom(rc)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return xerrors.Errorf("write %s: %w", localPath, err)
+	}
+	return nil
+}
+
+// fileModePerm parses the permission bits from a mode string such as
+// "-rwxr-xr-x".
+func fileModePerm(mode string) os.FileMode {
+	if len(mode) < 9 {
+		return 0o644
+	}
+	var perm os.FileMode
+	for i, c := range mode[len(mode)-9:] {
+		if c != '-' {
+			perm |= 1 << (8 - i)
+		}
+	}
+	return perm
+}
+
+func isNotFound(err error) bool {
+	var sdkErr *codersdk.Error
+	return xerrors.As(err, &sdkErr) && sdkErr.StatusCode() == http.StatusNotFound
+}
@@ -0,0 +1,87 @@
+package cli_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent/agenttest"
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestCp(t *testing.T) {
+	t.Parallel()
+
+	t.Run("File", func(t *testing.T) {
+		t.Parallel()
+
+		client, workspace, agentToken := setupWorkspaceForAgent(t)
+		_ = agenttest.New(t, client.URL, agentToken)
+		_ = coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		local := t.TempDir()
+		remote := t.TempDir()
+		err := os.WriteFile(filepath.Join(local, "hello.sh"), []byte("echo hello"), 0o700)
+		require.NoError(t, err)
+
+		// Copying into an existing directory keeps the file name.
+		inv, root := clitest.New(t, "cp", filepath.Join(local, "hello.sh"), workspace.Name+":"+remote)
+		clitest.SetupConfig(t, client, root)
+		require.NoError(t, inv.WithContext(ctx).Run())
+		content, err := os.ReadFile(filepath.Join(remote, "hello.sh"))
+		require.NoError(t, err)
+		require.Equal(t, "echo hello", string(content))
+
+		inv, root = clitest.New(t, "cp", workspace.Name+":"+filepath.Join(remote, "hello.sh"), filepath.Join(local, "copy.sh"))
+		clitest.SetupConfig(t, client, root)
+		require.NoError(t, inv.WithContext(ctx).Run())
+		info, err := os.Stat(filepath.Join(local, "copy.sh"))
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o700), info.Mode().Perm())
+	})
+
+	t.Run("Directory", func(t *testing.T) {
+		t.Parallel()
+
+		client, workspace, agentToken := setupWorkspaceForAgent(t)
+		_ = agenttest.New(t, client.URL, agentToken)
+		_ = coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		remote := t.TempDir()
+		err := os.MkdirAll(filepath.Join(remote, "project", "src"), 0o755)
+		require.NoError(t, err)
+		err = os.WriteFile(filepath.Join(remote, "project", "src", "main.go"), []byte("package main"), 0o600)
+		require.NoError(t, err)
+
+		inv, root := clitest.New(t, "cp", workspace.Name+":"+filepath.Join(remote, "project"), t.TempDir())
+		clitest.SetupConfig(t, client, root)
+		err = inv.WithContext(ctx).Run()
+		require.ErrorContains(t, err, "use --recursive")
+
+		local := filepath.Join(t.TempDir(), "project")
+		inv, root = clitest.New(t, "cp", "-r", workspace.Name+":"+filepath.Join(remote, "project"), local)
+		clitest.SetupConfig(t, client, root)
+		require.NoError(t, inv.WithContext(ctx).Run())
+		content, err := os.ReadFile(filepath.Join(local, "src", "main.go"))
+		require.NoError(t, err)
+		require.Equal(t, "package main", string(content))
+	})
+
+	t.Run("NoRemotePath", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		inv, root := clitest.New(t, "cp", "a.txt", "b.txt")
+		clitest.SetupConfig(t, client, root)
+		err := inv.Run()
+		require.ErrorContains(t, err, "exactly one of the source and destination must be a remote path")
+	})
+}
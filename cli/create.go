@@ -290,14 +290,23 @@ func (r *RootCmd) create() *serpent.Command {
 				ttlMillis = ptr.Ref(stopAfter.Milliseconds())
 			}
 
-			workspace, err := client.CreateUserWorkspace(inv.Context(), workspaceOwner, codersdk.CreateWorkspaceRequest{
+			req := codersdk.CreateWorkspaceRequest{
 				TemplateVersionID:   templateVersionID,
 				Name:                workspaceName,
 				AutostartSchedule:   schedSpec,
 				TTLMillis:           ttlMillis,
 				RichParameterValues: richParameters,
 				AutomaticUpdates:    codersdk.AutomaticUpdates(autoUpdates),
-			})
+			}
+			// Create the workspace from the template unless a version was
+			// chosen, so it builds the candidate version if it's included in
+			// the rollout in progress of the template.
+			if len(templateVersion) == 0 && copyParametersFrom == "" {
+				req.TemplateID = template.ID
+				req.TemplateVersionID = uuid.Nil
+			}
+
+			workspace, err := client.CreateUserWorkspace(inv.Context(), workspaceOwner, req)
 			if err != nil {
 				return xerrors.Errorf("create workspace: %w", err)
 			}
@@ -380,6 +389,11 @@ type prepWorkspaceBuildArgs struct {
 	RichParameters        []codersdk.WorkspaceBuildParameter
 	RichParameterFile     string
 	RichParameterDefaults []codersdk.WorkspaceBuildParameter
+
+	// PreviewWorkspaceID is set to preview updating an existing workspace.
+	// The dry-run plans against the workspace's current state, and the
+	// changes it would make are shown instead of the resulting resources.
+	PreviewWorkspaceID uuid.UUID
 }
 
 // prepWorkspaceBuild will ensure a workspace build will succeed on the latest template version.
@@ -432,6 +446,7 @@ func prepWorkspaceBuild(inv *serpent.Invocation, client *codersdk.Client, args p
 	dryRun, err := client.CreateTemplateVersionDryRun(inv.Context(), templateVersion.ID, codersdk.CreateTemplateVersionDryRunRequest{
 		WorkspaceName:       args.NewWorkspaceName,
 		RichParameterValues: buildParameters,
+		WorkspaceID:         args.PreviewWorkspaceID,
 	})
 	if err != nil {
 		return nil, xerrors.Errorf("begin workspace dry-run: %w", err)
@@ -462,6 +477,18 @@ func prepWorkspaceBuild(inv *serpent.Invocation, client *codersdk.Client, args p
 		return nil, xerrors.Errorf("dry-run workspace: %w", err)
 	}
 
+	if args.PreviewWorkspaceID != uuid.Nil {
+		changes, err := client.TemplateVersionDryRunResourceChanges(inv.Context(), templateVersion.ID, dryRun.ID)
+		if err != nil {
+			return nil, xerrors.Errorf("get workspace dry-run resource changes: %w", err)
+		}
+		err = cliui.WorkspaceResourceChanges(inv.Stdout, changes)
+		if err != nil {
+			return nil, xerrors.Errorf("show resource changes: %w", err)
+		}
+		return buildParameters, nil
+	}
+
 	resources, err := client.TemplateVersionDryRunResources(inv.Context(), templateVersion.ID, dryRun.ID)
 	if err != nil {
 		return nil, xerrors.Errorf("get workspace dry-run resources: %w", err)
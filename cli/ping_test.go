@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/agent/agenttest"
 	"github.com/coder/coder/v2/cli/clitest"
@@ -43,6 +44,20 @@ func TestPing(t *testing.T) {
 		<-cmdDone
 	})
 
+	t.Run("UnknownAgent", func(t *testing.T) {
+		t.Parallel()
+
+		// Dev container names are only accepted by ssh, other commands
+		// connect to an agent.
+		client, workspace, _ := setupWorkspaceForAgent(t)
+		inv, root := clitest.New(t, "ping", workspace.Name+".unknown")
+		clitest.SetupConfig(t, client, root)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		err := inv.WithContext(ctx).Run()
+		require.ErrorContains(t, err, `agent not found by name "unknown"`)
+	})
+
 	t.Run("1Ping", func(t *testing.T) {
 		t.Parallel()
 
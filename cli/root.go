@@ -93,6 +93,7 @@ func (r *RootCmd) CoreSubcommands() []*serpent.Command {
 		r.portForward(),
 		r.publickey(),
 		r.resetPassword(),
+		r.secrets(),
 		r.state(),
 		r.templates(),
 		r.tokens(),
@@ -102,6 +103,7 @@ func (r *RootCmd) CoreSubcommands() []*serpent.Command {
 		// Workspace Commands
 		r.autoupdate(),
 		r.configSSH(),
+		r.cp(),
 		r.create(),
 		r.deleteWorkspace(),
 		r.favorite(),
@@ -112,6 +114,7 @@ func (r *RootCmd) CoreSubcommands() []*serpent.Command {
 		r.restart(),
 		r.schedules(),
 		r.show(),
+		r.snapshot(),
 		r.speedtest(),
 		r.ssh(),
 		r.start(),
@@ -56,7 +56,7 @@ When enabling scheduled stop, enter a duration in one of the following formats:
 func (r *RootCmd) schedules() *serpent.Command {
 	scheduleCmd := &serpent.Command{
 		Annotations: workspaceCommand,
-		Use:         "schedule { show | start | stop | extend } <workspace>",
+		Use:         "schedule { show | start | stop | extend | action } <workspace>",
 		Short:       "Schedule automated start and stop times for workspaces",
 		Handler: func(inv *serpent.Invocation) error {
 			return inv.Command.HelpHandler(inv)
@@ -66,6 +66,7 @@ func (r *RootCmd) schedules() *serpent.Command {
 			r.scheduleStart(),
 			r.scheduleStop(),
 			r.scheduleExtend(),
+			r.scheduleAction(),
 		},
 	}
 
@@ -0,0 +1,215 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/coderd/schedule/cron"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/serpent"
+)
+
+const scheduleActionAddDescriptionLong = `Schedules an action to regularly run on a workspace, in addition to its start and stop schedule.
+Actions:
+  * start:   Start the workspace if it is stopped.
+  * stop:    Stop the workspace if it is running, regardless of activity.
+  * restart: Stop the workspace if it is running, then start it again.
+  * update:  Update the workspace to the active template version if it is outdated.
+Schedule format: <time> [day-of-week] [location], the same as "coder schedule start".
+Actions can run at most once an hour.
+`
+
+func (r *RootCmd) scheduleAction() *serpent.Command {
+	return &serpent.Command{
+		Use:     "action { list | add | remove }",
+		Aliases: []string{"actions"},
+		Short:   "Manage scheduled actions that start, stop, restart or update workspaces",
+		Handler: func(inv *serpent.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*serpent.Command{
+			r.scheduleActionList(),
+			r.scheduleActionAdd(),
+			r.scheduleActionRemove(),
+		},
+	}
+}
+
+// scheduleActionListRow is the type provided to the OutputFormatter.
+type scheduleActionListRow struct {
+	// For JSON format:
+	codersdk.WorkspaceScheduledAction `table:"-"`
+
+	// For table format:
+	ID       string `json:"-" table:"id"`
+	Action   string `json:"-" table:"action,default_sort"`
+	Schedule string `json:"-" table:"schedule"`
+	NextRun  string `json:"-" table:"next run"`
+	LastRun  string `json:"-" table:"last run"`
+}
+
+func scheduleActionListRowFromAction(action codersdk.WorkspaceScheduledAction) scheduleActionListRow {
+	schedule := action.Schedule
+	if sched, err := cron.Weekly(action.Schedule); err == nil {
+		schedule = sched.Humanize()
+	}
+	lastRun := ""
+	if action.LastRunAt != nil {
+		lastRun = timeDisplay(*action.LastRunAt)
+	}
+	return scheduleActionListRow{
+		WorkspaceScheduledAction: action,
+		ID:                       action.ID.String(),
+		Action:                   string(action.Action),
+		Schedule:                 schedule,
+		NextRun:                  timeDisplay(action.NextRunAt),
+		LastRun:                  lastRun,
+	}
+}
+
+func (r *RootCmd) scheduleActionList() *serpent.Command {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]scheduleActionListRow{}, []string{"id", "action", "schedule", "next run", "last run"}),
+		cliui.JSONFormat(),
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:     "list <workspace>",
+		Aliases: []string{"ls"},
+		Short:   "List the scheduled actions of a workspace",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			if err != nil {
+				return err
+			}
+			actions, err := client.WorkspaceScheduledActions(inv.Context(), workspace.ID)
+			if err != nil {
+				return xerrors.Errorf("list scheduled actions: %w", err)
+			}
+
+			if len(actions) == 0 {
+				cliui.Infof(
+					inv.Stdout,
+					"No scheduled actions found.\n",
+				)
+				return nil
+			}
+
+			rows := make([]scheduleActionListRow, len(actions))
+			for i, action := range actions {
+				rows[i] = scheduleActionListRowFromAction(action)
+			}
+			out, err := formatter.Format(inv.Context(), rows)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) scheduleActionAdd() *serpent.Command {
+	client := new(codersdk.Client)
+	return &serpent.Command{
+		Use: "add <workspace-name> { start | stop | restart | update } <time> [day-of-week] [location]",
+		Long: scheduleActionAddDescriptionLong + "\n" + FormatExamples(
+			Example{
+				Description: "Restart the workspace every Monday at 6:00am (in Dublin)",
+				Command:     "coder schedule action add my-workspace restart 6:00AM Mon Europe/Dublin",
+			},
+			Example{
+				Description: "Update the workspace to the latest template version every night",
+				Command:     "coder schedule action add my-workspace update 2:00AM",
+			},
+			Example{
+				Description: "Stop the workspace at 7:00pm on Fridays",
+				Command:     "coder schedule action add my-workspace stop 19:00 Fri",
+			},
+		),
+		Short: "Schedule an action to regularly run on a workspace",
+		Middleware: serpent.Chain(
+			serpent.RequireRangeArgs(3, 5),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			if err != nil {
+				return err
+			}
+
+			actionType := codersdk.WorkspaceScheduledActionType(strings.ToLower(inv.Args[1]))
+			switch actionType {
+			case codersdk.WorkspaceScheduledActionTypeStart,
+				codersdk.WorkspaceScheduledActionTypeStop,
+				codersdk.WorkspaceScheduledActionTypeRestart,
+				codersdk.WorkspaceScheduledActionTypeUpdate:
+			default:
+				return xerrors.Errorf("invalid action %q, must be one of start, stop, restart or update", inv.Args[1])
+			}
+
+			sched, err := parseCLISchedule(inv.Args[2:]...)
+			if err != nil {
+				return err
+			}
+
+			action, err := client.CreateWorkspaceScheduledAction(inv.Context(), workspace.ID, codersdk.CreateWorkspaceScheduledActionRequest{
+				Action:   actionType,
+				Schedule: sched.String(),
+			})
+			if err != nil {
+				return xerrors.Errorf("create scheduled action: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout,
+				"Scheduled %s of %s %s. The next run is at %s.\n",
+				cliui.Keyword(string(action.Action)),
+				cliui.Keyword(workspace.Name),
+				sched.Humanize(),
+				cliui.Timestamp(action.NextRunAt),
+			)
+			return nil
+		},
+	}
+}
+
+func (r *RootCmd) scheduleActionRemove() *serpent.Command {
+	client := new(codersdk.Client)
+	return &serpent.Command{
+		Use:   "remove <workspace-name> <action-id>",
+		Short: "Remove a scheduled action from a workspace",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			if err != nil {
+				return err
+			}
+			actionID, err := uuid.Parse(inv.Args[1])
+			if err != nil {
+				return xerrors.Errorf("invalid action ID %q, run \"coder schedule action list %s\" to find it: %w", inv.Args[1], workspace.Name, err)
+			}
+
+			err = client.DeleteWorkspaceScheduledAction(inv.Context(), workspace.ID, actionID)
+			if err != nil {
+				return xerrors.Errorf("remove scheduled action: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Removed scheduled action %s from %s.\n", cliui.Keyword(actionID.String()), cliui.Keyword(workspace.Name))
+			return nil
+		},
+	}
+}
@@ -0,0 +1,415 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/pretty"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) secrets() *serpent.Command {
+	cmd := &serpent.Command{
+		Use:   "secrets",
+		Short: "Manage secrets that are injected into workspaces",
+		Long: "Secrets are injected into workspaces by the agent as environment variables or files. " +
+			"Personal secrets are injected into all of your workspaces, and override template " +
+			"and organization secrets.\n" + FormatExamples(
+			Example{
+				Description: "Inject a personal GitHub token into all of your workspaces",
+				Command:     "coder secrets create github-token --env GITHUB_TOKEN",
+			},
+			Example{
+				Description: "Write a file from stdin into all workspaces of a template",
+				Command:     "coder secrets create npmrc --scope template --template my-template --file ~/.npmrc --value - < .npmrc",
+			},
+			Example{
+				Description: "List the secrets of an organization",
+				Command:     "coder secrets ls --scope organization",
+			},
+		),
+		Aliases: []string{"secret"},
+		Handler: func(inv *serpent.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*serpent.Command{
+			r.createSecret(),
+			r.listSecrets(),
+			r.updateSecret(),
+			r.removeSecret(),
+		},
+	}
+	return cmd
+}
+
+// secretScopeFlags select the user, organization or template that secrets
+// belong to.
+type secretScopeFlags struct {
+	scope      string
+	template   string
+	orgContext *OrganizationContext
+}
+
+func newSecretScopeFlags() *secretScopeFlags {
+	return &secretScopeFlags{orgContext: NewOrganizationContext()}
+}
+
+func (f *secretScopeFlags) attach(cmd *serpent.Command) {
+	cmd.Options = append(cmd.Options,
+		serpent.Option{
+			Flag:        "scope",
+			Env:         "CODER_SECRET_SCOPE",
+			Description: "Whether the secret belongs to you, the organization, or a template.",
+			Default:     "user",
+			Value:       serpent.EnumOf(&f.scope, "user", "organization", "template"),
+		},
+		serpent.Option{
+			Flag:          "template",
+			FlagShorthand: "t",
+			Env:           "CODER_SECRET_TEMPLATE",
+			Description:   "The template the secret belongs to. Required with --scope=template.",
+			Value:         serpent.StringOf(&f.template),
+		},
+	)
+	f.orgContext.AttachOptions(cmd)
+}
+
+// secretScope is a resolved secretScopeFlags.
+type secretScope struct {
+	organizationID uuid.UUID
+	templateID     uuid.UUID
+}
+
+func (f *secretScopeFlags) resolve(inv *serpent.Invocation, client *codersdk.Client) (secretScope, error) {
+	if f.template != "" && f.scope != "template" {
+		return secretScope{}, xerrors.New("--template can only be used with --scope=template")
+	}
+	switch f.scope {
+	case "organization":
+		org, err := f.orgContext.Selected(inv, client)
+		if err != nil {
+			return secretScope{}, err
+		}
+		return secretScope{organizationID: org.ID}, nil
+	case "template":
+		if f.template == "" {
+			return secretScope{}, xerrors.New("--template is required with --scope=template")
+		}
+		org, err := f.orgContext.Selected(inv, client)
+		if err != nil {
+			return secretScope{}, err
+		}
+		template, err := client.TemplateByName(inv.Context(), org.ID, f.template)
+		if err != nil {
+			return secretScope{}, xerrors.Errorf("get template by name: %w", err)
+		}
+		return secretScope{templateID: template.ID}, nil
+	default:
+		return secretScope{}, nil
+	}
+}
+
+func (s secretScope) list(ctx context.Context, client *codersdk.Client) ([]codersdk.Secret, error) {
+	switch {
+	case s.organizationID != uuid.Nil:
+		return client.OrganizationSecrets(ctx, s.organizationID)
+	case s.templateID != uuid.Nil:
+		return client.TemplateSecrets(ctx, s.templateID)
+	default:
+		return client.UserSecrets(ctx, codersdk.Me)
+	}
+}
+
+func (s secretScope) create(ctx context.Context, client *codersdk.Client, req codersdk.CreateSecretRequest) (codersdk.Secret, error) {
+	switch {
+	case s.organizationID != uuid.Nil:
+		return client.CreateOrganizationSecret(ctx, s.organizationID, req)
+	case s.templateID != uuid.Nil:
+		return client.CreateTemplateSecret(ctx, s.templateID, req)
+	default:
+		return client.CreateUserSecret(ctx, codersdk.Me, req)
+	}
+}
+
+func (s secretScope) update(ctx context.Context, client *codersdk.Client, name string, req codersdk.UpdateSecretRequest) (codersdk.Secret, error) {
+	switch {
+	case s.organizationID != uuid.Nil:
+		return client.UpdateOrganizationSecret(ctx, s.organizationID, name, req)
+	case s.templateID != uuid.Nil:
+		return client.UpdateTemplateSecret(ctx, s.templateID, name, req)
+	default:
+		return client.UpdateUserSecret(ctx, codersdk.Me, name, req)
+	}
+}
+
+func (s secretScope) remove(ctx context.Context, client *codersdk.Client, name string) error {
+	switch {
+	case s.organizationID != uuid.Nil:
+		return client.DeleteOrganizationSecret(ctx, s.organizationID, name)
+	case s.templateID != uuid.Nil:
+		return client.DeleteTemplateSecret(ctx, s.templateID, name)
+	default:
+		return client.DeleteUserSecret(ctx, codersdk.Me, name)
+	}
+}
+
+// readSecretValue returns the value of the --value flag. A value of "-" is
+// read from stdin, so secrets do not end up in the shell history.
+func readSecretValue(inv *serpent.Invocation, value string) (string, error) {
+	if value != "-" {
+		return value, nil
+	}
+	data, err := io.ReadAll(inv.Stdin)
+	if err != nil {
+		return "", xerrors.Errorf("read value from stdin: %w", err)
+	}
+	return string(data), nil
+}
+
+func (r *RootCmd) createSecret() *serpent.Command {
+	var (
+		scopeFlags  = newSecretScopeFlags()
+		value       string
+		envName     string
+		filePath    string
+		description string
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "create <name>",
+		Short: "Create a secret",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			if envName == "" && filePath == "" {
+				return xerrors.New("specify where to inject the secret with --env or --file")
+			}
+			scope, err := scopeFlags.resolve(inv, client)
+			if err != nil {
+				return err
+			}
+
+			if value == "" {
+				value, err = cliui.Prompt(inv, cliui.PromptOptions{
+					Text:   "Value:",
+					Secret: true,
+				})
+				if err != nil {
+					return err
+				}
+			}
+			value, err = readSecretValue(inv, value)
+			if err != nil {
+				return err
+			}
+
+			secret, err := scope.create(inv.Context(), client, codersdk.CreateSecretRequest{
+				Name:        inv.Args[0],
+				Description: description,
+				Value:       value,
+				EnvName:     envName,
+				FilePath:    filePath,
+			})
+			if err != nil {
+				return xerrors.Errorf("create secret: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Created secret %s. It is injected into workspaces when they start.\n",
+				pretty.Sprint(cliui.DefaultStyles.Keyword, secret.Name))
+			return nil
+		},
+	}
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "value",
+			Env:         "CODER_SECRET_VALUE",
+			Description: "The value of the secret. Use \"-\" to read it from stdin. If not specified, you will be prompted for it.",
+			Value:       serpent.StringOf(&value),
+		},
+		{
+			Flag:        "env",
+			Env:         "CODER_SECRET_ENV",
+			Description: "The name of the environment variable to inject the secret as.",
+			Value:       serpent.StringOf(&envName),
+		},
+		{
+			Flag:        "file",
+			Env:         "CODER_SECRET_FILE",
+			Description: "The path of the file to write the secret to in the workspace. Paths starting with ~/ are relative to the home directory.",
+			Value:       serpent.StringOf(&filePath),
+		},
+		{
+			Flag:        "description",
+			Env:         "CODER_SECRET_DESCRIPTION",
+			Description: "A description of the secret.",
+			Value:       serpent.StringOf(&description),
+		},
+	}
+	scopeFlags.attach(cmd)
+	return cmd
+}
+
+func (r *RootCmd) listSecrets() *serpent.Command {
+	var (
+		scopeFlags = newSecretScopeFlags()
+		formatter  = cliui.NewOutputFormatter(
+			cliui.TableFormat([]codersdk.Secret{}, []string{"name", "env", "file", "description", "updated at"}),
+			cliui.JSONFormat(),
+		)
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List secrets",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			scope, err := scopeFlags.resolve(inv, client)
+			if err != nil {
+				return err
+			}
+			secrets, err := scope.list(inv.Context(), client)
+			if err != nil {
+				return xerrors.Errorf("list secrets: %w", err)
+			}
+			if len(secrets) == 0 {
+				cliui.Infof(
+					inv.Stdout,
+					"No secrets found.\n",
+				)
+				return nil
+			}
+
+			out, err := formatter.Format(inv.Context(), secrets)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	scopeFlags.attach(cmd)
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) updateSecret() *serpent.Command {
+	var (
+		scopeFlags  = newSecretScopeFlags()
+		value       string
+		envName     string
+		filePath    string
+		description string
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "update <name>",
+		Short: "Update a secret",
+		Long:  "Only the specified options are changed. Set --env or --file to an empty value to stop injecting the secret as an environment variable or file.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			scope, err := scopeFlags.resolve(inv, client)
+			if err != nil {
+				return err
+			}
+
+			var req codersdk.UpdateSecretRequest
+			if inv.ParsedFlags().Changed("value") {
+				value, err = readSecretValue(inv, value)
+				if err != nil {
+					return err
+				}
+				req.Value = &value
+			}
+			if inv.ParsedFlags().Changed("env") {
+				req.EnvName = &envName
+			}
+			if inv.ParsedFlags().Changed("file") {
+				req.FilePath = &filePath
+			}
+			if inv.ParsedFlags().Changed("description") {
+				req.Description = &description
+			}
+			if req == (codersdk.UpdateSecretRequest{}) {
+				return xerrors.New("nothing to update: specify --value, --env, --file or --description")
+			}
+
+			secret, err := scope.update(inv.Context(), client, inv.Args[0], req)
+			if err != nil {
+				return xerrors.Errorf("update secret: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Updated secret %s. Workspaces receive the change when they restart.\n",
+				pretty.Sprint(cliui.DefaultStyles.Keyword, secret.Name))
+			return nil
+		},
+	}
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "value",
+			Env:         "CODER_SECRET_VALUE",
+			Description: "The new value of the secret. Use \"-\" to read it from stdin.",
+			Value:       serpent.StringOf(&value),
+		},
+		{
+			Flag:        "env",
+			Env:         "CODER_SECRET_ENV",
+			Description: "The name of the environment variable to inject the secret as.",
+			Value:       serpent.StringOf(&envName),
+		},
+		{
+			Flag:        "file",
+			Env:         "CODER_SECRET_FILE",
+			Description: "The path of the file to write the secret to in the workspace.",
+			Value:       serpent.StringOf(&filePath),
+		},
+		{
+			Flag:        "description",
+			Env:         "CODER_SECRET_DESCRIPTION",
+			Description: "A description of the secret.",
+			Value:       serpent.StringOf(&description),
+		},
+	}
+	scopeFlags.attach(cmd)
+	return cmd
+}
+
+func (r *RootCmd) removeSecret() *serpent.Command {
+	scopeFlags := newSecretScopeFlags()
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:     "remove <name>",
+		Aliases: []string{"delete"},
+		Short:   "Delete a secret",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			scope, err := scopeFlags.resolve(inv, client)
+			if err != nil {
+				return err
+			}
+			err = scope.remove(inv.Context(), client, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("delete secret: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Deleted secret %s.\n", pretty.Sprint(cliui.DefaultStyles.Keyword, inv.Args[0]))
+			return nil
+		},
+	}
+	scopeFlags.attach(cmd)
+	return cmd
+}
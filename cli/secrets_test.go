@@ -0,0 +1,111 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/pty/ptytest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestSecrets(t *testing.T) {
+	t.Parallel()
+
+	t.Run("User", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		inv, root := clitest.New(t, "secrets", "ls")
+		clitest.SetupConfig(t, member, root)
+		buf := new(bytes.Buffer)
+		inv.Stdout = buf
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Contains(t, buf.String(), "No secrets found")
+
+		// The value is prompted for when it is not specified.
+		inv, root = clitest.New(t, "secrets", "create", "github-token", "--env", "GITHUB_TOKEN")
+		clitest.SetupConfig(t, member, root)
+		pty := ptytest.New(t).Attach(inv)
+		done := make(chan error)
+		go func() {
+			done <- inv.WithContext(ctx).Run()
+		}()
+		pty.ExpectMatch("Value:")
+		pty.WriteLine("ghp_token")
+		pty.ExpectMatch("Created secret")
+		require.NoError(t, testutil.RequireRecvCtx(ctx, t, done))
+
+		inv, root = clitest.New(t, "secrets", "update", "github-token", "--description", "Used by gh", "--value", "-")
+		clitest.SetupConfig(t, member, root)
+		inv.Stdin = strings.NewReader("rotated")
+		buf = new(bytes.Buffer)
+		inv.Stdout = buf
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Contains(t, buf.String(), "Updated secret")
+
+		inv, root = clitest.New(t, "secrets", "ls", "-o", "json")
+		clitest.SetupConfig(t, member, root)
+		buf = new(bytes.Buffer)
+		inv.Stdout = buf
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		var secrets []codersdk.Secret
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &secrets))
+		require.Len(t, secrets, 1)
+		require.Equal(t, "github-token", secrets[0].Name)
+		require.Equal(t, "GITHUB_TOKEN", secrets[0].EnvName)
+		require.Equal(t, "Used by gh", secrets[0].Description)
+		// The value is never returned.
+		require.NotContains(t, buf.String(), "rotated")
+
+		inv, root = clitest.New(t, "secrets", "rm", "github-token")
+		clitest.SetupConfig(t, member, root)
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		secrets, err = member.UserSecrets(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Empty(t, secrets)
+	})
+
+	t.Run("Template", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		inv, root := clitest.New(t, "secrets", "create", "npmrc",
+			"--scope", "template", "--template", template.Name,
+			"--file", "~/.npmrc", "--value", "-")
+		//nolint:gocritic // Template secrets are managed by template admins.
+		clitest.SetupConfig(t, client, root)
+		inv.Stdin = strings.NewReader("//registry.npmjs.org/:_authToken=token\n")
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		secrets, err := client.TemplateSecrets(ctx, template.ID)
+		require.NoError(t, err)
+		require.Len(t, secrets, 1)
+		require.Equal(t, "~/.npmrc", secrets[0].FilePath)
+
+		inv, root = clitest.New(t, "secrets", "ls", "--scope", "template")
+		//nolint:gocritic // Template secrets are managed by template admins.
+		clitest.SetupConfig(t, client, root)
+		err = inv.WithContext(ctx).Run()
+		require.ErrorContains(t, err, "--template is required")
+	})
+}
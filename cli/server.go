@@ -706,7 +706,19 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				}()
 
 				options.Database = database.New(sqlDB)
-				ps, err := pubsub.New(ctx, logger.Named("pubsub"), sqlDB, dbURL)
+				var ps interface {
+					pubsub.Pubsub
+					prometheus.Collector
+				}
+				switch codersdk.PubsubBackend(vals.Pubsub.Backend) {
+				case codersdk.PubsubBackendNATS:
+					if vals.Pubsub.NATSURL == "" {
+						return xerrors.New("--pubsub-nats-url is required when --pubsub-backend is nats")
+					}
+					ps, err = pubsub.NewNATS(logger.Named("pubsub"), vals.Pubsub.NATSURL.String(), vals.Pubsub.NATSSubjectPrefix.String())
+				default:
+					ps, err = pubsub.New(ctx, logger.Named("pubsub"), sqlDB, dbURL)
+				}
 				if err != nil {
 					return xerrors.Errorf("create pubsub: %w", err)
 				}
@@ -844,6 +856,12 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				}
 				defer closeAgentsFunc()
 
+				closeNetworkRegionsFunc, err := prometheusmetrics.NetworkRegions(ctx, logger.Named("network_regions_metrics"), options.PrometheusRegistry, coderAPI.Database, coderAPI.DERPMap, 0)
+				if err != nil {
+					return xerrors.Errorf("register network regions prometheus metric: %w", err)
+				}
+				defer closeNetworkRegionsFunc()
+
 				var active codersdk.Experiments
 				for _, exp := range options.DeploymentValues.Experiments.Value() {
 					active = append(active, codersdk.Experiment(exp))
@@ -928,6 +946,12 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			var provisionerdWaitGroup sync.WaitGroup
 			defer provisionerdWaitGroup.Wait()
 			provisionerdMetrics := provisionerd.NewMetrics(options.PrometheusRegistry)
+			// The built-in provisioner daemons share their Terraform cache.
+			terraformCache := provisionerdTerraformCache{
+				Path:    filepath.Join(cacheDir, "tf"),
+				MaxSize: vals.Provisioner.TerraformCacheMaxSize.Value() * 1024 * 1024,
+				Metrics: terraform.NewCacheMetrics(options.PrometheusRegistry),
+			}
 
 			// Built in provisioner daemons will support the same types.
 			// By default, this is the slice {"terraform"}
@@ -943,7 +967,7 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				name := fmt.Sprintf("%s-%s", hostname, suffix)
 				daemonCacheDir := filepath.Join(cacheDir, fmt.Sprintf("provisioner-%d", i))
 				daemon, err := newProvisionerDaemon(
-					ctx, coderAPI, provisionerdMetrics, logger, vals, daemonCacheDir, errCh, &provisionerdWaitGroup, name, provisionerTypes,
+					ctx, coderAPI, provisionerdMetrics, logger, vals, daemonCacheDir, terraformCache, errCh, &provisionerdWaitGroup, name, provisionerTypes,
 				)
 				if err != nil {
 					return xerrors.Errorf("create provisioner daemon: %w", err)
@@ -956,7 +980,7 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			defer shutdownConns()
 
 			// Ensures that old database entries are cleaned up over time!
-			purger := dbpurge.New(ctx, logger.Named("dbpurge"), options.Database, quartz.NewReal())
+			purger := dbpurge.New(ctx, logger.Named("dbpurge"), options.Database, options.DeploymentValues, quartz.NewReal())
 			defer purger.Close()
 
 			// Updates workspace usage
@@ -1310,6 +1334,14 @@ func shutdownWithTimeout(shutdown func(context.Context) error, timeout time.Dura
 	return shutdown(ctx)
 }
 
+// provisionerdTerraformCache configures the Terraform cache of built-in
+// provisioner daemons.
+type provisionerdTerraformCache struct {
+	Path    string
+	MaxSize int64
+	Metrics *terraform.CacheMetrics
+}
+
 // nolint:revive
 func newProvisionerDaemon(
 	ctx context.Context,
@@ -1318,6 +1350,7 @@ func newProvisionerDaemon(
 	logger slog.Logger,
 	cfg *codersdk.DeploymentValues,
 	cacheDir string,
+	terraformCache provisionerdTerraformCache,
 	errCh chan error,
 	wg *sync.WaitGroup,
 	name string,
@@ -1377,7 +1410,7 @@ func newProvisionerDaemon(
 			}()
 			connector[string(database.ProvisionerTypeEcho)] = sdkproto.NewDRPCProvisionerClient(echoClient)
 		case codersdk.ProvisionerTypeTerraform:
-			tfDir := filepath.Join(cacheDir, "tf")
+			tfDir := terraformCache.Path
 			err = os.MkdirAll(tfDir, 0o700)
 			if err != nil {
 				return nil, xerrors.Errorf("mkdir terraform dir: %w", err)
@@ -1403,8 +1436,10 @@ func newProvisionerDaemon(
 						Logger:        provisionerLogger,
 						WorkDirectory: workDir,
 					},
-					CachePath: tfDir,
-					Tracer:    tracer,
+					CachePath:    tfDir,
+					CacheMaxSize: terraformCache.MaxSize,
+					CacheMetrics: terraformCache.Metrics,
+					Tracer:       tracer,
 				})
 				if err != nil && !xerrors.Is(err, context.Canceled) {
 					select {
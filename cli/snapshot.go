@@ -0,0 +1,283 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/pretty"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) snapshot() *serpent.Command {
+	cmd := &serpent.Command{
+		Annotations: workspaceCommand,
+		Use:         "snapshot",
+		Short:       "Create, list and restore workspace snapshots",
+		Long: "Snapshots persist workspace data, such as a home volume, using the " +
+			"coder_workspace_snapshot resources in the workspace's template. They can " +
+			"be restored into any workspace with the same owner and template, e.g. " +
+			"after deleting and recreating a workspace.\n" + FormatExamples(
+			Example{
+				Description: "Snapshot a workspace",
+				Command:     "coder snapshot create my-workspace --name before-update",
+			},
+			Example{
+				Description: "List the snapshots that can be restored into a workspace",
+				Command:     "coder snapshot list my-workspace",
+			},
+			Example{
+				Description: "Restore a snapshot into a workspace",
+				Command:     "coder snapshot restore my-workspace before-update",
+			},
+		),
+		Aliases: []string{"snapshots"},
+		Handler: func(inv *serpent.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*serpent.Command{
+			r.createSnapshot(),
+			r.listSnapshots(),
+			r.restoreSnapshot(),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) createSnapshot() *serpent.Command {
+	var name string
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "create <workspace>",
+		Short: "Stop a workspace and snapshot its data",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Options: serpent.OptionSet{
+			{
+				Flag:          "name",
+				FlagShorthand: "n",
+				Description:   "Specify a name for the snapshot. Defaults to the current time.",
+				Value:         serpent.StringOf(&name),
+			},
+			cliui.SkipPromptOption(),
+		},
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+
+			workspace, err := namedWorkspace(ctx, client, inv.Args[0])
+			if err != nil {
+				return err
+			}
+			if name == "" {
+				name = "snapshot-" + time.Now().UTC().Format("20060102-150405")
+			}
+
+			_, err = cliui.Prompt(inv, cliui.PromptOptions{
+				Text:      fmt.Sprintf("Stop %s to snapshot it?", pretty.Sprint(cliui.DefaultStyles.Keyword, workspace.Name)),
+				IsConfirm: true,
+			})
+			if err != nil {
+				return err
+			}
+
+			snapshot, err := client.CreateWorkspaceSnapshot(ctx, workspace.ID, codersdk.CreateWorkspaceSnapshotRequest{
+				Name: name,
+			})
+			if err != nil {
+				return xerrors.Errorf("create snapshot: %w", err)
+			}
+			err = cliui.WorkspaceBuild(ctx, inv.Stdout, client, snapshot.BuildID)
+			if err != nil {
+				return err
+			}
+
+			snapshot, err = findSnapshot(ctx, client, workspace.ID, snapshot.ID.String())
+			if err != nil {
+				return err
+			}
+			if snapshot.Status != codersdk.WorkspaceSnapshotStatusReady {
+				return xerrors.Errorf("snapshot %q is %s, check that the template defines a coder_workspace_snapshot resource", snapshot.Name, snapshot.Status)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout,
+				"\nThe %s snapshot of %s has been created at %s!\n",
+				cliui.Keyword(snapshot.Name),
+				cliui.Keyword(workspace.Name),
+				cliui.Timestamp(time.Now()),
+			)
+			return nil
+		},
+	}
+	return cmd
+}
+
+// snapshotListRow is the type provided to the OutputFormatter.
+type snapshotListRow struct {
+	// For JSON format:
+	codersdk.WorkspaceSnapshot `table:"-"`
+
+	// For table format:
+	Name      string    `json:"-" table:"name,default_sort"`
+	Status    string    `json:"-" table:"status"`
+	CreatedAt time.Time `json:"-" table:"created at"`
+	ID        string    `json:"-" table:"id"`
+	Artifacts string    `json:"-" table:"artifacts"`
+}
+
+func snapshotListRowFromSnapshot(snapshot codersdk.WorkspaceSnapshot) snapshotListRow {
+	artifacts := make([]string, 0, len(snapshot.Artifacts))
+	for name, artifact := range snapshot.Artifacts {
+		artifacts = append(artifacts, name+"="+artifact)
+	}
+	sort.Strings(artifacts)
+	return snapshotListRow{
+		WorkspaceSnapshot: snapshot,
+		Name:              snapshot.Name,
+		Status:            string(snapshot.Status),
+		CreatedAt:         snapshot.CreatedAt,
+		ID:                snapshot.ID.String(),
+		Artifacts:         strings.Join(artifacts, ", "),
+	}
+}
+
+func (r *RootCmd) listSnapshots() *serpent.Command {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]snapshotListRow{}, []string{"name", "status", "created at"}),
+		cliui.JSONFormat(),
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:     "list <workspace>",
+		Aliases: []string{"ls"},
+		Short:   "List the snapshots that can be restored into a workspace",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			if err != nil {
+				return err
+			}
+			snapshots, err := client.WorkspaceSnapshots(inv.Context(), workspace.ID)
+			if err != nil {
+				return xerrors.Errorf("list snapshots: %w", err)
+			}
+
+			if len(snapshots) == 0 {
+				cliui.Infof(
+					inv.Stdout,
+					"No snapshots found.\n",
+				)
+				return nil
+			}
+
+			rows := make([]snapshotListRow, len(snapshots))
+			for i, snapshot := range snapshots {
+				rows[i] = snapshotListRowFromSnapshot(snapshot)
+			}
+			out, err := formatter.Format(inv.Context(), rows)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) restoreSnapshot() *serpent.Command {
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "restore <workspace> <snapshot>",
+		Short: "Restore a snapshot into a workspace, stopping it first if needed",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Options: serpent.OptionSet{
+			cliui.SkipPromptOption(),
+		},
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+
+			workspace, err := namedWorkspace(ctx, client, inv.Args[0])
+			if err != nil {
+				return err
+			}
+			snapshot, err := findSnapshot(ctx, client, workspace.ID, inv.Args[1])
+			if err != nil {
+				return err
+			}
+
+			_, err = cliui.Prompt(inv, cliui.PromptOptions{
+				Text: fmt.Sprintf("Restore %s into %s? Changes made since the snapshot was taken will be lost.",
+					pretty.Sprint(cliui.DefaultStyles.Keyword, snapshot.Name),
+					pretty.Sprint(cliui.DefaultStyles.Keyword, workspace.Name),
+				),
+				IsConfirm: true,
+			})
+			if err != nil {
+				return err
+			}
+
+			if workspace.LatestBuild.Transition != codersdk.WorkspaceTransitionStop {
+				build, err := client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+					Transition: codersdk.WorkspaceTransitionStop,
+				})
+				if err != nil {
+					return xerrors.Errorf("stop workspace: %w", err)
+				}
+				err = cliui.WorkspaceBuild(ctx, inv.Stdout, client, build.ID)
+				if err != nil {
+					return err
+				}
+			}
+
+			build, err := client.RestoreWorkspaceSnapshot(ctx, workspace.ID, snapshot.ID)
+			if err != nil {
+				return xerrors.Errorf("restore snapshot: %w", err)
+			}
+			err = cliui.WorkspaceBuild(ctx, inv.Stdout, client, build.ID)
+			if err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout,
+				"\nThe %s snapshot has been restored into %s at %s!\n",
+				cliui.Keyword(snapshot.Name),
+				cliui.Keyword(workspace.Name),
+				cliui.Timestamp(time.Now()),
+			)
+			return nil
+		},
+	}
+	return cmd
+}
+
+// findSnapshot returns a snapshot which can be restored into the workspace by
+// name or ID.
+func findSnapshot(ctx context.Context, client *codersdk.Client, workspaceID uuid.UUID, nameOrID string) (codersdk.WorkspaceSnapshot, error) {
+	snapshots, err := client.WorkspaceSnapshots(ctx, workspaceID)
+	if err != nil {
+		return codersdk.WorkspaceSnapshot{}, xerrors.Errorf("list snapshots: %w", err)
+	}
+	for _, snapshot := range snapshots {
+		if snapshot.ID.String() == nameOrID || strings.EqualFold(snapshot.Name, nameOrID) {
+			return snapshot, nil
+		}
+	}
+	return codersdk.WorkspaceSnapshot{}, xerrors.Errorf("snapshot %q not found", nameOrID)
+}
@@ -0,0 +1,77 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/pty/ptytest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestSnapshot(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.PlanComplete,
+		ProvisionApply: echo.ApplyComplete,
+		ProvisionApplyMap: map[proto.WorkspaceTransition][]*proto.Response{
+			proto.WorkspaceTransition_SNAPSHOT: {{
+				Type: &proto.Response_Apply{Apply: &proto.ApplyComplete{
+					Snapshots: []*proto.Snapshot{{Name: "home", Artifact: "snap-123"}},
+				}},
+			}},
+		},
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, member, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	inv, root := clitest.New(t, "snapshot", "create", workspace.Name, "--name", "before-update", "--yes")
+	clitest.SetupConfig(t, member, root)
+	pty := ptytest.New(t).Attach(inv)
+	w := clitest.StartWithWaiter(t, inv.WithContext(ctx))
+	pty.ExpectMatch("Snapshotting workspace")
+	pty.ExpectMatch("has been created")
+	w.RequireSuccess()
+
+	var buf bytes.Buffer
+	inv, root = clitest.New(t, "snapshot", "list", workspace.Name, "--output", "json")
+	clitest.SetupConfig(t, member, root)
+	inv.Stdout = &buf
+	require.NoError(t, inv.WithContext(ctx).Run())
+	var snapshots []codersdk.WorkspaceSnapshot
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &snapshots))
+	require.Len(t, snapshots, 1)
+	require.Equal(t, "before-update", snapshots[0].Name)
+	require.Equal(t, codersdk.WorkspaceSnapshotStatusReady, snapshots[0].Status)
+
+	// The workspace is stopped after the snapshot, so restoring starts it
+	// directly.
+	inv, root = clitest.New(t, "snapshot", "restore", workspace.Name, "before-update", "--yes")
+	clitest.SetupConfig(t, member, root)
+	pty = ptytest.New(t).Attach(inv)
+	w = clitest.StartWithWaiter(t, inv.WithContext(ctx))
+	pty.ExpectMatch("Restoring workspace")
+	pty.ExpectMatch("has been restored")
+	w.RequireSuccess()
+
+	inv, root = clitest.New(t, "snapshot", "restore", workspace.Name, "missing", "--yes")
+	clitest.SetupConfig(t, member, root)
+	err := inv.WithContext(ctx).Run()
+	require.ErrorContains(t, err, `snapshot "missing" not found`)
+}
@@ -34,6 +34,7 @@ import (
 
 	"cdr.dev/slog"
 	"cdr.dev/slog/sloggers/sloghuman"
+	"github.com/coder/coder/v2/agent/agentssh"
 	"github.com/coder/coder/v2/cli/cliui"
 	"github.com/coder/coder/v2/cli/cliutil"
 	"github.com/coder/coder/v2/coderd/autobuild/notify"
@@ -200,11 +201,10 @@ func (r *RootCmd) ssh() *serpent.Command {
 			// Support "--" as a delimiter between owner and workspace name
 			namedWorkspace = strings.Replace(namedWorkspace, "--", "/", 1)
 
-			workspace, workspaceAgent, err := getWorkspaceAndAgent(ctx, inv, client, !disableAutostart, namedWorkspace)
+			workspace, workspaceAgent, container, err := getWorkspaceAgentAndContainer(ctx, inv, client, !disableAutostart, namedWorkspace)
 			if err != nil {
 				return err
 			}
-
 			// Select the startup script behavior based on template configuration or flags.
 			var wait bool
 			switch waitEnum {
@@ -282,6 +282,13 @@ func (r *RootCmd) ssh() *serpent.Command {
 			}
 			conn.AwaitReachable(ctx)
 
+			if container != "" {
+				err = verifyDevcontainer(ctx, conn, container)
+				if err != nil {
+					return err
+				}
+			}
+
 			stopPolling := tryPollWorkspaceAutostop(ctx, client, workspace)
 			defer stopPolling()
 
@@ -295,7 +302,12 @@ func (r *RootCmd) ssh() *serpent.Command {
 			}
 
 			if stdio {
-				rawSSH, err := conn.SSH(ctx)
+				var rawSSH *gonet.TCPConn
+				if container != "" {
+					rawSSH, err = conn.ContainerSSH(ctx, container)
+				} else {
+					rawSSH, err = conn.SSH(ctx)
+				}
 				if err != nil {
 					return xerrors.Errorf("connect SSH: %w", err)
 				}
@@ -453,6 +465,11 @@ func (r *RootCmd) ssh() *serpent.Command {
 					return xerrors.Errorf("setenv: %w", err)
 				}
 			}
+			if container != "" {
+				if err := sshSession.Setenv(agentssh.MagicContainerEnvironmentVariable, container); err != nil {
+					return xerrors.Errorf("setenv: %w", err)
+				}
+			}
 
 			err = sshSession.RequestPty("xterm-256color", 128, 128, gossh.TerminalModes{})
 			if err != nil {
@@ -672,37 +689,49 @@ startWatchLoop:
 // `<workspace>[.<agent>]` syntax via `in`.
 // If autoStart is true, the workspace will be started if it is not already running.
 func getWorkspaceAndAgent(ctx context.Context, inv *serpent.Invocation, client *codersdk.Client, autostart bool, input string) (codersdk.Workspace, codersdk.WorkspaceAgent, error) { //nolint:revive
+	workspace, workspaceAgent, _, err := getWorkspaceTarget(ctx, inv, client, autostart, false, input)
+	return workspace, workspaceAgent, err
+}
+
+// getWorkspaceAgentAndContainer is like getWorkspaceAndAgent, but also
+// accepts the `<workspace>[.<agent>].<container>` syntax and returns the dev
+// container named in the input, if any.
+func getWorkspaceAgentAndContainer(ctx context.Context, inv *serpent.Invocation, client *codersdk.Client, autostart bool, input string) (codersdk.Workspace, codersdk.WorkspaceAgent, string, error) { //nolint:revive
+	return getWorkspaceTarget(ctx, inv, client, autostart, true, input)
+}
+
+func getWorkspaceTarget(ctx context.Context, inv *serpent.Invocation, client *codersdk.Client, autostart, containers bool, input string) (codersdk.Workspace, codersdk.WorkspaceAgent, string, error) { //nolint:revive
 	var (
 		workspace codersdk.Workspace
-		// The input will be `owner/name.agent`
-		// The agent is optional.
+		// The input will be `owner/name.agent.container`
+		// The agent and container are optional.
 		workspaceParts = strings.Split(input, ".")
 		err            error
 	)
 
 	workspace, err = namedWorkspace(ctx, client, workspaceParts[0])
 	if err != nil {
-		return codersdk.Workspace{}, codersdk.WorkspaceAgent{}, err
+		return codersdk.Workspace{}, codersdk.WorkspaceAgent{}, "", err
 	}
 
 	if workspace.LatestBuild.Transition != codersdk.WorkspaceTransitionStart {
 		if !autostart {
-			return codersdk.Workspace{}, codersdk.WorkspaceAgent{}, xerrors.New("workspace must be started")
+			return codersdk.Workspace{}, codersdk.WorkspaceAgent{}, "", xerrors.New("workspace must be started")
 		}
 		// Autostart the workspace for the user.
 		// For some failure modes, return a better message.
 		if workspace.LatestBuild.Transition == codersdk.WorkspaceTransitionDelete {
 			// Any sort of deleting status, we should reject with a nicer error.
-			return codersdk.Workspace{}, codersdk.WorkspaceAgent{}, xerrors.Errorf("workspace %q is deleted", workspace.Name)
+			return codersdk.Workspace{}, codersdk.WorkspaceAgent{}, "", xerrors.Errorf("workspace %q is deleted", workspace.Name)
 		}
 		if workspace.LatestBuild.Job.Status == codersdk.ProvisionerJobFailed {
-			return codersdk.Workspace{}, codersdk.WorkspaceAgent{},
+			return codersdk.Workspace{}, codersdk.WorkspaceAgent{}, "",
 				xerrors.Errorf("workspace %q is in failed state, unable to autostart the workspace", workspace.Name)
 		}
 		// The workspace needs to be stopped before we can start it.
 		// It cannot be in any pending or failed state.
 		if workspace.LatestBuild.Status != codersdk.WorkspaceStatusStopped {
-			return codersdk.Workspace{}, codersdk.WorkspaceAgent{},
+			return codersdk.Workspace{}, codersdk.WorkspaceAgent{}, "",
 				xerrors.Errorf("workspace must be started; was unable to autostart as the last build job is %q, expected %q",
 					workspace.LatestBuild.Status,
 					codersdk.WorkspaceStatusStopped,
@@ -718,50 +747,89 @@ func getWorkspaceAndAgent(ctx context.Context, inv *serpent.Invocation, client *
 			switch cerr.StatusCode() {
 			case http.StatusConflict:
 				_, _ = fmt.Fprintln(inv.Stderr, "Unable to start the workspace due to conflict, the workspace may be starting, retrying without autostart...")
-				return getWorkspaceAndAgent(ctx, inv, client, false, input)
+				return getWorkspaceTarget(ctx, inv, client, false, containers, input)
 
 			case http.StatusForbidden:
 				_, err = startWorkspace(inv, client, workspace, workspaceParameterFlags{}, buildFlags{}, WorkspaceUpdate)
 				if err != nil {
-					return codersdk.Workspace{}, codersdk.WorkspaceAgent{}, xerrors.Errorf("start workspace with active template version: %w", err)
+					return codersdk.Workspace{}, codersdk.WorkspaceAgent{}, "", xerrors.Errorf("start workspace with active template version: %w", err)
 				}
 				_, _ = fmt.Fprintln(inv.Stdout, "Unable to start the workspace with template version from last build. Your workspace has been updated to the current active template version.")
 			}
 		} else if err != nil {
-			return codersdk.Workspace{}, codersdk.WorkspaceAgent{}, xerrors.Errorf("start workspace with current template version: %w", err)
+			return codersdk.Workspace{}, codersdk.WorkspaceAgent{}, "", xerrors.Errorf("start workspace with current template version: %w", err)
 		}
 
 		// Refresh workspace state so that `outdated`, `build`,`template_*` fields are up-to-date.
 		workspace, err = namedWorkspace(ctx, client, workspaceParts[0])
 		if err != nil {
-			return codersdk.Workspace{}, codersdk.WorkspaceAgent{}, err
+			return codersdk.Workspace{}, codersdk.WorkspaceAgent{}, "", err
 		}
 	}
 	if workspace.LatestBuild.Job.CompletedAt == nil {
 		err := cliui.WorkspaceBuild(ctx, inv.Stderr, client, workspace.LatestBuild.ID)
 		if err != nil {
-			return codersdk.Workspace{}, codersdk.WorkspaceAgent{}, err
+			return codersdk.Workspace{}, codersdk.WorkspaceAgent{}, "", err
 		}
 		// Fetch up-to-date build information after completion.
 		workspace.LatestBuild, err = client.WorkspaceBuild(ctx, workspace.LatestBuild.ID)
 		if err != nil {
-			return codersdk.Workspace{}, codersdk.WorkspaceAgent{}, err
+			return codersdk.Workspace{}, codersdk.WorkspaceAgent{}, "", err
 		}
 	}
 	if workspace.LatestBuild.Transition == codersdk.WorkspaceTransitionDelete {
-		return codersdk.Workspace{}, codersdk.WorkspaceAgent{}, xerrors.Errorf("workspace %q is being deleted", workspace.Name)
+		return codersdk.Workspace{}, codersdk.WorkspaceAgent{}, "", xerrors.Errorf("workspace %q is being deleted", workspace.Name)
 	}
 
-	var agentName string
-	if len(workspaceParts) >= 2 {
-		agentName = workspaceParts[1]
+	if !containers {
+		var agentName string
+		if len(workspaceParts) >= 2 {
+			agentName = workspaceParts[1]
+		}
+		workspaceAgent, err := getWorkspaceAgent(workspace, agentName)
+		if err != nil {
+			return codersdk.Workspace{}, codersdk.WorkspaceAgent{}, "", err
+		}
+		return workspace, workspaceAgent, "", nil
 	}
-	workspaceAgent, err := getWorkspaceAgent(workspace, agentName)
+
+	workspaceAgent, container, err := getWorkspaceAgentTarget(workspace, workspaceParts[1:])
 	if err != nil {
-		return codersdk.Workspace{}, codersdk.WorkspaceAgent{}, err
+		return codersdk.Workspace{}, codersdk.WorkspaceAgent{}, "", err
 	}
 
-	return workspace, workspaceAgent, nil
+	return workspace, workspaceAgent, container, nil
+}
+
+// getWorkspaceAgentTarget resolves the parts following the workspace name,
+// which are either "agent", "container" or "agent.container". A single
+// part is a container if it does not match an agent and the workspace has
+// only one agent.
+func getWorkspaceAgentTarget(workspace codersdk.Workspace, parts []string) (codersdk.WorkspaceAgent, string, error) {
+	switch len(parts) {
+	case 0:
+		workspaceAgent, err := getWorkspaceAgent(workspace, "")
+		return workspaceAgent, "", err
+	case 1:
+		workspaceAgent, err := getWorkspaceAgent(workspace, parts[0])
+		if err == nil {
+			return workspaceAgent, "", nil
+		}
+		var agents []codersdk.WorkspaceAgent
+		for _, resource := range workspace.LatestBuild.Resources {
+			agents = append(agents, resource.Agents...)
+		}
+		if len(agents) != 1 {
+			return codersdk.WorkspaceAgent{}, "", err
+		}
+		return agents[0], parts[0], nil
+	default:
+		workspaceAgent, err := getWorkspaceAgent(workspace, parts[0])
+		if err != nil {
+			return codersdk.WorkspaceAgent{}, "", err
+		}
+		return workspaceAgent, strings.Join(parts[1:], "."), nil
+	}
 }
 
 func getWorkspaceAgent(workspace codersdk.Workspace, agentName string) (workspaceAgent codersdk.WorkspaceAgent, err error) {
@@ -799,6 +867,29 @@ func getWorkspaceAgent(workspace codersdk.Workspace, agentName string) (workspac
 	return workspaceAgent, nil
 }
 
+// verifyDevcontainer checks that the agent is running the named dev
+// container, so a typo does not result in an opaque session error.
+func verifyDevcontainer(ctx context.Context, conn *workspacesdk.AgentConn, name string) error {
+	resp, err := conn.ListDevcontainers(ctx)
+	if err != nil {
+		return xerrors.Errorf("list dev containers: %w", err)
+	}
+	for _, dc := range resp.Devcontainers {
+		if dc.Name != name {
+			continue
+		}
+		switch dc.Status {
+		case codersdk.WorkspaceAgentDevcontainerStatusRunning:
+			return nil
+		case codersdk.WorkspaceAgentDevcontainerStatusError:
+			return xerrors.Errorf("dev container %q failed to start: %s", name, dc.Error)
+		default:
+			return xerrors.Errorf("dev container %q is %s, try again once the workspace is ready", name, dc.Status)
+		}
+	}
+	return xerrors.Errorf("no agent or dev container named %q", name)
+}
+
 // Attempt to poll workspace autostop. We write a per-workspace lockfile to
 // avoid spamming the user with notifications in case of multiple instances
 // of the CLI running simultaneously.
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/xerrors"
@@ -67,6 +68,52 @@ func TestBuildWorkspaceLink(t *testing.T) {
 	assert.Equal(t, workspaceLink.String(), fakeServerURL+"/@"+fakeOwnerName+"/"+fakeWorkspaceName)
 }
 
+func TestGetWorkspaceAgentTarget(t *testing.T) {
+	t.Parallel()
+
+	workspace := func(agentNames ...string) codersdk.Workspace {
+		var agents []codersdk.WorkspaceAgent
+		for _, name := range agentNames {
+			agents = append(agents, codersdk.WorkspaceAgent{ID: uuid.New(), Name: name})
+		}
+		return codersdk.Workspace{
+			Name: fakeWorkspaceName,
+			LatestBuild: codersdk.WorkspaceBuild{
+				Resources: []codersdk.WorkspaceResource{{Agents: agents}},
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		name      string
+		agents    []string
+		parts     []string
+		agent     string
+		container string
+		err       string
+	}{
+		{name: "Default", agents: []string{"main"}, agent: "main"},
+		{name: "Agent", agents: []string{"main", "other"}, parts: []string{"other"}, agent: "other"},
+		{name: "Container", agents: []string{"main"}, parts: []string{"api"}, agent: "main", container: "api"},
+		{name: "AgentContainer", agents: []string{"main", "other"}, parts: []string{"other", "api"}, agent: "other", container: "api"},
+		{name: "AmbiguousContainer", agents: []string{"main", "other"}, parts: []string{"api"}, err: "agent not found"},
+		{name: "UnknownAgent", agents: []string{"main"}, parts: []string{"other", "api"}, err: "agent not found"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			agent, container, err := getWorkspaceAgentTarget(workspace(tc.agents...), tc.parts)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.agent, agent.Name)
+			require.Equal(t, tc.container, container)
+		})
+	}
+}
+
 func TestCloserStack_Mainline(t *testing.T) {
 	t.Parallel()
 	ctx := testutil.Context(t, testutil.WaitShort)
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/cli/cliui"
@@ -100,6 +101,39 @@ func (r *RootCmd) start() *serpent.Command {
 }
 
 func buildWorkspaceStartRequest(inv *serpent.Invocation, client *codersdk.Client, workspace codersdk.Workspace, parameterFlags workspaceParameterFlags, buildFlags buildFlags, action WorkspaceCLIAction) (codersdk.CreateWorkspaceBuildRequest, error) {
+	args, err := workspaceStartBuildArgs(inv, client, workspace, parameterFlags, action)
+	if err != nil {
+		return codersdk.CreateWorkspaceBuildRequest{}, err
+	}
+
+	buildParameters, err := prepWorkspaceBuild(inv, client, args)
+	if err != nil {
+		return codersdk.CreateWorkspaceBuildRequest{}, err
+	}
+
+	wbr := codersdk.CreateWorkspaceBuildRequest{
+		Transition:          codersdk.WorkspaceTransitionStart,
+		RichParameterValues: buildParameters,
+		TemplateVersionID:   args.TemplateVersionID,
+	}
+	// Updates request the active version rather than its ID, so the workspace
+	// builds the candidate version if it's included in the rollout in progress
+	// of the template.
+	if args.TemplateVersionID == workspace.TemplateActiveVersionID &&
+		(workspace.AutomaticUpdates == codersdk.AutomaticUpdatesAlways || action == WorkspaceUpdate) {
+		wbr.TemplateVersionID = uuid.Nil
+		wbr.ActiveVersion = true
+	}
+	if buildFlags.provisionerLogDebug {
+		wbr.LogLevel = codersdk.ProvisionerLogLevelDebug
+	}
+
+	return wbr, nil
+}
+
+// workspaceStartBuildArgs determines the template version and parameters to
+// start an existing workspace with.
+func workspaceStartBuildArgs(inv *serpent.Invocation, client *codersdk.Client, workspace codersdk.Workspace, parameterFlags workspaceParameterFlags, action WorkspaceCLIAction) (prepWorkspaceBuildArgs, error) {
 	version := workspace.LatestBuild.TemplateVersionID
 
 	if workspace.AutomaticUpdates == codersdk.AutomaticUpdatesAlways || action == WorkspaceUpdate {
@@ -111,25 +145,25 @@ func buildWorkspaceStartRequest(inv *serpent.Invocation, client *codersdk.Client
 
 	lastBuildParameters, err := client.WorkspaceBuildParameters(inv.Context(), workspace.LatestBuild.ID)
 	if err != nil {
-		return codersdk.CreateWorkspaceBuildRequest{}, err
+		return prepWorkspaceBuildArgs{}, err
 	}
 
 	ephemeralParameters, err := asWorkspaceBuildParameters(parameterFlags.ephemeralParameters)
 	if err != nil {
-		return codersdk.CreateWorkspaceBuildRequest{}, xerrors.Errorf("unable to parse build options: %w", err)
+		return prepWorkspaceBuildArgs{}, xerrors.Errorf("unable to parse build options: %w", err)
 	}
 
 	cliRichParameters, err := asWorkspaceBuildParameters(parameterFlags.richParameters)
 	if err != nil {
-		return codersdk.CreateWorkspaceBuildRequest{}, xerrors.Errorf("unable to parse rich parameters: %w", err)
+		return prepWorkspaceBuildArgs{}, xerrors.Errorf("unable to parse rich parameters: %w", err)
 	}
 
 	cliRichParameterDefaults, err := asWorkspaceBuildParameters(parameterFlags.richParameterDefaults)
 	if err != nil {
-		return codersdk.CreateWorkspaceBuildRequest{}, xerrors.Errorf("unable to parse rich parameter defaults: %w", err)
+		return prepWorkspaceBuildArgs{}, xerrors.Errorf("unable to parse rich parameter defaults: %w", err)
 	}
 
-	buildParameters, err := prepWorkspaceBuild(inv, client, prepWorkspaceBuildArgs{
+	return prepWorkspaceBuildArgs{
 		Action:              action,
 		TemplateVersionID:   version,
 		NewWorkspaceName:    workspace.Name,
@@ -141,21 +175,7 @@ func buildWorkspaceStartRequest(inv *serpent.Invocation, client *codersdk.Client
 		RichParameters:            cliRichParameters,
 		RichParameterFile:         parameterFlags.richParameterFile,
 		RichParameterDefaults:     cliRichParameterDefaults,
-	})
-	if err != nil {
-		return codersdk.CreateWorkspaceBuildRequest{}, err
-	}
-
-	wbr := codersdk.CreateWorkspaceBuildRequest{
-		Transition:          codersdk.WorkspaceTransitionStart,
-		RichParameterValues: buildParameters,
-		TemplateVersionID:   version,
-	}
-	if buildFlags.provisionerLogDebug {
-		wbr.LogLevel = codersdk.ProvisionerLogLevelDebug
-	}
-
-	return wbr, nil
+	}, nil
 }
 
 func startWorkspace(inv *serpent.Invocation, client *codersdk.Client, workspace codersdk.Workspace, parameterFlags workspaceParameterFlags, buildFlags buildFlags, action WorkspaceCLIAction) (codersdk.WorkspaceBuild, error) {
@@ -5,12 +5,14 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 
 	"github.com/coder/pretty"
 	"github.com/coder/serpent"
 
 	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/codersdk"
 )
 
@@ -35,6 +37,11 @@ func (r *RootCmd) templateEdit() *serpent.Command {
 		requireActiveVersion           bool
 		deprecationMessage             string
 		disableEveryone                bool
+		sessionRecording               bool
+		requireBuildApproval           bool
+		buildApprovalParameters        []string
+		buildApprovalGroup             string
+		buildApprovalTimeout           time.Duration
 		orgContext                     = NewOrganizationContext()
 	)
 	client := new(codersdk.Client)
@@ -166,6 +173,39 @@ func (r *RootCmd) templateEdit() *serpent.Command {
 				disableEveryoneGroup = disableEveryone
 			}
 
+			var sessionRecordingEnabled *bool
+			if userSetOption(inv, "session-recording") {
+				sessionRecordingEnabled = &sessionRecording
+			}
+
+			var requireBuildApprovalPtr *bool
+			if userSetOption(inv, "require-build-approval") {
+				requireBuildApprovalPtr = &requireBuildApproval
+			}
+
+			var buildApprovalParametersPtr *[]string
+			if userSetOption(inv, "build-approval-parameters") {
+				buildApprovalParametersPtr = &buildApprovalParameters
+			}
+
+			var buildApprovalGroupID *uuid.UUID
+			if userSetOption(inv, "build-approval-group") {
+				groupID := uuid.Nil
+				if buildApprovalGroup != "" {
+					group, err := client.GroupByOrgAndName(inv.Context(), organization.ID, buildApprovalGroup)
+					if err != nil {
+						return xerrors.Errorf("get build approval group %q: %w", buildApprovalGroup, err)
+					}
+					groupID = group.ID
+				}
+				buildApprovalGroupID = &groupID
+			}
+
+			var buildApprovalTimeoutMillis *int64
+			if userSetOption(inv, "build-approval-timeout") {
+				buildApprovalTimeoutMillis = ptr.Ref(buildApprovalTimeout.Milliseconds())
+			}
+
 			req := codersdk.UpdateTemplateMeta{
 				Name:               name,
 				DisplayName:        displayName,
@@ -189,6 +229,11 @@ func (r *RootCmd) templateEdit() *serpent.Command {
 				RequireActiveVersion:           requireActiveVersion,
 				DeprecationMessage:             deprecated,
 				DisableEveryoneGroupAccess:     disableEveryoneGroup,
+				SessionRecordingEnabled:        sessionRecordingEnabled,
+				RequireBuildApproval:           requireBuildApprovalPtr,
+				BuildApprovalParameters:        buildApprovalParametersPtr,
+				BuildApprovalGroupID:           buildApprovalGroupID,
+				BuildApprovalTimeoutMillis:     buildApprovalTimeoutMillis,
 			}
 
 			_, err = client.UpdateTemplateMeta(inv.Context(), template.ID, req)
@@ -301,6 +346,35 @@ func (r *RootCmd) templateEdit() *serpent.Command {
 			Value:   serpent.BoolOf(&disableEveryone),
 			Default: "false",
 		},
+		{
+			Flag: "session-recording",
+			Description: "Record interactive SSH and web terminal sessions in workspaces created from this template. " +
+				"Recordings can be played back by users with access to the audit log.",
+			Value:   serpent.BoolOf(&sessionRecording),
+			Default: "false",
+		},
+		{
+			Flag:        "require-build-approval",
+			Description: "Require workspace start builds to be approved by a template admin or a member of the build approval group before they run.",
+			Value:       serpent.BoolOf(&requireBuildApproval),
+			Default:     "false",
+		},
+		{
+			Flag:        "build-approval-parameters",
+			Description: "Only require approval for builds that change the value of one of these parameters. If empty, all start builds require approval.",
+			Value:       serpent.StringArrayOf(&buildApprovalParameters),
+		},
+		{
+			Flag:        "build-approval-group",
+			Description: "The name of the group whose members can approve builds, in addition to template admins. Pass an empty string to only allow template admins to approve builds.",
+			Value:       serpent.StringOf(&buildApprovalGroup),
+		},
+		{
+			Flag:        "build-approval-timeout",
+			Description: "Reject builds that are not approved within this duration. 0 means builds never time out.",
+			Value:       serpent.DurationOf(&buildApprovalTimeout),
+			Default:     "24h",
+		},
 		cliui.SkipPromptOption(),
 	}
 	orgContext.AttachOptions(cmd)
@@ -0,0 +1,379 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/pretty"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) templateVersionsRollout() *serpent.Command {
+	cmd := &serpent.Command{
+		Use:   "rollout",
+		Short: "Roll out a template version to a share of workspaces before promoting it.",
+		Long: "While a rollout is in progress, workspaces included in it build the candidate version instead of the " +
+			"active version when they are created, updated or auto-updated. Once the candidate completed enough " +
+			"builds, the rollout is rolled back if too many of them failed, or promoted otherwise.\n\n" +
+			FormatExamples(
+				Example{
+					Description: "Roll out a version to 10% of workspaces and the members of the beta group, promoting it after 20 builds with at most 5% failures",
+					Command:     "coder templates versions rollout start my-template v2 --percent 10 --group beta --min-builds 20 --max-failure-rate 0.05 --auto-promote",
+				},
+				Example{
+					Description: "Widen the rollout in progress to half of the workspaces",
+					Command:     "coder templates versions rollout update my-template --percent 50",
+				},
+			),
+		Handler: func(inv *serpent.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*serpent.Command{
+			r.templateVersionsRolloutStart(),
+			r.templateVersionsRolloutList(),
+			r.templateVersionsRolloutUpdate(),
+			r.finishTemplateVersionsRollout(codersdk.TemplateRolloutStatusPromoted),
+			r.finishTemplateVersionsRollout(codersdk.TemplateRolloutStatusRolledBack),
+			r.finishTemplateVersionsRollout(codersdk.TemplateRolloutStatusCanceled),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) templateVersionsRolloutStart() *serpent.Command {
+	var (
+		percent        int64
+		groupName      string
+		minBuilds      int64
+		maxFailureRate float64
+		autoPromote    bool
+		orgContext     = NewOrganizationContext()
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "start <template> <version>",
+		Short: "Start a rollout of a template version.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Options: serpent.OptionSet{
+			{
+				Flag:        "percent",
+				Description: "The percentage of workspaces that build the version.",
+				Default:     "0",
+				Value:       serpent.Int64Of(&percent),
+			},
+			{
+				Flag:        "group",
+				Description: "The name of a group whose members' workspaces build the version regardless of --percent.",
+				Value:       serpent.StringOf(&groupName),
+			},
+			{
+				Flag:        "min-builds",
+				Description: "The number of completed builds of the version required before the rollout is promoted or rolled back automatically.",
+				Default:     "10",
+				Value:       serpent.Int64Of(&minBuilds),
+			},
+			{
+				Flag:        "max-failure-rate",
+				Description: "The share of failed builds, between 0 and 1, above which the rollout is rolled back.",
+				Default:     "0.1",
+				Value:       serpent.Float64Of(&maxFailureRate),
+			},
+			{
+				Flag:        "auto-promote",
+				Description: "Promote the version to active once enough builds completed within --max-failure-rate. Otherwise the rollout must be promoted by hand.",
+				Value:       serpent.BoolOf(&autoPromote),
+			},
+		},
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			organization, err := orgContext.Selected(inv, client)
+			if err != nil {
+				return err
+			}
+			template, err := client.TemplateByName(ctx, organization.ID, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get template by name: %w", err)
+			}
+			version, err := client.TemplateVersionByName(ctx, template.ID, inv.Args[1])
+			if err != nil {
+				return xerrors.Errorf("get template version by name: %w", err)
+			}
+			req := codersdk.CreateTemplateRolloutRequest{
+				TemplateVersionID: version.ID,
+				Percent:           int32(percent),
+				MinBuilds:         int32(minBuilds),
+				MaxFailureRate:    maxFailureRate,
+				AutoPromote:       autoPromote,
+			}
+			if groupName != "" {
+				group, err := client.GroupByOrgAndName(ctx, organization.ID, groupName)
+				if err != nil {
+					return xerrors.Errorf("get group by name: %w", err)
+				}
+				req.GroupID = &group.ID
+			}
+
+			rollout, err := client.CreateTemplateRollout(ctx, template.ID, req)
+			if err != nil {
+				return xerrors.Errorf("create template rollout: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Started rollout %s of version %s for template %s at %s\n",
+				rollout.ID,
+				pretty.Sprint(cliui.DefaultStyles.Keyword, version.Name),
+				pretty.Sprint(cliui.DefaultStyles.Keyword, template.Name),
+				cliui.Timestamp(rollout.CreatedAt),
+			)
+			return nil
+		},
+	}
+	orgContext.AttachOptions(cmd)
+	return cmd
+}
+
+func (r *RootCmd) templateVersionsRolloutList() *serpent.Command {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]templateRolloutRow{}, []string{"version", "status", "percent", "builds", "failed builds", "created at", "reason"}),
+		cliui.JSONFormat(),
+	)
+	orgContext := NewOrganizationContext()
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "list <template>",
+		Short: "List the rollouts of a template.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			organization, err := orgContext.Selected(inv, client)
+			if err != nil {
+				return err
+			}
+			template, err := client.TemplateByName(ctx, organization.ID, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get template by name: %w", err)
+			}
+			rollouts, err := client.TemplateRollouts(ctx, template.ID)
+			if err != nil {
+				return xerrors.Errorf("get template rollouts: %w", err)
+			}
+			versions, err := client.TemplateVersionsByTemplate(ctx, codersdk.TemplateVersionsByTemplateRequest{
+				TemplateID:      template.ID,
+				IncludeArchived: true,
+			})
+			if err != nil {
+				return xerrors.Errorf("get template versions by template: %w", err)
+			}
+			versionNames := make(map[uuid.UUID]string, len(versions))
+			for _, version := range versions {
+				versionNames[version.ID] = version.Name
+			}
+
+			rows := make([]templateRolloutRow, 0, len(rollouts))
+			for _, rollout := range rollouts {
+				rows = append(rows, templateRolloutRow{
+					TemplateRollout: rollout,
+					Version:         versionNames[rollout.TemplateVersionID],
+					Status:          strings.ReplaceAll(string(rollout.Status), "_", " "),
+					Percent:         fmt.Sprintf("%d%%", rollout.Percent),
+					Builds:          rollout.Builds,
+					FailedBuilds:    rollout.FailedBuilds,
+					CreatedAt:       rollout.CreatedAt,
+					Reason:          rollout.StatusReason,
+				})
+			}
+			out, err := formatter.Format(ctx, rows)
+			if err != nil {
+				return xerrors.Errorf("render table: %w", err)
+			}
+			if out == "" {
+				cliui.Infof(inv.Stderr, "No rollouts found for template %s.", template.Name)
+				return nil
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	orgContext.AttachOptions(cmd)
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+type templateRolloutRow struct {
+	// For json format:
+	TemplateRollout codersdk.TemplateRollout `table:"-"`
+
+	// For table format:
+	Version      string    `json:"-" table:"version"`
+	Status       string    `json:"-" table:"status"`
+	Percent      string    `json:"-" table:"percent"`
+	Builds       int64     `json:"-" table:"builds"`
+	FailedBuilds int64     `json:"-" table:"failed builds"`
+	CreatedAt    time.Time `json:"-" table:"created at,default_sort"`
+	Reason       string    `json:"-" table:"reason"`
+}
+
+func (r *RootCmd) templateVersionsRolloutUpdate() *serpent.Command {
+	var (
+		percent    int64
+		groupName  string
+		orgContext = NewOrganizationContext()
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "update <template>",
+		Short: "Change the workspaces included in the rollout in progress of a template.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Options: serpent.OptionSet{
+			{
+				Flag:        "percent",
+				Description: "The percentage of workspaces that build the candidate version.",
+				Value:       serpent.Int64Of(&percent),
+			},
+			{
+				Flag:        "group",
+				Description: `The name of a group whose members' workspaces build the candidate version. Use "none" to remove the group of the rollout.`,
+				Value:       serpent.StringOf(&groupName),
+			},
+		},
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			organization, err := orgContext.Selected(inv, client)
+			if err != nil {
+				return err
+			}
+			template, rollout, err := activeTemplateRollout(inv, client, organization.ID)
+			if err != nil {
+				return err
+			}
+
+			var req codersdk.UpdateTemplateRolloutRequest
+			if inv.ParsedFlags().Changed("percent") {
+				p := int32(percent)
+				req.Percent = &p
+			}
+			switch groupName {
+			case "":
+			case "none":
+				req.GroupID = &uuid.Nil
+			default:
+				group, err := client.GroupByOrgAndName(ctx, organization.ID, groupName)
+				if err != nil {
+					return xerrors.Errorf("get group by name: %w", err)
+				}
+				req.GroupID = &group.ID
+			}
+			if req.Percent == nil && req.GroupID == nil {
+				return xerrors.New("nothing to update: specify --percent or --group")
+			}
+
+			rollout, err = client.UpdateTemplateRollout(ctx, template.ID, rollout.ID, req)
+			if err != nil {
+				return xerrors.Errorf("update template rollout: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Updated rollout %s of template %s at %s\n",
+				rollout.ID,
+				pretty.Sprint(cliui.DefaultStyles.Keyword, template.Name),
+				cliui.Timestamp(rollout.UpdatedAt),
+			)
+			return nil
+		},
+	}
+	orgContext.AttachOptions(cmd)
+	return cmd
+}
+
+func (r *RootCmd) finishTemplateVersionsRollout(status codersdk.TemplateRolloutStatus) *serpent.Command {
+	var (
+		use   string
+		short string
+		past  string
+	)
+	switch status {
+	case codersdk.TemplateRolloutStatusPromoted:
+		use, short, past = "promote", "Promote the candidate version of the rollout in progress of a template to active.", "Promoted"
+	case codersdk.TemplateRolloutStatusRolledBack:
+		use, short, past = "rollback", "Roll back the rollout in progress of a template.", "Rolled back"
+	default:
+		use, short, past = "cancel", "Cancel the rollout in progress of a template without promoting or rolling it back.", "Canceled"
+	}
+
+	var (
+		reason     string
+		orgContext = NewOrganizationContext()
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   use + " <template>",
+		Short: short,
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Options: serpent.OptionSet{
+			{
+				Flag:        "reason",
+				Description: "The reason recorded on the rollout and sent to template admins.",
+				Value:       serpent.StringOf(&reason),
+			},
+		},
+		Handler: func(inv *serpent.Invocation) error {
+			organization, err := orgContext.Selected(inv, client)
+			if err != nil {
+				return err
+			}
+			template, rollout, err := activeTemplateRollout(inv, client, organization.ID)
+			if err != nil {
+				return err
+			}
+			rollout, err = client.UpdateTemplateRollout(inv.Context(), template.ID, rollout.ID, codersdk.UpdateTemplateRolloutRequest{
+				Status: &status,
+				Reason: reason,
+			})
+			if err != nil {
+				return xerrors.Errorf("update template rollout: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "%s rollout %s of template %s at %s\n",
+				past,
+				rollout.ID,
+				pretty.Sprint(cliui.DefaultStyles.Keyword, template.Name),
+				cliui.Timestamp(rollout.UpdatedAt),
+			)
+			return nil
+		},
+	}
+	orgContext.AttachOptions(cmd)
+	return cmd
+}
+
+// activeTemplateRollout returns the template named by the first argument and
+// its rollout in progress.
+func activeTemplateRollout(inv *serpent.Invocation, client *codersdk.Client, organizationID uuid.UUID) (codersdk.Template, codersdk.TemplateRollout, error) {
+	template, err := client.TemplateByName(inv.Context(), organizationID, inv.Args[0])
+	if err != nil {
+		return codersdk.Template{}, codersdk.TemplateRollout{}, xerrors.Errorf("get template by name: %w", err)
+	}
+	rollouts, err := client.TemplateRollouts(inv.Context(), template.ID)
+	if err != nil {
+		return codersdk.Template{}, codersdk.TemplateRollout{}, xerrors.Errorf("get template rollouts: %w", err)
+	}
+	for _, rollout := range rollouts {
+		if rollout.Status == codersdk.TemplateRolloutStatusInProgress {
+			return template, rollout, nil
+		}
+	}
+	return codersdk.Template{}, codersdk.TemplateRollout{}, xerrors.Errorf("template %q has no rollout in progress", template.Name)
+}
@@ -0,0 +1,79 @@
+package cli_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/pty/ptytest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateVersionsRollout(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	templateAdmin, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleTemplateAdmin())
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	candidate := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil, func(ctvr *codersdk.CreateTemplateVersionRequest) {
+		ctvr.TemplateID = template.ID
+		ctvr.Name = "2.0.0"
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, candidate.ID)
+
+	run := func(args ...string) *ptytest.PTY {
+		t.Helper()
+		inv, root := clitest.New(t, append([]string{"templates", "versions", "rollout"}, args...)...)
+		clitest.SetupConfig(t, templateAdmin, root)
+		pty := ptytest.New(t).Attach(inv)
+		require.NoError(t, inv.Run())
+		return pty
+	}
+
+	pty := run("start", template.Name, candidate.Name, "--percent", "10", "--min-builds", "5", "--max-failure-rate", "0.2")
+	pty.ExpectMatch("Started rollout")
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	rollouts, err := client.TemplateRollouts(ctx, template.ID)
+	require.NoError(t, err)
+	require.Len(t, rollouts, 1)
+	require.Equal(t, candidate.ID, rollouts[0].TemplateVersionID)
+	require.EqualValues(t, 10, rollouts[0].Percent)
+	require.EqualValues(t, 5, rollouts[0].MinBuilds)
+	require.InDelta(t, 0.2, rollouts[0].MaxFailureRate, 0.0001)
+	require.False(t, rollouts[0].AutoPromote)
+
+	pty = run("update", template.Name, "--percent", "50")
+	pty.ExpectMatch("Updated rollout")
+	rollout, err := client.TemplateRollout(ctx, template.ID, rollouts[0].ID)
+	require.NoError(t, err)
+	require.EqualValues(t, 50, rollout.Percent)
+
+	pty = run("list", template.Name)
+	pty.ExpectMatch(candidate.Name)
+	pty.ExpectMatch("in progress")
+	pty.ExpectMatch("50%")
+
+	pty = run("promote", template.Name, "--reason", "No failures so far.")
+	pty.ExpectMatch("Promoted rollout")
+	rollout, err = client.TemplateRollout(ctx, template.ID, rollout.ID)
+	require.NoError(t, err)
+	require.Equal(t, codersdk.TemplateRolloutStatusPromoted, rollout.Status)
+	require.Equal(t, "No failures so far.", rollout.StatusReason)
+	updated, err := client.Template(ctx, template.ID)
+	require.NoError(t, err)
+	require.Equal(t, candidate.ID, updated.ActiveVersionID)
+
+	// There's no rollout in progress anymore.
+	inv, root := clitest.New(t, "templates", "versions", "rollout", "cancel", template.Name)
+	clitest.SetupConfig(t, templateAdmin, root)
+	err = inv.Run()
+	require.ErrorContains(t, err, "has no rollout in progress")
+}
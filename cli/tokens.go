@@ -1,10 +1,13 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
@@ -22,6 +25,10 @@ func (r *RootCmd) tokens() *serpent.Command {
 				Description: "Create a token for automation",
 				Command:     "coder tokens create",
 			},
+			Example{
+				Description: "Create a token that can only push templates in one organization",
+				Command:     "coder tokens create --scope template:read@my-org --scope template:create@my-org --scope template:update@my-org --scope file:create --scope file:read",
+			},
 			Example{
 				Description: "List your tokens",
 				Command:     "coder tokens ls",
@@ -49,6 +56,8 @@ func (r *RootCmd) createToken() *serpent.Command {
 		tokenLifetime string
 		name          string
 		user          string
+		scopes        []string
+		resourceIDs   []string
 	)
 	client := new(codersdk.Client)
 	cmd := &serpent.Command{
@@ -85,9 +94,20 @@ func (r *RootCmd) createToken() *serpent.Command {
 				}
 			}
 
+			scope, scopePermissions, err := parseTokenScopes(inv.Context(), client, scopes)
+			if err != nil {
+				return err
+			}
+			if len(resourceIDs) > 0 && scope != codersdk.APIKeyScopeCustom {
+				return xerrors.New("--resource-id requires scopes in the form <resource>:<action>")
+			}
+
 			res, err := client.CreateToken(inv.Context(), userID, codersdk.CreateTokenRequest{
-				Lifetime:  parsedLifetime,
-				TokenName: name,
+				Lifetime:         parsedLifetime,
+				TokenName:        name,
+				Scope:            scope,
+				ScopePermissions: scopePermissions,
+				ScopeAllowList:   resourceIDs,
 			})
 			if err != nil {
 				return xerrors.Errorf("create tokens: %w", err)
@@ -120,11 +140,72 @@ func (r *RootCmd) createToken() *serpent.Command {
 			Description:   "Specify the user to create the token for (Only works if logged in user is admin).",
 			Value:         serpent.StringOf(&user),
 		},
+		{
+			Flag: "scope",
+			Env:  "CODER_TOKEN_SCOPE",
+			Description: "Limit what the token can do. Either \"all\", \"application_connect\", or one or more permissions in the form " +
+				"<resource>:<action>, e.g. workspace:read. Append @<organization> to limit a permission to one organization. " +
+				"The token can never do more than the user it belongs to.",
+			Value: serpent.StringArrayOf(&scopes),
+		},
+		{
+			Flag: "resource-id",
+			Env:  "CODER_TOKEN_RESOURCE_IDS",
+			Description: "Limit a token with permission scopes to the resources with these IDs, e.g. a workspace or template ID. " +
+				"An ID only matches the resource itself, a template ID does not match the workspaces of the template.",
+			Value: serpent.StringArrayOf(&resourceIDs),
+		},
 	}
 
 	return cmd
 }
 
+// parseTokenScopes parses the values of the --scope flag. The values are
+// either a single builtin scope, or permissions for the custom scope.
+func parseTokenScopes(ctx context.Context, client *codersdk.Client, scopes []string) (codersdk.APIKeyScope, []codersdk.APIKeyScopePermission, error) {
+	if len(scopes) == 0 {
+		return "", nil, nil
+	}
+	if len(scopes) == 1 {
+		switch codersdk.APIKeyScope(scopes[0]) {
+		case codersdk.APIKeyScopeAll, codersdk.APIKeyScopeApplicationConnect:
+			return codersdk.APIKeyScope(scopes[0]), nil, nil
+		}
+	}
+
+	orgIDs := map[string]uuid.UUID{}
+	permissions := make([]codersdk.APIKeyScopePermission, 0, len(scopes))
+	for _, scope := range scopes {
+		switch codersdk.APIKeyScope(scope) {
+		case codersdk.APIKeyScopeAll, codersdk.APIKeyScopeApplicationConnect:
+			return "", nil, xerrors.Errorf("scope %q cannot be combined with other scopes", scope)
+		}
+		perm, orgName, _ := strings.Cut(scope, "@")
+		resource, action, ok := strings.Cut(perm, ":")
+		if !ok || resource == "" || action == "" {
+			return "", nil, xerrors.Errorf("invalid scope %q: must be in the form <resource>:<action>[@<organization>]", scope)
+		}
+		permission := codersdk.APIKeyScopePermission{
+			ResourceType: codersdk.RBACResource(resource),
+			Action:       codersdk.RBACAction(action),
+		}
+		if orgName != "" {
+			orgID, ok := orgIDs[orgName]
+			if !ok {
+				org, err := client.OrganizationByName(ctx, orgName)
+				if err != nil {
+					return "", nil, xerrors.Errorf("get organization %q: %w", orgName, err)
+				}
+				orgID = org.ID
+				orgIDs[orgName] = orgID
+			}
+			permission.OrganizationID = orgID
+		}
+		permissions = append(permissions, permission)
+	}
+	return codersdk.APIKeyScopeCustom, permissions, nil
+}
+
 // tokenListRow is the type provided to the OutputFormatter.
 type tokenListRow struct {
 	// For JSON format:
@@ -133,6 +214,7 @@ type tokenListRow struct {
 	// For table format:
 	ID        string    `json:"-" table:"id,default_sort"`
 	TokenName string    `json:"token_name" table:"name"`
+	Scope     string    `json:"-" table:"scope"`
 	LastUsed  time.Time `json:"-" table:"last used"`
 	ExpiresAt time.Time `json:"-" table:"expires at"`
 	CreatedAt time.Time `json:"-" table:"created at"`
@@ -144,6 +226,7 @@ func tokenListRowFromToken(token codersdk.APIKeyWithOwner) tokenListRow {
 		APIKey:    token.APIKey,
 		ID:        token.ID,
 		TokenName: token.TokenName,
+		Scope:     tokenScope(token.APIKey),
 		LastUsed:  token.LastUsed,
 		ExpiresAt: token.ExpiresAt,
 		CreatedAt: token.CreatedAt,
@@ -151,6 +234,22 @@ func tokenListRowFromToken(token codersdk.APIKeyWithOwner) tokenListRow {
 	}
 }
 
+// tokenScope formats the scope of a token in the syntax of the --scope flag.
+func tokenScope(token codersdk.APIKey) string {
+	if token.Scope != codersdk.APIKeyScopeCustom {
+		return string(token.Scope)
+	}
+	perms := make([]string, 0, len(token.ScopePermissions))
+	for _, perm := range token.ScopePermissions {
+		s := string(perm.ResourceType) + ":" + string(perm.Action)
+		if perm.OrganizationID != uuid.Nil {
+			s += "@" + perm.OrganizationID.String()
+		}
+		perms = append(perms, s)
+	}
+	return strings.Join(perms, ",")
+}
+
 func (r *RootCmd) listTokens() *serpent.Command {
 	// we only display the 'owner' column if the --all argument is passed in
 	defaultCols := []string{"id", "name", "last used", "expires at", "created at"}
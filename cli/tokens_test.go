@@ -4,13 +4,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/cli/clitest"
 	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
 	"github.com/coder/coder/v2/testutil"
 )
 
@@ -123,3 +126,61 @@ func TestTokens(t *testing.T) {
 	require.NotEmpty(t, res)
 	require.Contains(t, res, "deleted")
 }
+
+func TestTokensScope(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	_ = coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	_, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	org, err := client.Organization(ctx, owner.OrganizationID)
+	require.NoError(t, err)
+
+	// Create a token that can push templates, like a CI pipeline would.
+	inv, root := clitest.New(t, "tokens", "create", "--name", "ci",
+		"--scope", "template:read@"+org.Name,
+		"--scope", "template:create@"+org.Name,
+		"--scope", "template:update@"+org.Name,
+		"--scope", "file:create",
+		"--scope", "file:read",
+	)
+	//nolint:gocritic // The owner creates a token for themselves.
+	clitest.SetupConfig(t, client, root)
+	buf := new(bytes.Buffer)
+	inv.Stdout = buf
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	token := strings.TrimSpace(buf.String())
+
+	scopedClient := codersdk.New(client.URL)
+	scopedClient.SetSessionToken(token)
+
+	source := clitest.CreateTemplateVersionSource(t, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionApply: echo.ApplyComplete,
+	})
+	inv, root = clitest.New(t, "templates", "push", template.Name, "--directory", source, "--test.provisioner", string(database.ProvisionerTypeEcho), "--yes")
+	clitest.SetupConfig(t, scopedClient, root)
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+
+	// The token cannot be used for anything else, even though it belongs to
+	// the owner.
+	err = scopedClient.DeleteUser(ctx, member.ID)
+	require.Error(t, err)
+	_, err = client.User(ctx, member.ID.String())
+	require.NoError(t, err)
+
+	inv, root = clitest.New(t, "tokens", "ls", "-c", "name,scope")
+	clitest.SetupConfig(t, client, root)
+	buf = new(bytes.Buffer)
+	inv.Stdout = buf
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "template:read@"+org.ID.String())
+}
@@ -13,13 +13,15 @@ func (r *RootCmd) update() *serpent.Command {
 	var (
 		parameterFlags workspaceParameterFlags
 		bflags         buildFlags
+		preview        bool
 	)
 	client := new(codersdk.Client)
 	cmd := &serpent.Command{
 		Annotations: workspaceCommand,
 		Use:         "update <workspace>",
 		Short:       "Will update and start a given workspace if it is out of date",
-		Long:        "Use --always-prompt to change the parameter values of the workspace.",
+		Long: "Use --always-prompt to change the parameter values of the workspace. " +
+			"Use --preview to see what the update will change before applying it.",
 		Middleware: serpent.Chain(
 			serpent.RequireNArgs(1),
 			r.InitClient(client),
@@ -34,6 +36,19 @@ func (r *RootCmd) update() *serpent.Command {
 				return nil
 			}
 
+			if preview {
+				args, err := workspaceStartBuildArgs(inv, client, workspace, parameterFlags, WorkspaceUpdate)
+				if err != nil {
+					return err
+				}
+				args.PreviewWorkspaceID = workspace.ID
+				_, err = prepWorkspaceBuild(inv, client, args)
+				if err != nil {
+					return xerrors.Errorf("preview update: %w", err)
+				}
+				return nil
+			}
+
 			build, err := startWorkspace(inv, client, workspace, parameterFlags, bflags, WorkspaceUpdate)
 			if err != nil {
 				return xerrors.Errorf("start workspace: %w", err)
@@ -55,6 +70,11 @@ func (r *RootCmd) update() *serpent.Command {
 		},
 	}
 
+	cmd.Options = append(cmd.Options, serpent.Option{
+		Flag:        "preview",
+		Description: "Show the changes the update would make to the workspace's resources without applying them.",
+		Value:       serpent.BoolOf(&preview),
+	})
 	cmd.Options = append(cmd.Options, parameterFlags.allOptions()...)
 	cmd.Options = append(cmd.Options, bflags.cliOptions()...)
 	return cmd
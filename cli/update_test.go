@@ -78,6 +78,63 @@ func TestUpdate(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, version2.ID.String(), ws.LatestBuild.TemplateVersionID.String())
 	})
+
+	t.Run("Preview", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, memberUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version1 := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version1.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version1.ID)
+		ws := coderdtest.CreateWorkspace(t, member, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, ws.LatestBuild.ID)
+
+		version2 := coderdtest.UpdateTemplateVersion(t, client, owner.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionApply: echo.ApplyComplete,
+			ProvisionPlan: []*proto.Response{{
+				Type: &proto.Response_Plan{
+					Plan: &proto.PlanComplete{
+						ResourceChanges: []*proto.ResourceChange{{
+							Address: "docker_volume.home",
+							Type:    "docker_volume",
+							Name:    "home",
+							Action:  "replace",
+							Attributes: []*proto.AttributeChange{{
+								Path:              "name",
+								Before:            `"home"`,
+								After:             `"home-2"`,
+								ForcesReplacement: true,
+							}},
+						}},
+					},
+				},
+			}},
+		}, template.ID)
+		_ = coderdtest.AwaitTemplateVersionJobCompleted(t, client, version2.ID)
+		err := client.UpdateActiveTemplateVersion(context.Background(), template.ID, codersdk.UpdateActiveTemplateVersion{
+			ID: version2.ID,
+		})
+		require.NoError(t, err)
+
+		inv, root := clitest.New(t, "update", ws.Name, "--preview")
+		clitest.SetupConfig(t, member, root)
+		pty := ptytest.New(t).Attach(inv)
+		clitest.Start(t, inv)
+
+		pty.ExpectMatch("replace  docker_volume.home")
+		pty.ExpectMatch(`name: "home" -> "home-2"`)
+		pty.ExpectMatch("(forces replacement)")
+		pty.ExpectMatch("0 to create, 0 to update, 1 to replace, 0 to delete.")
+
+		// The workspace must not have been updated.
+		ctx := testutil.Context(t, testutil.WaitLong)
+		ws, err = member.WorkspaceByOwnerAndName(ctx, memberUser.Username, ws.Name, codersdk.WorkspaceOptions{})
+		require.NoError(t, err)
+		require.Equal(t, version1.ID, ws.LatestBuild.TemplateVersionID)
+	})
 }
 
 func TestUpdateWithRichParameters(t *testing.T) {
@@ -21,6 +21,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
 	"github.com/coder/coder/v2/coderd/externalauth"
+	"github.com/coder/coder/v2/coderd/jwtutils"
 	"github.com/coder/coder/v2/coderd/prometheusmetrics"
 	"github.com/coder/coder/v2/coderd/tracing"
 	"github.com/coder/coder/v2/coderd/workspacestats"
@@ -44,6 +45,8 @@ type API struct {
 	*MetadataAPI
 	*LogsAPI
 	*ScriptsAPI
+	*SessionRecordingAPI
+	*WorkloadIdentityAPI
 	*tailnet.DRPCService
 
 	mu sync.Mutex
@@ -67,6 +70,7 @@ type Options struct {
 	PublishWorkspaceUpdateFn          func(ctx context.Context, userID uuid.UUID, event wspubsub.WorkspaceEvent)
 	PublishWorkspaceAgentLogsUpdateFn func(ctx context.Context, workspaceAgentID uuid.UUID, msg agentsdk.LogsNotifyMessage)
 	NetworkTelemetryHandler           func(batch []*tailnetproto.TelemetryEvent)
+	StartupCompletedFn                func(ctx context.Context, agent database.WorkspaceAgent)
 
 	AccessURL                 *url.URL
 	AppHostname               string
@@ -76,6 +80,7 @@ type Options struct {
 	DerpMapUpdateFrequency    time.Duration
 	ExternalAuthConfigs       []*externalauth.Config
 	Experiments               codersdk.Experiments
+	WorkloadIdentityKeyCache  jwtutils.SigningKeyProvider
 
 	UpdateAgentMetricsFn func(ctx context.Context, labels prometheusmetrics.AgentMetricLabels, metrics []*agentproto.Stats_Metric)
 }
@@ -117,6 +122,7 @@ func New(opts Options) *API {
 		Database:                 opts.Database,
 		Log:                      opts.Log,
 		PublishWorkspaceUpdateFn: api.publishWorkspaceUpdate,
+		StartupCompletedFn:       opts.StartupCompletedFn,
 	}
 
 	api.AppsAPI = &AppsAPI{
@@ -145,6 +151,21 @@ func New(opts Options) *API {
 		Database: opts.Database,
 	}
 
+	api.SessionRecordingAPI = &SessionRecordingAPI{
+		AgentFn:     api.agent,
+		WorkspaceID: opts.WorkspaceID,
+		OwnerID:     opts.OwnerID,
+		Database:    opts.Database,
+	}
+
+	api.WorkloadIdentityAPI = &WorkloadIdentityAPI{
+		AgentFn:     api.agent,
+		WorkspaceID: opts.WorkspaceID,
+		Database:    opts.Database,
+		AccessURL:   opts.AccessURL,
+		KeyCache:    opts.WorkloadIdentityKeyCache,
+	}
+
 	api.DRPCService = &tailnet.DRPCService{
 		CoordPtr:                opts.TailnetCoordinator,
 		Logger:                  opts.Log,
@@ -30,6 +30,9 @@ type LifecycleAPI struct {
 	Database                 database.Store
 	Log                      slog.Logger
 	PublishWorkspaceUpdateFn func(context.Context, *database.WorkspaceAgent, wspubsub.WorkspaceEventKind) error
+	// StartupCompletedFn is called when the agent has finished starting,
+	// successfully or not. It is optional.
+	StartupCompletedFn func(context.Context, database.WorkspaceAgent)
 
 	TimeNowFn func() time.Time // defaults to dbtime.Now()
 }
@@ -125,6 +128,13 @@ func (a *LifecycleAPI) UpdateLifecycle(ctx context.Context, req *agentproto.Upda
 		}
 	}
 
+	if a.StartupCompletedFn != nil && readyAt.Valid && !workspaceAgent.ReadyAt.Valid {
+		workspaceAgent.LifecycleState = lifecycleState
+		workspaceAgent.StartedAt = startedAt
+		workspaceAgent.ReadyAt = readyAt
+		a.StartupCompletedFn(ctx, workspaceAgent)
+	}
+
 	return req.Lifecycle, nil
 }
 
@@ -77,6 +77,9 @@ func TestUpdateLifecycle(t *testing.T) {
 				publishCalled = true
 				return nil
 			},
+			StartupCompletedFn: func(context.Context, database.WorkspaceAgent) {
+				t.Error("startup should not be completed while starting")
+			},
 		}
 
 		resp, err := api.UpdateLifecycle(context.Background(), &agentproto.UpdateLifecycleRequest{
@@ -106,6 +109,7 @@ func TestUpdateLifecycle(t *testing.T) {
 			},
 		}).Return(nil)
 
+		var completed []database.WorkspaceAgent
 		api := &agentapi.LifecycleAPI{
 			AgentFn: func(ctx context.Context) (database.WorkspaceAgent, error) {
 				return agentStarting, nil
@@ -115,6 +119,9 @@ func TestUpdateLifecycle(t *testing.T) {
 			Log:         testutil.Logger(t),
 			// Test that nil publish fn works.
 			PublishWorkspaceUpdateFn: nil,
+			StartupCompletedFn: func(_ context.Context, agent database.WorkspaceAgent) {
+				completed = append(completed, agent)
+			},
 		}
 
 		resp, err := api.UpdateLifecycle(context.Background(), &agentproto.UpdateLifecycleRequest{
@@ -122,6 +129,9 @@ func TestUpdateLifecycle(t *testing.T) {
 		})
 		require.NoError(t, err)
 		require.Equal(t, lifecycle, resp)
+		require.Len(t, completed, 1)
+		require.Equal(t, database.WorkspaceAgentLifecycleStateReady, completed[0].LifecycleState)
+		require.Equal(t, sql.NullTime{Time: now, Valid: true}, completed[0].ReadyAt)
 	})
 
 	// This test jumps from CREATING to READY, skipping STARTED. Both the
@@ -4,10 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"net/url"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/exp/maps"
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/xerrors"
 	"google.golang.org/protobuf/types/known/durationpb"
@@ -47,6 +49,8 @@ func (a *ManifestAPI) GetManifest(ctx context.Context, _ *agentproto.GetManifest
 		metadata  []database.WorkspaceAgentMetadatum
 		workspace database.Workspace
 		owner     database.User
+		template  database.Template
+		secrets   []database.Secret
 	)
 
 	var eg errgroup.Group
@@ -78,6 +82,22 @@ func (a *ManifestAPI) GetManifest(ctx context.Context, _ *agentproto.GetManifest
 		if err != nil {
 			return xerrors.Errorf("getting workspace owner by id: %w", err)
 		}
+		// nolint:gocritic // The agent only needs the template's settings, not
+		// read access to the template itself.
+		template, err = a.Database.GetTemplateByID(dbauthz.AsSystemRestricted(ctx), workspace.TemplateID)
+		if err != nil {
+			return xerrors.Errorf("getting workspace template by id: %w", err)
+		}
+		// nolint:gocritic // The agent injects the secrets of the owner, the
+		// template and the organization into the workspace.
+		secrets, err = a.Database.GetSecretsForWorkspace(dbauthz.AsSystemRestricted(ctx), database.GetSecretsForWorkspaceParams{
+			OwnerID:        workspace.OwnerID,
+			TemplateID:     workspace.TemplateID,
+			OrganizationID: workspace.OrganizationID,
+		})
+		if err != nil {
+			return xerrors.Errorf("getting workspace secrets: %w", err)
+		}
 		return err
 	})
 	err = eg.Wait()
@@ -124,14 +144,60 @@ func (a *ManifestAPI) GetManifest(ctx context.Context, _ *agentproto.GetManifest
 		MotdPath:                 workspaceAgent.MOTDFile,
 		DisableDirectConnections: a.DisableDirectConnections,
 		DerpForceWebsockets:      a.DerpForceWebSockets,
+		SessionRecordingEnabled:  template.SessionRecordingEnabled,
 
 		DerpMap:  tailnet.DERPMapToProto(a.DerpMapFn()),
 		Scripts:  dbAgentScriptsToProto(scripts),
 		Apps:     apps,
 		Metadata: dbAgentMetadataToProtoDescription(metadata),
+		Secrets:  dbSecretsToProto(secrets),
 	}, nil
 }
 
+// dbSecretsToProto converts the secrets of a workspace to the secrets that
+// the agent injects. Template secrets override organization secrets, and
+// personal secrets override both, for the same environment variable or file.
+func dbSecretsToProto(secrets []database.Secret) []*agentproto.Secret {
+	rank := func(secret database.Secret) int {
+		switch {
+		case secret.UserID.Valid:
+			return 2
+		case secret.TemplateID.Valid:
+			return 1
+		default:
+			return 0
+		}
+	}
+	envs := map[string][]byte{}
+	files := map[string][]byte{}
+	for r := 0; r <= 2; r++ {
+		for _, secret := range secrets {
+			if rank(secret) != r {
+				continue
+			}
+			if secret.EnvName != "" {
+				envs[secret.EnvName] = []byte(secret.Value)
+			}
+			if secret.FilePath != "" {
+				files[secret.FilePath] = []byte(secret.Value)
+			}
+		}
+	}
+
+	envNames := maps.Keys(envs)
+	slices.Sort(envNames)
+	filePaths := maps.Keys(files)
+	slices.Sort(filePaths)
+	ret := make([]*agentproto.Secret, 0, len(envs)+len(files))
+	for _, name := range envNames {
+		ret = append(ret, &agentproto.Secret{EnvName: name, Value: envs[name]})
+	}
+	for _, path := range filePaths {
+		ret = append(ret, &agentproto.Secret{FilePath: path, Value: files[path]})
+	}
+	return ret
+}
+
 func vscodeProxyURI(app appurl.ApplicationURL, accessURL *url.URL, appHost string) string {
 	// Proxying by port only works for subdomains. If subdomain support is not
 	// available, return an empty string.
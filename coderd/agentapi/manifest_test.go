@@ -45,10 +45,16 @@ func TestGetManifest(t *testing.T) {
 			ID:       uuid.New(),
 			Username: "cool-user",
 		}
+		template = database.Template{
+			ID:                      uuid.New(),
+			SessionRecordingEnabled: true,
+		}
 		workspace = database.Workspace{
-			ID:      uuid.New(),
-			OwnerID: owner.ID,
-			Name:    "cool-workspace",
+			ID:             uuid.New(),
+			OwnerID:        owner.ID,
+			OrganizationID: uuid.New(),
+			TemplateID:     template.ID,
+			Name:           "cool-workspace",
 		}
 		agent = database.WorkspaceAgent{
 			ID:   uuid.New(),
@@ -267,6 +273,37 @@ func TestGetManifest(t *testing.T) {
 				Timeout:     durationpb.New(time.Duration(metadata[1].Timeout)),
 			},
 		}
+		secrets = []database.Secret{
+			{
+				ID:      uuid.New(),
+				Name:    "github-token",
+				Value:   "user",
+				EnvName: "GITHUB_TOKEN",
+				UserID:  uuid.NullUUID{UUID: owner.ID, Valid: true},
+			},
+			{
+				ID:             uuid.New(),
+				Name:           "github-token",
+				Value:          "organization",
+				EnvName:        "GITHUB_TOKEN",
+				FilePath:       "/etc/github-token",
+				OrganizationID: uuid.NullUUID{UUID: workspace.OrganizationID, Valid: true},
+			},
+			{
+				ID:         uuid.New(),
+				Name:       "npmrc",
+				Value:      "template",
+				FilePath:   "~/.npmrc",
+				TemplateID: uuid.NullUUID{UUID: template.ID, Valid: true},
+			},
+		}
+		// Personal secrets override organization secrets for the same
+		// environment variable.
+		protoSecrets = []*agentproto.Secret{
+			{EnvName: "GITHUB_TOKEN", Value: []byte("user")},
+			{FilePath: "/etc/github-token", Value: []byte("organization")},
+			{FilePath: "~/.npmrc", Value: []byte("template")},
+		}
 	)
 
 	t.Run("OK", func(t *testing.T) {
@@ -301,6 +338,12 @@ func TestGetManifest(t *testing.T) {
 		}).Return(metadata, nil)
 		mDB.EXPECT().GetWorkspaceByID(gomock.Any(), workspace.ID).Return(workspace, nil)
 		mDB.EXPECT().GetUserByID(gomock.Any(), workspace.OwnerID).Return(owner, nil)
+		mDB.EXPECT().GetTemplateByID(gomock.Any(), template.ID).Return(template, nil)
+		mDB.EXPECT().GetSecretsForWorkspace(gomock.Any(), database.GetSecretsForWorkspaceParams{
+			OwnerID:        owner.ID,
+			TemplateID:     template.ID,
+			OrganizationID: workspace.OrganizationID,
+		}).Return(secrets, nil)
 
 		got, err := api.GetManifest(context.Background(), &agentproto.GetManifestRequest{})
 		require.NoError(t, err)
@@ -318,6 +361,7 @@ func TestGetManifest(t *testing.T) {
 			MotdPath:                 agent.MOTDFile,
 			DisableDirectConnections: true,
 			DerpForceWebsockets:      true,
+			SessionRecordingEnabled:  true,
 			// tailnet.DERPMapToProto() is extensively tested elsewhere, so it's
 			// not necessary to manually recreate a big DERP map here like we
 			// did for apps and metadata.
@@ -325,6 +369,7 @@ func TestGetManifest(t *testing.T) {
 			Scripts:  protoScripts,
 			Apps:     protoApps,
 			Metadata: protoMetadata,
+			Secrets:  protoSecrets,
 		}
 
 		// Log got and expected with spew.
@@ -366,6 +411,12 @@ func TestGetManifest(t *testing.T) {
 		}).Return(metadata, nil)
 		mDB.EXPECT().GetWorkspaceByID(gomock.Any(), workspace.ID).Return(workspace, nil)
 		mDB.EXPECT().GetUserByID(gomock.Any(), workspace.OwnerID).Return(owner, nil)
+		mDB.EXPECT().GetTemplateByID(gomock.Any(), template.ID).Return(template, nil)
+		mDB.EXPECT().GetSecretsForWorkspace(gomock.Any(), database.GetSecretsForWorkspaceParams{
+			OwnerID:        owner.ID,
+			TemplateID:     template.ID,
+			OrganizationID: workspace.OrganizationID,
+		}).Return(secrets, nil)
 
 		got, err := api.GetManifest(context.Background(), &agentproto.GetManifestRequest{})
 		require.NoError(t, err)
@@ -383,6 +434,7 @@ func TestGetManifest(t *testing.T) {
 			MotdPath:                 agent.MOTDFile,
 			DisableDirectConnections: true,
 			DerpForceWebsockets:      true,
+			SessionRecordingEnabled:  true,
 			// tailnet.DERPMapToProto() is extensively tested elsewhere, so it's
 			// not necessary to manually recreate a big DERP map here like we
 			// did for apps and metadata.
@@ -390,6 +442,7 @@ func TestGetManifest(t *testing.T) {
 			Scripts:  protoScripts,
 			Apps:     protoApps,
 			Metadata: protoMetadata,
+			Secrets:  protoSecrets,
 		}
 
 		// Log got and expected with spew.
@@ -0,0 +1,133 @@
+package agentapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"storj.io/drpc/drpcerr"
+
+	agentproto "github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+)
+
+// maxSessionRecordingChunkSize is the largest chunk the agent may upload at
+// once. The agent flushes recordings in much smaller chunks, this only guards
+// against misbehaving agents.
+const maxSessionRecordingChunkSize = 1 << 20
+
+type SessionRecordingAPI struct {
+	AgentFn     func(context.Context) (database.WorkspaceAgent, error)
+	WorkspaceID uuid.UUID
+	OwnerID     uuid.UUID
+	Database    database.Store
+
+	TimeNowFn func() time.Time // defaults to dbtime.Now()
+}
+
+func (a *SessionRecordingAPI) now() time.Time {
+	if a.TimeNowFn != nil {
+		return a.TimeNowFn()
+	}
+	return dbtime.Now()
+}
+
+func (a *SessionRecordingAPI) UploadSessionRecording(ctx context.Context, req *agentproto.UploadSessionRecordingRequest) (*agentproto.UploadSessionRecordingResponse, error) {
+	if len(req.Data) > maxSessionRecordingChunkSize {
+		return nil, rejectSessionRecordingChunk(xerrors.Errorf("session recording chunk of %d bytes exceeds the limit of %d bytes", len(req.Data), maxSessionRecordingChunkSize))
+	}
+	recordingID, err := uuid.FromBytes(req.Id)
+	if err != nil {
+		return nil, rejectSessionRecordingChunk(xerrors.Errorf("parse session recording ID %q: %w", req.Id, err))
+	}
+
+	var recordingType database.WorkspaceSessionRecordingType
+	switch req.Type {
+	case agentproto.UploadSessionRecordingRequest_SSH:
+		recordingType = database.WorkspaceSessionRecordingTypeSsh
+	case agentproto.UploadSessionRecordingRequest_RECONNECTING_PTY:
+		recordingType = database.WorkspaceSessionRecordingTypeReconnectingPty
+	default:
+		return nil, rejectSessionRecordingChunk(xerrors.Errorf("unknown session recording type %q", req.Type))
+	}
+
+	workspaceAgent, err := a.AgentFn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := a.now()
+	err = a.Database.InTx(func(tx database.Store) error {
+		// Recordings are only readable by auditors, so the agent cannot look up
+		// its own recordings.
+		// nolint:gocritic // The agent only needs to know whether the recording exists.
+		recording, err := tx.GetWorkspaceSessionRecordingByID(dbauthz.AsSystemRestricted(ctx), recordingID)
+		if errors.Is(err, sql.ErrNoRows) {
+			recording, err = tx.InsertWorkspaceSessionRecording(ctx, database.InsertWorkspaceSessionRecordingParams{
+				ID:          recordingID,
+				CreatedAt:   now,
+				UpdatedAt:   now,
+				WorkspaceID: a.WorkspaceID,
+				AgentID:     workspaceAgent.ID,
+				// The agent does not know which user connected, so the
+				// recording is attributed to the owner of the workspace.
+				UserID: a.OwnerID,
+				Type:   recordingType,
+			})
+		}
+		if err != nil {
+			return xerrors.Errorf("get or insert session recording: %w", err)
+		}
+		if recording.AgentID != workspaceAgent.ID {
+			return rejectSessionRecordingChunk(xerrors.Errorf("session recording %s belongs to another agent", recordingID))
+		}
+
+		inserted, err := tx.InsertWorkspaceSessionRecordingChunk(ctx, database.InsertWorkspaceSessionRecordingChunkParams{
+			RecordingID: recordingID,
+			Sequence:    req.Sequence,
+			Data:        req.Data,
+		})
+		if err != nil {
+			return xerrors.Errorf("insert session recording chunk: %w", err)
+		}
+		if inserted == 0 {
+			// The agent retried a chunk that was stored, e.g. because the
+			// response was lost. Its size was already counted.
+			return nil
+		}
+		if recording.EndedAt.Valid {
+			return rejectSessionRecordingChunk(xerrors.Errorf("session recording %s has already ended", recordingID))
+		}
+
+		var endedAt sql.NullTime
+		if req.Final {
+			endedAt = sql.NullTime{Time: now, Valid: true}
+		}
+		err = tx.UpdateWorkspaceSessionRecordingByID(ctx, database.UpdateWorkspaceSessionRecordingByIDParams{
+			ID:        recordingID,
+			UpdatedAt: now,
+			SizeDelta: int64(len(req.Data)),
+			EndedAt:   endedAt,
+		})
+		if err != nil {
+			return xerrors.Errorf("update session recording: %w", err)
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &agentproto.UploadSessionRecordingResponse{}, nil
+}
+
+// rejectSessionRecordingChunk marks the error of a chunk that will never be
+// accepted, so the agent drops the chunk instead of retrying it.
+func rejectSessionRecordingChunk(err error) error {
+	return drpcerr.WithCode(err, agentproto.ErrorCodeSessionRecordingChunkRejected)
+}
@@ -0,0 +1,167 @@
+package agentapi_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	agentproto "github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/coderd/agentapi"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbmock"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+)
+
+func TestUploadSessionRecording(t *testing.T) {
+	t.Parallel()
+
+	agent := database.WorkspaceAgent{
+		ID: uuid.New(),
+	}
+	workspaceID := uuid.New()
+	ownerID := uuid.New()
+	now := dbtime.Now()
+
+	newAPI := func(t *testing.T) (*agentapi.SessionRecordingAPI, *dbmock.MockStore) {
+		dbM := dbmock.NewMockStore(gomock.NewController(t))
+		dbM.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(f func(database.Store) error, _ *database.TxOptions) error {
+			return f(dbM)
+		}).AnyTimes()
+		return &agentapi.SessionRecordingAPI{
+			AgentFn: func(context.Context) (database.WorkspaceAgent, error) {
+				return agent, nil
+			},
+			WorkspaceID: workspaceID,
+			OwnerID:     ownerID,
+			Database:    dbM,
+			TimeNowFn: func() time.Time {
+				return now
+			},
+		}, dbM
+	}
+	recording := func(ended bool) database.WorkspaceSessionRecording {
+		r := database.WorkspaceSessionRecording{
+			ID:          uuid.New(),
+			WorkspaceID: workspaceID,
+			AgentID:     agent.ID,
+			UserID:      ownerID,
+			Type:        database.WorkspaceSessionRecordingTypeSsh,
+		}
+		if ended {
+			r.EndedAt = sql.NullTime{Time: now, Valid: true}
+		}
+		return r
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		api, dbM := newAPI(t)
+		rec := recording(false)
+		dbM.EXPECT().GetWorkspaceSessionRecordingByID(gomock.Any(), rec.ID).Return(database.WorkspaceSessionRecording{}, sql.ErrNoRows)
+		dbM.EXPECT().InsertWorkspaceSessionRecording(gomock.Any(), database.InsertWorkspaceSessionRecordingParams{
+			ID:          rec.ID,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+			WorkspaceID: workspaceID,
+			AgentID:     agent.ID,
+			UserID:      ownerID,
+			Type:        database.WorkspaceSessionRecordingTypeSsh,
+		}).Return(rec, nil)
+		dbM.EXPECT().InsertWorkspaceSessionRecordingChunk(gomock.Any(), database.InsertWorkspaceSessionRecordingChunkParams{
+			RecordingID: rec.ID,
+			Sequence:    0,
+			Data:        []byte("{}\n"),
+		}).Return(int64(1), nil)
+		dbM.EXPECT().UpdateWorkspaceSessionRecordingByID(gomock.Any(), database.UpdateWorkspaceSessionRecordingByIDParams{
+			ID:        rec.ID,
+			UpdatedAt: now,
+			SizeDelta: 3,
+			EndedAt:   sql.NullTime{Time: now, Valid: true},
+		}).Return(nil)
+
+		_, err := api.UploadSessionRecording(context.Background(), &agentproto.UploadSessionRecordingRequest{
+			Id:       rec.ID[:],
+			Type:     agentproto.UploadSessionRecordingRequest_SSH,
+			Sequence: 0,
+			Data:     []byte("{}\n"),
+			Final:    true,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Duplicate", func(t *testing.T) {
+		t.Parallel()
+
+		// The final chunk is retried after the recording ended, e.g. because
+		// the response was lost. It must succeed without counting its size
+		// again.
+		api, dbM := newAPI(t)
+		rec := recording(true)
+		dbM.EXPECT().GetWorkspaceSessionRecordingByID(gomock.Any(), rec.ID).Return(rec, nil)
+		dbM.EXPECT().InsertWorkspaceSessionRecordingChunk(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+
+		_, err := api.UploadSessionRecording(context.Background(), &agentproto.UploadSessionRecordingRequest{
+			Id:       rec.ID[:],
+			Type:     agentproto.UploadSessionRecordingRequest_SSH,
+			Sequence: 1,
+			Data:     []byte("[0.5, \"o\", \"hello\"]\n"),
+			Final:    true,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Ended", func(t *testing.T) {
+		t.Parallel()
+
+		api, dbM := newAPI(t)
+		rec := recording(true)
+		dbM.EXPECT().GetWorkspaceSessionRecordingByID(gomock.Any(), rec.ID).Return(rec, nil)
+		dbM.EXPECT().InsertWorkspaceSessionRecordingChunk(gomock.Any(), gomock.Any()).Return(int64(1), nil)
+
+		_, err := api.UploadSessionRecording(context.Background(), &agentproto.UploadSessionRecordingRequest{
+			Id:       rec.ID[:],
+			Type:     agentproto.UploadSessionRecordingRequest_SSH,
+			Sequence: 2,
+			Data:     []byte("[1.5, \"o\", \"late\"]\n"),
+		})
+		require.ErrorContains(t, err, "has already ended")
+		require.True(t, agentproto.IsSessionRecordingChunkRejected(err))
+	})
+
+	t.Run("TooLarge", func(t *testing.T) {
+		t.Parallel()
+
+		api, _ := newAPI(t)
+		id := uuid.New()
+		_, err := api.UploadSessionRecording(context.Background(), &agentproto.UploadSessionRecordingRequest{
+			Id:   id[:],
+			Type: agentproto.UploadSessionRecordingRequest_SSH,
+			Data: make([]byte, 1<<20+1),
+		})
+		require.ErrorContains(t, err, "exceeds the limit")
+		require.True(t, agentproto.IsSessionRecordingChunkRejected(err))
+	})
+
+	t.Run("DatabaseError", func(t *testing.T) {
+		t.Parallel()
+
+		// Errors that may be resolved by retrying are not rejections.
+		api, dbM := newAPI(t)
+		rec := recording(false)
+		dbM.EXPECT().GetWorkspaceSessionRecordingByID(gomock.Any(), rec.ID).Return(database.WorkspaceSessionRecording{}, sql.ErrConnDone)
+
+		_, err := api.UploadSessionRecording(context.Background(), &agentproto.UploadSessionRecordingRequest{
+			Id:   rec.ID[:],
+			Type: agentproto.UploadSessionRecordingRequest_SSH,
+			Data: []byte("{}\n"),
+		})
+		require.Error(t, err)
+		require.False(t, agentproto.IsSessionRecordingChunkRejected(err))
+	})
+}
@@ -2,12 +2,15 @@ package agentapi
 
 import (
 	"context"
+	"slices"
 	"time"
 
+	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 	"google.golang.org/protobuf/types/known/durationpb"
 
 	"cdr.dev/slog"
+	"github.com/coder/coder/v2/agent/agentcgroup"
 	agentproto "github.com/coder/coder/v2/agent/proto"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
@@ -80,5 +83,38 @@ func (a *StatsAPI) UpdateStats(ctx context.Context, req *agentproto.UpdateStatsR
 		return nil, xerrors.Errorf("report agent stats: %w", err)
 	}
 
+	if len(req.Stats.ProcessGroups) > 0 {
+		err = a.updateProcessGroups(ctx, workspaceAgent.ID, req.Stats.ProcessGroups)
+		if err != nil {
+			return nil, xerrors.Errorf("update process groups: %w", err)
+		}
+	}
+
 	return res, nil
 }
+
+// updateProcessGroups stores the usage of the process groups of the agent.
+// Only the groups known to agentcgroup are stored, once each, so an agent
+// cannot create arbitrary rows.
+func (a *StatsAPI) updateProcessGroups(ctx context.Context, agentID uuid.UUID, groups []*agentproto.Stats_ProcessGroup) error {
+	params := database.UpsertWorkspaceAgentProcessGroupsParams{
+		WorkspaceAgentID: agentID,
+		UpdatedAt:        a.now(),
+	}
+	for _, group := range groups {
+		if !slices.Contains(agentcgroup.Groups, group.Name) || slices.Contains(params.Name, group.Name) {
+			continue
+		}
+		params.Name = append(params.Name, group.Name)
+		params.CPUUsageUsec = append(params.CPUUsageUsec, group.CpuUsageUsec)
+		params.CPUCores = append(params.CPUCores, group.CpuCores)
+		params.CPULimitCores = append(params.CPULimitCores, group.CpuLimitCores)
+		params.MemoryBytes = append(params.MemoryBytes, group.MemoryBytes)
+		params.MemoryLimitBytes = append(params.MemoryLimitBytes, group.MemoryLimitBytes)
+		params.OOMKills = append(params.OOMKills, group.OomKills)
+	}
+	if len(params.Name) == 0 {
+		return nil
+	}
+	return a.Database.UpsertWorkspaceAgentProcessGroups(ctx, params)
+}
@@ -248,6 +248,94 @@ func TestUpdateStates(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("ProcessGroups", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			now                   = dbtime.Now()
+			dbM                   = dbmock.NewMockStore(gomock.NewController(t))
+			ps                    = pubsub.NewInMemory()
+			templateScheduleStore = schedule.MockTemplateScheduleStore{
+				GetFn: func(context.Context, database.Store, uuid.UUID) (schedule.TemplateScheduleOptions, error) {
+					panic("should not be called")
+				},
+				SetFn: func(context.Context, database.Store, database.Template, schedule.TemplateScheduleOptions) (database.Template, error) {
+					panic("not implemented")
+				},
+			}
+			batcher = &workspacestatstest.StatsBatcher{}
+
+			req = &agentproto.UpdateStatsRequest{
+				Stats: &agentproto.Stats{
+					ConnectionsByProto: map[string]int64{},
+					ProcessGroups: []*agentproto.Stats_ProcessGroup{
+						{
+							Name:          "sessions",
+							CpuUsageUsec:  1000,
+							CpuCores:      0.5,
+							CpuLimitCores: 2,
+							MemoryBytes:   1024,
+							OomKills:      1,
+						},
+						{
+							Name:             "scripts",
+							CpuUsageUsec:     2000,
+							MemoryBytes:      2048,
+							MemoryLimitBytes: 4096,
+						},
+						// Duplicate and unknown groups are not stored.
+						{
+							Name:         "sessions",
+							CpuUsageUsec: 3000,
+						},
+						{
+							Name:         "unknown",
+							CpuUsageUsec: 4000,
+						},
+					},
+				},
+			}
+		)
+		api := agentapi.StatsAPI{
+			AgentFn: func(context.Context) (database.WorkspaceAgent, error) {
+				return agent, nil
+			},
+			Database: dbM,
+			StatsReporter: workspacestats.NewReporter(workspacestats.ReporterOptions{
+				Database:              dbM,
+				Pubsub:                ps,
+				UsageTracker:          workspacestats.NewTracker(dbM),
+				StatsBatcher:          batcher,
+				TemplateScheduleStore: templateScheduleStorePtr(templateScheduleStore),
+				// Ignored when nil.
+				UpdateAgentMetricsFn: nil,
+			}),
+			AgentStatsRefreshInterval: 10 * time.Second,
+			TimeNowFn: func() time.Time {
+				return now
+			},
+		}
+
+		// Workspace gets fetched.
+		dbM.EXPECT().GetWorkspaceByAgentID(gomock.Any(), agent.ID).Return(workspace, nil)
+
+		// Process groups get stored.
+		dbM.EXPECT().UpsertWorkspaceAgentProcessGroups(gomock.Any(), database.UpsertWorkspaceAgentProcessGroupsParams{
+			WorkspaceAgentID: agent.ID,
+			Name:             []string{"sessions", "scripts"},
+			CPUUsageUsec:     []int64{1000, 2000},
+			CPUCores:         []float64{0.5, 0},
+			CPULimitCores:    []float64{2, 0},
+			MemoryBytes:      []int64{1024, 2048},
+			MemoryLimitBytes: []int64{0, 4096},
+			OOMKills:         []int64{1, 0},
+			UpdatedAt:        now,
+		}).Return(nil)
+
+		_, err := api.UpdateStats(context.Background(), req)
+		require.NoError(t, err)
+	})
+
 	t.Run("NoStats", func(t *testing.T) {
 		t.Parallel()
 
@@ -0,0 +1,71 @@
+package agentapi
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	agentproto "github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/jwtutils"
+	"github.com/coder/coder/v2/coderd/workloadidentity"
+)
+
+// maxWorkloadIdentityAudienceLength guards against agents requesting tokens
+// for absurdly long audiences.
+const maxWorkloadIdentityAudienceLength = 1024
+
+type WorkloadIdentityAPI struct {
+	AgentFn     func(context.Context) (database.WorkspaceAgent, error)
+	WorkspaceID uuid.UUID
+	Database    database.Store
+	AccessURL   *url.URL
+	KeyCache    jwtutils.SigningKeyProvider
+
+	TimeNowFn func() time.Time // defaults to dbtime.Now()
+}
+
+func (a *WorkloadIdentityAPI) now() time.Time {
+	if a.TimeNowFn != nil {
+		return a.TimeNowFn()
+	}
+	return dbtime.Now()
+}
+
+func (a *WorkloadIdentityAPI) GetWorkloadIdentityToken(ctx context.Context, req *agentproto.GetWorkloadIdentityTokenRequest) (*agentproto.WorkloadIdentityToken, error) {
+	if len(req.Audience) > maxWorkloadIdentityAudienceLength {
+		return nil, xerrors.Errorf("audience of %d characters exceeds the limit of %d characters", len(req.Audience), maxWorkloadIdentityAudienceLength)
+	}
+	if a.KeyCache == nil {
+		return nil, xerrors.New("workload identity tokens are not configured")
+	}
+
+	workspaceAgent, err := a.AgentFn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	workspace, err := a.Database.GetWorkspaceByID(ctx, a.WorkspaceID)
+	if err != nil {
+		return nil, xerrors.Errorf("get workspace by id: %w", err)
+	}
+
+	token, expiresAt, err := workloadidentity.Sign(ctx, a.KeyCache, a.AccessURL, workloadidentity.TokenParams{
+		Workspace: workspace,
+		Agent:     workspaceAgent,
+		Audience:  req.Audience,
+		Now:       a.now(),
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("sign workload identity token: %w", err)
+	}
+
+	return &agentproto.WorkloadIdentityToken{
+		Token:     token,
+		ExpiresAt: timestamppb.New(expiresAt),
+	}, nil
+}
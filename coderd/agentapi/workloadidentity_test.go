@@ -0,0 +1,131 @@
+package agentapi_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	agentproto "github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/coderd/agentapi"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbmock"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/jwtutils"
+	"github.com/coder/coder/v2/coderd/workloadidentity"
+)
+
+func TestGetWorkloadIdentityToken(t *testing.T) {
+	t.Parallel()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	accessURL, err := url.Parse("https://coder.example.com")
+	require.NoError(t, err)
+
+	agent := database.WorkspaceAgent{
+		ID:   uuid.New(),
+		Name: "main",
+	}
+	workspace := database.Workspace{
+		ID:               uuid.New(),
+		Name:             "dev",
+		OrganizationID:   uuid.New(),
+		OrganizationName: "acme",
+		TemplateID:       uuid.New(),
+		TemplateName:     "docker",
+		OwnerID:          uuid.New(),
+		OwnerUsername:    "alice",
+	}
+	now := dbtime.Now()
+
+	newAPI := func(t *testing.T) (*agentapi.WorkloadIdentityAPI, *dbmock.MockStore) {
+		dbM := dbmock.NewMockStore(gomock.NewController(t))
+		return &agentapi.WorkloadIdentityAPI{
+			AgentFn: func(context.Context) (database.WorkspaceAgent, error) {
+				return agent, nil
+			},
+			WorkspaceID: workspace.ID,
+			Database:    dbM,
+			AccessURL:   accessURL,
+			KeyCache:    jwtutils.StaticKey{ID: "key", Key: privateKey},
+			TimeNowFn: func() time.Time {
+				return now
+			},
+		}, dbM
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		api, dbM := newAPI(t)
+		dbM.EXPECT().GetWorkspaceByID(gomock.Any(), workspace.ID).Return(workspace, nil)
+
+		resp, err := api.GetWorkloadIdentityToken(context.Background(), &agentproto.GetWorkloadIdentityTokenRequest{
+			Audience: "sts.amazonaws.com",
+		})
+		require.NoError(t, err)
+		require.WithinDuration(t, now.Add(15*time.Minute), resp.ExpiresAt.AsTime(), time.Second)
+
+		var claims workloadidentity.Claims
+		err = jwtutils.Verify(context.Background(), jwtutils.StaticKey{ID: "key", Key: privateKey.Public()}, resp.Token, &claims,
+			jwtutils.WithVerifySignatureAlgorithm(jwtutils.AsymmetricSigningAlgo),
+			jwtutils.WithVerifyExpected(jwt.Expected{
+				Issuer:      "https://coder.example.com/api/v2/workloadidentity",
+				AnyAudience: jwt.Audience{"sts.amazonaws.com"},
+				Time:        now,
+			}),
+		)
+		require.NoError(t, err)
+		require.Equal(t, fmt.Sprintf("organization:%s:template:%s:owner:%s:workspace:%s:agent:%s",
+			workspace.OrganizationID, workspace.TemplateID, workspace.OwnerID, workspace.ID, agent.ID), claims.Subject)
+		require.Equal(t, workspace.OrganizationID, claims.OrganizationID)
+		require.Equal(t, workspace.TemplateID, claims.TemplateID)
+		require.Equal(t, workspace.OwnerID, claims.OwnerID)
+		require.Equal(t, workspace.ID, claims.WorkspaceID)
+		require.Equal(t, agent.ID, claims.AgentID)
+		require.Equal(t, "acme", claims.OrganizationName)
+		require.Equal(t, "docker", claims.TemplateName)
+		require.Equal(t, "alice", claims.OwnerName)
+		require.Equal(t, "dev", claims.WorkspaceName)
+		require.Equal(t, "main", claims.AgentName)
+	})
+
+	t.Run("DefaultAudience", func(t *testing.T) {
+		t.Parallel()
+
+		api, dbM := newAPI(t)
+		dbM.EXPECT().GetWorkspaceByID(gomock.Any(), workspace.ID).Return(workspace, nil)
+
+		resp, err := api.GetWorkloadIdentityToken(context.Background(), &agentproto.GetWorkloadIdentityTokenRequest{})
+		require.NoError(t, err)
+
+		var claims workloadidentity.Claims
+		err = jwtutils.Verify(context.Background(), jwtutils.StaticKey{ID: "key", Key: privateKey.Public()}, resp.Token, &claims,
+			jwtutils.WithVerifySignatureAlgorithm(jwtutils.AsymmetricSigningAlgo),
+			jwtutils.WithVerifyExpected(jwt.Expected{Time: now}),
+		)
+		require.NoError(t, err)
+		require.Equal(t, jwt.Audience{"https://coder.example.com/api/v2/workloadidentity"}, claims.Audience)
+	})
+
+	t.Run("AudienceTooLong", func(t *testing.T) {
+		t.Parallel()
+
+		api, _ := newAPI(t)
+		_, err := api.GetWorkloadIdentityToken(context.Background(), &agentproto.GetWorkloadIdentityTokenRequest{
+			Audience: strings.Repeat("a", 1025),
+		})
+		require.ErrorContains(t, err, "exceeds the limit")
+	})
+}
@@ -59,6 +59,34 @@ func (api *API) postToken(rw http.ResponseWriter, r *http.Request) {
 	if scope != "" {
 		scope = database.APIKeyScope(createToken.Scope)
 	}
+	if createToken.Scope == "" && len(createToken.ScopePermissions) > 0 {
+		scope = database.APIKeyScopeCustom
+	}
+
+	scopePermissions := make(database.APIKeyScopePermissions, 0, len(createToken.ScopePermissions))
+	for _, perm := range createToken.ScopePermissions {
+		scopePermissions = append(scopePermissions, database.APIKeyScopePermission{
+			ResourceType:   string(perm.ResourceType),
+			Action:         policy.Action(perm.Action),
+			OrganizationID: perm.OrganizationID,
+		})
+	}
+	if scope == database.APIKeyScopeCustom {
+		err := apikey.ValidateScopePermissions(scopePermissions, createToken.ScopeAllowList)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid token scope.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	} else if len(scopePermissions) > 0 || len(createToken.ScopeAllowList) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid token scope.",
+			Detail:  fmt.Sprintf("Scope permissions and allow list require the %q scope.", codersdk.APIKeyScopeCustom),
+		})
+		return
+	}
 
 	tokenName := namesgenerator.GetRandomName(1)
 
@@ -67,11 +95,13 @@ func (api *API) postToken(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	params := apikey.CreateParams{
-		UserID:          user.ID,
-		LoginType:       database.LoginTypeToken,
-		DefaultLifetime: api.DeploymentValues.Sessions.DefaultTokenDuration.Value(),
-		Scope:           scope,
-		TokenName:       tokenName,
+		UserID:           user.ID,
+		LoginType:        database.LoginTypeToken,
+		DefaultLifetime:  api.DeploymentValues.Sessions.DefaultTokenDuration.Value(),
+		Scope:            scope,
+		TokenName:        tokenName,
+		ScopePermissions: scopePermissions,
+		ScopeAllowList:   createToken.ScopeAllowList,
 	}
 
 	if createToken.Lifetime != 0 {
@@ -2,6 +2,7 @@ package apikey
 
 import (
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"net"
 	"time"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/cryptorand"
 )
 
@@ -28,6 +30,10 @@ type CreateParams struct {
 	Scope           database.APIKeyScope
 	TokenName       string
 	RemoteAddr      string
+	// ScopePermissions and ScopeAllowList are only allowed, and the
+	// permissions are required, with the custom scope.
+	ScopePermissions database.APIKeyScopePermissions
+	ScopeAllowList   []string
 }
 
 // Generate generates an API key, returning the key as a string as well as the
@@ -68,9 +74,23 @@ func Generate(params CreateParams) (database.InsertAPIKeyParams, string, error)
 	}
 	switch scope {
 	case database.APIKeyScopeAll, database.APIKeyScopeApplicationConnect:
+		if len(params.ScopePermissions) > 0 || len(params.ScopeAllowList) > 0 {
+			return database.InsertAPIKeyParams{}, "", xerrors.Errorf("permissions and allow list require the %q scope", database.APIKeyScopeCustom)
+		}
+	case database.APIKeyScopeCustom:
+		err := ValidateScopePermissions(params.ScopePermissions, params.ScopeAllowList)
+		if err != nil {
+			return database.InsertAPIKeyParams{}, "", err
+		}
 	default:
 		return database.InsertAPIKeyParams{}, "", xerrors.Errorf("invalid API key scope: %q", scope)
 	}
+	if params.ScopePermissions == nil {
+		params.ScopePermissions = database.APIKeyScopePermissions{}
+	}
+	if params.ScopeAllowList == nil {
+		params.ScopeAllowList = []string{}
+	}
 
 	token := fmt.Sprintf("%s-%s", keyID, keySecret)
 
@@ -87,16 +107,42 @@ func Generate(params CreateParams) (database.InsertAPIKeyParams, string, error)
 			Valid: true,
 		},
 		// Make sure in UTC time for common time zone
-		ExpiresAt:    params.ExpiresAt.UTC(),
-		CreatedAt:    dbtime.Now(),
-		UpdatedAt:    dbtime.Now(),
-		HashedSecret: hashed[:],
-		LoginType:    params.LoginType,
-		Scope:        scope,
-		TokenName:    params.TokenName,
+		ExpiresAt:        params.ExpiresAt.UTC(),
+		CreatedAt:        dbtime.Now(),
+		UpdatedAt:        dbtime.Now(),
+		HashedSecret:     hashed[:],
+		LoginType:        params.LoginType,
+		Scope:            scope,
+		TokenName:        params.TokenName,
+		ScopePermissions: params.ScopePermissions,
+		ScopeAllowList:   params.ScopeAllowList,
 	}, token, nil
 }
 
+// ValidateScopePermissions checks the permissions and allow list of a key
+// with the custom scope.
+func ValidateScopePermissions(perms database.APIKeyScopePermissions, allowList []string) error {
+	if len(perms) == 0 {
+		return xerrors.Errorf("the %q scope requires at least one permission", database.APIKeyScopeCustom)
+	}
+	var errs []error
+	for _, perm := range perms {
+		err := rbac.Permission{
+			ResourceType: perm.ResourceType,
+			Action:       perm.Action,
+		}.Valid()
+		if err != nil {
+			errs = append(errs, xerrors.Errorf("permission %q: %w", perm.String(), err))
+		}
+	}
+	for _, id := range allowList {
+		if id == "" {
+			errs = append(errs, xerrors.New("allow list must not contain empty IDs"))
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // generateKey a new ID and secret for an API key.
 func generateKey() (id string, secret string, err error) {
 	// Length of an API Key ID.
@@ -13,6 +13,8 @@ import (
 	"github.com/coder/coder/v2/coderd/apikey"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
 )
 
 func TestGenerate(t *testing.T) {
@@ -104,6 +106,89 @@ func TestGenerate(t *testing.T) {
 				Scope:           "",
 			},
 		},
+		{
+			name: "CustomScope",
+			params: apikey.CreateParams{
+				UserID:          uuid.New(),
+				LoginType:       database.LoginTypeToken,
+				DefaultLifetime: time.Duration(0),
+				ExpiresAt:       time.Now().Add(time.Hour),
+				LifetimeSeconds: int64(time.Hour.Seconds()),
+				TokenName:       "hello",
+				RemoteAddr:      "1.2.3.4",
+				Scope:           database.APIKeyScopeCustom,
+				ScopePermissions: database.APIKeyScopePermissions{
+					{ResourceType: rbac.ResourceTemplate.Type, Action: policy.ActionUpdate, OrganizationID: uuid.New()},
+					{ResourceType: rbac.ResourceWorkspace.Type, Action: policy.WildcardSymbol},
+				},
+				ScopeAllowList: []string{uuid.NewString()},
+			},
+		},
+		{
+			name: "CustomScopeNoPermissions",
+			params: apikey.CreateParams{
+				UserID:          uuid.New(),
+				LoginType:       database.LoginTypeToken,
+				DefaultLifetime: time.Duration(0),
+				ExpiresAt:       time.Now().Add(time.Hour),
+				LifetimeSeconds: int64(time.Hour.Seconds()),
+				TokenName:       "hello",
+				RemoteAddr:      "1.2.3.4",
+				Scope:           database.APIKeyScopeCustom,
+			},
+			fail: true,
+		},
+		{
+			name: "CustomScopeInvalidResource",
+			params: apikey.CreateParams{
+				UserID:          uuid.New(),
+				LoginType:       database.LoginTypeToken,
+				DefaultLifetime: time.Duration(0),
+				ExpiresAt:       time.Now().Add(time.Hour),
+				LifetimeSeconds: int64(time.Hour.Seconds()),
+				TokenName:       "hello",
+				RemoteAddr:      "1.2.3.4",
+				Scope:           database.APIKeyScopeCustom,
+				ScopePermissions: database.APIKeyScopePermissions{
+					{ResourceType: "unknown", Action: policy.ActionRead},
+				},
+			},
+			fail: true,
+		},
+		{
+			name: "CustomScopeInvalidAction",
+			params: apikey.CreateParams{
+				UserID:          uuid.New(),
+				LoginType:       database.LoginTypeToken,
+				DefaultLifetime: time.Duration(0),
+				ExpiresAt:       time.Now().Add(time.Hour),
+				LifetimeSeconds: int64(time.Hour.Seconds()),
+				TokenName:       "hello",
+				RemoteAddr:      "1.2.3.4",
+				Scope:           database.APIKeyScopeCustom,
+				ScopePermissions: database.APIKeyScopePermissions{
+					{ResourceType: rbac.ResourceTemplate.Type, Action: policy.ActionSSH},
+				},
+			},
+			fail: true,
+		},
+		{
+			name: "PermissionsWithoutCustomScope",
+			params: apikey.CreateParams{
+				UserID:          uuid.New(),
+				LoginType:       database.LoginTypeToken,
+				DefaultLifetime: time.Duration(0),
+				ExpiresAt:       time.Now().Add(time.Hour),
+				LifetimeSeconds: int64(time.Hour.Seconds()),
+				TokenName:       "hello",
+				RemoteAddr:      "1.2.3.4",
+				Scope:           database.APIKeyScopeAll,
+				ScopePermissions: database.APIKeyScopePermissions{
+					{ResourceType: rbac.ResourceTemplate.Type, Action: policy.ActionRead},
+				},
+			},
+			fail: true,
+		},
 	}
 
 	for _, tc := range cases {
@@ -163,6 +248,14 @@ func TestGenerate(t *testing.T) {
 				assert.Equal(t, database.APIKeyScopeAll, key.Scope)
 			}
 
+			if tc.params.Scope == database.APIKeyScopeCustom {
+				assert.Equal(t, tc.params.ScopePermissions, key.ScopePermissions)
+				assert.Equal(t, tc.params.ScopeAllowList, key.ScopeAllowList)
+			} else {
+				assert.Empty(t, key.ScopePermissions)
+				assert.NotNil(t, key.ScopeAllowList)
+			}
+
 			if tc.params.TokenName != "" {
 				assert.Equal(t, tc.params.TokenName, key.TokenName)
 			}
@@ -85,6 +85,120 @@ func TestTokenScoped(t *testing.T) {
 	require.Equal(t, keys[0].Scope, codersdk.APIKeyScopeApplicationConnect)
 }
 
+func TestTokenCustomScope(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+	client := coderdtest.New(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+
+	perms := []codersdk.APIKeyScopePermission{
+		{ResourceType: codersdk.ResourceTemplate, Action: codersdk.ActionRead, OrganizationID: owner.OrganizationID},
+		{ResourceType: codersdk.ResourceTemplate, Action: codersdk.ActionUpdate, OrganizationID: owner.OrganizationID},
+		{ResourceType: codersdk.ResourceWorkspace, Action: codersdk.ActionRead},
+	}
+	res, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+		ScopePermissions: perms,
+		ScopeAllowList:   []string{owner.OrganizationID.String()},
+	})
+	require.NoError(t, err)
+
+	keys, err := client.Tokens(ctx, codersdk.Me, codersdk.TokensFilter{})
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.Contains(t, res.Key, keys[0].ID)
+	require.Equal(t, codersdk.APIKeyScopeCustom, keys[0].Scope)
+	require.Equal(t, perms, keys[0].ScopePermissions)
+	require.Equal(t, []string{owner.OrganizationID.String()}, keys[0].ScopeAllowList)
+
+	t.Run("Requests", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		otherVersion := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, otherVersion.ID)
+		otherTemplate := coderdtest.CreateTemplate(t, client, owner.OrganizationID, otherVersion.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, otherTemplate.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		res, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			ScopePermissions: []codersdk.APIKeyScopePermission{
+				{ResourceType: codersdk.ResourceTemplate, Action: codersdk.ActionRead, OrganizationID: owner.OrganizationID},
+				{ResourceType: codersdk.ResourceTemplate, Action: codersdk.ActionUpdate, OrganizationID: owner.OrganizationID},
+			},
+			ScopeAllowList: []string{template.ID.String()},
+		})
+		require.NoError(t, err)
+		scoped := codersdk.New(client.URL)
+		scoped.SetSessionToken(res.Key)
+
+		// The user and their organization can always be read.
+		me, err := scoped.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, owner.UserID, me.ID)
+		_, err = scoped.Organization(ctx, owner.OrganizationID)
+		require.NoError(t, err)
+
+		// The allow listed template can be read and updated.
+		_, err = scoped.Template(ctx, template.ID)
+		require.NoError(t, err)
+		updated, err := scoped.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			DisplayName: "Scoped",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "Scoped", updated.DisplayName)
+
+		// Deleting is not in the scope.
+		err = scoped.DeleteTemplate(ctx, template.ID)
+		require.Error(t, err)
+		_, err = client.Template(ctx, template.ID)
+		require.NoError(t, err)
+
+		// Templates outside of the allow list are not found.
+		_, err = scoped.Template(ctx, otherTemplate.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+		// Other resources are not in the scope.
+		workspaces, err := scoped.Workspaces(ctx, codersdk.WorkspaceFilter{})
+		require.NoError(t, err)
+		require.Empty(t, workspaces.Workspaces)
+		_, err = scoped.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{})
+		require.Error(t, err)
+	})
+
+	t.Run("InvalidPermission", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			ScopePermissions: []codersdk.APIKeyScopePermission{
+				{ResourceType: codersdk.ResourceTemplate, Action: codersdk.ActionSSH},
+			},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("PermissionsWithScopeAll", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			Scope:            codersdk.APIKeyScopeAll,
+			ScopePermissions: perms,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
+
 func TestUserSetTokenDuration(t *testing.T) {
 	t.Parallel()
 
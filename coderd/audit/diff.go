@@ -28,6 +28,8 @@ type Auditable interface {
 		database.AuditableOrganizationMember |
 		database.Organization |
 		database.NotificationTemplate |
+		database.WorkspaceSessionRecording |
+		database.Secret |
 		idpsync.OrganizationSyncSettings |
 		idpsync.GroupSyncSettings |
 		idpsync.RoleSyncSettings
@@ -122,6 +122,10 @@ func ResourceTarget[T Auditable](tgt T) string {
 		return typed.Name
 	case database.NotificationTemplate:
 		return typed.Name
+	case database.WorkspaceSessionRecording:
+		return typed.ID.String()
+	case database.Secret:
+		return typed.Name
 	case idpsync.OrganizationSyncSettings:
 		return "Organization Sync"
 	case idpsync.GroupSyncSettings:
@@ -181,6 +185,10 @@ func ResourceID[T Auditable](tgt T) uuid.UUID {
 		return typed.ID
 	case database.NotificationTemplate:
 		return typed.ID
+	case database.WorkspaceSessionRecording:
+		return typed.ID
+	case database.Secret:
+		return typed.ID
 	case idpsync.OrganizationSyncSettings:
 		return noID // Deployment all uses the same org sync settings
 	case idpsync.GroupSyncSettings:
@@ -232,6 +240,10 @@ func ResourceType[T Auditable](tgt T) database.ResourceType {
 		return database.ResourceTypeOrganization
 	case database.NotificationTemplate:
 		return database.ResourceTypeNotificationTemplate
+	case database.WorkspaceSessionRecording:
+		return database.ResourceTypeWorkspaceSessionRecording
+	case database.Secret:
+		return database.ResourceTypeSecret
 	case idpsync.OrganizationSyncSettings:
 		return database.ResourceTypeIdpSyncSettingsOrganization
 	case idpsync.RoleSyncSettings:
@@ -285,6 +297,11 @@ func ResourceRequiresOrgID[T Auditable]() bool {
 		return true
 	case database.NotificationTemplate:
 		return false
+	case database.WorkspaceSessionRecording:
+		return true
+	case database.Secret:
+		// Personal secrets do not belong to an organization.
+		return false
 	case idpsync.OrganizationSyncSettings:
 		return false
 	case idpsync.GroupSyncSettings:
@@ -0,0 +1,209 @@
+package autobuild
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/wsbuilder"
+)
+
+// costBudgetPeriodStart returns the start of the month budgets are evaluated
+// in. Months are in UTC so every replica agrees on when a budget resets.
+func costBudgetPeriodStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// costBudgetThresholdReached returns true if the cost has reached the given
+// percentage of the monthly limit. A threshold of 0 is disabled.
+func costBudgetThresholdReached(budget database.CostBudget, cost float64, thresholdPercent int32) bool {
+	if thresholdPercent <= 0 || budget.MonthlyLimit <= 0 {
+		return false
+	}
+	return cost >= budget.MonthlyLimit*float64(thresholdPercent)/100
+}
+
+// enforceCostBudgets notifies the members of groups when the cost of their
+// workspaces this month reaches the notify threshold of the group's budget,
+// and stops their running workspaces once it reaches the stop threshold.
+func (e *Executor) enforceCostBudgets(currentTick time.Time, stats *Stats) {
+	periodStart := costBudgetPeriodStart(currentTick)
+	budgets, err := e.db.GetCostBudgetsWithCost(e.ctx, periodStart)
+	if err != nil {
+		e.log.Error(e.ctx, "get cost budgets", slog.Error(err))
+		return
+	}
+
+	for _, row := range budgets {
+		budget := row.CostBudget
+		log := e.log.With(
+			slog.F("group_id", budget.GroupID),
+			slog.F("cost", row.Cost),
+			slog.F("monthly_limit", budget.MonthlyLimit),
+		)
+
+		notify := costBudgetThresholdReached(budget, row.Cost, budget.NotifyThresholdPercent)
+		stop := costBudgetThresholdReached(budget, row.Cost, budget.StopThresholdPercent)
+		notifiedThisPeriod := budget.NotifiedAt.Valid && !budget.NotifiedAt.Time.Before(periodStart)
+		stoppedThisPeriod := budget.StoppedAt.Valid && !budget.StoppedAt.Time.Before(periodStart)
+
+		switch {
+		case stop && !stoppedThisPeriod:
+			log.Info(e.ctx, "cost budget stop threshold reached")
+			e.notifyCostBudgetThresholdReached(log, row, true)
+			err = e.db.UpdateCostBudgetStoppedAtByGroupID(e.ctx, database.UpdateCostBudgetStoppedAtByGroupIDParams{
+				GroupID:   budget.GroupID,
+				StoppedAt: sql.NullTime{Time: dbtime.Time(currentTick.UTC()), Valid: true},
+			})
+			if err != nil {
+				log.Error(e.ctx, "update cost budget stopped at", slog.Error(err))
+				continue
+			}
+			// Members were told the budget is exhausted, there's no need to
+			// warn them about approaching it as well.
+			if !notifiedThisPeriod {
+				err = e.db.UpdateCostBudgetNotifiedAtByGroupID(e.ctx, database.UpdateCostBudgetNotifiedAtByGroupIDParams{
+					GroupID:    budget.GroupID,
+					NotifiedAt: sql.NullTime{Time: dbtime.Time(currentTick.UTC()), Valid: true},
+				})
+				if err != nil {
+					log.Error(e.ctx, "update cost budget notified at", slog.Error(err))
+				}
+			}
+		case notify && !notifiedThisPeriod && !stoppedThisPeriod:
+			log.Info(e.ctx, "cost budget notify threshold reached")
+			e.notifyCostBudgetThresholdReached(log, row, false)
+			err = e.db.UpdateCostBudgetNotifiedAtByGroupID(e.ctx, database.UpdateCostBudgetNotifiedAtByGroupIDParams{
+				GroupID:    budget.GroupID,
+				NotifiedAt: sql.NullTime{Time: dbtime.Time(currentTick.UTC()), Valid: true},
+			})
+			if err != nil {
+				log.Error(e.ctx, "update cost budget notified at", slog.Error(err))
+			}
+		}
+
+		if !stop {
+			continue
+		}
+		// Workspaces started after the threshold was reached are stopped
+		// again until the budget resets at the start of the next month.
+		workspaceIDs, err := e.db.GetCostBudgetWorkspaceIDs(e.ctx, budget.GroupID)
+		if err != nil {
+			log.Error(e.ctx, "get cost budget workspaces", slog.Error(err))
+			continue
+		}
+		for _, workspaceID := range workspaceIDs {
+			wsLog := log.With(slog.F("workspace_id", workspaceID))
+			job, err := e.stopWorkspaceOverBudget(wsLog, workspaceID)
+			if err == nil && job != nil {
+				// The job must be posted after the transaction commits, see
+				// runOnce.
+				err = provisionerjobs.PostJob(e.ps, *job)
+				if err != nil {
+					err = xerrors.Errorf("post provisioner job to pubsub: %w", err)
+				}
+			}
+			if err != nil {
+				if !xerrors.Is(err, context.Canceled) {
+					wsLog.Error(e.ctx, "failed to stop workspace over cost budget", slog.Error(err))
+					stats.Errors[workspaceID] = err
+				}
+				continue
+			}
+			if job != nil {
+				stats.Transitions[workspaceID] = database.WorkspaceTransitionStop
+			}
+		}
+	}
+}
+
+func (e *Executor) notifyCostBudgetThresholdReached(log slog.Logger, row database.GetCostBudgetsWithCostRow, stopping bool) {
+	budget := row.CostBudget
+	memberIDs, err := e.db.GetCostBudgetMemberIDs(e.ctx, budget.GroupID)
+	if err != nil {
+		log.Error(e.ctx, "get cost budget members", slog.Error(err))
+		return
+	}
+	labels := map[string]string{
+		"group":    row.GroupName,
+		"cost":     fmt.Sprintf("%.2f", row.Cost),
+		"limit":    fmt.Sprintf("%.2f", budget.MonthlyLimit),
+		"percent":  strconv.Itoa(int(row.Cost / budget.MonthlyLimit * 100)),
+		"stopping": strconv.FormatBool(stopping),
+	}
+	for _, userID := range memberIDs {
+		_, err = e.notificationsEnqueuer.Enqueue(e.ctx, userID, notifications.TemplateCostBudgetThresholdReached,
+			labels, "lifecycle_executor",
+			// Associate this notification with all the related entities.
+			budget.GroupID, budget.OrganizationID,
+		)
+		if err != nil {
+			log.Warn(e.ctx, "failed to notify of cost budget threshold", slog.F("user_id", userID), slog.Error(err))
+		}
+	}
+}
+
+// stopWorkspaceOverBudget stops the workspace if it is running. It returns the
+// job of the stop build, if any.
+func (e *Executor) stopWorkspaceOverBudget(log slog.Logger, workspaceID uuid.UUID) (*database.ProvisionerJob, error) {
+	var job *database.ProvisionerJob
+	err := e.db.InTx(func(tx database.Store) error {
+		ok, err := tx.TryAcquireLock(e.ctx, database.GenLockID(fmt.Sprintf("lifecycle-executor:%s", workspaceID)))
+		if err != nil {
+			return xerrors.Errorf("try acquire lifecycle executor lock: %w", err)
+		}
+		if !ok {
+			log.Debug(e.ctx, "unable to acquire lock for workspace, skipping")
+			return nil
+		}
+
+		ws, err := tx.GetWorkspaceByID(e.ctx, workspaceID)
+		if err != nil {
+			return xerrors.Errorf("get workspace by id: %w", err)
+		}
+		latestBuild, err := tx.GetLatestWorkspaceBuildByWorkspaceID(e.ctx, ws.ID)
+		if err != nil {
+			return xerrors.Errorf("get latest workspace build: %w", err)
+		}
+		latestJob, err := tx.GetProvisionerJobByID(e.ctx, latestBuild.JobID)
+		if err != nil {
+			return xerrors.Errorf("get latest provisioner job: %w", err)
+		}
+		// Builds in progress are stopped on a later tick once they complete.
+		if latestBuild.Transition != database.WorkspaceTransitionStart ||
+			latestJob.JobStatus != database.ProvisionerJobStatusSucceeded {
+			return nil
+		}
+
+		builder := wsbuilder.New(ws, database.WorkspaceTransitionStop).
+			SetLastWorkspaceBuildInTx(&latestBuild).
+			SetLastWorkspaceBuildJobInTx(&latestJob).
+			Reason(database.BuildReasonAutostop)
+		_, job, _, err = builder.Build(e.ctx, tx, nil, audit.WorkspaceBuildBaggage{IP: "127.0.0.1"})
+		if err != nil {
+			return xerrors.Errorf("build workspace with transition %q: %w", database.WorkspaceTransitionStop, err)
+		}
+		log.Info(e.ctx, "stopping workspace over cost budget")
+		return nil
+	}, &database.TxOptions{
+		Isolation:    sql.LevelRepeatableRead,
+		TxIdentifier: "lifecycle",
+	})
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
@@ -121,6 +121,52 @@ func (e *Executor) Run() {
 	}()
 }
 
+// rejectExpiredBuildApprovals rejects workspace builds that were not approved
+// before their approval timed out, and cancels their provisioner jobs.
+func (e *Executor) rejectExpiredBuildApprovals(now time.Time) {
+	// nolint:gocritic // The autostart actor cannot review build approvals.
+	ctx := dbauthz.AsSystemRestricted(e.ctx)
+	err := e.db.InTx(func(tx database.Store) error {
+		approvals, err := tx.RejectExpiredWorkspaceBuildApprovals(ctx, database.RejectExpiredWorkspaceBuildApprovalsParams{
+			Now:    now,
+			Reason: "The build was not approved in time.",
+		})
+		if err != nil {
+			return xerrors.Errorf("reject expired workspace build approvals: %w", err)
+		}
+		for _, approval := range approvals {
+			build, err := tx.GetWorkspaceBuildByID(ctx, approval.WorkspaceBuildID)
+			if err != nil {
+				return xerrors.Errorf("get workspace build %s: %w", approval.WorkspaceBuildID, err)
+			}
+			job, err := tx.GetProvisionerJobByID(ctx, build.JobID)
+			if err != nil {
+				return xerrors.Errorf("get provisioner job %s: %w", build.JobID, err)
+			}
+			if job.CompletedAt.Valid {
+				continue
+			}
+			err = tx.UpdateProvisionerJobWithCompleteByID(ctx, database.UpdateProvisionerJobWithCompleteByIDParams{
+				ID:          job.ID,
+				UpdatedAt:   now,
+				CompletedAt: sql.NullTime{Time: now, Valid: true},
+				Error:       sql.NullString{String: approval.Reason, Valid: true},
+			})
+			if err != nil {
+				return xerrors.Errorf("fail provisioner job %s: %w", job.ID, err)
+			}
+			e.log.Info(e.ctx, "rejected workspace build that was not approved in time",
+				slog.F("workspace_id", build.WorkspaceID),
+				slog.F("workspace_build_id", build.ID),
+			)
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		e.log.Error(e.ctx, "reject expired workspace build approvals", slog.Error(err))
+	}
+}
+
 func (e *Executor) runOnce(t time.Time) Stats {
 	stats := Stats{
 		Transitions: make(map[uuid.UUID]database.WorkspaceTransition),
@@ -133,6 +179,8 @@ func (e *Executor) runOnce(t time.Time) Stats {
 	}()
 	currentTick := t.Truncate(time.Minute)
 
+	e.rejectExpiredBuildApprovals(t)
+
 	// TTL is set at the workspace level, and deadline at the workspace build level.
 	// When a workspace build is created, its deadline initially starts at zero.
 	// When provisionerd successfully completes a provision job, the deadline is
@@ -174,6 +222,7 @@ func (e *Executor) runOnce(t time.Time) Stats {
 					ws                    database.Workspace
 					tmpl                  database.Template
 					didAutoUpdate         bool
+					approvalRequired      bool
 				)
 				err := e.db.InTx(func(tx database.Store) error {
 					var err error
@@ -276,6 +325,7 @@ func (e *Executor) runOnce(t time.Time) Stats {
 						if err != nil {
 							return xerrors.Errorf("build workspace with transition %q: %w", nextTransition, err)
 						}
+						approvalRequired = builder.ApprovalRequired()
 					}
 
 					// Transition the workspace to dormant if it has breached the template's
@@ -366,7 +416,10 @@ func (e *Executor) runOnce(t time.Time) Stats {
 				if err != nil {
 					return xerrors.Errorf("transition workspace: %w", err)
 				}
-				if job != nil {
+				if approvalRequired {
+					// The job is posted once the build is approved.
+					wsbuilder.NotifyApprovalRequested(e.ctx, log, e.db, e.notificationsEnqueuer, "autobuild", nextBuild.InitiatorID, ws, *nextBuild)
+				} else if job != nil {
 					// Note that we can't refactor such that posting the job happens inside wsbuilder because it's called
 					// with an outer transaction like this, and we need to make sure the outer transaction commits before
 					// posting the job.  If we post before the transaction commits, provisionerd might try to acquire the
@@ -417,6 +470,14 @@ func (e *Executor) runOnce(t time.Time) Stats {
 		e.log.Error(e.ctx, "workspace scheduling errgroup failed", slog.Error(err))
 	}
 
+	// Scheduled actions run after the autostart and autostop transitions so
+	// they wait for any build started above.
+	e.runScheduledActions(currentTick, &stats)
+
+	// Cost budgets are enforced last so they stop workspaces started by
+	// schedules above.
+	e.enforceCostBudgets(currentTick, &stats)
+
 	return stats
 }
 
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/coderd/database"
@@ -161,3 +162,184 @@ func Test_isEligibleForAutostart(t *testing.T) {
 		})
 	}
 }
+
+func Test_getScheduledActionTransition(t *testing.T) {
+	t.Parallel()
+
+	var (
+		now             = time.Date(2024, 1, 1, 6, 1, 0, 0, time.UTC)
+		activeVersionID = uuid.New()
+		okUser          = database.User{Status: database.UserStatusActive}
+		okWorkspace     = database.Workspace{}
+		okJob           = database.ProvisionerJob{JobStatus: database.ProvisionerJobStatusSucceeded}
+		running         = database.WorkspaceBuild{Transition: database.WorkspaceTransitionStart, TemplateVersionID: activeVersionID}
+		stopped         = database.WorkspaceBuild{Transition: database.WorkspaceTransitionStop, TemplateVersionID: activeVersionID}
+		outdated        = func(b database.WorkspaceBuild) database.WorkspaceBuild {
+			b.TemplateVersionID = uuid.New()
+			return b
+		}
+		action = func(actionType database.WorkspaceScheduledActionType) database.WorkspaceScheduledAction {
+			return database.WorkspaceScheduledAction{
+				Action:    actionType,
+				NextRunAt: now.Add(-time.Minute),
+			}
+		}
+		// restarting is a restart action that stopped the workspace on a
+		// previous tick.
+		restarting = database.WorkspaceScheduledAction{
+			Action:    database.WorkspaceScheduledActionTypeRestart,
+			NextRunAt: now.Add(-time.Minute),
+			LastRunAt: sql.NullTime{Time: now.Add(-30 * time.Second), Valid: true},
+		}
+	)
+
+	testCases := []struct {
+		Name                  string
+		Action                database.WorkspaceScheduledAction
+		User                  database.User
+		Workspace             database.Workspace
+		Build                 database.WorkspaceBuild
+		Job                   database.ProvisionerJob
+		ExpectedTransition    database.WorkspaceTransition
+		ExpectedActiveVersion bool
+		ExpectedStep          scheduledActionStep
+	}{
+		{
+			Name:               "StartStopped",
+			Action:             action(database.WorkspaceScheduledActionTypeStart),
+			User:               okUser,
+			Workspace:          okWorkspace,
+			Build:              stopped,
+			Job:                okJob,
+			ExpectedTransition: database.WorkspaceTransitionStart,
+			ExpectedStep:       scheduledActionDone,
+		},
+		{
+			Name:         "StartRunning",
+			Action:       action(database.WorkspaceScheduledActionTypeStart),
+			User:         okUser,
+			Workspace:    okWorkspace,
+			Build:        running,
+			Job:          okJob,
+			ExpectedStep: scheduledActionDone,
+		},
+		{
+			Name:               "StopRunning",
+			Action:             action(database.WorkspaceScheduledActionTypeStop),
+			User:               okUser,
+			Workspace:          okWorkspace,
+			Build:              running,
+			Job:                okJob,
+			ExpectedTransition: database.WorkspaceTransitionStop,
+			ExpectedStep:       scheduledActionDone,
+		},
+		{
+			Name:               "RestartRunning",
+			Action:             action(database.WorkspaceScheduledActionTypeRestart),
+			User:               okUser,
+			Workspace:          okWorkspace,
+			Build:              running,
+			Job:                okJob,
+			ExpectedTransition: database.WorkspaceTransitionStop,
+			ExpectedStep:       scheduledActionContinue,
+		},
+		{
+			Name:               "RestartStoppedByAction",
+			Action:             restarting,
+			User:               okUser,
+			Workspace:          okWorkspace,
+			Build:              stopped,
+			Job:                okJob,
+			ExpectedTransition: database.WorkspaceTransitionStart,
+			ExpectedStep:       scheduledActionDone,
+		},
+		{
+			Name:         "RestartStopped",
+			Action:       action(database.WorkspaceScheduledActionTypeRestart),
+			User:         okUser,
+			Workspace:    okWorkspace,
+			Build:        stopped,
+			Job:          okJob,
+			ExpectedStep: scheduledActionDone,
+		},
+		{
+			Name:                  "UpdateRunning",
+			Action:                action(database.WorkspaceScheduledActionTypeUpdate),
+			User:                  okUser,
+			Workspace:             okWorkspace,
+			Build:                 outdated(running),
+			Job:                   okJob,
+			ExpectedTransition:    database.WorkspaceTransitionStart,
+			ExpectedActiveVersion: true,
+			ExpectedStep:          scheduledActionDone,
+		},
+		{
+			Name:                  "UpdateStopped",
+			Action:                action(database.WorkspaceScheduledActionTypeUpdate),
+			User:                  okUser,
+			Workspace:             okWorkspace,
+			Build:                 outdated(stopped),
+			Job:                   okJob,
+			ExpectedTransition:    database.WorkspaceTransitionStop,
+			ExpectedActiveVersion: true,
+			ExpectedStep:          scheduledActionDone,
+		},
+		{
+			Name:         "UpdateUpToDate",
+			Action:       action(database.WorkspaceScheduledActionTypeUpdate),
+			User:         okUser,
+			Workspace:    okWorkspace,
+			Build:        running,
+			Job:          okJob,
+			ExpectedStep: scheduledActionDone,
+		},
+		{
+			Name:         "BuildInProgress",
+			Action:       action(database.WorkspaceScheduledActionTypeStop),
+			User:         okUser,
+			Workspace:    okWorkspace,
+			Build:        running,
+			Job:          database.ProvisionerJob{JobStatus: database.ProvisionerJobStatusRunning},
+			ExpectedStep: scheduledActionWait,
+		},
+		{
+			Name:         "BuildFailed",
+			Action:       action(database.WorkspaceScheduledActionTypeStart),
+			User:         okUser,
+			Workspace:    okWorkspace,
+			Build:        stopped,
+			Job:          database.ProvisionerJob{JobStatus: database.ProvisionerJobStatusFailed},
+			ExpectedStep: scheduledActionDone,
+		},
+		{
+			Name:         "SuspendedUser",
+			Action:       action(database.WorkspaceScheduledActionTypeStart),
+			User:         database.User{Status: database.UserStatusSuspended},
+			Workspace:    okWorkspace,
+			Build:        stopped,
+			Job:          okJob,
+			ExpectedStep: scheduledActionDone,
+		},
+		{
+			Name:         "DormantWorkspace",
+			Action:       action(database.WorkspaceScheduledActionTypeStart),
+			User:         okUser,
+			Workspace:    database.Workspace{DormantAt: sql.NullTime{Time: now, Valid: true}},
+			Build:        stopped,
+			Job:          okJob,
+			ExpectedStep: scheduledActionDone,
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+
+			transition, activeVersion, step := getScheduledActionTransition(c.Action, c.User, c.Workspace, c.Build, c.Job, activeVersionID)
+			require.Equal(t, c.ExpectedTransition, transition)
+			require.Equal(t, c.ExpectedActiveVersion, activeVersion)
+			require.Equal(t, c.ExpectedStep, step)
+		})
+	}
+}
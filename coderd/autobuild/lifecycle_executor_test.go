@@ -18,9 +18,11 @@ import (
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
 	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/notifications/notificationstest"
+	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/schedule/cron"
 	"github.com/coder/coder/v2/coderd/util/ptr"
@@ -1115,6 +1117,321 @@ func TestExecutorInactiveWorkspace(t *testing.T) {
 	})
 }
 
+// TestExecutorBuildApprovalTimeout tests that builds that are not approved
+// before their approval expires are rejected.
+func TestExecutorBuildApprovalTimeout(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ticker = make(chan time.Time)
+		statCh = make(chan autobuild.Stats)
+		client = coderdtest.New(t, &coderdtest.Options{
+			AutobuildTicker:          ticker,
+			IncludeProvisionerDaemon: true,
+			AutobuildStats:           statCh,
+		})
+		timeout = time.Hour
+	)
+	owner := coderdtest.CreateFirstUser(t, client)
+	memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	_, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+		RequireBuildApproval:       ptr.Ref(true),
+		BuildApprovalTimeoutMillis: ptr.Ref(timeout.Milliseconds()),
+	})
+	require.NoError(t, err)
+
+	ws := coderdtest.CreateWorkspace(t, memberClient, template.ID)
+	approval, err := memberClient.WorkspaceBuildApproval(ctx, ws.LatestBuild.ID)
+	require.NoError(t, err)
+	require.Equal(t, codersdk.WorkspaceBuildApprovalStatusPending, approval.Status)
+	require.NotNil(t, approval.ExpiresAt)
+
+	// Nothing happens before the approval expires.
+	ticker <- approval.ExpiresAt.Add(-time.Minute)
+	<-statCh
+	approval, err = memberClient.WorkspaceBuildApproval(ctx, ws.LatestBuild.ID)
+	require.NoError(t, err)
+	require.Equal(t, codersdk.WorkspaceBuildApprovalStatusPending, approval.Status)
+
+	ticker <- approval.ExpiresAt.Add(time.Minute)
+	<-statCh
+	approval, err = memberClient.WorkspaceBuildApproval(ctx, ws.LatestBuild.ID)
+	require.NoError(t, err)
+	require.Equal(t, codersdk.WorkspaceBuildApprovalStatusRejected, approval.Status)
+	require.Nil(t, approval.ReviewerID)
+	build, err := memberClient.WorkspaceBuild(ctx, ws.LatestBuild.ID)
+	require.NoError(t, err)
+	require.Equal(t, codersdk.ProvisionerJobFailed, build.Job.Status)
+}
+
+// TestExecutorBuildApproval tests that automatic builds on templates that
+// require build approval only require approval if they update the workspace to
+// another template version.
+func TestExecutorBuildApproval(t *testing.T) {
+	t.Parallel()
+
+	type testEnv struct {
+		client     *codersdk.Client
+		approverID uuid.UUID
+		tickCh     chan time.Time
+		statsCh    chan autobuild.Stats
+		enqueuer   *notificationstest.FakeEnqueuer
+		workspace  codersdk.Workspace
+	}
+
+	// setup returns a running workspace with autostart on a template that
+	// requires build approval, and a template admin that approves its builds.
+	setup := func(t *testing.T, automaticUpdates codersdk.AutomaticUpdates) testEnv {
+		var (
+			sched    = mustSchedule(t, "CRON_TZ=UTC 0 * * * *")
+			tickCh   = make(chan time.Time)
+			statsCh  = make(chan autobuild.Stats)
+			enqueuer = &notificationstest.FakeEnqueuer{}
+			client   = coderdtest.New(t, &coderdtest.Options{
+				AutobuildTicker:          tickCh,
+				IncludeProvisionerDaemon: true,
+				AutobuildStats:           statsCh,
+				NotificationsEnqueuer:    enqueuer,
+			})
+			// The scheduled actions may be due after the workspace would be
+			// stopped by its TTL.
+			workspace = mustProvisionWorkspace(t, client, func(cwr *codersdk.CreateWorkspaceRequest) {
+				cwr.AutostartSchedule = ptr.Ref(sched.String())
+				cwr.TTLMillis = nil
+				cwr.AutomaticUpdates = automaticUpdates
+			})
+		)
+		_, err := client.UpdateTemplateMeta(context.Background(), workspace.TemplateID, codersdk.UpdateTemplateMeta{
+			RequireBuildApproval: ptr.Ref(true),
+		})
+		require.NoError(t, err)
+		_, approver := coderdtest.CreateAnotherUser(t, client, workspace.OrganizationID, rbac.RoleTemplateAdmin())
+		return testEnv{
+			client:     client,
+			approverID: approver.ID,
+			tickCh:     tickCh,
+			statsCh:    statsCh,
+			enqueuer:   enqueuer,
+			workspace:  workspace,
+		}
+	}
+
+	requirePendingApproval := func(t *testing.T, env testEnv, build codersdk.WorkspaceBuild) {
+		t.Helper()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		approval, err := env.client.WorkspaceBuildApproval(ctx, build.ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceBuildApprovalStatusPending, approval.Status)
+		require.Equal(t, codersdk.ProvisionerJobPending, build.Job.Status)
+		sent := env.enqueuer.Sent(notificationstest.WithTemplateID(notifications.TemplateWorkspaceBuildApprovalRequested))
+		require.Len(t, sent, 1)
+		require.Equal(t, env.approverID, sent[0].UserID)
+		require.Contains(t, sent[0].Targets, build.WorkspaceID)
+	}
+
+	t.Run("Autostart", func(t *testing.T) {
+		t.Parallel()
+
+		env := setup(t, codersdk.AutomaticUpdatesNever)
+		workspace := coderdtest.MustTransitionWorkspace(t, env.client, env.workspace.ID, database.WorkspaceTransitionStart, database.WorkspaceTransitionStop)
+
+		// When: the workspace is autostarted with the version of its last
+		// build.
+		env.tickCh <- mustSchedule(t, *workspace.AutostartSchedule).Next(workspace.LatestBuild.CreatedAt)
+		close(env.tickCh)
+		stats := <-env.statsCh
+		require.Len(t, stats.Errors, 0)
+		require.Equal(t, database.WorkspaceTransitionStart, stats.Transitions[workspace.ID])
+
+		// Then: the build doesn't require approval.
+		workspace = coderdtest.MustWorkspace(t, env.client, workspace.ID)
+		build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, env.client, workspace.LatestBuild.ID)
+		require.Equal(t, codersdk.WorkspaceStatusRunning, build.Status)
+		require.Empty(t, env.enqueuer.Sent(notificationstest.WithTemplateID(notifications.TemplateWorkspaceBuildApprovalRequested)))
+	})
+
+	t.Run("ScheduledUpdate", func(t *testing.T) {
+		t.Parallel()
+
+		env := setup(t, codersdk.AutomaticUpdatesNever)
+		workspace := env.workspace
+		ctx := testutil.Context(t, testutil.WaitLong)
+		action, err := env.client.CreateWorkspaceScheduledAction(ctx, workspace.ID, codersdk.CreateWorkspaceScheduledActionRequest{
+			Action:   codersdk.WorkspaceScheduledActionTypeUpdate,
+			Schedule: "CRON_TZ=UTC 30 2 * * *",
+		})
+		require.NoError(t, err)
+
+		// Given: the template has been updated.
+		newVersion := coderdtest.UpdateTemplateVersion(t, env.client, workspace.OrganizationID, nil, workspace.TemplateID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, env.client, newVersion.ID)
+		require.NoError(t, env.client.UpdateActiveTemplateVersion(ctx, workspace.TemplateID, codersdk.UpdateActiveTemplateVersion{
+			ID: newVersion.ID,
+		}))
+
+		// When: the update is due while the workspace is running.
+		env.tickCh <- action.NextRunAt.Add(time.Minute)
+		close(env.tickCh)
+		stats := <-env.statsCh
+		require.Len(t, stats.Errors, 0)
+		require.Equal(t, database.WorkspaceTransitionStart, stats.Transitions[workspace.ID])
+
+		// Then: the update waits for approval.
+		workspace = coderdtest.MustWorkspace(t, env.client, workspace.ID)
+		require.Equal(t, newVersion.ID, workspace.LatestBuild.TemplateVersionID)
+		require.Equal(t, codersdk.BuildReasonScheduled, workspace.LatestBuild.Reason)
+		requirePendingApproval(t, env, workspace.LatestBuild)
+	})
+
+	t.Run("Rollout", func(t *testing.T) {
+		t.Parallel()
+
+		env := setup(t, codersdk.AutomaticUpdatesAlways)
+		workspace := coderdtest.MustTransitionWorkspace(t, env.client, env.workspace.ID, database.WorkspaceTransitionStart, database.WorkspaceTransitionStop)
+
+		// Given: a rollout of a candidate version includes the workspace.
+		ctx := testutil.Context(t, testutil.WaitLong)
+		candidate := coderdtest.UpdateTemplateVersion(t, env.client, workspace.OrganizationID, nil, workspace.TemplateID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, env.client, candidate.ID)
+		_, err := env.client.CreateTemplateRollout(ctx, workspace.TemplateID, codersdk.CreateTemplateRolloutRequest{
+			TemplateVersionID: candidate.ID,
+			Percent:           100,
+			MinBuilds:         5,
+		})
+		require.NoError(t, err)
+
+		// When: the workspace is autostarted.
+		env.tickCh <- mustSchedule(t, *workspace.AutostartSchedule).Next(workspace.LatestBuild.CreatedAt)
+		close(env.tickCh)
+		stats := <-env.statsCh
+		require.Len(t, stats.Errors, 0)
+		require.Equal(t, database.WorkspaceTransitionStart, stats.Transitions[workspace.ID])
+
+		// Then: the update to the candidate waits for approval.
+		workspace = coderdtest.MustWorkspace(t, env.client, workspace.ID)
+		require.Equal(t, candidate.ID, workspace.LatestBuild.TemplateVersionID)
+		require.Equal(t, codersdk.BuildReasonAutostart, workspace.LatestBuild.Reason)
+		requirePendingApproval(t, env, workspace.LatestBuild)
+	})
+}
+
+func TestExecutorScheduledActions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Restart", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			tickCh  = make(chan time.Time)
+			statsCh = make(chan autobuild.Stats)
+			client  = coderdtest.New(t, &coderdtest.Options{
+				AutobuildTicker:          tickCh,
+				IncludeProvisionerDaemon: true,
+				AutobuildStats:           statsCh,
+			})
+			// The action may be due after the workspace would be stopped
+			// by its TTL.
+			workspace = mustProvisionWorkspace(t, client, func(cwr *codersdk.CreateWorkspaceRequest) {
+				cwr.TTLMillis = nil
+			})
+		)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		action, err := client.CreateWorkspaceScheduledAction(ctx, workspace.ID, codersdk.CreateWorkspaceScheduledActionRequest{
+			Action:   codersdk.WorkspaceScheduledActionTypeRestart,
+			Schedule: "CRON_TZ=UTC 0 6 * * *",
+		})
+		require.NoError(t, err)
+
+		// Nothing happens before the action is due.
+		tickCh <- action.NextRunAt.Add(-time.Minute)
+		stats := <-statsCh
+		require.Len(t, stats.Errors, 0)
+		require.Len(t, stats.Transitions, 0)
+
+		// When: the action is due, the workspace is stopped first.
+		tickCh <- action.NextRunAt.Add(time.Minute)
+		stats = <-statsCh
+		require.Len(t, stats.Errors, 0)
+		require.Equal(t, database.WorkspaceTransitionStop, stats.Transitions[workspace.ID])
+		workspace = coderdtest.MustWorkspace(t, client, workspace.ID)
+		require.Equal(t, codersdk.BuildReasonScheduled, workspace.LatestBuild.Reason)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		// Then: the workspace is started again on the next tick.
+		tickCh <- action.NextRunAt.Add(2 * time.Minute)
+		stats = <-statsCh
+		require.Len(t, stats.Errors, 0)
+		require.Equal(t, database.WorkspaceTransitionStart, stats.Transitions[workspace.ID])
+		workspace = coderdtest.MustWorkspace(t, client, workspace.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		// And: the action is scheduled for the next day.
+		actions, err := client.WorkspaceScheduledActions(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, actions, 1)
+		require.Equal(t, action.NextRunAt.Add(24*time.Hour), actions[0].NextRunAt)
+		require.NotNil(t, actions[0].LastRunAt)
+
+		tickCh <- action.NextRunAt.Add(3 * time.Minute)
+		close(tickCh)
+		stats = <-statsCh
+		require.Len(t, stats.Transitions, 0)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			tickCh  = make(chan time.Time)
+			statsCh = make(chan autobuild.Stats)
+			client  = coderdtest.New(t, &coderdtest.Options{
+				AutobuildTicker:          tickCh,
+				IncludeProvisionerDaemon: true,
+				AutobuildStats:           statsCh,
+			})
+			// The action may be due after the workspace would be stopped
+			// by its TTL.
+			workspace = mustProvisionWorkspace(t, client, func(cwr *codersdk.CreateWorkspaceRequest) {
+				cwr.TTLMillis = nil
+			})
+		)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		action, err := client.CreateWorkspaceScheduledAction(ctx, workspace.ID, codersdk.CreateWorkspaceScheduledActionRequest{
+			Action:   codersdk.WorkspaceScheduledActionTypeUpdate,
+			Schedule: "CRON_TZ=UTC 0 2 * * *",
+		})
+		require.NoError(t, err)
+
+		// Given: the workspace is stopped and its template has been updated.
+		workspace = coderdtest.MustTransitionWorkspace(t, client, workspace.ID, database.WorkspaceTransitionStart, database.WorkspaceTransitionStop)
+		newVersion := coderdtest.UpdateTemplateVersion(t, client, workspace.OrganizationID, nil, workspace.TemplateID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, newVersion.ID)
+		require.NoError(t, client.UpdateActiveTemplateVersion(ctx, workspace.TemplateID, codersdk.UpdateActiveTemplateVersion{
+			ID: newVersion.ID,
+		}))
+
+		// When: the action is due.
+		tickCh <- action.NextRunAt.Add(time.Minute)
+		close(tickCh)
+		stats := <-statsCh
+		require.Len(t, stats.Errors, 0)
+
+		// Then: the workspace is updated but remains stopped.
+		require.Equal(t, database.WorkspaceTransitionStop, stats.Transitions[workspace.ID])
+		workspace = coderdtest.MustWorkspace(t, client, workspace.ID)
+		require.Equal(t, newVersion.ID, workspace.LatestBuild.TemplateVersionID)
+		require.Equal(t, codersdk.WorkspaceTransitionStop, workspace.LatestBuild.Transition)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+	})
+}
+
 func TestNotifications(t *testing.T) {
 	t.Parallel()
 
@@ -1185,6 +1502,85 @@ func TestNotifications(t *testing.T) {
 	})
 }
 
+func TestExecutorCostBudgets(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db, ps    = dbtestutil.NewDB(t)
+		tickCh    = make(chan time.Time)
+		statsCh   = make(chan autobuild.Stats)
+		notifyEnq = notificationstest.FakeEnqueuer{}
+		client    = coderdtest.New(t, &coderdtest.Options{
+			AutobuildTicker:          tickCh,
+			IncludeProvisionerDaemon: true,
+			AutobuildStats:           statsCh,
+			NotificationsEnqueuer:    &notifyEnq,
+			Database:                 db,
+			Pubsub:                   ps,
+		})
+		owner   = coderdtest.CreateFirstUser(t, client)
+		version = coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, &echo.Responses{
+			Parse:         echo.ParseComplete,
+			ProvisionPlan: echo.PlanComplete,
+			ProvisionApply: []*proto.Response{{
+				Type: &proto.Response_Apply{
+					Apply: &proto.ApplyComplete{
+						Resources: []*proto.Resource{{
+							Name: "vm",
+							Type: "aws_instance",
+							Metadata: []*proto.Resource_Metadata{{
+								Key:   "hourly_cost",
+								Value: "100",
+							}},
+						}},
+					},
+				},
+			}},
+		})
+	)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	// Given: the members of the "Everyone" group have exceeded its budget.
+	_ = dbgen.CostBudget(t, db, database.CostBudget{
+		GroupID:                owner.OrganizationID,
+		OrganizationID:         owner.OrganizationID,
+		MonthlyLimit:           0.000001,
+		NotifyThresholdPercent: 80,
+		StopThresholdPercent:   100,
+	})
+	ctx := testutil.Context(t, testutil.WaitLong)
+	require.NoError(t, db.UpsertWorkspaceCosts(ctx))
+
+	// When: the lifecycle executor ticks.
+	tickCh <- time.Now()
+	stats := <-statsCh
+
+	// Then: the running workspace is stopped and its owner is notified once.
+	require.Len(t, stats.Errors, 0)
+	require.Equal(t, database.WorkspaceTransitionStop, stats.Transitions[workspace.ID])
+	workspace = coderdtest.MustWorkspace(t, client, workspace.ID)
+	require.Equal(t, codersdk.BuildReasonAutostop, workspace.LatestBuild.Reason)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	sent := notifyEnq.Sent(notificationstest.WithTemplateID(notifications.TemplateCostBudgetThresholdReached))
+	require.Len(t, sent, 1)
+	require.Equal(t, owner.UserID, sent[0].UserID)
+	require.Equal(t, "Everyone", sent[0].Labels["group"])
+	require.Equal(t, "true", sent[0].Labels["stopping"])
+
+	// Stopped workspaces are left alone, and members aren't notified again.
+	tickCh <- time.Now()
+	close(tickCh)
+	stats = <-statsCh
+	require.Len(t, stats.Errors, 0)
+	require.Len(t, stats.Transitions, 0)
+	sent = notifyEnq.Sent(notificationstest.WithTemplateID(notifications.TemplateCostBudgetThresholdReached))
+	require.Len(t, sent, 1)
+}
+
 func mustProvisionWorkspace(t *testing.T, client *codersdk.Client, mut ...func(*codersdk.CreateWorkspaceRequest)) codersdk.Workspace {
 	t.Helper()
 	user := coderdtest.CreateFirstUser(t, client)
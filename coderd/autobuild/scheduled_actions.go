@@ -0,0 +1,244 @@
+package autobuild
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
+	"github.com/coder/coder/v2/coderd/schedule/cron"
+	"github.com/coder/coder/v2/coderd/wsbuilder"
+)
+
+// scheduledActionStep is the outcome of evaluating a due scheduled action.
+type scheduledActionStep int
+
+const (
+	// scheduledActionWait means a build of the workspace is in progress. The
+	// action is evaluated again on the next tick.
+	scheduledActionWait scheduledActionStep = iota
+	// scheduledActionContinue means the action built the workspace but needs
+	// another build once it completes, e.g. the start of a restart.
+	scheduledActionContinue
+	// scheduledActionDone means the action is complete for this occurrence of
+	// its schedule.
+	scheduledActionDone
+)
+
+// runScheduledActions builds workspaces for the user-defined scheduled actions
+// that are due. Actions run one at a time so that multiple actions of the
+// same workspace are applied in the order they were due.
+func (e *Executor) runScheduledActions(currentTick time.Time, stats *Stats) {
+	actions, err := e.db.GetWorkspaceScheduledActionsDue(e.ctx, currentTick)
+	if err != nil {
+		e.log.Error(e.ctx, "get due workspace scheduled actions", slog.Error(err))
+		return
+	}
+
+	for _, action := range actions {
+		log := e.log.With(
+			slog.F("workspace_id", action.WorkspaceID),
+			slog.F("scheduled_action_id", action.ID),
+			slog.F("action", action.Action),
+		)
+		build, err := e.runScheduledAction(log, action, currentTick)
+		if err == nil && build != nil {
+			if build.approvalRequired {
+				// The job is posted once the build is approved.
+				wsbuilder.NotifyApprovalRequested(e.ctx, log, e.db, e.notificationsEnqueuer, "autobuild", build.build.InitiatorID, build.workspace, build.build)
+			} else {
+				// The job must be posted after the transaction commits, see
+				// runOnce.
+				err = provisionerjobs.PostJob(e.ps, build.job)
+				if err != nil {
+					err = xerrors.Errorf("post provisioner job to pubsub: %w", err)
+				}
+			}
+		}
+		if err != nil {
+			if !xerrors.Is(err, context.Canceled) {
+				log.Error(e.ctx, "failed to run scheduled action", slog.Error(err))
+				stats.Errors[action.WorkspaceID] = err
+			}
+			continue
+		}
+		if build != nil {
+			stats.Transitions[action.WorkspaceID] = build.build.Transition
+		}
+	}
+}
+
+// scheduledActionBuild is a workspace build created by a scheduled action.
+type scheduledActionBuild struct {
+	workspace        database.Workspace
+	build            database.WorkspaceBuild
+	job              database.ProvisionerJob
+	approvalRequired bool
+}
+
+// runScheduledAction runs a due scheduled action, and returns the build it
+// created, if any.
+func (e *Executor) runScheduledAction(log slog.Logger, action database.WorkspaceScheduledAction, currentTick time.Time) (*scheduledActionBuild, error) {
+	var result *scheduledActionBuild
+	err := e.db.InTx(func(tx database.Store) error {
+		ok, err := tx.TryAcquireLock(e.ctx, database.GenLockID(fmt.Sprintf("lifecycle-executor:%s", action.WorkspaceID)))
+		if err != nil {
+			return xerrors.Errorf("try acquire lifecycle executor lock: %w", err)
+		}
+		if !ok {
+			log.Debug(e.ctx, "unable to acquire lock for workspace, skipping")
+			return nil
+		}
+
+		ws, err := tx.GetWorkspaceByID(e.ctx, action.WorkspaceID)
+		if err != nil {
+			return xerrors.Errorf("get workspace by id: %w", err)
+		}
+		user, err := tx.GetUserByID(e.ctx, ws.OwnerID)
+		if err != nil {
+			return xerrors.Errorf("get user by id: %w", err)
+		}
+		latestBuild, err := tx.GetLatestWorkspaceBuildByWorkspaceID(e.ctx, ws.ID)
+		if err != nil {
+			return xerrors.Errorf("get latest workspace build: %w", err)
+		}
+		latestJob, err := tx.GetProvisionerJobByID(e.ctx, latestBuild.JobID)
+		if err != nil {
+			return xerrors.Errorf("get latest provisioner job: %w", err)
+		}
+		tmpl, err := tx.GetTemplateByID(e.ctx, ws.TemplateID)
+		if err != nil {
+			return xerrors.Errorf("get template by ID: %w", err)
+		}
+		sched, err := cron.Weekly(action.Schedule)
+		if err != nil {
+			return xerrors.Errorf("parse schedule: %w", err)
+		}
+
+		nextTransition, activeVersion, step := getScheduledActionTransition(action, user, ws, latestBuild, latestJob, tmpl.ActiveVersionID)
+		if step == scheduledActionWait {
+			log.Debug(e.ctx, "workspace build in progress, waiting to run scheduled action")
+			return nil
+		}
+
+		if nextTransition != "" {
+			builder := wsbuilder.New(ws, nextTransition).
+				SetLastWorkspaceBuildInTx(&latestBuild).
+				SetLastWorkspaceBuildJobInTx(&latestJob).
+				Reason(database.BuildReasonScheduled)
+			accessControl := (*(e.accessControlStore.Load())).GetTemplateAccessControl(tmpl)
+			if activeVersion ||
+				(nextTransition == database.WorkspaceTransitionStart && useActiveVersion(accessControl, ws)) {
+				builder = builder.ActiveVersion()
+			}
+			build, job, _, err := builder.Build(e.ctx, tx, nil, audit.WorkspaceBuildBaggage{IP: "127.0.0.1"})
+			if err != nil {
+				return xerrors.Errorf("build workspace with transition %q: %w", nextTransition, err)
+			}
+			result = &scheduledActionBuild{
+				workspace:        ws,
+				build:            *build,
+				job:              *job,
+				approvalRequired: builder.ApprovalRequired(),
+			}
+			log.Info(e.ctx, "running scheduled action", slog.F("transition", nextTransition))
+		}
+
+		nextRunAt := action.NextRunAt
+		if step == scheduledActionDone {
+			nextRunAt = sched.Next(currentTick)
+		}
+		err = tx.UpdateWorkspaceScheduledActionRunByID(e.ctx, database.UpdateWorkspaceScheduledActionRunByIDParams{
+			ID:        action.ID,
+			UpdatedAt: dbtime.Now(),
+			NextRunAt: dbtime.Time(nextRunAt.UTC()),
+			LastRunAt: sql.NullTime{Time: dbtime.Time(currentTick.UTC()), Valid: true},
+		})
+		if err != nil {
+			return xerrors.Errorf("update workspace scheduled action run: %w", err)
+		}
+		return nil
+	}, &database.TxOptions{
+		Isolation:    sql.LevelRepeatableRead,
+		TxIdentifier: "lifecycle",
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// getScheduledActionTransition returns the transition a due scheduled action
+// should build the workspace with, if any, and whether the workspace should be
+// built with the active version of its template.
+func getScheduledActionTransition(
+	action database.WorkspaceScheduledAction,
+	user database.User,
+	ws database.Workspace,
+	latestBuild database.WorkspaceBuild,
+	latestJob database.ProvisionerJob,
+	activeVersionID uuid.UUID,
+) (database.WorkspaceTransition, bool, scheduledActionStep) {
+	switch latestJob.JobStatus {
+	case database.ProvisionerJobStatusPending,
+		database.ProvisionerJobStatusRunning,
+		database.ProvisionerJobStatusCanceling:
+		return "", false, scheduledActionWait
+	case database.ProvisionerJobStatusSucceeded:
+	default:
+		// Don't attempt to recover failed or canceled builds, that's up to
+		// the user or the failure TTL of the template.
+		return "", false, scheduledActionDone
+	}
+
+	// Suspended users and dormant workspaces are never built by schedules.
+	if user.Status != database.UserStatusActive || ws.DormantAt.Valid {
+		return "", false, scheduledActionDone
+	}
+
+	running := latestBuild.Transition == database.WorkspaceTransitionStart
+	stopped := latestBuild.Transition == database.WorkspaceTransitionStop
+
+	switch action.Action {
+	case database.WorkspaceScheduledActionTypeStart:
+		if stopped {
+			return database.WorkspaceTransitionStart, false, scheduledActionDone
+		}
+	case database.WorkspaceScheduledActionTypeStop:
+		if running {
+			return database.WorkspaceTransitionStop, false, scheduledActionDone
+		}
+	case database.WorkspaceScheduledActionTypeRestart:
+		if running {
+			return database.WorkspaceTransitionStop, false, scheduledActionContinue
+		}
+		// The restart stopped the workspace on a previous tick, so start it
+		// again. Workspaces that were already stopped are left alone.
+		restarting := action.LastRunAt.Valid && !action.LastRunAt.Time.Before(action.NextRunAt)
+		if stopped && restarting {
+			return database.WorkspaceTransitionStart, false, scheduledActionDone
+		}
+	case database.WorkspaceScheduledActionTypeUpdate:
+		if latestBuild.TemplateVersionID == activeVersionID {
+			break
+		}
+		// Keep the workspace in the state it is in. Updating a stopped
+		// workspace means the next start uses the active version.
+		if running {
+			return database.WorkspaceTransitionStart, true, scheduledActionDone
+		}
+		if stopped {
+			return database.WorkspaceTransitionStop, true, scheduledActionDone
+		}
+	}
+	return "", false, scheduledActionDone
+}
@@ -0,0 +1,333 @@
+// Package buildtrace exports the timeline of workspace builds as
+// OpenTelemetry traces, so slow builds can be analyzed in an existing tracing
+// backend.
+//
+// Each build is exported as a single trace once it has finished: the job's
+// time in the queue, every Terraform stage with a span per resource, and the
+// connection and startup scripts of every agent. Spans are created after the
+// fact with the timestamps recorded in the database.
+package buildtrace
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/tracing"
+)
+
+// Exporter exports workspace builds as traces. The hooks of a nil Exporter do
+// nothing, so they can be set up unconditionally.
+type Exporter struct {
+	db     database.Store
+	tracer trace.Tracer
+	log    slog.Logger
+}
+
+func New(db database.Store, tracerProvider trace.TracerProvider, log slog.Logger) *Exporter {
+	return &Exporter{
+		db:     db,
+		tracer: tracerProvider.Tracer(tracing.TracerName),
+		log:    log,
+	}
+}
+
+// WorkspaceBuildCompleted must be called when the provisioner job of a build
+// completes or fails. Builds without agents, such as stops, deletes and
+// failed builds, are exported immediately. Other builds are exported once
+// their agents have started.
+func (e *Exporter) WorkspaceBuildCompleted(ctx context.Context, build database.WorkspaceBuild) {
+	if e == nil {
+		return
+	}
+	//nolint:gocritic // Exporting builds is a system function.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	agents, err := e.buildAgents(ctx, build)
+	if err != nil {
+		e.log.Error(ctx, "get workspace build agents", slog.F("workspace_build_id", build.ID), slog.Error(err))
+		return
+	}
+	if len(agents) > 0 {
+		return
+	}
+	e.export(ctx, build)
+}
+
+// AgentStartupCompleted must be called when an agent has finished starting,
+// successfully or not. The build of the agent is exported once all of its
+// agents have finished starting.
+func (e *Exporter) AgentStartupCompleted(ctx context.Context, agent database.WorkspaceAgent) {
+	if e == nil {
+		return
+	}
+	//nolint:gocritic // Exporting builds is a system function.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	resource, err := e.db.GetWorkspaceResourceByID(ctx, agent.ResourceID)
+	if err != nil {
+		e.log.Error(ctx, "get workspace resource of agent", slog.F("workspace_agent_id", agent.ID), slog.Error(err))
+		return
+	}
+	build, err := e.db.GetWorkspaceBuildByJobID(ctx, resource.JobID)
+	if err != nil {
+		e.log.Error(ctx, "get workspace build of agent", slog.F("workspace_agent_id", agent.ID), slog.Error(err))
+		return
+	}
+	agents, err := e.buildAgents(ctx, build)
+	if err != nil {
+		e.log.Error(ctx, "get workspace build agents", slog.F("workspace_build_id", build.ID), slog.Error(err))
+		return
+	}
+	for _, buildAgent := range agents {
+		if !startupCompleted(buildAgent.LifecycleState) {
+			return
+		}
+	}
+	e.export(ctx, build)
+}
+
+func (e *Exporter) export(ctx context.Context, build database.WorkspaceBuild) {
+	err := e.Export(ctx, build)
+	if err != nil {
+		e.log.Error(ctx, "export workspace build trace", slog.F("workspace_build_id", build.ID), slog.Error(err))
+		return
+	}
+	e.log.Debug(ctx, "exported workspace build trace", slog.F("workspace_build_id", build.ID))
+}
+
+// Export exports the timeline of a build as a trace. The caller must be
+// authorized to read the build, its job and its agents.
+func (e *Exporter) Export(ctx context.Context, build database.WorkspaceBuild) error {
+	workspace, err := e.db.GetWorkspaceByID(ctx, build.WorkspaceID)
+	if err != nil {
+		return xerrors.Errorf("get workspace: %w", err)
+	}
+	job, err := e.db.GetProvisionerJobByID(ctx, build.JobID)
+	if err != nil {
+		return xerrors.Errorf("get provisioner job: %w", err)
+	}
+	provisionerTimings, err := e.db.GetProvisionerJobTimingsByJobID(ctx, build.JobID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return xerrors.Errorf("get provisioner job timings: %w", err)
+	}
+	agents, err := e.buildAgents(ctx, build)
+	if err != nil {
+		return err
+	}
+	scriptTimings, err := e.db.GetWorkspaceAgentScriptTimingsByBuildID(ctx, build.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return xerrors.Errorf("get workspace agent script timings: %w", err)
+	}
+
+	end := buildEnd(job, provisionerTimings, agents, scriptTimings)
+	ctx, root := e.tracer.Start(ctx, "workspace build",
+		trace.WithNewRoot(),
+		trace.WithTimestamp(job.CreatedAt),
+		trace.WithAttributes(
+			attribute.String("workspace_id", workspace.ID.String()),
+			attribute.String("workspace_name", workspace.Name),
+			attribute.String("workspace_owner", workspace.OwnerUsername),
+			attribute.String("organization_name", workspace.OrganizationName),
+			attribute.String("template_name", workspace.TemplateName),
+			attribute.String("template_version_id", build.TemplateVersionID.String()),
+			attribute.String("workspace_build_id", build.ID.String()),
+			attribute.Int64("workspace_build_number", int64(build.BuildNumber)),
+			attribute.String("workspace_transition", string(build.Transition)),
+			attribute.String("build_reason", string(build.Reason)),
+			attribute.String("job_id", job.ID.String()),
+		),
+	)
+	if job.Error.Valid {
+		root.SetStatus(codes.Error, job.Error.String)
+	}
+
+	if job.StartedAt.Valid {
+		_, queue := e.tracer.Start(ctx, "queue", trace.WithTimestamp(job.CreatedAt))
+		queue.End(trace.WithTimestamp(job.StartedAt.Time))
+		e.exportProvisioning(ctx, job, provisionerTimings)
+	}
+	for _, agent := range agents {
+		e.exportAgent(ctx, agent, scriptTimings)
+	}
+	root.End(trace.WithTimestamp(end))
+	return nil
+}
+
+// exportProvisioning adds a span for the provisioner job with a span per
+// stage, and a span per resource within each stage.
+func (e *Exporter) exportProvisioning(ctx context.Context, job database.ProvisionerJob, timings []database.ProvisionerJobTiming) {
+	end := job.CompletedAt.Time
+	if !job.CompletedAt.Valid {
+		end = job.UpdatedAt
+	}
+	ctx, provision := e.tracer.Start(ctx, "provision",
+		trace.WithTimestamp(job.StartedAt.Time),
+		trace.WithAttributes(attribute.String("provisioner", string(job.Provisioner))),
+	)
+	if job.Error.Valid {
+		provision.SetStatus(codes.Error, job.Error.String)
+	}
+
+	// Stages are not stored themselves, so they span from the first to the
+	// last timing recorded in them.
+	var stages []database.ProvisionerJobTimingStage
+	byStage := map[database.ProvisionerJobTimingStage][]database.ProvisionerJobTiming{}
+	for _, timing := range timings {
+		if _, ok := byStage[timing.Stage]; !ok {
+			stages = append(stages, timing.Stage)
+		}
+		byStage[timing.Stage] = append(byStage[timing.Stage], timing)
+	}
+	for _, stage := range stages {
+		stageTimings := byStage[stage]
+		stageStart, stageEnd := stageTimings[0].StartedAt, stageTimings[0].EndedAt
+		for _, timing := range stageTimings[1:] {
+			stageStart = earliest(stageStart, timing.StartedAt)
+			stageEnd = latest(stageEnd, timing.EndedAt)
+		}
+		stageCtx, stageSpan := e.tracer.Start(ctx, fmt.Sprintf("terraform %s", stage), trace.WithTimestamp(stageStart))
+		for _, timing := range stageTimings {
+			_, span := e.tracer.Start(stageCtx, fmt.Sprintf("%s %s", timing.Action, timing.Resource),
+				trace.WithTimestamp(timing.StartedAt),
+				trace.WithAttributes(
+					attribute.String("source", timing.Source),
+					attribute.String("action", timing.Action),
+					attribute.String("resource", timing.Resource),
+				),
+			)
+			span.End(trace.WithTimestamp(timing.EndedAt))
+		}
+		stageSpan.End(trace.WithTimestamp(stageEnd))
+	}
+	provision.End(trace.WithTimestamp(end))
+}
+
+// exportAgent adds a span for an agent with spans for its connection and its
+// startup, and a span per startup script.
+func (e *Exporter) exportAgent(ctx context.Context, agent database.WorkspaceAgent, scriptTimings []database.GetWorkspaceAgentScriptTimingsByBuildIDRow) {
+	end := agentEnd(agent, scriptTimings)
+	ctx, agentSpan := e.tracer.Start(ctx, fmt.Sprintf("agent %s", agent.Name),
+		trace.WithTimestamp(agent.CreatedAt),
+		trace.WithAttributes(
+			attribute.String("workspace_agent_id", agent.ID.String()),
+			attribute.String("workspace_agent_name", agent.Name),
+			attribute.String("lifecycle_state", string(agent.LifecycleState)),
+		),
+	)
+	if agent.LifecycleState == database.WorkspaceAgentLifecycleStateStartError ||
+		agent.LifecycleState == database.WorkspaceAgentLifecycleStateStartTimeout {
+		agentSpan.SetStatus(codes.Error, string(agent.LifecycleState))
+	}
+
+	if agent.FirstConnectedAt.Valid {
+		_, connect := e.tracer.Start(ctx, "connect", trace.WithTimestamp(agent.CreatedAt))
+		connect.End(trace.WithTimestamp(agent.FirstConnectedAt.Time))
+	}
+	if agent.StartedAt.Valid && agent.ReadyAt.Valid {
+		ctx, startup := e.tracer.Start(ctx, "startup", trace.WithTimestamp(agent.StartedAt.Time))
+		for _, timing := range scriptTimings {
+			if timing.WorkspaceAgentID != agent.ID {
+				continue
+			}
+			_, span := e.tracer.Start(ctx, fmt.Sprintf("script %s", timing.DisplayName),
+				trace.WithTimestamp(timing.StartedAt),
+				trace.WithAttributes(
+					attribute.String("script_id", timing.ScriptID.String()),
+					attribute.String("stage", string(timing.Stage)),
+					attribute.String("status", string(timing.Status)),
+					attribute.Int64("exit_code", int64(timing.ExitCode)),
+				),
+			)
+			if timing.Status != database.WorkspaceAgentScriptTimingStatusOk {
+				span.SetStatus(codes.Error, string(timing.Status))
+			}
+			span.End(trace.WithTimestamp(timing.EndedAt))
+		}
+		startup.End(trace.WithTimestamp(agent.ReadyAt.Time))
+	}
+	agentSpan.End(trace.WithTimestamp(end))
+}
+
+func (e *Exporter) buildAgents(ctx context.Context, build database.WorkspaceBuild) ([]database.WorkspaceAgent, error) {
+	resources, err := e.db.GetWorkspaceResourcesByJobID(ctx, build.JobID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, xerrors.Errorf("get workspace resources: %w", err)
+	}
+	if len(resources) == 0 {
+		return nil, nil
+	}
+	resourceIDs := make([]uuid.UUID, 0, len(resources))
+	for _, resource := range resources {
+		resourceIDs = append(resourceIDs, resource.ID)
+	}
+	agents, err := e.db.GetWorkspaceAgentsByResourceIDs(ctx, resourceIDs)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, xerrors.Errorf("get workspace agents: %w", err)
+	}
+	return agents, nil
+}
+
+// startupCompleted returns whether an agent in the given state has finished
+// starting.
+func startupCompleted(state database.WorkspaceAgentLifecycleState) bool {
+	switch state {
+	case database.WorkspaceAgentLifecycleStateCreated, database.WorkspaceAgentLifecycleStateStarting:
+		return false
+	default:
+		return true
+	}
+}
+
+func buildEnd(job database.ProvisionerJob, provisionerTimings []database.ProvisionerJobTiming, agents []database.WorkspaceAgent, scriptTimings []database.GetWorkspaceAgentScriptTimingsByBuildIDRow) time.Time {
+	end := job.UpdatedAt
+	if job.CompletedAt.Valid {
+		end = latest(end, job.CompletedAt.Time)
+	}
+	for _, timing := range provisionerTimings {
+		end = latest(end, timing.EndedAt)
+	}
+	for _, agent := range agents {
+		end = latest(end, agentEnd(agent, scriptTimings))
+	}
+	return end
+}
+
+func agentEnd(agent database.WorkspaceAgent, scriptTimings []database.GetWorkspaceAgentScriptTimingsByBuildIDRow) time.Time {
+	end := agent.CreatedAt
+	if agent.FirstConnectedAt.Valid {
+		end = latest(end, agent.FirstConnectedAt.Time)
+	}
+	if agent.ReadyAt.Valid {
+		end = latest(end, agent.ReadyAt.Time)
+	}
+	for _, timing := range scriptTimings {
+		if timing.WorkspaceAgentID == agent.ID {
+			end = latest(end, timing.EndedAt)
+		}
+	}
+	return end
+}
+
+func earliest(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
+func latest(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,147 @@
+package buildtrace_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/coder/coder/v2/coderd/buildtrace"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbmem"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestExporter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("WaitsForAgents", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db := dbmem.New()
+		recorder, exporter := newExporter(t, db)
+		build := workspaceBuild(t, db)
+		dbgen.ProvisionerJobTimings(t, db, build, 1)
+		resource := dbgen.WorkspaceResource(t, db, database.WorkspaceResource{JobID: build.JobID})
+		agent := dbgen.WorkspaceAgent(t, db, database.WorkspaceAgent{ResourceID: resource.ID, Name: "main"})
+		script := dbgen.WorkspaceAgentScript(t, db, database.WorkspaceAgentScript{WorkspaceAgentID: agent.ID, DisplayName: "install"})
+		dbgen.WorkspaceAgentScriptTimings(t, db, script, 1)
+
+		// The agent hasn't started yet.
+		exporter.WorkspaceBuildCompleted(ctx, build)
+		require.Empty(t, recorder.Ended())
+
+		now := dbtime.Now()
+		err := db.UpdateWorkspaceAgentConnectionByID(ctx, database.UpdateWorkspaceAgentConnectionByIDParams{
+			ID:               agent.ID,
+			FirstConnectedAt: sql.NullTime{Time: now, Valid: true},
+			LastConnectedAt:  sql.NullTime{Time: now, Valid: true},
+			UpdatedAt:        now,
+		})
+		require.NoError(t, err)
+		err = db.UpdateWorkspaceAgentLifecycleStateByID(ctx, database.UpdateWorkspaceAgentLifecycleStateByIDParams{
+			ID:             agent.ID,
+			LifecycleState: database.WorkspaceAgentLifecycleStateReady,
+			StartedAt:      sql.NullTime{Time: now, Valid: true},
+			ReadyAt:        sql.NullTime{Time: now.Add(time.Second), Valid: true},
+		})
+		require.NoError(t, err)
+		agent, err = db.GetWorkspaceAgentByID(ctx, agent.ID)
+		require.NoError(t, err)
+		exporter.AgentStartupCompleted(ctx, agent)
+
+		spans := spansByName(t, recorder.Ended())
+		root := spans["workspace build"]
+		require.False(t, root.Parent().IsValid(), "the build must be the root of a new trace")
+		requireParent(t, root, spans["queue"])
+		requireParent(t, root, spans["provision"])
+		requireParent(t, spans["provision"], spans["terraform init"])
+		requireParent(t, spans["terraform init"], spans["action resource"])
+		requireParent(t, root, spans["agent main"])
+		requireParent(t, spans["agent main"], spans["connect"])
+		requireParent(t, spans["agent main"], spans["startup"])
+		requireParent(t, spans["startup"], spans["script install"])
+		require.Equal(t, now.Add(time.Second), spans["startup"].EndTime())
+	})
+
+	t.Run("NoAgents", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db := dbmem.New()
+		recorder, exporter := newExporter(t, db)
+		build := workspaceBuild(t, db)
+
+		exporter.WorkspaceBuildCompleted(ctx, build)
+		spans := spansByName(t, recorder.Ended())
+		require.Len(t, spans, 3)
+		require.Contains(t, spans, "workspace build")
+		require.Contains(t, spans, "queue")
+		require.Contains(t, spans, "provision")
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		var exporter *buildtrace.Exporter
+		exporter.WorkspaceBuildCompleted(ctx, database.WorkspaceBuild{})
+		exporter.AgentStartupCompleted(ctx, database.WorkspaceAgent{})
+	})
+}
+
+func newExporter(t *testing.T, db database.Store) (*tracetest.SpanRecorder, *buildtrace.Exporter) {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	return recorder, buildtrace.New(db, tracerProvider, testutil.Logger(t))
+}
+
+func workspaceBuild(t *testing.T, db database.Store) database.WorkspaceBuild {
+	t.Helper()
+	org := dbgen.Organization(t, db, database.Organization{})
+	user := dbgen.User(t, db, database.User{})
+	template := dbgen.Template(t, db, database.Template{OrganizationID: org.ID, CreatedBy: user.ID})
+	workspace := dbgen.Workspace(t, db, database.WorkspaceTable{
+		OwnerID:        user.ID,
+		OrganizationID: org.ID,
+		TemplateID:     template.ID,
+	})
+	jobID := uuid.New()
+	job := dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+		ID:             jobID,
+		CreatedAt:      dbtime.Now().Add(-time.Minute),
+		OrganizationID: org.ID,
+		InitiatorID:    user.ID,
+		Tags:           database.StringMap{jobID.String(): "true"},
+		StartedAt:      sql.NullTime{Time: dbtime.Now().Add(-30 * time.Second), Valid: true},
+		CompletedAt:    sql.NullTime{Time: dbtime.Now(), Valid: true},
+	})
+	return dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+		WorkspaceID: workspace.ID,
+		InitiatorID: user.ID,
+		JobID:       job.ID,
+	})
+}
+
+func spansByName(t *testing.T, spans []sdktrace.ReadOnlySpan) map[string]sdktrace.ReadOnlySpan {
+	t.Helper()
+	byName := make(map[string]sdktrace.ReadOnlySpan, len(spans))
+	for _, span := range spans {
+		require.NotContains(t, byName, span.Name(), "duplicate span")
+		byName[span.Name()] = span
+	}
+	return byName
+}
+
+func requireParent(t *testing.T, parent, child sdktrace.ReadOnlySpan) {
+	t.Helper()
+	require.NotNil(t, parent)
+	require.NotNil(t, child)
+	require.Equal(t, parent.SpanContext().TraceID(), child.SpanContext().TraceID())
+	require.Equal(t, parent.SpanContext().SpanID(), child.Parent().SpanID(), "%q must be a child of %q", child.Name(), parent.Name())
+}
@@ -52,6 +52,7 @@ import (
 	"github.com/coder/coder/v2/coderd/appearance"
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/awsidentity"
+	"github.com/coder/coder/v2/coderd/buildtrace"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbrollup"
@@ -71,6 +72,7 @@ import (
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/rbac/policy"
 	"github.com/coder/coder/v2/coderd/rbac/rolestore"
+	"github.com/coder/coder/v2/coderd/rollouts"
 	"github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/telemetry"
 	"github.com/coder/coder/v2/coderd/tracing"
@@ -255,7 +257,10 @@ type Options struct {
 	AppSigningKeyCache    cryptokeys.SigningKeycache
 	AppEncryptionKeyCache cryptokeys.EncryptionKeycache
 	OIDCConvertKeyCache   cryptokeys.SigningKeycache
-	Clock                 quartz.Clock
+	// WorkloadIdentityKeyCache signs the workload identity tokens issued to
+	// workspace agents, and publishes their public keys.
+	WorkloadIdentityKeyCache cryptokeys.PublicSigningKeycache
+	Clock                    quartz.Clock
 }
 
 // @title Coder API
@@ -483,6 +488,17 @@ func New(options *Options) *API {
 		}
 	}
 
+	if options.WorkloadIdentityKeyCache == nil {
+		options.WorkloadIdentityKeyCache, err = cryptokeys.NewPublicSigningCache(ctx,
+			options.Logger.Named("workload_identity_keycache"),
+			fetcher,
+			codersdk.CryptoKeyFeatureWorkloadIdentity,
+		)
+		if err != nil {
+			options.Logger.Fatal(ctx, "failed to properly instantiate workload identity signing cache", slog.Error(err))
+		}
+	}
+
 	if options.AppEncryptionKeyCache == nil {
 		options.AppEncryptionKeyCache, err = cryptokeys.NewEncryptionCache(ctx,
 			options.Logger,
@@ -557,6 +573,10 @@ func New(options *Options) *API {
 			options.Logger.Named("acquirer"),
 			options.Database,
 			options.Pubsub,
+			provisionerdserver.ConcurrencyLimits(
+				int32(options.DeploymentValues.Provisioner.MaxJobsPerOrganization.Value()),
+				int32(options.DeploymentValues.Provisioner.MaxJobsPerUser.Value()),
+			),
 		),
 		dbRolluper: options.DatabaseRolluper,
 	}
@@ -702,6 +722,10 @@ func New(options *Options) *API {
 		UpdateAgentMetricsFn:  options.UpdateAgentMetrics,
 		AppStatBatchSize:      workspaceapps.DefaultStatsDBReporterBatchSize,
 	})
+	if options.DeploymentValues.Trace.WorkspaceBuilds.Value() {
+		api.BuildTraceExporter = buildtrace.New(options.Database, options.TracerProvider, options.Logger.Named("buildtrace"))
+	}
+	api.TemplateRollouts = rollouts.New(options.Database, options.NotificationsEnqueuer, options.Logger.Named("rollouts"))
 	workspaceAppsLogger := options.Logger.Named("workspaceapps")
 	if options.WorkspaceAppsStatsCollectorOptions.Logger == nil {
 		named := workspaceAppsLogger.Named("stats_collector")
@@ -864,29 +888,39 @@ func New(options *Options) *API {
 	// for an external application to use Coder as an OAuth2 provider, not for
 	// logging into Coder with an external OAuth2 provider.
 	r.Route("/oauth2", func(r chi.Router) {
-		r.Use(
-			api.oAuth2ProviderMiddleware,
-			// Fetch the app as system because in the /tokens route there will be no
-			// authenticated user.
-			httpmw.AsAuthzSystem(httpmw.ExtractOAuth2ProviderApp(options.Database)),
-		)
-		r.Route("/authorize", func(r chi.Router) {
+		r.Use(api.oAuth2ProviderMiddleware)
+		// The user enters the code from their device on this page, so the app is
+		// found through the code rather than a client ID.
+		r.Route("/device/verify", func(r chi.Router) {
 			r.Use(apiKeyMiddlewareRedirect)
-			r.Get("/", api.getOAuth2ProviderAppAuthorize())
+			r.Get("/", api.getOAuth2ProviderDeviceVerify())
 		})
-		r.Route("/tokens", func(r chi.Router) {
-			r.Group(func(r chi.Router) {
-				r.Use(apiKeyMiddleware)
-				// DELETE on /tokens is not part of the OAuth2 spec.  It is our own
-				// route used to revoke permissions from an application.  It is here for
-				// parity with POST on /tokens.
-				r.Delete("/", api.deleteOAuth2ProviderAppTokens())
+		r.Group(func(r chi.Router) {
+			// Fetch the app as system because in the /tokens and /device routes
+			// there will be no authenticated user.
+			r.Use(httpmw.AsAuthzSystem(httpmw.ExtractOAuth2ProviderApp(options.Database)))
+			r.Route("/authorize", func(r chi.Router) {
+				r.Use(apiKeyMiddlewareRedirect)
+				r.Get("/", api.getOAuth2ProviderAppAuthorize())
 			})
-			// The POST /tokens endpoint will be called from an unauthorized client so
-			// we cannot require an API key.
-			r.Post("/", api.postOAuth2ProviderAppToken())
+			r.Route("/tokens", func(r chi.Router) {
+				r.Group(func(r chi.Router) {
+					r.Use(apiKeyMiddleware)
+					// DELETE on /tokens is not part of the OAuth2 spec.  It is our own
+					// route used to revoke permissions from an application.  It is here for
+					// parity with POST on /tokens.
+					r.Delete("/", api.deleteOAuth2ProviderAppTokens())
+				})
+				// The POST /tokens endpoint will be called from an unauthorized client so
+				// we cannot require an API key.
+				r.Post("/", api.postOAuth2ProviderAppToken())
+			})
+			// Devices are not authenticated either.
+			r.Post("/device", api.postOAuth2ProviderDeviceAuthorization())
 		})
 	})
+	r.With(api.oAuth2ProviderMiddleware).
+		Get("/.well-known/oauth-authorization-server", api.getOAuth2AuthorizationServerMetadata())
 
 	r.Route("/api/v2", func(r chi.Router) {
 		api.APIHandler = r
@@ -917,6 +951,7 @@ func New(options *Options) *API {
 			r.Get("/config", api.deploymentValues)
 			r.Get("/stats", api.deploymentStats)
 			r.Get("/ssh", api.sshConfig)
+			r.Get("/purge-report", api.deploymentPurgeReport)
 		})
 		r.Route("/experiments", func(r chi.Router) {
 			r.Use(apiKeyMiddleware)
@@ -924,6 +959,12 @@ func New(options *Options) *API {
 			r.Get("/", api.handleExperimentsGet)
 		})
 		r.Get("/updatecheck", api.updateCheck)
+		// Relying parties fetch the keys of the workload identity issuer
+		// without authenticating.
+		r.Route("/workloadidentity", func(r chi.Router) {
+			r.Get("/.well-known/openid-configuration", api.workloadIdentityProviderMetadata())
+			r.Get("/jwks", api.workloadIdentityJWKS())
+		})
 		r.Route("/audit", func(r chi.Router) {
 			r.Use(
 				apiKeyMiddleware,
@@ -1010,6 +1051,12 @@ func New(options *Options) *API {
 				r.Route("/provisionerdaemons", func(r chi.Router) {
 					r.Get("/", api.provisionerDaemons)
 				})
+				r.Route("/secrets", func(r chi.Router) {
+					r.Get("/", api.organizationSecrets)
+					r.Post("/", api.postOrganizationSecret)
+					r.Patch("/{secret}", api.patchOrganizationSecret)
+					r.Delete("/{secret}", api.deleteOrganizationSecret)
+				})
 			})
 		})
 		r.Route("/templates", func(r chi.Router) {
@@ -1026,6 +1073,22 @@ func New(options *Options) *API {
 				r.Get("/", api.template)
 				r.Delete("/", api.deleteTemplate)
 				r.Patch("/", api.patchTemplateMeta)
+				r.Route("/build-approvals", func(r chi.Router) {
+					r.Get("/", api.templateBuildApprovals)
+					r.Post("/{workspacebuild}", api.postTemplateBuildApproval)
+				})
+				r.Route("/rollouts", func(r chi.Router) {
+					r.Get("/", api.templateRollouts)
+					r.Post("/", api.postTemplateRollout)
+					r.Get("/{rollout}", api.templateRollout)
+					r.Patch("/{rollout}", api.patchTemplateRollout)
+				})
+				r.Route("/secrets", func(r chi.Router) {
+					r.Get("/", api.templateSecrets)
+					r.Post("/", api.postTemplateSecret)
+					r.Patch("/{secret}", api.patchTemplateSecret)
+					r.Delete("/{secret}", api.deleteTemplateSecret)
+				})
 				r.Route("/versions", func(r chi.Router) {
 					r.Post("/archive", api.postArchiveTemplateVersions)
 					r.Get("/", api.templateVersionsByTemplate)
@@ -1057,6 +1120,7 @@ func New(options *Options) *API {
 				r.Post("/", api.postTemplateVersionDryRun)
 				r.Get("/{jobID}", api.templateVersionDryRun)
 				r.Get("/{jobID}/resources", api.templateVersionDryRunResources)
+				r.Get("/{jobID}/resource-changes", api.templateVersionDryRunResourceChanges)
 				r.Get("/{jobID}/logs", api.templateVersionDryRunLogs)
 				r.Get("/{jobID}/matched-provisioners", api.templateVersionDryRunMatchedProvisioners)
 				r.Patch("/{jobID}/cancel", api.patchTemplateVersionDryRunCancel)
@@ -1152,6 +1216,12 @@ func New(options *Options) *API {
 					})
 					r.Get("/gitsshkey", api.gitSSHKey)
 					r.Put("/gitsshkey", api.regenerateGitSSHKey)
+					r.Route("/secrets", func(r chi.Router) {
+						r.Get("/", api.userSecrets)
+						r.Post("/", api.postUserSecret)
+						r.Patch("/{secret}", api.patchUserSecret)
+						r.Delete("/{secret}", api.deleteUserSecret)
+					})
 					r.Route("/notifications", func(r chi.Router) {
 						r.Route("/preferences", func(r chi.Router) {
 							r.Get("/", api.userNotificationPreferences)
@@ -1204,6 +1274,15 @@ func New(options *Options) *API {
 				r.Get("/startup-logs", api.workspaceAgentLogsDeprecated)
 				r.Get("/logs", api.workspaceAgentLogs)
 				r.Get("/listening-ports", api.workspaceAgentListeningPorts)
+				r.Get("/process-groups", api.workspaceAgentProcessGroups)
+				r.Route("/files", func(r chi.Router) {
+					r.Get("/list", api.workspaceAgentListFiles)
+					r.Get("/stat", api.workspaceAgentStatFile)
+					r.Get("/download", api.workspaceAgentDownloadFile)
+					r.Post("/upload", api.workspaceAgentUploadFile)
+					r.Post("/mkdir", api.workspaceAgentMakeDirectory)
+					r.Delete("/", api.workspaceAgentDeleteFile)
+				})
 				r.Get("/connection", api.workspaceAgentConnection)
 				r.Get("/coordinate", api.workspaceAgentClientCoordinate)
 
@@ -1245,6 +1324,20 @@ func New(options *Options) *API {
 					r.Delete("/", api.deleteWorkspaceAgentPortShare)
 				})
 				r.Get("/timings", api.workspaceTimings)
+				r.Route("/snapshots", func(r chi.Router) {
+					r.Get("/", api.workspaceSnapshots)
+					r.Post("/", api.postWorkspaceSnapshot)
+					r.Post("/{snapshot}/restore", api.postWorkspaceSnapshotRestore)
+				})
+				r.Route("/session-recordings", func(r chi.Router) {
+					r.Get("/", api.workspaceSessionRecordings)
+					r.Get("/{recording}", api.workspaceSessionRecording)
+				})
+				r.Route("/scheduled-actions", func(r chi.Router) {
+					r.Get("/", api.workspaceScheduledActions)
+					r.Post("/", api.postWorkspaceScheduledAction)
+					r.Delete("/{scheduledaction}", api.deleteWorkspaceScheduledAction)
+				})
 			})
 		})
 		r.Route("/workspacebuilds/{workspacebuild}", func(r chi.Router) {
@@ -1254,10 +1347,12 @@ func New(options *Options) *API {
 				httpmw.ExtractWorkspaceParam(options.Database),
 			)
 			r.Get("/", api.workspaceBuild)
+			r.Get("/approval", api.workspaceBuildApproval)
 			r.Patch("/cancel", api.patchCancelWorkspaceBuild)
 			r.Get("/logs", api.workspaceBuildLogs)
 			r.Get("/parameters", api.workspaceBuildParameters)
 			r.Get("/resources", api.workspaceBuildResourcesDeprecated)
+			r.Get("/resource-changes", api.workspaceBuildResourceChanges)
 			r.Get("/state", api.workspaceBuildState)
 			r.Get("/timings", api.workspaceBuildTimings)
 		})
@@ -1287,6 +1382,8 @@ func New(options *Options) *API {
 			r.Get("/user-status-counts", api.insightsUserStatusCounts)
 			r.Get("/user-latency", api.insightsUserLatency)
 			r.Get("/templates", api.insightsTemplates)
+			r.Get("/costs", api.insightsCosts)
+			r.Get("/network", api.insightsNetwork)
 		})
 		r.Route("/debug", func(r chi.Router) {
 			r.Use(
@@ -1497,6 +1594,12 @@ type API struct {
 	healthCheckCache atomic.Pointer[healthsdk.HealthcheckReport]
 
 	statsReporter *workspacestats.Reporter
+	// BuildTraceExporter exports workspace builds as traces. It is nil unless
+	// enabled with --trace-workspace-builds.
+	BuildTraceExporter *buildtrace.Exporter
+	// TemplateRollouts evaluates staged rollouts of template versions as
+	// their builds complete.
+	TemplateRollouts *rollouts.Manager
 
 	Acquirer *provisionerdserver.Acquirer
 	// dbRolluper rolls up template usage stats from raw agent and app
@@ -1552,6 +1655,7 @@ func (api *API) Close() error {
 	_ = api.OIDCConvertKeyCache.Close()
 	_ = api.AppSigningKeyCache.Close()
 	_ = api.AppEncryptionKeyCache.Close()
+	_ = api.WorkloadIdentityKeyCache.Close()
 	_ = api.UpdatesProvider.Close()
 	return nil
 }
@@ -1652,9 +1756,10 @@ func (api *API) CreateInMemoryTaggedProvisionerDaemon(dialCtx context.Context, n
 		api.UserQuietHoursScheduleStore,
 		api.DeploymentValues,
 		provisionerdserver.Options{
-			OIDCConfig:          api.OIDCConfig,
-			ExternalAuthConfigs: api.ExternalAuthConfigs,
-			Clock:               api.Clock,
+			OIDCConfig:                api.OIDCConfig,
+			ExternalAuthConfigs:       api.ExternalAuthConfigs,
+			Clock:                     api.Clock,
+			WorkspaceBuildCompletedFn: api.WorkspaceBuildCompleted,
 		},
 		api.NotificationsEnqueuer,
 	)
@@ -310,7 +310,9 @@ func assertSecurityDefined(t *testing.T, comment SwaggerComment) {
 		comment.router == "/" ||
 		comment.router == "/users/login" ||
 		comment.router == "/users/otp/request" ||
-		comment.router == "/users/otp/change-password" {
+		comment.router == "/users/otp/change-password" ||
+		comment.router == "/workloadidentity/.well-known/openid-configuration" ||
+		comment.router == "/workloadidentity/jwks" {
 		return // endpoints do not require authorization
 	}
 	assert.Containsf(t, authorizedSecurityTags, comment.security, "@Security must be either of these options: %v", authorizedSecurityTags)
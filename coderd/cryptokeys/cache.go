@@ -1,10 +1,14 @@
 package cryptokeys
 
 import (
+	"cmp"
 	"context"
+	"crypto/ecdsa"
+	"crypto/x509"
 	"encoding/hex"
 	"fmt"
 	"io"
+	"slices"
 	"strconv"
 	"sync"
 	"time"
@@ -55,6 +59,16 @@ type SigningKeycache interface {
 	io.Closer
 }
 
+// PublicSigningKeycache is a SigningKeycache for asymmetric keys, whose public
+// keys can be published so third parties can verify the signatures.
+type PublicSigningKeycache interface {
+	SigningKeycache
+	// VerifyingKeys returns all keys that are valid for verifying, ordered by
+	// sequence number. Like VerifyingKey, it includes keys prior to their start
+	// time.
+	VerifyingKeys(ctx context.Context) ([]codersdk.CryptoKey, error)
+}
+
 const (
 	// latestSequence is a special sequence number that represents the latest key.
 	latestSequence = -1
@@ -113,6 +127,19 @@ func NewSigningCache(ctx context.Context, logger slog.Logger, fetcher Fetcher,
 	return newCache(ctx, logger, fetcher, feature, opts...), nil
 }
 
+// NewPublicSigningCache instantiates a cache for an asymmetric signing feature.
+// Close should be called to release resources associated with its internal
+// timer.
+func NewPublicSigningCache(ctx context.Context, logger slog.Logger, fetcher Fetcher,
+	feature codersdk.CryptoKeyFeature, opts ...func(*cache),
+) (PublicSigningKeycache, error) {
+	if !isAsymmetricKeyFeature(feature) {
+		return nil, xerrors.Errorf("invalid feature: %s", feature)
+	}
+	logger = logger.Named(fmt.Sprintf("%s_signing_keycache", feature))
+	return newCache(ctx, logger, fetcher, feature, opts...), nil
+}
+
 func NewEncryptionCache(ctx context.Context, logger slog.Logger, fetcher Fetcher,
 	feature codersdk.CryptoKeyFeature, opts ...func(*cache),
 ) (EncryptionKeycache, error) {
@@ -184,7 +211,19 @@ func (c *cache) SigningKey(ctx context.Context) (string, interface{}, error) {
 
 	//nolint:gocritic // cache can only read crypto keys.
 	ctx = dbauthz.AsKeyReader(ctx)
-	return c.cryptoKey(ctx, latestSequence)
+	id, secret, err := c.cryptoKey(ctx, latestSequence)
+	if err != nil {
+		return "", nil, err
+	}
+	if !isAsymmetricKeyFeature(c.feature) {
+		return id, secret, nil
+	}
+
+	key, err := parseECDSAKey(secret)
+	if err != nil {
+		return "", nil, xerrors.Errorf("parse key: %w", err)
+	}
+	return id, key, nil
 }
 
 func (c *cache) VerifyingKey(ctx context.Context, id string) (interface{}, error) {
@@ -202,8 +241,67 @@ func (c *cache) VerifyingKey(ctx context.Context, id string) (interface{}, error
 	if err != nil {
 		return nil, xerrors.Errorf("crypto key: %w", err)
 	}
+	if !isAsymmetricKeyFeature(c.feature) {
+		return secret, nil
+	}
 
-	return secret, nil
+	key, err := parseECDSAKey(secret)
+	if err != nil {
+		return nil, xerrors.Errorf("parse key: %w", err)
+	}
+	return key.Public(), nil
+}
+
+func (c *cache) VerifyingKeys(ctx context.Context) ([]codersdk.CryptoKey, error) {
+	// Only the public keys of asymmetric keys can be published.
+	if !isAsymmetricKeyFeature(c.feature) {
+		return nil, ErrInvalidFeature
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for c.fetching && !c.closed {
+		c.cond.Wait()
+	}
+	if c.closed {
+		return nil, ErrClosed
+	}
+
+	// The keys are refreshed in the background, so they are only fetched here
+	// if there are none, e.g. because the initial fetch failed.
+	if len(c.keys) == 0 {
+		c.fetching = true
+		c.mu.Unlock()
+		//nolint:gocritic // cache can only read crypto keys.
+		keys, err := c.cryptoKeys(dbauthz.AsKeyReader(ctx))
+		c.mu.Lock()
+		c.fetching = false
+		c.cond.Broadcast()
+		if err != nil {
+			return nil, xerrors.Errorf("get keys: %w", err)
+		}
+		if c.closed {
+			return nil, ErrClosed
+		}
+		c.lastFetch = c.clock.Now()
+		c.refresher.Reset(refreshInterval)
+		c.keys = keys
+	}
+
+	now := c.clock.Now()
+	keys := make([]codersdk.CryptoKey, 0, len(c.keys))
+	for sequence, key := range c.keys {
+		// The latest key is also stored under its own sequence number.
+		if sequence == latestSequence || !key.CanVerify(now) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b codersdk.CryptoKey) int {
+		return cmp.Compare(a.Sequence, b.Sequence)
+	})
+	return keys, nil
 }
 
 func isEncryptionKeyFeature(feature codersdk.CryptoKeyFeature) bool {
@@ -212,13 +310,48 @@ func isEncryptionKeyFeature(feature codersdk.CryptoKeyFeature) bool {
 
 func isSigningKeyFeature(feature codersdk.CryptoKeyFeature) bool {
 	switch feature {
-	case codersdk.CryptoKeyFeatureTailnetResume, codersdk.CryptoKeyFeatureOIDCConvert, codersdk.CryptoKeyFeatureWorkspaceAppsToken:
+	case codersdk.CryptoKeyFeatureTailnetResume, codersdk.CryptoKeyFeatureOIDCConvert, codersdk.CryptoKeyFeatureWorkspaceAppsToken,
+		codersdk.CryptoKeyFeatureWorkloadIdentity:
 		return true
 	default:
 		return false
 	}
 }
 
+// isAsymmetricKeyFeature returns whether the keys of the feature are ECDSA
+// private keys rather than symmetric secrets.
+func isAsymmetricKeyFeature(feature codersdk.CryptoKeyFeature) bool {
+	return feature == codersdk.CryptoKeyFeatureWorkloadIdentity
+}
+
+// ECDSAKey returns the ID and the private key of an asymmetric signing key.
+func ECDSAKey(k codersdk.CryptoKey) (string, *ecdsa.PrivateKey, error) {
+	if !isAsymmetricKeyFeature(k.Feature) {
+		return "", nil, ErrInvalidFeature
+	}
+	id, secret, err := idSecret(k)
+	if err != nil {
+		return "", nil, err
+	}
+	key, err := parseECDSAKey(secret)
+	if err != nil {
+		return "", nil, xerrors.Errorf("parse key: %w", err)
+	}
+	return id, key, nil
+}
+
+func parseECDSAKey(secret []byte) (*ecdsa.PrivateKey, error) {
+	parsed, err := x509.ParsePKCS8PrivateKey(secret)
+	if err != nil {
+		return nil, xerrors.Errorf("parse pkcs8 key: %w", err)
+	}
+	key, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, xerrors.Errorf("expected ecdsa key, got %T", parsed)
+	}
+	return key, nil
+}
+
 func idSecret(k codersdk.CryptoKey) (string, []byte, error) {
 	key, err := hex.DecodeString(k.Secret)
 	if err != nil {
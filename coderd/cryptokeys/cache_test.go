@@ -435,6 +435,58 @@ func TestCryptoKeyCache(t *testing.T) {
 		require.Equal(t, 3, ff.called)
 	})
 
+	t.Run("VerifyingKeys", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx    = testutil.Context(t, testutil.WaitShort)
+			logger = testutil.Logger(t)
+			clock  = quartz.NewMock(t)
+		)
+
+		now := clock.Now().UTC()
+		current := codersdk.CryptoKey{
+			Feature:  codersdk.CryptoKeyFeatureWorkloadIdentity,
+			Secret:   generateKey(t, 64),
+			Sequence: 2,
+			StartsAt: now.Add(-time.Hour),
+		}
+		next := codersdk.CryptoKey{
+			Feature:  codersdk.CryptoKeyFeatureWorkloadIdentity,
+			Secret:   generateKey(t, 64),
+			Sequence: 3,
+			StartsAt: now.Add(time.Hour),
+		}
+		deleted := codersdk.CryptoKey{
+			Feature:   codersdk.CryptoKeyFeatureWorkloadIdentity,
+			Secret:    generateKey(t, 64),
+			Sequence:  1,
+			StartsAt:  now.Add(-2 * time.Hour),
+			DeletesAt: now,
+		}
+
+		ff := &fakeFetcher{
+			keys: []codersdk.CryptoKey{next, deleted, current},
+		}
+
+		cache, err := cryptokeys.NewPublicSigningCache(ctx, logger, ff, codersdk.CryptoKeyFeatureWorkloadIdentity, cryptokeys.WithCacheClock(clock))
+		require.NoError(t, err)
+
+		// Keys that are deleted are left out, keys that have not started
+		// yet are included.
+		keys, err := cache.VerifyingKeys(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.CryptoKey{current, next}, keys)
+		keys, err = cache.VerifyingKeys(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.CryptoKey{current, next}, keys)
+		require.Equal(t, 1, ff.called)
+
+		cache.Close()
+		_, err = cache.VerifyingKeys(ctx)
+		require.ErrorIs(t, err, cryptokeys.ErrClosed)
+	})
+
 	t.Run("Closed", func(t *testing.T) {
 		t.Parallel()
 
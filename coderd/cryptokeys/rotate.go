@@ -2,7 +2,10 @@ package cryptokeys
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
+	"crypto/x509"
 	"database/sql"
 	"encoding/hex"
 	"time"
@@ -20,6 +23,9 @@ const (
 	WorkspaceAppsTokenDuration = time.Minute
 	OIDCConvertTokenDuration   = time.Minute * 5
 	TailnetResumeTokenDuration = time.Hour * 24
+	// WorkloadIdentityTokenDuration is how long workload identity tokens
+	// issued to workspace agents are valid for.
+	WorkloadIdentityTokenDuration = time.Minute * 15
 
 	// defaultRotationInterval is the default interval at which keys are checked for rotation.
 	defaultRotationInterval = time.Minute * 10
@@ -236,6 +242,10 @@ func generateNewSecret(feature database.CryptoKeyFeature) (string, error) {
 		return generateKey(64)
 	case database.CryptoKeyFeatureTailnetResume:
 		return generateKey(64)
+	case database.CryptoKeyFeatureWorkloadIdentity:
+		// Workload identity tokens are verified by third parties, so they are
+		// signed with an asymmetric key.
+		return generateECDSAKey()
 	}
 	return "", xerrors.Errorf("unknown feature: %s", feature)
 }
@@ -249,6 +259,20 @@ func generateKey(length int) (string, error) {
 	return hex.EncodeToString(b), nil
 }
 
+// generateECDSAKey generates a P-256 private key, encoded as hex of its PKCS #8
+// form.
+func generateECDSAKey() (string, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", xerrors.Errorf("generate ecdsa key: %w", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", xerrors.Errorf("marshal ecdsa key: %w", err)
+	}
+	return hex.EncodeToString(der), nil
+}
+
 func tokenDuration(feature database.CryptoKeyFeature) time.Duration {
 	switch feature {
 	case database.CryptoKeyFeatureWorkspaceAppsAPIKey:
@@ -259,6 +283,8 @@ func tokenDuration(feature database.CryptoKeyFeature) time.Duration {
 		return OIDCConvertTokenDuration
 	case database.CryptoKeyFeatureTailnetResume:
 		return TailnetResumeTokenDuration
+	case database.CryptoKeyFeatureWorkloadIdentity:
+		return WorkloadIdentityTokenDuration
 	default:
 		return 0
 	}
@@ -305,11 +305,12 @@ func templateVersionParameterOptions(rawOptions json.RawMessage) ([]codersdk.Tem
 }
 
 func OAuth2ProviderApp(accessURL *url.URL, dbApp database.OAuth2ProviderApp) codersdk.OAuth2ProviderApp {
-	return codersdk.OAuth2ProviderApp{
-		ID:          dbApp.ID,
-		Name:        dbApp.Name,
-		CallbackURL: dbApp.CallbackURL,
-		Icon:        dbApp.Icon,
+	app := codersdk.OAuth2ProviderApp{
+		ID:           dbApp.ID,
+		Name:         dbApp.Name,
+		CallbackURL:  dbApp.CallbackURL,
+		Icon:         dbApp.Icon,
+		PublicClient: dbApp.PublicClient,
 		Endpoints: codersdk.OAuth2AppEndpoints{
 			Authorization: accessURL.ResolveReference(&url.URL{
 				Path: "/oauth2/authorize",
@@ -317,10 +318,15 @@ func OAuth2ProviderApp(accessURL *url.URL, dbApp database.OAuth2ProviderApp) cod
 			Token: accessURL.ResolveReference(&url.URL{
 				Path: "/oauth2/tokens",
 			}).String(),
-			// We do not currently support DeviceAuth.
-			DeviceAuth: "",
+			DeviceAuth: accessURL.ResolveReference(&url.URL{
+				Path: "/oauth2/device",
+			}).String(),
 		},
 	}
+	if dbApp.ClientCredentialsUserID.Valid {
+		app.ClientCredentialsUserID = &dbApp.ClientCredentialsUserID.UUID
+	}
+	return app
 }
 
 func OAuth2ProviderApps(accessURL *url.URL, dbApps []database.OAuth2ProviderApp) []codersdk.OAuth2ProviderApp {
@@ -467,6 +473,19 @@ func WorkspaceAgent(derpMap *tailcfg.DERPMap, coordinator tailnet.Coordinator,
 	return workspaceAgent, nil
 }
 
+func WorkspaceAgentProcessGroup(group database.WorkspaceAgentProcessGroup) codersdk.WorkspaceAgentProcessGroup {
+	return codersdk.WorkspaceAgentProcessGroup{
+		Name:             group.Name,
+		CPUUsageUsec:     group.CPUUsageUsec,
+		CPUCores:         group.CPUCores,
+		CPULimitCores:    group.CPULimitCores,
+		MemoryBytes:      group.MemoryBytes,
+		MemoryLimitBytes: group.MemoryLimitBytes,
+		OOMKills:         group.OOMKills,
+		UpdatedAt:        group.UpdatedAt,
+	}
+}
+
 func AppSubdomain(dbApp database.WorkspaceApp, agentName, workspaceName, ownerName string) string {
 	if !dbApp.Subdomain || agentName == "" || ownerName == "" || workspaceName == "" {
 		return ""
@@ -676,6 +695,97 @@ func CryptoKey(key database.CryptoKey) codersdk.CryptoKey {
 	}
 }
 
+func WorkspaceSessionRecording(recording database.WorkspaceSessionRecording) codersdk.WorkspaceSessionRecording {
+	converted := codersdk.WorkspaceSessionRecording{
+		ID:               recording.ID,
+		WorkspaceID:      recording.WorkspaceID,
+		AgentID:          recording.AgentID,
+		WorkspaceOwnerID: recording.UserID,
+		Type:             codersdk.WorkspaceSessionRecordingType(recording.Type),
+		StartedAt:        recording.CreatedAt,
+		Size:             recording.Size,
+	}
+	if recording.EndedAt.Valid {
+		converted.EndedAt = &recording.EndedAt.Time
+	}
+	return converted
+}
+
+// Secret converts a secret without its value, which is never returned by the
+// API.
+func Secret(secret database.Secret) codersdk.Secret {
+	return codersdk.Secret{
+		ID:          secret.ID,
+		Name:        secret.Name,
+		Description: secret.Description,
+		EnvName:     secret.EnvName,
+		FilePath:    secret.FilePath,
+		CreatedAt:   secret.CreatedAt,
+		UpdatedAt:   secret.UpdatedAt,
+	}
+}
+
+func WorkspaceBuildApproval(approval database.WorkspaceBuildApproval, workspaceID uuid.UUID) codersdk.WorkspaceBuildApproval {
+	converted := codersdk.WorkspaceBuildApproval{
+		WorkspaceBuildID: approval.WorkspaceBuildID,
+		WorkspaceID:      workspaceID,
+		Status:           codersdk.WorkspaceBuildApprovalStatus(approval.Status),
+		CreatedAt:        approval.CreatedAt,
+		Reason:           approval.Reason,
+	}
+	if approval.ExpiresAt.Valid {
+		converted.ExpiresAt = &approval.ExpiresAt.Time
+	}
+	if approval.ReviewerID.Valid {
+		converted.ReviewerID = &approval.ReviewerID.UUID
+	}
+	if approval.ReviewedAt.Valid {
+		converted.ReviewedAt = &approval.ReviewedAt.Time
+	}
+	return converted
+}
+
+func TemplateRollout(rollout database.TemplateRollout, counts database.GetTemplateRolloutBuildCountsRow) codersdk.TemplateRollout {
+	converted := codersdk.TemplateRollout{
+		ID:                rollout.ID,
+		TemplateID:        rollout.TemplateID,
+		TemplateVersionID: rollout.TemplateVersionID,
+		CreatedBy:         rollout.CreatedBy,
+		CreatedAt:         rollout.CreatedAt,
+		UpdatedAt:         rollout.UpdatedAt,
+		Status:            codersdk.TemplateRolloutStatus(rollout.Status),
+		StatusReason:      rollout.StatusReason,
+		Percent:           rollout.Percent,
+		MinBuilds:         rollout.MinBuilds,
+		MaxFailureRate:    rollout.MaxFailureRate,
+		AutoPromote:       rollout.AutoPromote,
+		Builds:            counts.Builds,
+		FailedBuilds:      counts.FailedBuilds,
+	}
+	if rollout.CompletedAt.Valid {
+		converted.CompletedAt = &rollout.CompletedAt.Time
+	}
+	if rollout.GroupID.Valid {
+		converted.GroupID = &rollout.GroupID.UUID
+	}
+	return converted
+}
+
+func WorkspaceScheduledAction(action database.WorkspaceScheduledAction) codersdk.WorkspaceScheduledAction {
+	converted := codersdk.WorkspaceScheduledAction{
+		ID:          action.ID,
+		WorkspaceID: action.WorkspaceID,
+		CreatedAt:   action.CreatedAt,
+		Action:      codersdk.WorkspaceScheduledActionType(action.Action),
+		Schedule:    action.Schedule,
+		NextRunAt:   action.NextRunAt,
+	}
+	if action.LastRunAt.Valid {
+		converted.LastRunAt = &action.LastRunAt.Time
+	}
+	return converted
+}
+
 func MatchedProvisioners(provisionerDaemons []database.ProvisionerDaemon, now time.Time, staleInterval time.Duration) codersdk.MatchedProvisioners {
 	minLastSeenAt := now.Add(-staleInterval)
 	mostRecentlySeen := codersdk.NullTime{}
@@ -284,6 +284,24 @@ var (
 		Scope: rbac.ScopeAll,
 	}.WithCachedASTValue()
 
+	// See the SCIM groups endpoints in the enterprise package.
+	subjectSCIMGroupSync = rbac.Subject{
+		FriendlyName: "SCIM Group Sync",
+		ID:           uuid.Nil.String(),
+		Roles: rbac.Roles([]rbac.Role{
+			{
+				Identifier:  rbac.RoleIdentifier{Name: "scimgroupsync"},
+				DisplayName: "SCIM Group Sync",
+				Site: rbac.Permissions(map[string][]policy.Action{
+					rbac.ResourceGroup.Type: {policy.ActionRead, policy.ActionDelete},
+				}),
+				Org:  map[string][]rbac.Permission{},
+				User: []rbac.Permission{},
+			},
+		}),
+		Scope: rbac.ScopeAll,
+	}.WithCachedASTValue()
+
 	subjectSystemRestricted = rbac.Subject{
 		FriendlyName: "System",
 		ID:           uuid.Nil.String(),
@@ -371,6 +389,12 @@ func AsNotifier(ctx context.Context) context.Context {
 	return context.WithValue(ctx, authContextKey{}, subjectNotifier)
 }
 
+// AsSCIMGroupSync returns a context with an actor that has permissions required
+// for deleting groups deprovisioned by the identity provider through SCIM.
+func AsSCIMGroupSync(ctx context.Context) context.Context {
+	return context.WithValue(ctx, authContextKey{}, subjectSCIMGroupSync)
+}
+
 // AsSystemRestricted returns a context with an actor that has permissions
 // required for various system operations (login, logout, metrics cache).
 func AsSystemRestricted(ctx context.Context) context.Context {
@@ -688,6 +712,65 @@ func (q *querier) authorizeUpdateFileTemplate(ctx context.Context, file database
 	}
 }
 
+// authorizeSessionRecordingUpdate checks that the actor can update the
+// workspace a session recording belongs to. Recordings themselves are only
+// readable by auditors, so the lookup is done without authorization.
+func (q *querier) authorizeSessionRecordingUpdate(ctx context.Context, recordingID uuid.UUID) error {
+	recording, err := q.db.GetWorkspaceSessionRecordingByID(ctx, recordingID)
+	if err != nil {
+		return xerrors.Errorf("get workspace session recording by id: %w", err)
+	}
+	w, err := q.db.GetWorkspaceByID(ctx, recording.WorkspaceID)
+	if err != nil {
+		return xerrors.Errorf("get workspace by id: %w", err)
+	}
+	return q.authorizeContext(ctx, policy.ActionUpdate, w)
+}
+
+// authorizeTemplateRollout checks that the actor can perform the action on the
+// template a rollout belongs to. Rollouts have no RBAC object of their own.
+func (q *querier) authorizeTemplateRollout(ctx context.Context, action policy.Action, templateID uuid.UUID) error {
+	template, err := q.db.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return xerrors.Errorf("get template by id: %w", err)
+	}
+	return q.authorizeContext(ctx, action, template)
+}
+
+// authorizeSecretScope checks that the actor can manage the secrets of a
+// user, organization or template. Secrets have no RBAC object of their own:
+// users manage their personal secrets, organization and template admins manage
+// the secrets of their organizations and templates. Values are never returned
+// by the API, so reading the secrets of a scope requires the same permissions
+// as updating them, except for personal secrets.
+func (q *querier) authorizeSecretScope(ctx context.Context, update bool, userID, organizationID, templateID uuid.NullUUID) error {
+	switch {
+	case userID.Valid:
+		action := policy.ActionReadPersonal
+		if update {
+			action = policy.ActionUpdatePersonal
+		}
+		return q.authorizeContext(ctx, action, rbac.ResourceUserObject(userID.UUID))
+	case organizationID.Valid:
+		return q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceOrganization.WithID(organizationID.UUID).InOrg(organizationID.UUID))
+	case templateID.Valid:
+		template, err := q.db.GetTemplateByID(ctx, templateID.UUID)
+		if err != nil {
+			return xerrors.Errorf("get template by id: %w", err)
+		}
+		return q.authorizeContext(ctx, policy.ActionUpdate, template)
+	default:
+		return xerrors.New("secret has no scope")
+	}
+}
+
+// isTemplateRolloutCandidate returns true if the version is the candidate of
+// the rollout in progress of the template.
+func (q *querier) isTemplateRolloutCandidate(ctx context.Context, templateID, versionID uuid.UUID) bool {
+	rollout, err := q.db.GetActiveTemplateRolloutByTemplateID(ctx, templateID)
+	return err == nil && rollout.TemplateVersionID == versionID
+}
+
 // convertToOrganizationRoles converts a set of scoped role names to their unique
 // scoped names. The database stores roles as an array of strings, and needs to be
 // converted.
@@ -1096,6 +1179,13 @@ func (q *querier) CleanTailnetTunnels(ctx context.Context) error {
 	return q.db.CleanTailnetTunnels(ctx)
 }
 
+func (q *querier) ClearOldWorkspaceBuildProvisionerStates(ctx context.Context, arg database.ClearOldWorkspaceBuildProvisionerStatesParams) (int64, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return 0, err
+	}
+	return q.db.ClearOldWorkspaceBuildProvisionerStates(ctx, arg)
+}
+
 // TODO: Handle org scoped lookups
 func (q *querier) CustomRoles(ctx context.Context, arg database.CustomRolesParams) ([]database.CustomRole, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceAssignRole); err != nil {
@@ -1149,6 +1239,17 @@ func (q *querier) DeleteCoordinator(ctx context.Context, id uuid.UUID) error {
 	return q.db.DeleteCoordinator(ctx, id)
 }
 
+func (q *querier) DeleteCostBudgetByGroupID(ctx context.Context, groupID uuid.UUID) error {
+	group, err := q.db.GetGroupByID(ctx, groupID)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, group); err != nil {
+		return err
+	}
+	return q.db.DeleteCostBudgetByGroupID(ctx, groupID)
+}
+
 func (q *querier) DeleteCryptoKey(ctx context.Context, arg database.DeleteCryptoKeyParams) (database.CryptoKey, error) {
 	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceCryptoKey); err != nil {
 		return database.CryptoKey{}, err
@@ -1245,11 +1346,32 @@ func (q *querier) DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx context.Contex
 	return q.db.DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx, arg)
 }
 
-func (q *querier) DeleteOldNotificationMessages(ctx context.Context) error {
+func (q *querier) DeleteOAuth2ProviderDeviceCodeByID(ctx context.Context, id uuid.UUID) error {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOAuth2ProviderDeviceCodeByID(ctx, id)
+}
+
+func (q *querier) DeleteOldAuditLogs(ctx context.Context, arg database.DeleteOldAuditLogsParams) (int64, error) {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return 0, err
+	}
+	return q.db.DeleteOldAuditLogs(ctx, arg)
+}
+
+func (q *querier) DeleteOldNetworkReports(ctx context.Context) error {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldNetworkReports(ctx)
+}
+
+func (q *querier) DeleteOldNotificationMessages(ctx context.Context, beforeTime time.Time) error {
 	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceNotificationMessage); err != nil {
 		return err
 	}
-	return q.db.DeleteOldNotificationMessages(ctx)
+	return q.db.DeleteOldNotificationMessages(ctx, beforeTime)
 }
 
 func (q *querier) DeleteOldProvisionerDaemons(ctx context.Context) error {
@@ -1259,6 +1381,13 @@ func (q *querier) DeleteOldProvisionerDaemons(ctx context.Context) error {
 	return q.db.DeleteOldProvisionerDaemons(ctx)
 }
 
+func (q *querier) DeleteOldProvisionerJobLogs(ctx context.Context, arg database.DeleteOldProvisionerJobLogsParams) (int64, error) {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return 0, err
+	}
+	return q.db.DeleteOldProvisionerJobLogs(ctx, arg)
+}
+
 func (q *querier) DeleteOldWorkspaceAgentLogs(ctx context.Context, threshold time.Time) error {
 	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
 		return err
@@ -1266,11 +1395,11 @@ func (q *querier) DeleteOldWorkspaceAgentLogs(ctx context.Context, threshold tim
 	return q.db.DeleteOldWorkspaceAgentLogs(ctx, threshold)
 }
 
-func (q *querier) DeleteOldWorkspaceAgentStats(ctx context.Context) error {
+func (q *querier) DeleteOldWorkspaceAgentStats(ctx context.Context, beforeTime time.Time) error {
 	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
 		return err
 	}
-	return q.db.DeleteOldWorkspaceAgentStats(ctx)
+	return q.db.DeleteOldWorkspaceAgentStats(ctx, beforeTime)
 }
 
 func (q *querier) DeleteOrganization(ctx context.Context, id uuid.UUID) error {
@@ -1305,6 +1434,17 @@ func (q *querier) DeleteRuntimeConfig(ctx context.Context, key string) error {
 	return q.db.DeleteRuntimeConfig(ctx, key)
 }
 
+func (q *querier) DeleteSecretByID(ctx context.Context, id uuid.UUID) error {
+	secret, err := q.db.GetSecretByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeSecretScope(ctx, true, secret.UserID, secret.OrganizationID, secret.TemplateID); err != nil {
+		return err
+	}
+	return q.db.DeleteSecretByID(ctx, id)
+}
+
 func (q *querier) DeleteTailnetAgent(ctx context.Context, arg database.DeleteTailnetAgentParams) (database.DeleteTailnetAgentRow, error) {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceTailnetCoordinator); err != nil {
 		return database.DeleteTailnetAgentRow{}, err
@@ -1367,6 +1507,21 @@ func (q *querier) DeleteWorkspaceAgentPortSharesByTemplate(ctx context.Context,
 	return q.db.DeleteWorkspaceAgentPortSharesByTemplate(ctx, templateID)
 }
 
+func (q *querier) DeleteWorkspaceScheduledActionByID(ctx context.Context, id uuid.UUID) error {
+	action, err := q.db.GetWorkspaceScheduledActionByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	w, err := q.db.GetWorkspaceByID(ctx, action.WorkspaceID)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, w); err != nil {
+		return err
+	}
+	return q.db.DeleteWorkspaceScheduledActionByID(ctx, id)
+}
+
 func (q *querier) DisableForeignKeysAndTriggers(ctx context.Context) error {
 	if !testing.Testing() {
 		return xerrors.Errorf("DisableForeignKeysAndTriggers is only allowed in tests")
@@ -1415,6 +1570,13 @@ func (q *querier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.Tim
 	return fetchWithPostFilter(q.auth, policy.ActionRead, q.db.GetAPIKeysLastUsedAfter)(ctx, lastUsed)
 }
 
+func (q *querier) GetActiveTemplateRolloutByTemplateID(ctx context.Context, templateID uuid.UUID) (database.TemplateRollout, error) {
+	if err := q.authorizeTemplateRollout(ctx, policy.ActionRead, templateID); err != nil {
+		return database.TemplateRollout{}, err
+	}
+	return q.db.GetActiveTemplateRolloutByTemplateID(ctx, templateID)
+}
+
 func (q *querier) GetActiveUserCount(ctx context.Context) (int64, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
 		return 0, err
@@ -1505,6 +1667,34 @@ func (q *querier) GetCoordinatorResumeTokenSigningKey(ctx context.Context) (stri
 	return q.db.GetCoordinatorResumeTokenSigningKey(ctx)
 }
 
+func (q *querier) GetCostBudgetByGroupID(ctx context.Context, groupID uuid.UUID) (database.CostBudget, error) {
+	if _, err := q.GetGroupByID(ctx, groupID); err != nil { // AuthZ check
+		return database.CostBudget{}, err
+	}
+	return q.db.GetCostBudgetByGroupID(ctx, groupID)
+}
+
+func (q *querier) GetCostBudgetMemberIDs(ctx context.Context, groupID uuid.UUID) ([]uuid.UUID, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetCostBudgetMemberIDs(ctx, groupID)
+}
+
+func (q *querier) GetCostBudgetWorkspaceIDs(ctx context.Context, groupID uuid.UUID) ([]uuid.UUID, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetCostBudgetWorkspaceIDs(ctx, groupID)
+}
+
+func (q *querier) GetCostBudgetsWithCost(ctx context.Context, since time.Time) ([]database.GetCostBudgetsWithCostRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetCostBudgetsWithCost(ctx, since)
+}
+
 func (q *querier) GetCryptoKeyByFeatureAndSequence(ctx context.Context, arg database.GetCryptoKeyByFeatureAndSequenceParams) (database.CryptoKey, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceCryptoKey); err != nil {
 		return database.CryptoKey{}, err
@@ -1757,6 +1947,13 @@ func (q *querier) GetLogoURL(ctx context.Context) (string, error) {
 	return q.db.GetLogoURL(ctx)
 }
 
+func (q *querier) GetNetworkRegionInsights(ctx context.Context, arg database.GetNetworkRegionInsightsParams) ([]database.GetNetworkRegionInsightsRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceDeploymentStats); err != nil {
+		return nil, err
+	}
+	return q.db.GetNetworkRegionInsights(ctx, arg)
+}
+
 func (q *querier) GetNotificationMessagesByStatus(ctx context.Context, arg database.GetNotificationMessagesByStatusParams) ([]database.NotificationMessage, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceNotificationMessage); err != nil {
 		return nil, err
@@ -1858,6 +2055,20 @@ func (q *querier) GetOAuth2ProviderAppsByUserID(ctx context.Context, userID uuid
 	return q.db.GetOAuth2ProviderAppsByUserID(ctx, userID)
 }
 
+func (q *querier) GetOAuth2ProviderDeviceCodeByPrefix(ctx context.Context, secretPrefix []byte) (database.OAuth2ProviderDeviceCode, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return database.OAuth2ProviderDeviceCode{}, err
+	}
+	return q.db.GetOAuth2ProviderDeviceCodeByPrefix(ctx, secretPrefix)
+}
+
+func (q *querier) GetOAuth2ProviderDeviceCodeByUserCode(ctx context.Context, userCode string) (database.OAuth2ProviderDeviceCode, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return database.OAuth2ProviderDeviceCode{}, err
+	}
+	return q.db.GetOAuth2ProviderDeviceCodeByUserCode(ctx, userCode)
+}
+
 func (q *querier) GetOAuthSigningKey(ctx context.Context) (string, error) {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return "", err
@@ -1911,6 +2122,23 @@ func (q *querier) GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUI
 	return q.db.GetParameterSchemasByJobID(ctx, jobID)
 }
 
+// TODO: we need to add a provisioner job resource
+func (q *querier) GetPendingProvisionerJobTags(ctx context.Context, arg database.GetPendingProvisionerJobTagsParams) ([]database.GetPendingProvisionerJobTagsRow, error) {
+	// if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+	// 	return nil, err
+	// }
+	return q.db.GetPendingProvisionerJobTags(ctx, arg)
+}
+
+func (q *querier) GetPendingWorkspaceBuildApprovalsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.GetPendingWorkspaceBuildApprovalsByTemplateIDRow, error) {
+	// Anyone who can read the template can see which builds are waiting for
+	// approval.
+	if _, err := q.GetTemplateByID(ctx, templateID); err != nil {
+		return nil, err
+	}
+	return q.db.GetPendingWorkspaceBuildApprovalsByTemplateID(ctx, templateID)
+}
+
 func (q *querier) GetPreviousTemplateVersion(ctx context.Context, arg database.GetPreviousTemplateVersionParams) (database.TemplateVersion, error) {
 	// An actor can read the previous template version if they can read the related template.
 	// If no linked template exists, we check if the actor can read *a* template.
@@ -1967,6 +2195,14 @@ func (q *querier) GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (data
 	return job, nil
 }
 
+func (q *querier) GetProvisionerJobResourceChangesByJobID(ctx context.Context, jobID uuid.UUID) ([]database.ProvisionerJobResourceChange, error) {
+	_, err := q.GetProvisionerJobByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	return q.db.GetProvisionerJobResourceChangesByJobID(ctx, jobID)
+}
+
 func (q *querier) GetProvisionerJobTimingsByJobID(ctx context.Context, jobID uuid.UUID) ([]database.ProvisionerJobTiming, error) {
 	_, err := q.GetProvisionerJobByID(ctx, jobID)
 	if err != nil {
@@ -2017,6 +2253,13 @@ func (q *querier) GetProvisionerLogsAfterID(ctx context.Context, arg database.Ge
 	return q.db.GetProvisionerLogsAfterID(ctx, arg)
 }
 
+func (q *querier) GetPurgeableRowCounts(ctx context.Context, arg database.GetPurgeableRowCountsParams) (database.GetPurgeableRowCountsRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return database.GetPurgeableRowCountsRow{}, err
+	}
+	return q.db.GetPurgeableRowCounts(ctx, arg)
+}
+
 func (q *querier) GetQuotaAllowanceForUser(ctx context.Context, params database.GetQuotaAllowanceForUserParams) (int64, error) {
 	err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceUserObject(params.UserID))
 	if err != nil {
@@ -2054,6 +2297,45 @@ func (q *querier) GetRuntimeConfig(ctx context.Context, key string) (string, err
 	return q.db.GetRuntimeConfig(ctx, key)
 }
 
+func (q *querier) GetSecretByID(ctx context.Context, id uuid.UUID) (database.Secret, error) {
+	secret, err := q.db.GetSecretByID(ctx, id)
+	if err != nil {
+		return database.Secret{}, err
+	}
+	if err := q.authorizeSecretScope(ctx, false, secret.UserID, secret.OrganizationID, secret.TemplateID); err != nil {
+		return database.Secret{}, err
+	}
+	return secret, nil
+}
+
+func (q *querier) GetSecretByScopeAndName(ctx context.Context, arg database.GetSecretByScopeAndNameParams) (database.Secret, error) {
+	if err := q.authorizeSecretScope(ctx, false, arg.UserID, arg.OrganizationID, arg.TemplateID); err != nil {
+		return database.Secret{}, err
+	}
+	return q.db.GetSecretByScopeAndName(ctx, arg)
+}
+
+func (q *querier) GetSecrets(ctx context.Context) ([]database.Secret, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetSecrets(ctx)
+}
+
+func (q *querier) GetSecretsByScope(ctx context.Context, arg database.GetSecretsByScopeParams) ([]database.Secret, error) {
+	if err := q.authorizeSecretScope(ctx, false, arg.UserID, arg.OrganizationID, arg.TemplateID); err != nil {
+		return nil, err
+	}
+	return q.db.GetSecretsByScope(ctx, arg)
+}
+
+func (q *querier) GetSecretsForWorkspace(ctx context.Context, arg database.GetSecretsForWorkspaceParams) ([]database.Secret, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetSecretsForWorkspace(ctx, arg)
+}
+
 func (q *querier) GetTailnetAgents(ctx context.Context, id uuid.UUID) ([]database.TailnetAgent, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceTailnetCoordinator); err != nil {
 		return nil, err
@@ -2157,6 +2439,38 @@ func (q *querier) GetTemplateParameterInsights(ctx context.Context, arg database
 	return q.db.GetTemplateParameterInsights(ctx, arg)
 }
 
+func (q *querier) GetTemplateRolloutBuildCounts(ctx context.Context, arg database.GetTemplateRolloutBuildCountsParams) (database.GetTemplateRolloutBuildCountsRow, error) {
+	tv, err := q.db.GetTemplateVersionByID(ctx, arg.TemplateVersionID)
+	if err != nil {
+		return database.GetTemplateRolloutBuildCountsRow{}, err
+	}
+	if !tv.TemplateID.Valid {
+		return database.GetTemplateRolloutBuildCountsRow{}, sql.ErrNoRows
+	}
+	if err := q.authorizeTemplateRollout(ctx, policy.ActionRead, tv.TemplateID.UUID); err != nil {
+		return database.GetTemplateRolloutBuildCountsRow{}, err
+	}
+	return q.db.GetTemplateRolloutBuildCounts(ctx, arg)
+}
+
+func (q *querier) GetTemplateRolloutByID(ctx context.Context, id uuid.UUID) (database.TemplateRollout, error) {
+	rollout, err := q.db.GetTemplateRolloutByID(ctx, id)
+	if err != nil {
+		return database.TemplateRollout{}, err
+	}
+	if err := q.authorizeTemplateRollout(ctx, policy.ActionRead, rollout.TemplateID); err != nil {
+		return database.TemplateRollout{}, err
+	}
+	return rollout, nil
+}
+
+func (q *querier) GetTemplateRolloutsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplateRollout, error) {
+	if err := q.authorizeTemplateRollout(ctx, policy.ActionRead, templateID); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateRolloutsByTemplateID(ctx, templateID)
+}
+
 func (q *querier) GetTemplateUsageStats(ctx context.Context, arg database.GetTemplateUsageStatsParams) ([]database.TemplateUsageStat, error) {
 	if err := q.authorizeTemplateInsights(ctx, arg.TemplateIDs); err != nil {
 		return nil, err
@@ -2550,6 +2864,20 @@ func (q *querier) GetWorkspaceAgentPortShare(ctx context.Context, arg database.G
 	return q.db.GetWorkspaceAgentPortShare(ctx, arg)
 }
 
+func (q *querier) GetWorkspaceAgentProcessGroups(ctx context.Context, workspaceAgentID uuid.UUID) ([]database.WorkspaceAgentProcessGroup, error) {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, workspaceAgentID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = q.authorizeContext(ctx, policy.ActionRead, workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.db.GetWorkspaceAgentProcessGroups(ctx, workspaceAgentID)
+}
+
 func (q *querier) GetWorkspaceAgentScriptTimingsByBuildID(ctx context.Context, id uuid.UUID) ([]database.GetWorkspaceAgentScriptTimingsByBuildIDRow, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
 		return nil, err
@@ -2637,6 +2965,14 @@ func (q *querier) GetWorkspaceAppsCreatedAfter(ctx context.Context, createdAt ti
 	return q.db.GetWorkspaceAppsCreatedAfter(ctx, createdAt)
 }
 
+func (q *querier) GetWorkspaceBuildApprovalByBuildID(ctx context.Context, workspaceBuildID uuid.UUID) (database.WorkspaceBuildApproval, error) {
+	// If we can read the build, we can read its approval.
+	if _, err := q.GetWorkspaceBuildByID(ctx, workspaceBuildID); err != nil {
+		return database.WorkspaceBuildApproval{}, err
+	}
+	return q.db.GetWorkspaceBuildApprovalByBuildID(ctx, workspaceBuildID)
+}
+
 func (q *querier) GetWorkspaceBuildByID(ctx context.Context, buildID uuid.UUID) (database.WorkspaceBuild, error) {
 	build, err := q.db.GetWorkspaceBuildByID(ctx, buildID)
 	if err != nil {
@@ -2719,6 +3055,34 @@ func (q *querier) GetWorkspaceByWorkspaceAppID(ctx context.Context, workspaceApp
 	return fetch(q.log, q.auth, q.db.GetWorkspaceByWorkspaceAppID)(ctx, workspaceAppID)
 }
 
+func (q *querier) GetWorkspaceCostInsightsByGroup(ctx context.Context, arg database.GetWorkspaceCostInsightsByGroupParams) ([]database.GetWorkspaceCostInsightsByGroupRow, error) {
+	if err := q.authorizeTemplateInsights(ctx, arg.TemplateIDs); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceCostInsightsByGroup(ctx, arg)
+}
+
+func (q *querier) GetWorkspaceCostInsightsByInterval(ctx context.Context, arg database.GetWorkspaceCostInsightsByIntervalParams) ([]database.GetWorkspaceCostInsightsByIntervalRow, error) {
+	if err := q.authorizeTemplateInsights(ctx, arg.TemplateIDs); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceCostInsightsByInterval(ctx, arg)
+}
+
+func (q *querier) GetWorkspaceCostInsightsByTemplate(ctx context.Context, arg database.GetWorkspaceCostInsightsByTemplateParams) ([]database.GetWorkspaceCostInsightsByTemplateRow, error) {
+	if err := q.authorizeTemplateInsights(ctx, arg.TemplateIDs); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceCostInsightsByTemplate(ctx, arg)
+}
+
+func (q *querier) GetWorkspaceCostInsightsByUser(ctx context.Context, arg database.GetWorkspaceCostInsightsByUserParams) ([]database.GetWorkspaceCostInsightsByUserRow, error) {
+	if err := q.authorizeTemplateInsights(ctx, arg.TemplateIDs); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceCostInsightsByUser(ctx, arg)
+}
+
 func (q *querier) GetWorkspaceModulesByJobID(ctx context.Context, jobID uuid.UUID) ([]database.WorkspaceModule, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
 		return nil, err
@@ -2847,6 +3211,63 @@ func (q *querier) GetWorkspaceResourcesCreatedAfter(ctx context.Context, created
 	return q.db.GetWorkspaceResourcesCreatedAfter(ctx, createdAt)
 }
 
+func (q *querier) GetWorkspaceScheduledActionByID(ctx context.Context, id uuid.UUID) (database.WorkspaceScheduledAction, error) {
+	action, err := q.db.GetWorkspaceScheduledActionByID(ctx, id)
+	if err != nil {
+		return database.WorkspaceScheduledAction{}, err
+	}
+	// Scheduled actions are readable by anyone that can read the workspace.
+	if _, err := q.GetWorkspaceByID(ctx, action.WorkspaceID); err != nil {
+		return database.WorkspaceScheduledAction{}, err
+	}
+	return action, nil
+}
+
+func (q *querier) GetWorkspaceScheduledActionsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceScheduledAction, error) {
+	if _, err := q.GetWorkspaceByID(ctx, workspaceID); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceScheduledActionsByWorkspaceID(ctx, workspaceID)
+}
+
+func (q *querier) GetWorkspaceScheduledActionsDue(ctx context.Context, now time.Time) ([]database.WorkspaceScheduledAction, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceScheduledActionsDue(ctx, now)
+}
+
+// Session recordings can contain sensitive output, so they are only readable
+// by those that can read the audit log.
+func (q *querier) GetWorkspaceSessionRecordingByID(ctx context.Context, id uuid.UUID) (database.WorkspaceSessionRecording, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceAuditLog); err != nil {
+		return database.WorkspaceSessionRecording{}, err
+	}
+	return q.db.GetWorkspaceSessionRecordingByID(ctx, id)
+}
+
+func (q *querier) GetWorkspaceSessionRecordingChunksByRecordingID(ctx context.Context, arg database.GetWorkspaceSessionRecordingChunksByRecordingIDParams) ([]database.WorkspaceSessionRecordingChunk, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceAuditLog); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceSessionRecordingChunksByRecordingID(ctx, arg)
+}
+
+func (q *querier) GetWorkspaceSessionRecordingsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceSessionRecording, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceAuditLog); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceSessionRecordingsByWorkspaceID(ctx, workspaceID)
+}
+
+func (q *querier) GetWorkspaceSnapshotByID(ctx context.Context, id uuid.UUID) (database.WorkspaceSnapshot, error) {
+	return fetch(q.log, q.auth, q.db.GetWorkspaceSnapshotByID)(ctx, id)
+}
+
+func (q *querier) GetWorkspaceSnapshotsByOwnerAndTemplate(ctx context.Context, arg database.GetWorkspaceSnapshotsByOwnerAndTemplateParams) ([]database.WorkspaceSnapshot, error) {
+	return fetchWithPostFilter(q.auth, policy.ActionRead, q.db.GetWorkspaceSnapshotsByOwnerAndTemplate)(ctx, arg)
+}
+
 func (q *querier) GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx context.Context, templateIds []uuid.UUID) ([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
 		return nil, err
@@ -3022,6 +3443,13 @@ func (q *querier) InsertOAuth2ProviderAppToken(ctx context.Context, arg database
 	return q.db.InsertOAuth2ProviderAppToken(ctx, arg)
 }
 
+func (q *querier) InsertOAuth2ProviderDeviceCode(ctx context.Context, arg database.InsertOAuth2ProviderDeviceCodeParams) (database.OAuth2ProviderDeviceCode, error) {
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.OAuth2ProviderDeviceCode{}, err
+	}
+	return q.db.InsertOAuth2ProviderDeviceCode(ctx, arg)
+}
+
 func (q *querier) InsertOrganization(ctx context.Context, arg database.InsertOrganizationParams) (database.Organization, error) {
 	return insert(q.log, q.auth, rbac.ResourceOrganization, q.db.InsertOrganization)(ctx, arg)
 }
@@ -3060,6 +3488,13 @@ func (q *querier) InsertProvisionerJobLogs(ctx context.Context, arg database.Ins
 }
 
 // TODO: We need to create a ProvisionerJob resource type
+func (q *querier) InsertProvisionerJobResourceChanges(ctx context.Context, arg database.InsertProvisionerJobResourceChangesParams) ([]database.ProvisionerJobResourceChange, error) {
+	// if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
+	// return nil, err
+	// }
+	return q.db.InsertProvisionerJobResourceChanges(ctx, arg)
+}
+
 func (q *querier) InsertProvisionerJobTimings(ctx context.Context, arg database.InsertProvisionerJobTimingsParams) ([]database.ProvisionerJobTiming, error) {
 	// if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
 	// return nil, err
@@ -3078,6 +3513,13 @@ func (q *querier) InsertReplica(ctx context.Context, arg database.InsertReplicaP
 	return q.db.InsertReplica(ctx, arg)
 }
 
+func (q *querier) InsertSecret(ctx context.Context, arg database.InsertSecretParams) (database.Secret, error) {
+	if err := q.authorizeSecretScope(ctx, true, arg.UserID, arg.OrganizationID, arg.TemplateID); err != nil {
+		return database.Secret{}, err
+	}
+	return q.db.InsertSecret(ctx, arg)
+}
+
 func (q *querier) InsertTemplate(ctx context.Context, arg database.InsertTemplateParams) error {
 	obj := rbac.ResourceTemplate.InOrg(arg.OrganizationID)
 	if err := q.authorizeContext(ctx, policy.ActionCreate, obj); err != nil {
@@ -3086,6 +3528,13 @@ func (q *querier) InsertTemplate(ctx context.Context, arg database.InsertTemplat
 	return q.db.InsertTemplate(ctx, arg)
 }
 
+func (q *querier) InsertTemplateRollout(ctx context.Context, arg database.InsertTemplateRolloutParams) (database.TemplateRollout, error) {
+	if err := q.authorizeTemplateRollout(ctx, policy.ActionUpdate, arg.TemplateID); err != nil {
+		return database.TemplateRollout{}, err
+	}
+	return q.db.InsertTemplateRollout(ctx, arg)
+}
+
 func (q *querier) InsertTemplateVersion(ctx context.Context, arg database.InsertTemplateVersionParams) error {
 	if !arg.TemplateID.Valid {
 		// Making a new template version is the same permission as creating a new template.
@@ -3271,9 +3720,10 @@ func (q *querier) InsertWorkspaceBuild(ctx context.Context, arg database.InsertW
 
 		// If the template requires the active version we need to check if
 		// the user is a template admin. If they aren't and are attempting
-		// to use a non-active version then we must fail the request.
+		// to use a non-active version then we must fail the request. The
+		// candidate version of a rollout in progress counts as active.
 		if accessControl.RequireActiveVersion {
-			if arg.TemplateVersionID != t.ActiveVersionID {
+			if arg.TemplateVersionID != t.ActiveVersionID && !q.isTemplateRolloutCandidate(ctx, t.ID, arg.TemplateVersionID) {
 				if err = q.authorizeContext(ctx, policy.ActionUpdate, t); err != nil {
 					return xerrors.Errorf("cannot use non-active version: %w", err)
 				}
@@ -3284,6 +3734,23 @@ func (q *querier) InsertWorkspaceBuild(ctx context.Context, arg database.InsertW
 	return q.db.InsertWorkspaceBuild(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceBuildApproval(ctx context.Context, arg database.InsertWorkspaceBuildApprovalParams) (database.WorkspaceBuildApproval, error) {
+	build, err := q.db.GetWorkspaceBuildByID(ctx, arg.WorkspaceBuildID)
+	if err != nil {
+		return database.WorkspaceBuildApproval{}, err
+	}
+	workspace, err := q.db.GetWorkspaceByID(ctx, build.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceBuildApproval{}, err
+	}
+	// Only start builds require approval, so the approval is inserted with the
+	// same permission as the build.
+	if err := q.authorizeContext(ctx, policy.ActionWorkspaceStart, workspace); err != nil {
+		return database.WorkspaceBuildApproval{}, err
+	}
+	return q.db.InsertWorkspaceBuildApproval(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceBuildParameters(ctx context.Context, arg database.InsertWorkspaceBuildParametersParams) error {
 	// TODO: Optimize this. We always have the workspace and build already fetched.
 	build, err := q.db.GetWorkspaceBuildByID(ctx, arg.WorkspaceBuildID)
@@ -3329,6 +3796,46 @@ func (q *querier) InsertWorkspaceResourceMetadata(ctx context.Context, arg datab
 	return q.db.InsertWorkspaceResourceMetadata(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceScheduledAction(ctx context.Context, arg database.InsertWorkspaceScheduledActionParams) (database.WorkspaceScheduledAction, error) {
+	w, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceScheduledAction{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, w); err != nil {
+		return database.WorkspaceScheduledAction{}, err
+	}
+	return q.db.InsertWorkspaceScheduledAction(ctx, arg)
+}
+
+func (q *querier) InsertWorkspaceSessionRecording(ctx context.Context, arg database.InsertWorkspaceSessionRecordingParams) (database.WorkspaceSessionRecording, error) {
+	w, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceSessionRecording{}, xerrors.Errorf("get workspace by id: %w", err)
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, w); err != nil {
+		return database.WorkspaceSessionRecording{}, err
+	}
+	return q.db.InsertWorkspaceSessionRecording(ctx, arg)
+}
+
+func (q *querier) InsertWorkspaceSessionRecordingChunk(ctx context.Context, arg database.InsertWorkspaceSessionRecordingChunkParams) (int64, error) {
+	if err := q.authorizeSessionRecordingUpdate(ctx, arg.RecordingID); err != nil {
+		return 0, err
+	}
+	return q.db.InsertWorkspaceSessionRecordingChunk(ctx, arg)
+}
+
+func (q *querier) InsertWorkspaceSnapshot(ctx context.Context, arg database.InsertWorkspaceSnapshotParams) (database.WorkspaceSnapshot, error) {
+	w, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceSnapshot{}, xerrors.Errorf("get workspace by id: %w", err)
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, w); err != nil {
+		return database.WorkspaceSnapshot{}, err
+	}
+	return q.db.InsertWorkspaceSnapshot(ctx, arg)
+}
+
 func (q *querier) ListProvisionerKeysByOrganization(ctx context.Context, organizationID uuid.UUID) ([]database.ProvisionerKey, error) {
 	return fetchWithPostFilter(q.auth, policy.ActionRead, q.db.ListProvisionerKeysByOrganization)(ctx, organizationID)
 }
@@ -3398,6 +3905,13 @@ func (q *querier) RegisterWorkspaceProxy(ctx context.Context, arg database.Regis
 	return updateWithReturn(q.log, q.auth, fetch, q.db.RegisterWorkspaceProxy)(ctx, arg)
 }
 
+func (q *querier) RejectExpiredWorkspaceBuildApprovals(ctx context.Context, arg database.RejectExpiredWorkspaceBuildApprovalsParams) ([]database.WorkspaceBuildApproval, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.RejectExpiredWorkspaceBuildApprovals(ctx, arg)
+}
+
 func (q *querier) RemoveUserFromAllGroups(ctx context.Context, userID uuid.UUID) error {
 	// This is a system function to clear user groups in group sync.
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
@@ -3455,6 +3969,20 @@ func (q *querier) UpdateAPIKeyByID(ctx context.Context, arg database.UpdateAPIKe
 	return update(q.log, q.auth, fetch, q.db.UpdateAPIKeyByID)(ctx, arg)
 }
 
+func (q *querier) UpdateCostBudgetNotifiedAtByGroupID(ctx context.Context, arg database.UpdateCostBudgetNotifiedAtByGroupIDParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateCostBudgetNotifiedAtByGroupID(ctx, arg)
+}
+
+func (q *querier) UpdateCostBudgetStoppedAtByGroupID(ctx context.Context, arg database.UpdateCostBudgetStoppedAtByGroupIDParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateCostBudgetStoppedAtByGroupID(ctx, arg)
+}
+
 func (q *querier) UpdateCryptoKeyDeletesAt(ctx context.Context, arg database.UpdateCryptoKeyDeletesAtParams) (database.CryptoKey, error) {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceCryptoKey); err != nil {
 		return database.CryptoKey{}, err
@@ -3462,6 +3990,13 @@ func (q *querier) UpdateCryptoKeyDeletesAt(ctx context.Context, arg database.Upd
 	return q.db.UpdateCryptoKeyDeletesAt(ctx, arg)
 }
 
+func (q *querier) UpdateCryptoKeySecret(ctx context.Context, arg database.UpdateCryptoKeySecretParams) (database.CryptoKey, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceCryptoKey); err != nil {
+		return database.CryptoKey{}, err
+	}
+	return q.db.UpdateCryptoKeySecret(ctx, arg)
+}
+
 func (q *querier) UpdateCustomRole(ctx context.Context, arg database.UpdateCustomRoleParams) (database.CustomRole, error) {
 	if arg.OrganizationID.UUID != uuid.Nil {
 		if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceAssignOrgRole.InOrg(arg.OrganizationID.UUID)); err != nil {
@@ -3579,6 +4114,23 @@ func (q *querier) UpdateOAuth2ProviderAppSecretByID(ctx context.Context, arg dat
 	return q.db.UpdateOAuth2ProviderAppSecretByID(ctx, arg)
 }
 
+func (q *querier) UpdateOAuth2ProviderDeviceCodeLastPolledAtByID(ctx context.Context, arg database.UpdateOAuth2ProviderDeviceCodeLastPolledAtByIDParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateOAuth2ProviderDeviceCodeLastPolledAtByID(ctx, arg)
+}
+
+func (q *querier) UpdateOAuth2ProviderDeviceCodeStatusByID(ctx context.Context, arg database.UpdateOAuth2ProviderDeviceCodeStatusByIDParams) (database.OAuth2ProviderDeviceCode, error) {
+	// Approving or denying a device is the same as a user granting or refusing
+	// an authorization code, so it is authorized the same way.
+	if err := q.authorizeContext(ctx, policy.ActionCreate,
+		rbac.ResourceOauth2AppCodeToken.WithOwner(arg.UserID.UUID.String())); err != nil {
+		return database.OAuth2ProviderDeviceCode{}, err
+	}
+	return q.db.UpdateOAuth2ProviderDeviceCodeStatusByID(ctx, arg)
+}
+
 func (q *querier) UpdateOrganization(ctx context.Context, arg database.UpdateOrganizationParams) (database.Organization, error) {
 	fetch := func(ctx context.Context, arg database.UpdateOrganizationParams) (database.Organization, error) {
 		return q.db.GetOrganizationByID(ctx, arg.ID)
@@ -3683,6 +4235,17 @@ func (q *querier) UpdateReplica(ctx context.Context, arg database.UpdateReplicaP
 	return q.db.UpdateReplica(ctx, arg)
 }
 
+func (q *querier) UpdateSecretByID(ctx context.Context, arg database.UpdateSecretByIDParams) (database.Secret, error) {
+	secret, err := q.db.GetSecretByID(ctx, arg.ID)
+	if err != nil {
+		return database.Secret{}, err
+	}
+	if err := q.authorizeSecretScope(ctx, true, secret.UserID, secret.OrganizationID, secret.TemplateID); err != nil {
+		return database.Secret{}, err
+	}
+	return q.db.UpdateSecretByID(ctx, arg)
+}
+
 func (q *querier) UpdateTailnetPeerStatusByCoordinator(ctx context.Context, arg database.UpdateTailnetPeerStatusByCoordinatorParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceTailnetCoordinator); err != nil {
 		return err
@@ -3725,6 +4288,28 @@ func (q *querier) UpdateTemplateMetaByID(ctx context.Context, arg database.Updat
 	return update(q.log, q.auth, fetch, q.db.UpdateTemplateMetaByID)(ctx, arg)
 }
 
+func (q *querier) UpdateTemplateRolloutByID(ctx context.Context, arg database.UpdateTemplateRolloutByIDParams) (database.TemplateRollout, error) {
+	rollout, err := q.db.GetTemplateRolloutByID(ctx, arg.ID)
+	if err != nil {
+		return database.TemplateRollout{}, err
+	}
+	if err := q.authorizeTemplateRollout(ctx, policy.ActionUpdate, rollout.TemplateID); err != nil {
+		return database.TemplateRollout{}, err
+	}
+	return q.db.UpdateTemplateRolloutByID(ctx, arg)
+}
+
+func (q *querier) UpdateTemplateRolloutStatusByID(ctx context.Context, arg database.UpdateTemplateRolloutStatusByIDParams) (database.TemplateRollout, error) {
+	rollout, err := q.db.GetTemplateRolloutByID(ctx, arg.ID)
+	if err != nil {
+		return database.TemplateRollout{}, err
+	}
+	if err := q.authorizeTemplateRollout(ctx, policy.ActionUpdate, rollout.TemplateID); err != nil {
+		return database.TemplateRollout{}, err
+	}
+	return q.db.UpdateTemplateRolloutStatusByID(ctx, arg)
+}
+
 func (q *querier) UpdateTemplateScheduleByID(ctx context.Context, arg database.UpdateTemplateScheduleByIDParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateTemplateScheduleByIDParams) (database.Template, error) {
 		return q.db.GetTemplateByID(ctx, arg.ID)
@@ -3895,6 +4480,13 @@ func (q *querier) UpdateUserLoginType(ctx context.Context, arg database.UpdateUs
 	return q.db.UpdateUserLoginType(ctx, arg)
 }
 
+func (q *querier) UpdateUserNotificationMethodPreferences(ctx context.Context, arg database.UpdateUserNotificationMethodPreferencesParams) (int64, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceNotificationPreference.WithOwner(arg.UserID.String())); err != nil {
+		return -1, err
+	}
+	return q.db.UpdateUserNotificationMethodPreferences(ctx, arg)
+}
+
 func (q *querier) UpdateUserNotificationPreferences(ctx context.Context, arg database.UpdateUserNotificationPreferencesParams) (int64, error) {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceNotificationPreference.WithOwner(arg.UserID.String())); err != nil {
 		return -1, err
@@ -4069,6 +4661,24 @@ func (q *querier) UpdateWorkspaceAutostart(ctx context.Context, arg database.Upd
 	return update(q.log, q.auth, fetch, q.db.UpdateWorkspaceAutostart)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceBuildApprovalByBuildID(ctx context.Context, arg database.UpdateWorkspaceBuildApprovalByBuildIDParams) (database.WorkspaceBuildApproval, error) {
+	// Reviewers may not have access to the workspace, so callers are expected
+	// to check that the user can review builds of the template and use a
+	// system context.
+	build, err := q.db.GetWorkspaceBuildByID(ctx, arg.WorkspaceBuildID)
+	if err != nil {
+		return database.WorkspaceBuildApproval{}, err
+	}
+	workspace, err := q.db.GetWorkspaceByID(ctx, build.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceBuildApproval{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, workspace); err != nil {
+		return database.WorkspaceBuildApproval{}, err
+	}
+	return q.db.UpdateWorkspaceBuildApprovalByBuildID(ctx, arg)
+}
+
 // UpdateWorkspaceBuildCostByID is used by the provisioning system to update the cost of a workspace build.
 func (q *querier) UpdateWorkspaceBuildCostByID(ctx context.Context, arg database.UpdateWorkspaceBuildCostByIDParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
@@ -4151,6 +4761,27 @@ func (q *querier) UpdateWorkspaceProxyDeleted(ctx context.Context, arg database.
 	return deleteQ(q.log, q.auth, fetch, q.db.UpdateWorkspaceProxyDeleted)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceScheduledActionRunByID(ctx context.Context, arg database.UpdateWorkspaceScheduledActionRunByIDParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateWorkspaceScheduledActionRunByID(ctx, arg)
+}
+
+func (q *querier) UpdateWorkspaceSessionRecordingByID(ctx context.Context, arg database.UpdateWorkspaceSessionRecordingByIDParams) error {
+	if err := q.authorizeSessionRecordingUpdate(ctx, arg.ID); err != nil {
+		return err
+	}
+	return q.db.UpdateWorkspaceSessionRecordingByID(ctx, arg)
+}
+
+func (q *querier) UpdateWorkspaceSnapshotArtifactsByID(ctx context.Context, arg database.UpdateWorkspaceSnapshotArtifactsByIDParams) error {
+	fetch := func(ctx context.Context, arg database.UpdateWorkspaceSnapshotArtifactsByIDParams) (database.WorkspaceSnapshot, error) {
+		return q.db.GetWorkspaceSnapshotByID(ctx, arg.ID)
+	}
+	return update(q.log, q.auth, fetch, q.db.UpdateWorkspaceSnapshotArtifactsByID)(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceTTL(ctx context.Context, arg database.UpdateWorkspaceTTLParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateWorkspaceTTLParams) (database.Workspace, error) {
 		return q.db.GetWorkspaceByID(ctx, arg.ID)
@@ -4208,6 +4839,17 @@ func (q *querier) UpsertCoordinatorResumeTokenSigningKey(ctx context.Context, va
 	return q.db.UpsertCoordinatorResumeTokenSigningKey(ctx, value)
 }
 
+func (q *querier) UpsertCostBudget(ctx context.Context, arg database.UpsertCostBudgetParams) (database.CostBudget, error) {
+	group, err := q.db.GetGroupByID(ctx, arg.GroupID)
+	if err != nil {
+		return database.CostBudget{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, group); err != nil {
+		return database.CostBudget{}, err
+	}
+	return q.db.UpsertCostBudget(ctx, arg)
+}
+
 func (q *querier) UpsertDefaultProxy(ctx context.Context, arg database.UpsertDefaultProxyParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return err
@@ -4257,6 +4899,20 @@ func (q *querier) UpsertLogoURL(ctx context.Context, value string) error {
 	return q.db.UpsertLogoURL(ctx, value)
 }
 
+func (q *querier) UpsertNetworkConnectionReports(ctx context.Context, arg database.UpsertNetworkConnectionReportsParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpsertNetworkConnectionReports(ctx, arg)
+}
+
+func (q *querier) UpsertNetworkLatencyReports(ctx context.Context, arg database.UpsertNetworkLatencyReportsParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpsertNetworkLatencyReports(ctx, arg)
+}
+
 func (q *querier) UpsertNotificationReportGeneratorLog(ctx context.Context, arg database.UpsertNotificationReportGeneratorLogParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
 		return err
@@ -4359,6 +5015,27 @@ func (q *querier) UpsertWorkspaceAgentPortShare(ctx context.Context, arg databas
 	return q.db.UpsertWorkspaceAgentPortShare(ctx, arg)
 }
 
+func (q *querier) UpsertWorkspaceAgentProcessGroups(ctx context.Context, arg database.UpsertWorkspaceAgentProcessGroupsParams) error {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.WorkspaceAgentID)
+	if err != nil {
+		return err
+	}
+
+	err = q.authorizeContext(ctx, policy.ActionUpdate, workspace)
+	if err != nil {
+		return err
+	}
+
+	return q.db.UpsertWorkspaceAgentProcessGroups(ctx, arg)
+}
+
+func (q *querier) UpsertWorkspaceCosts(ctx context.Context) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpsertWorkspaceCosts(ctx)
+}
+
 func (q *querier) GetAuthorizedTemplates(ctx context.Context, arg database.GetTemplatesWithFilterParams, _ rbac.PreparedAuthorized) ([]database.Template, error) {
 	// TODO Delete this function, all GetTemplates should be authorized. For now just call getTemplates on the authz querier.
 	return q.GetTemplatesWithFilter(ctx, arg)
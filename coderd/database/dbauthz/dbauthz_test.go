@@ -493,6 +493,27 @@ func (s *MethodTestSuite) TestGroup() {
 			ID: g.ID,
 		}).Asserts(g, policy.ActionUpdate)
 	}))
+	s.Run("UpsertCostBudget", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		g := dbgen.Group(s.T(), db, database.Group{})
+		check.Args(database.UpsertCostBudgetParams{
+			GroupID:        g.ID,
+			OrganizationID: g.OrganizationID,
+			MonthlyLimit:   100,
+		}).Asserts(g, policy.ActionUpdate)
+	}))
+	s.Run("GetCostBudgetByGroupID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		g := dbgen.Group(s.T(), db, database.Group{})
+		b := dbgen.CostBudget(s.T(), db, database.CostBudget{GroupID: g.ID, OrganizationID: g.OrganizationID})
+		check.Args(g.ID).Asserts(g, policy.ActionRead).Returns(b)
+	}))
+	s.Run("DeleteCostBudgetByGroupID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		g := dbgen.Group(s.T(), db, database.Group{})
+		_ = dbgen.CostBudget(s.T(), db, database.CostBudget{GroupID: g.ID, OrganizationID: g.OrganizationID})
+		check.Args(g.ID).Asserts(g, policy.ActionUpdate).Returns()
+	}))
 }
 
 func (s *MethodTestSuite) TestProvisionerJob() {
@@ -1091,6 +1112,63 @@ func (s *MethodTestSuite) TestTemplate() {
 		t1 := dbgen.Template(s.T(), db, database.Template{})
 		check.Args(t1.ID).Asserts(t1, policy.ActionUpdate)
 	}))
+	s.Run("InsertTemplateRollout", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(database.InsertTemplateRolloutParams{
+			ID:             uuid.New(),
+			TemplateID:     t1.ID,
+			Percent:        10,
+			MinBuilds:      1,
+			MaxFailureRate: 0.5,
+		}).Asserts(t1, policy.ActionUpdate)
+	}))
+	s.Run("GetTemplateRolloutByID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		r := dbgen.TemplateRollout(s.T(), db, database.TemplateRollout{TemplateID: t1.ID})
+		check.Args(r.ID).Asserts(t1, policy.ActionRead).Returns(r)
+	}))
+	s.Run("GetActiveTemplateRolloutByTemplateID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		r := dbgen.TemplateRollout(s.T(), db, database.TemplateRollout{TemplateID: t1.ID})
+		check.Args(t1.ID).Asserts(t1, policy.ActionRead).Returns(r)
+	}))
+	s.Run("GetTemplateRolloutsByTemplateID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		r := dbgen.TemplateRollout(s.T(), db, database.TemplateRollout{TemplateID: t1.ID})
+		check.Args(t1.ID).Asserts(t1, policy.ActionRead).Returns([]database.TemplateRollout{r})
+	}))
+	s.Run("GetTemplateRolloutBuildCounts", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID: uuid.NullUUID{UUID: t1.ID, Valid: true},
+		})
+		check.Args(database.GetTemplateRolloutBuildCountsParams{
+			TemplateVersionID: tv.ID,
+		}).Asserts(t1, policy.ActionRead)
+	}))
+	s.Run("UpdateTemplateRolloutByID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		r := dbgen.TemplateRollout(s.T(), db, database.TemplateRollout{TemplateID: t1.ID})
+		check.Args(database.UpdateTemplateRolloutByIDParams{
+			ID:      r.ID,
+			Percent: 50,
+		}).Asserts(t1, policy.ActionUpdate)
+	}))
+	s.Run("UpdateTemplateRolloutStatusByID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		r := dbgen.TemplateRollout(s.T(), db, database.TemplateRollout{TemplateID: t1.ID})
+		check.Args(database.UpdateTemplateRolloutStatusByIDParams{
+			ID:     r.ID,
+			Status: database.TemplateRolloutStatusCanceled,
+		}).Asserts(t1, policy.ActionUpdate)
+	}))
 	s.Run("GetTemplateVersionByID", s.Subtest(func(db database.Store, check *expects) {
 		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
 		t1 := dbgen.Template(s.T(), db, database.Template{})
@@ -1323,6 +1401,25 @@ func (s *MethodTestSuite) TestTemplate() {
 	s.Run("UpsertTemplateUsageStats", s.Subtest(func(db database.Store, check *expects) {
 		check.Asserts(rbac.ResourceSystem, policy.ActionUpdate)
 	}))
+	s.Run("GetWorkspaceCostInsightsByInterval", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetWorkspaceCostInsightsByIntervalParams{
+			IntervalDays: 1,
+			StartTime:    dbtime.Now().Add(-time.Hour * 24 * 7),
+			EndTime:      dbtime.Now(),
+		}).Asserts(rbac.ResourceTemplate, policy.ActionViewInsights)
+	}))
+	s.Run("GetWorkspaceCostInsightsByTemplate", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetWorkspaceCostInsightsByTemplateParams{}).Asserts(rbac.ResourceTemplate, policy.ActionViewInsights)
+	}))
+	s.Run("GetWorkspaceCostInsightsByUser", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetWorkspaceCostInsightsByUserParams{}).Asserts(rbac.ResourceTemplate, policy.ActionViewInsights)
+	}))
+	s.Run("GetWorkspaceCostInsightsByGroup", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetWorkspaceCostInsightsByGroupParams{}).Asserts(rbac.ResourceTemplate, policy.ActionViewInsights)
+	}))
+	s.Run("UpsertWorkspaceCosts", s.Subtest(func(db database.Store, check *expects) {
+		check.Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
 }
 
 func (s *MethodTestSuite) TestUser() {
@@ -1882,6 +1979,47 @@ func (s *MethodTestSuite) TestWorkspace() {
 			Keys:             []string{"test"},
 		}).Asserts(w, policy.ActionRead)
 	}))
+	s.Run("GetWorkspaceAgentProcessGroups", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
+			Type: database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		b := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{
+			JobID:             j.ID,
+			WorkspaceID:       w.ID,
+			TemplateVersionID: tv.ID,
+		})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: b.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		err := db.UpsertWorkspaceAgentProcessGroups(context.Background(), database.UpsertWorkspaceAgentProcessGroupsParams{
+			WorkspaceAgentID: agt.ID,
+			Name:             []string{"sessions"},
+			CPUUsageUsec:     []int64{1},
+			CPUCores:         []float64{0.5},
+			CPULimitCores:    []float64{0},
+			MemoryBytes:      []int64{1024},
+			MemoryLimitBytes: []int64{0},
+			OOMKills:         []int64{0},
+			UpdatedAt:        dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(agt.ID).Asserts(w, policy.ActionRead)
+	}))
 	s.Run("GetWorkspaceAgentByInstanceID", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		o := dbgen.Organization(s.T(), db, database.Organization{})
@@ -1974,6 +2112,37 @@ func (s *MethodTestSuite) TestWorkspace() {
 			WorkspaceAgentID: agt.ID,
 		}).Asserts(w, policy.ActionUpdate).Returns()
 	}))
+	s.Run("UpsertWorkspaceAgentProcessGroups", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
+			Type: database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		b := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{
+			JobID:             j.ID,
+			WorkspaceID:       w.ID,
+			TemplateVersionID: tv.ID,
+		})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: b.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		check.Args(database.UpsertWorkspaceAgentProcessGroupsParams{
+			WorkspaceAgentID: agt.ID,
+		}).Asserts(w, policy.ActionUpdate).Returns()
+	}))
 	s.Run("UpdateWorkspaceAgentLogOverflowByID", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		o := dbgen.Organization(s.T(), db, database.Organization{})
@@ -2894,6 +3063,350 @@ func (s *MethodTestSuite) TestWorkspace() {
 			ID: w.ID,
 		}).Asserts(w, policy.ActionUpdate).Returns()
 	}))
+	s.Run("InsertWorkspaceSnapshot", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		check.Args(database.InsertWorkspaceSnapshotParams{
+			ID:             uuid.New(),
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+			TemplateID:     tpl.ID,
+			WorkspaceID:    w.ID,
+			BuildID:        uuid.New(),
+			Name:           "snapshot",
+		}).Asserts(w, policy.ActionUpdate)
+	}))
+	s.Run("GetWorkspaceSnapshotByID", s.Subtest(func(db database.Store, check *expects) {
+		snap := dbgen.WorkspaceSnapshot(s.T(), db, database.WorkspaceSnapshot{})
+		check.Args(snap.ID).Asserts(snap, policy.ActionRead).Returns(snap)
+	}))
+	s.Run("GetWorkspaceSnapshotsByOwnerAndTemplate", s.Subtest(func(db database.Store, check *expects) {
+		snap := dbgen.WorkspaceSnapshot(s.T(), db, database.WorkspaceSnapshot{})
+		check.Args(database.GetWorkspaceSnapshotsByOwnerAndTemplateParams{
+			OwnerID:    snap.OwnerID,
+			TemplateID: snap.TemplateID,
+		}).Asserts(snap, policy.ActionRead).Returns([]database.WorkspaceSnapshot{snap})
+	}))
+	s.Run("UpdateWorkspaceSnapshotArtifactsByID", s.Subtest(func(db database.Store, check *expects) {
+		snap := dbgen.WorkspaceSnapshot(s.T(), db, database.WorkspaceSnapshot{})
+		check.Args(database.UpdateWorkspaceSnapshotArtifactsByIDParams{
+			ID:        snap.ID,
+			Artifacts: json.RawMessage(`{"home":"snap-123"}`),
+		}).Asserts(snap, policy.ActionUpdate).Returns()
+	}))
+	s.Run("InsertWorkspaceSessionRecording", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		check.Args(database.InsertWorkspaceSessionRecordingParams{
+			ID:          uuid.New(),
+			WorkspaceID: w.ID,
+			AgentID:     uuid.New(),
+			UserID:      u.ID,
+			Type:        database.WorkspaceSessionRecordingTypeSsh,
+		}).Asserts(w, policy.ActionUpdate)
+	}))
+	s.Run("InsertWorkspaceSessionRecordingChunk", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		rec := dbgen.WorkspaceSessionRecording(s.T(), db, database.WorkspaceSessionRecording{
+			WorkspaceID: w.ID,
+			UserID:      u.ID,
+		})
+		check.Args(database.InsertWorkspaceSessionRecordingChunkParams{
+			RecordingID: rec.ID,
+			Sequence:    0,
+			Data:        []byte("{}\n"),
+		}).Asserts(w, policy.ActionUpdate).Returns(int64(1))
+	}))
+	s.Run("UpdateWorkspaceSessionRecordingByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		rec := dbgen.WorkspaceSessionRecording(s.T(), db, database.WorkspaceSessionRecording{
+			WorkspaceID: w.ID,
+			UserID:      u.ID,
+		})
+		check.Args(database.UpdateWorkspaceSessionRecordingByIDParams{
+			ID:        rec.ID,
+			SizeDelta: 3,
+		}).Asserts(w, policy.ActionUpdate).Returns()
+	}))
+	s.Run("InsertWorkspaceBuildApproval", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
+			Type: database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		b := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{
+			JobID:             j.ID,
+			WorkspaceID:       w.ID,
+			TemplateVersionID: tv.ID,
+		})
+		check.Args(database.InsertWorkspaceBuildApprovalParams{
+			WorkspaceBuildID: b.ID,
+			CreatedAt:        dbtime.Now(),
+			UpdatedAt:        dbtime.Now(),
+		}).Asserts(w, policy.ActionWorkspaceStart)
+	}))
+	s.Run("GetWorkspaceBuildApprovalByBuildID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
+			Type: database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		b := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{
+			JobID:             j.ID,
+			WorkspaceID:       w.ID,
+			TemplateVersionID: tv.ID,
+		})
+		approval, err := db.InsertWorkspaceBuildApproval(context.Background(), database.InsertWorkspaceBuildApprovalParams{
+			WorkspaceBuildID: b.ID,
+			CreatedAt:        dbtime.Now(),
+			UpdatedAt:        dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(b.ID).Asserts(w, policy.ActionRead).Returns(approval)
+	}))
+	s.Run("GetPendingWorkspaceBuildApprovalsByTemplateID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
+			Type: database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		b := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{
+			JobID:             j.ID,
+			WorkspaceID:       w.ID,
+			TemplateVersionID: tv.ID,
+		})
+		_, err := db.InsertWorkspaceBuildApproval(context.Background(), database.InsertWorkspaceBuildApprovalParams{
+			WorkspaceBuildID: b.ID,
+			CreatedAt:        dbtime.Now(),
+			UpdatedAt:        dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(tpl.ID).Asserts(tpl, policy.ActionRead)
+	}))
+	s.Run("UpdateWorkspaceBuildApprovalByBuildID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
+			Type: database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		b := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{
+			JobID:             j.ID,
+			WorkspaceID:       w.ID,
+			TemplateVersionID: tv.ID,
+		})
+		_, err := db.InsertWorkspaceBuildApproval(context.Background(), database.InsertWorkspaceBuildApprovalParams{
+			WorkspaceBuildID: b.ID,
+			CreatedAt:        dbtime.Now(),
+			UpdatedAt:        dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.UpdateWorkspaceBuildApprovalByBuildIDParams{
+			WorkspaceBuildID: b.ID,
+			UpdatedAt:        dbtime.Now(),
+			Status:           database.WorkspaceBuildApprovalStatusApproved,
+			ReviewerID:       uuid.NullUUID{UUID: u.ID, Valid: true},
+		}).Asserts(w, policy.ActionUpdate)
+	}))
+	s.Run("RejectExpiredWorkspaceBuildApprovals", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.RejectExpiredWorkspaceBuildApprovalsParams{
+			Now:    dbtime.Now(),
+			Reason: "timed out",
+		}).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
+	s.Run("InsertWorkspaceScheduledAction", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		check.Args(database.InsertWorkspaceScheduledActionParams{
+			ID:          uuid.New(),
+			WorkspaceID: w.ID,
+			Action:      database.WorkspaceScheduledActionTypeRestart,
+			Schedule:    "CRON_TZ=UTC 0 6 * * *",
+			NextRunAt:   dbtime.Now(),
+		}).Asserts(w, policy.ActionUpdate)
+	}))
+	s.Run("GetWorkspaceScheduledActionByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		action := dbgen.WorkspaceScheduledAction(s.T(), db, database.WorkspaceScheduledAction{WorkspaceID: w.ID})
+		check.Args(action.ID).Asserts(w, policy.ActionRead).Returns(action)
+	}))
+	s.Run("GetWorkspaceScheduledActionsByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		action := dbgen.WorkspaceScheduledAction(s.T(), db, database.WorkspaceScheduledAction{WorkspaceID: w.ID})
+		check.Args(w.ID).Asserts(w, policy.ActionRead).Returns([]database.WorkspaceScheduledAction{action})
+	}))
+	s.Run("DeleteWorkspaceScheduledActionByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		action := dbgen.WorkspaceScheduledAction(s.T(), db, database.WorkspaceScheduledAction{WorkspaceID: w.ID})
+		check.Args(action.ID).Asserts(w, policy.ActionUpdate).Returns()
+	}))
+	s.Run("GetWorkspaceScheduledActionsDue", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(dbtime.Now()).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("UpdateWorkspaceScheduledActionRunByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		action := dbgen.WorkspaceScheduledAction(s.T(), db, database.WorkspaceScheduledAction{WorkspaceID: w.ID})
+		check.Args(database.UpdateWorkspaceScheduledActionRunByIDParams{
+			ID:        action.ID,
+			UpdatedAt: dbtime.Now(),
+			NextRunAt: dbtime.Now(),
+			LastRunAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+		}).Asserts(rbac.ResourceSystem, policy.ActionUpdate).Returns()
+	}))
+	s.Run("GetWorkspaceSessionRecordingByID", s.Subtest(func(db database.Store, check *expects) {
+		rec := dbgen.WorkspaceSessionRecording(s.T(), db, database.WorkspaceSessionRecording{})
+		check.Args(rec.ID).Asserts(rbac.ResourceAuditLog, policy.ActionRead).Returns(rec)
+	}))
+	s.Run("GetWorkspaceSessionRecordingsByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		rec := dbgen.WorkspaceSessionRecording(s.T(), db, database.WorkspaceSessionRecording{})
+		check.Args(rec.WorkspaceID).Asserts(rbac.ResourceAuditLog, policy.ActionRead).Returns([]database.WorkspaceSessionRecording{rec})
+	}))
+	s.Run("GetWorkspaceSessionRecordingChunksByRecordingID", s.Subtest(func(db database.Store, check *expects) {
+		rec := dbgen.WorkspaceSessionRecording(s.T(), db, database.WorkspaceSessionRecording{})
+		check.Args(database.GetWorkspaceSessionRecordingChunksByRecordingIDParams{RecordingID: rec.ID, LimitOpt: 10}).Asserts(rbac.ResourceAuditLog, policy.ActionRead)
+	}))
 	s.Run("GetWorkspaceByWorkspaceAppID", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		o := dbgen.Organization(s.T(), db, database.Organization{})
@@ -3430,6 +3943,103 @@ func (s *MethodTestSuite) TestDBCrypt() {
 	}))
 }
 
+func (s *MethodTestSuite) TestSecrets() {
+	s.Run("InsertSecret", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.InsertSecretParams{
+			ID:      uuid.New(),
+			Name:    "token",
+			Value:   "secret",
+			EnvName: "TOKEN",
+			UserID:  uuid.NullUUID{UUID: u.ID, Valid: true},
+		}).Asserts(rbac.ResourceUserObject(u.ID), policy.ActionUpdatePersonal)
+	}))
+	s.Run("Organization/InsertSecret", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		check.Args(database.InsertSecretParams{
+			ID:             uuid.New(),
+			Name:           "token",
+			Value:          "secret",
+			EnvName:        "TOKEN",
+			OrganizationID: uuid.NullUUID{UUID: o.ID, Valid: true},
+		}).Asserts(rbac.ResourceOrganization.WithID(o.ID).InOrg(o.ID), policy.ActionUpdate)
+	}))
+	s.Run("Template/InsertSecret", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		check.Args(database.InsertSecretParams{
+			ID:         uuid.New(),
+			Name:       "token",
+			Value:      "secret",
+			EnvName:    "TOKEN",
+			TemplateID: uuid.NullUUID{UUID: tpl.ID, Valid: true},
+		}).Asserts(tpl, policy.ActionUpdate)
+	}))
+	s.Run("GetSecretByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		secret := dbgen.Secret(s.T(), db, database.Secret{
+			UserID: uuid.NullUUID{UUID: u.ID, Valid: true},
+		})
+		check.Args(secret.ID).Asserts(rbac.ResourceUserObject(u.ID), policy.ActionReadPersonal).Returns(secret)
+	}))
+	s.Run("GetSecretByScopeAndName", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		secret := dbgen.Secret(s.T(), db, database.Secret{
+			UserID: uuid.NullUUID{UUID: u.ID, Valid: true},
+		})
+		check.Args(database.GetSecretByScopeAndNameParams{
+			UserID: secret.UserID,
+			Name:   secret.Name,
+		}).Asserts(rbac.ResourceUserObject(u.ID), policy.ActionReadPersonal).Returns(secret)
+	}))
+	s.Run("GetSecretsByScope", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		secret := dbgen.Secret(s.T(), db, database.Secret{
+			OrganizationID: uuid.NullUUID{UUID: o.ID, Valid: true},
+		})
+		check.Args(database.GetSecretsByScopeParams{
+			OrganizationID: secret.OrganizationID,
+		}).Asserts(rbac.ResourceOrganization.WithID(o.ID).InOrg(o.ID), policy.ActionUpdate).Returns([]database.Secret{secret})
+	}))
+	s.Run("GetSecrets", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("GetSecretsForWorkspace", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetSecretsForWorkspaceParams{
+			OwnerID:        uuid.New(),
+			TemplateID:     uuid.New(),
+			OrganizationID: uuid.New(),
+		}).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("UpdateSecretByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		secret := dbgen.Secret(s.T(), db, database.Secret{
+			TemplateID: uuid.NullUUID{UUID: tpl.ID, Valid: true},
+		})
+		check.Args(database.UpdateSecretByIDParams{
+			ID:      secret.ID,
+			Value:   "updated",
+			EnvName: secret.EnvName,
+		}).Asserts(tpl, policy.ActionUpdate)
+	}))
+	s.Run("DeleteSecretByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		secret := dbgen.Secret(s.T(), db, database.Secret{
+			UserID: uuid.NullUUID{UUID: u.ID, Valid: true},
+		})
+		check.Args(secret.ID).Asserts(rbac.ResourceUserObject(u.ID), policy.ActionUpdatePersonal).Returns()
+	}))
+}
+
 func (s *MethodTestSuite) TestCryptoKeys() {
 	s.Run("GetCryptoKeys", s.Subtest(func(db database.Store, check *expects) {
 		check.Args().
@@ -3479,6 +4089,17 @@ func (s *MethodTestSuite) TestCryptoKeys() {
 			DeletesAt: sql.NullTime{Time: time.Now(), Valid: true},
 		}).Asserts(rbac.ResourceCryptoKey, policy.ActionUpdate)
 	}))
+	s.Run("UpdateCryptoKeySecret", s.Subtest(func(db database.Store, check *expects) {
+		key := dbgen.CryptoKey(s.T(), db, database.CryptoKey{
+			Feature:  database.CryptoKeyFeatureWorkspaceAppsAPIKey,
+			Sequence: 4,
+		})
+		check.Args(database.UpdateCryptoKeySecretParams{
+			Feature:  key.Feature,
+			Sequence: key.Sequence,
+			Secret:   key.Secret,
+		}).Asserts(rbac.ResourceCryptoKey, policy.ActionUpdate)
+	}))
 	s.Run("GetCryptoKeysByFeature", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(database.CryptoKeyFeatureWorkspaceAppsAPIKey).
 			Asserts(rbac.ResourceCryptoKey, policy.ActionRead)
@@ -3627,7 +4248,19 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 		check.Args(time.Now()).Asserts(rbac.ResourceSystem, policy.ActionRead)
 	}))
 	s.Run("DeleteOldWorkspaceAgentStats", s.Subtest(func(db database.Store, check *expects) {
-		check.Args().Asserts(rbac.ResourceSystem, policy.ActionDelete)
+		check.Args(time.Time{}).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
+	s.Run("DeleteOldAuditLogs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.DeleteOldAuditLogsParams{}).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
+	s.Run("DeleteOldProvisionerJobLogs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.DeleteOldProvisionerJobLogsParams{}).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
+	s.Run("ClearOldWorkspaceBuildProvisionerStates", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.ClearOldWorkspaceBuildProvisionerStatesParams{}).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
+	s.Run("GetPurgeableRowCounts", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetPurgeableRowCountsParams{}).Asserts(rbac.ResourceSystem, policy.ActionRead)
 	}))
 	s.Run("GetProvisionerJobsCreatedAfter", s.Subtest(func(db database.Store, check *expects) {
 		// TODO: add provisioner job resource type
@@ -3805,6 +4438,14 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 			JobID: j.ID,
 		}).Asserts( /*rbac.ResourceSystem, policy.ActionCreate*/ )
 	}))
+	s.Run("InsertProvisionerJobResourceChanges", s.Subtest(func(db database.Store, check *expects) {
+		// TODO: we need to create a ProvisionerJob resource
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{})
+		check.Args(database.InsertProvisionerJobResourceChangesParams{
+			JobID:      j.ID,
+			Attributes: json.RawMessage("[]"),
+		}).Asserts( /*rbac.ResourceSystem, policy.ActionCreate*/ )
+	}))
 	s.Run("UpsertProvisionerDaemon", s.Subtest(func(db database.Store, check *expects) {
 		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
 		org := dbgen.Organization(s.T(), db, database.Organization{})
@@ -3882,6 +4523,34 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 	s.Run("GetDeploymentDAUs", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(int32(0)).Asserts(rbac.ResourceSystem, policy.ActionRead)
 	}))
+	s.Run("GetNetworkRegionInsights", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetNetworkRegionInsightsParams{
+			ReportedAfter: dbtime.Now().Add(-time.Hour),
+		}).Asserts(rbac.ResourceDeploymentStats, policy.ActionRead)
+	}))
+	s.Run("UpsertNetworkLatencyReports", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.UpsertNetworkLatencyReportsParams{
+			ClientID:   []uuid.UUID{uuid.New()},
+			ClientType: []string{"cli"},
+			RegionID:   []int32{1},
+			LatencyMS:  []float64{12.5},
+			Preferred:  []bool{true},
+			ReportedAt: []time.Time{dbtime.Now()},
+		}).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
+	s.Run("UpsertNetworkConnectionReports", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.UpsertNetworkConnectionReportsParams{
+			ClientID:     []uuid.UUID{uuid.New()},
+			RemoteNodeID: []int64{1},
+			ClientType:   []string{"cli"},
+			HomeRegionID: []int32{1},
+			Direct:       []bool{true},
+			ReportedAt:   []time.Time{dbtime.Now()},
+		}).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
+	s.Run("DeleteOldNetworkReports", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
 	s.Run("GetAppSecurityKey", s.Subtest(func(db database.Store, check *expects) {
 		check.Args().Asserts(rbac.ResourceSystem, policy.ActionRead).ErrorsWithPG(sql.ErrNoRows)
 	}))
@@ -4001,6 +4670,12 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 	s.Run("GetProvisionerJobsByIDsWithQueuePosition", s.Subtest(func(db database.Store, check *expects) {
 		check.Args([]uuid.UUID{}).Asserts()
 	}))
+	s.Run("GetPendingProvisionerJobTags", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetPendingProvisionerJobTagsParams{
+			OrganizationID: uuid.New(),
+			InitiatorID:    uuid.New(),
+		}).Asserts()
+	}))
 	s.Run("GetReplicaByID", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(uuid.New()).Asserts(rbac.ResourceSystem, policy.ActionRead).Errors(sql.ErrNoRows)
 	}))
@@ -4140,6 +4815,30 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 		t := dbgen.ProvisionerJobTimings(s.T(), db, b, 2)
 		check.Args(j.ID).Asserts(w, policy.ActionRead).Returns(t)
 	}))
+	s.Run("GetProvisionerJobResourceChangesByJobID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			OrganizationID: org.ID,
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			OwnerID:        u.ID,
+			OrganizationID: org.ID,
+			TemplateID:     tpl.ID,
+		})
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
+			Type: database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		_ = dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{JobID: j.ID, WorkspaceID: w.ID, TemplateVersionID: tv.ID})
+		c := dbgen.ProvisionerJobResourceChange(s.T(), db, database.ProvisionerJobResourceChange{JobID: j.ID})
+		check.Args(j.ID).Asserts(w, policy.ActionRead).Returns([]database.ProvisionerJobResourceChange{c})
+	}))
 	s.Run("GetWorkspaceAgentScriptTimingsByBuildID", s.Subtest(func(db database.Store, check *expects) {
 		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
 		workspace := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
@@ -4192,6 +4891,25 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 	s.Run("GetWorkspaceModulesCreatedAfter", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(dbtime.Now()).Asserts(rbac.ResourceSystem, policy.ActionRead)
 	}))
+	s.Run("GetCostBudgetsWithCost", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(dbtime.Now()).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("GetCostBudgetMemberIDs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(uuid.New()).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("GetCostBudgetWorkspaceIDs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(uuid.New()).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("UpdateCostBudgetNotifiedAtByGroupID", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.UpdateCostBudgetNotifiedAtByGroupIDParams{
+			GroupID: uuid.New(),
+		}).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
+	s.Run("UpdateCostBudgetStoppedAtByGroupID", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.UpdateCostBudgetStoppedAtByGroupIDParams{
+			GroupID: uuid.New(),
+		}).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
 }
 
 func (s *MethodTestSuite) TestNotifications() {
@@ -4206,7 +4924,7 @@ func (s *MethodTestSuite) TestNotifications() {
 		check.Args(database.BulkMarkNotificationMessagesSentParams{}).Asserts(rbac.ResourceNotificationMessage, policy.ActionUpdate)
 	}))
 	s.Run("DeleteOldNotificationMessages", s.Subtest(func(_ database.Store, check *expects) {
-		check.Args().Asserts(rbac.ResourceNotificationMessage, policy.ActionDelete)
+		check.Args(time.Time{}).Asserts(rbac.ResourceNotificationMessage, policy.ActionDelete)
 	}))
 	s.Run("EnqueueNotificationMessage", s.Subtest(func(db database.Store, check *expects) {
 		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
@@ -4265,6 +4983,14 @@ func (s *MethodTestSuite) TestNotifications() {
 			Disableds:               []bool{true, false},
 		}).Asserts(rbac.ResourceNotificationPreference.WithOwner(user.ID.String()), policy.ActionUpdate)
 	}))
+	s.Run("UpdateUserNotificationMethodPreferences", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpdateUserNotificationMethodPreferencesParams{
+			UserID:                  user.ID,
+			NotificationTemplateIds: []uuid.UUID{notifications.TemplateWorkspaceAutoUpdated, notifications.TemplateWorkspaceDeleted},
+			Methods:                 []string{string(database.NotificationMethodSlack), ""},
+		}).Asserts(rbac.ResourceNotificationPreference.WithOwner(user.ID.String()), policy.ActionUpdate)
+	}))
 }
 
 func (s *MethodTestSuite) TestOAuth2ProviderApps() {
@@ -4302,8 +5028,9 @@ func (s *MethodTestSuite) TestOAuth2ProviderApps() {
 		})
 		for i := 0; i < 5; i++ {
 			_ = dbgen.OAuth2ProviderAppToken(s.T(), db, database.OAuth2ProviderAppToken{
-				AppSecretID: secret.ID,
+				AppSecretID: uuid.NullUUID{UUID: secret.ID, Valid: true},
 				APIKeyID:    key.ID,
+				AppID:       app.ID,
 				HashPrefix:  []byte(fmt.Sprintf("%d", i)),
 			})
 		}
@@ -4470,8 +5197,9 @@ func (s *MethodTestSuite) TestOAuth2ProviderAppTokens() {
 			AppID: app.ID,
 		})
 		check.Args(database.InsertOAuth2ProviderAppTokenParams{
-			AppSecretID: secret.ID,
+			AppSecretID: uuid.NullUUID{UUID: secret.ID, Valid: true},
 			APIKeyID:    key.ID,
+			AppID:       app.ID,
 		}).Asserts(rbac.ResourceOauth2AppCodeToken.WithOwner(user.ID.String()), policy.ActionCreate)
 	}))
 	s.Run("GetOAuth2ProviderAppTokenByPrefix", s.Subtest(func(db database.Store, check *expects) {
@@ -4484,8 +5212,9 @@ func (s *MethodTestSuite) TestOAuth2ProviderAppTokens() {
 			AppID: app.ID,
 		})
 		token := dbgen.OAuth2ProviderAppToken(s.T(), db, database.OAuth2ProviderAppToken{
-			AppSecretID: secret.ID,
+			AppSecretID: uuid.NullUUID{UUID: secret.ID, Valid: true},
 			APIKeyID:    key.ID,
+			AppID:       app.ID,
 		})
 		check.Args(token.HashPrefix).Asserts(rbac.ResourceOauth2AppCodeToken.WithOwner(user.ID.String()), policy.ActionRead)
 	}))
@@ -4501,8 +5230,9 @@ func (s *MethodTestSuite) TestOAuth2ProviderAppTokens() {
 		})
 		for i := 0; i < 5; i++ {
 			_ = dbgen.OAuth2ProviderAppToken(s.T(), db, database.OAuth2ProviderAppToken{
-				AppSecretID: secret.ID,
+				AppSecretID: uuid.NullUUID{UUID: secret.ID, Valid: true},
 				APIKeyID:    key.ID,
+				AppID:       app.ID,
 				HashPrefix:  []byte(fmt.Sprintf("%d", i)),
 			})
 		}
@@ -4512,3 +5242,56 @@ func (s *MethodTestSuite) TestOAuth2ProviderAppTokens() {
 		}).Asserts(rbac.ResourceOauth2AppCodeToken.WithOwner(user.ID.String()), policy.ActionDelete)
 	}))
 }
+
+func (s *MethodTestSuite) TestOAuth2ProviderDeviceCodes() {
+	s.Run("InsertOAuth2ProviderDeviceCode", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		check.Args(database.InsertOAuth2ProviderDeviceCodeParams{
+			AppID:    app.ID,
+			UserCode: "BCDF-GHJK",
+		}).Asserts(rbac.ResourceSystem, policy.ActionCreate)
+	}))
+	s.Run("GetOAuth2ProviderDeviceCodeByPrefix", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		code := dbgen.OAuth2ProviderDeviceCode(s.T(), db, database.OAuth2ProviderDeviceCode{
+			AppID: app.ID,
+		})
+		check.Args(code.SecretPrefix).Asserts(rbac.ResourceSystem, policy.ActionRead).Returns(code)
+	}))
+	s.Run("GetOAuth2ProviderDeviceCodeByUserCode", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		code := dbgen.OAuth2ProviderDeviceCode(s.T(), db, database.OAuth2ProviderDeviceCode{
+			AppID: app.ID,
+		})
+		check.Args(code.UserCode).Asserts(rbac.ResourceSystem, policy.ActionRead).Returns(code)
+	}))
+	s.Run("UpdateOAuth2ProviderDeviceCodeStatusByID", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		code := dbgen.OAuth2ProviderDeviceCode(s.T(), db, database.OAuth2ProviderDeviceCode{
+			AppID: app.ID,
+		})
+		check.Args(database.UpdateOAuth2ProviderDeviceCodeStatusByIDParams{
+			ID:     code.ID,
+			Status: database.OAuth2ProviderDeviceCodeStatusApproved,
+			UserID: uuid.NullUUID{UUID: user.ID, Valid: true},
+		}).Asserts(rbac.ResourceOauth2AppCodeToken.WithOwner(user.ID.String()), policy.ActionCreate)
+	}))
+	s.Run("UpdateOAuth2ProviderDeviceCodeLastPolledAtByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		code := dbgen.OAuth2ProviderDeviceCode(s.T(), db, database.OAuth2ProviderDeviceCode{
+			AppID: app.ID,
+		})
+		check.Args(database.UpdateOAuth2ProviderDeviceCodeLastPolledAtByIDParams{
+			ID:           code.ID,
+			LastPolledAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+		}).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
+	s.Run("DeleteOAuth2ProviderDeviceCodeByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		code := dbgen.OAuth2ProviderDeviceCode(s.T(), db, database.OAuth2ProviderDeviceCode{
+			AppID: app.ID,
+		})
+		check.Args(code.ID).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
+}
@@ -2,8 +2,11 @@ package dbgen
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/sha256"
+	"crypto/x509"
 	"database/sql"
 	"encoding/hex"
 	"encoding/json"
@@ -125,17 +128,19 @@ func APIKey(t testing.TB, db database.Store, seed database.APIKey) (key database
 	key, err := db.InsertAPIKey(genCtx, database.InsertAPIKeyParams{
 		ID: takeFirst(seed.ID, id),
 		// 0 defaults to 86400 at the db layer
-		LifetimeSeconds: takeFirst(seed.LifetimeSeconds, 0),
-		HashedSecret:    takeFirstSlice(seed.HashedSecret, hashed[:]),
-		IPAddress:       ip,
-		UserID:          takeFirst(seed.UserID, uuid.New()),
-		LastUsed:        takeFirst(seed.LastUsed, dbtime.Now()),
-		ExpiresAt:       takeFirst(seed.ExpiresAt, dbtime.Now().Add(time.Hour)),
-		CreatedAt:       takeFirst(seed.CreatedAt, dbtime.Now()),
-		UpdatedAt:       takeFirst(seed.UpdatedAt, dbtime.Now()),
-		LoginType:       takeFirst(seed.LoginType, database.LoginTypePassword),
-		Scope:           takeFirst(seed.Scope, database.APIKeyScopeAll),
-		TokenName:       takeFirst(seed.TokenName),
+		LifetimeSeconds:  takeFirst(seed.LifetimeSeconds, 0),
+		HashedSecret:     takeFirstSlice(seed.HashedSecret, hashed[:]),
+		IPAddress:        ip,
+		UserID:           takeFirst(seed.UserID, uuid.New()),
+		LastUsed:         takeFirst(seed.LastUsed, dbtime.Now()),
+		ExpiresAt:        takeFirst(seed.ExpiresAt, dbtime.Now().Add(time.Hour)),
+		CreatedAt:        takeFirst(seed.CreatedAt, dbtime.Now()),
+		UpdatedAt:        takeFirst(seed.UpdatedAt, dbtime.Now()),
+		LoginType:        takeFirst(seed.LoginType, database.LoginTypePassword),
+		Scope:            takeFirst(seed.Scope, database.APIKeyScopeAll),
+		TokenName:        takeFirst(seed.TokenName),
+		ScopePermissions: takeFirstSlice(seed.ScopePermissions, database.APIKeyScopePermissions{}),
+		ScopeAllowList:   takeFirstSlice(seed.ScopeAllowList, []string{}),
 	})
 	require.NoError(t, err, "insert api key")
 	return key, fmt.Sprintf("%s-%s", key.ID, secret)
@@ -323,6 +328,90 @@ func WorkspaceBuild(t testing.TB, db database.Store, orig database.WorkspaceBuil
 	return build
 }
 
+func WorkspaceSnapshot(t testing.TB, db database.Store, orig database.WorkspaceSnapshot) database.WorkspaceSnapshot {
+	t.Helper()
+
+	snapshot, err := db.InsertWorkspaceSnapshot(genCtx, database.InsertWorkspaceSnapshotParams{
+		ID:             takeFirst(orig.ID, uuid.New()),
+		CreatedAt:      takeFirst(orig.CreatedAt, dbtime.Now()),
+		UpdatedAt:      takeFirst(orig.UpdatedAt, dbtime.Now()),
+		OrganizationID: takeFirst(orig.OrganizationID, uuid.New()),
+		OwnerID:        takeFirst(orig.OwnerID, uuid.New()),
+		TemplateID:     takeFirst(orig.TemplateID, uuid.New()),
+		WorkspaceID:    takeFirst(orig.WorkspaceID, uuid.New()),
+		BuildID:        takeFirst(orig.BuildID, uuid.New()),
+		Name:           takeFirst(orig.Name, testutil.GetRandomName(t)),
+	})
+	require.NoError(t, err, "insert workspace snapshot")
+	if len(orig.Artifacts) > 0 {
+		err = db.UpdateWorkspaceSnapshotArtifactsByID(genCtx, database.UpdateWorkspaceSnapshotArtifactsByIDParams{
+			ID:        snapshot.ID,
+			Artifacts: orig.Artifacts,
+			UpdatedAt: snapshot.UpdatedAt,
+		})
+		require.NoError(t, err, "update workspace snapshot artifacts")
+		snapshot.Artifacts = orig.Artifacts
+	}
+	return snapshot
+}
+
+func WorkspaceSessionRecording(t testing.TB, db database.Store, orig database.WorkspaceSessionRecording) database.WorkspaceSessionRecording {
+	t.Helper()
+
+	recording, err := db.InsertWorkspaceSessionRecording(genCtx, database.InsertWorkspaceSessionRecordingParams{
+		ID:          takeFirst(orig.ID, uuid.New()),
+		CreatedAt:   takeFirst(orig.CreatedAt, dbtime.Now()),
+		UpdatedAt:   takeFirst(orig.UpdatedAt, dbtime.Now()),
+		WorkspaceID: takeFirst(orig.WorkspaceID, uuid.New()),
+		AgentID:     takeFirst(orig.AgentID, uuid.New()),
+		UserID:      takeFirst(orig.UserID, uuid.New()),
+		Type:        takeFirst(orig.Type, database.WorkspaceSessionRecordingTypeSsh),
+	})
+	require.NoError(t, err, "insert workspace session recording")
+	return recording
+}
+
+func WorkspaceScheduledAction(t testing.TB, db database.Store, orig database.WorkspaceScheduledAction) database.WorkspaceScheduledAction {
+	t.Helper()
+
+	action, err := db.InsertWorkspaceScheduledAction(genCtx, database.InsertWorkspaceScheduledActionParams{
+		ID:          takeFirst(orig.ID, uuid.New()),
+		WorkspaceID: takeFirst(orig.WorkspaceID, uuid.New()),
+		CreatedAt:   takeFirst(orig.CreatedAt, dbtime.Now()),
+		UpdatedAt:   takeFirst(orig.UpdatedAt, dbtime.Now()),
+		Action:      takeFirst(orig.Action, database.WorkspaceScheduledActionTypeRestart),
+		Schedule:    takeFirst(orig.Schedule, "CRON_TZ=UTC 0 6 * * *"),
+		NextRunAt:   takeFirst(orig.NextRunAt, dbtime.Now().Add(time.Hour)),
+	})
+	require.NoError(t, err, "insert workspace scheduled action")
+	return action
+}
+
+func Secret(t testing.TB, db database.Store, orig database.Secret) database.Secret {
+	t.Helper()
+
+	envName := orig.EnvName
+	if envName == "" && orig.FilePath == "" {
+		envName = "TEST_SECRET"
+	}
+	secret, err := db.InsertSecret(genCtx, database.InsertSecretParams{
+		ID:             takeFirst(orig.ID, uuid.New()),
+		CreatedAt:      takeFirst(orig.CreatedAt, dbtime.Now()),
+		UpdatedAt:      takeFirst(orig.UpdatedAt, dbtime.Now()),
+		Name:           takeFirst(orig.Name, testutil.GetRandomName(t)),
+		Description:    orig.Description,
+		Value:          takeFirst(orig.Value, "secret"),
+		ValueKeyID:     orig.ValueKeyID,
+		EnvName:        envName,
+		FilePath:       orig.FilePath,
+		UserID:         orig.UserID,
+		OrganizationID: orig.OrganizationID,
+		TemplateID:     orig.TemplateID,
+	})
+	require.NoError(t, err, "insert secret")
+	return secret
+}
+
 func WorkspaceBuildParameters(t testing.TB, db database.Store, orig []database.WorkspaceBuildParameter) []database.WorkspaceBuildParameter {
 	if len(orig) == 0 {
 		return nil
@@ -447,6 +536,22 @@ func Group(t testing.TB, db database.Store, orig database.Group) database.Group
 	return group
 }
 
+func CostBudget(t testing.TB, db database.Store, orig database.CostBudget) database.CostBudget {
+	t.Helper()
+
+	budget, err := db.UpsertCostBudget(genCtx, database.UpsertCostBudgetParams{
+		GroupID:                takeFirst(orig.GroupID, uuid.New()),
+		OrganizationID:         takeFirst(orig.OrganizationID, uuid.New()),
+		CreatedAt:              takeFirst(orig.CreatedAt, dbtime.Now()),
+		UpdatedAt:              takeFirst(orig.UpdatedAt, dbtime.Now()),
+		MonthlyLimit:           takeFirst(orig.MonthlyLimit, 100),
+		NotifyThresholdPercent: takeFirst(orig.NotifyThresholdPercent, 80),
+		StopThresholdPercent:   takeFirst(orig.StopThresholdPercent, 0),
+	})
+	require.NoError(t, err, "insert cost budget")
+	return budget
+}
+
 // GroupMember requires a user + group to already exist.
 // Example for creating a group member for a random group + user.
 //
@@ -579,6 +684,7 @@ func ProvisionerJob(t testing.TB, db database.Store, ps pubsub.Pubsub, orig data
 		Input:          takeFirstSlice(orig.Input, []byte("{}")),
 		Tags:           orig.Tags,
 		TraceMetadata:  pqtype.NullRawMessage{},
+		Priority:       orig.Priority,
 	})
 	require.NoError(t, err, "insert job")
 	if ps != nil {
@@ -718,6 +824,7 @@ func WorkspaceResource(t testing.TB, db database.Store, orig database.WorkspaceR
 			String: takeFirst(orig.ModulePath.String, ""),
 			Valid:  takeFirst(orig.ModulePath.Valid, true),
 		},
+		HourlyCost: takeFirst(orig.HourlyCost, 0),
 	})
 	require.NoError(t, err, "insert resource")
 	return resource
@@ -859,6 +966,26 @@ func TemplateVersion(t testing.TB, db database.Store, orig database.TemplateVers
 	return version
 }
 
+func TemplateRollout(t testing.TB, db database.Store, orig database.TemplateRollout) database.TemplateRollout {
+	t.Helper()
+
+	rollout, err := db.InsertTemplateRollout(genCtx, database.InsertTemplateRolloutParams{
+		ID:                takeFirst(orig.ID, uuid.New()),
+		TemplateID:        takeFirst(orig.TemplateID, uuid.New()),
+		TemplateVersionID: takeFirst(orig.TemplateVersionID, uuid.New()),
+		CreatedBy:         takeFirst(orig.CreatedBy, uuid.New()),
+		CreatedAt:         takeFirst(orig.CreatedAt, dbtime.Now()),
+		UpdatedAt:         takeFirst(orig.UpdatedAt, dbtime.Now()),
+		Percent:           takeFirst(orig.Percent, 10),
+		GroupID:           orig.GroupID,
+		MinBuilds:         takeFirst(orig.MinBuilds, 10),
+		MaxFailureRate:    takeFirst(orig.MaxFailureRate, 0.1),
+		AutoPromote:       orig.AutoPromote,
+	})
+	require.NoError(t, err, "insert template rollout")
+	return rollout
+}
+
 func TemplateVersionVariable(t testing.TB, db database.Store, orig database.TemplateVersionVariable) database.TemplateVersionVariable {
 	version, err := db.InsertTemplateVersionVariable(genCtx, database.InsertTemplateVersionVariableParams{
 		TemplateVersionID: takeFirst(orig.TemplateVersionID, uuid.New()),
@@ -963,12 +1090,14 @@ func WorkspaceAgentStat(t testing.TB, db database.Store, orig database.Workspace
 
 func OAuth2ProviderApp(t testing.TB, db database.Store, seed database.OAuth2ProviderApp) database.OAuth2ProviderApp {
 	app, err := db.InsertOAuth2ProviderApp(genCtx, database.InsertOAuth2ProviderAppParams{
-		ID:          takeFirst(seed.ID, uuid.New()),
-		Name:        takeFirst(seed.Name, testutil.GetRandomName(t)),
-		CreatedAt:   takeFirst(seed.CreatedAt, dbtime.Now()),
-		UpdatedAt:   takeFirst(seed.UpdatedAt, dbtime.Now()),
-		Icon:        takeFirst(seed.Icon, ""),
-		CallbackURL: takeFirst(seed.CallbackURL, "http://localhost"),
+		ID:                      takeFirst(seed.ID, uuid.New()),
+		Name:                    takeFirst(seed.Name, testutil.GetRandomName(t)),
+		CreatedAt:               takeFirst(seed.CreatedAt, dbtime.Now()),
+		UpdatedAt:               takeFirst(seed.UpdatedAt, dbtime.Now()),
+		Icon:                    takeFirst(seed.Icon, ""),
+		CallbackURL:             takeFirst(seed.CallbackURL, "http://localhost"),
+		PublicClient:            seed.PublicClient,
+		ClientCredentialsUserID: seed.ClientCredentialsUserID,
 	})
 	require.NoError(t, err, "insert oauth2 app")
 	return app
@@ -989,13 +1118,14 @@ func OAuth2ProviderAppSecret(t testing.TB, db database.Store, seed database.OAut
 
 func OAuth2ProviderAppCode(t testing.TB, db database.Store, seed database.OAuth2ProviderAppCode) database.OAuth2ProviderAppCode {
 	code, err := db.InsertOAuth2ProviderAppCode(genCtx, database.InsertOAuth2ProviderAppCodeParams{
-		ID:           takeFirst(seed.ID, uuid.New()),
-		CreatedAt:    takeFirst(seed.CreatedAt, dbtime.Now()),
-		ExpiresAt:    takeFirst(seed.CreatedAt, dbtime.Now()),
-		SecretPrefix: takeFirstSlice(seed.SecretPrefix, []byte("prefix")),
-		HashedSecret: takeFirstSlice(seed.HashedSecret, []byte("hashed-secret")),
-		AppID:        takeFirst(seed.AppID, uuid.New()),
-		UserID:       takeFirst(seed.UserID, uuid.New()),
+		ID:            takeFirst(seed.ID, uuid.New()),
+		CreatedAt:     takeFirst(seed.CreatedAt, dbtime.Now()),
+		ExpiresAt:     takeFirst(seed.CreatedAt, dbtime.Now()),
+		SecretPrefix:  takeFirstSlice(seed.SecretPrefix, []byte("prefix")),
+		HashedSecret:  takeFirstSlice(seed.HashedSecret, []byte("hashed-secret")),
+		AppID:         takeFirst(seed.AppID, uuid.New()),
+		UserID:        takeFirst(seed.UserID, uuid.New()),
+		CodeChallenge: seed.CodeChallenge,
 	})
 	require.NoError(t, err, "insert oauth2 app code")
 	return code
@@ -1008,13 +1138,28 @@ func OAuth2ProviderAppToken(t testing.TB, db database.Store, seed database.OAuth
 		ExpiresAt:   takeFirst(seed.CreatedAt, dbtime.Now()),
 		HashPrefix:  takeFirstSlice(seed.HashPrefix, []byte("prefix")),
 		RefreshHash: takeFirstSlice(seed.RefreshHash, []byte("hashed-secret")),
-		AppSecretID: takeFirst(seed.AppSecretID, uuid.New()),
+		AppSecretID: seed.AppSecretID,
 		APIKeyID:    takeFirst(seed.APIKeyID, uuid.New().String()),
+		AppID:       takeFirst(seed.AppID, uuid.New()),
 	})
 	require.NoError(t, err, "insert oauth2 app token")
 	return token
 }
 
+func OAuth2ProviderDeviceCode(t testing.TB, db database.Store, seed database.OAuth2ProviderDeviceCode) database.OAuth2ProviderDeviceCode {
+	code, err := db.InsertOAuth2ProviderDeviceCode(genCtx, database.InsertOAuth2ProviderDeviceCodeParams{
+		ID:           takeFirst(seed.ID, uuid.New()),
+		CreatedAt:    takeFirst(seed.CreatedAt, dbtime.Now()),
+		ExpiresAt:    takeFirst(seed.ExpiresAt, dbtime.Now().Add(time.Minute*10)),
+		SecretPrefix: takeFirstSlice(seed.SecretPrefix, []byte("prefix")),
+		HashedSecret: takeFirstSlice(seed.HashedSecret, []byte("hashed-secret")),
+		UserCode:     takeFirst(seed.UserCode, "BCDF-GHJK"),
+		AppID:        takeFirst(seed.AppID, uuid.New()),
+	})
+	require.NoError(t, err, "insert oauth2 device code")
+	return code
+}
+
 func CustomRole(t testing.TB, db database.Store, seed database.CustomRole) database.CustomRole {
 	role, err := db.InsertCustomRole(genCtx, database.InsertCustomRoleParams{
 		Name:            takeFirst(seed.Name, strings.ToLower(testutil.GetRandomName(t))),
@@ -1090,6 +1235,19 @@ func provisionerJobTiming(t testing.TB, db database.Store, seed database.Provisi
 	return timing[0]
 }
 
+func ProvisionerJobResourceChange(t testing.TB, db database.Store, seed database.ProvisionerJobResourceChange) database.ProvisionerJobResourceChange {
+	changes, err := db.InsertProvisionerJobResourceChanges(genCtx, database.InsertProvisionerJobResourceChangesParams{
+		JobID:        takeFirst(seed.JobID, uuid.New()),
+		Address:      []string{takeFirst(seed.Address, "coder_agent.main")},
+		ResourceType: []string{takeFirst(seed.ResourceType, "coder_agent")},
+		ResourceName: []string{takeFirst(seed.ResourceName, "main")},
+		Action:       []database.ProvisionerJobResourceChangeAction{takeFirst(seed.Action, database.ProvisionerJobResourceChangeActionUpdate)},
+		Attributes:   must(json.Marshal([]json.RawMessage{takeFirstSlice(seed.Attributes, []byte("[]"))})),
+	})
+	require.NoError(t, err, "insert provisioner job resource change")
+	return changes[0]
+}
+
 func must[V any](v V, err error) V {
 	if err != nil {
 		panic(err)
@@ -1150,6 +1308,8 @@ func newCryptoKeySecret(feature database.CryptoKeyFeature) (string, error) {
 		return generateCryptoKey(64)
 	case database.CryptoKeyFeatureTailnetResume:
 		return generateCryptoKey(64)
+	case database.CryptoKeyFeatureWorkloadIdentity:
+		return generateECDSACryptoKey()
 	}
 	return "", xerrors.Errorf("unknown feature: %s", feature)
 }
@@ -1162,3 +1322,15 @@ func generateCryptoKey(length int) (string, error) {
 	}
 	return hex.EncodeToString(b), nil
 }
+
+func generateECDSACryptoKey() (string, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", xerrors.Errorf("generate ecdsa key: %w", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", xerrors.Errorf("marshal ecdsa key: %w", err)
+	}
+	return hex.EncodeToString(der), nil
+}
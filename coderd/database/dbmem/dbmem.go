@@ -197,9 +197,12 @@ type data struct {
 	externalAuthLinks               []database.ExternalAuthLink
 	gitSSHKey                       []database.GitSSHKey
 	groupMembers                    []database.GroupMemberTable
+	costBudgets                     []database.CostBudget
 	groups                          []database.Group
 	jfrogXRayScans                  []database.JfrogXrayScan
 	licenses                        []database.License
+	networkConnectionReports        []database.NetworkConnectionReport
+	networkLatencyReports           []database.NetworkLatencyReport
 	notificationMessages            []database.NotificationMessage
 	notificationPreferences         []database.NotificationPreference
 	notificationReportGeneratorLogs []database.NotificationReportGeneratorLog
@@ -207,23 +210,27 @@ type data struct {
 	oauth2ProviderAppSecrets        []database.OAuth2ProviderAppSecret
 	oauth2ProviderAppCodes          []database.OAuth2ProviderAppCode
 	oauth2ProviderAppTokens         []database.OAuth2ProviderAppToken
+	oauth2ProviderDeviceCodes       []database.OAuth2ProviderDeviceCode
 	parameterSchemas                []database.ParameterSchema
 	provisionerDaemons              []database.ProvisionerDaemon
 	provisionerJobLogs              []database.ProvisionerJobLog
 	provisionerJobs                 []database.ProvisionerJob
 	provisionerKeys                 []database.ProvisionerKey
 	replicas                        []database.Replica
+	secrets                         []database.Secret
 	templateVersions                []database.TemplateVersionTable
 	templateVersionParameters       []database.TemplateVersionParameter
 	templateVersionVariables        []database.TemplateVersionVariable
 	templateVersionWorkspaceTags    []database.TemplateVersionWorkspaceTag
 	templates                       []database.TemplateTable
+	templateRollouts                []database.TemplateRollout
 	templateUsageStats              []database.TemplateUsageStat
 	workspaceAgents                 []database.WorkspaceAgent
 	workspaceAgentMetadata          []database.WorkspaceAgentMetadatum
 	workspaceAgentLogs              []database.WorkspaceAgentLog
 	workspaceAgentLogSources        []database.WorkspaceAgentLogSource
 	workspaceAgentPortShares        []database.WorkspaceAgentPortShare
+	workspaceAgentProcessGroups     []database.WorkspaceAgentProcessGroup
 	workspaceAgentScriptTimings     []database.WorkspaceAgentScriptTiming
 	workspaceAgentScripts           []database.WorkspaceAgentScript
 	workspaceAgentStats             []database.WorkspaceAgentStat
@@ -231,14 +238,21 @@ type data struct {
 	workspaceAppStatsLastInsertID   int64
 	workspaceAppStats               []database.WorkspaceAppStat
 	workspaceBuilds                 []database.WorkspaceBuild
+	workspaceBuildApprovals         []database.WorkspaceBuildApproval
 	workspaceBuildParameters        []database.WorkspaceBuildParameter
+	workspaceCosts                  []database.WorkspaceCost
 	workspaceResourceMetadata       []database.WorkspaceResourceMetadatum
 	workspaceResources              []database.WorkspaceResource
 	workspaceModules                []database.WorkspaceModule
+	workspaceScheduledActions       []database.WorkspaceScheduledAction
+	workspaceSessionRecordings      []database.WorkspaceSessionRecording
+	workspaceSessionRecordingChunks []database.WorkspaceSessionRecordingChunk
+	workspaceSnapshots              []database.WorkspaceSnapshot
 	workspaces                      []database.WorkspaceTable
 	workspaceProxies                []database.WorkspaceProxy
 	customRoles                     []database.CustomRole
 	provisionerJobTimings           []database.ProvisionerJobTiming
+	provisionerJobResourceChanges   []database.ProvisionerJobResourceChange
 	runtimeConfig                   map[string]string
 	// Locks is a map of lock names. Any keys within the map are currently
 	// locked.
@@ -631,6 +645,130 @@ func (q *FakeQuerier) getWorkspaceBuildByIDNoLock(_ context.Context, id uuid.UUI
 	return database.WorkspaceBuild{}, sql.ErrNoRows
 }
 
+// oldWorkspaceAgentIDsNoLock returns the agents whose logs are purged by
+// DeleteOldWorkspaceAgentLogs.
+func (q *FakeQuerier) oldWorkspaceAgentIDsNoLock(threshold time.Time) map[uuid.UUID]struct{} {
+	/*
+		WITH
+			latest_builds AS (
+				SELECT
+					workspace_id, max(build_number) AS max_build_number
+				FROM
+					workspace_builds
+				GROUP BY
+					workspace_id
+			),
+	*/
+	latestBuilds := make(map[uuid.UUID]int32)
+	for _, wb := range q.workspaceBuilds {
+		if lastBuildNumber, found := latestBuilds[wb.WorkspaceID]; found && lastBuildNumber > wb.BuildNumber {
+			continue
+		}
+		// not found or newer build number
+		latestBuilds[wb.WorkspaceID] = wb.BuildNumber
+	}
+
+	/*
+		old_agents AS (
+			SELECT
+				wa.id
+			FROM
+				workspace_agents AS wa
+			JOIN
+				workspace_resources AS wr
+			ON
+				wa.resource_id = wr.id
+			JOIN
+				workspace_builds AS wb
+			ON
+				wb.job_id = wr.job_id
+			LEFT JOIN
+				latest_builds
+			ON
+				latest_builds.workspace_id = wb.workspace_id
+			AND
+				latest_builds.max_build_number = wb.build_number
+			WHERE
+				-- Filter out the latest builds for each workspace.
+				latest_builds.workspace_id IS NULL
+			AND CASE
+				-- If the last time the agent connected was before @threshold
+				WHEN wa.last_connected_at IS NOT NULL THEN
+					wa.last_connected_at < @threshold :: timestamptz
+				-- The agent never connected, and was created before @threshold
+				ELSE wa.created_at < @threshold :: timestamptz
+			END
+		)
+	*/
+	oldAgents := make(map[uuid.UUID]struct{})
+	for _, wa := range q.workspaceAgents {
+		for _, wr := range q.workspaceResources {
+			if wr.ID != wa.ResourceID {
+				continue
+			}
+			for _, wb := range q.workspaceBuilds {
+				if wb.JobID != wr.JobID {
+					continue
+				}
+				latestBuildNumber, found := latestBuilds[wb.WorkspaceID]
+				if !found {
+					panic("workspaceBuilds got modified somehow while q was locked! This is a bug in dbmem!")
+				}
+				if latestBuildNumber == wb.BuildNumber {
+					continue
+				}
+				if wa.LastConnectedAt.Valid && wa.LastConnectedAt.Time.Before(threshold) || wa.CreatedAt.Before(threshold) {
+					oldAgents[wa.ID] = struct{}{}
+				}
+			}
+		}
+	}
+	return oldAgents
+}
+
+// latestWorkspaceBuildIDsNoLock returns the ID of the latest build of each
+// workspace.
+func (q *FakeQuerier) latestWorkspaceBuildIDsNoLock() map[uuid.UUID]struct{} {
+	latest := make(map[uuid.UUID]database.WorkspaceBuild)
+	for _, wb := range q.workspaceBuilds {
+		if cur, ok := latest[wb.WorkspaceID]; ok && cur.BuildNumber > wb.BuildNumber {
+			continue
+		}
+		latest[wb.WorkspaceID] = wb
+	}
+	ids := make(map[uuid.UUID]struct{}, len(latest))
+	for _, wb := range latest {
+		ids[wb.ID] = struct{}{}
+	}
+	return ids
+}
+
+// latestWorkspaceBuildJobIDsNoLock returns the job ID of the latest build of
+// each workspace.
+func (q *FakeQuerier) latestWorkspaceBuildJobIDsNoLock() map[uuid.UUID]struct{} {
+	latest := q.latestWorkspaceBuildIDsNoLock()
+	ids := make(map[uuid.UUID]struct{}, len(latest))
+	for _, wb := range q.workspaceBuilds {
+		if _, ok := latest[wb.ID]; ok {
+			ids[wb.JobID] = struct{}{}
+		}
+	}
+	return ids
+}
+
+// workspaceAgentStatsPurgeCutoffNoLock mirrors the cutoff used by
+// DeleteOldWorkspaceAgentStats:
+// GREATEST(MAX(start_time) - '1 days'::interval, @before_time).
+func (q *FakeQuerier) workspaceAgentStatsPurgeCutoffNoLock(beforeTime time.Time) time.Time {
+	limit := beforeTime
+	for _, stat := range q.templateUsageStats {
+		if cutoff := stat.StartTime.AddDate(0, 0, -1); cutoff.After(limit) {
+			limit = cutoff
+		}
+	}
+	return limit
+}
+
 func (q *FakeQuerier) getLatestWorkspaceBuildByWorkspaceIDNoLock(_ context.Context, workspaceID uuid.UUID) (database.WorkspaceBuild, error) {
 	var row database.WorkspaceBuild
 	var buildNum int32 = -1
@@ -787,6 +925,23 @@ func (q *FakeQuerier) getProvisionerJobByIDNoLock(_ context.Context, id uuid.UUI
 	return database.ProvisionerJob{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) isProvisionerJobApprovedNoLock(job database.ProvisionerJob) bool {
+	if job.Type != database.ProvisionerJobTypeWorkspaceBuild {
+		return true
+	}
+	for _, build := range q.workspaceBuilds {
+		if build.JobID != job.ID {
+			continue
+		}
+		for _, approval := range q.workspaceBuildApprovals {
+			if approval.WorkspaceBuildID == build.ID {
+				return approval.Status == database.WorkspaceBuildApprovalStatusApproved
+			}
+		}
+	}
+	return true
+}
+
 func (q *FakeQuerier) getWorkspaceResourcesByJobIDNoLock(_ context.Context, jobID uuid.UUID) ([]database.WorkspaceResource, error) {
 	resources := make([]database.WorkspaceResource, 0)
 	for _, resource := range q.workspaceResources {
@@ -808,6 +963,22 @@ func (q *FakeQuerier) getGroupByIDNoLock(_ context.Context, id uuid.UUID) (datab
 	return database.Group{}, sql.ErrNoRows
 }
 
+// filterWorkspaceCostsNoLock returns the workspace costs in the given time
+// range, optionally filtered by template.
+func (q *FakeQuerier) filterWorkspaceCostsNoLock(startTime, endTime time.Time, templateIDs []uuid.UUID) []database.WorkspaceCost {
+	var costs []database.WorkspaceCost
+	for _, wc := range q.workspaceCosts {
+		if wc.BucketStart.Before(startTime) || !wc.BucketStart.Before(endTime) {
+			continue
+		}
+		if len(templateIDs) > 0 && !slices.Contains(templateIDs, wc.TemplateID) {
+			continue
+		}
+		costs = append(costs, wc)
+	}
+	return costs
+}
+
 // ErrUnimplemented is returned by methods only used by the enterprise/tailnet.pgCoord.  This coordinator explicitly
 // depends on  postgres triggers that announce changes on the pubsub.  Implementing support for this in the fake
 // database would  strongly couple the FakeQuerier to the pubsub, which is undesirable.  Furthermore, it makes little
@@ -1130,6 +1301,28 @@ func getOwnerFromTags(tags map[string]string) string {
 	return ""
 }
 
+func (q *FakeQuerier) getGroupMembersByGroupIDNoLock(ctx context.Context, id uuid.UUID) ([]database.GroupMember, error) {
+	if q.isEveryoneGroup(id) {
+		return q.getEveryoneGroupMembersNoLock(ctx, id), nil
+	}
+
+	var groupMembers []database.GroupMember
+	for _, member := range q.groupMembers {
+		if member.GroupID == id {
+			groupMember, err := q.getGroupMemberNoLock(ctx, member.UserID, member.GroupID)
+			if errors.Is(err, errUserDeleted) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			groupMembers = append(groupMembers, groupMember)
+		}
+	}
+
+	return groupMembers, nil
+}
+
 func (*FakeQuerier) AcquireLock(_ context.Context, _ int64) error {
 	return xerrors.New("AcquireLock must only be called within a transaction")
 }
@@ -1188,6 +1381,25 @@ func (q *FakeQuerier) AcquireProvisionerJob(_ context.Context, arg database.Acqu
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
+	tags := map[string]string{}
+	if arg.ProvisionerTags != nil {
+		err := json.Unmarshal(arg.ProvisionerTags, &tags)
+		if err != nil {
+			return database.ProvisionerJob{}, xerrors.Errorf("unmarshal: %w", err)
+		}
+	}
+
+	runningJobs := func(filter func(job database.ProvisionerJob) bool) int {
+		count := 0
+		for _, job := range q.provisionerJobs {
+			if job.StartedAt.Valid && !job.CompletedAt.Valid && filter(job) {
+				count++
+			}
+		}
+		return count
+	}
+
+	candidates := make([]int, 0)
 	for index, provisionerJob := range q.provisionerJobs {
 		if provisionerJob.OrganizationID != arg.OrganizationID {
 			continue
@@ -1206,13 +1418,6 @@ func (q *FakeQuerier) AcquireProvisionerJob(_ context.Context, arg database.Acqu
 		if !found {
 			continue
 		}
-		tags := map[string]string{}
-		if arg.ProvisionerTags != nil {
-			err := json.Unmarshal(arg.ProvisionerTags, &tags)
-			if err != nil {
-				return provisionerJob, xerrors.Errorf("unmarshal: %w", err)
-			}
-		}
 
 		// Special case for untagged provisioners: only match untagged jobs.
 		// Ref: coderd/database/queries/provisionerjobs.sql:24-30
@@ -1225,18 +1430,56 @@ func (q *FakeQuerier) AcquireProvisionerJob(_ context.Context, arg database.Acqu
 		if !tagsSubset(provisionerJob.Tags, tags) {
 			continue
 		}
-		provisionerJob.StartedAt = arg.StartedAt
-		provisionerJob.UpdatedAt = arg.StartedAt.Time
-		provisionerJob.WorkerID = arg.WorkerID
-		provisionerJob.JobStatus = provisionerJobStatus(provisionerJob)
-		q.provisionerJobs[index] = provisionerJob
-		// clone the Tags before returning, since maps are reference types and
-		// we don't want the caller to be able to mutate the map we have inside
-		// dbmem!
-		provisionerJob.Tags = maps.Clone(provisionerJob.Tags)
-		return provisionerJob, nil
+		// Workspace builds that are awaiting approval, or were rejected, must
+		// not be acquired.
+		if !q.isProvisionerJobApprovedNoLock(provisionerJob) {
+			continue
+		}
+		if arg.MaxOrganizationJobs > 0 && runningJobs(func(job database.ProvisionerJob) bool {
+			return job.OrganizationID == provisionerJob.OrganizationID
+		}) >= int(arg.MaxOrganizationJobs) {
+			continue
+		}
+		if arg.MaxUserJobs > 0 && runningJobs(func(job database.ProvisionerJob) bool {
+			return job.InitiatorID == provisionerJob.InitiatorID
+		}) >= int(arg.MaxUserJobs) {
+			continue
+		}
+		candidates = append(candidates, index)
 	}
-	return database.ProvisionerJob{}, sql.ErrNoRows
+	if len(candidates) == 0 {
+		return database.ProvisionerJob{}, sql.ErrNoRows
+	}
+
+	// ORDER BY priority DESC, <running jobs of the initiator> ASC, created_at
+	runningByInitiator := func(job database.ProvisionerJob) int {
+		return runningJobs(func(running database.ProvisionerJob) bool {
+			return running.OrganizationID == job.OrganizationID && running.InitiatorID == job.InitiatorID
+		})
+	}
+	slices.SortStableFunc(candidates, func(a, b int) int {
+		jobA, jobB := q.provisionerJobs[a], q.provisionerJobs[b]
+		if jobA.Priority != jobB.Priority {
+			return int(jobB.Priority - jobA.Priority)
+		}
+		if c := runningByInitiator(jobA) - runningByInitiator(jobB); c != 0 {
+			return c
+		}
+		return jobA.CreatedAt.Compare(jobB.CreatedAt)
+	})
+
+	index := candidates[0]
+	provisionerJob := q.provisionerJobs[index]
+	provisionerJob.StartedAt = arg.StartedAt
+	provisionerJob.UpdatedAt = arg.StartedAt.Time
+	provisionerJob.WorkerID = arg.WorkerID
+	provisionerJob.JobStatus = provisionerJobStatus(provisionerJob)
+	q.provisionerJobs[index] = provisionerJob
+	// clone the Tags before returning, since maps are reference types and
+	// we don't want the caller to be able to mutate the map we have inside
+	// dbmem!
+	provisionerJob.Tags = maps.Clone(provisionerJob.Tags)
+	return provisionerJob, nil
 }
 
 func (q *FakeQuerier) ActivityBumpWorkspace(ctx context.Context, arg database.ActivityBumpWorkspaceParams) error {
@@ -1499,6 +1742,35 @@ func (*FakeQuerier) CleanTailnetTunnels(context.Context) error {
 	return ErrUnimplemented
 }
 
+func (q *FakeQuerier) ClearOldWorkspaceBuildProvisionerStates(_ context.Context, arg database.ClearOldWorkspaceBuildProvisionerStatesParams) (int64, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return 0, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	latest := q.latestWorkspaceBuildIDsNoLock()
+	old := make([]int, 0)
+	for i, wb := range q.workspaceBuilds {
+		if _, ok := latest[wb.ID]; ok || wb.ProvisionerState == nil || !wb.CreatedAt.Before(arg.BeforeTime) {
+			continue
+		}
+		old = append(old, i)
+	}
+	slices.SortFunc(old, func(a, b int) int {
+		return q.workspaceBuilds[a].CreatedAt.Compare(q.workspaceBuilds[b].CreatedAt)
+	})
+	if len(old) > int(arg.LimitCount) {
+		old = old[:arg.LimitCount]
+	}
+	for _, i := range old {
+		q.workspaceBuilds[i].ProvisionerState = nil
+	}
+	return int64(len(old)), nil
+}
+
 func (q *FakeQuerier) CustomRoles(_ context.Context, arg database.CustomRolesParams) ([]database.CustomRole, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -1600,6 +1872,19 @@ func (*FakeQuerier) DeleteCoordinator(context.Context, uuid.UUID) error {
 	return ErrUnimplemented
 }
 
+func (q *FakeQuerier) DeleteCostBudgetByGroupID(_ context.Context, groupID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, budget := range q.costBudgets {
+		if budget.GroupID == groupID {
+			q.costBudgets = append(q.costBudgets[:i], q.costBudgets[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
 func (q *FakeQuerier) DeleteCryptoKey(_ context.Context, arg database.DeleteCryptoKeyParams) (database.CryptoKey, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -1695,6 +1980,14 @@ func (q *FakeQuerier) DeleteGroupByID(_ context.Context, id uuid.UUID) error {
 	for i, group := range q.groups {
 		if group.ID == id {
 			q.groups = append(q.groups[:i], q.groups[i+1:]...)
+			q.costBudgets = slices.DeleteFunc(q.costBudgets, func(budget database.CostBudget) bool {
+				return budget.GroupID == id
+			})
+			for i, rollout := range q.templateRollouts {
+				if rollout.GroupID.Valid && rollout.GroupID.UUID == id {
+					q.templateRollouts[i].GroupID = uuid.NullUUID{}
+				}
+			}
 			return nil
 		}
 	}
@@ -1744,19 +2037,22 @@ func (q *FakeQuerier) DeleteOAuth2ProviderAppByID(_ context.Context, id uuid.UUI
 	q.oauth2ProviderApps = q.oauth2ProviderApps[:len(q.oauth2ProviderApps)-1]
 
 	// Cascade delete secrets associated with the deleted app.
-	var deletedSecretIDs []uuid.UUID
 	q.oauth2ProviderAppSecrets = slices.DeleteFunc(q.oauth2ProviderAppSecrets, func(secret database.OAuth2ProviderAppSecret) bool {
-		matches := secret.AppID == id
-		if matches {
-			deletedSecretIDs = append(deletedSecretIDs, secret.ID)
-		}
-		return matches
+		return secret.AppID == id
+	})
+
+	// Cascade delete codes and device codes issued for the deleted app.
+	q.oauth2ProviderAppCodes = slices.DeleteFunc(q.oauth2ProviderAppCodes, func(code database.OAuth2ProviderAppCode) bool {
+		return code.AppID == id
+	})
+	q.oauth2ProviderDeviceCodes = slices.DeleteFunc(q.oauth2ProviderDeviceCodes, func(code database.OAuth2ProviderDeviceCode) bool {
+		return code.AppID == id
 	})
 
-	// Cascade delete tokens through the deleted secrets.
+	// Cascade delete tokens issued for the deleted app.
 	var keyIDsToDelete []string
 	q.oauth2ProviderAppTokens = slices.DeleteFunc(q.oauth2ProviderAppTokens, func(token database.OAuth2ProviderAppToken) bool {
-		matches := slice.Contains(deletedSecretIDs, token.AppSecretID)
+		matches := token.AppID == id
 		if matches {
 			keyIDsToDelete = append(keyIDsToDelete, token.APIKeyID)
 		}
@@ -1822,7 +2118,7 @@ func (q *FakeQuerier) DeleteOAuth2ProviderAppSecretByID(_ context.Context, id uu
 	// Cascade delete tokens created through the deleted secret.
 	var keyIDsToDelete []string
 	q.oauth2ProviderAppTokens = slices.DeleteFunc(q.oauth2ProviderAppTokens, func(token database.OAuth2ProviderAppToken) bool {
-		matches := token.AppSecretID == id
+		matches := token.AppSecretID.Valid && token.AppSecretID.UUID == id
 		if matches {
 			keyIDsToDelete = append(keyIDsToDelete, token.APIKeyID)
 		}
@@ -1848,15 +2144,11 @@ func (q *FakeQuerier) DeleteOAuth2ProviderAppTokensByAppAndUserID(_ context.Cont
 
 	var keyIDsToDelete []string
 	q.oauth2ProviderAppTokens = slices.DeleteFunc(q.oauth2ProviderAppTokens, func(token database.OAuth2ProviderAppToken) bool {
-		// Join secrets and keys to see if the token matches.
-		secretIdx := slices.IndexFunc(q.oauth2ProviderAppSecrets, func(secret database.OAuth2ProviderAppSecret) bool {
-			return secret.ID == token.AppSecretID
-		})
+		// Join keys to see if the token matches.
 		keyIdx := slices.IndexFunc(q.apiKeys, func(key database.APIKey) bool {
 			return key.ID == token.APIKeyID
 		})
-		matches := secretIdx != -1 &&
-			q.oauth2ProviderAppSecrets[secretIdx].AppID == arg.AppID &&
+		matches := token.AppID == arg.AppID &&
 			keyIdx != -1 && q.apiKeys[keyIdx].UserID == arg.UserID
 		if matches {
 			keyIDsToDelete = append(keyIDsToDelete, token.APIKeyID)
@@ -1872,7 +2164,94 @@ func (q *FakeQuerier) DeleteOAuth2ProviderAppTokensByAppAndUserID(_ context.Cont
 	return nil
 }
 
-func (*FakeQuerier) DeleteOldNotificationMessages(_ context.Context) error {
+func (q *FakeQuerier) DeleteOAuth2ProviderDeviceCodeByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, code := range q.oauth2ProviderDeviceCodes {
+		if code.ID == id {
+			q.oauth2ProviderDeviceCodes[index] = q.oauth2ProviderDeviceCodes[len(q.oauth2ProviderDeviceCodes)-1]
+			q.oauth2ProviderDeviceCodes = q.oauth2ProviderDeviceCodes[:len(q.oauth2ProviderDeviceCodes)-1]
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (q *FakeQuerier) DeleteOldAuditLogs(_ context.Context, arg database.DeleteOldAuditLogsParams) (int64, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return 0, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	old := make([]database.AuditLog, 0)
+	for _, alog := range q.auditLogs {
+		if alog.Time.Before(arg.BeforeTime) {
+			old = append(old, alog)
+		}
+	}
+	slices.SortFunc(old, func(a, b database.AuditLog) int {
+		return a.Time.Compare(b.Time)
+	})
+	if len(old) > int(arg.LimitCount) {
+		old = old[:arg.LimitCount]
+	}
+	deleted := make(map[uuid.UUID]struct{}, len(old))
+	for _, alog := range old {
+		deleted[alog.ID] = struct{}{}
+	}
+
+	validLogs := make([]database.AuditLog, 0, len(q.auditLogs))
+	for _, alog := range q.auditLogs {
+		if _, ok := deleted[alog.ID]; ok {
+			continue
+		}
+		validLogs = append(validLogs, alog)
+	}
+	q.auditLogs = validLogs
+	return int64(len(deleted)), nil
+}
+
+func (q *FakeQuerier) DeleteOldNetworkReports(_ context.Context) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	before := dbtime.Now().Add(-24 * time.Hour)
+	latencyReports := make([]database.NetworkLatencyReport, 0, len(q.networkLatencyReports))
+	for _, report := range q.networkLatencyReports {
+		if report.ReportedAt.Before(before) {
+			continue
+		}
+		latencyReports = append(latencyReports, report)
+	}
+	q.networkLatencyReports = latencyReports
+
+	connectionReports := make([]database.NetworkConnectionReport, 0, len(q.networkConnectionReports))
+	for _, report := range q.networkConnectionReports {
+		if report.ReportedAt.Before(before) {
+			continue
+		}
+		connectionReports = append(connectionReports, report)
+	}
+	q.networkConnectionReports = connectionReports
+	return nil
+}
+
+func (q *FakeQuerier) DeleteOldNotificationMessages(_ context.Context, beforeTime time.Time) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	validMessages := make([]database.NotificationMessage, 0, len(q.notificationMessages))
+	for _, msg := range q.notificationMessages {
+		if msg.UpdatedAt.Valid && msg.UpdatedAt.Time.Before(beforeTime) {
+			continue
+		}
+		validMessages = append(validMessages, msg)
+	}
+	q.notificationMessages = validMessages
 	return nil
 }
 
@@ -1895,85 +2274,36 @@ func (q *FakeQuerier) DeleteOldProvisionerDaemons(_ context.Context) error {
 	return nil
 }
 
-func (q *FakeQuerier) DeleteOldWorkspaceAgentLogs(_ context.Context, threshold time.Time) error {
+func (q *FakeQuerier) DeleteOldProvisionerJobLogs(_ context.Context, arg database.DeleteOldProvisionerJobLogsParams) (int64, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return 0, err
+	}
+
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
-	/*
-		WITH
-			latest_builds AS (
-				SELECT
-					workspace_id, max(build_number) AS max_build_number
-				FROM
-					workspace_builds
-				GROUP BY
-					workspace_id
-			),
-	*/
-	latestBuilds := make(map[uuid.UUID]int32)
-	for _, wb := range q.workspaceBuilds {
-		if lastBuildNumber, found := latestBuilds[wb.WorkspaceID]; found && lastBuildNumber > wb.BuildNumber {
+	latestJobs := q.latestWorkspaceBuildJobIDsNoLock()
+	// Logs are stored in insertion order, which matches id order.
+	var deleted int64
+	validLogs := make([]database.ProvisionerJobLog, 0, len(q.provisionerJobLogs))
+	for _, log := range q.provisionerJobLogs {
+		_, latest := latestJobs[log.JobID]
+		if deleted < int64(arg.LimitCount) && !latest && log.CreatedAt.Before(arg.BeforeTime) {
+			deleted++
 			continue
 		}
-		// not found or newer build number
-		latestBuilds[wb.WorkspaceID] = wb.BuildNumber
+		validLogs = append(validLogs, log)
 	}
+	q.provisionerJobLogs = validLogs
+	return deleted, nil
+}
 
-	/*
-		old_agents AS (
-			SELECT
-				wa.id
-			FROM
-				workspace_agents AS wa
-			JOIN
-				workspace_resources AS wr
-			ON
-				wa.resource_id = wr.id
-			JOIN
-				workspace_builds AS wb
-			ON
-				wb.job_id = wr.job_id
-			LEFT JOIN
-				latest_builds
-			ON
-				latest_builds.workspace_id = wb.workspace_id
-			AND
-				latest_builds.max_build_number = wb.build_number
-			WHERE
-				-- Filter out the latest builds for each workspace.
-				latest_builds.workspace_id IS NULL
-			AND CASE
-				-- If the last time the agent connected was before @threshold
-				WHEN wa.last_connected_at IS NOT NULL THEN
-					wa.last_connected_at < @threshold :: timestamptz
-				-- The agent never connected, and was created before @threshold
-				ELSE wa.created_at < @threshold :: timestamptz
-			END
-		)
-	*/
-	oldAgents := make(map[uuid.UUID]struct{})
-	for _, wa := range q.workspaceAgents {
-		for _, wr := range q.workspaceResources {
-			if wr.ID != wa.ResourceID {
-				continue
-			}
-			for _, wb := range q.workspaceBuilds {
-				if wb.JobID != wr.JobID {
-					continue
-				}
-				latestBuildNumber, found := latestBuilds[wb.WorkspaceID]
-				if !found {
-					panic("workspaceBuilds got modified somehow while q was locked! This is a bug in dbmem!")
-				}
-				if latestBuildNumber == wb.BuildNumber {
-					continue
-				}
-				if wa.LastConnectedAt.Valid && wa.LastConnectedAt.Time.Before(threshold) || wa.CreatedAt.Before(threshold) {
-					oldAgents[wa.ID] = struct{}{}
-				}
-			}
-		}
-	}
+func (q *FakeQuerier) DeleteOldWorkspaceAgentLogs(_ context.Context, threshold time.Time) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	oldAgents := q.oldWorkspaceAgentIDsNoLock(threshold)
 	/*
 		DELETE FROM workspace_agent_logs WHERE agent_id IN (SELECT id FROM old_agents);
 	*/
@@ -1988,7 +2318,7 @@ func (q *FakeQuerier) DeleteOldWorkspaceAgentLogs(_ context.Context, threshold t
 	return nil
 }
 
-func (q *FakeQuerier) DeleteOldWorkspaceAgentStats(_ context.Context) error {
+func (q *FakeQuerier) DeleteOldWorkspaceAgentStats(_ context.Context, beforeTime time.Time) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
@@ -1998,17 +2328,16 @@ func (q *FakeQuerier) DeleteOldWorkspaceAgentStats(_ context.Context) error {
 		WHERE
 			created_at < (
 				SELECT
-					COALESCE(
+					GREATEST(
 						-- When generating initial template usage stats, all the
 						-- raw agent stats are needed, after that only ~30 mins
 						-- from last rollup is needed. Deployment stats seem to
 						-- use between 15 mins and 1 hour of data. We keep a
 						-- little bit more (1 day) just in case.
 						MAX(start_time) - '1 days'::interval,
-						-- Fall back to ~6 months ago if there are no template
-						-- usage stats so that we don't delete the data before
-						-- it's rolled up.
-						NOW() - '180 days'::interval
+						-- Stats older than the retention period are deleted even
+						-- if they have not been rolled up.
+						@before_time::timestamptz
 					)
 				FROM
 					template_usage_stats
@@ -2024,18 +2353,7 @@ func (q *FakeQuerier) DeleteOldWorkspaceAgentStats(_ context.Context) error {
 			);
 	*/
 
-	now := dbtime.Now()
-	var limit time.Time
-	// MAX
-	for _, stat := range q.templateUsageStats {
-		if stat.StartTime.After(limit) {
-			limit = stat.StartTime.AddDate(0, 0, -1)
-		}
-	}
-	// COALESCE
-	if limit.IsZero() {
-		limit = now.AddDate(0, 0, -180)
-	}
+	limit := q.workspaceAgentStatsPurgeCutoffNoLock(beforeTime)
 
 	var validStats []database.WorkspaceAgentStat
 	var batchLimit time.Time
@@ -2135,6 +2453,19 @@ func (q *FakeQuerier) DeleteRuntimeConfig(_ context.Context, key string) error {
 	return nil
 }
 
+func (q *FakeQuerier) DeleteSecretByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, secret := range q.secrets {
+		if secret.ID == id {
+			q.secrets = append(q.secrets[:i], q.secrets[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
 func (*FakeQuerier) DeleteTailnetAgent(context.Context, database.DeleteTailnetAgentParams) (database.DeleteTailnetAgentRow, error) {
 	return database.DeleteTailnetAgentRow{}, ErrUnimplemented
 }
@@ -2208,6 +2539,19 @@ func (q *FakeQuerier) DeleteWorkspaceAgentPortSharesByTemplate(_ context.Context
 	return nil
 }
 
+func (q *FakeQuerier) DeleteWorkspaceScheduledActionByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, action := range q.workspaceScheduledActions {
+		if action.ID == id {
+			q.workspaceScheduledActions = append(q.workspaceScheduledActions[:i], q.workspaceScheduledActions[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
 func (*FakeQuerier) DisableForeignKeysAndTriggers(_ context.Context) error {
 	// This is a no-op in the in-memory database.
 	return nil
@@ -2287,6 +2631,14 @@ func (q *FakeQuerier) FetchNewMessageMetadata(_ context.Context, arg database.Fe
 		return database.FetchNewMessageMetadataRow{}, err
 	}
 
+	var userMethod database.NullNotificationMethod
+	for _, np := range q.notificationPreferences {
+		if np.UserID == arg.UserID && np.NotificationTemplateID == arg.NotificationTemplateID {
+			userMethod = np.Method
+			break
+		}
+	}
+
 	return database.FetchNewMessageMetadataRow{
 		UserEmail:        user.Email,
 		UserName:         userName,
@@ -2294,6 +2646,7 @@ func (q *FakeQuerier) FetchNewMessageMetadata(_ context.Context, arg database.Fe
 		NotificationName: "Some notification",
 		Actions:          actions,
 		UserID:           arg.UserID,
+		UserMethod:       userMethod,
 	}, nil
 }
 
@@ -2367,6 +2720,18 @@ func (q *FakeQuerier) GetAPIKeysLastUsedAfter(_ context.Context, after time.Time
 	return apiKeys, nil
 }
 
+func (q *FakeQuerier) GetActiveTemplateRolloutByTemplateID(_ context.Context, templateID uuid.UUID) (database.TemplateRollout, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, rollout := range q.templateRollouts {
+		if rollout.TemplateID == templateID && rollout.Status == database.TemplateRolloutStatusInProgress {
+			return rollout, nil
+		}
+	}
+	return database.TemplateRollout{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetActiveUserCount(_ context.Context) (int64, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -2481,34 +2846,122 @@ func (q *FakeQuerier) GetAuthorizationUserRoles(_ context.Context, userID uuid.U
 			roles = append(roles, "organization-member:"+mem.OrganizationID.String())
 		}
 	}
-
-	var groups []string
-	for _, member := range q.groupMembers {
-		if member.UserID == userID {
-			groups = append(groups, member.GroupID.String())
+
+	var groups []string
+	for _, member := range q.groupMembers {
+		if member.UserID == userID {
+			groups = append(groups, member.GroupID.String())
+		}
+	}
+
+	if user == nil {
+		return database.GetAuthorizationUserRolesRow{}, sql.ErrNoRows
+	}
+
+	return database.GetAuthorizationUserRolesRow{
+		ID:       userID,
+		Username: user.Username,
+		Status:   user.Status,
+		Roles:    roles,
+		Groups:   groups,
+	}, nil
+}
+
+func (q *FakeQuerier) GetCoordinatorResumeTokenSigningKey(_ context.Context) (string, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+	if q.coordinatorResumeTokenSigningKey == "" {
+		return "", sql.ErrNoRows
+	}
+	return q.coordinatorResumeTokenSigningKey, nil
+}
+
+func (q *FakeQuerier) GetCostBudgetByGroupID(_ context.Context, groupID uuid.UUID) (database.CostBudget, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, budget := range q.costBudgets {
+		if budget.GroupID == groupID {
+			return budget, nil
+		}
+	}
+	return database.CostBudget{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetCostBudgetMemberIDs(ctx context.Context, groupID uuid.UUID) ([]uuid.UUID, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	members, err := q.getGroupMembersByGroupIDNoLock(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	var userIDs []uuid.UUID
+	for _, member := range members {
+		if member.UserStatus == database.UserStatusActive {
+			userIDs = append(userIDs, member.UserID)
 		}
 	}
+	return userIDs, nil
+}
 
-	if user == nil {
-		return database.GetAuthorizationUserRolesRow{}, sql.ErrNoRows
-	}
+func (q *FakeQuerier) GetCostBudgetWorkspaceIDs(ctx context.Context, groupID uuid.UUID) ([]uuid.UUID, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
 
-	return database.GetAuthorizationUserRolesRow{
-		ID:       userID,
-		Username: user.Username,
-		Status:   user.Status,
-		Roles:    roles,
-		Groups:   groups,
-	}, nil
+	members, err := q.getGroupMembersByGroupIDNoLock(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	var workspaceIDs []uuid.UUID
+	for _, workspace := range q.workspaces {
+		if workspace.Deleted {
+			continue
+		}
+		for _, member := range members {
+			if member.UserID == workspace.OwnerID && member.OrganizationID == workspace.OrganizationID {
+				workspaceIDs = append(workspaceIDs, workspace.ID)
+				break
+			}
+		}
+	}
+	return workspaceIDs, nil
 }
 
-func (q *FakeQuerier) GetCoordinatorResumeTokenSigningKey(_ context.Context) (string, error) {
+func (q *FakeQuerier) GetCostBudgetsWithCost(ctx context.Context, since time.Time) ([]database.GetCostBudgetsWithCostRow, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
-	if q.coordinatorResumeTokenSigningKey == "" {
-		return "", sql.ErrNoRows
+
+	var rows []database.GetCostBudgetsWithCostRow
+	for _, budget := range q.costBudgets {
+		group, err := q.getGroupByIDNoLock(ctx, budget.GroupID)
+		if err != nil {
+			continue
+		}
+		members, err := q.getGroupMembersByGroupIDNoLock(ctx, budget.GroupID)
+		if err != nil {
+			return nil, err
+		}
+		memberIDs := make(map[uuid.UUID]struct{})
+		for _, member := range members {
+			memberIDs[member.UserID] = struct{}{}
+		}
+		row := database.GetCostBudgetsWithCostRow{
+			CostBudget: budget,
+			GroupName:  group.Name,
+		}
+		for _, wc := range q.workspaceCosts {
+			if _, ok := memberIDs[wc.OwnerID]; !ok {
+				continue
+			}
+			if wc.OrganizationID != budget.OrganizationID || wc.BucketStart.Before(since) {
+				continue
+			}
+			row.Cost += wc.Cost
+		}
+		rows = append(rows, row)
 	}
-	return q.coordinatorResumeTokenSigningKey, nil
+	return rows, nil
 }
 
 func (q *FakeQuerier) GetCryptoKeyByFeatureAndSequence(_ context.Context, arg database.GetCryptoKeyByFeatureAndSequenceParams) (database.CryptoKey, error) {
@@ -3085,25 +3538,7 @@ func (q *FakeQuerier) GetGroupMembersByGroupID(ctx context.Context, id uuid.UUID
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
 
-	if q.isEveryoneGroup(id) {
-		return q.getEveryoneGroupMembersNoLock(ctx, id), nil
-	}
-
-	var groupMembers []database.GroupMember
-	for _, member := range q.groupMembers {
-		if member.GroupID == id {
-			groupMember, err := q.getGroupMemberNoLock(ctx, member.UserID, member.GroupID)
-			if errors.Is(err, errUserDeleted) {
-				continue
-			}
-			if err != nil {
-				return nil, err
-			}
-			groupMembers = append(groupMembers, groupMember)
-		}
-	}
-
-	return groupMembers, nil
+	return q.getGroupMembersByGroupIDNoLock(ctx, id)
 }
 
 func (q *FakeQuerier) GetGroupMembersCountByGroupID(ctx context.Context, groupID uuid.UUID) (int64, error) {
@@ -3348,6 +3783,68 @@ func (q *FakeQuerier) GetLogoURL(_ context.Context) (string, error) {
 	return q.logoURL, nil
 }
 
+func (q *FakeQuerier) GetNetworkRegionInsights(_ context.Context, arg database.GetNetworkRegionInsightsParams) ([]database.GetNetworkRegionInsightsRow, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	include := func(clientType string, reportedAt time.Time) bool {
+		if reportedAt.Before(arg.ReportedAfter) {
+			return false
+		}
+		return len(arg.ClientTypes) == 0 || slices.Contains(arg.ClientTypes, clientType)
+	}
+
+	rows := map[int32]*database.GetNetworkRegionInsightsRow{}
+	getRow := func(regionID int32) *database.GetNetworkRegionInsightsRow {
+		row, ok := rows[regionID]
+		if !ok {
+			row = &database.GetNetworkRegionInsightsRow{RegionID: regionID}
+			rows[regionID] = row
+		}
+		return row
+	}
+
+	latencies := map[int32][]float64{}
+	for _, report := range q.networkLatencyReports {
+		if !include(report.ClientType, report.ReportedAt) {
+			continue
+		}
+		row := getRow(report.RegionID)
+		row.Clients++
+		if report.Preferred {
+			row.PreferredClients++
+		}
+		latencies[report.RegionID] = append(latencies[report.RegionID], report.LatencyMS)
+	}
+	for _, report := range q.networkConnectionReports {
+		if !include(report.ClientType, report.ReportedAt) {
+			continue
+		}
+		row := getRow(report.HomeRegionID)
+		if report.Direct {
+			row.DirectConnections++
+		} else {
+			row.RelayedConnections++
+		}
+	}
+
+	result := make([]database.GetNetworkRegionInsightsRow, 0, len(rows))
+	for regionID, row := range rows {
+		row.Latency50 = tryPercentile(latencies[regionID], 50)
+		row.Latency95 = tryPercentile(latencies[regionID], 95)
+		result = append(result, *row)
+	}
+	slices.SortFunc(result, func(a, b database.GetNetworkRegionInsightsRow) int {
+		return int(a.RegionID - b.RegionID)
+	})
+	return result, nil
+}
+
 func (q *FakeQuerier) GetNotificationMessagesByStatus(_ context.Context, arg database.GetNotificationMessagesByStatusParams) ([]database.NotificationMessage, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -3525,27 +4022,25 @@ func (q *FakeQuerier) GetOAuth2ProviderAppsByUserID(_ context.Context, userID uu
 	rows := []database.GetOAuth2ProviderAppsByUserIDRow{}
 	for _, app := range q.oauth2ProviderApps {
 		tokens := []database.OAuth2ProviderAppToken{}
-		for _, secret := range q.oauth2ProviderAppSecrets {
-			if secret.AppID == app.ID {
-				for _, token := range q.oauth2ProviderAppTokens {
-					if token.AppSecretID == secret.ID {
-						keyIdx := slices.IndexFunc(q.apiKeys, func(key database.APIKey) bool {
-							return key.ID == token.APIKeyID
-						})
-						if keyIdx != -1 && q.apiKeys[keyIdx].UserID == userID {
-							tokens = append(tokens, token)
-						}
-					}
+		for _, token := range q.oauth2ProviderAppTokens {
+			if token.AppID == app.ID {
+				keyIdx := slices.IndexFunc(q.apiKeys, func(key database.APIKey) bool {
+					return key.ID == token.APIKeyID
+				})
+				if keyIdx != -1 && q.apiKeys[keyIdx].UserID == userID {
+					tokens = append(tokens, token)
 				}
 			}
 		}
 		if len(tokens) > 0 {
 			rows = append(rows, database.GetOAuth2ProviderAppsByUserIDRow{
 				OAuth2ProviderApp: database.OAuth2ProviderApp{
-					CallbackURL: app.CallbackURL,
-					ID:          app.ID,
-					Icon:        app.Icon,
-					Name:        app.Name,
+					CallbackURL:             app.CallbackURL,
+					ID:                      app.ID,
+					Icon:                    app.Icon,
+					Name:                    app.Name,
+					PublicClient:            app.PublicClient,
+					ClientCredentialsUserID: app.ClientCredentialsUserID,
 				},
 				TokenCount: int64(len(tokens)),
 			})
@@ -3554,6 +4049,30 @@ func (q *FakeQuerier) GetOAuth2ProviderAppsByUserID(_ context.Context, userID uu
 	return rows, nil
 }
 
+func (q *FakeQuerier) GetOAuth2ProviderDeviceCodeByPrefix(_ context.Context, secretPrefix []byte) (database.OAuth2ProviderDeviceCode, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, code := range q.oauth2ProviderDeviceCodes {
+		if bytes.Equal(code.SecretPrefix, secretPrefix) {
+			return code, nil
+		}
+	}
+	return database.OAuth2ProviderDeviceCode{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetOAuth2ProviderDeviceCodeByUserCode(_ context.Context, userCode string) (database.OAuth2ProviderDeviceCode, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, code := range q.oauth2ProviderDeviceCodes {
+		if code.UserCode == userCode {
+			return code, nil
+		}
+	}
+	return database.OAuth2ProviderDeviceCode{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetOAuthSigningKey(_ context.Context) (string, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -3660,6 +4179,77 @@ func (q *FakeQuerier) GetParameterSchemasByJobID(_ context.Context, jobID uuid.U
 	return parameters, nil
 }
 
+func (q *FakeQuerier) GetPendingProvisionerJobTags(_ context.Context, arg database.GetPendingProvisionerJobTagsParams) ([]database.GetPendingProvisionerJobTagsRow, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rows := make([]database.GetPendingProvisionerJobTagsRow, 0)
+	for _, job := range q.provisionerJobs {
+		if job.StartedAt.Valid || job.CanceledAt.Valid || job.CompletedAt.Valid {
+			continue
+		}
+		if job.OrganizationID != arg.OrganizationID && job.InitiatorID != arg.InitiatorID {
+			continue
+		}
+		if slices.ContainsFunc(rows, func(row database.GetPendingProvisionerJobTagsRow) bool {
+			return row.OrganizationID == job.OrganizationID &&
+				row.Provisioner == job.Provisioner &&
+				maps.Equal(row.Tags, job.Tags)
+		}) {
+			continue
+		}
+		rows = append(rows, database.GetPendingProvisionerJobTagsRow{
+			OrganizationID: job.OrganizationID,
+			Provisioner:    job.Provisioner,
+			Tags:           maps.Clone(job.Tags),
+		})
+	}
+	return rows, nil
+}
+
+func (q *FakeQuerier) GetPendingWorkspaceBuildApprovalsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.GetPendingWorkspaceBuildApprovalsByTemplateIDRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rows := make([]database.GetPendingWorkspaceBuildApprovalsByTemplateIDRow, 0)
+	for _, approval := range q.workspaceBuildApprovals {
+		if approval.Status != database.WorkspaceBuildApprovalStatusPending {
+			continue
+		}
+		build, err := q.getWorkspaceBuildByIDNoLock(ctx, approval.WorkspaceBuildID)
+		if err != nil {
+			return nil, err
+		}
+		workspace, err := q.getWorkspaceByIDNoLock(ctx, build.WorkspaceID)
+		if err != nil {
+			return nil, err
+		}
+		if workspace.TemplateID != templateID {
+			continue
+		}
+		job, err := q.getProvisionerJobByIDNoLock(ctx, build.JobID)
+		if err != nil {
+			return nil, err
+		}
+		if job.CompletedAt.Valid {
+			continue
+		}
+		rows = append(rows, database.GetPendingWorkspaceBuildApprovalsByTemplateIDRow{
+			WorkspaceBuildApproval: approval,
+			WorkspaceID:            build.WorkspaceID,
+		})
+	}
+	slices.SortFunc(rows, func(a, b database.GetPendingWorkspaceBuildApprovalsByTemplateIDRow) int {
+		return a.WorkspaceBuildApproval.CreatedAt.Compare(b.WorkspaceBuildApproval.CreatedAt)
+	})
+	return rows, nil
+}
+
 func (q *FakeQuerier) GetPreviousTemplateVersion(_ context.Context, arg database.GetPreviousTemplateVersionParams) (database.TemplateVersion, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.TemplateVersion{}, err
@@ -3857,6 +4447,23 @@ func (q *FakeQuerier) GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (
 	return q.getProvisionerJobByIDNoLock(ctx, id)
 }
 
+func (q *FakeQuerier) GetProvisionerJobResourceChangesByJobID(_ context.Context, jobID uuid.UUID) ([]database.ProvisionerJobResourceChange, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	changes := make([]database.ProvisionerJobResourceChange, 0)
+	for _, change := range q.provisionerJobResourceChanges {
+		if change.JobID == jobID {
+			changes = append(changes, change)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Address < changes[j].Address
+	})
+
+	return changes, nil
+}
+
 func (q *FakeQuerier) GetProvisionerJobTimingsByJobID(_ context.Context, jobID uuid.UUID) ([]database.ProvisionerJobTiming, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -3907,7 +4514,7 @@ func (q *FakeQuerier) GetProvisionerJobsByIDsWithQueuePosition(_ context.Context
 
 	//	WITH pending_jobs AS (
 	//		SELECT
-	//			id, created_at
+	//			id, created_at, priority
 	//		FROM
 	//			provisioner_jobs
 	//		WHERE
@@ -3922,6 +4529,7 @@ func (q *FakeQuerier) GetProvisionerJobsByIDsWithQueuePosition(_ context.Context
 	type pendingJobRow struct {
 		ID        uuid.UUID
 		CreatedAt time.Time
+		Priority  int32
 	}
 	pendingJobs := make([]pendingJobRow, 0)
 	for _, job := range q.provisionerJobs {
@@ -3934,17 +4542,21 @@ func (q *FakeQuerier) GetProvisionerJobsByIDsWithQueuePosition(_ context.Context
 		pendingJobs = append(pendingJobs, pendingJobRow{
 			ID:        job.ID,
 			CreatedAt: job.CreatedAt,
+			Priority:  job.Priority,
 		})
 	}
 
 	//	queue_position AS (
 	//		SELECT
 	//			id,
-	//				ROW_NUMBER() OVER (ORDER BY created_at ASC) AS queue_position
+	//				ROW_NUMBER() OVER (ORDER BY priority DESC, created_at ASC) AS queue_position
 	//		FROM
 	//			pending_jobs
 	// 	),
 	slices.SortFunc(pendingJobs, func(a, b pendingJobRow) int {
+		if a.Priority != b.Priority {
+			return int(b.Priority - a.Priority)
+		}
 		c := a.CreatedAt.Compare(b.CreatedAt)
 		return c
 	})
@@ -4071,6 +4683,53 @@ func (q *FakeQuerier) GetProvisionerLogsAfterID(_ context.Context, arg database.
 	return logs, nil
 }
 
+func (q *FakeQuerier) GetPurgeableRowCounts(_ context.Context, arg database.GetPurgeableRowCountsParams) (database.GetPurgeableRowCountsRow, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.GetPurgeableRowCountsRow{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var row database.GetPurgeableRowCountsRow
+	for _, alog := range q.auditLogs {
+		if alog.Time.Before(arg.AuditLogsBefore) {
+			row.AuditLogs++
+		}
+	}
+	oldAgents := q.oldWorkspaceAgentIDsNoLock(arg.WorkspaceAgentLogsBefore)
+	for _, log := range q.workspaceAgentLogs {
+		if _, ok := oldAgents[log.AgentID]; ok {
+			row.WorkspaceAgentLogs++
+		}
+	}
+	latestJobs := q.latestWorkspaceBuildJobIDsNoLock()
+	for _, log := range q.provisionerJobLogs {
+		if _, ok := latestJobs[log.JobID]; !ok && log.CreatedAt.Before(arg.ProvisionerJobLogsBefore) {
+			row.ProvisionerJobLogs++
+		}
+	}
+	latestBuilds := q.latestWorkspaceBuildIDsNoLock()
+	for _, wb := range q.workspaceBuilds {
+		if _, ok := latestBuilds[wb.ID]; !ok && wb.ProvisionerState != nil && wb.CreatedAt.Before(arg.WorkspaceBuildStatesBefore) {
+			row.WorkspaceBuildStates++
+		}
+	}
+	for _, msg := range q.notificationMessages {
+		if msg.UpdatedAt.Valid && msg.UpdatedAt.Time.Before(arg.NotificationMessagesBefore) {
+			row.NotificationMessages++
+		}
+	}
+	statsCutoff := q.workspaceAgentStatsPurgeCutoffNoLock(arg.WorkspaceAgentStatsBefore)
+	for _, stat := range q.workspaceAgentStats {
+		if stat.CreatedAt.Before(statsCutoff) {
+			row.WorkspaceAgentStats++
+		}
+	}
+	return row, nil
+}
+
 func (q *FakeQuerier) GetQuotaAllowanceForUser(_ context.Context, params database.GetQuotaAllowanceForUserParams) (int64, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -4166,19 +4825,108 @@ func (q *FakeQuerier) GetReplicasUpdatedAfter(_ context.Context, updatedAt time.
 			replicas = append(replicas, replica)
 		}
 	}
-	return replicas, nil
+	return replicas, nil
+}
+
+func (q *FakeQuerier) GetRuntimeConfig(_ context.Context, key string) (string, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	val, ok := q.runtimeConfig[key]
+	if !ok {
+		return "", sql.ErrNoRows
+	}
+
+	return val, nil
+}
+
+func (q *FakeQuerier) GetSecretByID(_ context.Context, id uuid.UUID) (database.Secret, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, secret := range q.secrets {
+		if secret.ID == id {
+			return secret, nil
+		}
+	}
+	return database.Secret{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetSecretByScopeAndName(_ context.Context, arg database.GetSecretByScopeAndNameParams) (database.Secret, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.Secret{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, secret := range q.secrets {
+		if secret.UserID == arg.UserID &&
+			secret.OrganizationID == arg.OrganizationID &&
+			secret.TemplateID == arg.TemplateID &&
+			secret.Name == arg.Name {
+			return secret, nil
+		}
+	}
+	return database.Secret{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetSecrets(_ context.Context) ([]database.Secret, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	secrets := slices.Clone(q.secrets)
+	slices.SortFunc(secrets, func(a, b database.Secret) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return secrets, nil
+}
+
+func (q *FakeQuerier) GetSecretsByScope(_ context.Context, arg database.GetSecretsByScopeParams) ([]database.Secret, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	secrets := make([]database.Secret, 0)
+	for _, secret := range q.secrets {
+		if secret.UserID == arg.UserID &&
+			secret.OrganizationID == arg.OrganizationID &&
+			secret.TemplateID == arg.TemplateID {
+			secrets = append(secrets, secret)
+		}
+	}
+	slices.SortFunc(secrets, func(a, b database.Secret) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return secrets, nil
 }
 
-func (q *FakeQuerier) GetRuntimeConfig(_ context.Context, key string) (string, error) {
-	q.mutex.Lock()
-	defer q.mutex.Unlock()
-
-	val, ok := q.runtimeConfig[key]
-	if !ok {
-		return "", sql.ErrNoRows
+func (q *FakeQuerier) GetSecretsForWorkspace(_ context.Context, arg database.GetSecretsForWorkspaceParams) ([]database.Secret, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
 	}
 
-	return val, nil
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	secrets := make([]database.Secret, 0)
+	for _, secret := range q.secrets {
+		if (secret.UserID.Valid && secret.UserID.UUID == arg.OwnerID) ||
+			(secret.TemplateID.Valid && secret.TemplateID.UUID == arg.TemplateID) ||
+			(secret.OrganizationID.Valid && secret.OrganizationID.UUID == arg.OrganizationID) {
+			secrets = append(secrets, secret)
+		}
+	}
+	slices.SortFunc(secrets, func(a, b database.Secret) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return secrets, nil
 }
 
 func (*FakeQuerier) GetTailnetAgents(context.Context, uuid.UUID) ([]database.TailnetAgent, error) {
@@ -5191,6 +5939,65 @@ func (q *FakeQuerier) GetTemplateParameterInsights(ctx context.Context, arg data
 	return rows, nil
 }
 
+func (q *FakeQuerier) GetTemplateRolloutBuildCounts(ctx context.Context, arg database.GetTemplateRolloutBuildCountsParams) (database.GetTemplateRolloutBuildCountsRow, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.GetTemplateRolloutBuildCountsRow{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var row database.GetTemplateRolloutBuildCountsRow
+	for _, build := range q.workspaceBuilds {
+		if build.TemplateVersionID != arg.TemplateVersionID ||
+			build.Transition != database.WorkspaceTransitionStart ||
+			build.CreatedAt.Before(arg.Since) {
+			continue
+		}
+		job, err := q.getProvisionerJobByIDNoLock(ctx, build.JobID)
+		if err != nil {
+			return database.GetTemplateRolloutBuildCountsRow{}, err
+		}
+		if !job.CompletedAt.Valid || job.CanceledAt.Valid {
+			continue
+		}
+		row.Builds++
+		if job.Error.Valid && job.Error.String != "" {
+			row.FailedBuilds++
+		}
+	}
+	return row, nil
+}
+
+func (q *FakeQuerier) GetTemplateRolloutByID(_ context.Context, id uuid.UUID) (database.TemplateRollout, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, rollout := range q.templateRollouts {
+		if rollout.ID == id {
+			return rollout, nil
+		}
+	}
+	return database.TemplateRollout{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetTemplateRolloutsByTemplateID(_ context.Context, templateID uuid.UUID) ([]database.TemplateRollout, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var rollouts []database.TemplateRollout
+	for _, rollout := range q.templateRollouts {
+		if rollout.TemplateID == templateID {
+			rollouts = append(rollouts, rollout)
+		}
+	}
+	slices.SortFunc(rollouts, func(a, b database.TemplateRollout) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return rollouts, nil
+}
+
 func (q *FakeQuerier) GetTemplateUsageStats(_ context.Context, arg database.GetTemplateUsageStatsParams) ([]database.TemplateUsageStat, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -6186,6 +6993,22 @@ func (q *FakeQuerier) GetWorkspaceAgentPortShare(_ context.Context, arg database
 	return database.WorkspaceAgentPortShare{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) GetWorkspaceAgentProcessGroups(_ context.Context, workspaceAgentID uuid.UUID) ([]database.WorkspaceAgentProcessGroup, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	groups := make([]database.WorkspaceAgentProcessGroup, 0)
+	for _, group := range q.workspaceAgentProcessGroups {
+		if group.WorkspaceAgentID == workspaceAgentID {
+			groups = append(groups, group)
+		}
+	}
+	slices.SortFunc(groups, func(a, b database.WorkspaceAgentProcessGroup) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return groups, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceAgentScriptTimingsByBuildID(ctx context.Context, id uuid.UUID) ([]database.GetWorkspaceAgentScriptTimingsByBuildIDRow, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -6729,6 +7552,18 @@ func (q *FakeQuerier) GetWorkspaceAppsCreatedAfter(_ context.Context, after time
 	return apps, nil
 }
 
+func (q *FakeQuerier) GetWorkspaceBuildApprovalByBuildID(_ context.Context, workspaceBuildID uuid.UUID) (database.WorkspaceBuildApproval, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, approval := range q.workspaceBuildApprovals {
+		if approval.WorkspaceBuildID == workspaceBuildID {
+			return approval, nil
+		}
+	}
+	return database.WorkspaceBuildApproval{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetWorkspaceBuildByID(ctx context.Context, id uuid.UUID) (database.WorkspaceBuild, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -6982,6 +7817,149 @@ func (q *FakeQuerier) GetWorkspaceByWorkspaceAppID(_ context.Context, workspaceA
 	return database.Workspace{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) GetWorkspaceCostInsightsByGroup(ctx context.Context, arg database.GetWorkspaceCostInsightsByGroupParams) ([]database.GetWorkspaceCostInsightsByGroupRow, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	costByUserAndOrg := make(map[[2]uuid.UUID]float64)
+	for _, wc := range q.filterWorkspaceCostsNoLock(arg.StartTime, arg.EndTime, arg.TemplateIDs) {
+		costByUserAndOrg[[2]uuid.UUID{wc.OwnerID, wc.OrganizationID}] += wc.Cost
+	}
+
+	var rows []database.GetWorkspaceCostInsightsByGroupRow
+	for _, group := range q.groups {
+		members, err := q.getGroupMembersByGroupIDNoLock(ctx, group.ID)
+		if err != nil {
+			return nil, err
+		}
+		row := database.GetWorkspaceCostInsightsByGroupRow{
+			GroupID:   group.ID,
+			GroupName: group.Name,
+		}
+		var found bool
+		for _, member := range members {
+			cost, ok := costByUserAndOrg[[2]uuid.UUID{member.UserID, member.OrganizationID}]
+			if !ok {
+				continue
+			}
+			found = true
+			row.Cost += cost
+		}
+		if found {
+			rows = append(rows, row)
+		}
+	}
+	slices.SortFunc(rows, func(a, b database.GetWorkspaceCostInsightsByGroupRow) int {
+		if a.Cost != b.Cost {
+			return slice.Descending(a.Cost, b.Cost)
+		}
+		return slice.Ascending(a.GroupName, b.GroupName)
+	})
+	return rows, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceCostInsightsByInterval(_ context.Context, arg database.GetWorkspaceCostInsightsByIntervalParams) ([]database.GetWorkspaceCostInsightsByIntervalRow, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var rows []database.GetWorkspaceCostInsightsByIntervalRow
+	for d := arg.StartTime; d.Before(arg.EndTime); d = d.AddDate(0, 0, int(arg.IntervalDays)) {
+		to := d.AddDate(0, 0, int(arg.IntervalDays))
+		if to.After(arg.EndTime) {
+			to = arg.EndTime
+		}
+		row := database.GetWorkspaceCostInsightsByIntervalRow{
+			StartTime: d,
+			EndTime:   to,
+		}
+		for _, wc := range q.filterWorkspaceCostsNoLock(d, to, arg.TemplateIDs) {
+			row.Cost += wc.Cost
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceCostInsightsByTemplate(ctx context.Context, arg database.GetWorkspaceCostInsightsByTemplateParams) ([]database.GetWorkspaceCostInsightsByTemplateRow, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	costByTemplate := make(map[uuid.UUID]float64)
+	for _, wc := range q.filterWorkspaceCostsNoLock(arg.StartTime, arg.EndTime, arg.TemplateIDs) {
+		costByTemplate[wc.TemplateID] += wc.Cost
+	}
+
+	var rows []database.GetWorkspaceCostInsightsByTemplateRow
+	for templateID, cost := range costByTemplate {
+		template, err := q.getTemplateByIDNoLock(ctx, templateID)
+		if err != nil {
+			continue
+		}
+		rows = append(rows, database.GetWorkspaceCostInsightsByTemplateRow{
+			TemplateID:   templateID,
+			TemplateName: template.Name,
+			Cost:         cost,
+		})
+	}
+	slices.SortFunc(rows, func(a, b database.GetWorkspaceCostInsightsByTemplateRow) int {
+		if a.Cost != b.Cost {
+			return slice.Descending(a.Cost, b.Cost)
+		}
+		return slice.Ascending(a.TemplateName, b.TemplateName)
+	})
+	return rows, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceCostInsightsByUser(_ context.Context, arg database.GetWorkspaceCostInsightsByUserParams) ([]database.GetWorkspaceCostInsightsByUserRow, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	costByUser := make(map[uuid.UUID]float64)
+	for _, wc := range q.filterWorkspaceCostsNoLock(arg.StartTime, arg.EndTime, arg.TemplateIDs) {
+		costByUser[wc.OwnerID] += wc.Cost
+	}
+
+	var rows []database.GetWorkspaceCostInsightsByUserRow
+	for userID, cost := range costByUser {
+		user, err := q.getUserByIDNoLock(userID)
+		if err != nil {
+			continue
+		}
+		rows = append(rows, database.GetWorkspaceCostInsightsByUserRow{
+			UserID:   userID,
+			Username: user.Username,
+			Cost:     cost,
+		})
+	}
+	slices.SortFunc(rows, func(a, b database.GetWorkspaceCostInsightsByUserRow) int {
+		if a.Cost != b.Cost {
+			return slice.Descending(a.Cost, b.Cost)
+		}
+		return slice.Ascending(a.Username, b.Username)
+	})
+	return rows, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceModulesByJobID(_ context.Context, jobID uuid.UUID) ([]database.WorkspaceModule, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -7172,6 +8150,140 @@ func (q *FakeQuerier) GetWorkspaceResourcesCreatedAfter(_ context.Context, after
 	return resources, nil
 }
 
+func (q *FakeQuerier) GetWorkspaceScheduledActionByID(_ context.Context, id uuid.UUID) (database.WorkspaceScheduledAction, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, action := range q.workspaceScheduledActions {
+		if action.ID == id {
+			return action, nil
+		}
+	}
+	return database.WorkspaceScheduledAction{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWorkspaceScheduledActionsByWorkspaceID(_ context.Context, workspaceID uuid.UUID) ([]database.WorkspaceScheduledAction, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var actions []database.WorkspaceScheduledAction
+	for _, action := range q.workspaceScheduledActions {
+		if action.WorkspaceID == workspaceID {
+			actions = append(actions, action)
+		}
+	}
+	slices.SortFunc(actions, func(a, b database.WorkspaceScheduledAction) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return actions, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceScheduledActionsDue(_ context.Context, now time.Time) ([]database.WorkspaceScheduledAction, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var actions []database.WorkspaceScheduledAction
+	for _, action := range q.workspaceScheduledActions {
+		if action.NextRunAt.After(now) {
+			continue
+		}
+		workspace, err := q.getWorkspaceByIDNoLock(context.Background(), action.WorkspaceID)
+		if err != nil || workspace.Deleted {
+			continue
+		}
+		actions = append(actions, action)
+	}
+	slices.SortFunc(actions, func(a, b database.WorkspaceScheduledAction) int {
+		return a.NextRunAt.Compare(b.NextRunAt)
+	})
+	return actions, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceSessionRecordingByID(_ context.Context, id uuid.UUID) (database.WorkspaceSessionRecording, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, recording := range q.workspaceSessionRecordings {
+		if recording.ID == id {
+			return recording, nil
+		}
+	}
+	return database.WorkspaceSessionRecording{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWorkspaceSessionRecordingChunksByRecordingID(_ context.Context, arg database.GetWorkspaceSessionRecordingChunksByRecordingIDParams) ([]database.WorkspaceSessionRecordingChunk, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var chunks []database.WorkspaceSessionRecordingChunk
+	for _, chunk := range q.workspaceSessionRecordingChunks {
+		if chunk.RecordingID == arg.RecordingID && chunk.Sequence > arg.AfterSequence {
+			chunks = append(chunks, chunk)
+		}
+	}
+	slices.SortFunc(chunks, func(a, b database.WorkspaceSessionRecordingChunk) int {
+		return slice.Ascending(a.Sequence, b.Sequence)
+	})
+	if len(chunks) > int(arg.LimitOpt) {
+		chunks = chunks[:arg.LimitOpt]
+	}
+	return chunks, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceSessionRecordingsByWorkspaceID(_ context.Context, workspaceID uuid.UUID) ([]database.WorkspaceSessionRecording, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var recordings []database.WorkspaceSessionRecording
+	for _, recording := range q.workspaceSessionRecordings {
+		if recording.WorkspaceID == workspaceID {
+			recordings = append(recordings, recording)
+		}
+	}
+	slices.SortFunc(recordings, func(a, b database.WorkspaceSessionRecording) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return recordings, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceSnapshotByID(_ context.Context, id uuid.UUID) (database.WorkspaceSnapshot, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, snapshot := range q.workspaceSnapshots {
+		if snapshot.ID == id {
+			return snapshot, nil
+		}
+	}
+	return database.WorkspaceSnapshot{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWorkspaceSnapshotsByOwnerAndTemplate(_ context.Context, arg database.GetWorkspaceSnapshotsByOwnerAndTemplateParams) ([]database.WorkspaceSnapshot, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	snapshots := make([]database.WorkspaceSnapshot, 0)
+	for _, snapshot := range q.workspaceSnapshots {
+		if snapshot.OwnerID == arg.OwnerID && snapshot.TemplateID == arg.TemplateID {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	slices.SortFunc(snapshots, func(a, b database.WorkspaceSnapshot) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return snapshots, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceUniqueOwnerCountByTemplateIDs(_ context.Context, templateIds []uuid.UUID) ([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -7357,18 +8469,26 @@ func (q *FakeQuerier) InsertAPIKey(_ context.Context, arg database.InsertAPIKeyP
 
 	//nolint:gosimple
 	key := database.APIKey{
-		ID:              arg.ID,
-		LifetimeSeconds: arg.LifetimeSeconds,
-		HashedSecret:    arg.HashedSecret,
-		IPAddress:       arg.IPAddress,
-		UserID:          arg.UserID,
-		ExpiresAt:       arg.ExpiresAt,
-		CreatedAt:       arg.CreatedAt,
-		UpdatedAt:       arg.UpdatedAt,
-		LastUsed:        arg.LastUsed,
-		LoginType:       arg.LoginType,
-		Scope:           arg.Scope,
-		TokenName:       arg.TokenName,
+		ID:               arg.ID,
+		LifetimeSeconds:  arg.LifetimeSeconds,
+		HashedSecret:     arg.HashedSecret,
+		IPAddress:        arg.IPAddress,
+		UserID:           arg.UserID,
+		ExpiresAt:        arg.ExpiresAt,
+		CreatedAt:        arg.CreatedAt,
+		UpdatedAt:        arg.UpdatedAt,
+		LastUsed:         arg.LastUsed,
+		LoginType:        arg.LoginType,
+		Scope:            arg.Scope,
+		TokenName:        arg.TokenName,
+		ScopePermissions: arg.ScopePermissions,
+		ScopeAllowList:   arg.ScopeAllowList,
+	}
+	if key.ScopePermissions == nil {
+		key.ScopePermissions = database.APIKeyScopePermissions{}
+	}
+	if key.ScopeAllowList == nil {
+		key.ScopeAllowList = []string{}
 	}
 	q.apiKeys = append(q.apiKeys, key)
 	return key, nil
@@ -7467,6 +8587,9 @@ func (q *FakeQuerier) InsertDBCryptKey(_ context.Context, arg database.InsertDBC
 		return err
 	}
 
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
 	for _, key := range q.dbcryptKeys {
 		if key.Number == arg.Number {
 			return errUniqueConstraint
@@ -7476,7 +8599,10 @@ func (q *FakeQuerier) InsertDBCryptKey(_ context.Context, arg database.InsertDBC
 	q.dbcryptKeys = append(q.dbcryptKeys, database.DBCryptKey{
 		Number:          arg.Number,
 		ActiveKeyDigest: sql.NullString{String: arg.ActiveKeyDigest, Valid: true},
+		CreatedAt:       sql.NullTime{Time: dbtime.Now(), Valid: true},
 		Test:            arg.Test,
+		KeyProvider:     arg.KeyProvider,
+		WrappedKey:      arg.WrappedKey,
 	})
 	return nil
 }
@@ -7694,12 +8820,14 @@ func (q *FakeQuerier) InsertOAuth2ProviderApp(_ context.Context, arg database.In
 
 	//nolint:gosimple // Go wants database.OAuth2ProviderApp(arg), but we cannot be sure the structs will remain identical.
 	app := database.OAuth2ProviderApp{
-		ID:          arg.ID,
-		CreatedAt:   arg.CreatedAt,
-		UpdatedAt:   arg.UpdatedAt,
-		Name:        arg.Name,
-		Icon:        arg.Icon,
-		CallbackURL: arg.CallbackURL,
+		ID:                      arg.ID,
+		CreatedAt:               arg.CreatedAt,
+		UpdatedAt:               arg.UpdatedAt,
+		Name:                    arg.Name,
+		Icon:                    arg.Icon,
+		CallbackURL:             arg.CallbackURL,
+		PublicClient:            arg.PublicClient,
+		ClientCredentialsUserID: arg.ClientCredentialsUserID,
 	}
 	q.oauth2ProviderApps = append(q.oauth2ProviderApps, app)
 
@@ -7718,13 +8846,14 @@ func (q *FakeQuerier) InsertOAuth2ProviderAppCode(_ context.Context, arg databas
 	for _, app := range q.oauth2ProviderApps {
 		if app.ID == arg.AppID {
 			code := database.OAuth2ProviderAppCode{
-				ID:           arg.ID,
-				CreatedAt:    arg.CreatedAt,
-				ExpiresAt:    arg.ExpiresAt,
-				SecretPrefix: arg.SecretPrefix,
-				HashedSecret: arg.HashedSecret,
-				UserID:       arg.UserID,
-				AppID:        arg.AppID,
+				ID:            arg.ID,
+				CreatedAt:     arg.CreatedAt,
+				ExpiresAt:     arg.ExpiresAt,
+				SecretPrefix:  arg.SecretPrefix,
+				HashedSecret:  arg.HashedSecret,
+				UserID:        arg.UserID,
+				AppID:         arg.AppID,
+				CodeChallenge: arg.CodeChallenge,
 			}
 			q.oauth2ProviderAppCodes = append(q.oauth2ProviderAppCodes, code)
 			return code, nil
@@ -7770,8 +8899,8 @@ func (q *FakeQuerier) InsertOAuth2ProviderAppToken(_ context.Context, arg databa
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
-	for _, secret := range q.oauth2ProviderAppSecrets {
-		if secret.ID == arg.AppSecretID {
+	for _, app := range q.oauth2ProviderApps {
+		if app.ID == arg.AppID {
 			//nolint:gosimple // Go wants database.OAuth2ProviderAppToken(arg), but we cannot be sure the structs will remain identical.
 			token := database.OAuth2ProviderAppToken{
 				ID:          arg.ID,
@@ -7781,6 +8910,7 @@ func (q *FakeQuerier) InsertOAuth2ProviderAppToken(_ context.Context, arg databa
 				RefreshHash: arg.RefreshHash,
 				APIKeyID:    arg.APIKeyID,
 				AppSecretID: arg.AppSecretID,
+				AppID:       arg.AppID,
 			}
 			q.oauth2ProviderAppTokens = append(q.oauth2ProviderAppTokens, token)
 			return token, nil
@@ -7790,6 +8920,41 @@ func (q *FakeQuerier) InsertOAuth2ProviderAppToken(_ context.Context, arg databa
 	return database.OAuth2ProviderAppToken{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) InsertOAuth2ProviderDeviceCode(_ context.Context, arg database.InsertOAuth2ProviderDeviceCodeParams) (database.OAuth2ProviderDeviceCode, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.OAuth2ProviderDeviceCode{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, code := range q.oauth2ProviderDeviceCodes {
+		if code.UserCode == arg.UserCode || bytes.Equal(code.SecretPrefix, arg.SecretPrefix) {
+			return database.OAuth2ProviderDeviceCode{}, errUniqueConstraint
+		}
+	}
+
+	for _, app := range q.oauth2ProviderApps {
+		if app.ID == arg.AppID {
+			code := database.OAuth2ProviderDeviceCode{
+				ID:           arg.ID,
+				CreatedAt:    arg.CreatedAt,
+				ExpiresAt:    arg.ExpiresAt,
+				SecretPrefix: arg.SecretPrefix,
+				HashedSecret: arg.HashedSecret,
+				UserCode:     arg.UserCode,
+				AppID:        arg.AppID,
+				Status:       database.OAuth2ProviderDeviceCodeStatusPending,
+			}
+			q.oauth2ProviderDeviceCodes = append(q.oauth2ProviderDeviceCodes, code)
+			return code, nil
+		}
+	}
+
+	return database.OAuth2ProviderDeviceCode{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) InsertOrganization(_ context.Context, arg database.InsertOrganizationParams) (database.Organization, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.Organization{}, err
@@ -7867,6 +9032,7 @@ func (q *FakeQuerier) InsertProvisionerJob(_ context.Context, arg database.Inser
 		Input:          arg.Input,
 		Tags:           maps.Clone(arg.Tags),
 		TraceMetadata:  arg.TraceMetadata,
+		Priority:       arg.Priority,
 	}
 	job.JobStatus = provisionerJobStatus(job)
 	q.provisionerJobs = append(q.provisionerJobs, job)
@@ -7902,6 +9068,48 @@ func (q *FakeQuerier) InsertProvisionerJobLogs(_ context.Context, arg database.I
 	return logs, nil
 }
 
+func (q *FakeQuerier) InsertProvisionerJobResourceChanges(_ context.Context, arg database.InsertProvisionerJobResourceChangesParams) ([]database.ProvisionerJobResourceChange, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	var attributes []json.RawMessage
+	if err := json.Unmarshal(arg.Attributes, &attributes); err != nil {
+		return nil, xerrors.Errorf("unmarshal attributes: %w", err)
+	}
+	if len(attributes) != len(arg.Address) {
+		return nil, xerrors.Errorf("expected %d attributes, got %d", len(arg.Address), len(attributes))
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, address := range arg.Address {
+		for _, change := range q.provisionerJobResourceChanges {
+			if change.JobID == arg.JobID && change.Address == address {
+				return nil, errUniqueConstraint
+			}
+		}
+	}
+
+	insertedChanges := make([]database.ProvisionerJobResourceChange, 0, len(arg.Address))
+	for i := range arg.Address {
+		change := database.ProvisionerJobResourceChange{
+			JobID:        arg.JobID,
+			Address:      arg.Address[i],
+			ResourceType: arg.ResourceType[i],
+			ResourceName: arg.ResourceName[i],
+			Action:       arg.Action[i],
+			Attributes:   attributes[i],
+		}
+		q.provisionerJobResourceChanges = append(q.provisionerJobResourceChanges, change)
+		insertedChanges = append(insertedChanges, change)
+	}
+
+	return insertedChanges, nil
+}
+
 func (q *FakeQuerier) InsertProvisionerJobTimings(_ context.Context, arg database.InsertProvisionerJobTimingsParams) ([]database.ProvisionerJobTiming, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -7982,6 +9190,48 @@ func (q *FakeQuerier) InsertReplica(_ context.Context, arg database.InsertReplic
 	return replica, nil
 }
 
+func (q *FakeQuerier) InsertSecret(_ context.Context, arg database.InsertSecretParams) (database.Secret, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.Secret{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, secret := range q.secrets {
+		if secret.Name != arg.Name {
+			continue
+		}
+		switch {
+		case arg.UserID.Valid && secret.UserID == arg.UserID:
+			return database.Secret{}, newUniqueConstraintError(database.UniqueIndexSecretsUserIDName)
+		case arg.OrganizationID.Valid && secret.OrganizationID == arg.OrganizationID:
+			return database.Secret{}, newUniqueConstraintError(database.UniqueIndexSecretsOrganizationIDName)
+		case arg.TemplateID.Valid && secret.TemplateID == arg.TemplateID:
+			return database.Secret{}, newUniqueConstraintError(database.UniqueIndexSecretsTemplateIDName)
+		}
+	}
+
+	//nolint:gosimple
+	secret := database.Secret{
+		ID:             arg.ID,
+		CreatedAt:      arg.CreatedAt,
+		UpdatedAt:      arg.UpdatedAt,
+		Name:           arg.Name,
+		Description:    arg.Description,
+		Value:          arg.Value,
+		ValueKeyID:     arg.ValueKeyID,
+		EnvName:        arg.EnvName,
+		FilePath:       arg.FilePath,
+		UserID:         arg.UserID,
+		OrganizationID: arg.OrganizationID,
+		TemplateID:     arg.TemplateID,
+	}
+	q.secrets = append(q.secrets, secret)
+	return secret, nil
+}
+
 func (q *FakeQuerier) InsertTemplate(_ context.Context, arg database.InsertTemplateParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -8009,11 +9259,46 @@ func (q *FakeQuerier) InsertTemplate(_ context.Context, arg database.InsertTempl
 		AllowUserAutostart:           true,
 		AllowUserAutostop:            true,
 		MaxPortSharingLevel:          arg.MaxPortSharingLevel,
+		BuildApprovalParameters:      []string{},
+		BuildApprovalTimeout:         int64(24 * time.Hour),
 	}
 	q.templates = append(q.templates, template)
 	return nil
 }
 
+func (q *FakeQuerier) InsertTemplateRollout(_ context.Context, arg database.InsertTemplateRolloutParams) (database.TemplateRollout, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.TemplateRollout{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, rollout := range q.templateRollouts {
+		if rollout.TemplateID == arg.TemplateID && rollout.Status == database.TemplateRolloutStatusInProgress {
+			return database.TemplateRollout{}, newUniqueConstraintError(database.UniqueTemplateRolloutsTemplateIDInProgressIndex)
+		}
+	}
+
+	rollout := database.TemplateRollout{
+		ID:                arg.ID,
+		TemplateID:        arg.TemplateID,
+		TemplateVersionID: arg.TemplateVersionID,
+		CreatedBy:         arg.CreatedBy,
+		CreatedAt:         arg.CreatedAt,
+		UpdatedAt:         arg.UpdatedAt,
+		Status:            database.TemplateRolloutStatusInProgress,
+		Percent:           arg.Percent,
+		GroupID:           arg.GroupID,
+		MinBuilds:         arg.MinBuilds,
+		MaxFailureRate:    arg.MaxFailureRate,
+		AutoPromote:       arg.AutoPromote,
+	}
+	q.templateRollouts = append(q.templateRollouts, rollout)
+	return rollout, nil
+}
+
 func (q *FakeQuerier) InsertTemplateVersion(_ context.Context, arg database.InsertTemplateVersionParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -8427,6 +9712,7 @@ func (q *FakeQuerier) InsertWorkspaceAgentScripts(_ context.Context, arg databas
 			RunOnStart:       arg.RunOnStart[index],
 			RunOnStop:        arg.RunOnStop[index],
 			TimeoutSeconds:   arg.TimeoutSeconds[index],
+			DisplayName:      arg.DisplayName[index],
 			CreatedAt:        arg.CreatedAt,
 		}
 		scripts = append(scripts, script)
@@ -8585,6 +9871,32 @@ func (q *FakeQuerier) InsertWorkspaceBuild(_ context.Context, arg database.Inser
 	return nil
 }
 
+func (q *FakeQuerier) InsertWorkspaceBuildApproval(_ context.Context, arg database.InsertWorkspaceBuildApprovalParams) (database.WorkspaceBuildApproval, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceBuildApproval{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, approval := range q.workspaceBuildApprovals {
+		if approval.WorkspaceBuildID == arg.WorkspaceBuildID {
+			return database.WorkspaceBuildApproval{}, errUniqueConstraint
+		}
+	}
+
+	approval := database.WorkspaceBuildApproval{
+		WorkspaceBuildID: arg.WorkspaceBuildID,
+		CreatedAt:        arg.CreatedAt,
+		UpdatedAt:        arg.UpdatedAt,
+		ExpiresAt:        arg.ExpiresAt,
+		Status:           database.WorkspaceBuildApprovalStatusPending,
+	}
+	q.workspaceBuildApprovals = append(q.workspaceBuildApprovals, approval)
+	return approval, nil
+}
+
 func (q *FakeQuerier) InsertWorkspaceBuildParameters(_ context.Context, arg database.InsertWorkspaceBuildParametersParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -8668,6 +9980,7 @@ func (q *FakeQuerier) InsertWorkspaceResource(_ context.Context, arg database.In
 		Icon:       arg.Icon,
 		DailyCost:  arg.DailyCost,
 		ModulePath: arg.ModulePath,
+		HourlyCost: arg.HourlyCost,
 	}
 	q.workspaceResources = append(q.workspaceResources, resource)
 	return resource, nil
@@ -8704,6 +10017,116 @@ func (q *FakeQuerier) InsertWorkspaceResourceMetadata(_ context.Context, arg dat
 	return metadata, nil
 }
 
+func (q *FakeQuerier) InsertWorkspaceScheduledAction(_ context.Context, arg database.InsertWorkspaceScheduledActionParams) (database.WorkspaceScheduledAction, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceScheduledAction{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, action := range q.workspaceScheduledActions {
+		if action.ID == arg.ID {
+			return database.WorkspaceScheduledAction{}, errUniqueConstraint
+		}
+	}
+
+	action := database.WorkspaceScheduledAction{
+		ID:          arg.ID,
+		WorkspaceID: arg.WorkspaceID,
+		CreatedAt:   arg.CreatedAt,
+		UpdatedAt:   arg.UpdatedAt,
+		Action:      arg.Action,
+		Schedule:    arg.Schedule,
+		NextRunAt:   arg.NextRunAt,
+	}
+	q.workspaceScheduledActions = append(q.workspaceScheduledActions, action)
+	return action, nil
+}
+
+func (q *FakeQuerier) InsertWorkspaceSessionRecording(_ context.Context, arg database.InsertWorkspaceSessionRecordingParams) (database.WorkspaceSessionRecording, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceSessionRecording{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, recording := range q.workspaceSessionRecordings {
+		if recording.ID == arg.ID {
+			return database.WorkspaceSessionRecording{}, errUniqueConstraint
+		}
+	}
+
+	recording := database.WorkspaceSessionRecording{
+		ID:          arg.ID,
+		CreatedAt:   arg.CreatedAt,
+		UpdatedAt:   arg.UpdatedAt,
+		WorkspaceID: arg.WorkspaceID,
+		AgentID:     arg.AgentID,
+		UserID:      arg.UserID,
+		Type:        arg.Type,
+	}
+	q.workspaceSessionRecordings = append(q.workspaceSessionRecordings, recording)
+	return recording, nil
+}
+
+func (q *FakeQuerier) InsertWorkspaceSessionRecordingChunk(_ context.Context, arg database.InsertWorkspaceSessionRecordingChunkParams) (int64, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return 0, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, chunk := range q.workspaceSessionRecordingChunks {
+		if chunk.RecordingID == arg.RecordingID && chunk.Sequence == arg.Sequence {
+			return 0, nil
+		}
+	}
+
+	q.workspaceSessionRecordingChunks = append(q.workspaceSessionRecordingChunks, database.WorkspaceSessionRecordingChunk{
+		RecordingID: arg.RecordingID,
+		Sequence:    arg.Sequence,
+		Data:        slices.Clone(arg.Data),
+	})
+	return 1, nil
+}
+
+func (q *FakeQuerier) InsertWorkspaceSnapshot(_ context.Context, arg database.InsertWorkspaceSnapshotParams) (database.WorkspaceSnapshot, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceSnapshot{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, snapshot := range q.workspaceSnapshots {
+		if snapshot.OwnerID == arg.OwnerID && snapshot.TemplateID == arg.TemplateID && strings.EqualFold(snapshot.Name, arg.Name) {
+			return database.WorkspaceSnapshot{}, errUniqueConstraint
+		}
+	}
+
+	snapshot := database.WorkspaceSnapshot{
+		ID:             arg.ID,
+		CreatedAt:      arg.CreatedAt,
+		UpdatedAt:      arg.UpdatedAt,
+		OrganizationID: arg.OrganizationID,
+		OwnerID:        arg.OwnerID,
+		TemplateID:     arg.TemplateID,
+		WorkspaceID:    arg.WorkspaceID,
+		BuildID:        arg.BuildID,
+		Name:           arg.Name,
+		Artifacts:      json.RawMessage("{}"),
+	}
+	q.workspaceSnapshots = append(q.workspaceSnapshots, snapshot)
+	return snapshot, nil
+}
+
 func (q *FakeQuerier) ListProvisionerKeysByOrganization(_ context.Context, organizationID uuid.UUID) ([]database.ProvisionerKey, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -8901,6 +10324,33 @@ func (q *FakeQuerier) RegisterWorkspaceProxy(_ context.Context, arg database.Reg
 	return database.WorkspaceProxy{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) RejectExpiredWorkspaceBuildApprovals(_ context.Context, arg database.RejectExpiredWorkspaceBuildApprovalsParams) ([]database.WorkspaceBuildApproval, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	rejected := make([]database.WorkspaceBuildApproval, 0)
+	for i, approval := range q.workspaceBuildApprovals {
+		if approval.Status != database.WorkspaceBuildApprovalStatusPending {
+			continue
+		}
+		if !approval.ExpiresAt.Valid || approval.ExpiresAt.Time.After(arg.Now) {
+			continue
+		}
+		approval.UpdatedAt = arg.Now
+		approval.Status = database.WorkspaceBuildApprovalStatusRejected
+		approval.ReviewedAt = sql.NullTime{Time: arg.Now, Valid: true}
+		approval.Reason = arg.Reason
+		q.workspaceBuildApprovals[i] = approval
+		rejected = append(rejected, approval)
+	}
+	return rejected, nil
+}
+
 func (q *FakeQuerier) RemoveUserFromAllGroups(_ context.Context, userID uuid.UUID) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -9050,6 +10500,42 @@ func (q *FakeQuerier) UpdateAPIKeyByID(_ context.Context, arg database.UpdateAPI
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateCostBudgetNotifiedAtByGroupID(_ context.Context, arg database.UpdateCostBudgetNotifiedAtByGroupIDParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, budget := range q.costBudgets {
+		if budget.GroupID == arg.GroupID {
+			q.costBudgets[i].NotifiedAt = arg.NotifiedAt
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *FakeQuerier) UpdateCostBudgetStoppedAtByGroupID(_ context.Context, arg database.UpdateCostBudgetStoppedAtByGroupIDParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, budget := range q.costBudgets {
+		if budget.GroupID == arg.GroupID {
+			q.costBudgets[i].StoppedAt = arg.StoppedAt
+			return nil
+		}
+	}
+	return nil
+}
+
 func (q *FakeQuerier) UpdateCryptoKeyDeletesAt(_ context.Context, arg database.UpdateCryptoKeyDeletesAtParams) (database.CryptoKey, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -9069,6 +10555,26 @@ func (q *FakeQuerier) UpdateCryptoKeyDeletesAt(_ context.Context, arg database.U
 	return database.CryptoKey{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateCryptoKeySecret(_ context.Context, arg database.UpdateCryptoKeySecretParams) (database.CryptoKey, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.CryptoKey{}, err
+	}
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, key := range q.cryptoKeys {
+		if key.Feature == arg.Feature && key.Sequence == arg.Sequence {
+			key.Secret = arg.Secret
+			key.SecretKeyID = arg.SecretKeyID
+			q.cryptoKeys[i] = key
+			return key, nil
+		}
+	}
+
+	return database.CryptoKey{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateCustomRole(_ context.Context, arg database.UpdateCustomRoleParams) (database.CustomRole, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -9269,12 +10775,14 @@ func (q *FakeQuerier) UpdateOAuth2ProviderAppByID(_ context.Context, arg databas
 	for index, app := range q.oauth2ProviderApps {
 		if app.ID == arg.ID {
 			newApp := database.OAuth2ProviderApp{
-				ID:          arg.ID,
-				CreatedAt:   app.CreatedAt,
-				UpdatedAt:   arg.UpdatedAt,
-				Name:        arg.Name,
-				Icon:        arg.Icon,
-				CallbackURL: arg.CallbackURL,
+				ID:                      arg.ID,
+				CreatedAt:               app.CreatedAt,
+				UpdatedAt:               arg.UpdatedAt,
+				Name:                    arg.Name,
+				Icon:                    arg.Icon,
+				CallbackURL:             arg.CallbackURL,
+				PublicClient:            arg.PublicClient,
+				ClientCredentialsUserID: arg.ClientCredentialsUserID,
 			}
 			q.oauth2ProviderApps[index] = newApp
 			return newApp, nil
@@ -9307,7 +10815,46 @@ func (q *FakeQuerier) UpdateOAuth2ProviderAppSecretByID(_ context.Context, arg d
 			return newSecret, nil
 		}
 	}
-	return database.OAuth2ProviderAppSecret{}, sql.ErrNoRows
+	return database.OAuth2ProviderAppSecret{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) UpdateOAuth2ProviderDeviceCodeLastPolledAtByID(_ context.Context, arg database.UpdateOAuth2ProviderDeviceCodeLastPolledAtByIDParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, code := range q.oauth2ProviderDeviceCodes {
+		if code.ID == arg.ID {
+			code.LastPolledAt = arg.LastPolledAt
+			q.oauth2ProviderDeviceCodes[index] = code
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *FakeQuerier) UpdateOAuth2ProviderDeviceCodeStatusByID(_ context.Context, arg database.UpdateOAuth2ProviderDeviceCodeStatusByIDParams) (database.OAuth2ProviderDeviceCode, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.OAuth2ProviderDeviceCode{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, code := range q.oauth2ProviderDeviceCodes {
+		if code.ID == arg.ID && code.Status == database.OAuth2ProviderDeviceCodeStatusPending {
+			code.Status = arg.Status
+			code.UserID = arg.UserID
+			q.oauth2ProviderDeviceCodes[index] = code
+			return code, nil
+		}
+	}
+	return database.OAuth2ProviderDeviceCode{}, sql.ErrNoRows
 }
 
 func (q *FakeQuerier) UpdateOrganization(_ context.Context, arg database.UpdateOrganizationParams) (database.Organization, error) {
@@ -9454,6 +11001,31 @@ func (q *FakeQuerier) UpdateReplica(_ context.Context, arg database.UpdateReplic
 	return database.Replica{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateSecretByID(_ context.Context, arg database.UpdateSecretByIDParams) (database.Secret, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.Secret{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, secret := range q.secrets {
+		if secret.ID != arg.ID {
+			continue
+		}
+		secret.UpdatedAt = arg.UpdatedAt
+		secret.Description = arg.Description
+		secret.Value = arg.Value
+		secret.ValueKeyID = arg.ValueKeyID
+		secret.EnvName = arg.EnvName
+		secret.FilePath = arg.FilePath
+		q.secrets[i] = secret
+		return secret, nil
+	}
+	return database.Secret{}, sql.ErrNoRows
+}
+
 func (*FakeQuerier) UpdateTailnetPeerStatusByCoordinator(context.Context, database.UpdateTailnetPeerStatusByCoordinatorParams) error {
 	return ErrUnimplemented
 }
@@ -9559,6 +11131,11 @@ func (q *FakeQuerier) UpdateTemplateMetaByID(_ context.Context, arg database.Upd
 		tpl.GroupACL = arg.GroupACL
 		tpl.AllowUserCancelWorkspaceJobs = arg.AllowUserCancelWorkspaceJobs
 		tpl.MaxPortSharingLevel = arg.MaxPortSharingLevel
+		tpl.SessionRecordingEnabled = arg.SessionRecordingEnabled
+		tpl.RequireBuildApproval = arg.RequireBuildApproval
+		tpl.BuildApprovalParameters = arg.BuildApprovalParameters
+		tpl.BuildApprovalGroupID = arg.BuildApprovalGroupID
+		tpl.BuildApprovalTimeout = arg.BuildApprovalTimeout
 		q.templates[idx] = tpl
 		return nil
 	}
@@ -9566,6 +11143,51 @@ func (q *FakeQuerier) UpdateTemplateMetaByID(_ context.Context, arg database.Upd
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateTemplateRolloutByID(_ context.Context, arg database.UpdateTemplateRolloutByIDParams) (database.TemplateRollout, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.TemplateRollout{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, rollout := range q.templateRollouts {
+		if rollout.ID != arg.ID || rollout.Status != database.TemplateRolloutStatusInProgress {
+			continue
+		}
+		rollout.Percent = arg.Percent
+		rollout.GroupID = arg.GroupID
+		rollout.UpdatedAt = arg.UpdatedAt
+		q.templateRollouts[i] = rollout
+		return rollout, nil
+	}
+	return database.TemplateRollout{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) UpdateTemplateRolloutStatusByID(_ context.Context, arg database.UpdateTemplateRolloutStatusByIDParams) (database.TemplateRollout, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.TemplateRollout{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, rollout := range q.templateRollouts {
+		if rollout.ID != arg.ID || rollout.Status != database.TemplateRolloutStatusInProgress {
+			continue
+		}
+		rollout.Status = arg.Status
+		rollout.StatusReason = arg.StatusReason
+		rollout.UpdatedAt = arg.UpdatedAt
+		rollout.CompletedAt = sql.NullTime{Time: arg.UpdatedAt, Valid: true}
+		q.templateRollouts[i] = rollout
+		return rollout, nil
+	}
+	return database.TemplateRollout{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateTemplateScheduleByID(_ context.Context, arg database.UpdateTemplateScheduleByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -9871,6 +11493,63 @@ func (q *FakeQuerier) UpdateUserLoginType(_ context.Context, arg database.Update
 	return database.User{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateUserNotificationMethodPreferences(_ context.Context, arg database.UpdateUserNotificationMethodPreferencesParams) (int64, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return 0, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	var upserted int64
+	for i := range arg.NotificationTemplateIds {
+		var (
+			found      bool
+			templateID = arg.NotificationTemplateIds[i]
+			method     database.NullNotificationMethod
+		)
+		if arg.Methods[i] != "" {
+			method = database.NullNotificationMethod{
+				NotificationMethod: database.NotificationMethod(arg.Methods[i]),
+				Valid:              true,
+			}
+		}
+
+		for j, np := range q.notificationPreferences {
+			if np.UserID != arg.UserID {
+				continue
+			}
+
+			if np.NotificationTemplateID != templateID {
+				continue
+			}
+
+			np.Method = method
+			np.UpdatedAt = dbtime.Now()
+			q.notificationPreferences[j] = np
+
+			upserted++
+			found = true
+			break
+		}
+
+		if !found {
+			np := database.NotificationPreference{
+				UserID:                 arg.UserID,
+				NotificationTemplateID: templateID,
+				Method:                 method,
+				CreatedAt:              dbtime.Now(),
+				UpdatedAt:              dbtime.Now(),
+			}
+			q.notificationPreferences = append(q.notificationPreferences, np)
+			upserted++
+		}
+	}
+
+	return upserted, nil
+}
+
 func (q *FakeQuerier) UpdateUserNotificationPreferences(_ context.Context, arg database.UpdateUserNotificationPreferencesParams) (int64, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -10233,6 +11912,33 @@ func (q *FakeQuerier) UpdateWorkspaceAutostart(_ context.Context, arg database.U
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWorkspaceBuildApprovalByBuildID(_ context.Context, arg database.UpdateWorkspaceBuildApprovalByBuildIDParams) (database.WorkspaceBuildApproval, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceBuildApproval{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, approval := range q.workspaceBuildApprovals {
+		if approval.WorkspaceBuildID != arg.WorkspaceBuildID {
+			continue
+		}
+		if approval.Status != database.WorkspaceBuildApprovalStatusPending {
+			return database.WorkspaceBuildApproval{}, sql.ErrNoRows
+		}
+		approval.UpdatedAt = arg.UpdatedAt
+		approval.Status = arg.Status
+		approval.ReviewerID = arg.ReviewerID
+		approval.ReviewedAt = sql.NullTime{Time: arg.UpdatedAt, Valid: true}
+		approval.Reason = arg.Reason
+		q.workspaceBuildApprovals[i] = approval
+		return approval, nil
+	}
+	return database.WorkspaceBuildApproval{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateWorkspaceBuildCostByID(_ context.Context, arg database.UpdateWorkspaceBuildCostByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -10438,6 +12144,71 @@ func (q *FakeQuerier) UpdateWorkspaceProxyDeleted(_ context.Context, arg databas
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWorkspaceScheduledActionRunByID(_ context.Context, arg database.UpdateWorkspaceScheduledActionRunByIDParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, action := range q.workspaceScheduledActions {
+		if action.ID != arg.ID {
+			continue
+		}
+		action.UpdatedAt = arg.UpdatedAt
+		action.NextRunAt = arg.NextRunAt
+		action.LastRunAt = arg.LastRunAt
+		q.workspaceScheduledActions[i] = action
+		return nil
+	}
+	return nil
+}
+
+func (q *FakeQuerier) UpdateWorkspaceSessionRecordingByID(_ context.Context, arg database.UpdateWorkspaceSessionRecordingByIDParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, recording := range q.workspaceSessionRecordings {
+		if recording.ID != arg.ID {
+			continue
+		}
+		recording.UpdatedAt = arg.UpdatedAt
+		recording.Size += arg.SizeDelta
+		recording.EndedAt = arg.EndedAt
+		q.workspaceSessionRecordings[i] = recording
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
+func (q *FakeQuerier) UpdateWorkspaceSnapshotArtifactsByID(_ context.Context, arg database.UpdateWorkspaceSnapshotArtifactsByIDParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, snapshot := range q.workspaceSnapshots {
+		if snapshot.ID != arg.ID {
+			continue
+		}
+		snapshot.Artifacts = arg.Artifacts
+		snapshot.UpdatedAt = arg.UpdatedAt
+		q.workspaceSnapshots[i] = snapshot
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateWorkspaceTTL(_ context.Context, arg database.UpdateWorkspaceTTLParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -10550,6 +12321,40 @@ func (q *FakeQuerier) UpsertCoordinatorResumeTokenSigningKey(_ context.Context,
 	return nil
 }
 
+func (q *FakeQuerier) UpsertCostBudget(_ context.Context, arg database.UpsertCostBudgetParams) (database.CostBudget, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.CostBudget{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, budget := range q.costBudgets {
+		if budget.GroupID == arg.GroupID {
+			budget.UpdatedAt = arg.UpdatedAt
+			budget.MonthlyLimit = arg.MonthlyLimit
+			budget.NotifyThresholdPercent = arg.NotifyThresholdPercent
+			budget.StopThresholdPercent = arg.StopThresholdPercent
+			q.costBudgets[i] = budget
+			return budget, nil
+		}
+	}
+
+	//nolint:gosimple
+	budget := database.CostBudget{
+		GroupID:                arg.GroupID,
+		OrganizationID:         arg.OrganizationID,
+		CreatedAt:              arg.CreatedAt,
+		UpdatedAt:              arg.UpdatedAt,
+		MonthlyLimit:           arg.MonthlyLimit,
+		NotifyThresholdPercent: arg.NotifyThresholdPercent,
+		StopThresholdPercent:   arg.StopThresholdPercent,
+	}
+	q.costBudgets = append(q.costBudgets, budget)
+	return budget, nil
+}
+
 func (q *FakeQuerier) UpsertDefaultProxy(_ context.Context, arg database.UpsertDefaultProxyParams) error {
 	q.defaultProxyDisplayName = arg.DisplayName
 	q.defaultProxyIconURL = arg.IconUrl
@@ -10613,6 +12418,72 @@ func (q *FakeQuerier) UpsertLogoURL(_ context.Context, data string) error {
 	return nil
 }
 
+func (q *FakeQuerier) UpsertNetworkConnectionReports(_ context.Context, arg database.UpsertNetworkConnectionReportsParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i := range arg.ClientID {
+		report := database.NetworkConnectionReport{
+			ClientID:     arg.ClientID[i],
+			RemoteNodeID: arg.RemoteNodeID[i],
+			ClientType:   arg.ClientType[i],
+			HomeRegionID: arg.HomeRegionID[i],
+			Direct:       arg.Direct[i],
+			ReportedAt:   arg.ReportedAt[i],
+		}
+		idx := slices.IndexFunc(q.networkConnectionReports, func(r database.NetworkConnectionReport) bool {
+			return r.ClientID == report.ClientID && r.RemoteNodeID == report.RemoteNodeID
+		})
+		if idx < 0 {
+			q.networkConnectionReports = append(q.networkConnectionReports, report)
+			continue
+		}
+		if q.networkConnectionReports[idx].ReportedAt.After(report.ReportedAt) {
+			continue
+		}
+		q.networkConnectionReports[idx] = report
+	}
+	return nil
+}
+
+func (q *FakeQuerier) UpsertNetworkLatencyReports(_ context.Context, arg database.UpsertNetworkLatencyReportsParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i := range arg.ClientID {
+		report := database.NetworkLatencyReport{
+			ClientID:   arg.ClientID[i],
+			ClientType: arg.ClientType[i],
+			RegionID:   arg.RegionID[i],
+			LatencyMS:  arg.LatencyMS[i],
+			Preferred:  arg.Preferred[i],
+			ReportedAt: arg.ReportedAt[i],
+		}
+		idx := slices.IndexFunc(q.networkLatencyReports, func(r database.NetworkLatencyReport) bool {
+			return r.ClientID == report.ClientID && r.RegionID == report.RegionID
+		})
+		if idx < 0 {
+			q.networkLatencyReports = append(q.networkLatencyReports, report)
+			continue
+		}
+		if q.networkLatencyReports[idx].ReportedAt.After(report.ReportedAt) {
+			continue
+		}
+		q.networkLatencyReports[idx] = report
+	}
+	return nil
+}
+
 func (q *FakeQuerier) UpsertNotificationReportGeneratorLog(_ context.Context, arg database.UpsertNotificationReportGeneratorLogParams) error {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -11359,6 +13230,137 @@ func (q *FakeQuerier) UpsertWorkspaceAgentPortShare(_ context.Context, arg datab
 	return psl, nil
 }
 
+func (q *FakeQuerier) UpsertWorkspaceAgentProcessGroups(_ context.Context, arg database.UpsertWorkspaceAgentProcessGroupsParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, name := range arg.Name {
+		group := database.WorkspaceAgentProcessGroup{
+			WorkspaceAgentID: arg.WorkspaceAgentID,
+			Name:             name,
+			CPUUsageUsec:     arg.CPUUsageUsec[i],
+			CPUCores:         arg.CPUCores[i],
+			CPULimitCores:    arg.CPULimitCores[i],
+			MemoryBytes:      arg.MemoryBytes[i],
+			MemoryLimitBytes: arg.MemoryLimitBytes[i],
+			OOMKills:         arg.OOMKills[i],
+			UpdatedAt:        arg.UpdatedAt,
+		}
+		idx := slices.IndexFunc(q.workspaceAgentProcessGroups, func(g database.WorkspaceAgentProcessGroup) bool {
+			return g.WorkspaceAgentID == arg.WorkspaceAgentID && g.Name == name
+		})
+		if idx >= 0 {
+			q.workspaceAgentProcessGroups[idx] = group
+			continue
+		}
+		q.workspaceAgentProcessGroups = append(q.workspaceAgentProcessGroups, group)
+	}
+	return nil
+}
+
+func (q *FakeQuerier) UpsertWorkspaceCosts(_ context.Context) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	now := time.Now()
+	latestStart := now.Add(-24 * time.Hour)
+	if len(q.workspaceCosts) > 0 {
+		latestStart = time.Time{}
+		for _, wc := range q.workspaceCosts {
+			if wc.BucketStart.After(latestStart) {
+				latestStart = wc.BucketStart
+			}
+		}
+		latestStart = latestStart.Add(-time.Hour)
+	}
+
+	type buildCost struct {
+		workspaceID uuid.UUID
+		buildNumber int32
+		start       time.Time
+		end         time.Time
+		hourlyCost  float64
+	}
+	var builds []buildCost
+	for _, build := range q.workspaceBuilds {
+		job, err := q.getProvisionerJobByIDNoLock(context.Background(), build.JobID)
+		if err != nil || job.JobStatus != database.ProvisionerJobStatusSucceeded {
+			continue
+		}
+		bc := buildCost{
+			workspaceID: build.WorkspaceID,
+			buildNumber: build.BuildNumber,
+			start:       job.CompletedAt.Time,
+		}
+		for _, resource := range q.workspaceResources {
+			if resource.JobID == build.JobID {
+				bc.hourlyCost += resource.HourlyCost
+			}
+		}
+		builds = append(builds, bc)
+	}
+	// A build accrues cost until the next successful build of the workspace
+	// completed.
+	slices.SortFunc(builds, func(a, b buildCost) int {
+		if a.workspaceID != b.workspaceID {
+			return slice.Ascending(a.workspaceID.String(), b.workspaceID.String())
+		}
+		return slice.Ascending(a.buildNumber, b.buildNumber)
+	})
+	for i := range builds {
+		builds[i].end = now
+		if i+1 < len(builds) && builds[i+1].workspaceID == builds[i].workspaceID {
+			builds[i].end = builds[i+1].start
+		}
+	}
+
+	for bucket := latestStart.Truncate(time.Hour); !bucket.After(now.Truncate(time.Hour)); bucket = bucket.Add(time.Hour) {
+		bucketEnd := bucket.Add(time.Hour)
+		costByWorkspace := make(map[uuid.UUID]float64)
+		for _, bc := range builds {
+			if bc.hourlyCost <= 0 || !bc.start.Before(bucketEnd) || !bc.end.After(bucket) {
+				continue
+			}
+			start, end := bc.start, bc.end
+			if start.Before(bucket) {
+				start = bucket
+			}
+			if end.After(bucketEnd) {
+				end = bucketEnd
+			}
+			costByWorkspace[bc.workspaceID] += bc.hourlyCost * end.Sub(start).Hours()
+		}
+		for workspaceID, cost := range costByWorkspace {
+			workspace, err := q.getWorkspaceByIDNoLock(context.Background(), workspaceID)
+			if err != nil {
+				continue
+			}
+			row := database.WorkspaceCost{
+				BucketStart:    bucket,
+				WorkspaceID:    workspaceID,
+				OwnerID:        workspace.OwnerID,
+				TemplateID:     workspace.TemplateID,
+				OrganizationID: workspace.OrganizationID,
+				Cost:           cost,
+			}
+			idx := slices.IndexFunc(q.workspaceCosts, func(wc database.WorkspaceCost) bool {
+				return wc.BucketStart.Equal(bucket) && wc.WorkspaceID == workspaceID
+			})
+			if idx >= 0 {
+				q.workspaceCosts[idx].Cost = cost
+				continue
+			}
+			q.workspaceCosts = append(q.workspaceCosts, row)
+		}
+	}
+	return nil
+}
+
 func (q *FakeQuerier) GetAuthorizedTemplates(ctx context.Context, arg database.GetTemplatesWithFilterParams, prepared rbac.PreparedAuthorized) ([]database.Template, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return nil, err
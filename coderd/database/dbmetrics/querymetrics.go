@@ -168,6 +168,13 @@ func (m queryMetricsStore) CleanTailnetTunnels(ctx context.Context) error {
 	return r0
 }
 
+func (m queryMetricsStore) ClearOldWorkspaceBuildProvisionerStates(ctx context.Context, arg database.ClearOldWorkspaceBuildProvisionerStatesParams) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.ClearOldWorkspaceBuildProvisionerStates(ctx, arg)
+	m.queryLatencies.WithLabelValues("ClearOldWorkspaceBuildProvisionerStates").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) CustomRoles(ctx context.Context, arg database.CustomRolesParams) ([]database.CustomRole, error) {
 	start := time.Now()
 	r0, r1 := m.s.CustomRoles(ctx, arg)
@@ -217,6 +224,13 @@ func (m queryMetricsStore) DeleteCoordinator(ctx context.Context, id uuid.UUID)
 	return r0
 }
 
+func (m queryMetricsStore) DeleteCostBudgetByGroupID(ctx context.Context, groupID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteCostBudgetByGroupID(ctx, groupID)
+	m.queryLatencies.WithLabelValues("DeleteCostBudgetByGroupID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) DeleteCryptoKey(ctx context.Context, arg database.DeleteCryptoKeyParams) (database.CryptoKey, error) {
 	start := time.Now()
 	r0, r1 := m.s.DeleteCryptoKey(ctx, arg)
@@ -301,9 +315,30 @@ func (m queryMetricsStore) DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx conte
 	return r0
 }
 
-func (m queryMetricsStore) DeleteOldNotificationMessages(ctx context.Context) error {
+func (m queryMetricsStore) DeleteOAuth2ProviderDeviceCodeByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteOAuth2ProviderDeviceCodeByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteOAuth2ProviderDeviceCodeByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) DeleteOldAuditLogs(ctx context.Context, arg database.DeleteOldAuditLogsParams) (int64, error) {
 	start := time.Now()
-	r0 := m.s.DeleteOldNotificationMessages(ctx)
+	r0, r1 := m.s.DeleteOldAuditLogs(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteOldAuditLogs").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) DeleteOldNetworkReports(ctx context.Context) error {
+	start := time.Now()
+	r0 := m.s.DeleteOldNetworkReports(ctx)
+	m.queryLatencies.WithLabelValues("DeleteOldNetworkReports").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) DeleteOldNotificationMessages(ctx context.Context, beforeTime time.Time) error {
+	start := time.Now()
+	r0 := m.s.DeleteOldNotificationMessages(ctx, beforeTime)
 	m.queryLatencies.WithLabelValues("DeleteOldNotificationMessages").Observe(time.Since(start).Seconds())
 	return r0
 }
@@ -315,6 +350,13 @@ func (m queryMetricsStore) DeleteOldProvisionerDaemons(ctx context.Context) erro
 	return r0
 }
 
+func (m queryMetricsStore) DeleteOldProvisionerJobLogs(ctx context.Context, arg database.DeleteOldProvisionerJobLogsParams) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.DeleteOldProvisionerJobLogs(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteOldProvisionerJobLogs").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) DeleteOldWorkspaceAgentLogs(ctx context.Context, arg time.Time) error {
 	start := time.Now()
 	r0 := m.s.DeleteOldWorkspaceAgentLogs(ctx, arg)
@@ -322,9 +364,9 @@ func (m queryMetricsStore) DeleteOldWorkspaceAgentLogs(ctx context.Context, arg
 	return r0
 }
 
-func (m queryMetricsStore) DeleteOldWorkspaceAgentStats(ctx context.Context) error {
+func (m queryMetricsStore) DeleteOldWorkspaceAgentStats(ctx context.Context, beforeTime time.Time) error {
 	start := time.Now()
-	err := m.s.DeleteOldWorkspaceAgentStats(ctx)
+	err := m.s.DeleteOldWorkspaceAgentStats(ctx, beforeTime)
 	m.queryLatencies.WithLabelValues("DeleteOldWorkspaceAgentStats").Observe(time.Since(start).Seconds())
 	return err
 }
@@ -364,6 +406,13 @@ func (m queryMetricsStore) DeleteRuntimeConfig(ctx context.Context, key string)
 	return r0
 }
 
+func (m queryMetricsStore) DeleteSecretByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteSecretByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteSecretByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) DeleteTailnetAgent(ctx context.Context, arg database.DeleteTailnetAgentParams) (database.DeleteTailnetAgentRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.DeleteTailnetAgent(ctx, arg)
@@ -413,6 +462,13 @@ func (m queryMetricsStore) DeleteWorkspaceAgentPortSharesByTemplate(ctx context.
 	return r0
 }
 
+func (m queryMetricsStore) DeleteWorkspaceScheduledActionByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteWorkspaceScheduledActionByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteWorkspaceScheduledActionByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) DisableForeignKeysAndTriggers(ctx context.Context) error {
 	start := time.Now()
 	r0 := m.s.DisableForeignKeysAndTriggers(ctx)
@@ -476,6 +532,13 @@ func (m queryMetricsStore) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed
 	return apiKeys, err
 }
 
+func (m queryMetricsStore) GetActiveTemplateRolloutByTemplateID(ctx context.Context, templateID uuid.UUID) (database.TemplateRollout, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetActiveTemplateRolloutByTemplateID(ctx, templateID)
+	m.queryLatencies.WithLabelValues("GetActiveTemplateRolloutByTemplateID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetActiveUserCount(ctx context.Context) (int64, error) {
 	start := time.Now()
 	count, err := m.s.GetActiveUserCount(ctx)
@@ -560,6 +623,34 @@ func (m queryMetricsStore) GetCoordinatorResumeTokenSigningKey(ctx context.Conte
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetCostBudgetByGroupID(ctx context.Context, groupID uuid.UUID) (database.CostBudget, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetCostBudgetByGroupID(ctx, groupID)
+	m.queryLatencies.WithLabelValues("GetCostBudgetByGroupID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetCostBudgetMemberIDs(ctx context.Context, groupID uuid.UUID) ([]uuid.UUID, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetCostBudgetMemberIDs(ctx, groupID)
+	m.queryLatencies.WithLabelValues("GetCostBudgetMemberIDs").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetCostBudgetWorkspaceIDs(ctx context.Context, groupID uuid.UUID) ([]uuid.UUID, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetCostBudgetWorkspaceIDs(ctx, groupID)
+	m.queryLatencies.WithLabelValues("GetCostBudgetWorkspaceIDs").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetCostBudgetsWithCost(ctx context.Context, since time.Time) ([]database.GetCostBudgetsWithCostRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetCostBudgetsWithCost(ctx, since)
+	m.queryLatencies.WithLabelValues("GetCostBudgetsWithCost").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetCryptoKeyByFeatureAndSequence(ctx context.Context, arg database.GetCryptoKeyByFeatureAndSequenceParams) (database.CryptoKey, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetCryptoKeyByFeatureAndSequence(ctx, arg)
@@ -819,6 +910,13 @@ func (m queryMetricsStore) GetLogoURL(ctx context.Context) (string, error) {
 	return url, err
 }
 
+func (m queryMetricsStore) GetNetworkRegionInsights(ctx context.Context, arg database.GetNetworkRegionInsightsParams) ([]database.GetNetworkRegionInsightsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetNetworkRegionInsights(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetNetworkRegionInsights").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetNotificationMessagesByStatus(ctx context.Context, arg database.GetNotificationMessagesByStatusParams) ([]database.NotificationMessage, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetNotificationMessagesByStatus(ctx, arg)
@@ -917,6 +1015,20 @@ func (m queryMetricsStore) GetOAuth2ProviderAppsByUserID(ctx context.Context, us
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetOAuth2ProviderDeviceCodeByPrefix(ctx context.Context, secretPrefix []byte) (database.OAuth2ProviderDeviceCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderDeviceCodeByPrefix(ctx, secretPrefix)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderDeviceCodeByPrefix").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetOAuth2ProviderDeviceCodeByUserCode(ctx context.Context, userCode string) (database.OAuth2ProviderDeviceCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderDeviceCodeByUserCode(ctx, userCode)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderDeviceCodeByUserCode").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetOAuthSigningKey(ctx context.Context) (string, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetOAuthSigningKey(ctx)
@@ -966,6 +1078,20 @@ func (m queryMetricsStore) GetParameterSchemasByJobID(ctx context.Context, jobID
 	return schemas, err
 }
 
+func (m queryMetricsStore) GetPendingProvisionerJobTags(ctx context.Context, arg database.GetPendingProvisionerJobTagsParams) ([]database.GetPendingProvisionerJobTagsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetPendingProvisionerJobTags(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetPendingProvisionerJobTags").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetPendingWorkspaceBuildApprovalsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.GetPendingWorkspaceBuildApprovalsByTemplateIDRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetPendingWorkspaceBuildApprovalsByTemplateID(ctx, templateID)
+	m.queryLatencies.WithLabelValues("GetPendingWorkspaceBuildApprovalsByTemplateID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetPreviousTemplateVersion(ctx context.Context, arg database.GetPreviousTemplateVersionParams) (database.TemplateVersion, error) {
 	start := time.Now()
 	version, err := m.s.GetPreviousTemplateVersion(ctx, arg)
@@ -1001,6 +1127,13 @@ func (m queryMetricsStore) GetProvisionerJobByID(ctx context.Context, id uuid.UU
 	return job, err
 }
 
+func (m queryMetricsStore) GetProvisionerJobResourceChangesByJobID(ctx context.Context, jobID uuid.UUID) ([]database.ProvisionerJobResourceChange, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetProvisionerJobResourceChangesByJobID(ctx, jobID)
+	m.queryLatencies.WithLabelValues("GetProvisionerJobResourceChangesByJobID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetProvisionerJobTimingsByJobID(ctx context.Context, jobID uuid.UUID) ([]database.ProvisionerJobTiming, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetProvisionerJobTimingsByJobID(ctx, jobID)
@@ -1057,6 +1190,13 @@ func (m queryMetricsStore) GetProvisionerLogsAfterID(ctx context.Context, arg da
 	return logs, err
 }
 
+func (m queryMetricsStore) GetPurgeableRowCounts(ctx context.Context, arg database.GetPurgeableRowCountsParams) (database.GetPurgeableRowCountsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetPurgeableRowCounts(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetPurgeableRowCounts").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetQuotaAllowanceForUser(ctx context.Context, userID database.GetQuotaAllowanceForUserParams) (int64, error) {
 	start := time.Now()
 	allowance, err := m.s.GetQuotaAllowanceForUser(ctx, userID)
@@ -1092,6 +1232,41 @@ func (m queryMetricsStore) GetRuntimeConfig(ctx context.Context, key string) (st
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetSecretByID(ctx context.Context, id uuid.UUID) (database.Secret, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetSecretByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetSecretByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetSecretByScopeAndName(ctx context.Context, arg database.GetSecretByScopeAndNameParams) (database.Secret, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetSecretByScopeAndName(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetSecretByScopeAndName").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetSecrets(ctx context.Context) ([]database.Secret, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetSecrets(ctx)
+	m.queryLatencies.WithLabelValues("GetSecrets").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetSecretsByScope(ctx context.Context, arg database.GetSecretsByScopeParams) ([]database.Secret, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetSecretsByScope(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetSecretsByScope").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetSecretsForWorkspace(ctx context.Context, arg database.GetSecretsForWorkspaceParams) ([]database.Secret, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetSecretsForWorkspace(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetSecretsForWorkspace").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetTailnetAgents(ctx context.Context, id uuid.UUID) ([]database.TailnetAgent, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTailnetAgents(ctx, id)
@@ -1197,6 +1372,27 @@ func (m queryMetricsStore) GetTemplateParameterInsights(ctx context.Context, arg
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetTemplateRolloutBuildCounts(ctx context.Context, arg database.GetTemplateRolloutBuildCountsParams) (database.GetTemplateRolloutBuildCountsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateRolloutBuildCounts(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetTemplateRolloutBuildCounts").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplateRolloutByID(ctx context.Context, id uuid.UUID) (database.TemplateRollout, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateRolloutByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetTemplateRolloutByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplateRolloutsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplateRollout, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateRolloutsByTemplateID(ctx, templateID)
+	m.queryLatencies.WithLabelValues("GetTemplateRolloutsByTemplateID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetTemplateUsageStats(ctx context.Context, arg database.GetTemplateUsageStatsParams) ([]database.TemplateUsageStat, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTemplateUsageStats(ctx, arg)
@@ -1435,6 +1631,13 @@ func (m queryMetricsStore) GetWorkspaceAgentPortShare(ctx context.Context, arg d
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetWorkspaceAgentProcessGroups(ctx context.Context, workspaceAgentID uuid.UUID) ([]database.WorkspaceAgentProcessGroup, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentProcessGroups(ctx, workspaceAgentID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentProcessGroups").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceAgentScriptTimingsByBuildID(ctx context.Context, id uuid.UUID) ([]database.GetWorkspaceAgentScriptTimingsByBuildIDRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentScriptTimingsByBuildID(ctx, id)
@@ -1526,6 +1729,13 @@ func (m queryMetricsStore) GetWorkspaceAppsCreatedAfter(ctx context.Context, cre
 	return apps, err
 }
 
+func (m queryMetricsStore) GetWorkspaceBuildApprovalByBuildID(ctx context.Context, workspaceBuildID uuid.UUID) (database.WorkspaceBuildApproval, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceBuildApprovalByBuildID(ctx, workspaceBuildID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceBuildApprovalByBuildID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceBuildByID(ctx context.Context, id uuid.UUID) (database.WorkspaceBuild, error) {
 	start := time.Now()
 	build, err := m.s.GetWorkspaceBuildByID(ctx, id)
@@ -1603,6 +1813,34 @@ func (m queryMetricsStore) GetWorkspaceByWorkspaceAppID(ctx context.Context, wor
 	return workspace, err
 }
 
+func (m queryMetricsStore) GetWorkspaceCostInsightsByGroup(ctx context.Context, arg database.GetWorkspaceCostInsightsByGroupParams) ([]database.GetWorkspaceCostInsightsByGroupRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceCostInsightsByGroup(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceCostInsightsByGroup").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceCostInsightsByInterval(ctx context.Context, arg database.GetWorkspaceCostInsightsByIntervalParams) ([]database.GetWorkspaceCostInsightsByIntervalRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceCostInsightsByInterval(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceCostInsightsByInterval").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceCostInsightsByTemplate(ctx context.Context, arg database.GetWorkspaceCostInsightsByTemplateParams) ([]database.GetWorkspaceCostInsightsByTemplateRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceCostInsightsByTemplate(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceCostInsightsByTemplate").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceCostInsightsByUser(ctx context.Context, arg database.GetWorkspaceCostInsightsByUserParams) ([]database.GetWorkspaceCostInsightsByUserRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceCostInsightsByUser(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceCostInsightsByUser").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceModulesByJobID(ctx context.Context, jobID uuid.UUID) ([]database.WorkspaceModule, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceModulesByJobID(ctx, jobID)
@@ -1687,6 +1925,62 @@ func (m queryMetricsStore) GetWorkspaceResourcesCreatedAfter(ctx context.Context
 	return resources, err
 }
 
+func (m queryMetricsStore) GetWorkspaceScheduledActionByID(ctx context.Context, id uuid.UUID) (database.WorkspaceScheduledAction, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceScheduledActionByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetWorkspaceScheduledActionByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceScheduledActionsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceScheduledAction, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceScheduledActionsByWorkspaceID(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceScheduledActionsByWorkspaceID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceScheduledActionsDue(ctx context.Context, now time.Time) ([]database.WorkspaceScheduledAction, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceScheduledActionsDue(ctx, now)
+	m.queryLatencies.WithLabelValues("GetWorkspaceScheduledActionsDue").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceSessionRecordingByID(ctx context.Context, id uuid.UUID) (database.WorkspaceSessionRecording, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceSessionRecordingByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetWorkspaceSessionRecordingByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceSessionRecordingChunksByRecordingID(ctx context.Context, arg database.GetWorkspaceSessionRecordingChunksByRecordingIDParams) ([]database.WorkspaceSessionRecordingChunk, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceSessionRecordingChunksByRecordingID(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceSessionRecordingChunksByRecordingID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceSessionRecordingsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceSessionRecording, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceSessionRecordingsByWorkspaceID(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceSessionRecordingsByWorkspaceID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceSnapshotByID(ctx context.Context, id uuid.UUID) (database.WorkspaceSnapshot, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceSnapshotByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetWorkspaceSnapshotByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceSnapshotsByOwnerAndTemplate(ctx context.Context, arg database.GetWorkspaceSnapshotsByOwnerAndTemplateParams) ([]database.WorkspaceSnapshot, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceSnapshotsByOwnerAndTemplate(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceSnapshotsByOwnerAndTemplate").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx context.Context, templateIds []uuid.UUID) ([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx, templateIds)
@@ -1855,6 +2149,13 @@ func (m queryMetricsStore) InsertOAuth2ProviderAppToken(ctx context.Context, arg
 	return r0, r1
 }
 
+func (m queryMetricsStore) InsertOAuth2ProviderDeviceCode(ctx context.Context, arg database.InsertOAuth2ProviderDeviceCodeParams) (database.OAuth2ProviderDeviceCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertOAuth2ProviderDeviceCode(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertOAuth2ProviderDeviceCode").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertOrganization(ctx context.Context, arg database.InsertOrganizationParams) (database.Organization, error) {
 	start := time.Now()
 	organization, err := m.s.InsertOrganization(ctx, arg)
@@ -1883,6 +2184,13 @@ func (m queryMetricsStore) InsertProvisionerJobLogs(ctx context.Context, arg dat
 	return logs, err
 }
 
+func (m queryMetricsStore) InsertProvisionerJobResourceChanges(ctx context.Context, arg database.InsertProvisionerJobResourceChangesParams) ([]database.ProvisionerJobResourceChange, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertProvisionerJobResourceChanges(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertProvisionerJobResourceChanges").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertProvisionerJobTimings(ctx context.Context, arg database.InsertProvisionerJobTimingsParams) ([]database.ProvisionerJobTiming, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertProvisionerJobTimings(ctx, arg)
@@ -1904,6 +2212,13 @@ func (m queryMetricsStore) InsertReplica(ctx context.Context, arg database.Inser
 	return replica, err
 }
 
+func (m queryMetricsStore) InsertSecret(ctx context.Context, arg database.InsertSecretParams) (database.Secret, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertSecret(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertSecret").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertTemplate(ctx context.Context, arg database.InsertTemplateParams) error {
 	start := time.Now()
 	err := m.s.InsertTemplate(ctx, arg)
@@ -1911,6 +2226,13 @@ func (m queryMetricsStore) InsertTemplate(ctx context.Context, arg database.Inse
 	return err
 }
 
+func (m queryMetricsStore) InsertTemplateRollout(ctx context.Context, arg database.InsertTemplateRolloutParams) (database.TemplateRollout, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertTemplateRollout(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertTemplateRollout").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertTemplateVersion(ctx context.Context, arg database.InsertTemplateVersionParams) error {
 	start := time.Now()
 	err := m.s.InsertTemplateVersion(ctx, arg)
@@ -2044,6 +2366,13 @@ func (m queryMetricsStore) InsertWorkspaceBuild(ctx context.Context, arg databas
 	return err
 }
 
+func (m queryMetricsStore) InsertWorkspaceBuildApproval(ctx context.Context, arg database.InsertWorkspaceBuildApprovalParams) (database.WorkspaceBuildApproval, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceBuildApproval(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceBuildApproval").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertWorkspaceBuildParameters(ctx context.Context, arg database.InsertWorkspaceBuildParametersParams) error {
 	start := time.Now()
 	err := m.s.InsertWorkspaceBuildParameters(ctx, arg)
@@ -2079,6 +2408,34 @@ func (m queryMetricsStore) InsertWorkspaceResourceMetadata(ctx context.Context,
 	return metadata, err
 }
 
+func (m queryMetricsStore) InsertWorkspaceScheduledAction(ctx context.Context, arg database.InsertWorkspaceScheduledActionParams) (database.WorkspaceScheduledAction, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceScheduledAction(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceScheduledAction").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) InsertWorkspaceSessionRecording(ctx context.Context, arg database.InsertWorkspaceSessionRecordingParams) (database.WorkspaceSessionRecording, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceSessionRecording(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceSessionRecording").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) InsertWorkspaceSessionRecordingChunk(ctx context.Context, arg database.InsertWorkspaceSessionRecordingChunkParams) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceSessionRecordingChunk(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceSessionRecordingChunk").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) InsertWorkspaceSnapshot(ctx context.Context, arg database.InsertWorkspaceSnapshotParams) (database.WorkspaceSnapshot, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceSnapshot(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceSnapshot").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) ListProvisionerKeysByOrganization(ctx context.Context, organizationID uuid.UUID) ([]database.ProvisionerKey, error) {
 	start := time.Now()
 	r0, r1 := m.s.ListProvisionerKeysByOrganization(ctx, organizationID)
@@ -2135,6 +2492,13 @@ func (m queryMetricsStore) RegisterWorkspaceProxy(ctx context.Context, arg datab
 	return proxy, err
 }
 
+func (m queryMetricsStore) RejectExpiredWorkspaceBuildApprovals(ctx context.Context, arg database.RejectExpiredWorkspaceBuildApprovalsParams) ([]database.WorkspaceBuildApproval, error) {
+	start := time.Now()
+	r0, r1 := m.s.RejectExpiredWorkspaceBuildApprovals(ctx, arg)
+	m.queryLatencies.WithLabelValues("RejectExpiredWorkspaceBuildApprovals").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) RemoveUserFromAllGroups(ctx context.Context, userID uuid.UUID) error {
 	start := time.Now()
 	r0 := m.s.RemoveUserFromAllGroups(ctx, userID)
@@ -2184,6 +2548,20 @@ func (m queryMetricsStore) UpdateAPIKeyByID(ctx context.Context, arg database.Up
 	return err
 }
 
+func (m queryMetricsStore) UpdateCostBudgetNotifiedAtByGroupID(ctx context.Context, arg database.UpdateCostBudgetNotifiedAtByGroupIDParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateCostBudgetNotifiedAtByGroupID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateCostBudgetNotifiedAtByGroupID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) UpdateCostBudgetStoppedAtByGroupID(ctx context.Context, arg database.UpdateCostBudgetStoppedAtByGroupIDParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateCostBudgetStoppedAtByGroupID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateCostBudgetStoppedAtByGroupID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) UpdateCryptoKeyDeletesAt(ctx context.Context, arg database.UpdateCryptoKeyDeletesAtParams) (database.CryptoKey, error) {
 	start := time.Now()
 	key, err := m.s.UpdateCryptoKeyDeletesAt(ctx, arg)
@@ -2191,6 +2569,13 @@ func (m queryMetricsStore) UpdateCryptoKeyDeletesAt(ctx context.Context, arg dat
 	return key, err
 }
 
+func (m queryMetricsStore) UpdateCryptoKeySecret(ctx context.Context, arg database.UpdateCryptoKeySecretParams) (database.CryptoKey, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateCryptoKeySecret(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateCryptoKeySecret").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdateCustomRole(ctx context.Context, arg database.UpdateCustomRoleParams) (database.CustomRole, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpdateCustomRole(ctx, arg)
@@ -2261,6 +2646,20 @@ func (m queryMetricsStore) UpdateOAuth2ProviderAppSecretByID(ctx context.Context
 	return r0, r1
 }
 
+func (m queryMetricsStore) UpdateOAuth2ProviderDeviceCodeLastPolledAtByID(ctx context.Context, arg database.UpdateOAuth2ProviderDeviceCodeLastPolledAtByIDParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateOAuth2ProviderDeviceCodeLastPolledAtByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateOAuth2ProviderDeviceCodeLastPolledAtByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) UpdateOAuth2ProviderDeviceCodeStatusByID(ctx context.Context, arg database.UpdateOAuth2ProviderDeviceCodeStatusByIDParams) (database.OAuth2ProviderDeviceCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateOAuth2ProviderDeviceCodeStatusByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateOAuth2ProviderDeviceCodeStatusByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdateOrganization(ctx context.Context, arg database.UpdateOrganizationParams) (database.Organization, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpdateOrganization(ctx, arg)
@@ -2303,6 +2702,13 @@ func (m queryMetricsStore) UpdateReplica(ctx context.Context, arg database.Updat
 	return replica, err
 }
 
+func (m queryMetricsStore) UpdateSecretByID(ctx context.Context, arg database.UpdateSecretByIDParams) (database.Secret, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateSecretByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateSecretByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdateTailnetPeerStatusByCoordinator(ctx context.Context, arg database.UpdateTailnetPeerStatusByCoordinatorParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateTailnetPeerStatusByCoordinator(ctx, arg)
@@ -2345,6 +2751,20 @@ func (m queryMetricsStore) UpdateTemplateMetaByID(ctx context.Context, arg datab
 	return err
 }
 
+func (m queryMetricsStore) UpdateTemplateRolloutByID(ctx context.Context, arg database.UpdateTemplateRolloutByIDParams) (database.TemplateRollout, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateTemplateRolloutByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateTemplateRolloutByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpdateTemplateRolloutStatusByID(ctx context.Context, arg database.UpdateTemplateRolloutStatusByIDParams) (database.TemplateRollout, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateTemplateRolloutStatusByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateTemplateRolloutStatusByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdateTemplateScheduleByID(ctx context.Context, arg database.UpdateTemplateScheduleByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateTemplateScheduleByID(ctx, arg)
@@ -2443,6 +2863,13 @@ func (m queryMetricsStore) UpdateUserLoginType(ctx context.Context, arg database
 	return r0, r1
 }
 
+func (m queryMetricsStore) UpdateUserNotificationMethodPreferences(ctx context.Context, arg database.UpdateUserNotificationMethodPreferencesParams) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateUserNotificationMethodPreferences(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateUserNotificationMethodPreferences").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdateUserNotificationPreferences(ctx context.Context, arg database.UpdateUserNotificationPreferencesParams) (int64, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpdateUserNotificationPreferences(ctx, arg)
@@ -2541,6 +2968,13 @@ func (m queryMetricsStore) UpdateWorkspaceAutostart(ctx context.Context, arg dat
 	return err
 }
 
+func (m queryMetricsStore) UpdateWorkspaceBuildApprovalByBuildID(ctx context.Context, arg database.UpdateWorkspaceBuildApprovalByBuildIDParams) (database.WorkspaceBuildApproval, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateWorkspaceBuildApprovalByBuildID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceBuildApprovalByBuildID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdateWorkspaceBuildCostByID(ctx context.Context, arg database.UpdateWorkspaceBuildCostByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateWorkspaceBuildCostByID(ctx, arg)
@@ -2604,6 +3038,27 @@ func (m queryMetricsStore) UpdateWorkspaceProxyDeleted(ctx context.Context, arg
 	return r0
 }
 
+func (m queryMetricsStore) UpdateWorkspaceScheduledActionRunByID(ctx context.Context, arg database.UpdateWorkspaceScheduledActionRunByIDParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateWorkspaceScheduledActionRunByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceScheduledActionRunByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) UpdateWorkspaceSessionRecordingByID(ctx context.Context, arg database.UpdateWorkspaceSessionRecordingByIDParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateWorkspaceSessionRecordingByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceSessionRecordingByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) UpdateWorkspaceSnapshotArtifactsByID(ctx context.Context, arg database.UpdateWorkspaceSnapshotArtifactsByIDParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateWorkspaceSnapshotArtifactsByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceSnapshotArtifactsByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) UpdateWorkspaceTTL(ctx context.Context, arg database.UpdateWorkspaceTTLParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateWorkspaceTTL(ctx, arg)
@@ -2653,6 +3108,13 @@ func (m queryMetricsStore) UpsertCoordinatorResumeTokenSigningKey(ctx context.Co
 	return r0
 }
 
+func (m queryMetricsStore) UpsertCostBudget(ctx context.Context, arg database.UpsertCostBudgetParams) (database.CostBudget, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertCostBudget(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertCostBudget").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpsertDefaultProxy(ctx context.Context, arg database.UpsertDefaultProxyParams) error {
 	start := time.Now()
 	r0 := m.s.UpsertDefaultProxy(ctx, arg)
@@ -2688,6 +3150,20 @@ func (m queryMetricsStore) UpsertLogoURL(ctx context.Context, value string) erro
 	return r0
 }
 
+func (m queryMetricsStore) UpsertNetworkConnectionReports(ctx context.Context, arg database.UpsertNetworkConnectionReportsParams) error {
+	start := time.Now()
+	r0 := m.s.UpsertNetworkConnectionReports(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertNetworkConnectionReports").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) UpsertNetworkLatencyReports(ctx context.Context, arg database.UpsertNetworkLatencyReportsParams) error {
+	start := time.Now()
+	r0 := m.s.UpsertNetworkLatencyReports(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertNetworkLatencyReports").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) UpsertNotificationReportGeneratorLog(ctx context.Context, arg database.UpsertNotificationReportGeneratorLogParams) error {
 	start := time.Now()
 	r0 := m.s.UpsertNotificationReportGeneratorLog(ctx, arg)
@@ -2779,6 +3255,20 @@ func (m queryMetricsStore) UpsertWorkspaceAgentPortShare(ctx context.Context, ar
 	return r0, r1
 }
 
+func (m queryMetricsStore) UpsertWorkspaceAgentProcessGroups(ctx context.Context, arg database.UpsertWorkspaceAgentProcessGroupsParams) error {
+	start := time.Now()
+	r0 := m.s.UpsertWorkspaceAgentProcessGroups(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertWorkspaceAgentProcessGroups").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) UpsertWorkspaceCosts(ctx context.Context) error {
+	start := time.Now()
+	r0 := m.s.UpsertWorkspaceCosts(ctx)
+	m.queryLatencies.WithLabelValues("UpsertWorkspaceCosts").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) GetAuthorizedTemplates(ctx context.Context, arg database.GetTemplatesWithFilterParams, prepared rbac.PreparedAuthorized) ([]database.Template, error) {
 	start := time.Now()
 	templates, err := m.s.GetAuthorizedTemplates(ctx, arg, prepared)
@@ -231,6 +231,21 @@ func (mr *MockStoreMockRecorder) CleanTailnetTunnels(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CleanTailnetTunnels", reflect.TypeOf((*MockStore)(nil).CleanTailnetTunnels), arg0)
 }
 
+// ClearOldWorkspaceBuildProvisionerStates mocks base method.
+func (m *MockStore) ClearOldWorkspaceBuildProvisionerStates(arg0 context.Context, arg1 database.ClearOldWorkspaceBuildProvisionerStatesParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearOldWorkspaceBuildProvisionerStates", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClearOldWorkspaceBuildProvisionerStates indicates an expected call of ClearOldWorkspaceBuildProvisionerStates.
+func (mr *MockStoreMockRecorder) ClearOldWorkspaceBuildProvisionerStates(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearOldWorkspaceBuildProvisionerStates", reflect.TypeOf((*MockStore)(nil).ClearOldWorkspaceBuildProvisionerStates), arg0, arg1)
+}
+
 // CustomRoles mocks base method.
 func (m *MockStore) CustomRoles(arg0 context.Context, arg1 database.CustomRolesParams) ([]database.CustomRole, error) {
 	m.ctrl.T.Helper()
@@ -330,6 +345,20 @@ func (mr *MockStoreMockRecorder) DeleteCoordinator(arg0, arg1 any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCoordinator", reflect.TypeOf((*MockStore)(nil).DeleteCoordinator), arg0, arg1)
 }
 
+// DeleteCostBudgetByGroupID mocks base method.
+func (m *MockStore) DeleteCostBudgetByGroupID(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCostBudgetByGroupID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCostBudgetByGroupID indicates an expected call of DeleteCostBudgetByGroupID.
+func (mr *MockStoreMockRecorder) DeleteCostBudgetByGroupID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCostBudgetByGroupID", reflect.TypeOf((*MockStore)(nil).DeleteCostBudgetByGroupID), arg0, arg1)
+}
+
 // DeleteCryptoKey mocks base method.
 func (m *MockStore) DeleteCryptoKey(arg0 context.Context, arg1 database.DeleteCryptoKeyParams) (database.CryptoKey, error) {
 	m.ctrl.T.Helper()
@@ -500,18 +529,61 @@ func (mr *MockStoreMockRecorder) DeleteOAuth2ProviderAppTokensByAppAndUserID(arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOAuth2ProviderAppTokensByAppAndUserID", reflect.TypeOf((*MockStore)(nil).DeleteOAuth2ProviderAppTokensByAppAndUserID), arg0, arg1)
 }
 
+// DeleteOAuth2ProviderDeviceCodeByID mocks base method.
+func (m *MockStore) DeleteOAuth2ProviderDeviceCodeByID(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOAuth2ProviderDeviceCodeByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOAuth2ProviderDeviceCodeByID indicates an expected call of DeleteOAuth2ProviderDeviceCodeByID.
+func (mr *MockStoreMockRecorder) DeleteOAuth2ProviderDeviceCodeByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOAuth2ProviderDeviceCodeByID", reflect.TypeOf((*MockStore)(nil).DeleteOAuth2ProviderDeviceCodeByID), arg0, arg1)
+}
+
+// DeleteOldAuditLogs mocks base method.
+func (m *MockStore) DeleteOldAuditLogs(arg0 context.Context, arg1 database.DeleteOldAuditLogsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldAuditLogs", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteOldAuditLogs indicates an expected call of DeleteOldAuditLogs.
+func (mr *MockStoreMockRecorder) DeleteOldAuditLogs(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldAuditLogs", reflect.TypeOf((*MockStore)(nil).DeleteOldAuditLogs), arg0, arg1)
+}
+
+// DeleteOldNetworkReports mocks base method.
+func (m *MockStore) DeleteOldNetworkReports(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldNetworkReports", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOldNetworkReports indicates an expected call of DeleteOldNetworkReports.
+func (mr *MockStoreMockRecorder) DeleteOldNetworkReports(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldNetworkReports", reflect.TypeOf((*MockStore)(nil).DeleteOldNetworkReports), arg0)
+}
+
 // DeleteOldNotificationMessages mocks base method.
-func (m *MockStore) DeleteOldNotificationMessages(arg0 context.Context) error {
+func (m *MockStore) DeleteOldNotificationMessages(arg0 context.Context, arg1 time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteOldNotificationMessages", arg0)
+	ret := m.ctrl.Call(m, "DeleteOldNotificationMessages", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteOldNotificationMessages indicates an expected call of DeleteOldNotificationMessages.
-func (mr *MockStoreMockRecorder) DeleteOldNotificationMessages(arg0 any) *gomock.Call {
+func (mr *MockStoreMockRecorder) DeleteOldNotificationMessages(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldNotificationMessages", reflect.TypeOf((*MockStore)(nil).DeleteOldNotificationMessages), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldNotificationMessages", reflect.TypeOf((*MockStore)(nil).DeleteOldNotificationMessages), arg0, arg1)
 }
 
 // DeleteOldProvisionerDaemons mocks base method.
@@ -528,6 +600,21 @@ func (mr *MockStoreMockRecorder) DeleteOldProvisionerDaemons(arg0 any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldProvisionerDaemons", reflect.TypeOf((*MockStore)(nil).DeleteOldProvisionerDaemons), arg0)
 }
 
+// DeleteOldProvisionerJobLogs mocks base method.
+func (m *MockStore) DeleteOldProvisionerJobLogs(arg0 context.Context, arg1 database.DeleteOldProvisionerJobLogsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldProvisionerJobLogs", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteOldProvisionerJobLogs indicates an expected call of DeleteOldProvisionerJobLogs.
+func (mr *MockStoreMockRecorder) DeleteOldProvisionerJobLogs(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldProvisionerJobLogs", reflect.TypeOf((*MockStore)(nil).DeleteOldProvisionerJobLogs), arg0, arg1)
+}
+
 // DeleteOldWorkspaceAgentLogs mocks base method.
 func (m *MockStore) DeleteOldWorkspaceAgentLogs(arg0 context.Context, arg1 time.Time) error {
 	m.ctrl.T.Helper()
@@ -543,17 +630,17 @@ func (mr *MockStoreMockRecorder) DeleteOldWorkspaceAgentLogs(arg0, arg1 any) *go
 }
 
 // DeleteOldWorkspaceAgentStats mocks base method.
-func (m *MockStore) DeleteOldWorkspaceAgentStats(arg0 context.Context) error {
+func (m *MockStore) DeleteOldWorkspaceAgentStats(arg0 context.Context, arg1 time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteOldWorkspaceAgentStats", arg0)
+	ret := m.ctrl.Call(m, "DeleteOldWorkspaceAgentStats", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteOldWorkspaceAgentStats indicates an expected call of DeleteOldWorkspaceAgentStats.
-func (mr *MockStoreMockRecorder) DeleteOldWorkspaceAgentStats(arg0 any) *gomock.Call {
+func (mr *MockStoreMockRecorder) DeleteOldWorkspaceAgentStats(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWorkspaceAgentStats", reflect.TypeOf((*MockStore)(nil).DeleteOldWorkspaceAgentStats), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWorkspaceAgentStats", reflect.TypeOf((*MockStore)(nil).DeleteOldWorkspaceAgentStats), arg0, arg1)
 }
 
 // DeleteOrganization mocks base method.
@@ -626,6 +713,20 @@ func (mr *MockStoreMockRecorder) DeleteRuntimeConfig(arg0, arg1 any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRuntimeConfig", reflect.TypeOf((*MockStore)(nil).DeleteRuntimeConfig), arg0, arg1)
 }
 
+// DeleteSecretByID mocks base method.
+func (m *MockStore) DeleteSecretByID(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSecretByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSecretByID indicates an expected call of DeleteSecretByID.
+func (mr *MockStoreMockRecorder) DeleteSecretByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSecretByID", reflect.TypeOf((*MockStore)(nil).DeleteSecretByID), arg0, arg1)
+}
+
 // DeleteTailnetAgent mocks base method.
 func (m *MockStore) DeleteTailnetAgent(arg0 context.Context, arg1 database.DeleteTailnetAgentParams) (database.DeleteTailnetAgentRow, error) {
 	m.ctrl.T.Helper()
@@ -728,6 +829,20 @@ func (mr *MockStoreMockRecorder) DeleteWorkspaceAgentPortSharesByTemplate(arg0,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceAgentPortSharesByTemplate", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceAgentPortSharesByTemplate), arg0, arg1)
 }
 
+// DeleteWorkspaceScheduledActionByID mocks base method.
+func (m *MockStore) DeleteWorkspaceScheduledActionByID(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspaceScheduledActionByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspaceScheduledActionByID indicates an expected call of DeleteWorkspaceScheduledActionByID.
+func (mr *MockStoreMockRecorder) DeleteWorkspaceScheduledActionByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceScheduledActionByID", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceScheduledActionByID), arg0, arg1)
+}
+
 // DisableForeignKeysAndTriggers mocks base method.
 func (m *MockStore) DisableForeignKeysAndTriggers(arg0 context.Context) error {
 	m.ctrl.T.Helper()
@@ -860,6 +975,21 @@ func (mr *MockStoreMockRecorder) GetAPIKeysLastUsedAfter(arg0, arg1 any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeysLastUsedAfter", reflect.TypeOf((*MockStore)(nil).GetAPIKeysLastUsedAfter), arg0, arg1)
 }
 
+// GetActiveTemplateRolloutByTemplateID mocks base method.
+func (m *MockStore) GetActiveTemplateRolloutByTemplateID(arg0 context.Context, arg1 uuid.UUID) (database.TemplateRollout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveTemplateRolloutByTemplateID", arg0, arg1)
+	ret0, _ := ret[0].(database.TemplateRollout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveTemplateRolloutByTemplateID indicates an expected call of GetActiveTemplateRolloutByTemplateID.
+func (mr *MockStoreMockRecorder) GetActiveTemplateRolloutByTemplateID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveTemplateRolloutByTemplateID", reflect.TypeOf((*MockStore)(nil).GetActiveTemplateRolloutByTemplateID), arg0, arg1)
+}
+
 // GetActiveUserCount mocks base method.
 func (m *MockStore) GetActiveUserCount(arg0 context.Context) (int64, error) {
 	m.ctrl.T.Helper()
@@ -1115,6 +1245,66 @@ func (mr *MockStoreMockRecorder) GetCoordinatorResumeTokenSigningKey(arg0 any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCoordinatorResumeTokenSigningKey", reflect.TypeOf((*MockStore)(nil).GetCoordinatorResumeTokenSigningKey), arg0)
 }
 
+// GetCostBudgetByGroupID mocks base method.
+func (m *MockStore) GetCostBudgetByGroupID(arg0 context.Context, arg1 uuid.UUID) (database.CostBudget, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCostBudgetByGroupID", arg0, arg1)
+	ret0, _ := ret[0].(database.CostBudget)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCostBudgetByGroupID indicates an expected call of GetCostBudgetByGroupID.
+func (mr *MockStoreMockRecorder) GetCostBudgetByGroupID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCostBudgetByGroupID", reflect.TypeOf((*MockStore)(nil).GetCostBudgetByGroupID), arg0, arg1)
+}
+
+// GetCostBudgetMemberIDs mocks base method.
+func (m *MockStore) GetCostBudgetMemberIDs(arg0 context.Context, arg1 uuid.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCostBudgetMemberIDs", arg0, arg1)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCostBudgetMemberIDs indicates an expected call of GetCostBudgetMemberIDs.
+func (mr *MockStoreMockRecorder) GetCostBudgetMemberIDs(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCostBudgetMemberIDs", reflect.TypeOf((*MockStore)(nil).GetCostBudgetMemberIDs), arg0, arg1)
+}
+
+// GetCostBudgetWorkspaceIDs mocks base method.
+func (m *MockStore) GetCostBudgetWorkspaceIDs(arg0 context.Context, arg1 uuid.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCostBudgetWorkspaceIDs", arg0, arg1)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCostBudgetWorkspaceIDs indicates an expected call of GetCostBudgetWorkspaceIDs.
+func (mr *MockStoreMockRecorder) GetCostBudgetWorkspaceIDs(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCostBudgetWorkspaceIDs", reflect.TypeOf((*MockStore)(nil).GetCostBudgetWorkspaceIDs), arg0, arg1)
+}
+
+// GetCostBudgetsWithCost mocks base method.
+func (m *MockStore) GetCostBudgetsWithCost(arg0 context.Context, arg1 time.Time) ([]database.GetCostBudgetsWithCostRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCostBudgetsWithCost", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetCostBudgetsWithCostRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCostBudgetsWithCost indicates an expected call of GetCostBudgetsWithCost.
+func (mr *MockStoreMockRecorder) GetCostBudgetsWithCost(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCostBudgetsWithCost", reflect.TypeOf((*MockStore)(nil).GetCostBudgetsWithCost), arg0, arg1)
+}
+
 // GetCryptoKeyByFeatureAndSequence mocks base method.
 func (m *MockStore) GetCryptoKeyByFeatureAndSequence(arg0 context.Context, arg1 database.GetCryptoKeyByFeatureAndSequenceParams) (database.CryptoKey, error) {
 	m.ctrl.T.Helper()
@@ -1670,6 +1860,21 @@ func (mr *MockStoreMockRecorder) GetLogoURL(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogoURL", reflect.TypeOf((*MockStore)(nil).GetLogoURL), arg0)
 }
 
+// GetNetworkRegionInsights mocks base method.
+func (m *MockStore) GetNetworkRegionInsights(arg0 context.Context, arg1 database.GetNetworkRegionInsightsParams) ([]database.GetNetworkRegionInsightsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNetworkRegionInsights", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetNetworkRegionInsightsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNetworkRegionInsights indicates an expected call of GetNetworkRegionInsights.
+func (mr *MockStoreMockRecorder) GetNetworkRegionInsights(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNetworkRegionInsights", reflect.TypeOf((*MockStore)(nil).GetNetworkRegionInsights), arg0, arg1)
+}
+
 // GetNotificationMessagesByStatus mocks base method.
 func (m *MockStore) GetNotificationMessagesByStatus(arg0 context.Context, arg1 database.GetNotificationMessagesByStatusParams) ([]database.NotificationMessage, error) {
 	m.ctrl.T.Helper()
@@ -1880,6 +2085,36 @@ func (mr *MockStoreMockRecorder) GetOAuth2ProviderAppsByUserID(arg0, arg1 any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuth2ProviderAppsByUserID", reflect.TypeOf((*MockStore)(nil).GetOAuth2ProviderAppsByUserID), arg0, arg1)
 }
 
+// GetOAuth2ProviderDeviceCodeByPrefix mocks base method.
+func (m *MockStore) GetOAuth2ProviderDeviceCodeByPrefix(arg0 context.Context, arg1 []byte) (database.OAuth2ProviderDeviceCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOAuth2ProviderDeviceCodeByPrefix", arg0, arg1)
+	ret0, _ := ret[0].(database.OAuth2ProviderDeviceCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOAuth2ProviderDeviceCodeByPrefix indicates an expected call of GetOAuth2ProviderDeviceCodeByPrefix.
+func (mr *MockStoreMockRecorder) GetOAuth2ProviderDeviceCodeByPrefix(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuth2ProviderDeviceCodeByPrefix", reflect.TypeOf((*MockStore)(nil).GetOAuth2ProviderDeviceCodeByPrefix), arg0, arg1)
+}
+
+// GetOAuth2ProviderDeviceCodeByUserCode mocks base method.
+func (m *MockStore) GetOAuth2ProviderDeviceCodeByUserCode(arg0 context.Context, arg1 string) (database.OAuth2ProviderDeviceCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOAuth2ProviderDeviceCodeByUserCode", arg0, arg1)
+	ret0, _ := ret[0].(database.OAuth2ProviderDeviceCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOAuth2ProviderDeviceCodeByUserCode indicates an expected call of GetOAuth2ProviderDeviceCodeByUserCode.
+func (mr *MockStoreMockRecorder) GetOAuth2ProviderDeviceCodeByUserCode(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuth2ProviderDeviceCodeByUserCode", reflect.TypeOf((*MockStore)(nil).GetOAuth2ProviderDeviceCodeByUserCode), arg0, arg1)
+}
+
 // GetOAuthSigningKey mocks base method.
 func (m *MockStore) GetOAuthSigningKey(arg0 context.Context) (string, error) {
 	m.ctrl.T.Helper()
@@ -1985,6 +2220,36 @@ func (mr *MockStoreMockRecorder) GetParameterSchemasByJobID(arg0, arg1 any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParameterSchemasByJobID", reflect.TypeOf((*MockStore)(nil).GetParameterSchemasByJobID), arg0, arg1)
 }
 
+// GetPendingProvisionerJobTags mocks base method.
+func (m *MockStore) GetPendingProvisionerJobTags(arg0 context.Context, arg1 database.GetPendingProvisionerJobTagsParams) ([]database.GetPendingProvisionerJobTagsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingProvisionerJobTags", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetPendingProvisionerJobTagsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingProvisionerJobTags indicates an expected call of GetPendingProvisionerJobTags.
+func (mr *MockStoreMockRecorder) GetPendingProvisionerJobTags(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingProvisionerJobTags", reflect.TypeOf((*MockStore)(nil).GetPendingProvisionerJobTags), arg0, arg1)
+}
+
+// GetPendingWorkspaceBuildApprovalsByTemplateID mocks base method.
+func (m *MockStore) GetPendingWorkspaceBuildApprovalsByTemplateID(arg0 context.Context, arg1 uuid.UUID) ([]database.GetPendingWorkspaceBuildApprovalsByTemplateIDRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingWorkspaceBuildApprovalsByTemplateID", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetPendingWorkspaceBuildApprovalsByTemplateIDRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingWorkspaceBuildApprovalsByTemplateID indicates an expected call of GetPendingWorkspaceBuildApprovalsByTemplateID.
+func (mr *MockStoreMockRecorder) GetPendingWorkspaceBuildApprovalsByTemplateID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingWorkspaceBuildApprovalsByTemplateID", reflect.TypeOf((*MockStore)(nil).GetPendingWorkspaceBuildApprovalsByTemplateID), arg0, arg1)
+}
+
 // GetPreviousTemplateVersion mocks base method.
 func (m *MockStore) GetPreviousTemplateVersion(arg0 context.Context, arg1 database.GetPreviousTemplateVersionParams) (database.TemplateVersion, error) {
 	m.ctrl.T.Helper()
@@ -2060,6 +2325,21 @@ func (mr *MockStoreMockRecorder) GetProvisionerJobByID(arg0, arg1 any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerJobByID", reflect.TypeOf((*MockStore)(nil).GetProvisionerJobByID), arg0, arg1)
 }
 
+// GetProvisionerJobResourceChangesByJobID mocks base method.
+func (m *MockStore) GetProvisionerJobResourceChangesByJobID(arg0 context.Context, arg1 uuid.UUID) ([]database.ProvisionerJobResourceChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProvisionerJobResourceChangesByJobID", arg0, arg1)
+	ret0, _ := ret[0].([]database.ProvisionerJobResourceChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProvisionerJobResourceChangesByJobID indicates an expected call of GetProvisionerJobResourceChangesByJobID.
+func (mr *MockStoreMockRecorder) GetProvisionerJobResourceChangesByJobID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerJobResourceChangesByJobID", reflect.TypeOf((*MockStore)(nil).GetProvisionerJobResourceChangesByJobID), arg0, arg1)
+}
+
 // GetProvisionerJobTimingsByJobID mocks base method.
 func (m *MockStore) GetProvisionerJobTimingsByJobID(arg0 context.Context, arg1 uuid.UUID) ([]database.ProvisionerJobTiming, error) {
 	m.ctrl.T.Helper()
@@ -2180,6 +2460,21 @@ func (mr *MockStoreMockRecorder) GetProvisionerLogsAfterID(arg0, arg1 any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerLogsAfterID", reflect.TypeOf((*MockStore)(nil).GetProvisionerLogsAfterID), arg0, arg1)
 }
 
+// GetPurgeableRowCounts mocks base method.
+func (m *MockStore) GetPurgeableRowCounts(arg0 context.Context, arg1 database.GetPurgeableRowCountsParams) (database.GetPurgeableRowCountsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPurgeableRowCounts", arg0, arg1)
+	ret0, _ := ret[0].(database.GetPurgeableRowCountsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPurgeableRowCounts indicates an expected call of GetPurgeableRowCounts.
+func (mr *MockStoreMockRecorder) GetPurgeableRowCounts(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPurgeableRowCounts", reflect.TypeOf((*MockStore)(nil).GetPurgeableRowCounts), arg0, arg1)
+}
+
 // GetQuotaAllowanceForUser mocks base method.
 func (m *MockStore) GetQuotaAllowanceForUser(arg0 context.Context, arg1 database.GetQuotaAllowanceForUserParams) (int64, error) {
 	m.ctrl.T.Helper()
@@ -2255,6 +2550,81 @@ func (mr *MockStoreMockRecorder) GetRuntimeConfig(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRuntimeConfig", reflect.TypeOf((*MockStore)(nil).GetRuntimeConfig), arg0, arg1)
 }
 
+// GetSecretByID mocks base method.
+func (m *MockStore) GetSecretByID(arg0 context.Context, arg1 uuid.UUID) (database.Secret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSecretByID", arg0, arg1)
+	ret0, _ := ret[0].(database.Secret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSecretByID indicates an expected call of GetSecretByID.
+func (mr *MockStoreMockRecorder) GetSecretByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecretByID", reflect.TypeOf((*MockStore)(nil).GetSecretByID), arg0, arg1)
+}
+
+// GetSecretByScopeAndName mocks base method.
+func (m *MockStore) GetSecretByScopeAndName(arg0 context.Context, arg1 database.GetSecretByScopeAndNameParams) (database.Secret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSecretByScopeAndName", arg0, arg1)
+	ret0, _ := ret[0].(database.Secret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSecretByScopeAndName indicates an expected call of GetSecretByScopeAndName.
+func (mr *MockStoreMockRecorder) GetSecretByScopeAndName(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecretByScopeAndName", reflect.TypeOf((*MockStore)(nil).GetSecretByScopeAndName), arg0, arg1)
+}
+
+// GetSecrets mocks base method.
+func (m *MockStore) GetSecrets(arg0 context.Context) ([]database.Secret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSecrets", arg0)
+	ret0, _ := ret[0].([]database.Secret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSecrets indicates an expected call of GetSecrets.
+func (mr *MockStoreMockRecorder) GetSecrets(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecrets", reflect.TypeOf((*MockStore)(nil).GetSecrets), arg0)
+}
+
+// GetSecretsByScope mocks base method.
+func (m *MockStore) GetSecretsByScope(arg0 context.Context, arg1 database.GetSecretsByScopeParams) ([]database.Secret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSecretsByScope", arg0, arg1)
+	ret0, _ := ret[0].([]database.Secret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSecretsByScope indicates an expected call of GetSecretsByScope.
+func (mr *MockStoreMockRecorder) GetSecretsByScope(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecretsByScope", reflect.TypeOf((*MockStore)(nil).GetSecretsByScope), arg0, arg1)
+}
+
+// GetSecretsForWorkspace mocks base method.
+func (m *MockStore) GetSecretsForWorkspace(arg0 context.Context, arg1 database.GetSecretsForWorkspaceParams) ([]database.Secret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSecretsForWorkspace", arg0, arg1)
+	ret0, _ := ret[0].([]database.Secret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSecretsForWorkspace indicates an expected call of GetSecretsForWorkspace.
+func (mr *MockStoreMockRecorder) GetSecretsForWorkspace(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecretsForWorkspace", reflect.TypeOf((*MockStore)(nil).GetSecretsForWorkspace), arg0, arg1)
+}
+
 // GetTailnetAgents mocks base method.
 func (m *MockStore) GetTailnetAgents(arg0 context.Context, arg1 uuid.UUID) ([]database.TailnetAgent, error) {
 	m.ctrl.T.Helper()
@@ -2495,6 +2865,51 @@ func (mr *MockStoreMockRecorder) GetTemplateParameterInsights(arg0, arg1 any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateParameterInsights", reflect.TypeOf((*MockStore)(nil).GetTemplateParameterInsights), arg0, arg1)
 }
 
+// GetTemplateRolloutBuildCounts mocks base method.
+func (m *MockStore) GetTemplateRolloutBuildCounts(arg0 context.Context, arg1 database.GetTemplateRolloutBuildCountsParams) (database.GetTemplateRolloutBuildCountsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateRolloutBuildCounts", arg0, arg1)
+	ret0, _ := ret[0].(database.GetTemplateRolloutBuildCountsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateRolloutBuildCounts indicates an expected call of GetTemplateRolloutBuildCounts.
+func (mr *MockStoreMockRecorder) GetTemplateRolloutBuildCounts(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateRolloutBuildCounts", reflect.TypeOf((*MockStore)(nil).GetTemplateRolloutBuildCounts), arg0, arg1)
+}
+
+// GetTemplateRolloutByID mocks base method.
+func (m *MockStore) GetTemplateRolloutByID(arg0 context.Context, arg1 uuid.UUID) (database.TemplateRollout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateRolloutByID", arg0, arg1)
+	ret0, _ := ret[0].(database.TemplateRollout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateRolloutByID indicates an expected call of GetTemplateRolloutByID.
+func (mr *MockStoreMockRecorder) GetTemplateRolloutByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateRolloutByID", reflect.TypeOf((*MockStore)(nil).GetTemplateRolloutByID), arg0, arg1)
+}
+
+// GetTemplateRolloutsByTemplateID mocks base method.
+func (m *MockStore) GetTemplateRolloutsByTemplateID(arg0 context.Context, arg1 uuid.UUID) ([]database.TemplateRollout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateRolloutsByTemplateID", arg0, arg1)
+	ret0, _ := ret[0].([]database.TemplateRollout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateRolloutsByTemplateID indicates an expected call of GetTemplateRolloutsByTemplateID.
+func (mr *MockStoreMockRecorder) GetTemplateRolloutsByTemplateID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateRolloutsByTemplateID", reflect.TypeOf((*MockStore)(nil).GetTemplateRolloutsByTemplateID), arg0, arg1)
+}
+
 // GetTemplateUsageStats mocks base method.
 func (m *MockStore) GetTemplateUsageStats(arg0 context.Context, arg1 database.GetTemplateUsageStatsParams) ([]database.TemplateUsageStat, error) {
 	m.ctrl.T.Helper()
@@ -3020,6 +3435,21 @@ func (mr *MockStoreMockRecorder) GetWorkspaceAgentPortShare(arg0, arg1 any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentPortShare", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentPortShare), arg0, arg1)
 }
 
+// GetWorkspaceAgentProcessGroups mocks base method.
+func (m *MockStore) GetWorkspaceAgentProcessGroups(arg0 context.Context, arg1 uuid.UUID) ([]database.WorkspaceAgentProcessGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentProcessGroups", arg0, arg1)
+	ret0, _ := ret[0].([]database.WorkspaceAgentProcessGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentProcessGroups indicates an expected call of GetWorkspaceAgentProcessGroups.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentProcessGroups(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentProcessGroups", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentProcessGroups), arg0, arg1)
+}
+
 // GetWorkspaceAgentScriptTimingsByBuildID mocks base method.
 func (m *MockStore) GetWorkspaceAgentScriptTimingsByBuildID(arg0 context.Context, arg1 uuid.UUID) ([]database.GetWorkspaceAgentScriptTimingsByBuildIDRow, error) {
 	m.ctrl.T.Helper()
@@ -3215,6 +3645,21 @@ func (mr *MockStoreMockRecorder) GetWorkspaceAppsCreatedAfter(arg0, arg1 any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAppsCreatedAfter", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAppsCreatedAfter), arg0, arg1)
 }
 
+// GetWorkspaceBuildApprovalByBuildID mocks base method.
+func (m *MockStore) GetWorkspaceBuildApprovalByBuildID(arg0 context.Context, arg1 uuid.UUID) (database.WorkspaceBuildApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceBuildApprovalByBuildID", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceBuildApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceBuildApprovalByBuildID indicates an expected call of GetWorkspaceBuildApprovalByBuildID.
+func (mr *MockStoreMockRecorder) GetWorkspaceBuildApprovalByBuildID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceBuildApprovalByBuildID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceBuildApprovalByBuildID), arg0, arg1)
+}
+
 // GetWorkspaceBuildByID mocks base method.
 func (m *MockStore) GetWorkspaceBuildByID(arg0 context.Context, arg1 uuid.UUID) (database.WorkspaceBuild, error) {
 	m.ctrl.T.Helper()
@@ -3380,26 +3825,86 @@ func (mr *MockStoreMockRecorder) GetWorkspaceByWorkspaceAppID(arg0, arg1 any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceByWorkspaceAppID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceByWorkspaceAppID), arg0, arg1)
 }
 
-// GetWorkspaceModulesByJobID mocks base method.
-func (m *MockStore) GetWorkspaceModulesByJobID(arg0 context.Context, arg1 uuid.UUID) ([]database.WorkspaceModule, error) {
+// GetWorkspaceCostInsightsByGroup mocks base method.
+func (m *MockStore) GetWorkspaceCostInsightsByGroup(arg0 context.Context, arg1 database.GetWorkspaceCostInsightsByGroupParams) ([]database.GetWorkspaceCostInsightsByGroupRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetWorkspaceModulesByJobID", arg0, arg1)
-	ret0, _ := ret[0].([]database.WorkspaceModule)
+	ret := m.ctrl.Call(m, "GetWorkspaceCostInsightsByGroup", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetWorkspaceCostInsightsByGroupRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetWorkspaceModulesByJobID indicates an expected call of GetWorkspaceModulesByJobID.
-func (mr *MockStoreMockRecorder) GetWorkspaceModulesByJobID(arg0, arg1 any) *gomock.Call {
+// GetWorkspaceCostInsightsByGroup indicates an expected call of GetWorkspaceCostInsightsByGroup.
+func (mr *MockStoreMockRecorder) GetWorkspaceCostInsightsByGroup(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceModulesByJobID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceModulesByJobID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceCostInsightsByGroup", reflect.TypeOf((*MockStore)(nil).GetWorkspaceCostInsightsByGroup), arg0, arg1)
 }
 
-// GetWorkspaceModulesCreatedAfter mocks base method.
-func (m *MockStore) GetWorkspaceModulesCreatedAfter(arg0 context.Context, arg1 time.Time) ([]database.WorkspaceModule, error) {
+// GetWorkspaceCostInsightsByInterval mocks base method.
+func (m *MockStore) GetWorkspaceCostInsightsByInterval(arg0 context.Context, arg1 database.GetWorkspaceCostInsightsByIntervalParams) ([]database.GetWorkspaceCostInsightsByIntervalRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetWorkspaceModulesCreatedAfter", arg0, arg1)
-	ret0, _ := ret[0].([]database.WorkspaceModule)
+	ret := m.ctrl.Call(m, "GetWorkspaceCostInsightsByInterval", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetWorkspaceCostInsightsByIntervalRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceCostInsightsByInterval indicates an expected call of GetWorkspaceCostInsightsByInterval.
+func (mr *MockStoreMockRecorder) GetWorkspaceCostInsightsByInterval(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceCostInsightsByInterval", reflect.TypeOf((*MockStore)(nil).GetWorkspaceCostInsightsByInterval), arg0, arg1)
+}
+
+// GetWorkspaceCostInsightsByTemplate mocks base method.
+func (m *MockStore) GetWorkspaceCostInsightsByTemplate(arg0 context.Context, arg1 database.GetWorkspaceCostInsightsByTemplateParams) ([]database.GetWorkspaceCostInsightsByTemplateRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceCostInsightsByTemplate", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetWorkspaceCostInsightsByTemplateRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceCostInsightsByTemplate indicates an expected call of GetWorkspaceCostInsightsByTemplate.
+func (mr *MockStoreMockRecorder) GetWorkspaceCostInsightsByTemplate(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceCostInsightsByTemplate", reflect.TypeOf((*MockStore)(nil).GetWorkspaceCostInsightsByTemplate), arg0, arg1)
+}
+
+// GetWorkspaceCostInsightsByUser mocks base method.
+func (m *MockStore) GetWorkspaceCostInsightsByUser(arg0 context.Context, arg1 database.GetWorkspaceCostInsightsByUserParams) ([]database.GetWorkspaceCostInsightsByUserRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceCostInsightsByUser", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetWorkspaceCostInsightsByUserRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceCostInsightsByUser indicates an expected call of GetWorkspaceCostInsightsByUser.
+func (mr *MockStoreMockRecorder) GetWorkspaceCostInsightsByUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceCostInsightsByUser", reflect.TypeOf((*MockStore)(nil).GetWorkspaceCostInsightsByUser), arg0, arg1)
+}
+
+// GetWorkspaceModulesByJobID mocks base method.
+func (m *MockStore) GetWorkspaceModulesByJobID(arg0 context.Context, arg1 uuid.UUID) ([]database.WorkspaceModule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceModulesByJobID", arg0, arg1)
+	ret0, _ := ret[0].([]database.WorkspaceModule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceModulesByJobID indicates an expected call of GetWorkspaceModulesByJobID.
+func (mr *MockStoreMockRecorder) GetWorkspaceModulesByJobID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceModulesByJobID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceModulesByJobID), arg0, arg1)
+}
+
+// GetWorkspaceModulesCreatedAfter mocks base method.
+func (m *MockStore) GetWorkspaceModulesCreatedAfter(arg0 context.Context, arg1 time.Time) ([]database.WorkspaceModule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceModulesCreatedAfter", arg0, arg1)
+	ret0, _ := ret[0].([]database.WorkspaceModule)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -3560,6 +4065,126 @@ func (mr *MockStoreMockRecorder) GetWorkspaceResourcesCreatedAfter(arg0, arg1 an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceResourcesCreatedAfter", reflect.TypeOf((*MockStore)(nil).GetWorkspaceResourcesCreatedAfter), arg0, arg1)
 }
 
+// GetWorkspaceScheduledActionByID mocks base method.
+func (m *MockStore) GetWorkspaceScheduledActionByID(arg0 context.Context, arg1 uuid.UUID) (database.WorkspaceScheduledAction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceScheduledActionByID", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceScheduledAction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceScheduledActionByID indicates an expected call of GetWorkspaceScheduledActionByID.
+func (mr *MockStoreMockRecorder) GetWorkspaceScheduledActionByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceScheduledActionByID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceScheduledActionByID), arg0, arg1)
+}
+
+// GetWorkspaceScheduledActionsByWorkspaceID mocks base method.
+func (m *MockStore) GetWorkspaceScheduledActionsByWorkspaceID(arg0 context.Context, arg1 uuid.UUID) ([]database.WorkspaceScheduledAction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceScheduledActionsByWorkspaceID", arg0, arg1)
+	ret0, _ := ret[0].([]database.WorkspaceScheduledAction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceScheduledActionsByWorkspaceID indicates an expected call of GetWorkspaceScheduledActionsByWorkspaceID.
+func (mr *MockStoreMockRecorder) GetWorkspaceScheduledActionsByWorkspaceID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceScheduledActionsByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceScheduledActionsByWorkspaceID), arg0, arg1)
+}
+
+// GetWorkspaceScheduledActionsDue mocks base method.
+func (m *MockStore) GetWorkspaceScheduledActionsDue(arg0 context.Context, arg1 time.Time) ([]database.WorkspaceScheduledAction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceScheduledActionsDue", arg0, arg1)
+	ret0, _ := ret[0].([]database.WorkspaceScheduledAction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceScheduledActionsDue indicates an expected call of GetWorkspaceScheduledActionsDue.
+func (mr *MockStoreMockRecorder) GetWorkspaceScheduledActionsDue(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceScheduledActionsDue", reflect.TypeOf((*MockStore)(nil).GetWorkspaceScheduledActionsDue), arg0, arg1)
+}
+
+// GetWorkspaceSessionRecordingByID mocks base method.
+func (m *MockStore) GetWorkspaceSessionRecordingByID(arg0 context.Context, arg1 uuid.UUID) (database.WorkspaceSessionRecording, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceSessionRecordingByID", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceSessionRecording)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceSessionRecordingByID indicates an expected call of GetWorkspaceSessionRecordingByID.
+func (mr *MockStoreMockRecorder) GetWorkspaceSessionRecordingByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceSessionRecordingByID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceSessionRecordingByID), arg0, arg1)
+}
+
+// GetWorkspaceSessionRecordingChunksByRecordingID mocks base method.
+func (m *MockStore) GetWorkspaceSessionRecordingChunksByRecordingID(arg0 context.Context, arg1 database.GetWorkspaceSessionRecordingChunksByRecordingIDParams) ([]database.WorkspaceSessionRecordingChunk, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceSessionRecordingChunksByRecordingID", arg0, arg1)
+	ret0, _ := ret[0].([]database.WorkspaceSessionRecordingChunk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceSessionRecordingChunksByRecordingID indicates an expected call of GetWorkspaceSessionRecordingChunksByRecordingID.
+func (mr *MockStoreMockRecorder) GetWorkspaceSessionRecordingChunksByRecordingID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceSessionRecordingChunksByRecordingID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceSessionRecordingChunksByRecordingID), arg0, arg1)
+}
+
+// GetWorkspaceSessionRecordingsByWorkspaceID mocks base method.
+func (m *MockStore) GetWorkspaceSessionRecordingsByWorkspaceID(arg0 context.Context, arg1 uuid.UUID) ([]database.WorkspaceSessionRecording, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceSessionRecordingsByWorkspaceID", arg0, arg1)
+	ret0, _ := ret[0].([]database.WorkspaceSessionRecording)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceSessionRecordingsByWorkspaceID indicates an expected call of GetWorkspaceSessionRecordingsByWorkspaceID.
+func (mr *MockStoreMockRecorder) GetWorkspaceSessionRecordingsByWorkspaceID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceSessionRecordingsByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceSessionRecordingsByWorkspaceID), arg0, arg1)
+}
+
+// GetWorkspaceSnapshotByID mocks base method.
+func (m *MockStore) GetWorkspaceSnapshotByID(arg0 context.Context, arg1 uuid.UUID) (database.WorkspaceSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceSnapshotByID", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceSnapshotByID indicates an expected call of GetWorkspaceSnapshotByID.
+func (mr *MockStoreMockRecorder) GetWorkspaceSnapshotByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceSnapshotByID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceSnapshotByID), arg0, arg1)
+}
+
+// GetWorkspaceSnapshotsByOwnerAndTemplate mocks base method.
+func (m *MockStore) GetWorkspaceSnapshotsByOwnerAndTemplate(arg0 context.Context, arg1 database.GetWorkspaceSnapshotsByOwnerAndTemplateParams) ([]database.WorkspaceSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceSnapshotsByOwnerAndTemplate", arg0, arg1)
+	ret0, _ := ret[0].([]database.WorkspaceSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceSnapshotsByOwnerAndTemplate indicates an expected call of GetWorkspaceSnapshotsByOwnerAndTemplate.
+func (mr *MockStoreMockRecorder) GetWorkspaceSnapshotsByOwnerAndTemplate(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceSnapshotsByOwnerAndTemplate", reflect.TypeOf((*MockStore)(nil).GetWorkspaceSnapshotsByOwnerAndTemplate), arg0, arg1)
+}
+
 // GetWorkspaceUniqueOwnerCountByTemplateIDs mocks base method.
 func (m *MockStore) GetWorkspaceUniqueOwnerCountByTemplateIDs(arg0 context.Context, arg1 []uuid.UUID) ([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error) {
 	m.ctrl.T.Helper()
@@ -3930,6 +4555,21 @@ func (mr *MockStoreMockRecorder) InsertOAuth2ProviderAppToken(arg0, arg1 any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOAuth2ProviderAppToken", reflect.TypeOf((*MockStore)(nil).InsertOAuth2ProviderAppToken), arg0, arg1)
 }
 
+// InsertOAuth2ProviderDeviceCode mocks base method.
+func (m *MockStore) InsertOAuth2ProviderDeviceCode(arg0 context.Context, arg1 database.InsertOAuth2ProviderDeviceCodeParams) (database.OAuth2ProviderDeviceCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertOAuth2ProviderDeviceCode", arg0, arg1)
+	ret0, _ := ret[0].(database.OAuth2ProviderDeviceCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertOAuth2ProviderDeviceCode indicates an expected call of InsertOAuth2ProviderDeviceCode.
+func (mr *MockStoreMockRecorder) InsertOAuth2ProviderDeviceCode(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOAuth2ProviderDeviceCode", reflect.TypeOf((*MockStore)(nil).InsertOAuth2ProviderDeviceCode), arg0, arg1)
+}
+
 // InsertOrganization mocks base method.
 func (m *MockStore) InsertOrganization(arg0 context.Context, arg1 database.InsertOrganizationParams) (database.Organization, error) {
 	m.ctrl.T.Helper()
@@ -3990,6 +4630,21 @@ func (mr *MockStoreMockRecorder) InsertProvisionerJobLogs(arg0, arg1 any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertProvisionerJobLogs", reflect.TypeOf((*MockStore)(nil).InsertProvisionerJobLogs), arg0, arg1)
 }
 
+// InsertProvisionerJobResourceChanges mocks base method.
+func (m *MockStore) InsertProvisionerJobResourceChanges(arg0 context.Context, arg1 database.InsertProvisionerJobResourceChangesParams) ([]database.ProvisionerJobResourceChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertProvisionerJobResourceChanges", arg0, arg1)
+	ret0, _ := ret[0].([]database.ProvisionerJobResourceChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertProvisionerJobResourceChanges indicates an expected call of InsertProvisionerJobResourceChanges.
+func (mr *MockStoreMockRecorder) InsertProvisionerJobResourceChanges(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertProvisionerJobResourceChanges", reflect.TypeOf((*MockStore)(nil).InsertProvisionerJobResourceChanges), arg0, arg1)
+}
+
 // InsertProvisionerJobTimings mocks base method.
 func (m *MockStore) InsertProvisionerJobTimings(arg0 context.Context, arg1 database.InsertProvisionerJobTimingsParams) ([]database.ProvisionerJobTiming, error) {
 	m.ctrl.T.Helper()
@@ -4035,6 +4690,21 @@ func (mr *MockStoreMockRecorder) InsertReplica(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertReplica", reflect.TypeOf((*MockStore)(nil).InsertReplica), arg0, arg1)
 }
 
+// InsertSecret mocks base method.
+func (m *MockStore) InsertSecret(arg0 context.Context, arg1 database.InsertSecretParams) (database.Secret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertSecret", arg0, arg1)
+	ret0, _ := ret[0].(database.Secret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertSecret indicates an expected call of InsertSecret.
+func (mr *MockStoreMockRecorder) InsertSecret(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertSecret", reflect.TypeOf((*MockStore)(nil).InsertSecret), arg0, arg1)
+}
+
 // InsertTemplate mocks base method.
 func (m *MockStore) InsertTemplate(arg0 context.Context, arg1 database.InsertTemplateParams) error {
 	m.ctrl.T.Helper()
@@ -4049,6 +4719,21 @@ func (mr *MockStoreMockRecorder) InsertTemplate(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTemplate", reflect.TypeOf((*MockStore)(nil).InsertTemplate), arg0, arg1)
 }
 
+// InsertTemplateRollout mocks base method.
+func (m *MockStore) InsertTemplateRollout(arg0 context.Context, arg1 database.InsertTemplateRolloutParams) (database.TemplateRollout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertTemplateRollout", arg0, arg1)
+	ret0, _ := ret[0].(database.TemplateRollout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertTemplateRollout indicates an expected call of InsertTemplateRollout.
+func (mr *MockStoreMockRecorder) InsertTemplateRollout(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTemplateRollout", reflect.TypeOf((*MockStore)(nil).InsertTemplateRollout), arg0, arg1)
+}
+
 // InsertTemplateVersion mocks base method.
 func (m *MockStore) InsertTemplateVersion(arg0 context.Context, arg1 database.InsertTemplateVersionParams) error {
 	m.ctrl.T.Helper()
@@ -4328,6 +5013,21 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceBuild(arg0, arg1 any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceBuild", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceBuild), arg0, arg1)
 }
 
+// InsertWorkspaceBuildApproval mocks base method.
+func (m *MockStore) InsertWorkspaceBuildApproval(arg0 context.Context, arg1 database.InsertWorkspaceBuildApprovalParams) (database.WorkspaceBuildApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceBuildApproval", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceBuildApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceBuildApproval indicates an expected call of InsertWorkspaceBuildApproval.
+func (mr *MockStoreMockRecorder) InsertWorkspaceBuildApproval(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceBuildApproval", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceBuildApproval), arg0, arg1)
+}
+
 // InsertWorkspaceBuildParameters mocks base method.
 func (m *MockStore) InsertWorkspaceBuildParameters(arg0 context.Context, arg1 database.InsertWorkspaceBuildParametersParams) error {
 	m.ctrl.T.Helper()
@@ -4402,6 +5102,66 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceResourceMetadata(arg0, arg1 any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceResourceMetadata", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceResourceMetadata), arg0, arg1)
 }
 
+// InsertWorkspaceScheduledAction mocks base method.
+func (m *MockStore) InsertWorkspaceScheduledAction(arg0 context.Context, arg1 database.InsertWorkspaceScheduledActionParams) (database.WorkspaceScheduledAction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceScheduledAction", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceScheduledAction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceScheduledAction indicates an expected call of InsertWorkspaceScheduledAction.
+func (mr *MockStoreMockRecorder) InsertWorkspaceScheduledAction(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceScheduledAction", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceScheduledAction), arg0, arg1)
+}
+
+// InsertWorkspaceSessionRecording mocks base method.
+func (m *MockStore) InsertWorkspaceSessionRecording(arg0 context.Context, arg1 database.InsertWorkspaceSessionRecordingParams) (database.WorkspaceSessionRecording, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceSessionRecording", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceSessionRecording)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceSessionRecording indicates an expected call of InsertWorkspaceSessionRecording.
+func (mr *MockStoreMockRecorder) InsertWorkspaceSessionRecording(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceSessionRecording", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceSessionRecording), arg0, arg1)
+}
+
+// InsertWorkspaceSessionRecordingChunk mocks base method.
+func (m *MockStore) InsertWorkspaceSessionRecordingChunk(arg0 context.Context, arg1 database.InsertWorkspaceSessionRecordingChunkParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceSessionRecordingChunk", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceSessionRecordingChunk indicates an expected call of InsertWorkspaceSessionRecordingChunk.
+func (mr *MockStoreMockRecorder) InsertWorkspaceSessionRecordingChunk(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceSessionRecordingChunk", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceSessionRecordingChunk), arg0, arg1)
+}
+
+// InsertWorkspaceSnapshot mocks base method.
+func (m *MockStore) InsertWorkspaceSnapshot(arg0 context.Context, arg1 database.InsertWorkspaceSnapshotParams) (database.WorkspaceSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceSnapshot", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceSnapshot indicates an expected call of InsertWorkspaceSnapshot.
+func (mr *MockStoreMockRecorder) InsertWorkspaceSnapshot(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceSnapshot", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceSnapshot), arg0, arg1)
+}
+
 // ListProvisionerKeysByOrganization mocks base method.
 func (m *MockStore) ListProvisionerKeysByOrganization(arg0 context.Context, arg1 uuid.UUID) ([]database.ProvisionerKey, error) {
 	m.ctrl.T.Helper()
@@ -4551,6 +5311,21 @@ func (mr *MockStoreMockRecorder) RegisterWorkspaceProxy(arg0, arg1 any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterWorkspaceProxy", reflect.TypeOf((*MockStore)(nil).RegisterWorkspaceProxy), arg0, arg1)
 }
 
+// RejectExpiredWorkspaceBuildApprovals mocks base method.
+func (m *MockStore) RejectExpiredWorkspaceBuildApprovals(arg0 context.Context, arg1 database.RejectExpiredWorkspaceBuildApprovalsParams) ([]database.WorkspaceBuildApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectExpiredWorkspaceBuildApprovals", arg0, arg1)
+	ret0, _ := ret[0].([]database.WorkspaceBuildApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RejectExpiredWorkspaceBuildApprovals indicates an expected call of RejectExpiredWorkspaceBuildApprovals.
+func (mr *MockStoreMockRecorder) RejectExpiredWorkspaceBuildApprovals(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectExpiredWorkspaceBuildApprovals", reflect.TypeOf((*MockStore)(nil).RejectExpiredWorkspaceBuildApprovals), arg0, arg1)
+}
+
 // RemoveUserFromAllGroups mocks base method.
 func (m *MockStore) RemoveUserFromAllGroups(arg0 context.Context, arg1 uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -4651,6 +5426,34 @@ func (mr *MockStoreMockRecorder) UpdateAPIKeyByID(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAPIKeyByID", reflect.TypeOf((*MockStore)(nil).UpdateAPIKeyByID), arg0, arg1)
 }
 
+// UpdateCostBudgetNotifiedAtByGroupID mocks base method.
+func (m *MockStore) UpdateCostBudgetNotifiedAtByGroupID(arg0 context.Context, arg1 database.UpdateCostBudgetNotifiedAtByGroupIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCostBudgetNotifiedAtByGroupID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCostBudgetNotifiedAtByGroupID indicates an expected call of UpdateCostBudgetNotifiedAtByGroupID.
+func (mr *MockStoreMockRecorder) UpdateCostBudgetNotifiedAtByGroupID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCostBudgetNotifiedAtByGroupID", reflect.TypeOf((*MockStore)(nil).UpdateCostBudgetNotifiedAtByGroupID), arg0, arg1)
+}
+
+// UpdateCostBudgetStoppedAtByGroupID mocks base method.
+func (m *MockStore) UpdateCostBudgetStoppedAtByGroupID(arg0 context.Context, arg1 database.UpdateCostBudgetStoppedAtByGroupIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCostBudgetStoppedAtByGroupID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCostBudgetStoppedAtByGroupID indicates an expected call of UpdateCostBudgetStoppedAtByGroupID.
+func (mr *MockStoreMockRecorder) UpdateCostBudgetStoppedAtByGroupID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCostBudgetStoppedAtByGroupID", reflect.TypeOf((*MockStore)(nil).UpdateCostBudgetStoppedAtByGroupID), arg0, arg1)
+}
+
 // UpdateCryptoKeyDeletesAt mocks base method.
 func (m *MockStore) UpdateCryptoKeyDeletesAt(arg0 context.Context, arg1 database.UpdateCryptoKeyDeletesAtParams) (database.CryptoKey, error) {
 	m.ctrl.T.Helper()
@@ -4666,6 +5469,21 @@ func (mr *MockStoreMockRecorder) UpdateCryptoKeyDeletesAt(arg0, arg1 any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCryptoKeyDeletesAt", reflect.TypeOf((*MockStore)(nil).UpdateCryptoKeyDeletesAt), arg0, arg1)
 }
 
+// UpdateCryptoKeySecret mocks base method.
+func (m *MockStore) UpdateCryptoKeySecret(arg0 context.Context, arg1 database.UpdateCryptoKeySecretParams) (database.CryptoKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCryptoKeySecret", arg0, arg1)
+	ret0, _ := ret[0].(database.CryptoKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCryptoKeySecret indicates an expected call of UpdateCryptoKeySecret.
+func (mr *MockStoreMockRecorder) UpdateCryptoKeySecret(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCryptoKeySecret", reflect.TypeOf((*MockStore)(nil).UpdateCryptoKeySecret), arg0, arg1)
+}
+
 // UpdateCustomRole mocks base method.
 func (m *MockStore) UpdateCustomRole(arg0 context.Context, arg1 database.UpdateCustomRoleParams) (database.CustomRole, error) {
 	m.ctrl.T.Helper()
@@ -4815,6 +5633,35 @@ func (mr *MockStoreMockRecorder) UpdateOAuth2ProviderAppSecretByID(arg0, arg1 an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOAuth2ProviderAppSecretByID", reflect.TypeOf((*MockStore)(nil).UpdateOAuth2ProviderAppSecretByID), arg0, arg1)
 }
 
+// UpdateOAuth2ProviderDeviceCodeLastPolledAtByID mocks base method.
+func (m *MockStore) UpdateOAuth2ProviderDeviceCodeLastPolledAtByID(arg0 context.Context, arg1 database.UpdateOAuth2ProviderDeviceCodeLastPolledAtByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOAuth2ProviderDeviceCodeLastPolledAtByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOAuth2ProviderDeviceCodeLastPolledAtByID indicates an expected call of UpdateOAuth2ProviderDeviceCodeLastPolledAtByID.
+func (mr *MockStoreMockRecorder) UpdateOAuth2ProviderDeviceCodeLastPolledAtByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOAuth2ProviderDeviceCodeLastPolledAtByID", reflect.TypeOf((*MockStore)(nil).UpdateOAuth2ProviderDeviceCodeLastPolledAtByID), arg0, arg1)
+}
+
+// UpdateOAuth2ProviderDeviceCodeStatusByID mocks base method.
+func (m *MockStore) UpdateOAuth2ProviderDeviceCodeStatusByID(arg0 context.Context, arg1 database.UpdateOAuth2ProviderDeviceCodeStatusByIDParams) (database.OAuth2ProviderDeviceCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOAuth2ProviderDeviceCodeStatusByID", arg0, arg1)
+	ret0, _ := ret[0].(database.OAuth2ProviderDeviceCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOAuth2ProviderDeviceCodeStatusByID indicates an expected call of UpdateOAuth2ProviderDeviceCodeStatusByID.
+func (mr *MockStoreMockRecorder) UpdateOAuth2ProviderDeviceCodeStatusByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOAuth2ProviderDeviceCodeStatusByID", reflect.TypeOf((*MockStore)(nil).UpdateOAuth2ProviderDeviceCodeStatusByID), arg0, arg1)
+}
+
 // UpdateOrganization mocks base method.
 func (m *MockStore) UpdateOrganization(arg0 context.Context, arg1 database.UpdateOrganizationParams) (database.Organization, error) {
 	m.ctrl.T.Helper()
@@ -4901,6 +5748,21 @@ func (mr *MockStoreMockRecorder) UpdateReplica(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReplica", reflect.TypeOf((*MockStore)(nil).UpdateReplica), arg0, arg1)
 }
 
+// UpdateSecretByID mocks base method.
+func (m *MockStore) UpdateSecretByID(arg0 context.Context, arg1 database.UpdateSecretByIDParams) (database.Secret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSecretByID", arg0, arg1)
+	ret0, _ := ret[0].(database.Secret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSecretByID indicates an expected call of UpdateSecretByID.
+func (mr *MockStoreMockRecorder) UpdateSecretByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSecretByID", reflect.TypeOf((*MockStore)(nil).UpdateSecretByID), arg0, arg1)
+}
+
 // UpdateTailnetPeerStatusByCoordinator mocks base method.
 func (m *MockStore) UpdateTailnetPeerStatusByCoordinator(arg0 context.Context, arg1 database.UpdateTailnetPeerStatusByCoordinatorParams) error {
 	m.ctrl.T.Helper()
@@ -4985,6 +5847,36 @@ func (mr *MockStoreMockRecorder) UpdateTemplateMetaByID(arg0, arg1 any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateMetaByID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateMetaByID), arg0, arg1)
 }
 
+// UpdateTemplateRolloutByID mocks base method.
+func (m *MockStore) UpdateTemplateRolloutByID(arg0 context.Context, arg1 database.UpdateTemplateRolloutByIDParams) (database.TemplateRollout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTemplateRolloutByID", arg0, arg1)
+	ret0, _ := ret[0].(database.TemplateRollout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTemplateRolloutByID indicates an expected call of UpdateTemplateRolloutByID.
+func (mr *MockStoreMockRecorder) UpdateTemplateRolloutByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateRolloutByID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateRolloutByID), arg0, arg1)
+}
+
+// UpdateTemplateRolloutStatusByID mocks base method.
+func (m *MockStore) UpdateTemplateRolloutStatusByID(arg0 context.Context, arg1 database.UpdateTemplateRolloutStatusByIDParams) (database.TemplateRollout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTemplateRolloutStatusByID", arg0, arg1)
+	ret0, _ := ret[0].(database.TemplateRollout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTemplateRolloutStatusByID indicates an expected call of UpdateTemplateRolloutStatusByID.
+func (mr *MockStoreMockRecorder) UpdateTemplateRolloutStatusByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateRolloutStatusByID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateRolloutStatusByID), arg0, arg1)
+}
+
 // UpdateTemplateScheduleByID mocks base method.
 func (m *MockStore) UpdateTemplateScheduleByID(arg0 context.Context, arg1 database.UpdateTemplateScheduleByIDParams) error {
 	m.ctrl.T.Helper()
@@ -5186,6 +6078,21 @@ func (mr *MockStoreMockRecorder) UpdateUserLoginType(arg0, arg1 any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserLoginType", reflect.TypeOf((*MockStore)(nil).UpdateUserLoginType), arg0, arg1)
 }
 
+// UpdateUserNotificationMethodPreferences mocks base method.
+func (m *MockStore) UpdateUserNotificationMethodPreferences(arg0 context.Context, arg1 database.UpdateUserNotificationMethodPreferencesParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserNotificationMethodPreferences", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserNotificationMethodPreferences indicates an expected call of UpdateUserNotificationMethodPreferences.
+func (mr *MockStoreMockRecorder) UpdateUserNotificationMethodPreferences(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserNotificationMethodPreferences", reflect.TypeOf((*MockStore)(nil).UpdateUserNotificationMethodPreferences), arg0, arg1)
+}
+
 // UpdateUserNotificationPreferences mocks base method.
 func (m *MockStore) UpdateUserNotificationPreferences(arg0 context.Context, arg1 database.UpdateUserNotificationPreferencesParams) (int64, error) {
 	m.ctrl.T.Helper()
@@ -5388,6 +6295,21 @@ func (mr *MockStoreMockRecorder) UpdateWorkspaceAutostart(arg0, arg1 any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceAutostart", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceAutostart), arg0, arg1)
 }
 
+// UpdateWorkspaceBuildApprovalByBuildID mocks base method.
+func (m *MockStore) UpdateWorkspaceBuildApprovalByBuildID(arg0 context.Context, arg1 database.UpdateWorkspaceBuildApprovalByBuildIDParams) (database.WorkspaceBuildApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceBuildApprovalByBuildID", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceBuildApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWorkspaceBuildApprovalByBuildID indicates an expected call of UpdateWorkspaceBuildApprovalByBuildID.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceBuildApprovalByBuildID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceBuildApprovalByBuildID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceBuildApprovalByBuildID), arg0, arg1)
+}
+
 // UpdateWorkspaceBuildCostByID mocks base method.
 func (m *MockStore) UpdateWorkspaceBuildCostByID(arg0 context.Context, arg1 database.UpdateWorkspaceBuildCostByIDParams) error {
 	m.ctrl.T.Helper()
@@ -5516,6 +6438,48 @@ func (mr *MockStoreMockRecorder) UpdateWorkspaceProxyDeleted(arg0, arg1 any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceProxyDeleted", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceProxyDeleted), arg0, arg1)
 }
 
+// UpdateWorkspaceScheduledActionRunByID mocks base method.
+func (m *MockStore) UpdateWorkspaceScheduledActionRunByID(arg0 context.Context, arg1 database.UpdateWorkspaceScheduledActionRunByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceScheduledActionRunByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspaceScheduledActionRunByID indicates an expected call of UpdateWorkspaceScheduledActionRunByID.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceScheduledActionRunByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceScheduledActionRunByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceScheduledActionRunByID), arg0, arg1)
+}
+
+// UpdateWorkspaceSessionRecordingByID mocks base method.
+func (m *MockStore) UpdateWorkspaceSessionRecordingByID(arg0 context.Context, arg1 database.UpdateWorkspaceSessionRecordingByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceSessionRecordingByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspaceSessionRecordingByID indicates an expected call of UpdateWorkspaceSessionRecordingByID.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceSessionRecordingByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceSessionRecordingByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceSessionRecordingByID), arg0, arg1)
+}
+
+// UpdateWorkspaceSnapshotArtifactsByID mocks base method.
+func (m *MockStore) UpdateWorkspaceSnapshotArtifactsByID(arg0 context.Context, arg1 database.UpdateWorkspaceSnapshotArtifactsByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceSnapshotArtifactsByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspaceSnapshotArtifactsByID indicates an expected call of UpdateWorkspaceSnapshotArtifactsByID.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceSnapshotArtifactsByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceSnapshotArtifactsByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceSnapshotArtifactsByID), arg0, arg1)
+}
+
 // UpdateWorkspaceTTL mocks base method.
 func (m *MockStore) UpdateWorkspaceTTL(arg0 context.Context, arg1 database.UpdateWorkspaceTTLParams) error {
 	m.ctrl.T.Helper()
@@ -5615,6 +6579,21 @@ func (mr *MockStoreMockRecorder) UpsertCoordinatorResumeTokenSigningKey(arg0, ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertCoordinatorResumeTokenSigningKey", reflect.TypeOf((*MockStore)(nil).UpsertCoordinatorResumeTokenSigningKey), arg0, arg1)
 }
 
+// UpsertCostBudget mocks base method.
+func (m *MockStore) UpsertCostBudget(arg0 context.Context, arg1 database.UpsertCostBudgetParams) (database.CostBudget, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertCostBudget", arg0, arg1)
+	ret0, _ := ret[0].(database.CostBudget)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertCostBudget indicates an expected call of UpsertCostBudget.
+func (mr *MockStoreMockRecorder) UpsertCostBudget(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertCostBudget", reflect.TypeOf((*MockStore)(nil).UpsertCostBudget), arg0, arg1)
+}
+
 // UpsertDefaultProxy mocks base method.
 func (m *MockStore) UpsertDefaultProxy(arg0 context.Context, arg1 database.UpsertDefaultProxyParams) error {
 	m.ctrl.T.Helper()
@@ -5685,6 +6664,34 @@ func (mr *MockStoreMockRecorder) UpsertLogoURL(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertLogoURL", reflect.TypeOf((*MockStore)(nil).UpsertLogoURL), arg0, arg1)
 }
 
+// UpsertNetworkConnectionReports mocks base method.
+func (m *MockStore) UpsertNetworkConnectionReports(arg0 context.Context, arg1 database.UpsertNetworkConnectionReportsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertNetworkConnectionReports", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertNetworkConnectionReports indicates an expected call of UpsertNetworkConnectionReports.
+func (mr *MockStoreMockRecorder) UpsertNetworkConnectionReports(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertNetworkConnectionReports", reflect.TypeOf((*MockStore)(nil).UpsertNetworkConnectionReports), arg0, arg1)
+}
+
+// UpsertNetworkLatencyReports mocks base method.
+func (m *MockStore) UpsertNetworkLatencyReports(arg0 context.Context, arg1 database.UpsertNetworkLatencyReportsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertNetworkLatencyReports", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertNetworkLatencyReports indicates an expected call of UpsertNetworkLatencyReports.
+func (mr *MockStoreMockRecorder) UpsertNetworkLatencyReports(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertNetworkLatencyReports", reflect.TypeOf((*MockStore)(nil).UpsertNetworkLatencyReports), arg0, arg1)
+}
+
 // UpsertNotificationReportGeneratorLog mocks base method.
 func (m *MockStore) UpsertNotificationReportGeneratorLog(arg0 context.Context, arg1 database.UpsertNotificationReportGeneratorLogParams) error {
 	m.ctrl.T.Helper()
@@ -5874,6 +6881,34 @@ func (mr *MockStoreMockRecorder) UpsertWorkspaceAgentPortShare(arg0, arg1 any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceAgentPortShare", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceAgentPortShare), arg0, arg1)
 }
 
+// UpsertWorkspaceAgentProcessGroups mocks base method.
+func (m *MockStore) UpsertWorkspaceAgentProcessGroups(arg0 context.Context, arg1 database.UpsertWorkspaceAgentProcessGroupsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceAgentProcessGroups", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertWorkspaceAgentProcessGroups indicates an expected call of UpsertWorkspaceAgentProcessGroups.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceAgentProcessGroups(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceAgentProcessGroups", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceAgentProcessGroups), arg0, arg1)
+}
+
+// UpsertWorkspaceCosts mocks base method.
+func (m *MockStore) UpsertWorkspaceCosts(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceCosts", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertWorkspaceCosts indicates an expected call of UpsertWorkspaceCosts.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceCosts(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceCosts", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceCosts), arg0)
+}
+
 // Wrappers mocks base method.
 func (m *MockStore) Wrappers() []string {
 	m.ctrl.T.Helper()
@@ -12,19 +12,26 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/quartz"
+	"github.com/coder/serpent"
 )
 
 const (
-	delay          = 10 * time.Minute
-	maxAgentLogAge = 7 * 24 * time.Hour
+	delay = 10 * time.Minute
+	// batchSize and maxBatches bound the number of rows purged from large
+	// tables on each tick, so that a large backlog is worked through over
+	// several ticks and short transactions rather than in one long-running
+	// transaction.
+	batchSize  = 10000
+	maxBatches = 10
 )
 
 // New creates a new periodically purging database instance.
 // It is the caller's responsibility to call Close on the returned instance.
 //
 // This is for cleaning up old, unused resources from the database that take up space.
-func New(ctx context.Context, logger slog.Logger, db database.Store, clk quartz.Clock) io.Closer {
+func New(ctx context.Context, logger slog.Logger, db database.Store, vals *codersdk.DeploymentValues, clk quartz.Clock) io.Closer {
 	closed := make(chan struct{})
 
 	ctx, cancelFunc := context.WithCancel(ctx)
@@ -35,6 +42,8 @@ func New(ctx context.Context, logger slog.Logger, db database.Store, clk quartz.
 	ticker := clk.NewTicker(delay)
 	doTick := func(start time.Time) {
 		defer ticker.Reset(delay)
+		var locked bool
+		c := newCutoffs(vals.Retention, start)
 		// Start a transaction to grab advisory lock, we don't want to run
 		// multiple purges at the same time (multiple replicas).
 		if err := db.InTx(func(tx database.Store) error {
@@ -48,28 +57,87 @@ func New(ctx context.Context, logger slog.Logger, db database.Store, clk quartz.
 				logger.Debug(ctx, "unable to acquire lock for purging old database entries, skipping")
 				return nil
 			}
+			locked = true
 
-			deleteOldWorkspaceAgentLogsBefore := start.Add(-maxAgentLogAge)
-			if err := tx.DeleteOldWorkspaceAgentLogs(ctx, deleteOldWorkspaceAgentLogsBefore); err != nil {
-				return xerrors.Errorf("failed to delete old workspace agent logs: %w", err)
+			if c.workspaceAgentLogs != nil {
+				if err := tx.DeleteOldWorkspaceAgentLogs(ctx, *c.workspaceAgentLogs); err != nil {
+					return xerrors.Errorf("failed to delete old workspace agent logs: %w", err)
+				}
 			}
-			if err := tx.DeleteOldWorkspaceAgentStats(ctx); err != nil {
+			// Stats which have been rolled up are deleted regardless of
+			// retention, so this always runs.
+			if err := tx.DeleteOldWorkspaceAgentStats(ctx, c.workspaceAgentStatsOrZero()); err != nil {
 				return xerrors.Errorf("failed to delete old workspace agent stats: %w", err)
 			}
 			if err := tx.DeleteOldProvisionerDaemons(ctx); err != nil {
 				return xerrors.Errorf("failed to delete old provisioner daemons: %w", err)
 			}
-			if err := tx.DeleteOldNotificationMessages(ctx); err != nil {
-				return xerrors.Errorf("failed to delete old notification messages: %w", err)
+			if err := tx.DeleteOldNetworkReports(ctx); err != nil {
+				return xerrors.Errorf("failed to delete old network reports: %w", err)
+			}
+			if c.notificationMessages != nil {
+				if err := tx.DeleteOldNotificationMessages(ctx, *c.notificationMessages); err != nil {
+					return xerrors.Errorf("failed to delete old notification messages: %w", err)
+				}
 			}
-
-			logger.Info(ctx, "purged old database entries", slog.F("duration", clk.Since(start)))
-
 			return nil
 		}, database.DefaultTXOptions().WithID("db_purge")); err != nil {
 			logger.Error(ctx, "failed to purge old database entries", slog.Error(err))
 			return
 		}
+		if !locked {
+			return
+		}
+
+		// The large tables are purged in batches, each in its own
+		// transaction, so that rows are not locked for the whole purge.
+		var (
+			auditLogs, provisionerJobLogs, workspaceBuildStates int64
+			err                                                 error
+		)
+		if c.auditLogs != nil {
+			auditLogs, err = purgeInBatches(ctx, db, func(tx database.Store) (int64, error) {
+				return tx.DeleteOldAuditLogs(ctx, database.DeleteOldAuditLogsParams{
+					BeforeTime: *c.auditLogs,
+					LimitCount: batchSize,
+				})
+			})
+			if err != nil {
+				logger.Error(ctx, "failed to delete old audit logs", slog.Error(err))
+				return
+			}
+		}
+		if c.provisionerJobLogs != nil {
+			provisionerJobLogs, err = purgeInBatches(ctx, db, func(tx database.Store) (int64, error) {
+				return tx.DeleteOldProvisionerJobLogs(ctx, database.DeleteOldProvisionerJobLogsParams{
+					BeforeTime: *c.provisionerJobLogs,
+					LimitCount: batchSize,
+				})
+			})
+			if err != nil {
+				logger.Error(ctx, "failed to delete old provisioner job logs", slog.Error(err))
+				return
+			}
+		}
+		if c.workspaceBuildStates != nil {
+			workspaceBuildStates, err = purgeInBatches(ctx, db, func(tx database.Store) (int64, error) {
+				return tx.ClearOldWorkspaceBuildProvisionerStates(ctx, database.ClearOldWorkspaceBuildProvisionerStatesParams{
+					BeforeTime: *c.workspaceBuildStates,
+					LimitCount: batchSize,
+				})
+			})
+			if err != nil {
+				logger.Error(ctx, "failed to clear old workspace build states", slog.Error(err))
+				return
+			}
+		}
+
+		logger.Info(ctx, "purged old database entries",
+			slog.F("duration", clk.Since(start)),
+			slog.F("audit_logs", auditLogs),
+			slog.F("provisioner_job_logs", provisionerJobLogs),
+			slog.F("workspace_build_states", workspaceBuildStates),
+		)
 	}
 
 	go func() {
@@ -93,6 +161,128 @@ func New(ctx context.Context, logger slog.Logger, db database.Store, clk quartz.
 	}
 }
 
+// purgeInBatches calls purge until it removes fewer than batchSize rows, or
+// maxBatches is reached. Each batch runs in its own transaction, which holds
+// the purge lock. It stops early if another replica holds the lock. It returns
+// the total number of rows purged.
+func purgeInBatches(ctx context.Context, db database.Store, purge func(tx database.Store) (int64, error)) (int64, error) {
+	var total int64
+	for i := 0; i < maxBatches; i++ {
+		var (
+			n      int64
+			locked bool
+		)
+		err := db.InTx(func(tx database.Store) error {
+			var err error
+			locked, err = tx.TryAcquireLock(ctx, database.LockIDDBPurge)
+			if err != nil || !locked {
+				return err
+			}
+			n, err = purge(tx)
+			return err
+		}, database.DefaultTXOptions().WithID("db_purge_batch"))
+		if err != nil {
+			return total, err
+		}
+		if !locked {
+			break
+		}
+		total += n
+		if n < batchSize {
+			break
+		}
+	}
+	return total, nil
+}
+
+// cutoffs holds the time before which each kind of data is purged. A nil
+// cutoff means purging is disabled.
+type cutoffs struct {
+	auditLogs            *time.Time
+	workspaceAgentLogs   *time.Time
+	provisionerJobLogs   *time.Time
+	workspaceBuildStates *time.Time
+	notificationMessages *time.Time
+	workspaceAgentStats  *time.Time
+}
+
+func newCutoffs(r codersdk.RetentionConfig, now time.Time) cutoffs {
+	before := func(d serpent.Duration) *time.Time {
+		if d.Value() <= 0 {
+			return nil
+		}
+		t := now.Add(-d.Value())
+		return &t
+	}
+	return cutoffs{
+		auditLogs:            before(r.AuditLogs),
+		workspaceAgentLogs:   before(r.WorkspaceAgentLogs),
+		provisionerJobLogs:   before(r.ProvisionerJobLogs),
+		workspaceBuildStates: before(r.WorkspaceBuildStates),
+		notificationMessages: before(r.NotificationMessages),
+		workspaceAgentStats:  before(r.WorkspaceAgentStats),
+	}
+}
+
+// workspaceAgentStatsOrZero returns the zero time if retention of workspace
+// agent stats is disabled, so that only stats which have been rolled up are
+// deleted.
+func (c cutoffs) workspaceAgentStatsOrZero() time.Time {
+	if c.workspaceAgentStats == nil {
+		return time.Time{}
+	}
+	return *c.workspaceAgentStats
+}
+
+// Report returns how many rows the next purge would remove according to the
+// retention policies in vals, without removing anything.
+func Report(ctx context.Context, db database.Store, vals *codersdk.DeploymentValues, now time.Time) (codersdk.PurgeReport, error) {
+	c := newCutoffs(vals.Retention, now)
+	orZero := func(t *time.Time) time.Time {
+		if t == nil {
+			return time.Time{}
+		}
+		return *t
+	}
+	counts, err := db.GetPurgeableRowCounts(ctx, database.GetPurgeableRowCountsParams{
+		AuditLogsBefore:            orZero(c.auditLogs),
+		WorkspaceAgentLogsBefore:   orZero(c.workspaceAgentLogs),
+		ProvisionerJobLogsBefore:   orZero(c.provisionerJobLogs),
+		WorkspaceBuildStatesBefore: orZero(c.workspaceBuildStates),
+		NotificationMessagesBefore: orZero(c.notificationMessages),
+		WorkspaceAgentStatsBefore:  c.workspaceAgentStatsOrZero(),
+	})
+	if err != nil {
+		return codersdk.PurgeReport{}, xerrors.Errorf("get purgeable row counts: %w", err)
+	}
+
+	table := func(name string, cutoff *time.Time, rows int64) codersdk.PurgeReportTable {
+		return codersdk.PurgeReportTable{
+			Name:    name,
+			Enabled: cutoff != nil,
+			Cutoff:  cutoff,
+			Rows:    rows,
+		}
+	}
+	return codersdk.PurgeReport{
+		GeneratedAt: now,
+		Tables: []codersdk.PurgeReportTable{
+			table("audit_logs", c.auditLogs, counts.AuditLogs),
+			table("workspace_agent_logs", c.workspaceAgentLogs, counts.WorkspaceAgentLogs),
+			table("provisioner_job_logs", c.provisionerJobLogs, counts.ProvisionerJobLogs),
+			table("workspace_build_states", c.workspaceBuildStates, counts.WorkspaceBuildStates),
+			table("notification_messages", c.notificationMessages, counts.NotificationMessages),
+			// Stats which have been rolled up are always purged.
+			{
+				Name:    "workspace_agent_stats",
+				Enabled: true,
+				Cutoff:  c.workspaceAgentStats,
+				Rows:    counts.WorkspaceAgentStats,
+			},
+		},
+	}, nil
+}
+
 type instance struct {
 	cancel context.CancelFunc
 	closed chan struct{}
@@ -19,6 +19,7 @@ import (
 	"cdr.dev/slog"
 	"cdr.dev/slog/sloggers/slogtest"
 
+	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/database/dbmem"
@@ -31,6 +32,7 @@ import (
 	"github.com/coder/coder/v2/provisionersdk"
 	"github.com/coder/coder/v2/testutil"
 	"github.com/coder/quartz"
+	"github.com/coder/serpent"
 )
 
 func TestMain(m *testing.M) {
@@ -47,7 +49,7 @@ func TestPurge(t *testing.T) {
 	// We want to make sure dbpurge is actually started so that this test is meaningful.
 	clk := quartz.NewMock(t)
 	done := awaitDoTick(ctx, t, clk)
-	purger := dbpurge.New(context.Background(), testutil.Logger(t), dbmem.New(), clk)
+	purger := dbpurge.New(context.Background(), testutil.Logger(t), dbmem.New(), coderdtest.DeploymentValues(t), clk)
 	<-done // wait for doTick() to run.
 	require.NoError(t, purger.Close())
 }
@@ -125,7 +127,7 @@ func TestDeleteOldWorkspaceAgentStats(t *testing.T) {
 	})
 
 	// when
-	closer := dbpurge.New(ctx, logger, db, clk)
+	closer := dbpurge.New(ctx, logger, db, coderdtest.DeploymentValues(t), clk)
 	defer closer.Close()
 
 	// then
@@ -150,7 +152,7 @@ func TestDeleteOldWorkspaceAgentStats(t *testing.T) {
 
 	// Start a new purger to immediately trigger delete after rollup.
 	_ = closer.Close()
-	closer = dbpurge.New(ctx, logger, db, clk)
+	closer = dbpurge.New(ctx, logger, db, coderdtest.DeploymentValues(t), clk)
 	defer closer.Close()
 
 	// then
@@ -241,7 +243,7 @@ func TestDeleteOldWorkspaceAgentLogs(t *testing.T) {
 	// After dbpurge completes, the ticker is reset. Trap this call.
 
 	done := awaitDoTick(ctx, t, clk)
-	closer := dbpurge.New(ctx, logger, db, clk)
+	closer := dbpurge.New(ctx, logger, db, coderdtest.DeploymentValues(t), clk)
 	defer closer.Close()
 	<-done // doTick() has now run.
 
@@ -462,7 +464,7 @@ func TestDeleteOldProvisionerDaemons(t *testing.T) {
 	require.NoError(t, err)
 
 	// when
-	closer := dbpurge.New(ctx, logger, db, clk)
+	closer := dbpurge.New(ctx, logger, db, coderdtest.DeploymentValues(t), clk)
 	defer closer.Close()
 
 	// then
@@ -490,3 +492,178 @@ func containsProvisionerDaemon(daemons []database.ProvisionerDaemon, name string
 		return d.Name == name
 	})
 }
+
+//nolint:paralleltest // It uses LockIDDBPurge.
+func TestDeleteOldAuditLogs(t *testing.T) {
+	ctx := testutil.Context(t, testutil.WaitShort)
+	clk := quartz.NewMock(t)
+	now := dbtime.Now()
+	clk.Set(now).MustWait(ctx)
+
+	db, _ := dbtestutil.NewDB(t, dbtestutil.WithDumpOnFailure())
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+	vals := coderdtest.DeploymentValues(t, func(vals *codersdk.DeploymentValues) {
+		vals.Retention.AuditLogs = serpent.Duration(30 * 24 * time.Hour)
+	})
+
+	// given
+	oldLog := dbgen.AuditLog(t, db, database.AuditLog{Time: now.AddDate(0, 0, -31)})
+	newLog := dbgen.AuditLog(t, db, database.AuditLog{Time: now.AddDate(0, 0, -29)})
+
+	report, err := dbpurge.Report(ctx, db, vals, now)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), reportRows(t, report, "audit_logs"))
+
+	// when
+	done := awaitDoTick(ctx, t, clk)
+	closer := dbpurge.New(ctx, logger, db, vals, clk)
+	defer closer.Close()
+	<-done // doTick() has now run.
+
+	// then
+	logs, err := db.GetAuditLogsOffset(ctx, database.GetAuditLogsOffsetParams{})
+	require.NoError(t, err)
+	ids := make([]uuid.UUID, 0, len(logs))
+	for _, l := range logs {
+		ids = append(ids, l.AuditLog.ID)
+	}
+	require.NotContains(t, ids, oldLog.ID)
+	require.Contains(t, ids, newLog.ID)
+
+	report, err = dbpurge.Report(ctx, db, vals, now)
+	require.NoError(t, err)
+	require.Zero(t, reportRows(t, report, "audit_logs"))
+}
+
+//nolint:paralleltest // It uses LockIDDBPurge.
+func TestDeleteOldProvisionerJobLogsAndBuildStates(t *testing.T) {
+	ctx := testutil.Context(t, testutil.WaitShort)
+	clk := quartz.NewMock(t)
+	now := dbtime.Now()
+	threshold := now.AddDate(0, 0, -30)
+	beforeThreshold := threshold.Add(-24 * time.Hour)
+	afterThreshold := threshold.Add(24 * time.Hour)
+	clk.Set(now).MustWait(ctx)
+
+	db, _ := dbtestutil.NewDB(t, dbtestutil.WithDumpOnFailure())
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+	vals := coderdtest.DeploymentValues(t, func(vals *codersdk.DeploymentValues) {
+		vals.Retention.ProvisionerJobLogs = serpent.Duration(30 * 24 * time.Hour)
+		vals.Retention.WorkspaceBuildStates = serpent.Duration(30 * 24 * time.Hour)
+	})
+
+	org := dbgen.Organization(t, db, database.Organization{})
+	user := dbgen.User(t, db, database.User{})
+	_ = dbgen.OrganizationMember(t, db, database.OrganizationMember{UserID: user.ID, OrganizationID: org.ID})
+	tv := dbgen.TemplateVersion(t, db, database.TemplateVersion{OrganizationID: org.ID, CreatedBy: user.ID})
+	tmpl := dbgen.Template(t, db, database.Template{OrganizationID: org.ID, ActiveVersionID: tv.ID, CreatedBy: user.ID})
+
+	// given
+	// Workspace A was built twice before the threshold.
+	wsA := dbgen.Workspace(t, db, database.WorkspaceTable{Name: "a", OwnerID: user.ID, OrganizationID: org.ID, TemplateID: tmpl.ID})
+	wbA1 := mustCreateWorkspaceBuild(t, db, org, tv, wsA.ID, beforeThreshold, 1)
+	wbA2 := mustCreateWorkspaceBuild(t, db, org, tv, wsA.ID, beforeThreshold, 2)
+	// Workspace B was built once before the threshold, and once after.
+	wsB := dbgen.Workspace(t, db, database.WorkspaceTable{Name: "b", OwnerID: user.ID, OrganizationID: org.ID, TemplateID: tmpl.ID})
+	wbB1 := mustCreateWorkspaceBuild(t, db, org, tv, wsB.ID, beforeThreshold, 1)
+	wbB2 := mustCreateWorkspaceBuild(t, db, org, tv, wsB.ID, afterThreshold, 2)
+	for _, wb := range []database.WorkspaceBuild{wbA1, wbA2, wbB1, wbB2} {
+		mustCreateProvisionerJobLogs(ctx, t, db, wb.JobID, wb.CreatedAt)
+		require.NoError(t, db.UpdateWorkspaceBuildProvisionerStateByID(ctx, database.UpdateWorkspaceBuildProvisionerStateByIDParams{
+			ID:               wb.ID,
+			ProvisionerState: []byte("state"),
+			UpdatedAt:        wb.CreatedAt,
+		}))
+	}
+
+	report, err := dbpurge.Report(ctx, db, vals, now)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), reportRows(t, report, "provisioner_job_logs"))
+	require.Equal(t, int64(2), reportRows(t, report, "workspace_build_states"))
+	require.Zero(t, reportRows(t, report, "audit_logs"))
+
+	// when
+	done := awaitDoTick(ctx, t, clk)
+	closer := dbpurge.New(ctx, logger, db, vals, clk)
+	defer closer.Close()
+	<-done // doTick() has now run.
+
+	// then builds A1 and B1 are neither the latest build nor after the
+	// threshold, so their logs and state are purged.
+	for _, wb := range []database.WorkspaceBuild{wbA1, wbB1} {
+		logs, err := db.GetProvisionerLogsAfterID(ctx, database.GetProvisionerLogsAfterIDParams{JobID: wb.JobID})
+		require.NoError(t, err)
+		assert.Empty(t, logs)
+		build, err := db.GetWorkspaceBuildByID(ctx, wb.ID)
+		require.NoError(t, err)
+		assert.Nil(t, build.ProvisionerState)
+	}
+	// then builds A2 and B2 are the latest builds, so they are retained.
+	for _, wb := range []database.WorkspaceBuild{wbA2, wbB2} {
+		logs, err := db.GetProvisionerLogsAfterID(ctx, database.GetProvisionerLogsAfterIDParams{JobID: wb.JobID})
+		require.NoError(t, err)
+		assert.NotEmpty(t, logs)
+		build, err := db.GetWorkspaceBuildByID(ctx, wb.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("state"), build.ProvisionerState)
+	}
+}
+
+//nolint:paralleltest // It uses LockIDDBPurge.
+func TestRetentionDisabled(t *testing.T) {
+	ctx := testutil.Context(t, testutil.WaitShort)
+	clk := quartz.NewMock(t)
+	now := dbtime.Now()
+	clk.Set(now).MustWait(ctx)
+
+	db, _ := dbtestutil.NewDB(t, dbtestutil.WithDumpOnFailure())
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+	// Audit logs are kept forever by default.
+	vals := coderdtest.DeploymentValues(t)
+
+	alog := dbgen.AuditLog(t, db, database.AuditLog{Time: now.AddDate(-5, 0, 0)})
+
+	report, err := dbpurge.Report(ctx, db, vals, now)
+	require.NoError(t, err)
+	for _, table := range report.Tables {
+		if table.Name == "audit_logs" {
+			require.False(t, table.Enabled)
+			require.Nil(t, table.Cutoff)
+		}
+	}
+	require.Zero(t, reportRows(t, report, "audit_logs"))
+
+	done := awaitDoTick(ctx, t, clk)
+	closer := dbpurge.New(ctx, logger, db, vals, clk)
+	defer closer.Close()
+	<-done // doTick() has now run.
+
+	logs, err := db.GetAuditLogsOffset(ctx, database.GetAuditLogsOffsetParams{})
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	require.Equal(t, alog.ID, logs[0].AuditLog.ID)
+}
+
+func reportRows(t *testing.T, report codersdk.PurgeReport, name string) int64 {
+	t.Helper()
+	for _, table := range report.Tables {
+		if table.Name == name {
+			return table.Rows
+		}
+	}
+	t.Fatalf("table %q not found in purge report", name)
+	return 0
+}
+
+func mustCreateProvisionerJobLogs(ctx context.Context, t *testing.T, db database.Store, jobID uuid.UUID, createdAt time.Time) {
+	t.Helper()
+	_, err := db.InsertProvisionerJobLogs(ctx, database.InsertProvisionerJobLogsParams{
+		JobID:     jobID,
+		CreatedAt: []time.Time{createdAt},
+		Source:    []database.LogSource{database.LogSourceProvisioner},
+		Level:     []database.LogLevel{database.LogLevelInfo},
+		Stage:     []string{"Planning"},
+		Output:    []string{"log line"},
+	})
+	require.NoError(t, err)
+}
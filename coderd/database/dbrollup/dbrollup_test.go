@@ -261,3 +261,55 @@ func TestRollupTemplateUsageStats(t *testing.T) {
 		},
 	}, stats[0])
 }
+
+func TestRollupWorkspaceCosts(t *testing.T) {
+	t.Parallel()
+
+	db, ps := dbtestutil.NewDB(t, dbtestutil.WithDumpOnFailure())
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug)
+
+	now := dbtime.Now()
+	started := now.Add(-90 * time.Minute)
+
+	var (
+		org  = dbgen.Organization(t, db, database.Organization{})
+		user = dbgen.User(t, db, database.User{Name: "user1"})
+		tpl  = dbgen.Template(t, db, database.Template{OrganizationID: org.ID, CreatedBy: user.ID})
+		ver  = dbgen.TemplateVersion(t, db, database.TemplateVersion{OrganizationID: org.ID, TemplateID: uuid.NullUUID{UUID: tpl.ID, Valid: true}, CreatedBy: user.ID})
+		ws   = dbgen.Workspace(t, db, database.WorkspaceTable{OrganizationID: org.ID, TemplateID: tpl.ID, OwnerID: user.ID})
+		job  = dbgen.ProvisionerJob(t, db, ps, database.ProvisionerJob{
+			OrganizationID: org.ID,
+			StartedAt:      sql.NullTime{Time: started.Add(-time.Minute), Valid: true},
+			CompletedAt:    sql.NullTime{Time: started, Valid: true},
+		})
+		build = dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: job.ID, TemplateVersionID: ver.ID})
+	)
+	// The costs of the resources of a build add up.
+	_ = dbgen.WorkspaceResource(t, db, database.WorkspaceResource{JobID: build.JobID, HourlyCost: 1.5})
+	_ = dbgen.WorkspaceResource(t, db, database.WorkspaceResource{JobID: build.JobID, HourlyCost: 0.5})
+
+	events := make(chan dbrollup.Event, 1)
+	rolluper := dbrollup.New(logger, db, dbrollup.WithInterval(250*time.Millisecond), dbrollup.WithEventChannel(events))
+	defer rolluper.Close()
+
+	<-events // Deplete init event, resume operation.
+
+	ctx := testutil.Context(t, testutil.WaitMedium)
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for rollup to occur")
+	case ev := <-events:
+		require.True(t, ev.WorkspaceCosts, "expected workspace costs to be rolled up")
+	}
+
+	rows, err := db.GetWorkspaceCostInsightsByTemplate(ctx, database.GetWorkspaceCostInsightsByTemplateParams{
+		StartTime: started.Add(-time.Hour),
+		EndTime:   now.Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	require.Equal(t, tpl.ID, rows[0].TemplateID)
+	// The workspace has been running for 1.5 hours at 2 per hour.
+	require.InDelta(t, 3, rows[0].Cost, 0.1)
+}
@@ -7,7 +7,8 @@ CREATE TYPE agent_id_name_pair AS (
 
 CREATE TYPE api_key_scope AS ENUM (
     'all',
-    'application_connect'
+    'application_connect',
+    'custom'
 );
 
 CREATE TYPE app_sharing_level AS ENUM (
@@ -25,7 +26,8 @@ CREATE TYPE audit_action AS ENUM (
     'login',
     'logout',
     'register',
-    'request_password_reset'
+    'request_password_reset',
+    'open'
 );
 
 CREATE TYPE automatic_updates AS ENUM (
@@ -39,14 +41,16 @@ CREATE TYPE build_reason AS ENUM (
     'autostop',
     'dormancy',
     'failedstop',
-    'autodelete'
+    'autodelete',
+    'scheduled'
 );
 
 CREATE TYPE crypto_key_feature AS ENUM (
     'workspace_apps_token',
     'workspace_apps_api_key',
     'oidc_convert',
-    'tailnet_resume'
+    'tailnet_resume',
+    'workload_identity'
 );
 
 CREATE TYPE display_app AS ENUM (
@@ -59,7 +63,8 @@ CREATE TYPE display_app AS ENUM (
 
 CREATE TYPE group_source AS ENUM (
     'user',
-    'oidc'
+    'oidc',
+    'scim'
 );
 
 CREATE TYPE log_level AS ENUM (
@@ -103,13 +108,21 @@ CREATE TYPE notification_message_status AS ENUM (
 
 CREATE TYPE notification_method AS ENUM (
     'smtp',
-    'webhook'
+    'webhook',
+    'slack',
+    'teams'
 );
 
 CREATE TYPE notification_template_kind AS ENUM (
     'system'
 );
 
+CREATE TYPE oauth2_provider_device_code_status AS ENUM (
+    'pending',
+    'approved',
+    'denied'
+);
+
 CREATE TYPE parameter_destination_scheme AS ENUM (
     'none',
     'environment_variable',
@@ -145,6 +158,13 @@ CREATE TYPE provisioner_daemon_status AS ENUM (
 
 COMMENT ON TYPE provisioner_daemon_status IS 'The status of a provisioner daemon.';
 
+CREATE TYPE provisioner_job_resource_change_action AS ENUM (
+    'create',
+    'update',
+    'replace',
+    'delete'
+);
+
 CREATE TYPE provisioner_job_status AS ENUM (
     'pending',
     'running',
@@ -201,7 +221,9 @@ CREATE TYPE resource_type AS ENUM (
     'notification_template',
     'idp_sync_settings_organization',
     'idp_sync_settings_group',
-    'idp_sync_settings_role'
+    'idp_sync_settings_role',
+    'workspace_session_recording',
+    'secret'
 );
 
 CREATE TYPE startup_script_behavior AS ENUM (
@@ -218,6 +240,13 @@ CREATE TYPE tailnet_status AS ENUM (
     'lost'
 );
 
+CREATE TYPE template_rollout_status AS ENUM (
+    'in_progress',
+    'promoted',
+    'rolled_back',
+    'canceled'
+);
+
 CREATE TYPE user_status AS ENUM (
     'active',
     'suspended',
@@ -275,6 +304,24 @@ CREATE TYPE workspace_app_open_in AS ENUM (
     'slim-window'
 );
 
+CREATE TYPE workspace_build_approval_status AS ENUM (
+    'pending',
+    'approved',
+    'rejected'
+);
+
+CREATE TYPE workspace_scheduled_action_type AS ENUM (
+    'start',
+    'stop',
+    'restart',
+    'update'
+);
+
+CREATE TYPE workspace_session_recording_type AS ENUM (
+    'ssh',
+    'reconnecting_pty'
+);
+
 CREATE TYPE workspace_transition AS ENUM (
     'start',
     'stop',
@@ -616,11 +663,17 @@ CREATE TABLE api_keys (
     lifetime_seconds bigint DEFAULT 86400 NOT NULL,
     ip_address inet DEFAULT '0.0.0.0'::inet NOT NULL,
     scope api_key_scope DEFAULT 'all'::api_key_scope NOT NULL,
-    token_name text DEFAULT ''::text NOT NULL
+    token_name text DEFAULT ''::text NOT NULL,
+    scope_permissions jsonb DEFAULT '[]'::jsonb NOT NULL,
+    scope_allow_list text[] DEFAULT '{}'::text[] NOT NULL
 );
 
 COMMENT ON COLUMN api_keys.hashed_secret IS 'hashed_secret contains a SHA256 hash of the key secret. This is considered a secret and MUST NOT be returned from the API as it is used for API key encryption in app proxying code.';
 
+COMMENT ON COLUMN api_keys.scope_permissions IS 'The resource types and actions a key with the custom scope is limited to, optionally within one organization.';
+
+COMMENT ON COLUMN api_keys.scope_allow_list IS 'The IDs of the resources a key with the custom scope is limited to. An empty list allows all resources.';
+
 CREATE TABLE audit_logs (
     id uuid NOT NULL,
     "time" timestamp with time zone NOT NULL,
@@ -639,6 +692,31 @@ CREATE TABLE audit_logs (
     resource_icon text NOT NULL
 );
 
+CREATE TABLE cost_budgets (
+    group_id uuid NOT NULL,
+    organization_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    monthly_limit double precision NOT NULL,
+    notify_threshold_percent integer NOT NULL,
+    stop_threshold_percent integer NOT NULL,
+    notified_at timestamp with time zone,
+    stopped_at timestamp with time zone,
+    CONSTRAINT cost_budgets_monthly_limit_check CHECK ((monthly_limit > (0)::double precision)),
+    CONSTRAINT cost_budgets_notify_threshold_percent_check CHECK ((notify_threshold_percent >= 0)),
+    CONSTRAINT cost_budgets_stop_threshold_percent_check CHECK ((stop_threshold_percent >= 0))
+);
+
+COMMENT ON TABLE cost_budgets IS 'Monthly limits on the cost of the workspaces owned by the members of a group.';
+
+COMMENT ON COLUMN cost_budgets.notify_threshold_percent IS 'Members are notified when the cost of their workspaces reaches this percentage of the monthly limit. 0 disables notifications.';
+
+COMMENT ON COLUMN cost_budgets.stop_threshold_percent IS 'Running workspaces of members are stopped when the cost of their workspaces reaches this percentage of the monthly limit. 0 disables stopping workspaces.';
+
+COMMENT ON COLUMN cost_budgets.notified_at IS 'The last time members were notified that the notify threshold was reached.';
+
+COMMENT ON COLUMN cost_budgets.stopped_at IS 'The last time members were notified that the stop threshold was reached.';
+
 CREATE TABLE crypto_keys (
     feature crypto_key_feature NOT NULL,
     sequence integer NOT NULL,
@@ -672,7 +750,9 @@ CREATE TABLE dbcrypt_keys (
     revoked_key_digest text,
     created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP,
     revoked_at timestamp with time zone,
-    test text NOT NULL
+    test text NOT NULL,
+    key_provider text,
+    wrapped_key text
 );
 
 COMMENT ON TABLE dbcrypt_keys IS 'A table used to store the keys used to encrypt the database.';
@@ -689,6 +769,10 @@ COMMENT ON COLUMN dbcrypt_keys.revoked_at IS 'The time at which the key was revo
 
 COMMENT ON COLUMN dbcrypt_keys.test IS 'A column used to test the encryption.';
 
+COMMENT ON COLUMN dbcrypt_keys.key_provider IS 'The external key provider that wrapped the key, e.g. vault-transit:transit/coder. Null if the key was provided to Coder directly.';
+
+COMMENT ON COLUMN dbcrypt_keys.wrapped_key IS 'The key, encrypted by the key provider. Null if the key was provided to Coder directly.';
+
 CREATE TABLE external_auth_links (
     provider_id text NOT NULL,
     user_id uuid NOT NULL,
@@ -849,6 +933,36 @@ CREATE SEQUENCE licenses_id_seq
 
 ALTER SEQUENCE licenses_id_seq OWNED BY licenses.id;
 
+CREATE TABLE network_connection_reports (
+    client_id uuid NOT NULL,
+    remote_node_id bigint NOT NULL,
+    client_type text NOT NULL,
+    home_region_id integer NOT NULL,
+    direct boolean NOT NULL,
+    reported_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE network_connection_reports IS 'The latest type of the connections between tailnet clients and their peers.';
+
+COMMENT ON COLUMN network_connection_reports.home_region_id IS 'The home DERP region of the client when the connection was reported.';
+
+COMMENT ON COLUMN network_connection_reports.direct IS 'Whether the connection is direct (peer-to-peer), rather than relayed through DERP.';
+
+CREATE TABLE network_latency_reports (
+    client_id uuid NOT NULL,
+    client_type text NOT NULL,
+    region_id integer NOT NULL,
+    latency_ms double precision NOT NULL,
+    preferred boolean NOT NULL,
+    reported_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE network_latency_reports IS 'The latest latency to each DERP region reported by tailnet clients, like agents and the CLI.';
+
+COMMENT ON COLUMN network_latency_reports.client_id IS 'The ID of the tailnet connection of the client.';
+
+COMMENT ON COLUMN network_latency_reports.preferred IS 'Whether the region is the preferred (home) region of the client.';
+
 CREATE TABLE notification_messages (
     id uuid NOT NULL,
     notification_template_id uuid NOT NULL,
@@ -875,9 +989,12 @@ CREATE TABLE notification_preferences (
     notification_template_id uuid NOT NULL,
     disabled boolean DEFAULT false NOT NULL,
     created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
-    updated_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+    updated_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    method notification_method
 );
 
+COMMENT ON COLUMN notification_preferences.method IS 'NULL defers to the template-level or deployment-level method';
+
 CREATE TABLE notification_report_generator_logs (
     notification_template_id uuid NOT NULL,
     last_generated_at timestamp with time zone NOT NULL
@@ -908,11 +1025,14 @@ CREATE TABLE oauth2_provider_app_codes (
     secret_prefix bytea NOT NULL,
     hashed_secret bytea NOT NULL,
     user_id uuid NOT NULL,
-    app_id uuid NOT NULL
+    app_id uuid NOT NULL,
+    code_challenge text DEFAULT ''::text NOT NULL
 );
 
 COMMENT ON TABLE oauth2_provider_app_codes IS 'Codes are meant to be exchanged for access tokens.';
 
+COMMENT ON COLUMN oauth2_provider_app_codes.code_challenge IS 'The S256 PKCE code challenge the code verifier must match when the code is exchanged. Empty if PKCE was not used.';
+
 CREATE TABLE oauth2_provider_app_secrets (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -931,8 +1051,9 @@ CREATE TABLE oauth2_provider_app_tokens (
     expires_at timestamp with time zone NOT NULL,
     hash_prefix bytea NOT NULL,
     refresh_hash bytea NOT NULL,
-    app_secret_id uuid NOT NULL,
-    api_key_id text NOT NULL
+    app_secret_id uuid,
+    api_key_id text NOT NULL,
+    app_id uuid NOT NULL
 );
 
 COMMENT ON COLUMN oauth2_provider_app_tokens.refresh_hash IS 'Refresh tokens provide a way to refresh an access token (API key). An expired API key can be refreshed if this token is not yet expired, meaning this expiry can outlive an API key.';
@@ -943,11 +1064,36 @@ CREATE TABLE oauth2_provider_apps (
     updated_at timestamp with time zone NOT NULL,
     name character varying(64) NOT NULL,
     icon character varying(256) NOT NULL,
-    callback_url text NOT NULL
+    callback_url text NOT NULL,
+    public_client boolean DEFAULT false NOT NULL,
+    client_credentials_user_id uuid
 );
 
 COMMENT ON TABLE oauth2_provider_apps IS 'A table used to configure apps that can use Coder as an OAuth2 provider, the reverse of what we are calling external authentication.';
 
+COMMENT ON COLUMN oauth2_provider_apps.public_client IS 'Public clients cannot keep a secret, so they authenticate with PKCE instead of a client secret.';
+
+COMMENT ON COLUMN oauth2_provider_apps.client_credentials_user_id IS 'The user that tokens issued with the client credentials grant act as. The grant is disabled when null.';
+
+CREATE TABLE oauth2_provider_device_codes (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone NOT NULL,
+    secret_prefix bytea NOT NULL,
+    hashed_secret bytea NOT NULL,
+    user_code text NOT NULL,
+    app_id uuid NOT NULL,
+    status oauth2_provider_device_code_status DEFAULT 'pending'::oauth2_provider_device_code_status NOT NULL,
+    user_id uuid,
+    last_polled_at timestamp with time zone
+);
+
+COMMENT ON TABLE oauth2_provider_device_codes IS 'Device codes are exchanged for access tokens once the user enters the user code and approves the device, see RFC 8628.';
+
+COMMENT ON COLUMN oauth2_provider_device_codes.user_id IS 'The user that approved or denied the device.';
+
+COMMENT ON COLUMN oauth2_provider_device_codes.last_polled_at IS 'The last time the device polled the token endpoint, used to tell devices polling too often to slow down.';
+
 CREATE TABLE organizations (
     id uuid NOT NULL,
     name text NOT NULL,
@@ -1026,6 +1172,19 @@ CREATE SEQUENCE provisioner_job_logs_id_seq
 
 ALTER SEQUENCE provisioner_job_logs_id_seq OWNED BY provisioner_job_logs.id;
 
+CREATE TABLE provisioner_job_resource_changes (
+    job_id uuid NOT NULL,
+    address text NOT NULL,
+    resource_type text NOT NULL,
+    resource_name text NOT NULL,
+    action provisioner_job_resource_change_action NOT NULL,
+    attributes jsonb DEFAULT '[]'::jsonb NOT NULL
+);
+
+COMMENT ON TABLE provisioner_job_resource_changes IS 'The changes the plan of a provisioner job makes to each resource. Resources that do not change are not stored.';
+
+COMMENT ON COLUMN provisioner_job_resource_changes.attributes IS 'The changed attributes of the resource. Values of sensitive attributes are never stored.';
+
 CREATE VIEW provisioner_job_stats AS
 SELECT
     NULL::uuid AS job_id,
@@ -1087,11 +1246,14 @@ CASE
         WHEN (started_at IS NULL) THEN 'pending'::provisioner_job_status
         ELSE 'running'::provisioner_job_status
     END
-END) STORED NOT NULL
+END) STORED NOT NULL,
+    priority integer DEFAULT 0 NOT NULL
 );
 
 COMMENT ON COLUMN provisioner_jobs.job_status IS 'Computed column to track the status of the job.';
 
+COMMENT ON COLUMN provisioner_jobs.priority IS 'Pending jobs with a higher priority are acquired first. Workspace builds started by users have the highest priority, followed by automatic workspace builds, then template version imports and dry-runs.';
+
 CREATE TABLE provisioner_keys (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -1116,6 +1278,31 @@ CREATE TABLE replicas (
     "primary" boolean DEFAULT true NOT NULL
 );
 
+CREATE TABLE secrets (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    name text NOT NULL,
+    description text DEFAULT ''::text NOT NULL,
+    value text NOT NULL,
+    value_key_id text,
+    env_name text DEFAULT ''::text NOT NULL,
+    file_path text DEFAULT ''::text NOT NULL,
+    user_id uuid,
+    organization_id uuid,
+    template_id uuid,
+    CONSTRAINT secrets_scope CHECK ((num_nonnulls(user_id, organization_id, template_id) = 1)),
+    CONSTRAINT secrets_target CHECK (((env_name <> ''::text) OR (file_path <> ''::text)))
+);
+
+COMMENT ON TABLE secrets IS 'Secrets that are injected into workspaces by the agent. A secret belongs to exactly one user, organization or template.';
+
+COMMENT ON COLUMN secrets.value_key_id IS 'The ID of the key used to encrypt the value. If this is NULL, the value is not encrypted';
+
+COMMENT ON COLUMN secrets.env_name IS 'The name of the environment variable the secret is injected as, or empty.';
+
+COMMENT ON COLUMN secrets.file_path IS 'The path of the file the secret is written to in the workspace, or empty.';
+
 CREATE TABLE site_configs (
     key character varying(256) NOT NULL,
     value text NOT NULL
@@ -1164,6 +1351,40 @@ CREATE TABLE tailnet_tunnels (
     updated_at timestamp with time zone NOT NULL
 );
 
+CREATE TABLE template_rollouts (
+    id uuid NOT NULL,
+    template_id uuid NOT NULL,
+    template_version_id uuid NOT NULL,
+    created_by uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    completed_at timestamp with time zone,
+    status template_rollout_status DEFAULT 'in_progress'::template_rollout_status NOT NULL,
+    status_reason text DEFAULT ''::text NOT NULL,
+    percent integer NOT NULL,
+    group_id uuid,
+    min_builds integer NOT NULL,
+    max_failure_rate double precision NOT NULL,
+    auto_promote boolean DEFAULT false NOT NULL,
+    CONSTRAINT template_rollouts_max_failure_rate_check CHECK (((max_failure_rate >= (0)::double precision) AND (max_failure_rate <= (1)::double precision))),
+    CONSTRAINT template_rollouts_min_builds_check CHECK ((min_builds > 0)),
+    CONSTRAINT template_rollouts_percent_check CHECK (((percent >= 0) AND (percent <= 100)))
+);
+
+COMMENT ON TABLE template_rollouts IS 'Staged rollouts of template versions. Workspaces included in a rollout in progress build its candidate version instead of the active version of the template.';
+
+COMMENT ON COLUMN template_rollouts.template_version_id IS 'The candidate version that is rolled out.';
+
+COMMENT ON COLUMN template_rollouts.percent IS 'The percentage of workspaces of the template that are included in the rollout.';
+
+COMMENT ON COLUMN template_rollouts.group_id IS 'Workspaces owned by members of this group are included in the rollout regardless of percent.';
+
+COMMENT ON COLUMN template_rollouts.min_builds IS 'The number of completed builds of the candidate version required before the rollout is promoted or rolled back automatically.';
+
+COMMENT ON COLUMN template_rollouts.max_failure_rate IS 'The rollout is rolled back when the share of failed builds of the candidate version exceeds this rate.';
+
+COMMENT ON COLUMN template_rollouts.auto_promote IS 'Whether the candidate version is promoted automatically once min_builds builds completed within max_failure_rate.';
+
 CREATE TABLE template_usage_stats (
     start_time timestamp with time zone NOT NULL,
     end_time timestamp with time zone NOT NULL,
@@ -1366,7 +1587,12 @@ CREATE TABLE templates (
     require_active_version boolean DEFAULT false NOT NULL,
     deprecated text DEFAULT ''::text NOT NULL,
     activity_bump bigint DEFAULT '3600000000000'::bigint NOT NULL,
-    max_port_sharing_level app_sharing_level DEFAULT 'owner'::app_sharing_level NOT NULL
+    max_port_sharing_level app_sharing_level DEFAULT 'owner'::app_sharing_level NOT NULL,
+    session_recording_enabled boolean DEFAULT false NOT NULL,
+    require_build_approval boolean DEFAULT false NOT NULL,
+    build_approval_parameters text[] DEFAULT '{}'::text[] NOT NULL,
+    build_approval_group_id uuid,
+    build_approval_timeout bigint DEFAULT '86400000000000'::bigint NOT NULL
 );
 
 COMMENT ON COLUMN templates.default_ttl IS 'The default duration for autostop for workspaces created from this template.';
@@ -1387,6 +1613,16 @@ COMMENT ON COLUMN templates.autostart_block_days_of_week IS 'A bitmap of days of
 
 COMMENT ON COLUMN templates.deprecated IS 'If set to a non empty string, the template will no longer be able to be used. The message will be displayed to the user.';
 
+COMMENT ON COLUMN templates.session_recording_enabled IS 'Record the output of interactive SSH and reconnecting PTY sessions in workspaces created from this template.';
+
+COMMENT ON COLUMN templates.require_build_approval IS 'Workspace start builds must be approved before they are queued for provisioners.';
+
+COMMENT ON COLUMN templates.build_approval_parameters IS 'If not empty, only builds that change the value of one of these parameters require approval.';
+
+COMMENT ON COLUMN templates.build_approval_group_id IS 'The group whose members can approve builds. Template admins can always approve builds.';
+
+COMMENT ON COLUMN templates.build_approval_timeout IS 'Builds that are not approved within this duration are rejected. 0 means builds never time out.';
+
 CREATE VIEW template_with_names AS
  SELECT templates.id,
     templates.created_at,
@@ -1416,6 +1652,11 @@ CREATE VIEW template_with_names AS
     templates.deprecated,
     templates.activity_bump,
     templates.max_port_sharing_level,
+    templates.session_recording_enabled,
+    templates.require_build_approval,
+    templates.build_approval_parameters,
+    templates.build_approval_group_id,
+    templates.build_approval_timeout,
     COALESCE(visible_users.avatar_url, ''::text) AS created_by_avatar_url,
     COALESCE(visible_users.username, ''::text) AS created_by_username,
     COALESCE(organizations.name, ''::text) AS organization_name,
@@ -1502,6 +1743,26 @@ CREATE TABLE workspace_agent_port_share (
     protocol port_share_protocol DEFAULT 'http'::port_share_protocol NOT NULL
 );
 
+CREATE UNLOGGED TABLE workspace_agent_process_groups (
+    workspace_agent_id uuid NOT NULL,
+    name text NOT NULL,
+    cpu_usage_usec bigint NOT NULL,
+    cpu_cores double precision NOT NULL,
+    cpu_limit_cores double precision NOT NULL,
+    memory_bytes bigint NOT NULL,
+    memory_limit_bytes bigint NOT NULL,
+    oom_kills bigint NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_process_groups IS 'The latest resource usage of the cgroups that agents place their SSH sessions and scripts in.';
+
+COMMENT ON COLUMN workspace_agent_process_groups.cpu_cores IS 'The average number of CPUs used since the previous report of the agent.';
+
+COMMENT ON COLUMN workspace_agent_process_groups.cpu_limit_cores IS 'The number of CPUs the group may use, or 0 if unlimited.';
+
+COMMENT ON COLUMN workspace_agent_process_groups.memory_limit_bytes IS 'The memory limit of the group, or 0 if unlimited.';
+
 CREATE TABLE workspace_agent_script_timings (
     script_id uuid NOT NULL,
     started_at timestamp with time zone NOT NULL,
@@ -1683,6 +1944,23 @@ COMMENT ON COLUMN workspace_apps.display_order IS 'Specifies the order in which
 
 COMMENT ON COLUMN workspace_apps.hidden IS 'Determines if the app is not shown in user interfaces.';
 
+CREATE TABLE workspace_build_approvals (
+    workspace_build_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone,
+    status workspace_build_approval_status DEFAULT 'pending'::workspace_build_approval_status NOT NULL,
+    reviewer_id uuid,
+    reviewed_at timestamp with time zone,
+    reason text DEFAULT ''::text NOT NULL
+);
+
+COMMENT ON TABLE workspace_build_approvals IS 'Workspace builds that require approval before their provisioner job can be acquired.';
+
+COMMENT ON COLUMN workspace_build_approvals.expires_at IS 'Pending approvals are rejected after this time. NULL means the approval never expires.';
+
+COMMENT ON COLUMN workspace_build_approvals.reviewer_id IS 'The user that approved or rejected the build. NULL if the build is pending or timed out.';
+
 CREATE TABLE workspace_build_parameters (
     workspace_build_id uuid NOT NULL,
     name text NOT NULL,
@@ -1732,6 +2010,19 @@ CREATE VIEW workspace_build_with_user AS
 
 COMMENT ON VIEW workspace_build_with_user IS 'Joins in the username + avatar url of the initiated by user.';
 
+CREATE TABLE workspace_costs (
+    bucket_start timestamp with time zone NOT NULL,
+    workspace_id uuid NOT NULL,
+    owner_id uuid NOT NULL,
+    template_id uuid NOT NULL,
+    organization_id uuid NOT NULL,
+    cost double precision NOT NULL
+);
+
+COMMENT ON TABLE workspace_costs IS 'Records the cost of workspaces in hourly buckets, rolled up from the hourly cost of the resources of their builds.';
+
+COMMENT ON COLUMN workspace_costs.bucket_start IS 'Start time of the hour the cost was accrued in.';
+
 CREATE TABLE workspace_modules (
     id uuid NOT NULL,
     job_id uuid NOT NULL,
@@ -1809,9 +2100,74 @@ CREATE TABLE workspace_resources (
     icon character varying(256) DEFAULT ''::character varying NOT NULL,
     instance_type character varying(256),
     daily_cost integer DEFAULT 0 NOT NULL,
-    module_path text
+    module_path text,
+    hourly_cost double precision DEFAULT 0 NOT NULL
+);
+
+COMMENT ON COLUMN workspace_resources.hourly_cost IS 'The cost of running the resource for an hour, declared with the hourly_cost metadata item of the resource.';
+
+CREATE TABLE workspace_scheduled_actions (
+    id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    action workspace_scheduled_action_type NOT NULL,
+    schedule text NOT NULL,
+    next_run_at timestamp with time zone NOT NULL,
+    last_run_at timestamp with time zone
+);
+
+COMMENT ON TABLE workspace_scheduled_actions IS 'User-defined schedules that build workspaces, executed by the lifecycle executor.';
+
+COMMENT ON COLUMN workspace_scheduled_actions.schedule IS 'Cron schedule with an optional CRON_TZ prefix, in the same format as workspace autostart schedules.';
+
+COMMENT ON COLUMN workspace_scheduled_actions.last_run_at IS 'The last time the action ran. A restart that has stopped the workspace but not started it yet has a last_run_at on or after its next_run_at.';
+
+CREATE TABLE workspace_session_recording_chunks (
+    recording_id uuid NOT NULL,
+    sequence integer NOT NULL,
+    data bytea NOT NULL
+);
+
+CREATE TABLE workspace_session_recordings (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    ended_at timestamp with time zone,
+    workspace_id uuid NOT NULL,
+    agent_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    type workspace_session_recording_type NOT NULL,
+    size bigint DEFAULT 0 NOT NULL
 );
 
+COMMENT ON TABLE workspace_session_recordings IS 'Recordings of interactive sessions in workspaces, in the asciicast v2 format.';
+
+COMMENT ON COLUMN workspace_session_recordings.ended_at IS 'Set once the agent has uploaded the final chunk of the recording.';
+
+COMMENT ON COLUMN workspace_session_recordings.user_id IS 'The owner of the workspace at the time of the recording.';
+
+COMMENT ON COLUMN workspace_session_recordings.size IS 'The total size of all chunks in bytes.';
+
+CREATE TABLE workspace_snapshots (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    organization_id uuid NOT NULL,
+    owner_id uuid NOT NULL,
+    template_id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    build_id uuid NOT NULL,
+    name text NOT NULL,
+    artifacts jsonb DEFAULT '{}'::jsonb NOT NULL
+);
+
+COMMENT ON TABLE workspace_snapshots IS 'Snapshots of workspace data taken by templates which declare coder_workspace_snapshot resources. Snapshots outlive the workspace they were taken from and can be restored into any workspace of the same owner and template.';
+
+COMMENT ON COLUMN workspace_snapshots.build_id IS 'The workspace build which took the snapshot.';
+
+COMMENT ON COLUMN workspace_snapshots.artifacts IS 'The artifacts reported by each coder_workspace_snapshot resource, keyed by resource name.';
+
 CREATE TABLE workspaces (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -1888,6 +2244,9 @@ ALTER TABLE ONLY api_keys
 ALTER TABLE ONLY audit_logs
     ADD CONSTRAINT audit_logs_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY cost_budgets
+    ADD CONSTRAINT cost_budgets_pkey PRIMARY KEY (group_id);
+
 ALTER TABLE ONLY crypto_keys
     ADD CONSTRAINT crypto_keys_pkey PRIMARY KEY (feature, sequence);
 
@@ -1933,6 +2292,12 @@ ALTER TABLE ONLY licenses
 ALTER TABLE ONLY licenses
     ADD CONSTRAINT licenses_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY network_connection_reports
+    ADD CONSTRAINT network_connection_reports_pkey PRIMARY KEY (client_id, remote_node_id);
+
+ALTER TABLE ONLY network_latency_reports
+    ADD CONSTRAINT network_latency_reports_pkey PRIMARY KEY (client_id, region_id);
+
 ALTER TABLE ONLY notification_messages
     ADD CONSTRAINT notification_messages_pkey PRIMARY KEY (id);
 
@@ -1972,6 +2337,15 @@ ALTER TABLE ONLY oauth2_provider_apps
 ALTER TABLE ONLY oauth2_provider_apps
     ADD CONSTRAINT oauth2_provider_apps_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY oauth2_provider_device_codes
+    ADD CONSTRAINT oauth2_provider_device_codes_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY oauth2_provider_device_codes
+    ADD CONSTRAINT oauth2_provider_device_codes_secret_prefix_key UNIQUE (secret_prefix);
+
+ALTER TABLE ONLY oauth2_provider_device_codes
+    ADD CONSTRAINT oauth2_provider_device_codes_user_code_key UNIQUE (user_code);
+
 ALTER TABLE ONLY organization_members
     ADD CONSTRAINT organization_members_pkey PRIMARY KEY (organization_id, user_id);
 
@@ -1999,12 +2373,18 @@ ALTER TABLE ONLY provisioner_daemons
 ALTER TABLE ONLY provisioner_job_logs
     ADD CONSTRAINT provisioner_job_logs_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY provisioner_job_resource_changes
+    ADD CONSTRAINT provisioner_job_resource_changes_pkey PRIMARY KEY (job_id, address);
+
 ALTER TABLE ONLY provisioner_jobs
     ADD CONSTRAINT provisioner_jobs_pkey PRIMARY KEY (id);
 
 ALTER TABLE ONLY provisioner_keys
     ADD CONSTRAINT provisioner_keys_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY secrets
+    ADD CONSTRAINT secrets_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY site_configs
     ADD CONSTRAINT site_configs_key_key UNIQUE (key);
 
@@ -2026,6 +2406,9 @@ ALTER TABLE ONLY tailnet_peers
 ALTER TABLE ONLY tailnet_tunnels
     ADD CONSTRAINT tailnet_tunnels_pkey PRIMARY KEY (coordinator_id, src_id, dst_id);
 
+ALTER TABLE ONLY template_rollouts
+    ADD CONSTRAINT template_rollouts_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY template_usage_stats
     ADD CONSTRAINT template_usage_stats_pkey PRIMARY KEY (start_time, template_id, user_id);
 
@@ -2068,6 +2451,9 @@ ALTER TABLE ONLY workspace_agent_metadata
 ALTER TABLE ONLY workspace_agent_port_share
     ADD CONSTRAINT workspace_agent_port_share_pkey PRIMARY KEY (workspace_id, agent_name, port);
 
+ALTER TABLE ONLY workspace_agent_process_groups
+    ADD CONSTRAINT workspace_agent_process_groups_pkey PRIMARY KEY (workspace_agent_id, name);
+
 ALTER TABLE ONLY workspace_agent_script_timings
     ADD CONSTRAINT workspace_agent_script_timings_script_id_started_at_key UNIQUE (script_id, started_at);
 
@@ -2092,6 +2478,9 @@ ALTER TABLE ONLY workspace_apps
 ALTER TABLE ONLY workspace_apps
     ADD CONSTRAINT workspace_apps_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY workspace_build_approvals
+    ADD CONSTRAINT workspace_build_approvals_pkey PRIMARY KEY (workspace_build_id);
+
 ALTER TABLE ONLY workspace_build_parameters
     ADD CONSTRAINT workspace_build_parameters_workspace_build_id_name_key UNIQUE (workspace_build_id, name);
 
@@ -2104,6 +2493,9 @@ ALTER TABLE ONLY workspace_builds
 ALTER TABLE ONLY workspace_builds
     ADD CONSTRAINT workspace_builds_workspace_id_build_number_key UNIQUE (workspace_id, build_number);
 
+ALTER TABLE ONLY workspace_costs
+    ADD CONSTRAINT workspace_costs_pkey PRIMARY KEY (bucket_start, workspace_id);
+
 ALTER TABLE ONLY workspace_proxies
     ADD CONSTRAINT workspace_proxies_pkey PRIMARY KEY (id);
 
@@ -2119,6 +2511,18 @@ ALTER TABLE ONLY workspace_resource_metadata
 ALTER TABLE ONLY workspace_resources
     ADD CONSTRAINT workspace_resources_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY workspace_scheduled_actions
+    ADD CONSTRAINT workspace_scheduled_actions_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY workspace_session_recording_chunks
+    ADD CONSTRAINT workspace_session_recording_chunks_pkey PRIMARY KEY (recording_id, sequence);
+
+ALTER TABLE ONLY workspace_session_recordings
+    ADD CONSTRAINT workspace_session_recordings_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY workspace_snapshots
+    ADD CONSTRAINT workspace_snapshots_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY workspaces
     ADD CONSTRAINT workspaces_pkey PRIMARY KEY (id);
 
@@ -2156,6 +2560,12 @@ CREATE UNIQUE INDEX idx_provisioner_daemons_org_name_owner_key ON provisioner_da
 
 COMMENT ON INDEX idx_provisioner_daemons_org_name_owner_key IS 'Allow unique provisioner daemon names by organization and user';
 
+CREATE UNIQUE INDEX idx_secrets_organization_id_name ON secrets USING btree (organization_id, name) WHERE (organization_id IS NOT NULL);
+
+CREATE UNIQUE INDEX idx_secrets_template_id_name ON secrets USING btree (template_id, name) WHERE (template_id IS NOT NULL);
+
+CREATE UNIQUE INDEX idx_secrets_user_id_name ON secrets USING btree (user_id, name) WHERE (user_id IS NOT NULL);
+
 CREATE INDEX idx_tailnet_agents_coordinator ON tailnet_agents USING btree (coordinator_id);
 
 CREATE INDEX idx_tailnet_clients_coordinator ON tailnet_clients USING btree (coordinator_id);
@@ -2174,16 +2584,24 @@ CREATE UNIQUE INDEX idx_users_email ON users USING btree (email) WHERE (deleted
 
 CREATE UNIQUE INDEX idx_users_username ON users USING btree (username) WHERE (deleted = false);
 
+CREATE INDEX network_connection_reports_reported_at_idx ON network_connection_reports USING btree (reported_at);
+
+CREATE INDEX network_latency_reports_reported_at_idx ON network_latency_reports USING btree (reported_at);
+
 CREATE UNIQUE INDEX notification_messages_dedupe_hash_idx ON notification_messages USING btree (dedupe_hash);
 
 CREATE UNIQUE INDEX organizations_single_default_org ON organizations USING btree (is_default) WHERE (is_default = true);
 
 CREATE INDEX provisioner_job_logs_id_job_id_idx ON provisioner_job_logs USING btree (job_id, id);
 
+CREATE INDEX provisioner_jobs_running_idx ON provisioner_jobs USING btree (organization_id, initiator_id) WHERE ((started_at IS NOT NULL) AND (completed_at IS NULL));
+
 CREATE INDEX provisioner_jobs_started_at_idx ON provisioner_jobs USING btree (started_at) WHERE (started_at IS NULL);
 
 CREATE UNIQUE INDEX provisioner_keys_organization_id_name_idx ON provisioner_keys USING btree (organization_id, lower((name)::text));
 
+CREATE UNIQUE INDEX template_rollouts_template_id_in_progress_idx ON template_rollouts USING btree (template_id) WHERE (status = 'in_progress'::template_rollout_status);
+
 CREATE INDEX template_usage_stats_start_time_idx ON template_usage_stats USING btree (start_time DESC);
 
 COMMENT ON INDEX template_usage_stats_start_time_idx IS 'Index for querying MAX(start_time).';
@@ -2216,6 +2634,12 @@ CREATE INDEX workspace_agents_resource_id_idx ON workspace_agents USING btree (r
 
 CREATE INDEX workspace_app_stats_workspace_id_idx ON workspace_app_stats USING btree (workspace_id);
 
+CREATE INDEX workspace_build_approvals_pending_expires_at_idx ON workspace_build_approvals USING btree (expires_at) WHERE (status = 'pending'::workspace_build_approval_status);
+
+CREATE INDEX workspace_costs_owner_id_idx ON workspace_costs USING btree (owner_id);
+
+CREATE INDEX workspace_costs_template_id_idx ON workspace_costs USING btree (template_id);
+
 CREATE INDEX workspace_modules_created_at_idx ON workspace_modules USING btree (created_at);
 
 CREATE INDEX workspace_next_start_at_idx ON workspaces USING btree (next_start_at) WHERE (deleted = false);
@@ -2224,6 +2648,16 @@ CREATE UNIQUE INDEX workspace_proxies_lower_name_idx ON workspace_proxies USING
 
 CREATE INDEX workspace_resources_job_id_idx ON workspace_resources USING btree (job_id);
 
+CREATE INDEX workspace_scheduled_actions_next_run_at_idx ON workspace_scheduled_actions USING btree (next_run_at);
+
+CREATE INDEX workspace_scheduled_actions_workspace_id_idx ON workspace_scheduled_actions USING btree (workspace_id);
+
+CREATE INDEX workspace_session_recordings_workspace_id_idx ON workspace_session_recordings USING btree (workspace_id);
+
+CREATE INDEX workspace_snapshots_build_id_idx ON workspace_snapshots USING btree (build_id);
+
+CREATE UNIQUE INDEX workspace_snapshots_owner_id_template_id_lower_name_idx ON workspace_snapshots USING btree (owner_id, template_id, lower(name));
+
 CREATE INDEX workspace_template_id_idx ON workspaces USING btree (template_id) WHERE (deleted = false);
 
 CREATE UNIQUE INDEX workspaces_owner_id_lower_idx ON workspaces USING btree (owner_id, lower((name)::text)) WHERE (deleted = false);
@@ -2317,6 +2751,12 @@ CREATE TRIGGER user_status_change_trigger AFTER INSERT OR UPDATE ON users FOR EA
 ALTER TABLE ONLY api_keys
     ADD CONSTRAINT api_keys_user_id_uuid_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY cost_budgets
+    ADD CONSTRAINT cost_budgets_group_id_fkey FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY cost_budgets
+    ADD CONSTRAINT cost_budgets_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY crypto_keys
     ADD CONSTRAINT crypto_keys_secret_key_id_fkey FOREIGN KEY (secret_key_id) REFERENCES dbcrypt_keys(active_key_digest);
 
@@ -2368,9 +2808,21 @@ ALTER TABLE ONLY oauth2_provider_app_secrets
 ALTER TABLE ONLY oauth2_provider_app_tokens
     ADD CONSTRAINT oauth2_provider_app_tokens_api_key_id_fkey FOREIGN KEY (api_key_id) REFERENCES api_keys(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY oauth2_provider_app_tokens
+    ADD CONSTRAINT oauth2_provider_app_tokens_app_id_fkey FOREIGN KEY (app_id) REFERENCES oauth2_provider_apps(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY oauth2_provider_app_tokens
     ADD CONSTRAINT oauth2_provider_app_tokens_app_secret_id_fkey FOREIGN KEY (app_secret_id) REFERENCES oauth2_provider_app_secrets(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY oauth2_provider_apps
+    ADD CONSTRAINT oauth2_provider_apps_client_credentials_user_id_fkey FOREIGN KEY (client_credentials_user_id) REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE ONLY oauth2_provider_device_codes
+    ADD CONSTRAINT oauth2_provider_device_codes_app_id_fkey FOREIGN KEY (app_id) REFERENCES oauth2_provider_apps(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY oauth2_provider_device_codes
+    ADD CONSTRAINT oauth2_provider_device_codes_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY organization_members
     ADD CONSTRAINT organization_members_organization_id_uuid_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
@@ -2389,6 +2841,9 @@ ALTER TABLE ONLY provisioner_daemons
 ALTER TABLE ONLY provisioner_job_logs
     ADD CONSTRAINT provisioner_job_logs_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY provisioner_job_resource_changes
+    ADD CONSTRAINT provisioner_job_resource_changes_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY provisioner_job_timings
     ADD CONSTRAINT provisioner_job_timings_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 
@@ -2398,6 +2853,18 @@ ALTER TABLE ONLY provisioner_jobs
 ALTER TABLE ONLY provisioner_keys
     ADD CONSTRAINT provisioner_keys_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY secrets
+    ADD CONSTRAINT secrets_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY secrets
+    ADD CONSTRAINT secrets_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY secrets
+    ADD CONSTRAINT secrets_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY secrets
+    ADD CONSTRAINT secrets_value_key_id_fkey FOREIGN KEY (value_key_id) REFERENCES dbcrypt_keys(active_key_digest);
+
 ALTER TABLE ONLY tailnet_agents
     ADD CONSTRAINT tailnet_agents_coordinator_id_fkey FOREIGN KEY (coordinator_id) REFERENCES tailnet_coordinators(id) ON DELETE CASCADE;
 
@@ -2413,6 +2880,18 @@ ALTER TABLE ONLY tailnet_peers
 ALTER TABLE ONLY tailnet_tunnels
     ADD CONSTRAINT tailnet_tunnels_coordinator_id_fkey FOREIGN KEY (coordinator_id) REFERENCES tailnet_coordinators(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY template_rollouts
+    ADD CONSTRAINT template_rollouts_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE RESTRICT;
+
+ALTER TABLE ONLY template_rollouts
+    ADD CONSTRAINT template_rollouts_group_id_fkey FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE SET NULL;
+
+ALTER TABLE ONLY template_rollouts
+    ADD CONSTRAINT template_rollouts_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_rollouts
+    ADD CONSTRAINT template_rollouts_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY template_version_parameters
     ADD CONSTRAINT template_version_parameters_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
 
@@ -2431,6 +2910,9 @@ ALTER TABLE ONLY template_versions
 ALTER TABLE ONLY template_versions
     ADD CONSTRAINT template_versions_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY templates
+    ADD CONSTRAINT templates_build_approval_group_id_fkey FOREIGN KEY (build_approval_group_id) REFERENCES groups(id) ON DELETE SET NULL;
+
 ALTER TABLE ONLY templates
     ADD CONSTRAINT templates_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE RESTRICT;
 
@@ -2461,6 +2943,9 @@ ALTER TABLE ONLY workspace_agent_metadata
 ALTER TABLE ONLY workspace_agent_port_share
     ADD CONSTRAINT workspace_agent_port_share_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_agent_process_groups
+    ADD CONSTRAINT workspace_agent_process_groups_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_agent_script_timings
     ADD CONSTRAINT workspace_agent_script_timings_script_id_fkey FOREIGN KEY (script_id) REFERENCES workspace_agent_scripts(id) ON DELETE CASCADE;
 
@@ -2485,6 +2970,12 @@ ALTER TABLE ONLY workspace_app_stats
 ALTER TABLE ONLY workspace_apps
     ADD CONSTRAINT workspace_apps_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_build_approvals
+    ADD CONSTRAINT workspace_build_approvals_reviewer_id_fkey FOREIGN KEY (reviewer_id) REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE ONLY workspace_build_approvals
+    ADD CONSTRAINT workspace_build_approvals_workspace_build_id_fkey FOREIGN KEY (workspace_build_id) REFERENCES workspace_builds(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_build_parameters
     ADD CONSTRAINT workspace_build_parameters_workspace_build_id_fkey FOREIGN KEY (workspace_build_id) REFERENCES workspace_builds(id) ON DELETE CASCADE;
 
@@ -2506,6 +2997,36 @@ ALTER TABLE ONLY workspace_resource_metadata
 ALTER TABLE ONLY workspace_resources
     ADD CONSTRAINT workspace_resources_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_scheduled_actions
+    ADD CONSTRAINT workspace_scheduled_actions_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_session_recording_chunks
+    ADD CONSTRAINT workspace_session_recording_chunks_recording_id_fkey FOREIGN KEY (recording_id) REFERENCES workspace_session_recordings(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_session_recordings
+    ADD CONSTRAINT workspace_session_recordings_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_session_recordings
+    ADD CONSTRAINT workspace_session_recordings_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_session_recordings
+    ADD CONSTRAINT workspace_session_recordings_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_snapshots
+    ADD CONSTRAINT workspace_snapshots_build_id_fkey FOREIGN KEY (build_id) REFERENCES workspace_builds(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_snapshots
+    ADD CONSTRAINT workspace_snapshots_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_snapshots
+    ADD CONSTRAINT workspace_snapshots_owner_id_fkey FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_snapshots
+    ADD CONSTRAINT workspace_snapshots_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_snapshots
+    ADD CONSTRAINT workspace_snapshots_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspaces
     ADD CONSTRAINT workspaces_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT;
 
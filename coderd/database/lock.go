@@ -12,6 +12,7 @@ const (
 	LockIDDBPurge
 	LockIDNotificationsReportGenerator
 	LockIDCryptoKeyRotation
+	LockIDDBCryptKeyRotation
 )
 
 // GenLockID generates a unique and consistent lock ID from a given string.
@@ -0,0 +1,8 @@
+-- No equivalent in down migration because ENUM values cannot be deleted.
+ALTER TYPE notification_method ADD VALUE IF NOT EXISTS 'slack';
+ALTER TYPE notification_method ADD VALUE IF NOT EXISTS 'teams';
+
+-- Allow users to opt in to a specific delivery method per notification template.
+ALTER TABLE notification_preferences
+	ADD COLUMN method notification_method;
+COMMENT ON COLUMN notification_preferences.method IS 'NULL defers to the template-level or deployment-level method';
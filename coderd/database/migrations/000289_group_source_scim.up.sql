@@ -0,0 +1,2 @@
+-- Groups provisioned by an identity provider through the SCIM Groups endpoint.
+ALTER TYPE group_source ADD VALUE IF NOT EXISTS 'scim';
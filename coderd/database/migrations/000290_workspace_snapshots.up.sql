@@ -0,0 +1,20 @@
+CREATE TABLE workspace_snapshots (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    organization_id uuid NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    owner_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    template_id uuid NOT NULL REFERENCES templates (id) ON DELETE CASCADE,
+    workspace_id uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+    build_id uuid NOT NULL REFERENCES workspace_builds (id) ON DELETE CASCADE,
+    name text NOT NULL,
+    artifacts jsonb DEFAULT '{}'::jsonb NOT NULL,
+    PRIMARY KEY (id)
+);
+
+COMMENT ON TABLE workspace_snapshots IS 'Snapshots of workspace data taken by templates which declare coder_workspace_snapshot resources. Snapshots outlive the workspace they were taken from and can be restored into any workspace of the same owner and template.';
+COMMENT ON COLUMN workspace_snapshots.build_id IS 'The workspace build which took the snapshot.';
+COMMENT ON COLUMN workspace_snapshots.artifacts IS 'The artifacts reported by each coder_workspace_snapshot resource, keyed by resource name.';
+
+CREATE UNIQUE INDEX workspace_snapshots_owner_id_template_id_lower_name_idx ON workspace_snapshots USING btree (owner_id, template_id, lower(name));
+CREATE INDEX workspace_snapshots_build_id_idx ON workspace_snapshots USING btree (build_id);
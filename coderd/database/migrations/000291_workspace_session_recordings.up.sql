@@ -0,0 +1,66 @@
+ALTER TABLE templates ADD COLUMN session_recording_enabled boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN templates.session_recording_enabled IS 'Record the output of interactive SSH and reconnecting PTY sessions in workspaces created from this template.';
+
+-- Update the template_with_names view by recreating it.
+DROP VIEW template_with_names;
+CREATE VIEW
+	template_with_names
+AS
+SELECT
+	templates.*,
+	coalesce(visible_users.avatar_url, '') AS created_by_avatar_url,
+	coalesce(visible_users.username, '') AS created_by_username,
+	coalesce(organizations.name, '') AS organization_name,
+	coalesce(organizations.display_name, '') AS organization_display_name,
+	coalesce(organizations.icon, '') AS organization_icon
+FROM
+	templates
+		LEFT JOIN
+	visible_users
+	ON
+		templates.created_by = visible_users.id
+		LEFT JOIN
+	organizations
+	ON templates.organization_id = organizations.id
+;
+
+COMMENT ON VIEW template_with_names IS 'Joins in the display name information such as username, avatar, and organization name.';
+
+CREATE TYPE workspace_session_recording_type AS ENUM (
+    'ssh',
+    'reconnecting_pty'
+);
+
+CREATE TABLE workspace_session_recordings (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    ended_at timestamp with time zone,
+    workspace_id uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+    agent_id uuid NOT NULL REFERENCES workspace_agents (id) ON DELETE CASCADE,
+    user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    type workspace_session_recording_type NOT NULL,
+    size bigint DEFAULT 0 NOT NULL,
+    PRIMARY KEY (id)
+);
+
+COMMENT ON TABLE workspace_session_recordings IS 'Recordings of interactive sessions in workspaces, in the asciicast v2 format.';
+COMMENT ON COLUMN workspace_session_recordings.user_id IS 'The owner of the workspace at the time of the recording.';
+COMMENT ON COLUMN workspace_session_recordings.ended_at IS 'Set once the agent has uploaded the final chunk of the recording.';
+COMMENT ON COLUMN workspace_session_recordings.size IS 'The total size of all chunks in bytes.';
+
+CREATE INDEX workspace_session_recordings_workspace_id_idx ON workspace_session_recordings USING btree (workspace_id);
+
+CREATE TABLE workspace_session_recording_chunks (
+    recording_id uuid NOT NULL REFERENCES workspace_session_recordings (id) ON DELETE CASCADE,
+    sequence integer NOT NULL,
+    data bytea NOT NULL,
+    PRIMARY KEY (recording_id, sequence)
+);
+
+ALTER TYPE audit_action
+  ADD VALUE IF NOT EXISTS 'open';
+
+ALTER TYPE resource_type
+  ADD VALUE IF NOT EXISTS 'workspace_session_recording';
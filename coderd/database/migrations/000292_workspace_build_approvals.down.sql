@@ -0,0 +1,34 @@
+DELETE FROM notification_templates WHERE id = 'b96a5b0e-3ab9-45d3-8f1b-0e4801a65cc0';
+
+DROP TABLE IF EXISTS workspace_build_approvals;
+DROP TYPE IF EXISTS workspace_build_approval_status;
+
+DROP VIEW template_with_names;
+ALTER TABLE templates
+	DROP COLUMN require_build_approval,
+	DROP COLUMN build_approval_parameters,
+	DROP COLUMN build_approval_group_id,
+	DROP COLUMN build_approval_timeout;
+
+CREATE VIEW
+	template_with_names
+AS
+SELECT
+	templates.*,
+	coalesce(visible_users.avatar_url, '') AS created_by_avatar_url,
+	coalesce(visible_users.username, '') AS created_by_username,
+	coalesce(organizations.name, '') AS organization_name,
+	coalesce(organizations.display_name, '') AS organization_display_name,
+	coalesce(organizations.icon, '') AS organization_icon
+FROM
+	templates
+		LEFT JOIN
+	visible_users
+	ON
+		templates.created_by = visible_users.id
+		LEFT JOIN
+	organizations
+	ON templates.organization_id = organizations.id
+;
+
+COMMENT ON VIEW template_with_names IS 'Joins in the display name information such as username, avatar, and organization name.';
@@ -0,0 +1,76 @@
+ALTER TABLE templates
+	ADD COLUMN require_build_approval boolean NOT NULL DEFAULT false,
+	ADD COLUMN build_approval_parameters text[] NOT NULL DEFAULT '{}',
+	ADD COLUMN build_approval_group_id uuid REFERENCES groups (id) ON DELETE SET NULL,
+	ADD COLUMN build_approval_timeout bigint NOT NULL DEFAULT 86400000000000; -- 24 hours
+
+COMMENT ON COLUMN templates.require_build_approval IS 'Workspace start builds must be approved before they are queued for provisioners.';
+COMMENT ON COLUMN templates.build_approval_parameters IS 'If not empty, only builds that change the value of one of these parameters require approval.';
+COMMENT ON COLUMN templates.build_approval_group_id IS 'The group whose members can approve builds. Template admins can always approve builds.';
+COMMENT ON COLUMN templates.build_approval_timeout IS 'Builds that are not approved within this duration are rejected. 0 means builds never time out.';
+
+-- Update the template_with_names view by recreating it.
+DROP VIEW template_with_names;
+CREATE VIEW
+	template_with_names
+AS
+SELECT
+	templates.*,
+	coalesce(visible_users.avatar_url, '') AS created_by_avatar_url,
+	coalesce(visible_users.username, '') AS created_by_username,
+	coalesce(organizations.name, '') AS organization_name,
+	coalesce(organizations.display_name, '') AS organization_display_name,
+	coalesce(organizations.icon, '') AS organization_icon
+FROM
+	templates
+		LEFT JOIN
+	visible_users
+	ON
+		templates.created_by = visible_users.id
+		LEFT JOIN
+	organizations
+	ON templates.organization_id = organizations.id
+;
+
+COMMENT ON VIEW template_with_names IS 'Joins in the display name information such as username, avatar, and organization name.';
+
+CREATE TYPE workspace_build_approval_status AS ENUM (
+    'pending',
+    'approved',
+    'rejected'
+);
+
+CREATE TABLE workspace_build_approvals (
+    workspace_build_id uuid NOT NULL REFERENCES workspace_builds (id) ON DELETE CASCADE,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone,
+    status workspace_build_approval_status NOT NULL DEFAULT 'pending',
+    reviewer_id uuid REFERENCES users (id) ON DELETE SET NULL,
+    reviewed_at timestamp with time zone,
+    reason text NOT NULL DEFAULT '',
+    PRIMARY KEY (workspace_build_id)
+);
+
+COMMENT ON TABLE workspace_build_approvals IS 'Workspace builds that require approval before their provisioner job can be acquired.';
+COMMENT ON COLUMN workspace_build_approvals.expires_at IS 'Pending approvals are rejected after this time. NULL means the approval never expires.';
+COMMENT ON COLUMN workspace_build_approvals.reviewer_id IS 'The user that approved or rejected the build. NULL if the build is pending or timed out.';
+
+CREATE INDEX workspace_build_approvals_pending_expires_at_idx ON workspace_build_approvals (expires_at) WHERE status = 'pending';
+
+INSERT INTO notification_templates
+	(id, name, title_template, body_template, "group", actions)
+VALUES (
+	'b96a5b0e-3ab9-45d3-8f1b-0e4801a65cc0',
+	'Workspace Build Approval Requested',
+	E'Workspace build for ''{{.Labels.workspace}}'' needs approval',
+	E'Hello {{.UserName}},\n\n'||
+		E'**{{.Labels.initiator}}** requested a build of workspace **{{.Labels.workspace}}** owned by **{{.Labels.owner}}** from template **{{.Labels.template}}**. The build will not run until it is approved.',
+	'Workspace Events',
+	'[
+		{
+			"label": "Review build",
+			"url": "{{base_url}}/@{{.Labels.owner}}/{{.Labels.workspace}}/builds/{{.Labels.build_number}}"
+		}
+	]'::jsonb
+);
@@ -0,0 +1,4 @@
+-- It's not possible to drop enum values from enum types, so the UP has "IF NOT
+-- EXISTS".
+DROP TABLE IF EXISTS workspace_scheduled_actions;
+DROP TYPE IF EXISTS workspace_scheduled_action_type;
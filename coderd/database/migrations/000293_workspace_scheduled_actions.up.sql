@@ -0,0 +1,27 @@
+ALTER TYPE build_reason ADD VALUE IF NOT EXISTS 'scheduled';
+
+CREATE TYPE workspace_scheduled_action_type AS ENUM (
+    'start',
+    'stop',
+    'restart',
+    'update'
+);
+
+CREATE TABLE workspace_scheduled_actions (
+    id uuid NOT NULL,
+    workspace_id uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    action workspace_scheduled_action_type NOT NULL,
+    schedule text NOT NULL,
+    next_run_at timestamp with time zone NOT NULL,
+    last_run_at timestamp with time zone,
+    PRIMARY KEY (id)
+);
+
+COMMENT ON TABLE workspace_scheduled_actions IS 'User-defined schedules that build workspaces, executed by the lifecycle executor.';
+COMMENT ON COLUMN workspace_scheduled_actions.schedule IS 'Cron schedule with an optional CRON_TZ prefix, in the same format as workspace autostart schedules.';
+COMMENT ON COLUMN workspace_scheduled_actions.last_run_at IS 'The last time the action ran. A restart that has stopped the workspace but not started it yet has a last_run_at on or after its next_run_at.';
+
+CREATE INDEX workspace_scheduled_actions_workspace_id_idx ON workspace_scheduled_actions USING btree (workspace_id);
+CREATE INDEX workspace_scheduled_actions_next_run_at_idx ON workspace_scheduled_actions USING btree (next_run_at);
@@ -0,0 +1,6 @@
+DELETE FROM notification_templates WHERE id = '0b8d5a47-1f2c-4e59-9c3a-7d4e6f8a9b10';
+
+DROP TABLE IF EXISTS cost_budgets;
+DROP TABLE IF EXISTS workspace_costs;
+
+ALTER TABLE workspace_resources DROP COLUMN IF EXISTS hourly_cost;
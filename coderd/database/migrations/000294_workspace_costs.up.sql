@@ -0,0 +1,57 @@
+ALTER TABLE workspace_resources
+	ADD COLUMN hourly_cost double precision NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN workspace_resources.hourly_cost IS 'The cost of running the resource for an hour, declared with the hourly_cost metadata item of the resource.';
+
+CREATE TABLE workspace_costs (
+    bucket_start timestamp with time zone NOT NULL,
+    workspace_id uuid NOT NULL,
+    owner_id uuid NOT NULL,
+    template_id uuid NOT NULL,
+    organization_id uuid NOT NULL,
+    cost double precision NOT NULL,
+    PRIMARY KEY (bucket_start, workspace_id)
+);
+
+COMMENT ON TABLE workspace_costs IS 'Records the cost of workspaces in hourly buckets, rolled up from the hourly cost of the resources of their builds.';
+COMMENT ON COLUMN workspace_costs.bucket_start IS 'Start time of the hour the cost was accrued in.';
+
+CREATE INDEX workspace_costs_owner_id_idx ON workspace_costs USING btree (owner_id);
+CREATE INDEX workspace_costs_template_id_idx ON workspace_costs USING btree (template_id);
+
+CREATE TABLE cost_budgets (
+    group_id uuid NOT NULL REFERENCES groups (id) ON DELETE CASCADE,
+    organization_id uuid NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    monthly_limit double precision NOT NULL CHECK (monthly_limit > 0),
+    notify_threshold_percent integer NOT NULL CHECK (notify_threshold_percent >= 0),
+    stop_threshold_percent integer NOT NULL CHECK (stop_threshold_percent >= 0),
+    notified_at timestamp with time zone,
+    stopped_at timestamp with time zone,
+    PRIMARY KEY (group_id)
+);
+
+COMMENT ON TABLE cost_budgets IS 'Monthly limits on the cost of the workspaces owned by the members of a group.';
+COMMENT ON COLUMN cost_budgets.notify_threshold_percent IS 'Members are notified when the cost of their workspaces reaches this percentage of the monthly limit. 0 disables notifications.';
+COMMENT ON COLUMN cost_budgets.stop_threshold_percent IS 'Running workspaces of members are stopped when the cost of their workspaces reaches this percentage of the monthly limit. 0 disables stopping workspaces.';
+COMMENT ON COLUMN cost_budgets.notified_at IS 'The last time members were notified that the notify threshold was reached.';
+COMMENT ON COLUMN cost_budgets.stopped_at IS 'The last time members were notified that the stop threshold was reached.';
+
+INSERT INTO notification_templates
+	(id, name, title_template, body_template, "group", actions)
+VALUES (
+	'0b8d5a47-1f2c-4e59-9c3a-7d4e6f8a9b10',
+	'Cost Budget Threshold Reached',
+	E'Group ''{{.Labels.group}}'' has used {{.Labels.percent}}% of its monthly budget',
+	E'Hello {{.UserName}},\n\n'||
+		E'The workspaces of the members of group **{{.Labels.group}}** have cost **{{.Labels.cost}}** this month, {{.Labels.percent}}% of its monthly budget of **{{.Labels.limit}}**.'||
+		E'{{if eq .Labels.stopping "true"}}\n\nRunning workspaces of the members of the group will be stopped until the end of the month.{{end}}',
+	'Workspace Events',
+	'[
+		{
+			"label": "View workspaces",
+			"url": "{{base_url}}/workspaces"
+		}
+	]'::jsonb
+);
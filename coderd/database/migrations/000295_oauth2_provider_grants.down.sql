@@ -0,0 +1,16 @@
+DROP TABLE IF EXISTS oauth2_provider_device_codes;
+DROP TYPE IF EXISTS oauth2_provider_device_code_status;
+
+-- Tokens of public clients have no secret and cannot be kept.
+DELETE FROM oauth2_provider_app_tokens WHERE app_secret_id IS NULL;
+
+ALTER TABLE oauth2_provider_app_tokens
+	ALTER COLUMN app_secret_id SET NOT NULL,
+	DROP COLUMN app_id;
+
+ALTER TABLE oauth2_provider_app_codes
+	DROP COLUMN code_challenge;
+
+ALTER TABLE oauth2_provider_apps
+	DROP COLUMN public_client,
+	DROP COLUMN client_credentials_user_id;
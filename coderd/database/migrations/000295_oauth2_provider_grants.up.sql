@@ -0,0 +1,51 @@
+ALTER TABLE oauth2_provider_apps
+	ADD COLUMN public_client boolean NOT NULL DEFAULT false,
+	ADD COLUMN client_credentials_user_id uuid REFERENCES users (id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN oauth2_provider_apps.public_client IS 'Public clients cannot keep a secret, so they authenticate with PKCE instead of a client secret.';
+COMMENT ON COLUMN oauth2_provider_apps.client_credentials_user_id IS 'The user that tokens issued with the client credentials grant act as. The grant is disabled when null.';
+
+ALTER TABLE oauth2_provider_app_codes
+	ADD COLUMN code_challenge text NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN oauth2_provider_app_codes.code_challenge IS 'The S256 PKCE code challenge the code verifier must match when the code is exchanged. Empty if PKCE was not used.';
+
+-- Tokens of public clients are not issued for a secret, so they reference the
+-- app directly.
+ALTER TABLE oauth2_provider_app_tokens
+	ADD COLUMN app_id uuid REFERENCES oauth2_provider_apps (id) ON DELETE CASCADE;
+
+UPDATE oauth2_provider_app_tokens
+	SET app_id = oauth2_provider_app_secrets.app_id
+	FROM oauth2_provider_app_secrets
+	WHERE oauth2_provider_app_secrets.id = oauth2_provider_app_tokens.app_secret_id;
+
+ALTER TABLE oauth2_provider_app_tokens
+	ALTER COLUMN app_id SET NOT NULL,
+	ALTER COLUMN app_secret_id DROP NOT NULL;
+
+CREATE TYPE oauth2_provider_device_code_status AS ENUM (
+	'pending',
+	'approved',
+	'denied'
+);
+
+CREATE TABLE oauth2_provider_device_codes (
+	id uuid NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	expires_at timestamp with time zone NOT NULL,
+	secret_prefix bytea NOT NULL,
+	hashed_secret bytea NOT NULL,
+	user_code text NOT NULL,
+	app_id uuid NOT NULL REFERENCES oauth2_provider_apps (id) ON DELETE CASCADE,
+	status oauth2_provider_device_code_status NOT NULL DEFAULT 'pending',
+	user_id uuid REFERENCES users (id) ON DELETE CASCADE,
+	last_polled_at timestamp with time zone,
+	PRIMARY KEY (id),
+	UNIQUE (secret_prefix),
+	UNIQUE (user_code)
+);
+
+COMMENT ON TABLE oauth2_provider_device_codes IS 'Device codes are exchanged for access tokens once the user enters the user code and approves the device, see RFC 8628.';
+COMMENT ON COLUMN oauth2_provider_device_codes.user_id IS 'The user that approved or denied the device.';
+COMMENT ON COLUMN oauth2_provider_device_codes.last_polled_at IS 'The last time the device polled the token endpoint, used to tell devices polling too often to slow down.';
@@ -0,0 +1,19 @@
+CREATE TYPE provisioner_job_resource_change_action AS ENUM (
+	'create',
+	'update',
+	'replace',
+	'delete'
+);
+
+CREATE TABLE provisioner_job_resource_changes (
+	job_id uuid NOT NULL REFERENCES provisioner_jobs (id) ON DELETE CASCADE,
+	address text NOT NULL,
+	resource_type text NOT NULL,
+	resource_name text NOT NULL,
+	action provisioner_job_resource_change_action NOT NULL,
+	attributes jsonb NOT NULL DEFAULT '[]'::jsonb,
+	PRIMARY KEY (job_id, address)
+);
+
+COMMENT ON TABLE provisioner_job_resource_changes IS 'The changes the plan of a provisioner job makes to each resource. Resources that do not change are not stored.';
+COMMENT ON COLUMN provisioner_job_resource_changes.attributes IS 'The changed attributes of the resource. Values of sensitive attributes are never stored.';
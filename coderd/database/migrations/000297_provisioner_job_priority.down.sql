@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS provisioner_jobs_running_idx;
+
+ALTER TABLE provisioner_jobs
+	DROP COLUMN IF EXISTS priority;
@@ -0,0 +1,8 @@
+ALTER TABLE provisioner_jobs
+	ADD COLUMN priority integer NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN provisioner_jobs.priority IS 'Pending jobs with a higher priority are acquired first. Workspace builds started by users have the highest priority, followed by automatic workspace builds, then template version imports and dry-runs.';
+
+-- Used to count the running jobs of an organization or user when enforcing
+-- concurrency limits.
+CREATE INDEX provisioner_jobs_running_idx ON provisioner_jobs USING btree (organization_id, initiator_id) WHERE (started_at IS NOT NULL AND completed_at IS NULL);
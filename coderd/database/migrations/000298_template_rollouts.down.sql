@@ -0,0 +1,5 @@
+DELETE FROM notification_templates WHERE id = '5d9a3c1e-7b2f-4e8a-9c6d-1f0e2b3a4c5d';
+
+DROP TABLE IF EXISTS template_rollouts;
+
+DROP TYPE IF EXISTS template_rollout_status;
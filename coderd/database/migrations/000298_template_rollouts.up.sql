@@ -0,0 +1,55 @@
+CREATE TYPE template_rollout_status AS ENUM (
+	'in_progress',
+	'promoted',
+	'rolled_back',
+	'canceled'
+);
+
+CREATE TABLE template_rollouts (
+	id uuid NOT NULL,
+	template_id uuid NOT NULL REFERENCES templates (id) ON DELETE CASCADE,
+	template_version_id uuid NOT NULL REFERENCES template_versions (id) ON DELETE CASCADE,
+	created_by uuid NOT NULL REFERENCES users (id) ON DELETE RESTRICT,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	completed_at timestamp with time zone,
+	status template_rollout_status NOT NULL DEFAULT 'in_progress',
+	status_reason text NOT NULL DEFAULT '',
+	percent integer NOT NULL,
+	group_id uuid REFERENCES groups (id) ON DELETE SET NULL,
+	min_builds integer NOT NULL,
+	max_failure_rate double precision NOT NULL,
+	auto_promote boolean NOT NULL DEFAULT false,
+	PRIMARY KEY (id),
+	CONSTRAINT template_rollouts_percent_check CHECK (percent >= 0 AND percent <= 100),
+	CONSTRAINT template_rollouts_min_builds_check CHECK (min_builds > 0),
+	CONSTRAINT template_rollouts_max_failure_rate_check CHECK (max_failure_rate >= 0 AND max_failure_rate <= 1)
+);
+
+COMMENT ON TABLE template_rollouts IS 'Staged rollouts of template versions. Workspaces included in a rollout in progress build its candidate version instead of the active version of the template.';
+COMMENT ON COLUMN template_rollouts.template_version_id IS 'The candidate version that is rolled out.';
+COMMENT ON COLUMN template_rollouts.percent IS 'The percentage of workspaces of the template that are included in the rollout.';
+COMMENT ON COLUMN template_rollouts.group_id IS 'Workspaces owned by members of this group are included in the rollout regardless of percent.';
+COMMENT ON COLUMN template_rollouts.min_builds IS 'The number of completed builds of the candidate version required before the rollout is promoted or rolled back automatically.';
+COMMENT ON COLUMN template_rollouts.max_failure_rate IS 'The rollout is rolled back when the share of failed builds of the candidate version exceeds this rate.';
+COMMENT ON COLUMN template_rollouts.auto_promote IS 'Whether the candidate version is promoted automatically once min_builds builds completed within max_failure_rate.';
+
+CREATE UNIQUE INDEX template_rollouts_template_id_in_progress_idx ON template_rollouts USING btree (template_id) WHERE (status = 'in_progress'::template_rollout_status);
+
+INSERT INTO notification_templates
+	(id, name, title_template, body_template, "group", actions)
+VALUES (
+	'5d9a3c1e-7b2f-4e8a-9c6d-1f0e2b3a4c5d',
+	'Template Version Rollout Updated',
+	E'Rollout of template ''{{.Labels.template}}'' {{.Labels.status}}',
+	E'Hello {{.UserName}},\n\n'||
+		E'The rollout of version **{{.Labels.version}}** of template **{{.Labels.template}}** was {{.Labels.status}}.'||
+		E'{{if .Labels.reason}}\n\n{{.Labels.reason}}{{end}}',
+	'Template Events',
+	'[
+		{
+			"label": "View template version",
+			"url": "{{base_url}}/templates/{{.Labels.organization}}/{{.Labels.template}}/versions/{{.Labels.version}}"
+		}
+	]'::jsonb
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS network_connection_reports;
+
+DROP TABLE IF EXISTS network_latency_reports;
@@ -0,0 +1,31 @@
+CREATE TABLE network_latency_reports (
+	client_id uuid NOT NULL,
+	client_type text NOT NULL,
+	region_id integer NOT NULL,
+	latency_ms double precision NOT NULL,
+	preferred boolean NOT NULL,
+	reported_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (client_id, region_id)
+);
+
+COMMENT ON TABLE network_latency_reports IS 'The latest latency to each DERP region reported by tailnet clients, like agents and the CLI.';
+COMMENT ON COLUMN network_latency_reports.client_id IS 'The ID of the tailnet connection of the client.';
+COMMENT ON COLUMN network_latency_reports.preferred IS 'Whether the region is the preferred (home) region of the client.';
+
+CREATE INDEX network_latency_reports_reported_at_idx ON network_latency_reports (reported_at);
+
+CREATE TABLE network_connection_reports (
+	client_id uuid NOT NULL,
+	remote_node_id bigint NOT NULL,
+	client_type text NOT NULL,
+	home_region_id integer NOT NULL,
+	direct boolean NOT NULL,
+	reported_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (client_id, remote_node_id)
+);
+
+COMMENT ON TABLE network_connection_reports IS 'The latest type of the connections between tailnet clients and their peers.';
+COMMENT ON COLUMN network_connection_reports.home_region_id IS 'The home DERP region of the client when the connection was reported.';
+COMMENT ON COLUMN network_connection_reports.direct IS 'Whether the connection is direct (peer-to-peer), rather than relayed through DERP.';
+
+CREATE INDEX network_connection_reports_reported_at_idx ON network_connection_reports (reported_at);
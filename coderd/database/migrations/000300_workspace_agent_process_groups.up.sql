@@ -0,0 +1,17 @@
+CREATE UNLOGGED TABLE workspace_agent_process_groups (
+	workspace_agent_id uuid NOT NULL REFERENCES workspace_agents(id) ON DELETE CASCADE,
+	name text NOT NULL,
+	cpu_usage_usec bigint NOT NULL,
+	cpu_cores double precision NOT NULL,
+	cpu_limit_cores double precision NOT NULL,
+	memory_bytes bigint NOT NULL,
+	memory_limit_bytes bigint NOT NULL,
+	oom_kills bigint NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (workspace_agent_id, name)
+);
+
+COMMENT ON TABLE workspace_agent_process_groups IS 'The latest resource usage of the cgroups that agents place their SSH sessions and scripts in.';
+COMMENT ON COLUMN workspace_agent_process_groups.cpu_cores IS 'The average number of CPUs used since the previous report of the agent.';
+COMMENT ON COLUMN workspace_agent_process_groups.cpu_limit_cores IS 'The number of CPUs the group may use, or 0 if unlimited.';
+COMMENT ON COLUMN workspace_agent_process_groups.memory_limit_bytes IS 'The memory limit of the group, or 0 if unlimited.';
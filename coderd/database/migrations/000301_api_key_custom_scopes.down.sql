@@ -0,0 +1,5 @@
+-- It's not possible to drop enum values from enum types, so the UP has "IF NOT
+-- EXISTS".
+ALTER TABLE api_keys
+	DROP COLUMN IF EXISTS scope_permissions,
+	DROP COLUMN IF EXISTS scope_allow_list;
@@ -0,0 +1,9 @@
+ALTER TYPE api_key_scope ADD VALUE IF NOT EXISTS 'custom';
+
+ALTER TABLE api_keys
+	ADD COLUMN scope_permissions jsonb DEFAULT '[]'::jsonb NOT NULL,
+	ADD COLUMN scope_allow_list text[] DEFAULT '{}'::text[] NOT NULL;
+
+COMMENT ON COLUMN api_keys.scope_permissions IS 'The resource types and actions a key with the custom scope is limited to, optionally within one organization.';
+
+COMMENT ON COLUMN api_keys.scope_allow_list IS 'The IDs of the resources a key with the custom scope is limited to. An empty list allows all resources.';
@@ -0,0 +1,3 @@
+ALTER TABLE dbcrypt_keys
+	DROP COLUMN wrapped_key,
+	DROP COLUMN key_provider;
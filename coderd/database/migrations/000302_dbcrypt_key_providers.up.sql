@@ -0,0 +1,7 @@
+ALTER TABLE dbcrypt_keys
+	ADD COLUMN key_provider text,
+	ADD COLUMN wrapped_key text;
+
+COMMENT ON COLUMN dbcrypt_keys.key_provider IS 'The external key provider that wrapped the key, e.g. vault-transit:transit/coder. Null if the key was provided to Coder directly.';
+
+COMMENT ON COLUMN dbcrypt_keys.wrapped_key IS 'The key, encrypted by the key provider. Null if the key was provided to Coder directly.';
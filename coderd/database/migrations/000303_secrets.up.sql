@@ -0,0 +1,28 @@
+CREATE TABLE secrets (
+	id uuid NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	name text NOT NULL,
+	description text NOT NULL DEFAULT '',
+	value text NOT NULL,
+	value_key_id text REFERENCES dbcrypt_keys(active_key_digest),
+	env_name text NOT NULL DEFAULT '',
+	file_path text NOT NULL DEFAULT '',
+	user_id uuid REFERENCES users(id) ON DELETE CASCADE,
+	organization_id uuid REFERENCES organizations(id) ON DELETE CASCADE,
+	template_id uuid REFERENCES templates(id) ON DELETE CASCADE,
+	PRIMARY KEY (id),
+	CONSTRAINT secrets_scope CHECK (num_nonnulls(user_id, organization_id, template_id) = 1),
+	CONSTRAINT secrets_target CHECK (env_name != '' OR file_path != '')
+);
+
+COMMENT ON TABLE secrets IS 'Secrets that are injected into workspaces by the agent. A secret belongs to exactly one user, organization or template.';
+COMMENT ON COLUMN secrets.value_key_id IS 'The ID of the key used to encrypt the value. If this is NULL, the value is not encrypted';
+COMMENT ON COLUMN secrets.env_name IS 'The name of the environment variable the secret is injected as, or empty.';
+COMMENT ON COLUMN secrets.file_path IS 'The path of the file the secret is written to in the workspace, or empty.';
+
+CREATE UNIQUE INDEX idx_secrets_user_id_name ON secrets USING btree (user_id, name) WHERE (user_id IS NOT NULL);
+CREATE UNIQUE INDEX idx_secrets_organization_id_name ON secrets USING btree (organization_id, name) WHERE (organization_id IS NOT NULL);
+CREATE UNIQUE INDEX idx_secrets_template_id_name ON secrets USING btree (template_id, name) WHERE (template_id IS NOT NULL);
+
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'secret';
@@ -0,0 +1,3 @@
+-- It's not possible to drop enum values from enum types, so the UP has "IF NOT
+-- EXISTS". The keys are removed so that the enum value is unused.
+DELETE FROM crypto_keys WHERE feature = 'workload_identity';
@@ -0,0 +1,26 @@
+INSERT INTO
+    public.workspace_snapshots (
+        id,
+        created_at,
+        updated_at,
+        organization_id,
+        owner_id,
+        template_id,
+        workspace_id,
+        build_id,
+        name,
+        artifacts
+    )
+VALUES
+    (
+        'b0ab5fc0-9c7a-4b8a-9a9d-8f3d2b1b6d2e',
+        '2024-12-01 10:00:00+00',
+        '2024-12-01 10:05:00+00',
+        'bb640d07-ca8a-4869-b6bc-ae61ebb2fda1',
+        '30095c71-380b-457a-8995-97b8ee6e5307',
+        '4cc1f466-f326-477e-8762-9d0c6781fc56',
+        '3a9a1feb-e89d-457c-9d53-ac751b198ebe',
+        'a8c0b8c5-c9a8-4f33-93a4-8142e6858244',
+        'before-upgrade',
+        '{"home": "vol-snapshot-1"}'
+    );
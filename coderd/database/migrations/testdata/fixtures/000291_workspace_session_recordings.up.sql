@@ -0,0 +1,37 @@
+INSERT INTO
+    public.workspace_session_recordings (
+        id,
+        created_at,
+        updated_at,
+        ended_at,
+        workspace_id,
+        agent_id,
+        user_id,
+        type,
+        size
+    )
+VALUES
+    (
+        'e4c1d3b2-7f6a-4c89-9b2e-5d1a0f3c8e71',
+        '2024-12-01 10:00:00+00',
+        '2024-12-01 10:05:00+00',
+        '2024-12-01 10:05:00+00',
+        '3a9a1feb-e89d-457c-9d53-ac751b198ebe',
+        '7a1ce5f8-8d00-431c-ad1b-97a846512804',
+        '30095c71-380b-457a-8995-97b8ee6e5307',
+        'ssh',
+        66
+    );
+
+INSERT INTO
+    public.workspace_session_recording_chunks (
+        recording_id,
+        sequence,
+        data
+    )
+VALUES
+    (
+        'e4c1d3b2-7f6a-4c89-9b2e-5d1a0f3c8e71',
+        0,
+        '{"version": 2, "width": 80, "height": 24, "timestamp": 1733047200}'::bytea
+    );
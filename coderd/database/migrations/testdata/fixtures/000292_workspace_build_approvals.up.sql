@@ -0,0 +1,22 @@
+INSERT INTO
+    public.workspace_build_approvals (
+        workspace_build_id,
+        created_at,
+        updated_at,
+        expires_at,
+        status,
+        reviewer_id,
+        reviewed_at,
+        reason
+    )
+VALUES
+    (
+        'a8c0b8c5-c9a8-4f33-93a4-8142e6858244',
+        '2024-12-01 10:00:00+00',
+        '2024-12-01 10:05:00+00',
+        '2024-12-02 10:00:00+00',
+        'approved',
+        '30095c71-380b-457a-8995-97b8ee6e5307',
+        '2024-12-01 10:05:00+00',
+        'Looks good.'
+    );
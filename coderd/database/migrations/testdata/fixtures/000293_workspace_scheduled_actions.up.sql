@@ -0,0 +1,22 @@
+INSERT INTO
+    public.workspace_scheduled_actions (
+        id,
+        workspace_id,
+        created_at,
+        updated_at,
+        action,
+        schedule,
+        next_run_at,
+        last_run_at
+    )
+VALUES
+    (
+        '5b0c1f9e-2a7d-4c3b-8e6f-9d4a1b2c3e5f',
+        '3a9a1feb-e89d-457c-9d53-ac751b198ebe',
+        '2024-12-01 10:00:00+00',
+        '2024-12-02 06:00:00+00',
+        'update',
+        'CRON_TZ=UTC 0 6 * * *',
+        '2024-12-03 06:00:00+00',
+        '2024-12-02 06:00:00+00'
+    );
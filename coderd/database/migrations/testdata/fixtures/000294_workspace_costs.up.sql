@@ -0,0 +1,52 @@
+INSERT INTO
+    public.workspace_costs (
+        bucket_start,
+        workspace_id,
+        owner_id,
+        template_id,
+        organization_id,
+        cost
+    )
+VALUES
+    (
+        '2024-12-01 10:00:00+00',
+        '3a9a1feb-e89d-457c-9d53-ac751b198ebe',
+        '30095c71-380b-457a-8995-97b8ee6e5307',
+        '4cc1f466-f326-477e-8762-9d0c6781fc56',
+        'bb640d07-ca8a-4869-b6bc-ae61ebb2fda1',
+        0.25
+    );
+
+INSERT INTO
+    public.groups (id, name, organization_id)
+VALUES
+    (
+        '8e7b5c3a-4d2f-4a1b-9c6e-5f3d2a1b0c9d',
+        'cost-budget-fixture',
+        'bb640d07-ca8a-4869-b6bc-ae61ebb2fda1'
+    );
+
+INSERT INTO
+    public.cost_budgets (
+        group_id,
+        organization_id,
+        created_at,
+        updated_at,
+        monthly_limit,
+        notify_threshold_percent,
+        stop_threshold_percent,
+        notified_at,
+        stopped_at
+    )
+VALUES
+    (
+        '8e7b5c3a-4d2f-4a1b-9c6e-5f3d2a1b0c9d',
+        'bb640d07-ca8a-4869-b6bc-ae61ebb2fda1',
+        '2024-12-01 10:00:00+00',
+        '2024-12-01 10:00:00+00',
+        500,
+        80,
+        100,
+        '2024-12-20 10:00:00+00',
+        NULL
+    );
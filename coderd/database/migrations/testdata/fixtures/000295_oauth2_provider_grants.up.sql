@@ -0,0 +1,14 @@
+INSERT INTO oauth2_provider_device_codes
+	(id, created_at, expires_at, secret_prefix, hashed_secret, user_code, app_id, status, user_id, last_polled_at)
+VALUES (
+	'e0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11',
+	'2023-06-15 10:23:54+00',
+	'2023-06-15 10:33:54+00',
+	CAST('abcdefg' AS bytea),
+	CAST('abcdefg' AS bytea),
+	'BCDF-GHJK',
+	'a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11',
+	'approved',
+	'0ed9befc-4911-4ccf-a8e2-559bf72daa94',
+	'2023-06-15 10:25:33+00'
+);
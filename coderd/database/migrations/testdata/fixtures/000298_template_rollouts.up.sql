@@ -0,0 +1,34 @@
+INSERT INTO
+    public.template_rollouts (
+        id,
+        template_id,
+        template_version_id,
+        created_by,
+        created_at,
+        updated_at,
+        completed_at,
+        status,
+        status_reason,
+        percent,
+        group_id,
+        min_builds,
+        max_failure_rate,
+        auto_promote
+    )
+VALUES
+    (
+        '0e3c8e5a-62a4-4a5e-9f57-5f4b0b6e7d21',
+        '4cc1f466-f326-477e-8762-9d0c6781fc56',
+        '4e681a60-83da-42c2-902e-6535376ebb77',
+        '30095c71-380b-457a-8995-97b8ee6e5307',
+        '2024-12-01 10:00:00+00',
+        '2024-12-01 12:00:00+00',
+        '2024-12-01 12:00:00+00',
+        'promoted',
+        '10 of 10 builds succeeded.',
+        10,
+        NULL,
+        10,
+        0.2,
+        true
+    );
@@ -0,0 +1,37 @@
+INSERT INTO
+	network_latency_reports (
+		client_id,
+		client_type,
+		region_id,
+		latency_ms,
+		preferred,
+		reported_at
+	)
+VALUES
+	(
+		'b3f8a1f6-5c2e-4e1b-9a1d-3f6c7e2d9a10',
+		'agent',
+		999,
+		12.5,
+		true,
+		'2024-12-01 10:00:00+00'
+	);
+
+INSERT INTO
+	network_connection_reports (
+		client_id,
+		remote_node_id,
+		client_type,
+		home_region_id,
+		direct,
+		reported_at
+	)
+VALUES
+	(
+		'b3f8a1f6-5c2e-4e1b-9a1d-3f6c7e2d9a10',
+		1234,
+		'agent',
+		999,
+		true,
+		'2024-12-01 10:00:00+00'
+	);
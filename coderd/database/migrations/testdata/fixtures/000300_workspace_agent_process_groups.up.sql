@@ -0,0 +1,24 @@
+INSERT INTO
+	workspace_agent_process_groups (
+		workspace_agent_id,
+		name,
+		cpu_usage_usec,
+		cpu_cores,
+		cpu_limit_cores,
+		memory_bytes,
+		memory_limit_bytes,
+		oom_kills,
+		updated_at
+	)
+VALUES
+	(
+		'45e89705-e09d-4850-bcec-f9a937f5d78d',
+		'sessions',
+		123456789,
+		1.5,
+		2,
+		1073741824,
+		4294967296,
+		1,
+		'2024-11-20 10:00:00+00'
+	);
@@ -0,0 +1,47 @@
+INSERT INTO
+    public.secrets (
+        id,
+        created_at,
+        updated_at,
+        name,
+        description,
+        value,
+        env_name,
+        file_path,
+        user_id
+    )
+VALUES
+    (
+        'b4a6d2a3-5f2c-4b1e-9d27-3c0f9e1a8d44',
+        '2024-12-01 10:00:00+00',
+        '2024-12-01 10:00:00+00',
+        'github-token',
+        'Personal access token for GitHub',
+        'ghp_secret',
+        'GITHUB_TOKEN',
+        '',
+        '30095c71-380b-457a-8995-97b8ee6e5307'
+    );
+
+INSERT INTO
+    public.secrets (
+        id,
+        created_at,
+        updated_at,
+        name,
+        value,
+        env_name,
+        file_path,
+        template_id
+    )
+VALUES
+    (
+        '5e2f0c1b-7a43-4d8e-8b61-2f9d4c7e0a15',
+        '2024-12-01 10:00:00+00',
+        '2024-12-01 10:00:00+00',
+        'npmrc',
+        '//registry.npmjs.org/:_authToken=secret',
+        '',
+        '~/.npmrc',
+        '4cc1f466-f326-477e-8762-9d0c6781fc56'
+    );
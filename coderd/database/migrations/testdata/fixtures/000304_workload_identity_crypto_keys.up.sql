@@ -0,0 +1,9 @@
+INSERT INTO crypto_keys (feature, sequence, secret, secret_key_id, starts_at, deletes_at)
+VALUES (
+  'workload_identity',
+  1,
+  'mno',
+  NULL,
+  '1970-01-01 00:00:00 UTC'::timestamptz,
+  '2100-01-01 00:00:00 UTC'::timestamptz
+);
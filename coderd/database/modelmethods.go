@@ -128,6 +128,28 @@ func (s APIKeyScope) ToRBAC() rbac.ScopeName {
 	}
 }
 
+// RBACScope returns the scope that limits what the key can be used for.
+func (k APIKey) RBACScope() rbac.ExpandableScope {
+	if k.Scope != APIKeyScopeCustom {
+		return rbac.ScopeName(k.Scope)
+	}
+
+	site := []rbac.Permission{}
+	org := map[string][]rbac.Permission{}
+	for _, perm := range k.ScopePermissions {
+		p := rbac.Permission{
+			ResourceType: perm.ResourceType,
+			Action:       perm.Action,
+		}
+		if perm.OrganizationID == uuid.Nil {
+			site = append(site, p)
+			continue
+		}
+		org[perm.OrganizationID.String()] = append(org[perm.OrganizationID.String()], p)
+	}
+	return rbac.CustomScope(site, org, k.ScopeAllowList)
+}
+
 func (k APIKey) RBACObject() rbac.Object {
 	return rbac.ResourceApiKey.WithIDString(k.ID).
 		WithOwner(k.UserID.String())
@@ -232,6 +254,14 @@ func (w WorkspaceTable) RBACObject() rbac.Object {
 		WithOwner(w.OwnerID.String())
 }
 
+// RBACObject returns the workspace object the snapshot was taken from, so
+// access to snapshots follows access to the owner's workspaces.
+func (s WorkspaceSnapshot) RBACObject() rbac.Object {
+	return rbac.ResourceWorkspace.WithID(s.WorkspaceID).
+		InOrg(s.OrganizationID).
+		WithOwner(s.OwnerID.String())
+}
+
 func (w WorkspaceTable) DormantRBAC() rbac.Object {
 	return rbac.ResourceWorkspaceDormant.
 		WithID(w.ID).
@@ -117,6 +117,11 @@ func (q *sqlQuerier) GetAuthorizedTemplates(ctx context.Context, arg GetTemplate
 			&i.Deprecated,
 			&i.ActivityBump,
 			&i.MaxPortSharingLevel,
+			&i.SessionRecordingEnabled,
+			&i.RequireBuildApproval,
+			pq.Array(&i.BuildApprovalParameters),
+			&i.BuildApprovalGroupID,
+			&i.BuildApprovalTimeout,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 			&i.OrganizationName,
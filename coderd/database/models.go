@@ -21,6 +21,7 @@ type APIKeyScope string
 const (
 	APIKeyScopeAll                APIKeyScope = "all"
 	APIKeyScopeApplicationConnect APIKeyScope = "application_connect"
+	APIKeyScopeCustom             APIKeyScope = "custom"
 )
 
 func (e *APIKeyScope) Scan(src interface{}) error {
@@ -61,7 +62,8 @@ func (ns NullAPIKeyScope) Value() (driver.Value, error) {
 func (e APIKeyScope) Valid() bool {
 	switch e {
 	case APIKeyScopeAll,
-		APIKeyScopeApplicationConnect:
+		APIKeyScopeApplicationConnect,
+		APIKeyScopeCustom:
 		return true
 	}
 	return false
@@ -71,6 +73,7 @@ func AllAPIKeyScopeValues() []APIKeyScope {
 	return []APIKeyScope{
 		APIKeyScopeAll,
 		APIKeyScopeApplicationConnect,
+		APIKeyScopeCustom,
 	}
 }
 
@@ -147,6 +150,7 @@ const (
 	AuditActionLogout               AuditAction = "logout"
 	AuditActionRegister             AuditAction = "register"
 	AuditActionRequestPasswordReset AuditAction = "request_password_reset"
+	AuditActionOpen                 AuditAction = "open"
 )
 
 func (e *AuditAction) Scan(src interface{}) error {
@@ -194,7 +198,8 @@ func (e AuditAction) Valid() bool {
 		AuditActionLogin,
 		AuditActionLogout,
 		AuditActionRegister,
-		AuditActionRequestPasswordReset:
+		AuditActionRequestPasswordReset,
+		AuditActionOpen:
 		return true
 	}
 	return false
@@ -211,6 +216,7 @@ func AllAuditActionValues() []AuditAction {
 		AuditActionLogout,
 		AuditActionRegister,
 		AuditActionRequestPasswordReset,
+		AuditActionOpen,
 	}
 }
 
@@ -281,6 +287,7 @@ const (
 	BuildReasonDormancy   BuildReason = "dormancy"
 	BuildReasonFailedstop BuildReason = "failedstop"
 	BuildReasonAutodelete BuildReason = "autodelete"
+	BuildReasonScheduled  BuildReason = "scheduled"
 )
 
 func (e *BuildReason) Scan(src interface{}) error {
@@ -325,7 +332,8 @@ func (e BuildReason) Valid() bool {
 		BuildReasonAutostop,
 		BuildReasonDormancy,
 		BuildReasonFailedstop,
-		BuildReasonAutodelete:
+		BuildReasonAutodelete,
+		BuildReasonScheduled:
 		return true
 	}
 	return false
@@ -339,6 +347,7 @@ func AllBuildReasonValues() []BuildReason {
 		BuildReasonDormancy,
 		BuildReasonFailedstop,
 		BuildReasonAutodelete,
+		BuildReasonScheduled,
 	}
 }
 
@@ -349,6 +358,7 @@ const (
 	CryptoKeyFeatureWorkspaceAppsAPIKey CryptoKeyFeature = "workspace_apps_api_key"
 	CryptoKeyFeatureOIDCConvert         CryptoKeyFeature = "oidc_convert"
 	CryptoKeyFeatureTailnetResume       CryptoKeyFeature = "tailnet_resume"
+	CryptoKeyFeatureWorkloadIdentity    CryptoKeyFeature = "workload_identity"
 )
 
 func (e *CryptoKeyFeature) Scan(src interface{}) error {
@@ -391,7 +401,8 @@ func (e CryptoKeyFeature) Valid() bool {
 	case CryptoKeyFeatureWorkspaceAppsToken,
 		CryptoKeyFeatureWorkspaceAppsAPIKey,
 		CryptoKeyFeatureOIDCConvert,
-		CryptoKeyFeatureTailnetResume:
+		CryptoKeyFeatureTailnetResume,
+		CryptoKeyFeatureWorkloadIdentity:
 		return true
 	}
 	return false
@@ -403,6 +414,7 @@ func AllCryptoKeyFeatureValues() []CryptoKeyFeature {
 		CryptoKeyFeatureWorkspaceAppsAPIKey,
 		CryptoKeyFeatureOIDCConvert,
 		CryptoKeyFeatureTailnetResume,
+		CryptoKeyFeatureWorkloadIdentity,
 	}
 }
 
@@ -478,6 +490,7 @@ type GroupSource string
 const (
 	GroupSourceUser GroupSource = "user"
 	GroupSourceOidc GroupSource = "oidc"
+	GroupSourceScim GroupSource = "scim"
 )
 
 func (e *GroupSource) Scan(src interface{}) error {
@@ -518,7 +531,8 @@ func (ns NullGroupSource) Value() (driver.Value, error) {
 func (e GroupSource) Valid() bool {
 	switch e {
 	case GroupSourceUser,
-		GroupSourceOidc:
+		GroupSourceOidc,
+		GroupSourceScim:
 		return true
 	}
 	return false
@@ -528,6 +542,7 @@ func AllGroupSourceValues() []GroupSource {
 	return []GroupSource{
 		GroupSourceUser,
 		GroupSourceOidc,
+		GroupSourceScim,
 	}
 }
 
@@ -805,6 +820,8 @@ type NotificationMethod string
 const (
 	NotificationMethodSmtp    NotificationMethod = "smtp"
 	NotificationMethodWebhook NotificationMethod = "webhook"
+	NotificationMethodSlack   NotificationMethod = "slack"
+	NotificationMethodTeams   NotificationMethod = "teams"
 )
 
 func (e *NotificationMethod) Scan(src interface{}) error {
@@ -845,7 +862,9 @@ func (ns NullNotificationMethod) Value() (driver.Value, error) {
 func (e NotificationMethod) Valid() bool {
 	switch e {
 	case NotificationMethodSmtp,
-		NotificationMethodWebhook:
+		NotificationMethodWebhook,
+		NotificationMethodSlack,
+		NotificationMethodTeams:
 		return true
 	}
 	return false
@@ -855,6 +874,8 @@ func AllNotificationMethodValues() []NotificationMethod {
 	return []NotificationMethod{
 		NotificationMethodSmtp,
 		NotificationMethodWebhook,
+		NotificationMethodSlack,
+		NotificationMethodTeams,
 	}
 }
 
@@ -913,6 +934,67 @@ func AllNotificationTemplateKindValues() []NotificationTemplateKind {
 	}
 }
 
+type OAuth2ProviderDeviceCodeStatus string
+
+const (
+	OAuth2ProviderDeviceCodeStatusPending  OAuth2ProviderDeviceCodeStatus = "pending"
+	OAuth2ProviderDeviceCodeStatusApproved OAuth2ProviderDeviceCodeStatus = "approved"
+	OAuth2ProviderDeviceCodeStatusDenied   OAuth2ProviderDeviceCodeStatus = "denied"
+)
+
+func (e *OAuth2ProviderDeviceCodeStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = OAuth2ProviderDeviceCodeStatus(s)
+	case string:
+		*e = OAuth2ProviderDeviceCodeStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for OAuth2ProviderDeviceCodeStatus: %T", src)
+	}
+	return nil
+}
+
+type NullOAuth2ProviderDeviceCodeStatus struct {
+	OAuth2ProviderDeviceCodeStatus OAuth2ProviderDeviceCodeStatus `json:"oauth2_provider_device_code_status"`
+	Valid                          bool                           `json:"valid"` // Valid is true if OAuth2ProviderDeviceCodeStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullOAuth2ProviderDeviceCodeStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.OAuth2ProviderDeviceCodeStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.OAuth2ProviderDeviceCodeStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullOAuth2ProviderDeviceCodeStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.OAuth2ProviderDeviceCodeStatus), nil
+}
+
+func (e OAuth2ProviderDeviceCodeStatus) Valid() bool {
+	switch e {
+	case OAuth2ProviderDeviceCodeStatusPending,
+		OAuth2ProviderDeviceCodeStatusApproved,
+		OAuth2ProviderDeviceCodeStatusDenied:
+		return true
+	}
+	return false
+}
+
+func AllOAuth2ProviderDeviceCodeStatusValues() []OAuth2ProviderDeviceCodeStatus {
+	return []OAuth2ProviderDeviceCodeStatus{
+		OAuth2ProviderDeviceCodeStatusPending,
+		OAuth2ProviderDeviceCodeStatusApproved,
+		OAuth2ProviderDeviceCodeStatusDenied,
+	}
+}
+
 type ParameterDestinationScheme string
 
 const (
@@ -1271,6 +1353,70 @@ func AllProvisionerDaemonStatusValues() []ProvisionerDaemonStatus {
 	}
 }
 
+type ProvisionerJobResourceChangeAction string
+
+const (
+	ProvisionerJobResourceChangeActionCreate  ProvisionerJobResourceChangeAction = "create"
+	ProvisionerJobResourceChangeActionUpdate  ProvisionerJobResourceChangeAction = "update"
+	ProvisionerJobResourceChangeActionReplace ProvisionerJobResourceChangeAction = "replace"
+	ProvisionerJobResourceChangeActionDelete  ProvisionerJobResourceChangeAction = "delete"
+)
+
+func (e *ProvisionerJobResourceChangeAction) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ProvisionerJobResourceChangeAction(s)
+	case string:
+		*e = ProvisionerJobResourceChangeAction(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ProvisionerJobResourceChangeAction: %T", src)
+	}
+	return nil
+}
+
+type NullProvisionerJobResourceChangeAction struct {
+	ProvisionerJobResourceChangeAction ProvisionerJobResourceChangeAction `json:"provisioner_job_resource_change_action"`
+	Valid                              bool                               `json:"valid"` // Valid is true if ProvisionerJobResourceChangeAction is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullProvisionerJobResourceChangeAction) Scan(value interface{}) error {
+	if value == nil {
+		ns.ProvisionerJobResourceChangeAction, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ProvisionerJobResourceChangeAction.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullProvisionerJobResourceChangeAction) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ProvisionerJobResourceChangeAction), nil
+}
+
+func (e ProvisionerJobResourceChangeAction) Valid() bool {
+	switch e {
+	case ProvisionerJobResourceChangeActionCreate,
+		ProvisionerJobResourceChangeActionUpdate,
+		ProvisionerJobResourceChangeActionReplace,
+		ProvisionerJobResourceChangeActionDelete:
+		return true
+	}
+	return false
+}
+
+func AllProvisionerJobResourceChangeActionValues() []ProvisionerJobResourceChangeAction {
+	return []ProvisionerJobResourceChangeAction{
+		ProvisionerJobResourceChangeActionCreate,
+		ProvisionerJobResourceChangeActionUpdate,
+		ProvisionerJobResourceChangeActionReplace,
+		ProvisionerJobResourceChangeActionDelete,
+	}
+}
+
 // Computed status of a provisioner job. Jobs could be stuck in a hung state, these states do not guarantee any transition to another state.
 type ProvisionerJobStatus string
 
@@ -1608,6 +1754,8 @@ const (
 	ResourceTypeIdpSyncSettingsOrganization ResourceType = "idp_sync_settings_organization"
 	ResourceTypeIdpSyncSettingsGroup        ResourceType = "idp_sync_settings_group"
 	ResourceTypeIdpSyncSettingsRole         ResourceType = "idp_sync_settings_role"
+	ResourceTypeWorkspaceSessionRecording   ResourceType = "workspace_session_recording"
+	ResourceTypeSecret                      ResourceType = "secret"
 )
 
 func (e *ResourceType) Scan(src interface{}) error {
@@ -1668,7 +1816,9 @@ func (e ResourceType) Valid() bool {
 		ResourceTypeNotificationTemplate,
 		ResourceTypeIdpSyncSettingsOrganization,
 		ResourceTypeIdpSyncSettingsGroup,
-		ResourceTypeIdpSyncSettingsRole:
+		ResourceTypeIdpSyncSettingsRole,
+		ResourceTypeWorkspaceSessionRecording,
+		ResourceTypeSecret:
 		return true
 	}
 	return false
@@ -1698,6 +1848,8 @@ func AllResourceTypeValues() []ResourceType {
 		ResourceTypeIdpSyncSettingsOrganization,
 		ResourceTypeIdpSyncSettingsGroup,
 		ResourceTypeIdpSyncSettingsRole,
+		ResourceTypeWorkspaceSessionRecording,
+		ResourceTypeSecret,
 	}
 }
 
@@ -1817,6 +1969,70 @@ func AllTailnetStatusValues() []TailnetStatus {
 	}
 }
 
+type TemplateRolloutStatus string
+
+const (
+	TemplateRolloutStatusInProgress TemplateRolloutStatus = "in_progress"
+	TemplateRolloutStatusPromoted   TemplateRolloutStatus = "promoted"
+	TemplateRolloutStatusRolledBack TemplateRolloutStatus = "rolled_back"
+	TemplateRolloutStatusCanceled   TemplateRolloutStatus = "canceled"
+)
+
+func (e *TemplateRolloutStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = TemplateRolloutStatus(s)
+	case string:
+		*e = TemplateRolloutStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for TemplateRolloutStatus: %T", src)
+	}
+	return nil
+}
+
+type NullTemplateRolloutStatus struct {
+	TemplateRolloutStatus TemplateRolloutStatus `json:"template_rollout_status"`
+	Valid                 bool                  `json:"valid"` // Valid is true if TemplateRolloutStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullTemplateRolloutStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.TemplateRolloutStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.TemplateRolloutStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullTemplateRolloutStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.TemplateRolloutStatus), nil
+}
+
+func (e TemplateRolloutStatus) Valid() bool {
+	switch e {
+	case TemplateRolloutStatusInProgress,
+		TemplateRolloutStatusPromoted,
+		TemplateRolloutStatusRolledBack,
+		TemplateRolloutStatusCanceled:
+		return true
+	}
+	return false
+}
+
+func AllTemplateRolloutStatusValues() []TemplateRolloutStatus {
+	return []TemplateRolloutStatus{
+		TemplateRolloutStatusInProgress,
+		TemplateRolloutStatusPromoted,
+		TemplateRolloutStatusRolledBack,
+		TemplateRolloutStatusCanceled,
+	}
+}
+
 // Defines the users status: active, dormant, or suspended.
 type UserStatus string
 
@@ -2274,6 +2490,189 @@ func AllWorkspaceAppOpenInValues() []WorkspaceAppOpenIn {
 	}
 }
 
+type WorkspaceBuildApprovalStatus string
+
+const (
+	WorkspaceBuildApprovalStatusPending  WorkspaceBuildApprovalStatus = "pending"
+	WorkspaceBuildApprovalStatusApproved WorkspaceBuildApprovalStatus = "approved"
+	WorkspaceBuildApprovalStatusRejected WorkspaceBuildApprovalStatus = "rejected"
+)
+
+func (e *WorkspaceBuildApprovalStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceBuildApprovalStatus(s)
+	case string:
+		*e = WorkspaceBuildApprovalStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceBuildApprovalStatus: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceBuildApprovalStatus struct {
+	WorkspaceBuildApprovalStatus WorkspaceBuildApprovalStatus `json:"workspace_build_approval_status"`
+	Valid                        bool                         `json:"valid"` // Valid is true if WorkspaceBuildApprovalStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceBuildApprovalStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceBuildApprovalStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceBuildApprovalStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceBuildApprovalStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceBuildApprovalStatus), nil
+}
+
+func (e WorkspaceBuildApprovalStatus) Valid() bool {
+	switch e {
+	case WorkspaceBuildApprovalStatusPending,
+		WorkspaceBuildApprovalStatusApproved,
+		WorkspaceBuildApprovalStatusRejected:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceBuildApprovalStatusValues() []WorkspaceBuildApprovalStatus {
+	return []WorkspaceBuildApprovalStatus{
+		WorkspaceBuildApprovalStatusPending,
+		WorkspaceBuildApprovalStatusApproved,
+		WorkspaceBuildApprovalStatusRejected,
+	}
+}
+
+type WorkspaceScheduledActionType string
+
+const (
+	WorkspaceScheduledActionTypeStart   WorkspaceScheduledActionType = "start"
+	WorkspaceScheduledActionTypeStop    WorkspaceScheduledActionType = "stop"
+	WorkspaceScheduledActionTypeRestart WorkspaceScheduledActionType = "restart"
+	WorkspaceScheduledActionTypeUpdate  WorkspaceScheduledActionType = "update"
+)
+
+func (e *WorkspaceScheduledActionType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceScheduledActionType(s)
+	case string:
+		*e = WorkspaceScheduledActionType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceScheduledActionType: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceScheduledActionType struct {
+	WorkspaceScheduledActionType WorkspaceScheduledActionType `json:"workspace_scheduled_action_type"`
+	Valid                        bool                         `json:"valid"` // Valid is true if WorkspaceScheduledActionType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceScheduledActionType) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceScheduledActionType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceScheduledActionType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceScheduledActionType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceScheduledActionType), nil
+}
+
+func (e WorkspaceScheduledActionType) Valid() bool {
+	switch e {
+	case WorkspaceScheduledActionTypeStart,
+		WorkspaceScheduledActionTypeStop,
+		WorkspaceScheduledActionTypeRestart,
+		WorkspaceScheduledActionTypeUpdate:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceScheduledActionTypeValues() []WorkspaceScheduledActionType {
+	return []WorkspaceScheduledActionType{
+		WorkspaceScheduledActionTypeStart,
+		WorkspaceScheduledActionTypeStop,
+		WorkspaceScheduledActionTypeRestart,
+		WorkspaceScheduledActionTypeUpdate,
+	}
+}
+
+type WorkspaceSessionRecordingType string
+
+const (
+	WorkspaceSessionRecordingTypeSsh             WorkspaceSessionRecordingType = "ssh"
+	WorkspaceSessionRecordingTypeReconnectingPty WorkspaceSessionRecordingType = "reconnecting_pty"
+)
+
+func (e *WorkspaceSessionRecordingType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceSessionRecordingType(s)
+	case string:
+		*e = WorkspaceSessionRecordingType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceSessionRecordingType: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceSessionRecordingType struct {
+	WorkspaceSessionRecordingType WorkspaceSessionRecordingType `json:"workspace_session_recording_type"`
+	Valid                         bool                          `json:"valid"` // Valid is true if WorkspaceSessionRecordingType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceSessionRecordingType) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceSessionRecordingType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceSessionRecordingType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceSessionRecordingType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceSessionRecordingType), nil
+}
+
+func (e WorkspaceSessionRecordingType) Valid() bool {
+	switch e {
+	case WorkspaceSessionRecordingTypeSsh,
+		WorkspaceSessionRecordingTypeReconnectingPty:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceSessionRecordingTypeValues() []WorkspaceSessionRecordingType {
+	return []WorkspaceSessionRecordingType{
+		WorkspaceSessionRecordingTypeSsh,
+		WorkspaceSessionRecordingTypeReconnectingPty,
+	}
+}
+
 type WorkspaceTransition string
 
 const (
@@ -2349,6 +2748,10 @@ type APIKey struct {
 	IPAddress       pqtype.Inet `db:"ip_address" json:"ip_address"`
 	Scope           APIKeyScope `db:"scope" json:"scope"`
 	TokenName       string      `db:"token_name" json:"token_name"`
+	// The resource types and actions a key with the custom scope is limited to, optionally within one organization.
+	ScopePermissions APIKeyScopePermissions `db:"scope_permissions" json:"scope_permissions"`
+	// The IDs of the resources a key with the custom scope is limited to. An empty list allows all resources.
+	ScopeAllowList []string `db:"scope_allow_list" json:"scope_allow_list"`
 }
 
 type AuditLog struct {
@@ -2369,6 +2772,23 @@ type AuditLog struct {
 	ResourceIcon     string          `db:"resource_icon" json:"resource_icon"`
 }
 
+// Monthly limits on the cost of the workspaces owned by the members of a group.
+type CostBudget struct {
+	GroupID        uuid.UUID `db:"group_id" json:"group_id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
+	MonthlyLimit   float64   `db:"monthly_limit" json:"monthly_limit"`
+	// Members are notified when the cost of their workspaces reaches this percentage of the monthly limit. 0 disables notifications.
+	NotifyThresholdPercent int32 `db:"notify_threshold_percent" json:"notify_threshold_percent"`
+	// Running workspaces of members are stopped when the cost of their workspaces reaches this percentage of the monthly limit. 0 disables stopping workspaces.
+	StopThresholdPercent int32 `db:"stop_threshold_percent" json:"stop_threshold_percent"`
+	// The last time members were notified that the notify threshold was reached.
+	NotifiedAt sql.NullTime `db:"notified_at" json:"notified_at"`
+	// The last time members were notified that the stop threshold was reached.
+	StoppedAt sql.NullTime `db:"stopped_at" json:"stopped_at"`
+}
+
 type CryptoKey struct {
 	Feature     CryptoKeyFeature `db:"feature" json:"feature"`
 	Sequence    int32            `db:"sequence" json:"sequence"`
@@ -2407,6 +2827,10 @@ type DBCryptKey struct {
 	RevokedAt sql.NullTime `db:"revoked_at" json:"revoked_at"`
 	// A column used to test the encryption.
 	Test string `db:"test" json:"test"`
+	// The external key provider that wrapped the key, e.g. vault-transit:transit/coder. Null if the key was provided to Coder directly.
+	KeyProvider sql.NullString `db:"key_provider" json:"key_provider"`
+	// The key, encrypted by the key provider. Null if the key was provided to Coder directly.
+	WrappedKey sql.NullString `db:"wrapped_key" json:"wrapped_key"`
 }
 
 type ExternalAuthLink struct {
@@ -2499,6 +2923,30 @@ type License struct {
 	UUID uuid.UUID `db:"uuid" json:"uuid"`
 }
 
+// The latest type of the connections between tailnet clients and their peers.
+type NetworkConnectionReport struct {
+	ClientID     uuid.UUID `db:"client_id" json:"client_id"`
+	RemoteNodeID int64     `db:"remote_node_id" json:"remote_node_id"`
+	ClientType   string    `db:"client_type" json:"client_type"`
+	// The home DERP region of the client when the connection was reported.
+	HomeRegionID int32 `db:"home_region_id" json:"home_region_id"`
+	// Whether the connection is direct (peer-to-peer), rather than relayed through DERP.
+	Direct     bool      `db:"direct" json:"direct"`
+	ReportedAt time.Time `db:"reported_at" json:"reported_at"`
+}
+
+// The latest latency to each DERP region reported by tailnet clients, like agents and the CLI.
+type NetworkLatencyReport struct {
+	// The ID of the tailnet connection of the client.
+	ClientID   uuid.UUID `db:"client_id" json:"client_id"`
+	ClientType string    `db:"client_type" json:"client_type"`
+	RegionID   int32     `db:"region_id" json:"region_id"`
+	LatencyMS  float64   `db:"latency_ms" json:"latency_ms"`
+	// Whether the region is the preferred (home) region of the client.
+	Preferred  bool      `db:"preferred" json:"preferred"`
+	ReportedAt time.Time `db:"reported_at" json:"reported_at"`
+}
+
 type NotificationMessage struct {
 	ID                     uuid.UUID                 `db:"id" json:"id"`
 	NotificationTemplateID uuid.UUID                 `db:"notification_template_id" json:"notification_template_id"`
@@ -2525,6 +2973,8 @@ type NotificationPreference struct {
 	Disabled               bool      `db:"disabled" json:"disabled"`
 	CreatedAt              time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt              time.Time `db:"updated_at" json:"updated_at"`
+	// NULL defers to the template-level or deployment-level method
+	Method NullNotificationMethod `db:"method" json:"method"`
 }
 
 // Log of generated reports for users.
@@ -2555,6 +3005,10 @@ type OAuth2ProviderApp struct {
 	Name        string    `db:"name" json:"name"`
 	Icon        string    `db:"icon" json:"icon"`
 	CallbackURL string    `db:"callback_url" json:"callback_url"`
+	// Public clients cannot keep a secret, so they authenticate with PKCE instead of a client secret.
+	PublicClient bool `db:"public_client" json:"public_client"`
+	// The user that tokens issued with the client credentials grant act as. The grant is disabled when null.
+	ClientCredentialsUserID uuid.NullUUID `db:"client_credentials_user_id" json:"client_credentials_user_id"`
 }
 
 // Codes are meant to be exchanged for access tokens.
@@ -2566,6 +3020,8 @@ type OAuth2ProviderAppCode struct {
 	HashedSecret []byte    `db:"hashed_secret" json:"hashed_secret"`
 	UserID       uuid.UUID `db:"user_id" json:"user_id"`
 	AppID        uuid.UUID `db:"app_id" json:"app_id"`
+	// The S256 PKCE code challenge the code verifier must match when the code is exchanged. Empty if PKCE was not used.
+	CodeChallenge string `db:"code_challenge" json:"code_challenge"`
 }
 
 type OAuth2ProviderAppSecret struct {
@@ -2585,9 +3041,26 @@ type OAuth2ProviderAppToken struct {
 	ExpiresAt  time.Time `db:"expires_at" json:"expires_at"`
 	HashPrefix []byte    `db:"hash_prefix" json:"hash_prefix"`
 	// Refresh tokens provide a way to refresh an access token (API key). An expired API key can be refreshed if this token is not yet expired, meaning this expiry can outlive an API key.
-	RefreshHash []byte    `db:"refresh_hash" json:"refresh_hash"`
-	AppSecretID uuid.UUID `db:"app_secret_id" json:"app_secret_id"`
-	APIKeyID    string    `db:"api_key_id" json:"api_key_id"`
+	RefreshHash []byte        `db:"refresh_hash" json:"refresh_hash"`
+	AppSecretID uuid.NullUUID `db:"app_secret_id" json:"app_secret_id"`
+	APIKeyID    string        `db:"api_key_id" json:"api_key_id"`
+	AppID       uuid.UUID     `db:"app_id" json:"app_id"`
+}
+
+// Device codes are exchanged for access tokens once the user enters the user code and approves the device, see RFC 8628.
+type OAuth2ProviderDeviceCode struct {
+	ID           uuid.UUID                      `db:"id" json:"id"`
+	CreatedAt    time.Time                      `db:"created_at" json:"created_at"`
+	ExpiresAt    time.Time                      `db:"expires_at" json:"expires_at"`
+	SecretPrefix []byte                         `db:"secret_prefix" json:"secret_prefix"`
+	HashedSecret []byte                         `db:"hashed_secret" json:"hashed_secret"`
+	UserCode     string                         `db:"user_code" json:"user_code"`
+	AppID        uuid.UUID                      `db:"app_id" json:"app_id"`
+	Status       OAuth2ProviderDeviceCodeStatus `db:"status" json:"status"`
+	// The user that approved or denied the device.
+	UserID uuid.NullUUID `db:"user_id" json:"user_id"`
+	// The last time the device polled the token endpoint, used to tell devices polling too often to slow down.
+	LastPolledAt sql.NullTime `db:"last_polled_at" json:"last_polled_at"`
 }
 
 type Organization struct {
@@ -2677,6 +3150,8 @@ type ProvisionerJob struct {
 	TraceMetadata  pqtype.NullRawMessage    `db:"trace_metadata" json:"trace_metadata"`
 	// Computed column to track the status of the job.
 	JobStatus ProvisionerJobStatus `db:"job_status" json:"job_status"`
+	// Pending jobs with a higher priority are acquired first. Workspace builds started by users have the highest priority, followed by automatic workspace builds, then template version imports and dry-runs.
+	Priority int32 `db:"priority" json:"priority"`
 }
 
 type ProvisionerJobLog struct {
@@ -2689,6 +3164,17 @@ type ProvisionerJobLog struct {
 	ID        int64     `db:"id" json:"id"`
 }
 
+// The changes the plan of a provisioner job makes to each resource. Resources that do not change are not stored.
+type ProvisionerJobResourceChange struct {
+	JobID        uuid.UUID                          `db:"job_id" json:"job_id"`
+	Address      string                             `db:"address" json:"address"`
+	ResourceType string                             `db:"resource_type" json:"resource_type"`
+	ResourceName string                             `db:"resource_name" json:"resource_name"`
+	Action       ProvisionerJobResourceChangeAction `db:"action" json:"action"`
+	// The changed attributes of the resource. Values of sensitive attributes are never stored.
+	Attributes json.RawMessage `db:"attributes" json:"attributes"`
+}
+
 type ProvisionerJobStat struct {
 	JobID          uuid.UUID            `db:"job_id" json:"job_id"`
 	JobStatus      ProvisionerJobStatus `db:"job_status" json:"job_status"`
@@ -2740,6 +3226,25 @@ type Replica struct {
 	Primary         bool         `db:"primary" json:"primary"`
 }
 
+// Secrets that are injected into workspaces by the agent. A secret belongs to exactly one user, organization or template.
+type Secret struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+	Name        string    `db:"name" json:"name"`
+	Description string    `db:"description" json:"description"`
+	Value       string    `db:"value" json:"value"`
+	// The ID of the key used to encrypt the value. If this is NULL, the value is not encrypted
+	ValueKeyID sql.NullString `db:"value_key_id" json:"value_key_id"`
+	// The name of the environment variable the secret is injected as, or empty.
+	EnvName string `db:"env_name" json:"env_name"`
+	// The path of the file the secret is written to in the workspace, or empty.
+	FilePath       string        `db:"file_path" json:"file_path"`
+	UserID         uuid.NullUUID `db:"user_id" json:"user_id"`
+	OrganizationID uuid.NullUUID `db:"organization_id" json:"organization_id"`
+	TemplateID     uuid.NullUUID `db:"template_id" json:"template_id"`
+}
+
 type SiteConfig struct {
 	Key   string `db:"key" json:"key"`
 	Value string `db:"value" json:"value"`
@@ -2817,6 +3322,11 @@ type Template struct {
 	Deprecated                    string          `db:"deprecated" json:"deprecated"`
 	ActivityBump                  int64           `db:"activity_bump" json:"activity_bump"`
 	MaxPortSharingLevel           AppSharingLevel `db:"max_port_sharing_level" json:"max_port_sharing_level"`
+	SessionRecordingEnabled       bool            `db:"session_recording_enabled" json:"session_recording_enabled"`
+	RequireBuildApproval          bool            `db:"require_build_approval" json:"require_build_approval"`
+	BuildApprovalParameters       []string        `db:"build_approval_parameters" json:"build_approval_parameters"`
+	BuildApprovalGroupID          uuid.NullUUID   `db:"build_approval_group_id" json:"build_approval_group_id"`
+	BuildApprovalTimeout          int64           `db:"build_approval_timeout" json:"build_approval_timeout"`
 	CreatedByAvatarURL            string          `db:"created_by_avatar_url" json:"created_by_avatar_url"`
 	CreatedByUsername             string          `db:"created_by_username" json:"created_by_username"`
 	OrganizationName              string          `db:"organization_name" json:"organization_name"`
@@ -2862,6 +3372,40 @@ type TemplateTable struct {
 	Deprecated          string          `db:"deprecated" json:"deprecated"`
 	ActivityBump        int64           `db:"activity_bump" json:"activity_bump"`
 	MaxPortSharingLevel AppSharingLevel `db:"max_port_sharing_level" json:"max_port_sharing_level"`
+	// Record the output of interactive SSH and reconnecting PTY sessions in workspaces created from this template.
+	SessionRecordingEnabled bool `db:"session_recording_enabled" json:"session_recording_enabled"`
+	// Workspace start builds must be approved before they are queued for provisioners.
+	RequireBuildApproval bool `db:"require_build_approval" json:"require_build_approval"`
+	// If not empty, only builds that change the value of one of these parameters require approval.
+	BuildApprovalParameters []string `db:"build_approval_parameters" json:"build_approval_parameters"`
+	// The group whose members can approve builds. Template admins can always approve builds.
+	BuildApprovalGroupID uuid.NullUUID `db:"build_approval_group_id" json:"build_approval_group_id"`
+	// Builds that are not approved within this duration are rejected. 0 means builds never time out.
+	BuildApprovalTimeout int64 `db:"build_approval_timeout" json:"build_approval_timeout"`
+}
+
+// Staged rollouts of template versions. Workspaces included in a rollout in progress build its candidate version instead of the active version of the template.
+type TemplateRollout struct {
+	ID         uuid.UUID `db:"id" json:"id"`
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	// The candidate version that is rolled out.
+	TemplateVersionID uuid.UUID             `db:"template_version_id" json:"template_version_id"`
+	CreatedBy         uuid.UUID             `db:"created_by" json:"created_by"`
+	CreatedAt         time.Time             `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time             `db:"updated_at" json:"updated_at"`
+	CompletedAt       sql.NullTime          `db:"completed_at" json:"completed_at"`
+	Status            TemplateRolloutStatus `db:"status" json:"status"`
+	StatusReason      string                `db:"status_reason" json:"status_reason"`
+	// The percentage of workspaces of the template that are included in the rollout.
+	Percent int32 `db:"percent" json:"percent"`
+	// Workspaces owned by members of this group are included in the rollout regardless of percent.
+	GroupID uuid.NullUUID `db:"group_id" json:"group_id"`
+	// The number of completed builds of the candidate version required before the rollout is promoted or rolled back automatically.
+	MinBuilds int32 `db:"min_builds" json:"min_builds"`
+	// The rollout is rolled back when the share of failed builds of the candidate version exceeds this rate.
+	MaxFailureRate float64 `db:"max_failure_rate" json:"max_failure_rate"`
+	// Whether the candidate version is promoted automatically once min_builds builds completed within max_failure_rate.
+	AutoPromote bool `db:"auto_promote" json:"auto_promote"`
 }
 
 // Records aggregated usage statistics for templates/users. All usage is rounded up to the nearest minute.
@@ -3167,6 +3711,22 @@ type WorkspaceAgentPortShare struct {
 	Protocol    PortShareProtocol `db:"protocol" json:"protocol"`
 }
 
+// The latest resource usage of the cgroups that agents place their SSH sessions and scripts in.
+type WorkspaceAgentProcessGroup struct {
+	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
+	Name             string    `db:"name" json:"name"`
+	CPUUsageUsec     int64     `db:"cpu_usage_usec" json:"cpu_usage_usec"`
+	// The average number of CPUs used since the previous report of the agent.
+	CPUCores float64 `db:"cpu_cores" json:"cpu_cores"`
+	// The number of CPUs the group may use, or 0 if unlimited.
+	CPULimitCores float64 `db:"cpu_limit_cores" json:"cpu_limit_cores"`
+	MemoryBytes   int64   `db:"memory_bytes" json:"memory_bytes"`
+	// The memory limit of the group, or 0 if unlimited.
+	MemoryLimitBytes int64     `db:"memory_limit_bytes" json:"memory_limit_bytes"`
+	OOMKills         int64     `db:"oom_kills" json:"oom_kills"`
+	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
+}
+
 type WorkspaceAgentScript struct {
 	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
 	LogSourceID      uuid.UUID `db:"log_source_id" json:"log_source_id"`
@@ -3279,6 +3839,20 @@ type WorkspaceBuild struct {
 	InitiatorByUsername  string              `db:"initiator_by_username" json:"initiator_by_username"`
 }
 
+// Workspace builds that require approval before their provisioner job can be acquired.
+type WorkspaceBuildApproval struct {
+	WorkspaceBuildID uuid.UUID `db:"workspace_build_id" json:"workspace_build_id"`
+	CreatedAt        time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
+	// Pending approvals are rejected after this time. NULL means the approval never expires.
+	ExpiresAt sql.NullTime                 `db:"expires_at" json:"expires_at"`
+	Status    WorkspaceBuildApprovalStatus `db:"status" json:"status"`
+	// The user that approved or rejected the build. NULL if the build is pending or timed out.
+	ReviewerID uuid.NullUUID `db:"reviewer_id" json:"reviewer_id"`
+	ReviewedAt sql.NullTime  `db:"reviewed_at" json:"reviewed_at"`
+	Reason     string        `db:"reason" json:"reason"`
+}
+
 type WorkspaceBuildParameter struct {
 	WorkspaceBuildID uuid.UUID `db:"workspace_build_id" json:"workspace_build_id"`
 	// Parameter name
@@ -3304,6 +3878,17 @@ type WorkspaceBuildTable struct {
 	MaxDeadline       time.Time           `db:"max_deadline" json:"max_deadline"`
 }
 
+// Records the cost of workspaces in hourly buckets, rolled up from the hourly cost of the resources of their builds.
+type WorkspaceCost struct {
+	// Start time of the hour the cost was accrued in.
+	BucketStart    time.Time `db:"bucket_start" json:"bucket_start"`
+	WorkspaceID    uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	OwnerID        uuid.UUID `db:"owner_id" json:"owner_id"`
+	TemplateID     uuid.UUID `db:"template_id" json:"template_id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Cost           float64   `db:"cost" json:"cost"`
+}
+
 type WorkspaceModule struct {
 	ID         uuid.UUID           `db:"id" json:"id"`
 	JobID      uuid.UUID           `db:"job_id" json:"job_id"`
@@ -3349,6 +3934,8 @@ type WorkspaceResource struct {
 	InstanceType sql.NullString      `db:"instance_type" json:"instance_type"`
 	DailyCost    int32               `db:"daily_cost" json:"daily_cost"`
 	ModulePath   sql.NullString      `db:"module_path" json:"module_path"`
+	// The cost of running the resource for an hour, declared with the hourly_cost metadata item of the resource.
+	HourlyCost float64 `db:"hourly_cost" json:"hourly_cost"`
 }
 
 type WorkspaceResourceMetadatum struct {
@@ -3359,6 +3946,58 @@ type WorkspaceResourceMetadatum struct {
 	ID                  int64          `db:"id" json:"id"`
 }
 
+// User-defined schedules that build workspaces, executed by the lifecycle executor.
+type WorkspaceScheduledAction struct {
+	ID          uuid.UUID                    `db:"id" json:"id"`
+	WorkspaceID uuid.UUID                    `db:"workspace_id" json:"workspace_id"`
+	CreatedAt   time.Time                    `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time                    `db:"updated_at" json:"updated_at"`
+	Action      WorkspaceScheduledActionType `db:"action" json:"action"`
+	// Cron schedule with an optional CRON_TZ prefix, in the same format as workspace autostart schedules.
+	Schedule  string    `db:"schedule" json:"schedule"`
+	NextRunAt time.Time `db:"next_run_at" json:"next_run_at"`
+	// The last time the action ran. A restart that has stopped the workspace but not started it yet has a last_run_at on or after its next_run_at.
+	LastRunAt sql.NullTime `db:"last_run_at" json:"last_run_at"`
+}
+
+// Recordings of interactive sessions in workspaces, in the asciicast v2 format.
+type WorkspaceSessionRecording struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	// Set once the agent has uploaded the final chunk of the recording.
+	EndedAt     sql.NullTime `db:"ended_at" json:"ended_at"`
+	WorkspaceID uuid.UUID    `db:"workspace_id" json:"workspace_id"`
+	AgentID     uuid.UUID    `db:"agent_id" json:"agent_id"`
+	// The owner of the workspace at the time of the recording.
+	UserID uuid.UUID                     `db:"user_id" json:"user_id"`
+	Type   WorkspaceSessionRecordingType `db:"type" json:"type"`
+	// The total size of all chunks in bytes.
+	Size int64 `db:"size" json:"size"`
+}
+
+type WorkspaceSessionRecordingChunk struct {
+	RecordingID uuid.UUID `db:"recording_id" json:"recording_id"`
+	Sequence    int32     `db:"sequence" json:"sequence"`
+	Data        []byte    `db:"data" json:"data"`
+}
+
+// Snapshots of workspace data taken by templates which declare coder_workspace_snapshot resources. Snapshots outlive the workspace they were taken from and can be restored into any workspace of the same owner and template.
+type WorkspaceSnapshot struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	OwnerID        uuid.UUID `db:"owner_id" json:"owner_id"`
+	TemplateID     uuid.UUID `db:"template_id" json:"template_id"`
+	WorkspaceID    uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	// The workspace build which took the snapshot.
+	BuildID uuid.UUID `db:"build_id" json:"build_id"`
+	Name    string    `db:"name" json:"name"`
+	// The artifacts reported by each coder_workspace_snapshot resource, keyed by resource name.
+	Artifacts json.RawMessage `db:"artifacts" json:"artifacts"`
+}
+
 type WorkspaceTable struct {
 	ID                uuid.UUID        `db:"id" json:"id"`
 	CreatedAt         time.Time        `db:"created_at" json:"created_at"`
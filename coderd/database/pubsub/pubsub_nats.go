@@ -0,0 +1,371 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+)
+
+// natsFlushTimeout bounds how long Subscribe waits for the NATS server to
+// acknowledge a new subscription.
+const natsFlushTimeout = 10 * time.Second
+
+// natsQueueSet holds the subscribers for a single event. Each event has one
+// NATS subscription that fans out to all of them.
+type natsQueueSet struct {
+	sub *nats.Subscription
+	m   map[*msgQueue]struct{}
+}
+
+// NATSPubsub is a pubsub implementation using NATS core messaging. Unlike
+// PGPubsub, messages aren't limited to 8KB and events don't share a single
+// database connection, so it scales better for large deployments.
+type NATSPubsub struct {
+	logger slog.Logger
+	conn   *nats.Conn
+	prefix string
+
+	qMu    sync.Mutex
+	queues map[string]*natsQueueSet
+
+	publishesTotal      *prometheus.CounterVec
+	subscribesTotal     *prometheus.CounterVec
+	messagesTotal       *prometheus.CounterVec
+	publishedBytesTotal prometheus.Counter
+	receivedBytesTotal  prometheus.Counter
+	disconnectionsTotal prometheus.Counter
+	connected           prometheus.Gauge
+
+	latencyMeasurer       *LatencyMeasurer
+	latencyMeasureCounter atomic.Int64
+	latencyErrCounter     atomic.Int64
+}
+
+// Subscribe calls the listener when an event matching the name is received.
+func (p *NATSPubsub) Subscribe(event string, listener Listener) (cancel func(), err error) {
+	return p.subscribeQueue(event, newMsgQueue(context.Background(), listener, nil))
+}
+
+func (p *NATSPubsub) SubscribeWithErr(event string, listener ListenerWithErr) (cancel func(), err error) {
+	return p.subscribeQueue(event, newMsgQueue(context.Background(), nil, listener))
+}
+
+func (p *NATSPubsub) subscribeQueue(event string, newQ *msgQueue) (cancel func(), err error) {
+	defer func() {
+		if err != nil {
+			// if we hit an error, we need to close the queue so we don't
+			// leak its goroutine.
+			newQ.close()
+			p.subscribesTotal.WithLabelValues("false").Inc()
+		} else {
+			p.subscribesTotal.WithLabelValues("true").Inc()
+		}
+	}()
+
+	// Subscribing and unsubscribing only buffer a command for the server, so
+	// it's safe to hold the lock while doing so.
+	err = func() error {
+		p.qMu.Lock()
+		defer p.qMu.Unlock()
+		qs, ok := p.queues[event]
+		if !ok {
+			sub, err := p.conn.Subscribe(p.subject(event), func(msg *nats.Msg) {
+				p.receive(event, msg)
+			})
+			if err != nil {
+				return xerrors.Errorf("subscribe: %w", err)
+			}
+			qs = &natsQueueSet{sub: sub, m: make(map[*msgQueue]struct{})}
+			p.queues[event] = qs
+			p.logger.Debug(context.Background(), "started listening to event channel", slog.F("event", event))
+		}
+		qs.m[newQ] = struct{}{}
+		return nil
+	}()
+	if err != nil {
+		return nil, err
+	}
+
+	cancel = func() {
+		p.qMu.Lock()
+		defer p.qMu.Unlock()
+		newQ.close()
+		qs, ok := p.queues[event]
+		if !ok {
+			p.logger.Critical(context.Background(), "event was removed before cancel", slog.F("event", event))
+			return
+		}
+		delete(qs.m, newQ)
+		if len(qs.m) > 0 {
+			return
+		}
+		delete(p.queues, event)
+		err := qs.sub.Unsubscribe()
+		if err != nil && !errors.Is(err, nats.ErrConnectionClosed) {
+			p.logger.Warn(context.Background(), "failed to unsubscribe", slog.Error(err), slog.F("event", event))
+			return
+		}
+		p.logger.Debug(context.Background(), "stopped listening to event channel", slog.F("event", event))
+	}
+
+	// Subscribe returns before the server has registered our interest in the
+	// subject. Waiting for a round trip ensures that messages published after
+	// we return are delivered to the listener.
+	err = p.conn.FlushTimeout(natsFlushTimeout)
+	if err != nil {
+		cancel()
+		return nil, xerrors.Errorf("flush subscription: %w", err)
+	}
+	return cancel, nil
+}
+
+func (p *NATSPubsub) Publish(event string, message []byte) error {
+	p.logger.Debug(context.Background(), "publish", slog.F("event", event), slog.F("message_len", len(message)))
+	err := p.conn.Publish(p.subject(event), message)
+	if err != nil {
+		p.publishesTotal.WithLabelValues("false").Inc()
+		return xerrors.Errorf("nats publish: %w", err)
+	}
+	p.publishesTotal.WithLabelValues("true").Inc()
+	p.publishedBytesTotal.Add(float64(len(message)))
+	return nil
+}
+
+// Close closes the pubsub instance.
+func (p *NATSPubsub) Close() error {
+	p.logger.Info(context.Background(), "pubsub is closing")
+	p.conn.Close()
+	p.logger.Debug(context.Background(), "pubsub closed")
+	return nil
+}
+
+func (p *NATSPubsub) receive(event string, msg *nats.Msg) {
+	sizeLabel := messageSizeNormal
+	if len(msg.Data) >= colossalThreshold {
+		sizeLabel = messageSizeColossal
+	}
+	p.messagesTotal.WithLabelValues(sizeLabel).Inc()
+	p.receivedBytesTotal.Add(float64(len(msg.Data)))
+
+	p.qMu.Lock()
+	defer p.qMu.Unlock()
+	qs, ok := p.queues[event]
+	if !ok {
+		return
+	}
+	for q := range qs.m {
+		q.enqueue(msg.Data)
+	}
+}
+
+// recordDropped notifies the subscribers of sub, or of every event if sub is
+// nil, that messages might have been dropped.
+func (p *NATSPubsub) recordDropped(sub *nats.Subscription) {
+	p.qMu.Lock()
+	defer p.qMu.Unlock()
+	for _, qs := range p.queues {
+		if sub != nil && qs.sub != sub {
+			continue
+		}
+		for q := range qs.m {
+			q.dropped()
+		}
+	}
+}
+
+// subject maps an event onto a NATS subject. Subjects can't contain
+// whitespace, and '.', '*' and '>' separate tokens and act as wildcards, so
+// these are percent-encoded along with '%' itself.
+func (p *NATSPubsub) subject(event string) string {
+	var sb strings.Builder
+	_, _ = sb.WriteString(p.prefix)
+	_ = sb.WriteByte('.')
+	for i := 0; i < len(event); i++ {
+		c := event[i]
+		switch {
+		case c == '.', c == '*', c == '>', c == '%', c <= ' ', c >= 0x7f:
+			_, _ = fmt.Fprintf(&sb, "%%%02X", c)
+		default:
+			_ = sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+// Describe implements, along with Collect, the prometheus.Collector interface
+// for metrics.
+func (p *NATSPubsub) Describe(descs chan<- *prometheus.Desc) {
+	// explicit metrics
+	p.publishesTotal.Describe(descs)
+	p.subscribesTotal.Describe(descs)
+	p.messagesTotal.Describe(descs)
+	p.publishedBytesTotal.Describe(descs)
+	p.receivedBytesTotal.Describe(descs)
+	p.disconnectionsTotal.Describe(descs)
+	p.connected.Describe(descs)
+
+	// implicit metrics
+	descs <- currentSubscribersDesc
+	descs <- currentEventsDesc
+
+	// additional metrics
+	descs <- pubsubSendLatencyDesc
+	descs <- pubsubRecvLatencyDesc
+	descs <- pubsubLatencyMeasureCountDesc
+	descs <- pubsubLatencyMeasureErrDesc
+}
+
+// Collect implements, along with Describe, the prometheus.Collector interface
+// for metrics
+func (p *NATSPubsub) Collect(metrics chan<- prometheus.Metric) {
+	// explicit metrics
+	p.publishesTotal.Collect(metrics)
+	p.subscribesTotal.Collect(metrics)
+	p.messagesTotal.Collect(metrics)
+	p.publishedBytesTotal.Collect(metrics)
+	p.receivedBytesTotal.Collect(metrics)
+	p.disconnectionsTotal.Collect(metrics)
+	p.connected.Collect(metrics)
+
+	// implicit metrics
+	p.qMu.Lock()
+	events := len(p.queues)
+	subs := 0
+	for _, qs := range p.queues {
+		subs += len(qs.m)
+	}
+	p.qMu.Unlock()
+	metrics <- prometheus.MustNewConstMetric(currentSubscribersDesc, prometheus.GaugeValue, float64(subs))
+	metrics <- prometheus.MustNewConstMetric(currentEventsDesc, prometheus.GaugeValue, float64(events))
+
+	// additional metrics
+	ctx, cancel := context.WithTimeout(context.Background(), LatencyMeasureTimeout)
+	defer cancel()
+	send, recv, err := p.latencyMeasurer.Measure(ctx, p)
+
+	metrics <- prometheus.MustNewConstMetric(pubsubLatencyMeasureCountDesc, prometheus.CounterValue, float64(p.latencyMeasureCounter.Add(1)))
+	if err != nil {
+		p.logger.Warn(context.Background(), "failed to measure latency", slog.Error(err))
+		metrics <- prometheus.MustNewConstMetric(pubsubLatencyMeasureErrDesc, prometheus.CounterValue, float64(p.latencyErrCounter.Add(1)))
+		return
+	}
+	metrics <- prometheus.MustNewConstMetric(pubsubSendLatencyDesc, prometheus.GaugeValue, send.Seconds())
+	metrics <- prometheus.MustNewConstMetric(pubsubRecvLatencyDesc, prometheus.GaugeValue, recv.Seconds())
+}
+
+// NewNATS creates a new Pubsub implementation using a NATS server or cluster.
+// url may contain multiple comma-separated server URLs. Events are published
+// to subjects beginning with subjectPrefix. Additional options are applied
+// after the defaults, e.g. to configure authentication or TLS.
+func NewNATS(logger slog.Logger, url, subjectPrefix string, opts ...nats.Option) (*NATSPubsub, error) {
+	if subjectPrefix == "" {
+		return nil, xerrors.New("subject prefix must not be empty")
+	}
+	if strings.ContainsAny(subjectPrefix, " \t\r\n*>") || strings.HasPrefix(subjectPrefix, ".") || strings.HasSuffix(subjectPrefix, ".") {
+		return nil, xerrors.Errorf("invalid subject prefix %q", subjectPrefix)
+	}
+	p := newNATSWithoutConn(logger, subjectPrefix)
+
+	opts = append([]nats.Option{
+		nats.Name("coderd"),
+		// Never give up reconnecting. Subscribers are told they might have
+		// missed messages once we're connected again.
+		nats.MaxReconnects(-1),
+		nats.DisconnectErrHandler(func(conn *nats.Conn, err error) {
+			if conn.IsClosed() {
+				// We disconnected because Close was called.
+				return
+			}
+			p.logger.Warn(context.Background(), "pubsub disconnected from nats", slog.Error(err))
+			p.disconnectionsTotal.Inc()
+			p.connected.Set(0)
+		}),
+		nats.ReconnectHandler(func(conn *nats.Conn) {
+			p.logger.Info(context.Background(), "pubsub reconnected to nats", slog.F("server", conn.ConnectedUrlRedacted()))
+			p.connected.Set(1)
+			p.recordDropped(nil)
+		}),
+		nats.ClosedHandler(func(_ *nats.Conn) {
+			p.connected.Set(0)
+		}),
+		nats.ErrorHandler(func(_ *nats.Conn, sub *nats.Subscription, err error) {
+			if errors.Is(err, nats.ErrSlowConsumer) && sub != nil {
+				p.logger.Warn(context.Background(), "nats dropped messages for slow subscription", slog.F("subject", sub.Subject))
+				p.recordDropped(sub)
+				return
+			}
+			p.logger.Error(context.Background(), "nats error", slog.Error(err))
+		}),
+	}, opts...)
+
+	conn, err := nats.Connect(url, opts...)
+	if err != nil {
+		return nil, xerrors.Errorf("connect to nats: %w", err)
+	}
+	p.conn = conn
+	p.connected.Set(1)
+	logger.Debug(context.Background(), "pubsub has started", slog.F("server", conn.ConnectedUrlRedacted()))
+	return p, nil
+}
+
+func newNATSWithoutConn(logger slog.Logger, subjectPrefix string) *NATSPubsub {
+	return &NATSPubsub{
+		logger:          logger,
+		prefix:          subjectPrefix,
+		queues:          make(map[string]*natsQueueSet),
+		latencyMeasurer: NewLatencyMeasurer(logger.Named("latency-measurer")),
+
+		publishesTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "coder",
+			Subsystem: "pubsub",
+			Name:      "publishes_total",
+			Help:      "Total number of calls to Publish",
+		}, []string{"success"}),
+		subscribesTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "coder",
+			Subsystem: "pubsub",
+			Name:      "subscribes_total",
+			Help:      "Total number of calls to Subscribe/SubscribeWithErr",
+		}, []string{"success"}),
+		messagesTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "coder",
+			Subsystem: "pubsub",
+			Name:      "messages_total",
+			Help:      "Total number of messages received from nats",
+		}, []string{"size"}),
+		publishedBytesTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "coder",
+			Subsystem: "pubsub",
+			Name:      "published_bytes_total",
+			Help:      "Total number of bytes successfully published across all publishes",
+		}),
+		receivedBytesTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "coder",
+			Subsystem: "pubsub",
+			Name:      "received_bytes_total",
+			Help:      "Total number of bytes received across all messages",
+		}),
+		disconnectionsTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "coder",
+			Subsystem: "pubsub",
+			Name:      "disconnections_total",
+			Help:      "Total number of times we disconnected unexpectedly from nats",
+		}),
+		connected: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "coder",
+			Subsystem: "pubsub",
+			Name:      "connected",
+			Help:      "Whether we are connected (1) or not connected (0) to nats",
+		}),
+	}
+}
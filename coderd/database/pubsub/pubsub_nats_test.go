@@ -0,0 +1,184 @@
+package pubsub_test
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestNATSPubsub(t *testing.T) {
+	t.Parallel()
+
+	t.Run("PublishSubscribe", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		ns := startNATSServer(t, server.RANDOM_PORT)
+		uut, err := pubsub.NewNATS(testutil.Logger(t), ns.ClientURL(), "coder")
+		require.NoError(t, err)
+		defer uut.Close()
+
+		// Events containing characters that are special in NATS subjects
+		// must not match each other.
+		const event = "workspace.owner:*>"
+		messages := make(chan []byte, 2)
+		cancel, err := uut.Subscribe(event, func(_ context.Context, message []byte) {
+			messages <- message
+		})
+		require.NoError(t, err)
+		otherCancel, err := uut.Subscribe("workspace.other", func(_ context.Context, message []byte) {
+			assert.Fail(t, "unexpected message for other event", string(message))
+		})
+		require.NoError(t, err)
+		defer otherCancel()
+
+		// Messages are not limited to the 8KB postgres NOTIFY payload.
+		large := []byte(strings.Repeat("q", 64*1024))
+		require.NoError(t, uut.Publish(event, []byte("small")))
+		require.NoError(t, uut.Publish(event, large))
+		require.Equal(t, []byte("small"), testutil.RequireRecvCtx(ctx, t, messages))
+		require.Equal(t, large, testutil.RequireRecvCtx(ctx, t, messages))
+
+		// No more messages after canceling.
+		cancel()
+		require.NoError(t, uut.Publish(event, []byte("canceled")))
+		resubscribed, err := uut.Subscribe(event, func(_ context.Context, _ []byte) {})
+		require.NoError(t, err)
+		defer resubscribed()
+		require.NoError(t, uut.Publish(event, []byte("canceled")))
+		select {
+		case msg := <-messages:
+			t.Fatalf("unexpected message after cancel: %q", msg)
+		case <-time.After(testutil.IntervalMedium):
+		}
+	})
+
+	t.Run("BetweenReplicas", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		ns := startNATSServer(t, server.RANDOM_PORT)
+		first, err := pubsub.NewNATS(testutil.Logger(t), ns.ClientURL(), "coder")
+		require.NoError(t, err)
+		defer first.Close()
+		second, err := pubsub.NewNATS(testutil.Logger(t), ns.ClientURL(), "coder")
+		require.NoError(t, err)
+		defer second.Close()
+		// A deployment sharing the NATS server with a different prefix.
+		other, err := pubsub.NewNATS(testutil.Logger(t), ns.ClientURL(), "other")
+		require.NoError(t, err)
+		defer other.Close()
+
+		messages := make(chan []byte, 1)
+		cancel, err := second.Subscribe("test", func(_ context.Context, message []byte) {
+			messages <- message
+		})
+		require.NoError(t, err)
+		defer cancel()
+
+		require.NoError(t, other.Publish("test", []byte("other")))
+		require.NoError(t, first.Publish("test", []byte("first")))
+		require.Equal(t, []byte("first"), testutil.RequireRecvCtx(ctx, t, messages))
+	})
+
+	t.Run("Reconnect", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		ns := startNATSServer(t, server.RANDOM_PORT)
+		port := ns.Addr().(*net.TCPAddr).Port
+		uut, err := pubsub.NewNATS(testutil.Logger(t), ns.ClientURL(), "coder", nats.ReconnectWait(testutil.IntervalFast))
+		require.NoError(t, err)
+		defer uut.Close()
+
+		messages := make(chan []byte, 1)
+		errs := make(chan error, 1)
+		cancel, err := uut.SubscribeWithErr("test", func(_ context.Context, message []byte, err error) {
+			if err != nil {
+				errs <- err
+				return
+			}
+			messages <- message
+		})
+		require.NoError(t, err)
+		defer cancel()
+
+		ns.Shutdown()
+		ns.WaitForShutdown()
+		startNATSServer(t, port)
+
+		// Subscribers are told messages might have been missed while we were
+		// disconnected, and the subscription is restored.
+		require.ErrorIs(t, testutil.RequireRecvCtx(ctx, t, errs), pubsub.ErrDroppedMessages)
+		require.NoError(t, uut.Publish("test", []byte("reconnected")))
+		require.Equal(t, []byte("reconnected"), testutil.RequireRecvCtx(ctx, t, messages))
+	})
+
+	t.Run("InvalidPrefix", func(t *testing.T) {
+		t.Parallel()
+		_, err := pubsub.NewNATS(testutil.Logger(t), nats.DefaultURL, "coder.*")
+		require.ErrorContains(t, err, "invalid subject prefix")
+	})
+}
+
+func TestNATSPubsub_Metrics(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	ns := startNATSServer(t, server.RANDOM_PORT)
+	registry := prometheus.NewRegistry()
+	uut, err := pubsub.NewNATS(testutil.Logger(t), ns.ClientURL(), "coder")
+	require.NoError(t, err)
+	defer uut.Close()
+	require.NoError(t, registry.Register(uut))
+
+	messages := make(chan []byte, 2)
+	for i := 0; i < 2; i++ {
+		cancel, err := uut.Subscribe("test", func(_ context.Context, message []byte) {
+			messages <- message
+		})
+		require.NoError(t, err)
+		defer cancel()
+	}
+	colossalData := []byte(strings.Repeat("q", 7600))
+	require.NoError(t, uut.Publish("test", colossalData))
+	_ = testutil.RequireRecvCtx(ctx, t, messages)
+	_ = testutil.RequireRecvCtx(ctx, t, messages)
+
+	// each Gather measures pubsub latency by publishing a message & subscribing to it
+	metrics, err := registry.Gather()
+	require.NoError(t, err)
+	require.True(t, testutil.PromGaugeHasValue(t, metrics, 1, "coder_pubsub_current_events"))
+	require.True(t, testutil.PromGaugeHasValue(t, metrics, 2, "coder_pubsub_current_subscribers"))
+	require.True(t, testutil.PromGaugeHasValue(t, metrics, 1, "coder_pubsub_connected"))
+	require.True(t, testutil.PromCounterHasValue(t, metrics, 1, "coder_pubsub_publishes_total", "true"))
+	require.True(t, testutil.PromCounterHasValue(t, metrics, 2, "coder_pubsub_subscribes_total", "true"))
+	require.True(t, testutil.PromCounterHasValue(t, metrics, 1, "coder_pubsub_messages_total", "colossal"))
+	require.True(t, testutil.PromCounterHasValue(t, metrics, 7600, "coder_pubsub_published_bytes_total"))
+	require.True(t, testutil.PromCounterHasValue(t, metrics, 1, "coder_pubsub_latency_measures_total"))
+	require.False(t, testutil.PromCounterGathered(t, metrics, "coder_pubsub_latency_measure_errs_total"))
+	require.True(t, testutil.PromGaugeAssertion(t, metrics, func(in float64) bool { return in > 0 }, "coder_pubsub_receive_latency_seconds"))
+}
+
+func startNATSServer(t *testing.T, port int) *server.Server {
+	t.Helper()
+	ns, err := server.NewServer(&server.Options{
+		Host:   "127.0.0.1",
+		Port:   port,
+		NoLog:  true,
+		NoSigs: true,
+	})
+	require.NoError(t, err)
+	go ns.Start()
+	t.Cleanup(ns.Shutdown)
+	require.True(t, ns.ReadyForConnections(testutil.WaitShort), "nats server not ready")
+	return ns
+}
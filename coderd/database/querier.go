@@ -32,6 +32,8 @@ type sqlcQuerier interface {
 	// Acquires the lock for a single job that isn't started, completed,
 	// canceled, and that matches an array of provisioner types.
 	//
+	// Jobs with a higher priority are acquired first.
+	//
 	// SKIP LOCKED is used to jump over locked rows. This prevents
 	// multiple provisioners from acquiring the same jobs. See:
 	// https://www.postgresql.org/docs/9.5/sql-select.html#SQL-FOR-UPDATE-SHARE
@@ -63,6 +65,10 @@ type sqlcQuerier interface {
 	CleanTailnetCoordinators(ctx context.Context) error
 	CleanTailnetLostPeers(ctx context.Context) error
 	CleanTailnetTunnels(ctx context.Context) error
+	// Clear the Terraform state of workspace builds created before @before_time
+	// in batches of @limit_count rows. Only the state of the latest build of each
+	// workspace is used by subsequent builds, so it is always kept.
+	ClearOldWorkspaceBuildProvisionerStates(ctx context.Context, arg ClearOldWorkspaceBuildProvisionerStatesParams) (int64, error)
 	CustomRoles(ctx context.Context, arg CustomRolesParams) ([]CustomRole, error)
 	DeleteAPIKeyByID(ctx context.Context, id string) error
 	DeleteAPIKeysByUserID(ctx context.Context, userID uuid.UUID) error
@@ -70,6 +76,7 @@ type sqlcQuerier interface {
 	DeleteAllTailnetTunnels(ctx context.Context, arg DeleteAllTailnetTunnelsParams) error
 	DeleteApplicationConnectAPIKeysByUserID(ctx context.Context, userID uuid.UUID) error
 	DeleteCoordinator(ctx context.Context, id uuid.UUID) error
+	DeleteCostBudgetByGroupID(ctx context.Context, groupID uuid.UUID) error
 	DeleteCryptoKey(ctx context.Context, arg DeleteCryptoKeyParams) (CryptoKey, error)
 	DeleteCustomRole(ctx context.Context, arg DeleteCustomRoleParams) error
 	DeleteExternalAuthLink(ctx context.Context, arg DeleteExternalAuthLinkParams) error
@@ -82,23 +89,34 @@ type sqlcQuerier interface {
 	DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx context.Context, arg DeleteOAuth2ProviderAppCodesByAppAndUserIDParams) error
 	DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) error
 	DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx context.Context, arg DeleteOAuth2ProviderAppTokensByAppAndUserIDParams) error
-	// Delete all notification messages which have not been updated for over a week.
-	DeleteOldNotificationMessages(ctx context.Context) error
+	DeleteOAuth2ProviderDeviceCodeByID(ctx context.Context, id uuid.UUID) error
+	// Delete audit logs older than @before_time in batches of @limit_count rows
+	// to keep the load on the database low.
+	DeleteOldAuditLogs(ctx context.Context, arg DeleteOldAuditLogsParams) (int64, error)
+	// Network reports are only used for insights about the last day.
+	DeleteOldNetworkReports(ctx context.Context) error
+	// Delete all notification messages which have not been updated since @before_time.
+	DeleteOldNotificationMessages(ctx context.Context, beforeTime time.Time) error
 	// Delete provisioner daemons that have been created at least a week ago
 	// and have not connected to coderd since a week.
 	// A provisioner daemon with "zeroed" last_seen_at column indicates possible
 	// connectivity issues (no provisioner daemon activity since registration).
 	DeleteOldProvisionerDaemons(ctx context.Context) error
+	// Delete provisioner job logs created before @before_time in batches of
+	// @limit_count rows.
+	// Exception: logs of the latest build of each workspace are kept around.
+	DeleteOldProvisionerJobLogs(ctx context.Context, arg DeleteOldProvisionerJobLogsParams) (int64, error)
 	// If an agent hasn't connected in the last 7 days, we purge it's logs.
 	// Exception: if the logs are related to the latest build, we keep those around.
 	// Logs can take up a lot of space, so it's important we clean up frequently.
 	DeleteOldWorkspaceAgentLogs(ctx context.Context, threshold time.Time) error
-	DeleteOldWorkspaceAgentStats(ctx context.Context) error
+	DeleteOldWorkspaceAgentStats(ctx context.Context, beforeTime time.Time) error
 	DeleteOrganization(ctx context.Context, id uuid.UUID) error
 	DeleteOrganizationMember(ctx context.Context, arg DeleteOrganizationMemberParams) error
 	DeleteProvisionerKey(ctx context.Context, id uuid.UUID) error
 	DeleteReplicasUpdatedBefore(ctx context.Context, updatedAt time.Time) error
 	DeleteRuntimeConfig(ctx context.Context, key string) error
+	DeleteSecretByID(ctx context.Context, id uuid.UUID) error
 	DeleteTailnetAgent(ctx context.Context, arg DeleteTailnetAgentParams) (DeleteTailnetAgentRow, error)
 	DeleteTailnetClient(ctx context.Context, arg DeleteTailnetClientParams) (DeleteTailnetClientRow, error)
 	DeleteTailnetClientSubscription(ctx context.Context, arg DeleteTailnetClientSubscriptionParams) error
@@ -106,6 +124,7 @@ type sqlcQuerier interface {
 	DeleteTailnetTunnel(ctx context.Context, arg DeleteTailnetTunnelParams) (DeleteTailnetTunnelRow, error)
 	DeleteWorkspaceAgentPortShare(ctx context.Context, arg DeleteWorkspaceAgentPortShareParams) error
 	DeleteWorkspaceAgentPortSharesByTemplate(ctx context.Context, templateID uuid.UUID) error
+	DeleteWorkspaceScheduledActionByID(ctx context.Context, id uuid.UUID) error
 	// Disable foreign keys and triggers for all tables.
 	// Deprecated: disable foreign keys was created to aid in migrating off
 	// of the test-only in-memory database. Do not use this in new code.
@@ -120,6 +139,7 @@ type sqlcQuerier interface {
 	GetAPIKeysByLoginType(ctx context.Context, loginType LoginType) ([]APIKey, error)
 	GetAPIKeysByUserID(ctx context.Context, arg GetAPIKeysByUserIDParams) ([]APIKey, error)
 	GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.Time) ([]APIKey, error)
+	GetActiveTemplateRolloutByTemplateID(ctx context.Context, templateID uuid.UUID) (TemplateRollout, error)
 	GetActiveUserCount(ctx context.Context) (int64, error)
 	GetActiveWorkspaceBuildsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]WorkspaceBuild, error)
 	GetAllTailnetAgents(ctx context.Context) ([]TailnetAgent, error)
@@ -137,6 +157,15 @@ type sqlcQuerier interface {
 	// are included.
 	GetAuthorizationUserRoles(ctx context.Context, userID uuid.UUID) (GetAuthorizationUserRolesRow, error)
 	GetCoordinatorResumeTokenSigningKey(ctx context.Context) (string, error)
+	GetCostBudgetByGroupID(ctx context.Context, groupID uuid.UUID) (CostBudget, error)
+	// Returns the active members of the group of a budget.
+	GetCostBudgetMemberIDs(ctx context.Context, groupID uuid.UUID) ([]uuid.UUID, error)
+	// Returns the non-deleted workspaces owned by the members of the group of a
+	// budget in the organization of the group.
+	GetCostBudgetWorkspaceIDs(ctx context.Context, groupID uuid.UUID) ([]uuid.UUID, error)
+	// Returns every budget with the cost of the workspaces owned by the members of
+	// its group in its organization since the given time.
+	GetCostBudgetsWithCost(ctx context.Context, since time.Time) ([]GetCostBudgetsWithCostRow, error)
 	GetCryptoKeyByFeatureAndSequence(ctx context.Context, arg GetCryptoKeyByFeatureAndSequenceParams) (CryptoKey, error)
 	GetCryptoKeys(ctx context.Context) ([]CryptoKey, error)
 	GetCryptoKeysByFeature(ctx context.Context, feature CryptoKeyFeature) ([]CryptoKey, error)
@@ -178,6 +207,11 @@ type sqlcQuerier interface {
 	GetLicenseByID(ctx context.Context, id int32) (License, error)
 	GetLicenses(ctx context.Context) ([]License, error)
 	GetLogoURL(ctx context.Context) (string, error)
+	// GetNetworkRegionInsights returns, for each DERP region, the latency clients
+	// reported to it and the share of direct connections of the clients it's the
+	// home region of. Only reports since reported_after are included, and they
+	// can be filtered on client_types.
+	GetNetworkRegionInsights(ctx context.Context, arg GetNetworkRegionInsightsParams) ([]GetNetworkRegionInsightsRow, error)
 	GetNotificationMessagesByStatus(ctx context.Context, arg GetNotificationMessagesByStatusParams) ([]NotificationMessage, error)
 	// Fetch the notification report generator log indicating recent activity.
 	GetNotificationReportGeneratorLogByTemplate(ctx context.Context, templateID uuid.UUID) (NotificationReportGeneratorLog, error)
@@ -193,6 +227,8 @@ type sqlcQuerier interface {
 	GetOAuth2ProviderAppTokenByPrefix(ctx context.Context, hashPrefix []byte) (OAuth2ProviderAppToken, error)
 	GetOAuth2ProviderApps(ctx context.Context) ([]OAuth2ProviderApp, error)
 	GetOAuth2ProviderAppsByUserID(ctx context.Context, userID uuid.UUID) ([]GetOAuth2ProviderAppsByUserIDRow, error)
+	GetOAuth2ProviderDeviceCodeByPrefix(ctx context.Context, secretPrefix []byte) (OAuth2ProviderDeviceCode, error)
+	GetOAuth2ProviderDeviceCodeByUserCode(ctx context.Context, userCode string) (OAuth2ProviderDeviceCode, error)
 	GetOAuthSigningKey(ctx context.Context) (string, error)
 	GetOrganizationByID(ctx context.Context, id uuid.UUID) (Organization, error)
 	GetOrganizationByName(ctx context.Context, name string) (Organization, error)
@@ -200,24 +236,47 @@ type sqlcQuerier interface {
 	GetOrganizations(ctx context.Context, arg GetOrganizationsParams) ([]Organization, error)
 	GetOrganizationsByUserID(ctx context.Context, userID uuid.UUID) ([]Organization, error)
 	GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUID) ([]ParameterSchema, error)
+	// Returns the distinct provisioner types and tags of the pending jobs in an
+	// organization or initiated by a user. These are the jobs that may have been
+	// held back by the limits on concurrently running jobs.
+	GetPendingProvisionerJobTags(ctx context.Context, arg GetPendingProvisionerJobTagsParams) ([]GetPendingProvisionerJobTagsRow, error)
+	// Builds that were canceled while waiting for approval are excluded.
+	GetPendingWorkspaceBuildApprovalsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]GetPendingWorkspaceBuildApprovalsByTemplateIDRow, error)
 	GetPreviousTemplateVersion(ctx context.Context, arg GetPreviousTemplateVersionParams) (TemplateVersion, error)
 	GetProvisionerDaemons(ctx context.Context) ([]ProvisionerDaemon, error)
 	GetProvisionerDaemonsByOrganization(ctx context.Context, arg GetProvisionerDaemonsByOrganizationParams) ([]ProvisionerDaemon, error)
 	GetProvisionerDaemonsWithStatusByOrganization(ctx context.Context, arg GetProvisionerDaemonsWithStatusByOrganizationParams) ([]GetProvisionerDaemonsWithStatusByOrganizationRow, error)
 	GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (ProvisionerJob, error)
+	GetProvisionerJobResourceChangesByJobID(ctx context.Context, jobID uuid.UUID) ([]ProvisionerJobResourceChange, error)
 	GetProvisionerJobTimingsByJobID(ctx context.Context, jobID uuid.UUID) ([]ProvisionerJobTiming, error)
 	GetProvisionerJobsByIDs(ctx context.Context, ids []uuid.UUID) ([]ProvisionerJob, error)
+	// Queue positions are ordered by priority and creation time only. They don't
+	// account for the limits on concurrently running jobs, so a job held back by
+	// the limit of its organization or user may be acquired after jobs behind it.
 	GetProvisionerJobsByIDsWithQueuePosition(ctx context.Context, ids []uuid.UUID) ([]GetProvisionerJobsByIDsWithQueuePositionRow, error)
 	GetProvisionerJobsCreatedAfter(ctx context.Context, createdAt time.Time) ([]ProvisionerJob, error)
 	GetProvisionerKeyByHashedSecret(ctx context.Context, hashedSecret []byte) (ProvisionerKey, error)
 	GetProvisionerKeyByID(ctx context.Context, id uuid.UUID) (ProvisionerKey, error)
 	GetProvisionerKeyByName(ctx context.Context, arg GetProvisionerKeyByNameParams) (ProvisionerKey, error)
 	GetProvisionerLogsAfterID(ctx context.Context, arg GetProvisionerLogsAfterIDParams) ([]ProvisionerJobLog, error)
+	// Counts the rows which would be removed by purging with the given cutoffs.
+	// Each count mirrors the corresponding DeleteOld* query, ignoring any batch
+	// limits.
+	GetPurgeableRowCounts(ctx context.Context, arg GetPurgeableRowCountsParams) (GetPurgeableRowCountsRow, error)
 	GetQuotaAllowanceForUser(ctx context.Context, arg GetQuotaAllowanceForUserParams) (int64, error)
 	GetQuotaConsumedForUser(ctx context.Context, arg GetQuotaConsumedForUserParams) (int64, error)
 	GetReplicaByID(ctx context.Context, id uuid.UUID) (Replica, error)
 	GetReplicasUpdatedAfter(ctx context.Context, updatedAt time.Time) ([]Replica, error)
 	GetRuntimeConfig(ctx context.Context, key string) (string, error)
+	GetSecretByID(ctx context.Context, id uuid.UUID) (Secret, error)
+	GetSecretByScopeAndName(ctx context.Context, arg GetSecretByScopeAndNameParams) (Secret, error)
+	GetSecrets(ctx context.Context) ([]Secret, error)
+	// Returns the secrets of exactly one user, organization or template. The
+	// other scopes must be NULL.
+	GetSecretsByScope(ctx context.Context, arg GetSecretsByScopeParams) ([]Secret, error)
+	// Returns the secrets that apply to a workspace: those of its owner, its
+	// template and its organization.
+	GetSecretsForWorkspace(ctx context.Context, arg GetSecretsForWorkspaceParams) ([]Secret, error)
 	GetTailnetAgents(ctx context.Context, id uuid.UUID) ([]TailnetAgent, error)
 	GetTailnetClientsForAgent(ctx context.Context, agentID uuid.UUID) ([]TailnetClient, error)
 	GetTailnetPeers(ctx context.Context, id uuid.UUID) ([]TailnetPeer, error)
@@ -257,6 +316,11 @@ type sqlcQuerier interface {
 	// created in the timeframe and return the aggregate usage counts of parameter
 	// values.
 	GetTemplateParameterInsights(ctx context.Context, arg GetTemplateParameterInsightsParams) ([]GetTemplateParameterInsightsRow, error)
+	// Counts the completed start builds of a template version since the given
+	// time. Canceled builds are not counted.
+	GetTemplateRolloutBuildCounts(ctx context.Context, arg GetTemplateRolloutBuildCountsParams) (GetTemplateRolloutBuildCountsRow, error)
+	GetTemplateRolloutByID(ctx context.Context, id uuid.UUID) (TemplateRollout, error)
+	GetTemplateRolloutsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]TemplateRollout, error)
 	GetTemplateUsageStats(ctx context.Context, arg GetTemplateUsageStatsParams) ([]TemplateUsageStat, error)
 	GetTemplateVersionByID(ctx context.Context, id uuid.UUID) (TemplateVersion, error)
 	GetTemplateVersionByJobID(ctx context.Context, jobID uuid.UUID) (TemplateVersion, error)
@@ -318,6 +382,7 @@ type sqlcQuerier interface {
 	GetWorkspaceAgentLogsAfter(ctx context.Context, arg GetWorkspaceAgentLogsAfterParams) ([]WorkspaceAgentLog, error)
 	GetWorkspaceAgentMetadata(ctx context.Context, arg GetWorkspaceAgentMetadataParams) ([]WorkspaceAgentMetadatum, error)
 	GetWorkspaceAgentPortShare(ctx context.Context, arg GetWorkspaceAgentPortShareParams) (WorkspaceAgentPortShare, error)
+	GetWorkspaceAgentProcessGroups(ctx context.Context, workspaceAgentID uuid.UUID) ([]WorkspaceAgentProcessGroup, error)
 	GetWorkspaceAgentScriptTimingsByBuildID(ctx context.Context, id uuid.UUID) ([]GetWorkspaceAgentScriptTimingsByBuildIDRow, error)
 	GetWorkspaceAgentScriptsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentScript, error)
 	GetWorkspaceAgentStats(ctx context.Context, createdAt time.Time) ([]GetWorkspaceAgentStatsRow, error)
@@ -332,6 +397,7 @@ type sqlcQuerier interface {
 	GetWorkspaceAppsByAgentID(ctx context.Context, agentID uuid.UUID) ([]WorkspaceApp, error)
 	GetWorkspaceAppsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceApp, error)
 	GetWorkspaceAppsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceApp, error)
+	GetWorkspaceBuildApprovalByBuildID(ctx context.Context, workspaceBuildID uuid.UUID) (WorkspaceBuildApproval, error)
 	GetWorkspaceBuildByID(ctx context.Context, id uuid.UUID) (WorkspaceBuild, error)
 	GetWorkspaceBuildByJobID(ctx context.Context, jobID uuid.UUID) (WorkspaceBuild, error)
 	GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx context.Context, arg GetWorkspaceBuildByWorkspaceIDAndBuildNumberParams) (WorkspaceBuild, error)
@@ -343,6 +409,16 @@ type sqlcQuerier interface {
 	GetWorkspaceByID(ctx context.Context, id uuid.UUID) (Workspace, error)
 	GetWorkspaceByOwnerIDAndName(ctx context.Context, arg GetWorkspaceByOwnerIDAndNameParams) (Workspace, error)
 	GetWorkspaceByWorkspaceAppID(ctx context.Context, workspaceAppID uuid.UUID) (Workspace, error)
+	// The cost of a workspace counts towards every group its owner is a member of
+	// in the organization of the workspace, including the "Everyone" group.
+	GetWorkspaceCostInsightsByGroup(ctx context.Context, arg GetWorkspaceCostInsightsByGroupParams) ([]GetWorkspaceCostInsightsByGroupRow, error)
+	// GetWorkspaceCostInsightsByInterval returns the cost of workspaces in each
+	// interval between start and end time. If end time is a partial interval, it
+	// will be included in the results and that interval will be shorter than a
+	// full one. Intervals without costs are included with a cost of 0.
+	GetWorkspaceCostInsightsByInterval(ctx context.Context, arg GetWorkspaceCostInsightsByIntervalParams) ([]GetWorkspaceCostInsightsByIntervalRow, error)
+	GetWorkspaceCostInsightsByTemplate(ctx context.Context, arg GetWorkspaceCostInsightsByTemplateParams) ([]GetWorkspaceCostInsightsByTemplateRow, error)
+	GetWorkspaceCostInsightsByUser(ctx context.Context, arg GetWorkspaceCostInsightsByUserParams) ([]GetWorkspaceCostInsightsByUserRow, error)
 	GetWorkspaceModulesByJobID(ctx context.Context, jobID uuid.UUID) ([]WorkspaceModule, error)
 	GetWorkspaceModulesCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceModule, error)
 	GetWorkspaceProxies(ctx context.Context) ([]WorkspaceProxy, error)
@@ -362,6 +438,20 @@ type sqlcQuerier interface {
 	GetWorkspaceResourcesByJobID(ctx context.Context, jobID uuid.UUID) ([]WorkspaceResource, error)
 	GetWorkspaceResourcesByJobIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceResource, error)
 	GetWorkspaceResourcesCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceResource, error)
+	GetWorkspaceScheduledActionByID(ctx context.Context, id uuid.UUID) (WorkspaceScheduledAction, error)
+	GetWorkspaceScheduledActionsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceScheduledAction, error)
+	// Returns the scheduled actions of non-deleted workspaces that are due to run
+	// at the given time, oldest first.
+	GetWorkspaceScheduledActionsDue(ctx context.Context, now time.Time) ([]WorkspaceScheduledAction, error)
+	GetWorkspaceSessionRecordingByID(ctx context.Context, id uuid.UUID) (WorkspaceSessionRecording, error)
+	// Recordings can be large, so their chunks are fetched in pages. Each page
+	// starts after the sequence of the last chunk of the previous page.
+	GetWorkspaceSessionRecordingChunksByRecordingID(ctx context.Context, arg GetWorkspaceSessionRecordingChunksByRecordingIDParams) ([]WorkspaceSessionRecordingChunk, error)
+	GetWorkspaceSessionRecordingsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceSessionRecording, error)
+	GetWorkspaceSnapshotByID(ctx context.Context, id uuid.UUID) (WorkspaceSnapshot, error)
+	// Snapshots can be restored into any workspace of the same owner and template,
+	// including snapshots taken from workspaces that have since been deleted.
+	GetWorkspaceSnapshotsByOwnerAndTemplate(ctx context.Context, arg GetWorkspaceSnapshotsByOwnerAndTemplateParams) ([]WorkspaceSnapshot, error)
 	GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx context.Context, templateIds []uuid.UUID) ([]GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error)
 	// build_params is used to filter by build parameters if present.
 	// It has to be a CTE because the set returning function 'unnest' cannot
@@ -396,14 +486,18 @@ type sqlcQuerier interface {
 	InsertOAuth2ProviderAppCode(ctx context.Context, arg InsertOAuth2ProviderAppCodeParams) (OAuth2ProviderAppCode, error)
 	InsertOAuth2ProviderAppSecret(ctx context.Context, arg InsertOAuth2ProviderAppSecretParams) (OAuth2ProviderAppSecret, error)
 	InsertOAuth2ProviderAppToken(ctx context.Context, arg InsertOAuth2ProviderAppTokenParams) (OAuth2ProviderAppToken, error)
+	InsertOAuth2ProviderDeviceCode(ctx context.Context, arg InsertOAuth2ProviderDeviceCodeParams) (OAuth2ProviderDeviceCode, error)
 	InsertOrganization(ctx context.Context, arg InsertOrganizationParams) (Organization, error)
 	InsertOrganizationMember(ctx context.Context, arg InsertOrganizationMemberParams) (OrganizationMember, error)
 	InsertProvisionerJob(ctx context.Context, arg InsertProvisionerJobParams) (ProvisionerJob, error)
 	InsertProvisionerJobLogs(ctx context.Context, arg InsertProvisionerJobLogsParams) ([]ProvisionerJobLog, error)
+	InsertProvisionerJobResourceChanges(ctx context.Context, arg InsertProvisionerJobResourceChangesParams) ([]ProvisionerJobResourceChange, error)
 	InsertProvisionerJobTimings(ctx context.Context, arg InsertProvisionerJobTimingsParams) ([]ProvisionerJobTiming, error)
 	InsertProvisionerKey(ctx context.Context, arg InsertProvisionerKeyParams) (ProvisionerKey, error)
 	InsertReplica(ctx context.Context, arg InsertReplicaParams) (Replica, error)
+	InsertSecret(ctx context.Context, arg InsertSecretParams) (Secret, error)
 	InsertTemplate(ctx context.Context, arg InsertTemplateParams) error
+	InsertTemplateRollout(ctx context.Context, arg InsertTemplateRolloutParams) (TemplateRollout, error)
 	InsertTemplateVersion(ctx context.Context, arg InsertTemplateVersionParams) error
 	InsertTemplateVersionParameter(ctx context.Context, arg InsertTemplateVersionParameterParams) (TemplateVersionParameter, error)
 	InsertTemplateVersionVariable(ctx context.Context, arg InsertTemplateVersionVariableParams) (TemplateVersionVariable, error)
@@ -426,11 +520,18 @@ type sqlcQuerier interface {
 	InsertWorkspaceApp(ctx context.Context, arg InsertWorkspaceAppParams) (WorkspaceApp, error)
 	InsertWorkspaceAppStats(ctx context.Context, arg InsertWorkspaceAppStatsParams) error
 	InsertWorkspaceBuild(ctx context.Context, arg InsertWorkspaceBuildParams) error
+	InsertWorkspaceBuildApproval(ctx context.Context, arg InsertWorkspaceBuildApprovalParams) (WorkspaceBuildApproval, error)
 	InsertWorkspaceBuildParameters(ctx context.Context, arg InsertWorkspaceBuildParametersParams) error
 	InsertWorkspaceModule(ctx context.Context, arg InsertWorkspaceModuleParams) (WorkspaceModule, error)
 	InsertWorkspaceProxy(ctx context.Context, arg InsertWorkspaceProxyParams) (WorkspaceProxy, error)
 	InsertWorkspaceResource(ctx context.Context, arg InsertWorkspaceResourceParams) (WorkspaceResource, error)
 	InsertWorkspaceResourceMetadata(ctx context.Context, arg InsertWorkspaceResourceMetadataParams) ([]WorkspaceResourceMetadatum, error)
+	InsertWorkspaceScheduledAction(ctx context.Context, arg InsertWorkspaceScheduledActionParams) (WorkspaceScheduledAction, error)
+	InsertWorkspaceSessionRecording(ctx context.Context, arg InsertWorkspaceSessionRecordingParams) (WorkspaceSessionRecording, error)
+	// Agents retry chunks whose upload failed, so inserting a chunk that already
+	// exists is a no-op. It returns the number of chunks inserted.
+	InsertWorkspaceSessionRecordingChunk(ctx context.Context, arg InsertWorkspaceSessionRecordingChunkParams) (int64, error)
+	InsertWorkspaceSnapshot(ctx context.Context, arg InsertWorkspaceSnapshotParams) (WorkspaceSnapshot, error)
 	ListProvisionerKeysByOrganization(ctx context.Context, organizationID uuid.UUID) ([]ProvisionerKey, error)
 	ListProvisionerKeysByOrganizationExcludeReserved(ctx context.Context, organizationID uuid.UUID) ([]ProvisionerKey, error)
 	ListWorkspaceAgentPortShares(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceAgentPortShare, error)
@@ -445,6 +546,7 @@ type sqlcQuerier interface {
 	OrganizationMembers(ctx context.Context, arg OrganizationMembersParams) ([]OrganizationMembersRow, error)
 	ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate(ctx context.Context, templateID uuid.UUID) error
 	RegisterWorkspaceProxy(ctx context.Context, arg RegisterWorkspaceProxyParams) (WorkspaceProxy, error)
+	RejectExpiredWorkspaceBuildApprovals(ctx context.Context, arg RejectExpiredWorkspaceBuildApprovalsParams) ([]WorkspaceBuildApproval, error)
 	RemoveUserFromAllGroups(ctx context.Context, userID uuid.UUID) error
 	RemoveUserFromGroups(ctx context.Context, arg RemoveUserFromGroupsParams) ([]uuid.UUID, error)
 	RevokeDBCryptKey(ctx context.Context, activeKeyDigest string) error
@@ -457,7 +559,10 @@ type sqlcQuerier interface {
 	UnarchiveTemplateVersion(ctx context.Context, arg UnarchiveTemplateVersionParams) error
 	UnfavoriteWorkspace(ctx context.Context, id uuid.UUID) error
 	UpdateAPIKeyByID(ctx context.Context, arg UpdateAPIKeyByIDParams) error
+	UpdateCostBudgetNotifiedAtByGroupID(ctx context.Context, arg UpdateCostBudgetNotifiedAtByGroupIDParams) error
+	UpdateCostBudgetStoppedAtByGroupID(ctx context.Context, arg UpdateCostBudgetStoppedAtByGroupIDParams) error
 	UpdateCryptoKeyDeletesAt(ctx context.Context, arg UpdateCryptoKeyDeletesAtParams) (CryptoKey, error)
+	UpdateCryptoKeySecret(ctx context.Context, arg UpdateCryptoKeySecretParams) (CryptoKey, error)
 	UpdateCustomRole(ctx context.Context, arg UpdateCustomRoleParams) (CustomRole, error)
 	UpdateExternalAuthLink(ctx context.Context, arg UpdateExternalAuthLinkParams) (ExternalAuthLink, error)
 	UpdateExternalAuthLinkRefreshToken(ctx context.Context, arg UpdateExternalAuthLinkRefreshTokenParams) error
@@ -468,18 +573,24 @@ type sqlcQuerier interface {
 	UpdateNotificationTemplateMethodByID(ctx context.Context, arg UpdateNotificationTemplateMethodByIDParams) (NotificationTemplate, error)
 	UpdateOAuth2ProviderAppByID(ctx context.Context, arg UpdateOAuth2ProviderAppByIDParams) (OAuth2ProviderApp, error)
 	UpdateOAuth2ProviderAppSecretByID(ctx context.Context, arg UpdateOAuth2ProviderAppSecretByIDParams) (OAuth2ProviderAppSecret, error)
+	UpdateOAuth2ProviderDeviceCodeLastPolledAtByID(ctx context.Context, arg UpdateOAuth2ProviderDeviceCodeLastPolledAtByIDParams) error
+	UpdateOAuth2ProviderDeviceCodeStatusByID(ctx context.Context, arg UpdateOAuth2ProviderDeviceCodeStatusByIDParams) (OAuth2ProviderDeviceCode, error)
 	UpdateOrganization(ctx context.Context, arg UpdateOrganizationParams) (Organization, error)
 	UpdateProvisionerDaemonLastSeenAt(ctx context.Context, arg UpdateProvisionerDaemonLastSeenAtParams) error
 	UpdateProvisionerJobByID(ctx context.Context, arg UpdateProvisionerJobByIDParams) error
 	UpdateProvisionerJobWithCancelByID(ctx context.Context, arg UpdateProvisionerJobWithCancelByIDParams) error
 	UpdateProvisionerJobWithCompleteByID(ctx context.Context, arg UpdateProvisionerJobWithCompleteByIDParams) error
 	UpdateReplica(ctx context.Context, arg UpdateReplicaParams) (Replica, error)
+	UpdateSecretByID(ctx context.Context, arg UpdateSecretByIDParams) (Secret, error)
 	UpdateTailnetPeerStatusByCoordinator(ctx context.Context, arg UpdateTailnetPeerStatusByCoordinatorParams) error
 	UpdateTemplateACLByID(ctx context.Context, arg UpdateTemplateACLByIDParams) error
 	UpdateTemplateAccessControlByID(ctx context.Context, arg UpdateTemplateAccessControlByIDParams) error
 	UpdateTemplateActiveVersionByID(ctx context.Context, arg UpdateTemplateActiveVersionByIDParams) error
 	UpdateTemplateDeletedByID(ctx context.Context, arg UpdateTemplateDeletedByIDParams) error
 	UpdateTemplateMetaByID(ctx context.Context, arg UpdateTemplateMetaByIDParams) error
+	UpdateTemplateRolloutByID(ctx context.Context, arg UpdateTemplateRolloutByIDParams) (TemplateRollout, error)
+	// Completes a rollout in progress. Completed rollouts are never updated again.
+	UpdateTemplateRolloutStatusByID(ctx context.Context, arg UpdateTemplateRolloutStatusByIDParams) (TemplateRollout, error)
 	UpdateTemplateScheduleByID(ctx context.Context, arg UpdateTemplateScheduleByIDParams) error
 	UpdateTemplateVersionByID(ctx context.Context, arg UpdateTemplateVersionByIDParams) error
 	UpdateTemplateVersionDescriptionByJobID(ctx context.Context, arg UpdateTemplateVersionDescriptionByJobIDParams) error
@@ -494,6 +605,9 @@ type sqlcQuerier interface {
 	UpdateUserLink(ctx context.Context, arg UpdateUserLinkParams) (UserLink, error)
 	UpdateUserLinkedID(ctx context.Context, arg UpdateUserLinkedIDParams) (UserLink, error)
 	UpdateUserLoginType(ctx context.Context, arg UpdateUserLoginTypeParams) (User, error)
+	// Sets the user's preferred delivery method per notification template. An empty method resets the preference, deferring
+	// to the template-level or deployment-level method.
+	UpdateUserNotificationMethodPreferences(ctx context.Context, arg UpdateUserNotificationMethodPreferencesParams) (int64, error)
 	UpdateUserNotificationPreferences(ctx context.Context, arg UpdateUserNotificationPreferencesParams) (int64, error)
 	UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error)
 	UpdateUserQuietHoursSchedule(ctx context.Context, arg UpdateUserQuietHoursScheduleParams) (User, error)
@@ -508,6 +622,9 @@ type sqlcQuerier interface {
 	UpdateWorkspaceAppHealthByID(ctx context.Context, arg UpdateWorkspaceAppHealthByIDParams) error
 	UpdateWorkspaceAutomaticUpdates(ctx context.Context, arg UpdateWorkspaceAutomaticUpdatesParams) error
 	UpdateWorkspaceAutostart(ctx context.Context, arg UpdateWorkspaceAutostartParams) error
+	// Only pending approvals can be reviewed, so reviewing an approval twice
+	// returns no rows.
+	UpdateWorkspaceBuildApprovalByBuildID(ctx context.Context, arg UpdateWorkspaceBuildApprovalByBuildIDParams) (WorkspaceBuildApproval, error)
 	UpdateWorkspaceBuildCostByID(ctx context.Context, arg UpdateWorkspaceBuildCostByIDParams) error
 	UpdateWorkspaceBuildDeadlineByID(ctx context.Context, arg UpdateWorkspaceBuildDeadlineByIDParams) error
 	UpdateWorkspaceBuildProvisionerStateByID(ctx context.Context, arg UpdateWorkspaceBuildProvisionerStateByIDParams) error
@@ -518,6 +635,9 @@ type sqlcQuerier interface {
 	// This allows editing the properties of a workspace proxy.
 	UpdateWorkspaceProxy(ctx context.Context, arg UpdateWorkspaceProxyParams) (WorkspaceProxy, error)
 	UpdateWorkspaceProxyDeleted(ctx context.Context, arg UpdateWorkspaceProxyDeletedParams) error
+	UpdateWorkspaceScheduledActionRunByID(ctx context.Context, arg UpdateWorkspaceScheduledActionRunByIDParams) error
+	UpdateWorkspaceSessionRecordingByID(ctx context.Context, arg UpdateWorkspaceSessionRecordingByIDParams) error
+	UpdateWorkspaceSnapshotArtifactsByID(ctx context.Context, arg UpdateWorkspaceSnapshotArtifactsByIDParams) error
 	UpdateWorkspaceTTL(ctx context.Context, arg UpdateWorkspaceTTLParams) error
 	UpdateWorkspacesDormantDeletingAtByTemplateID(ctx context.Context, arg UpdateWorkspacesDormantDeletingAtByTemplateIDParams) ([]WorkspaceTable, error)
 	UpdateWorkspacesTTLByTemplateID(ctx context.Context, arg UpdateWorkspacesTTLByTemplateIDParams) error
@@ -525,6 +645,7 @@ type sqlcQuerier interface {
 	UpsertAppSecurityKey(ctx context.Context, value string) error
 	UpsertApplicationName(ctx context.Context, value string) error
 	UpsertCoordinatorResumeTokenSigningKey(ctx context.Context, value string) error
+	UpsertCostBudget(ctx context.Context, arg UpsertCostBudgetParams) (CostBudget, error)
 	// The default proxy is implied and not actually stored in the database.
 	// So we need to store it's configuration here for display purposes.
 	// The functional values are immutable and controlled implicitly.
@@ -533,6 +654,14 @@ type sqlcQuerier interface {
 	UpsertJFrogXrayScanByWorkspaceAndAgentID(ctx context.Context, arg UpsertJFrogXrayScanByWorkspaceAndAgentIDParams) error
 	UpsertLastUpdateCheck(ctx context.Context, value string) error
 	UpsertLogoURL(ctx context.Context, value string) error
+	// UpsertNetworkConnectionReports stores the latest type of the connections
+	// of clients. The reports must not contain duplicate
+	// (client_id, remote_node_id) pairs.
+	UpsertNetworkConnectionReports(ctx context.Context, arg UpsertNetworkConnectionReportsParams) error
+	// UpsertNetworkLatencyReports stores the latest latency reported by clients
+	// to each DERP region. The reports must not contain duplicate
+	// (client_id, region_id) pairs.
+	UpsertNetworkLatencyReports(ctx context.Context, arg UpsertNetworkLatencyReportsParams) error
 	// Insert or update notification report generator logs with recent activity.
 	UpsertNotificationReportGeneratorLog(ctx context.Context, arg UpsertNotificationReportGeneratorLogParams) error
 	UpsertNotificationsSettings(ctx context.Context, value string) error
@@ -551,6 +680,15 @@ type sqlcQuerier interface {
 	// combination. The result is stored in the template_usage_stats table.
 	UpsertTemplateUsageStats(ctx context.Context) error
 	UpsertWorkspaceAgentPortShare(ctx context.Context, arg UpsertWorkspaceAgentPortShareParams) (WorkspaceAgentPortShare, error)
+	// UpsertWorkspaceAgentProcessGroups stores the latest resource usage of the
+	// process groups reported by an agent.
+	UpsertWorkspaceAgentProcessGroups(ctx context.Context, arg UpsertWorkspaceAgentProcessGroupsParams) error
+	// This query aggregates the hourly cost of the resources of workspace builds
+	// into hourly buckets per workspace. A build accrues cost from the time its
+	// job completed until the next successful build of the workspace completed.
+	// Buckets are recalculated from the hour before the latest bucket, so the
+	// query can run repeatedly.
+	UpsertWorkspaceCosts(ctx context.Context) error
 }
 
 var _ sqlcQuerier = (*sqlQuerier)(nil)
@@ -146,7 +146,7 @@ func (q *sqlQuerier) DeleteApplicationConnectAPIKeysByUserID(ctx context.Context
 
 const getAPIKeyByID = `-- name: GetAPIKeyByID :one
 SELECT
-	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
+	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scope_permissions, scope_allow_list
 FROM
 	api_keys
 WHERE
@@ -171,13 +171,15 @@ func (q *sqlQuerier) GetAPIKeyByID(ctx context.Context, id string) (APIKey, erro
 		&i.IPAddress,
 		&i.Scope,
 		&i.TokenName,
+		&i.ScopePermissions,
+		pq.Array(&i.ScopeAllowList),
 	)
 	return i, err
 }
 
 const getAPIKeyByName = `-- name: GetAPIKeyByName :one
 SELECT
-	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
+	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scope_permissions, scope_allow_list
 FROM
 	api_keys
 WHERE
@@ -210,12 +212,14 @@ func (q *sqlQuerier) GetAPIKeyByName(ctx context.Context, arg GetAPIKeyByNamePar
 		&i.IPAddress,
 		&i.Scope,
 		&i.TokenName,
+		&i.ScopePermissions,
+		pq.Array(&i.ScopeAllowList),
 	)
 	return i, err
 }
 
 const getAPIKeysByLoginType = `-- name: GetAPIKeysByLoginType :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE login_type = $1
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scope_permissions, scope_allow_list FROM api_keys WHERE login_type = $1
 `
 
 func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, loginType LoginType) ([]APIKey, error) {
@@ -240,6 +244,8 @@ func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, loginType LoginT
 			&i.IPAddress,
 			&i.Scope,
 			&i.TokenName,
+			&i.ScopePermissions,
+			pq.Array(&i.ScopeAllowList),
 		); err != nil {
 			return nil, err
 		}
@@ -255,7 +261,7 @@ func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, loginType LoginT
 }
 
 const getAPIKeysByUserID = `-- name: GetAPIKeysByUserID :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE login_type = $1 AND user_id = $2
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scope_permissions, scope_allow_list FROM api_keys WHERE login_type = $1 AND user_id = $2
 `
 
 type GetAPIKeysByUserIDParams struct {
@@ -285,6 +291,8 @@ func (q *sqlQuerier) GetAPIKeysByUserID(ctx context.Context, arg GetAPIKeysByUse
 			&i.IPAddress,
 			&i.Scope,
 			&i.TokenName,
+			&i.ScopePermissions,
+			pq.Array(&i.ScopeAllowList),
 		); err != nil {
 			return nil, err
 		}
@@ -300,7 +308,7 @@ func (q *sqlQuerier) GetAPIKeysByUserID(ctx context.Context, arg GetAPIKeysByUse
 }
 
 const getAPIKeysLastUsedAfter = `-- name: GetAPIKeysLastUsedAfter :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE last_used > $1
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scope_permissions, scope_allow_list FROM api_keys WHERE last_used > $1
 `
 
 func (q *sqlQuerier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.Time) ([]APIKey, error) {
@@ -325,6 +333,8 @@ func (q *sqlQuerier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.
 			&i.IPAddress,
 			&i.Scope,
 			&i.TokenName,
+			&i.ScopePermissions,
+			pq.Array(&i.ScopeAllowList),
 		); err != nil {
 			return nil, err
 		}
@@ -353,7 +363,9 @@ INSERT INTO
 		updated_at,
 		login_type,
 		scope,
-		token_name
+		token_name,
+		scope_permissions,
+		scope_allow_list
 	)
 VALUES
 	($1,
@@ -362,22 +374,24 @@ VALUES
 	     WHEN 0 THEN 86400
 		 ELSE $2::bigint
 	 END
-	 , $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
+	 , $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, COALESCE($14::text[], '{}'::text[])) RETURNING id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scope_permissions, scope_allow_list
 `
 
 type InsertAPIKeyParams struct {
-	ID              string      `db:"id" json:"id"`
-	LifetimeSeconds int64       `db:"lifetime_seconds" json:"lifetime_seconds"`
-	HashedSecret    []byte      `db:"hashed_secret" json:"hashed_secret"`
-	IPAddress       pqtype.Inet `db:"ip_address" json:"ip_address"`
-	UserID          uuid.UUID   `db:"user_id" json:"user_id"`
-	LastUsed        time.Time   `db:"last_used" json:"last_used"`
-	ExpiresAt       time.Time   `db:"expires_at" json:"expires_at"`
-	CreatedAt       time.Time   `db:"created_at" json:"created_at"`
-	UpdatedAt       time.Time   `db:"updated_at" json:"updated_at"`
-	LoginType       LoginType   `db:"login_type" json:"login_type"`
-	Scope           APIKeyScope `db:"scope" json:"scope"`
-	TokenName       string      `db:"token_name" json:"token_name"`
+	ID               string                 `db:"id" json:"id"`
+	LifetimeSeconds  int64                  `db:"lifetime_seconds" json:"lifetime_seconds"`
+	HashedSecret     []byte                 `db:"hashed_secret" json:"hashed_secret"`
+	IPAddress        pqtype.Inet            `db:"ip_address" json:"ip_address"`
+	UserID           uuid.UUID              `db:"user_id" json:"user_id"`
+	LastUsed         time.Time              `db:"last_used" json:"last_used"`
+	ExpiresAt        time.Time              `db:"expires_at" json:"expires_at"`
+	CreatedAt        time.Time              `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time              `db:"updated_at" json:"updated_at"`
+	LoginType        LoginType              `db:"login_type" json:"login_type"`
+	Scope            APIKeyScope            `db:"scope" json:"scope"`
+	TokenName        string                 `db:"token_name" json:"token_name"`
+	ScopePermissions APIKeyScopePermissions `db:"scope_permissions" json:"scope_permissions"`
+	ScopeAllowList   []string               `db:"scope_allow_list" json:"scope_allow_list"`
 }
 
 func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (APIKey, error) {
@@ -394,6 +408,8 @@ func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (
 		arg.LoginType,
 		arg.Scope,
 		arg.TokenName,
+		arg.ScopePermissions,
+		pq.Array(arg.ScopeAllowList),
 	)
 	var i APIKey
 	err := row.Scan(
@@ -409,6 +425,8 @@ func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (
 		&i.IPAddress,
 		&i.Scope,
 		&i.TokenName,
+		&i.ScopePermissions,
+		pq.Array(&i.ScopeAllowList),
 	)
 	return i, err
 }
@@ -441,6 +459,39 @@ func (q *sqlQuerier) UpdateAPIKeyByID(ctx context.Context, arg UpdateAPIKeyByIDP
 	return err
 }
 
+const deleteOldAuditLogs = `-- name: DeleteOldAuditLogs :execrows
+DELETE FROM
+	audit_logs
+WHERE
+	id IN (
+		SELECT
+			id
+		FROM
+			audit_logs
+		WHERE
+			"time" < $1 :: timestamptz
+		ORDER BY
+			"time" ASC
+		LIMIT
+			$2 :: int
+	)
+`
+
+type DeleteOldAuditLogsParams struct {
+	BeforeTime time.Time `db:"before_time" json:"before_time"`
+	LimitCount int32     `db:"limit_count" json:"limit_count"`
+}
+
+// Delete audit logs older than @before_time in batches of @limit_count rows
+// to keep the load on the database low.
+func (q *sqlQuerier) DeleteOldAuditLogs(ctx context.Context, arg DeleteOldAuditLogsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteOldAuditLogs, arg.BeforeTime, arg.LimitCount)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getAuditLogsOffset = `-- name: GetAuditLogsOffset :many
 SELECT
     audit_logs.id, audit_logs.time, audit_logs.user_id, audit_logs.organization_id, audit_logs.ip, audit_logs.user_agent, audit_logs.resource_type, audit_logs.resource_id, audit_logs.resource_target, audit_logs.action, audit_logs.diff, audit_logs.status_code, audit_logs.additional_fields, audit_logs.request_id, audit_logs.resource_icon,
@@ -761,6 +812,282 @@ func (q *sqlQuerier) InsertAuditLog(ctx context.Context, arg InsertAuditLogParam
 	return i, err
 }
 
+const deleteCostBudgetByGroupID = `-- name: DeleteCostBudgetByGroupID :exec
+DELETE FROM
+	cost_budgets
+WHERE
+	group_id = $1
+`
+
+func (q *sqlQuerier) DeleteCostBudgetByGroupID(ctx context.Context, groupID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteCostBudgetByGroupID, groupID)
+	return err
+}
+
+const getCostBudgetByGroupID = `-- name: GetCostBudgetByGroupID :one
+SELECT
+	group_id, organization_id, created_at, updated_at, monthly_limit, notify_threshold_percent, stop_threshold_percent, notified_at, stopped_at
+FROM
+	cost_budgets
+WHERE
+	group_id = $1
+`
+
+func (q *sqlQuerier) GetCostBudgetByGroupID(ctx context.Context, groupID uuid.UUID) (CostBudget, error) {
+	row := q.db.QueryRowContext(ctx, getCostBudgetByGroupID, groupID)
+	var i CostBudget
+	err := row.Scan(
+		&i.GroupID,
+		&i.OrganizationID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.MonthlyLimit,
+		&i.NotifyThresholdPercent,
+		&i.StopThresholdPercent,
+		&i.NotifiedAt,
+		&i.StoppedAt,
+	)
+	return i, err
+}
+
+const getCostBudgetMemberIDs = `-- name: GetCostBudgetMemberIDs :many
+SELECT
+	user_id
+FROM
+	group_members_expanded
+WHERE
+	group_id = $1
+	AND user_status = 'active'
+`
+
+// Returns the active members of the group of a budget.
+func (q *sqlQuerier) GetCostBudgetMemberIDs(ctx context.Context, groupID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, getCostBudgetMemberIDs, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var user_id uuid.UUID
+		if err := rows.Scan(&user_id); err != nil {
+			return nil, err
+		}
+		items = append(items, user_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCostBudgetWorkspaceIDs = `-- name: GetCostBudgetWorkspaceIDs :many
+SELECT
+	workspaces.id
+FROM
+	workspaces
+JOIN
+	group_members_expanded AS gme
+ON
+	gme.user_id = workspaces.owner_id
+	AND gme.organization_id = workspaces.organization_id
+WHERE
+	gme.group_id = $1
+	AND workspaces.deleted = false
+`
+
+// Returns the non-deleted workspaces owned by the members of the group of a
+// budget in the organization of the group.
+func (q *sqlQuerier) GetCostBudgetWorkspaceIDs(ctx context.Context, groupID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, getCostBudgetWorkspaceIDs, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCostBudgetsWithCost = `-- name: GetCostBudgetsWithCost :many
+SELECT
+	cost_budgets.group_id, cost_budgets.organization_id, cost_budgets.created_at, cost_budgets.updated_at, cost_budgets.monthly_limit, cost_budgets.notify_threshold_percent, cost_budgets.stop_threshold_percent, cost_budgets.notified_at, cost_budgets.stopped_at,
+	groups.name AS group_name,
+	COALESCE((
+		SELECT
+			SUM(wc.cost)
+		FROM
+			workspace_costs AS wc
+		JOIN
+			group_members_expanded AS gme
+		ON
+			gme.user_id = wc.owner_id
+			AND gme.group_id = cost_budgets.group_id
+		WHERE
+			wc.organization_id = cost_budgets.organization_id
+			AND wc.bucket_start >= $1 :: timestamptz
+	), 0)::float AS cost
+FROM
+	cost_budgets
+JOIN
+	groups
+ON
+	groups.id = cost_budgets.group_id
+`
+
+type GetCostBudgetsWithCostRow struct {
+	CostBudget CostBudget `db:"cost_budget" json:"cost_budget"`
+	GroupName  string     `db:"group_name" json:"group_name"`
+	Cost       float64    `db:"cost" json:"cost"`
+}
+
+// Returns every budget with the cost of the workspaces owned by the members of
+// its group in its organization since the given time.
+func (q *sqlQuerier) GetCostBudgetsWithCost(ctx context.Context, since time.Time) ([]GetCostBudgetsWithCostRow, error) {
+	rows, err := q.db.QueryContext(ctx, getCostBudgetsWithCost, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCostBudgetsWithCostRow
+	for rows.Next() {
+		var i GetCostBudgetsWithCostRow
+		if err := rows.Scan(
+			&i.CostBudget.GroupID,
+			&i.CostBudget.OrganizationID,
+			&i.CostBudget.CreatedAt,
+			&i.CostBudget.UpdatedAt,
+			&i.CostBudget.MonthlyLimit,
+			&i.CostBudget.NotifyThresholdPercent,
+			&i.CostBudget.StopThresholdPercent,
+			&i.CostBudget.NotifiedAt,
+			&i.CostBudget.StoppedAt,
+			&i.GroupName,
+			&i.Cost,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateCostBudgetNotifiedAtByGroupID = `-- name: UpdateCostBudgetNotifiedAtByGroupID :exec
+UPDATE
+	cost_budgets
+SET
+	notified_at = $2
+WHERE
+	group_id = $1
+`
+
+type UpdateCostBudgetNotifiedAtByGroupIDParams struct {
+	GroupID    uuid.UUID    `db:"group_id" json:"group_id"`
+	NotifiedAt sql.NullTime `db:"notified_at" json:"notified_at"`
+}
+
+func (q *sqlQuerier) UpdateCostBudgetNotifiedAtByGroupID(ctx context.Context, arg UpdateCostBudgetNotifiedAtByGroupIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateCostBudgetNotifiedAtByGroupID, arg.GroupID, arg.NotifiedAt)
+	return err
+}
+
+const updateCostBudgetStoppedAtByGroupID = `-- name: UpdateCostBudgetStoppedAtByGroupID :exec
+UPDATE
+	cost_budgets
+SET
+	stopped_at = $2
+WHERE
+	group_id = $1
+`
+
+type UpdateCostBudgetStoppedAtByGroupIDParams struct {
+	GroupID   uuid.UUID    `db:"group_id" json:"group_id"`
+	StoppedAt sql.NullTime `db:"stopped_at" json:"stopped_at"`
+}
+
+func (q *sqlQuerier) UpdateCostBudgetStoppedAtByGroupID(ctx context.Context, arg UpdateCostBudgetStoppedAtByGroupIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateCostBudgetStoppedAtByGroupID, arg.GroupID, arg.StoppedAt)
+	return err
+}
+
+const upsertCostBudget = `-- name: UpsertCostBudget :one
+INSERT INTO cost_budgets (
+	group_id,
+	organization_id,
+	created_at,
+	updated_at,
+	monthly_limit,
+	notify_threshold_percent,
+	stop_threshold_percent
+)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT
+	(group_id)
+DO UPDATE SET
+	updated_at = EXCLUDED.updated_at,
+	monthly_limit = EXCLUDED.monthly_limit,
+	notify_threshold_percent = EXCLUDED.notify_threshold_percent,
+	stop_threshold_percent = EXCLUDED.stop_threshold_percent
+RETURNING group_id, organization_id, created_at, updated_at, monthly_limit, notify_threshold_percent, stop_threshold_percent, notified_at, stopped_at
+`
+
+type UpsertCostBudgetParams struct {
+	GroupID                uuid.UUID `db:"group_id" json:"group_id"`
+	OrganizationID         uuid.UUID `db:"organization_id" json:"organization_id"`
+	CreatedAt              time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt              time.Time `db:"updated_at" json:"updated_at"`
+	MonthlyLimit           float64   `db:"monthly_limit" json:"monthly_limit"`
+	NotifyThresholdPercent int32     `db:"notify_threshold_percent" json:"notify_threshold_percent"`
+	StopThresholdPercent   int32     `db:"stop_threshold_percent" json:"stop_threshold_percent"`
+}
+
+func (q *sqlQuerier) UpsertCostBudget(ctx context.Context, arg UpsertCostBudgetParams) (CostBudget, error) {
+	row := q.db.QueryRowContext(ctx, upsertCostBudget,
+		arg.GroupID,
+		arg.OrganizationID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.MonthlyLimit,
+		arg.NotifyThresholdPercent,
+		arg.StopThresholdPercent,
+	)
+	var i CostBudget
+	err := row.Scan(
+		&i.GroupID,
+		&i.OrganizationID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.MonthlyLimit,
+		&i.NotifyThresholdPercent,
+		&i.StopThresholdPercent,
+		&i.NotifiedAt,
+		&i.StoppedAt,
+	)
+	return i, err
+}
+
 const deleteCryptoKey = `-- name: DeleteCryptoKey :one
 UPDATE crypto_keys
 SET secret = NULL, secret_key_id = NULL
@@ -979,8 +1306,40 @@ func (q *sqlQuerier) UpdateCryptoKeyDeletesAt(ctx context.Context, arg UpdateCry
 	return i, err
 }
 
+const updateCryptoKeySecret = `-- name: UpdateCryptoKeySecret :one
+UPDATE crypto_keys
+SET secret = $3, secret_key_id = $4
+WHERE feature = $1 AND sequence = $2 RETURNING feature, sequence, secret, secret_key_id, starts_at, deletes_at
+`
+
+type UpdateCryptoKeySecretParams struct {
+	Feature     CryptoKeyFeature `db:"feature" json:"feature"`
+	Sequence    int32            `db:"sequence" json:"sequence"`
+	Secret      sql.NullString   `db:"secret" json:"secret"`
+	SecretKeyID sql.NullString   `db:"secret_key_id" json:"secret_key_id"`
+}
+
+func (q *sqlQuerier) UpdateCryptoKeySecret(ctx context.Context, arg UpdateCryptoKeySecretParams) (CryptoKey, error) {
+	row := q.db.QueryRowContext(ctx, updateCryptoKeySecret,
+		arg.Feature,
+		arg.Sequence,
+		arg.Secret,
+		arg.SecretKeyID,
+	)
+	var i CryptoKey
+	err := row.Scan(
+		&i.Feature,
+		&i.Sequence,
+		&i.Secret,
+		&i.SecretKeyID,
+		&i.StartsAt,
+		&i.DeletesAt,
+	)
+	return i, err
+}
+
 const getDBCryptKeys = `-- name: GetDBCryptKeys :many
-SELECT number, active_key_digest, revoked_key_digest, created_at, revoked_at, test FROM dbcrypt_keys ORDER BY number ASC
+SELECT number, active_key_digest, revoked_key_digest, created_at, revoked_at, test, key_provider, wrapped_key FROM dbcrypt_keys ORDER BY number ASC
 `
 
 func (q *sqlQuerier) GetDBCryptKeys(ctx context.Context) ([]DBCryptKey, error) {
@@ -999,6 +1358,8 @@ func (q *sqlQuerier) GetDBCryptKeys(ctx context.Context) ([]DBCryptKey, error) {
 			&i.CreatedAt,
 			&i.RevokedAt,
 			&i.Test,
+			&i.KeyProvider,
+			&i.WrappedKey,
 		); err != nil {
 			return nil, err
 		}
@@ -1015,18 +1376,26 @@ func (q *sqlQuerier) GetDBCryptKeys(ctx context.Context) ([]DBCryptKey, error) {
 
 const insertDBCryptKey = `-- name: InsertDBCryptKey :exec
 INSERT INTO dbcrypt_keys
-	(number, active_key_digest, created_at, test)
-VALUES ($1::int, $2::text, CURRENT_TIMESTAMP, $3::text)
+	(number, active_key_digest, created_at, test, key_provider, wrapped_key)
+VALUES ($1::int, $2::text, CURRENT_TIMESTAMP, $3::text, $4::text, $5::text)
 `
 
 type InsertDBCryptKeyParams struct {
-	Number          int32  `db:"number" json:"number"`
-	ActiveKeyDigest string `db:"active_key_digest" json:"active_key_digest"`
-	Test            string `db:"test" json:"test"`
+	Number          int32          `db:"number" json:"number"`
+	ActiveKeyDigest string         `db:"active_key_digest" json:"active_key_digest"`
+	Test            string         `db:"test" json:"test"`
+	KeyProvider     sql.NullString `db:"key_provider" json:"key_provider"`
+	WrappedKey      sql.NullString `db:"wrapped_key" json:"wrapped_key"`
 }
 
 func (q *sqlQuerier) InsertDBCryptKey(ctx context.Context, arg InsertDBCryptKeyParams) error {
-	_, err := q.db.ExecContext(ctx, insertDBCryptKey, arg.Number, arg.ActiveKeyDigest, arg.Test)
+	_, err := q.db.ExecContext(ctx, insertDBCryptKey,
+		arg.Number,
+		arg.ActiveKeyDigest,
+		arg.Test,
+		arg.KeyProvider,
+		arg.WrappedKey,
+	)
 	return err
 }
 
@@ -1047,6 +1416,112 @@ func (q *sqlQuerier) RevokeDBCryptKey(ctx context.Context, activeKeyDigest strin
 	return err
 }
 
+const getPurgeableRowCounts = `-- name: GetPurgeableRowCounts :one
+WITH
+	latest_builds AS (
+		SELECT DISTINCT ON (workspace_id)
+			id, job_id
+		FROM
+			workspace_builds
+		ORDER BY
+			workspace_id, build_number DESC
+	),
+	old_agents AS (
+		SELECT
+			wa.id
+		FROM
+			workspace_agents AS wa
+		JOIN
+			workspace_resources AS wr
+		ON
+			wa.resource_id = wr.id
+		JOIN
+			workspace_builds AS wb
+		ON
+			wb.job_id = wr.job_id
+		WHERE
+			wb.id NOT IN (SELECT id FROM latest_builds)
+		AND CASE
+			WHEN wa.last_connected_at IS NOT NULL THEN
+				wa.last_connected_at < $1 :: timestamptz
+			ELSE wa.created_at < $1 :: timestamptz
+		END
+	)
+SELECT
+	(
+		SELECT COUNT(*) FROM audit_logs
+		WHERE "time" < $2 :: timestamptz
+	) :: bigint AS audit_logs,
+	(
+		SELECT COUNT(*) FROM workspace_agent_logs
+		WHERE agent_id IN (SELECT id FROM old_agents)
+	) :: bigint AS workspace_agent_logs,
+	(
+		SELECT COUNT(*) FROM provisioner_job_logs
+		WHERE created_at < $3 :: timestamptz
+		AND job_id NOT IN (SELECT job_id FROM latest_builds)
+	) :: bigint AS provisioner_job_logs,
+	(
+		SELECT COUNT(*) FROM workspace_builds
+		WHERE created_at < $4 :: timestamptz
+		AND provisioner_state IS NOT NULL
+		AND id NOT IN (SELECT id FROM latest_builds)
+	) :: bigint AS workspace_build_states,
+	(
+		SELECT COUNT(*) FROM notification_messages
+		WHERE updated_at < $5 :: timestamptz
+	) :: bigint AS notification_messages,
+	(
+		SELECT COUNT(*) FROM workspace_agent_stats
+		WHERE created_at < (
+			SELECT GREATEST(MAX(start_time) - '1 days'::interval, $6 :: timestamptz)
+			FROM template_usage_stats
+		)
+	) :: bigint AS workspace_agent_stats
+`
+
+type GetPurgeableRowCountsParams struct {
+	WorkspaceAgentLogsBefore   time.Time `db:"workspace_agent_logs_before" json:"workspace_agent_logs_before"`
+	AuditLogsBefore            time.Time `db:"audit_logs_before" json:"audit_logs_before"`
+	ProvisionerJobLogsBefore   time.Time `db:"provisioner_job_logs_before" json:"provisioner_job_logs_before"`
+	WorkspaceBuildStatesBefore time.Time `db:"workspace_build_states_before" json:"workspace_build_states_before"`
+	NotificationMessagesBefore time.Time `db:"notification_messages_before" json:"notification_messages_before"`
+	WorkspaceAgentStatsBefore  time.Time `db:"workspace_agent_stats_before" json:"workspace_agent_stats_before"`
+}
+
+type GetPurgeableRowCountsRow struct {
+	AuditLogs            int64 `db:"audit_logs" json:"audit_logs"`
+	WorkspaceAgentLogs   int64 `db:"workspace_agent_logs" json:"workspace_agent_logs"`
+	ProvisionerJobLogs   int64 `db:"provisioner_job_logs" json:"provisioner_job_logs"`
+	WorkspaceBuildStates int64 `db:"workspace_build_states" json:"workspace_build_states"`
+	NotificationMessages int64 `db:"notification_messages" json:"notification_messages"`
+	WorkspaceAgentStats  int64 `db:"workspace_agent_stats" json:"workspace_agent_stats"`
+}
+
+// Counts the rows which would be removed by purging with the given cutoffs.
+// Each count mirrors the corresponding DeleteOld* query, ignoring any batch
+// limits.
+func (q *sqlQuerier) GetPurgeableRowCounts(ctx context.Context, arg GetPurgeableRowCountsParams) (GetPurgeableRowCountsRow, error) {
+	row := q.db.QueryRowContext(ctx, getPurgeableRowCounts,
+		arg.WorkspaceAgentLogsBefore,
+		arg.AuditLogsBefore,
+		arg.ProvisionerJobLogsBefore,
+		arg.WorkspaceBuildStatesBefore,
+		arg.NotificationMessagesBefore,
+		arg.WorkspaceAgentStatsBefore,
+	)
+	var i GetPurgeableRowCountsRow
+	err := row.Scan(
+		&i.AuditLogs,
+		&i.WorkspaceAgentLogs,
+		&i.ProvisionerJobLogs,
+		&i.WorkspaceBuildStates,
+		&i.NotificationMessages,
+		&i.WorkspaceAgentStats,
+	)
+	return i, err
+}
+
 const deleteExternalAuthLink = `-- name: DeleteExternalAuthLink :exec
 DELETE FROM external_auth_links WHERE provider_id = $1 AND user_id = $2
 `
@@ -3247,19 +3722,271 @@ func (q *sqlQuerier) GetUserStatusCounts(ctx context.Context, arg GetUserStatusC
 	return items, nil
 }
 
-const upsertTemplateUsageStats = `-- name: UpsertTemplateUsageStats :exec
-WITH
-	latest_start AS (
-		SELECT
-			-- Truncate to hour so that we always look at even ranges of data.
-			date_trunc('hour', COALESCE(
-				MAX(start_time) - '1 hour'::interval,
-				-- Fallback when there are no template usage stats yet.
-				-- App stats can exist before this, but not agent stats,
-				-- limit the lookback to avoid inconsistency.
-				(SELECT MIN(created_at) FROM workspace_agent_stats)
-			)) AS t
-		FROM
+const getWorkspaceCostInsightsByGroup = `-- name: GetWorkspaceCostInsightsByGroup :many
+SELECT
+	gme.group_id,
+	gme.group_name,
+	SUM(wc.cost)::float AS cost
+FROM
+	workspace_costs AS wc
+JOIN
+	group_members_expanded AS gme
+ON
+	gme.user_id = wc.owner_id
+	AND gme.organization_id = wc.organization_id
+WHERE
+	wc.bucket_start >= $1::timestamptz
+	AND wc.bucket_start < $2::timestamptz
+	AND CASE WHEN COALESCE(array_length($3::uuid[], 1), 0) > 0 THEN wc.template_id = ANY($3::uuid[]) ELSE TRUE END
+GROUP BY
+	gme.group_id, gme.group_name
+ORDER BY
+	cost DESC, gme.group_name
+`
+
+type GetWorkspaceCostInsightsByGroupParams struct {
+	StartTime   time.Time   `db:"start_time" json:"start_time"`
+	EndTime     time.Time   `db:"end_time" json:"end_time"`
+	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
+}
+
+type GetWorkspaceCostInsightsByGroupRow struct {
+	GroupID   uuid.UUID `db:"group_id" json:"group_id"`
+	GroupName string    `db:"group_name" json:"group_name"`
+	Cost      float64   `db:"cost" json:"cost"`
+}
+
+// The cost of a workspace counts towards every group its owner is a member of
+// in the organization of the workspace, including the "Everyone" group.
+func (q *sqlQuerier) GetWorkspaceCostInsightsByGroup(ctx context.Context, arg GetWorkspaceCostInsightsByGroupParams) ([]GetWorkspaceCostInsightsByGroupRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceCostInsightsByGroup, arg.StartTime, arg.EndTime, pq.Array(arg.TemplateIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceCostInsightsByGroupRow
+	for rows.Next() {
+		var i GetWorkspaceCostInsightsByGroupRow
+		if err := rows.Scan(&i.GroupID, &i.GroupName, &i.Cost); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceCostInsightsByInterval = `-- name: GetWorkspaceCostInsightsByInterval :many
+WITH
+	ts AS (
+		SELECT
+			d::timestamptz AS from_,
+			LEAST(
+				(d::timestamptz + ($1::int || ' day')::interval)::timestamptz,
+				$2::timestamptz
+			)::timestamptz AS to_
+		FROM
+			generate_series(
+				$3::timestamptz,
+				-- Subtract 1 μs to avoid creating an extra series.
+				($2::timestamptz) - '1 microsecond'::interval,
+				($1::int || ' day')::interval
+			) AS d
+	)
+
+SELECT
+	ts.from_ AS start_time,
+	ts.to_ AS end_time,
+	COALESCE(SUM(wc.cost), 0)::float AS cost
+FROM
+	ts
+LEFT JOIN
+	workspace_costs AS wc
+ON
+	wc.bucket_start >= ts.from_
+	AND wc.bucket_start < ts.to_
+	AND CASE WHEN COALESCE(array_length($4::uuid[], 1), 0) > 0 THEN wc.template_id = ANY($4::uuid[]) ELSE TRUE END
+GROUP BY
+	ts.from_, ts.to_
+ORDER BY
+	ts.from_
+`
+
+type GetWorkspaceCostInsightsByIntervalParams struct {
+	IntervalDays int32       `db:"interval_days" json:"interval_days"`
+	EndTime      time.Time   `db:"end_time" json:"end_time"`
+	StartTime    time.Time   `db:"start_time" json:"start_time"`
+	TemplateIDs  []uuid.UUID `db:"template_ids" json:"template_ids"`
+}
+
+type GetWorkspaceCostInsightsByIntervalRow struct {
+	StartTime time.Time `db:"start_time" json:"start_time"`
+	EndTime   time.Time `db:"end_time" json:"end_time"`
+	Cost      float64   `db:"cost" json:"cost"`
+}
+
+// GetWorkspaceCostInsightsByInterval returns the cost of workspaces in each
+// interval between start and end time. If end time is a partial interval, it
+// will be included in the results and that interval will be shorter than a
+// full one. Intervals without costs are included with a cost of 0.
+func (q *sqlQuerier) GetWorkspaceCostInsightsByInterval(ctx context.Context, arg GetWorkspaceCostInsightsByIntervalParams) ([]GetWorkspaceCostInsightsByIntervalRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceCostInsightsByInterval,
+		arg.IntervalDays,
+		arg.EndTime,
+		arg.StartTime,
+		pq.Array(arg.TemplateIDs),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceCostInsightsByIntervalRow
+	for rows.Next() {
+		var i GetWorkspaceCostInsightsByIntervalRow
+		if err := rows.Scan(&i.StartTime, &i.EndTime, &i.Cost); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceCostInsightsByTemplate = `-- name: GetWorkspaceCostInsightsByTemplate :many
+SELECT
+	wc.template_id,
+	t.name AS template_name,
+	SUM(wc.cost)::float AS cost
+FROM
+	workspace_costs AS wc
+JOIN
+	templates AS t
+ON
+	t.id = wc.template_id
+WHERE
+	wc.bucket_start >= $1::timestamptz
+	AND wc.bucket_start < $2::timestamptz
+	AND CASE WHEN COALESCE(array_length($3::uuid[], 1), 0) > 0 THEN wc.template_id = ANY($3::uuid[]) ELSE TRUE END
+GROUP BY
+	wc.template_id, t.name
+ORDER BY
+	cost DESC, t.name
+`
+
+type GetWorkspaceCostInsightsByTemplateParams struct {
+	StartTime   time.Time   `db:"start_time" json:"start_time"`
+	EndTime     time.Time   `db:"end_time" json:"end_time"`
+	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
+}
+
+type GetWorkspaceCostInsightsByTemplateRow struct {
+	TemplateID   uuid.UUID `db:"template_id" json:"template_id"`
+	TemplateName string    `db:"template_name" json:"template_name"`
+	Cost         float64   `db:"cost" json:"cost"`
+}
+
+func (q *sqlQuerier) GetWorkspaceCostInsightsByTemplate(ctx context.Context, arg GetWorkspaceCostInsightsByTemplateParams) ([]GetWorkspaceCostInsightsByTemplateRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceCostInsightsByTemplate, arg.StartTime, arg.EndTime, pq.Array(arg.TemplateIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceCostInsightsByTemplateRow
+	for rows.Next() {
+		var i GetWorkspaceCostInsightsByTemplateRow
+		if err := rows.Scan(&i.TemplateID, &i.TemplateName, &i.Cost); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceCostInsightsByUser = `-- name: GetWorkspaceCostInsightsByUser :many
+SELECT
+	wc.owner_id AS user_id,
+	u.username,
+	SUM(wc.cost)::float AS cost
+FROM
+	workspace_costs AS wc
+JOIN
+	users AS u
+ON
+	u.id = wc.owner_id
+WHERE
+	wc.bucket_start >= $1::timestamptz
+	AND wc.bucket_start < $2::timestamptz
+	AND CASE WHEN COALESCE(array_length($3::uuid[], 1), 0) > 0 THEN wc.template_id = ANY($3::uuid[]) ELSE TRUE END
+GROUP BY
+	wc.owner_id, u.username
+ORDER BY
+	cost DESC, u.username
+`
+
+type GetWorkspaceCostInsightsByUserParams struct {
+	StartTime   time.Time   `db:"start_time" json:"start_time"`
+	EndTime     time.Time   `db:"end_time" json:"end_time"`
+	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
+}
+
+type GetWorkspaceCostInsightsByUserRow struct {
+	UserID   uuid.UUID `db:"user_id" json:"user_id"`
+	Username string    `db:"username" json:"username"`
+	Cost     float64   `db:"cost" json:"cost"`
+}
+
+func (q *sqlQuerier) GetWorkspaceCostInsightsByUser(ctx context.Context, arg GetWorkspaceCostInsightsByUserParams) ([]GetWorkspaceCostInsightsByUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceCostInsightsByUser, arg.StartTime, arg.EndTime, pq.Array(arg.TemplateIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceCostInsightsByUserRow
+	for rows.Next() {
+		var i GetWorkspaceCostInsightsByUserRow
+		if err := rows.Scan(&i.UserID, &i.Username, &i.Cost); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertTemplateUsageStats = `-- name: UpsertTemplateUsageStats :exec
+WITH
+	latest_start AS (
+		SELECT
+			-- Truncate to hour so that we always look at even ranges of data.
+			date_trunc('hour', COALESCE(
+				MAX(start_time) - '1 hour'::interval,
+				-- Fallback when there are no template usage stats yet.
+				-- App stats can exist before this, but not agent stats,
+				-- limit the lookback to avoid inconsistency.
+				(SELECT MIN(created_at) FROM workspace_agent_stats)
+			)) AS t
+		FROM
 			template_usage_stats
 	),
 	workspace_app_stat_buckets AS (
@@ -3510,6 +4237,132 @@ func (q *sqlQuerier) UpsertTemplateUsageStats(ctx context.Context) error {
 	return err
 }
 
+const upsertWorkspaceCosts = `-- name: UpsertWorkspaceCosts :exec
+WITH
+	latest_start AS (
+		SELECT
+			-- Default to 1 day ago, costs are only backfilled for a day.
+			COALESCE(MAX(bucket_start) - '1 hour'::interval, NOW() - '1 day'::interval) AS t
+		FROM
+			workspace_costs
+	),
+	buckets AS (
+		SELECT
+			b::timestamptz AS bucket_start
+		FROM
+			generate_series(
+				date_trunc('hour', (SELECT t FROM latest_start)),
+				date_trunc('hour', NOW()),
+				'1 hour'::interval
+			) AS b
+	),
+	succeeded_builds AS (
+		SELECT
+			wb.workspace_id,
+			wb.build_number,
+			wb.job_id,
+			pj.completed_at
+		FROM
+			workspace_builds AS wb
+		JOIN
+			provisioner_jobs AS pj
+		ON
+			pj.id = wb.job_id
+		WHERE
+			pj.job_status = 'succeeded'
+	),
+	-- Only the builds that accrue cost in the buckets are needed: the latest
+	-- build of each workspace that completed before the first bucket, and
+	-- every build that completed after it.
+	window_builds AS (
+		(
+			SELECT DISTINCT ON (sb.workspace_id)
+				sb.*
+			FROM
+				succeeded_builds AS sb
+			WHERE
+				sb.completed_at <= date_trunc('hour', (SELECT t FROM latest_start))
+			ORDER BY
+				sb.workspace_id, sb.build_number DESC
+		)
+		UNION ALL
+		SELECT
+			sb.*
+		FROM
+			succeeded_builds AS sb
+		WHERE
+			sb.completed_at > date_trunc('hour', (SELECT t FROM latest_start))
+	),
+	build_costs AS (
+		SELECT
+			wb.workspace_id,
+			wb.completed_at AS start_time,
+			COALESCE(
+				LEAD(wb.completed_at) OVER (PARTITION BY wb.workspace_id ORDER BY wb.build_number),
+				NOW()
+			) AS end_time,
+			(
+				SELECT
+					COALESCE(SUM(wr.hourly_cost), 0)
+				FROM
+					workspace_resources AS wr
+				WHERE
+					wr.job_id = wb.job_id
+			) AS hourly_cost
+		FROM
+			window_builds AS wb
+	)
+INSERT INTO workspace_costs AS wc (
+	bucket_start,
+	workspace_id,
+	owner_id,
+	template_id,
+	organization_id,
+	cost
+)
+SELECT
+	b.bucket_start,
+	w.id,
+	w.owner_id,
+	w.template_id,
+	w.organization_id,
+	SUM(
+		bc.hourly_cost * EXTRACT(EPOCH FROM (
+			LEAST(bc.end_time, b.bucket_start + '1 hour'::interval)
+			- GREATEST(bc.start_time, b.bucket_start)
+		)) / 3600
+	)::float AS cost
+FROM
+	buckets AS b
+JOIN
+	build_costs AS bc
+ON
+	bc.start_time < b.bucket_start + '1 hour'::interval
+	AND bc.end_time > b.bucket_start
+JOIN
+	workspaces AS w
+ON
+	w.id = bc.workspace_id
+WHERE
+	bc.hourly_cost > 0
+GROUP BY
+	b.bucket_start, w.id, w.owner_id, w.template_id, w.organization_id
+ON CONFLICT
+	(bucket_start, workspace_id)
+DO UPDATE SET
+	cost = EXCLUDED.cost
+`
+
+// This query aggregates the hourly cost of the resources of workspace builds
+// into hourly buckets per workspace. A build accrues cost from the time its
+// job completed until the next successful build of the workspace completed.
+// Buckets are recalculated from the hour before the latest bucket, so the
+// query can run repeatedly.
+func (q *sqlQuerier) UpsertWorkspaceCosts(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, upsertWorkspaceCosts)
+	return err
+}
+
 const getJFrogXrayScanByWorkspaceAndAgentID = `-- name: GetJFrogXrayScanByWorkspaceAndAgentID :one
 SELECT
 	agent_id, workspace_id, critical, high, medium, results_url
@@ -3958,12 +4811,12 @@ FROM notification_messages
 WHERE id IN
       (SELECT id
        FROM notification_messages AS nested
-       WHERE nested.updated_at < NOW() - INTERVAL '7 days')
+       WHERE nested.updated_at < $1::timestamptz)
 `
 
-// Delete all notification messages which have not been updated for over a week.
-func (q *sqlQuerier) DeleteOldNotificationMessages(ctx context.Context) error {
-	_, err := q.db.ExecContext(ctx, deleteOldNotificationMessages)
+// Delete all notification messages which have not been updated since @before_time.
+func (q *sqlQuerier) DeleteOldNotificationMessages(ctx context.Context, beforeTime time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteOldNotificationMessages, beforeTime)
 	return err
 }
 
@@ -4004,36 +4857,253 @@ func (q *sqlQuerier) EnqueueNotificationMessage(ctx context.Context, arg Enqueue
 	return err
 }
 
-const fetchNewMessageMetadata = `-- name: FetchNewMessageMetadata :one
-SELECT nt.name                                                    AS notification_name,
-       nt.id                                                      AS notification_template_id,
-       nt.actions                                                 AS actions,
-       nt.method                                                  AS custom_method,
-       u.id                                                       AS user_id,
-       u.email                                                    AS user_email,
-       COALESCE(NULLIF(u.name, ''), NULLIF(u.username, ''))::text AS user_name,
-       u.username                                                 AS user_username
-FROM notification_templates nt,
-     users u
-WHERE nt.id = $1
-  AND u.id = $2
+const deleteOldNetworkReports = `-- name: DeleteOldNetworkReports :exec
+WITH
+	deleted_latency_reports AS (
+		DELETE FROM network_latency_reports WHERE reported_at < NOW() - INTERVAL '1 day'
+	)
+DELETE FROM network_connection_reports WHERE reported_at < NOW() - INTERVAL '1 day'
 `
 
-type FetchNewMessageMetadataParams struct {
-	NotificationTemplateID uuid.UUID `db:"notification_template_id" json:"notification_template_id"`
-	UserID                 uuid.UUID `db:"user_id" json:"user_id"`
+// Network reports are only used for insights about the last day.
+func (q *sqlQuerier) DeleteOldNetworkReports(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteOldNetworkReports)
+	return err
 }
 
-type FetchNewMessageMetadataRow struct {
-	NotificationName       string                 `db:"notification_name" json:"notification_name"`
-	NotificationTemplateID uuid.UUID              `db:"notification_template_id" json:"notification_template_id"`
-	Actions                []byte                 `db:"actions" json:"actions"`
-	CustomMethod           NullNotificationMethod `db:"custom_method" json:"custom_method"`
-	UserID                 uuid.UUID              `db:"user_id" json:"user_id"`
-	UserEmail              string                 `db:"user_email" json:"user_email"`
-	UserName               string                 `db:"user_name" json:"user_name"`
-	UserUsername           string                 `db:"user_username" json:"user_username"`
-}
+const getNetworkRegionInsights = `-- name: GetNetworkRegionInsights :many
+WITH
+	latencies AS (
+		SELECT
+			region_id,
+			COUNT(*) AS clients,
+			COUNT(*) FILTER (WHERE preferred) AS preferred_clients,
+			PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY latency_ms) AS latency_50,
+			PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY latency_ms) AS latency_95
+		FROM
+			network_latency_reports
+		WHERE
+			reported_at >= $1 :: timestamptz
+			AND CASE WHEN COALESCE(array_length($2 :: text[], 1), 0) > 0 THEN client_type = ANY($2 :: text[]) ELSE TRUE END
+		GROUP BY
+			region_id
+	),
+	connections AS (
+		SELECT
+			home_region_id AS region_id,
+			COUNT(*) FILTER (WHERE direct) AS direct_connections,
+			COUNT(*) FILTER (WHERE NOT direct) AS relayed_connections
+		FROM
+			network_connection_reports
+		WHERE
+			reported_at >= $1 :: timestamptz
+			AND CASE WHEN COALESCE(array_length($2 :: text[], 1), 0) > 0 THEN client_type = ANY($2 :: text[]) ELSE TRUE END
+		GROUP BY
+			home_region_id
+	)
+SELECT
+	COALESCE(l.region_id, c.region_id) :: integer AS region_id,
+	COALESCE(l.clients, 0) :: bigint AS clients,
+	COALESCE(l.preferred_clients, 0) :: bigint AS preferred_clients,
+	COALESCE(l.latency_50, -1) :: float AS latency_50,
+	COALESCE(l.latency_95, -1) :: float AS latency_95,
+	COALESCE(c.direct_connections, 0) :: bigint AS direct_connections,
+	COALESCE(c.relayed_connections, 0) :: bigint AS relayed_connections
+FROM
+	latencies l
+FULL OUTER JOIN
+	connections c
+ON
+	c.region_id = l.region_id
+ORDER BY
+	region_id ASC
+`
+
+type GetNetworkRegionInsightsParams struct {
+	ReportedAfter time.Time `db:"reported_after" json:"reported_after"`
+	ClientTypes   []string  `db:"client_types" json:"client_types"`
+}
+
+type GetNetworkRegionInsightsRow struct {
+	RegionID           int32   `db:"region_id" json:"region_id"`
+	Clients            int64   `db:"clients" json:"clients"`
+	PreferredClients   int64   `db:"preferred_clients" json:"preferred_clients"`
+	Latency50          float64 `db:"latency_50" json:"latency_50"`
+	Latency95          float64 `db:"latency_95" json:"latency_95"`
+	DirectConnections  int64   `db:"direct_connections" json:"direct_connections"`
+	RelayedConnections int64   `db:"relayed_connections" json:"relayed_connections"`
+}
+
+// GetNetworkRegionInsights returns, for each DERP region, the latency clients
+// reported to it and the share of direct connections of the clients it's the
+// home region of. Only reports since reported_after are included, and they
+// can be filtered on client_types.
+func (q *sqlQuerier) GetNetworkRegionInsights(ctx context.Context, arg GetNetworkRegionInsightsParams) ([]GetNetworkRegionInsightsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getNetworkRegionInsights, arg.ReportedAfter, pq.Array(arg.ClientTypes))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetNetworkRegionInsightsRow
+	for rows.Next() {
+		var i GetNetworkRegionInsightsRow
+		if err := rows.Scan(
+			&i.RegionID,
+			&i.Clients,
+			&i.PreferredClients,
+			&i.Latency50,
+			&i.Latency95,
+			&i.DirectConnections,
+			&i.RelayedConnections,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertNetworkConnectionReports = `-- name: UpsertNetworkConnectionReports :exec
+INSERT INTO
+	network_connection_reports (
+		client_id,
+		remote_node_id,
+		client_type,
+		home_region_id,
+		direct,
+		reported_at
+	)
+SELECT
+	unnest($1 :: uuid[]) AS client_id,
+	unnest($2 :: bigint[]) AS remote_node_id,
+	unnest($3 :: text[]) AS client_type,
+	unnest($4 :: integer[]) AS home_region_id,
+	unnest($5 :: boolean[]) AS direct,
+	unnest($6 :: timestamptz[]) AS reported_at
+ON CONFLICT (client_id, remote_node_id) DO UPDATE SET
+	client_type = EXCLUDED.client_type,
+	home_region_id = EXCLUDED.home_region_id,
+	direct = EXCLUDED.direct,
+	reported_at = EXCLUDED.reported_at
+WHERE
+	network_connection_reports.reported_at <= EXCLUDED.reported_at
+`
+
+type UpsertNetworkConnectionReportsParams struct {
+	ClientID     []uuid.UUID `db:"client_id" json:"client_id"`
+	RemoteNodeID []int64     `db:"remote_node_id" json:"remote_node_id"`
+	ClientType   []string    `db:"client_type" json:"client_type"`
+	HomeRegionID []int32     `db:"home_region_id" json:"home_region_id"`
+	Direct       []bool      `db:"direct" json:"direct"`
+	ReportedAt   []time.Time `db:"reported_at" json:"reported_at"`
+}
+
+// UpsertNetworkConnectionReports stores the latest type of the connections
+// of clients. The reports must not contain duplicate
+// (client_id, remote_node_id) pairs.
+func (q *sqlQuerier) UpsertNetworkConnectionReports(ctx context.Context, arg UpsertNetworkConnectionReportsParams) error {
+	_, err := q.db.ExecContext(ctx, upsertNetworkConnectionReports,
+		pq.Array(arg.ClientID),
+		pq.Array(arg.RemoteNodeID),
+		pq.Array(arg.ClientType),
+		pq.Array(arg.HomeRegionID),
+		pq.Array(arg.Direct),
+		pq.Array(arg.ReportedAt),
+	)
+	return err
+}
+
+const upsertNetworkLatencyReports = `-- name: UpsertNetworkLatencyReports :exec
+INSERT INTO
+	network_latency_reports (
+		client_id,
+		client_type,
+		region_id,
+		latency_ms,
+		preferred,
+		reported_at
+	)
+SELECT
+	unnest($1 :: uuid[]) AS client_id,
+	unnest($2 :: text[]) AS client_type,
+	unnest($3 :: integer[]) AS region_id,
+	unnest($4 :: double precision[]) AS latency_ms,
+	unnest($5 :: boolean[]) AS preferred,
+	unnest($6 :: timestamptz[]) AS reported_at
+ON CONFLICT (client_id, region_id) DO UPDATE SET
+	client_type = EXCLUDED.client_type,
+	latency_ms = EXCLUDED.latency_ms,
+	preferred = EXCLUDED.preferred,
+	reported_at = EXCLUDED.reported_at
+WHERE
+	network_latency_reports.reported_at <= EXCLUDED.reported_at
+`
+
+type UpsertNetworkLatencyReportsParams struct {
+	ClientID   []uuid.UUID `db:"client_id" json:"client_id"`
+	ClientType []string    `db:"client_type" json:"client_type"`
+	RegionID   []int32     `db:"region_id" json:"region_id"`
+	LatencyMS  []float64   `db:"latency_ms" json:"latency_ms"`
+	Preferred  []bool      `db:"preferred" json:"preferred"`
+	ReportedAt []time.Time `db:"reported_at" json:"reported_at"`
+}
+
+// UpsertNetworkLatencyReports stores the latest latency reported by clients
+// to each DERP region. The reports must not contain duplicate
+// (client_id, region_id) pairs.
+func (q *sqlQuerier) UpsertNetworkLatencyReports(ctx context.Context, arg UpsertNetworkLatencyReportsParams) error {
+	_, err := q.db.ExecContext(ctx, upsertNetworkLatencyReports,
+		pq.Array(arg.ClientID),
+		pq.Array(arg.ClientType),
+		pq.Array(arg.RegionID),
+		pq.Array(arg.LatencyMS),
+		pq.Array(arg.Preferred),
+		pq.Array(arg.ReportedAt),
+	)
+	return err
+}
+
+const fetchNewMessageMetadata = `-- name: FetchNewMessageMetadata :one
+SELECT nt.name                                                    AS notification_name,
+       nt.id                                                      AS notification_template_id,
+       nt.actions                                                 AS actions,
+       nt.method                                                  AS custom_method,
+       np.method                                                  AS user_method,
+       u.id                                                       AS user_id,
+       u.email                                                    AS user_email,
+       COALESCE(NULLIF(u.name, ''), NULLIF(u.username, ''))::text AS user_name,
+       u.username                                                 AS user_username
+FROM notification_templates nt
+         CROSS JOIN users u
+         LEFT JOIN notification_preferences np
+                   ON np.notification_template_id = nt.id
+                       AND np.user_id = u.id
+WHERE nt.id = $1
+  AND u.id = $2
+`
+
+type FetchNewMessageMetadataParams struct {
+	NotificationTemplateID uuid.UUID `db:"notification_template_id" json:"notification_template_id"`
+	UserID                 uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+type FetchNewMessageMetadataRow struct {
+	NotificationName       string                 `db:"notification_name" json:"notification_name"`
+	NotificationTemplateID uuid.UUID              `db:"notification_template_id" json:"notification_template_id"`
+	Actions                []byte                 `db:"actions" json:"actions"`
+	CustomMethod           NullNotificationMethod `db:"custom_method" json:"custom_method"`
+	UserMethod             NullNotificationMethod `db:"user_method" json:"user_method"`
+	UserID                 uuid.UUID              `db:"user_id" json:"user_id"`
+	UserEmail              string                 `db:"user_email" json:"user_email"`
+	UserName               string                 `db:"user_name" json:"user_name"`
+	UserUsername           string                 `db:"user_username" json:"user_username"`
+}
 
 // This is used to build up the notification_message's JSON payload.
 func (q *sqlQuerier) FetchNewMessageMetadata(ctx context.Context, arg FetchNewMessageMetadataParams) (FetchNewMessageMetadataRow, error) {
@@ -4044,6 +5114,7 @@ func (q *sqlQuerier) FetchNewMessageMetadata(ctx context.Context, arg FetchNewMe
 		&i.NotificationTemplateID,
 		&i.Actions,
 		&i.CustomMethod,
+		&i.UserMethod,
 		&i.UserID,
 		&i.UserEmail,
 		&i.UserName,
@@ -4185,7 +5256,7 @@ func (q *sqlQuerier) GetNotificationTemplatesByKind(ctx context.Context, kind No
 }
 
 const getUserNotificationPreferences = `-- name: GetUserNotificationPreferences :many
-SELECT user_id, notification_template_id, disabled, created_at, updated_at
+SELECT user_id, notification_template_id, disabled, created_at, updated_at, method
 FROM notification_preferences
 WHERE user_id = $1::uuid
 `
@@ -4205,6 +5276,7 @@ func (q *sqlQuerier) GetUserNotificationPreferences(ctx context.Context, userID
 			&i.Disabled,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Method,
 		); err != nil {
 			return nil, err
 		}
@@ -4273,6 +5345,33 @@ func (q *sqlQuerier) UpdateUserNotificationPreferences(ctx context.Context, arg
 	return result.RowsAffected()
 }
 
+const updateUserNotificationMethodPreferences = `-- name: UpdateUserNotificationMethodPreferences :execrows
+INSERT
+INTO notification_preferences (user_id, notification_template_id, method)
+SELECT $1::uuid, new_values.notification_template_id, NULLIF(new_values.method, '')::notification_method
+FROM (SELECT UNNEST($2::uuid[]) AS notification_template_id,
+             UNNEST($3::text[])                   AS method) AS new_values
+ON CONFLICT (user_id, notification_template_id) DO UPDATE
+    SET method     = EXCLUDED.method,
+        updated_at = CURRENT_TIMESTAMP
+`
+
+type UpdateUserNotificationMethodPreferencesParams struct {
+	UserID                  uuid.UUID   `db:"user_id" json:"user_id"`
+	NotificationTemplateIds []uuid.UUID `db:"notification_template_ids" json:"notification_template_ids"`
+	Methods                 []string    `db:"methods" json:"methods"`
+}
+
+// Sets the user's preferred delivery method per notification template. An empty method resets the preference, deferring
+// to the template-level or deployment-level method.
+func (q *sqlQuerier) UpdateUserNotificationMethodPreferences(ctx context.Context, arg UpdateUserNotificationMethodPreferencesParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateUserNotificationMethodPreferences, arg.UserID, pq.Array(arg.NotificationTemplateIds), pq.Array(arg.Methods))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const upsertNotificationReportGeneratorLog = `-- name: UpsertNotificationReportGeneratorLog :exec
 INSERT INTO notification_report_generator_logs (notification_template_id, last_generated_at) VALUES ($1, $2)
 ON CONFLICT (notification_template_id) DO UPDATE set last_generated_at = EXCLUDED.last_generated_at
@@ -4335,11 +5434,10 @@ const deleteOAuth2ProviderAppTokensByAppAndUserID = `-- name: DeleteOAuth2Provid
 DELETE FROM
   oauth2_provider_app_tokens
 USING
-  oauth2_provider_app_secrets, api_keys
+  api_keys
 WHERE
-  oauth2_provider_app_secrets.id = oauth2_provider_app_tokens.app_secret_id
-  AND api_keys.id = oauth2_provider_app_tokens.api_key_id
-  AND oauth2_provider_app_secrets.app_id = $1
+  api_keys.id = oauth2_provider_app_tokens.api_key_id
+  AND oauth2_provider_app_tokens.app_id = $1
 	AND api_keys.user_id = $2
 `
 
@@ -4353,8 +5451,17 @@ func (q *sqlQuerier) DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx context.Con
 	return err
 }
 
+const deleteOAuth2ProviderDeviceCodeByID = `-- name: DeleteOAuth2ProviderDeviceCodeByID :exec
+DELETE FROM oauth2_provider_device_codes WHERE id = $1
+`
+
+func (q *sqlQuerier) DeleteOAuth2ProviderDeviceCodeByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteOAuth2ProviderDeviceCodeByID, id)
+	return err
+}
+
 const getOAuth2ProviderAppByID = `-- name: GetOAuth2ProviderAppByID :one
-SELECT id, created_at, updated_at, name, icon, callback_url FROM oauth2_provider_apps WHERE id = $1
+SELECT id, created_at, updated_at, name, icon, callback_url, public_client, client_credentials_user_id FROM oauth2_provider_apps WHERE id = $1
 `
 
 func (q *sqlQuerier) GetOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderApp, error) {
@@ -4367,12 +5474,14 @@ func (q *sqlQuerier) GetOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID)
 		&i.Name,
 		&i.Icon,
 		&i.CallbackURL,
+		&i.PublicClient,
+		&i.ClientCredentialsUserID,
 	)
 	return i, err
 }
 
 const getOAuth2ProviderAppCodeByID = `-- name: GetOAuth2ProviderAppCodeByID :one
-SELECT id, created_at, expires_at, secret_prefix, hashed_secret, user_id, app_id FROM oauth2_provider_app_codes WHERE id = $1
+SELECT id, created_at, expires_at, secret_prefix, hashed_secret, user_id, app_id, code_challenge FROM oauth2_provider_app_codes WHERE id = $1
 `
 
 func (q *sqlQuerier) GetOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderAppCode, error) {
@@ -4386,12 +5495,13 @@ func (q *sqlQuerier) GetOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.U
 		&i.HashedSecret,
 		&i.UserID,
 		&i.AppID,
+		&i.CodeChallenge,
 	)
 	return i, err
 }
 
 const getOAuth2ProviderAppCodeByPrefix = `-- name: GetOAuth2ProviderAppCodeByPrefix :one
-SELECT id, created_at, expires_at, secret_prefix, hashed_secret, user_id, app_id FROM oauth2_provider_app_codes WHERE secret_prefix = $1
+SELECT id, created_at, expires_at, secret_prefix, hashed_secret, user_id, app_id, code_challenge FROM oauth2_provider_app_codes WHERE secret_prefix = $1
 `
 
 func (q *sqlQuerier) GetOAuth2ProviderAppCodeByPrefix(ctx context.Context, secretPrefix []byte) (OAuth2ProviderAppCode, error) {
@@ -4405,6 +5515,7 @@ func (q *sqlQuerier) GetOAuth2ProviderAppCodeByPrefix(ctx context.Context, secre
 		&i.HashedSecret,
 		&i.UserID,
 		&i.AppID,
+		&i.CodeChallenge,
 	)
 	return i, err
 }
@@ -4483,7 +5594,7 @@ func (q *sqlQuerier) GetOAuth2ProviderAppSecretsByAppID(ctx context.Context, app
 }
 
 const getOAuth2ProviderAppTokenByPrefix = `-- name: GetOAuth2ProviderAppTokenByPrefix :one
-SELECT id, created_at, expires_at, hash_prefix, refresh_hash, app_secret_id, api_key_id FROM oauth2_provider_app_tokens WHERE hash_prefix = $1
+SELECT id, created_at, expires_at, hash_prefix, refresh_hash, app_secret_id, api_key_id, app_id FROM oauth2_provider_app_tokens WHERE hash_prefix = $1
 `
 
 func (q *sqlQuerier) GetOAuth2ProviderAppTokenByPrefix(ctx context.Context, hashPrefix []byte) (OAuth2ProviderAppToken, error) {
@@ -4497,12 +5608,13 @@ func (q *sqlQuerier) GetOAuth2ProviderAppTokenByPrefix(ctx context.Context, hash
 		&i.RefreshHash,
 		&i.AppSecretID,
 		&i.APIKeyID,
+		&i.AppID,
 	)
 	return i, err
 }
 
 const getOAuth2ProviderApps = `-- name: GetOAuth2ProviderApps :many
-SELECT id, created_at, updated_at, name, icon, callback_url FROM oauth2_provider_apps ORDER BY (name, id) ASC
+SELECT id, created_at, updated_at, name, icon, callback_url, public_client, client_credentials_user_id FROM oauth2_provider_apps ORDER BY (name, id) ASC
 `
 
 func (q *sqlQuerier) GetOAuth2ProviderApps(ctx context.Context) ([]OAuth2ProviderApp, error) {
@@ -4521,6 +5633,8 @@ func (q *sqlQuerier) GetOAuth2ProviderApps(ctx context.Context) ([]OAuth2Provide
 			&i.Name,
 			&i.Icon,
 			&i.CallbackURL,
+			&i.PublicClient,
+			&i.ClientCredentialsUserID,
 		); err != nil {
 			return nil, err
 		}
@@ -4538,12 +5652,10 @@ func (q *sqlQuerier) GetOAuth2ProviderApps(ctx context.Context) ([]OAuth2Provide
 const getOAuth2ProviderAppsByUserID = `-- name: GetOAuth2ProviderAppsByUserID :many
 SELECT
   COUNT(DISTINCT oauth2_provider_app_tokens.id) as token_count,
-  oauth2_provider_apps.id, oauth2_provider_apps.created_at, oauth2_provider_apps.updated_at, oauth2_provider_apps.name, oauth2_provider_apps.icon, oauth2_provider_apps.callback_url
+  oauth2_provider_apps.id, oauth2_provider_apps.created_at, oauth2_provider_apps.updated_at, oauth2_provider_apps.name, oauth2_provider_apps.icon, oauth2_provider_apps.callback_url, oauth2_provider_apps.public_client, oauth2_provider_apps.client_credentials_user_id
 FROM oauth2_provider_app_tokens
-  INNER JOIN oauth2_provider_app_secrets
-    ON oauth2_provider_app_secrets.id = oauth2_provider_app_tokens.app_secret_id
   INNER JOIN oauth2_provider_apps
-    ON oauth2_provider_apps.id = oauth2_provider_app_secrets.app_id
+    ON oauth2_provider_apps.id = oauth2_provider_app_tokens.app_id
   INNER JOIN api_keys
     ON api_keys.id = oauth2_provider_app_tokens.api_key_id
 WHERE
@@ -4574,6 +5686,8 @@ func (q *sqlQuerier) GetOAuth2ProviderAppsByUserID(ctx context.Context, userID u
 			&i.OAuth2ProviderApp.Name,
 			&i.OAuth2ProviderApp.Icon,
 			&i.OAuth2ProviderApp.CallbackURL,
+			&i.OAuth2ProviderApp.PublicClient,
+			&i.OAuth2ProviderApp.ClientCredentialsUserID,
 		); err != nil {
 			return nil, err
 		}
@@ -4588,6 +5702,50 @@ func (q *sqlQuerier) GetOAuth2ProviderAppsByUserID(ctx context.Context, userID u
 	return items, nil
 }
 
+const getOAuth2ProviderDeviceCodeByPrefix = `-- name: GetOAuth2ProviderDeviceCodeByPrefix :one
+SELECT id, created_at, expires_at, secret_prefix, hashed_secret, user_code, app_id, status, user_id, last_polled_at FROM oauth2_provider_device_codes WHERE secret_prefix = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderDeviceCodeByPrefix(ctx context.Context, secretPrefix []byte) (OAuth2ProviderDeviceCode, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderDeviceCodeByPrefix, secretPrefix)
+	var i OAuth2ProviderDeviceCode
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.SecretPrefix,
+		&i.HashedSecret,
+		&i.UserCode,
+		&i.AppID,
+		&i.Status,
+		&i.UserID,
+		&i.LastPolledAt,
+	)
+	return i, err
+}
+
+const getOAuth2ProviderDeviceCodeByUserCode = `-- name: GetOAuth2ProviderDeviceCodeByUserCode :one
+SELECT id, created_at, expires_at, secret_prefix, hashed_secret, user_code, app_id, status, user_id, last_polled_at FROM oauth2_provider_device_codes WHERE user_code = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderDeviceCodeByUserCode(ctx context.Context, userCode string) (OAuth2ProviderDeviceCode, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderDeviceCodeByUserCode, userCode)
+	var i OAuth2ProviderDeviceCode
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.SecretPrefix,
+		&i.HashedSecret,
+		&i.UserCode,
+		&i.AppID,
+		&i.Status,
+		&i.UserID,
+		&i.LastPolledAt,
+	)
+	return i, err
+}
+
 const insertOAuth2ProviderApp = `-- name: InsertOAuth2ProviderApp :one
 INSERT INTO oauth2_provider_apps (
     id,
@@ -4595,24 +5753,30 @@ INSERT INTO oauth2_provider_apps (
     updated_at,
     name,
     icon,
-    callback_url
+    callback_url,
+    public_client,
+    client_credentials_user_id
 ) VALUES(
     $1,
     $2,
     $3,
     $4,
     $5,
-    $6
-) RETURNING id, created_at, updated_at, name, icon, callback_url
+    $6,
+    $7,
+    $8
+) RETURNING id, created_at, updated_at, name, icon, callback_url, public_client, client_credentials_user_id
 `
 
 type InsertOAuth2ProviderAppParams struct {
-	ID          uuid.UUID `db:"id" json:"id"`
-	CreatedAt   time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
-	Name        string    `db:"name" json:"name"`
-	Icon        string    `db:"icon" json:"icon"`
-	CallbackURL string    `db:"callback_url" json:"callback_url"`
+	ID                      uuid.UUID     `db:"id" json:"id"`
+	CreatedAt               time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt               time.Time     `db:"updated_at" json:"updated_at"`
+	Name                    string        `db:"name" json:"name"`
+	Icon                    string        `db:"icon" json:"icon"`
+	CallbackURL             string        `db:"callback_url" json:"callback_url"`
+	PublicClient            bool          `db:"public_client" json:"public_client"`
+	ClientCredentialsUserID uuid.NullUUID `db:"client_credentials_user_id" json:"client_credentials_user_id"`
 }
 
 func (q *sqlQuerier) InsertOAuth2ProviderApp(ctx context.Context, arg InsertOAuth2ProviderAppParams) (OAuth2ProviderApp, error) {
@@ -4623,6 +5787,8 @@ func (q *sqlQuerier) InsertOAuth2ProviderApp(ctx context.Context, arg InsertOAut
 		arg.Name,
 		arg.Icon,
 		arg.CallbackURL,
+		arg.PublicClient,
+		arg.ClientCredentialsUserID,
 	)
 	var i OAuth2ProviderApp
 	err := row.Scan(
@@ -4632,6 +5798,8 @@ func (q *sqlQuerier) InsertOAuth2ProviderApp(ctx context.Context, arg InsertOAut
 		&i.Name,
 		&i.Icon,
 		&i.CallbackURL,
+		&i.PublicClient,
+		&i.ClientCredentialsUserID,
 	)
 	return i, err
 }
@@ -4644,7 +5812,8 @@ INSERT INTO oauth2_provider_app_codes (
     secret_prefix,
     hashed_secret,
     app_id,
-    user_id
+    user_id,
+    code_challenge
 ) VALUES(
     $1,
     $2,
@@ -4652,18 +5821,20 @@ INSERT INTO oauth2_provider_app_codes (
     $4,
     $5,
     $6,
-    $7
-) RETURNING id, created_at, expires_at, secret_prefix, hashed_secret, user_id, app_id
+    $7,
+    $8
+) RETURNING id, created_at, expires_at, secret_prefix, hashed_secret, user_id, app_id, code_challenge
 `
 
 type InsertOAuth2ProviderAppCodeParams struct {
-	ID           uuid.UUID `db:"id" json:"id"`
-	CreatedAt    time.Time `db:"created_at" json:"created_at"`
-	ExpiresAt    time.Time `db:"expires_at" json:"expires_at"`
-	SecretPrefix []byte    `db:"secret_prefix" json:"secret_prefix"`
-	HashedSecret []byte    `db:"hashed_secret" json:"hashed_secret"`
-	AppID        uuid.UUID `db:"app_id" json:"app_id"`
-	UserID       uuid.UUID `db:"user_id" json:"user_id"`
+	ID            uuid.UUID `db:"id" json:"id"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+	ExpiresAt     time.Time `db:"expires_at" json:"expires_at"`
+	SecretPrefix  []byte    `db:"secret_prefix" json:"secret_prefix"`
+	HashedSecret  []byte    `db:"hashed_secret" json:"hashed_secret"`
+	AppID         uuid.UUID `db:"app_id" json:"app_id"`
+	UserID        uuid.UUID `db:"user_id" json:"user_id"`
+	CodeChallenge string    `db:"code_challenge" json:"code_challenge"`
 }
 
 func (q *sqlQuerier) InsertOAuth2ProviderAppCode(ctx context.Context, arg InsertOAuth2ProviderAppCodeParams) (OAuth2ProviderAppCode, error) {
@@ -4675,6 +5846,7 @@ func (q *sqlQuerier) InsertOAuth2ProviderAppCode(ctx context.Context, arg Insert
 		arg.HashedSecret,
 		arg.AppID,
 		arg.UserID,
+		arg.CodeChallenge,
 	)
 	var i OAuth2ProviderAppCode
 	err := row.Scan(
@@ -4685,6 +5857,7 @@ func (q *sqlQuerier) InsertOAuth2ProviderAppCode(ctx context.Context, arg Insert
 		&i.HashedSecret,
 		&i.UserID,
 		&i.AppID,
+		&i.CodeChallenge,
 	)
 	return i, err
 }
@@ -4746,7 +5919,8 @@ INSERT INTO oauth2_provider_app_tokens (
     hash_prefix,
     refresh_hash,
     app_secret_id,
-    api_key_id
+    api_key_id,
+    app_id
 ) VALUES(
     $1,
     $2,
@@ -4754,18 +5928,20 @@ INSERT INTO oauth2_provider_app_tokens (
     $4,
     $5,
     $6,
-    $7
-) RETURNING id, created_at, expires_at, hash_prefix, refresh_hash, app_secret_id, api_key_id
+    $7,
+    $8
+) RETURNING id, created_at, expires_at, hash_prefix, refresh_hash, app_secret_id, api_key_id, app_id
 `
 
 type InsertOAuth2ProviderAppTokenParams struct {
-	ID          uuid.UUID `db:"id" json:"id"`
-	CreatedAt   time.Time `db:"created_at" json:"created_at"`
-	ExpiresAt   time.Time `db:"expires_at" json:"expires_at"`
-	HashPrefix  []byte    `db:"hash_prefix" json:"hash_prefix"`
-	RefreshHash []byte    `db:"refresh_hash" json:"refresh_hash"`
-	AppSecretID uuid.UUID `db:"app_secret_id" json:"app_secret_id"`
-	APIKeyID    string    `db:"api_key_id" json:"api_key_id"`
+	ID          uuid.UUID     `db:"id" json:"id"`
+	CreatedAt   time.Time     `db:"created_at" json:"created_at"`
+	ExpiresAt   time.Time     `db:"expires_at" json:"expires_at"`
+	HashPrefix  []byte        `db:"hash_prefix" json:"hash_prefix"`
+	RefreshHash []byte        `db:"refresh_hash" json:"refresh_hash"`
+	AppSecretID uuid.NullUUID `db:"app_secret_id" json:"app_secret_id"`
+	APIKeyID    string        `db:"api_key_id" json:"api_key_id"`
+	AppID       uuid.UUID     `db:"app_id" json:"app_id"`
 }
 
 func (q *sqlQuerier) InsertOAuth2ProviderAppToken(ctx context.Context, arg InsertOAuth2ProviderAppTokenParams) (OAuth2ProviderAppToken, error) {
@@ -4777,6 +5953,7 @@ func (q *sqlQuerier) InsertOAuth2ProviderAppToken(ctx context.Context, arg Inser
 		arg.RefreshHash,
 		arg.AppSecretID,
 		arg.APIKeyID,
+		arg.AppID,
 	)
 	var i OAuth2ProviderAppToken
 	err := row.Scan(
@@ -4787,6 +5964,63 @@ func (q *sqlQuerier) InsertOAuth2ProviderAppToken(ctx context.Context, arg Inser
 		&i.RefreshHash,
 		&i.AppSecretID,
 		&i.APIKeyID,
+		&i.AppID,
+	)
+	return i, err
+}
+
+const insertOAuth2ProviderDeviceCode = `-- name: InsertOAuth2ProviderDeviceCode :one
+INSERT INTO oauth2_provider_device_codes (
+    id,
+    created_at,
+    expires_at,
+    secret_prefix,
+    hashed_secret,
+    user_code,
+    app_id
+) VALUES(
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+) RETURNING id, created_at, expires_at, secret_prefix, hashed_secret, user_code, app_id, status, user_id, last_polled_at
+`
+
+type InsertOAuth2ProviderDeviceCodeParams struct {
+	ID           uuid.UUID `db:"id" json:"id"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+	ExpiresAt    time.Time `db:"expires_at" json:"expires_at"`
+	SecretPrefix []byte    `db:"secret_prefix" json:"secret_prefix"`
+	HashedSecret []byte    `db:"hashed_secret" json:"hashed_secret"`
+	UserCode     string    `db:"user_code" json:"user_code"`
+	AppID        uuid.UUID `db:"app_id" json:"app_id"`
+}
+
+func (q *sqlQuerier) InsertOAuth2ProviderDeviceCode(ctx context.Context, arg InsertOAuth2ProviderDeviceCodeParams) (OAuth2ProviderDeviceCode, error) {
+	row := q.db.QueryRowContext(ctx, insertOAuth2ProviderDeviceCode,
+		arg.ID,
+		arg.CreatedAt,
+		arg.ExpiresAt,
+		arg.SecretPrefix,
+		arg.HashedSecret,
+		arg.UserCode,
+		arg.AppID,
+	)
+	var i OAuth2ProviderDeviceCode
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.SecretPrefix,
+		&i.HashedSecret,
+		&i.UserCode,
+		&i.AppID,
+		&i.Status,
+		&i.UserID,
+		&i.LastPolledAt,
 	)
 	return i, err
 }
@@ -4796,16 +6030,20 @@ UPDATE oauth2_provider_apps SET
     updated_at = $2,
     name = $3,
     icon = $4,
-    callback_url = $5
-WHERE id = $1 RETURNING id, created_at, updated_at, name, icon, callback_url
+    callback_url = $5,
+    public_client = $6,
+    client_credentials_user_id = $7
+WHERE id = $1 RETURNING id, created_at, updated_at, name, icon, callback_url, public_client, client_credentials_user_id
 `
 
 type UpdateOAuth2ProviderAppByIDParams struct {
-	ID          uuid.UUID `db:"id" json:"id"`
-	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
-	Name        string    `db:"name" json:"name"`
-	Icon        string    `db:"icon" json:"icon"`
-	CallbackURL string    `db:"callback_url" json:"callback_url"`
+	ID                      uuid.UUID     `db:"id" json:"id"`
+	UpdatedAt               time.Time     `db:"updated_at" json:"updated_at"`
+	Name                    string        `db:"name" json:"name"`
+	Icon                    string        `db:"icon" json:"icon"`
+	CallbackURL             string        `db:"callback_url" json:"callback_url"`
+	PublicClient            bool          `db:"public_client" json:"public_client"`
+	ClientCredentialsUserID uuid.NullUUID `db:"client_credentials_user_id" json:"client_credentials_user_id"`
 }
 
 func (q *sqlQuerier) UpdateOAuth2ProviderAppByID(ctx context.Context, arg UpdateOAuth2ProviderAppByIDParams) (OAuth2ProviderApp, error) {
@@ -4815,6 +6053,8 @@ func (q *sqlQuerier) UpdateOAuth2ProviderAppByID(ctx context.Context, arg Update
 		arg.Name,
 		arg.Icon,
 		arg.CallbackURL,
+		arg.PublicClient,
+		arg.ClientCredentialsUserID,
 	)
 	var i OAuth2ProviderApp
 	err := row.Scan(
@@ -4824,6 +6064,8 @@ func (q *sqlQuerier) UpdateOAuth2ProviderAppByID(ctx context.Context, arg Update
 		&i.Name,
 		&i.Icon,
 		&i.CallbackURL,
+		&i.PublicClient,
+		&i.ClientCredentialsUserID,
 	)
 	return i, err
 }
@@ -4854,6 +6096,53 @@ func (q *sqlQuerier) UpdateOAuth2ProviderAppSecretByID(ctx context.Context, arg
 	return i, err
 }
 
+const updateOAuth2ProviderDeviceCodeLastPolledAtByID = `-- name: UpdateOAuth2ProviderDeviceCodeLastPolledAtByID :exec
+UPDATE oauth2_provider_device_codes SET
+    last_polled_at = $2
+WHERE id = $1
+`
+
+type UpdateOAuth2ProviderDeviceCodeLastPolledAtByIDParams struct {
+	ID           uuid.UUID    `db:"id" json:"id"`
+	LastPolledAt sql.NullTime `db:"last_polled_at" json:"last_polled_at"`
+}
+
+func (q *sqlQuerier) UpdateOAuth2ProviderDeviceCodeLastPolledAtByID(ctx context.Context, arg UpdateOAuth2ProviderDeviceCodeLastPolledAtByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateOAuth2ProviderDeviceCodeLastPolledAtByID, arg.ID, arg.LastPolledAt)
+	return err
+}
+
+const updateOAuth2ProviderDeviceCodeStatusByID = `-- name: UpdateOAuth2ProviderDeviceCodeStatusByID :one
+UPDATE oauth2_provider_device_codes SET
+    status = $2,
+    user_id = $3
+WHERE id = $1 AND status = 'pending' RETURNING id, created_at, expires_at, secret_prefix, hashed_secret, user_code, app_id, status, user_id, last_polled_at
+`
+
+type UpdateOAuth2ProviderDeviceCodeStatusByIDParams struct {
+	ID     uuid.UUID                      `db:"id" json:"id"`
+	Status OAuth2ProviderDeviceCodeStatus `db:"status" json:"status"`
+	UserID uuid.NullUUID                  `db:"user_id" json:"user_id"`
+}
+
+func (q *sqlQuerier) UpdateOAuth2ProviderDeviceCodeStatusByID(ctx context.Context, arg UpdateOAuth2ProviderDeviceCodeStatusByIDParams) (OAuth2ProviderDeviceCode, error) {
+	row := q.db.QueryRowContext(ctx, updateOAuth2ProviderDeviceCodeStatusByID, arg.ID, arg.Status, arg.UserID)
+	var i OAuth2ProviderDeviceCode
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.SecretPrefix,
+		&i.HashedSecret,
+		&i.UserCode,
+		&i.AppID,
+		&i.Status,
+		&i.UserID,
+		&i.LastPolledAt,
+	)
+	return i, err
+}
+
 const deleteOrganizationMember = `-- name: DeleteOrganizationMember :exec
 DELETE
 	FROM
@@ -5769,13 +7058,57 @@ func (q *sqlQuerier) UpsertProvisionerDaemon(ctx context.Context, arg UpsertProv
 	return i, err
 }
 
-const getProvisionerLogsAfterID = `-- name: GetProvisionerLogsAfterID :many
-SELECT
-	job_id, created_at, source, level, stage, output, id
-FROM
+const deleteOldProvisionerJobLogs = `-- name: DeleteOldProvisionerJobLogs :execrows
+WITH
+	latest_builds AS (
+		SELECT DISTINCT ON (workspace_id)
+			job_id
+		FROM
+			workspace_builds
+		ORDER BY
+			workspace_id, build_number DESC
+	)
+DELETE FROM
 	provisioner_job_logs
 WHERE
-	job_id = $1
+	id IN (
+		SELECT
+			id
+		FROM
+			provisioner_job_logs
+		WHERE
+			created_at < $1 :: timestamptz
+			AND job_id NOT IN (SELECT job_id FROM latest_builds)
+		ORDER BY
+			id ASC
+		LIMIT
+			$2 :: int
+	)
+`
+
+type DeleteOldProvisionerJobLogsParams struct {
+	BeforeTime time.Time `db:"before_time" json:"before_time"`
+	LimitCount int32     `db:"limit_count" json:"limit_count"`
+}
+
+// Delete provisioner job logs created before @before_time in batches of
+// @limit_count rows.
+// Exception: logs of the latest build of each workspace are kept around.
+func (q *sqlQuerier) DeleteOldProvisionerJobLogs(ctx context.Context, arg DeleteOldProvisionerJobLogsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteOldProvisionerJobLogs, arg.BeforeTime, arg.LimitCount)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getProvisionerLogsAfterID = `-- name: GetProvisionerLogsAfterID :many
+SELECT
+	job_id, created_at, source, level, stage, output, id
+FROM
+	provisioner_job_logs
+WHERE
+	job_id = $1
 	AND (
 		id > $2
 	) ORDER BY id ASC
@@ -5897,26 +7230,86 @@ WHERE
 			-- elsewhere, we use the tagset type, but here we use jsonb for backward compatibility
 			-- they are aliases and the code that calls this query already relies on a different type
 			AND provisioner_tagset_contains($5 :: jsonb, potential_job.tags :: jsonb)
+			-- Workspace builds that are awaiting approval, or were rejected, must
+			-- not be acquired.
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					workspace_builds
+				INNER JOIN
+					workspace_build_approvals ON workspace_build_approvals.workspace_build_id = workspace_builds.id
+				WHERE
+					workspace_builds.job_id = potential_job.id
+					AND workspace_build_approvals.status != 'approved'
+			)
+			-- Enforce the limits on concurrently running jobs. There is no limit
+			-- if it is 0. The limits may be briefly exceeded when jobs are
+			-- acquired concurrently.
+			AND (
+				$6 :: integer <= 0
+				OR (
+					SELECT
+						COUNT(*)
+					FROM
+						provisioner_jobs AS running_job
+					WHERE
+						running_job.organization_id = potential_job.organization_id
+						AND running_job.started_at IS NOT NULL
+						AND running_job.completed_at IS NULL
+				) < $6 :: integer
+			)
+			AND (
+				$7 :: integer <= 0
+				OR (
+					SELECT
+						COUNT(*)
+					FROM
+						provisioner_jobs AS running_job
+					WHERE
+						running_job.initiator_id = potential_job.initiator_id
+						AND running_job.started_at IS NOT NULL
+						AND running_job.completed_at IS NULL
+				) < $7 :: integer
+			)
 		ORDER BY
+			potential_job.priority DESC,
+			-- Among jobs of the same priority, prefer users with fewer running
+			-- jobs so that one user queueing many jobs doesn't starve others.
+			(
+				SELECT
+					COUNT(*)
+				FROM
+					provisioner_jobs AS running_job
+				WHERE
+					running_job.organization_id = potential_job.organization_id
+					AND running_job.initiator_id = potential_job.initiator_id
+					AND running_job.started_at IS NOT NULL
+					AND running_job.completed_at IS NULL
+			) ASC,
 			potential_job.created_at
 		FOR UPDATE
 		SKIP LOCKED
 		LIMIT
 			1
-	) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status
+	) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, priority
 `
 
 type AcquireProvisionerJobParams struct {
-	StartedAt       sql.NullTime      `db:"started_at" json:"started_at"`
-	WorkerID        uuid.NullUUID     `db:"worker_id" json:"worker_id"`
-	OrganizationID  uuid.UUID         `db:"organization_id" json:"organization_id"`
-	Types           []ProvisionerType `db:"types" json:"types"`
-	ProvisionerTags json.RawMessage   `db:"provisioner_tags" json:"provisioner_tags"`
+	StartedAt           sql.NullTime      `db:"started_at" json:"started_at"`
+	WorkerID            uuid.NullUUID     `db:"worker_id" json:"worker_id"`
+	OrganizationID      uuid.UUID         `db:"organization_id" json:"organization_id"`
+	Types               []ProvisionerType `db:"types" json:"types"`
+	ProvisionerTags     json.RawMessage   `db:"provisioner_tags" json:"provisioner_tags"`
+	MaxOrganizationJobs int32             `db:"max_organization_jobs" json:"max_organization_jobs"`
+	MaxUserJobs         int32             `db:"max_user_jobs" json:"max_user_jobs"`
 }
 
 // Acquires the lock for a single job that isn't started, completed,
 // canceled, and that matches an array of provisioner types.
 //
+// Jobs with a higher priority are acquired first.
+//
 // SKIP LOCKED is used to jump over locked rows. This prevents
 // multiple provisioners from acquiring the same jobs. See:
 // https://www.postgresql.org/docs/9.5/sql-select.html#SQL-FOR-UPDATE-SHARE
@@ -5927,6 +7320,8 @@ func (q *sqlQuerier) AcquireProvisionerJob(ctx context.Context, arg AcquireProvi
 		arg.OrganizationID,
 		pq.Array(arg.Types),
 		arg.ProvisionerTags,
+		arg.MaxOrganizationJobs,
+		arg.MaxUserJobs,
 	)
 	var i ProvisionerJob
 	err := row.Scan(
@@ -5949,13 +7344,14 @@ func (q *sqlQuerier) AcquireProvisionerJob(ctx context.Context, arg AcquireProvi
 		&i.ErrorCode,
 		&i.TraceMetadata,
 		&i.JobStatus,
+		&i.Priority,
 	)
 	return i, err
 }
 
 const getHungProvisionerJobs = `-- name: GetHungProvisionerJobs :many
 SELECT
-	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status
+	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, priority
 FROM
 	provisioner_jobs
 WHERE
@@ -5993,6 +7389,7 @@ func (q *sqlQuerier) GetHungProvisionerJobs(ctx context.Context, updatedAt time.
 			&i.ErrorCode,
 			&i.TraceMetadata,
 			&i.JobStatus,
+			&i.Priority,
 		); err != nil {
 			return nil, err
 		}
@@ -6007,9 +7404,63 @@ func (q *sqlQuerier) GetHungProvisionerJobs(ctx context.Context, updatedAt time.
 	return items, nil
 }
 
+const getPendingProvisionerJobTags = `-- name: GetPendingProvisionerJobTags :many
+SELECT DISTINCT
+	organization_id,
+	provisioner,
+	tags
+FROM
+	provisioner_jobs
+WHERE
+	started_at IS NULL
+	AND canceled_at IS NULL
+	AND completed_at IS NULL
+	AND (
+		organization_id = $1
+		OR initiator_id = $2
+	)
+`
+
+type GetPendingProvisionerJobTagsParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	InitiatorID    uuid.UUID `db:"initiator_id" json:"initiator_id"`
+}
+
+type GetPendingProvisionerJobTagsRow struct {
+	OrganizationID uuid.UUID       `db:"organization_id" json:"organization_id"`
+	Provisioner    ProvisionerType `db:"provisioner" json:"provisioner"`
+	Tags           StringMap       `db:"tags" json:"tags"`
+}
+
+// Returns the distinct provisioner types and tags of the pending jobs in an
+// organization or initiated by a user. These are the jobs that may have been
+// held back by the limits on concurrently running jobs.
+func (q *sqlQuerier) GetPendingProvisionerJobTags(ctx context.Context, arg GetPendingProvisionerJobTagsParams) ([]GetPendingProvisionerJobTagsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPendingProvisionerJobTags, arg.OrganizationID, arg.InitiatorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPendingProvisionerJobTagsRow
+	for rows.Next() {
+		var i GetPendingProvisionerJobTagsRow
+		if err := rows.Scan(&i.OrganizationID, &i.Provisioner, &i.Tags); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getProvisionerJobByID = `-- name: GetProvisionerJobByID :one
 SELECT
-	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status
+	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, priority
 FROM
 	provisioner_jobs
 WHERE
@@ -6039,10 +7490,47 @@ func (q *sqlQuerier) GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (P
 		&i.ErrorCode,
 		&i.TraceMetadata,
 		&i.JobStatus,
+		&i.Priority,
 	)
 	return i, err
 }
 
+const getProvisionerJobResourceChangesByJobID = `-- name: GetProvisionerJobResourceChangesByJobID :many
+SELECT job_id, address, resource_type, resource_name, action, attributes FROM provisioner_job_resource_changes
+WHERE job_id = $1
+ORDER BY address ASC
+`
+
+func (q *sqlQuerier) GetProvisionerJobResourceChangesByJobID(ctx context.Context, jobID uuid.UUID) ([]ProvisionerJobResourceChange, error) {
+	rows, err := q.db.QueryContext(ctx, getProvisionerJobResourceChangesByJobID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProvisionerJobResourceChange
+	for rows.Next() {
+		var i ProvisionerJobResourceChange
+		if err := rows.Scan(
+			&i.JobID,
+			&i.Address,
+			&i.ResourceType,
+			&i.ResourceName,
+			&i.Action,
+			&i.Attributes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getProvisionerJobTimingsByJobID = `-- name: GetProvisionerJobTimingsByJobID :many
 SELECT job_id, started_at, ended_at, stage, source, action, resource FROM provisioner_job_timings
 WHERE job_id = $1
@@ -6082,7 +7570,7 @@ func (q *sqlQuerier) GetProvisionerJobTimingsByJobID(ctx context.Context, jobID
 
 const getProvisionerJobsByIDs = `-- name: GetProvisionerJobsByIDs :many
 SELECT
-	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status
+	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, priority
 FROM
 	provisioner_jobs
 WHERE
@@ -6118,6 +7606,7 @@ func (q *sqlQuerier) GetProvisionerJobsByIDs(ctx context.Context, ids []uuid.UUI
 			&i.ErrorCode,
 			&i.TraceMetadata,
 			&i.JobStatus,
+			&i.Priority,
 		); err != nil {
 			return nil, err
 		}
@@ -6135,7 +7624,7 @@ func (q *sqlQuerier) GetProvisionerJobsByIDs(ctx context.Context, ids []uuid.UUI
 const getProvisionerJobsByIDsWithQueuePosition = `-- name: GetProvisionerJobsByIDsWithQueuePosition :many
 WITH pending_jobs AS (
     SELECT
-        id, created_at
+        id, created_at, priority
     FROM
         provisioner_jobs
     WHERE
@@ -6150,7 +7639,7 @@ WITH pending_jobs AS (
 queue_position AS (
     SELECT
         id,
-        ROW_NUMBER() OVER (ORDER BY created_at ASC) AS queue_position
+        ROW_NUMBER() OVER (ORDER BY priority DESC, created_at ASC) AS queue_position
     FROM
         pending_jobs
 ),
@@ -6158,7 +7647,7 @@ queue_size AS (
 	SELECT COUNT(*) AS count FROM pending_jobs
 )
 SELECT
-	pj.id, pj.created_at, pj.updated_at, pj.started_at, pj.canceled_at, pj.completed_at, pj.error, pj.organization_id, pj.initiator_id, pj.provisioner, pj.storage_method, pj.type, pj.input, pj.worker_id, pj.file_id, pj.tags, pj.error_code, pj.trace_metadata, pj.job_status,
+	pj.id, pj.created_at, pj.updated_at, pj.started_at, pj.canceled_at, pj.completed_at, pj.error, pj.organization_id, pj.initiator_id, pj.provisioner, pj.storage_method, pj.type, pj.input, pj.worker_id, pj.file_id, pj.tags, pj.error_code, pj.trace_metadata, pj.job_status, pj.priority,
     COALESCE(qp.queue_position, 0) AS queue_position,
     COALESCE(qs.count, 0) AS queue_size
 FROM
@@ -6177,6 +7666,9 @@ type GetProvisionerJobsByIDsWithQueuePositionRow struct {
 	QueueSize      int64          `db:"queue_size" json:"queue_size"`
 }
 
+// Queue positions are ordered by priority and creation time only. They don't
+// account for the limits on concurrently running jobs, so a job held back by
+// the limit of its organization or user may be acquired after jobs behind it.
 func (q *sqlQuerier) GetProvisionerJobsByIDsWithQueuePosition(ctx context.Context, ids []uuid.UUID) ([]GetProvisionerJobsByIDsWithQueuePositionRow, error) {
 	rows, err := q.db.QueryContext(ctx, getProvisionerJobsByIDsWithQueuePosition, pq.Array(ids))
 	if err != nil {
@@ -6206,6 +7698,7 @@ func (q *sqlQuerier) GetProvisionerJobsByIDsWithQueuePosition(ctx context.Contex
 			&i.ProvisionerJob.ErrorCode,
 			&i.ProvisionerJob.TraceMetadata,
 			&i.ProvisionerJob.JobStatus,
+			&i.ProvisionerJob.Priority,
 			&i.QueuePosition,
 			&i.QueueSize,
 		); err != nil {
@@ -6223,7 +7716,7 @@ func (q *sqlQuerier) GetProvisionerJobsByIDsWithQueuePosition(ctx context.Contex
 }
 
 const getProvisionerJobsCreatedAfter = `-- name: GetProvisionerJobsCreatedAfter :many
-SELECT id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status FROM provisioner_jobs WHERE created_at > $1
+SELECT id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, priority FROM provisioner_jobs WHERE created_at > $1
 `
 
 func (q *sqlQuerier) GetProvisionerJobsCreatedAfter(ctx context.Context, createdAt time.Time) ([]ProvisionerJob, error) {
@@ -6255,6 +7748,7 @@ func (q *sqlQuerier) GetProvisionerJobsCreatedAfter(ctx context.Context, created
 			&i.ErrorCode,
 			&i.TraceMetadata,
 			&i.JobStatus,
+			&i.Priority,
 		); err != nil {
 			return nil, err
 		}
@@ -6283,10 +7777,11 @@ INSERT INTO
 		"type",
 		"input",
 		tags,
-		trace_metadata
+		trace_metadata,
+		priority
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, priority
 `
 
 type InsertProvisionerJobParams struct {
@@ -6302,6 +7797,7 @@ type InsertProvisionerJobParams struct {
 	Input          json.RawMessage          `db:"input" json:"input"`
 	Tags           StringMap                `db:"tags" json:"tags"`
 	TraceMetadata  pqtype.NullRawMessage    `db:"trace_metadata" json:"trace_metadata"`
+	Priority       int32                    `db:"priority" json:"priority"`
 }
 
 func (q *sqlQuerier) InsertProvisionerJob(ctx context.Context, arg InsertProvisionerJobParams) (ProvisionerJob, error) {
@@ -6318,6 +7814,7 @@ func (q *sqlQuerier) InsertProvisionerJob(ctx context.Context, arg InsertProvisi
 		arg.Input,
 		arg.Tags,
 		arg.TraceMetadata,
+		arg.Priority,
 	)
 	var i ProvisionerJob
 	err := row.Scan(
@@ -6340,10 +7837,69 @@ func (q *sqlQuerier) InsertProvisionerJob(ctx context.Context, arg InsertProvisi
 		&i.ErrorCode,
 		&i.TraceMetadata,
 		&i.JobStatus,
+		&i.Priority,
 	)
 	return i, err
 }
 
+const insertProvisionerJobResourceChanges = `-- name: InsertProvisionerJobResourceChanges :many
+INSERT INTO provisioner_job_resource_changes (job_id, address, resource_type, resource_name, action, attributes)
+SELECT
+    $1::uuid AS provisioner_job_id,
+    unnest($2::text[]),
+    unnest($3::text[]),
+    unnest($4::text[]),
+    unnest($5::provisioner_job_resource_change_action[]),
+    jsonb_array_elements($6::jsonb)
+RETURNING job_id, address, resource_type, resource_name, action, attributes
+`
+
+type InsertProvisionerJobResourceChangesParams struct {
+	JobID        uuid.UUID                            `db:"job_id" json:"job_id"`
+	Address      []string                             `db:"address" json:"address"`
+	ResourceType []string                             `db:"resource_type" json:"resource_type"`
+	ResourceName []string                             `db:"resource_name" json:"resource_name"`
+	Action       []ProvisionerJobResourceChangeAction `db:"action" json:"action"`
+	Attributes   json.RawMessage                      `db:"attributes" json:"attributes"`
+}
+
+func (q *sqlQuerier) InsertProvisionerJobResourceChanges(ctx context.Context, arg InsertProvisionerJobResourceChangesParams) ([]ProvisionerJobResourceChange, error) {
+	rows, err := q.db.QueryContext(ctx, insertProvisionerJobResourceChanges,
+		arg.JobID,
+		pq.Array(arg.Address),
+		pq.Array(arg.ResourceType),
+		pq.Array(arg.ResourceName),
+		pq.Array(arg.Action),
+		arg.Attributes,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProvisionerJobResourceChange
+	for rows.Next() {
+		var i ProvisionerJobResourceChange
+		if err := rows.Scan(
+			&i.JobID,
+			&i.Address,
+			&i.ResourceType,
+			&i.ResourceName,
+			&i.Action,
+			&i.Attributes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const insertProvisionerJobTimings = `-- name: InsertProvisionerJobTimings :many
 INSERT INTO provisioner_job_timings (job_id, started_at, ended_at, stage, source, action, resource)
 SELECT
@@ -7524,76 +9080,445 @@ func (q *sqlQuerier) UpdateCustomRole(ctx context.Context, arg UpdateCustomRoleP
 	return i, err
 }
 
-const deleteRuntimeConfig = `-- name: DeleteRuntimeConfig :exec
-DELETE FROM site_configs
-WHERE site_configs.key = $1
+const deleteSecretByID = `-- name: DeleteSecretByID :exec
+DELETE FROM
+	secrets
+WHERE
+	id = $1
 `
 
-func (q *sqlQuerier) DeleteRuntimeConfig(ctx context.Context, key string) error {
-	_, err := q.db.ExecContext(ctx, deleteRuntimeConfig, key)
+func (q *sqlQuerier) DeleteSecretByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteSecretByID, id)
 	return err
 }
 
-const getAnnouncementBanners = `-- name: GetAnnouncementBanners :one
-SELECT value FROM site_configs WHERE key = 'announcement_banners'
+const getSecretByID = `-- name: GetSecretByID :one
+SELECT
+	id, created_at, updated_at, name, description, value, value_key_id, env_name, file_path, user_id, organization_id, template_id
+FROM
+	secrets
+WHERE
+	id = $1
+LIMIT
+	1
 `
 
-func (q *sqlQuerier) GetAnnouncementBanners(ctx context.Context) (string, error) {
-	row := q.db.QueryRowContext(ctx, getAnnouncementBanners)
-	var value string
-	err := row.Scan(&value)
-	return value, err
+func (q *sqlQuerier) GetSecretByID(ctx context.Context, id uuid.UUID) (Secret, error) {
+	row := q.db.QueryRowContext(ctx, getSecretByID, id)
+	var i Secret
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Description,
+		&i.Value,
+		&i.ValueKeyID,
+		&i.EnvName,
+		&i.FilePath,
+		&i.UserID,
+		&i.OrganizationID,
+		&i.TemplateID,
+	)
+	return i, err
 }
 
-const getAppSecurityKey = `-- name: GetAppSecurityKey :one
-SELECT value FROM site_configs WHERE key = 'app_signing_key'
+const getSecretByScopeAndName = `-- name: GetSecretByScopeAndName :one
+SELECT
+	id, created_at, updated_at, name, description, value, value_key_id, env_name, file_path, user_id, organization_id, template_id
+FROM
+	secrets
+WHERE
+	user_id IS NOT DISTINCT FROM $1 :: uuid
+	AND organization_id IS NOT DISTINCT FROM $2 :: uuid
+	AND template_id IS NOT DISTINCT FROM $3 :: uuid
+	AND name = $4
+LIMIT
+	1
 `
 
-func (q *sqlQuerier) GetAppSecurityKey(ctx context.Context) (string, error) {
-	row := q.db.QueryRowContext(ctx, getAppSecurityKey)
-	var value string
-	err := row.Scan(&value)
-	return value, err
+type GetSecretByScopeAndNameParams struct {
+	UserID         uuid.NullUUID `db:"user_id" json:"user_id"`
+	OrganizationID uuid.NullUUID `db:"organization_id" json:"organization_id"`
+	TemplateID     uuid.NullUUID `db:"template_id" json:"template_id"`
+	Name           string        `db:"name" json:"name"`
 }
 
-const getApplicationName = `-- name: GetApplicationName :one
-SELECT value FROM site_configs WHERE key = 'application_name'
-`
-
-func (q *sqlQuerier) GetApplicationName(ctx context.Context) (string, error) {
-	row := q.db.QueryRowContext(ctx, getApplicationName)
-	var value string
-	err := row.Scan(&value)
-	return value, err
+func (q *sqlQuerier) GetSecretByScopeAndName(ctx context.Context, arg GetSecretByScopeAndNameParams) (Secret, error) {
+	row := q.db.QueryRowContext(ctx, getSecretByScopeAndName,
+		arg.UserID,
+		arg.OrganizationID,
+		arg.TemplateID,
+		arg.Name,
+	)
+	var i Secret
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Description,
+		&i.Value,
+		&i.ValueKeyID,
+		&i.EnvName,
+		&i.FilePath,
+		&i.UserID,
+		&i.OrganizationID,
+		&i.TemplateID,
+	)
+	return i, err
 }
 
-const getCoordinatorResumeTokenSigningKey = `-- name: GetCoordinatorResumeTokenSigningKey :one
-SELECT value FROM site_configs WHERE key = 'coordinator_resume_token_signing_key'
+const getSecrets = `-- name: GetSecrets :many
+SELECT
+	id, created_at, updated_at, name, description, value, value_key_id, env_name, file_path, user_id, organization_id, template_id
+FROM
+	secrets
+ORDER BY
+	created_at ASC
 `
 
-func (q *sqlQuerier) GetCoordinatorResumeTokenSigningKey(ctx context.Context) (string, error) {
-	row := q.db.QueryRowContext(ctx, getCoordinatorResumeTokenSigningKey)
-	var value string
-	err := row.Scan(&value)
-	return value, err
+func (q *sqlQuerier) GetSecrets(ctx context.Context) ([]Secret, error) {
+	rows, err := q.db.QueryContext(ctx, getSecrets)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Secret
+	for rows.Next() {
+		var i Secret
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Description,
+			&i.Value,
+			&i.ValueKeyID,
+			&i.EnvName,
+			&i.FilePath,
+			&i.UserID,
+			&i.OrganizationID,
+			&i.TemplateID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const getDERPMeshKey = `-- name: GetDERPMeshKey :one
-SELECT value FROM site_configs WHERE key = 'derp_mesh_key'
+const getSecretsByScope = `-- name: GetSecretsByScope :many
+SELECT
+	id, created_at, updated_at, name, description, value, value_key_id, env_name, file_path, user_id, organization_id, template_id
+FROM
+	secrets
+WHERE
+	user_id IS NOT DISTINCT FROM $1 :: uuid
+	AND organization_id IS NOT DISTINCT FROM $2 :: uuid
+	AND template_id IS NOT DISTINCT FROM $3 :: uuid
+ORDER BY
+	name ASC
 `
 
-func (q *sqlQuerier) GetDERPMeshKey(ctx context.Context) (string, error) {
-	row := q.db.QueryRowContext(ctx, getDERPMeshKey)
-	var value string
-	err := row.Scan(&value)
-	return value, err
+type GetSecretsByScopeParams struct {
+	UserID         uuid.NullUUID `db:"user_id" json:"user_id"`
+	OrganizationID uuid.NullUUID `db:"organization_id" json:"organization_id"`
+	TemplateID     uuid.NullUUID `db:"template_id" json:"template_id"`
 }
 
-const getDefaultProxyConfig = `-- name: GetDefaultProxyConfig :one
-SELECT
-	COALESCE((SELECT value FROM site_configs WHERE key = 'default_proxy_display_name'), 'Default') :: text AS display_name,
-	COALESCE((SELECT value FROM site_configs WHERE key = 'default_proxy_icon_url'), '/emojis/1f3e1.png') :: text AS icon_url
-`
+// Returns the secrets of exactly one user, organization or template. The
+// other scopes must be NULL.
+func (q *sqlQuerier) GetSecretsByScope(ctx context.Context, arg GetSecretsByScopeParams) ([]Secret, error) {
+	rows, err := q.db.QueryContext(ctx, getSecretsByScope, arg.UserID, arg.OrganizationID, arg.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Secret
+	for rows.Next() {
+		var i Secret
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Description,
+			&i.Value,
+			&i.ValueKeyID,
+			&i.EnvName,
+			&i.FilePath,
+			&i.UserID,
+			&i.OrganizationID,
+			&i.TemplateID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSecretsForWorkspace = `-- name: GetSecretsForWorkspace :many
+SELECT
+	id, created_at, updated_at, name, description, value, value_key_id, env_name, file_path, user_id, organization_id, template_id
+FROM
+	secrets
+WHERE
+	user_id = $1 :: uuid
+	OR template_id = $2 :: uuid
+	OR organization_id = $3 :: uuid
+ORDER BY
+	name ASC
+`
+
+type GetSecretsForWorkspaceParams struct {
+	OwnerID        uuid.UUID `db:"owner_id" json:"owner_id"`
+	TemplateID     uuid.UUID `db:"template_id" json:"template_id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+}
+
+// Returns the secrets that apply to a workspace: those of its owner, its
+// template and its organization.
+func (q *sqlQuerier) GetSecretsForWorkspace(ctx context.Context, arg GetSecretsForWorkspaceParams) ([]Secret, error) {
+	rows, err := q.db.QueryContext(ctx, getSecretsForWorkspace, arg.OwnerID, arg.TemplateID, arg.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Secret
+	for rows.Next() {
+		var i Secret
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Description,
+			&i.Value,
+			&i.ValueKeyID,
+			&i.EnvName,
+			&i.FilePath,
+			&i.UserID,
+			&i.OrganizationID,
+			&i.TemplateID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertSecret = `-- name: InsertSecret :one
+INSERT INTO
+	secrets (
+		id,
+		created_at,
+		updated_at,
+		name,
+		description,
+		value,
+		value_key_id,
+		env_name,
+		file_path,
+		user_id,
+		organization_id,
+		template_id
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, created_at, updated_at, name, description, value, value_key_id, env_name, file_path, user_id, organization_id, template_id
+`
+
+type InsertSecretParams struct {
+	ID             uuid.UUID      `db:"id" json:"id"`
+	CreatedAt      time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time      `db:"updated_at" json:"updated_at"`
+	Name           string         `db:"name" json:"name"`
+	Description    string         `db:"description" json:"description"`
+	Value          string         `db:"value" json:"value"`
+	ValueKeyID     sql.NullString `db:"value_key_id" json:"value_key_id"`
+	EnvName        string         `db:"env_name" json:"env_name"`
+	FilePath       string         `db:"file_path" json:"file_path"`
+	UserID         uuid.NullUUID  `db:"user_id" json:"user_id"`
+	OrganizationID uuid.NullUUID  `db:"organization_id" json:"organization_id"`
+	TemplateID     uuid.NullUUID  `db:"template_id" json:"template_id"`
+}
+
+func (q *sqlQuerier) InsertSecret(ctx context.Context, arg InsertSecretParams) (Secret, error) {
+	row := q.db.QueryRowContext(ctx, insertSecret,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.Name,
+		arg.Description,
+		arg.Value,
+		arg.ValueKeyID,
+		arg.EnvName,
+		arg.FilePath,
+		arg.UserID,
+		arg.OrganizationID,
+		arg.TemplateID,
+	)
+	var i Secret
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Description,
+		&i.Value,
+		&i.ValueKeyID,
+		&i.EnvName,
+		&i.FilePath,
+		&i.UserID,
+		&i.OrganizationID,
+		&i.TemplateID,
+	)
+	return i, err
+}
+
+const updateSecretByID = `-- name: UpdateSecretByID :one
+UPDATE
+	secrets
+SET
+	updated_at = $1,
+	description = $2,
+	value = $3,
+	value_key_id = $4,
+	env_name = $5,
+	file_path = $6
+WHERE
+	id = $7
+RETURNING id, created_at, updated_at, name, description, value, value_key_id, env_name, file_path, user_id, organization_id, template_id
+`
+
+type UpdateSecretByIDParams struct {
+	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
+	Description string         `db:"description" json:"description"`
+	Value       string         `db:"value" json:"value"`
+	ValueKeyID  sql.NullString `db:"value_key_id" json:"value_key_id"`
+	EnvName     string         `db:"env_name" json:"env_name"`
+	FilePath    string         `db:"file_path" json:"file_path"`
+	ID          uuid.UUID      `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateSecretByID(ctx context.Context, arg UpdateSecretByIDParams) (Secret, error) {
+	row := q.db.QueryRowContext(ctx, updateSecretByID,
+		arg.UpdatedAt,
+		arg.Description,
+		arg.Value,
+		arg.ValueKeyID,
+		arg.EnvName,
+		arg.FilePath,
+		arg.ID,
+	)
+	var i Secret
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Description,
+		&i.Value,
+		&i.ValueKeyID,
+		&i.EnvName,
+		&i.FilePath,
+		&i.UserID,
+		&i.OrganizationID,
+		&i.TemplateID,
+	)
+	return i, err
+}
+
+const deleteRuntimeConfig = `-- name: DeleteRuntimeConfig :exec
+DELETE FROM site_configs
+WHERE site_configs.key = $1
+`
+
+func (q *sqlQuerier) DeleteRuntimeConfig(ctx context.Context, key string) error {
+	_, err := q.db.ExecContext(ctx, deleteRuntimeConfig, key)
+	return err
+}
+
+const getAnnouncementBanners = `-- name: GetAnnouncementBanners :one
+SELECT value FROM site_configs WHERE key = 'announcement_banners'
+`
+
+func (q *sqlQuerier) GetAnnouncementBanners(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getAnnouncementBanners)
+	var value string
+	err := row.Scan(&value)
+	return value, err
+}
+
+const getAppSecurityKey = `-- name: GetAppSecurityKey :one
+SELECT value FROM site_configs WHERE key = 'app_signing_key'
+`
+
+func (q *sqlQuerier) GetAppSecurityKey(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getAppSecurityKey)
+	var value string
+	err := row.Scan(&value)
+	return value, err
+}
+
+const getApplicationName = `-- name: GetApplicationName :one
+SELECT value FROM site_configs WHERE key = 'application_name'
+`
+
+func (q *sqlQuerier) GetApplicationName(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getApplicationName)
+	var value string
+	err := row.Scan(&value)
+	return value, err
+}
+
+const getCoordinatorResumeTokenSigningKey = `-- name: GetCoordinatorResumeTokenSigningKey :one
+SELECT value FROM site_configs WHERE key = 'coordinator_resume_token_signing_key'
+`
+
+func (q *sqlQuerier) GetCoordinatorResumeTokenSigningKey(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getCoordinatorResumeTokenSigningKey)
+	var value string
+	err := row.Scan(&value)
+	return value, err
+}
+
+const getDERPMeshKey = `-- name: GetDERPMeshKey :one
+SELECT value FROM site_configs WHERE key = 'derp_mesh_key'
+`
+
+func (q *sqlQuerier) GetDERPMeshKey(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getDERPMeshKey)
+	var value string
+	err := row.Scan(&value)
+	return value, err
+}
+
+const getDefaultProxyConfig = `-- name: GetDefaultProxyConfig :one
+SELECT
+	COALESCE((SELECT value FROM site_configs WHERE key = 'default_proxy_display_name'), 'Default') :: text AS display_name,
+	COALESCE((SELECT value FROM site_configs WHERE key = 'default_proxy_icon_url'), '/emojis/1f3e1.png') :: text AS icon_url
+`
 
 type GetDefaultProxyConfigRow struct {
 	DisplayName string `db:"display_name" json:"display_name"`
@@ -8573,49 +10498,361 @@ func (q *sqlQuerier) UpsertTailnetTunnel(ctx context.Context, arg UpsertTailnetT
 	return i, err
 }
 
-const getTemplateAverageBuildTime = `-- name: GetTemplateAverageBuildTime :one
-WITH build_times AS (
+const getActiveTemplateRolloutByTemplateID = `-- name: GetActiveTemplateRolloutByTemplateID :one
 SELECT
-	EXTRACT(EPOCH FROM (pj.completed_at - pj.started_at))::FLOAT AS exec_time_sec,
-	workspace_builds.transition
+	id, template_id, template_version_id, created_by, created_at, updated_at, completed_at, status, status_reason, percent, group_id, min_builds, max_failure_rate, auto_promote
 FROM
-	workspace_builds
-JOIN template_versions ON
-	workspace_builds.template_version_id = template_versions.id
-JOIN provisioner_jobs pj ON
-	workspace_builds.job_id = pj.id
+	template_rollouts
 WHERE
-	template_versions.template_id = $1 AND
-		(pj.completed_at IS NOT NULL) AND (pj.started_at IS NOT NULL) AND
-		(pj.started_at > $2) AND
-		(pj.canceled_at IS NULL) AND
-		((pj.error IS NULL) OR (pj.error = ''))
-ORDER BY
-	workspace_builds.created_at DESC
-)
-SELECT
-	-- Postgres offers no clear way to DRY this short of a function or other
-	-- complexities.
-	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'start')), -1)::FLOAT AS start_50,
-	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'stop')), -1)::FLOAT AS stop_50,
-	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'delete')), -1)::FLOAT AS delete_50,
-	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'start')), -1)::FLOAT AS start_95,
-	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'stop')), -1)::FLOAT AS stop_95,
-	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'delete')), -1)::FLOAT AS delete_95
-FROM build_times
+	template_id = $1
+	AND status = 'in_progress'::template_rollout_status
 `
 
-type GetTemplateAverageBuildTimeParams struct {
-	TemplateID uuid.NullUUID `db:"template_id" json:"template_id"`
-	StartTime  sql.NullTime  `db:"start_time" json:"start_time"`
+func (q *sqlQuerier) GetActiveTemplateRolloutByTemplateID(ctx context.Context, templateID uuid.UUID) (TemplateRollout, error) {
+	row := q.db.QueryRowContext(ctx, getActiveTemplateRolloutByTemplateID, templateID)
+	var i TemplateRollout
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.TemplateVersionID,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CompletedAt,
+		&i.Status,
+		&i.StatusReason,
+		&i.Percent,
+		&i.GroupID,
+		&i.MinBuilds,
+		&i.MaxFailureRate,
+		&i.AutoPromote,
+	)
+	return i, err
 }
 
-type GetTemplateAverageBuildTimeRow struct {
-	Start50  float64 `db:"start_50" json:"start_50"`
-	Stop50   float64 `db:"stop_50" json:"stop_50"`
-	Delete50 float64 `db:"delete_50" json:"delete_50"`
-	Start95  float64 `db:"start_95" json:"start_95"`
-	Stop95   float64 `db:"stop_95" json:"stop_95"`
+const getTemplateRolloutBuildCounts = `-- name: GetTemplateRolloutBuildCounts :one
+SELECT
+	COUNT(*)::bigint AS builds,
+	COUNT(*) FILTER (WHERE provisioner_jobs.error IS NOT NULL AND provisioner_jobs.error != '')::bigint AS failed_builds
+FROM
+	workspace_builds
+JOIN
+	provisioner_jobs
+ON
+	provisioner_jobs.id = workspace_builds.job_id
+WHERE
+	workspace_builds.template_version_id = $1
+	AND workspace_builds.transition = 'start'::workspace_transition
+	AND workspace_builds.created_at >= $2 :: timestamptz
+	AND provisioner_jobs.completed_at IS NOT NULL
+	AND provisioner_jobs.canceled_at IS NULL
+`
+
+type GetTemplateRolloutBuildCountsParams struct {
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+	Since             time.Time `db:"since" json:"since"`
+}
+
+type GetTemplateRolloutBuildCountsRow struct {
+	Builds       int64 `db:"builds" json:"builds"`
+	FailedBuilds int64 `db:"failed_builds" json:"failed_builds"`
+}
+
+// Counts the completed start builds of a template version since the given
+// time. Canceled builds are not counted.
+func (q *sqlQuerier) GetTemplateRolloutBuildCounts(ctx context.Context, arg GetTemplateRolloutBuildCountsParams) (GetTemplateRolloutBuildCountsRow, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateRolloutBuildCounts, arg.TemplateVersionID, arg.Since)
+	var i GetTemplateRolloutBuildCountsRow
+	err := row.Scan(&i.Builds, &i.FailedBuilds)
+	return i, err
+}
+
+const getTemplateRolloutByID = `-- name: GetTemplateRolloutByID :one
+SELECT
+	id, template_id, template_version_id, created_by, created_at, updated_at, completed_at, status, status_reason, percent, group_id, min_builds, max_failure_rate, auto_promote
+FROM
+	template_rollouts
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetTemplateRolloutByID(ctx context.Context, id uuid.UUID) (TemplateRollout, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateRolloutByID, id)
+	var i TemplateRollout
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.TemplateVersionID,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CompletedAt,
+		&i.Status,
+		&i.StatusReason,
+		&i.Percent,
+		&i.GroupID,
+		&i.MinBuilds,
+		&i.MaxFailureRate,
+		&i.AutoPromote,
+	)
+	return i, err
+}
+
+const getTemplateRolloutsByTemplateID = `-- name: GetTemplateRolloutsByTemplateID :many
+SELECT
+	id, template_id, template_version_id, created_by, created_at, updated_at, completed_at, status, status_reason, percent, group_id, min_builds, max_failure_rate, auto_promote
+FROM
+	template_rollouts
+WHERE
+	template_id = $1
+ORDER BY
+	created_at DESC
+`
+
+func (q *sqlQuerier) GetTemplateRolloutsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]TemplateRollout, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateRolloutsByTemplateID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateRollout
+	for rows.Next() {
+		var i TemplateRollout
+		if err := rows.Scan(
+			&i.ID,
+			&i.TemplateID,
+			&i.TemplateVersionID,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CompletedAt,
+			&i.Status,
+			&i.StatusReason,
+			&i.Percent,
+			&i.GroupID,
+			&i.MinBuilds,
+			&i.MaxFailureRate,
+			&i.AutoPromote,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertTemplateRollout = `-- name: InsertTemplateRollout :one
+INSERT INTO template_rollouts (
+	id,
+	template_id,
+	template_version_id,
+	created_by,
+	created_at,
+	updated_at,
+	percent,
+	group_id,
+	min_builds,
+	max_failure_rate,
+	auto_promote
+)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING id, template_id, template_version_id, created_by, created_at, updated_at, completed_at, status, status_reason, percent, group_id, min_builds, max_failure_rate, auto_promote
+`
+
+type InsertTemplateRolloutParams struct {
+	ID                uuid.UUID     `db:"id" json:"id"`
+	TemplateID        uuid.UUID     `db:"template_id" json:"template_id"`
+	TemplateVersionID uuid.UUID     `db:"template_version_id" json:"template_version_id"`
+	CreatedBy         uuid.UUID     `db:"created_by" json:"created_by"`
+	CreatedAt         time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time     `db:"updated_at" json:"updated_at"`
+	Percent           int32         `db:"percent" json:"percent"`
+	GroupID           uuid.NullUUID `db:"group_id" json:"group_id"`
+	MinBuilds         int32         `db:"min_builds" json:"min_builds"`
+	MaxFailureRate    float64       `db:"max_failure_rate" json:"max_failure_rate"`
+	AutoPromote       bool          `db:"auto_promote" json:"auto_promote"`
+}
+
+func (q *sqlQuerier) InsertTemplateRollout(ctx context.Context, arg InsertTemplateRolloutParams) (TemplateRollout, error) {
+	row := q.db.QueryRowContext(ctx, insertTemplateRollout,
+		arg.ID,
+		arg.TemplateID,
+		arg.TemplateVersionID,
+		arg.CreatedBy,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.Percent,
+		arg.GroupID,
+		arg.MinBuilds,
+		arg.MaxFailureRate,
+		arg.AutoPromote,
+	)
+	var i TemplateRollout
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.TemplateVersionID,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CompletedAt,
+		&i.Status,
+		&i.StatusReason,
+		&i.Percent,
+		&i.GroupID,
+		&i.MinBuilds,
+		&i.MaxFailureRate,
+		&i.AutoPromote,
+	)
+	return i, err
+}
+
+const updateTemplateRolloutByID = `-- name: UpdateTemplateRolloutByID :one
+UPDATE
+	template_rollouts
+SET
+	percent = $2,
+	group_id = $3,
+	updated_at = $4
+WHERE
+	id = $1
+	AND status = 'in_progress'::template_rollout_status
+RETURNING id, template_id, template_version_id, created_by, created_at, updated_at, completed_at, status, status_reason, percent, group_id, min_builds, max_failure_rate, auto_promote
+`
+
+type UpdateTemplateRolloutByIDParams struct {
+	ID        uuid.UUID     `db:"id" json:"id"`
+	Percent   int32         `db:"percent" json:"percent"`
+	GroupID   uuid.NullUUID `db:"group_id" json:"group_id"`
+	UpdatedAt time.Time     `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateTemplateRolloutByID(ctx context.Context, arg UpdateTemplateRolloutByIDParams) (TemplateRollout, error) {
+	row := q.db.QueryRowContext(ctx, updateTemplateRolloutByID,
+		arg.ID,
+		arg.Percent,
+		arg.GroupID,
+		arg.UpdatedAt,
+	)
+	var i TemplateRollout
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.TemplateVersionID,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CompletedAt,
+		&i.Status,
+		&i.StatusReason,
+		&i.Percent,
+		&i.GroupID,
+		&i.MinBuilds,
+		&i.MaxFailureRate,
+		&i.AutoPromote,
+	)
+	return i, err
+}
+
+const updateTemplateRolloutStatusByID = `-- name: UpdateTemplateRolloutStatusByID :one
+UPDATE
+	template_rollouts
+SET
+	status = $1,
+	status_reason = $2,
+	updated_at = $3,
+	completed_at = $3
+WHERE
+	id = $4
+	AND status = 'in_progress'::template_rollout_status
+RETURNING id, template_id, template_version_id, created_by, created_at, updated_at, completed_at, status, status_reason, percent, group_id, min_builds, max_failure_rate, auto_promote
+`
+
+type UpdateTemplateRolloutStatusByIDParams struct {
+	Status       TemplateRolloutStatus `db:"status" json:"status"`
+	StatusReason string                `db:"status_reason" json:"status_reason"`
+	UpdatedAt    time.Time             `db:"updated_at" json:"updated_at"`
+	ID           uuid.UUID             `db:"id" json:"id"`
+}
+
+// Completes a rollout in progress. Completed rollouts are never updated again.
+func (q *sqlQuerier) UpdateTemplateRolloutStatusByID(ctx context.Context, arg UpdateTemplateRolloutStatusByIDParams) (TemplateRollout, error) {
+	row := q.db.QueryRowContext(ctx, updateTemplateRolloutStatusByID,
+		arg.Status,
+		arg.StatusReason,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	var i TemplateRollout
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.TemplateVersionID,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CompletedAt,
+		&i.Status,
+		&i.StatusReason,
+		&i.Percent,
+		&i.GroupID,
+		&i.MinBuilds,
+		&i.MaxFailureRate,
+		&i.AutoPromote,
+	)
+	return i, err
+}
+
+const getTemplateAverageBuildTime = `-- name: GetTemplateAverageBuildTime :one
+WITH build_times AS (
+SELECT
+	EXTRACT(EPOCH FROM (pj.completed_at - pj.started_at))::FLOAT AS exec_time_sec,
+	workspace_builds.transition
+FROM
+	workspace_builds
+JOIN template_versions ON
+	workspace_builds.template_version_id = template_versions.id
+JOIN provisioner_jobs pj ON
+	workspace_builds.job_id = pj.id
+WHERE
+	template_versions.template_id = $1 AND
+		(pj.completed_at IS NOT NULL) AND (pj.started_at IS NOT NULL) AND
+		(pj.started_at > $2) AND
+		(pj.canceled_at IS NULL) AND
+		((pj.error IS NULL) OR (pj.error = ''))
+ORDER BY
+	workspace_builds.created_at DESC
+)
+SELECT
+	-- Postgres offers no clear way to DRY this short of a function or other
+	-- complexities.
+	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'start')), -1)::FLOAT AS start_50,
+	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'stop')), -1)::FLOAT AS stop_50,
+	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'delete')), -1)::FLOAT AS delete_50,
+	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'start')), -1)::FLOAT AS start_95,
+	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'stop')), -1)::FLOAT AS stop_95,
+	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'delete')), -1)::FLOAT AS delete_95
+FROM build_times
+`
+
+type GetTemplateAverageBuildTimeParams struct {
+	TemplateID uuid.NullUUID `db:"template_id" json:"template_id"`
+	StartTime  sql.NullTime  `db:"start_time" json:"start_time"`
+}
+
+type GetTemplateAverageBuildTimeRow struct {
+	Start50  float64 `db:"start_50" json:"start_50"`
+	Stop50   float64 `db:"stop_50" json:"stop_50"`
+	Delete50 float64 `db:"delete_50" json:"delete_50"`
+	Start95  float64 `db:"start_95" json:"start_95"`
+	Stop95   float64 `db:"stop_95" json:"stop_95"`
 	Delete95 float64 `db:"delete_95" json:"delete_95"`
 }
 
@@ -8635,7 +10872,7 @@ func (q *sqlQuerier) GetTemplateAverageBuildTime(ctx context.Context, arg GetTem
 
 const getTemplateByID = `-- name: GetTemplateByID :one
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, created_by_avatar_url, created_by_username, organization_name, organization_display_name, organization_icon
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, session_recording_enabled, require_build_approval, build_approval_parameters, build_approval_group_id, build_approval_timeout, created_by_avatar_url, created_by_username, organization_name, organization_display_name, organization_icon
 FROM
 	template_with_names
 WHERE
@@ -8676,6 +10913,11 @@ func (q *sqlQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (Templat
 		&i.Deprecated,
 		&i.ActivityBump,
 		&i.MaxPortSharingLevel,
+		&i.SessionRecordingEnabled,
+		&i.RequireBuildApproval,
+		pq.Array(&i.BuildApprovalParameters),
+		&i.BuildApprovalGroupID,
+		&i.BuildApprovalTimeout,
 		&i.CreatedByAvatarURL,
 		&i.CreatedByUsername,
 		&i.OrganizationName,
@@ -8687,7 +10929,7 @@ func (q *sqlQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (Templat
 
 const getTemplateByOrganizationAndName = `-- name: GetTemplateByOrganizationAndName :one
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, created_by_avatar_url, created_by_username, organization_name, organization_display_name, organization_icon
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, session_recording_enabled, require_build_approval, build_approval_parameters, build_approval_group_id, build_approval_timeout, created_by_avatar_url, created_by_username, organization_name, organization_display_name, organization_icon
 FROM
 	template_with_names AS templates
 WHERE
@@ -8736,6 +10978,11 @@ func (q *sqlQuerier) GetTemplateByOrganizationAndName(ctx context.Context, arg G
 		&i.Deprecated,
 		&i.ActivityBump,
 		&i.MaxPortSharingLevel,
+		&i.SessionRecordingEnabled,
+		&i.RequireBuildApproval,
+		pq.Array(&i.BuildApprovalParameters),
+		&i.BuildApprovalGroupID,
+		&i.BuildApprovalTimeout,
 		&i.CreatedByAvatarURL,
 		&i.CreatedByUsername,
 		&i.OrganizationName,
@@ -8746,7 +10993,7 @@ func (q *sqlQuerier) GetTemplateByOrganizationAndName(ctx context.Context, arg G
 }
 
 const getTemplates = `-- name: GetTemplates :many
-SELECT id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, created_by_avatar_url, created_by_username, organization_name, organization_display_name, organization_icon FROM template_with_names AS templates
+SELECT id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, session_recording_enabled, require_build_approval, build_approval_parameters, build_approval_group_id, build_approval_timeout, created_by_avatar_url, created_by_username, organization_name, organization_display_name, organization_icon FROM template_with_names AS templates
 ORDER BY (name, id) ASC
 `
 
@@ -8788,6 +11035,11 @@ func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
 			&i.Deprecated,
 			&i.ActivityBump,
 			&i.MaxPortSharingLevel,
+			&i.SessionRecordingEnabled,
+			&i.RequireBuildApproval,
+			pq.Array(&i.BuildApprovalParameters),
+			&i.BuildApprovalGroupID,
+			&i.BuildApprovalTimeout,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 			&i.OrganizationName,
@@ -8809,7 +11061,7 @@ func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
 
 const getTemplatesWithFilter = `-- name: GetTemplatesWithFilter :many
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, created_by_avatar_url, created_by_username, organization_name, organization_display_name, organization_icon
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, session_recording_enabled, require_build_approval, build_approval_parameters, build_approval_group_id, build_approval_timeout, created_by_avatar_url, created_by_username, organization_name, organization_display_name, organization_icon
 FROM
 	template_with_names AS templates
 WHERE
@@ -8909,6 +11161,11 @@ func (q *sqlQuerier) GetTemplatesWithFilter(ctx context.Context, arg GetTemplate
 			&i.Deprecated,
 			&i.ActivityBump,
 			&i.MaxPortSharingLevel,
+			&i.SessionRecordingEnabled,
+			&i.RequireBuildApproval,
+			pq.Array(&i.BuildApprovalParameters),
+			&i.BuildApprovalGroupID,
+			&i.BuildApprovalTimeout,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 			&i.OrganizationName,
@@ -9085,7 +11342,12 @@ SET
 	display_name = $6,
 	allow_user_cancel_workspace_jobs = $7,
 	group_acl = $8,
-	max_port_sharing_level = $9
+	max_port_sharing_level = $9,
+	session_recording_enabled = $10,
+	require_build_approval = $11,
+	build_approval_parameters = $12,
+	build_approval_group_id = $13,
+	build_approval_timeout = $14
 WHERE
 	id = $1
 `
@@ -9100,6 +11362,11 @@ type UpdateTemplateMetaByIDParams struct {
 	AllowUserCancelWorkspaceJobs bool            `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
 	GroupACL                     TemplateACL     `db:"group_acl" json:"group_acl"`
 	MaxPortSharingLevel          AppSharingLevel `db:"max_port_sharing_level" json:"max_port_sharing_level"`
+	SessionRecordingEnabled      bool            `db:"session_recording_enabled" json:"session_recording_enabled"`
+	RequireBuildApproval         bool            `db:"require_build_approval" json:"require_build_approval"`
+	BuildApprovalParameters      []string        `db:"build_approval_parameters" json:"build_approval_parameters"`
+	BuildApprovalGroupID         uuid.NullUUID   `db:"build_approval_group_id" json:"build_approval_group_id"`
+	BuildApprovalTimeout         int64           `db:"build_approval_timeout" json:"build_approval_timeout"`
 }
 
 func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTemplateMetaByIDParams) error {
@@ -9113,6 +11380,11 @@ func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTempl
 		arg.AllowUserCancelWorkspaceJobs,
 		arg.GroupACL,
 		arg.MaxPortSharingLevel,
+		arg.SessionRecordingEnabled,
+		arg.RequireBuildApproval,
+		pq.Array(arg.BuildApprovalParameters),
+		arg.BuildApprovalGroupID,
+		arg.BuildApprovalTimeout,
 	)
 	return err
 }
@@ -11971,6 +14243,50 @@ func (q *sqlQuerier) GetWorkspaceAgentMetadata(ctx context.Context, arg GetWorks
 	return items, nil
 }
 
+const getWorkspaceAgentProcessGroups = `-- name: GetWorkspaceAgentProcessGroups :many
+SELECT
+	workspace_agent_id, name, cpu_usage_usec, cpu_cores, cpu_limit_cores, memory_bytes, memory_limit_bytes, oom_kills, updated_at
+FROM
+	workspace_agent_process_groups
+WHERE
+	workspace_agent_id = $1
+ORDER BY
+	name
+`
+
+func (q *sqlQuerier) GetWorkspaceAgentProcessGroups(ctx context.Context, workspaceAgentID uuid.UUID) ([]WorkspaceAgentProcessGroup, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentProcessGroups, workspaceAgentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentProcessGroup
+	for rows.Next() {
+		var i WorkspaceAgentProcessGroup
+		if err := rows.Scan(
+			&i.WorkspaceAgentID,
+			&i.Name,
+			&i.CPUUsageUsec,
+			&i.CPUCores,
+			&i.CPULimitCores,
+			&i.MemoryBytes,
+			&i.MemoryLimitBytes,
+			&i.OOMKills,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getWorkspaceAgentScriptTimingsByBuildID = `-- name: GetWorkspaceAgentScriptTimingsByBuildID :many
 SELECT
 	workspace_agent_script_timings.script_id, workspace_agent_script_timings.started_at, workspace_agent_script_timings.ended_at, workspace_agent_script_timings.exit_code, workspace_agent_script_timings.stage, workspace_agent_script_timings.status,
@@ -12686,23 +15002,86 @@ func (q *sqlQuerier) UpdateWorkspaceAgentStartupByID(ctx context.Context, arg Up
 	return err
 }
 
+const upsertWorkspaceAgentProcessGroups = `-- name: UpsertWorkspaceAgentProcessGroups :exec
+INSERT INTO
+	workspace_agent_process_groups (
+		workspace_agent_id,
+		name,
+		cpu_usage_usec,
+		cpu_cores,
+		cpu_limit_cores,
+		memory_bytes,
+		memory_limit_bytes,
+		oom_kills,
+		updated_at
+	)
+SELECT
+	$1 :: uuid AS workspace_agent_id,
+	unnest($2 :: text[]) AS name,
+	unnest($3 :: bigint[]) AS cpu_usage_usec,
+	unnest($4 :: double precision[]) AS cpu_cores,
+	unnest($5 :: double precision[]) AS cpu_limit_cores,
+	unnest($6 :: bigint[]) AS memory_bytes,
+	unnest($7 :: bigint[]) AS memory_limit_bytes,
+	unnest($8 :: bigint[]) AS oom_kills,
+	$9 :: timestamptz AS updated_at
+ON CONFLICT (workspace_agent_id, name) DO UPDATE SET
+	cpu_usage_usec = EXCLUDED.cpu_usage_usec,
+	cpu_cores = EXCLUDED.cpu_cores,
+	cpu_limit_cores = EXCLUDED.cpu_limit_cores,
+	memory_bytes = EXCLUDED.memory_bytes,
+	memory_limit_bytes = EXCLUDED.memory_limit_bytes,
+	oom_kills = EXCLUDED.oom_kills,
+	updated_at = EXCLUDED.updated_at
+`
+
+type UpsertWorkspaceAgentProcessGroupsParams struct {
+	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
+	Name             []string  `db:"name" json:"name"`
+	CPUUsageUsec     []int64   `db:"cpu_usage_usec" json:"cpu_usage_usec"`
+	CPUCores         []float64 `db:"cpu_cores" json:"cpu_cores"`
+	CPULimitCores    []float64 `db:"cpu_limit_cores" json:"cpu_limit_cores"`
+	MemoryBytes      []int64   `db:"memory_bytes" json:"memory_bytes"`
+	MemoryLimitBytes []int64   `db:"memory_limit_bytes" json:"memory_limit_bytes"`
+	OOMKills         []int64   `db:"oom_kills" json:"oom_kills"`
+	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// UpsertWorkspaceAgentProcessGroups stores the latest resource usage of the
+// process groups reported by an agent.
+func (q *sqlQuerier) UpsertWorkspaceAgentProcessGroups(ctx context.Context, arg UpsertWorkspaceAgentProcessGroupsParams) error {
+	_, err := q.db.ExecContext(ctx, upsertWorkspaceAgentProcessGroups,
+		arg.WorkspaceAgentID,
+		pq.Array(arg.Name),
+		pq.Array(arg.CPUUsageUsec),
+		pq.Array(arg.CPUCores),
+		pq.Array(arg.CPULimitCores),
+		pq.Array(arg.MemoryBytes),
+		pq.Array(arg.MemoryLimitBytes),
+		pq.Array(arg.OOMKills),
+		arg.UpdatedAt,
+	)
+	return err
+}
+
 const deleteOldWorkspaceAgentStats = `-- name: DeleteOldWorkspaceAgentStats :exec
 DELETE FROM
 	workspace_agent_stats
 WHERE
 	created_at < (
 		SELECT
-			COALESCE(
+			-- GREATEST ignores NULLs, so with no template usage stats only
+			-- the retention period applies.
+			GREATEST(
 				-- When generating initial template usage stats, all the
 				-- raw agent stats are needed, after that only ~30 mins
 				-- from last rollup is needed. Deployment stats seem to
 				-- use between 15 mins and 1 hour of data. We keep a
 				-- little bit more (1 day) just in case.
 				MAX(start_time) - '1 days'::interval,
-				-- Fall back to ~6 months ago if there are no template
-				-- usage stats so that we don't delete the data before
-				-- it's rolled up.
-				NOW() - '180 days'::interval
+				-- Stats older than the retention period are deleted even
+				-- if they have not been rolled up.
+				$1::timestamptz
 			)
 		FROM
 			template_usage_stats
@@ -12718,8 +15097,8 @@ WHERE
 	)
 `
 
-func (q *sqlQuerier) DeleteOldWorkspaceAgentStats(ctx context.Context) error {
-	_, err := q.db.ExecContext(ctx, deleteOldWorkspaceAgentStats)
+func (q *sqlQuerier) DeleteOldWorkspaceAgentStats(ctx context.Context, beforeTime time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteOldWorkspaceAgentStats, beforeTime)
 	return err
 }
 
@@ -13801,17 +16180,242 @@ func (q *sqlQuerier) InsertWorkspaceAppStats(ctx context.Context, arg InsertWork
 	return err
 }
 
-const getUserWorkspaceBuildParameters = `-- name: GetUserWorkspaceBuildParameters :many
-SELECT name, value
-FROM (
-    SELECT DISTINCT ON (tvp.name)
-        tvp.name,
-        wbp.value,
-        wb.created_at
-    FROM
-        workspace_build_parameters wbp
-    JOIN
-        workspace_builds wb ON wb.id = wbp.workspace_build_id
+const getPendingWorkspaceBuildApprovalsByTemplateID = `-- name: GetPendingWorkspaceBuildApprovalsByTemplateID :many
+SELECT
+	workspace_build_approvals.workspace_build_id, workspace_build_approvals.created_at, workspace_build_approvals.updated_at, workspace_build_approvals.expires_at, workspace_build_approvals.status, workspace_build_approvals.reviewer_id, workspace_build_approvals.reviewed_at, workspace_build_approvals.reason,
+	workspace_builds.workspace_id
+FROM
+	workspace_build_approvals
+INNER JOIN
+	workspace_builds ON workspace_builds.id = workspace_build_approvals.workspace_build_id
+INNER JOIN
+	workspaces ON workspaces.id = workspace_builds.workspace_id
+INNER JOIN
+	provisioner_jobs ON provisioner_jobs.id = workspace_builds.job_id
+WHERE
+	workspaces.template_id = $1
+	AND workspace_build_approvals.status = 'pending'
+	AND provisioner_jobs.completed_at IS NULL
+ORDER BY
+	workspace_build_approvals.created_at ASC
+`
+
+type GetPendingWorkspaceBuildApprovalsByTemplateIDRow struct {
+	WorkspaceBuildApproval WorkspaceBuildApproval `db:"workspace_build_approval" json:"workspace_build_approval"`
+	WorkspaceID            uuid.UUID              `db:"workspace_id" json:"workspace_id"`
+}
+
+// Builds that were canceled while waiting for approval are excluded.
+func (q *sqlQuerier) GetPendingWorkspaceBuildApprovalsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]GetPendingWorkspaceBuildApprovalsByTemplateIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPendingWorkspaceBuildApprovalsByTemplateID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPendingWorkspaceBuildApprovalsByTemplateIDRow
+	for rows.Next() {
+		var i GetPendingWorkspaceBuildApprovalsByTemplateIDRow
+		if err := rows.Scan(
+			&i.WorkspaceBuildApproval.WorkspaceBuildID,
+			&i.WorkspaceBuildApproval.CreatedAt,
+			&i.WorkspaceBuildApproval.UpdatedAt,
+			&i.WorkspaceBuildApproval.ExpiresAt,
+			&i.WorkspaceBuildApproval.Status,
+			&i.WorkspaceBuildApproval.ReviewerID,
+			&i.WorkspaceBuildApproval.ReviewedAt,
+			&i.WorkspaceBuildApproval.Reason,
+			&i.WorkspaceID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceBuildApprovalByBuildID = `-- name: GetWorkspaceBuildApprovalByBuildID :one
+SELECT
+	workspace_build_id, created_at, updated_at, expires_at, status, reviewer_id, reviewed_at, reason
+FROM
+	workspace_build_approvals
+WHERE
+	workspace_build_id = $1
+LIMIT
+	1
+`
+
+func (q *sqlQuerier) GetWorkspaceBuildApprovalByBuildID(ctx context.Context, workspaceBuildID uuid.UUID) (WorkspaceBuildApproval, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceBuildApprovalByBuildID, workspaceBuildID)
+	var i WorkspaceBuildApproval
+	err := row.Scan(
+		&i.WorkspaceBuildID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExpiresAt,
+		&i.Status,
+		&i.ReviewerID,
+		&i.ReviewedAt,
+		&i.Reason,
+	)
+	return i, err
+}
+
+const insertWorkspaceBuildApproval = `-- name: InsertWorkspaceBuildApproval :one
+INSERT INTO
+	workspace_build_approvals (
+		workspace_build_id,
+		created_at,
+		updated_at,
+		expires_at
+	)
+VALUES
+	($1, $2, $3, $4) RETURNING workspace_build_id, created_at, updated_at, expires_at, status, reviewer_id, reviewed_at, reason
+`
+
+type InsertWorkspaceBuildApprovalParams struct {
+	WorkspaceBuildID uuid.UUID    `db:"workspace_build_id" json:"workspace_build_id"`
+	CreatedAt        time.Time    `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time    `db:"updated_at" json:"updated_at"`
+	ExpiresAt        sql.NullTime `db:"expires_at" json:"expires_at"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceBuildApproval(ctx context.Context, arg InsertWorkspaceBuildApprovalParams) (WorkspaceBuildApproval, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceBuildApproval,
+		arg.WorkspaceBuildID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.ExpiresAt,
+	)
+	var i WorkspaceBuildApproval
+	err := row.Scan(
+		&i.WorkspaceBuildID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExpiresAt,
+		&i.Status,
+		&i.ReviewerID,
+		&i.ReviewedAt,
+		&i.Reason,
+	)
+	return i, err
+}
+
+const rejectExpiredWorkspaceBuildApprovals = `-- name: RejectExpiredWorkspaceBuildApprovals :many
+UPDATE
+	workspace_build_approvals
+SET
+	updated_at = $1,
+	status = 'rejected',
+	reviewed_at = $1,
+	reason = $2
+WHERE
+	status = 'pending'
+	AND expires_at IS NOT NULL
+	AND expires_at <= $1
+RETURNING workspace_build_id, created_at, updated_at, expires_at, status, reviewer_id, reviewed_at, reason
+`
+
+type RejectExpiredWorkspaceBuildApprovalsParams struct {
+	Now    time.Time `db:"now" json:"now"`
+	Reason string    `db:"reason" json:"reason"`
+}
+
+func (q *sqlQuerier) RejectExpiredWorkspaceBuildApprovals(ctx context.Context, arg RejectExpiredWorkspaceBuildApprovalsParams) ([]WorkspaceBuildApproval, error) {
+	rows, err := q.db.QueryContext(ctx, rejectExpiredWorkspaceBuildApprovals, arg.Now, arg.Reason)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceBuildApproval
+	for rows.Next() {
+		var i WorkspaceBuildApproval
+		if err := rows.Scan(
+			&i.WorkspaceBuildID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ExpiresAt,
+			&i.Status,
+			&i.ReviewerID,
+			&i.ReviewedAt,
+			&i.Reason,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateWorkspaceBuildApprovalByBuildID = `-- name: UpdateWorkspaceBuildApprovalByBuildID :one
+UPDATE
+	workspace_build_approvals
+SET
+	updated_at = $1,
+	status = $2,
+	reviewer_id = $3,
+	reviewed_at = $1,
+	reason = $4
+WHERE
+	workspace_build_id = $5
+	AND status = 'pending'
+RETURNING workspace_build_id, created_at, updated_at, expires_at, status, reviewer_id, reviewed_at, reason
+`
+
+type UpdateWorkspaceBuildApprovalByBuildIDParams struct {
+	UpdatedAt        time.Time                    `db:"updated_at" json:"updated_at"`
+	Status           WorkspaceBuildApprovalStatus `db:"status" json:"status"`
+	ReviewerID       uuid.NullUUID                `db:"reviewer_id" json:"reviewer_id"`
+	Reason           string                       `db:"reason" json:"reason"`
+	WorkspaceBuildID uuid.UUID                    `db:"workspace_build_id" json:"workspace_build_id"`
+}
+
+// Only pending approvals can be reviewed, so reviewing an approval twice
+// returns no rows.
+func (q *sqlQuerier) UpdateWorkspaceBuildApprovalByBuildID(ctx context.Context, arg UpdateWorkspaceBuildApprovalByBuildIDParams) (WorkspaceBuildApproval, error) {
+	row := q.db.QueryRowContext(ctx, updateWorkspaceBuildApprovalByBuildID,
+		arg.UpdatedAt,
+		arg.Status,
+		arg.ReviewerID,
+		arg.Reason,
+		arg.WorkspaceBuildID,
+	)
+	var i WorkspaceBuildApproval
+	err := row.Scan(
+		&i.WorkspaceBuildID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExpiresAt,
+		&i.Status,
+		&i.ReviewerID,
+		&i.ReviewedAt,
+		&i.Reason,
+	)
+	return i, err
+}
+
+const getUserWorkspaceBuildParameters = `-- name: GetUserWorkspaceBuildParameters :many
+SELECT name, value
+FROM (
+    SELECT DISTINCT ON (tvp.name)
+        tvp.name,
+        wbp.value,
+        wb.created_at
+    FROM
+        workspace_build_parameters wbp
+    JOIN
+        workspace_builds wb ON wb.id = wbp.workspace_build_id
     JOIN
         workspaces w ON w.id = wb.workspace_id
     JOIN
@@ -13915,6 +16519,53 @@ func (q *sqlQuerier) InsertWorkspaceBuildParameters(ctx context.Context, arg Ins
 	return err
 }
 
+const clearOldWorkspaceBuildProvisionerStates = `-- name: ClearOldWorkspaceBuildProvisionerStates :execrows
+WITH
+	latest_builds AS (
+		SELECT DISTINCT ON (workspace_id)
+			id
+		FROM
+			workspace_builds
+		ORDER BY
+			workspace_id, build_number DESC
+	)
+UPDATE
+	workspace_builds
+SET
+	provisioner_state = NULL
+WHERE
+	id IN (
+		SELECT
+			id
+		FROM
+			workspace_builds
+		WHERE
+			created_at < $1 :: timestamptz
+			AND provisioner_state IS NOT NULL
+			AND id NOT IN (SELECT id FROM latest_builds)
+		ORDER BY
+			created_at ASC
+		LIMIT
+			$2 :: int
+	)
+`
+
+type ClearOldWorkspaceBuildProvisionerStatesParams struct {
+	BeforeTime time.Time `db:"before_time" json:"before_time"`
+	LimitCount int32     `db:"limit_count" json:"limit_count"`
+}
+
+// Clear the Terraform state of workspace builds created before @before_time
+// in batches of @limit_count rows. Only the state of the latest build of each
+// workspace is used by subsequent builds, so it is always kept.
+func (q *sqlQuerier) ClearOldWorkspaceBuildProvisionerStates(ctx context.Context, arg ClearOldWorkspaceBuildProvisionerStatesParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, clearOldWorkspaceBuildProvisionerStates, arg.BeforeTime, arg.LimitCount)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getActiveWorkspaceBuildsByTemplateID = `-- name: GetActiveWorkspaceBuildsByTemplateID :many
 SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.initiator_by_avatar_url, wb.initiator_by_username
 FROM (
@@ -14759,7 +17410,7 @@ func (q *sqlQuerier) InsertWorkspaceModule(ctx context.Context, arg InsertWorksp
 
 const getWorkspaceResourceByID = `-- name: GetWorkspaceResourceByID :one
 SELECT
-	id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, module_path
+	id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, module_path, hourly_cost
 FROM
 	workspace_resources
 WHERE
@@ -14781,6 +17432,7 @@ func (q *sqlQuerier) GetWorkspaceResourceByID(ctx context.Context, id uuid.UUID)
 		&i.InstanceType,
 		&i.DailyCost,
 		&i.ModulePath,
+		&i.HourlyCost,
 	)
 	return i, err
 }
@@ -14860,7 +17512,7 @@ func (q *sqlQuerier) GetWorkspaceResourceMetadataCreatedAfter(ctx context.Contex
 
 const getWorkspaceResourcesByJobID = `-- name: GetWorkspaceResourcesByJobID :many
 SELECT
-	id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, module_path
+	id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, module_path, hourly_cost
 FROM
 	workspace_resources
 WHERE
@@ -14888,6 +17540,7 @@ func (q *sqlQuerier) GetWorkspaceResourcesByJobID(ctx context.Context, jobID uui
 			&i.InstanceType,
 			&i.DailyCost,
 			&i.ModulePath,
+			&i.HourlyCost,
 		); err != nil {
 			return nil, err
 		}
@@ -14904,7 +17557,7 @@ func (q *sqlQuerier) GetWorkspaceResourcesByJobID(ctx context.Context, jobID uui
 
 const getWorkspaceResourcesByJobIDs = `-- name: GetWorkspaceResourcesByJobIDs :many
 SELECT
-	id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, module_path
+	id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, module_path, hourly_cost
 FROM
 	workspace_resources
 WHERE
@@ -14932,6 +17585,7 @@ func (q *sqlQuerier) GetWorkspaceResourcesByJobIDs(ctx context.Context, ids []uu
 			&i.InstanceType,
 			&i.DailyCost,
 			&i.ModulePath,
+			&i.HourlyCost,
 		); err != nil {
 			return nil, err
 		}
@@ -14947,7 +17601,7 @@ func (q *sqlQuerier) GetWorkspaceResourcesByJobIDs(ctx context.Context, ids []uu
 }
 
 const getWorkspaceResourcesCreatedAfter = `-- name: GetWorkspaceResourcesCreatedAfter :many
-SELECT id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, module_path FROM workspace_resources WHERE created_at > $1
+SELECT id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, module_path, hourly_cost FROM workspace_resources WHERE created_at > $1
 `
 
 func (q *sqlQuerier) GetWorkspaceResourcesCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceResource, error) {
@@ -14971,6 +17625,7 @@ func (q *sqlQuerier) GetWorkspaceResourcesCreatedAfter(ctx context.Context, crea
 			&i.InstanceType,
 			&i.DailyCost,
 			&i.ModulePath,
+			&i.HourlyCost,
 		); err != nil {
 			return nil, err
 		}
@@ -14987,9 +17642,9 @@ func (q *sqlQuerier) GetWorkspaceResourcesCreatedAfter(ctx context.Context, crea
 
 const insertWorkspaceResource = `-- name: InsertWorkspaceResource :one
 INSERT INTO
-	workspace_resources (id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, module_path)
+	workspace_resources (id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, module_path, hourly_cost)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, module_path
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, module_path, hourly_cost
 `
 
 type InsertWorkspaceResourceParams struct {
@@ -15004,6 +17659,7 @@ type InsertWorkspaceResourceParams struct {
 	InstanceType sql.NullString      `db:"instance_type" json:"instance_type"`
 	DailyCost    int32               `db:"daily_cost" json:"daily_cost"`
 	ModulePath   sql.NullString      `db:"module_path" json:"module_path"`
+	HourlyCost   float64             `db:"hourly_cost" json:"hourly_cost"`
 }
 
 func (q *sqlQuerier) InsertWorkspaceResource(ctx context.Context, arg InsertWorkspaceResourceParams) (WorkspaceResource, error) {
@@ -15019,6 +17675,7 @@ func (q *sqlQuerier) InsertWorkspaceResource(ctx context.Context, arg InsertWork
 		arg.InstanceType,
 		arg.DailyCost,
 		arg.ModulePath,
+		arg.HourlyCost,
 	)
 	var i WorkspaceResource
 	err := row.Scan(
@@ -15033,6 +17690,7 @@ func (q *sqlQuerier) InsertWorkspaceResource(ctx context.Context, arg InsertWork
 		&i.InstanceType,
 		&i.DailyCost,
 		&i.ModulePath,
+		&i.HourlyCost,
 	)
 	return i, err
 }
@@ -15555,7 +18213,7 @@ LEFT JOIN LATERAL (
 ) latest_build ON TRUE
 LEFT JOIN LATERAL (
 	SELECT
-		id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level
+		id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, session_recording_enabled, require_build_approval, build_approval_parameters, build_approval_group_id, build_approval_timeout
 	FROM
 		templates
 	WHERE
@@ -16757,3 +19415,593 @@ func (q *sqlQuerier) InsertWorkspaceAgentScripts(ctx context.Context, arg Insert
 	}
 	return items, nil
 }
+
+const deleteWorkspaceScheduledActionByID = `-- name: DeleteWorkspaceScheduledActionByID :exec
+DELETE FROM
+	workspace_scheduled_actions
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) DeleteWorkspaceScheduledActionByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspaceScheduledActionByID, id)
+	return err
+}
+
+const getWorkspaceScheduledActionByID = `-- name: GetWorkspaceScheduledActionByID :one
+SELECT
+	id, workspace_id, created_at, updated_at, action, schedule, next_run_at, last_run_at
+FROM
+	workspace_scheduled_actions
+WHERE
+	id = $1
+LIMIT
+	1
+`
+
+func (q *sqlQuerier) GetWorkspaceScheduledActionByID(ctx context.Context, id uuid.UUID) (WorkspaceScheduledAction, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceScheduledActionByID, id)
+	var i WorkspaceScheduledAction
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Action,
+		&i.Schedule,
+		&i.NextRunAt,
+		&i.LastRunAt,
+	)
+	return i, err
+}
+
+const getWorkspaceScheduledActionsByWorkspaceID = `-- name: GetWorkspaceScheduledActionsByWorkspaceID :many
+SELECT
+	id, workspace_id, created_at, updated_at, action, schedule, next_run_at, last_run_at
+FROM
+	workspace_scheduled_actions
+WHERE
+	workspace_id = $1
+ORDER BY
+	created_at ASC
+`
+
+func (q *sqlQuerier) GetWorkspaceScheduledActionsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceScheduledAction, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceScheduledActionsByWorkspaceID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceScheduledAction
+	for rows.Next() {
+		var i WorkspaceScheduledAction
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Action,
+			&i.Schedule,
+			&i.NextRunAt,
+			&i.LastRunAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceScheduledActionsDue = `-- name: GetWorkspaceScheduledActionsDue :many
+SELECT
+	workspace_scheduled_actions.id, workspace_scheduled_actions.workspace_id, workspace_scheduled_actions.created_at, workspace_scheduled_actions.updated_at, workspace_scheduled_actions.action, workspace_scheduled_actions.schedule, workspace_scheduled_actions.next_run_at, workspace_scheduled_actions.last_run_at
+FROM
+	workspace_scheduled_actions
+INNER JOIN
+	workspaces ON workspaces.id = workspace_scheduled_actions.workspace_id
+WHERE
+	workspace_scheduled_actions.next_run_at <= $1 :: timestamptz
+	AND workspaces.deleted = false
+ORDER BY
+	workspace_scheduled_actions.next_run_at ASC
+`
+
+// Returns the scheduled actions of non-deleted workspaces that are due to run
+// at the given time, oldest first.
+func (q *sqlQuerier) GetWorkspaceScheduledActionsDue(ctx context.Context, now time.Time) ([]WorkspaceScheduledAction, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceScheduledActionsDue, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceScheduledAction
+	for rows.Next() {
+		var i WorkspaceScheduledAction
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Action,
+			&i.Schedule,
+			&i.NextRunAt,
+			&i.LastRunAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspaceScheduledAction = `-- name: InsertWorkspaceScheduledAction :one
+INSERT INTO
+	workspace_scheduled_actions (
+		id,
+		workspace_id,
+		created_at,
+		updated_at,
+		action,
+		schedule,
+		next_run_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7) RETURNING id, workspace_id, created_at, updated_at, action, schedule, next_run_at, last_run_at
+`
+
+type InsertWorkspaceScheduledActionParams struct {
+	ID          uuid.UUID                    `db:"id" json:"id"`
+	WorkspaceID uuid.UUID                    `db:"workspace_id" json:"workspace_id"`
+	CreatedAt   time.Time                    `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time                    `db:"updated_at" json:"updated_at"`
+	Action      WorkspaceScheduledActionType `db:"action" json:"action"`
+	Schedule    string                       `db:"schedule" json:"schedule"`
+	NextRunAt   time.Time                    `db:"next_run_at" json:"next_run_at"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceScheduledAction(ctx context.Context, arg InsertWorkspaceScheduledActionParams) (WorkspaceScheduledAction, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceScheduledAction,
+		arg.ID,
+		arg.WorkspaceID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.Action,
+		arg.Schedule,
+		arg.NextRunAt,
+	)
+	var i WorkspaceScheduledAction
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Action,
+		&i.Schedule,
+		&i.NextRunAt,
+		&i.LastRunAt,
+	)
+	return i, err
+}
+
+const updateWorkspaceScheduledActionRunByID = `-- name: UpdateWorkspaceScheduledActionRunByID :exec
+UPDATE
+	workspace_scheduled_actions
+SET
+	updated_at = $2,
+	next_run_at = $3,
+	last_run_at = $4
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceScheduledActionRunByIDParams struct {
+	ID        uuid.UUID    `db:"id" json:"id"`
+	UpdatedAt time.Time    `db:"updated_at" json:"updated_at"`
+	NextRunAt time.Time    `db:"next_run_at" json:"next_run_at"`
+	LastRunAt sql.NullTime `db:"last_run_at" json:"last_run_at"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceScheduledActionRunByID(ctx context.Context, arg UpdateWorkspaceScheduledActionRunByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceScheduledActionRunByID,
+		arg.ID,
+		arg.UpdatedAt,
+		arg.NextRunAt,
+		arg.LastRunAt,
+	)
+	return err
+}
+
+const getWorkspaceSessionRecordingByID = `-- name: GetWorkspaceSessionRecordingByID :one
+SELECT
+	id, created_at, updated_at, ended_at, workspace_id, agent_id, user_id, type, size
+FROM
+	workspace_session_recordings
+WHERE
+	id = $1
+LIMIT
+	1
+`
+
+func (q *sqlQuerier) GetWorkspaceSessionRecordingByID(ctx context.Context, id uuid.UUID) (WorkspaceSessionRecording, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceSessionRecordingByID, id)
+	var i WorkspaceSessionRecording
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EndedAt,
+		&i.WorkspaceID,
+		&i.AgentID,
+		&i.UserID,
+		&i.Type,
+		&i.Size,
+	)
+	return i, err
+}
+
+const getWorkspaceSessionRecordingChunksByRecordingID = `-- name: GetWorkspaceSessionRecordingChunksByRecordingID :many
+SELECT
+	recording_id, sequence, data
+FROM
+	workspace_session_recording_chunks
+WHERE
+	recording_id = $1
+	AND sequence > $2
+ORDER BY
+	sequence ASC
+LIMIT
+	$3 :: int
+`
+
+type GetWorkspaceSessionRecordingChunksByRecordingIDParams struct {
+	RecordingID   uuid.UUID `db:"recording_id" json:"recording_id"`
+	AfterSequence int32     `db:"after_sequence" json:"after_sequence"`
+	LimitOpt      int32     `db:"limit_opt" json:"limit_opt"`
+}
+
+// Recordings can be large, so their chunks are fetched in pages. Each page
+// starts after the sequence of the last chunk of the previous page.
+func (q *sqlQuerier) GetWorkspaceSessionRecordingChunksByRecordingID(ctx context.Context, arg GetWorkspaceSessionRecordingChunksByRecordingIDParams) ([]WorkspaceSessionRecordingChunk, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceSessionRecordingChunksByRecordingID, arg.RecordingID, arg.AfterSequence, arg.LimitOpt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceSessionRecordingChunk
+	for rows.Next() {
+		var i WorkspaceSessionRecordingChunk
+		if err := rows.Scan(&i.RecordingID, &i.Sequence, &i.Data); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceSessionRecordingsByWorkspaceID = `-- name: GetWorkspaceSessionRecordingsByWorkspaceID :many
+SELECT
+	id, created_at, updated_at, ended_at, workspace_id, agent_id, user_id, type, size
+FROM
+	workspace_session_recordings
+WHERE
+	workspace_id = $1
+ORDER BY
+	created_at DESC
+`
+
+func (q *sqlQuerier) GetWorkspaceSessionRecordingsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceSessionRecording, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceSessionRecordingsByWorkspaceID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceSessionRecording
+	for rows.Next() {
+		var i WorkspaceSessionRecording
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.EndedAt,
+			&i.WorkspaceID,
+			&i.AgentID,
+			&i.UserID,
+			&i.Type,
+			&i.Size,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspaceSessionRecording = `-- name: InsertWorkspaceSessionRecording :one
+INSERT INTO
+	workspace_session_recordings (
+		id,
+		created_at,
+		updated_at,
+		workspace_id,
+		agent_id,
+		user_id,
+		type
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7) RETURNING id, created_at, updated_at, ended_at, workspace_id, agent_id, user_id, type, size
+`
+
+type InsertWorkspaceSessionRecordingParams struct {
+	ID          uuid.UUID                     `db:"id" json:"id"`
+	CreatedAt   time.Time                     `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time                     `db:"updated_at" json:"updated_at"`
+	WorkspaceID uuid.UUID                     `db:"workspace_id" json:"workspace_id"`
+	AgentID     uuid.UUID                     `db:"agent_id" json:"agent_id"`
+	UserID      uuid.UUID                     `db:"user_id" json:"user_id"`
+	Type        WorkspaceSessionRecordingType `db:"type" json:"type"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceSessionRecording(ctx context.Context, arg InsertWorkspaceSessionRecordingParams) (WorkspaceSessionRecording, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceSessionRecording,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.WorkspaceID,
+		arg.AgentID,
+		arg.UserID,
+		arg.Type,
+	)
+	var i WorkspaceSessionRecording
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EndedAt,
+		&i.WorkspaceID,
+		&i.AgentID,
+		&i.UserID,
+		&i.Type,
+		&i.Size,
+	)
+	return i, err
+}
+
+const insertWorkspaceSessionRecordingChunk = `-- name: InsertWorkspaceSessionRecordingChunk :execrows
+INSERT INTO
+	workspace_session_recording_chunks (
+		recording_id,
+		sequence,
+		data
+	)
+VALUES
+	($1, $2, $3)
+ON CONFLICT (recording_id, sequence) DO NOTHING
+`
+
+type InsertWorkspaceSessionRecordingChunkParams struct {
+	RecordingID uuid.UUID `db:"recording_id" json:"recording_id"`
+	Sequence    int32     `db:"sequence" json:"sequence"`
+	Data        []byte    `db:"data" json:"data"`
+}
+
+// Agents retry chunks whose upload failed, so inserting a chunk that already
+// exists is a no-op. It returns the number of chunks inserted.
+func (q *sqlQuerier) InsertWorkspaceSessionRecordingChunk(ctx context.Context, arg InsertWorkspaceSessionRecordingChunkParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, insertWorkspaceSessionRecordingChunk, arg.RecordingID, arg.Sequence, arg.Data)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateWorkspaceSessionRecordingByID = `-- name: UpdateWorkspaceSessionRecordingByID :exec
+UPDATE
+	workspace_session_recordings
+SET
+	updated_at = $1,
+	size = size + $2 :: bigint,
+	ended_at = $3
+WHERE
+	id = $4
+`
+
+type UpdateWorkspaceSessionRecordingByIDParams struct {
+	UpdatedAt time.Time    `db:"updated_at" json:"updated_at"`
+	SizeDelta int64        `db:"size_delta" json:"size_delta"`
+	EndedAt   sql.NullTime `db:"ended_at" json:"ended_at"`
+	ID        uuid.UUID    `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceSessionRecordingByID(ctx context.Context, arg UpdateWorkspaceSessionRecordingByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceSessionRecordingByID,
+		arg.UpdatedAt,
+		arg.SizeDelta,
+		arg.EndedAt,
+		arg.ID,
+	)
+	return err
+}
+
+const getWorkspaceSnapshotByID = `-- name: GetWorkspaceSnapshotByID :one
+SELECT
+	id, created_at, updated_at, organization_id, owner_id, template_id, workspace_id, build_id, name, artifacts
+FROM
+	workspace_snapshots
+WHERE
+	id = $1
+LIMIT
+	1
+`
+
+func (q *sqlQuerier) GetWorkspaceSnapshotByID(ctx context.Context, id uuid.UUID) (WorkspaceSnapshot, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceSnapshotByID, id)
+	var i WorkspaceSnapshot
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OrganizationID,
+		&i.OwnerID,
+		&i.TemplateID,
+		&i.WorkspaceID,
+		&i.BuildID,
+		&i.Name,
+		&i.Artifacts,
+	)
+	return i, err
+}
+
+const getWorkspaceSnapshotsByOwnerAndTemplate = `-- name: GetWorkspaceSnapshotsByOwnerAndTemplate :many
+SELECT
+	id, created_at, updated_at, organization_id, owner_id, template_id, workspace_id, build_id, name, artifacts
+FROM
+	workspace_snapshots
+WHERE
+	owner_id = $1
+	AND template_id = $2
+ORDER BY
+	created_at DESC
+`
+
+type GetWorkspaceSnapshotsByOwnerAndTemplateParams struct {
+	OwnerID    uuid.UUID `db:"owner_id" json:"owner_id"`
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+}
+
+// Snapshots can be restored into any workspace of the same owner and template,
+// including snapshots taken from workspaces that have since been deleted.
+func (q *sqlQuerier) GetWorkspaceSnapshotsByOwnerAndTemplate(ctx context.Context, arg GetWorkspaceSnapshotsByOwnerAndTemplateParams) ([]WorkspaceSnapshot, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceSnapshotsByOwnerAndTemplate, arg.OwnerID, arg.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceSnapshot
+	for rows.Next() {
+		var i WorkspaceSnapshot
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.OrganizationID,
+			&i.OwnerID,
+			&i.TemplateID,
+			&i.WorkspaceID,
+			&i.BuildID,
+			&i.Name,
+			&i.Artifacts,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspaceSnapshot = `-- name: InsertWorkspaceSnapshot :one
+INSERT INTO
+	workspace_snapshots (
+		id,
+		created_at,
+		updated_at,
+		organization_id,
+		owner_id,
+		template_id,
+		workspace_id,
+		build_id,
+		name
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, created_at, updated_at, organization_id, owner_id, template_id, workspace_id, build_id, name, artifacts
+`
+
+type InsertWorkspaceSnapshotParams struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	OwnerID        uuid.UUID `db:"owner_id" json:"owner_id"`
+	TemplateID     uuid.UUID `db:"template_id" json:"template_id"`
+	WorkspaceID    uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	BuildID        uuid.UUID `db:"build_id" json:"build_id"`
+	Name           string    `db:"name" json:"name"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceSnapshot(ctx context.Context, arg InsertWorkspaceSnapshotParams) (WorkspaceSnapshot, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceSnapshot,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.OrganizationID,
+		arg.OwnerID,
+		arg.TemplateID,
+		arg.WorkspaceID,
+		arg.BuildID,
+		arg.Name,
+	)
+	var i WorkspaceSnapshot
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OrganizationID,
+		&i.OwnerID,
+		&i.TemplateID,
+		&i.WorkspaceID,
+		&i.BuildID,
+		&i.Name,
+		&i.Artifacts,
+	)
+	return i, err
+}
+
+const updateWorkspaceSnapshotArtifactsByID = `-- name: UpdateWorkspaceSnapshotArtifactsByID :exec
+UPDATE
+	workspace_snapshots
+SET
+	artifacts = $2,
+	updated_at = $3
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceSnapshotArtifactsByIDParams struct {
+	ID        uuid.UUID       `db:"id" json:"id"`
+	Artifacts json.RawMessage `db:"artifacts" json:"artifacts"`
+	UpdatedAt time.Time       `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceSnapshotArtifactsByID(ctx context.Context, arg UpdateWorkspaceSnapshotArtifactsByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceSnapshotArtifactsByID, arg.ID, arg.Artifacts, arg.UpdatedAt)
+	return err
+}
@@ -1,3 +1,22 @@
+-- Delete audit logs older than @before_time in batches of @limit_count rows
+-- to keep the load on the database low.
+-- name: DeleteOldAuditLogs :execrows
+DELETE FROM
+	audit_logs
+WHERE
+	id IN (
+		SELECT
+			id
+		FROM
+			audit_logs
+		WHERE
+			"time" < @before_time :: timestamptz
+		ORDER BY
+			"time" ASC
+		LIMIT
+			@limit_count :: int
+	);
+
 -- GetAuditLogsBefore retrieves `row_limit` number of audit logs before the provided
 -- ID.
 -- name: GetAuditLogsOffset :many
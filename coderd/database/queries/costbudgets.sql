@@ -0,0 +1,103 @@
+-- name: UpsertCostBudget :one
+INSERT INTO cost_budgets (
+	group_id,
+	organization_id,
+	created_at,
+	updated_at,
+	monthly_limit,
+	notify_threshold_percent,
+	stop_threshold_percent
+)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT
+	(group_id)
+DO UPDATE SET
+	updated_at = EXCLUDED.updated_at,
+	monthly_limit = EXCLUDED.monthly_limit,
+	notify_threshold_percent = EXCLUDED.notify_threshold_percent,
+	stop_threshold_percent = EXCLUDED.stop_threshold_percent
+RETURNING *;
+
+-- name: GetCostBudgetByGroupID :one
+SELECT
+	*
+FROM
+	cost_budgets
+WHERE
+	group_id = $1;
+
+-- name: DeleteCostBudgetByGroupID :exec
+DELETE FROM
+	cost_budgets
+WHERE
+	group_id = $1;
+
+-- name: GetCostBudgetsWithCost :many
+-- Returns every budget with the cost of the workspaces owned by the members of
+-- its group in its organization since the given time.
+SELECT
+	sqlc.embed(cost_budgets),
+	groups.name AS group_name,
+	COALESCE((
+		SELECT
+			SUM(wc.cost)
+		FROM
+			workspace_costs AS wc
+		JOIN
+			group_members_expanded AS gme
+		ON
+			gme.user_id = wc.owner_id
+			AND gme.group_id = cost_budgets.group_id
+		WHERE
+			wc.organization_id = cost_budgets.organization_id
+			AND wc.bucket_start >= @since :: timestamptz
+	), 0)::float AS cost
+FROM
+	cost_budgets
+JOIN
+	groups
+ON
+	groups.id = cost_budgets.group_id;
+
+-- name: GetCostBudgetMemberIDs :many
+-- Returns the active members of the group of a budget.
+SELECT
+	user_id
+FROM
+	group_members_expanded
+WHERE
+	group_id = $1
+	AND user_status = 'active';
+
+-- name: GetCostBudgetWorkspaceIDs :many
+-- Returns the non-deleted workspaces owned by the members of the group of a
+-- budget in the organization of the group.
+SELECT
+	workspaces.id
+FROM
+	workspaces
+JOIN
+	group_members_expanded AS gme
+ON
+	gme.user_id = workspaces.owner_id
+	AND gme.organization_id = workspaces.organization_id
+WHERE
+	gme.group_id = $1
+	AND workspaces.deleted = false;
+
+-- name: UpdateCostBudgetNotifiedAtByGroupID :exec
+UPDATE
+	cost_budgets
+SET
+	notified_at = $2
+WHERE
+	group_id = $1;
+
+-- name: UpdateCostBudgetStoppedAtByGroupID :exec
+UPDATE
+	cost_budgets
+SET
+	stopped_at = $2
+WHERE
+	group_id = $1;
@@ -48,3 +48,8 @@ INSERT INTO crypto_keys (
 UPDATE crypto_keys
 SET deletes_at = $3
 WHERE feature = $1 AND sequence = $2 RETURNING *;
+
+-- name: UpdateCryptoKeySecret :one
+UPDATE crypto_keys
+SET secret = $3, secret_key_id = $4
+WHERE feature = $1 AND sequence = $2 RETURNING *;
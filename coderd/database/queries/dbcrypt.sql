@@ -14,6 +14,6 @@ AND
 
 -- name: InsertDBCryptKey :exec
 INSERT INTO dbcrypt_keys
-	(number, active_key_digest, created_at, test)
-VALUES (@number::int, @active_key_digest::text, CURRENT_TIMESTAMP, @test::text);
+	(number, active_key_digest, created_at, test, key_provider, wrapped_key)
+VALUES (@number::int, @active_key_digest::text, CURRENT_TIMESTAMP, @test::text, sqlc.narg('key_provider')::text, sqlc.narg('wrapped_key')::text);
 
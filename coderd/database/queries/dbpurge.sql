@@ -0,0 +1,65 @@
+-- Counts the rows which would be removed by purging with the given cutoffs.
+-- Each count mirrors the corresponding DeleteOld* query, ignoring any batch
+-- limits.
+-- name: GetPurgeableRowCounts :one
+WITH
+	latest_builds AS (
+		SELECT DISTINCT ON (workspace_id)
+			id, job_id
+		FROM
+			workspace_builds
+		ORDER BY
+			workspace_id, build_number DESC
+	),
+	old_agents AS (
+		SELECT
+			wa.id
+		FROM
+			workspace_agents AS wa
+		JOIN
+			workspace_resources AS wr
+		ON
+			wa.resource_id = wr.id
+		JOIN
+			workspace_builds AS wb
+		ON
+			wb.job_id = wr.job_id
+		WHERE
+			wb.id NOT IN (SELECT id FROM latest_builds)
+		AND CASE
+			WHEN wa.last_connected_at IS NOT NULL THEN
+				wa.last_connected_at < @workspace_agent_logs_before :: timestamptz
+			ELSE wa.created_at < @workspace_agent_logs_before :: timestamptz
+		END
+	)
+SELECT
+	(
+		SELECT COUNT(*) FROM audit_logs
+		WHERE "time" < @audit_logs_before :: timestamptz
+	) :: bigint AS audit_logs,
+	(
+		SELECT COUNT(*) FROM workspace_agent_logs
+		WHERE agent_id IN (SELECT id FROM old_agents)
+	) :: bigint AS workspace_agent_logs,
+	(
+		SELECT COUNT(*) FROM provisioner_job_logs
+		WHERE created_at < @provisioner_job_logs_before :: timestamptz
+		AND job_id NOT IN (SELECT job_id FROM latest_builds)
+	) :: bigint AS provisioner_job_logs,
+	(
+		SELECT COUNT(*) FROM workspace_builds
+		WHERE created_at < @workspace_build_states_before :: timestamptz
+		AND provisioner_state IS NOT NULL
+		AND id NOT IN (SELECT id FROM latest_builds)
+	) :: bigint AS workspace_build_states,
+	(
+		SELECT COUNT(*) FROM notification_messages
+		WHERE updated_at < @notification_messages_before :: timestamptz
+	) :: bigint AS notification_messages,
+	(
+		SELECT COUNT(*) FROM workspace_agent_stats
+		WHERE created_at < (
+			SELECT GREATEST(MAX(start_time) - '1 days'::interval, @workspace_agent_stats_before :: timestamptz)
+			FROM template_usage_stats
+		)
+	) :: bigint AS workspace_agent_stats;
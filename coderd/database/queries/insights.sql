@@ -888,3 +888,225 @@ FROM ranked_status_change_per_user_per_date rscpupd
 CROSS JOIN statuses
 GROUP BY rscpupd.date, statuses.new_status
 ORDER BY rscpupd.date;
+
+-- name: UpsertWorkspaceCosts :exec
+-- This query aggregates the hourly cost of the resources of workspace builds
+-- into hourly buckets per workspace. A build accrues cost from the time its
+-- job completed until the next successful build of the workspace completed.
+-- Buckets are recalculated from the hour before the latest bucket, so the
+-- query can run repeatedly.
+WITH
+	latest_start AS (
+		SELECT
+			-- Default to 1 day ago, costs are only backfilled for a day.
+			COALESCE(MAX(bucket_start) - '1 hour'::interval, NOW() - '1 day'::interval) AS t
+		FROM
+			workspace_costs
+	),
+	buckets AS (
+		SELECT
+			b::timestamptz AS bucket_start
+		FROM
+			generate_series(
+				date_trunc('hour', (SELECT t FROM latest_start)),
+				date_trunc('hour', NOW()),
+				'1 hour'::interval
+			) AS b
+	),
+	succeeded_builds AS (
+		SELECT
+			wb.workspace_id,
+			wb.build_number,
+			wb.job_id,
+			pj.completed_at
+		FROM
+			workspace_builds AS wb
+		JOIN
+			provisioner_jobs AS pj
+		ON
+			pj.id = wb.job_id
+		WHERE
+			pj.job_status = 'succeeded'
+	),
+	-- Only the builds that accrue cost in the buckets are needed: the latest
+	-- build of each workspace that completed before the first bucket, and
+	-- every build that completed after it.
+	window_builds AS (
+		(
+			SELECT DISTINCT ON (sb.workspace_id)
+				sb.*
+			FROM
+				succeeded_builds AS sb
+			WHERE
+				sb.completed_at <= date_trunc('hour', (SELECT t FROM latest_start))
+			ORDER BY
+				sb.workspace_id, sb.build_number DESC
+		)
+		UNION ALL
+		SELECT
+			sb.*
+		FROM
+			succeeded_builds AS sb
+		WHERE
+			sb.completed_at > date_trunc('hour', (SELECT t FROM latest_start))
+	),
+	build_costs AS (
+		SELECT
+			wb.workspace_id,
+			wb.completed_at AS start_time,
+			COALESCE(
+				LEAD(wb.completed_at) OVER (PARTITION BY wb.workspace_id ORDER BY wb.build_number),
+				NOW()
+			) AS end_time,
+			(
+				SELECT
+					COALESCE(SUM(wr.hourly_cost), 0)
+				FROM
+					workspace_resources AS wr
+				WHERE
+					wr.job_id = wb.job_id
+			) AS hourly_cost
+		FROM
+			window_builds AS wb
+	)
+INSERT INTO workspace_costs AS wc (
+	bucket_start,
+	workspace_id,
+	owner_id,
+	template_id,
+	organization_id,
+	cost
+)
+SELECT
+	b.bucket_start,
+	w.id,
+	w.owner_id,
+	w.template_id,
+	w.organization_id,
+	SUM(
+		bc.hourly_cost * EXTRACT(EPOCH FROM (
+			LEAST(bc.end_time, b.bucket_start + '1 hour'::interval)
+			- GREATEST(bc.start_time, b.bucket_start)
+		)) / 3600
+	)::float AS cost
+FROM
+	buckets AS b
+JOIN
+	build_costs AS bc
+ON
+	bc.start_time < b.bucket_start + '1 hour'::interval
+	AND bc.end_time > b.bucket_start
+JOIN
+	workspaces AS w
+ON
+	w.id = bc.workspace_id
+WHERE
+	bc.hourly_cost > 0
+GROUP BY
+	b.bucket_start, w.id, w.owner_id, w.template_id, w.organization_id
+ON CONFLICT
+	(bucket_start, workspace_id)
+DO UPDATE SET
+	cost = EXCLUDED.cost;
+
+-- name: GetWorkspaceCostInsightsByInterval :many
+-- GetWorkspaceCostInsightsByInterval returns the cost of workspaces in each
+-- interval between start and end time. If end time is a partial interval, it
+-- will be included in the results and that interval will be shorter than a
+-- full one. Intervals without costs are included with a cost of 0.
+WITH
+	ts AS (
+		SELECT
+			d::timestamptz AS from_,
+			LEAST(
+				(d::timestamptz + (@interval_days::int || ' day')::interval)::timestamptz,
+				@end_time::timestamptz
+			)::timestamptz AS to_
+		FROM
+			generate_series(
+				@start_time::timestamptz,
+				-- Subtract 1 μs to avoid creating an extra series.
+				(@end_time::timestamptz) - '1 microsecond'::interval,
+				(@interval_days::int || ' day')::interval
+			) AS d
+	)
+
+SELECT
+	ts.from_ AS start_time,
+	ts.to_ AS end_time,
+	COALESCE(SUM(wc.cost), 0)::float AS cost
+FROM
+	ts
+LEFT JOIN
+	workspace_costs AS wc
+ON
+	wc.bucket_start >= ts.from_
+	AND wc.bucket_start < ts.to_
+	AND CASE WHEN COALESCE(array_length(@template_ids::uuid[], 1), 0) > 0 THEN wc.template_id = ANY(@template_ids::uuid[]) ELSE TRUE END
+GROUP BY
+	ts.from_, ts.to_
+ORDER BY
+	ts.from_;
+
+-- name: GetWorkspaceCostInsightsByTemplate :many
+SELECT
+	wc.template_id,
+	t.name AS template_name,
+	SUM(wc.cost)::float AS cost
+FROM
+	workspace_costs AS wc
+JOIN
+	templates AS t
+ON
+	t.id = wc.template_id
+WHERE
+	wc.bucket_start >= @start_time::timestamptz
+	AND wc.bucket_start < @end_time::timestamptz
+	AND CASE WHEN COALESCE(array_length(@template_ids::uuid[], 1), 0) > 0 THEN wc.template_id = ANY(@template_ids::uuid[]) ELSE TRUE END
+GROUP BY
+	wc.template_id, t.name
+ORDER BY
+	cost DESC, t.name;
+
+-- name: GetWorkspaceCostInsightsByUser :many
+SELECT
+	wc.owner_id AS user_id,
+	u.username,
+	SUM(wc.cost)::float AS cost
+FROM
+	workspace_costs AS wc
+JOIN
+	users AS u
+ON
+	u.id = wc.owner_id
+WHERE
+	wc.bucket_start >= @start_time::timestamptz
+	AND wc.bucket_start < @end_time::timestamptz
+	AND CASE WHEN COALESCE(array_length(@template_ids::uuid[], 1), 0) > 0 THEN wc.template_id = ANY(@template_ids::uuid[]) ELSE TRUE END
+GROUP BY
+	wc.owner_id, u.username
+ORDER BY
+	cost DESC, u.username;
+
+-- name: GetWorkspaceCostInsightsByGroup :many
+-- The cost of a workspace counts towards every group its owner is a member of
+-- in the organization of the workspace, including the "Everyone" group.
+SELECT
+	gme.group_id,
+	gme.group_name,
+	SUM(wc.cost)::float AS cost
+FROM
+	workspace_costs AS wc
+JOIN
+	group_members_expanded AS gme
+ON
+	gme.user_id = wc.owner_id
+	AND gme.organization_id = wc.organization_id
+WHERE
+	wc.bucket_start >= @start_time::timestamptz
+	AND wc.bucket_start < @end_time::timestamptz
+	AND CASE WHEN COALESCE(array_length(@template_ids::uuid[], 1), 0) > 0 THEN wc.template_id = ANY(@template_ids::uuid[]) ELSE TRUE END
+GROUP BY
+	gme.group_id, gme.group_name
+ORDER BY
+	cost DESC, gme.group_name;
@@ -0,0 +1,114 @@
+-- name: UpsertNetworkLatencyReports :exec
+-- UpsertNetworkLatencyReports stores the latest latency reported by clients
+-- to each DERP region. The reports must not contain duplicate
+-- (client_id, region_id) pairs.
+INSERT INTO
+	network_latency_reports (
+		client_id,
+		client_type,
+		region_id,
+		latency_ms,
+		preferred,
+		reported_at
+	)
+SELECT
+	unnest(@client_id :: uuid[]) AS client_id,
+	unnest(@client_type :: text[]) AS client_type,
+	unnest(@region_id :: integer[]) AS region_id,
+	unnest(@latency_ms :: double precision[]) AS latency_ms,
+	unnest(@preferred :: boolean[]) AS preferred,
+	unnest(@reported_at :: timestamptz[]) AS reported_at
+ON CONFLICT (client_id, region_id) DO UPDATE SET
+	client_type = EXCLUDED.client_type,
+	latency_ms = EXCLUDED.latency_ms,
+	preferred = EXCLUDED.preferred,
+	reported_at = EXCLUDED.reported_at
+WHERE
+	network_latency_reports.reported_at <= EXCLUDED.reported_at;
+
+-- name: UpsertNetworkConnectionReports :exec
+-- UpsertNetworkConnectionReports stores the latest type of the connections
+-- of clients. The reports must not contain duplicate
+-- (client_id, remote_node_id) pairs.
+INSERT INTO
+	network_connection_reports (
+		client_id,
+		remote_node_id,
+		client_type,
+		home_region_id,
+		direct,
+		reported_at
+	)
+SELECT
+	unnest(@client_id :: uuid[]) AS client_id,
+	unnest(@remote_node_id :: bigint[]) AS remote_node_id,
+	unnest(@client_type :: text[]) AS client_type,
+	unnest(@home_region_id :: integer[]) AS home_region_id,
+	unnest(@direct :: boolean[]) AS direct,
+	unnest(@reported_at :: timestamptz[]) AS reported_at
+ON CONFLICT (client_id, remote_node_id) DO UPDATE SET
+	client_type = EXCLUDED.client_type,
+	home_region_id = EXCLUDED.home_region_id,
+	direct = EXCLUDED.direct,
+	reported_at = EXCLUDED.reported_at
+WHERE
+	network_connection_reports.reported_at <= EXCLUDED.reported_at;
+
+-- name: GetNetworkRegionInsights :many
+-- GetNetworkRegionInsights returns, for each DERP region, the latency clients
+-- reported to it and the share of direct connections of the clients it's the
+-- home region of. Only reports since reported_after are included, and they
+-- can be filtered on client_types.
+WITH
+	latencies AS (
+		SELECT
+			region_id,
+			COUNT(*) AS clients,
+			COUNT(*) FILTER (WHERE preferred) AS preferred_clients,
+			PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY latency_ms) AS latency_50,
+			PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY latency_ms) AS latency_95
+		FROM
+			network_latency_reports
+		WHERE
+			reported_at >= @reported_after :: timestamptz
+			AND CASE WHEN COALESCE(array_length(@client_types :: text[], 1), 0) > 0 THEN client_type = ANY(@client_types :: text[]) ELSE TRUE END
+		GROUP BY
+			region_id
+	),
+	connections AS (
+		SELECT
+			home_region_id AS region_id,
+			COUNT(*) FILTER (WHERE direct) AS direct_connections,
+			COUNT(*) FILTER (WHERE NOT direct) AS relayed_connections
+		FROM
+			network_connection_reports
+		WHERE
+			reported_at >= @reported_after :: timestamptz
+			AND CASE WHEN COALESCE(array_length(@client_types :: text[], 1), 0) > 0 THEN client_type = ANY(@client_types :: text[]) ELSE TRUE END
+		GROUP BY
+			home_region_id
+	)
+SELECT
+	COALESCE(l.region_id, c.region_id) :: integer AS region_id,
+	COALESCE(l.clients, 0) :: bigint AS clients,
+	COALESCE(l.preferred_clients, 0) :: bigint AS preferred_clients,
+	COALESCE(l.latency_50, -1) :: float AS latency_50,
+	COALESCE(l.latency_95, -1) :: float AS latency_95,
+	COALESCE(c.direct_connections, 0) :: bigint AS direct_connections,
+	COALESCE(c.relayed_connections, 0) :: bigint AS relayed_connections
+FROM
+	latencies l
+FULL OUTER JOIN
+	connections c
+ON
+	c.region_id = l.region_id
+ORDER BY
+	region_id ASC;
+
+-- name: DeleteOldNetworkReports :exec
+-- Network reports are only used for insights about the last day.
+WITH
+	deleted_latency_reports AS (
+		DELETE FROM network_latency_reports WHERE reported_at < NOW() - INTERVAL '1 day'
+	)
+DELETE FROM network_connection_reports WHERE reported_at < NOW() - INTERVAL '1 day';
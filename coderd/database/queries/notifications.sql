@@ -4,12 +4,16 @@ SELECT nt.name                                                    AS notificatio
        nt.id                                                      AS notification_template_id,
        nt.actions                                                 AS actions,
        nt.method                                                  AS custom_method,
+       np.method                                                  AS user_method,
        u.id                                                       AS user_id,
        u.email                                                    AS user_email,
        COALESCE(NULLIF(u.name, ''), NULLIF(u.username, ''))::text AS user_name,
        u.username                                                 AS user_username
-FROM notification_templates nt,
-     users u
+FROM notification_templates nt
+         CROSS JOIN users u
+         LEFT JOIN notification_preferences np
+                   ON np.notification_template_id = nt.id
+                       AND np.user_id = u.id
 WHERE nt.id = @notification_template_id
   AND u.id = @user_id;
 
@@ -128,14 +132,14 @@ FROM (SELECT UNNEST(@ids::uuid[])             AS id,
          AS new_values
 WHERE notification_messages.id = new_values.id;
 
--- Delete all notification messages which have not been updated for over a week.
+-- Delete all notification messages which have not been updated since @before_time.
 -- name: DeleteOldNotificationMessages :exec
 DELETE
 FROM notification_messages
 WHERE id IN
       (SELECT id
        FROM notification_messages AS nested
-       WHERE nested.updated_at < NOW() - INTERVAL '7 days');
+       WHERE nested.updated_at < @before_time::timestamptz);
 
 -- name: GetNotificationMessagesByStatus :many
 SELECT *
@@ -158,6 +162,18 @@ ON CONFLICT (user_id, notification_template_id) DO UPDATE
     SET disabled   = EXCLUDED.disabled,
         updated_at = CURRENT_TIMESTAMP;
 
+-- name: UpdateUserNotificationMethodPreferences :execrows
+-- Sets the user's preferred delivery method per notification template. An empty method resets the preference, deferring
+-- to the template-level or deployment-level method.
+INSERT
+INTO notification_preferences (user_id, notification_template_id, method)
+SELECT @user_id::uuid, new_values.notification_template_id, NULLIF(new_values.method, '')::notification_method
+FROM (SELECT UNNEST(@notification_template_ids::uuid[]) AS notification_template_id,
+             UNNEST(@methods::text[])                   AS method) AS new_values
+ON CONFLICT (user_id, notification_template_id) DO UPDATE
+    SET method     = EXCLUDED.method,
+        updated_at = CURRENT_TIMESTAMP;
+
 -- name: UpdateNotificationTemplateMethodByID :one
 UPDATE notification_templates
 SET method = sqlc.narg('method')::notification_method
@@ -1,3 +1,33 @@
+-- Delete provisioner job logs created before @before_time in batches of
+-- @limit_count rows.
+-- Exception: logs of the latest build of each workspace are kept around.
+-- name: DeleteOldProvisionerJobLogs :execrows
+WITH
+	latest_builds AS (
+		SELECT DISTINCT ON (workspace_id)
+			job_id
+		FROM
+			workspace_builds
+		ORDER BY
+			workspace_id, build_number DESC
+	)
+DELETE FROM
+	provisioner_job_logs
+WHERE
+	id IN (
+		SELECT
+			id
+		FROM
+			provisioner_job_logs
+		WHERE
+			created_at < @before_time :: timestamptz
+			AND job_id NOT IN (SELECT job_id FROM latest_builds)
+		ORDER BY
+			id ASC
+		LIMIT
+			@limit_count :: int
+	);
+
 -- name: GetProvisionerLogsAfterID :many
 SELECT
 	*
@@ -1,6 +1,8 @@
 -- Acquires the lock for a single job that isn't started, completed,
 -- canceled, and that matches an array of provisioner types.
 --
+-- Jobs with a higher priority are acquired first.
+--
 -- SKIP LOCKED is used to jump over locked rows. This prevents
 -- multiple provisioners from acquiring the same jobs. See:
 -- https://www.postgresql.org/docs/9.5/sql-select.html#SQL-FOR-UPDATE-SHARE
@@ -25,7 +27,63 @@ WHERE
 			-- elsewhere, we use the tagset type, but here we use jsonb for backward compatibility
 			-- they are aliases and the code that calls this query already relies on a different type
 			AND provisioner_tagset_contains(@provisioner_tags :: jsonb, potential_job.tags :: jsonb)
+			-- Workspace builds that are awaiting approval, or were rejected, must
+			-- not be acquired.
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					workspace_builds
+				INNER JOIN
+					workspace_build_approvals ON workspace_build_approvals.workspace_build_id = workspace_builds.id
+				WHERE
+					workspace_builds.job_id = potential_job.id
+					AND workspace_build_approvals.status != 'approved'
+			)
+			-- Enforce the limits on concurrently running jobs. There is no limit
+			-- if it is 0. The limits may be briefly exceeded when jobs are
+			-- acquired concurrently.
+			AND (
+				@max_organization_jobs :: integer <= 0
+				OR (
+					SELECT
+						COUNT(*)
+					FROM
+						provisioner_jobs AS running_job
+					WHERE
+						running_job.organization_id = potential_job.organization_id
+						AND running_job.started_at IS NOT NULL
+						AND running_job.completed_at IS NULL
+				) < @max_organization_jobs :: integer
+			)
+			AND (
+				@max_user_jobs :: integer <= 0
+				OR (
+					SELECT
+						COUNT(*)
+					FROM
+						provisioner_jobs AS running_job
+					WHERE
+						running_job.initiator_id = potential_job.initiator_id
+						AND running_job.started_at IS NOT NULL
+						AND running_job.completed_at IS NULL
+				) < @max_user_jobs :: integer
+			)
 		ORDER BY
+			potential_job.priority DESC,
+			-- Among jobs of the same priority, prefer users with fewer running
+			-- jobs so that one user queueing many jobs doesn't starve others.
+			(
+				SELECT
+					COUNT(*)
+				FROM
+					provisioner_jobs AS running_job
+				WHERE
+					running_job.organization_id = potential_job.organization_id
+					AND running_job.initiator_id = potential_job.initiator_id
+					AND running_job.started_at IS NOT NULL
+					AND running_job.completed_at IS NULL
+			) ASC,
 			potential_job.created_at
 		FOR UPDATE
 		SKIP LOCKED
@@ -49,10 +107,13 @@ FROM
 WHERE
 	id = ANY(@ids :: uuid [ ]);
 
+-- Queue positions are ordered by priority and creation time only. They don't
+-- account for the limits on concurrently running jobs, so a job held back by
+-- the limit of its organization or user may be acquired after jobs behind it.
 -- name: GetProvisionerJobsByIDsWithQueuePosition :many
 WITH pending_jobs AS (
     SELECT
-        id, created_at
+        id, created_at, priority
     FROM
         provisioner_jobs
     WHERE
@@ -67,7 +128,7 @@ WITH pending_jobs AS (
 queue_position AS (
     SELECT
         id,
-        ROW_NUMBER() OVER (ORDER BY created_at ASC) AS queue_position
+        ROW_NUMBER() OVER (ORDER BY priority DESC, created_at ASC) AS queue_position
     FROM
         pending_jobs
 ),
@@ -104,10 +165,11 @@ INSERT INTO
 		"type",
 		"input",
 		tags,
-		trace_metadata
+		trace_metadata,
+		priority
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING *;
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING *;
 
 -- name: UpdateProvisionerJobByID :exec
 UPDATE
@@ -147,6 +209,25 @@ WHERE
 	AND started_at IS NOT NULL
 	AND completed_at IS NULL;
 
+-- Returns the distinct provisioner types and tags of the pending jobs in an
+-- organization or initiated by a user. These are the jobs that may have been
+-- held back by the limits on concurrently running jobs.
+-- name: GetPendingProvisionerJobTags :many
+SELECT DISTINCT
+	organization_id,
+	provisioner,
+	tags
+FROM
+	provisioner_jobs
+WHERE
+	started_at IS NULL
+	AND canceled_at IS NULL
+	AND completed_at IS NULL
+	AND (
+		organization_id = @organization_id
+		OR initiator_id = @initiator_id
+	);
+
 -- name: InsertProvisionerJobTimings :many
 INSERT INTO provisioner_job_timings (job_id, started_at, ended_at, stage, source, action, resource)
 SELECT
@@ -163,3 +244,19 @@ RETURNING *;
 SELECT * FROM provisioner_job_timings
 WHERE job_id = $1
 ORDER BY started_at ASC;
+
+-- name: InsertProvisionerJobResourceChanges :many
+INSERT INTO provisioner_job_resource_changes (job_id, address, resource_type, resource_name, action, attributes)
+SELECT
+    @job_id::uuid AS provisioner_job_id,
+    unnest(@address::text[]),
+    unnest(@resource_type::text[]),
+    unnest(@resource_name::text[]),
+    unnest(@action::provisioner_job_resource_change_action[]),
+    jsonb_array_elements(@attributes::jsonb)
+RETURNING *;
+
+-- name: GetProvisionerJobResourceChangesByJobID :many
+SELECT * FROM provisioner_job_resource_changes
+WHERE job_id = $1
+ORDER BY address ASC;
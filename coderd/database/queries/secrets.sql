@@ -0,0 +1,97 @@
+-- name: InsertSecret :one
+INSERT INTO
+	secrets (
+		id,
+		created_at,
+		updated_at,
+		name,
+		description,
+		value,
+		value_key_id,
+		env_name,
+		file_path,
+		user_id,
+		organization_id,
+		template_id
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING *;
+
+-- name: GetSecretByID :one
+SELECT
+	*
+FROM
+	secrets
+WHERE
+	id = $1
+LIMIT
+	1;
+
+-- name: GetSecretsByScope :many
+-- Returns the secrets of exactly one user, organization or template. The
+-- other scopes must be NULL.
+SELECT
+	*
+FROM
+	secrets
+WHERE
+	user_id IS NOT DISTINCT FROM sqlc.narg('user_id') :: uuid
+	AND organization_id IS NOT DISTINCT FROM sqlc.narg('organization_id') :: uuid
+	AND template_id IS NOT DISTINCT FROM sqlc.narg('template_id') :: uuid
+ORDER BY
+	name ASC;
+
+-- name: GetSecretByScopeAndName :one
+SELECT
+	*
+FROM
+	secrets
+WHERE
+	user_id IS NOT DISTINCT FROM sqlc.narg('user_id') :: uuid
+	AND organization_id IS NOT DISTINCT FROM sqlc.narg('organization_id') :: uuid
+	AND template_id IS NOT DISTINCT FROM sqlc.narg('template_id') :: uuid
+	AND name = @name
+LIMIT
+	1;
+
+-- name: GetSecretsForWorkspace :many
+-- Returns the secrets that apply to a workspace: those of its owner, its
+-- template and its organization.
+SELECT
+	*
+FROM
+	secrets
+WHERE
+	user_id = @owner_id :: uuid
+	OR template_id = @template_id :: uuid
+	OR organization_id = @organization_id :: uuid
+ORDER BY
+	name ASC;
+
+-- name: GetSecrets :many
+SELECT
+	*
+FROM
+	secrets
+ORDER BY
+	created_at ASC;
+
+-- name: UpdateSecretByID :one
+UPDATE
+	secrets
+SET
+	updated_at = @updated_at,
+	description = @description,
+	value = @value,
+	value_key_id = @value_key_id,
+	env_name = @env_name,
+	file_path = @file_path
+WHERE
+	id = @id
+RETURNING *;
+
+-- name: DeleteSecretByID :exec
+DELETE FROM
+	secrets
+WHERE
+	id = $1;
@@ -0,0 +1,89 @@
+-- name: InsertTemplateRollout :one
+INSERT INTO template_rollouts (
+	id,
+	template_id,
+	template_version_id,
+	created_by,
+	created_at,
+	updated_at,
+	percent,
+	group_id,
+	min_builds,
+	max_failure_rate,
+	auto_promote
+)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING *;
+
+-- name: GetTemplateRolloutByID :one
+SELECT
+	*
+FROM
+	template_rollouts
+WHERE
+	id = $1;
+
+-- name: GetActiveTemplateRolloutByTemplateID :one
+SELECT
+	*
+FROM
+	template_rollouts
+WHERE
+	template_id = $1
+	AND status = 'in_progress'::template_rollout_status;
+
+-- name: GetTemplateRolloutsByTemplateID :many
+SELECT
+	*
+FROM
+	template_rollouts
+WHERE
+	template_id = $1
+ORDER BY
+	created_at DESC;
+
+-- name: UpdateTemplateRolloutByID :one
+UPDATE
+	template_rollouts
+SET
+	percent = $2,
+	group_id = $3,
+	updated_at = $4
+WHERE
+	id = $1
+	AND status = 'in_progress'::template_rollout_status
+RETURNING *;
+
+-- name: UpdateTemplateRolloutStatusByID :one
+-- Completes a rollout in progress. Completed rollouts are never updated again.
+UPDATE
+	template_rollouts
+SET
+	status = @status,
+	status_reason = @status_reason,
+	updated_at = @updated_at,
+	completed_at = @updated_at
+WHERE
+	id = @id
+	AND status = 'in_progress'::template_rollout_status
+RETURNING *;
+
+-- name: GetTemplateRolloutBuildCounts :one
+-- Counts the completed start builds of a template version since the given
+-- time. Canceled builds are not counted.
+SELECT
+	COUNT(*)::bigint AS builds,
+	COUNT(*) FILTER (WHERE provisioner_jobs.error IS NOT NULL AND provisioner_jobs.error != '')::bigint AS failed_builds
+FROM
+	workspace_builds
+JOIN
+	provisioner_jobs
+ON
+	provisioner_jobs.id = workspace_builds.job_id
+WHERE
+	workspace_builds.template_version_id = @template_version_id
+	AND workspace_builds.transition = 'start'::workspace_transition
+	AND workspace_builds.created_at >= @since :: timestamptz
+	AND provisioner_jobs.completed_at IS NOT NULL
+	AND provisioner_jobs.canceled_at IS NULL;
@@ -124,7 +124,12 @@ SET
 	display_name = $6,
 	allow_user_cancel_workspace_jobs = $7,
 	group_acl = $8,
-	max_port_sharing_level = $9
+	max_port_sharing_level = $9,
+	session_recording_enabled = $10,
+	require_build_approval = $11,
+	build_approval_parameters = $12,
+	build_approval_group_id = $13,
+	build_approval_timeout = $14
 WHERE
 	id = $1
 ;
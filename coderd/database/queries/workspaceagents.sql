@@ -139,6 +139,50 @@ WHERE
 	workspace_agent_id = $1
 	AND CASE WHEN COALESCE(array_length(sqlc.arg('keys')::text[], 1), 0) > 0 THEN key = ANY(sqlc.arg('keys')::text[]) ELSE TRUE END;
 
+-- name: UpsertWorkspaceAgentProcessGroups :exec
+-- UpsertWorkspaceAgentProcessGroups stores the latest resource usage of the
+-- process groups reported by an agent.
+INSERT INTO
+	workspace_agent_process_groups (
+		workspace_agent_id,
+		name,
+		cpu_usage_usec,
+		cpu_cores,
+		cpu_limit_cores,
+		memory_bytes,
+		memory_limit_bytes,
+		oom_kills,
+		updated_at
+	)
+SELECT
+	@workspace_agent_id :: uuid AS workspace_agent_id,
+	unnest(@name :: text[]) AS name,
+	unnest(@cpu_usage_usec :: bigint[]) AS cpu_usage_usec,
+	unnest(@cpu_cores :: double precision[]) AS cpu_cores,
+	unnest(@cpu_limit_cores :: double precision[]) AS cpu_limit_cores,
+	unnest(@memory_bytes :: bigint[]) AS memory_bytes,
+	unnest(@memory_limit_bytes :: bigint[]) AS memory_limit_bytes,
+	unnest(@oom_kills :: bigint[]) AS oom_kills,
+	@updated_at :: timestamptz AS updated_at
+ON CONFLICT (workspace_agent_id, name) DO UPDATE SET
+	cpu_usage_usec = EXCLUDED.cpu_usage_usec,
+	cpu_cores = EXCLUDED.cpu_cores,
+	cpu_limit_cores = EXCLUDED.cpu_limit_cores,
+	memory_bytes = EXCLUDED.memory_bytes,
+	memory_limit_bytes = EXCLUDED.memory_limit_bytes,
+	oom_kills = EXCLUDED.oom_kills,
+	updated_at = EXCLUDED.updated_at;
+
+-- name: GetWorkspaceAgentProcessGroups :many
+SELECT
+	*
+FROM
+	workspace_agent_process_groups
+WHERE
+	workspace_agent_id = $1
+ORDER BY
+	name;
+
 -- name: UpdateWorkspaceAgentLogOverflowByID :exec
 UPDATE
 	workspace_agents
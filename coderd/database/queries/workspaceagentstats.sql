@@ -73,17 +73,18 @@ DELETE FROM
 WHERE
 	created_at < (
 		SELECT
-			COALESCE(
+			-- GREATEST ignores NULLs, so with no template usage stats only
+			-- the retention period applies.
+			GREATEST(
 				-- When generating initial template usage stats, all the
 				-- raw agent stats are needed, after that only ~30 mins
 				-- from last rollup is needed. Deployment stats seem to
 				-- use between 15 mins and 1 hour of data. We keep a
 				-- little bit more (1 day) just in case.
 				MAX(start_time) - '1 days'::interval,
-				-- Fall back to ~6 months ago if there are no template
-				-- usage stats so that we don't delete the data before
-				-- it's rolled up.
-				NOW() - '180 days'::interval
+				-- Stats older than the retention period are deleted even
+				-- if they have not been rolled up.
+				@before_time::timestamptz
 			)
 		FROM
 			template_usage_stats
@@ -0,0 +1,70 @@
+-- name: InsertWorkspaceBuildApproval :one
+INSERT INTO
+	workspace_build_approvals (
+		workspace_build_id,
+		created_at,
+		updated_at,
+		expires_at
+	)
+VALUES
+	($1, $2, $3, $4) RETURNING *;
+
+-- name: GetWorkspaceBuildApprovalByBuildID :one
+SELECT
+	*
+FROM
+	workspace_build_approvals
+WHERE
+	workspace_build_id = $1
+LIMIT
+	1;
+
+-- name: GetPendingWorkspaceBuildApprovalsByTemplateID :many
+-- Builds that were canceled while waiting for approval are excluded.
+SELECT
+	sqlc.embed(workspace_build_approvals),
+	workspace_builds.workspace_id
+FROM
+	workspace_build_approvals
+INNER JOIN
+	workspace_builds ON workspace_builds.id = workspace_build_approvals.workspace_build_id
+INNER JOIN
+	workspaces ON workspaces.id = workspace_builds.workspace_id
+INNER JOIN
+	provisioner_jobs ON provisioner_jobs.id = workspace_builds.job_id
+WHERE
+	workspaces.template_id = @template_id
+	AND workspace_build_approvals.status = 'pending'
+	AND provisioner_jobs.completed_at IS NULL
+ORDER BY
+	workspace_build_approvals.created_at ASC;
+
+-- name: UpdateWorkspaceBuildApprovalByBuildID :one
+-- Only pending approvals can be reviewed, so reviewing an approval twice
+-- returns no rows.
+UPDATE
+	workspace_build_approvals
+SET
+	updated_at = @updated_at,
+	status = @status,
+	reviewer_id = @reviewer_id,
+	reviewed_at = @updated_at,
+	reason = @reason
+WHERE
+	workspace_build_id = @workspace_build_id
+	AND status = 'pending'
+RETURNING *;
+
+-- name: RejectExpiredWorkspaceBuildApprovals :many
+UPDATE
+	workspace_build_approvals
+SET
+	updated_at = @now,
+	status = 'rejected',
+	reviewed_at = @now,
+	reason = @reason
+WHERE
+	status = 'pending'
+	AND expires_at IS NOT NULL
+	AND expires_at <= @now
+RETURNING *;
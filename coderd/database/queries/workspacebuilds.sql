@@ -1,3 +1,36 @@
+-- Clear the Terraform state of workspace builds created before @before_time
+-- in batches of @limit_count rows. Only the state of the latest build of each
+-- workspace is used by subsequent builds, so it is always kept.
+-- name: ClearOldWorkspaceBuildProvisionerStates :execrows
+WITH
+	latest_builds AS (
+		SELECT DISTINCT ON (workspace_id)
+			id
+		FROM
+			workspace_builds
+		ORDER BY
+			workspace_id, build_number DESC
+	)
+UPDATE
+	workspace_builds
+SET
+	provisioner_state = NULL
+WHERE
+	id IN (
+		SELECT
+			id
+		FROM
+			workspace_builds
+		WHERE
+			created_at < @before_time :: timestamptz
+			AND provisioner_state IS NOT NULL
+			AND id NOT IN (SELECT id FROM latest_builds)
+		ORDER BY
+			created_at ASC
+		LIMIT
+			@limit_count :: int
+	);
+
 -- name: GetWorkspaceBuildByID :one
 SELECT
 	*
@@ -0,0 +1,64 @@
+-- name: InsertWorkspaceScheduledAction :one
+INSERT INTO
+	workspace_scheduled_actions (
+		id,
+		workspace_id,
+		created_at,
+		updated_at,
+		action,
+		schedule,
+		next_run_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7) RETURNING *;
+
+-- name: GetWorkspaceScheduledActionByID :one
+SELECT
+	*
+FROM
+	workspace_scheduled_actions
+WHERE
+	id = $1
+LIMIT
+	1;
+
+-- name: GetWorkspaceScheduledActionsByWorkspaceID :many
+SELECT
+	*
+FROM
+	workspace_scheduled_actions
+WHERE
+	workspace_id = $1
+ORDER BY
+	created_at ASC;
+
+-- name: GetWorkspaceScheduledActionsDue :many
+-- Returns the scheduled actions of non-deleted workspaces that are due to run
+-- at the given time, oldest first.
+SELECT
+	workspace_scheduled_actions.*
+FROM
+	workspace_scheduled_actions
+INNER JOIN
+	workspaces ON workspaces.id = workspace_scheduled_actions.workspace_id
+WHERE
+	workspace_scheduled_actions.next_run_at <= @now :: timestamptz
+	AND workspaces.deleted = false
+ORDER BY
+	workspace_scheduled_actions.next_run_at ASC;
+
+-- name: UpdateWorkspaceScheduledActionRunByID :exec
+UPDATE
+	workspace_scheduled_actions
+SET
+	updated_at = $2,
+	next_run_at = $3,
+	last_run_at = $4
+WHERE
+	id = $1;
+
+-- name: DeleteWorkspaceScheduledActionByID :exec
+DELETE FROM
+	workspace_scheduled_actions
+WHERE
+	id = $1;
@@ -0,0 +1,71 @@
+-- name: InsertWorkspaceSessionRecording :one
+INSERT INTO
+	workspace_session_recordings (
+		id,
+		created_at,
+		updated_at,
+		workspace_id,
+		agent_id,
+		user_id,
+		type
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7) RETURNING *;
+
+-- name: GetWorkspaceSessionRecordingByID :one
+SELECT
+	*
+FROM
+	workspace_session_recordings
+WHERE
+	id = $1
+LIMIT
+	1;
+
+-- name: GetWorkspaceSessionRecordingsByWorkspaceID :many
+SELECT
+	*
+FROM
+	workspace_session_recordings
+WHERE
+	workspace_id = $1
+ORDER BY
+	created_at DESC;
+
+-- name: UpdateWorkspaceSessionRecordingByID :exec
+UPDATE
+	workspace_session_recordings
+SET
+	updated_at = @updated_at,
+	size = size + @size_delta :: bigint,
+	ended_at = @ended_at
+WHERE
+	id = @id;
+
+-- name: InsertWorkspaceSessionRecordingChunk :execrows
+-- Agents retry chunks whose upload failed, so inserting a chunk that already
+-- exists is a no-op. It returns the number of chunks inserted.
+INSERT INTO
+	workspace_session_recording_chunks (
+		recording_id,
+		sequence,
+		data
+	)
+VALUES
+	($1, $2, $3)
+ON CONFLICT (recording_id, sequence) DO NOTHING;
+
+-- name: GetWorkspaceSessionRecordingChunksByRecordingID :many
+-- Recordings can be large, so their chunks are fetched in pages. Each page
+-- starts after the sequence of the last chunk of the previous page.
+SELECT
+	*
+FROM
+	workspace_session_recording_chunks
+WHERE
+	recording_id = @recording_id
+	AND sequence > @after_sequence
+ORDER BY
+	sequence ASC
+LIMIT
+	@limit_opt :: int;
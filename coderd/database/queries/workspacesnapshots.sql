@@ -0,0 +1,47 @@
+-- name: InsertWorkspaceSnapshot :one
+INSERT INTO
+	workspace_snapshots (
+		id,
+		created_at,
+		updated_at,
+		organization_id,
+		owner_id,
+		template_id,
+		workspace_id,
+		build_id,
+		name
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING *;
+
+-- name: GetWorkspaceSnapshotByID :one
+SELECT
+	*
+FROM
+	workspace_snapshots
+WHERE
+	id = $1
+LIMIT
+	1;
+
+-- name: GetWorkspaceSnapshotsByOwnerAndTemplate :many
+-- Snapshots can be restored into any workspace of the same owner and template,
+-- including snapshots taken from workspaces that have since been deleted.
+SELECT
+	*
+FROM
+	workspace_snapshots
+WHERE
+	owner_id = @owner_id
+	AND template_id = @template_id
+ORDER BY
+	created_at DESC;
+
+-- name: UpdateWorkspaceSnapshotArtifactsByID :exec
+UPDATE
+	workspace_snapshots
+SET
+	artifacts = $2,
+	updated_at = $3
+WHERE
+	id = $1;
@@ -149,6 +149,42 @@ func (a CustomRolePermission) String() string {
 	return str
 }
 
+type APIKeyScopePermissions []APIKeyScopePermission
+
+func (a *APIKeyScopePermissions) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), &a)
+	case []byte:
+		return json.Unmarshal(v, &a)
+	}
+	return xerrors.Errorf("unexpected type %T", src)
+}
+
+func (a APIKeyScopePermissions) Value() (driver.Value, error) {
+	if a == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(a)
+}
+
+// APIKeyScopePermission is a permission of an API key with the custom scope.
+type APIKeyScopePermission struct {
+	ResourceType string        `json:"resource_type"`
+	Action       policy.Action `json:"action"`
+	// OrganizationID limits the permission to one organization. If it is
+	// uuid.Nil, the permission applies in every organization.
+	OrganizationID uuid.UUID `json:"organization_id"`
+}
+
+func (a APIKeyScopePermission) String() string {
+	str := a.ResourceType + ":" + string(a.Action)
+	if a.OrganizationID != uuid.Nil {
+		return str + "@" + a.OrganizationID.String()
+	}
+	return str
+}
+
 // NameOrganizationPair is used as a lookup tuple for custom role rows.
 type NameOrganizationPair struct {
 	Name string `db:"name" json:"name"`
@@ -3,6 +3,9 @@ package coderd
 import (
 	"net/http"
 
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbpurge"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/rbac/policy"
@@ -61,6 +64,34 @@ func (api *API) deploymentStats(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(r.Context(), rw, http.StatusOK, stats)
 }
 
+// @Summary Get database purge report
+// @ID get-database-purge-report
+// @Security CoderSessionToken
+// @Produce json
+// @Tags General
+// @Success 200 {object} codersdk.PurgeReport
+// @Router /deployment/purge-report [get]
+func (api *API) deploymentPurgeReport(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.Authorize(r, policy.ActionRead, rbac.ResourceDeploymentConfig) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	//nolint:gocritic // Counting purgeable rows spans tables the user may not
+	// be able to read; they are authorized to read the deployment config.
+	report, err := dbpurge.Report(dbauthz.AsSystemRestricted(ctx), api.Database, api.DeploymentValues, dbtime.Now())
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error generating purge report.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, report)
+}
+
 // @Summary Build info
 // @ID build-info
 // @Produce json
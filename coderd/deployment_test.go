@@ -2,13 +2,20 @@ package coderd_test
 
 import (
 	"context"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/serpent"
 )
 
 func TestDeploymentValues(t *testing.T) {
@@ -28,6 +35,8 @@ func TestDeploymentValues(t *testing.T) {
 	cfg.SCIMAPIKey.Set(hi)
 	cfg.ExternalTokenEncryptionKeys.Set("the_random_key_we_never_expected,an_other_key_we_never_unexpected")
 	cfg.Provisioner.DaemonPSK = "provisionersftw"
+	cfg.Notifications.Slack.WebhookURL.Set("https://hooks.slack.com/services/secret")
+	cfg.Notifications.Teams.WebhookURL.Set("https://example.webhook.office.com/secret")
 
 	client := coderdtest.New(t, &coderdtest.Options{
 		DeploymentValues: cfg,
@@ -48,6 +57,8 @@ func TestDeploymentValues(t *testing.T) {
 	require.Empty(t, scrubbed.Values.SCIMAPIKey.Value())
 	require.Empty(t, scrubbed.Values.ExternalTokenEncryptionKeys.Value())
 	require.Empty(t, scrubbed.Values.Provisioner.DaemonPSK.Value())
+	require.Empty(t, scrubbed.Values.Notifications.Slack.WebhookURL.String())
+	require.Empty(t, scrubbed.Values.Notifications.Teams.WebhookURL.String())
 }
 
 func TestDeploymentStats(t *testing.T) {
@@ -62,3 +73,36 @@ func TestDeploymentStats(t *testing.T) {
 		return err == nil
 	}, testutil.IntervalMedium), "failed to get deployment stats in time")
 }
+
+func TestDeploymentPurgeReport(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{
+		DeploymentValues: coderdtest.DeploymentValues(t, func(vals *codersdk.DeploymentValues) {
+			vals.Retention.AuditLogs = serpent.Duration(24 * time.Hour)
+		}),
+	})
+	owner := coderdtest.CreateFirstUser(t, client)
+	memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	_ = dbgen.AuditLog(t, db, database.AuditLog{Time: dbtime.Now().Add(-48 * time.Hour)})
+
+	report, err := client.DeploymentPurgeReport(ctx)
+	require.NoError(t, err)
+	var auditLogs *codersdk.PurgeReportTable
+	for i, table := range report.Tables {
+		if table.Name == "audit_logs" {
+			auditLogs = &report.Tables[i]
+		}
+	}
+	require.NotNil(t, auditLogs)
+	require.True(t, auditLogs.Enabled)
+	require.NotNil(t, auditLogs.Cutoff)
+	require.EqualValues(t, 1, auditLogs.Rows)
+
+	_, err = memberClient.DeploymentPurgeReport(ctx)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+}
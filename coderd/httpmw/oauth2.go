@@ -227,6 +227,13 @@ func ExtractOAuth2ProviderApp(db database.Store) func(http.Handler) http.Handler
 						paramAppID = r.Form.Get("client_id")
 					}
 				}
+				if paramAppID == "" {
+					// Clients may also authenticate with HTTP basic auth on the token
+					// endpoint, in which case the username is the client ID.
+					if username, _, ok := r.BasicAuth(); ok {
+						paramAppID = username
+					}
+				}
 				if paramAppID == "" {
 					httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 						Message: "Missing OAuth2 client ID.",
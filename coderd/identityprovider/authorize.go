@@ -1,8 +1,11 @@
 package identityprovider
 
 import (
+	"crypto/sha256"
 	"database/sql"
+	"encoding/base64"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
 	"time"
@@ -18,25 +21,49 @@ import (
 )
 
 type authorizeParams struct {
-	clientID     string
-	redirectURL  *url.URL
-	responseType codersdk.OAuth2ProviderResponseType
-	scope        []string
-	state        string
+	clientID            string
+	codeChallenge       string
+	codeChallengeMethod codersdk.OAuth2PKCECodeChallengeMethod
+	redirectURL         *url.URL
+	responseType        codersdk.OAuth2ProviderResponseType
+	scope               []string
+	state               string
 }
 
-func extractAuthorizeParams(r *http.Request, callbackURL *url.URL) (authorizeParams, []codersdk.ValidationError, error) {
+func extractAuthorizeParams(r *http.Request, app database.OAuth2ProviderApp, callbackURL *url.URL) (authorizeParams, []codersdk.ValidationError, error) {
 	p := httpapi.NewQueryParamParser()
 	vals := r.URL.Query()
 
 	p.RequiredNotEmpty("state", "response_type", "client_id")
+	if app.PublicClient {
+		// Public clients have no secret, so PKCE is the only way to tell that the
+		// client redeeming the code is the one that asked for it.
+		p.RequiredNotEmpty("code_challenge", "code_challenge_method")
+	}
 
 	params := authorizeParams{
-		clientID:     p.String(vals, "", "client_id"),
-		redirectURL:  p.RedirectURL(vals, callbackURL, "redirect_uri"),
-		responseType: httpapi.ParseCustom(p, vals, "", "response_type", httpapi.ParseEnum[codersdk.OAuth2ProviderResponseType]),
-		scope:        p.Strings(vals, []string{}, "scope"),
-		state:        p.String(vals, "", "state"),
+		clientID:            p.String(vals, "", "client_id"),
+		codeChallenge:       p.String(vals, "", "code_challenge"),
+		codeChallengeMethod: httpapi.ParseCustom(p, vals, "", "code_challenge_method", httpapi.ParseEnum[codersdk.OAuth2PKCECodeChallengeMethod]),
+		redirectURL:         p.RedirectURL(vals, callbackURL, "redirect_uri"),
+		responseType:        httpapi.ParseCustom(p, vals, "", "response_type", httpapi.ParseEnum[codersdk.OAuth2ProviderResponseType]),
+		scope:               p.Strings(vals, []string{}, "scope"),
+		state:               p.String(vals, "", "state"),
+	}
+
+	// The method defaults to "plain" when omitted, which we do not support.
+	if params.codeChallenge != "" && params.codeChallengeMethod == "" {
+		p.Errors = append(p.Errors, codersdk.ValidationError{
+			Field:  "code_challenge_method",
+			Detail: fmt.Sprintf("Query param %q is required when a code challenge is provided", "code_challenge_method"),
+		})
+	}
+	// An S256 challenge is always a base64url encoded SHA-256 hash.
+	if params.codeChallenge != "" && len(params.codeChallenge) != base64.RawURLEncoding.EncodedLen(sha256.Size) {
+		p.Errors = append(p.Errors, codersdk.ValidationError{
+			Field:  "code_challenge",
+			Detail: fmt.Sprintf("Query param %q must be a base64url encoded SHA-256 hash", "code_challenge"),
+		})
 	}
 
 	// We add "redirected" when coming from the authorize page.
@@ -68,7 +95,7 @@ func Authorize(db database.Store, accessURL *url.URL) http.HandlerFunc {
 			return
 		}
 
-		params, validationErrs, err := extractAuthorizeParams(r, callbackURL)
+		params, validationErrs, err := extractAuthorizeParams(r, app, callbackURL)
 		if err != nil {
 			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 				Message:     "Invalid query params.",
@@ -107,11 +134,12 @@ func Authorize(db database.Store, accessURL *url.URL) http.HandlerFunc {
 				// is received.  If the application does wait before exchanging the
 				// token (for example suppose they ask the user to confirm and the user
 				// has left) then they can just retry immediately and get a new code.
-				ExpiresAt:    dbtime.Now().Add(time.Duration(10) * time.Minute),
-				SecretPrefix: []byte(code.Prefix),
-				HashedSecret: []byte(code.Hashed),
-				AppID:        app.ID,
-				UserID:       apiKey.UserID,
+				ExpiresAt:     dbtime.Now().Add(time.Duration(10) * time.Minute),
+				SecretPrefix:  []byte(code.Prefix),
+				HashedSecret:  []byte(code.Hashed),
+				AppID:         app.ID,
+				UserID:        apiKey.UserID,
+				CodeChallenge: params.codeChallenge,
 			})
 			if err != nil {
 				return xerrors.Errorf("insert oauth2 authorization code: %w", err)
@@ -0,0 +1,256 @@
+package identityprovider
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/cryptorand"
+	"github.com/coder/coder/v2/site"
+)
+
+const (
+	// DeviceCodeLifetime is how long a device has to be approved before the
+	// device and user codes expire.  Fifteen minutes matches GitHub.
+	DeviceCodeLifetime = 15 * time.Minute
+	// DeviceCodePollInterval is the minimum amount of time a device must wait
+	// between polls to the token endpoint.  Five seconds is the RFC 8628
+	// default.
+	DeviceCodePollInterval = 5 * time.Second
+
+	// userCodeCharset omits vowels to avoid spelling words and characters that
+	// are easily confused with each other, as recommended by RFC 8628.
+	userCodeCharset = "BCDFGHJKLMNPQRSTVWXZ"
+	userCodeLength  = 8
+)
+
+// generateUserCode returns a short code the user types in to approve a
+// device, formatted as two groups of four characters.
+func generateUserCode() (string, error) {
+	code, err := cryptorand.StringCharset(userCodeCharset, userCodeLength)
+	if err != nil {
+		return "", err
+	}
+	return code[:userCodeLength/2] + "-" + code[userCodeLength/2:], nil
+}
+
+// normalizeUserCode accepts the user code in the forms a user is likely to
+// type it, ignoring case, spaces and dashes.
+func normalizeUserCode(code string) string {
+	code = strings.ToUpper(code)
+	code = strings.NewReplacer("-", "", " ", "").Replace(code)
+	if len(code) != userCodeLength {
+		return code
+	}
+	return code[:userCodeLength/2] + "-" + code[userCodeLength/2:]
+}
+
+// DeviceAuthorization starts the device authorization grant described in RFC
+// 8628.  It returns a device code the device uses to poll the token endpoint
+// and a user code the user enters on the verification page to approve it.
+func DeviceAuthorization(db database.Store, accessURL *url.URL) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		app := httpmw.OAuth2ProviderApp(r)
+
+		p := httpapi.NewQueryParamParser()
+		err := r.ParseForm()
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Failed to parse form.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		p.RequiredNotEmpty("client_id")
+		_ = p.String(r.Form, "", "client_id")
+		// TODO: We are ignoring scopes for now.
+		_ = p.Strings(r.Form, []string{}, "scope")
+		p.ErrorExcessParams(r.Form)
+		if len(p.Errors) > 0 {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message:     "Invalid query params.",
+				Validations: p.Errors,
+			})
+			return
+		}
+
+		deviceCode, err := GenerateSecret()
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Failed to generate OAuth2 device code.",
+			})
+			return
+		}
+		userCode, err := generateUserCode()
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Failed to generate OAuth2 user code.",
+			})
+			return
+		}
+
+		//nolint:gocritic // There is no user yet so we must use the system.
+		_, err = db.InsertOAuth2ProviderDeviceCode(dbauthz.AsSystemRestricted(ctx), database.InsertOAuth2ProviderDeviceCodeParams{
+			ID:           uuid.New(),
+			CreatedAt:    dbtime.Now(),
+			ExpiresAt:    dbtime.Now().Add(DeviceCodeLifetime),
+			SecretPrefix: []byte(deviceCode.Prefix),
+			HashedSecret: []byte(deviceCode.Hashed),
+			UserCode:     userCode,
+			AppID:        app.ID,
+		})
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Failed to create OAuth2 device code.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+
+		verifyURL := accessURL.ResolveReference(&url.URL{Path: "/oauth2/device/verify"})
+		verifyCompleteURL := *verifyURL
+		verifyCompleteURL.RawQuery = url.Values{"user_code": {userCode}}.Encode()
+		httpapi.Write(ctx, rw, http.StatusOK, codersdk.OAuth2DeviceAuthorizationResponse{
+			DeviceCode:              deviceCode.Formatted,
+			UserCode:                userCode,
+			VerificationURI:         verifyURL.String(),
+			VerificationURIComplete: verifyCompleteURL.String(),
+			ExpiresIn:               int64(DeviceCodeLifetime.Seconds()),
+			Interval:                int64(DeviceCodePollInterval.Seconds()),
+		})
+	}
+}
+
+// DeviceVerify displays an HTML page where the user enters the code shown on
+// their device, then asks them to allow or deny the device.  Like Authorize,
+// the choice is only acted on when the request came from the page itself,
+// which is detected via the origin and referer headers.
+func DeviceVerify(db database.Store, accessURL *url.URL) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		apiKey := httpmw.APIKey(r)
+		ua := httpmw.UserAuthorization(r)
+
+		renderError := func(status int, title, description string) {
+			site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+				Status:       status,
+				HideStatus:   false,
+				Title:        title,
+				Description:  description,
+				RetryEnabled: false,
+				DashboardURL: accessURL.String(),
+				Warnings:     nil,
+			})
+		}
+
+		userCode := r.URL.Query().Get("user_code")
+		if userCode == "" {
+			site.RenderOAuthAllowPage(rw, r, site.RenderOAuthAllowData{
+				RedirectURI:    r.URL.Path,
+				Username:       ua.FriendlyName,
+				DeviceCodeForm: true,
+			})
+			return
+		}
+
+		//nolint:gocritic // Device codes are not owned by a user until approved.
+		dbCode, err := db.GetOAuth2ProviderDeviceCodeByUserCode(dbauthz.AsSystemRestricted(ctx), normalizeUserCode(userCode))
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			renderError(http.StatusInternalServerError, "Internal Server Error", err.Error())
+			return
+		}
+		if errors.Is(err, sql.ErrNoRows) ||
+			dbCode.ExpiresAt.Before(dbtime.Now()) ||
+			dbCode.Status != database.OAuth2ProviderDeviceCodeStatusPending {
+			renderError(http.StatusBadRequest, "Invalid Code", "The code is invalid or has expired. Restart the login on your device to get a new code.")
+			return
+		}
+
+		app, err := db.GetOAuth2ProviderAppByID(ctx, dbCode.AppID)
+		if err != nil {
+			renderError(http.StatusInternalServerError, "Internal Server Error", err.Error())
+			return
+		}
+
+		action := r.URL.Query().Get("action")
+		if action != "" && cameFromDeviceVerify(r, accessURL) {
+			status := database.OAuth2ProviderDeviceCodeStatusDenied
+			if action == "allow" {
+				status = database.OAuth2ProviderDeviceCodeStatusApproved
+			}
+			_, err = db.UpdateOAuth2ProviderDeviceCodeStatusByID(ctx, database.UpdateOAuth2ProviderDeviceCodeStatusByIDParams{
+				ID:     dbCode.ID,
+				Status: status,
+				UserID: uuid.NullUUID{UUID: apiKey.UserID, Valid: true},
+			})
+			if err != nil {
+				renderError(http.StatusInternalServerError, "Internal Server Error", xerrors.Errorf("update device code: %w", err).Error())
+				return
+			}
+
+			title, description := "Device Approved", fmt.Sprintf("%s is now connected to your account. You can return to your device.", app.Name)
+			if status == database.OAuth2ProviderDeviceCodeStatusDenied {
+				title, description = "Device Denied", fmt.Sprintf("%s was not given access to your account.", app.Name)
+			}
+			site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+				Status:       http.StatusOK,
+				HideStatus:   true,
+				Title:        title,
+				Description:  description,
+				RetryEnabled: false,
+				DashboardURL: accessURL.String(),
+				Warnings:     nil,
+			})
+			return
+		}
+
+		withAction := func(action string) string {
+			u := *r.URL
+			q := url.Values{}
+			q.Set("user_code", dbCode.UserCode)
+			q.Set("action", action)
+			u.RawQuery = q.Encode()
+			return u.String()
+		}
+		site.RenderOAuthAllowPage(rw, r, site.RenderOAuthAllowData{
+			AppIcon:     app.Icon,
+			AppName:     app.Name,
+			CancelURI:   withAction("deny"),
+			RedirectURI: withAction("allow"),
+			Username:    ua.FriendlyName,
+			UserCode:    dbCode.UserCode,
+		})
+	}
+}
+
+// cameFromDeviceVerify reports whether the request was made by clicking a
+// button on the device verification page.  See authorizeMW for why the origin
+// is optional but the referer is not.
+func cameFromDeviceVerify(r *http.Request, accessURL *url.URL) bool {
+	origin := r.Header.Get(httpmw.OriginHeader)
+	originU, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	refererU, err := url.Parse(r.Referer())
+	if err != nil {
+		return false
+	}
+	return (origin == "" || originU.Hostname() == accessURL.Hostname()) &&
+		refererU.Hostname() == accessURL.Hostname() &&
+		refererU.Path == "/oauth2/device/verify"
+}
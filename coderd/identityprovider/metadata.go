@@ -0,0 +1,42 @@
+package identityprovider
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// Metadata serves the authorization server metadata document described in RFC
+// 8414 so clients can discover the provider's endpoints and capabilities.
+func Metadata(accessURL *url.URL) http.HandlerFunc {
+	endpoint := func(path string) string {
+		return accessURL.ResolveReference(&url.URL{Path: path}).String()
+	}
+	metadata := codersdk.OAuth2AuthorizationServerMetadata{
+		// The issuer must not have a trailing slash, since clients compare it to
+		// the URL they fetched the metadata from with the well-known path removed.
+		Issuer:                      strings.TrimSuffix(accessURL.String(), "/"),
+		AuthorizationEndpoint:       endpoint("/oauth2/authorize"),
+		TokenEndpoint:               endpoint("/oauth2/tokens"),
+		DeviceAuthorizationEndpoint: endpoint("/oauth2/device"),
+		ResponseTypesSupported: []codersdk.OAuth2ProviderResponseType{
+			codersdk.OAuth2ProviderResponseTypeCode,
+		},
+		GrantTypesSupported: []codersdk.OAuth2ProviderGrantType{
+			codersdk.OAuth2ProviderGrantTypeAuthorizationCode,
+			codersdk.OAuth2ProviderGrantTypeRefreshToken,
+			codersdk.OAuth2ProviderGrantTypeClientCredentials,
+			codersdk.OAuth2ProviderGrantTypeDeviceCode,
+		},
+		CodeChallengeMethodsSupported: []codersdk.OAuth2PKCECodeChallengeMethod{
+			codersdk.OAuth2PKCECodeChallengeMethodS256,
+		},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_post", "client_secret_basic", "none"},
+	}
+	return func(rw http.ResponseWriter, r *http.Request) {
+		httpapi.Write(r.Context(), rw, http.StatusOK, metadata)
+	}
+}
@@ -2,11 +2,15 @@ package identityprovider
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/subtle"
 	"database/sql"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/google/uuid"
 	"golang.org/x/oauth2"
@@ -30,18 +34,45 @@ var (
 	errBadCode = xerrors.New("Invalid code")
 	// errBadToken means the user provided a bad token.
 	errBadToken = xerrors.New("Invalid token")
+	// errBadVerifier means the PKCE code verifier does not match the challenge
+	// that was sent when the code was requested.
+	errBadVerifier = xerrors.New("Invalid code verifier")
+	// errUnauthorizedClient means the app is not allowed to use the grant type.
+	errUnauthorizedClient = xerrors.New("Client is not allowed to use this grant type")
+)
+
+// deviceGrantError is returned while a device polls for its token. Devices
+// rely on the error codes from RFC 8628 to decide whether to keep polling, so
+// these are written in the standard OAuth2 error format rather than as a
+// codersdk.Response.
+type deviceGrantError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e deviceGrantError) Error() string {
+	return e.Description
+}
+
+var (
+	errAuthorizationPending = deviceGrantError{Code: "authorization_pending", Description: "The user has not approved the device yet."}
+	errSlowDown             = deviceGrantError{Code: "slow_down", Description: "The device is polling too frequently."}
+	errAccessDenied         = deviceGrantError{Code: "access_denied", Description: "The user denied the device."}
+	errExpiredToken         = deviceGrantError{Code: "expired_token", Description: "The device code has expired."}
 )
 
 type tokenParams struct {
 	clientID     string
 	clientSecret string
 	code         string
+	codeVerifier string
+	deviceCode   string
 	grantType    codersdk.OAuth2ProviderGrantType
 	redirectURL  *url.URL
 	refreshToken string
 }
 
-func extractTokenParams(r *http.Request, callbackURL *url.URL) (tokenParams, []codersdk.ValidationError, error) {
+func extractTokenParams(r *http.Request, app database.OAuth2ProviderApp, callbackURL *url.URL) (tokenParams, []codersdk.ValidationError, error) {
 	p := httpapi.NewQueryParamParser()
 	err := r.ParseForm()
 	if err != nil {
@@ -49,23 +80,46 @@ func extractTokenParams(r *http.Request, callbackURL *url.URL) (tokenParams, []c
 	}
 
 	vals := r.Form
+	// Clients may authenticate using HTTP basic auth instead of sending their
+	// credentials in the form, see RFC 6749 section 2.3.1.
+	if username, password, ok := r.BasicAuth(); ok {
+		if !vals.Has("client_id") {
+			vals.Set("client_id", formUnescape(username))
+		}
+		if !vals.Has("client_secret") {
+			vals.Set("client_secret", formUnescape(password))
+		}
+	}
+
 	p.RequiredNotEmpty("grant_type")
 	grantType := httpapi.ParseCustom(p, vals, "", "grant_type", httpapi.ParseEnum[codersdk.OAuth2ProviderGrantType])
 	switch grantType {
 	case codersdk.OAuth2ProviderGrantTypeRefreshToken:
 		p.RequiredNotEmpty("refresh_token")
 	case codersdk.OAuth2ProviderGrantTypeAuthorizationCode:
-		p.RequiredNotEmpty("client_secret", "client_id", "code")
+		p.RequiredNotEmpty("client_id", "code")
+	case codersdk.OAuth2ProviderGrantTypeClientCredentials:
+		p.RequiredNotEmpty("client_id")
+	case codersdk.OAuth2ProviderGrantTypeDeviceCode:
+		p.RequiredNotEmpty("client_id", "device_code")
+	}
+	// Public clients cannot keep a secret so they never send one.
+	if !app.PublicClient && grantType != codersdk.OAuth2ProviderGrantTypeRefreshToken {
+		p.RequiredNotEmpty("client_secret")
 	}
 
 	params := tokenParams{
 		clientID:     p.String(vals, "", "client_id"),
 		clientSecret: p.String(vals, "", "client_secret"),
 		code:         p.String(vals, "", "code"),
+		codeVerifier: p.String(vals, "", "code_verifier"),
+		deviceCode:   p.String(vals, "", "device_code"),
 		grantType:    grantType,
 		redirectURL:  p.RedirectURL(vals, callbackURL, "redirect_uri"),
 		refreshToken: p.String(vals, "", "refresh_token"),
 	}
+	// TODO: We are ignoring scopes for now.
+	_ = p.Strings(vals, []string{}, "scope")
 
 	p.ErrorExcessParams(vals)
 	if len(p.Errors) > 0 {
@@ -74,6 +128,16 @@ func extractTokenParams(r *http.Request, callbackURL *url.URL) (tokenParams, []c
 	return params, nil, nil
 }
 
+// formUnescape decodes a client credential sent through basic auth, which
+// are form encoded before being placed in the header.
+func formUnescape(s string) string {
+	unescaped, err := url.QueryUnescape(s)
+	if err != nil {
+		return s
+	}
+	return unescaped
+}
+
 // Tokens
 // TODO: the sessions lifetime config passed is for coder api tokens.
 // Should there be a separate config for oauth2 tokens? They are related,
@@ -92,7 +156,7 @@ func Tokens(db database.Store, lifetimes codersdk.SessionLifetime) http.HandlerF
 			return
 		}
 
-		params, validationErrs, err := extractTokenParams(r, callbackURL)
+		params, validationErrs, err := extractTokenParams(r, app, callbackURL)
 		if err != nil {
 			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 				Message:     "Invalid query params.",
@@ -103,13 +167,15 @@ func Tokens(db database.Store, lifetimes codersdk.SessionLifetime) http.HandlerF
 		}
 
 		var token oauth2.Token
-		//nolint:gocritic,revive // More cases will be added later.
 		switch params.grantType {
-		// TODO: Client creds, device code.
 		case codersdk.OAuth2ProviderGrantTypeRefreshToken:
 			token, err = refreshTokenGrant(ctx, db, app, lifetimes, params)
 		case codersdk.OAuth2ProviderGrantTypeAuthorizationCode:
 			token, err = authorizationCodeGrant(ctx, db, app, lifetimes, params)
+		case codersdk.OAuth2ProviderGrantTypeClientCredentials:
+			token, err = clientCredentialsGrant(ctx, db, app, lifetimes, params)
+		case codersdk.OAuth2ProviderGrantTypeDeviceCode:
+			token, err = deviceCodeGrant(ctx, db, app, lifetimes, params)
 		default:
 			// Grant types are validated by the parser, so getting through here means
 			// the developer added a type but forgot to add a case here.
@@ -120,12 +186,23 @@ func Tokens(db database.Store, lifetimes codersdk.SessionLifetime) http.HandlerF
 			return
 		}
 
-		if errors.Is(err, errBadCode) || errors.Is(err, errBadSecret) {
+		var deviceErr deviceGrantError
+		if errors.As(err, &deviceErr) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, deviceErr)
+			return
+		}
+		if errors.Is(err, errBadCode) || errors.Is(err, errBadSecret) || errors.Is(err, errBadVerifier) {
 			httpapi.Write(r.Context(), rw, http.StatusUnauthorized, codersdk.Response{
 				Message: err.Error(),
 			})
 			return
 		}
+		if errors.Is(err, errUnauthorizedClient) {
+			httpapi.Write(r.Context(), rw, http.StatusBadRequest, codersdk.Response{
+				Message: err.Error(),
+			})
+			return
+		}
 		if err != nil {
 			httpapi.Write(r.Context(), rw, http.StatusInternalServerError, codersdk.Response{
 				Message: "Failed to exchange token",
@@ -140,26 +217,66 @@ func Tokens(db database.Store, lifetimes codersdk.SessionLifetime) http.HandlerF
 	}
 }
 
-func authorizationCodeGrant(ctx context.Context, db database.Store, app database.OAuth2ProviderApp, lifetimes codersdk.SessionLifetime, params tokenParams) (oauth2.Token, error) {
-	// Validate the client secret.
-	secret, err := parseSecret(params.clientSecret)
+// authenticateClient validates the client secret of a confidential app and
+// returns the ID of the secret that was used. Public apps have no secret, so
+// the returned ID is not valid for them.
+func authenticateClient(ctx context.Context, db database.Store, app database.OAuth2ProviderApp, clientSecret string) (uuid.NullUUID, error) {
+	if app.PublicClient {
+		return uuid.NullUUID{}, nil
+	}
+
+	secret, err := parseSecret(clientSecret)
 	if err != nil {
-		return oauth2.Token{}, errBadSecret
+		return uuid.NullUUID{}, errBadSecret
 	}
 	//nolint:gocritic // Users cannot read secrets so we must use the system.
 	dbSecret, err := db.GetOAuth2ProviderAppSecretByPrefix(dbauthz.AsSystemRestricted(ctx), []byte(secret.prefix))
 	if errors.Is(err, sql.ErrNoRows) {
-		return oauth2.Token{}, errBadSecret
+		return uuid.NullUUID{}, errBadSecret
 	}
 	if err != nil {
-		return oauth2.Token{}, err
+		return uuid.NullUUID{}, err
+	}
+	if dbSecret.AppID != app.ID {
+		return uuid.NullUUID{}, errBadSecret
 	}
 	equal, err := userpassword.Compare(string(dbSecret.HashedSecret), secret.secret)
 	if err != nil {
-		return oauth2.Token{}, xerrors.Errorf("unable to compare secret: %w", err)
+		return uuid.NullUUID{}, xerrors.Errorf("unable to compare secret: %w", err)
 	}
 	if !equal {
-		return oauth2.Token{}, errBadSecret
+		return uuid.NullUUID{}, errBadSecret
+	}
+	return uuid.NullUUID{UUID: dbSecret.ID, Valid: true}, nil
+}
+
+// verifyCodeChallenge checks a PKCE code verifier against the S256 challenge
+// stored with the authorization code, as described in RFC 7636.
+func verifyCodeChallenge(challenge, verifier string) error {
+	if challenge == "" {
+		// A verifier without a challenge means the request was tampered with or
+		// the client is confused, either way it should not succeed.
+		if verifier != "" {
+			return errBadVerifier
+		}
+		return nil
+	}
+	if len(verifier) < 43 || len(verifier) > 128 {
+		return errBadVerifier
+	}
+	hashed := sha256.Sum256([]byte(verifier))
+	expected := base64.RawURLEncoding.EncodeToString(hashed[:])
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) != 1 {
+		return errBadVerifier
+	}
+	return nil
+}
+
+func authorizationCodeGrant(ctx context.Context, db database.Store, app database.OAuth2ProviderApp, lifetimes codersdk.SessionLifetime, params tokenParams) (oauth2.Token, error) {
+	// Validate the client secret.
+	secretID, err := authenticateClient(ctx, db, app, params.clientSecret)
+	if err != nil {
+		return oauth2.Token{}, err
 	}
 
 	// Validate the authorization code.
@@ -175,11 +292,11 @@ func authorizationCodeGrant(ctx context.Context, db database.Store, app database
 	if err != nil {
 		return oauth2.Token{}, err
 	}
-	equal, err = userpassword.Compare(string(dbCode.HashedSecret), code.secret)
+	equal, err := userpassword.Compare(string(dbCode.HashedSecret), code.secret)
 	if err != nil {
 		return oauth2.Token{}, xerrors.Errorf("unable to compare code: %w", err)
 	}
-	if !equal {
+	if !equal || dbCode.AppID != app.ID {
 		return oauth2.Token{}, errBadCode
 	}
 
@@ -188,28 +305,171 @@ func authorizationCodeGrant(ctx context.Context, db database.Store, app database
 		return oauth2.Token{}, errBadCode
 	}
 
-	// Generate a refresh token.
+	// Ensure the client redeeming the code is the one that requested it.
+	err = verifyCodeChallenge(dbCode.CodeChallenge, params.codeVerifier)
+	if err != nil {
+		return oauth2.Token{}, err
+	}
+
+	return issueToken(ctx, db, issueTokenParams{
+		app:          app,
+		lifetimes:    lifetimes,
+		userID:       dbCode.UserID,
+		secretID:     secretID,
+		tokenName:    fmt.Sprintf("%s_%s_oauth_session_token", dbCode.UserID, app.ID),
+		refreshToken: true,
+		consume: func(ctx context.Context, tx database.Store) error {
+			err := tx.DeleteOAuth2ProviderAppCodeByID(ctx, dbCode.ID)
+			if err != nil {
+				return xerrors.Errorf("delete oauth2 app code: %w", err)
+			}
+			return nil
+		},
+	})
+}
+
+func clientCredentialsGrant(ctx context.Context, db database.Store, app database.OAuth2ProviderApp, lifetimes codersdk.SessionLifetime, params tokenParams) (oauth2.Token, error) {
+	// Only confidential apps an administrator has assigned a user to may use
+	// this grant, since there is no user to consent to it.
+	if app.PublicClient || !app.ClientCredentialsUserID.Valid {
+		return oauth2.Token{}, errUnauthorizedClient
+	}
+
+	secretID, err := authenticateClient(ctx, db, app, params.clientSecret)
+	if err != nil {
+		return oauth2.Token{}, err
+	}
+
+	userID := app.ClientCredentialsUserID.UUID
+	return issueToken(ctx, db, issueTokenParams{
+		app:       app,
+		lifetimes: lifetimes,
+		userID:    userID,
+		secretID:  secretID,
+		tokenName: fmt.Sprintf("%s_%s_oauth_client_credentials_token", userID, app.ID),
+		// The client can always request a new token with its credentials, so
+		// RFC 6749 section 4.4.3 recommends against issuing a refresh token.
+		refreshToken: false,
+	})
+}
+
+func deviceCodeGrant(ctx context.Context, db database.Store, app database.OAuth2ProviderApp, lifetimes codersdk.SessionLifetime, params tokenParams) (oauth2.Token, error) {
+	secretID, err := authenticateClient(ctx, db, app, params.clientSecret)
+	if err != nil {
+		return oauth2.Token{}, err
+	}
+
+	// Validate the device code.
+	code, err := parseSecret(params.deviceCode)
+	if err != nil {
+		return oauth2.Token{}, errBadCode
+	}
+	//nolint:gocritic // Device codes are not owned by a user.
+	dbCode, err := db.GetOAuth2ProviderDeviceCodeByPrefix(dbauthz.AsSystemRestricted(ctx), []byte(code.prefix))
+	if errors.Is(err, sql.ErrNoRows) {
+		return oauth2.Token{}, errBadCode
+	}
+	if err != nil {
+		return oauth2.Token{}, err
+	}
+	equal, err := userpassword.Compare(string(dbCode.HashedSecret), code.secret)
+	if err != nil {
+		return oauth2.Token{}, xerrors.Errorf("unable to compare code: %w", err)
+	}
+	if !equal || dbCode.AppID != app.ID {
+		return oauth2.Token{}, errBadCode
+	}
+
+	if dbCode.ExpiresAt.Before(dbtime.Now()) {
+		return oauth2.Token{}, errExpiredToken
+	}
+
+	switch dbCode.Status {
+	case database.OAuth2ProviderDeviceCodeStatusPending:
+		now := dbtime.Now()
+		//nolint:gocritic // Device codes are not owned by a user.
+		err = db.UpdateOAuth2ProviderDeviceCodeLastPolledAtByID(dbauthz.AsSystemRestricted(ctx), database.UpdateOAuth2ProviderDeviceCodeLastPolledAtByIDParams{
+			ID:           dbCode.ID,
+			LastPolledAt: sql.NullTime{Time: now, Valid: true},
+		})
+		if err != nil {
+			return oauth2.Token{}, xerrors.Errorf("update device code last polled at: %w", err)
+		}
+		// Allow for some jitter, since the device's polls will never arrive at
+		// exactly the interval.
+		if dbCode.LastPolledAt.Valid && now.Sub(dbCode.LastPolledAt.Time) < DeviceCodePollInterval-time.Second {
+			return oauth2.Token{}, errSlowDown
+		}
+		return oauth2.Token{}, errAuthorizationPending
+	case database.OAuth2ProviderDeviceCodeStatusDenied:
+		//nolint:gocritic // Device codes are not owned by a user.
+		err = db.DeleteOAuth2ProviderDeviceCodeByID(dbauthz.AsSystemRestricted(ctx), dbCode.ID)
+		if err != nil {
+			return oauth2.Token{}, xerrors.Errorf("delete device code: %w", err)
+		}
+		return oauth2.Token{}, errAccessDenied
+	}
+
+	userID := dbCode.UserID.UUID
+	return issueToken(ctx, db, issueTokenParams{
+		app:          app,
+		lifetimes:    lifetimes,
+		userID:       userID,
+		secretID:     secretID,
+		tokenName:    fmt.Sprintf("%s_%s_oauth_session_token", userID, app.ID),
+		refreshToken: true,
+		consume: func(ctx context.Context, tx database.Store) error {
+			//nolint:gocritic // Device codes are not owned by a user.
+			err := tx.DeleteOAuth2ProviderDeviceCodeByID(dbauthz.AsSystemRestricted(ctx), dbCode.ID)
+			if err != nil {
+				return xerrors.Errorf("delete device code: %w", err)
+			}
+			return nil
+		},
+	})
+}
+
+type issueTokenParams struct {
+	app       database.OAuth2ProviderApp
+	lifetimes codersdk.SessionLifetime
+	// userID is the user the token acts as.
+	userID uuid.UUID
+	// secretID is the secret the client authenticated with, if any.
+	secretID uuid.NullUUID
+	// tokenName is unique per app and user, any previous token with the same
+	// name is replaced.
+	tokenName    string
+	refreshToken bool
+	// consume is called within the transaction to invalidate the grant that was
+	// exchanged for the token, so it cannot be used twice.
+	consume func(ctx context.Context, tx database.Store) error
+}
+
+// issueToken generates a new API key for a validated grant and records it
+// against the app so it can be refreshed and revoked.
+func issueToken(ctx context.Context, db database.Store, params issueTokenParams) (oauth2.Token, error) {
+	// Generate a refresh token.  Even when the client does not get one a token
+	// row is still needed so that the app's access can be revoked.
 	refreshToken, err := GenerateSecret()
 	if err != nil {
 		return oauth2.Token{}, err
 	}
 
-	// Generate the API key we will swap for the code.
+	// Generate the API key we will swap for the grant.
 	// TODO: We are ignoring scopes for now.
-	tokenName := fmt.Sprintf("%s_%s_oauth_session_token", dbCode.UserID, app.ID)
 	key, sessionToken, err := apikey.Generate(apikey.CreateParams{
-		UserID:          dbCode.UserID,
+		UserID:          params.userID,
 		LoginType:       database.LoginTypeOAuth2ProviderApp,
-		DefaultLifetime: lifetimes.DefaultDuration.Value(),
+		DefaultLifetime: params.lifetimes.DefaultDuration.Value(),
 		// For now, we allow only one token per app and user at a time.
-		TokenName: tokenName,
+		TokenName: params.tokenName,
 	})
 	if err != nil {
 		return oauth2.Token{}, err
 	}
 
 	// Grab the user roles so we can perform the exchange as the user.
-	actor, _, err := httpmw.UserRBACSubject(ctx, db, dbCode.UserID, rbac.ScopeAll)
+	actor, _, err := httpmw.UserRBACSubject(ctx, db, params.userID, rbac.ScopeAll)
 	if err != nil {
 		return oauth2.Token{}, xerrors.Errorf("fetch user actor: %w", err)
 	}
@@ -217,15 +477,17 @@ func authorizationCodeGrant(ctx context.Context, db database.Store, app database
 	// Do the actual token exchange in the database.
 	err = db.InTx(func(tx database.Store) error {
 		ctx := dbauthz.As(ctx, actor)
-		err = tx.DeleteOAuth2ProviderAppCodeByID(ctx, dbCode.ID)
-		if err != nil {
-			return xerrors.Errorf("delete oauth2 app code: %w", err)
+		if params.consume != nil {
+			err := params.consume(ctx, tx)
+			if err != nil {
+				return err
+			}
 		}
 
 		// Delete the previous key, if any.
 		prevKey, err := tx.GetAPIKeyByName(ctx, database.GetAPIKeyByNameParams{
-			UserID:    dbCode.UserID,
-			TokenName: tokenName,
+			UserID:    params.userID,
+			TokenName: params.tokenName,
 		})
 		if err == nil {
 			err = tx.DeleteAPIKeyByID(ctx, prevKey.ID)
@@ -245,8 +507,9 @@ func authorizationCodeGrant(ctx context.Context, db database.Store, app database
 			ExpiresAt:   key.ExpiresAt,
 			HashPrefix:  []byte(refreshToken.Prefix),
 			RefreshHash: []byte(refreshToken.Hashed),
-			AppSecretID: dbSecret.ID,
+			AppSecretID: params.secretID,
 			APIKeyID:    newKey.ID,
+			AppID:       params.app.ID,
 		})
 		if err != nil {
 			return xerrors.Errorf("insert oauth2 refresh token: %w", err)
@@ -257,12 +520,15 @@ func authorizationCodeGrant(ctx context.Context, db database.Store, app database
 		return oauth2.Token{}, err
 	}
 
-	return oauth2.Token{
-		AccessToken:  sessionToken,
-		TokenType:    "Bearer",
-		RefreshToken: refreshToken.Formatted,
-		Expiry:       key.ExpiresAt,
-	}, nil
+	token := oauth2.Token{
+		AccessToken: sessionToken,
+		TokenType:   "Bearer",
+		Expiry:      key.ExpiresAt,
+	}
+	if params.refreshToken {
+		token.RefreshToken = refreshToken.Formatted
+	}
+	return token, nil
 }
 
 func refreshTokenGrant(ctx context.Context, db database.Store, app database.OAuth2ProviderApp, lifetimes codersdk.SessionLifetime, params tokenParams) (oauth2.Token, error) {
@@ -283,7 +549,7 @@ func refreshTokenGrant(ctx context.Context, db database.Store, app database.OAut
 	if err != nil {
 		return oauth2.Token{}, xerrors.Errorf("unable to compare token: %w", err)
 	}
-	if !equal {
+	if !equal || dbToken.AppID != app.ID {
 		return oauth2.Token{}, errBadToken
 	}
 
@@ -345,6 +611,7 @@ func refreshTokenGrant(ctx context.Context, db database.Store, app database.OAut
 			RefreshHash: []byte(refreshToken.Hashed),
 			AppSecretID: dbToken.AppSecretID,
 			APIKeyID:    newKey.ID,
+			AppID:       app.ID,
 		})
 		if err != nil {
 			return xerrors.Errorf("insert oauth2 refresh token: %w", err)
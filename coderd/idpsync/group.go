@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
@@ -162,6 +163,12 @@ func (s AGPLIDPSync) SyncGroups(ctx context.Context, db database.Store, user dat
 			// determine if we have to do any group updates to sync the user's
 			// state.
 			existingGroups := userOrgs[orgID]
+			// Membership of groups provisioned over SCIM is owned by the
+			// identity provider's SCIM client, so never remove users from
+			// them here. HandleMissingGroups excludes them from additions.
+			existingGroups = slices.DeleteFunc(slices.Clone(existingGroups), func(g database.GetGroupsRow) bool {
+				return g.Group.Source == database.GroupSourceScim
+			})
 			existingGroupsTyped := db2sdk.List(existingGroups, func(f database.GetGroupsRow) ExpectedGroup {
 				return ExpectedGroup{
 					OrganizationID: orgID,
@@ -402,7 +409,37 @@ func (s GroupSyncSettings) HandleMissingGroups(ctx context.Context, tx database.
 		}
 	}
 
-	return addIDs, nil
+	return excludeSCIMGroups(ctx, tx, orgID, addIDs)
+}
+
+// excludeSCIMGroups removes the groups provisioned over SCIM from groupIDs.
+// Their membership is owned by the identity provider's SCIM client, so group
+// sync must neither add users to them nor remove users from them.
+func excludeSCIMGroups(ctx context.Context, tx database.Store, orgID uuid.UUID, groupIDs []uuid.UUID) ([]uuid.UUID, error) {
+	if len(groupIDs) == 0 {
+		return groupIDs, nil
+	}
+
+	groups, err := tx.GetGroups(ctx, database.GetGroupsParams{
+		OrganizationID: orgID,
+		GroupIds:       groupIDs,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("get groups by ids: %w", err)
+	}
+	scimGroups := make(map[uuid.UUID]struct{})
+	for _, g := range groups {
+		if g.Group.Source == database.GroupSourceScim {
+			scimGroups[g.Group.ID] = struct{}{}
+		}
+	}
+	if len(scimGroups) == 0 {
+		return groupIDs, nil
+	}
+	return slices.DeleteFunc(groupIDs, func(id uuid.UUID) bool {
+		_, ok := scimGroups[id]
+		return ok
+	}), nil
 }
 
 func ConvertAllowList(allowList []string) map[string]struct{} {
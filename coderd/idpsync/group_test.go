@@ -430,6 +430,81 @@ func TestSyncDisabled(t *testing.T) {
 	def.Assert(t, orgID, db, user)
 }
 
+// TestSyncSCIMGroups ensures group sync never changes the membership of
+// groups provisioned over SCIM.
+func TestSyncSCIMGroups(t *testing.T) {
+	t.Parallel()
+
+	if dbtestutil.WillUsePostgres() {
+		t.Skip("Skipping test because it populates a lot of db entries, which is slow on postgres.")
+	}
+
+	db, _ := dbtestutil.NewDB(t)
+	manager := runtimeconfig.NewManager()
+	s := idpsync.NewAGPLSync(slogtest.Make(t, &slogtest.Options{}),
+		manager,
+		idpsync.DeploymentSyncSettings{},
+	)
+
+	ids := coderdtest.NewDeterministicUUIDGenerator()
+	ctx := testutil.Context(t, testutil.WaitSuperLong)
+	user := dbgen.User(t, db, database.User{})
+	orgID := uuid.New()
+
+	def := orgSetupDefinition{
+		Name: "SCIMGroups",
+		Groups: map[uuid.UUID]bool{
+			ids.ID("oidc"): false,
+		},
+		GroupSettings: &codersdk.GroupSyncSettings{
+			Field:             "groups",
+			AutoCreateMissing: true,
+		},
+	}
+	SetupOrganization(t, s, db, user, orgID, def)
+
+	scimGroups, err := db.InsertMissingGroups(ctx, database.InsertMissingGroupsParams{
+		OrganizationID: orgID,
+		Source:         database.GroupSourceScim,
+		GroupNames:     []string{"scim-member", "scim-by-name", "scim-by-id"},
+	})
+	require.NoError(t, err)
+	require.Len(t, scimGroups, 3)
+	groupIDs := make(map[string]uuid.UUID)
+	for _, g := range scimGroups {
+		groupIDs[g.Name] = g.ID
+	}
+	dbgen.GroupMember(t, db, database.GroupMemberTable{
+		UserID:  user.ID,
+		GroupID: groupIDs["scim-member"],
+	})
+
+	// Map a claim to a SCIM group by ID, and another by name.
+	def.GroupSettings.Mapping = map[string][]uuid.UUID{
+		"oidc":     {ids.ID("oidc")},
+		"platform": {groupIDs["scim-by-id"]},
+	}
+	err = s.Group.SetRuntimeValue(ctx, manager.OrganizationResolver(db, orgID), (*idpsync.GroupSyncSettings)(def.GroupSettings))
+	require.NoError(t, err)
+
+	err = s.SyncGroups(ctx, db, user, idpsync.GroupParams{
+		SyncEntitled: true,
+		MergedClaims: jwt.MapClaims{
+			"groups": []string{"oidc", "platform", "scim-by-name"},
+		},
+	})
+	require.NoError(t, err)
+
+	// The user joined the OIDC group and stayed in the SCIM group they were
+	// a member of, but wasn't added to the other SCIM groups.
+	orgGroupAssert{
+		ExpectedGroups: []uuid.UUID{
+			ids.ID("oidc"),
+			groupIDs["scim-member"],
+		},
+	}.Assert(t, orgID, db, user)
+}
+
 // TestApplyGroupDifference is mainly testing the database functions
 func TestApplyGroupDifference(t *testing.T) {
 	t.Parallel()
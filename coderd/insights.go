@@ -14,6 +14,7 @@ import (
 	"golang.org/x/exp/slices"
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/xerrors"
+	"tailscale.com/tailcfg"
 
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
@@ -533,6 +534,261 @@ func (api *API) insightsTemplates(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusOK, resp)
 }
 
+// @Summary Get insights about workspace costs
+// @ID get-insights-about-workspace-costs
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Insights
+// @Param start_time query string true "Start time" format(date-time)
+// @Param end_time query string true "End time" format(date-time)
+// @Param interval query string false "Interval" enums(week,day)
+// @Param template_ids query []string false "Template IDs" collectionFormat(csv)
+// @Param group_by query string false "Group by" enums(template,user,group)
+// @Success 200 {object} codersdk.CostInsightsResponse
+// @Router /insights/costs [get]
+func (api *API) insightsCosts(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	p := httpapi.NewQueryParamParser().
+		RequiredNotEmpty("start_time").
+		RequiredNotEmpty("end_time")
+	vals := r.URL.Query()
+	var (
+		// The QueryParamParser does not preserve timezone, so we need
+		// to parse the time ourselves.
+		startTimeString = p.String(vals, "", "start_time")
+		endTimeString   = p.String(vals, "", "end_time")
+		intervalString  = p.String(vals, string(codersdk.InsightsReportIntervalDay), "interval")
+		templateIDs     = p.UUIDs(vals, []uuid.UUID{}, "template_ids")
+		groupByString   = p.String(vals, string(codersdk.CostInsightsGroupByTemplate), "group_by")
+	)
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	startTime, endTime, ok := parseInsightsStartAndEndTime(ctx, rw, time.Now(), startTimeString, endTimeString)
+	if !ok {
+		return
+	}
+	interval, ok := parseInsightsInterval(ctx, rw, intervalString, startTime, endTime)
+	if !ok {
+		return
+	}
+	if interval == "" {
+		interval = codersdk.InsightsReportIntervalDay
+	}
+	groupBy := codersdk.CostInsightsGroupBy(groupByString)
+	switch groupBy {
+	case codersdk.CostInsightsGroupByTemplate, codersdk.CostInsightsGroupByUser, codersdk.CostInsightsGroupByGroup:
+	default:
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Query parameter has invalid value.",
+			Validations: []codersdk.ValidationError{
+				{
+					Field:  "group_by",
+					Detail: fmt.Sprintf("must be one of %v", []codersdk.CostInsightsGroupBy{codersdk.CostInsightsGroupByTemplate, codersdk.CostInsightsGroupByUser, codersdk.CostInsightsGroupByGroup}),
+				},
+			},
+		})
+		return
+	}
+
+	var intervalCosts []database.GetWorkspaceCostInsightsByIntervalRow
+	breakdown := []codersdk.CostBreakdown{}
+
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(2)
+
+	eg.Go(func() error {
+		var err error
+		intervalCosts, err = api.Database.GetWorkspaceCostInsightsByInterval(egCtx, database.GetWorkspaceCostInsightsByIntervalParams{
+			StartTime:    startTime,
+			EndTime:      endTime,
+			TemplateIDs:  templateIDs,
+			IntervalDays: interval.Days(),
+		})
+		if err != nil {
+			return xerrors.Errorf("get workspace cost insights by interval: %w", err)
+		}
+		return nil
+	})
+	eg.Go(func() error {
+		switch groupBy {
+		case codersdk.CostInsightsGroupByTemplate:
+			rows, err := api.Database.GetWorkspaceCostInsightsByTemplate(egCtx, database.GetWorkspaceCostInsightsByTemplateParams{
+				StartTime:   startTime,
+				EndTime:     endTime,
+				TemplateIDs: templateIDs,
+			})
+			if err != nil {
+				return xerrors.Errorf("get workspace cost insights by template: %w", err)
+			}
+			for _, row := range rows {
+				breakdown = append(breakdown, codersdk.CostBreakdown{ID: row.TemplateID, Name: row.TemplateName, Cost: row.Cost})
+			}
+		case codersdk.CostInsightsGroupByUser:
+			rows, err := api.Database.GetWorkspaceCostInsightsByUser(egCtx, database.GetWorkspaceCostInsightsByUserParams{
+				StartTime:   startTime,
+				EndTime:     endTime,
+				TemplateIDs: templateIDs,
+			})
+			if err != nil {
+				return xerrors.Errorf("get workspace cost insights by user: %w", err)
+			}
+			for _, row := range rows {
+				breakdown = append(breakdown, codersdk.CostBreakdown{ID: row.UserID, Name: row.Username, Cost: row.Cost})
+			}
+		case codersdk.CostInsightsGroupByGroup:
+			rows, err := api.Database.GetWorkspaceCostInsightsByGroup(egCtx, database.GetWorkspaceCostInsightsByGroupParams{
+				StartTime:   startTime,
+				EndTime:     endTime,
+				TemplateIDs: templateIDs,
+			})
+			if err != nil {
+				return xerrors.Errorf("get workspace cost insights by group: %w", err)
+			}
+			for _, row := range rows {
+				breakdown = append(breakdown, codersdk.CostBreakdown{ID: row.GroupID, Name: row.GroupName, Cost: row.Cost})
+			}
+		}
+		return nil
+	})
+
+	err := eg.Wait()
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching cost insights.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	resp := codersdk.CostInsightsResponse{
+		Report: codersdk.CostInsightsReport{
+			StartTime:   startTime,
+			EndTime:     endTime,
+			TemplateIDs: templateIDs,
+			GroupBy:     groupBy,
+			Breakdown:   breakdown,
+		},
+		IntervalReports: make([]codersdk.CostInsightsIntervalReport, 0, len(intervalCosts)),
+	}
+	for _, row := range intervalCosts {
+		// Breakdowns by group count a workspace once per group, so the total
+		// is derived from the intervals instead.
+		resp.Report.TotalCost += row.Cost
+		resp.IntervalReports = append(resp.IntervalReports, codersdk.CostInsightsIntervalReport{
+			StartTime: row.StartTime.In(startTime.Location()),
+			EndTime:   row.EndTime.In(startTime.Location()),
+			Interval:  interval,
+			Cost:      row.Cost,
+		})
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// networkInsightsWindow is how far back network reports are included in
+// network insights. Older reports are purged.
+const networkInsightsWindow = 24 * time.Hour
+
+// @Summary Get insights about the network
+// @ID get-insights-about-the-network
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Insights
+// @Param client_types query []string false "Client types" collectionFormat(csv) enums(agent,cli,coderd,wsproxy)
+// @Success 200 {object} codersdk.NetworkInsightsResponse
+// @Router /insights/network [get]
+func (api *API) insightsNetwork(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	p := httpapi.NewQueryParamParser()
+	vals := r.URL.Query()
+	clientTypes := httpapi.ParseCustomList(p, vals, []codersdk.NetworkClientType{}, "client_types", httpapi.ParseEnum[codersdk.NetworkClientType])
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	reportedAfter := dbtime.Now().Add(-networkInsightsWindow)
+	rows, err := api.Database.GetNetworkRegionInsights(ctx, database.GetNetworkRegionInsightsParams{
+		ReportedAfter: reportedAfter,
+		ClientTypes:   slice.ToStrings(clientTypes),
+	})
+	if err != nil {
+		if httpapi.IsUnauthorizedError(err) {
+			httpapi.Forbidden(rw)
+			return
+		}
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching network insights.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	resp := codersdk.NetworkInsightsResponse{
+		ReportedAfter: reportedAfter,
+		ClientTypes:   clientTypes,
+		Regions:       convertNetworkInsightsRegions(api.DERPMap(), rows),
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// convertNetworkInsightsRegions returns the insights of every region of the
+// DERP map, including the ones no client reported about, and of the regions
+// clients reported about that are no longer in the DERP map.
+func convertNetworkInsightsRegions(derpMap *tailcfg.DERPMap, rows []database.GetNetworkRegionInsightsRow) []codersdk.NetworkInsightsRegion {
+	regions := map[int]codersdk.NetworkInsightsRegion{}
+	if derpMap != nil {
+		for id, region := range derpMap.Regions {
+			if region == nil {
+				continue
+			}
+			regions[id] = codersdk.NetworkInsightsRegion{
+				RegionID:     id,
+				RegionCode:   region.RegionCode,
+				RegionName:   region.RegionName,
+				LatencyP50Ms: -1,
+				LatencyP95Ms: -1,
+			}
+		}
+	}
+	for _, row := range rows {
+		region := regions[int(row.RegionID)]
+		region.RegionID = int(row.RegionID)
+		region.Clients = row.Clients
+		region.PreferredClients = row.PreferredClients
+		region.LatencyP50Ms = row.Latency50
+		region.LatencyP95Ms = row.Latency95
+		region.DirectConnections = row.DirectConnections
+		region.RelayedConnections = row.RelayedConnections
+		regions[region.RegionID] = region
+	}
+
+	result := make([]codersdk.NetworkInsightsRegion, 0, len(regions))
+	for _, region := range regions {
+		result = append(result, region)
+	}
+	slices.SortFunc(result, func(a, b codersdk.NetworkInsightsRegion) int {
+		return a.RegionID - b.RegionID
+	})
+	return result
+}
+
 // convertTemplateInsightsApps builds the list of builtin apps and template apps
 // from the provided database rows, builtin apps are implicitly a part of all
 // templates.
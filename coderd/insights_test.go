@@ -2,6 +2,7 @@ package coderd_test
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -28,6 +29,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/database/dbrollup"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/workspaceapps"
 	"github.com/coder/coder/v2/coderd/workspacestats"
@@ -2140,6 +2142,174 @@ func TestTemplateInsights_BadRequest(t *testing.T) {
 	assert.Error(t, err, "want error for bad section")
 }
 
+func TestCostInsights(t *testing.T) {
+	t.Parallel()
+
+	db, ps := dbtestutil.NewDB(t)
+	client := coderdtest.New(t, &coderdtest.Options{Database: db, Pubsub: ps})
+	owner := coderdtest.CreateFirstUser(t, client)
+
+	now := dbtime.Now()
+	started := now.Add(-90 * time.Minute)
+
+	var (
+		tpl = dbgen.Template(t, db, database.Template{OrganizationID: owner.OrganizationID, CreatedBy: owner.UserID})
+		ver = dbgen.TemplateVersion(t, db, database.TemplateVersion{OrganizationID: owner.OrganizationID, TemplateID: uuid.NullUUID{UUID: tpl.ID, Valid: true}, CreatedBy: owner.UserID})
+		ws  = dbgen.Workspace(t, db, database.WorkspaceTable{OrganizationID: owner.OrganizationID, TemplateID: tpl.ID, OwnerID: owner.UserID})
+		job = dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+			OrganizationID: owner.OrganizationID,
+			StartedAt:      sql.NullTime{Time: started.Add(-time.Minute), Valid: true},
+			CompletedAt:    sql.NullTime{Time: started, Valid: true},
+		})
+		build = dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: job.ID, TemplateVersionID: ver.ID})
+	)
+	_ = dbgen.WorkspaceResource(t, db, database.WorkspaceResource{JobID: build.JobID, HourlyCost: 2})
+
+	// A workspace that was built before costs are backfilled accrues the cost
+	// of its latest build only.
+	oldWs := dbgen.Workspace(t, db, database.WorkspaceTable{OrganizationID: owner.OrganizationID, TemplateID: tpl.ID, OwnerID: owner.UserID})
+	for i, cost := range []float64{100, 1} {
+		completed := now.Add(time.Duration(i-4) * 24 * time.Hour)
+		oldJob := dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+			OrganizationID: owner.OrganizationID,
+			StartedAt:      sql.NullTime{Time: completed.Add(-time.Minute), Valid: true},
+			CompletedAt:    sql.NullTime{Time: completed, Valid: true},
+		})
+		oldBuild := dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{WorkspaceID: oldWs.ID, JobID: oldJob.ID, TemplateVersionID: ver.ID, BuildNumber: int32(i + 1)})
+		_ = dbgen.WorkspaceResource(t, db, database.WorkspaceResource{JobID: oldBuild.JobID, HourlyCost: cost})
+	}
+	// Costs are backfilled from the start of the hour a day ago.
+	oldWsCost := now.Sub(now.Add(-24 * time.Hour).Truncate(time.Hour)).Hours()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	err := db.UpsertWorkspaceCosts(ctx)
+	require.NoError(t, err)
+
+	y, m, d := now.UTC().Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	req := codersdk.CostInsightsRequest{
+		StartTime: today.AddDate(0, 0, -2),
+		EndTime:   now.UTC().Truncate(time.Hour).Add(time.Hour),
+		Interval:  codersdk.InsightsReportIntervalDay,
+	}
+
+	resp, err := client.CostInsights(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, codersdk.CostInsightsGroupByTemplate, resp.Report.GroupBy)
+	// The workspace has been running for 1.5 hours at 2 per hour.
+	require.InDelta(t, 3+oldWsCost, resp.Report.TotalCost, 0.1)
+	require.Len(t, resp.Report.Breakdown, 1)
+	require.Equal(t, tpl.ID, resp.Report.Breakdown[0].ID)
+	require.Equal(t, tpl.Name, resp.Report.Breakdown[0].Name)
+	require.Len(t, resp.IntervalReports, 3)
+
+	req.GroupBy = codersdk.CostInsightsGroupByUser
+	resp, err = client.CostInsights(ctx, req)
+	require.NoError(t, err)
+	require.Len(t, resp.Report.Breakdown, 1)
+	require.Equal(t, owner.UserID, resp.Report.Breakdown[0].ID)
+	require.InDelta(t, 3+oldWsCost, resp.Report.Breakdown[0].Cost, 0.1)
+
+	req.GroupBy = "invalid"
+	_, err = client.CostInsights(ctx, req)
+	assert.Error(t, err, "want error for bad group by")
+}
+
+func TestNetworkInsights(t *testing.T) {
+	t.Parallel()
+
+	db, ps := dbtestutil.NewDB(t)
+	client, _, api := coderdtest.NewWithAPI(t, &coderdtest.Options{Database: db, Pubsub: ps})
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	derpMap := api.DERPMap()
+	require.NotEmpty(t, derpMap.Regions)
+	region := int32(derpMap.RegionIDs()[0])
+	// Reports about regions that are no longer in the DERP map are included
+	// too.
+	const removedRegion = 9999
+	findRegion := func(t *testing.T, regions []codersdk.NetworkInsightsRegion, id int32) codersdk.NetworkInsightsRegion {
+		t.Helper()
+		for _, r := range regions {
+			if r.RegionID == int(id) {
+				return r
+			}
+		}
+		require.FailNow(t, "region not found", "region %d", id)
+		return codersdk.NetworkInsightsRegion{}
+	}
+
+	now := dbtime.Now()
+	var (
+		agent = uuid.New()
+		cli   = uuid.New()
+		stale = uuid.New()
+	)
+	err := db.UpsertNetworkLatencyReports(ctx, database.UpsertNetworkLatencyReportsParams{
+		ClientID:   []uuid.UUID{agent, cli, stale, agent},
+		ClientType: []string{"agent", "cli", "cli", "agent"},
+		RegionID:   []int32{region, region, region, removedRegion},
+		LatencyMS:  []float64{10, 30, 100, 50},
+		Preferred:  []bool{true, false, true, false},
+		ReportedAt: []time.Time{now, now, now.Add(-48 * time.Hour), now},
+	})
+	require.NoError(t, err)
+	err = db.UpsertNetworkConnectionReports(ctx, database.UpsertNetworkConnectionReportsParams{
+		ClientID:     []uuid.UUID{agent, agent, cli},
+		RemoteNodeID: []int64{1, 2, 3},
+		ClientType:   []string{"agent", "agent", "cli"},
+		HomeRegionID: []int32{region, region, region},
+		Direct:       []bool{true, false, true},
+		ReportedAt:   []time.Time{now, now, now},
+	})
+	require.NoError(t, err)
+
+	resp, err := client.NetworkInsights(ctx, codersdk.NetworkInsightsRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.Regions, len(derpMap.Regions)+1)
+	got := findRegion(t, resp.Regions, region)
+	require.Equal(t, derpMap.Regions[int(region)].RegionCode, got.RegionCode)
+	require.EqualValues(t, 2, got.Clients)
+	require.EqualValues(t, 1, got.PreferredClients)
+	require.InDelta(t, 20, got.LatencyP50Ms, 0.01)
+	require.EqualValues(t, 2, got.DirectConnections)
+	require.EqualValues(t, 1, got.RelayedConnections)
+	got = findRegion(t, resp.Regions, removedRegion)
+	require.Empty(t, got.RegionCode)
+	require.EqualValues(t, 1, got.Clients)
+
+	resp, err = client.NetworkInsights(ctx, codersdk.NetworkInsightsRequest{
+		ClientTypes: []codersdk.NetworkClientType{codersdk.NetworkClientTypeCLI},
+	})
+	require.NoError(t, err)
+	got = findRegion(t, resp.Regions, region)
+	require.EqualValues(t, 1, got.Clients)
+	require.InDelta(t, 30, got.LatencyP50Ms, 0.01)
+	require.EqualValues(t, 1, got.DirectConnections)
+	require.EqualValues(t, 0, got.RelayedConnections)
+	// Regions in the DERP map are included even without reports.
+	require.Len(t, resp.Regions, len(derpMap.Regions))
+	for _, r := range resp.Regions {
+		if r.RegionID != int(region) {
+			require.EqualValues(t, 0, r.Clients)
+			require.EqualValues(t, -1, r.LatencyP50Ms)
+		}
+	}
+
+	_, err = client.NetworkInsights(ctx, codersdk.NetworkInsightsRequest{
+		ClientTypes: []codersdk.NetworkClientType{"invalid"},
+	})
+	var sdkErr *codersdk.Error
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+
+	_, err = member.NetworkInsights(ctx, codersdk.NetworkInsightsRequest{})
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, http.StatusForbidden, sdkErr.StatusCode())
+}
+
 func TestTemplateInsights_RBAC(t *testing.T) {
 	t.Parallel()
 
@@ -2,6 +2,7 @@ package jwtutils
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"encoding/json"
 	"time"
 
@@ -39,8 +40,19 @@ type Claims interface {
 
 const (
 	SigningAlgo = jose.HS512
+	// AsymmetricSigningAlgo is used for tokens signed with ECDSA keys, which
+	// can be verified by third parties.
+	AsymmetricSigningAlgo = jose.ES256
 )
 
+// signingAlgorithm returns the algorithm used to sign with the provided key.
+func signingAlgorithm(key interface{}) jose.SignatureAlgorithm {
+	if _, ok := key.(*ecdsa.PrivateKey); ok {
+		return AsymmetricSigningAlgo
+	}
+	return SigningAlgo
+}
+
 type SigningKeyManager interface {
 	SigningKeyProvider
 	VerifyKeyProvider
@@ -62,7 +74,7 @@ func Sign(ctx context.Context, s SigningKeyProvider, claims Claims) (string, err
 	}
 
 	signer, err := jose.NewSigner(jose.SigningKey{
-		Algorithm: SigningAlgo,
+		Algorithm: signingAlgorithm(key),
 		Key:       key,
 	}, &jose.SignerOptions{
 		ExtraHeaders: map[jose.HeaderKey]interface{}{
@@ -103,6 +115,12 @@ func WithVerifyExpected(expected jwt.Expected) func(*VerifyOptions) {
 	}
 }
 
+func WithVerifySignatureAlgorithm(algorithm jose.SignatureAlgorithm) func(*VerifyOptions) {
+	return func(opts *VerifyOptions) {
+		opts.SignatureAlgorithm = algorithm
+	}
+}
+
 // Verify verifies that a token was signed by the provided key. It unmarshals into the provided claims.
 func Verify(ctx context.Context, v VerifyKeyProvider, token string, claims Claims, opts ...func(*VerifyOptions)) error {
 	options := VerifyOptions{
@@ -127,8 +145,8 @@ func Verify(ctx context.Context, v VerifyKeyProvider, token string, claims Claim
 
 	signature := object.Signatures[0]
 
-	if signature.Header.Algorithm != string(SigningAlgo) {
-		return xerrors.Errorf("expected JWS algorithm to be %q, got %q", SigningAlgo, object.Signatures[0].Header.Algorithm)
+	if signature.Header.Algorithm != string(options.SignatureAlgorithm) {
+		return xerrors.Errorf("expected JWS algorithm to be %q, got %q", options.SignatureAlgorithm, object.Signatures[0].Header.Algorithm)
 	}
 
 	kid := signature.Header.KeyID
@@ -259,6 +259,44 @@ func TestJWS(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, claims, actual)
 	})
+
+	t.Run("WithAsymmetricKeycache", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx   = testutil.Context(t, testutil.WaitShort)
+			db, _ = dbtestutil.NewDB(t)
+			_     = dbgen.CryptoKey(t, db, database.CryptoKey{
+				Feature:  database.CryptoKeyFeatureWorkloadIdentity,
+				StartsAt: time.Now(),
+			})
+			log     = testutil.Logger(t)
+			fetcher = &cryptokeys.DBFetcher{DB: db}
+		)
+
+		cache, err := cryptokeys.NewSigningCache(ctx, log, fetcher, codersdk.CryptoKeyFeatureWorkloadIdentity)
+		require.NoError(t, err)
+
+		claims := testClaims{
+			MyClaim: "my_value",
+			Claims: jwt.Claims{
+				Expiry: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			},
+		}
+
+		token, err := jwtutils.Sign(ctx, cache, claims)
+		require.NoError(t, err)
+
+		// Tokens signed with asymmetric keys are rejected unless the
+		// algorithm is expected.
+		var actual testClaims
+		err = jwtutils.Verify(ctx, cache, token, &actual)
+		require.Error(t, err)
+
+		err = jwtutils.Verify(ctx, cache, token, &actual, jwtutils.WithVerifySignatureAlgorithm(jwtutils.AsymmetricSigningAlgo))
+		require.NoError(t, err)
+		require.Equal(t, claims, actual)
+	})
 }
 
 func TestJWE(t *testing.T) {
@@ -0,0 +1,154 @@
+package coderd
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/telemetry"
+)
+
+// storeNetworkReports stores the DERP region latencies and the connection
+// types reported by tailnet clients in network telemetry events, which are
+// aggregated by network insights.
+func (api *API) storeNetworkReports(ctx context.Context, events []telemetry.NetworkEvent) error {
+	latencies, connections := networkReportsFromEvents(dbtime.Now(), events)
+	//nolint:gocritic // Network reports are stored on behalf of the clients.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	if len(latencies.ClientID) > 0 {
+		err := api.Database.UpsertNetworkLatencyReports(ctx, latencies)
+		if err != nil {
+			return xerrors.Errorf("upsert network latency reports: %w", err)
+		}
+	}
+	if len(connections.ClientID) > 0 {
+		err := api.Database.UpsertNetworkConnectionReports(ctx, connections)
+		if err != nil {
+			return xerrors.Errorf("upsert network connection reports: %w", err)
+		}
+	}
+	return nil
+}
+
+type networkLatencyReportKey struct {
+	clientID [16]byte
+	regionID int32
+}
+
+type networkConnectionReportKey struct {
+	clientID     [16]byte
+	remoteNodeID int64
+}
+
+// networkReportsFromEvents converts network telemetry events to the latest
+// latency to each DERP region and connection type reported by each client.
+// Events from the future are assumed to be reported now, so a client with a
+// skewed clock can't keep its reports from being purged.
+func networkReportsFromEvents(now time.Time, events []telemetry.NetworkEvent) (database.UpsertNetworkLatencyReportsParams, database.UpsertNetworkConnectionReportsParams) {
+	var (
+		latencies   = map[networkLatencyReportKey]database.NetworkLatencyReport{}
+		connections = map[networkConnectionReportKey]database.NetworkConnectionReport{}
+	)
+	for _, event := range events {
+		reportedAt := event.Time
+		if reportedAt.IsZero() || reportedAt.After(now) {
+			reportedAt = now
+		}
+
+		for regionID, latency := range networkEventRegionLatencies(event.LatestNetcheck) {
+			report := database.NetworkLatencyReport{
+				ClientID:   event.ID,
+				ClientType: event.ClientType,
+				RegionID:   int32(regionID),
+				LatencyMS:  float64(latency) / float64(time.Millisecond),
+				Preferred:  networkEventPreferredRegion(event) == regionID,
+				ReportedAt: reportedAt,
+			}
+			key := networkLatencyReportKey{clientID: event.ID, regionID: report.RegionID}
+			if existing, ok := latencies[key]; ok && existing.ReportedAt.After(report.ReportedAt) {
+				continue
+			}
+			latencies[key] = report
+		}
+
+		// Connections are reported by the home region of the client, as
+		// that's the region relayed connections go through.
+		if event.NodeIDRemote == 0 || event.HomeDERP == 0 || event.Status != "connected" {
+			continue
+		}
+		var direct bool
+		switch {
+		case event.P2PLatency != nil || event.P2PEndpoint.Hash != "":
+			direct = true
+		case event.DERPLatency != nil:
+			direct = false
+		default:
+			// The type of the connection isn't known yet.
+			continue
+		}
+		report := database.NetworkConnectionReport{
+			ClientID:     event.ID,
+			RemoteNodeID: int64(event.NodeIDRemote), //nolint:gosec // Only used as an identifier.
+			ClientType:   event.ClientType,
+			HomeRegionID: int32(event.HomeDERP), //nolint:gosec // DERP region IDs are small.
+			Direct:       direct,
+			ReportedAt:   reportedAt,
+		}
+		key := networkConnectionReportKey{clientID: event.ID, remoteNodeID: report.RemoteNodeID}
+		if existing, ok := connections[key]; ok && existing.ReportedAt.After(report.ReportedAt) {
+			continue
+		}
+		connections[key] = report
+	}
+
+	var latencyParams database.UpsertNetworkLatencyReportsParams
+	for _, report := range latencies {
+		latencyParams.ClientID = append(latencyParams.ClientID, report.ClientID)
+		latencyParams.ClientType = append(latencyParams.ClientType, report.ClientType)
+		latencyParams.RegionID = append(latencyParams.RegionID, report.RegionID)
+		latencyParams.LatencyMS = append(latencyParams.LatencyMS, report.LatencyMS)
+		latencyParams.Preferred = append(latencyParams.Preferred, report.Preferred)
+		latencyParams.ReportedAt = append(latencyParams.ReportedAt, report.ReportedAt)
+	}
+	var connectionParams database.UpsertNetworkConnectionReportsParams
+	for _, report := range connections {
+		connectionParams.ClientID = append(connectionParams.ClientID, report.ClientID)
+		connectionParams.RemoteNodeID = append(connectionParams.RemoteNodeID, report.RemoteNodeID)
+		connectionParams.ClientType = append(connectionParams.ClientType, report.ClientType)
+		connectionParams.HomeRegionID = append(connectionParams.HomeRegionID, report.HomeRegionID)
+		connectionParams.Direct = append(connectionParams.Direct, report.Direct)
+		connectionParams.ReportedAt = append(connectionParams.ReportedAt, report.ReportedAt)
+	}
+	return latencyParams, connectionParams
+}
+
+// networkEventRegionLatencies returns the lowest of the IPv4 and IPv6
+// latencies to each DERP region in a netcheck.
+func networkEventRegionLatencies(netcheck telemetry.Netcheck) map[int64]time.Duration {
+	latencies := make(map[int64]time.Duration, len(netcheck.RegionV4Latency))
+	for _, regionLatencies := range []map[int64]time.Duration{netcheck.RegionV4Latency, netcheck.RegionV6Latency} {
+		for regionID, latency := range regionLatencies {
+			if regionID <= 0 || latency <= 0 {
+				continue
+			}
+			if existing, ok := latencies[regionID]; ok && existing <= latency {
+				continue
+			}
+			latencies[regionID] = latency
+		}
+	}
+	return latencies
+}
+
+// networkEventPreferredRegion returns the region the client prefers, falling
+// back to its home region when the netcheck didn't pick one.
+func networkEventPreferredRegion(event telemetry.NetworkEvent) int64 {
+	if event.LatestNetcheck.PreferredDERP != 0 {
+		return event.LatestNetcheck.PreferredDERP
+	}
+	return int64(event.HomeDERP)
+}
@@ -0,0 +1,113 @@
+package coderd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/telemetry"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+)
+
+func TestNetworkReportsFromEvents(t *testing.T) {
+	t.Parallel()
+
+	now := dbtime.Now()
+	client := uuid.New()
+	netcheck := telemetry.Netcheck{
+		PreferredDERP:   1,
+		RegionV4Latency: map[int64]time.Duration{1: 20 * time.Millisecond, 2: 80 * time.Millisecond},
+		RegionV6Latency: map[int64]time.Duration{1: 10 * time.Millisecond},
+	}
+	events := []telemetry.NetworkEvent{
+		{
+			// An older event with a relayed connection to the same peer.
+			ID:             client,
+			Time:           now.Add(-time.Minute),
+			Status:         "connected",
+			ClientType:     "agent",
+			NodeIDRemote:   42,
+			HomeDERP:       1,
+			LatestNetcheck: netcheck,
+			DERPLatency:    ptr.Ref(50 * time.Millisecond),
+		},
+		{
+			ID:             client,
+			Time:           now,
+			Status:         "connected",
+			ClientType:     "agent",
+			NodeIDRemote:   42,
+			HomeDERP:       1,
+			LatestNetcheck: netcheck,
+			P2PLatency:     ptr.Ref(5 * time.Millisecond),
+		},
+		{
+			// The type of the connection isn't known yet.
+			ID:           client,
+			Time:         now,
+			Status:       "connected",
+			ClientType:   "agent",
+			NodeIDRemote: 43,
+			HomeDERP:     1,
+		},
+		{
+			// Disconnected peers aren't reported.
+			ID:           client,
+			Time:         now,
+			Status:       "disconnected",
+			ClientType:   "agent",
+			NodeIDRemote: 44,
+			HomeDERP:     1,
+			DERPLatency:  ptr.Ref(50 * time.Millisecond),
+		},
+		{
+			// Events from the future are reported now.
+			ID:           uuid.New(),
+			Time:         now.Add(time.Hour),
+			Status:       "connected",
+			ClientType:   "cli",
+			NodeIDRemote: 45,
+			HomeDERP:     2,
+			DERPLatency:  ptr.Ref(50 * time.Millisecond),
+		},
+	}
+
+	latencies, connections := networkReportsFromEvents(now, events)
+	require.Len(t, latencies.ClientID, 2)
+	for i, regionID := range latencies.RegionID {
+		require.Equal(t, client, latencies.ClientID[i])
+		require.Equal(t, "agent", latencies.ClientType[i])
+		require.Equal(t, now, latencies.ReportedAt[i])
+		switch regionID {
+		case 1:
+			// The lowest of the IPv4 and IPv6 latencies.
+			require.InDelta(t, 10, latencies.LatencyMS[i], 0.01)
+			require.True(t, latencies.Preferred[i])
+		case 2:
+			require.InDelta(t, 80, latencies.LatencyMS[i], 0.01)
+			require.False(t, latencies.Preferred[i])
+		default:
+			t.Fatalf("unexpected region %d", regionID)
+		}
+	}
+
+	require.Len(t, connections.ClientID, 2)
+	for i, remoteNodeID := range connections.RemoteNodeID {
+		require.Equal(t, now, connections.ReportedAt[i])
+		switch remoteNodeID {
+		case 42:
+			require.Equal(t, client, connections.ClientID[i])
+			require.EqualValues(t, 1, connections.HomeRegionID[i])
+			require.True(t, connections.Direct[i])
+		case 45:
+			require.Equal(t, "cli", connections.ClientType[i])
+			require.EqualValues(t, 2, connections.HomeRegionID[i])
+			require.False(t, connections.Direct[i])
+		default:
+			t.Fatalf("unexpected remote node %d", remoteNodeID)
+		}
+	}
+}
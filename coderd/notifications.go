@@ -3,7 +3,9 @@ package coderd
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/google/uuid"
 
@@ -238,6 +240,48 @@ func (api *API) putUserNotificationPreferences(rw http.ResponseWriter, r *http.R
 		input.Disableds = append(input.Disableds, disabled)
 	}
 
+	methodInput := database.UpdateUserNotificationMethodPreferencesParams{
+		UserID:                  user.ID,
+		NotificationTemplateIds: make([]uuid.UUID, 0, len(prefs.TemplateMethodMap)),
+		Methods:                 make([]string, 0, len(prefs.TemplateMethodMap)),
+	}
+	for tmplID, method := range prefs.TemplateMethodMap {
+		id, err := uuid.Parse(tmplID)
+		if err != nil {
+			logger.Warn(ctx, "failed to parse notification template UUID", slog.F("input", tmplID), slog.Error(err))
+
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Unable to parse notification template UUID.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+
+		if method != "" && !database.NotificationMethod(method).Valid() {
+			vals := database.AllNotificationMethodValues()
+			acceptable := make([]string, len(vals))
+			for i, v := range vals {
+				acceptable[i] = string(v)
+			}
+
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid request to update user notification preferences.",
+				Validations: []codersdk.ValidationError{
+					{
+						Field: "template_method_map",
+						Detail: fmt.Sprintf("%q is not a valid method; %s are the available options",
+							method, strings.Join(acceptable, ", "),
+						),
+					},
+				},
+			})
+			return
+		}
+
+		methodInput.NotificationTemplateIds = append(methodInput.NotificationTemplateIds, id)
+		methodInput.Methods = append(methodInput.Methods, method)
+	}
+
 	// Update preferences with params.
 	updated, err := api.Database.UpdateUserNotificationPreferences(ctx, input)
 	if err != nil {
@@ -250,6 +294,20 @@ func (api *API) putUserNotificationPreferences(rw http.ResponseWriter, r *http.R
 		return
 	}
 
+	if len(methodInput.NotificationTemplateIds) > 0 {
+		methodsUpdated, err := api.Database.UpdateUserNotificationMethodPreferences(ctx, methodInput)
+		if err != nil {
+			logger.Error(ctx, "failed to update method preferences", slog.Error(err))
+
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Failed to update user notifications preferences.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		updated += methodsUpdated
+	}
+
 	// Preferences updated, now fetch all preferences belonging to this user.
 	logger.Info(ctx, "updated preferences", slog.F("count", updated))
 
@@ -291,6 +349,7 @@ func convertNotificationPreferences(in []database.NotificationPreference) (out [
 		out = append(out, codersdk.NotificationPreference{
 			NotificationTemplateID: pref.NotificationTemplateID,
 			Disabled:               pref.Disabled,
+			Method:                 string(pref.Method.NotificationMethod),
 			UpdatedAt:              pref.UpdatedAt,
 		})
 	}
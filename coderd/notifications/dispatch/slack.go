@@ -0,0 +1,253 @@
+package dispatch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/notifications/types"
+	markdown "github.com/coder/coder/v2/coderd/render"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	// Slack rejects header blocks with text longer than this.
+	slackHeaderMaxLength = 150
+	// Slack rejects section blocks with text longer than this.
+	slackSectionMaxLength = 3000
+)
+
+// SlackHandler dispatches notification messages to Slack.
+//
+// If a bot token is configured, the recipient is looked up by their email address and messaged directly. Otherwise, the
+// message is posted to the configured incoming webhook.
+type SlackHandler struct {
+	cfg codersdk.NotificationsSlackConfig
+	log slog.Logger
+
+	cl *http.Client
+}
+
+// SlackMessage describes the JSON payload delivered to Slack, using Block Kit to render the notification.
+// See: https://api.slack.com/block-kit
+type SlackMessage struct {
+	// Channel is only used when sending direct messages via the Web API; incoming webhooks post to a fixed channel.
+	Channel string `json:"channel,omitempty"`
+	// Text is shown in push notifications, and by clients which cannot render blocks.
+	Text   string       `json:"text"`
+	Blocks []SlackBlock `json:"blocks"`
+}
+
+type SlackBlock struct {
+	Type     string         `json:"type"`
+	Text     *SlackText     `json:"text,omitempty"`
+	Elements []SlackElement `json:"elements,omitempty"`
+}
+
+type SlackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type SlackElement struct {
+	Type string    `json:"type"`
+	Text SlackText `json:"text"`
+	URL  string    `json:"url"`
+}
+
+// slackAPIResponse describes the fields we're interested in from Slack Web API responses.
+// The Web API returns 200 OK for most errors, so the "ok" field must be inspected.
+type slackAPIResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+	User  struct {
+		ID string `json:"id"`
+	} `json:"user"`
+}
+
+func NewSlackHandler(cfg codersdk.NotificationsSlackConfig, log slog.Logger) *SlackHandler {
+	return &SlackHandler{cfg: cfg, log: log, cl: &http.Client{}}
+}
+
+func (s *SlackHandler) Dispatcher(payload types.MessagePayload, titleMarkdown, bodyMarkdown string, _ template.FuncMap) (DeliveryFunc, error) {
+	if !s.cfg.Enabled() {
+		return nil, xerrors.New("slack webhook URL or bot token not defined")
+	}
+
+	titlePlaintext, err := markdown.PlaintextFromMarkdown(titleMarkdown)
+	if err != nil {
+		return nil, xerrors.Errorf("render title: %w", err)
+	}
+	bodyMrkdwn := markdown.SlackMrkdwnFromMarkdown(bodyMarkdown)
+
+	blocks := []SlackBlock{
+		{
+			Type: "header",
+			Text: &SlackText{Type: "plain_text", Text: truncate(titlePlaintext, slackHeaderMaxLength)},
+		},
+		{
+			Type: "section",
+			Text: &SlackText{Type: "mrkdwn", Text: truncate(bodyMrkdwn, slackSectionMaxLength)},
+		},
+	}
+	if len(payload.Actions) > 0 {
+		buttons := make([]SlackElement, 0, len(payload.Actions))
+		for _, action := range payload.Actions {
+			buttons = append(buttons, SlackElement{
+				Type: "button",
+				Text: SlackText{Type: "plain_text", Text: action.Label},
+				URL:  action.URL,
+			})
+		}
+		blocks = append(blocks, SlackBlock{Type: "actions", Elements: buttons})
+	}
+
+	msg := SlackMessage{
+		Text:   titlePlaintext,
+		Blocks: blocks,
+	}
+	return s.dispatch(payload, msg), nil
+}
+
+func (s *SlackHandler) dispatch(payload types.MessagePayload, msg SlackMessage) DeliveryFunc {
+	return func(ctx context.Context, msgID uuid.UUID) (retryable bool, err error) {
+		// Prefer direct messages so that the notification reaches only its intended recipient.
+		if s.cfg.BotToken.String() != "" && payload.UserEmail != "" {
+			userID, retryable, err := s.lookupUserByEmail(ctx, payload.UserEmail)
+			if err != nil {
+				return retryable, xerrors.Errorf("lookup slack user: %w", err)
+			}
+
+			msg.Channel = userID
+			return s.postMessage(ctx, msgID, msg)
+		}
+
+		if s.cfg.WebhookURL.String() == "" {
+			return false, xerrors.New("recipient cannot be messaged directly and slack webhook URL not defined")
+		}
+
+		return s.postWebhook(ctx, msgID, msg)
+	}
+}
+
+// lookupUserByEmail finds the Slack user ID of the given email address.
+// See: https://api.slack.com/methods/users.lookupByEmail
+func (s *SlackHandler) lookupUserByEmail(ctx context.Context, email string) (userID string, retryable bool, err error) {
+	endpoint := s.cfg.APIURL.String() + "/users.lookupByEmail?" + url.Values{"email": {email}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", false, xerrors.Errorf("create HTTP request: %v", err)
+	}
+
+	resp, retryable, err := s.callAPI(req)
+	if err != nil {
+		return "", retryable, err
+	}
+	return resp.User.ID, false, nil
+}
+
+// postMessage sends the message via the Web API.
+// See: https://api.slack.com/methods/chat.postMessage
+func (s *SlackHandler) postMessage(ctx context.Context, msgID uuid.UUID, msg SlackMessage) (retryable bool, err error) {
+	m, err := json.Marshal(msg)
+	if err != nil {
+		return false, xerrors.Errorf("marshal payload: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.APIURL.String()+"/chat.postMessage", bytes.NewBuffer(m))
+	if err != nil {
+		return false, xerrors.Errorf("create HTTP request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("X-Message-Id", msgID.String())
+
+	_, retryable, err = s.callAPI(req)
+	return retryable, err
+}
+
+func (s *SlackHandler) callAPI(req *http.Request) (_ slackAPIResponse, retryable bool, err error) {
+	req.Header.Set("Authorization", "Bearer "+s.cfg.BotToken.String())
+
+	resp, err := s.cl.Do(req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return slackAPIResponse{}, true, xerrors.Errorf("request timeout: %w", err)
+		}
+
+		return slackAPIResponse{}, true, xerrors.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 > 2 {
+		return slackAPIResponse{}, retryableStatus(resp.StatusCode), xerrors.Errorf("non-2xx response (%d)", resp.StatusCode)
+	}
+
+	var out slackAPIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return slackAPIResponse{}, true, xerrors.Errorf("decode response: %w", err)
+	}
+	if !out.OK {
+		return slackAPIResponse{}, out.Error == "ratelimited", xerrors.Errorf("slack API error: %s", out.Error)
+	}
+	return out, false, nil
+}
+
+// postWebhook sends the message to an incoming webhook.
+// See: https://api.slack.com/messaging/webhooks
+func (s *SlackHandler) postWebhook(ctx context.Context, msgID uuid.UUID, msg SlackMessage) (retryable bool, err error) {
+	m, err := json.Marshal(msg)
+	if err != nil {
+		return false, xerrors.Errorf("marshal payload: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.WebhookURL.String(), bytes.NewBuffer(m))
+	if err != nil {
+		return false, xerrors.Errorf("create HTTP request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.cl.Do(req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return true, xerrors.Errorf("request timeout: %w", err)
+		}
+
+		return true, xerrors.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 > 2 {
+		// Incoming webhooks respond with a short, plaintext error code (e.g. "invalid_blocks").
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		s.log.Warn(ctx, "unsuccessful delivery", slog.F("status_code", resp.StatusCode),
+			slog.F("response", respBody), slog.F("msg_id", msgID))
+		return retryableStatus(resp.StatusCode), xerrors.Errorf("non-2xx response (%d)", resp.StatusCode)
+	}
+
+	return false, nil
+}
+
+// retryableStatus determines whether a request which failed with the given HTTP status code may succeed if retried.
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// truncate shortens the given string to at most limit runes, indicating that truncation has occurred.
+func truncate(s string, limit int) string {
+	r := []rune(s)
+	if len(r) <= limit {
+		return s
+	}
+	return strings.TrimSpace(string(r[:limit-1])) + "…"
+}
@@ -0,0 +1,220 @@
+package dispatch_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/serpent"
+
+	"github.com/coder/coder/v2/coderd/notifications/dispatch"
+	"github.com/coder/coder/v2/coderd/notifications/types"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestSlack(t *testing.T) {
+	t.Parallel()
+
+	const (
+		titleMarkdown = "Workspace **dev** deleted"
+		bodyMarkdown  = "Hi there, your workspace **dev** was _deleted_.\n\nSee [the docs](https://coder.com/docs)."
+		botToken      = "xoxb-test"
+		slackUserID   = "U123456"
+	)
+
+	msgPayload := types.MessagePayload{
+		Version:          "1.1",
+		NotificationName: "test",
+		UserEmail:        "bob@coder.com",
+		Actions: []types.TemplateAction{
+			{Label: "View workspaces", URL: "https://coder.com/workspaces"},
+		},
+	}
+
+	assertMessage := func(t *testing.T, msg dispatch.SlackMessage) {
+		t.Helper()
+
+		assert.Equal(t, "Workspace dev deleted", msg.Text)
+		require.Len(t, msg.Blocks, 3)
+		assert.Equal(t, "header", msg.Blocks[0].Type)
+		assert.Equal(t, "Workspace dev deleted", msg.Blocks[0].Text.Text)
+		assert.Equal(t, "section", msg.Blocks[1].Type)
+		assert.Equal(t, "mrkdwn", msg.Blocks[1].Text.Type)
+		assert.Equal(t, "Hi there, your workspace *dev* was _deleted_.\n\nSee <https://coder.com/docs|the docs>.", msg.Blocks[1].Text.Text)
+		assert.Equal(t, "actions", msg.Blocks[2].Type)
+		require.Len(t, msg.Blocks[2].Elements, 1)
+		assert.Equal(t, "View workspaces", msg.Blocks[2].Elements[0].Text.Text)
+		assert.Equal(t, "https://coder.com/workspaces", msg.Blocks[2].Elements[0].URL)
+	}
+
+	tests := []struct {
+		name     string
+		botToken string
+		payload  types.MessagePayload
+		serverFn func(t *testing.T, w http.ResponseWriter, r *http.Request)
+
+		expectSuccess   bool
+		expectRetryable bool
+		expectErr       string
+	}{
+		{
+			name:    "webhook",
+			payload: msgPayload,
+			serverFn: func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/webhook", r.URL.Path)
+				assert.Empty(t, r.Header.Get("Authorization"))
+
+				var msg dispatch.SlackMessage
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+				assert.Empty(t, msg.Channel)
+				assertMessage(t, msg)
+
+				_, _ = w.Write([]byte("ok"))
+			},
+			expectSuccess: true,
+		},
+		{
+			name:     "direct message",
+			botToken: botToken,
+			payload:  msgPayload,
+			serverFn: func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "Bearer "+botToken, r.Header.Get("Authorization"))
+
+				switch r.URL.Path {
+				case "/api/users.lookupByEmail":
+					assert.Equal(t, msgPayload.UserEmail, r.URL.Query().Get("email"))
+					_, _ = w.Write([]byte(`{"ok":true,"user":{"id":"` + slackUserID + `"}}`))
+				case "/api/chat.postMessage":
+					var msg dispatch.SlackMessage
+					assert.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+					assert.Equal(t, slackUserID, msg.Channel)
+					assertMessage(t, msg)
+					_, _ = w.Write([]byte(`{"ok":true}`))
+				default:
+					t.Errorf("unexpected request to %s", r.URL.Path)
+				}
+			},
+			expectSuccess: true,
+		},
+		{
+			name:     "unknown user",
+			botToken: botToken,
+			payload:  msgPayload,
+			serverFn: func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/api/users.lookupByEmail", r.URL.Path)
+				_, _ = w.Write([]byte(`{"ok":false,"error":"users_not_found"}`))
+			},
+			expectSuccess:   false,
+			expectRetryable: false,
+			expectErr:       "users_not_found",
+		},
+		{
+			name:     "rate limited",
+			botToken: botToken,
+			payload:  msgPayload,
+			serverFn: func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusTooManyRequests)
+			},
+			expectSuccess:   false,
+			expectRetryable: true,
+			expectErr:       "non-2xx response (429)",
+		},
+		{
+			name:     "no email falls back to webhook",
+			botToken: botToken,
+			payload: func() types.MessagePayload {
+				p := msgPayload
+				p.UserEmail = ""
+				return p
+			}(),
+			serverFn: func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/webhook", r.URL.Path)
+				_, _ = w.Write([]byte("ok"))
+			},
+			expectSuccess: true,
+		},
+		{
+			name:    "invalid blocks",
+			payload: msgPayload,
+			serverFn: func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte("invalid_blocks"))
+			},
+			expectSuccess:   false,
+			expectRetryable: false,
+			expectErr:       "non-2xx response (400)",
+		},
+	}
+
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug)
+
+	// nolint:paralleltest // Irrelevant as of Go v1.22
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := testutil.Context(t, testutil.WaitLong)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tc.serverFn(t, w, r)
+			}))
+			t.Cleanup(server.Close)
+
+			webhookURL, err := url.Parse(server.URL + "/webhook")
+			require.NoError(t, err)
+			apiURL, err := url.Parse(server.URL + "/api")
+			require.NoError(t, err)
+
+			cfg := codersdk.NotificationsSlackConfig{
+				WebhookURL: *serpent.URLOf(webhookURL),
+				BotToken:   serpent.String(tc.botToken),
+				APIURL:     *serpent.URLOf(apiURL),
+			}
+			handler := dispatch.NewSlackHandler(cfg, logger.With(slog.F("test", tc.name)))
+			deliveryFn, err := handler.Dispatcher(tc.payload, titleMarkdown, bodyMarkdown, helpers())
+			require.NoError(t, err)
+
+			retryable, err := deliveryFn(ctx, uuid.New())
+			if tc.expectSuccess {
+				require.NoError(t, err)
+				require.False(t, retryable)
+				return
+			}
+
+			require.ErrorContains(t, err, tc.expectErr)
+			require.Equal(t, tc.expectRetryable, retryable)
+		})
+	}
+
+	t.Run("not configured", func(t *testing.T) {
+		t.Parallel()
+
+		handler := dispatch.NewSlackHandler(codersdk.NotificationsSlackConfig{}, logger)
+		_, err := handler.Dispatcher(msgPayload, titleMarkdown, bodyMarkdown, helpers())
+		require.ErrorContains(t, err, "slack webhook URL or bot token not defined")
+	})
+
+	t.Run("no email and no webhook", func(t *testing.T) {
+		t.Parallel()
+
+		p := msgPayload
+		p.UserEmail = ""
+		handler := dispatch.NewSlackHandler(codersdk.NotificationsSlackConfig{BotToken: botToken}, logger)
+		deliveryFn, err := handler.Dispatcher(p, titleMarkdown, bodyMarkdown, helpers())
+		require.NoError(t, err)
+
+		retryable, err := deliveryFn(context.Background(), uuid.New())
+		require.ErrorContains(t, err, "slack webhook URL not defined")
+		require.False(t, retryable)
+	})
+}
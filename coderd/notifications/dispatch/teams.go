@@ -0,0 +1,147 @@
+package dispatch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"text/template"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/notifications/types"
+	markdown "github.com/coder/coder/v2/coderd/render"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// TeamsHandler dispatches notification messages to a Microsoft Teams incoming webhook as Adaptive Cards.
+type TeamsHandler struct {
+	cfg codersdk.NotificationsTeamsConfig
+	log slog.Logger
+
+	cl *http.Client
+}
+
+// TeamsMessage describes the JSON payload delivered to the Microsoft Teams incoming webhook.
+// See: https://learn.microsoft.com/en-us/microsoftteams/platform/webhooks-and-connectors/how-to/connectors-using#send-adaptive-cards-using-an-incoming-webhook
+type TeamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []TeamsAttachment `json:"attachments"`
+}
+
+type TeamsAttachment struct {
+	ContentType string            `json:"contentType"`
+	Content     TeamsAdaptiveCard `json:"content"`
+}
+
+// TeamsAdaptiveCard describes the subset of the Adaptive Card schema used to render notifications.
+// See: https://adaptivecards.io/explorer/AdaptiveCard.html
+type TeamsAdaptiveCard struct {
+	Schema  string             `json:"$schema"`
+	Type    string             `json:"type"`
+	Version string             `json:"version"`
+	Body    []TeamsCardElement `json:"body"`
+	Actions []TeamsCardAction  `json:"actions,omitempty"`
+	MSTeams TeamsCardOptions   `json:"msteams"`
+}
+
+type TeamsCardElement struct {
+	Type   string `json:"type"`
+	Text   string `json:"text"`
+	Size   string `json:"size,omitempty"`
+	Weight string `json:"weight,omitempty"`
+	Wrap   bool   `json:"wrap"`
+}
+
+type TeamsCardAction struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+type TeamsCardOptions struct {
+	Width string `json:"width"`
+}
+
+func NewTeamsHandler(cfg codersdk.NotificationsTeamsConfig, log slog.Logger) *TeamsHandler {
+	return &TeamsHandler{cfg: cfg, log: log, cl: &http.Client{}}
+}
+
+func (t *TeamsHandler) Dispatcher(payload types.MessagePayload, titleMarkdown, bodyMarkdown string, _ template.FuncMap) (DeliveryFunc, error) {
+	if t.cfg.WebhookURL.String() == "" {
+		return nil, xerrors.New("teams webhook URL not defined")
+	}
+
+	titlePlaintext, err := markdown.PlaintextFromMarkdown(titleMarkdown)
+	if err != nil {
+		return nil, xerrors.Errorf("render title: %w", err)
+	}
+
+	card := TeamsAdaptiveCard{
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:    "AdaptiveCard",
+		Version: "1.4",
+		Body: []TeamsCardElement{
+			{Type: "TextBlock", Text: titlePlaintext, Size: "Large", Weight: "Bolder", Wrap: true},
+			// TextBlocks natively support a subset of Markdown, so the body is passed through as-is.
+			// See: https://learn.microsoft.com/en-us/adaptive-cards/authoring-cards/text-features
+			{Type: "TextBlock", Text: bodyMarkdown, Wrap: true},
+		},
+		MSTeams: TeamsCardOptions{Width: "Full"},
+	}
+	for _, action := range payload.Actions {
+		card.Actions = append(card.Actions, TeamsCardAction{
+			Type:  "Action.OpenUrl",
+			Title: action.Label,
+			URL:   action.URL,
+		})
+	}
+
+	msg := TeamsMessage{
+		Type: "message",
+		Attachments: []TeamsAttachment{
+			{ContentType: "application/vnd.microsoft.card.adaptive", Content: card},
+		},
+	}
+	return t.dispatch(msg, t.cfg.WebhookURL.String()), nil
+}
+
+func (t *TeamsHandler) dispatch(msg TeamsMessage, endpoint string) DeliveryFunc {
+	return func(ctx context.Context, msgID uuid.UUID) (retryable bool, err error) {
+		m, err := json.Marshal(msg)
+		if err != nil {
+			return false, xerrors.Errorf("marshal payload: %v", err)
+		}
+
+		// Outer context has a deadline (see CODER_NOTIFICATIONS_DISPATCH_TIMEOUT).
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewBuffer(m))
+		if err != nil {
+			return false, xerrors.Errorf("create HTTP request: %v", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := t.cl.Do(req)
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				return true, xerrors.Errorf("request timeout: %w", err)
+			}
+
+			return true, xerrors.Errorf("request failed: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode/100 > 2 {
+			respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+			t.log.Warn(ctx, "unsuccessful delivery", slog.F("status_code", resp.StatusCode),
+				slog.F("response", respBody), slog.F("msg_id", msgID))
+			return retryableStatus(resp.StatusCode), xerrors.Errorf("non-2xx response (%d)", resp.StatusCode)
+		}
+
+		return false, nil
+	}
+}
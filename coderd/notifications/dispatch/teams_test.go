@@ -0,0 +1,140 @@
+package dispatch_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/serpent"
+
+	"github.com/coder/coder/v2/coderd/notifications/dispatch"
+	"github.com/coder/coder/v2/coderd/notifications/types"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTeams(t *testing.T) {
+	t.Parallel()
+
+	const (
+		titleMarkdown = "Workspace **dev** deleted"
+		bodyMarkdown  = "Hi there, your workspace **dev** was _deleted_."
+	)
+
+	msgPayload := types.MessagePayload{
+		Version:          "1.1",
+		NotificationName: "test",
+		Actions: []types.TemplateAction{
+			{Label: "View workspaces", URL: "https://coder.com/workspaces"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		serverFn func(t *testing.T, w http.ResponseWriter, r *http.Request)
+
+		expectSuccess   bool
+		expectRetryable bool
+		expectErr       string
+	}{
+		{
+			name: "successful",
+			serverFn: func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+				var msg dispatch.TeamsMessage
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+				assert.Equal(t, "message", msg.Type)
+				if !assert.Len(t, msg.Attachments, 1) {
+					return
+				}
+				assert.Equal(t, "application/vnd.microsoft.card.adaptive", msg.Attachments[0].ContentType)
+
+				card := msg.Attachments[0].Content
+				assert.Equal(t, "AdaptiveCard", card.Type)
+				if assert.Len(t, card.Body, 2) {
+					assert.Equal(t, "Workspace dev deleted", card.Body[0].Text)
+					assert.Equal(t, bodyMarkdown, card.Body[1].Text)
+				}
+				if assert.Len(t, card.Actions, 1) {
+					assert.Equal(t, "Action.OpenUrl", card.Actions[0].Type)
+					assert.Equal(t, "View workspaces", card.Actions[0].Title)
+					assert.Equal(t, "https://coder.com/workspaces", card.Actions[0].URL)
+				}
+
+				w.WriteHeader(http.StatusAccepted)
+			},
+			expectSuccess: true,
+		},
+		{
+			name: "bad request",
+			serverFn: func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+			},
+			expectSuccess:   false,
+			expectRetryable: false,
+			expectErr:       "non-2xx response (400)",
+		},
+		{
+			name: "server error",
+			serverFn: func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			},
+			expectSuccess:   false,
+			expectRetryable: true,
+			expectErr:       "non-2xx response (503)",
+		},
+	}
+
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug)
+
+	// nolint:paralleltest // Irrelevant as of Go v1.22
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := testutil.Context(t, testutil.WaitLong)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tc.serverFn(t, w, r)
+			}))
+			t.Cleanup(server.Close)
+
+			endpoint, err := url.Parse(server.URL)
+			require.NoError(t, err)
+
+			cfg := codersdk.NotificationsTeamsConfig{
+				WebhookURL: *serpent.URLOf(endpoint),
+			}
+			handler := dispatch.NewTeamsHandler(cfg, logger.With(slog.F("test", tc.name)))
+			deliveryFn, err := handler.Dispatcher(msgPayload, titleMarkdown, bodyMarkdown, helpers())
+			require.NoError(t, err)
+
+			retryable, err := deliveryFn(ctx, uuid.New())
+			if tc.expectSuccess {
+				require.NoError(t, err)
+				require.False(t, retryable)
+				return
+			}
+
+			require.ErrorContains(t, err, tc.expectErr)
+			require.Equal(t, tc.expectRetryable, retryable)
+		})
+	}
+
+	t.Run("not configured", func(t *testing.T) {
+		t.Parallel()
+
+		handler := dispatch.NewTeamsHandler(codersdk.NotificationsTeamsConfig{}, logger)
+		_, err := handler.Dispatcher(msgPayload, titleMarkdown, bodyMarkdown, helpers())
+		require.ErrorContains(t, err, "teams webhook URL not defined")
+	})
+}
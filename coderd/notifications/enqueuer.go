@@ -69,8 +69,12 @@ func (s *StoreEnqueuer) EnqueueWithData(ctx context.Context, userID, templateID
 		return nil, xerrors.Errorf("new message metadata: %w", err)
 	}
 
+	// A method chosen by the user takes precedence over one chosen for the template, which in turn takes precedence over
+	// the deployment-level default.
 	dispatchMethod := s.defaultMethod
-	if metadata.CustomMethod.Valid {
+	if metadata.UserMethod.Valid {
+		dispatchMethod = metadata.UserMethod.NotificationMethod
+	} else if metadata.CustomMethod.Valid {
 		dispatchMethod = metadata.CustomMethod.NotificationMethod
 	}
 
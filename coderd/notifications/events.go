@@ -15,6 +15,10 @@ var (
 	TemplateWorkspaceAutoUpdated       = uuid.MustParse("c34a0c09-0704-4cac-bd1c-0c0146811c2b")
 	TemplateWorkspaceMarkedForDeletion = uuid.MustParse("51ce2fdf-c9ca-4be1-8d70-628674f9bc42")
 	TemplateWorkspaceManualBuildFailed = uuid.MustParse("2faeee0f-26cb-4e96-821c-85ccb9f71513")
+
+	TemplateWorkspaceBuildApprovalRequested = uuid.MustParse("b96a5b0e-3ab9-45d3-8f1b-0e4801a65cc0")
+
+	TemplateCostBudgetThresholdReached = uuid.MustParse("0b8d5a47-1f2c-4e59-9c3a-7d4e6f8a9b10")
 )
 
 // Account-related events.
@@ -36,4 +40,6 @@ var (
 	TemplateTemplateDeprecated = uuid.MustParse("f40fae84-55a2-42cd-99fa-b41c1ca64894")
 
 	TemplateWorkspaceBuildsFailedReport = uuid.MustParse("34a20db2-e9cc-4a93-b0e4-8569699d7a00")
+
+	TemplateTemplateVersionRolloutUpdated = uuid.MustParse("5d9a3c1e-7b2f-4e8a-9c6d-1f0e2b3a4c5d")
 )
@@ -1064,6 +1064,54 @@ func TestNotificationTemplates_Golden(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "TemplateWorkspaceBuildApprovalRequested",
+			id:   notifications.TemplateWorkspaceBuildApprovalRequested,
+			payload: types.MessagePayload{
+				UserName:     "Bobby",
+				UserEmail:    "bobby@coder.com",
+				UserUsername: "bobby",
+				Labels: map[string]string{
+					"initiator":    "alice",
+					"owner":        "alice",
+					"workspace":    "alice-workspace",
+					"template":     "sensitive-template",
+					"build_number": "3",
+				},
+			},
+		},
+		{
+			name: "TemplateCostBudgetThresholdReached",
+			id:   notifications.TemplateCostBudgetThresholdReached,
+			payload: types.MessagePayload{
+				UserName:     "Bobby",
+				UserEmail:    "bobby@coder.com",
+				UserUsername: "bobby",
+				Labels: map[string]string{
+					"group":    "developers",
+					"cost":     "412.50",
+					"limit":    "500.00",
+					"percent":  "82",
+					"stopping": "false",
+				},
+			},
+		},
+		{
+			name: "TemplateTemplateVersionRolloutUpdated",
+			id:   notifications.TemplateTemplateVersionRolloutUpdated,
+			payload: types.MessagePayload{
+				UserName:     "Bobby",
+				UserEmail:    "bobby@coder.com",
+				UserUsername: "bobby",
+				Labels: map[string]string{
+					"organization": "coder",
+					"template":     "bobby-template",
+					"version":      "vigorous_hopper3",
+					"status":       "rolled back",
+					"reason":       "4 of 10 builds failed (40%), above the maximum failure rate of 10%.",
+				},
+			},
+		},
 	}
 
 	// We must have a test case for every notification_template. This is enforced below:
@@ -1659,6 +1707,75 @@ func TestCustomNotificationMethod(t *testing.T) {
 	}, testutil.WaitLong, testutil.IntervalFast)
 }
 
+func TestUserNotificationMethod(t *testing.T) {
+	t.Parallel()
+
+	// nolint:gocritic // Unit test.
+	ctx := dbauthz.AsNotifier(testutil.Context(t, testutil.WaitSuperLong))
+	store, _ := dbtestutil.NewDB(t)
+	logger := testutil.Logger(t)
+
+	received := make(chan dispatch.SlackMessage, 1)
+
+	// SETUP:
+	// Start mock server to simulate a Slack incoming webhook.
+	mockSlackSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg dispatch.SlackMessage
+		err := json.NewDecoder(r.Body).Decode(&msg)
+		assert.NoError(t, err)
+
+		received <- msg
+		close(received)
+
+		w.WriteHeader(http.StatusOK)
+		_, err = w.Write([]byte("ok"))
+		assert.NoError(t, err)
+	}))
+	defer mockSlackSrv.Close()
+
+	endpoint, err := url.Parse(mockSlackSrv.URL)
+	require.NoError(t, err)
+
+	// GIVEN: a user who has opted in to receiving a notification via Slack
+	var (
+		tmpl          = notifications.TemplateWorkspaceDeleted
+		defaultMethod = database.NotificationMethodSmtp
+		userMethod    = database.NotificationMethodSlack
+	)
+	user := createSampleUser(t, store)
+	_, err = store.UpdateUserNotificationMethodPreferences(ctx, database.UpdateUserNotificationMethodPreferencesParams{
+		UserID:                  user.ID,
+		NotificationTemplateIds: []uuid.UUID{tmpl},
+		Methods:                 []string{string(userMethod)},
+	})
+	require.NoError(t, err)
+
+	// GIVEN: a manager configured with Slack
+	cfg := defaultNotificationsConfig(defaultMethod)
+	cfg.Slack = codersdk.NotificationsSlackConfig{
+		WebhookURL: *serpent.URLOf(endpoint),
+	}
+
+	mgr, err := notifications.NewManager(cfg, store, defaultHelpers(), createMetrics(), logger.Named("manager"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = mgr.Stop(ctx)
+	})
+
+	enq, err := notifications.NewStoreEnqueuer(cfg, store, defaultHelpers(), logger.Named("enqueuer"), quartz.NewReal())
+	require.NoError(t, err)
+
+	// WHEN: a notification of that template is enqueued
+	_, err = enq.Enqueue(ctx, user.ID, tmpl, map[string]string{"name": "my-workspace", "reason": "test", "initiator": "autobuild"}, "test")
+	require.NoError(t, err)
+
+	// THEN: the notification should be received via the user's chosen method, not the default
+	mgr.Run(ctx)
+
+	msg := testutil.RequireRecvCtx(ctx, t, received)
+	require.NotEmpty(t, msg.Blocks)
+}
+
 func TestNotificationsTemplates(t *testing.T) {
 	t.Parallel()
 
@@ -267,6 +267,69 @@ func TestNotificationPreferences(t *testing.T) {
 		}
 		require.True(t, found, "dormant notification preference was not found")
 	})
+
+	t.Run("Opt in to delivery method", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitSuperLong)
+		api := coderdtest.New(t, createOpts(t))
+		firstUser := coderdtest.CreateFirstUser(t, api)
+
+		// Given: a member with a disabled notification.
+		memberClient, member := coderdtest.CreateAnotherUser(t, api, firstUser.OrganizationID)
+		_, err := memberClient.UpdateUserNotificationPreferences(ctx, member.ID, codersdk.UpdateUserNotificationPreferences{
+			TemplateDisabledMap: map[string]bool{
+				notifications.TemplateWorkspaceDormant.String(): true,
+			},
+		})
+		require.NoError(t, err)
+
+		// When: opting in to receive notifications via Slack.
+		prefs, err := memberClient.UpdateUserNotificationPreferences(ctx, member.ID, codersdk.UpdateUserNotificationPreferences{
+			TemplateMethodMap: map[string]string{
+				notifications.TemplateWorkspaceDeleted.String(): string(database.NotificationMethodSlack),
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, prefs, 2)
+
+		// Then: the method should be set without affecting the other preferences.
+		for _, p := range prefs {
+			switch p.NotificationTemplateID {
+			case notifications.TemplateWorkspaceDeleted:
+				require.False(t, p.Disabled)
+				require.Equal(t, string(database.NotificationMethodSlack), p.Method)
+			case notifications.TemplateWorkspaceDormant:
+				require.True(t, p.Disabled)
+				require.Empty(t, p.Method)
+			}
+		}
+
+		// When: resetting the method.
+		prefs, err = memberClient.UpdateUserNotificationPreferences(ctx, member.ID, codersdk.UpdateUserNotificationPreferences{
+			TemplateMethodMap: map[string]string{
+				notifications.TemplateWorkspaceDeleted.String(): "",
+			},
+		})
+		require.NoError(t, err)
+
+		// Then: the method should be cleared.
+		for _, p := range prefs {
+			require.Empty(t, p.Method)
+		}
+
+		// When: choosing an unknown method.
+		_, err = memberClient.UpdateUserNotificationPreferences(ctx, member.ID, codersdk.UpdateUserNotificationPreferences{
+			TemplateMethodMap: map[string]string{
+				notifications.TemplateWorkspaceDeleted.String(): "carrier-pigeon",
+			},
+		})
+
+		// Then: the request should be rejected.
+		var sdkError *codersdk.Error
+		require.ErrorAsf(t, err, &sdkError, "error should be of type *codersdk.Error")
+		require.Equal(t, http.StatusBadRequest, sdkError.StatusCode())
+	})
 }
 
 func TestNotificationDispatchMethods(t *testing.T) {
@@ -113,13 +113,19 @@ func (api *API) postOAuth2ProviderApp(rw http.ResponseWriter, r *http.Request) {
 	if !httpapi.Read(ctx, rw, r, &req) {
 		return
 	}
+	clientCredentialsUserID, ok := api.oAuth2ProviderAppClientCredentialsUser(rw, r, req.PublicClient, req.ClientCredentialsUserID)
+	if !ok {
+		return
+	}
 	app, err := api.Database.InsertOAuth2ProviderApp(ctx, database.InsertOAuth2ProviderAppParams{
-		ID:          uuid.New(),
-		CreatedAt:   dbtime.Now(),
-		UpdatedAt:   dbtime.Now(),
-		Name:        req.Name,
-		Icon:        req.Icon,
-		CallbackURL: req.CallbackURL,
+		ID:                      uuid.New(),
+		CreatedAt:               dbtime.Now(),
+		UpdatedAt:               dbtime.Now(),
+		Name:                    req.Name,
+		Icon:                    req.Icon,
+		CallbackURL:             req.CallbackURL,
+		PublicClient:            req.PublicClient,
+		ClientCredentialsUserID: clientCredentialsUserID,
 	})
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -160,12 +166,18 @@ func (api *API) putOAuth2ProviderApp(rw http.ResponseWriter, r *http.Request) {
 	if !httpapi.Read(ctx, rw, r, &req) {
 		return
 	}
+	clientCredentialsUserID, ok := api.oAuth2ProviderAppClientCredentialsUser(rw, r, req.PublicClient, req.ClientCredentialsUserID)
+	if !ok {
+		return
+	}
 	app, err := api.Database.UpdateOAuth2ProviderAppByID(ctx, database.UpdateOAuth2ProviderAppByIDParams{
-		ID:          app.ID,
-		UpdatedAt:   dbtime.Now(),
-		Name:        req.Name,
-		Icon:        req.Icon,
-		CallbackURL: req.CallbackURL,
+		ID:                      app.ID,
+		UpdatedAt:               dbtime.Now(),
+		Name:                    req.Name,
+		Icon:                    req.Icon,
+		CallbackURL:             req.CallbackURL,
+		PublicClient:            req.PublicClient,
+		ClientCredentialsUserID: clientCredentialsUserID,
 	})
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -178,6 +190,43 @@ func (api *API) putOAuth2ProviderApp(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.OAuth2ProviderApp(api.AccessURL, app))
 }
 
+// oAuth2ProviderAppClientCredentialsUser validates the user an app's client
+// credentials grant acts as. Public clients cannot keep a secret, so they are
+// not allowed to use the grant at all.
+func (api *API) oAuth2ProviderAppClientCredentialsUser(rw http.ResponseWriter, r *http.Request, publicClient bool, userID *uuid.UUID) (uuid.NullUUID, bool) {
+	ctx := r.Context()
+	if userID == nil {
+		return uuid.NullUUID{}, true
+	}
+	if publicClient {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Public clients cannot use the client credentials grant.",
+			Validations: []codersdk.ValidationError{
+				{Field: "client_credentials_user_id", Detail: "must be empty for public clients"},
+			},
+		})
+		return uuid.NullUUID{}, false
+	}
+	user, err := api.Database.GetUserByID(ctx, *userID)
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Client credentials user does not exist.",
+			Validations: []codersdk.ValidationError{
+				{Field: "client_credentials_user_id", Detail: "user not found"},
+			},
+		})
+		return uuid.NullUUID{}, false
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching client credentials user.",
+			Detail:  err.Error(),
+		})
+		return uuid.NullUUID{}, false
+	}
+	return uuid.NullUUID{UUID: user.ID, Valid: true}, true
+}
+
 // @Summary Delete OAuth2 application.
 // @ID delete-oauth2-application
 // @Security CoderSessionToken
@@ -336,6 +385,8 @@ func (api *API) deleteOAuth2ProviderAppSecret(rw http.ResponseWriter, r *http.Re
 // @Param response_type query codersdk.OAuth2ProviderResponseType true "Response type"
 // @Param redirect_uri query string false "Redirect here after authorization"
 // @Param scope query string false "Token scopes (currently ignored)"
+// @Param code_challenge query string false "PKCE code challenge, required for public clients"
+// @Param code_challenge_method query codersdk.OAuth2PKCECodeChallengeMethod false "PKCE code challenge method"
 // @Success 302
 // @Router /oauth2/authorize [post]
 func (api *API) getOAuth2ProviderAppAuthorize() http.HandlerFunc {
@@ -347,8 +398,10 @@ func (api *API) getOAuth2ProviderAppAuthorize() http.HandlerFunc {
 // @Produce json
 // @Tags Enterprise
 // @Param client_id formData string false "Client ID, required if grant_type=authorization_code"
-// @Param client_secret formData string false "Client secret, required if grant_type=authorization_code"
+// @Param client_secret formData string false "Client secret, required for confidential clients unless HTTP basic authentication is used"
 // @Param code formData string false "Authorization code, required if grant_type=authorization_code"
+// @Param code_verifier formData string false "PKCE code verifier, required if a code challenge was sent to the authorize endpoint"
+// @Param device_code formData string false "Device code, required if grant_type=urn:ietf:params:oauth:grant-type:device_code"
 // @Param refresh_token formData string false "Refresh token, required if grant_type=refresh_token"
 // @Param grant_type formData codersdk.OAuth2ProviderGrantType true "Grant type"
 // @Success 200 {object} oauth2.Token
@@ -367,3 +420,37 @@ func (api *API) postOAuth2ProviderAppToken() http.HandlerFunc {
 func (api *API) deleteOAuth2ProviderAppTokens() http.HandlerFunc {
 	return identityprovider.RevokeApp(api.Database)
 }
+
+// @Summary OAuth2 device authorization request.
+// @ID oauth2-device-authorization-request
+// @Produce json
+// @Tags Enterprise
+// @Param client_id formData string true "Client ID"
+// @Param scope formData string false "Token scopes (currently ignored)"
+// @Success 200 {object} codersdk.OAuth2DeviceAuthorizationResponse
+// @Router /oauth2/device [post]
+func (api *API) postOAuth2ProviderDeviceAuthorization() http.HandlerFunc {
+	return identityprovider.DeviceAuthorization(api.Database, api.AccessURL)
+}
+
+// @Summary OAuth2 device verification.
+// @ID oauth2-device-verification
+// @Security CoderSessionToken
+// @Tags Enterprise
+// @Param user_code query string false "The code displayed on the device"
+// @Param action query string false "Whether to allow or deny the device" Enums(allow,deny)
+// @Success 200
+// @Router /oauth2/device/verify [get]
+func (api *API) getOAuth2ProviderDeviceVerify() http.HandlerFunc {
+	return identityprovider.DeviceVerify(api.Database, api.AccessURL)
+}
+
+// @Summary OAuth2 authorization server metadata.
+// @ID oauth2-authorization-server-metadata
+// @Produce json
+// @Tags Enterprise
+// @Success 200 {object} codersdk.OAuth2AuthorizationServerMetadata
+// @Router /.well-known/oauth-authorization-server [get]
+func (api *API) getOAuth2AuthorizationServerMetadata() http.HandlerFunc {
+	return identityprovider.Metadata(api.AccessURL)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
 
 	"github.com/coder/coder/v2/coderd/apikey"
 	"github.com/coder/coder/v2/coderd/coderdtest"
@@ -378,6 +379,8 @@ func TestOAuth2ProviderTokenExchange(t *testing.T) {
 	secret, err := ownerClient.PostOAuth2ProviderAppSecret(topCtx, apps.Default.ID)
 	require.NoError(t, err)
 
+	pkceVerifier := oauth2.GenerateVerifier()
+
 	// The typical oauth2 flow from this point is:
 	// Create an oauth2.Config using the id, secret, endpoints, and redirect:
 	//	cfg := oauth2.Config{ ... }
@@ -409,6 +412,8 @@ func TestOAuth2ProviderTokenExchange(t *testing.T) {
 		defaultCode *string
 		// custom allows some more advanced manipulation of the oauth2 exchange.
 		exchangeMutate []oauth2.AuthCodeOption
+		// authMutate allows adding parameters to the authorization URL.
+		authMutate []oauth2.AuthCodeOption
 	}{
 		{
 			name: "AuthInParams",
@@ -655,6 +660,47 @@ func TestOAuth2ProviderTokenExchange(t *testing.T) {
 				return err
 			},
 		},
+		{
+			name: "AuthInHeader",
+			app:  apps.Default,
+			preToken: func(valid *oauth2.Config) {
+				valid.Endpoint.AuthStyle = oauth2.AuthStyleInHeader
+			},
+		},
+		{
+			name:           "PKCE",
+			app:            apps.Default,
+			authMutate:     []oauth2.AuthCodeOption{oauth2.S256ChallengeOption(pkceVerifier)},
+			exchangeMutate: []oauth2.AuthCodeOption{oauth2.VerifierOption(pkceVerifier)},
+		},
+		{
+			name:           "PKCEWrongVerifier",
+			app:            apps.Default,
+			authMutate:     []oauth2.AuthCodeOption{oauth2.S256ChallengeOption(pkceVerifier)},
+			exchangeMutate: []oauth2.AuthCodeOption{oauth2.VerifierOption(oauth2.GenerateVerifier())},
+			tokenError:     "Invalid code verifier",
+		},
+		{
+			name:       "PKCEMissingVerifier",
+			app:        apps.Default,
+			authMutate: []oauth2.AuthCodeOption{oauth2.S256ChallengeOption(pkceVerifier)},
+			tokenError: "Invalid code verifier",
+		},
+		{
+			name:           "PKCEVerifierWithoutChallenge",
+			app:            apps.Default,
+			exchangeMutate: []oauth2.AuthCodeOption{oauth2.VerifierOption(pkceVerifier)},
+			tokenError:     "Invalid code verifier",
+		},
+		{
+			name: "PKCEPlainMethod",
+			app:  apps.Default,
+			authMutate: []oauth2.AuthCodeOption{
+				oauth2.SetAuthURLParam("code_challenge", pkceVerifier),
+				oauth2.SetAuthURLParam("code_challenge_method", "plain"),
+			},
+			authError: "Invalid query params",
+		},
 		{
 			name: "OK",
 			app:  apps.Default,
@@ -699,7 +745,7 @@ func TestOAuth2ProviderTokenExchange(t *testing.T) {
 				code = *test.defaultCode
 			} else {
 				var err error
-				code, err = authorizationFlow(ctx, userClient, valid)
+				code, err = authorizationFlow(ctx, userClient, valid, test.authMutate...)
 				if test.authError != "" {
 					require.Error(t, err)
 					require.ErrorContains(t, err, test.authError)
@@ -836,8 +882,9 @@ func TestOAuth2ProviderTokenRefresh(t *testing.T) {
 				ExpiresAt:   expires,
 				HashPrefix:  []byte(token.Prefix),
 				RefreshHash: []byte(token.Hashed),
-				AppSecretID: secret.ID,
+				AppSecretID: uuid.NullUUID{UUID: secret.ID, Valid: true},
 				APIKeyID:    newKey.ID,
+				AppID:       test.app.ID,
 			})
 			require.NoError(t, err)
 
@@ -1053,6 +1100,301 @@ func TestOAuth2ProviderRevoke(t *testing.T) {
 	}
 }
 
+func TestOAuth2ProviderPublicClient(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	//nolint:gocritic // OAauth2 app management requires owner permission.
+	app, err := client.PostOAuth2ProviderApp(ctx, codersdk.PostOAuth2ProviderAppRequest{
+		Name:         "public-client",
+		CallbackURL:  "http://localhost",
+		PublicClient: true,
+	})
+	require.NoError(t, err)
+	require.True(t, app.PublicClient)
+
+	t.Run("RequiresPKCE", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		userClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		cfg := publicClientConfig(app)
+		_, err := authorizationFlow(ctx, userClient, cfg)
+		require.Error(t, err)
+		require.ErrorContains(t, err, "Invalid query params")
+	})
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		userClient, user := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		cfg := publicClientConfig(app)
+		verifier := oauth2.GenerateVerifier()
+		code, err := authorizationFlow(ctx, userClient, cfg, oauth2.S256ChallengeOption(verifier))
+		require.NoError(t, err)
+
+		// No secret is sent, the verifier proves the client started the flow.
+		token, err := cfg.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+		require.NoError(t, err)
+
+		newClient := codersdk.New(client.URL)
+		newClient.SetSessionToken(token.AccessToken)
+		gotUser, err := newClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, user.ID, gotUser.ID)
+	})
+
+	t.Run("NoClientCredentials", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		//nolint:gocritic // OAauth2 app management requires owner permission.
+		_, err := client.PostOAuth2ProviderApp(ctx, codersdk.PostOAuth2ProviderAppRequest{
+			Name:                    "public-client-credentials",
+			CallbackURL:             "http://localhost",
+			PublicClient:            true,
+			ClientCredentialsUserID: &owner.UserID,
+		})
+		require.Error(t, err)
+		require.ErrorContains(t, err, "Public clients cannot use the client credentials grant")
+	})
+}
+
+func TestOAuth2ProviderClientCredentials(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	_, user := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	setup := func(ctx context.Context, t *testing.T, name string, userID *uuid.UUID) *clientcredentials.Config {
+		//nolint:gocritic // OAauth2 app management requires owner permission.
+		app, err := client.PostOAuth2ProviderApp(ctx, codersdk.PostOAuth2ProviderAppRequest{
+			Name:                    name,
+			CallbackURL:             "http://localhost",
+			ClientCredentialsUserID: userID,
+		})
+		require.NoError(t, err)
+		require.Equal(t, userID, app.ClientCredentialsUserID)
+
+		//nolint:gocritic // OAauth2 app management requires owner permission.
+		secret, err := client.PostOAuth2ProviderAppSecret(ctx, app.ID)
+		require.NoError(t, err)
+
+		return &clientcredentials.Config{
+			ClientID:     app.ID.String(),
+			ClientSecret: secret.ClientSecretFull,
+			TokenURL:     app.Endpoints.Token,
+			AuthStyle:    oauth2.AuthStyleInParams,
+		}
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		cfg := setup(ctx, t, "client-credentials-ok", &user.ID)
+		token, err := cfg.Token(ctx)
+		require.NoError(t, err)
+		require.NotEmpty(t, token.AccessToken)
+		require.Empty(t, token.RefreshToken)
+
+		newClient := codersdk.New(client.URL)
+		newClient.SetSessionToken(token.AccessToken)
+		gotUser, err := newClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, user.ID, gotUser.ID)
+	})
+
+	t.Run("AuthInHeader", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		cfg := setup(ctx, t, "client-credentials-header", &user.ID)
+		cfg.AuthStyle = oauth2.AuthStyleInHeader
+		_, err := cfg.Token(ctx)
+		require.NoError(t, err)
+	})
+
+	t.Run("BadSecret", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		cfg := setup(ctx, t, "client-credentials-bad-secret", &user.ID)
+		cfg.ClientSecret = "coder_prefix_secret"
+		_, err := cfg.Token(ctx)
+		require.Error(t, err)
+		require.ErrorContains(t, err, "Invalid client secret")
+	})
+
+	t.Run("NoUser", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		cfg := setup(ctx, t, "client-credentials-no-user", nil)
+		_, err := cfg.Token(ctx)
+		require.Error(t, err)
+		require.ErrorContains(t, err, "Client is not allowed to use this grant type")
+	})
+
+	t.Run("UnknownUser", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		//nolint:gocritic // OAauth2 app management requires owner permission.
+		_, err := client.PostOAuth2ProviderApp(ctx, codersdk.PostOAuth2ProviderAppRequest{
+			Name:                    "client-credentials-unknown",
+			CallbackURL:             "http://localhost",
+			ClientCredentialsUserID: ptr.Ref(uuid.New()),
+		})
+		require.Error(t, err)
+		require.ErrorContains(t, err, "does not exist")
+	})
+}
+
+func TestOAuth2ProviderDeviceAuthorization(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+
+	setup := func(ctx context.Context, t *testing.T, name string) *oauth2.Config {
+		//nolint:gocritic // OAauth2 app management requires owner permission.
+		app, err := client.PostOAuth2ProviderApp(ctx, codersdk.PostOAuth2ProviderAppRequest{
+			Name:        name,
+			CallbackURL: "http://localhost",
+		})
+		require.NoError(t, err)
+
+		//nolint:gocritic // OAauth2 app management requires owner permission.
+		secret, err := client.PostOAuth2ProviderAppSecret(ctx, app.ID)
+		require.NoError(t, err)
+
+		return &oauth2.Config{
+			ClientID:     app.ID.String(),
+			ClientSecret: secret.ClientSecretFull,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:       app.Endpoints.Authorization,
+				DeviceAuthURL: app.Endpoints.DeviceAuth,
+				TokenURL:      app.Endpoints.Token,
+				AuthStyle:     oauth2.AuthStyleInParams,
+			},
+			RedirectURL: app.CallbackURL,
+			Scopes:      []string{},
+		}
+	}
+
+	// verify mimics the user clicking allow or deny on the verification page.
+	verify := func(ctx context.Context, t *testing.T, userClient *codersdk.Client, da *oauth2.DeviceAuthResponse, action string, fromPage bool) {
+		verifyURL := must(url.Parse(da.VerificationURIComplete))
+		q := verifyURL.Query()
+		q.Set("action", action)
+		verifyURL.RawQuery = q.Encode()
+		res, err := userClient.Request(ctx, http.MethodGet, verifyURL.String(), nil, func(req *http.Request) {
+			if fromPage {
+				req.Header.Set("Referer", da.VerificationURI)
+			}
+		})
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+	}
+
+	// poll makes a single request to the token endpoint, unlike
+	// DeviceAccessToken which keeps polling until the device is approved.
+	poll := func(ctx context.Context, cfg *oauth2.Config, deviceCode string) error {
+		_, err := cfg.Exchange(ctx, "",
+			oauth2.SetAuthURLParam("grant_type", string(codersdk.OAuth2ProviderGrantTypeDeviceCode)),
+			oauth2.SetAuthURLParam("device_code", deviceCode),
+		)
+		return err
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		userClient, user := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		cfg := setup(ctx, t, "device-ok")
+		da, err := cfg.DeviceAuth(ctx)
+		require.NoError(t, err)
+		require.NotEmpty(t, da.DeviceCode)
+		require.Regexp(t, `^[A-Z]{4}-[A-Z]{4}$`, da.UserCode)
+		require.Equal(t, client.URL.String()+"/oauth2/device/verify", da.VerificationURI)
+
+		// The device is told to wait until the user approves.
+		err = poll(ctx, cfg, da.DeviceCode)
+		require.ErrorContains(t, err, "authorization_pending")
+		// Polling again immediately is too fast.
+		err = poll(ctx, cfg, da.DeviceCode)
+		require.ErrorContains(t, err, "slow_down")
+
+		// Requests that did not come from the page are ignored.
+		verify(ctx, t, userClient, da, "allow", false)
+		verify(ctx, t, userClient, da, "allow", true)
+
+		token, err := cfg.DeviceAccessToken(ctx, da)
+		require.NoError(t, err)
+
+		newClient := codersdk.New(client.URL)
+		newClient.SetSessionToken(token.AccessToken)
+		gotUser, err := newClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, user.ID, gotUser.ID)
+
+		// The device code can only be used once.
+		err = poll(ctx, cfg, da.DeviceCode)
+		require.ErrorContains(t, err, "Invalid code")
+	})
+
+	t.Run("Denied", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		userClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		cfg := setup(ctx, t, "device-denied")
+		da, err := cfg.DeviceAuth(ctx)
+		require.NoError(t, err)
+
+		verify(ctx, t, userClient, da, "deny", true)
+		err = poll(ctx, cfg, da.DeviceCode)
+		require.ErrorContains(t, err, "access_denied")
+	})
+
+	t.Run("BadSecret", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		cfg := setup(ctx, t, "device-bad-secret")
+		da, err := cfg.DeviceAuth(ctx)
+		require.NoError(t, err)
+
+		cfg.ClientSecret = "coder_prefix_secret"
+		err = poll(ctx, cfg, da.DeviceCode)
+		require.ErrorContains(t, err, "Invalid client secret")
+	})
+}
+
+func TestOAuth2AuthorizationServerMetadata(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	metadata, err := client.OAuth2AuthorizationServerMetadata(ctx)
+	require.NoError(t, err)
+	require.Equal(t, client.URL.String(), metadata.Issuer)
+	require.Equal(t, client.URL.String()+"/oauth2/authorize", metadata.AuthorizationEndpoint)
+	require.Equal(t, client.URL.String()+"/oauth2/tokens", metadata.TokenEndpoint)
+	require.Equal(t, client.URL.String()+"/oauth2/device", metadata.DeviceAuthorizationEndpoint)
+	require.Contains(t, metadata.GrantTypesSupported, codersdk.OAuth2ProviderGrantTypeDeviceCode)
+	require.Equal(t, []codersdk.OAuth2PKCECodeChallengeMethod{codersdk.OAuth2PKCECodeChallengeMethodS256}, metadata.CodeChallengeMethodsSupported)
+}
+
 type provisionedApps struct {
 	Default   codersdk.OAuth2ProviderApp
 	NoPort    codersdk.OAuth2ProviderApp
@@ -1087,10 +1429,10 @@ func generateApps(ctx context.Context, t *testing.T, client *codersdk.Client, su
 	}
 }
 
-func authorizationFlow(ctx context.Context, client *codersdk.Client, cfg *oauth2.Config) (string, error) {
+func authorizationFlow(ctx context.Context, client *codersdk.Client, cfg *oauth2.Config, opts ...oauth2.AuthCodeOption) (string, error) {
 	state := uuid.NewString()
 	return oidctest.OAuth2GetCode(
-		cfg.AuthCodeURL(state),
+		cfg.AuthCodeURL(state, opts...),
 		func(req *http.Request) (*http.Response, error) {
 			// TODO: Would be better if client had a .Do() method.
 			// TODO: Is this the best way to handle redirects?
@@ -1107,6 +1449,21 @@ func authorizationFlow(ctx context.Context, client *codersdk.Client, cfg *oauth2
 	)
 }
 
+// publicClientConfig returns a config for an app that has no secret.
+func publicClientConfig(app codersdk.OAuth2ProviderApp) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID: app.ID.String(),
+		Endpoint: oauth2.Endpoint{
+			AuthURL:       app.Endpoints.Authorization,
+			DeviceAuthURL: app.Endpoints.DeviceAuth,
+			TokenURL:      app.Endpoints.Token,
+			AuthStyle:     oauth2.AuthStyleInParams,
+		},
+		RedirectURL: app.CallbackURL,
+		Scopes:      []string{},
+	}
+}
+
 func must[T any](value T, err error) T {
 	if err != nil {
 		panic(err)
@@ -644,6 +644,119 @@ func AgentStats(ctx context.Context, logger slog.Logger, registerer prometheus.R
 	}, nil
 }
 
+// networkRegionsWindow is how far back the network reports of clients are
+// included in network region metrics.
+const networkRegionsWindow = 24 * time.Hour
+
+// NetworkRegions tracks how clients across the deployment reach each DERP
+// region, based on the network reports of the last day.
+func NetworkRegions(ctx context.Context, logger slog.Logger, registerer prometheus.Registerer, db database.Store, derpMapFn func() *tailcfg.DERPMap, duration time.Duration) (func(), error) {
+	if duration == 0 {
+		duration = defaultRefreshRate
+	}
+
+	clientsGauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "network",
+		Name:      "derp_region_clients",
+		Help:      "The number of clients that reported their latency to the DERP region in the last day.",
+	}, []string{"derp_region"})
+	if err := registerer.Register(clientsGauge); err != nil {
+		return nil, err
+	}
+
+	preferredClientsGauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "network",
+		Name:      "derp_region_preferred_clients",
+		Help:      "The number of clients that reported the DERP region as their preferred region in the last day.",
+	}, []string{"derp_region"})
+	if err := registerer.Register(preferredClientsGauge); err != nil {
+		return nil, err
+	}
+
+	latencyGauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "network",
+		Name:      "derp_region_latency_seconds",
+		Help:      "The latency clients reported to the DERP region in the last day, by quantile.",
+	}, []string{"derp_region", "quantile"})
+	if err := registerer.Register(latencyGauge); err != nil {
+		return nil, err
+	}
+
+	connectionsGauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "network",
+		Name:      "derp_region_connections",
+		Help:      "The number of connections of the clients the DERP region is the home region of, by whether they're direct or relayed.",
+	}, []string{"derp_region", "type"})
+	if err := registerer.Register(connectionsGauge); err != nil {
+		return nil, err
+	}
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	// nolint:gocritic // Prometheus must collect metrics for all clients.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	done := make(chan struct{})
+
+	updateNetworkRegions := func() {
+		rows, err := db.GetNetworkRegionInsights(ctx, database.GetNetworkRegionInsightsParams{
+			ReportedAfter: dbtime.Now().Add(-networkRegionsWindow),
+		})
+		if err != nil {
+			logger.Warn(ctx, "failed to load network region insights", slog.Error(err))
+			return
+		}
+
+		derpMap := derpMapFn()
+		clientsGauge.Reset()
+		preferredClientsGauge.Reset()
+		latencyGauge.Reset()
+		connectionsGauge.Reset()
+		for _, row := range rows {
+			regionName := fmt.Sprintf("Unnamed %d", row.RegionID)
+			if derpMap != nil {
+				if region, ok := derpMap.Regions[int(row.RegionID)]; ok && region != nil {
+					regionName = region.RegionName
+				}
+			}
+
+			clientsGauge.WithLabelValues(regionName).Set(float64(row.Clients))
+			preferredClientsGauge.WithLabelValues(regionName).Set(float64(row.PreferredClients))
+			if row.Latency50 >= 0 {
+				latencyGauge.WithLabelValues(regionName, "0.5").Set(row.Latency50 / 1000)
+			}
+			if row.Latency95 >= 0 {
+				latencyGauge.WithLabelValues(regionName, "0.95").Set(row.Latency95 / 1000)
+			}
+			connectionsGauge.WithLabelValues(regionName, "direct").Set(float64(row.DirectConnections))
+			connectionsGauge.WithLabelValues(regionName, "relayed").Set(float64(row.RelayedConnections))
+		}
+	}
+
+	// Use time.Nanosecond to force an initial tick. It will be reset to the
+	// correct duration after executing once.
+	ticker := time.NewTicker(time.Nanosecond)
+	go func() {
+		defer close(done)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				updateNetworkRegions()
+				ticker.Reset(duration)
+			}
+		}
+	}()
+	return func() {
+		cancelFunc()
+		<-done
+	}, nil
+}
+
 // Experiments registers a metric which indicates whether each experiment is enabled or not.
 func Experiments(registerer prometheus.Registerer, active codersdk.Experiments) error {
 	experimentsGauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
@@ -5,8 +5,10 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"reflect"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -613,6 +615,77 @@ func TestAgentStats(t *testing.T) {
 	assert.EqualValues(t, golden, collected)
 }
 
+func TestNetworkRegions(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	db := dbmem.New()
+	now := dbtime.Now()
+	err := db.UpsertNetworkLatencyReports(ctx, database.UpsertNetworkLatencyReportsParams{
+		ClientID:   []uuid.UUID{uuid.New(), uuid.New()},
+		ClientType: []string{"agent", "cli"},
+		RegionID:   []int32{1, 1},
+		LatencyMS:  []float64{10, 30},
+		Preferred:  []bool{true, false},
+		ReportedAt: []time.Time{now, now},
+	})
+	require.NoError(t, err)
+	err = db.UpsertNetworkConnectionReports(ctx, database.UpsertNetworkConnectionReportsParams{
+		ClientID:     []uuid.UUID{uuid.New(), uuid.New()},
+		RemoteNodeID: []int64{1, 2},
+		ClientType:   []string{"agent", "cli"},
+		HomeRegionID: []int32{1, 2},
+		Direct:       []bool{true, false},
+		ReportedAt:   []time.Time{now, now},
+	})
+	require.NoError(t, err)
+
+	derpMap := &tailcfg.DERPMap{
+		Regions: map[int]*tailcfg.DERPRegion{
+			1: {RegionID: 1, RegionName: "Frankfurt"},
+		},
+	}
+	registry := prometheus.NewRegistry()
+	closeFunc, err := prometheusmetrics.NetworkRegions(ctx, testutil.Logger(t), registry, db, func() *tailcfg.DERPMap {
+		return derpMap
+	}, time.Millisecond)
+	require.NoError(t, err)
+	t.Cleanup(closeFunc)
+
+	want := map[string]float64{
+		"coderd_network_derp_region_clients[derp_region=Frankfurt]":                       2,
+		"coderd_network_derp_region_preferred_clients[derp_region=Frankfurt]":             1,
+		"coderd_network_derp_region_latency_seconds[derp_region=Frankfurt,quantile=0.5]":  0.02,
+		"coderd_network_derp_region_connections[derp_region=Frankfurt,type=direct]":       1,
+		"coderd_network_derp_region_connections[derp_region=Frankfurt,type=relayed]":      0,
+		"coderd_network_derp_region_connections[derp_region=Unnamed 2,type=relayed]":      1,
+		"coderd_network_derp_region_preferred_clients[derp_region=Unnamed 2]":             0,
+		"coderd_network_derp_region_latency_seconds[derp_region=Frankfurt,quantile=0.95]": 0.029,
+	}
+	require.Eventually(t, func() bool {
+		metrics, err := registry.Gather()
+		if !assert.NoError(t, err) {
+			return false
+		}
+		got := map[string]float64{}
+		for _, metric := range metrics {
+			for _, m := range metric.Metric {
+				var labels []string
+				for _, label := range m.Label {
+					labels = append(labels, label.GetName()+"="+label.GetValue())
+				}
+				got[fmt.Sprintf("%s[%s]", metric.GetName(), strings.Join(labels, ","))] = m.Gauge.GetValue()
+			}
+		}
+		for name, value := range want {
+			if v, ok := got[name]; !ok || math.Abs(v-value) > 0.001 {
+				return false
+			}
+		}
+		return true
+	}, testutil.WaitShort, testutil.IntervalFast)
+}
+
 func TestExperimentsMetric(t *testing.T) {
 	t.Parallel()
 
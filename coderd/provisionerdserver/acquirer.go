@@ -49,6 +49,12 @@ type Acquirer struct {
 	mu sync.Mutex
 	q  map[dKey]domain
 
+	// maxOrganizationJobs and maxUserJobs limit the number of jobs that run
+	// concurrently for an organization and for the user that initiated them.
+	// 0 means there is no limit.
+	maxOrganizationJobs int32
+	maxUserJobs         int32
+
 	// testing only
 	backupPollDuration time.Duration
 }
@@ -61,6 +67,16 @@ func TestingBackupPollDuration(dur time.Duration) AcquirerOption {
 	}
 }
 
+// ConcurrencyLimits limits the number of jobs that run concurrently for each
+// organization and for each user, so that a burst of jobs doesn't occupy every
+// provisioner. 0 means there is no limit.
+func ConcurrencyLimits(maxOrganizationJobs, maxUserJobs int32) AcquirerOption {
+	return func(a *Acquirer) {
+		a.maxOrganizationJobs = maxOrganizationJobs
+		a.maxUserJobs = maxUserJobs
+	}
+}
+
 // AcquirerStore is the subset of database.Store that the Acquirer needs
 type AcquirerStore interface {
 	AcquireProvisionerJob(context.Context, database.AcquireProvisionerJobParams) (database.ProvisionerJob, error)
@@ -130,8 +146,10 @@ func (a *Acquirer) AcquireJob(
 					UUID:  worker,
 					Valid: true,
 				},
-				Types:           pt,
-				ProvisionerTags: dbTags,
+				Types:               pt,
+				ProvisionerTags:     dbTags,
+				MaxOrganizationJobs: a.maxOrganizationJobs,
+				MaxUserJobs:         a.maxUserJobs,
 			})
 			if xerrors.Is(err, sql.ErrNoRows) {
 				logger.Debug(ctx, "no job available")
@@ -153,6 +171,12 @@ func (a *Acquirer) AcquireJob(
 	}
 }
 
+// limitsConcurrency returns whether jobs can be held back until other jobs
+// complete.
+func (a *Acquirer) limitsConcurrency() bool {
+	return a.maxOrganizationJobs > 0 || a.maxUserJobs > 0
+}
+
 // want signals that an acquiree wants clearance to query for a job with the given dKey.
 func (a *Acquirer) want(organization uuid.UUID, pt []database.ProvisionerType, tags Tags, clearance chan<- struct{}) {
 	dk := domainKey(organization, pt, tags)
@@ -18,6 +18,7 @@ import (
 	"golang.org/x/exp/slices"
 
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/database/dbmem"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
@@ -552,6 +553,155 @@ func TestAcquirer_MatchTags(t *testing.T) {
 	})
 }
 
+func TestAcquirer_Scheduling(t *testing.T) {
+	t.Parallel()
+
+	untagged := map[string]string{"scope": "organization", "owner": ""}
+	ptypes := []database.ProvisionerType{database.ProvisionerTypeEcho}
+
+	setup := func(t *testing.T) (database.Store, pubsub.Pubsub, database.Organization) {
+		// NOTE: explicitly not using fake store for this test.
+		db, ps := dbtestutil.NewDB(t)
+		org := dbgen.Organization(t, db, database.Organization{})
+		return db, ps, org
+	}
+	pendingJob := func(t *testing.T, db database.Store, org database.Organization, initiator uuid.UUID, priority int32, age time.Duration) database.ProvisionerJob {
+		return dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+			CreatedAt:      dbtime.Now().Add(-age),
+			OrganizationID: org.ID,
+			InitiatorID:    initiator,
+			Tags:           untagged,
+			Priority:       priority,
+		})
+	}
+	runningJob := func(t *testing.T, db database.Store, org database.Organization, initiator uuid.UUID) database.ProvisionerJob {
+		return dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+			OrganizationID: org.ID,
+			InitiatorID:    initiator,
+			StartedAt:      sql.NullTime{Time: dbtime.Now(), Valid: true},
+		})
+	}
+
+	t.Run("Priority", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db, ps, org := setup(t)
+		initiator := uuid.New()
+		templateImport := pendingJob(t, db, org, initiator, provisionerdserver.JobPriorityTemplateVersion, 3*time.Minute)
+		autostart := pendingJob(t, db, org, initiator, provisionerdserver.WorkspaceBuildJobPriority(database.BuildReasonAutostart), 2*time.Minute)
+		userBuild := pendingJob(t, db, org, initiator, provisionerdserver.WorkspaceBuildJobPriority(database.BuildReasonInitiator), time.Minute)
+
+		// The queue position reflects the priority of jobs.
+		jobs, err := db.GetProvisionerJobsByIDsWithQueuePosition(ctx, []uuid.UUID{templateImport.ID, autostart.ID, userBuild.ID})
+		require.NoError(t, err)
+		positions := map[uuid.UUID]int64{}
+		for _, job := range jobs {
+			positions[job.ProvisionerJob.ID] = job.QueuePosition
+		}
+		require.Equal(t, map[uuid.UUID]int64{
+			userBuild.ID:      1,
+			autostart.ID:      2,
+			templateImport.ID: 3,
+		}, positions)
+
+		acq := provisionerdserver.NewAcquirer(ctx, testutil.Logger(t), db, ps)
+		for _, want := range []database.ProvisionerJob{userBuild, autostart, templateImport} {
+			got, err := acq.AcquireJob(ctx, org.ID, uuid.New(), ptypes, untagged)
+			require.NoError(t, err)
+			require.Equal(t, want.ID, got.ID)
+		}
+	})
+
+	t.Run("FairShare", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db, ps, org := setup(t)
+		busyUser, idleUser := uuid.New(), uuid.New()
+		_ = runningJob(t, db, org, busyUser)
+		busyJob := pendingJob(t, db, org, busyUser, provisionerdserver.JobPriorityUser, 2*time.Minute)
+		idleJob := pendingJob(t, db, org, idleUser, provisionerdserver.JobPriorityUser, time.Minute)
+
+		// The user without running jobs goes first, even though their job
+		// was queued later.
+		acq := provisionerdserver.NewAcquirer(ctx, testutil.Logger(t), db, ps)
+		got, err := acq.AcquireJob(ctx, org.ID, uuid.New(), ptypes, untagged)
+		require.NoError(t, err)
+		require.Equal(t, idleJob.ID, got.ID)
+		got, err = acq.AcquireJob(ctx, org.ID, uuid.New(), ptypes, untagged)
+		require.NoError(t, err)
+		require.Equal(t, busyJob.ID, got.ID)
+	})
+
+	t.Run("ConcurrencyLimits", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db, ps, org := setup(t)
+		busyUser, otherUser := uuid.New(), uuid.New()
+		running := runningJob(t, db, org, busyUser)
+		busyJob := pendingJob(t, db, org, busyUser, provisionerdserver.JobPriorityUser, 2*time.Minute)
+		otherJob := pendingJob(t, db, org, otherUser, provisionerdserver.JobPriorityTemplateVersion, time.Minute)
+
+		acq := provisionerdserver.NewAcquirer(ctx, testutil.Logger(t), db, ps,
+			provisionerdserver.ConcurrencyLimits(0, 1))
+		got, err := acq.AcquireJob(ctx, org.ID, uuid.New(), ptypes, untagged)
+		require.NoError(t, err)
+		require.Equal(t, otherJob.ID, got.ID, "the job of the user at their limit should be skipped")
+
+		// Nothing can be acquired until the running job of the user completes.
+		waitCtx, cancel := context.WithTimeout(ctx, testutil.IntervalMedium)
+		defer cancel()
+		_, err = acq.AcquireJob(waitCtx, org.ID, uuid.New(), ptypes, untagged)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		err = db.UpdateProvisionerJobWithCompleteByID(ctx, database.UpdateProvisionerJobWithCompleteByIDParams{
+			ID:          running.ID,
+			UpdatedAt:   dbtime.Now(),
+			CompletedAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+		})
+		require.NoError(t, err)
+		got, err = acq.AcquireJob(ctx, org.ID, uuid.New(), ptypes, untagged)
+		require.NoError(t, err)
+		require.Equal(t, busyJob.ID, got.ID)
+	})
+
+	t.Run("PendingTags", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db, _, org := setup(t)
+		otherOrg := dbgen.Organization(t, db, database.Organization{})
+		user := uuid.New()
+		tagged := map[string]string{"scope": "organization", "owner": "", "env": "prod"}
+		_ = pendingJob(t, db, org, uuid.New(), provisionerdserver.JobPriorityUser, time.Minute)
+		_ = pendingJob(t, db, org, uuid.New(), provisionerdserver.JobPriorityUser, time.Minute)
+		_ = dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+			OrganizationID: org.ID,
+			InitiatorID:    uuid.New(),
+			Tags:           tagged,
+		})
+		// Jobs of the user in another organization are held back by the
+		// limit of the user.
+		_ = pendingJob(t, db, otherOrg, user, provisionerdserver.JobPriorityUser, time.Minute)
+		// Jobs of other users in other organizations are not affected.
+		_ = dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+			OrganizationID: otherOrg.ID,
+			InitiatorID:    uuid.New(),
+			Tags:           tagged,
+		})
+		_ = runningJob(t, db, org, user)
+
+		rows, err := db.GetPendingProvisionerJobTags(ctx, database.GetPendingProvisionerJobTagsParams{
+			OrganizationID: org.ID,
+			InitiatorID:    user,
+		})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []database.GetPendingProvisionerJobTagsRow{
+			{OrganizationID: org.ID, Provisioner: database.ProvisionerTypeEcho, Tags: untagged},
+			{OrganizationID: org.ID, Provisioner: database.ProvisionerTypeEcho, Tags: tagged},
+			{OrganizationID: otherOrg.ID, Provisioner: database.ProvisionerTypeEcho, Tags: untagged},
+		}, rows)
+	})
+}
+
 func postJob(t *testing.T, ps pubsub.Pubsub, pt database.ProvisionerType, tags provisionerdserver.Tags) {
 	t.Helper()
 	msg, err := json.Marshal(provisionerjobs.JobPosting{
@@ -0,0 +1,26 @@
+package provisionerdserver
+
+import "github.com/coder/coder/v2/coderd/database"
+
+// Pending provisioner jobs with a higher priority are acquired before jobs
+// with a lower priority, regardless of when they were queued. This keeps users
+// from waiting behind a burst of autostarts or template imports.
+const (
+	// JobPriorityTemplateVersion is the priority of template version imports
+	// and dry-runs.
+	JobPriorityTemplateVersion int32 = 0
+	// JobPriorityAutobuild is the priority of workspace builds started by
+	// coderd, e.g. autostart, autostop and dormancy.
+	JobPriorityAutobuild int32 = 10
+	// JobPriorityUser is the priority of workspace builds started by a user.
+	JobPriorityUser int32 = 20
+)
+
+// WorkspaceBuildJobPriority returns the priority of the provisioner job for a
+// workspace build started for the given reason.
+func WorkspaceBuildJobPriority(reason database.BuildReason) int32 {
+	if reason == database.BuildReasonInitiator {
+		return JobPriorityUser
+	}
+	return JobPriorityAutobuild
+}
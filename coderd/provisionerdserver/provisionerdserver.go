@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"net/url"
 	"reflect"
@@ -27,11 +28,13 @@ import (
 
 	"cdr.dev/slog"
 
+	"github.com/coder/coder/v2/apiversion"
 	"github.com/coder/coder/v2/coderd/apikey"
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
 	"github.com/coder/coder/v2/coderd/externalauth"
 	"github.com/coder/coder/v2/coderd/notifications"
@@ -82,6 +85,15 @@ type Options struct {
 	// The default function just calls UpdateProvisionerDaemonLastSeenAt.
 	// This is mainly used for testing.
 	HeartbeatFn func(context.Context) error
+
+	// WorkspaceBuildCompletedFn is called after the job of a workspace build
+	// completed or failed. It is optional.
+	WorkspaceBuildCompletedFn func(ctx context.Context, build database.WorkspaceBuild)
+
+	// APIVersion is the provisionerd API version of the daemon. Jobs which
+	// need a newer version are failed instead of being sent to the daemon.
+	// Defaults to the current version.
+	APIVersion string
 }
 
 type server struct {
@@ -113,9 +125,12 @@ type server struct {
 	Clock quartz.Clock
 
 	acquireJobLongPollDur time.Duration
+	apiVersion            string
 
 	heartbeatInterval time.Duration
 	heartbeatFn       func(ctx context.Context) error
+
+	workspaceBuildCompletedFn func(ctx context.Context, build database.WorkspaceBuild)
 }
 
 // We use the null byte (0x00) in generating a canonical map key for tags, so
@@ -200,6 +215,9 @@ func NewServer(
 	if options.Clock == nil {
 		options.Clock = quartz.NewReal()
 	}
+	if options.APIVersion == "" {
+		options.APIVersion = proto.CurrentVersion.String()
+	}
 
 	s := &server{
 		lifecycleCtx:                lifecycleCtx,
@@ -224,8 +242,10 @@ func NewServer(
 		OIDCConfig:                  options.OIDCConfig,
 		Clock:                       options.Clock,
 		acquireJobLongPollDur:       options.AcquireJobLongPollDur,
+		apiVersion:                  options.APIVersion,
 		heartbeatInterval:           options.HeartbeatInterval,
 		heartbeatFn:                 options.HeartbeatFn,
+		workspaceBuildCompletedFn:   options.WorkspaceBuildCompletedFn,
 	}
 
 	if s.heartbeatFn == nil {
@@ -540,6 +560,37 @@ func (s *server) acquireProtoJob(ctx context.Context, job database.ProvisionerJo
 			return nil, failJob(fmt.Sprintf("convert workspace transition: %s", err))
 		}
 
+		var (
+			snapshotID        string
+			snapshotName      string
+			snapshotArtifacts []*sdkproto.Snapshot
+		)
+		if input.SnapshotID != uuid.Nil {
+			// Older daemons do not know the snapshot and restore transitions,
+			// and would run the build as a regular start or stop.
+			if !s.apiVersionAtLeast(1, 3) {
+				return nil, failJob(fmt.Sprintf("workspace snapshots require provisioner daemons with API version 1.3 or later, but this daemon uses %s, upgrade the provisioner daemon", s.apiVersion))
+			}
+			snapshot, err := s.Database.GetWorkspaceSnapshotByID(ctx, input.SnapshotID)
+			if err != nil {
+				return nil, failJob(fmt.Sprintf("get workspace snapshot: %s", err))
+			}
+			snapshotID = snapshot.ID.String()
+			snapshotName = snapshot.Name
+			switch workspaceBuild.Transition {
+			case database.WorkspaceTransitionStop:
+				transition = sdkproto.WorkspaceTransition_SNAPSHOT
+			case database.WorkspaceTransitionStart:
+				transition = sdkproto.WorkspaceTransition_RESTORE
+				snapshotArtifacts, err = convertSnapshotArtifacts(snapshot.Artifacts)
+				if err != nil {
+					return nil, failJob(fmt.Sprintf("convert workspace snapshot artifacts: %s", err))
+				}
+			default:
+				return nil, failJob(fmt.Sprintf("workspace snapshots cannot be used with the %q transition", workspaceBuild.Transition))
+			}
+		}
+
 		workspaceBuildParameters, err := s.Database.GetWorkspaceBuildParameters(ctx, workspaceBuild.ID)
 		if err != nil {
 			return nil, failJob(fmt.Sprintf("get workspace build parameters: %s", err))
@@ -621,6 +672,9 @@ func (s *server) acquireProtoJob(ctx context.Context, job database.ProvisionerJo
 					WorkspaceOwnerSshPrivateKey:   ownerSSHPrivateKey,
 					WorkspaceBuildId:              workspaceBuild.ID.String(),
 					WorkspaceOwnerLoginType:       string(owner.LoginType),
+					WorkspaceSnapshotId:           snapshotID,
+					WorkspaceSnapshotName:         snapshotName,
+					WorkspaceSnapshotArtifacts:    snapshotArtifacts,
 				},
 				LogLevel: input.LogLevel,
 			},
@@ -641,14 +695,65 @@ func (s *server) acquireProtoJob(ctx context.Context, job database.ProvisionerJo
 			return nil, failJob(fmt.Sprintf("get template version variables: %s", err))
 		}
 
+		metadata := &sdkproto.Metadata{
+			CoderUrl:      s.AccessURL.String(),
+			WorkspaceName: input.WorkspaceName,
+		}
+		var state []byte
+		if input.WorkspaceID != uuid.Nil {
+			// Previewing an update to an existing workspace, so plan against
+			// its current state and identity. Otherwise every resource would
+			// show up as being created, and anything derived from the
+			// workspace or owner would show up as changed. Credentials are
+			// deliberately left out since nothing is applied.
+			workspace, err := s.Database.GetWorkspaceByID(ctx, input.WorkspaceID)
+			if err != nil {
+				return nil, failJob(fmt.Sprintf("get workspace: %s", err))
+			}
+			latestBuild, err := s.Database.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspace.ID)
+			if err != nil {
+				return nil, failJob(fmt.Sprintf("get latest workspace build: %s", err))
+			}
+			template, err := s.Database.GetTemplateByID(ctx, templateVersion.TemplateID.UUID)
+			if err != nil {
+				return nil, failJob(fmt.Sprintf("get template: %s", err))
+			}
+			owner, err := s.Database.GetUserByID(ctx, workspace.OwnerID)
+			if err != nil {
+				return nil, failJob(fmt.Sprintf("get owner: %s", err))
+			}
+			ownerGroups, err := s.Database.GetGroups(ctx, database.GetGroupsParams{
+				HasMemberID:    owner.ID,
+				OrganizationID: s.OrganizationID,
+			})
+			if err != nil {
+				return nil, failJob(fmt.Sprintf("get owner group names: %s", err))
+			}
+			ownerGroupNames := []string{}
+			for _, group := range ownerGroups {
+				ownerGroupNames = append(ownerGroupNames, group.Group.Name)
+			}
+
+			state = latestBuild.ProvisionerState
+			metadata.WorkspaceName = workspace.Name
+			metadata.WorkspaceId = workspace.ID.String()
+			metadata.WorkspaceOwner = owner.Username
+			metadata.WorkspaceOwnerEmail = owner.Email
+			metadata.WorkspaceOwnerName = owner.Name
+			metadata.WorkspaceOwnerGroups = ownerGroupNames
+			metadata.WorkspaceOwnerId = owner.ID.String()
+			metadata.WorkspaceOwnerLoginType = string(owner.LoginType)
+			metadata.TemplateId = template.ID.String()
+			metadata.TemplateName = template.Name
+			metadata.TemplateVersion = templateVersion.Name
+		}
+
 		protoJob.Type = &proto.AcquiredJob_TemplateDryRun_{
 			TemplateDryRun: &proto.AcquiredJob_TemplateDryRun{
 				RichParameterValues: convertRichParameterValues(input.RichParameterValues),
 				VariableValues:      asVariableValues(templateVariables),
-				Metadata: &sdkproto.Metadata{
-					CoderUrl:      s.AccessURL.String(),
-					WorkspaceName: input.WorkspaceName,
-				},
+				Metadata:            metadata,
+				State:               state,
 			},
 		}
 	case database.ProvisionerJobTypeTemplateVersionImport:
@@ -1050,6 +1155,9 @@ func (s *server) FailJob(ctx context.Context, failJob *proto.FailedJob) (*proto.
 		if err != nil {
 			return nil, xerrors.Errorf("publish workspace update: %w", err)
 		}
+		if s.workspaceBuildCompletedFn != nil {
+			s.workspaceBuildCompletedFn(ctx, build)
+		}
 	case *proto.FailedJob_TemplateImport_:
 	}
 
@@ -1108,6 +1216,8 @@ func (s *server) FailJob(ctx context.Context, failJob *proto.FailedJob) (*proto.
 		}
 	}
 
+	s.postCompletedJob(ctx, job)
+
 	data, err := json.Marshal(provisionersdk.ProvisionerJobLogsNotifyMessage{EndOfLogs: true})
 	if err != nil {
 		return nil, xerrors.Errorf("marshal job log: %w", err)
@@ -1520,6 +1630,24 @@ func (s *server) CompleteJob(ctx context.Context, completed *proto.CompletedJob)
 					return xerrors.Errorf("insert provisioner job module: %w", err)
 				}
 			}
+			if input.SnapshotID != uuid.Nil && workspaceBuild.Transition == database.WorkspaceTransitionStop {
+				artifacts := make(map[string]string, len(jobType.WorkspaceBuild.Snapshots))
+				for _, snapshot := range jobType.WorkspaceBuild.Snapshots {
+					artifacts[snapshot.Name] = snapshot.Artifact
+				}
+				data, err := json.Marshal(artifacts)
+				if err != nil {
+					return xerrors.Errorf("marshal workspace snapshot artifacts: %w", err)
+				}
+				err = db.UpdateWorkspaceSnapshotArtifactsByID(ctx, database.UpdateWorkspaceSnapshotArtifactsByIDParams{
+					ID:        input.SnapshotID,
+					Artifacts: data,
+					UpdatedAt: now,
+				})
+				if err != nil {
+					return xerrors.Errorf("update workspace snapshot artifacts: %w", err)
+				}
+			}
 
 			// On start, we want to ensure that workspace agents timeout statuses
 			// are propagated. This method is simple and does not protect against
@@ -1627,6 +1755,10 @@ func (s *server) CompleteJob(ctx context.Context, completed *proto.CompletedJob)
 			// Don't fail the transaction for non-critical data.
 			s.Logger.Warn(ctx, "failed to update provisioner job timings", slog.F("job_id", jobID), slog.Error(err))
 		}
+		err = InsertProvisionerJobResourceChanges(ctx, s.Database, jobID, completed.GetWorkspaceBuild().GetResourceChanges())
+		if err != nil {
+			s.Logger.Warn(ctx, "failed to insert provisioner job resource changes", slog.F("job_id", jobID), slog.Error(err))
+		}
 
 		// audit the outcome of the workspace build
 		if getWorkspaceError == nil {
@@ -1689,6 +1821,9 @@ func (s *server) CompleteJob(ctx context.Context, completed *proto.CompletedJob)
 		if err != nil {
 			return nil, xerrors.Errorf("update workspace: %w", err)
 		}
+		if s.workspaceBuildCompletedFn != nil {
+			s.workspaceBuildCompletedFn(ctx, workspaceBuild)
+		}
 	case *proto.CompletedJob_TemplateDryRun_:
 		for _, resource := range jobType.TemplateDryRun.Resources {
 			s.Logger.Info(ctx, "inserting template dry-run job resource",
@@ -1711,6 +1846,10 @@ func (s *server) CompleteJob(ctx context.Context, completed *proto.CompletedJob)
 				return nil, xerrors.Errorf("insert module: %w", err)
 			}
 		}
+		err = InsertProvisionerJobResourceChanges(ctx, s.Database, jobID, jobType.TemplateDryRun.ResourceChanges)
+		if err != nil {
+			return nil, xerrors.Errorf("insert resource changes: %w", err)
+		}
 
 		err = s.Database.UpdateProvisionerJobWithCompleteByID(ctx, database.UpdateProvisionerJobWithCompleteByIDParams{
 			ID:        jobID,
@@ -1735,6 +1874,8 @@ func (s *server) CompleteJob(ctx context.Context, completed *proto.CompletedJob)
 			reflect.TypeOf(completed.Type).String())
 	}
 
+	s.postCompletedJob(ctx, job)
+
 	data, err := json.Marshal(provisionersdk.ProvisionerJobLogsNotifyMessage{EndOfLogs: true})
 	if err != nil {
 		return nil, xerrors.Errorf("marshal job log: %w", err)
@@ -1786,6 +1927,38 @@ func (s *server) notifyWorkspaceDeleted(ctx context.Context, workspace database.
 	}
 }
 
+// postCompletedJob wakes up provisioners that might be waiting to acquire a job
+// that was held back by the concurrency limits until a running job completed.
+// The freed slot counts against the limits of the organization and the user, so
+// every set of tags with pending jobs in either is posted.
+func (s *server) postCompletedJob(ctx context.Context, job database.ProvisionerJob) {
+	if s.Acquirer == nil || !s.Acquirer.limitsConcurrency() {
+		return
+	}
+	pending, err := s.Database.GetPendingProvisionerJobTags(ctx, database.GetPendingProvisionerJobTagsParams{
+		OrganizationID: job.OrganizationID,
+		InitiatorID:    job.InitiatorID,
+	})
+	if err != nil {
+		s.Logger.Warn(ctx, "failed to get pending job tags", slog.F("job_id", job.ID), slog.Error(err))
+		pending = []database.GetPendingProvisionerJobTagsRow{{
+			OrganizationID: job.OrganizationID,
+			Provisioner:    job.Provisioner,
+			Tags:           job.Tags,
+		}}
+	}
+	for _, p := range pending {
+		err = provisionerjobs.PostJob(s.Pubsub, database.ProvisionerJob{
+			OrganizationID: p.OrganizationID,
+			Provisioner:    p.Provisioner,
+			Tags:           p.Tags,
+		})
+		if err != nil {
+			s.Logger.Warn(ctx, "failed to post completed job", slog.F("job_id", job.ID), slog.Error(err))
+		}
+	}
+}
+
 func (s *server) startTrace(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
 	return s.Tracer.Start(ctx, name, append(opts, trace.WithAttributes(
 		semconv.ServiceNameKey.String("coderd.provisionerd"),
@@ -1820,6 +1993,7 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 		Hide:       protoResource.Hide,
 		Icon:       protoResource.Icon,
 		DailyCost:  protoResource.DailyCost,
+		HourlyCost: resourceHourlyCost(protoResource),
 		InstanceType: sql.NullString{
 			String: protoResource.InstanceType,
 			Valid:  protoResource.InstanceType != "",
@@ -2076,6 +2250,31 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 	return nil
 }
 
+// hourlyCostMetadataKey is the key of the coder_metadata item that declares
+// the hourly cost of a resource, e.g.
+//
+//	item {
+//	  key   = "hourly_cost"
+//	  value = "0.12"
+//	}
+const hourlyCostMetadataKey = "hourly_cost"
+
+// resourceHourlyCost returns the hourly cost declared in the metadata of a
+// resource. Resources without a valid, non-negative cost are free.
+func resourceHourlyCost(resource *sdkproto.Resource) float64 {
+	for _, metadatum := range resource.Metadata {
+		if metadatum.IsNull || metadatum.Key != hourlyCostMetadataKey {
+			continue
+		}
+		cost, err := strconv.ParseFloat(strings.TrimSpace(metadatum.Value), 64)
+		if err != nil || cost < 0 || math.IsNaN(cost) || math.IsInf(cost, 0) {
+			return 0
+		}
+		return cost
+	}
+	return 0
+}
+
 func workspaceSessionTokenName(workspace database.Workspace) string {
 	return fmt.Sprintf("%s_%s_session_token", workspace.OwnerID, workspace.ID)
 }
@@ -2246,6 +2445,39 @@ func convertWorkspaceTransition(transition database.WorkspaceTransition) (sdkpro
 	}
 }
 
+// apiVersionAtLeast reports whether the daemon supports at least the given
+// provisionerd API version.
+func (s *server) apiVersionAtLeast(major, minor int) bool {
+	daemonMajor, daemonMinor, err := apiversion.Parse(s.apiVersion)
+	if err != nil {
+		return false
+	}
+	return daemonMajor > major || (daemonMajor == major && daemonMinor >= minor)
+}
+
+// convertSnapshotArtifacts converts the artifacts stored on a workspace
+// snapshot, a JSON object of snapshot resource names to artifacts.
+func convertSnapshotArtifacts(raw json.RawMessage) ([]*sdkproto.Snapshot, error) {
+	artifacts := map[string]string{}
+	if len(raw) > 0 {
+		err := json.Unmarshal(raw, &artifacts)
+		if err != nil {
+			return nil, xerrors.Errorf("unmarshal artifacts: %w", err)
+		}
+	}
+	snapshots := make([]*sdkproto.Snapshot, 0, len(artifacts))
+	for name, artifact := range artifacts {
+		snapshots = append(snapshots, &sdkproto.Snapshot{
+			Name:     name,
+			Artifact: artifact,
+		})
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Name < snapshots[j].Name
+	})
+	return snapshots, nil
+}
+
 func auditActionFromTransition(transition database.WorkspaceTransition) database.AuditAction {
 	switch transition {
 	case database.WorkspaceTransitionStart:
@@ -2269,6 +2501,9 @@ type WorkspaceProvisionJob struct {
 	WorkspaceBuildID uuid.UUID `json:"workspace_build_id"`
 	DryRun           bool      `json:"dry_run"`
 	LogLevel         string    `json:"log_level,omitempty"`
+	// SnapshotID is set when the build creates (stop) or restores from (start)
+	// a workspace snapshot.
+	SnapshotID uuid.UUID `json:"snapshot_id,omitempty"`
 }
 
 // TemplateVersionDryRunJob is the payload for the "template_version_dry_run" job type.
@@ -2276,6 +2511,53 @@ type TemplateVersionDryRunJob struct {
 	TemplateVersionID   uuid.UUID                          `json:"template_version_id"`
 	WorkspaceName       string                             `json:"workspace_name"`
 	RichParameterValues []database.WorkspaceBuildParameter `json:"rich_parameter_values"`
+	// WorkspaceID is set when previewing an update to an existing workspace.
+	WorkspaceID uuid.UUID `json:"workspace_id,omitempty"`
+}
+
+// InsertProvisionerJobResourceChanges stores the summary of changes the plan
+// for a job made, or would make, to each resource.
+func InsertProvisionerJobResourceChanges(ctx context.Context, db database.Store, jobID uuid.UUID, changes []*sdkproto.ResourceChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	params := database.InsertProvisionerJobResourceChangesParams{
+		JobID: jobID,
+	}
+	attributes := make([][]codersdk.ProvisionerJobResourceAttributeChange, 0, len(changes))
+	for _, change := range changes {
+		action := database.ProvisionerJobResourceChangeAction(change.Action)
+		if !action.Valid() {
+			return xerrors.Errorf("invalid action %q for resource %q", change.Action, change.Address)
+		}
+		params.Address = append(params.Address, change.Address)
+		params.ResourceType = append(params.ResourceType, change.Type)
+		params.ResourceName = append(params.ResourceName, change.Name)
+		params.Action = append(params.Action, action)
+
+		converted := make([]codersdk.ProvisionerJobResourceAttributeChange, 0, len(change.Attributes))
+		for _, attribute := range change.Attributes {
+			converted = append(converted, codersdk.ProvisionerJobResourceAttributeChange{
+				Path:              attribute.Path,
+				Before:            attribute.Before,
+				After:             attribute.After,
+				Sensitive:         attribute.Sensitive,
+				AfterUnknown:      attribute.AfterUnknown,
+				ForcesReplacement: attribute.ForcesReplacement,
+			})
+		}
+		attributes = append(attributes, converted)
+	}
+	var err error
+	params.Attributes, err = json.Marshal(attributes)
+	if err != nil {
+		return xerrors.Errorf("marshal attributes: %w", err)
+	}
+	_, err = db.InsertProvisionerJobResourceChanges(ctx, params)
+	if err != nil {
+		return xerrors.Errorf("insert provisioner job resource changes: %w", err)
+	}
+	return nil
 }
 
 func asVariableValues(templateVariables []database.TemplateVersionVariable) []*sdkproto.VariableValue {
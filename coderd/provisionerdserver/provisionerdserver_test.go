@@ -472,6 +472,85 @@ func TestAcquireJob(t *testing.T) {
 			require.NoError(t, err)
 			require.JSONEq(t, string(want), string(got))
 		})
+		t.Run(tc.name+"_TemplateVersionDryRunPreview", func(t *testing.T) {
+			t.Parallel()
+			srv, db, ps, pd := setup(t, false, nil)
+			ctx := context.Background()
+
+			user := dbgen.User(t, db, database.User{})
+			template := dbgen.Template(t, db, database.Template{
+				Name:           "template",
+				Provisioner:    database.ProvisionerTypeEcho,
+				OrganizationID: pd.OrganizationID,
+			})
+			version := dbgen.TemplateVersion(t, db, database.TemplateVersion{
+				Name:           "v2",
+				OrganizationID: pd.OrganizationID,
+				TemplateID:     uuid.NullUUID{UUID: template.ID, Valid: true},
+			})
+			workspace := dbgen.Workspace(t, db, database.WorkspaceTable{
+				Name:           "dev",
+				TemplateID:     template.ID,
+				OwnerID:        user.ID,
+				OrganizationID: pd.OrganizationID,
+			})
+			buildJob := dbgen.ProvisionerJob(t, db, ps, database.ProvisionerJob{
+				OrganizationID: pd.OrganizationID,
+				InitiatorID:    user.ID,
+				Provisioner:    database.ProvisionerTypeEcho,
+				Type:           database.ProvisionerJobTypeWorkspaceBuild,
+				StartedAt:      sql.NullTime{Time: dbtime.Now(), Valid: true},
+				CompletedAt:    sql.NullTime{Time: dbtime.Now(), Valid: true},
+			})
+			_ = dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+				WorkspaceID:       workspace.ID,
+				BuildNumber:       1,
+				JobID:             buildJob.ID,
+				TemplateVersionID: version.ID,
+				ProvisionerState:  []byte("state"),
+			})
+			file := dbgen.File(t, db, database.File{CreatedBy: user.ID})
+			_ = dbgen.ProvisionerJob(t, db, ps, database.ProvisionerJob{
+				OrganizationID: pd.OrganizationID,
+				InitiatorID:    user.ID,
+				Provisioner:    database.ProvisionerTypeEcho,
+				StorageMethod:  database.ProvisionerStorageMethodFile,
+				FileID:         file.ID,
+				Type:           database.ProvisionerJobTypeTemplateVersionDryRun,
+				Input: must(json.Marshal(provisionerdserver.TemplateVersionDryRunJob{
+					TemplateVersionID: version.ID,
+					WorkspaceID:       workspace.ID,
+				})),
+			})
+
+			job, err := tc.acquire(ctx, srv)
+			require.NoError(t, err)
+
+			got, err := json.Marshal(job.Type)
+			require.NoError(t, err)
+
+			want, err := json.Marshal(&proto.AcquiredJob_TemplateDryRun_{
+				TemplateDryRun: &proto.AcquiredJob_TemplateDryRun{
+					State: []byte("state"),
+					Metadata: &sdkproto.Metadata{
+						CoderUrl:                (&url.URL{}).String(),
+						WorkspaceName:           workspace.Name,
+						WorkspaceId:             workspace.ID.String(),
+						WorkspaceOwner:          user.Username,
+						WorkspaceOwnerEmail:     user.Email,
+						WorkspaceOwnerName:      user.Name,
+						WorkspaceOwnerGroups:    []string{},
+						WorkspaceOwnerId:        user.ID.String(),
+						WorkspaceOwnerLoginType: string(user.LoginType),
+						TemplateId:              template.ID.String(),
+						TemplateName:            template.Name,
+						TemplateVersion:         version.Name,
+					},
+				},
+			})
+			require.NoError(t, err)
+			require.JSONEq(t, string(want), string(got))
+		})
 		t.Run(tc.name+"_TemplateVersionImport", func(t *testing.T) {
 			t.Parallel()
 			srv, db, ps, _ := setup(t, false, nil)
@@ -549,6 +628,92 @@ func TestAcquireJob(t *testing.T) {
 	}
 }
 
+func TestAcquireJob_Snapshot(t *testing.T) {
+	t.Parallel()
+
+	// setupSnapshotBuild creates a stop build which snapshots the workspace.
+	setupSnapshotBuild := func(t *testing.T, db database.Store, ps pubsub.Pubsub, pd database.ProvisionerDaemon) database.ProvisionerJob {
+		user := dbgen.User(t, db, database.User{})
+		template := dbgen.Template(t, db, database.Template{
+			Provisioner:    database.ProvisionerTypeEcho,
+			OrganizationID: pd.OrganizationID,
+		})
+		version := dbgen.TemplateVersion(t, db, database.TemplateVersion{
+			OrganizationID: pd.OrganizationID,
+			TemplateID:     uuid.NullUUID{UUID: template.ID, Valid: true},
+			JobID:          uuid.New(),
+		})
+		_ = dbgen.ProvisionerJob(t, db, ps, database.ProvisionerJob{
+			ID:             version.JobID,
+			OrganizationID: pd.OrganizationID,
+			InitiatorID:    user.ID,
+			Type:           database.ProvisionerJobTypeTemplateVersionImport,
+			StartedAt:      sql.NullTime{Time: dbtime.Now(), Valid: true},
+			CompletedAt:    sql.NullTime{Time: dbtime.Now(), Valid: true},
+		})
+		workspace := dbgen.Workspace(t, db, database.WorkspaceTable{
+			TemplateID:     template.ID,
+			OwnerID:        user.ID,
+			OrganizationID: pd.OrganizationID,
+		})
+		build := dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+			WorkspaceID:       workspace.ID,
+			BuildNumber:       1,
+			JobID:             uuid.New(),
+			TemplateVersionID: version.ID,
+			Transition:        database.WorkspaceTransitionStop,
+			Reason:            database.BuildReasonInitiator,
+		})
+		snapshot := dbgen.WorkspaceSnapshot(t, db, database.WorkspaceSnapshot{
+			OrganizationID: pd.OrganizationID,
+			OwnerID:        user.ID,
+			TemplateID:     template.ID,
+			WorkspaceID:    workspace.ID,
+			BuildID:        build.ID,
+		})
+		file := dbgen.File(t, db, database.File{CreatedBy: user.ID})
+		return dbgen.ProvisionerJob(t, db, ps, database.ProvisionerJob{
+			ID:             build.JobID,
+			OrganizationID: pd.OrganizationID,
+			InitiatorID:    user.ID,
+			FileID:         file.ID,
+			Type:           database.ProvisionerJobTypeWorkspaceBuild,
+			Input: must(json.Marshal(provisionerdserver.WorkspaceProvisionJob{
+				WorkspaceBuildID: build.ID,
+				SnapshotID:       snapshot.ID,
+			})),
+		})
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		srv, db, ps, pd := setup(t, false, nil)
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_ = setupSnapshotBuild(t, db, ps, pd)
+
+		job, err := srv.AcquireJob(ctx, nil)
+		require.NoError(t, err)
+		build, ok := job.Type.(*proto.AcquiredJob_WorkspaceBuild_)
+		require.True(t, ok)
+		require.Equal(t, sdkproto.WorkspaceTransition_SNAPSHOT, build.WorkspaceBuild.Metadata.WorkspaceTransition)
+	})
+
+	t.Run("UnsupportedAPIVersion", func(t *testing.T) {
+		t.Parallel()
+		srv, db, ps, pd := setup(t, true, &overrides{apiVersion: "1.2"})
+		ctx := testutil.Context(t, testutil.WaitShort)
+		job := setupSnapshotBuild(t, db, ps, pd)
+
+		// The daemon would run the build as a regular stop.
+		_, err := srv.AcquireJob(ctx, nil)
+		require.ErrorContains(t, err, "API version 1.3")
+		job, err = db.GetProvisionerJobByID(ctx, job.ID)
+		require.NoError(t, err)
+		require.True(t, job.CompletedAt.Valid)
+		require.Contains(t, job.Error.String, "API version 1.3")
+	})
+}
+
 func TestUpdateJob(t *testing.T) {
 	t.Parallel()
 	ctx := context.Background()
@@ -1422,10 +1587,29 @@ func TestCompleteJob(t *testing.T) {
 						Name: "something",
 						Type: "aws_instance",
 					}},
+					ResourceChanges: []*sdkproto.ResourceChange{{
+						Address: "aws_instance.something",
+						Type:    "aws_instance",
+						Name:    "something",
+						Action:  "replace",
+						Attributes: []*sdkproto.AttributeChange{{
+							Path:              "ami",
+							Before:            `"ami-1"`,
+							After:             `"ami-2"`,
+							ForcesReplacement: true,
+						}},
+					}},
 				},
 			},
 		})
 		require.NoError(t, err)
+
+		changes, err := db.GetProvisionerJobResourceChangesByJobID(ctx, job.ID)
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		require.Equal(t, "aws_instance.something", changes[0].Address)
+		require.Equal(t, database.ProvisionerJobResourceChangeActionReplace, changes[0].Action)
+		require.JSONEq(t, `[{"path":"ami","before":"\"ami-1\"","after":"\"ami-2\"","sensitive":false,"after_unknown":false,"forces_replacement":true}]`, string(changes[0].Attributes))
 	})
 
 	t.Run("Modules", func(t *testing.T) {
@@ -1727,6 +1911,35 @@ func TestInsertWorkspaceResource(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, resources, 1)
 	})
+	t.Run("HourlyCost", func(t *testing.T) {
+		t.Parallel()
+		for _, tc := range []struct {
+			value string
+			want  float64
+		}{
+			{value: "0.25", want: 0.25},
+			{value: " 3 ", want: 3},
+			{value: "-1", want: 0},
+			{value: "NaN", want: 0},
+			{value: "cheap", want: 0},
+		} {
+			db := dbmem.New()
+			job := uuid.New()
+			err := insert(db, job, &sdkproto.Resource{
+				Name: "something",
+				Type: "aws_instance",
+				Metadata: []*sdkproto.Resource_Metadata{{
+					Key:   "hourly_cost",
+					Value: tc.value,
+				}},
+			})
+			require.NoError(t, err)
+			resources, err := db.GetWorkspaceResourcesByJobID(ctx, job)
+			require.NoError(t, err)
+			require.Len(t, resources, 1)
+			require.Equal(t, tc.want, resources[0].HourlyCost, tc.value)
+		}
+	})
 	t.Run("InvalidAgentToken", func(t *testing.T) {
 		t.Parallel()
 		err := insert(dbmem.New(), uuid.New(), &sdkproto.Resource{
@@ -2195,6 +2408,7 @@ type overrides struct {
 	heartbeatInterval           time.Duration
 	auditor                     audit.Auditor
 	notificationEnqueuer        notifications.Enqueuer
+	apiVersion                  string
 }
 
 func setup(t *testing.T, ignoreLogErrors bool, ov *overrides) (proto.DRPCProvisionerDaemonServer, database.Store, pubsub.Pubsub, database.ProvisionerDaemon) {
@@ -2263,6 +2477,11 @@ func setup(t *testing.T, ignoreLogErrors bool, ov *overrides) (proto.DRPCProvisi
 		notifEnq = notifications.NewNoopEnqueuer()
 	}
 
+	apiVersion := proto.CurrentVersion.String()
+	if ov.apiVersion != "" {
+		apiVersion = ov.apiVersion
+	}
+
 	daemon, err := db.UpsertProvisionerDaemon(ov.ctx, database.UpsertProvisionerDaemonParams{
 		Name:           "test",
 		CreatedAt:      dbtime.Now(),
@@ -2270,7 +2489,7 @@ func setup(t *testing.T, ignoreLogErrors bool, ov *overrides) (proto.DRPCProvisi
 		Tags:           database.StringMap{},
 		LastSeenAt:     sql.NullTime{},
 		Version:        buildinfo.Version(),
-		APIVersion:     proto.CurrentVersion.String(),
+		APIVersion:     apiVersion,
 		OrganizationID: defOrg.ID,
 		KeyID:          uuid.MustParse(codersdk.ProvisionerKeyIDBuiltIn),
 	})
@@ -2301,6 +2520,7 @@ func setup(t *testing.T, ignoreLogErrors bool, ov *overrides) (proto.DRPCProvisi
 			AcquireJobLongPollDur: pollDur,
 			HeartbeatInterval:     ov.heartbeatInterval,
 			HeartbeatFn:           ov.heartbeatFn,
+			APIVersion:            apiVersion,
 		},
 		notifEnq,
 	)
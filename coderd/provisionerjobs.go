@@ -214,6 +214,37 @@ func (api *API) provisionerJobResources(rw http.ResponseWriter, r *http.Request,
 	httpapi.Write(ctx, rw, http.StatusOK, apiResources)
 }
 
+// Returns the changes the plan for a provisioner job makes to each resource.
+func (api *API) provisionerJobResourceChanges(rw http.ResponseWriter, r *http.Request, job database.ProvisionerJob) {
+	ctx := r.Context()
+	if !job.CompletedAt.Valid {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Job hasn't completed!",
+		})
+		return
+	}
+
+	changes, err := api.Database.GetProvisionerJobResourceChangesByJobID(ctx, job.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching job resource changes.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	apiChanges, err := convertProvisionerJobResourceChanges(changes)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error reading job resource changes.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, apiChanges)
+}
+
 func convertProvisionerJobLogs(provisionerJobLogs []database.ProvisionerJobLog) []codersdk.ProvisionerJobLog {
 	sdk := make([]codersdk.ProvisionerJobLog, 0, len(provisionerJobLogs))
 	for _, log := range provisionerJobLogs {
@@ -233,6 +264,25 @@ func convertProvisionerJobLog(provisionerJobLog database.ProvisionerJobLog) code
 	}
 }
 
+func convertProvisionerJobResourceChanges(changes []database.ProvisionerJobResourceChange) ([]codersdk.ProvisionerJobResourceChange, error) {
+	sdk := make([]codersdk.ProvisionerJobResourceChange, 0, len(changes))
+	for _, change := range changes {
+		attributes := make([]codersdk.ProvisionerJobResourceAttributeChange, 0)
+		if err := json.Unmarshal(change.Attributes, &attributes); err != nil {
+			return nil, xerrors.Errorf("unmarshal attributes for %q: %w", change.Address, err)
+		}
+		sdk = append(sdk, codersdk.ProvisionerJobResourceChange{
+			JobID:        change.JobID,
+			Address:      change.Address,
+			ResourceType: change.ResourceType,
+			ResourceName: change.ResourceName,
+			Action:       codersdk.ProvisionerJobResourceChangeAction(change.Action),
+			Attributes:   attributes,
+		})
+	}
+	return sdk, nil
+}
+
 func convertProvisionerJob(pj database.GetProvisionerJobsByIDsWithQueuePositionRow) codersdk.ProvisionerJob {
 	provisionerJob := pj.ProvisionerJob
 	job := codersdk.ProvisionerJob{
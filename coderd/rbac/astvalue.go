@@ -186,6 +186,10 @@ func (s Scope) regoValue() ast.Value {
 		ast.StringTerm("allow_list"),
 		ast.NewTerm(regoSliceString(s.AllowIDList...)),
 	)
+	r.Insert(
+		ast.StringTerm("implicit"),
+		ast.NewTerm(s.Implicit.regoValue()),
+	)
 	return r
 }
 
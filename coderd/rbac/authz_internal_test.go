@@ -1053,6 +1053,247 @@ func TestAuthorizeScope(t *testing.T) {
 			{resource: ResourceWorkspace.InOrg(unusedID).WithOwner("not-me"), actions: []policy.Action{policy.ActionCreate}, allow: false},
 		},
 	)
+
+	// The "all" scope must not restrict anything the roles allow.
+	user = Subject{
+		ID:    "me",
+		Roles: Roles{must(RoleByName(RoleOwner()))},
+		Scope: must(ExpandScope(ScopeAll)),
+	}
+
+	testAuthorize(t, "Admin_ScopeAll", user,
+		cases(func(c authTestCase) authTestCase {
+			c.actions = []policy.Action{policy.ActionRead, policy.ActionUpdate, policy.ActionDelete, policy.ActionApplicationConnect}
+			c.allow = true
+			return c
+		}, []authTestCase{
+			{resource: ResourceWorkspace.InOrg(defOrg).WithOwner(user.ID)},
+			{resource: ResourceWorkspace.InOrg(defOrg)},
+			{resource: ResourceWorkspace.WithOwner(user.ID)},
+			{resource: ResourceWorkspace.All()},
+			{resource: ResourceWorkspace.InOrg(unusedID).WithOwner("not-me")},
+			{resource: ResourceTemplate.InOrg(unusedID)},
+			{resource: ResourceUser.WithID(uuid.New())},
+		}),
+	)
+
+	user = Subject{
+		ID: "me",
+		Roles: Roles{
+			must(RoleByName(RoleMember())),
+			must(RoleByName(ScopedRoleOrgMember(defOrg))),
+		},
+		Scope: must(ExpandScope(ScopeAll)),
+	}
+
+	testAuthorize(t, "User_ScopeAll", user,
+		cases(func(c authTestCase) authTestCase {
+			c.actions = []policy.Action{policy.ActionRead, policy.ActionUpdate, policy.ActionDelete, policy.ActionApplicationConnect}
+			return c
+		}, []authTestCase{
+			{resource: ResourceWorkspace.InOrg(defOrg).WithOwner(user.ID), allow: true},
+			{resource: ResourceWorkspace.InOrg(defOrg).WithOwner("not-me"), allow: false},
+			{resource: ResourceWorkspace.InOrg(unusedID).WithOwner(user.ID), allow: false},
+			{resource: ResourceWorkspace.InOrg(unusedID).WithOwner("not-me"), allow: false},
+		}),
+	)
+
+	// WorkspaceAgentScope has the permissions of "all", limited to the IDs
+	// the agent needs.
+	ownerID := uuid.New()
+	templateID := uuid.New()
+	user = Subject{
+		ID: ownerID.String(),
+		Roles: Roles{
+			must(RoleByName(RoleMember())),
+			must(RoleByName(ScopedRoleOrgMember(defOrg))),
+		},
+		Scope: WorkspaceAgentScope(WorkspaceAgentScopeParams{
+			WorkspaceID: workspaceID,
+			OwnerID:     ownerID,
+			TemplateID:  templateID,
+			VersionID:   uuid.New(),
+		}),
+	}
+
+	testAuthorize(t, "User_WorkspaceAgentScope", user,
+		// Resources outside of the allow list are never allowed.
+		cases(func(c authTestCase) authTestCase {
+			c.actions = []policy.Action{policy.ActionRead, policy.ActionUpdate, policy.ActionDelete}
+			c.allow = false
+			c.resource.ID = uuid.NewString()
+			return c
+		}, []authTestCase{
+			{resource: ResourceWorkspace.InOrg(defOrg).WithOwner(user.ID)},
+			{resource: ResourceWorkspace.InOrg(defOrg).WithOwner("not-me")},
+			{resource: ResourceTemplate.InOrg(defOrg)},
+		}),
+		[]authTestCase{
+			{resource: ResourceWorkspace.WithID(workspaceID).InOrg(defOrg).WithOwner(user.ID), actions: []policy.Action{policy.ActionRead, policy.ActionUpdate, policy.ActionApplicationConnect}, allow: true},
+			// The scope allows the workspace, but the roles do not allow
+			// workspaces owned by someone else.
+			{resource: ResourceWorkspace.WithID(workspaceID).InOrg(defOrg).WithOwner("not-me"), actions: []policy.Action{policy.ActionRead}, allow: false},
+			{resource: ResourceWorkspace.WithID(workspaceID).InOrg(unusedID).WithOwner(user.ID), actions: []policy.Action{policy.ActionRead}, allow: false},
+			{resource: ResourceUserObject(ownerID), actions: []policy.Action{policy.ActionReadPersonal}, allow: true},
+		},
+	)
+
+	// Scopes can grant permissions at the organization and user level, the
+	// same way roles do.
+	user = Subject{
+		ID: "me",
+		Roles: Roles{
+			must(RoleByName(RoleOwner())),
+			must(RoleByName(ScopedRoleOrgMember(defOrg))),
+		},
+		Scope: Scope{
+			Role: Role{
+				Identifier:  RoleIdentifier{Name: "org_and_user"},
+				DisplayName: "Organization and user",
+				Site:        []Permission{},
+				Org: map[string][]Permission{
+					defOrg.String(): Permissions(map[string][]policy.Action{
+						ResourceTemplate.Type: {policy.ActionRead},
+					}),
+				},
+				User: Permissions(map[string][]policy.Action{
+					ResourceWorkspace.Type: {policy.ActionRead},
+				}),
+			},
+			AllowIDList: []string{policy.WildcardSymbol},
+		},
+	}
+
+	testAuthorize(t, "Admin_OrgAndUserScope", user,
+		[]authTestCase{
+			{resource: ResourceTemplate.InOrg(defOrg), actions: []policy.Action{policy.ActionRead}, allow: true},
+			{resource: ResourceTemplate.InOrg(defOrg), actions: []policy.Action{policy.ActionUpdate}, allow: false},
+			{resource: ResourceTemplate.InOrg(unusedID), actions: []policy.Action{policy.ActionRead}, allow: false},
+			{resource: ResourceWorkspace.InOrg(defOrg).WithOwner(user.ID), actions: []policy.Action{policy.ActionRead}, allow: true},
+			{resource: ResourceWorkspace.InOrg(defOrg).WithOwner(user.ID), actions: []policy.Action{policy.ActionUpdate}, allow: false},
+			{resource: ResourceWorkspace.InOrg(defOrg).WithOwner("not-me"), actions: []policy.Action{policy.ActionRead}, allow: false},
+		},
+	)
+
+	// This scope can only update templates in one organization and read
+	// workspaces in any organization.
+	user = Subject{
+		ID: "me",
+		Roles: Roles{
+			must(RoleByName(RoleOwner())),
+			must(RoleByName(ScopedRoleOrgMember(defOrg))),
+			must(RoleByName(ScopedRoleOrgMember(unusedID))),
+		},
+		Scope: CustomScope(
+			Permissions(map[string][]policy.Action{
+				ResourceWorkspace.Type: {policy.ActionRead},
+			}),
+			map[string][]Permission{
+				defOrg.String(): Permissions(map[string][]policy.Action{
+					ResourceTemplate.Type: {policy.ActionRead, policy.ActionUpdate},
+				}),
+			},
+			nil,
+		),
+	}
+
+	testAuthorize(t, "Admin_CustomScope", user,
+		// Not allowed by the scope.
+		cases(func(c authTestCase) authTestCase {
+			c.actions = []policy.Action{policy.ActionCreate, policy.ActionDelete}
+			c.allow = false
+			return c
+		}, []authTestCase{
+			{resource: ResourceWorkspace.InOrg(defOrg).WithOwner(user.ID)},
+			{resource: ResourceWorkspace.InOrg(unusedID).WithOwner("not-me")},
+			{resource: ResourceTemplate.InOrg(defOrg)},
+			{resource: ResourceTemplate.InOrg(unusedID)},
+			{resource: ResourceUser.WithID(uuid.New())},
+		}),
+		// Allowed by scope:
+		[]authTestCase{
+			{resource: ResourceWorkspace.InOrg(defOrg).WithOwner(user.ID), actions: []policy.Action{policy.ActionRead}, allow: true},
+			{resource: ResourceWorkspace.InOrg(unusedID).WithOwner("not-me"), actions: []policy.Action{policy.ActionRead}, allow: true},
+			{resource: ResourceWorkspace.InOrg(defOrg).WithOwner(user.ID), actions: []policy.Action{policy.ActionUpdate}, allow: false},
+			{resource: ResourceTemplate.InOrg(defOrg), actions: []policy.Action{policy.ActionRead, policy.ActionUpdate}, allow: true},
+			// The template permissions are limited to one organization.
+			{resource: ResourceTemplate.InOrg(unusedID), actions: []policy.Action{policy.ActionRead, policy.ActionUpdate}, allow: false},
+			{resource: ResourceUser.WithID(uuid.New()), actions: []policy.Action{policy.ActionRead}, allow: false},
+		},
+	)
+
+	// An allow list must not block the reads of the user and their
+	// organizations every custom scope allows.
+	userID := uuid.New()
+	workspaceID = uuid.New()
+	user = Subject{
+		ID: userID.String(),
+		Roles: Roles{
+			must(RoleByName(RoleOwner())),
+			must(RoleByName(ScopedRoleOrgMember(defOrg))),
+			must(RoleByName(ScopedRoleOrgMember(unusedID))),
+		},
+		Scope: CustomScope(
+			Permissions(map[string][]policy.Action{
+				ResourceWorkspace.Type: {policy.ActionRead},
+			}),
+			map[string][]Permission{
+				defOrg.String(): Permissions(map[string][]policy.Action{
+					ResourceTemplate.Type: {policy.ActionRead},
+				}),
+			},
+			[]string{workspaceID.String()},
+		),
+	}
+
+	testAuthorize(t, "Admin_CustomScopeAllowList", user,
+		[]authTestCase{
+			{resource: ResourceWorkspace.WithID(workspaceID).InOrg(defOrg).WithOwner(user.ID), actions: []policy.Action{policy.ActionRead}, allow: true},
+			{resource: ResourceWorkspace.WithID(uuid.New()).InOrg(defOrg).WithOwner(user.ID), actions: []policy.Action{policy.ActionRead}, allow: false},
+			{resource: ResourceTemplate.WithID(uuid.New()).InOrg(defOrg), actions: []policy.Action{policy.ActionRead}, allow: false},
+			// Implicit reads.
+			{resource: ResourceUserObject(userID), actions: []policy.Action{policy.ActionRead, policy.ActionReadPersonal}, allow: true},
+			{resource: ResourceOrganizationMember.WithID(userID).InOrg(defOrg).WithOwner(user.ID), actions: []policy.Action{policy.ActionRead}, allow: true},
+			{resource: ResourceOrganization.WithID(defOrg).InOrg(defOrg), actions: []policy.Action{policy.ActionRead}, allow: true},
+			{resource: ResourceUserObject(userID), actions: []policy.Action{policy.ActionUpdate}, allow: false},
+			// Organizations without permissions must be in the allow list.
+			{resource: ResourceOrganization.WithID(unusedID).InOrg(unusedID), actions: []policy.Action{policy.ActionRead}, allow: false},
+		},
+	)
+
+	// The implicit reads must not widen the allow list for the permissions
+	// of the scope.
+	otherID := uuid.New()
+	user = Subject{
+		ID: userID.String(),
+		Roles: Roles{
+			must(RoleByName(RoleOwner())),
+			must(RoleByName(ScopedRoleOrgMember(defOrg))),
+		},
+		Scope: CustomScope(
+			Permissions(map[string][]policy.Action{
+				ResourceUser.Type: {policy.ActionRead, policy.ActionDelete},
+			}),
+			map[string][]Permission{
+				defOrg.String(): Permissions(map[string][]policy.Action{
+					ResourceOrganization.Type: {policy.ActionUpdate},
+				}),
+			},
+			[]string{otherID.String()},
+		),
+	}
+
+	testAuthorize(t, "Admin_CustomScopeAllowListNotWidened", user,
+		[]authTestCase{
+			{resource: ResourceUserObject(otherID), actions: []policy.Action{policy.ActionDelete}, allow: true},
+			// The user and the organization are only implicitly readable.
+			{resource: ResourceUserObject(userID), actions: []policy.Action{policy.ActionRead}, allow: true},
+			{resource: ResourceUserObject(userID), actions: []policy.Action{policy.ActionDelete}, allow: false},
+			{resource: ResourceUserObject(uuid.New()), actions: []policy.Action{policy.ActionRead, policy.ActionDelete}, allow: false},
+			{resource: ResourceOrganization.WithID(defOrg).InOrg(defOrg), actions: []policy.Action{policy.ActionRead}, allow: true},
+			{resource: ResourceOrganization.WithID(defOrg).InOrg(defOrg), actions: []policy.Action{policy.ActionUpdate}, allow: false},
+		},
+	)
 }
 
 // cases applies a given function to all test cases. This makes generalities easier to create.
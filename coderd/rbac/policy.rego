@@ -99,7 +99,7 @@ org := org_allow(input.subject.roles)
 
 default scope_org := 0
 
-scope_org := org_allow([input.scope])
+scope_org := org_allow([input.subject.scope])
 
 # org_allow_set is a helper function that iterates over all orgs that the actor
 # is a member of. For each organization it sets the numerical allow value
@@ -197,9 +197,9 @@ default user := 0
 
 user := user_allow(input.subject.roles)
 
-default user_scope := 0
+default scope_user := 0
 
-scope_user := user_allow([input.scope])
+scope_user := user_allow([input.subject.scope])
 
 user_allow(roles) := num if {
 	input.object.owner != ""
@@ -213,6 +213,16 @@ user_allow(roles) := num if {
 	num := number(allow)
 }
 
+# Implicit scope permissions are not limited by the allow_list. Custom scopes
+# use them to always allow reading the user and their organizations.
+default scope_implicit_org := 0
+
+scope_implicit_org := org_allow([input.subject.scope.implicit])
+
+default scope_implicit_user := 0
+
+scope_implicit_user := user_allow([input.subject.scope.implicit])
+
 # Scope allow_list is a list of resource IDs explicitly allowed by the scope.
 # If the list is '*', then all resources are allowed.
 scope_allow_list if {
@@ -280,6 +290,18 @@ scope_allow if {
 	scope_user = 1
 }
 
+scope_allow if {
+	not scope_site = -1
+	scope_implicit_org = 1
+}
+
+scope_allow if {
+	not scope_site = -1
+	not scope_org = -1
+	org_ok
+	scope_implicit_user = 1
+}
+
 # ACL for users
 acl_allow if {
 	# Should you have to be a member of the org too?
@@ -203,6 +203,15 @@ func ignoreNames(t *testing.T, value ast.Value) {
 
 	scopeObj.Insert(ast.StringTerm("name"), ast.StringTerm("ignore"))
 	scopeObj.Insert(ast.StringTerm("display_name"), ast.StringTerm("ignore"))
+
+	// Override the names of the implicit scope permissions
+	implicit := scopeObj.Get(ast.StringTerm("implicit"))
+	require.NotNil(t, implicit, "scope is expected to have implicit permissions")
+	implicitObj, ok := implicit.Value.(ast.Object)
+	require.True(t, ok, "implicit is expected to be an object")
+
+	implicitObj.Insert(ast.StringTerm("name"), ast.StringTerm("ignore"))
+	implicitObj.Insert(ast.StringTerm("display_name"), ast.StringTerm("ignore"))
 }
 
 func TestRoleByName(t *testing.T) {
@@ -47,11 +47,62 @@ func WorkspaceAgentScope(params WorkspaceAgentScopeParams) Scope {
 	}
 }
 
+// CustomScope returns a scope that only allows the given permissions. Site
+// permissions apply to resources in every organization, org permissions only
+// to resources in the organization they are listed under. If allowIDs is
+// empty, the scope is not limited to specific resources.
+//
+// The scope can only ever limit the roles of the subject, it never grants
+// permissions the subject does not already have. Every custom scope can read
+// the user it belongs to and the organizations it has permissions in, as
+// clients need them to identify the user. These reads are implicit
+// permissions, so they are not limited by allowIDs. Other organizations must
+// be in allowIDs to be read.
+func CustomScope(site []Permission, org map[string][]Permission, allowIDs []string) Scope {
+	site = append(site, Permission{ResourceType: ResourceOrganization.Type, Action: policy.ActionRead})
+	if len(allowIDs) == 0 {
+		allowIDs = []string{policy.WildcardSymbol}
+	}
+	if org == nil {
+		org = map[string][]Permission{}
+	}
+	implicitOrg := make(map[string][]Permission, len(org))
+	for orgID := range org {
+		implicitOrg[orgID] = Permissions(map[string][]policy.Action{
+			ResourceOrganization.Type: {policy.ActionRead},
+		})
+	}
+	return Scope{
+		Role: Role{
+			Identifier:  RoleIdentifier{Name: "Scope_custom"},
+			DisplayName: "Custom",
+			Site:        site,
+			Org:         org,
+			User:        []Permission{},
+		},
+		AllowIDList: allowIDs,
+		Implicit: Role{
+			Site: []Permission{},
+			Org:  implicitOrg,
+			User: Permissions(map[string][]policy.Action{
+				ResourceUser.Type:               {policy.ActionRead, policy.ActionReadPersonal},
+				ResourceOrganizationMember.Type: {policy.ActionRead},
+			}),
+		},
+	}
+}
+
 const (
 	ScopeAll                ScopeName = "all"
 	ScopeApplicationConnect ScopeName = "application_connect"
 )
 
+var noImplicitPermissions = Role{
+	Site: []Permission{},
+	Org:  map[string][]Permission{},
+	User: []Permission{},
+}
+
 // TODO: Support passing in scopeID list for allowlisting resources.
 var builtinScopes = map[ScopeName]Scope{
 	// ScopeAll is a special scope that allows access to all resources. During
@@ -67,6 +118,7 @@ var builtinScopes = map[ScopeName]Scope{
 			User: []Permission{},
 		},
 		AllowIDList: []string{policy.WildcardSymbol},
+		Implicit:    noImplicitPermissions,
 	},
 
 	ScopeApplicationConnect: {
@@ -80,6 +132,7 @@ var builtinScopes = map[ScopeName]Scope{
 			User: []Permission{},
 		},
 		AllowIDList: []string{policy.WildcardSymbol},
+		Implicit:    noImplicitPermissions,
 	},
 }
 
@@ -108,6 +161,9 @@ func (name ScopeName) Name() RoleIdentifier {
 type Scope struct {
 	Role
 	AllowIDList []string `json:"allow_list"`
+	// Implicit permissions are allowed by the scope without being limited by
+	// the AllowIDList. Only the Org and User permissions are used.
+	Implicit Role `json:"implicit"`
 }
 
 func (s Scope) Expand() (Scope, error) {
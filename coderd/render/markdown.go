@@ -2,11 +2,13 @@ package render
 
 import (
 	"bytes"
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/glamour/ansi"
 	gomarkdown "github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/ast"
 	"github.com/gomarkdown/markdown/html"
 	"github.com/gomarkdown/markdown/parser"
 	"golang.org/x/xerrors"
@@ -108,3 +110,109 @@ func HTMLFromMarkdown(markdown string) string {
 	})
 	return string(bytes.TrimSpace(gomarkdown.Render(doc, renderer)))
 }
+
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// SlackMrkdwnFromMarkdown converts Markdown into Slack's "mrkdwn" dialect.
+// Slack doesn't support headings, so these are rendered as bold text. Raw HTML
+// is dropped.
+// See: https://api.slack.com/reference/surfaces/formatting
+func SlackMrkdwnFromMarkdown(markdown string) string {
+	p := parser.NewWithExtensions(parser.CommonExtensions)
+	doc := p.Parse([]byte(markdown))
+
+	var sb strings.Builder
+	ast.WalkFunc(doc, func(node ast.Node, entering bool) ast.WalkStatus {
+		switch n := node.(type) {
+		case *ast.Text:
+			_, _ = sb.WriteString(slackEscaper.Replace(string(n.Literal)))
+		case *ast.Strong:
+			_, _ = sb.WriteString("*")
+		case *ast.Emph:
+			_, _ = sb.WriteString("_")
+		case *ast.Del:
+			_, _ = sb.WriteString("~")
+		case *ast.Code:
+			_, _ = sb.WriteString("`" + string(n.Literal) + "`")
+		case *ast.CodeBlock:
+			_, _ = sb.WriteString("```\n" + strings.TrimSuffix(string(n.Literal), "\n") + "\n```\n\n")
+		case *ast.Link:
+			if entering {
+				_, _ = sb.WriteString("<" + string(n.Destination) + "|")
+			} else {
+				_, _ = sb.WriteString(">")
+			}
+		case *ast.Image:
+			if entering {
+				_, _ = sb.WriteString("<" + string(n.Destination) + "|")
+			} else {
+				_, _ = sb.WriteString(">")
+			}
+		case *ast.Heading:
+			if entering {
+				_, _ = sb.WriteString("*")
+			} else {
+				_, _ = sb.WriteString("*\n\n")
+			}
+		case *ast.BlockQuote:
+			if entering {
+				_, _ = sb.WriteString("> ")
+			}
+		case *ast.ListItem:
+			if entering {
+				_, _ = sb.WriteString(strings.Repeat("    ", listDepth(n)-1) + listItemPrefix(n))
+			}
+		case *ast.List:
+			if !entering && listDepth(n) == 0 {
+				_, _ = sb.WriteString("\n")
+			}
+		case *ast.Paragraph:
+			if !entering {
+				_, _ = sb.WriteString("\n")
+				if _, ok := n.Parent.(*ast.ListItem); !ok {
+					_, _ = sb.WriteString("\n")
+				}
+			}
+		case *ast.Softbreak, *ast.Hardbreak:
+			_, _ = sb.WriteString("\n")
+		case *ast.HorizontalRule:
+			_, _ = sb.WriteString("---\n\n")
+		case *ast.HTMLSpan, *ast.HTMLBlock:
+			// Raw HTML is not supported by Slack.
+		}
+		return ast.GoToNext
+	})
+	return strings.TrimSpace(sb.String())
+}
+
+// listDepth returns the number of lists which contain the given node.
+func listDepth(node ast.Node) int {
+	var depth int
+	for p := node.GetParent(); p != nil; p = p.GetParent() {
+		if _, ok := p.(*ast.List); ok {
+			depth++
+		}
+	}
+	return depth
+}
+
+func listItemPrefix(item *ast.ListItem) string {
+	if item.ListFlags&ast.ListTypeOrdered == 0 {
+		return "• "
+	}
+
+	list, ok := item.Parent.(*ast.List)
+	if !ok {
+		return "• "
+	}
+	start := list.Start
+	if start == 0 {
+		start = 1
+	}
+	for i, child := range list.Children {
+		if child == item {
+			return fmt.Sprintf("%d. ", start+i)
+		}
+	}
+	return fmt.Sprintf("%d. ", start)
+}
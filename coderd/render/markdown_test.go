@@ -89,3 +89,50 @@ func TestHTML(t *testing.T) {
 		})
 	}
 }
+
+func TestSlackMrkdwn(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Simple",
+			input:    `**Coder** is in *early access* mode. To ~~register~~ request access, fill out [this form](https://internal.example.com).`,
+			expected: `*Coder* is in _early access_ mode. To ~register~ request access, fill out <https://internal.example.com|this form>.`,
+		},
+		{
+			name:     "Heading",
+			input:    "# Workspace deleted\nYour workspace `dev` was deleted.",
+			expected: "*Workspace deleted*\n\nYour workspace `dev` was deleted.",
+		},
+		{
+			name:     "Lists",
+			input:    "Reasons:\n\n- first\n- second\n\n1. one\n2. two",
+			expected: "Reasons:\n\n• first\n• second\n\n1. one\n2. two",
+		},
+		{
+			name:     "Escaping",
+			input:    `a < b & c > d <img src="foobar">`,
+			expected: `a &lt; b &amp; c &gt; d`,
+		},
+		{
+			name:     "No Markdown tags",
+			input:    "This is a simple description, so nothing changes.",
+			expected: "This is a simple description, so nothing changes.",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rendered := render.SlackMrkdwnFromMarkdown(tt.input)
+			require.Equal(t, tt.expected, rendered)
+		})
+	}
+}
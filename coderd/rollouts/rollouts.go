@@ -0,0 +1,303 @@
+// Package rollouts implements staged rollouts of template versions. While a
+// rollout is in progress, a share of the workspaces of a template and the
+// workspaces of the members of a group build a candidate version instead of
+// the active version. The candidate is promoted to the active version or
+// rolled back depending on the failure rate of its builds.
+package rollouts
+
+import (
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// Includes returns true if the workspace builds the candidate version of the
+// rollout instead of the active version of the template. inGroup is whether
+// the owner of the workspace is a member of the group of the rollout.
+//
+// Workspaces are assigned to a bucket by hashing their ID with the ID of the
+// rollout, so raising the percentage of a rollout only ever adds workspaces
+// to it.
+func Includes(rollout database.TemplateRollout, workspaceID uuid.UUID, inGroup bool) bool {
+	if rollout.Status != database.TemplateRolloutStatusInProgress {
+		return false
+	}
+	if inGroup && rollout.GroupID.Valid {
+		return true
+	}
+	return bucket(rollout.ID, workspaceID) < uint32(rollout.Percent)
+}
+
+func bucket(rolloutID, workspaceID uuid.UUID) uint32 {
+	h := fnv.New64a()
+	_, _ = h.Write(rolloutID[:])
+	_, _ = h.Write(workspaceID[:])
+	return uint32(binary.BigEndian.Uint64(h.Sum(nil)) % 100)
+}
+
+// Manager starts, evaluates and completes rollouts, and notifies template
+// admins of every transition.
+type Manager struct {
+	db       database.Store
+	enqueuer notifications.Enqueuer
+	log      slog.Logger
+}
+
+func New(db database.Store, enqueuer notifications.Enqueuer, log slog.Logger) *Manager {
+	return &Manager{
+		db:       db,
+		enqueuer: enqueuer,
+		log:      log,
+	}
+}
+
+// Start starts a rollout of a candidate version. Only one rollout per
+// template can be in progress.
+func (m *Manager) Start(ctx context.Context, params database.InsertTemplateRolloutParams) (database.TemplateRollout, error) {
+	rollout, err := m.db.InsertTemplateRollout(ctx, params)
+	if err != nil {
+		return database.TemplateRollout{}, xerrors.Errorf("insert template rollout: %w", err)
+	}
+	m.log.Info(ctx, "template rollout started",
+		slog.F("rollout_id", rollout.ID),
+		slog.F("template_id", rollout.TemplateID),
+		slog.F("template_version_id", rollout.TemplateVersionID),
+	)
+	m.notify(ctx, rollout, "started")
+	return rollout, nil
+}
+
+// Finish completes a rollout in progress with the given status. Promoting a
+// rollout makes its candidate the active version of the template. It returns
+// sql.ErrNoRows if the rollout was already completed.
+func (m *Manager) Finish(ctx context.Context, rollout database.TemplateRollout, status database.TemplateRolloutStatus, reason string) (database.TemplateRollout, error) {
+	if status == database.TemplateRolloutStatusInProgress || !status.Valid() {
+		return database.TemplateRollout{}, xerrors.Errorf("invalid rollout status %q", status)
+	}
+	var completed database.TemplateRollout
+	err := m.db.InTx(func(tx database.Store) error {
+		var err error
+		now := dbtime.Now()
+		completed, err = tx.UpdateTemplateRolloutStatusByID(ctx, database.UpdateTemplateRolloutStatusByIDParams{
+			ID:           rollout.ID,
+			Status:       status,
+			StatusReason: reason,
+			UpdatedAt:    now,
+		})
+		if err != nil {
+			return xerrors.Errorf("update template rollout status: %w", err)
+		}
+		if status != database.TemplateRolloutStatusPromoted {
+			return nil
+		}
+		err = tx.UpdateTemplateActiveVersionByID(ctx, database.UpdateTemplateActiveVersionByIDParams{
+			ID:              rollout.TemplateID,
+			ActiveVersionID: rollout.TemplateVersionID,
+			UpdatedAt:       now,
+		})
+		if err != nil {
+			return xerrors.Errorf("update template active version: %w", err)
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		return database.TemplateRollout{}, err
+	}
+	m.log.Info(ctx, "template rollout completed",
+		slog.F("rollout_id", completed.ID),
+		slog.F("template_id", completed.TemplateID),
+		slog.F("template_version_id", completed.TemplateVersionID),
+		slog.F("status", completed.Status),
+		slog.F("reason", completed.StatusReason),
+	)
+	m.notify(ctx, completed, statusLabel(completed.Status))
+	return completed, nil
+}
+
+// Evaluate promotes or rolls back a rollout once its candidate version
+// completed the minimum number of builds. The rollout is returned unchanged
+// if it isn't due yet.
+func (m *Manager) Evaluate(ctx context.Context, rollout database.TemplateRollout) (database.TemplateRollout, error) {
+	if rollout.Status != database.TemplateRolloutStatusInProgress {
+		return rollout, nil
+	}
+	counts, err := m.db.GetTemplateRolloutBuildCounts(ctx, database.GetTemplateRolloutBuildCountsParams{
+		TemplateVersionID: rollout.TemplateVersionID,
+		Since:             rollout.CreatedAt,
+	})
+	if err != nil {
+		return database.TemplateRollout{}, xerrors.Errorf("get template rollout build counts: %w", err)
+	}
+	if counts.Builds == 0 || counts.Builds < int64(rollout.MinBuilds) {
+		return rollout, nil
+	}
+
+	rate := FailureRate(counts)
+	switch {
+	case rate > rollout.MaxFailureRate:
+		reason := fmt.Sprintf("%d of %d builds failed (%.0f%%), above the maximum failure rate of %.0f%%.",
+			counts.FailedBuilds, counts.Builds, rate*100, rollout.MaxFailureRate*100)
+		return m.Finish(ctx, rollout, database.TemplateRolloutStatusRolledBack, reason)
+	case rollout.AutoPromote:
+		reason := fmt.Sprintf("%d of %d builds failed (%.0f%%), within the maximum failure rate of %.0f%%.",
+			counts.FailedBuilds, counts.Builds, rate*100, rollout.MaxFailureRate*100)
+		return m.Finish(ctx, rollout, database.TemplateRolloutStatusPromoted, reason)
+	default:
+		return rollout, nil
+	}
+}
+
+// FailureRate returns the share of failed builds, or 0 if there were none.
+func FailureRate(counts database.GetTemplateRolloutBuildCountsRow) float64 {
+	if counts.Builds == 0 {
+		return 0
+	}
+	return float64(counts.FailedBuilds) / float64(counts.Builds)
+}
+
+// WorkspaceBuildCompleted evaluates the rollout of the version of a completed
+// build, if any. It's called by provisionerd for every workspace build.
+func (m *Manager) WorkspaceBuildCompleted(ctx context.Context, build database.WorkspaceBuild) {
+	if m == nil || build.Transition != database.WorkspaceTransitionStart {
+		return
+	}
+	// nolint:gocritic // Rollouts are evaluated on behalf of provisionerd,
+	// which may promote the candidate to the active version.
+	ctx = dbauthz.AsProvisionerd(ctx)
+	log := m.log.With(slog.F("workspace_build_id", build.ID), slog.F("template_version_id", build.TemplateVersionID))
+
+	version, err := m.db.GetTemplateVersionByID(ctx, build.TemplateVersionID)
+	if err != nil {
+		log.Warn(ctx, "get template version of workspace build", slog.Error(err))
+		return
+	}
+	if !version.TemplateID.Valid {
+		return
+	}
+	rollout, err := m.db.GetActiveTemplateRolloutByTemplateID(ctx, version.TemplateID.UUID)
+	if xerrors.Is(err, sql.ErrNoRows) {
+		return
+	}
+	if err != nil {
+		log.Warn(ctx, "get active template rollout", slog.Error(err))
+		return
+	}
+	if rollout.TemplateVersionID != build.TemplateVersionID {
+		return
+	}
+	_, err = m.Evaluate(ctx, rollout)
+	// Another build of the candidate may have completed the rollout first.
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		log.Error(ctx, "evaluate template rollout", slog.F("rollout_id", rollout.ID), slog.Error(err))
+	}
+}
+
+// ActiveVersionChanged completes the rollout in progress of a template when its
+// active version is changed by hand. The rollout is promoted if its candidate
+// became the active version, and canceled otherwise.
+func (m *Manager) ActiveVersionChanged(ctx context.Context, templateID, versionID uuid.UUID) error {
+	rollout, err := m.db.GetActiveTemplateRolloutByTemplateID(ctx, templateID)
+	if xerrors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return xerrors.Errorf("get active template rollout: %w", err)
+	}
+	status, reason := database.TemplateRolloutStatusCanceled, "Another version was promoted to the active version."
+	if rollout.TemplateVersionID == versionID {
+		status, reason = database.TemplateRolloutStatusPromoted, "The version was promoted to the active version by hand."
+	}
+	_, err = m.Finish(ctx, rollout, status, reason)
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	return nil
+}
+
+func statusLabel(status database.TemplateRolloutStatus) string {
+	switch status {
+	case database.TemplateRolloutStatusRolledBack:
+		return "rolled back"
+	default:
+		return string(status)
+	}
+}
+
+// notify notifies the creator of the rollout and all template admins.
+func (m *Manager) notify(ctx context.Context, rollout database.TemplateRollout, status string) {
+	// nolint:gocritic // The recipients aren't necessarily visible to the actor.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	log := m.log.With(slog.F("rollout_id", rollout.ID))
+
+	template, err := m.db.GetTemplateByID(ctx, rollout.TemplateID)
+	if err != nil {
+		log.Warn(ctx, "get template for rollout notification", slog.Error(err))
+		return
+	}
+	version, err := m.db.GetTemplateVersionByID(ctx, rollout.TemplateVersionID)
+	if err != nil {
+		log.Warn(ctx, "get template version for rollout notification", slog.Error(err))
+		return
+	}
+	recipients, err := m.recipients(ctx, rollout)
+	if err != nil {
+		log.Warn(ctx, "find recipients of rollout notification", slog.Error(err))
+		return
+	}
+	labels := map[string]string{
+		"organization": template.OrganizationName,
+		"template":     template.Name,
+		"version":      version.Name,
+		"status":       status,
+		"reason":       rollout.StatusReason,
+	}
+	for _, userID := range recipients {
+		// nolint:gocritic // Need notifier actor to enqueue notifications.
+		_, err = m.enqueuer.Enqueue(dbauthz.AsNotifier(ctx), userID, notifications.TemplateTemplateVersionRolloutUpdated,
+			labels, "template-rollouts",
+			// Associate this notification with all the related entities.
+			rollout.ID, template.ID, version.ID, template.OrganizationID,
+		)
+		if err != nil {
+			log.Warn(ctx, "failed to notify of template rollout", slog.F("user_id", userID), slog.Error(err))
+		}
+	}
+}
+
+// recipients returns the creator of the rollout and all owners and template
+// admins, without duplicates.
+func (m *Manager) recipients(ctx context.Context, rollout database.TemplateRollout) ([]uuid.UUID, error) {
+	recipients := []uuid.UUID{rollout.CreatedBy}
+	seen := map[uuid.UUID]struct{}{rollout.CreatedBy: {}}
+	// Notice: we can't scrape the user information in parallel as pq
+	// fails with: unexpected describe rows response: 'D'
+	for _, role := range []string{codersdk.RoleOwner, codersdk.RoleTemplateAdmin} {
+		users, err := m.db.GetUsers(ctx, database.GetUsersParams{
+			RbacRole: []string{role},
+		})
+		if err != nil {
+			return nil, xerrors.Errorf("get users with role %q: %w", role, err)
+		}
+		for _, user := range users {
+			if _, ok := seen[user.ID]; ok {
+				continue
+			}
+			seen[user.ID] = struct{}{}
+			recipients = append(recipients, user.ID)
+		}
+	}
+	return recipients, nil
+}
@@ -0,0 +1,249 @@
+package rollouts_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbmem"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/notifications/notificationstest"
+	"github.com/coder/coder/v2/coderd/rollouts"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestIncludes(t *testing.T) {
+	t.Parallel()
+
+	rollout := database.TemplateRollout{
+		ID:     uuid.New(),
+		Status: database.TemplateRolloutStatusInProgress,
+	}
+	workspaceIDs := make([]uuid.UUID, 1000)
+	for i := range workspaceIDs {
+		workspaceIDs[i] = uuid.New()
+	}
+	included := func(percent int32) map[uuid.UUID]bool {
+		r := rollout
+		r.Percent = percent
+		set := map[uuid.UUID]bool{}
+		for _, id := range workspaceIDs {
+			if rollouts.Includes(r, id, false) {
+				set[id] = true
+			}
+		}
+		return set
+	}
+
+	require.Empty(t, included(0))
+	require.Len(t, included(100), len(workspaceIDs))
+
+	// Raising the percentage only adds workspaces.
+	ten, fifty := included(10), included(50)
+	require.InDelta(t, 100, len(ten), 50)
+	require.InDelta(t, 500, len(fifty), 100)
+	for id := range ten {
+		require.True(t, fifty[id], "workspace included at 10%% must be included at 50%%")
+	}
+
+	// Members of the group are always included.
+	rollout.GroupID = uuid.NullUUID{UUID: uuid.New(), Valid: true}
+	require.True(t, rollouts.Includes(rollout, uuid.New(), true))
+	require.False(t, rollouts.Includes(rollout, uuid.New(), false))
+
+	// Completed rollouts include no workspaces.
+	rollout.Status = database.TemplateRolloutStatusRolledBack
+	rollout.Percent = 100
+	require.False(t, rollouts.Includes(rollout, uuid.New(), true))
+}
+
+func TestManager(t *testing.T) {
+	t.Parallel()
+
+	t.Run("RollsBack", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db := dbmem.New()
+		enqueuer := &notificationstest.FakeEnqueuer{}
+		manager := rollouts.New(db, enqueuer, testutil.Logger(t))
+		env := setup(t, db)
+
+		rollout, err := manager.Start(ctx, env.rolloutParams(3, 0.5, true))
+		require.NoError(t, err)
+		sent := enqueuer.Sent(notificationstest.WithTemplateID(notifications.TemplateTemplateVersionRolloutUpdated))
+		require.Len(t, sent, 1)
+		require.Equal(t, env.user.ID, sent[0].UserID)
+		require.Equal(t, "started", sent[0].Labels["status"])
+
+		env.build(t, "")
+		env.build(t, "failed")
+		manager.WorkspaceBuildCompleted(ctx, env.build(t, "failed"))
+
+		rollout, err = db.GetTemplateRolloutByID(ctx, rollout.ID)
+		require.NoError(t, err)
+		require.Equal(t, database.TemplateRolloutStatusRolledBack, rollout.Status)
+		require.Contains(t, rollout.StatusReason, "2 of 3 builds failed (67%)")
+		require.True(t, rollout.CompletedAt.Valid)
+		template, err := db.GetTemplateByID(ctx, env.template.ID)
+		require.NoError(t, err)
+		require.Equal(t, env.template.ActiveVersionID, template.ActiveVersionID)
+
+		sent = enqueuer.Sent(notificationstest.WithTemplateID(notifications.TemplateTemplateVersionRolloutUpdated))
+		require.Len(t, sent, 2)
+		require.Equal(t, "rolled back", sent[1].Labels["status"])
+		require.Equal(t, rollout.StatusReason, sent[1].Labels["reason"])
+	})
+
+	t.Run("Promotes", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db := dbmem.New()
+		manager := rollouts.New(db, &notificationstest.FakeEnqueuer{}, testutil.Logger(t))
+		env := setup(t, db)
+
+		rollout, err := manager.Start(ctx, env.rolloutParams(2, 0.5, true))
+		require.NoError(t, err)
+
+		// Not enough builds yet.
+		manager.WorkspaceBuildCompleted(ctx, env.build(t, "failed"))
+		rollout, err = db.GetTemplateRolloutByID(ctx, rollout.ID)
+		require.NoError(t, err)
+		require.Equal(t, database.TemplateRolloutStatusInProgress, rollout.Status)
+
+		manager.WorkspaceBuildCompleted(ctx, env.build(t, ""))
+		rollout, err = db.GetTemplateRolloutByID(ctx, rollout.ID)
+		require.NoError(t, err)
+		require.Equal(t, database.TemplateRolloutStatusPromoted, rollout.Status)
+		template, err := db.GetTemplateByID(ctx, env.template.ID)
+		require.NoError(t, err)
+		require.Equal(t, env.candidate.ID, template.ActiveVersionID)
+	})
+
+	t.Run("WaitsWithoutAutoPromote", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db := dbmem.New()
+		manager := rollouts.New(db, &notificationstest.FakeEnqueuer{}, testutil.Logger(t))
+		env := setup(t, db)
+
+		rollout, err := manager.Start(ctx, env.rolloutParams(1, 0, false))
+		require.NoError(t, err)
+		manager.WorkspaceBuildCompleted(ctx, env.build(t, ""))
+		rollout, err = db.GetTemplateRolloutByID(ctx, rollout.ID)
+		require.NoError(t, err)
+		require.Equal(t, database.TemplateRolloutStatusInProgress, rollout.Status)
+	})
+
+	t.Run("ActiveVersionChanged", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db := dbmem.New()
+		manager := rollouts.New(db, &notificationstest.FakeEnqueuer{}, testutil.Logger(t))
+		env := setup(t, db)
+
+		rollout, err := manager.Start(ctx, env.rolloutParams(1, 0, false))
+		require.NoError(t, err)
+		err = manager.ActiveVersionChanged(ctx, env.template.ID, uuid.New())
+		require.NoError(t, err)
+		rollout, err = db.GetTemplateRolloutByID(ctx, rollout.ID)
+		require.NoError(t, err)
+		require.Equal(t, database.TemplateRolloutStatusCanceled, rollout.Status)
+
+		// Finishing a completed rollout fails.
+		_, err = manager.Finish(ctx, rollout, database.TemplateRolloutStatusPromoted, "")
+		require.ErrorIs(t, err, sql.ErrNoRows)
+	})
+}
+
+type environment struct {
+	db        database.Store
+	org       database.Organization
+	user      database.User
+	template  database.Template
+	candidate database.TemplateVersion
+	workspace database.WorkspaceTable
+	builds    *int32
+}
+
+func setup(t *testing.T, db database.Store) environment {
+	t.Helper()
+	org := dbgen.Organization(t, db, database.Organization{})
+	user := dbgen.User(t, db, database.User{})
+	template := dbgen.Template(t, db, database.Template{OrganizationID: org.ID, CreatedBy: user.ID})
+	active := dbgen.TemplateVersion(t, db, database.TemplateVersion{
+		OrganizationID: org.ID,
+		TemplateID:     uuid.NullUUID{UUID: template.ID, Valid: true},
+		CreatedBy:      user.ID,
+	})
+	err := db.UpdateTemplateActiveVersionByID(context.Background(), database.UpdateTemplateActiveVersionByIDParams{
+		ID:              template.ID,
+		ActiveVersionID: active.ID,
+		UpdatedAt:       dbtime.Now(),
+	})
+	require.NoError(t, err)
+	template.ActiveVersionID = active.ID
+	candidate := dbgen.TemplateVersion(t, db, database.TemplateVersion{
+		OrganizationID: org.ID,
+		TemplateID:     uuid.NullUUID{UUID: template.ID, Valid: true},
+		CreatedBy:      user.ID,
+	})
+	workspace := dbgen.Workspace(t, db, database.WorkspaceTable{
+		OwnerID:        user.ID,
+		OrganizationID: org.ID,
+		TemplateID:     template.ID,
+	})
+	return environment{
+		db:        db,
+		org:       org,
+		user:      user,
+		template:  template,
+		candidate: candidate,
+		workspace: workspace,
+		builds:    new(int32),
+	}
+}
+
+func (e environment) rolloutParams(minBuilds int32, maxFailureRate float64, autoPromote bool) database.InsertTemplateRolloutParams {
+	now := dbtime.Now()
+	return database.InsertTemplateRolloutParams{
+		ID:                uuid.New(),
+		TemplateID:        e.template.ID,
+		TemplateVersionID: e.candidate.ID,
+		CreatedBy:         e.user.ID,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+		Percent:           100,
+		MinBuilds:         minBuilds,
+		MaxFailureRate:    maxFailureRate,
+		AutoPromote:       autoPromote,
+	}
+}
+
+// build inserts a completed start build of the candidate version. It failed
+// if errorMessage isn't empty.
+func (e environment) build(t *testing.T, errorMessage string) database.WorkspaceBuild {
+	t.Helper()
+	job := dbgen.ProvisionerJob(t, e.db, nil, database.ProvisionerJob{
+		OrganizationID: e.org.ID,
+		InitiatorID:    e.user.ID,
+		Type:           database.ProvisionerJobTypeWorkspaceBuild,
+		StartedAt:      sql.NullTime{Time: dbtime.Now(), Valid: true},
+		CompletedAt:    sql.NullTime{Time: dbtime.Now(), Valid: true},
+		Error:          sql.NullString{String: errorMessage, Valid: errorMessage != ""},
+	})
+	*e.builds++
+	return dbgen.WorkspaceBuild(t, e.db, database.WorkspaceBuild{
+		WorkspaceID:       e.workspace.ID,
+		TemplateVersionID: e.candidate.ID,
+		InitiatorID:       e.user.ID,
+		JobID:             job.ID,
+		BuildNumber:       *e.builds,
+		Transition:        database.WorkspaceTransitionStart,
+	})
+}
@@ -0,0 +1,482 @@
+package coderd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// maxSecretValueSize is the maximum size of a secret value in bytes. Secrets
+// are sent to the agent in the manifest, so they must stay small.
+const maxSecretValueSize = 64 << 10
+
+var (
+	secretEnvNameRegex         = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	secretWindowsFilePathRegex = regexp.MustCompile(`^[A-Za-z]:[\\/]`)
+)
+
+// secretScope is the user, organization or template that secrets belong to.
+// Exactly one of the IDs is valid.
+type secretScope struct {
+	UserID         uuid.NullUUID
+	OrganizationID uuid.NullUUID
+	TemplateID     uuid.NullUUID
+	// AuditOrganizationID is the organization the audit logs of the secrets
+	// are associated with, if any.
+	AuditOrganizationID uuid.UUID
+}
+
+// @Summary Get user secrets
+// @ID get-user-secrets
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Secrets
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {array} codersdk.Secret
+// @Router /users/{user}/secrets [get]
+func (api *API) userSecrets(rw http.ResponseWriter, r *http.Request) {
+	api.listSecrets(rw, r, userSecretScope(r))
+}
+
+// @Summary Create user secret
+// @ID create-user-secret
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Secrets
+// @Param user path string true "User ID, name, or me"
+// @Param request body codersdk.CreateSecretRequest true "Create secret request"
+// @Success 201 {object} codersdk.Secret
+// @Router /users/{user}/secrets [post]
+func (api *API) postUserSecret(rw http.ResponseWriter, r *http.Request) {
+	api.createSecret(rw, r, userSecretScope(r))
+}
+
+// @Summary Update user secret
+// @ID update-user-secret
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Secrets
+// @Param user path string true "User ID, name, or me"
+// @Param secret path string true "Secret name"
+// @Param request body codersdk.UpdateSecretRequest true "Update secret request"
+// @Success 200 {object} codersdk.Secret
+// @Router /users/{user}/secrets/{secret} [patch]
+func (api *API) patchUserSecret(rw http.ResponseWriter, r *http.Request) {
+	api.updateSecret(rw, r, userSecretScope(r))
+}
+
+// @Summary Delete user secret
+// @ID delete-user-secret
+// @Security CoderSessionToken
+// @Tags Secrets
+// @Param user path string true "User ID, name, or me"
+// @Param secret path string true "Secret name"
+// @Success 204
+// @Router /users/{user}/secrets/{secret} [delete]
+func (api *API) deleteUserSecret(rw http.ResponseWriter, r *http.Request) {
+	api.deleteSecret(rw, r, userSecretScope(r))
+}
+
+// @Summary Get organization secrets
+// @ID get-organization-secrets
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Secrets
+// @Param organization path string true "Organization ID" format(uuid)
+// @Success 200 {array} codersdk.Secret
+// @Router /organizations/{organization}/secrets [get]
+func (api *API) organizationSecrets(rw http.ResponseWriter, r *http.Request) {
+	api.listSecrets(rw, r, organizationSecretScope(r))
+}
+
+// @Summary Create organization secret
+// @ID create-organization-secret
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Secrets
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param request body codersdk.CreateSecretRequest true "Create secret request"
+// @Success 201 {object} codersdk.Secret
+// @Router /organizations/{organization}/secrets [post]
+func (api *API) postOrganizationSecret(rw http.ResponseWriter, r *http.Request) {
+	api.createSecret(rw, r, organizationSecretScope(r))
+}
+
+// @Summary Update organization secret
+// @ID update-organization-secret
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Secrets
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param secret path string true "Secret name"
+// @Param request body codersdk.UpdateSecretRequest true "Update secret request"
+// @Success 200 {object} codersdk.Secret
+// @Router /organizations/{organization}/secrets/{secret} [patch]
+func (api *API) patchOrganizationSecret(rw http.ResponseWriter, r *http.Request) {
+	api.updateSecret(rw, r, organizationSecretScope(r))
+}
+
+// @Summary Delete organization secret
+// @ID delete-organization-secret
+// @Security CoderSessionToken
+// @Tags Secrets
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param secret path string true "Secret name"
+// @Success 204
+// @Router /organizations/{organization}/secrets/{secret} [delete]
+func (api *API) deleteOrganizationSecret(rw http.ResponseWriter, r *http.Request) {
+	api.deleteSecret(rw, r, organizationSecretScope(r))
+}
+
+// @Summary Get template secrets
+// @ID get-template-secrets
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Secrets
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {array} codersdk.Secret
+// @Router /templates/{template}/secrets [get]
+func (api *API) templateSecrets(rw http.ResponseWriter, r *http.Request) {
+	api.listSecrets(rw, r, templateSecretScope(r))
+}
+
+// @Summary Create template secret
+// @ID create-template-secret
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Secrets
+// @Param template path string true "Template ID" format(uuid)
+// @Param request body codersdk.CreateSecretRequest true "Create secret request"
+// @Success 201 {object} codersdk.Secret
+// @Router /templates/{template}/secrets [post]
+func (api *API) postTemplateSecret(rw http.ResponseWriter, r *http.Request) {
+	api.createSecret(rw, r, templateSecretScope(r))
+}
+
+// @Summary Update template secret
+// @ID update-template-secret
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Secrets
+// @Param template path string true "Template ID" format(uuid)
+// @Param secret path string true "Secret name"
+// @Param request body codersdk.UpdateSecretRequest true "Update secret request"
+// @Success 200 {object} codersdk.Secret
+// @Router /templates/{template}/secrets/{secret} [patch]
+func (api *API) patchTemplateSecret(rw http.ResponseWriter, r *http.Request) {
+	api.updateSecret(rw, r, templateSecretScope(r))
+}
+
+// @Summary Delete template secret
+// @ID delete-template-secret
+// @Security CoderSessionToken
+// @Tags Secrets
+// @Param template path string true "Template ID" format(uuid)
+// @Param secret path string true "Secret name"
+// @Success 204
+// @Router /templates/{template}/secrets/{secret} [delete]
+func (api *API) deleteTemplateSecret(rw http.ResponseWriter, r *http.Request) {
+	api.deleteSecret(rw, r, templateSecretScope(r))
+}
+
+func userSecretScope(r *http.Request) secretScope {
+	user := httpmw.UserParam(r)
+	return secretScope{UserID: uuid.NullUUID{UUID: user.ID, Valid: true}}
+}
+
+func organizationSecretScope(r *http.Request) secretScope {
+	organization := httpmw.OrganizationParam(r)
+	return secretScope{
+		OrganizationID:      uuid.NullUUID{UUID: organization.ID, Valid: true},
+		AuditOrganizationID: organization.ID,
+	}
+}
+
+func templateSecretScope(r *http.Request) secretScope {
+	template := httpmw.TemplateParam(r)
+	return secretScope{
+		TemplateID:          uuid.NullUUID{UUID: template.ID, Valid: true},
+		AuditOrganizationID: template.OrganizationID,
+	}
+}
+
+func (api *API) listSecrets(rw http.ResponseWriter, r *http.Request, scope secretScope) {
+	ctx := r.Context()
+	secrets, err := api.Database.GetSecretsByScope(ctx, database.GetSecretsByScopeParams{
+		UserID:         scope.UserID,
+		OrganizationID: scope.OrganizationID,
+		TemplateID:     scope.TemplateID,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching secrets.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.List(secrets, db2sdk.Secret))
+}
+
+func (api *API) createSecret(rw http.ResponseWriter, r *http.Request, scope secretScope) {
+	var (
+		ctx               = r.Context()
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Secret](rw, &audit.RequestParams{
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionCreate,
+			OrganizationID: scope.AuditOrganizationID,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.CreateSecretRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if validations := validateSecret(req.EnvName, req.FilePath, req.Value); len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid secret.",
+			Validations: validations,
+		})
+		return
+	}
+
+	now := dbtime.Now()
+	secret, err := api.Database.InsertSecret(ctx, database.InsertSecretParams{
+		ID:             uuid.New(),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		Name:           req.Name,
+		Description:    req.Description,
+		Value:          req.Value,
+		EnvName:        req.EnvName,
+		FilePath:       req.FilePath,
+		UserID:         scope.UserID,
+		OrganizationID: scope.OrganizationID,
+		TemplateID:     scope.TemplateID,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if database.IsUniqueViolation(err,
+		database.UniqueIndexSecretsUserIDName,
+		database.UniqueIndexSecretsOrganizationIDName,
+		database.UniqueIndexSecretsTemplateIDName,
+	) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("A secret named %q already exists.", req.Name),
+			Validations: []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: "This value is already in use and should be unique.",
+			}},
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = secret
+
+	httpapi.Write(ctx, rw, http.StatusCreated, db2sdk.Secret(secret))
+}
+
+func (api *API) updateSecret(rw http.ResponseWriter, r *http.Request, scope secretScope) {
+	var (
+		ctx               = r.Context()
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Secret](rw, &audit.RequestParams{
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionWrite,
+			OrganizationID: scope.AuditOrganizationID,
+		})
+	)
+	defer commitAudit()
+
+	secret, ok := api.secretByName(rw, r, scope)
+	if !ok {
+		return
+	}
+	aReq.Old = secret
+
+	var req codersdk.UpdateSecretRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	params := database.UpdateSecretByIDParams{
+		ID:          secret.ID,
+		UpdatedAt:   dbtime.Now(),
+		Description: secret.Description,
+		Value:       secret.Value,
+		ValueKeyID:  secret.ValueKeyID,
+		EnvName:     secret.EnvName,
+		FilePath:    secret.FilePath,
+	}
+	if req.Description != nil {
+		params.Description = *req.Description
+	}
+	if req.Value != nil {
+		params.Value = *req.Value
+	}
+	if req.EnvName != nil {
+		params.EnvName = *req.EnvName
+	}
+	if req.FilePath != nil {
+		params.FilePath = *req.FilePath
+	}
+	if validations := validateSecret(params.EnvName, params.FilePath, params.Value); len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid secret.",
+			Validations: validations,
+		})
+		return
+	}
+
+	updated, err := api.Database.UpdateSecretByID(ctx, params)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = updated
+
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.Secret(updated))
+}
+
+func (api *API) deleteSecret(rw http.ResponseWriter, r *http.Request, scope secretScope) {
+	var (
+		ctx               = r.Context()
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Secret](rw, &audit.RequestParams{
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionDelete,
+			OrganizationID: scope.AuditOrganizationID,
+		})
+	)
+	defer commitAudit()
+
+	secret, ok := api.secretByName(rw, r, scope)
+	if !ok {
+		return
+	}
+	aReq.Old = secret
+
+	err := api.Database.DeleteSecretByID(ctx, secret.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// secretByName fetches the secret named by the URL parameter in the scope,
+// and writes an error response if it cannot be fetched.
+func (api *API) secretByName(rw http.ResponseWriter, r *http.Request, scope secretScope) (database.Secret, bool) {
+	ctx := r.Context()
+	secret, err := api.Database.GetSecretByScopeAndName(ctx, database.GetSecretByScopeAndNameParams{
+		UserID:         scope.UserID,
+		OrganizationID: scope.OrganizationID,
+		TemplateID:     scope.TemplateID,
+		Name:           chi.URLParam(r, "secret"),
+	})
+	if errors.Is(err, sql.ErrNoRows) || dbauthz.IsNotAuthorizedError(err) {
+		httpapi.ResourceNotFound(rw)
+		return database.Secret{}, false
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching secret.",
+			Detail:  err.Error(),
+		})
+		return database.Secret{}, false
+	}
+	return secret, true
+}
+
+// validateSecret validates where a secret is injected into workspaces, and
+// the size of its value.
+func validateSecret(envName, filePath, value string) []codersdk.ValidationError {
+	var validations []codersdk.ValidationError
+	if envName == "" && filePath == "" {
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "env_name",
+			Detail: "Either an environment variable name or a file path is required.",
+		})
+	}
+	if envName != "" {
+		if !secretEnvNameRegex.MatchString(envName) {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "env_name",
+				Detail: fmt.Sprintf("%q is not a valid environment variable name.", envName),
+			})
+		} else if strings.HasPrefix(strings.ToUpper(envName), "CODER_") {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "env_name",
+				Detail: "Environment variables starting with CODER_ are reserved.",
+			})
+		}
+	}
+	if filePath != "" && !strings.HasPrefix(filePath, "/") && !strings.HasPrefix(filePath, "~/") && !secretWindowsFilePathRegex.MatchString(filePath) {
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "file_path",
+			Detail: "The file path must be absolute or start with ~/.",
+		})
+	}
+	if len(value) > maxSecretValueSize {
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "value",
+			Detail: fmt.Sprintf("The value must not be larger than %d bytes.", maxSecretValueSize),
+		})
+	}
+	return validations
+}
@@ -0,0 +1,192 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestUserSecrets(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		created, err := member.CreateUserSecret(ctx, codersdk.Me, codersdk.CreateSecretRequest{
+			Name:    "github-token",
+			Value:   "ghp_token",
+			EnvName: "GITHUB_TOKEN",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "github-token", created.Name)
+		require.Equal(t, "GITHUB_TOKEN", created.EnvName)
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			Action:       database.AuditActionCreate,
+			ResourceType: database.ResourceTypeSecret,
+			ResourceID:   created.ID,
+		}))
+
+		updated, err := member.UpdateUserSecret(ctx, codersdk.Me, created.Name, codersdk.UpdateSecretRequest{
+			Description: ptr.Ref("Used by gh"),
+			FilePath:    ptr.Ref("~/.config/gh/token"),
+		})
+		require.NoError(t, err)
+		require.Equal(t, "Used by gh", updated.Description)
+		require.Equal(t, "GITHUB_TOKEN", updated.EnvName)
+		require.Equal(t, "~/.config/gh/token", updated.FilePath)
+
+		secrets, err := member.UserSecrets(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Len(t, secrets, 1)
+		require.Equal(t, updated, secrets[0])
+
+		err = member.DeleteUserSecret(ctx, codersdk.Me, created.Name)
+		require.NoError(t, err)
+		secrets, err = member.UserSecrets(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Empty(t, secrets)
+
+		err = member.DeleteUserSecret(ctx, codersdk.Me, created.Name)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Conflict", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		req := codersdk.CreateSecretRequest{
+			Name:    "token",
+			Value:   "value",
+			EnvName: "TOKEN",
+		}
+		_, err := client.CreateUserSecret(ctx, codersdk.Me, req)
+		require.NoError(t, err)
+		_, err = client.CreateUserSecret(ctx, codersdk.Me, req)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		for _, req := range []codersdk.CreateSecretRequest{
+			{Name: "no-target", Value: "value"},
+			{Name: "invalid-env", Value: "value", EnvName: "1TOKEN"},
+			{Name: "reserved-env", Value: "value", EnvName: "CODER_AGENT_TOKEN"},
+			{Name: "relative-file", Value: "value", FilePath: "token"},
+		} {
+			_, err := client.CreateUserSecret(ctx, codersdk.Me, req)
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr, req.Name)
+			require.Equal(t, http.StatusBadRequest, apiErr.StatusCode(), req.Name)
+		}
+	})
+
+	t.Run("OtherUser", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		_, err := client.CreateUserSecret(ctx, codersdk.Me, codersdk.CreateSecretRequest{
+			Name:    "token",
+			Value:   "value",
+			EnvName: "TOKEN",
+		})
+		require.NoError(t, err)
+
+		// Members cannot see the secrets of other users.
+		_, err = member.UserSecrets(ctx, owner.UserID.String())
+		require.Error(t, err)
+	})
+}
+
+func TestOrganizationSecrets(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	client := coderdtest.New(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	created, err := client.CreateOrganizationSecret(ctx, owner.OrganizationID, codersdk.CreateSecretRequest{
+		Name:     "ca-cert",
+		Value:    "-----BEGIN CERTIFICATE-----",
+		FilePath: "/etc/ssl/certs/internal.pem",
+	})
+	require.NoError(t, err)
+
+	secrets, err := client.OrganizationSecrets(ctx, owner.OrganizationID)
+	require.NoError(t, err)
+	require.Equal(t, []codersdk.Secret{created}, secrets)
+
+	// Members cannot manage the secrets of the organization.
+	_, err = member.CreateOrganizationSecret(ctx, owner.OrganizationID, codersdk.CreateSecretRequest{
+		Name:    "token",
+		Value:   "value",
+		EnvName: "TOKEN",
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+}
+
+func TestTemplateSecrets(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+	created, err := client.CreateTemplateSecret(ctx, template.ID, codersdk.CreateSecretRequest{
+		Name:    "npm-token",
+		Value:   "token",
+		EnvName: "NPM_TOKEN",
+	})
+	require.NoError(t, err)
+
+	updated, err := client.UpdateTemplateSecret(ctx, template.ID, created.Name, codersdk.UpdateSecretRequest{
+		Value: ptr.Ref("rotated"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, created.EnvName, updated.EnvName)
+
+	secrets, err := client.TemplateSecrets(ctx, template.ID)
+	require.NoError(t, err)
+	require.Len(t, secrets, 1)
+
+	// Members cannot manage the secrets of the template.
+	err = member.DeleteTemplateSecret(ctx, template.ID, created.Name)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+	err = client.DeleteTemplateSecret(ctx, template.ID, created.Name)
+	require.NoError(t, err)
+}
@@ -0,0 +1,402 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Create template rollout
+// @Description Starts a staged rollout of a template version. Workspaces
+// @Description included in the rollout build the candidate version instead
+// @Description of the active version until it's promoted or rolled back.
+// @ID create-template-rollout
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param request body codersdk.CreateTemplateRolloutRequest true "Create rollout request"
+// @Success 201 {object} codersdk.TemplateRollout
+// @Router /templates/{template}/rollouts [post]
+func (api *API) postTemplateRollout(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+	apiKey := httpmw.APIKey(r)
+
+	var req codersdk.CreateTemplateRolloutRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if !api.Authorize(r, policy.ActionUpdate, template) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if req.Percent == 0 && req.GroupID == nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "A rollout must include a percentage of workspaces or a group.",
+		})
+		return
+	}
+
+	version, err := api.Database.GetTemplateVersionByID(ctx, req.TemplateVersionID)
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "Template version not found.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if version.TemplateID.UUID != template.ID {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The provided template version doesn't belong to the specified template.",
+		})
+		return
+	}
+	if version.ID == template.ActiveVersionID {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The provided template version is already the active version.",
+		})
+		return
+	}
+	if version.Archived {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The provided template version is archived.",
+		})
+		return
+	}
+	job, err := api.Database.GetProvisionerJobByID(ctx, version.JobID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version job status.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if job.JobStatus != database.ProvisionerJobStatusSucceeded {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Only versions that have been built successfully can be rolled out.",
+			Detail:  fmt.Sprintf("Attempted to roll out a version with a %s build", job.JobStatus),
+		})
+		return
+	}
+
+	groupID, ok := api.templateRolloutGroupID(ctx, rw, template, req.GroupID)
+	if !ok {
+		return
+	}
+
+	now := dbtime.Now()
+	rollout, err := api.TemplateRollouts.Start(ctx, database.InsertTemplateRolloutParams{
+		ID:                uuid.New(),
+		TemplateID:        template.ID,
+		TemplateVersionID: version.ID,
+		CreatedBy:         apiKey.UserID,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+		Percent:           req.Percent,
+		GroupID:           groupID,
+		MinBuilds:         req.MinBuilds,
+		MaxFailureRate:    req.MaxFailureRate,
+		AutoPromote:       req.AutoPromote,
+	})
+	if database.IsUniqueViolation(err, database.UniqueTemplateRolloutsTemplateIDInProgressIndex) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "A rollout of this template is already in progress.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error starting template rollout.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, db2sdk.TemplateRollout(rollout, database.GetTemplateRolloutBuildCountsRow{}))
+}
+
+// @Summary Get template rollouts
+// @ID get-template-rollouts
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {array} codersdk.TemplateRollout
+// @Router /templates/{template}/rollouts [get]
+func (api *API) templateRollouts(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	rollouts, err := api.Database.GetTemplateRolloutsByTemplateID(ctx, template.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template rollouts.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	converted := make([]codersdk.TemplateRollout, 0, len(rollouts))
+	for _, rollout := range rollouts {
+		c, err := api.convertTemplateRollout(ctx, rollout)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching template rollout builds.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		converted = append(converted, c)
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+// @Summary Get template rollout
+// @ID get-template-rollout
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param rollout path string true "Rollout ID" format(uuid)
+// @Success 200 {object} codersdk.TemplateRollout
+// @Router /templates/{template}/rollouts/{rollout} [get]
+func (api *API) templateRollout(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	rollout, ok := api.templateRolloutParam(rw, r)
+	if !ok {
+		return
+	}
+
+	converted, err := api.convertTemplateRollout(ctx, rollout)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template rollout builds.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+// @Summary Update template rollout
+// @Description Changes the workspaces included in a rollout in progress, or
+// @Description completes it. Promoting a rollout makes its candidate the
+// @Description active version of the template.
+// @ID update-template-rollout
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param rollout path string true "Rollout ID" format(uuid)
+// @Param request body codersdk.UpdateTemplateRolloutRequest true "Update rollout request"
+// @Success 200 {object} codersdk.TemplateRollout
+// @Router /templates/{template}/rollouts/{rollout} [patch]
+func (api *API) patchTemplateRollout(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	var req codersdk.UpdateTemplateRolloutRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	rollout, ok := api.templateRolloutParam(rw, r)
+	if !ok {
+		return
+	}
+	if !api.Authorize(r, policy.ActionUpdate, template) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if rollout.Status != database.TemplateRolloutStatusInProgress {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("The rollout was already %s.", rollout.Status),
+		})
+		return
+	}
+
+	if req.Percent != nil || req.GroupID != nil {
+		params := database.UpdateTemplateRolloutByIDParams{
+			ID:        rollout.ID,
+			Percent:   rollout.Percent,
+			GroupID:   rollout.GroupID,
+			UpdatedAt: dbtime.Now(),
+		}
+		if req.Percent != nil {
+			params.Percent = *req.Percent
+		}
+		if req.GroupID != nil {
+			params.GroupID, ok = api.templateRolloutGroupID(ctx, rw, template, req.GroupID)
+			if !ok {
+				return
+			}
+		}
+		if params.Percent == 0 && !params.GroupID.Valid {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "A rollout must include a percentage of workspaces or a group.",
+			})
+			return
+		}
+		var err error
+		rollout, err = api.Database.UpdateTemplateRolloutByID(ctx, params)
+		if httpapi.Is404Error(err) {
+			httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+				Message: "The rollout was completed in the meantime.",
+			})
+			return
+		}
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error updating template rollout.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	}
+
+	if req.Status != nil {
+		switch *req.Status {
+		case codersdk.TemplateRolloutStatusPromoted, codersdk.TemplateRolloutStatusRolledBack, codersdk.TemplateRolloutStatusCanceled:
+		default:
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Invalid rollout status %q.", *req.Status),
+				Detail:  "A rollout can be promoted, rolled back or canceled.",
+			})
+			return
+		}
+		var err error
+		rollout, err = api.TemplateRollouts.Finish(ctx, rollout, database.TemplateRolloutStatus(*req.Status), req.Reason)
+		if xerrors.Is(err, sql.ErrNoRows) {
+			httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+				Message: "The rollout was completed in the meantime.",
+			})
+			return
+		}
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error completing template rollout.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		if rollout.Status == database.TemplateRolloutStatusPromoted {
+			api.publishTemplateUpdate(ctx, template.ID)
+		}
+	}
+
+	converted, err := api.convertTemplateRollout(ctx, rollout)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template rollout builds.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+// templateRolloutParam fetches the rollout in the URL and checks it belongs
+// to the template in the URL. It writes an error response if it returns false.
+func (api *API) templateRolloutParam(rw http.ResponseWriter, r *http.Request) (database.TemplateRollout, bool) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+	rolloutID, ok := httpmw.ParseUUIDParam(rw, r, "rollout")
+	if !ok {
+		return database.TemplateRollout{}, false
+	}
+	rollout, err := api.Database.GetTemplateRolloutByID(ctx, rolloutID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return database.TemplateRollout{}, false
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template rollout.",
+			Detail:  err.Error(),
+		})
+		return database.TemplateRollout{}, false
+	}
+	if rollout.TemplateID != template.ID {
+		httpapi.ResourceNotFound(rw)
+		return database.TemplateRollout{}, false
+	}
+	return rollout, true
+}
+
+// templateRolloutGroupID validates the group of a rollout. The nil UUID
+// removes the group. It writes an error response if it returns false.
+func (api *API) templateRolloutGroupID(ctx context.Context, rw http.ResponseWriter, template database.Template, groupID *uuid.UUID) (uuid.NullUUID, bool) {
+	if groupID == nil || *groupID == uuid.Nil {
+		return uuid.NullUUID{}, true
+	}
+	group, err := api.Database.GetGroupByID(ctx, *groupID)
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Group not found.",
+		})
+		return uuid.NullUUID{}, false
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching group.",
+			Detail:  err.Error(),
+		})
+		return uuid.NullUUID{}, false
+	}
+	if group.OrganizationID != template.OrganizationID {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The group must belong to the organization of the template.",
+		})
+		return uuid.NullUUID{}, false
+	}
+	return uuid.NullUUID{UUID: group.ID, Valid: true}, true
+}
+
+func (api *API) convertTemplateRollout(ctx context.Context, rollout database.TemplateRollout) (codersdk.TemplateRollout, error) {
+	counts, err := api.Database.GetTemplateRolloutBuildCounts(ctx, database.GetTemplateRolloutBuildCountsParams{
+		TemplateVersionID: rollout.TemplateVersionID,
+		Since:             rollout.CreatedAt,
+	})
+	if err != nil {
+		return codersdk.TemplateRollout{}, xerrors.Errorf("get template rollout build counts: %w", err)
+	}
+	return db2sdk.TemplateRollout(rollout, counts), nil
+}
+
+// WorkspaceBuildCompleted is called by provisionerd whenever a workspace build
+// completes, successfully or not.
+func (api *API) WorkspaceBuildCompleted(ctx context.Context, build database.WorkspaceBuild) {
+	api.BuildTraceExporter.WorkspaceBuildCompleted(ctx, build)
+	api.TemplateRollouts.WorkspaceBuildCompleted(ctx, build)
+}
+
+// templateActiveVersionChanged completes the rollout in progress of a template
+// after its active version was changed by hand.
+func (api *API) templateActiveVersionChanged(ctx context.Context, templateID, versionID uuid.UUID) {
+	err := api.TemplateRollouts.ActiveVersionChanged(ctx, templateID, versionID)
+	if err != nil {
+		api.Logger.Error(ctx, "complete template rollout after active version changed",
+			slog.F("template_id", templateID), slog.F("template_version_id", versionID), slog.Error(err))
+	}
+}
@@ -0,0 +1,284 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/notifications/notificationstest"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateRollouts(t *testing.T) {
+	t.Parallel()
+
+	t.Run("AutoPromote", func(t *testing.T) {
+		t.Parallel()
+
+		notify := &notificationstest.FakeEnqueuer{}
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true, NotificationsEnqueuer: notify})
+		owner := coderdtest.CreateFirstUser(t, client)
+		memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		candidate := coderdtest.UpdateTemplateVersion(t, client, owner.OrganizationID, nil, template.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, candidate.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		rollout, err := client.CreateTemplateRollout(ctx, template.ID, codersdk.CreateTemplateRolloutRequest{
+			TemplateVersionID: candidate.ID,
+			Percent:           100,
+			MinBuilds:         1,
+			MaxFailureRate:    0.5,
+			AutoPromote:       true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.TemplateRolloutStatusInProgress, rollout.Status)
+
+		// New workspaces build the candidate.
+		workspace := coderdtest.CreateWorkspace(t, memberClient, template.ID)
+		require.Equal(t, candidate.ID, workspace.LatestBuild.TemplateVersionID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, memberClient, workspace.LatestBuild.ID)
+
+		require.Eventually(t, func() bool {
+			rollout, err = client.TemplateRollout(ctx, template.ID, rollout.ID)
+			return err == nil && rollout.Status == codersdk.TemplateRolloutStatusPromoted
+		}, testutil.WaitShort, testutil.IntervalFast)
+		require.EqualValues(t, 1, rollout.Builds)
+		require.EqualValues(t, 0, rollout.FailedBuilds)
+		require.NotNil(t, rollout.CompletedAt)
+
+		template, err = client.Template(ctx, template.ID)
+		require.NoError(t, err)
+		require.Equal(t, candidate.ID, template.ActiveVersionID)
+
+		sent := notify.Sent(notificationstest.WithTemplateID(notifications.TemplateTemplateVersionRolloutUpdated))
+		require.Len(t, sent, 2)
+		require.Equal(t, "started", sent[0].Labels["status"])
+		require.Equal(t, "promoted", sent[1].Labels["status"])
+	})
+
+	t.Run("RollBack", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		candidate := coderdtest.UpdateTemplateVersion(t, client, owner.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.PlanComplete,
+			ProvisionApply: echo.ApplyFailed,
+		}, template.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, candidate.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		rollout, err := client.CreateTemplateRollout(ctx, template.ID, codersdk.CreateTemplateRolloutRequest{
+			TemplateVersionID: candidate.ID,
+			Percent:           100,
+			MinBuilds:         1,
+			MaxFailureRate:    0,
+			AutoPromote:       true,
+		})
+		require.NoError(t, err)
+
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		require.Equal(t, candidate.ID, workspace.LatestBuild.TemplateVersionID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		require.Eventually(t, func() bool {
+			rollout, err = client.TemplateRollout(ctx, template.ID, rollout.ID)
+			return err == nil && rollout.Status == codersdk.TemplateRolloutStatusRolledBack
+		}, testutil.WaitShort, testutil.IntervalFast)
+		require.EqualValues(t, 1, rollout.FailedBuilds)
+		require.Contains(t, rollout.StatusReason, "1 of 1 builds failed")
+
+		template, err = client.Template(ctx, template.ID)
+		require.NoError(t, err)
+		require.Equal(t, version.ID, template.ActiveVersionID)
+
+		// New workspaces build the active version again.
+		workspace = coderdtest.CreateWorkspace(t, client, template.ID)
+		require.Equal(t, version.ID, workspace.LatestBuild.TemplateVersionID)
+	})
+
+	t.Run("ActiveVersionRequested", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+		candidate := coderdtest.UpdateTemplateVersion(t, client, owner.OrganizationID, nil, template.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, candidate.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.CreateTemplateRollout(ctx, template.ID, codersdk.CreateTemplateRolloutRequest{
+			TemplateVersionID: candidate.ID,
+			Percent:           100,
+			MinBuilds:         5,
+		})
+		require.NoError(t, err)
+
+		// Builds of the active version requested explicitly stay on the active
+		// version.
+		created := coderdtest.CreateWorkspace(t, client, uuid.Nil, func(cwr *codersdk.CreateWorkspaceRequest) {
+			cwr.TemplateVersionID = version.ID
+		})
+		require.Equal(t, version.ID, created.LatestBuild.TemplateVersionID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, created.LatestBuild.ID)
+
+		build, err := client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			TemplateVersionID: version.ID,
+			Transition:        codersdk.WorkspaceTransitionStart,
+		})
+		require.NoError(t, err)
+		require.Equal(t, version.ID, build.TemplateVersionID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+
+		// Updates to the active version follow the rollout.
+		build, err = client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			ActiveVersion: true,
+			Transition:    codersdk.WorkspaceTransitionStart,
+		})
+		require.NoError(t, err)
+		require.Equal(t, candidate.ID, build.TemplateVersionID)
+
+		_, err = client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			TemplateVersionID: version.ID,
+			ActiveVersion:     true,
+			Transition:        codersdk.WorkspaceTransitionStart,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("Manual", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		candidate := coderdtest.UpdateTemplateVersion(t, client, owner.OrganizationID, nil, template.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, candidate.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		var apiErr *codersdk.Error
+
+		// A rollout must include some workspaces.
+		_, err := client.CreateTemplateRollout(ctx, template.ID, codersdk.CreateTemplateRolloutRequest{
+			TemplateVersionID: candidate.ID,
+			MinBuilds:         1,
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		// Members can't start rollouts.
+		req := codersdk.CreateTemplateRolloutRequest{
+			TemplateVersionID: candidate.ID,
+			Percent:           10,
+			MinBuilds:         5,
+			MaxFailureRate:    0.1,
+		}
+		_, err = memberClient.CreateTemplateRollout(ctx, template.ID, req)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		rollout, err := client.CreateTemplateRollout(ctx, template.ID, req)
+		require.NoError(t, err)
+
+		// Only one rollout per template can be in progress.
+		_, err = client.CreateTemplateRollout(ctx, template.ID, req)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		rollout, err = client.UpdateTemplateRollout(ctx, template.ID, rollout.ID, codersdk.UpdateTemplateRolloutRequest{
+			Percent: ptr.Ref[int32](50),
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, 50, rollout.Percent)
+
+		// Removing the group of a rollout without a percentage leaves no
+		// workspaces.
+		_, err = client.UpdateTemplateRollout(ctx, template.ID, rollout.ID, codersdk.UpdateTemplateRolloutRequest{
+			Percent: ptr.Ref[int32](0),
+			GroupID: ptr.Ref(uuid.Nil),
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		rollout, err = client.UpdateTemplateRollout(ctx, template.ID, rollout.ID, codersdk.UpdateTemplateRolloutRequest{
+			Status: ptr.Ref(codersdk.TemplateRolloutStatusPromoted),
+			Reason: "Looks good.",
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.TemplateRolloutStatusPromoted, rollout.Status)
+		require.Equal(t, "Looks good.", rollout.StatusReason)
+
+		template, err = client.Template(ctx, template.ID)
+		require.NoError(t, err)
+		require.Equal(t, candidate.ID, template.ActiveVersionID)
+
+		// Completed rollouts can't be changed.
+		_, err = client.UpdateTemplateRollout(ctx, template.ID, rollout.ID, codersdk.UpdateTemplateRolloutRequest{
+			Status: ptr.Ref(codersdk.TemplateRolloutStatusCanceled),
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		rollouts, err := client.TemplateRollouts(ctx, template.ID)
+		require.NoError(t, err)
+		require.Len(t, rollouts, 1)
+		require.Equal(t, rollout.ID, rollouts[0].ID)
+	})
+
+	t.Run("ActiveVersionChanged", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		candidate := coderdtest.UpdateTemplateVersion(t, client, owner.OrganizationID, nil, template.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, candidate.ID)
+		other := coderdtest.UpdateTemplateVersion(t, client, owner.OrganizationID, nil, template.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, other.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		rollout, err := client.CreateTemplateRollout(ctx, template.ID, codersdk.CreateTemplateRolloutRequest{
+			TemplateVersionID: candidate.ID,
+			Percent:           10,
+			MinBuilds:         5,
+			MaxFailureRate:    0.1,
+		})
+		require.NoError(t, err)
+
+		// Promoting another version by hand cancels the rollout.
+		err = client.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{ID: other.ID})
+		require.NoError(t, err)
+		rollout, err = client.TemplateRollout(ctx, template.ID, rollout.ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.TemplateRolloutStatusCanceled, rollout.Status)
+	})
+}
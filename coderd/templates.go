@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"sort"
 	"time"
 
@@ -706,6 +707,40 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	sessionRecordingEnabled := template.SessionRecordingEnabled
+	if req.SessionRecordingEnabled != nil {
+		sessionRecordingEnabled = *req.SessionRecordingEnabled
+	}
+
+	requireBuildApproval := template.RequireBuildApproval
+	if req.RequireBuildApproval != nil {
+		requireBuildApproval = *req.RequireBuildApproval
+	}
+	buildApprovalParameters := template.BuildApprovalParameters
+	if req.BuildApprovalParameters != nil {
+		buildApprovalParameters = *req.BuildApprovalParameters
+		if buildApprovalParameters == nil {
+			buildApprovalParameters = []string{}
+		}
+	}
+	buildApprovalGroupID := template.BuildApprovalGroupID
+	if req.BuildApprovalGroupID != nil {
+		buildApprovalGroupID = uuid.NullUUID{UUID: *req.BuildApprovalGroupID, Valid: *req.BuildApprovalGroupID != uuid.Nil}
+		if buildApprovalGroupID.Valid && buildApprovalGroupID != template.BuildApprovalGroupID {
+			group, err := api.Database.GetGroupByID(ctx, buildApprovalGroupID.UUID)
+			if err != nil || group.OrganizationID != template.OrganizationID {
+				validErrs = append(validErrs, codersdk.ValidationError{Field: "build_approval_group_id", Detail: "Group does not exist in the template's organization."})
+			}
+		}
+	}
+	buildApprovalTimeout := template.BuildApprovalTimeout
+	if req.BuildApprovalTimeoutMillis != nil {
+		if *req.BuildApprovalTimeoutMillis < 0 || (*req.BuildApprovalTimeoutMillis > 0 && *req.BuildApprovalTimeoutMillis < minTTL) {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "build_approval_timeout_ms", Detail: "Value must be at least one minute."})
+		}
+		buildApprovalTimeout = int64(time.Duration(*req.BuildApprovalTimeoutMillis) * time.Millisecond)
+	}
+
 	if len(validErrs) > 0 {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message:     "Invalid request to update template metadata!",
@@ -733,7 +768,12 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 			req.TimeTilDormantAutoDeleteMillis == time.Duration(template.TimeTilDormantAutoDelete).Milliseconds() &&
 			req.RequireActiveVersion == template.RequireActiveVersion &&
 			(deprecationMessage == template.Deprecated) &&
-			maxPortShareLevel == template.MaxPortSharingLevel {
+			maxPortShareLevel == template.MaxPortSharingLevel &&
+			sessionRecordingEnabled == template.SessionRecordingEnabled &&
+			requireBuildApproval == template.RequireBuildApproval &&
+			slices.Equal(buildApprovalParameters, template.BuildApprovalParameters) &&
+			buildApprovalGroupID == template.BuildApprovalGroupID &&
+			buildApprovalTimeout == template.BuildApprovalTimeout {
 			return nil
 		}
 
@@ -774,6 +814,11 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 			AllowUserCancelWorkspaceJobs: req.AllowUserCancelWorkspaceJobs,
 			GroupACL:                     groupACL,
 			MaxPortSharingLevel:          maxPortShareLevel,
+			SessionRecordingEnabled:      sessionRecordingEnabled,
+			RequireBuildApproval:         requireBuildApproval,
+			BuildApprovalParameters:      buildApprovalParameters,
+			BuildApprovalGroupID:         buildApprovalGroupID,
+			BuildApprovalTimeout:         buildApprovalTimeout,
 		})
 		if err != nil {
 			return xerrors.Errorf("update template metadata: %w", err)
@@ -1018,6 +1063,15 @@ func (api *API) convertTemplate(
 	portSharer := *(api.PortSharer.Load())
 	maxPortShareLevel := portSharer.ConvertMaxLevel(template.MaxPortSharingLevel)
 
+	buildApprovalParameters := template.BuildApprovalParameters
+	if buildApprovalParameters == nil {
+		buildApprovalParameters = []string{}
+	}
+	var buildApprovalGroupID *uuid.UUID
+	if template.BuildApprovalGroupID.Valid {
+		buildApprovalGroupID = &template.BuildApprovalGroupID.UUID
+	}
+
 	return codersdk.Template{
 		ID:                             template.ID,
 		CreatedAt:                      template.CreatedAt,
@@ -1056,6 +1110,13 @@ func (api *API) convertTemplate(
 		Deprecated:           templateAccessControl.IsDeprecated(),
 		DeprecationMessage:   templateAccessControl.Deprecated,
 		MaxPortShareLevel:    maxPortShareLevel,
+
+		SessionRecordingEnabled: template.SessionRecordingEnabled,
+
+		RequireBuildApproval:       template.RequireBuildApproval,
+		BuildApprovalParameters:    buildApprovalParameters,
+		BuildApprovalGroupID:       buildApprovalGroupID,
+		BuildApprovalTimeoutMillis: time.Duration(template.BuildApprovalTimeout).Milliseconds(),
 	}
 }
 
@@ -489,6 +489,35 @@ func (api *API) postTemplateVersionDryRun(rw http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	if req.WorkspaceID != uuid.Nil {
+		// Previewing an update plans against the workspace's state, so only
+		// allow it for users who could update the workspace themselves.
+		workspace, err := api.Database.GetWorkspaceByID(ctx, req.WorkspaceID)
+		if httpapi.Is404Error(err) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Workspace %q not found.", req.WorkspaceID),
+			})
+			return
+		}
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching workspace.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		if !api.Authorize(r, policy.ActionUpdate, workspace) {
+			httpapi.Forbidden(rw)
+			return
+		}
+		if !templateVersion.TemplateID.Valid || workspace.TemplateID != templateVersion.TemplateID.UUID {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "The template version must belong to the workspace's template.",
+			})
+			return
+		}
+	}
+
 	richParameterValues := make([]database.WorkspaceBuildParameter, len(req.RichParameterValues))
 	for i, v := range req.RichParameterValues {
 		richParameterValues[i] = database.WorkspaceBuildParameter{
@@ -504,6 +533,7 @@ func (api *API) postTemplateVersionDryRun(rw http.ResponseWriter, r *http.Reques
 		TemplateVersionID:   templateVersion.ID,
 		WorkspaceName:       req.WorkspaceName,
 		RichParameterValues: richParameterValues,
+		WorkspaceID:         req.WorkspaceID,
 	})
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -541,6 +571,7 @@ func (api *API) postTemplateVersionDryRun(rw http.ResponseWriter, r *http.Reques
 			Valid:      true,
 			RawMessage: metadataRaw,
 		},
+		Priority: provisionerdserver.JobPriorityTemplateVersion,
 	})
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -635,6 +666,24 @@ func (api *API) templateVersionDryRunResources(rw http.ResponseWriter, r *http.R
 	api.provisionerJobResources(rw, r, job.ProvisionerJob)
 }
 
+// @Summary Get template version dry-run resource changes by job ID
+// @ID get-template-version-dry-run-resource-changes-by-job-id
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param templateversion path string true "Template version ID" format(uuid)
+// @Param jobID path string true "Job ID" format(uuid)
+// @Success 200 {array} codersdk.ProvisionerJobResourceChange
+// @Router /templateversions/{templateversion}/dry-run/{jobID}/resource-changes [get]
+func (api *API) templateVersionDryRunResourceChanges(rw http.ResponseWriter, r *http.Request) {
+	job, ok := api.fetchTemplateVersionDryRunJob(rw, r)
+	if !ok {
+		return
+	}
+
+	api.provisionerJobResourceChanges(rw, r, job.ProvisionerJob)
+}
+
 // @Summary Get template version dry-run logs by job ID
 // @ID get-template-version-dry-run-logs-by-job-id
 // @Security CoderSessionToken
@@ -1404,6 +1453,7 @@ func (api *API) patchActiveTemplateVersion(rw http.ResponseWriter, r *http.Reque
 	newTemplate.ActiveVersionID = req.ID
 	aReq.New = newTemplate
 
+	api.templateActiveVersionChanged(ctx, template.ID, req.ID)
 	api.publishTemplateUpdate(ctx, template.ID)
 
 	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
@@ -1645,6 +1695,7 @@ func (api *API) postTemplateVersionsByOrganization(rw http.ResponseWriter, r *ht
 				Valid:      true,
 				RawMessage: traceMetadataRaw,
 			},
+			Priority: provisionerdserver.JobPriorityTemplateVersion,
 		})
 		if err != nil {
 			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
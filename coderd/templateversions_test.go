@@ -1150,6 +1150,78 @@ func TestTemplateVersionDryRun(t *testing.T) {
 		require.Equal(t, resource.Type, resources[0].Type)
 	})
 
+	t.Run("PreviewWorkspaceUpdate", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		change := &proto.ResourceChange{
+			Address: "docker_volume.home",
+			Type:    "docker_volume",
+			Name:    "home",
+			Action:  "replace",
+			Attributes: []*proto.AttributeChange{{
+				Path:              "name",
+				Before:            `"home"`,
+				After:             `"home-2"`,
+				ForcesReplacement: true,
+			}},
+		}
+		newVersion := coderdtest.UpdateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse: echo.ParseComplete,
+			ProvisionPlan: []*proto.Response{{
+				Type: &proto.Response_Plan{
+					Plan: &proto.PlanComplete{
+						ResourceChanges: []*proto.ResourceChange{change},
+					},
+				},
+			}},
+			ProvisionApply: echo.ApplyComplete,
+		}, template.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, newVersion.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		job, err := client.CreateTemplateVersionDryRun(ctx, newVersion.ID, codersdk.CreateTemplateVersionDryRunRequest{
+			WorkspaceID: workspace.ID,
+		})
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			job, err := client.TemplateVersionDryRun(ctx, newVersion.ID, job.ID)
+			return assert.NoError(t, err) && job.Status == codersdk.ProvisionerJobSucceeded
+		}, testutil.WaitShort, testutil.IntervalFast)
+
+		changes, err := client.TemplateVersionDryRunResourceChanges(ctx, newVersion.ID, job.ID)
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.ProvisionerJobResourceChange{{
+			JobID:        job.ID,
+			Address:      change.Address,
+			ResourceType: change.Type,
+			ResourceName: change.Name,
+			Action:       codersdk.ProvisionerJobResourceChangeActionReplace,
+			Attributes: []codersdk.ProvisionerJobResourceAttributeChange{{
+				Path:              "name",
+				Before:            `"home"`,
+				After:             `"home-2"`,
+				ForcesReplacement: true,
+			}},
+		}}, changes)
+
+		// The version must belong to the workspace's template.
+		otherVersion := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, otherVersion.ID)
+		_, err = client.CreateTemplateVersionDryRun(ctx, otherVersion.ID, codersdk.CreateTemplateVersionDryRunRequest{
+			WorkspaceID: workspace.ID,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
 	t.Run("ImportNotFinished", func(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
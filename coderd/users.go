@@ -1484,7 +1484,7 @@ func userOrganizationIDs(ctx context.Context, api *API, user database.User) ([]u
 }
 
 func convertAPIKey(k database.APIKey) codersdk.APIKey {
-	return codersdk.APIKey{
+	key := codersdk.APIKey{
 		ID:              k.ID,
 		UserID:          k.UserID,
 		LastUsed:        k.LastUsed,
@@ -1496,4 +1496,15 @@ func convertAPIKey(k database.APIKey) codersdk.APIKey {
 		LifetimeSeconds: k.LifetimeSeconds,
 		TokenName:       k.TokenName,
 	}
+	if k.Scope == database.APIKeyScopeCustom {
+		for _, perm := range k.ScopePermissions {
+			key.ScopePermissions = append(key.ScopePermissions, codersdk.APIKeyScopePermission{
+				ResourceType:   codersdk.RBACResource(perm.ResourceType),
+				Action:         codersdk.RBACAction(perm.Action),
+				OrganizationID: perm.OrganizationID,
+			})
+		}
+		key.ScopeAllowList = k.ScopeAllowList
+	}
+	return key
 }
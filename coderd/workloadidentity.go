@@ -0,0 +1,27 @@
+package coderd
+
+import (
+	"net/http"
+
+	"github.com/coder/coder/v2/coderd/workloadidentity"
+)
+
+// @Summary Get workload identity provider metadata
+// @ID get-workload-identity-provider-metadata
+// @Produce json
+// @Tags WorkloadIdentity
+// @Success 200 {object} codersdk.WorkloadIdentityProviderMetadata
+// @Router /workloadidentity/.well-known/openid-configuration [get]
+func (api *API) workloadIdentityProviderMetadata() http.HandlerFunc {
+	return workloadidentity.Metadata(api.AccessURL)
+}
+
+// @Summary Get workload identity JSON Web Key Set
+// @ID get-workload-identity-json-web-key-set
+// @Produce json
+// @Tags WorkloadIdentity
+// @Success 200 {object} codersdk.WorkloadIdentityJWKS
+// @Router /workloadidentity/jwks [get]
+func (api *API) workloadIdentityJWKS() http.HandlerFunc {
+	return workloadidentity.JWKS(api.WorkloadIdentityKeyCache)
+}
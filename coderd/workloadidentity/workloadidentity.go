@@ -0,0 +1,186 @@
+// Package workloadidentity issues OpenID Connect tokens that prove the identity
+// of a workspace to third parties, such as cloud providers and Vault, so
+// workspaces can access them without static credentials.
+package workloadidentity
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/cryptokeys"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/jwtutils"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// IssuerPath is the path of the issuer of workload identity tokens, relative to
+// the access URL.
+const IssuerPath = "/api/v2/workloadidentity"
+
+// Issuer returns the issuer of the workload identity tokens of the deployment.
+// It has no trailing slash, since relying parties append the well-known path
+// to it to discover the provider metadata.
+func Issuer(accessURL *url.URL) string {
+	return strings.TrimSuffix(accessURL.JoinPath(IssuerPath).String(), "/")
+}
+
+// Claims are the claims of a workload identity token.
+type Claims struct {
+	jwtutils.RegisteredClaims
+	OrganizationID   uuid.UUID `json:"organization_id"`
+	OrganizationName string    `json:"organization_name"`
+	TemplateID       uuid.UUID `json:"template_id"`
+	TemplateName     string    `json:"template_name"`
+	OwnerID          uuid.UUID `json:"owner_id"`
+	OwnerName        string    `json:"owner_name"`
+	WorkspaceID      uuid.UUID `json:"workspace_id"`
+	WorkspaceName    string    `json:"workspace_name"`
+	AgentID          uuid.UUID `json:"agent_id"`
+	AgentName        string    `json:"agent_name"`
+}
+
+func (c Claims) Validate(e jwt.Expected) error {
+	return c.RegisteredClaims.Validate(e)
+}
+
+// Subject returns the subject of the tokens issued to an agent. It is built
+// from IDs rather than names, since names can be changed and reused: a
+// workspace renamed to the name of a deleted one must not inherit its access.
+// It is ordered from the least to the most specific component, so relying
+// parties can match on prefixes, e.g. "organization:<id>:template:<id>:*".
+func Subject(workspace database.Workspace, agent database.WorkspaceAgent) string {
+	return fmt.Sprintf("organization:%s:template:%s:owner:%s:workspace:%s:agent:%s",
+		workspace.OrganizationID, workspace.TemplateID, workspace.OwnerID, workspace.ID, agent.ID)
+}
+
+// TokenParams are the parameters of a workload identity token.
+type TokenParams struct {
+	Workspace database.Workspace
+	Agent     database.WorkspaceAgent
+	// Audience is the intended recipient of the token. It defaults to the
+	// issuer.
+	Audience string
+	Now      time.Time
+}
+
+// Sign issues a workload identity token for the agent of a workspace. It
+// returns the token and when it expires.
+func Sign(ctx context.Context, keys jwtutils.SigningKeyProvider, accessURL *url.URL, params TokenParams) (string, time.Time, error) {
+	issuer := Issuer(accessURL)
+	audience := params.Audience
+	if audience == "" {
+		audience = issuer
+	}
+	expiresAt := params.Now.Add(cryptokeys.WorkloadIdentityTokenDuration)
+
+	claims := Claims{
+		RegisteredClaims: jwtutils.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    issuer,
+			Subject:   Subject(params.Workspace, params.Agent),
+			Audience:  jwt.Audience{audience},
+			Expiry:    jwt.NewNumericDate(expiresAt),
+			NotBefore: jwt.NewNumericDate(params.Now),
+			IssuedAt:  jwt.NewNumericDate(params.Now),
+		},
+		OrganizationID:   params.Workspace.OrganizationID,
+		OrganizationName: params.Workspace.OrganizationName,
+		TemplateID:       params.Workspace.TemplateID,
+		TemplateName:     params.Workspace.TemplateName,
+		OwnerID:          params.Workspace.OwnerID,
+		OwnerName:        params.Workspace.OwnerUsername,
+		WorkspaceID:      params.Workspace.ID,
+		WorkspaceName:    params.Workspace.Name,
+		AgentID:          params.Agent.ID,
+		AgentName:        params.Agent.Name,
+	}
+	token, err := jwtutils.Sign(ctx, keys, claims)
+	if err != nil {
+		return "", time.Time{}, xerrors.Errorf("sign token: %w", err)
+	}
+	return token, expiresAt, nil
+}
+
+// Metadata serves the OpenID provider metadata of the issuer, so relying
+// parties can discover the keys used to sign workload identity tokens.
+func Metadata(accessURL *url.URL) http.HandlerFunc {
+	issuer := Issuer(accessURL)
+	metadata := codersdk.WorkloadIdentityProviderMetadata{
+		Issuer:  issuer,
+		JWKSURI: issuer + "/jwks",
+		// Tokens are never issued through the authorization endpoint, but the
+		// field is required by the specification.
+		ResponseTypesSupported:           []string{"id_token"},
+		SubjectTypesSupported:            []string{"public"},
+		IDTokenSigningAlgValuesSupported: []string{string(jwtutils.AsymmetricSigningAlgo)},
+		ClaimsSupported: []string{
+			"iss", "sub", "aud", "exp", "nbf", "iat", "jti",
+			"organization_id", "organization_name",
+			"template_id", "template_name",
+			"owner_id", "owner_name",
+			"workspace_id", "workspace_name",
+			"agent_id", "agent_name",
+		},
+	}
+	return func(rw http.ResponseWriter, r *http.Request) {
+		httpapi.Write(r.Context(), rw, http.StatusOK, metadata)
+	}
+}
+
+// JWKS serves the public keys that workload identity tokens may be signed
+// with from the key cache, so requests of relying parties don't hit the
+// database. Keys that have not started yet are included, so relying parties
+// that cache the set can verify tokens as soon as the keys are rotated.
+func JWKS(keys cryptokeys.PublicSigningKeycache) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		verifying, err := keys.VerifyingKeys(ctx)
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+
+		jwks := codersdk.WorkloadIdentityJWKS{
+			Keys: []codersdk.WorkloadIdentityJWK{},
+		}
+		for _, key := range verifying {
+			id, privateKey, err := cryptokeys.ECDSAKey(key)
+			if err != nil {
+				httpapi.InternalServerError(rw, err)
+				return
+			}
+			publicKey, err := privateKey.PublicKey.ECDH()
+			if err != nil {
+				httpapi.InternalServerError(rw, err)
+				return
+			}
+			// The uncompressed form of the point is 0x04 || X || Y.
+			point := publicKey.Bytes()[1:]
+			size := len(point) / 2
+			jwks.Keys = append(jwks.Keys, codersdk.WorkloadIdentityJWK{
+				KeyType:   "EC",
+				KeyID:     id,
+				Use:       "sig",
+				Algorithm: string(jwtutils.AsymmetricSigningAlgo),
+				Curve:     privateKey.Curve.Params().Name,
+				X:         base64.RawURLEncoding.EncodeToString(point[:size]),
+				Y:         base64.RawURLEncoding.EncodeToString(point[size:]),
+			})
+		}
+
+		// New keys are inserted an hour before they are used for signing, so
+		// relying parties can safely cache the set for a few minutes.
+		rw.Header().Set("Cache-Control", "public, max-age=300")
+		httpapi.Write(ctx, rw, http.StatusOK, jwks)
+	}
+}
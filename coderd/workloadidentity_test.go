@@ -0,0 +1,101 @@
+package coderd_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/workloadidentity"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkloadIdentity(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	client, _, api := coderdtest.NewWithAPI(t, nil)
+
+	metadata, err := client.WorkloadIdentityProviderMetadata(ctx)
+	require.NoError(t, err)
+	issuer := client.URL.String() + "/api/v2/workloadidentity"
+	require.Equal(t, issuer, metadata.Issuer)
+	require.Equal(t, issuer+"/jwks", metadata.JWKSURI)
+	require.Equal(t, []string{"ES256"}, metadata.IDTokenSigningAlgValuesSupported)
+
+	workspace := database.Workspace{
+		ID:               uuid.New(),
+		Name:             "dev",
+		OrganizationID:   uuid.New(),
+		OrganizationName: "acme",
+		TemplateID:       uuid.New(),
+		TemplateName:     "docker",
+		OwnerID:          uuid.New(),
+		OwnerUsername:    "alice",
+	}
+	agent := database.WorkspaceAgent{
+		ID:   uuid.New(),
+		Name: "main",
+	}
+	token, expiresAt, err := workloadidentity.Sign(ctx, api.WorkloadIdentityKeyCache, client.URL, workloadidentity.TokenParams{
+		Workspace: workspace,
+		Agent:     agent,
+		Audience:  "sts.amazonaws.com",
+		Now:       time.Now(),
+	})
+	require.NoError(t, err)
+
+	// Relying parties verify tokens with the published keys, without access
+	// to anything else of the deployment.
+	jwks, err := client.WorkloadIdentityJWKS(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, jwks.Keys)
+
+	parsed, err := jwt.ParseSigned(token, []jose.SignatureAlgorithm{jose.ES256})
+	require.NoError(t, err)
+	require.Len(t, parsed.Headers, 1)
+	var publicKey *ecdsa.PublicKey
+	for _, key := range jwks.Keys {
+		if key.KeyID != parsed.Headers[0].KeyID {
+			continue
+		}
+		require.Equal(t, "EC", key.KeyType)
+		require.Equal(t, "P-256", key.Curve)
+		x, err := base64.RawURLEncoding.DecodeString(key.X)
+		require.NoError(t, err)
+		y, err := base64.RawURLEncoding.DecodeString(key.Y)
+		require.NoError(t, err)
+		publicKey = &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+	}
+	require.NotNil(t, publicKey, "signing key is not published")
+
+	var claims workloadidentity.Claims
+	err = parsed.Claims(publicKey, &claims)
+	require.NoError(t, err)
+	err = claims.Validate(jwt.Expected{
+		Issuer:      issuer,
+		AnyAudience: jwt.Audience{"sts.amazonaws.com"},
+		Time:        time.Now(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("organization:%s:template:%s:owner:%s:workspace:%s:agent:%s",
+		workspace.OrganizationID, workspace.TemplateID, workspace.OwnerID, workspace.ID, agent.ID), claims.Subject)
+	require.Equal(t, workspace.ID, claims.WorkspaceID)
+	require.Equal(t, "dev", claims.WorkspaceName)
+	require.Equal(t, agent.ID, claims.AgentID)
+	require.WithinDuration(t, expiresAt, claims.Expiry.Time(), time.Second)
+}
@@ -0,0 +1,241 @@
+package coderd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+)
+
+// @Summary List files in a workspace agent directory
+// @ID list-files-in-a-workspace-agent-directory
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param path query string true "Directory path, relative to the home directory"
+// @Success 200 {object} codersdk.WorkspaceAgentListFilesResponse
+// @Router /workspaceagents/{workspaceagent}/files/list [get]
+func (api *API) workspaceAgentListFiles(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	agentConn, release, ok := api.workspaceAgentFilesConn(rw, r)
+	if !ok {
+		return
+	}
+	defer release()
+
+	resp, err := agentConn.ListFiles(ctx, r.URL.Query().Get("path"))
+	if err != nil {
+		writeAgentFilesError(rw, r, "Internal error listing files.", err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// @Summary Get file information from a workspace agent
+// @ID get-file-information-from-a-workspace-agent
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param path query string true "File path, relative to the home directory"
+// @Success 200 {object} codersdk.WorkspaceAgentFileInfo
+// @Router /workspaceagents/{workspaceagent}/files/stat [get]
+func (api *API) workspaceAgentStatFile(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	agentConn, release, ok := api.workspaceAgentFilesConn(rw, r)
+	if !ok {
+		return
+	}
+	defer release()
+
+	resp, err := agentConn.StatFile(ctx, r.URL.Query().Get("path"))
+	if err != nil {
+		writeAgentFilesError(rw, r, "Internal error reading file information.", err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// @Summary Download a file from a workspace agent
+// @Description Range requests are supported to resume downloads.
+// @ID download-a-file-from-a-workspace-agent
+// @Security CoderSessionToken
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param path query string true "File path, relative to the home directory"
+// @Success 200
+// @Router /workspaceagents/{workspaceagent}/files/download [get]
+func (api *API) workspaceAgentDownloadFile(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	agentConn, release, ok := api.workspaceAgentFilesConn(rw, r)
+	if !ok {
+		return
+	}
+	defer release()
+
+	res, err := agentConn.DownloadFile(ctx, r.URL.Query().Get("path"), r.Header)
+	if err != nil {
+		writeAgentFilesError(rw, r, "Internal error downloading file.", err)
+		return
+	}
+	defer res.Body.Close()
+
+	for _, key := range []string{
+		"Accept-Ranges", "Content-Disposition", "Content-Length", "Content-Range",
+		"Content-Type", "ETag", "Last-Modified",
+	} {
+		if value := res.Header.Get(key); value != "" {
+			rw.Header().Set(key, value)
+		}
+	}
+	rw.WriteHeader(res.StatusCode)
+	_, _ = io.Copy(rw, res.Body)
+}
+
+// @Summary Upload a file to a workspace agent
+// @Description The file is replaced if it exists. The parent directory must exist.
+// @ID upload-a-file-to-a-workspace-agent
+// @Security CoderSessionToken
+// @Accept octet-stream
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param path query string true "File path, relative to the home directory"
+// @Param mode query string false "Octal file permissions, defaults to 644"
+// @Param file formData file true "File contents, sent as the raw request body"
+// @Success 201 {object} codersdk.WorkspaceAgentFileInfo
+// @Router /workspaceagents/{workspaceagent}/files/upload [post]
+func (api *API) workspaceAgentUploadFile(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	agentConn, release, ok := api.workspaceAgentFilesConn(rw, r)
+	if !ok {
+		return
+	}
+	defer release()
+
+	query := r.URL.Query()
+	resp, err := agentConn.UploadFile(ctx, query.Get("path"), query.Get("mode"), r.Body)
+	if err != nil {
+		writeAgentFilesError(rw, r, "Internal error uploading file.", err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusCreated, resp)
+}
+
+// @Summary Create a directory in a workspace agent
+// @ID create-a-directory-in-a-workspace-agent
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param path query string true "Directory path, relative to the home directory"
+// @Success 201 {object} codersdk.WorkspaceAgentFileInfo
+// @Router /workspaceagents/{workspaceagent}/files/mkdir [post]
+func (api *API) workspaceAgentMakeDirectory(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	agentConn, release, ok := api.workspaceAgentFilesConn(rw, r)
+	if !ok {
+		return
+	}
+	defer release()
+
+	resp, err := agentConn.MakeDirectory(ctx, r.URL.Query().Get("path"))
+	if err != nil {
+		writeAgentFilesError(rw, r, "Internal error creating directory.", err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusCreated, resp)
+}
+
+// @Summary Delete a file in a workspace agent
+// @ID delete-a-file-in-a-workspace-agent
+// @Security CoderSessionToken
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param path query string true "File path, relative to the home directory"
+// @Param recursive query bool false "Delete non-empty directories"
+// @Success 204
+// @Router /workspaceagents/{workspaceagent}/files [delete]
+func (api *API) workspaceAgentDeleteFile(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	agentConn, release, ok := api.workspaceAgentFilesConn(rw, r)
+	if !ok {
+		return
+	}
+	defer release()
+
+	query := r.URL.Query()
+	recursive, _ := strconv.ParseBool(query.Get("recursive"))
+	err := agentConn.DeleteFile(ctx, query.Get("path"), recursive)
+	if err != nil {
+		writeAgentFilesError(rw, r, "Internal error deleting file.", err)
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// workspaceAgentFilesConn authorizes file access to the workspace and returns
+// a connection to the agent. File access requires the same permission as SSH,
+// since both allow reading and writing any file the agent user can.
+func (api *API) workspaceAgentFilesConn(rw http.ResponseWriter, r *http.Request) (*workspacesdk.AgentConn, func(), bool) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+	workspaceAgent := httpmw.WorkspaceAgentParam(r)
+
+	if !api.Authorize(r, policy.ActionSSH, workspace) {
+		httpapi.ResourceNotFound(rw)
+		return nil, nil, false
+	}
+
+	apiAgent, err := db2sdk.WorkspaceAgent(
+		api.DERPMap(), *api.TailnetCoordinator.Load(), workspaceAgent, nil, nil, nil, api.AgentInactiveDisconnectTimeout,
+		api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
+	)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error reading workspace agent.",
+			Detail:  err.Error(),
+		})
+		return nil, nil, false
+	}
+	if apiAgent.Status != codersdk.WorkspaceAgentConnected {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Agent state is %q, it must be in the %q state.", apiAgent.Status, codersdk.WorkspaceAgentConnected),
+		})
+		return nil, nil, false
+	}
+
+	agentConn, release, err := api.agentProvider.AgentConn(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error dialing workspace agent.",
+			Detail:  err.Error(),
+		})
+		return nil, nil, false
+	}
+	return agentConn, release, true
+}
+
+// writeAgentFilesError relays errors returned by the agent, such as a missing
+// file or file transfer being blocked, with their original status code.
+func writeAgentFilesError(rw http.ResponseWriter, r *http.Request, message string, err error) {
+	var sdkErr *codersdk.Error
+	if xerrors.As(err, &sdkErr) {
+		httpapi.Write(r.Context(), rw, sdkErr.StatusCode(), sdkErr.Response)
+		return
+	}
+	httpapi.Write(r.Context(), rw, http.StatusInternalServerError, codersdk.Response{
+		Message: message,
+		Detail:  err.Error(),
+	})
+}
@@ -0,0 +1,109 @@
+package coderd_test
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent"
+	"github.com/coder/coder/v2/agent/agenttest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceAgentFiles(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T, opts ...func(*agent.Options)) (*codersdk.Client, codersdk.CreateFirstUserResponse, uuid.UUID) {
+		t.Helper()
+
+		client, db := coderdtest.NewWithDatabase(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: user.OrganizationID,
+			OwnerID:        user.UserID,
+		}).WithAgent().Do()
+		_ = agenttest.New(t, client.URL, r.AgentToken, opts...)
+		resources := coderdtest.AwaitWorkspaceAgents(t, client, r.Workspace.ID)
+		return client, user, resources[0].Agents[0].ID
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, agentID := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		dir := t.TempDir()
+
+		info, err := client.WorkspaceAgentUploadFile(ctx, agentID, filepath.Join(dir, "hello.txt"), 0o755, strings.NewReader("hello world"))
+		require.NoError(t, err)
+		require.Equal(t, "hello.txt", info.Name)
+		require.Equal(t, "-rwxr-xr-x", info.Mode)
+		content, err := os.ReadFile(filepath.Join(dir, "hello.txt"))
+		require.NoError(t, err)
+		require.Equal(t, "hello world", string(content))
+
+		_, err = client.WorkspaceAgentMakeDirectory(ctx, agentID, filepath.Join(dir, "nested", "dir"))
+		require.NoError(t, err)
+
+		list, err := client.WorkspaceAgentListFiles(ctx, agentID, dir)
+		require.NoError(t, err)
+		require.Len(t, list.Files, 2)
+		require.Equal(t, "hello.txt", list.Files[0].Name)
+		require.Equal(t, "nested", list.Files[1].Name)
+
+		rc, err := client.WorkspaceAgentDownloadFile(ctx, agentID, filepath.Join(dir, "hello.txt"))
+		require.NoError(t, err)
+		content, err = io.ReadAll(rc)
+		_ = rc.Close()
+		require.NoError(t, err)
+		require.Equal(t, "hello world", string(content))
+
+		// Errors from the agent keep their status code.
+		_, err = client.WorkspaceAgentStatFile(ctx, agentID, filepath.Join(dir, "missing"))
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+		err = client.WorkspaceAgentDeleteFile(ctx, agentID, filepath.Join(dir, "nested"), true)
+		require.NoError(t, err)
+		_, err = os.Stat(filepath.Join(dir, "nested"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("Blocked", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, agentID := setup(t, func(o *agent.Options) {
+			o.BlockFileTransfer = true
+		})
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.WorkspaceAgentListFiles(ctx, agentID, t.TempDir())
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("OtherUser", func(t *testing.T) {
+		t.Parallel()
+
+		client, user, agentID := setup(t)
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := member.WorkspaceAgentListFiles(ctx, agentID, t.TempDir())
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
@@ -1192,6 +1192,84 @@ func TestWorkspaceAgentPostLogSource(t *testing.T) {
 	})
 }
 
+func TestWorkspaceAgentProcessGroups(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	user := coderdtest.CreateFirstUser(t, client)
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: user.OrganizationID,
+		OwnerID:        user.UserID,
+	}).WithAgent().Do()
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(r.AgentToken)
+
+	ctx := testutil.Context(t, testutil.WaitMedium)
+	conn, err := agentClient.ConnectRPC(ctx)
+	require.NoError(t, err)
+	defer func() {
+		cErr := conn.Close()
+		require.NoError(t, cErr)
+	}()
+	aAPI := agentproto.NewDRPCAgentClient(conn)
+
+	workspace, err := client.Workspace(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	agentID := workspace.LatestBuild.Resources[0].Agents[0].ID
+
+	groups, err := client.WorkspaceAgentProcessGroups(ctx, agentID)
+	require.NoError(t, err)
+	require.Empty(t, groups)
+
+	report := func(sessionsMemory int64) {
+		_, err := aAPI.UpdateStats(ctx, &agentproto.UpdateStatsRequest{
+			Stats: &agentproto.Stats{
+				ConnectionsByProto: map[string]int64{},
+				ProcessGroups: []*agentproto.Stats_ProcessGroup{
+					{
+						Name:          "sessions",
+						CpuUsageUsec:  1000,
+						CpuCores:      0.5,
+						CpuLimitCores: 2,
+						MemoryBytes:   sessionsMemory,
+					},
+					{
+						Name:             "scripts",
+						MemoryBytes:      2048,
+						MemoryLimitBytes: 4096,
+						OomKills:         1,
+					},
+				},
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	report(1024)
+	groups, err = client.WorkspaceAgentProcessGroups(ctx, agentID)
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	// Groups are sorted by name.
+	require.Equal(t, "scripts", groups[0].Name)
+	require.EqualValues(t, 2048, groups[0].MemoryBytes)
+	require.EqualValues(t, 4096, groups[0].MemoryLimitBytes)
+	require.EqualValues(t, 1, groups[0].OOMKills)
+	require.Equal(t, "sessions", groups[1].Name)
+	require.EqualValues(t, 1000, groups[1].CPUUsageUsec)
+	require.Equal(t, 0.5, groups[1].CPUCores)
+	require.Equal(t, 2.0, groups[1].CPULimitCores)
+	require.EqualValues(t, 1024, groups[1].MemoryBytes)
+	require.NotZero(t, groups[1].UpdatedAt)
+
+	// Later reports replace the usage of a group.
+	report(4096)
+	groups, err = client.WorkspaceAgentProcessGroups(ctx, agentID)
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	require.EqualValues(t, 4096, groups[1].MemoryBytes)
+}
+
 func TestWorkspaceAgent_LifecycleState(t *testing.T) {
 	t.Parallel()
 
@@ -2041,7 +2119,7 @@ func requireGetManifest(ctx context.Context, t testing.TB, aAPI agentproto.DRPCA
 }
 
 func postStartup(ctx context.Context, t testing.TB, client agent.Client, startup *agentproto.Startup) error {
-	aAPI, _, err := client.ConnectRPC23(ctx)
+	aAPI, _, err := client.ConnectRPC24(ctx)
 	require.NoError(t, err)
 	defer func() {
 		cErr := aAPI.DRPCConn().Close()
@@ -161,9 +161,11 @@ func (api *API) workspaceAgentRPC(rw http.ResponseWriter, r *http.Request) {
 		DerpMapUpdateFrequency:    api.Options.DERPMapUpdateFrequency,
 		ExternalAuthConfigs:       api.ExternalAuthConfigs,
 		Experiments:               api.Experiments,
+		WorkloadIdentityKeyCache:  api.WorkloadIdentityKeyCache,
 
 		// Optional:
 		UpdateAgentMetricsFn: api.UpdateAgentMetrics,
+		StartupCompletedFn:   api.BuildTraceExporter.AgentStartupCompleted,
 	})
 
 	streamID := tailnet.StreamID{
@@ -202,6 +204,13 @@ func (api *API) handleNetworkTelemetry(batch []*tailnetproto.TelemetryEvent) {
 	api.Telemetry.Report(&telemetry.Snapshot{
 		NetworkEvents: telemetryEvents,
 	})
+
+	// Network reports are stored regardless of whether telemetry is enabled,
+	// as they're used for network insights.
+	err := api.storeNetworkReports(api.ctx, telemetryEvents)
+	if err != nil && !database.IsQueryCanceledError(err) {
+		api.Logger.Warn(api.ctx, "failed to store network reports", slog.Error(err))
+	}
 }
 
 type yamuxPingerCloser struct {
@@ -0,0 +1,288 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/wsbuilder"
+	"github.com/coder/coder/v2/coderd/wspubsub"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get workspace build approval
+// @ID get-workspace-build-approval
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Builds
+// @Param workspacebuild path string true "Workspace build ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceBuildApproval
+// @Router /workspacebuilds/{workspacebuild}/approval [get]
+func (api *API) workspaceBuildApproval(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceBuild := httpmw.WorkspaceBuildParam(r)
+
+	approval, err := api.Database.GetWorkspaceBuildApprovalByBuildID(ctx, workspaceBuild.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "This workspace build does not require approval.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace build approval.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.WorkspaceBuildApproval(approval, workspaceBuild.WorkspaceID))
+}
+
+// @Summary Get pending build approvals of template
+// @ID get-pending-build-approvals-of-template
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {array} codersdk.WorkspaceBuildApproval
+// @Router /templates/{template}/build-approvals [get]
+func (api *API) templateBuildApprovals(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	canReview, err := api.canReviewBuilds(ctx, r, template, httpmw.APIKey(r).UserID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error verifying permission to review builds.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if !canReview {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	rows, err := api.Database.GetPendingWorkspaceBuildApprovalsByTemplateID(ctx, template.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching build approvals.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	approvals := make([]codersdk.WorkspaceBuildApproval, 0, len(rows))
+	for _, row := range rows {
+		approvals = append(approvals, db2sdk.WorkspaceBuildApproval(row.WorkspaceBuildApproval, row.WorkspaceID))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, approvals)
+}
+
+// @Summary Review build approval of template
+// @Description Approved builds are queued for provisioners, rejected builds
+// @Description fail with the reason given by the reviewer.
+// @ID review-build-approval-of-template
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param workspacebuild path string true "Workspace build ID" format(uuid)
+// @Param request body codersdk.ReviewWorkspaceBuildApprovalRequest true "Review request"
+// @Success 200 {object} codersdk.WorkspaceBuildApproval
+// @Router /templates/{template}/build-approvals/{workspacebuild} [post]
+func (api *API) postTemplateBuildApproval(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+	apiKey := httpmw.APIKey(r)
+	buildID, ok := httpmw.ParseUUIDParam(rw, r, "workspacebuild")
+	if !ok {
+		return
+	}
+
+	var req codersdk.ReviewWorkspaceBuildApprovalRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	canReview, err := api.canReviewBuilds(ctx, r, template, apiKey.UserID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error verifying permission to review builds.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if !canReview {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Only template admins and members of the template's approver group can review builds.",
+		})
+		return
+	}
+
+	// nolint:gocritic // Members of the approver group may not be able to read
+	// the workspace. They were authorized to review builds above.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	workspaceBuild, err := api.Database.GetWorkspaceBuildByID(sysCtx, buildID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace build.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	workspace, err := api.Database.GetWorkspaceByID(sysCtx, workspaceBuild.WorkspaceID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if workspace.TemplateID != template.ID {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	// Approvals require a second person, even when the initiator of the build
+	// may review the builds of others. Rejecting is allowed, since it's no
+	// different from canceling the build.
+	if req.Approved && workspaceBuild.InitiatorID == apiKey.UserID {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "You can't approve a build you initiated.",
+		})
+		return
+	}
+
+	status := database.WorkspaceBuildApprovalStatusRejected
+	if req.Approved {
+		status = database.WorkspaceBuildApprovalStatusApproved
+	}
+
+	var (
+		approval database.WorkspaceBuildApproval
+		job      database.ProvisionerJob
+	)
+	err = api.Database.InTx(func(tx database.Store) error {
+		job, err = tx.GetProvisionerJobByID(sysCtx, workspaceBuild.JobID)
+		if err != nil {
+			return xerrors.Errorf("get provisioner job: %w", err)
+		}
+		if job.CompletedAt.Valid {
+			return sql.ErrNoRows
+		}
+
+		now := dbtime.Now()
+		approval, err = tx.UpdateWorkspaceBuildApprovalByBuildID(sysCtx, database.UpdateWorkspaceBuildApprovalByBuildIDParams{
+			WorkspaceBuildID: workspaceBuild.ID,
+			UpdatedAt:        now,
+			Status:           status,
+			ReviewerID:       uuid.NullUUID{UUID: apiKey.UserID, Valid: true},
+			Reason:           req.Reason,
+		})
+		if err != nil {
+			return xerrors.Errorf("update workspace build approval: %w", err)
+		}
+
+		if !req.Approved {
+			// Rejected builds fail with the reason given by the reviewer, so
+			// the owner can see why the build didn't run.
+			jobErr := "The build was rejected."
+			if req.Reason != "" {
+				jobErr = fmt.Sprintf("The build was rejected: %s", req.Reason)
+			}
+			err = tx.UpdateProvisionerJobWithCompleteByID(sysCtx, database.UpdateProvisionerJobWithCompleteByIDParams{
+				ID:          job.ID,
+				UpdatedAt:   now,
+				CompletedAt: sql.NullTime{Time: now, Valid: true},
+				Error:       sql.NullString{String: jobErr, Valid: true},
+			})
+			if err != nil {
+				return xerrors.Errorf("fail provisioner job: %w", err)
+			}
+		}
+		return nil
+	}, nil)
+	if xerrors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "This workspace build is not pending approval.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error reviewing workspace build.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	if req.Approved {
+		if err := provisionerjobs.PostJob(api.Pubsub, job); err != nil {
+			// Client probably doesn't care about this error, so just log it.
+			api.Logger.Error(ctx, "failed to post provisioner job to pubsub", slog.Error(err))
+		}
+	}
+
+	api.publishWorkspaceUpdate(ctx, workspace.OwnerID, wspubsub.WorkspaceEvent{
+		Kind:        wspubsub.WorkspaceEventKindStateChange,
+		WorkspaceID: workspace.ID,
+	})
+
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.WorkspaceBuildApproval(approval, workspace.ID))
+}
+
+// canReviewBuilds returns true if the user may approve or reject builds of the
+// template. Template admins can always review builds. It doesn't consider the
+// build being reviewed: users may never approve builds they initiated.
+func (api *API) canReviewBuilds(ctx context.Context, r *http.Request, template database.Template, userID uuid.UUID) (bool, error) {
+	if api.Authorize(r, policy.ActionUpdate, template) {
+		return true, nil
+	}
+	if !template.BuildApprovalGroupID.Valid {
+		return false, nil
+	}
+
+	// nolint:gocritic // Users may not be able to read the members of the
+	// approver group they belong to.
+	members, err := api.Database.GetGroupMembersByGroupID(dbauthz.AsSystemRestricted(ctx), template.BuildApprovalGroupID.UUID)
+	if err != nil {
+		return false, xerrors.Errorf("get approver group members: %w", err)
+	}
+	for _, member := range members {
+		if member.UserID == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// notifyBuildApprovalRequested notifies the approvers of the template that a
+// build is waiting for their approval.
+func (api *API) notifyBuildApprovalRequested(
+	ctx context.Context,
+	initiatorID uuid.UUID,
+	workspace database.Workspace,
+	workspaceBuild database.WorkspaceBuild,
+) {
+	wsbuilder.NotifyApprovalRequested(ctx, api.Logger, api.Database, api.NotificationsEnqueuer, "api-workspace-builds", initiatorID, workspace, workspaceBuild)
+}
@@ -0,0 +1,159 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/notifications/notificationstest"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceBuildApprovals(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ApproveAndReject", func(t *testing.T) {
+		t.Parallel()
+
+		notify := &notificationstest.FakeEnqueuer{}
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true, NotificationsEnqueuer: notify})
+		owner := coderdtest.CreateFirstUser(t, client)
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		template, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			RequireBuildApproval: ptr.Ref(true),
+		})
+		require.NoError(t, err)
+		require.True(t, template.RequireBuildApproval)
+
+		// The first build of the workspace waits for approval.
+		workspace := coderdtest.CreateWorkspace(t, memberClient, template.ID)
+		approval, err := memberClient.WorkspaceBuildApproval(ctx, workspace.LatestBuild.ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceBuildApprovalStatusPending, approval.Status)
+		require.Equal(t, workspace.ID, approval.WorkspaceID)
+		require.NotNil(t, approval.ExpiresAt)
+
+		sent := notify.Sent(notificationstest.WithTemplateID(notifications.TemplateWorkspaceBuildApprovalRequested))
+		require.Len(t, sent, 1)
+		require.Equal(t, owner.UserID, sent[0].UserID)
+		require.Equal(t, member.Username, sent[0].Labels["initiator"])
+
+		// Members can't review their own builds.
+		_, err = memberClient.TemplateBuildApprovals(ctx, template.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+		_, err = memberClient.ReviewTemplateBuildApproval(ctx, template.ID, workspace.LatestBuild.ID, codersdk.ReviewWorkspaceBuildApprovalRequest{Approved: true})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		pending, err := client.TemplateBuildApprovals(ctx, template.ID)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		require.Equal(t, workspace.LatestBuild.ID, pending[0].WorkspaceBuildID)
+
+		approval, err = client.ReviewTemplateBuildApproval(ctx, template.ID, workspace.LatestBuild.ID, codersdk.ReviewWorkspaceBuildApprovalRequest{Approved: true})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceBuildApprovalStatusApproved, approval.Status)
+		require.NotNil(t, approval.ReviewerID)
+		require.Equal(t, owner.UserID, *approval.ReviewerID)
+		build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, memberClient, workspace.LatestBuild.ID)
+		require.Equal(t, codersdk.ProvisionerJobSucceeded, build.Job.Status)
+
+		// A build can only be reviewed once.
+		_, err = client.ReviewTemplateBuildApproval(ctx, template.ID, workspace.LatestBuild.ID, codersdk.ReviewWorkspaceBuildApprovalRequest{Approved: false})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		// Stopping doesn't require approval.
+		coderdtest.MustTransitionWorkspace(t, memberClient, workspace.ID, database.WorkspaceTransitionStart, database.WorkspaceTransitionStop)
+
+		// Rejected builds fail with the reason given by the reviewer.
+		build = coderdtest.CreateWorkspaceBuild(t, memberClient, workspace, database.WorkspaceTransitionStart)
+		approval, err = client.ReviewTemplateBuildApproval(ctx, template.ID, build.ID, codersdk.ReviewWorkspaceBuildApprovalRequest{
+			Approved: false,
+			Reason:   "Not during the change freeze.",
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceBuildApprovalStatusRejected, approval.Status)
+		require.Equal(t, "Not during the change freeze.", approval.Reason)
+		build, err = memberClient.WorkspaceBuild(ctx, build.ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.ProvisionerJobFailed, build.Job.Status)
+		require.Equal(t, "The build was rejected: Not during the change freeze.", build.Job.Error)
+	})
+
+	t.Run("InitiatorCannotApprove", func(t *testing.T) {
+		t.Parallel()
+
+		client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		approverClient, approver := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		group := dbgen.Group(t, db, database.Group{OrganizationID: owner.OrganizationID})
+		dbgen.GroupMember(t, db, database.GroupMemberTable{UserID: approver.ID, GroupID: group.ID})
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			RequireBuildApproval: ptr.Ref(true),
+			BuildApprovalGroupID: ptr.Ref(group.ID),
+		})
+		require.NoError(t, err)
+
+		// Members of the approver group need the approval of someone else for
+		// their own builds.
+		workspace := coderdtest.CreateWorkspace(t, approverClient, template.ID)
+		pending, err := approverClient.TemplateBuildApprovals(ctx, template.ID)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		_, err = approverClient.ReviewTemplateBuildApproval(ctx, template.ID, workspace.LatestBuild.ID, codersdk.ReviewWorkspaceBuildApprovalRequest{Approved: true})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		approval, err := client.ReviewTemplateBuildApproval(ctx, template.ID, workspace.LatestBuild.ID, codersdk.ReviewWorkspaceBuildApprovalRequest{Approved: true})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceBuildApprovalStatusApproved, approval.Status)
+	})
+
+	t.Run("TemplateAdminBypassesApproval", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			RequireBuildApproval: ptr.Ref(true),
+		})
+		require.NoError(t, err)
+
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+		_, err = client.WorkspaceBuildApproval(ctx, workspace.LatestBuild.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
@@ -354,6 +354,19 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 			return nil
 		}
 
+		if createBuild.ActiveVersion {
+			if createBuild.TemplateVersionID != uuid.Nil {
+				httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+					Message: "ActiveVersion cannot be set alongside TemplateVersionID.",
+				})
+				return nil
+			}
+			// Updates to the active version build the candidate version
+			// instead if the workspace is included in the rollout in progress
+			// of the template. Builds of an explicit version, even the active
+			// one, always build that version.
+			builder = builder.ActiveVersion()
+		}
 		if createBuild.TemplateVersionID != uuid.Nil {
 			builder = builder.VersionID(createBuild.TemplateVersionID)
 		}
@@ -412,7 +425,9 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if provisionerJob != nil {
+	if builder.ApprovalRequired() {
+		api.notifyBuildApprovalRequested(ctx, apiKey.UserID, workspace, *workspaceBuild)
+	} else if provisionerJob != nil {
 		if err := provisionerjobs.PostJob(api.Pubsub, *provisionerJob); err != nil {
 			// Client probably doesn't care about this error, so just log it.
 			api.Logger.Error(ctx, "failed to post provisioner job to pubsub", slog.Error(err))
@@ -754,6 +769,32 @@ func (api *API) workspaceBuildTimings(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusOK, timings)
 }
 
+// @Summary Get workspace build resource changes
+// @ID get-workspace-build-resource-changes
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Builds
+// @Param workspacebuild path string true "Workspace build ID" format(uuid)
+// @Success 200 {array} codersdk.ProvisionerJobResourceChange
+// @Router /workspacebuilds/{workspacebuild}/resource-changes [get]
+func (api *API) workspaceBuildResourceChanges(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx            = r.Context()
+		workspaceBuild = httpmw.WorkspaceBuildParam(r)
+	)
+
+	job, err := api.Database.GetProvisionerJobByID(ctx, workspaceBuild.JobID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner job.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	api.provisionerJobResourceChanges(rw, r, job)
+}
+
 type workspaceBuildsData struct {
 	jobs               []database.GetProvisionerJobsByIDsWithQueuePositionRow
 	templateVersions   []database.TemplateVersion
@@ -1087,6 +1128,7 @@ func convertWorkspaceResource(resource database.WorkspaceResource, agents []code
 		Agents:     agents,
 		Metadata:   convertedMetadata,
 		DailyCost:  resource.DailyCost,
+		HourlyCost: resource.HourlyCost,
 	}
 }
 
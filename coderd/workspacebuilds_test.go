@@ -692,6 +692,63 @@ func assertWorkspaceResource(t *testing.T, actual codersdk.WorkspaceResource, na
 	assert.Len(t, actual.Agents, numAgents)
 }
 
+func TestWorkspaceBuildResourceChanges(t *testing.T) {
+	t.Parallel()
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionPlan: []*proto.Response{{
+			Type: &proto.Response_Plan{
+				Plan: &proto.PlanComplete{
+					ResourceChanges: []*proto.ResourceChange{{
+						Address: "docker_container.workspace[0]",
+						Type:    "docker_container",
+						Name:    "workspace",
+						Action:  "create",
+					}, {
+						Address: "coder_agent.main",
+						Type:    "coder_agent",
+						Name:    "main",
+						Action:  "update",
+						Attributes: []*proto.AttributeChange{{
+							Path:      "env",
+							Sensitive: true,
+						}},
+					}},
+				},
+			},
+		}},
+		ProvisionApply: echo.ApplyComplete,
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+	build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	changes, err := client.WorkspaceBuildResourceChanges(ctx, build.ID)
+	require.NoError(t, err)
+	require.Equal(t, []codersdk.ProvisionerJobResourceChange{{
+		JobID:        build.Job.ID,
+		Address:      "coder_agent.main",
+		ResourceType: "coder_agent",
+		ResourceName: "main",
+		Action:       codersdk.ProvisionerJobResourceChangeActionUpdate,
+		Attributes: []codersdk.ProvisionerJobResourceAttributeChange{{
+			Path:      "env",
+			Sensitive: true,
+		}},
+	}, {
+		JobID:        build.Job.ID,
+		Address:      "docker_container.workspace[0]",
+		ResourceType: "docker_container",
+		ResourceName: "workspace",
+		Action:       codersdk.ProvisionerJobResourceChangeActionCreate,
+		Attributes:   []codersdk.ProvisionerJobResourceAttributeChange{},
+	}}, changes)
+}
+
 func TestWorkspaceBuildLogs(t *testing.T) {
 	t.Parallel()
 	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
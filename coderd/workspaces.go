@@ -626,6 +626,7 @@ func createWorkspace(
 		provisionerJob     *database.ProvisionerJob
 		workspaceBuild     *database.WorkspaceBuild
 		provisionerDaemons []database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow
+		approvalRequired   bool
 	)
 	err = api.Database.InTx(func(db database.Store) error {
 		now := dbtime.Now()
@@ -663,6 +664,10 @@ func createWorkspace(
 			Initiator(initiatorID).
 			ActiveVersion().
 			RichParameterValues(req.RichParameterValues)
+		// Workspaces created from the template build the candidate version
+		// instead of the active version if they're included in the rollout in
+		// progress of the template. Workspaces created from an explicit
+		// version, even the active one, always build that version.
 		if req.TemplateVersionID != uuid.Nil {
 			builder = builder.VersionID(req.TemplateVersionID)
 		}
@@ -675,6 +680,7 @@ func createWorkspace(
 			},
 			audit.WorkspaceBuildBaggageFromRequest(r),
 		)
+		approvalRequired = builder.ApprovalRequired()
 		return err
 	}, nil)
 	var bldErr wsbuilder.BuildError
@@ -692,8 +698,9 @@ func createWorkspace(
 		})
 		return
 	}
-	err = provisionerjobs.PostJob(api.Pubsub, *provisionerJob)
-	if err != nil {
+	if approvalRequired {
+		api.notifyBuildApprovalRequested(ctx, initiatorID, workspace, *workspaceBuild)
+	} else if err := provisionerjobs.PostJob(api.Pubsub, *provisionerJob); err != nil {
 		// Client probably doesn't care about this error, so just log it.
 		api.Logger.Error(ctx, "failed to post provisioner job to pubsub", slog.Error(err))
 	}
@@ -0,0 +1,190 @@
+package coderd
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/schedule/cron"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// minScheduledActionInterval is the minimum time between two runs of a
+// scheduled action. Restarting or updating a workspace takes a while, so
+// running actions more often than this isn't useful.
+const minScheduledActionInterval = time.Hour
+
+// @Summary Get workspace scheduled actions
+// @ID get-workspace-scheduled-actions
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {array} codersdk.WorkspaceScheduledAction
+// @Router /workspaces/{workspace}/scheduled-actions [get]
+func (api *API) workspaceScheduledActions(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	actions, err := api.Database.GetWorkspaceScheduledActionsByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace scheduled actions.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.List(actions, db2sdk.WorkspaceScheduledAction))
+}
+
+// @Summary Create workspace scheduled action
+// @ID create-workspace-scheduled-action
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.CreateWorkspaceScheduledActionRequest true "Create scheduled action request"
+// @Success 201 {object} codersdk.WorkspaceScheduledAction
+// @Router /workspaces/{workspace}/scheduled-actions [post]
+func (api *API) postWorkspaceScheduledAction(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	var req codersdk.CreateWorkspaceScheduledActionRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	actionType := database.WorkspaceScheduledActionType(req.Action)
+	if !actionType.Valid() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid scheduled action.",
+			Validations: []codersdk.ValidationError{{Field: "action", Detail: "Action must be one of start, stop, restart or update."}},
+		})
+		return
+	}
+
+	sched, err := cron.Weekly(req.Schedule)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid schedule.",
+			Validations: []codersdk.ValidationError{{Field: "schedule", Detail: err.Error()}},
+		})
+		return
+	}
+	if sched.Min() < minScheduledActionInterval {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid schedule.",
+			Validations: []codersdk.ValidationError{{Field: "schedule", Detail: "Scheduled actions can run at most once an hour."}},
+		})
+		return
+	}
+
+	templateSchedule, err := (*api.TemplateScheduleStore.Load()).Get(ctx, api.Database, workspace.TemplateID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error getting template schedule options.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	switch actionType {
+	case database.WorkspaceScheduledActionTypeStart, database.WorkspaceScheduledActionTypeRestart:
+		if !templateSchedule.UserAutostartEnabled {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message:     "Autostart is not allowed for workspaces using this template.",
+				Validations: []codersdk.ValidationError{{Field: "action", Detail: "Autostart is not allowed for workspaces using this template."}},
+			})
+			return
+		}
+	case database.WorkspaceScheduledActionTypeStop:
+		if !templateSchedule.UserAutostopEnabled {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message:     "Autostop is not allowed for workspaces using this template.",
+				Validations: []codersdk.ValidationError{{Field: "action", Detail: "Autostop is not allowed for workspaces using this template."}},
+			})
+			return
+		}
+	}
+
+	now := dbtime.Now()
+	action, err := api.Database.InsertWorkspaceScheduledAction(ctx, database.InsertWorkspaceScheduledActionParams{
+		ID:          uuid.New(),
+		WorkspaceID: workspace.ID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Action:      actionType,
+		Schedule:    req.Schedule,
+		NextRunAt:   dbtime.Time(sched.Next(now).UTC()),
+	})
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating workspace scheduled action.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, db2sdk.WorkspaceScheduledAction(action))
+}
+
+// @Summary Delete workspace scheduled action
+// @ID delete-workspace-scheduled-action
+// @Security CoderSessionToken
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param scheduledaction path string true "Scheduled action ID" format(uuid)
+// @Success 204
+// @Router /workspaces/{workspace}/scheduled-actions/{scheduledaction} [delete]
+func (api *API) deleteWorkspaceScheduledAction(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+	actionID, ok := httpmw.ParseUUIDParam(rw, r, "scheduledaction")
+	if !ok {
+		return
+	}
+
+	action, err := api.Database.GetWorkspaceScheduledActionByID(ctx, actionID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace scheduled action.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if action.WorkspaceID != workspace.ID {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	err = api.Database.DeleteWorkspaceScheduledActionByID(ctx, action.ID)
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting workspace scheduled action.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
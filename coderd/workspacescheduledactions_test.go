@@ -0,0 +1,84 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceScheduledActions(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, memberClient, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, memberClient, workspace.LatestBuild.ID)
+	otherWorkspace := coderdtest.CreateWorkspace(t, client, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, otherWorkspace.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	actions, err := memberClient.WorkspaceScheduledActions(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Empty(t, actions)
+
+	action, err := memberClient.CreateWorkspaceScheduledAction(ctx, workspace.ID, codersdk.CreateWorkspaceScheduledActionRequest{
+		Action:   codersdk.WorkspaceScheduledActionTypeRestart,
+		Schedule: "CRON_TZ=Europe/Dublin 0 6 * * 1",
+	})
+	require.NoError(t, err)
+	require.Equal(t, workspace.ID, action.WorkspaceID)
+	require.Equal(t, codersdk.WorkspaceScheduledActionTypeRestart, action.Action)
+	require.Equal(t, "CRON_TZ=Europe/Dublin 0 6 * * 1", action.Schedule)
+	require.True(t, action.NextRunAt.After(action.CreatedAt))
+	require.Nil(t, action.LastRunAt)
+
+	actions, err = memberClient.WorkspaceScheduledActions(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Len(t, actions, 1)
+	require.Equal(t, action.ID, actions[0].ID)
+
+	var apiErr *codersdk.Error
+	for _, req := range []codersdk.CreateWorkspaceScheduledActionRequest{
+		{Action: "rebuild", Schedule: "CRON_TZ=UTC 0 6 * * *"},
+		{Action: codersdk.WorkspaceScheduledActionTypeStop, Schedule: "not a schedule"},
+		// Actions can run at most once an hour.
+		{Action: codersdk.WorkspaceScheduledActionTypeStop, Schedule: "CRON_TZ=UTC */30 * * * *"},
+	} {
+		_, err = memberClient.CreateWorkspaceScheduledAction(ctx, workspace.ID, req)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	}
+
+	// Members can't manage the scheduled actions of other users' workspaces.
+	_, err = memberClient.CreateWorkspaceScheduledAction(ctx, otherWorkspace.ID, codersdk.CreateWorkspaceScheduledActionRequest{
+		Action:   codersdk.WorkspaceScheduledActionTypeStop,
+		Schedule: "CRON_TZ=UTC 0 18 * * *",
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+	// Actions can only be deleted through the workspace they belong to.
+	err = client.DeleteWorkspaceScheduledAction(ctx, otherWorkspace.ID, action.ID)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	err = memberClient.DeleteWorkspaceScheduledAction(ctx, workspace.ID, uuid.New())
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+	err = memberClient.DeleteWorkspaceScheduledAction(ctx, workspace.ID, action.ID)
+	require.NoError(t, err)
+	actions, err = memberClient.WorkspaceScheduledActions(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Empty(t, actions)
+}
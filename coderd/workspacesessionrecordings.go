@@ -0,0 +1,139 @@
+package coderd
+
+import (
+	"net/http"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// sessionRecordingChunkPageSize is the number of chunks of a recording that
+// are held in memory while it is streamed.
+const sessionRecordingChunkPageSize = 64
+
+// @Summary Get workspace session recordings
+// @Description Session recordings are only visible to users that can read
+// @Description the audit log.
+// @ID get-workspace-session-recordings
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {array} codersdk.WorkspaceSessionRecording
+// @Router /workspaces/{workspace}/session-recordings [get]
+func (api *API) workspaceSessionRecordings(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	recordings, err := api.Database.GetWorkspaceSessionRecordingsByWorkspaceID(ctx, workspace.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace session recordings.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.List(recordings, db2sdk.WorkspaceSessionRecording))
+}
+
+// @Summary Get workspace session recording
+// @Description Returns the recording in the asciicast v2 format. Opening a
+// @Description recording is recorded in the audit log.
+// @ID get-workspace-session-recording
+// @Security CoderSessionToken
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param recording path string true "Recording ID" format(uuid)
+// @Success 200
+// @Router /workspaces/{workspace}/session-recordings/{recording} [get]
+func (api *API) workspaceSessionRecording(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+	recordingID, ok := httpmw.ParseUUIDParam(rw, r, "recording")
+	if !ok {
+		return
+	}
+
+	recording, err := api.Database.GetWorkspaceSessionRecordingByID(ctx, recordingID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace session recording.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if recording.WorkspaceID != workspace.ID {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	auditor := api.Auditor.Load()
+	aReq, commitAudit := audit.InitRequest[database.WorkspaceSessionRecording](rw, &audit.RequestParams{
+		Audit:          *auditor,
+		Log:            api.Logger,
+		Request:        r,
+		Action:         database.AuditActionOpen,
+		OrganizationID: workspace.OrganizationID,
+		AdditionalFields: audit.AdditionalFields{
+			WorkspaceName:  workspace.Name,
+			WorkspaceOwner: workspace.OwnerUsername,
+			WorkspaceID:    workspace.ID,
+		},
+	})
+	defer commitAudit()
+	aReq.Old = recording
+	aReq.New = recording
+
+	// Recordings can be large, so they are streamed a page of chunks at a
+	// time instead of being loaded into memory.
+	params := database.GetWorkspaceSessionRecordingChunksByRecordingIDParams{
+		RecordingID:   recording.ID,
+		AfterSequence: -1,
+		LimitOpt:      sessionRecordingChunkPageSize,
+	}
+	chunks, err := api.Database.GetWorkspaceSessionRecordingChunksByRecordingID(ctx, params)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace session recording chunks.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/x-asciicast")
+	rw.WriteHeader(http.StatusOK)
+	for {
+		for _, chunk := range chunks {
+			_, err = rw.Write(chunk.Data)
+			if err != nil {
+				api.Logger.Debug(ctx, "write session recording", slog.Error(err))
+				return
+			}
+		}
+		if len(chunks) < sessionRecordingChunkPageSize {
+			return
+		}
+		params.AfterSequence = chunks[len(chunks)-1].Sequence
+		chunks, err = api.Database.GetWorkspaceSessionRecordingChunksByRecordingID(ctx, params)
+		if err != nil {
+			// The response has started, so the recording is cut short.
+			api.Logger.Error(ctx, "fetch session recording chunks", slog.F("recording_id", recording.ID), slog.Error(err))
+			return
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package coderd_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceSessionRecordings(t *testing.T) {
+	t.Parallel()
+
+	auditor := audit.NewMock()
+	client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{Auditor: auditor})
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, memberUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        memberUser.ID,
+	}).WithAgent().Do()
+	// nolint:gocritic // Inserting recordings is done by the agent.
+	sysCtx := dbauthz.AsSystemRestricted(context.Background())
+	agents, err := db.GetWorkspaceAgentsInLatestBuildByWorkspaceID(sysCtx, r.Workspace.ID)
+	require.NoError(t, err)
+	require.Len(t, agents, 1)
+
+	recording := dbgen.WorkspaceSessionRecording(t, db, database.WorkspaceSessionRecording{
+		WorkspaceID: r.Workspace.ID,
+		AgentID:     agents[0].ID,
+		UserID:      memberUser.ID,
+		Type:        database.WorkspaceSessionRecordingTypeReconnectingPty,
+	})
+	cast := []string{
+		`{"version": 2, "width": 80, "height": 24}` + "\n",
+	}
+	// Enough chunks to be streamed in several pages.
+	for i := 0; i < 150; i++ {
+		cast = append(cast, fmt.Sprintf(`[%d.5, "o", "hello\r\n"]`, i)+"\n")
+	}
+	for i, data := range cast {
+		_, err := db.InsertWorkspaceSessionRecordingChunk(sysCtx, database.InsertWorkspaceSessionRecordingChunkParams{
+			RecordingID: recording.ID,
+			Sequence:    int32(i),
+			Data:        []byte(data),
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("List", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		recordings, err := client.WorkspaceSessionRecordings(ctx, r.Workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, recordings, 1)
+		require.Equal(t, recording.ID, recordings[0].ID)
+		require.Equal(t, agents[0].ID, recordings[0].AgentID)
+		require.Equal(t, memberUser.ID, recordings[0].WorkspaceOwnerID)
+		require.Equal(t, codersdk.WorkspaceSessionRecordingTypeReconnectingPTY, recordings[0].Type)
+		require.Nil(t, recordings[0].EndedAt)
+	})
+
+	t.Run("Cast", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		rc, err := client.WorkspaceSessionRecordingCast(ctx, r.Workspace.ID, recording.ID)
+		require.NoError(t, err)
+		defer rc.Close()
+		data, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.Equal(t, strings.Join(cast, ""), string(data))
+
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			Action:       database.AuditActionOpen,
+			ResourceType: database.ResourceTypeWorkspaceSessionRecording,
+			ResourceID:   recording.ID,
+		}))
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		other := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: owner.OrganizationID,
+			OwnerID:        memberUser.ID,
+		}).Do()
+		_, err := client.WorkspaceSessionRecordingCast(ctx, other.Workspace.ID, recording.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("WorkspaceOwnerForbidden", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		// Recordings are only visible to users that can read the audit log,
+		// not to the owner of the workspace.
+		_, err := member.WorkspaceSessionRecordings(ctx, r.Workspace.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		_, err = member.WorkspaceSessionRecordingCast(ctx, r.Workspace.ID, recording.ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
@@ -0,0 +1,395 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/wsbuilder"
+	"github.com/coder/coder/v2/coderd/wspubsub"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Create workspace snapshot
+// @Description Stops the workspace and snapshots the data of each
+// @Description coder_workspace_snapshot resource in the template.
+// @ID create-workspace-snapshot
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.CreateWorkspaceSnapshotRequest true "Create workspace snapshot request"
+// @Success 201 {object} codersdk.WorkspaceSnapshot
+// @Router /workspaces/{workspace}/snapshots [post]
+func (api *API) postWorkspaceSnapshot(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	apiKey := httpmw.APIKey(r)
+	workspace := httpmw.WorkspaceParam(r)
+	var req codersdk.CreateWorkspaceSnapshotRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	var (
+		snapshotID     = uuid.New()
+		snapshot       database.WorkspaceSnapshot
+		provisionerJob *database.ProvisionerJob
+	)
+	err := api.Database.InTx(func(tx database.Store) error {
+		builder := wsbuilder.New(workspace, database.WorkspaceTransitionStop).
+			Initiator(apiKey.UserID).
+			Snapshot(snapshotID).
+			DeploymentValues(api.Options.DeploymentValues)
+		workspaceBuild, job, _, err := builder.Build(
+			ctx,
+			tx,
+			func(action policy.Action, object rbac.Objecter) bool {
+				return api.Authorize(r, action, object)
+			},
+			audit.WorkspaceBuildBaggageFromRequest(r),
+		)
+		if err != nil {
+			return err
+		}
+		provisionerJob = job
+
+		now := dbtime.Now()
+		snapshot, err = tx.InsertWorkspaceSnapshot(ctx, database.InsertWorkspaceSnapshotParams{
+			ID:             snapshotID,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+			OrganizationID: workspace.OrganizationID,
+			OwnerID:        workspace.OwnerID,
+			TemplateID:     workspace.TemplateID,
+			WorkspaceID:    workspace.ID,
+			BuildID:        workspaceBuild.ID,
+			Name:           req.Name,
+		})
+		if err != nil {
+			return xerrors.Errorf("insert workspace snapshot: %w", err)
+		}
+		return nil
+	}, nil)
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("A snapshot named %q already exists for this template.", req.Name),
+			Validations: []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: "This value is already in use and should be unique.",
+			}},
+		})
+		return
+	}
+	if writeBuildError(ctx, api, rw, err) {
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating workspace snapshot.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	if err := provisionerjobs.PostJob(api.Pubsub, *provisionerJob); err != nil {
+		// Client probably doesn't care about this error, so just log it.
+		api.Logger.Error(ctx, "failed to post provisioner job to pubsub", slog.Error(err))
+	}
+	api.publishWorkspaceUpdate(ctx, workspace.OwnerID, wspubsub.WorkspaceEvent{
+		Kind:        wspubsub.WorkspaceEventKindStateChange,
+		WorkspaceID: workspace.ID,
+	})
+
+	converted, err := convertWorkspaceSnapshot(snapshot, *provisionerJob)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting workspace snapshot.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusCreated, converted)
+}
+
+// @Summary Get workspace snapshots
+// @Description Returns the snapshots which can be restored into the workspace.
+// @Description This includes snapshots of other workspaces with the same owner
+// @Description and template.
+// @ID get-workspace-snapshots
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {array} codersdk.WorkspaceSnapshot
+// @Router /workspaces/{workspace}/snapshots [get]
+func (api *API) workspaceSnapshots(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	snapshots, err := api.Database.GetWorkspaceSnapshotsByOwnerAndTemplate(ctx, database.GetWorkspaceSnapshotsByOwnerAndTemplateParams{
+		OwnerID:    workspace.OwnerID,
+		TemplateID: workspace.TemplateID,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace snapshots.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	converted, err := api.convertWorkspaceSnapshots(ctx, snapshots)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting workspace snapshots.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+// @Summary Restore workspace snapshot
+// @Description Starts a stopped workspace, restoring its data from a snapshot.
+// @ID restore-workspace-snapshot
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param snapshot path string true "Snapshot ID" format(uuid)
+// @Success 201 {object} codersdk.WorkspaceBuild
+// @Router /workspaces/{workspace}/snapshots/{snapshot}/restore [post]
+func (api *API) postWorkspaceSnapshotRestore(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	apiKey := httpmw.APIKey(r)
+	workspace := httpmw.WorkspaceParam(r)
+	snapshotID, ok := httpmw.ParseUUIDParam(rw, r, "snapshot")
+	if !ok {
+		return
+	}
+
+	snapshot, err := api.Database.GetWorkspaceSnapshotByID(ctx, snapshotID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace snapshot.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if snapshot.OwnerID != workspace.OwnerID || snapshot.TemplateID != workspace.TemplateID {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Snapshots can only be restored into a workspace with the same owner and template.",
+		})
+		return
+	}
+	converted, err := api.convertWorkspaceSnapshots(ctx, []database.WorkspaceSnapshot{snapshot})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting workspace snapshot.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if converted[0].Status != codersdk.WorkspaceSnapshotStatusReady {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Snapshot %q cannot be restored because its status is %q.", snapshot.Name, converted[0].Status),
+		})
+		return
+	}
+
+	var (
+		workspaceBuild     *database.WorkspaceBuild
+		provisionerJob     *database.ProvisionerJob
+		provisionerDaemons []database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow
+		approvalRequired   bool
+	)
+	err = api.Database.InTx(func(tx database.Store) error {
+		latestBuild, err := tx.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspace.ID)
+		if err != nil {
+			return xerrors.Errorf("get latest workspace build: %w", err)
+		}
+		if latestBuild.Transition != database.WorkspaceTransitionStop {
+			return wsbuilder.BuildError{
+				Status:  http.StatusBadRequest,
+				Message: "The workspace must be stopped before a snapshot can be restored.",
+				Wrapped: xerrors.Errorf("latest build transition is %q", latestBuild.Transition),
+			}
+		}
+
+		builder := wsbuilder.New(workspace, database.WorkspaceTransitionStart).
+			Initiator(apiKey.UserID).
+			Snapshot(snapshot.ID).
+			DeploymentValues(api.Options.DeploymentValues)
+		workspaceBuild, provisionerJob, provisionerDaemons, err = builder.Build(
+			ctx,
+			tx,
+			func(action policy.Action, object rbac.Objecter) bool {
+				return api.Authorize(r, action, object)
+			},
+			audit.WorkspaceBuildBaggageFromRequest(r),
+		)
+		approvalRequired = builder.ApprovalRequired()
+		return err
+	}, nil)
+	if writeBuildError(ctx, api, rw, err) {
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Error restoring workspace snapshot.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	if approvalRequired {
+		api.notifyBuildApprovalRequested(ctx, apiKey.UserID, workspace, *workspaceBuild)
+	} else if err := provisionerjobs.PostJob(api.Pubsub, *provisionerJob); err != nil {
+		// Client probably doesn't care about this error, so just log it.
+		api.Logger.Error(ctx, "failed to post provisioner job to pubsub", slog.Error(err))
+	}
+
+	apiBuild, err := api.convertWorkspaceBuild(
+		*workspaceBuild,
+		workspace,
+		database.GetProvisionerJobsByIDsWithQueuePositionRow{
+			ProvisionerJob: *provisionerJob,
+			QueuePosition:  0,
+		},
+		[]database.WorkspaceResource{},
+		[]database.WorkspaceResourceMetadatum{},
+		[]database.WorkspaceAgent{},
+		[]database.WorkspaceApp{},
+		[]database.WorkspaceAgentScript{},
+		[]database.WorkspaceAgentLogSource{},
+		database.TemplateVersion{},
+		provisionerDaemons,
+	)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting workspace build.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	api.publishWorkspaceUpdate(ctx, workspace.OwnerID, wspubsub.WorkspaceEvent{
+		Kind:        wspubsub.WorkspaceEventKindStateChange,
+		WorkspaceID: workspace.ID,
+	})
+
+	httpapi.Write(ctx, rw, http.StatusCreated, apiBuild)
+}
+
+// writeBuildError writes a response for errors returned by wsbuilder and
+// reports whether one was written.
+func writeBuildError(ctx context.Context, api *API, rw http.ResponseWriter, err error) bool {
+	var buildErr wsbuilder.BuildError
+	if !xerrors.As(err, &buildErr) {
+		return false
+	}
+	var authErr dbauthz.NotAuthorizedError
+	if xerrors.As(err, &authErr) {
+		buildErr.Status = http.StatusForbidden
+	}
+	if buildErr.Status == http.StatusInternalServerError {
+		api.Logger.Error(ctx, "workspace build error", slog.Error(buildErr.Wrapped))
+	}
+	httpapi.Write(ctx, rw, buildErr.Status, codersdk.Response{
+		Message: buildErr.Message,
+		Detail:  buildErr.Error(),
+	})
+	return true
+}
+
+func (api *API) convertWorkspaceSnapshots(ctx context.Context, snapshots []database.WorkspaceSnapshot) ([]codersdk.WorkspaceSnapshot, error) {
+	jobIDs := make([]uuid.UUID, 0, len(snapshots))
+	jobIDsByBuildID := make(map[uuid.UUID]uuid.UUID, len(snapshots))
+	for _, snapshot := range snapshots {
+		build, err := api.Database.GetWorkspaceBuildByID(ctx, snapshot.BuildID)
+		if err != nil {
+			return nil, xerrors.Errorf("get workspace build %s: %w", snapshot.BuildID, err)
+		}
+		jobIDs = append(jobIDs, build.JobID)
+		jobIDsByBuildID[build.ID] = build.JobID
+	}
+	jobs, err := api.Database.GetProvisionerJobsByIDs(ctx, jobIDs)
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		return nil, xerrors.Errorf("get provisioner jobs: %w", err)
+	}
+	jobsByID := make(map[uuid.UUID]database.ProvisionerJob, len(jobs))
+	for _, job := range jobs {
+		jobsByID[job.ID] = job
+	}
+
+	converted := make([]codersdk.WorkspaceSnapshot, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		job, ok := jobsByID[jobIDsByBuildID[snapshot.BuildID]]
+		if !ok {
+			return nil, xerrors.Errorf("provisioner job for workspace build %s not found", snapshot.BuildID)
+		}
+		c, err := convertWorkspaceSnapshot(snapshot, job)
+		if err != nil {
+			return nil, err
+		}
+		converted = append(converted, c)
+	}
+	return converted, nil
+}
+
+func convertWorkspaceSnapshot(snapshot database.WorkspaceSnapshot, job database.ProvisionerJob) (codersdk.WorkspaceSnapshot, error) {
+	artifacts := map[string]string{}
+	if len(snapshot.Artifacts) > 0 {
+		err := json.Unmarshal(snapshot.Artifacts, &artifacts)
+		if err != nil {
+			return codersdk.WorkspaceSnapshot{}, xerrors.Errorf("unmarshal artifacts of snapshot %s: %w", snapshot.ID, err)
+		}
+	}
+
+	var status codersdk.WorkspaceSnapshotStatus
+	switch codersdk.ProvisionerJobStatus(job.JobStatus) {
+	case codersdk.ProvisionerJobPending, codersdk.ProvisionerJobRunning:
+		status = codersdk.WorkspaceSnapshotStatusPending
+	case codersdk.ProvisionerJobSucceeded:
+		// A template without any coder_workspace_snapshot resources
+		// stops the workspace successfully but has nothing to restore.
+		status = codersdk.WorkspaceSnapshotStatusReady
+		if len(artifacts) == 0 {
+			status = codersdk.WorkspaceSnapshotStatusFailed
+		}
+	default:
+		status = codersdk.WorkspaceSnapshotStatusFailed
+	}
+
+	return codersdk.WorkspaceSnapshot{
+		ID:          snapshot.ID,
+		CreatedAt:   snapshot.CreatedAt,
+		Name:        snapshot.Name,
+		OwnerID:     snapshot.OwnerID,
+		TemplateID:  snapshot.TemplateID,
+		WorkspaceID: snapshot.WorkspaceID,
+		BuildID:     snapshot.BuildID,
+		Status:      status,
+		Artifacts:   artifacts,
+	}, nil
+}
@@ -0,0 +1,158 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceSnapshots(t *testing.T) {
+	t.Parallel()
+
+	snapshotResponses := &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.PlanComplete,
+		ProvisionApply: echo.ApplyComplete,
+		ProvisionApplyMap: map[proto.WorkspaceTransition][]*proto.Response{
+			proto.WorkspaceTransition_SNAPSHOT: {{
+				Type: &proto.Response_Apply{Apply: &proto.ApplyComplete{
+					Snapshots: []*proto.Snapshot{{Name: "home", Artifact: "snap-123"}},
+				}},
+			}},
+			proto.WorkspaceTransition_RESTORE: {{
+				Type: &proto.Response_Apply{Apply: &proto.ApplyComplete{
+					Resources: []*proto.Resource{{Name: "restored", Type: "example"}},
+				}},
+			}},
+		},
+	}
+
+	t.Run("CreateListRestore", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, snapshotResponses)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		snapshot, err := client.CreateWorkspaceSnapshot(ctx, workspace.ID, codersdk.CreateWorkspaceSnapshotRequest{
+			Name: "before-update",
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceSnapshotStatusPending, snapshot.Status)
+		build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, snapshot.BuildID)
+		require.Equal(t, codersdk.WorkspaceTransitionStop, build.Transition)
+
+		// Names must be unique per owner and template.
+		_, err = client.CreateWorkspaceSnapshot(ctx, workspace.ID, codersdk.CreateWorkspaceSnapshotRequest{
+			Name: "before-update",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		// Snapshots outlive the workspace they were taken of.
+		other := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, other.LatestBuild.ID)
+		snapshots, err := client.WorkspaceSnapshots(ctx, other.ID)
+		require.NoError(t, err)
+		require.Len(t, snapshots, 1)
+		require.Equal(t, snapshot.ID, snapshots[0].ID)
+		require.Equal(t, workspace.ID, snapshots[0].WorkspaceID)
+		require.Equal(t, codersdk.WorkspaceSnapshotStatusReady, snapshots[0].Status)
+		require.Equal(t, map[string]string{"home": "snap-123"}, snapshots[0].Artifacts)
+
+		// The workspace must be stopped before restoring.
+		_, err = client.RestoreWorkspaceSnapshot(ctx, other.ID, snapshot.ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		stop := coderdtest.CreateWorkspaceBuild(t, client, other, database.WorkspaceTransitionStop)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, stop.ID)
+
+		restore, err := client.RestoreWorkspaceSnapshot(ctx, other.ID, snapshot.ID)
+		require.NoError(t, err)
+		restore = coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, restore.ID)
+		require.Equal(t, codersdk.WorkspaceTransitionStart, restore.Transition)
+		require.Len(t, restore.Resources, 1)
+		require.Equal(t, "restored", restore.Resources[0].Name)
+	})
+
+	t.Run("NoArtifacts", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		snapshot, err := client.CreateWorkspaceSnapshot(ctx, workspace.ID, codersdk.CreateWorkspaceSnapshotRequest{
+			Name: "empty",
+		})
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, snapshot.BuildID)
+
+		snapshots, err := client.WorkspaceSnapshots(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, snapshots, 1)
+		require.Equal(t, codersdk.WorkspaceSnapshotStatusFailed, snapshots[0].Status)
+
+		_, err = client.RestoreWorkspaceSnapshot(ctx, workspace.ID, snapshot.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("OtherTemplate", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, snapshotResponses)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		otherVersion := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, snapshotResponses)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, otherVersion.ID)
+		otherTemplate := coderdtest.CreateTemplate(t, client, user.OrganizationID, otherVersion.ID)
+		other := coderdtest.CreateWorkspace(t, client, otherTemplate.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, other.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		snapshot, err := client.CreateWorkspaceSnapshot(ctx, workspace.ID, codersdk.CreateWorkspaceSnapshotRequest{
+			Name: "home",
+		})
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, snapshot.BuildID)
+
+		snapshots, err := client.WorkspaceSnapshots(ctx, other.ID)
+		require.NoError(t, err)
+		require.Empty(t, snapshots)
+
+		_, err = client.RestoreWorkspaceSnapshot(ctx, other.ID, snapshot.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
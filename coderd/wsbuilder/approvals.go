@@ -0,0 +1,110 @@
+package wsbuilder
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// NotifyApprovalRequested notifies the approvers of the template that a build
+// is waiting for their approval. If the template has an approver group, its
+// members are notified. Otherwise, template admins are notified. Failures are
+// logged, since the build was already created.
+func NotifyApprovalRequested(
+	ctx context.Context,
+	logger slog.Logger,
+	store database.Store,
+	enqueuer notifications.Enqueuer,
+	createdBy string,
+	initiatorID uuid.UUID,
+	workspace database.Workspace,
+	workspaceBuild database.WorkspaceBuild,
+) {
+	log := logger.With(slog.F("workspace_id", workspace.ID), slog.F("workspace_build_id", workspaceBuild.ID))
+
+	// nolint:gocritic // Need system context to fetch the template and approvers.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	template, err := store.GetTemplateByID(sysCtx, workspace.TemplateID)
+	if err != nil {
+		log.Warn(ctx, "failed to fetch template for build approval notification", slog.F("template_id", workspace.TemplateID), slog.Error(err))
+		return
+	}
+
+	initiator, err := store.GetUserByID(sysCtx, initiatorID)
+	if err != nil {
+		log.Warn(ctx, "failed to fetch user for build approval notification", slog.F("initiator_id", initiatorID), slog.Error(err))
+		return
+	}
+
+	approverIDs, err := approvers(sysCtx, store, template)
+	if err != nil {
+		log.Warn(ctx, "failed to fetch build approvers", slog.Error(err))
+		return
+	}
+
+	for _, approverID := range approverIDs {
+		// Don't send notifications to user which initiated the event.
+		if approverID == initiatorID {
+			continue
+		}
+
+		if _, err := enqueuer.Enqueue(
+			// nolint:gocritic // Need notifier actor to enqueue notifications
+			dbauthz.AsNotifier(ctx),
+			approverID,
+			notifications.TemplateWorkspaceBuildApprovalRequested,
+			map[string]string{
+				"initiator":    initiator.Username,
+				"owner":        workspace.OwnerUsername,
+				"workspace":    workspace.Name,
+				"template":     template.Name,
+				"build_number": strconv.Itoa(int(workspaceBuild.BuildNumber)),
+			},
+			createdBy,
+			// Associate this notification with all the related entities
+			workspace.ID, workspace.OwnerID, workspace.TemplateID, workspace.OrganizationID,
+		); err != nil {
+			log.Warn(ctx, "failed to notify of build approval request", slog.F("approver_id", approverID), slog.Error(err))
+		}
+	}
+}
+
+// approvers returns the users that can approve the builds of the template: the
+// members of its approver group, or template admins if it has none.
+func approvers(ctx context.Context, store database.Store, template database.Template) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	if template.BuildApprovalGroupID.Valid {
+		members, err := store.GetGroupMembersByGroupID(ctx, template.BuildApprovalGroupID.UUID)
+		if err != nil {
+			return nil, xerrors.Errorf("get approver group members: %w", err)
+		}
+		for _, member := range members {
+			ids = append(ids, member.UserID)
+		}
+		return ids, nil
+	}
+
+	// Notice: we can't scrape the user information in parallel as pq
+	// fails with: unexpected describe rows response: 'D'
+	for _, role := range []string{codersdk.RoleOwner, codersdk.RoleTemplateAdmin} {
+		users, err := store.GetUsers(ctx, database.GetUsersParams{
+			RbacRole: []string{role},
+		})
+		if err != nil {
+			return nil, xerrors.Errorf("get users with role %q: %w", role, err)
+		}
+		for _, user := range users {
+			ids = append(ids, user.ID)
+		}
+	}
+	return ids, nil
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/hashicorp/hcl/v2"
@@ -29,6 +30,7 @@ import (
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/provisionerdserver"
 	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rollouts"
 	"github.com/coder/coder/v2/coderd/tracing"
 	"github.com/coder/coder/v2/codersdk"
 )
@@ -54,6 +56,7 @@ type Builder struct {
 	richParameterValues []codersdk.WorkspaceBuildParameter
 	initiator           uuid.UUID
 	reason              database.BuildReason
+	snapshotID          uuid.UUID
 
 	// used during build, makes function arguments less verbose
 	ctx   context.Context
@@ -61,6 +64,7 @@ type Builder struct {
 
 	// cache of objects, so we only fetch once
 	template                     *database.Template
+	activeVersionID              *uuid.UUID
 	templateVersion              *database.TemplateVersion
 	templateVersionJob           *database.ProvisionerJob
 	templateVersionParameters    *[]database.TemplateVersionParameter
@@ -74,6 +78,10 @@ type Builder struct {
 	parameterValues              *[]string
 
 	verifyNoLegacyParametersOnce bool
+
+	// set during build if the template requires the new build to be approved
+	// before it is queued for provisioners.
+	approvalRequired bool
 }
 
 type Option func(Builder) Builder
@@ -156,6 +164,14 @@ func (b Builder) Initiator(u uuid.UUID) Builder {
 	return b
 }
 
+// Snapshot makes a stop build create the given workspace snapshot, or a start
+// build restore from it.
+func (b Builder) Snapshot(id uuid.UUID) Builder {
+	// nolint: revive
+	b.snapshotID = id
+	return b
+}
+
 func (b Builder) Reason(r database.BuildReason) Builder {
 	// nolint: revive
 	b.reason = r
@@ -295,6 +311,7 @@ func (b *Builder) buildTx(authFunc func(action policy.Action, object rbac.Object
 	input, err := json.Marshal(provisionerdserver.WorkspaceProvisionJob{
 		WorkspaceBuildID: workspaceBuildID,
 		LogLevel:         b.logLevel,
+		SnapshotID:       b.snapshotID,
 	})
 	if err != nil {
 		return nil, nil, nil, BuildError{
@@ -330,6 +347,7 @@ func (b *Builder) buildTx(authFunc func(action policy.Action, object rbac.Object
 			Valid:      true,
 			RawMessage: traceMetadataRaw,
 		},
+		Priority: provisionerdserver.WorkspaceBuildJobPriority(b.reason),
 	})
 	if err != nil {
 		return nil, nil, nil, BuildError{http.StatusInternalServerError, "insert provisioner job", err}
@@ -360,6 +378,11 @@ func (b *Builder) buildTx(authFunc func(action policy.Action, object rbac.Object
 		return nil, nil, nil, BuildError{http.StatusInternalServerError, "compute build state", err}
 	}
 
+	approvalRequired, err := b.requiresApproval(authFunc)
+	if err != nil {
+		return nil, nil, nil, err // already wrapped BuildError
+	}
+
 	var workspaceBuild database.WorkspaceBuild
 	err = b.store.InTx(func(store database.Store) error {
 		err = store.InsertWorkspaceBuild(b.ctx, database.InsertWorkspaceBuildParams{
@@ -404,6 +427,25 @@ func (b *Builder) buildTx(authFunc func(action policy.Action, object rbac.Object
 			return BuildError{http.StatusInternalServerError, "insert workspace build parameters: %w", err}
 		}
 
+		if approvalRequired {
+			var expiresAt sql.NullTime
+			if template.BuildApprovalTimeout > 0 {
+				expiresAt = sql.NullTime{
+					Time:  now.Add(time.Duration(template.BuildApprovalTimeout)),
+					Valid: true,
+				}
+			}
+			_, err = store.InsertWorkspaceBuildApproval(b.ctx, database.InsertWorkspaceBuildApprovalParams{
+				WorkspaceBuildID: workspaceBuildID,
+				CreatedAt:        now,
+				UpdatedAt:        now,
+				ExpiresAt:        expiresAt,
+			})
+			if err != nil {
+				return BuildError{http.StatusInternalServerError, "insert workspace build approval", err}
+			}
+		}
+
 		workspaceBuild, err = store.GetWorkspaceBuildByID(b.ctx, workspaceBuildID)
 		if err != nil {
 			return BuildError{http.StatusInternalServerError, "get workspace build", err}
@@ -414,10 +456,89 @@ func (b *Builder) buildTx(authFunc func(action policy.Action, object rbac.Object
 	if err != nil {
 		return nil, nil, nil, err
 	}
+	b.approvalRequired = approvalRequired
 
 	return &workspaceBuild, &provisionerJob, provisionerDaemons, nil
 }
 
+// ApprovalRequired returns true if the template required the last build to be
+// approved. The build's provisioner job is not acquired by provisioners until
+// it is approved, so callers should not post it.
+func (b *Builder) ApprovalRequired() bool {
+	return b.approvalRequired
+}
+
+// requiresApproval determines whether the template requires the new build to be
+// approved before it may run. Start builds initiated by a user require
+// approval. Other builds, i.e. stops and builds started automatically by
+// autostart or scheduled actions, only require approval if they update the
+// workspace to another template version, e.g. the active version or the
+// candidate of a rollout. Delete builds never require approval, and users that
+// can manage the template approve their own builds.
+func (b *Builder) requiresApproval(authFunc func(action policy.Action, object rbac.Objecter) bool) (bool, error) {
+	template, err := b.getTemplate()
+	if err != nil {
+		return false, BuildError{http.StatusInternalServerError, "failed to fetch template", err}
+	}
+	if !template.RequireBuildApproval || b.trans == database.WorkspaceTransitionDelete {
+		return false, nil
+	}
+	if b.trans != database.WorkspaceTransitionStart || b.reason != database.BuildReasonInitiator {
+		updated, err := b.updatesTemplateVersion()
+		if err != nil {
+			return false, BuildError{http.StatusInternalServerError, "failed to compare template versions", err}
+		}
+		if !updated {
+			return false, nil
+		}
+	}
+	if authFunc != nil && authFunc(policy.ActionUpdate, template.RBACObject()) {
+		return false, nil
+	}
+	if len(template.BuildApprovalParameters) == 0 {
+		return true, nil
+	}
+
+	names, values, err := b.getParameters()
+	if err != nil {
+		// getParameters already wraps errors in BuildError
+		return false, err
+	}
+	lastBuildParameters, err := b.getLastBuildParameters()
+	if err != nil {
+		return false, BuildError{http.StatusInternalServerError, "failed to fetch last build parameters", err}
+	}
+	for i, name := range names {
+		if !slices.Contains(template.BuildApprovalParameters, name) {
+			continue
+		}
+		idx := slices.IndexFunc(lastBuildParameters, func(p database.WorkspaceBuildParameter) bool {
+			return p.Name == name
+		})
+		if idx == -1 || lastBuildParameters[idx].Value != values[i] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// updatesTemplateVersion returns true if the build uses another template
+// version than the last build of the workspace.
+func (b *Builder) updatesTemplateVersion() (bool, error) {
+	versionID, err := b.getTemplateVersionID()
+	if err != nil {
+		return false, xerrors.Errorf("get template version ID: %w", err)
+	}
+	lastBuild, err := b.getLastBuild()
+	if xerrors.Is(err, sql.ErrNoRows) {
+		return true, nil
+	}
+	if err != nil {
+		return false, xerrors.Errorf("get last build: %w", err)
+	}
+	return lastBuild.TemplateVersionID != versionID, nil
+}
+
 func (b *Builder) getTemplate() (*database.Template, error) {
 	if b.template != nil {
 		return b.template, nil
@@ -471,7 +592,7 @@ func (b *Builder) getTemplateVersionID() (uuid.UUID, error) {
 		if err != nil {
 			return uuid.Nil, xerrors.Errorf("get template so we can get active version: %w", err)
 		}
-		return t.ActiveVersionID, nil
+		return b.getActiveVersionID(t)
 	}
 	// default is prior version
 	bld, err := b.getLastBuild()
@@ -481,6 +602,49 @@ func (b *Builder) getTemplateVersionID() (uuid.UUID, error) {
 	return bld.TemplateVersionID, nil
 }
 
+// getActiveVersionID returns the version the workspace builds when it follows
+// the active version of the template. That's the candidate version of the
+// rollout in progress if the workspace is included in it.
+func (b *Builder) getActiveVersionID(t *database.Template) (uuid.UUID, error) {
+	if b.activeVersionID != nil {
+		return *b.activeVersionID, nil
+	}
+	id, err := b.resolveActiveVersionID(t)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	b.activeVersionID = &id
+	return id, nil
+}
+
+func (b *Builder) resolveActiveVersionID(t *database.Template) (uuid.UUID, error) {
+	// nolint:gocritic // The initiator may not be able to read the rollout or the group members.
+	ctx := dbauthz.AsSystemRestricted(b.ctx)
+	rollout, err := b.store.GetActiveTemplateRolloutByTemplateID(ctx, t.ID)
+	if xerrors.Is(err, sql.ErrNoRows) {
+		return t.ActiveVersionID, nil
+	}
+	if err != nil {
+		return uuid.Nil, xerrors.Errorf("get active template rollout: %w", err)
+	}
+	var inGroup bool
+	if rollout.GroupID.Valid {
+		groups, err := b.store.GetGroups(ctx, database.GetGroupsParams{
+			OrganizationID: t.OrganizationID,
+			HasMemberID:    b.workspace.OwnerID,
+			GroupIds:       []uuid.UUID{rollout.GroupID.UUID},
+		})
+		if err != nil {
+			return uuid.Nil, xerrors.Errorf("get rollout group: %w", err)
+		}
+		inGroup = len(groups) > 0
+	}
+	if rollouts.Includes(rollout, b.workspace.ID, inGroup) {
+		return rollout.TemplateVersionID, nil
+	}
+	return t.ActiveVersionID, nil
+}
+
 func (b *Builder) getLastBuild() (*database.WorkspaceBuild, error) {
 	if b.lastBuild != nil {
 		return b.lastBuild, nil
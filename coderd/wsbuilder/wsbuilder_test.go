@@ -234,6 +234,7 @@ func TestBuilder_ActiveVersion(t *testing.T) {
 	mDB := expectDB(t,
 		// Inputs
 		withTemplate,
+		withNoActiveTemplateRollout,
 		withActiveVersion(nil),
 		withLastBuildNotFound,
 		withTemplateVersionVariables(activeVersionID, nil),
@@ -266,6 +267,297 @@ func TestBuilder_ActiveVersion(t *testing.T) {
 	req.NoError(err)
 }
 
+func TestBuilder_ActiveVersionRollout(t *testing.T) {
+	t.Parallel()
+	req := require.New(t)
+	asrt := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	groupID := uuid.New()
+	mDB := expectDB(t,
+		// Inputs
+		withTemplate,
+		withActiveTemplateRollout(database.TemplateRollout{
+			ID:                uuid.New(),
+			TemplateID:        templateID,
+			TemplateVersionID: inactiveVersionID,
+			Status:            database.TemplateRolloutStatusInProgress,
+			// Only members of the group are included.
+			Percent: 0,
+			GroupID: uuid.NullUUID{UUID: groupID, Valid: true},
+		}),
+		func(mTx *dbmock.MockStore) {
+			mTx.EXPECT().GetGroups(gomock.Any(), database.GetGroupsParams{
+				OrganizationID: orgID,
+				HasMemberID:    userID,
+				GroupIds:       []uuid.UUID{groupID},
+			}).
+				Times(1).
+				Return([]database.GetGroupsRow{{Group: database.Group{ID: groupID}}}, nil)
+		},
+		withInactiveVersion(nil),
+		withLastBuildNotFound,
+		withTemplateVersionVariables(inactiveVersionID, nil),
+		withParameterSchemas(inactiveJobID, nil),
+		withWorkspaceTags(inactiveVersionID, nil),
+		withProvisionerDaemons([]database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow{}),
+
+		// Outputs
+		expectProvisionerJob(func(job database.InsertProvisionerJobParams) {
+			asrt.Equal(inactiveFileID, job.FileID)
+		}),
+		withInTx,
+		expectBuild(func(bld database.InsertWorkspaceBuildParams) {
+			asrt.Equal(inactiveVersionID, bld.TemplateVersionID)
+		}),
+		expectBuildParameters(func(params database.InsertWorkspaceBuildParametersParams) {
+		}),
+		withBuild,
+	)
+
+	ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
+	uut := wsbuilder.New(ws, database.WorkspaceTransitionStart).ActiveVersion()
+	// nolint: dogsled
+	_, _, _, err := uut.Build(ctx, mDB, nil, audit.WorkspaceBuildBaggage{})
+	req.NoError(err)
+}
+
+func TestBuilder_RequireApproval(t *testing.T) {
+	t.Parallel()
+
+	const sizeParameterName = "instance_size"
+	richParameters := []database.TemplateVersionParameter{
+		{Name: sizeParameterName, Mutable: true, Options: json.RawMessage("[]")},
+	}
+	lastBuildParameters := []database.WorkspaceBuildParameter{
+		{Name: sizeParameterName, Value: "small"},
+	}
+
+	t.Run("AllBuilds", func(t *testing.T) {
+		t.Parallel()
+		req := require.New(t)
+		asrt := assert.New(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var buildID uuid.UUID
+		mDB := expectDB(t,
+			// Inputs
+			withTemplateRequiringApproval(nil),
+			withInactiveVersion(nil),
+			withLastBuildFound,
+			withTemplateVersionVariables(inactiveVersionID, nil),
+			withRichParameters(nil),
+			withParameterSchemas(inactiveJobID, nil),
+			withWorkspaceTags(inactiveVersionID, nil),
+			withProvisionerDaemons([]database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow{}),
+
+			// Outputs
+			expectProvisionerJob(func(job database.InsertProvisionerJobParams) {}),
+			withInTx,
+			expectBuild(func(bld database.InsertWorkspaceBuildParams) {
+				buildID = bld.ID
+			}),
+			expectBuildParameters(func(params database.InsertWorkspaceBuildParametersParams) {}),
+			expectBuildApproval(func(approval database.InsertWorkspaceBuildApprovalParams) {
+				asrt.Equal(buildID, approval.WorkspaceBuildID)
+				asrt.True(approval.ExpiresAt.Valid)
+				asrt.Equal(approval.CreatedAt.Add(time.Hour), approval.ExpiresAt.Time)
+			}),
+			withBuild,
+		)
+
+		ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
+		uut := wsbuilder.New(ws, database.WorkspaceTransitionStart)
+		// nolint: dogsled
+		_, _, _, err := uut.Build(ctx, mDB, nil, audit.WorkspaceBuildBaggage{})
+		req.NoError(err)
+		req.True(uut.ApprovalRequired())
+	})
+
+	t.Run("ParameterChanged", func(t *testing.T) {
+		t.Parallel()
+		req := require.New(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mDB := expectDB(t,
+			// Inputs
+			withTemplateRequiringApproval([]string{sizeParameterName}),
+			withInactiveVersion(richParameters),
+			withLastBuildFound,
+			withTemplateVersionVariables(inactiveVersionID, nil),
+			withRichParameters(lastBuildParameters),
+			withParameterSchemas(inactiveJobID, nil),
+			withWorkspaceTags(inactiveVersionID, nil),
+			withProvisionerDaemons([]database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow{}),
+
+			// Outputs
+			expectProvisionerJob(func(job database.InsertProvisionerJobParams) {}),
+			withInTx,
+			expectBuild(func(bld database.InsertWorkspaceBuildParams) {}),
+			expectBuildParameters(func(params database.InsertWorkspaceBuildParametersParams) {}),
+			expectBuildApproval(func(approval database.InsertWorkspaceBuildApprovalParams) {}),
+			withBuild,
+		)
+
+		ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
+		uut := wsbuilder.New(ws, database.WorkspaceTransitionStart).
+			RichParameterValues([]codersdk.WorkspaceBuildParameter{{Name: sizeParameterName, Value: "large"}})
+		// nolint: dogsled
+		_, _, _, err := uut.Build(ctx, mDB, nil, audit.WorkspaceBuildBaggage{})
+		req.NoError(err)
+		req.True(uut.ApprovalRequired())
+	})
+
+	t.Run("ParameterUnchanged", func(t *testing.T) {
+		t.Parallel()
+		req := require.New(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mDB := expectDB(t,
+			// Inputs
+			withTemplateRequiringApproval([]string{sizeParameterName}),
+			withInactiveVersion(richParameters),
+			withLastBuildFound,
+			withTemplateVersionVariables(inactiveVersionID, nil),
+			withRichParameters(lastBuildParameters),
+			withParameterSchemas(inactiveJobID, nil),
+			withWorkspaceTags(inactiveVersionID, nil),
+			withProvisionerDaemons([]database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow{}),
+
+			// Outputs
+			expectProvisionerJob(func(job database.InsertProvisionerJobParams) {}),
+			withInTx,
+			expectBuild(func(bld database.InsertWorkspaceBuildParams) {}),
+			expectBuildParameters(func(params database.InsertWorkspaceBuildParametersParams) {}),
+			withBuild,
+		)
+
+		ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
+		uut := wsbuilder.New(ws, database.WorkspaceTransitionStart)
+		// nolint: dogsled
+		_, _, _, err := uut.Build(ctx, mDB, nil, audit.WorkspaceBuildBaggage{})
+		req.NoError(err)
+		req.False(uut.ApprovalRequired())
+	})
+
+	t.Run("Stop", func(t *testing.T) {
+		t.Parallel()
+		req := require.New(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mDB := expectDB(t,
+			// Inputs
+			withTemplateRequiringApproval(nil),
+			withInactiveVersion(nil),
+			withLastBuildFound,
+			withTemplateVersionVariables(inactiveVersionID, nil),
+			withRichParameters(nil),
+			withWorkspaceTags(inactiveVersionID, nil),
+			withProvisionerDaemons([]database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow{}),
+
+			// Outputs
+			expectProvisionerJob(func(job database.InsertProvisionerJobParams) {}),
+			withInTx,
+			expectBuild(func(bld database.InsertWorkspaceBuildParams) {}),
+			expectBuildParameters(func(params database.InsertWorkspaceBuildParametersParams) {}),
+			withBuild,
+		)
+
+		ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
+		uut := wsbuilder.New(ws, database.WorkspaceTransitionStop)
+		// nolint: dogsled
+		_, _, _, err := uut.Build(ctx, mDB, nil, audit.WorkspaceBuildBaggage{})
+		req.NoError(err)
+		req.False(uut.ApprovalRequired())
+	})
+
+	t.Run("Autostart", func(t *testing.T) {
+		t.Parallel()
+		req := require.New(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mDB := expectDB(t,
+			// Inputs
+			withTemplateRequiringApproval(nil),
+			withInactiveVersion(nil),
+			withLastBuildFound,
+			withTemplateVersionVariables(inactiveVersionID, nil),
+			withRichParameters(nil),
+			withParameterSchemas(inactiveJobID, nil),
+			withWorkspaceTags(inactiveVersionID, nil),
+			withProvisionerDaemons([]database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow{}),
+
+			// Outputs
+			expectProvisionerJob(func(job database.InsertProvisionerJobParams) {}),
+			withInTx,
+			expectBuild(func(bld database.InsertWorkspaceBuildParams) {}),
+			expectBuildParameters(func(params database.InsertWorkspaceBuildParametersParams) {}),
+			withBuild,
+		)
+
+		// Autostart builds of the version of the last build don't require
+		// approval.
+		ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
+		uut := wsbuilder.New(ws, database.WorkspaceTransitionStart).Reason(database.BuildReasonAutostart)
+		// nolint: dogsled
+		_, _, _, err := uut.Build(ctx, mDB, nil, audit.WorkspaceBuildBaggage{})
+		req.NoError(err)
+		req.False(uut.ApprovalRequired())
+	})
+
+	t.Run("AutostartUpdate", func(t *testing.T) {
+		t.Parallel()
+		req := require.New(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mDB := expectDB(t,
+			// Inputs
+			withTemplateRequiringApproval(nil),
+			withNoActiveTemplateRollout,
+			withActiveVersion(nil),
+			withLastBuildFound,
+			withTemplateVersionVariables(activeVersionID, nil),
+			withRichParameters(nil),
+			withParameterSchemas(activeJobID, nil),
+			withWorkspaceTags(activeVersionID, nil),
+			withProvisionerDaemons([]database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow{}),
+
+			// Outputs
+			expectProvisionerJob(func(job database.InsertProvisionerJobParams) {}),
+			withInTx,
+			expectBuild(func(bld database.InsertWorkspaceBuildParams) {}),
+			expectBuildParameters(func(params database.InsertWorkspaceBuildParametersParams) {}),
+			expectBuildApproval(func(approval database.InsertWorkspaceBuildApprovalParams) {}),
+			withBuild,
+		)
+
+		// Autostart builds that update the workspace to the active version
+		// require approval.
+		ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
+		uut := wsbuilder.New(ws, database.WorkspaceTransitionStart).
+			Reason(database.BuildReasonAutostart).
+			ActiveVersion()
+		// nolint: dogsled
+		_, _, _, err := uut.Build(ctx, mDB, nil, audit.WorkspaceBuildBaggage{})
+		req.NoError(err)
+		req.True(uut.ApprovalRequired())
+	})
+}
+
 func TestWorkspaceBuildWithTags(t *testing.T) {
 	t.Parallel()
 
@@ -809,7 +1101,37 @@ func withTemplate(mTx *dbmock.MockStore) {
 		}, nil)
 }
 
+func withTemplateRequiringApproval(parameters []string) func(mTx *dbmock.MockStore) {
+	return func(mTx *dbmock.MockStore) {
+		mTx.EXPECT().GetTemplateByID(gomock.Any(), templateID).
+			Times(1).
+			Return(database.Template{
+				ID:                      templateID,
+				OrganizationID:          orgID,
+				Provisioner:             database.ProvisionerTypeTerraform,
+				ActiveVersionID:         activeVersionID,
+				RequireBuildApproval:    true,
+				BuildApprovalParameters: parameters,
+				BuildApprovalTimeout:    int64(time.Hour),
+			}, nil)
+	}
+}
+
 // withInTx runs the given functions on the same db mock.
+func withNoActiveTemplateRollout(mTx *dbmock.MockStore) {
+	mTx.EXPECT().GetActiveTemplateRolloutByTemplateID(gomock.Any(), templateID).
+		Times(1).
+		Return(database.TemplateRollout{}, sql.ErrNoRows)
+}
+
+func withActiveTemplateRollout(rollout database.TemplateRollout) func(mTx *dbmock.MockStore) {
+	return func(mTx *dbmock.MockStore) {
+		mTx.EXPECT().GetActiveTemplateRolloutByTemplateID(gomock.Any(), templateID).
+			Times(1).
+			Return(rollout, nil)
+	}
+}
+
 func withInTx(mTx *dbmock.MockStore) {
 	mTx.EXPECT().InTx(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
 		func(f func(store database.Store) error, _ *database.TxOptions) error {
@@ -1049,6 +1371,23 @@ func expectBuildParameters(
 	}
 }
 
+// expectBuildApproval captures a call to InsertWorkspaceBuildApproval and runs the provided assertions
+// against it.
+func expectBuildApproval(
+	assertions func(database.InsertWorkspaceBuildApprovalParams),
+) func(mTx *dbmock.MockStore) {
+	return func(mTx *dbmock.MockStore) {
+		mTx.EXPECT().InsertWorkspaceBuildApproval(gomock.Any(), gomock.Any()).
+			Times(1).
+			DoAndReturn(
+				func(ctx context.Context, params database.InsertWorkspaceBuildApprovalParams) (database.WorkspaceBuildApproval, error) {
+					assertions(params)
+					return database.WorkspaceBuildApproval{WorkspaceBuildID: params.WorkspaceBuildID}, nil
+				},
+			)
+	}
+}
+
 func withProvisionerDaemons(provisionerDaemons []database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow) func(mTx *dbmock.MockStore) {
 	return func(mTx *dbmock.MockStore) {
 		mTx.EXPECT().GetEligibleProvisionerDaemonsByProvisionerJobIDs(gomock.Any(), gomock.Any()).Return(provisionerDaemons, nil)
@@ -109,6 +109,17 @@ type Manifest struct {
 	DisableDirectConnections bool                                         `json:"disable_direct_connections"`
 	Metadata                 []codersdk.WorkspaceAgentMetadataDescription `json:"metadata"`
 	Scripts                  []codersdk.WorkspaceAgentScript              `json:"scripts"`
+	SessionRecordingEnabled  bool                                         `json:"session_recording_enabled"`
+	Secrets                  []Secret                                     `json:"secrets"`
+}
+
+// Secret is injected into the workspace by the agent as an environment
+// variable, a file, or both. The value is omitted when the manifest is
+// serialized to JSON, so it does not end up in logs.
+type Secret struct {
+	EnvName  string `json:"env_name,omitempty"`
+	FilePath string `json:"file_path,omitempty"`
+	Value    []byte `json:"-"`
 }
 
 type LogSource struct {
@@ -229,6 +240,18 @@ func (c *Client) ConnectRPC23(ctx context.Context) (
 	return proto.NewDRPCAgentClient(conn), tailnetproto.NewDRPCTailnetClient(conn), nil
 }
 
+// ConnectRPC24 returns a dRPC client to the Agent API v2.4.  It is useful when you want to be
+// maximally compatible with Coderd Release Versions from 2.19+
+func (c *Client) ConnectRPC24(ctx context.Context) (
+	proto.DRPCAgentClient24, tailnetproto.DRPCTailnetClient24, error,
+) {
+	conn, err := c.connectRPCVersion(ctx, apiversion.New(2, 4))
+	if err != nil {
+		return nil, nil, err
+	}
+	return proto.NewDRPCAgentClient(conn), tailnetproto.NewDRPCTailnetClient(conn), nil
+}
+
 // ConnectRPC connects to the workspace agent API and tailnet API
 func (c *Client) ConnectRPC(ctx context.Context) (drpc.Conn, error) {
 	return c.connectRPCVersion(ctx, proto.CurrentVersion)
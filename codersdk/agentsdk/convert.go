@@ -48,6 +48,8 @@ func ManifestFromProto(manifest *proto.Manifest) (Manifest, error) {
 		MOTDFile:                 manifest.MotdPath,
 		DisableDirectConnections: manifest.DisableDirectConnections,
 		Metadata:                 MetadataDescriptionsFromProto(manifest.Metadata),
+		SessionRecordingEnabled:  manifest.SessionRecordingEnabled,
+		Secrets:                  SecretsFromProto(manifest.Secrets),
 	}, nil
 }
 
@@ -73,9 +75,35 @@ func ProtoFromManifest(manifest Manifest) (*proto.Manifest, error) {
 		Scripts:                  ProtoFromScripts(manifest.Scripts),
 		Apps:                     apps,
 		Metadata:                 ProtoFromMetadataDescriptions(manifest.Metadata),
+		SessionRecordingEnabled:  manifest.SessionRecordingEnabled,
+		Secrets:                  ProtoFromSecrets(manifest.Secrets),
 	}, nil
 }
 
+func SecretsFromProto(secrets []*proto.Secret) []Secret {
+	ret := make([]Secret, len(secrets))
+	for i, secret := range secrets {
+		ret[i] = Secret{
+			EnvName:  secret.EnvName,
+			FilePath: secret.FilePath,
+			Value:    secret.Value,
+		}
+	}
+	return ret
+}
+
+func ProtoFromSecrets(secrets []Secret) []*proto.Secret {
+	ret := make([]*proto.Secret, len(secrets))
+	for i, secret := range secrets {
+		ret[i] = &proto.Secret{
+			EnvName:  secret.EnvName,
+			FilePath: secret.FilePath,
+			Value:    secret.Value,
+		}
+	}
+	return ret
+}
+
 func MetadataDescriptionsFromProto(descriptions []*proto.WorkspaceAgentMetadata_Description) []codersdk.WorkspaceAgentMetadataDescription {
 	ret := make([]codersdk.WorkspaceAgentMetadataDescription, len(descriptions))
 	for i, description := range descriptions {
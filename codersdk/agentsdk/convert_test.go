@@ -88,6 +88,7 @@ func TestManifest(t *testing.T) {
 		Directory:                "/home/coder",
 		MOTDFile:                 "/etc/motd",
 		DisableDirectConnections: true,
+		SessionRecordingEnabled:  true,
 		Metadata: []codersdk.WorkspaceAgentMetadataDescription{
 			{
 				DisplayName: "CPU",
@@ -130,6 +131,16 @@ func TestManifest(t *testing.T) {
 				DisplayName:      "bar",
 			},
 		},
+		Secrets: []agentsdk.Secret{
+			{
+				EnvName: "GITHUB_TOKEN",
+				Value:   []byte("ghp_token"),
+			},
+			{
+				FilePath: "~/.npmrc",
+				Value:    []byte("//registry.npmjs.org/:_authToken=token"),
+			},
+		},
 	}
 	p, err := agentsdk.ProtoFromManifest(manifest)
 	require.NoError(t, err)
@@ -152,6 +163,8 @@ func TestManifest(t *testing.T) {
 	require.Equal(t, manifest.DisableDirectConnections, back.DisableDirectConnections)
 	require.Equal(t, manifest.Metadata, back.Metadata)
 	require.Equal(t, manifest.Scripts, back.Scripts)
+	require.Equal(t, manifest.SessionRecordingEnabled, back.SessionRecordingEnabled)
+	require.Equal(t, manifest.Secrets, back.Secrets)
 }
 
 func TestSubsystems(t *testing.T) {
@@ -19,9 +19,12 @@ type APIKey struct {
 	CreatedAt       time.Time   `json:"created_at" validate:"required" format:"date-time"`
 	UpdatedAt       time.Time   `json:"updated_at" validate:"required" format:"date-time"`
 	LoginType       LoginType   `json:"login_type" validate:"required" enums:"password,github,oidc,token"`
-	Scope           APIKeyScope `json:"scope" validate:"required" enums:"all,application_connect"`
+	Scope           APIKeyScope `json:"scope" validate:"required" enums:"all,application_connect,custom"`
 	TokenName       string      `json:"token_name" validate:"required"`
 	LifetimeSeconds int64       `json:"lifetime_seconds" validate:"required"`
+	// ScopePermissions and ScopeAllowList are only set for the custom scope.
+	ScopePermissions []APIKeyScopePermission `json:"scope_permissions,omitempty"`
+	ScopeAllowList   []string                `json:"scope_allow_list,omitempty"`
 }
 
 // LoginType is the type of login used to create the API key.
@@ -48,12 +51,34 @@ const (
 	// APIKeyScopeApplicationConnect is a scope that allows the user
 	// to connect to applications in a workspace.
 	APIKeyScopeApplicationConnect APIKeyScope = "application_connect"
+	// APIKeyScopeCustom is a scope that only allows the actions listed in
+	// the scope permissions of the key, optionally only on the resources
+	// in its allow list.
+	APIKeyScopeCustom APIKeyScope = "custom"
 )
 
+// APIKeyScopePermission allows a token with the custom scope to perform an
+// action on a type of resource. A token can never do more than the user it
+// belongs to.
+type APIKeyScopePermission struct {
+	ResourceType RBACResource `json:"resource_type"`
+	Action       RBACAction   `json:"action"`
+	// OrganizationID limits the permission to resources in one
+	// organization. If unset, the permission applies in every organization.
+	OrganizationID uuid.UUID `json:"organization_id,omitempty" format:"uuid"`
+}
+
 type CreateTokenRequest struct {
 	Lifetime  time.Duration `json:"lifetime"`
-	Scope     APIKeyScope   `json:"scope" enums:"all,application_connect"`
+	Scope     APIKeyScope   `json:"scope" enums:"all,application_connect,custom"`
 	TokenName string        `json:"token_name"`
+	// ScopePermissions are the permissions of a token with the custom
+	// scope. If set, the scope defaults to custom.
+	ScopePermissions []APIKeyScopePermission `json:"scope_permissions,omitempty"`
+	// ScopeAllowList limits a token with the custom scope to the resources
+	// with these IDs. If empty, the token is not limited to specific
+	// resources.
+	ScopeAllowList []string `json:"scope_allow_list,omitempty"`
 }
 
 // GenerateAPIKeyResponse contains an API key for a user.
@@ -37,6 +37,8 @@ const (
 	ResourceTypeIdpSyncSettingsOrganization ResourceType = "idp_sync_settings_organization"
 	ResourceTypeIdpSyncSettingsGroup        ResourceType = "idp_sync_settings_group"
 	ResourceTypeIdpSyncSettingsRole         ResourceType = "idp_sync_settings_role"
+	ResourceTypeWorkspaceSessionRecording   ResourceType = "workspace_session_recording"
+	ResourceTypeSecret                      ResourceType = "secret"
 )
 
 func (r ResourceType) FriendlyString() string {
@@ -87,6 +89,10 @@ func (r ResourceType) FriendlyString() string {
 		return "settings"
 	case ResourceTypeIdpSyncSettingsRole:
 		return "settings"
+	case ResourceTypeWorkspaceSessionRecording:
+		return "session recording"
+	case ResourceTypeSecret:
+		return "secret"
 	default:
 		return "unknown"
 	}
@@ -104,6 +110,7 @@ const (
 	AuditActionLogout               AuditAction = "logout"
 	AuditActionRegister             AuditAction = "register"
 	AuditActionRequestPasswordReset AuditAction = "request_password_reset"
+	AuditActionOpen                 AuditAction = "open"
 )
 
 func (a AuditAction) Friendly() string {
@@ -126,6 +133,8 @@ func (a AuditAction) Friendly() string {
 		return "registered"
 	case AuditActionRequestPasswordReset:
 		return "password reset requested"
+	case AuditActionOpen:
+		return "opened"
 	default:
 		return "unknown"
 	}
@@ -182,7 +191,7 @@ type CreateTestAuditLogRequest struct {
 	ResourceID       uuid.UUID       `json:"resource_id,omitempty" format:"uuid"`
 	AdditionalFields json.RawMessage `json:"additional_fields,omitempty"`
 	Time             time.Time       `json:"time,omitempty" format:"date-time"`
-	BuildReason      BuildReason     `json:"build_reason,omitempty" enums:"autostart,autostop,initiator"`
+	BuildReason      BuildReason     `json:"build_reason,omitempty" enums:"autostart,autostop,initiator,scheduled"`
 	OrganizationID   uuid.UUID       `json:"organization_id,omitempty" format:"uuid"`
 }
 
@@ -0,0 +1,89 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// CostBudget is a monthly limit on the cost of the workspaces owned by the
+// members of a group. Months start at midnight UTC on the first day of the
+// month.
+type CostBudget struct {
+	GroupID        uuid.UUID `json:"group_id" format:"uuid"`
+	OrganizationID uuid.UUID `json:"organization_id" format:"uuid"`
+	CreatedAt      time.Time `json:"created_at" format:"date-time"`
+	UpdatedAt      time.Time `json:"updated_at" format:"date-time"`
+	MonthlyLimit   float64   `json:"monthly_limit" example:"500"`
+	// NotifyThresholdPercent is the percentage of the monthly limit at which
+	// members of the group are notified. 0 disables notifications.
+	NotifyThresholdPercent int32 `json:"notify_threshold_percent" example:"80"`
+	// StopThresholdPercent is the percentage of the monthly limit at which
+	// running workspaces of members of the group are stopped. 0 disables
+	// stopping workspaces.
+	StopThresholdPercent int32 `json:"stop_threshold_percent" example:"100"`
+}
+
+type UpsertCostBudgetRequest struct {
+	MonthlyLimit           float64 `json:"monthly_limit" validate:"required,gt=0"`
+	NotifyThresholdPercent int32   `json:"notify_threshold_percent" validate:"gte=0"`
+	StopThresholdPercent   int32   `json:"stop_threshold_percent" validate:"gte=0"`
+}
+
+// GroupCostBudget returns the cost budget of a group.
+func (c *Client) GroupCostBudget(ctx context.Context, group uuid.UUID) (CostBudget, error) {
+	res, err := c.Request(ctx, http.MethodGet,
+		fmt.Sprintf("/api/v2/groups/%s/cost-budget", group.String()),
+		nil,
+	)
+	if err != nil {
+		return CostBudget{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return CostBudget{}, ReadBodyAsError(res)
+	}
+	var resp CostBudget
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// UpsertGroupCostBudget creates or updates the cost budget of a group.
+func (c *Client) UpsertGroupCostBudget(ctx context.Context, group uuid.UUID, req UpsertCostBudgetRequest) (CostBudget, error) {
+	res, err := c.Request(ctx, http.MethodPut,
+		fmt.Sprintf("/api/v2/groups/%s/cost-budget", group.String()),
+		req,
+	)
+	if err != nil {
+		return CostBudget{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return CostBudget{}, ReadBodyAsError(res)
+	}
+	var resp CostBudget
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// DeleteGroupCostBudget removes the cost budget of a group.
+func (c *Client) DeleteGroupCostBudget(ctx context.Context, group uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete,
+		fmt.Sprintf("/api/v2/groups/%s/cost-budget", group.String()),
+		nil,
+	)
+	if err != nil {
+		return xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
@@ -84,6 +84,21 @@ func TestDeploymentValues_HighlyConfigurable(t *testing.T) {
 		"Notifications: Email Auth: Password": {
 			yaml: true,
 		},
+		"Notifications: Slack: Webhook URL": {
+			yaml: true,
+		},
+		"Notifications: Slack: Bot Token": {
+			yaml: true,
+		},
+		"Notifications: Microsoft Teams: Webhook URL": {
+			yaml: true,
+		},
+		"Audit Log Streaming: HTTP: Headers": {
+			yaml: true,
+		},
+		"Pubsub: NATS URL": {
+			yaml: true,
+		},
 	}
 
 	set := (&codersdk.DeploymentValues{}).Options()
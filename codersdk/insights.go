@@ -313,3 +313,159 @@ func (c *Client) GetUserStatusCounts(ctx context.Context, req GetUserStatusCount
 	var result GetUserStatusCountsResponse
 	return result, json.NewDecoder(resp.Body).Decode(&result)
 }
+
+// CostInsightsGroupBy is the entity the cost of workspaces is broken down by.
+type CostInsightsGroupBy string
+
+// CostInsightsGroupBy enums.
+const (
+	CostInsightsGroupByTemplate CostInsightsGroupBy = "template"
+	CostInsightsGroupByUser     CostInsightsGroupBy = "user"
+	CostInsightsGroupByGroup    CostInsightsGroupBy = "group"
+)
+
+// CostInsightsResponse is the response from the cost insights endpoint.
+type CostInsightsResponse struct {
+	Report          CostInsightsReport           `json:"report"`
+	IntervalReports []CostInsightsIntervalReport `json:"interval_reports"`
+}
+
+// CostInsightsReport is the cost of workspaces within a time range, broken
+// down by template, user or group.
+type CostInsightsReport struct {
+	StartTime   time.Time           `json:"start_time" format:"date-time"`
+	EndTime     time.Time           `json:"end_time" format:"date-time"`
+	TemplateIDs []uuid.UUID         `json:"template_ids" format:"uuid"`
+	GroupBy     CostInsightsGroupBy `json:"group_by" enums:"template,user,group"`
+	TotalCost   float64             `json:"total_cost" example:"1250.5"`
+	Breakdown   []CostBreakdown     `json:"breakdown"`
+}
+
+// CostBreakdown is the cost of the workspaces of a template, user or group.
+// The cost of a workspace counts towards every group its owner is a member
+// of.
+type CostBreakdown struct {
+	ID   uuid.UUID `json:"id" format:"uuid"`
+	Name string    `json:"name"`
+	Cost float64   `json:"cost" example:"412.25"`
+}
+
+// CostInsightsIntervalReport is the cost of workspaces within an interval.
+type CostInsightsIntervalReport struct {
+	StartTime time.Time              `json:"start_time" format:"date-time"`
+	EndTime   time.Time              `json:"end_time" format:"date-time"`
+	Interval  InsightsReportInterval `json:"interval" example:"day"`
+	Cost      float64                `json:"cost" example:"42.5"`
+}
+
+type CostInsightsRequest struct {
+	StartTime   time.Time              `json:"start_time" format:"date-time"`
+	EndTime     time.Time              `json:"end_time" format:"date-time"`
+	TemplateIDs []uuid.UUID            `json:"template_ids" format:"uuid"`
+	Interval    InsightsReportInterval `json:"interval" example:"day"`
+	GroupBy     CostInsightsGroupBy    `json:"group_by" example:"template"`
+}
+
+func (c *Client) CostInsights(ctx context.Context, req CostInsightsRequest) (CostInsightsResponse, error) {
+	qp := url.Values{}
+	qp.Add("start_time", req.StartTime.Format(insightsTimeLayout))
+	qp.Add("end_time", req.EndTime.Format(insightsTimeLayout))
+	if len(req.TemplateIDs) > 0 {
+		var templateIDs []string
+		for _, id := range req.TemplateIDs {
+			templateIDs = append(templateIDs, id.String())
+		}
+		qp.Add("template_ids", strings.Join(templateIDs, ","))
+	}
+	if req.Interval != "" {
+		qp.Add("interval", string(req.Interval))
+	}
+	if req.GroupBy != "" {
+		qp.Add("group_by", string(req.GroupBy))
+	}
+
+	reqURL := fmt.Sprintf("/api/v2/insights/costs?%s", qp.Encode())
+	resp, err := c.Request(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return CostInsightsResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return CostInsightsResponse{}, ReadBodyAsError(resp)
+	}
+	var result CostInsightsResponse
+	return result, json.NewDecoder(resp.Body).Decode(&result)
+}
+
+// NetworkClientType is the type of a tailnet client reporting network
+// insights.
+type NetworkClientType string
+
+// NetworkClientType enums.
+const (
+	NetworkClientTypeAgent   NetworkClientType = "agent"
+	NetworkClientTypeCLI     NetworkClientType = "cli"
+	NetworkClientTypeCoderd  NetworkClientType = "coderd"
+	NetworkClientTypeWSProxy NetworkClientType = "wsproxy"
+)
+
+func (e NetworkClientType) Valid() bool {
+	switch e {
+	case NetworkClientTypeAgent, NetworkClientTypeCLI,
+		NetworkClientTypeCoderd, NetworkClientTypeWSProxy:
+		return true
+	}
+	return false
+}
+
+// NetworkInsightsResponse is how clients across the deployment reach each
+// DERP region, based on the reports of the last day.
+type NetworkInsightsResponse struct {
+	ReportedAfter time.Time               `json:"reported_after" format:"date-time"`
+	ClientTypes   []NetworkClientType     `json:"client_types"`
+	Regions       []NetworkInsightsRegion `json:"regions"`
+}
+
+// NetworkInsightsRegion is the latency clients reported to a DERP region, and
+// the type of the connections of the clients it's the home region of.
+// Latencies are -1 when no client reported a latency to the region.
+type NetworkInsightsRegion struct {
+	RegionID           int     `json:"region_id" example:"999"`
+	RegionCode         string  `json:"region_code" example:"coder"`
+	RegionName         string  `json:"region_name" example:"Coder Embedded Relay"`
+	Clients            int64   `json:"clients" example:"42"`
+	PreferredClients   int64   `json:"preferred_clients" example:"30"`
+	LatencyP50Ms       float64 `json:"latency_p50_ms" example:"25.5"`
+	LatencyP95Ms       float64 `json:"latency_p95_ms" example:"80"`
+	DirectConnections  int64   `json:"direct_connections" example:"60"`
+	RelayedConnections int64   `json:"relayed_connections" example:"15"`
+}
+
+type NetworkInsightsRequest struct {
+	ClientTypes []NetworkClientType `json:"client_types"`
+}
+
+func (c *Client) NetworkInsights(ctx context.Context, req NetworkInsightsRequest) (NetworkInsightsResponse, error) {
+	qp := url.Values{}
+	if len(req.ClientTypes) > 0 {
+		var clientTypes []string
+		for _, clientType := range req.ClientTypes {
+			clientTypes = append(clientTypes, string(clientType))
+		}
+		qp.Add("client_types", strings.Join(clientTypes, ","))
+	}
+
+	reqURL := fmt.Sprintf("/api/v2/insights/network?%s", qp.Encode())
+	resp, err := c.Request(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return NetworkInsightsResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return NetworkInsightsResponse{}, ReadBodyAsError(resp)
+	}
+	var result NetworkInsightsResponse
+	return result, json.NewDecoder(resp.Body).Decode(&result)
+}
@@ -36,7 +36,10 @@ type NotificationMethodsResponse struct {
 type NotificationPreference struct {
 	NotificationTemplateID uuid.UUID `json:"id" format:"uuid"`
 	Disabled               bool      `json:"disabled"`
-	UpdatedAt              time.Time `json:"updated_at" format:"date-time"`
+	// Method is the delivery method the user opted into for this notification template.
+	// An empty value defers to the template-level or deployment-level method.
+	Method    string    `json:"method,omitempty"`
+	UpdatedAt time.Time `json:"updated_at" format:"date-time"`
 }
 
 // GetNotificationsSettings retrieves the notifications settings, which currently just describes whether all
@@ -199,4 +202,7 @@ type UpdateNotificationTemplateMethod struct {
 
 type UpdateUserNotificationPreferences struct {
 	TemplateDisabledMap map[string]bool `json:"template_disabled_map"`
+	// TemplateMethodMap sets the delivery method per notification template, e.g. "slack" to receive a direct message.
+	// An empty method resets the preference.
+	TemplateMethodMap map[string]string `json:"template_method_map,omitempty"`
 }
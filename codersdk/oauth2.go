@@ -14,6 +14,12 @@ type OAuth2ProviderApp struct {
 	Name        string    `json:"name"`
 	CallbackURL string    `json:"callback_url"`
 	Icon        string    `json:"icon"`
+	// PublicClient apps cannot keep a secret, so they must use PKCE and may not
+	// use the client credentials grant.
+	PublicClient bool `json:"public_client"`
+	// ClientCredentialsUserID is the user tokens issued through the client
+	// credentials grant act as. The grant is disabled when it is unset.
+	ClientCredentialsUserID *uuid.UUID `json:"client_credentials_user_id,omitempty" format:"uuid"`
 
 	// Endpoints are included in the app response for easier discovery. The OAuth2
 	// spec does not have a defined place to find these (for comparison, OIDC has
@@ -70,9 +76,11 @@ func (c *Client) OAuth2ProviderApp(ctx context.Context, id uuid.UUID) (OAuth2Pro
 }
 
 type PostOAuth2ProviderAppRequest struct {
-	Name        string `json:"name" validate:"required,oauth2_app_name"`
-	CallbackURL string `json:"callback_url" validate:"required,http_url"`
-	Icon        string `json:"icon" validate:"omitempty"`
+	Name                    string     `json:"name" validate:"required,oauth2_app_name"`
+	CallbackURL             string     `json:"callback_url" validate:"required,http_url"`
+	Icon                    string     `json:"icon" validate:"omitempty"`
+	PublicClient            bool       `json:"public_client,omitempty"`
+	ClientCredentialsUserID *uuid.UUID `json:"client_credentials_user_id,omitempty" format:"uuid"`
 }
 
 // PostOAuth2ProviderApp adds an application that can authenticate using Coder
@@ -91,9 +99,11 @@ func (c *Client) PostOAuth2ProviderApp(ctx context.Context, app PostOAuth2Provid
 }
 
 type PutOAuth2ProviderAppRequest struct {
-	Name        string `json:"name" validate:"required,oauth2_app_name"`
-	CallbackURL string `json:"callback_url" validate:"required,http_url"`
-	Icon        string `json:"icon" validate:"omitempty"`
+	Name                    string     `json:"name" validate:"required,oauth2_app_name"`
+	CallbackURL             string     `json:"callback_url" validate:"required,http_url"`
+	Icon                    string     `json:"icon" validate:"omitempty"`
+	PublicClient            bool       `json:"public_client,omitempty"`
+	ClientCredentialsUserID *uuid.UUID `json:"client_credentials_user_id,omitempty" format:"uuid"`
 }
 
 // PutOAuth2ProviderApp updates an application that can authenticate using Coder
@@ -185,11 +195,31 @@ type OAuth2ProviderGrantType string
 const (
 	OAuth2ProviderGrantTypeAuthorizationCode OAuth2ProviderGrantType = "authorization_code"
 	OAuth2ProviderGrantTypeRefreshToken      OAuth2ProviderGrantType = "refresh_token"
+	OAuth2ProviderGrantTypeClientCredentials OAuth2ProviderGrantType = "client_credentials"
+	OAuth2ProviderGrantTypeDeviceCode        OAuth2ProviderGrantType = "urn:ietf:params:oauth:grant-type:device_code"
 )
 
 func (e OAuth2ProviderGrantType) Valid() bool {
 	switch e {
-	case OAuth2ProviderGrantTypeAuthorizationCode, OAuth2ProviderGrantTypeRefreshToken:
+	case OAuth2ProviderGrantTypeAuthorizationCode, OAuth2ProviderGrantTypeRefreshToken,
+		OAuth2ProviderGrantTypeClientCredentials, OAuth2ProviderGrantTypeDeviceCode:
+		return true
+	}
+	return false
+}
+
+type OAuth2PKCECodeChallengeMethod string
+
+const (
+	// OAuth2PKCECodeChallengeMethodS256 is the only supported method. The
+	// "plain" method is deliberately not supported.
+	OAuth2PKCECodeChallengeMethodS256 OAuth2PKCECodeChallengeMethod = "S256"
+)
+
+func (e OAuth2PKCECodeChallengeMethod) Valid() bool {
+	//nolint:gocritic,revive // More cases might be added later.
+	switch e {
+	case OAuth2PKCECodeChallengeMethodS256:
 		return true
 	}
 	return false
@@ -210,6 +240,48 @@ func (e OAuth2ProviderResponseType) Valid() bool {
 	return false
 }
 
+// OAuth2DeviceAuthorizationResponse is returned when a device starts the
+// device authorization grant, as described in RFC 8628.
+type OAuth2DeviceAuthorizationResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	// ExpiresIn is the lifetime of the device and user codes in seconds.
+	ExpiresIn int64 `json:"expires_in"`
+	// Interval is the minimum number of seconds the device should wait between
+	// polling requests to the token endpoint.
+	Interval int64 `json:"interval"`
+}
+
+// OAuth2AuthorizationServerMetadata is served from
+// /.well-known/oauth-authorization-server, as described in RFC 8414.
+type OAuth2AuthorizationServerMetadata struct {
+	Issuer                            string                          `json:"issuer"`
+	AuthorizationEndpoint             string                          `json:"authorization_endpoint"`
+	TokenEndpoint                     string                          `json:"token_endpoint"`
+	DeviceAuthorizationEndpoint       string                          `json:"device_authorization_endpoint"`
+	ResponseTypesSupported            []OAuth2ProviderResponseType    `json:"response_types_supported"`
+	GrantTypesSupported               []OAuth2ProviderGrantType       `json:"grant_types_supported"`
+	CodeChallengeMethodsSupported     []OAuth2PKCECodeChallengeMethod `json:"code_challenge_methods_supported"`
+	TokenEndpointAuthMethodsSupported []string                        `json:"token_endpoint_auth_methods_supported"`
+}
+
+// OAuth2AuthorizationServerMetadata returns the metadata describing Coder's
+// OAuth2 provider endpoints and capabilities.
+func (c *Client) OAuth2AuthorizationServerMetadata(ctx context.Context) (OAuth2AuthorizationServerMetadata, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/.well-known/oauth-authorization-server", nil)
+	if err != nil {
+		return OAuth2AuthorizationServerMetadata{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return OAuth2AuthorizationServerMetadata{}, ReadBodyAsError(res)
+	}
+	var resp OAuth2AuthorizationServerMetadata
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 // RevokeOAuth2ProviderApp completely revokes an app's access for the
 // authenticated user.
 func (c *Client) RevokeOAuth2ProviderApp(ctx context.Context, appID uuid.UUID) error {
@@ -152,6 +152,40 @@ type ProvisionerJobLog struct {
 	Output    string    `json:"output"`
 }
 
+type ProvisionerJobResourceChangeAction string
+
+const (
+	ProvisionerJobResourceChangeActionCreate  ProvisionerJobResourceChangeAction = "create"
+	ProvisionerJobResourceChangeActionUpdate  ProvisionerJobResourceChangeAction = "update"
+	ProvisionerJobResourceChangeActionReplace ProvisionerJobResourceChangeAction = "replace"
+	ProvisionerJobResourceChangeActionDelete  ProvisionerJobResourceChangeAction = "delete"
+)
+
+// ProvisionerJobResourceChange describes what the plan for a provisioner job
+// does to a single managed resource. Resources that don't change are omitted.
+type ProvisionerJobResourceChange struct {
+	JobID        uuid.UUID                          `json:"job_id" format:"uuid"`
+	Address      string                             `json:"address"`
+	ResourceType string                             `json:"resource_type"`
+	ResourceName string                             `json:"resource_name"`
+	Action       ProvisionerJobResourceChangeAction `json:"action" enums:"create,update,replace,delete"`
+	// Attributes lists the top-level attributes that change. It is only
+	// populated for updates and replacements.
+	Attributes []ProvisionerJobResourceAttributeChange `json:"attributes"`
+}
+
+// ProvisionerJobResourceAttributeChange describes a single attribute that
+// differs between the current and planned state of a resource. Before and
+// After are JSON encoded, and are never set for sensitive attributes.
+type ProvisionerJobResourceAttributeChange struct {
+	Path              string `json:"path"`
+	Before            string `json:"before,omitempty"`
+	After             string `json:"after,omitempty"`
+	Sensitive         bool   `json:"sensitive"`
+	AfterUnknown      bool   `json:"after_unknown"`
+	ForcesReplacement bool   `json:"forces_replacement"`
+}
+
 // provisionerJobLogsAfter streams logs that occurred after a specific time.
 func (c *Client) provisionerJobLogsAfter(ctx context.Context, path string, after int64) (<-chan ProvisionerJobLog, io.Closer, error) {
 	afterQuery := ""
@@ -0,0 +1,153 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Secret is a value that is injected into workspaces by the agent, as an
+// environment variable, a file, or both. Secrets belong to a user, an
+// organization or a template. The value of a secret is write-only and never
+// returned by the API.
+type Secret struct {
+	ID          uuid.UUID `json:"id" format:"uuid" table:"id"`
+	Name        string    `json:"name" table:"name,default_sort"`
+	Description string    `json:"description" table:"description"`
+	// EnvName is the name of the environment variable the secret is injected
+	// as.
+	EnvName string `json:"env_name,omitempty" table:"env"`
+	// FilePath is the path of the file the secret is written to in the
+	// workspace. Paths starting with "~/" are relative to the home directory
+	// of the agent user.
+	FilePath  string    `json:"file_path,omitempty" table:"file"`
+	CreatedAt time.Time `json:"created_at" format:"date-time" table:"created at"`
+	UpdatedAt time.Time `json:"updated_at" format:"date-time" table:"updated at"`
+}
+
+// CreateSecretRequest creates a secret. At least one of EnvName and FilePath
+// must be set.
+type CreateSecretRequest struct {
+	Name        string `json:"name" validate:"required,username"`
+	Description string `json:"description,omitempty"`
+	Value       string `json:"value" validate:"required"`
+	EnvName     string `json:"env_name,omitempty"`
+	FilePath    string `json:"file_path,omitempty"`
+}
+
+// UpdateSecretRequest updates the fields of a secret that are set.
+type UpdateSecretRequest struct {
+	Description *string `json:"description,omitempty"`
+	Value       *string `json:"value,omitempty"`
+	EnvName     *string `json:"env_name,omitempty"`
+	FilePath    *string `json:"file_path,omitempty"`
+}
+
+// UserSecrets returns the personal secrets of a user, which are injected into
+// all of their workspaces.
+func (c *Client) UserSecrets(ctx context.Context, user string) ([]Secret, error) {
+	return c.secrets(ctx, fmt.Sprintf("/api/v2/users/%s/secrets", user))
+}
+
+func (c *Client) CreateUserSecret(ctx context.Context, user string, req CreateSecretRequest) (Secret, error) {
+	return c.createSecret(ctx, fmt.Sprintf("/api/v2/users/%s/secrets", user), req)
+}
+
+func (c *Client) UpdateUserSecret(ctx context.Context, user string, name string, req UpdateSecretRequest) (Secret, error) {
+	return c.updateSecret(ctx, fmt.Sprintf("/api/v2/users/%s/secrets/%s", user, name), req)
+}
+
+func (c *Client) DeleteUserSecret(ctx context.Context, user string, name string) error {
+	return c.deleteSecret(ctx, fmt.Sprintf("/api/v2/users/%s/secrets/%s", user, name))
+}
+
+// OrganizationSecrets returns the secrets of an organization, which are
+// injected into all workspaces of the organization.
+func (c *Client) OrganizationSecrets(ctx context.Context, organizationID uuid.UUID) ([]Secret, error) {
+	return c.secrets(ctx, fmt.Sprintf("/api/v2/organizations/%s/secrets", organizationID))
+}
+
+func (c *Client) CreateOrganizationSecret(ctx context.Context, organizationID uuid.UUID, req CreateSecretRequest) (Secret, error) {
+	return c.createSecret(ctx, fmt.Sprintf("/api/v2/organizations/%s/secrets", organizationID), req)
+}
+
+func (c *Client) UpdateOrganizationSecret(ctx context.Context, organizationID uuid.UUID, name string, req UpdateSecretRequest) (Secret, error) {
+	return c.updateSecret(ctx, fmt.Sprintf("/api/v2/organizations/%s/secrets/%s", organizationID, name), req)
+}
+
+func (c *Client) DeleteOrganizationSecret(ctx context.Context, organizationID uuid.UUID, name string) error {
+	return c.deleteSecret(ctx, fmt.Sprintf("/api/v2/organizations/%s/secrets/%s", organizationID, name))
+}
+
+// TemplateSecrets returns the secrets of a template, which are injected into
+// all workspaces created from the template.
+func (c *Client) TemplateSecrets(ctx context.Context, templateID uuid.UUID) ([]Secret, error) {
+	return c.secrets(ctx, fmt.Sprintf("/api/v2/templates/%s/secrets", templateID))
+}
+
+func (c *Client) CreateTemplateSecret(ctx context.Context, templateID uuid.UUID, req CreateSecretRequest) (Secret, error) {
+	return c.createSecret(ctx, fmt.Sprintf("/api/v2/templates/%s/secrets", templateID), req)
+}
+
+func (c *Client) UpdateTemplateSecret(ctx context.Context, templateID uuid.UUID, name string, req UpdateSecretRequest) (Secret, error) {
+	return c.updateSecret(ctx, fmt.Sprintf("/api/v2/templates/%s/secrets/%s", templateID, name), req)
+}
+
+func (c *Client) DeleteTemplateSecret(ctx context.Context, templateID uuid.UUID, name string) error {
+	return c.deleteSecret(ctx, fmt.Sprintf("/api/v2/templates/%s/secrets/%s", templateID, name))
+}
+
+func (c *Client) secrets(ctx context.Context, path string) ([]Secret, error) {
+	res, err := c.Request(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var secrets []Secret
+	return secrets, json.NewDecoder(res.Body).Decode(&secrets)
+}
+
+func (c *Client) createSecret(ctx context.Context, path string, req CreateSecretRequest) (Secret, error) {
+	res, err := c.Request(ctx, http.MethodPost, path, req)
+	if err != nil {
+		return Secret{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return Secret{}, ReadBodyAsError(res)
+	}
+	var secret Secret
+	return secret, json.NewDecoder(res.Body).Decode(&secret)
+}
+
+func (c *Client) updateSecret(ctx context.Context, path string, req UpdateSecretRequest) (Secret, error) {
+	res, err := c.Request(ctx, http.MethodPatch, path, req)
+	if err != nil {
+		return Secret{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return Secret{}, ReadBodyAsError(res)
+	}
+	var secret Secret
+	return secret, json.NewDecoder(res.Body).Decode(&secret)
+}
+
+func (c *Client) deleteSecret(ctx context.Context, path string) error {
+	res, err := c.Request(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
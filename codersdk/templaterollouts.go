@@ -0,0 +1,132 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TemplateRolloutStatus string
+
+const (
+	TemplateRolloutStatusInProgress TemplateRolloutStatus = "in_progress"
+	TemplateRolloutStatusPromoted   TemplateRolloutStatus = "promoted"
+	TemplateRolloutStatusRolledBack TemplateRolloutStatus = "rolled_back"
+	TemplateRolloutStatusCanceled   TemplateRolloutStatus = "canceled"
+)
+
+// TemplateRollout is a staged rollout of a candidate template version. While
+// it's in progress, workspaces included in the rollout build the candidate
+// instead of the active version of the template.
+type TemplateRollout struct {
+	ID                uuid.UUID             `json:"id" format:"uuid"`
+	TemplateID        uuid.UUID             `json:"template_id" format:"uuid"`
+	TemplateVersionID uuid.UUID             `json:"template_version_id" format:"uuid"`
+	CreatedBy         uuid.UUID             `json:"created_by" format:"uuid"`
+	CreatedAt         time.Time             `json:"created_at" format:"date-time"`
+	UpdatedAt         time.Time             `json:"updated_at" format:"date-time"`
+	CompletedAt       *time.Time            `json:"completed_at,omitempty" format:"date-time"`
+	Status            TemplateRolloutStatus `json:"status" enums:"in_progress,promoted,rolled_back,canceled"`
+	StatusReason      string                `json:"status_reason"`
+	// Percent is the percentage of the workspaces of the template that build
+	// the candidate version.
+	Percent int32 `json:"percent"`
+	// GroupID is the group whose members' workspaces build the candidate
+	// version regardless of Percent.
+	GroupID *uuid.UUID `json:"group_id,omitempty" format:"uuid"`
+	// MinBuilds is the number of completed builds of the candidate version
+	// required before the rollout is promoted or rolled back automatically.
+	MinBuilds int32 `json:"min_builds"`
+	// MaxFailureRate is the share of failed builds of the candidate version,
+	// between 0 and 1, above which the rollout is rolled back.
+	MaxFailureRate float64 `json:"max_failure_rate"`
+	// AutoPromote promotes the candidate version to the active version once
+	// MinBuilds builds completed within MaxFailureRate.
+	AutoPromote bool `json:"auto_promote"`
+	// Builds and FailedBuilds count the completed start builds of the
+	// candidate version since the rollout started.
+	Builds       int64 `json:"builds"`
+	FailedBuilds int64 `json:"failed_builds"`
+}
+
+type CreateTemplateRolloutRequest struct {
+	TemplateVersionID uuid.UUID  `json:"template_version_id" validate:"required" format:"uuid"`
+	Percent           int32      `json:"percent" validate:"min=0,max=100"`
+	GroupID           *uuid.UUID `json:"group_id,omitempty" format:"uuid"`
+	MinBuilds         int32      `json:"min_builds" validate:"min=1"`
+	MaxFailureRate    float64    `json:"max_failure_rate" validate:"min=0,max=1"`
+	AutoPromote       bool       `json:"auto_promote"`
+}
+
+// UpdateTemplateRolloutRequest updates a rollout in progress. Unset fields are
+// left unchanged.
+type UpdateTemplateRolloutRequest struct {
+	Percent *int32 `json:"percent,omitempty" validate:"omitempty,min=0,max=100"`
+	// GroupID replaces the group of the rollout. The nil UUID removes it.
+	GroupID *uuid.UUID `json:"group_id,omitempty" format:"uuid"`
+	// Status completes the rollout. Promoting it makes the candidate the
+	// active version of the template.
+	Status *TemplateRolloutStatus `json:"status,omitempty" enums:"promoted,rolled_back,canceled"`
+	Reason string                 `json:"reason,omitempty"`
+}
+
+// CreateTemplateRollout starts a staged rollout of a template version.
+func (c *Client) CreateTemplateRollout(ctx context.Context, templateID uuid.UUID, req CreateTemplateRolloutRequest) (TemplateRollout, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/templates/%s/rollouts", templateID), req)
+	if err != nil {
+		return TemplateRollout{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return TemplateRollout{}, ReadBodyAsError(res)
+	}
+	var rollout TemplateRollout
+	return rollout, json.NewDecoder(res.Body).Decode(&rollout)
+}
+
+// TemplateRollouts returns the rollouts of a template, newest first.
+func (c *Client) TemplateRollouts(ctx context.Context, templateID uuid.UUID) ([]TemplateRollout, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/rollouts", templateID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var rollouts []TemplateRollout
+	return rollouts, json.NewDecoder(res.Body).Decode(&rollouts)
+}
+
+// TemplateRollout returns a rollout of a template.
+func (c *Client) TemplateRollout(ctx context.Context, templateID, rolloutID uuid.UUID) (TemplateRollout, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/rollouts/%s", templateID, rolloutID), nil)
+	if err != nil {
+		return TemplateRollout{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateRollout{}, ReadBodyAsError(res)
+	}
+	var rollout TemplateRollout
+	return rollout, json.NewDecoder(res.Body).Decode(&rollout)
+}
+
+// UpdateTemplateRollout widens, narrows, promotes, rolls back or cancels a
+// rollout in progress.
+func (c *Client) UpdateTemplateRollout(ctx context.Context, templateID, rolloutID uuid.UUID, req UpdateTemplateRolloutRequest) (TemplateRollout, error) {
+	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/templates/%s/rollouts/%s", templateID, rolloutID), req)
+	if err != nil {
+		return TemplateRollout{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateRollout{}, ReadBodyAsError(res)
+	}
+	var rollout TemplateRollout
+	return rollout, json.NewDecoder(res.Body).Decode(&rollout)
+}
@@ -61,6 +61,18 @@ type Template struct {
 	// template version.
 	RequireActiveVersion bool                         `json:"require_active_version"`
 	MaxPortShareLevel    WorkspaceAgentPortShareLevel `json:"max_port_share_level"`
+	// SessionRecordingEnabled records interactive SSH and terminal sessions
+	// in workspaces created from this template.
+	SessionRecordingEnabled bool `json:"session_recording_enabled"`
+	// RequireBuildApproval holds workspace start builds until they are
+	// approved by a template admin or a member of BuildApprovalGroupID.
+	RequireBuildApproval bool `json:"require_build_approval"`
+	// BuildApprovalParameters limits approvals to builds that change the
+	// value of one of these parameters. If empty, all start builds require
+	// approval.
+	BuildApprovalParameters    []string   `json:"build_approval_parameters"`
+	BuildApprovalGroupID       *uuid.UUID `json:"build_approval_group_id,omitempty" format:"uuid"`
+	BuildApprovalTimeoutMillis int64      `json:"build_approval_timeout_ms"`
 }
 
 // WeekdaysToBitmap converts a list of weekdays to a bitmap in accordance with
@@ -250,6 +262,19 @@ type UpdateTemplateMeta struct {
 	// of the template.
 	DisableEveryoneGroupAccess bool                          `json:"disable_everyone_group_access"`
 	MaxPortShareLevel          *WorkspaceAgentPortShareLevel `json:"max_port_share_level,omitempty"`
+	// SessionRecordingEnabled records interactive SSH and terminal sessions
+	// in workspaces created from this template. Leaving it unset keeps the
+	// current value.
+	SessionRecordingEnabled *bool `json:"session_recording_enabled,omitempty"`
+	// RequireBuildApproval, BuildApprovalParameters, BuildApprovalGroupID and
+	// BuildApprovalTimeoutMillis configure the build approval policy of the
+	// template. Leaving them unset keeps the current values. Setting
+	// BuildApprovalGroupID to uuid.Nil removes the approver group, so only
+	// template admins can approve builds.
+	RequireBuildApproval       *bool      `json:"require_build_approval,omitempty"`
+	BuildApprovalParameters    *[]string  `json:"build_approval_parameters,omitempty"`
+	BuildApprovalGroupID       *uuid.UUID `json:"build_approval_group_id,omitempty" format:"uuid"`
+	BuildApprovalTimeoutMillis *int64     `json:"build_approval_timeout_ms,omitempty"`
 }
 
 type TemplateExample struct {
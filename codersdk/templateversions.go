@@ -190,6 +190,10 @@ type CreateTemplateVersionDryRunRequest struct {
 	WorkspaceName       string                    `json:"workspace_name"`
 	RichParameterValues []WorkspaceBuildParameter `json:"rich_parameter_values"`
 	UserVariableValues  []VariableValue           `json:"user_variable_values,omitempty"`
+	// WorkspaceID previews updating an existing workspace to the template
+	// version. The dry-run plans against the workspace's current state, so
+	// its resource changes show what a build would actually do.
+	WorkspaceID uuid.UUID `json:"workspace_id,omitempty" format:"uuid"`
 }
 
 // CreateTemplateVersionDryRun begins a dry-run provisioner job against the
@@ -256,6 +260,22 @@ func (c *Client) TemplateVersionDryRunResources(ctx context.Context, version, jo
 	return resources, json.NewDecoder(res.Body).Decode(&resources)
 }
 
+// TemplateVersionDryRunResourceChanges returns the changes a finished
+// template version dry-run job would make to each resource.
+func (c *Client) TemplateVersionDryRunResourceChanges(ctx context.Context, version, job uuid.UUID) ([]ProvisionerJobResourceChange, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templateversions/%s/dry-run/%s/resource-changes", version, job), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+
+	var changes []ProvisionerJobResourceChange
+	return changes, json.NewDecoder(res.Body).Decode(&changes)
+}
+
 // TemplateVersionDryRunLogsAfter streams logs for a template version dry-run
 // that occurred after a specific log ID.
 func (c *Client) TemplateVersionDryRunLogsAfter(ctx context.Context, version, job uuid.UUID, after int64) (<-chan ProvisionerJobLog, io.Closer, error) {
@@ -0,0 +1,68 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// WorkloadIdentityProviderMetadata is the OpenID provider metadata of the
+// issuer of workload identity tokens. It is served from
+// /api/v2/workloadidentity/.well-known/openid-configuration, so third parties
+// such as cloud providers can discover the keys used to sign the tokens.
+type WorkloadIdentityProviderMetadata struct {
+	Issuer                           string   `json:"issuer"`
+	JWKSURI                          string   `json:"jwks_uri"`
+	ResponseTypesSupported           []string `json:"response_types_supported"`
+	SubjectTypesSupported            []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported []string `json:"id_token_signing_alg_values_supported"`
+	ClaimsSupported                  []string `json:"claims_supported"`
+}
+
+// WorkloadIdentityJWKS is the JSON Web Key Set, as described in RFC 7517,
+// containing the public keys used to sign workload identity tokens.
+type WorkloadIdentityJWKS struct {
+	Keys []WorkloadIdentityJWK `json:"keys"`
+}
+
+// WorkloadIdentityJWK is an elliptic curve public key used to verify workload
+// identity tokens.
+type WorkloadIdentityJWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	Y         string `json:"y"`
+}
+
+// WorkloadIdentityProviderMetadata returns the OpenID provider metadata of the
+// workload identity token issuer.
+func (c *Client) WorkloadIdentityProviderMetadata(ctx context.Context) (WorkloadIdentityProviderMetadata, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/workloadidentity/.well-known/openid-configuration", nil)
+	if err != nil {
+		return WorkloadIdentityProviderMetadata{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkloadIdentityProviderMetadata{}, ReadBodyAsError(res)
+	}
+	var resp WorkloadIdentityProviderMetadata
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// WorkloadIdentityJWKS returns the public keys used to sign workload identity
+// tokens.
+func (c *Client) WorkloadIdentityJWKS(ctx context.Context) (WorkloadIdentityJWKS, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/workloadidentity/jwks", nil)
+	if err != nil {
+		return WorkloadIdentityJWKS{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkloadIdentityJWKS{}, ReadBodyAsError(res)
+	}
+	var resp WorkloadIdentityJWKS
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
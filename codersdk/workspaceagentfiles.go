@@ -0,0 +1,138 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkspaceAgentFileInfo describes a file or directory in a workspace.
+type WorkspaceAgentFileInfo struct {
+	Name string `json:"name"`
+	// Path is the absolute path of the file in the workspace.
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+	// Mode is the file mode as a string, e.g. "-rw-r--r--".
+	Mode    string    `json:"mode"`
+	ModTime time.Time `json:"mod_time" format:"date-time"`
+	IsDir   bool      `json:"is_dir"`
+}
+
+// WorkspaceAgentListFilesResponse is the contents of a directory in a
+// workspace.
+type WorkspaceAgentListFilesResponse struct {
+	// Path is the absolute path of the directory in the workspace.
+	Path  string                   `json:"path"`
+	Files []WorkspaceAgentFileInfo `json:"files"`
+}
+
+// WorkspaceAgentListFiles lists the contents of a directory in the workspace.
+// Relative paths are resolved from the home directory of the agent user.
+func (c *Client) WorkspaceAgentListFiles(ctx context.Context, agentID uuid.UUID, path string) (WorkspaceAgentListFilesResponse, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/files/list", agentID), nil,
+		WithQueryParam("path", path),
+	)
+	if err != nil {
+		return WorkspaceAgentListFilesResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentListFilesResponse{}, ReadBodyAsError(res)
+	}
+	var resp WorkspaceAgentListFilesResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// WorkspaceAgentStatFile returns information about a file in the workspace.
+func (c *Client) WorkspaceAgentStatFile(ctx context.Context, agentID uuid.UUID, path string) (WorkspaceAgentFileInfo, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/files/stat", agentID), nil,
+		WithQueryParam("path", path),
+	)
+	if err != nil {
+		return WorkspaceAgentFileInfo{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentFileInfo{}, ReadBodyAsError(res)
+	}
+	var resp WorkspaceAgentFileInfo
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// WorkspaceAgentDownloadFile streams the contents of a file in the workspace.
+// The caller must close the returned reader.
+func (c *Client) WorkspaceAgentDownloadFile(ctx context.Context, agentID uuid.UUID, path string) (io.ReadCloser, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/files/download", agentID), nil,
+		WithQueryParam("path", path),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, ReadBodyAsError(res)
+	}
+	return res.Body, nil
+}
+
+// WorkspaceAgentUploadFile writes the contents of rd to a file in the
+// workspace, replacing it if it exists. The parent directory must exist.
+func (c *Client) WorkspaceAgentUploadFile(ctx context.Context, agentID uuid.UUID, path string, mode os.FileMode, rd io.Reader) (WorkspaceAgentFileInfo, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaceagents/%s/files/upload", agentID), rd,
+		WithQueryParam("path", path),
+		WithQueryParam("mode", strconv.FormatUint(uint64(mode.Perm()), 8)),
+		func(r *http.Request) {
+			r.Header.Set("Content-Type", "application/octet-stream")
+		},
+	)
+	if err != nil {
+		return WorkspaceAgentFileInfo{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return WorkspaceAgentFileInfo{}, ReadBodyAsError(res)
+	}
+	var resp WorkspaceAgentFileInfo
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// WorkspaceAgentMakeDirectory creates a directory, and any missing parents, in
+// the workspace.
+func (c *Client) WorkspaceAgentMakeDirectory(ctx context.Context, agentID uuid.UUID, path string) (WorkspaceAgentFileInfo, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaceagents/%s/files/mkdir", agentID), nil,
+		WithQueryParam("path", path),
+	)
+	if err != nil {
+		return WorkspaceAgentFileInfo{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return WorkspaceAgentFileInfo{}, ReadBodyAsError(res)
+	}
+	var resp WorkspaceAgentFileInfo
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// WorkspaceAgentDeleteFile deletes a file in the workspace. Directories are
+// only deleted if they are empty, unless recursive is set.
+func (c *Client) WorkspaceAgentDeleteFile(ctx context.Context, agentID uuid.UUID, path string, recursive bool) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/workspaceagents/%s/files", agentID), nil,
+		WithQueryParam("path", path),
+		WithQueryParam("recursive", strconv.FormatBool(recursive)),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
@@ -377,6 +377,48 @@ type WorkspaceAgentListeningPort struct {
 	Port        uint16 `json:"port"`
 }
 
+type WorkspaceAgentDevcontainerStatus string
+
+const (
+	WorkspaceAgentDevcontainerStatusStarting WorkspaceAgentDevcontainerStatus = "starting"
+	WorkspaceAgentDevcontainerStatusRunning  WorkspaceAgentDevcontainerStatus = "running"
+	WorkspaceAgentDevcontainerStatusError    WorkspaceAgentDevcontainerStatus = "error"
+)
+
+type WorkspaceAgentDevcontainersResponse struct {
+	Devcontainers []WorkspaceAgentDevcontainer `json:"devcontainers"`
+}
+
+// WorkspaceAgentDevcontainer is a dev container started by the agent from a
+// devcontainer.json in one of its project directories. The name can be used
+// as a target, e.g. "coder ssh workspace.name".
+type WorkspaceAgentDevcontainer struct {
+	Name            string                           `json:"name"`
+	LocalFolder     string                           `json:"local_folder"`
+	ConfigPath      string                           `json:"config_path"`
+	WorkspaceFolder string                           `json:"workspace_folder"`
+	ContainerID     string                           `json:"container_id,omitempty"`
+	Status          WorkspaceAgentDevcontainerStatus `json:"status"`
+	Error           string                           `json:"error,omitempty"`
+}
+
+// WorkspaceAgentProcessGroup is the resource usage of a group of processes
+// started by the agent, e.g. "sessions" or "scripts". It is only reported by
+// agents that manage cgroups.
+type WorkspaceAgentProcessGroup struct {
+	Name         string `json:"name"`
+	CPUUsageUsec int64  `json:"cpu_usage_usec"`
+	// CPUCores is the average number of CPUs used since the previous report.
+	CPUCores float64 `json:"cpu_cores"`
+	// CPULimitCores is zero if the group has no CPU limit.
+	CPULimitCores float64 `json:"cpu_limit_cores"`
+	MemoryBytes   int64   `json:"memory_bytes"`
+	// MemoryLimitBytes is zero if the group has no memory limit.
+	MemoryLimitBytes int64     `json:"memory_limit_bytes"`
+	OOMKills         int64     `json:"oom_kills"`
+	UpdatedAt        time.Time `json:"updated_at" format:"date-time"`
+}
+
 // WorkspaceAgentListeningPorts returns a list of ports that are currently being
 // listened on inside the workspace agent's network namespace.
 func (c *Client) WorkspaceAgentListeningPorts(ctx context.Context, agentID uuid.UUID) (WorkspaceAgentListeningPortsResponse, error) {
@@ -392,6 +434,21 @@ func (c *Client) WorkspaceAgentListeningPorts(ctx context.Context, agentID uuid.
 	return listeningPorts, json.NewDecoder(res.Body).Decode(&listeningPorts)
 }
 
+// WorkspaceAgentProcessGroups returns the latest resource usage reported for
+// each process group of the agent.
+func (c *Client) WorkspaceAgentProcessGroups(ctx context.Context, agentID uuid.UUID) ([]WorkspaceAgentProcessGroup, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/process-groups", agentID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var groups []WorkspaceAgentProcessGroup
+	return groups, json.NewDecoder(res.Body).Decode(&groups)
+}
+
 //nolint:revive // Follow is a control flag on the server as well.
 func (c *Client) WorkspaceAgentLogsAfter(ctx context.Context, agentID uuid.UUID, after int64, follow bool) (<-chan []WorkspaceAgentLog, io.Closer, error) {
 	var queryParams []string
@@ -0,0 +1,87 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WorkspaceBuildApprovalStatus string
+
+const (
+	WorkspaceBuildApprovalStatusPending  WorkspaceBuildApprovalStatus = "pending"
+	WorkspaceBuildApprovalStatusApproved WorkspaceBuildApprovalStatus = "approved"
+	WorkspaceBuildApprovalStatusRejected WorkspaceBuildApprovalStatus = "rejected"
+)
+
+// WorkspaceBuildApproval is the approval state of a workspace build on a
+// template that requires builds to be approved. The build is not queued for
+// provisioners until it is approved.
+type WorkspaceBuildApproval struct {
+	WorkspaceBuildID uuid.UUID                    `json:"workspace_build_id" format:"uuid"`
+	WorkspaceID      uuid.UUID                    `json:"workspace_id" format:"uuid"`
+	Status           WorkspaceBuildApprovalStatus `json:"status" enums:"pending,approved,rejected"`
+	CreatedAt        time.Time                    `json:"created_at" format:"date-time"`
+	// ExpiresAt is the time after which a pending build is rejected. It is
+	// nil if the build never times out.
+	ExpiresAt *time.Time `json:"expires_at,omitempty" format:"date-time"`
+	// ReviewerID is nil while the build is pending, or if it was rejected
+	// because it timed out.
+	ReviewerID *uuid.UUID `json:"reviewer_id,omitempty" format:"uuid"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty" format:"date-time"`
+	Reason     string     `json:"reason"`
+}
+
+type ReviewWorkspaceBuildApprovalRequest struct {
+	Approved bool   `json:"approved"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// WorkspaceBuildApproval returns the approval of a workspace build.
+func (c *Client) WorkspaceBuildApproval(ctx context.Context, buildID uuid.UUID) (WorkspaceBuildApproval, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspacebuilds/%s/approval", buildID), nil)
+	if err != nil {
+		return WorkspaceBuildApproval{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceBuildApproval{}, ReadBodyAsError(res)
+	}
+	var approval WorkspaceBuildApproval
+	return approval, json.NewDecoder(res.Body).Decode(&approval)
+}
+
+// ReviewTemplateBuildApproval approves or rejects a pending workspace build of
+// a template. Approved builds are queued for provisioners, rejected builds
+// fail with the reason given by the reviewer.
+func (c *Client) ReviewTemplateBuildApproval(ctx context.Context, templateID, buildID uuid.UUID, req ReviewWorkspaceBuildApprovalRequest) (WorkspaceBuildApproval, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/templates/%s/build-approvals/%s", templateID, buildID), req)
+	if err != nil {
+		return WorkspaceBuildApproval{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceBuildApproval{}, ReadBodyAsError(res)
+	}
+	var approval WorkspaceBuildApproval
+	return approval, json.NewDecoder(res.Body).Decode(&approval)
+}
+
+// TemplateBuildApprovals returns the pending build approvals of a template,
+// oldest first.
+func (c *Client) TemplateBuildApprovals(ctx context.Context, templateID uuid.UUID) ([]WorkspaceBuildApproval, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/build-approvals", templateID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var approvals []WorkspaceBuildApproval
+	return approvals, json.NewDecoder(res.Body).Decode(&approvals)
+}
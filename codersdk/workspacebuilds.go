@@ -46,6 +46,9 @@ const (
 	// "autostop" is used when a build to stop a workspace is triggered by Autostop.
 	// The initiator id/username in this case is the workspace owner and can be ignored.
 	BuildReasonAutostop BuildReason = "autostop"
+	// "scheduled" is used when a build is triggered by a scheduled action of the
+	// workspace. The initiator id/username in this case is the workspace owner and can be ignored.
+	BuildReasonScheduled BuildReason = "scheduled"
 )
 
 // WorkspaceBuild is an at-point representation of a workspace state.
@@ -66,7 +69,7 @@ type WorkspaceBuild struct {
 	InitiatorID             uuid.UUID            `json:"initiator_id" format:"uuid"`
 	InitiatorUsername       string               `json:"initiator_name"`
 	Job                     ProvisionerJob       `json:"job"`
-	Reason                  BuildReason          `db:"reason" json:"reason" enums:"initiator,autostart,autostop"`
+	Reason                  BuildReason          `db:"reason" json:"reason" enums:"initiator,autostart,autostop,scheduled"`
 	Resources               []WorkspaceResource  `json:"resources"`
 	Deadline                NullTime             `json:"deadline,omitempty" format:"date-time"`
 	MaxDeadline             NullTime             `json:"max_deadline,omitempty" format:"date-time"`
@@ -89,6 +92,7 @@ type WorkspaceResource struct {
 	Agents     []WorkspaceAgent            `json:"agents,omitempty"`
 	Metadata   []WorkspaceResourceMetadata `json:"metadata,omitempty"`
 	DailyCost  int32                       `json:"daily_cost"`
+	HourlyCost float64                     `json:"hourly_cost"`
 }
 
 // WorkspaceResourceMetadata annotates the workspace resource with custom key-value pairs.
@@ -242,3 +246,19 @@ func (c *Client) WorkspaceBuildTimings(ctx context.Context, build uuid.UUID) (Wo
 	var timings WorkspaceBuildTimings
 	return timings, json.NewDecoder(res.Body).Decode(&timings)
 }
+
+// WorkspaceBuildResourceChanges returns the changes the build made to each
+// resource.
+func (c *Client) WorkspaceBuildResourceChanges(ctx context.Context, build uuid.UUID) ([]ProvisionerJobResourceChange, error) {
+	path := fmt.Sprintf("/api/v2/workspacebuilds/%s/resource-changes", build.String())
+	res, err := c.Request(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var changes []ProvisionerJobResourceChange
+	return changes, json.NewDecoder(res.Body).Decode(&changes)
+}
@@ -95,8 +95,13 @@ const (
 type CreateWorkspaceBuildRequest struct {
 	TemplateVersionID uuid.UUID           `json:"template_version_id,omitempty" format:"uuid"`
 	Transition        WorkspaceTransition `json:"transition" validate:"oneof=start stop delete,required"`
-	DryRun            bool                `json:"dry_run,omitempty"`
-	ProvisionerState  []byte              `json:"state,omitempty"`
+	// ActiveVersion updates the workspace to the active version of the
+	// template, or to the candidate version of the rollout in progress if the
+	// workspace is included in it. It cannot be set alongside
+	// TemplateVersionID.
+	ActiveVersion    bool   `json:"active_version,omitempty"`
+	DryRun           bool   `json:"dry_run,omitempty"`
+	ProvisionerState []byte `json:"state,omitempty"`
 	// Orphan may be set for the Destroy transition.
 	Orphan bool `json:"orphan,omitempty"`
 	// ParameterValues are optional. It will write params to the 'workspace' scope.
@@ -0,0 +1,91 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WorkspaceScheduledActionType string
+
+const (
+	// WorkspaceScheduledActionTypeStart starts the workspace if it is stopped.
+	WorkspaceScheduledActionTypeStart WorkspaceScheduledActionType = "start"
+	// WorkspaceScheduledActionTypeStop stops the workspace if it is running,
+	// regardless of activity.
+	WorkspaceScheduledActionTypeStop WorkspaceScheduledActionType = "stop"
+	// WorkspaceScheduledActionTypeRestart stops the workspace if it is
+	// running, then starts it again.
+	WorkspaceScheduledActionTypeRestart WorkspaceScheduledActionType = "restart"
+	// WorkspaceScheduledActionTypeUpdate updates the workspace to the active
+	// version of its template if it is outdated. The workspace is left in
+	// the state it was in.
+	WorkspaceScheduledActionTypeUpdate WorkspaceScheduledActionType = "update"
+)
+
+// WorkspaceScheduledAction is a user-defined schedule that builds a
+// workspace.
+type WorkspaceScheduledAction struct {
+	ID          uuid.UUID                    `json:"id" format:"uuid"`
+	WorkspaceID uuid.UUID                    `json:"workspace_id" format:"uuid"`
+	CreatedAt   time.Time                    `json:"created_at" format:"date-time"`
+	Action      WorkspaceScheduledActionType `json:"action" enums:"start,stop,restart,update"`
+	// Schedule is a cron expression in the same format as the autostart
+	// schedule of a workspace.
+	Schedule  string     `json:"schedule"`
+	NextRunAt time.Time  `json:"next_run_at" format:"date-time"`
+	LastRunAt *time.Time `json:"last_run_at,omitempty" format:"date-time"`
+}
+
+type CreateWorkspaceScheduledActionRequest struct {
+	Action WorkspaceScheduledActionType `json:"action" validate:"required" enums:"start,stop,restart,update"`
+	// Schedule is a cron expression with an optional CRON_TZ prefix, e.g.
+	// "CRON_TZ=Europe/Dublin 0 6 * * 1". The day-of-month and month fields
+	// must be "*".
+	Schedule string `json:"schedule" validate:"required"`
+}
+
+// WorkspaceScheduledActions returns the scheduled actions of a workspace.
+func (c *Client) WorkspaceScheduledActions(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceScheduledAction, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/scheduled-actions", workspaceID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var actions []WorkspaceScheduledAction
+	return actions, json.NewDecoder(res.Body).Decode(&actions)
+}
+
+// CreateWorkspaceScheduledAction schedules an action to run on a workspace.
+func (c *Client) CreateWorkspaceScheduledAction(ctx context.Context, workspaceID uuid.UUID, req CreateWorkspaceScheduledActionRequest) (WorkspaceScheduledAction, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/scheduled-actions", workspaceID), req)
+	if err != nil {
+		return WorkspaceScheduledAction{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return WorkspaceScheduledAction{}, ReadBodyAsError(res)
+	}
+	var action WorkspaceScheduledAction
+	return action, json.NewDecoder(res.Body).Decode(&action)
+}
+
+// DeleteWorkspaceScheduledAction deletes a scheduled action of a workspace.
+func (c *Client) DeleteWorkspaceScheduledAction(ctx context.Context, workspaceID, actionID uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/workspaces/%s/scheduled-actions/%s", workspaceID, actionID), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
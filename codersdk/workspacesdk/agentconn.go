@@ -9,6 +9,7 @@ import (
 	"net"
 	"net/http"
 	"net/netip"
+	"net/url"
 	"strconv"
 	"time"
 
@@ -154,6 +155,30 @@ func (c *AgentConn) SSH(ctx context.Context) (*gonet.TCPConn, error) {
 	return c.Conn.DialContextTCP(ctx, netip.AddrPortFrom(c.agentAddress(), AgentSSHPort))
 }
 
+// ContainerSSH pipes the SSH protocol to the named dev container of the
+// agent. This connects to the built-in SSH server in the workspace agent,
+// which runs the sessions of the connection inside the container.
+func (c *AgentConn) ContainerSSH(ctx context.Context, container string) (*gonet.TCPConn, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+
+	if !c.AwaitReachable(ctx) {
+		return nil, xerrors.Errorf("workspace agent not reachable in time: %v", ctx.Err())
+	}
+
+	c.Conn.SendConnectedTelemetry(c.agentAddress(), tailnet.TelemetryApplicationSSH)
+	conn, err := c.Conn.DialContextTCP(ctx, netip.AddrPortFrom(c.agentAddress(), AgentContainerSSHPort))
+	if err != nil {
+		return nil, err
+	}
+	// The agent reads the name of the container before the SSH protocol.
+	if _, err := conn.Write([]byte(container + "\n")); err != nil {
+		_ = conn.Close()
+		return nil, xerrors.Errorf("write container name: %w", err)
+	}
+	return conn, nil
+}
+
 // SSHClient calls SSH to create a client that uses a weak cipher
 // to improve throughput.
 func (c *AgentConn) SSHClient(ctx context.Context) (*ssh.Client, error) {
@@ -242,6 +267,23 @@ func (c *AgentConn) ListeningPorts(ctx context.Context) (codersdk.WorkspaceAgent
 	return resp, json.NewDecoder(res.Body).Decode(&resp)
 }
 
+// ListDevcontainers lists the dev containers started by the agent.
+func (c *AgentConn) ListDevcontainers(ctx context.Context) (codersdk.WorkspaceAgentDevcontainersResponse, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodGet, "/api/v0/containers", nil)
+	if err != nil {
+		return codersdk.WorkspaceAgentDevcontainersResponse{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return codersdk.WorkspaceAgentDevcontainersResponse{}, codersdk.ReadBodyAsError(res)
+	}
+
+	var resp codersdk.WorkspaceAgentDevcontainersResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 // Netcheck returns a network check report from the workspace agent.
 func (c *AgentConn) Netcheck(ctx context.Context) (healthsdk.AgentNetcheckReport, error) {
 	ctx, span := tracing.StartSpan(ctx)
@@ -336,8 +378,127 @@ func (c *AgentConn) PrometheusMetrics(ctx context.Context) ([]byte, error) {
 	return bs, nil
 }
 
+// ListFiles lists the contents of a directory in the workspace.
+func (c *AgentConn) ListFiles(ctx context.Context, path string) (codersdk.WorkspaceAgentListFilesResponse, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodGet, filesAPIPath("/list", path, nil), nil)
+	if err != nil {
+		return codersdk.WorkspaceAgentListFilesResponse{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return codersdk.WorkspaceAgentListFilesResponse{}, codersdk.ReadBodyAsError(res)
+	}
+
+	var resp codersdk.WorkspaceAgentListFilesResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// StatFile returns information about a file in the workspace.
+func (c *AgentConn) StatFile(ctx context.Context, path string) (codersdk.WorkspaceAgentFileInfo, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodGet, filesAPIPath("/stat", path, nil), nil)
+	if err != nil {
+		return codersdk.WorkspaceAgentFileInfo{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return codersdk.WorkspaceAgentFileInfo{}, codersdk.ReadBodyAsError(res)
+	}
+
+	var resp codersdk.WorkspaceAgentFileInfo
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// DownloadFile requests the contents of a file in the workspace. Range and
+// conditional headers in header are forwarded to the agent, so the response
+// may be a partial (206) or not modified (304) response. The caller must close
+// the response body.
+func (c *AgentConn) DownloadFile(ctx context.Context, path string, header http.Header) (*http.Response, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodGet, filesAPIPath("/download", path, nil), nil, func(r *http.Request) {
+		for _, key := range []string{"Range", "If-Range", "If-Modified-Since", "If-None-Match"} {
+			if value := header.Get(key); value != "" {
+				r.Header.Set(key, value)
+			}
+		}
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("do request: %w", err)
+	}
+	if res.StatusCode >= http.StatusBadRequest {
+		defer res.Body.Close()
+		return nil, codersdk.ReadBodyAsError(res)
+	}
+	return res, nil
+}
+
+// UploadFile writes the contents of rd to a file in the workspace.
+func (c *AgentConn) UploadFile(ctx context.Context, path string, mode string, rd io.Reader) (codersdk.WorkspaceAgentFileInfo, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodPost, filesAPIPath("/upload", path, url.Values{"mode": {mode}}), rd)
+	if err != nil {
+		return codersdk.WorkspaceAgentFileInfo{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return codersdk.WorkspaceAgentFileInfo{}, codersdk.ReadBodyAsError(res)
+	}
+
+	var resp codersdk.WorkspaceAgentFileInfo
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// MakeDirectory creates a directory, and any missing parents, in the
+// workspace.
+func (c *AgentConn) MakeDirectory(ctx context.Context, path string) (codersdk.WorkspaceAgentFileInfo, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodPost, filesAPIPath("/mkdir", path, nil), nil)
+	if err != nil {
+		return codersdk.WorkspaceAgentFileInfo{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return codersdk.WorkspaceAgentFileInfo{}, codersdk.ReadBodyAsError(res)
+	}
+
+	var resp codersdk.WorkspaceAgentFileInfo
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// DeleteFile deletes a file in the workspace. Non-empty directories are only
+// deleted if recursive is set.
+func (c *AgentConn) DeleteFile(ctx context.Context, path string, recursive bool) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodDelete, filesAPIPath("", path, url.Values{"recursive": {strconv.FormatBool(recursive)}}), nil)
+	if err != nil {
+		return xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return codersdk.ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// filesAPIPath returns the path of a file API endpoint on the agent with the
+// given file path and extra query parameters.
+func filesAPIPath(endpoint, path string, query url.Values) string {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("path", path)
+	return "/api/v0/files" + endpoint + "?" + query.Encode()
+}
+
 // apiRequest makes a request to the workspace agent's HTTP API server.
-func (c *AgentConn) apiRequest(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
+func (c *AgentConn) apiRequest(ctx context.Context, method, path string, body io.Reader, opts ...func(*http.Request)) (*http.Response, error) {
 	ctx, span := tracing.StartSpan(ctx)
 	defer span.End()
 
@@ -348,6 +509,9 @@ func (c *AgentConn) apiRequest(ctx context.Context, method, path string, body io
 	if err != nil {
 		return nil, xerrors.Errorf("new http api request to %q: %w", url, err)
 	}
+	for _, opt := range opts {
+		opt(req)
+	}
 
 	return c.apiClient().Do(req)
 }
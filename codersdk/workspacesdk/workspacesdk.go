@@ -11,6 +11,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
@@ -34,10 +35,13 @@ const (
 	// AgentHTTPAPIServerPort serves a HTTP server with endpoints for e.g.
 	// gathering agent statistics.
 	AgentHTTPAPIServerPort = 4
+	// AgentContainerSSHPort serves SSH connections into the dev containers
+	// of the agent. See AgentConn.ContainerSSH.
+	AgentContainerSSHPort = tailnet.WorkspaceAgentContainerSSHPort
 
 	// AgentMinimumListeningPort is the minimum port that the listening-ports
 	// endpoint will return to the client, and the minimum port that is accepted
-	// by the proxy applications endpoint. Coder consumes ports 1-4 at the
+	// by the proxy applications endpoint. Coder consumes ports 1-5 at the
 	// moment, and we reserve some extra ports for future use. Port 9 and up are
 	// available for the user.
 	//
@@ -232,22 +236,27 @@ func (c *Client) DialAgent(dialCtx context.Context, agentID uuid.UUID, options *
 	if headerTransport, ok := c.client.HTTPClient.Transport.(*codersdk.HeaderTransport); ok {
 		header = headerTransport.Header
 	}
-	var telemetrySink tailnet.TelemetrySink
+	var (
+		telemetrySink         tailnet.TelemetrySink
+		networkReportInterval time.Duration
+	)
 	if options.EnableTelemetry {
 		basicTel := tailnet.NewBasicTelemetryController(options.Logger)
 		telemetrySink = basicTel
+		networkReportInterval = tailnet.DefaultNetworkReportInterval
 		controller.TelemetryCtrl = basicTel
 	}
 	conn, err := tailnet.NewConn(&tailnet.Options{
-		Addresses:           []netip.Prefix{netip.PrefixFrom(ip, 128)},
-		DERPMap:             connInfo.DERPMap,
-		DERPHeader:          &header,
-		DERPForceWebSockets: connInfo.DERPForceWebSockets,
-		Logger:              options.Logger,
-		BlockEndpoints:      c.client.DisableDirectConnections || options.BlockEndpoints,
-		CaptureHook:         options.CaptureHook,
-		ClientType:          proto.TelemetryEvent_CLI,
-		TelemetrySink:       telemetrySink,
+		Addresses:             []netip.Prefix{netip.PrefixFrom(ip, 128)},
+		DERPMap:               connInfo.DERPMap,
+		DERPHeader:            &header,
+		DERPForceWebSockets:   connInfo.DERPForceWebSockets,
+		Logger:                options.Logger,
+		BlockEndpoints:        c.client.DisableDirectConnections || options.BlockEndpoints,
+		CaptureHook:           options.CaptureHook,
+		ClientType:            proto.TelemetryEvent_CLI,
+		TelemetrySink:         telemetrySink,
+		NetworkReportInterval: networkReportInterval,
 	})
 	if err != nil {
 		return nil, xerrors.Errorf("create tailnet: %w", err)
@@ -0,0 +1,72 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WorkspaceSessionRecordingType string
+
+const (
+	// WorkspaceSessionRecordingTypeSSH is an interactive SSH session, e.g.
+	// from `coder ssh` or an IDE terminal.
+	WorkspaceSessionRecordingTypeSSH WorkspaceSessionRecordingType = "ssh"
+	// WorkspaceSessionRecordingTypeReconnectingPTY is a web terminal session.
+	WorkspaceSessionRecordingTypeReconnectingPTY WorkspaceSessionRecordingType = "reconnecting_pty"
+)
+
+// WorkspaceSessionRecording describes a recording of an interactive session
+// in a workspace. The recording itself is in the asciicast v2 format.
+type WorkspaceSessionRecording struct {
+	ID          uuid.UUID `json:"id" format:"uuid"`
+	WorkspaceID uuid.UUID `json:"workspace_id" format:"uuid"`
+	AgentID     uuid.UUID `json:"agent_id" format:"uuid"`
+	// WorkspaceOwnerID is the owner of the workspace when the session started.
+	// The agent does not know which user connected to it, so this is not
+	// necessarily the user who ran the session.
+	WorkspaceOwnerID uuid.UUID                     `json:"workspace_owner_id" format:"uuid"`
+	Type             WorkspaceSessionRecordingType `json:"type" enums:"ssh,reconnecting_pty"`
+	StartedAt        time.Time                     `json:"started_at" format:"date-time"`
+	// EndedAt is nil while the session is still running, or if the agent
+	// disconnected before uploading the end of the recording.
+	EndedAt *time.Time `json:"ended_at,omitempty" format:"date-time"`
+	// Size is the size of the recording in bytes.
+	Size int64 `json:"size"`
+}
+
+// WorkspaceSessionRecordings returns the session recordings of a workspace,
+// newest first.
+func (c *Client) WorkspaceSessionRecordings(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceSessionRecording, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/session-recordings", workspaceID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var recordings []WorkspaceSessionRecording
+	return recordings, json.NewDecoder(res.Body).Decode(&recordings)
+}
+
+// WorkspaceSessionRecordingCast returns the recording in the asciicast v2
+// format, which can be played back with e.g. `asciinema play`. Opening a
+// recording is recorded in the audit log. The caller must close the returned
+// reader.
+func (c *Client) WorkspaceSessionRecordingCast(ctx context.Context, workspaceID, recordingID uuid.UUID) (io.ReadCloser, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/session-recordings/%s", workspaceID, recordingID), nil)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, ReadBodyAsError(res)
+	}
+	return res.Body, nil
+}
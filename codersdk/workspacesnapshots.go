@@ -0,0 +1,91 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WorkspaceSnapshotStatus string
+
+const (
+	// WorkspaceSnapshotStatusPending means the build creating the snapshot
+	// has not completed yet.
+	WorkspaceSnapshotStatusPending WorkspaceSnapshotStatus = "pending"
+	// WorkspaceSnapshotStatusReady means the snapshot can be restored.
+	WorkspaceSnapshotStatusReady WorkspaceSnapshotStatus = "ready"
+	// WorkspaceSnapshotStatusFailed means the build creating the snapshot
+	// failed, or the template did not produce any snapshot artifacts.
+	WorkspaceSnapshotStatusFailed WorkspaceSnapshotStatus = "failed"
+)
+
+// WorkspaceSnapshot is a point-in-time copy of workspace data, such as a home
+// volume, created by the template's coder_workspace_snapshot resources.
+// Snapshots belong to a user and template rather than a single workspace, so
+// they can be restored into a workspace that replaces the original.
+type WorkspaceSnapshot struct {
+	ID          uuid.UUID               `json:"id" format:"uuid"`
+	CreatedAt   time.Time               `json:"created_at" format:"date-time"`
+	Name        string                  `json:"name"`
+	OwnerID     uuid.UUID               `json:"owner_id" format:"uuid"`
+	TemplateID  uuid.UUID               `json:"template_id" format:"uuid"`
+	WorkspaceID uuid.UUID               `json:"workspace_id" format:"uuid"`
+	BuildID     uuid.UUID               `json:"build_id" format:"uuid"`
+	Status      WorkspaceSnapshotStatus `json:"status" enums:"pending,ready,failed"`
+	// Artifacts maps the name of each coder_workspace_snapshot resource to
+	// the artifact it reported, e.g. a cloud volume snapshot ID.
+	Artifacts map[string]string `json:"artifacts"`
+}
+
+type CreateWorkspaceSnapshotRequest struct {
+	Name string `json:"name" validate:"workspace_name,required"`
+}
+
+// CreateWorkspaceSnapshot stops the workspace and snapshots its data. The
+// snapshot is ready once the returned build completes.
+func (c *Client) CreateWorkspaceSnapshot(ctx context.Context, workspaceID uuid.UUID, req CreateWorkspaceSnapshotRequest) (WorkspaceSnapshot, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/snapshots", workspaceID), req)
+	if err != nil {
+		return WorkspaceSnapshot{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return WorkspaceSnapshot{}, ReadBodyAsError(res)
+	}
+	var snapshot WorkspaceSnapshot
+	return snapshot, json.NewDecoder(res.Body).Decode(&snapshot)
+}
+
+// WorkspaceSnapshots returns the snapshots that can be restored into the
+// workspace, including those taken of other workspaces with the same owner
+// and template.
+func (c *Client) WorkspaceSnapshots(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceSnapshot, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/snapshots", workspaceID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var snapshots []WorkspaceSnapshot
+	return snapshots, json.NewDecoder(res.Body).Decode(&snapshots)
+}
+
+// RestoreWorkspaceSnapshot starts a stopped workspace from a snapshot.
+func (c *Client) RestoreWorkspaceSnapshot(ctx context.Context, workspaceID, snapshotID uuid.UUID) (WorkspaceBuild, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/snapshots/%s/restore", workspaceID, snapshotID), nil)
+	if err != nil {
+		return WorkspaceBuild{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return WorkspaceBuild{}, ReadBodyAsError(res)
+	}
+	var build WorkspaceBuild
+	return build, json.NewDecoder(res.Body).Decode(&build)
+}
@@ -0,0 +1,66 @@
+package backends
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/enterprise/audit"
+)
+
+// Event is the representation of an audit log sent to external systems by the
+// streaming backends. Field names mirror the audit log API so that consumers
+// can share parsing logic between the two.
+type Event struct {
+	ID               uuid.UUID       `json:"id"`
+	Time             time.Time       `json:"time"`
+	UserID           uuid.UUID       `json:"user_id"`
+	OrganizationID   uuid.UUID       `json:"organization_id"`
+	IP               string          `json:"ip"`
+	UserAgent        string          `json:"user_agent"`
+	ResourceType     string          `json:"resource_type"`
+	ResourceID       uuid.UUID       `json:"resource_id"`
+	ResourceTarget   string          `json:"resource_target"`
+	ResourceIcon     string          `json:"resource_icon"`
+	Action           string          `json:"action"`
+	Diff             json.RawMessage `json:"diff"`
+	StatusCode       int32           `json:"status_code"`
+	AdditionalFields json.RawMessage `json:"additional_fields"`
+	RequestID        uuid.UUID       `json:"request_id"`
+	Actor            *audit.Actor    `json:"actor,omitempty"`
+}
+
+// NewEvent converts an audit log into its streamed representation.
+func NewEvent(alog database.AuditLog, details audit.BackendDetails) Event {
+	ev := Event{
+		ID:               alog.ID,
+		Time:             alog.Time.UTC(),
+		UserID:           alog.UserID,
+		OrganizationID:   alog.OrganizationID,
+		UserAgent:        alog.UserAgent.String,
+		ResourceType:     string(alog.ResourceType),
+		ResourceID:       alog.ResourceID,
+		ResourceTarget:   alog.ResourceTarget,
+		ResourceIcon:     alog.ResourceIcon,
+		Action:           string(alog.Action),
+		Diff:             rawJSONOrNull(alog.Diff),
+		StatusCode:       alog.StatusCode,
+		AdditionalFields: rawJSONOrNull(alog.AdditionalFields),
+		RequestID:        alog.RequestID,
+		Actor:            details.Actor,
+	}
+	if alog.Ip.Valid {
+		ev.IP = alog.Ip.IPNet.IP.String()
+	}
+	return ev
+}
+
+// rawJSONOrNull avoids producing invalid JSON when a column is empty.
+func rawJSONOrNull(raw json.RawMessage) json.RawMessage {
+	if len(raw) == 0 || !json.Valid(raw) {
+		return json.RawMessage("null")
+	}
+	return raw
+}
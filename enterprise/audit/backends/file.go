@@ -0,0 +1,81 @@
+package backends
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"golang.org/x/xerrors"
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/enterprise/audit"
+)
+
+// FileOptions configures the rotating file backend.
+type FileOptions struct {
+	Path string
+	// MaxSizeMB is the size at which the file is rotated.
+	MaxSizeMB int
+	// MaxBackups is the number of rotated files to keep. Zero keeps all of
+	// them.
+	MaxBackups int
+}
+
+type fileBackend struct {
+	mu     sync.Mutex
+	w      *lumberjack.Logger
+	closed bool
+}
+
+// NewFile returns a backend that appends audit logs as JSON lines to a local
+// file, rotating it once it exceeds the configured size.
+func NewFile(opts FileOptions) (audit.Backend, error) {
+	if opts.Path == "" {
+		return nil, xerrors.New("path is required")
+	}
+	if opts.MaxSizeMB <= 0 {
+		opts.MaxSizeMB = 100
+	}
+	if opts.MaxBackups < 0 {
+		return nil, xerrors.Errorf("max backups must not be negative, got %d", opts.MaxBackups)
+	}
+	return &fileBackend{
+		w: &lumberjack.Logger{
+			Filename:   opts.Path,
+			MaxSize:    opts.MaxSizeMB,
+			MaxBackups: opts.MaxBackups,
+		},
+	}, nil
+}
+
+func (*fileBackend) Decision() audit.FilterDecision {
+	return audit.FilterDecisionExport
+}
+
+func (b *fileBackend) Export(_ context.Context, alog database.AuditLog, details audit.BackendDetails) error {
+	line, err := json.Marshal(NewEvent(alog, details))
+	if err != nil {
+		return xerrors.Errorf("marshal audit log: %w", err)
+	}
+	line = append(line, '\n')
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	// lumberjack re-opens the file on write, so guard against writes after
+	// Close.
+	if b.closed {
+		return xerrors.New("file backend is closed")
+	}
+	if _, err := b.w.Write(line); err != nil {
+		return xerrors.Errorf("write audit log: %w", err)
+	}
+	return nil
+}
+
+func (b *fileBackend) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	return b.w.Close()
+}
@@ -0,0 +1,75 @@
+package backends_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/enterprise/audit"
+	"github.com/coder/coder/v2/enterprise/audit/audittest"
+	"github.com/coder/coder/v2/enterprise/audit/backends"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestFileBackend(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		path := filepath.Join(t.TempDir(), "audit.log")
+		backend, err := backends.NewFile(backends.FileOptions{Path: path})
+		require.NoError(t, err)
+
+		var (
+			actor = &audit.Actor{Username: "alice"}
+			first = audittest.RandomLog()
+			other = audittest.RandomLog()
+		)
+		require.NoError(t, backend.Export(ctx, first, audit.BackendDetails{Actor: actor}))
+		require.NoError(t, backend.Export(ctx, other, audit.BackendDetails{}))
+		require.NoError(t, backend.(io.Closer).Close())
+
+		f, err := os.Open(path)
+		require.NoError(t, err)
+		defer f.Close()
+
+		var events []backends.Event
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var ev backends.Event
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &ev))
+			events = append(events, ev)
+		}
+		require.NoError(t, scanner.Err())
+		require.Len(t, events, 2)
+		require.Equal(t, first.ID, events[0].ID)
+		require.Equal(t, "127.0.0.1", events[0].IP)
+		require.Equal(t, "alice", events[0].Actor.Username)
+		require.Equal(t, other.ID, events[1].ID)
+		require.Nil(t, events[1].Actor)
+	})
+
+	t.Run("Closed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		backend, err := backends.NewFile(backends.FileOptions{Path: filepath.Join(t.TempDir(), "audit.log")})
+		require.NoError(t, err)
+		require.NoError(t, backend.(io.Closer).Close())
+		require.Error(t, backend.Export(ctx, audittest.RandomLog(), audit.BackendDetails{}))
+	})
+
+	t.Run("NoPath", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := backends.NewFile(backends.FileOptions{})
+		require.Error(t, err)
+	})
+}
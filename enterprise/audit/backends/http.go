@@ -0,0 +1,437 @@
+package backends
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/enterprise/audit"
+	"github.com/coder/quartz"
+)
+
+// HTTPFormat is the encoding of request bodies sent by the HTTP backend.
+type HTTPFormat string
+
+const (
+	// HTTPFormatNDJSON sends one JSON object per line.
+	HTTPFormatNDJSON HTTPFormat = "ndjson"
+	// HTTPFormatJSON sends a JSON array of objects.
+	HTTPFormatJSON HTTPFormat = "json"
+	// HTTPFormatSplunk sends concatenated Splunk HTTP Event Collector events.
+	HTTPFormatSplunk HTTPFormat = "splunk"
+)
+
+const (
+	httpSpoolExt       = ".json"
+	httpMaxRetries     = 3
+	httpRequestTimeout = 30 * time.Second
+	// httpRejectedExt is the extension of spool files whose batch was
+	// rejected by the endpoint. They are kept for inspection, but not resent.
+	httpRejectedExt = ".rejected"
+)
+
+// HTTPOptions configures the HTTP backend.
+type HTTPOptions struct {
+	URL     *url.URL
+	Headers http.Header
+	Format  HTTPFormat
+	// BatchSize is the maximum number of audit logs sent per request.
+	BatchSize int
+	// FlushInterval is the maximum time audit logs are buffered.
+	FlushInterval time.Duration
+	// SpoolDir stores batches which failed to send so they can be retried
+	// later, including after a restart. Batches which the endpoint rejected
+	// are stored there too, but not retried. Spooling is disabled if empty.
+	SpoolDir string
+	// RetryBackoff is the initial delay between attempts to send a batch.
+	RetryBackoff time.Duration
+	Client       *http.Client
+	Clock        quartz.Clock
+	Logger       slog.Logger
+}
+
+type httpBackend struct {
+	opts HTTPOptions
+	log  slog.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu     sync.RWMutex // Protects closed and sending on events.
+	closed bool
+	events chan Event
+}
+
+// ParseHTTPHeaders parses headers in the "Name: value" format.
+func ParseHTTPHeaders(raw []string) (http.Header, error) {
+	headers := http.Header{}
+	for _, h := range raw {
+		name, value, ok := strings.Cut(h, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, xerrors.Errorf("invalid header %q, expected the \"Name: value\" format", h)
+		}
+		headers.Add(name, strings.TrimSpace(value))
+	}
+	return headers, nil
+}
+
+// NewHTTP returns a backend that posts batches of audit logs to an HTTP
+// endpoint such as a Splunk HTTP Event Collector or a generic SIEM. Batches
+// which cannot be delivered after retrying are spooled to disk and resent on
+// a later flush. Audit logs which do not fit in the queue are spooled too, or
+// block the caller until there is room if spooling is disabled.
+func NewHTTP(opts HTTPOptions) (audit.Backend, error) {
+	if opts.URL == nil || opts.URL.String() == "" {
+		return nil, xerrors.New("url is required")
+	}
+	switch opts.Format {
+	case "":
+		opts.Format = HTTPFormatNDJSON
+	case HTTPFormatNDJSON, HTTPFormatJSON, HTTPFormatSplunk:
+	default:
+		return nil, xerrors.Errorf("unknown format %q, expected one of %q, %q or %q", opts.Format, HTTPFormatNDJSON, HTTPFormatJSON, HTTPFormatSplunk)
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = 5 * time.Second
+	}
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = time.Second
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: httpRequestTimeout}
+	}
+	if opts.Clock == nil {
+		opts.Clock = quartz.NewReal()
+	}
+	if opts.SpoolDir != "" {
+		err := os.MkdirAll(opts.SpoolDir, 0o700)
+		if err != nil {
+			return nil, xerrors.Errorf("create spool directory: %w", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b := &httpBackend{
+		opts:   opts,
+		log:    opts.Logger.Named("http_audit"),
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+		events: make(chan Event, opts.BatchSize*10),
+	}
+	go b.run()
+	return b, nil
+}
+
+func (*httpBackend) Decision() audit.FilterDecision {
+	return audit.FilterDecisionExport
+}
+
+func (b *httpBackend) Export(ctx context.Context, alog database.AuditLog, details audit.BackendDetails) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return xerrors.New("http backend is closed")
+	}
+	ev := NewEvent(alog, details)
+	select {
+	case b.events <- ev:
+		return nil
+	default:
+	}
+
+	// The endpoint is not keeping up. Audit logs must never be dropped, so
+	// spool the overflow to disk and let it be resent with the other spooled
+	// batches.
+	if b.opts.SpoolDir != "" {
+		b.log.Warn(ctx, "http audit queue is full, spooling audit log", slog.F("audit_log_id", alog.ID))
+		b.spool([]Event{ev})
+		return nil
+	}
+
+	// Without a spool directory, wait for the queue to drain.
+	b.log.Warn(ctx, "http audit queue is full, waiting to queue audit log", slog.F("audit_log_id", alog.ID))
+	select {
+	case b.events <- ev:
+		return nil
+	case <-ctx.Done():
+		return xerrors.Errorf("http audit queue is full: %w", ctx.Err())
+	}
+}
+
+func (b *httpBackend) run() {
+	defer close(b.done)
+
+	ticker := b.opts.Clock.NewTicker(b.opts.FlushInterval, "httpBackend", "flush")
+	defer ticker.Stop()
+
+	// Resend anything left over from a previous run.
+	b.drainSpool()
+
+	batch := make([]Event, 0, b.opts.BatchSize)
+	for {
+		select {
+		case ev, ok := <-b.events:
+			if !ok {
+				b.flush(batch)
+				return
+			}
+			batch = append(batch, ev)
+			if len(batch) >= b.opts.BatchSize {
+				b.flush(batch)
+				batch = make([]Event, 0, b.opts.BatchSize)
+			}
+		case <-ticker.C:
+			b.drainSpool()
+			if len(batch) > 0 {
+				b.flush(batch)
+				batch = make([]Event, 0, b.opts.BatchSize)
+			}
+		}
+	}
+}
+
+// flush sends a batch, retrying transient failures, and spools it if it still
+// could not be delivered. Batches which the endpoint rejected are spooled as
+// rejected, since resending them would fail again and block the spool.
+func (b *httpBackend) flush(batch []Event) {
+	if len(batch) == 0 {
+		return
+	}
+
+	backoff := b.opts.RetryBackoff
+	var (
+		err   error
+		retry bool
+	)
+	for attempt := 1; attempt <= httpMaxRetries; attempt++ {
+		retry, err = b.send(batch)
+		if err == nil {
+			return
+		}
+		if !retry || attempt == httpMaxRetries {
+			break
+		}
+		b.log.Debug(b.ctx, "retrying audit log batch", slog.F("attempt", attempt), slog.Error(err))
+		timer := b.opts.Clock.NewTimer(backoff, "httpBackend", "retry")
+		select {
+		case <-b.ctx.Done():
+			timer.Stop()
+			attempt = httpMaxRetries
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+
+	if !retry {
+		b.log.Error(b.ctx, "audit log batch rejected", slog.F("count", len(batch)), slog.Error(err))
+		b.spoolFile(batch, httpRejectedExt)
+		return
+	}
+	b.log.Warn(b.ctx, "send audit log batch", slog.F("count", len(batch)), slog.Error(err))
+	b.spool(batch)
+}
+
+// send reports whether a failed request may succeed if retried.
+func (b *httpBackend) send(batch []Event) (bool, error) {
+	body, err := b.encode(batch)
+	if err != nil {
+		return false, err
+	}
+
+	req, err := http.NewRequestWithContext(b.ctx, http.MethodPost, b.opts.URL.String(), bytes.NewReader(body))
+	if err != nil {
+		return false, xerrors.Errorf("create request: %w", err)
+	}
+	for name, values := range b.opts.Headers {
+		req.Header[name] = values
+	}
+	if req.Header.Get("Content-Type") == "" {
+		switch b.opts.Format {
+		case HTTPFormatNDJSON:
+			req.Header.Set("Content-Type", "application/x-ndjson")
+		default:
+			req.Header.Set("Content-Type", "application/json")
+		}
+	}
+
+	res, err := b.opts.Client.Do(req)
+	if err != nil {
+		return true, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 1<<16))
+
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return false, nil
+	}
+	retry := res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500
+	return retry, xerrors.Errorf("unexpected status code %d", res.StatusCode)
+}
+
+func (b *httpBackend) encode(batch []Event) ([]byte, error) {
+	var buf bytes.Buffer
+	switch b.opts.Format {
+	case HTTPFormatJSON:
+		err := json.NewEncoder(&buf).Encode(batch)
+		if err != nil {
+			return nil, xerrors.Errorf("encode batch: %w", err)
+		}
+	case HTTPFormatSplunk:
+		enc := json.NewEncoder(&buf)
+		for _, ev := range batch {
+			err := enc.Encode(struct {
+				Time       float64 `json:"time"`
+				Source     string  `json:"source"`
+				SourceType string  `json:"sourcetype"`
+				Event      Event   `json:"event"`
+			}{
+				Time:       float64(ev.Time.UnixMicro()) / 1e6,
+				Source:     "coder",
+				SourceType: "coder:audit",
+				Event:      ev,
+			})
+			if err != nil {
+				return nil, xerrors.Errorf("encode event: %w", err)
+			}
+		}
+	default:
+		enc := json.NewEncoder(&buf)
+		for _, ev := range batch {
+			err := enc.Encode(ev)
+			if err != nil {
+				return nil, xerrors.Errorf("encode event: %w", err)
+			}
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// spool writes a batch to the spool directory to be resent.
+func (b *httpBackend) spool(batch []Event) {
+	b.spoolFile(batch, httpSpoolExt)
+}
+
+// spoolFile writes a batch to the spool directory with the extension. Files
+// are written to a temporary name and renamed so a crash never leaves a
+// partial batch behind.
+func (b *httpBackend) spoolFile(batch []Event, ext string) {
+	if b.opts.SpoolDir == "" {
+		b.log.Error(b.ctx, "dropping audit logs, no spool directory is configured", slog.F("count", len(batch)))
+		return
+	}
+
+	data, err := json.Marshal(batch)
+	if err != nil {
+		b.log.Error(b.ctx, "marshal spooled audit logs", slog.Error(err))
+		return
+	}
+	// Names sort in the order batches were spooled.
+	name := fmt.Sprintf("%020d-%s%s", b.opts.Clock.Now().UnixNano(), uuid.NewString(), ext)
+	tmp, err := os.CreateTemp(b.opts.SpoolDir, ".tmp-*")
+	if err != nil {
+		b.log.Error(b.ctx, "create spool file", slog.Error(err))
+		return
+	}
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(b.opts.SpoolDir, name))
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		b.log.Error(b.ctx, "write spool file", slog.Error(err))
+	}
+}
+
+// drainSpool resends spooled batches in order, stopping at the first failure
+// that may succeed later so that ordering is preserved. Batches which the
+// endpoint rejected are renamed to the rejected extension and skipped.
+func (b *httpBackend) drainSpool() {
+	if b.opts.SpoolDir == "" {
+		return
+	}
+	entries, err := os.ReadDir(b.opts.SpoolDir)
+	if err != nil {
+		b.log.Error(b.ctx, "read spool directory", slog.Error(err))
+		return
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), httpSpoolExt) || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		path := filepath.Join(b.opts.SpoolDir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			b.log.Error(b.ctx, "read spool file", slog.F("path", path), slog.Error(err))
+			return
+		}
+		var batch []Event
+		err = json.Unmarshal(data, &batch)
+		if err != nil {
+			// A corrupt file would otherwise block the spool forever.
+			b.log.Error(b.ctx, "discarding corrupt spool file", slog.F("path", path), slog.Error(err))
+			_ = os.Remove(path)
+			continue
+		}
+		if retry, err := b.send(batch); err != nil {
+			if retry {
+				b.log.Debug(b.ctx, "spooled audit logs not yet deliverable", slog.Error(err))
+				return
+			}
+			b.log.Error(b.ctx, "spooled audit log batch rejected", slog.F("path", path), slog.F("count", len(batch)), slog.Error(err))
+			err = os.Rename(path, strings.TrimSuffix(path, httpSpoolExt)+httpRejectedExt)
+			if err != nil {
+				b.log.Error(b.ctx, "move rejected spool file", slog.F("path", path), slog.Error(err))
+				return
+			}
+			continue
+		}
+		_ = os.Remove(path)
+	}
+}
+
+// Close stops accepting audit logs and flushes those that are buffered. Any
+// that cannot be delivered are spooled.
+func (b *httpBackend) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	close(b.events)
+	b.mu.Unlock()
+
+	<-b.done
+	b.cancel()
+	return nil
+}
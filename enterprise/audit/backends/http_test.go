@@ -0,0 +1,399 @@
+package backends_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/enterprise/audit"
+	"github.com/coder/coder/v2/enterprise/audit/audittest"
+	"github.com/coder/coder/v2/enterprise/audit/backends"
+	"github.com/coder/coder/v2/testutil"
+)
+
+type httpSink struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (s *httpSink) handler(status func() int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		code := status()
+		if code == http.StatusOK {
+			s.mu.Lock()
+			s.requests = append(s.requests, r)
+			s.bodies = append(s.bodies, body)
+			s.mu.Unlock()
+		}
+		w.WriteHeader(code)
+	}
+}
+
+func (s *httpSink) events(t *testing.T) []backends.Event {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var events []backends.Event
+	for _, body := range s.bodies {
+		scanner := bufio.NewScanner(bytes.NewReader(body))
+		for scanner.Scan() {
+			var ev backends.Event
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &ev))
+			events = append(events, ev)
+		}
+	}
+	return events
+}
+
+func always(code int) func() int {
+	return func() int { return code }
+}
+
+func TestHTTPBackend(t *testing.T) {
+	t.Parallel()
+
+	t.Run("NDJSON", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		sink := &httpSink{}
+		srv := httptest.NewServer(sink.handler(always(http.StatusOK)))
+		defer srv.Close()
+
+		u, _ := url.Parse(srv.URL)
+		headers, err := backends.ParseHTTPHeaders([]string{"Authorization: Bearer secret"})
+		require.NoError(t, err)
+		backend, err := backends.NewHTTP(backends.HTTPOptions{
+			URL:           u,
+			Headers:       headers,
+			BatchSize:     2,
+			FlushInterval: time.Hour,
+			Logger:        slogtest.Make(t, nil),
+		})
+		require.NoError(t, err)
+
+		for i := 0; i < 3; i++ {
+			require.NoError(t, backend.Export(ctx, audittest.RandomLog(), audit.BackendDetails{}))
+		}
+		// The third log is only sent once the backend is closed.
+		require.NoError(t, backend.(io.Closer).Close())
+
+		require.Len(t, sink.events(t), 3)
+		require.Len(t, sink.requests, 2)
+		require.Equal(t, "Bearer secret", sink.requests[0].Header.Get("Authorization"))
+		require.Equal(t, "application/x-ndjson", sink.requests[0].Header.Get("Content-Type"))
+	})
+
+	t.Run("Splunk", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		sink := &httpSink{}
+		srv := httptest.NewServer(sink.handler(always(http.StatusOK)))
+		defer srv.Close()
+
+		u, _ := url.Parse(srv.URL)
+		backend, err := backends.NewHTTP(backends.HTTPOptions{
+			URL:    u,
+			Format: backends.HTTPFormatSplunk,
+			Logger: slogtest.Make(t, nil),
+		})
+		require.NoError(t, err)
+
+		alog := audittest.RandomLog()
+		require.NoError(t, backend.Export(ctx, alog, audit.BackendDetails{}))
+		require.NoError(t, backend.(io.Closer).Close())
+
+		require.Len(t, sink.bodies, 1)
+		var hec struct {
+			Time       float64        `json:"time"`
+			SourceType string         `json:"sourcetype"`
+			Event      backends.Event `json:"event"`
+		}
+		require.NoError(t, json.Unmarshal(sink.bodies[0], &hec))
+		require.Equal(t, "coder:audit", hec.SourceType)
+		require.Equal(t, alog.ID, hec.Event.ID)
+		require.InDelta(t, float64(alog.Time.Unix()), hec.Time, 1)
+	})
+
+	t.Run("Retries", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		sink := &httpSink{}
+		var calls atomic.Int32
+		srv := httptest.NewServer(sink.handler(func() int {
+			if calls.Add(1) == 1 {
+				return http.StatusServiceUnavailable
+			}
+			return http.StatusOK
+		}))
+		defer srv.Close()
+
+		u, _ := url.Parse(srv.URL)
+		backend, err := backends.NewHTTP(backends.HTTPOptions{
+			URL:          u,
+			RetryBackoff: time.Millisecond,
+			SpoolDir:     t.TempDir(),
+			Logger:       slogtest.Make(t, nil),
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, backend.Export(ctx, audittest.RandomLog(), audit.BackendDetails{}))
+		require.NoError(t, backend.(io.Closer).Close())
+
+		require.EqualValues(t, 2, calls.Load())
+		require.Len(t, sink.events(t), 1)
+	})
+
+	t.Run("Spool", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		spoolDir := t.TempDir()
+
+		var down atomic.Bool
+		down.Store(true)
+		sink := &httpSink{}
+		srv := httptest.NewServer(sink.handler(func() int {
+			if down.Load() {
+				return http.StatusInternalServerError
+			}
+			return http.StatusOK
+		}))
+		defer srv.Close()
+		u, _ := url.Parse(srv.URL)
+
+		logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+		backend, err := backends.NewHTTP(backends.HTTPOptions{
+			URL:          u,
+			RetryBackoff: time.Millisecond,
+			SpoolDir:     spoolDir,
+			Logger:       logger,
+		})
+		require.NoError(t, err)
+
+		alog := audittest.RandomLog()
+		require.NoError(t, backend.Export(ctx, alog, audit.BackendDetails{}))
+		require.NoError(t, backend.(io.Closer).Close())
+
+		entries, err := os.ReadDir(spoolDir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.Empty(t, sink.events(t))
+
+		// A new backend, e.g. after a restart, delivers the spooled logs.
+		down.Store(false)
+		backend, err = backends.NewHTTP(backends.HTTPOptions{
+			URL:      u,
+			SpoolDir: spoolDir,
+			Logger:   logger,
+		})
+		require.NoError(t, err)
+		require.NoError(t, backend.(io.Closer).Close())
+
+		events := sink.events(t)
+		require.Len(t, events, 1)
+		require.Equal(t, alog.ID, events[0].ID)
+		entries, err = os.ReadDir(spoolDir)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		spoolDir := t.TempDir()
+
+		var (
+			status atomic.Int32
+			calls  atomic.Int32
+		)
+		status.Store(http.StatusInternalServerError)
+		sink := &httpSink{}
+		srv := httptest.NewServer(sink.handler(func() int {
+			calls.Add(1)
+			code := int(status.Load())
+			if code == http.StatusBadRequest {
+				// Reject a single request.
+				status.Store(http.StatusOK)
+			}
+			return code
+		}))
+		defer srv.Close()
+		u, _ := url.Parse(srv.URL)
+
+		logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+		newBackend := func() io.Closer {
+			backend, err := backends.NewHTTP(backends.HTTPOptions{
+				URL:          u,
+				RetryBackoff: time.Millisecond,
+				SpoolDir:     spoolDir,
+				Logger:       logger,
+			})
+			require.NoError(t, err)
+			return backend.(io.Closer)
+		}
+
+		// Spool two batches while the endpoint is down.
+		logs := []database.AuditLog{audittest.RandomLog(), audittest.RandomLog()}
+		for _, alog := range logs {
+			backend := newBackend()
+			require.NoError(t, backend.(audit.Backend).Export(ctx, alog, audit.BackendDetails{}))
+			require.NoError(t, backend.Close())
+		}
+
+		// The endpoint rejects the first batch and accepts the second. The
+		// rejected batch must not block the spool.
+		status.Store(http.StatusBadRequest)
+		require.NoError(t, newBackend().Close())
+
+		events := sink.events(t)
+		require.Len(t, events, 1)
+		require.Equal(t, logs[1].ID, events[0].ID)
+		entries, err := os.ReadDir(spoolDir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.True(t, strings.HasSuffix(entries[0].Name(), ".rejected"))
+
+		// Rejected batches are kept, but not resent.
+		calls.Store(0)
+		require.NoError(t, newBackend().Close())
+		require.Zero(t, calls.Load())
+		entries, err = os.ReadDir(spoolDir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+	})
+
+	t.Run("OverflowSpooled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		spoolDir := t.TempDir()
+
+		// Block the endpoint so the queue fills up.
+		release := make(chan struct{})
+		sink := &httpSink{}
+		srv := httptest.NewServer(sink.handler(func() int {
+			<-release
+			return http.StatusOK
+		}))
+		defer srv.Close()
+		u, _ := url.Parse(srv.URL)
+
+		logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+		backend, err := backends.NewHTTP(backends.HTTPOptions{
+			URL:           u,
+			BatchSize:     1,
+			FlushInterval: time.Hour,
+			SpoolDir:      spoolDir,
+			Logger:        logger,
+		})
+		require.NoError(t, err)
+
+		// The queue holds ten batches, the rest must be spooled.
+		const count = 20
+		for i := 0; i < count; i++ {
+			require.NoError(t, backend.Export(ctx, audittest.RandomLog(), audit.BackendDetails{}))
+		}
+		entries, err := os.ReadDir(spoolDir)
+		require.NoError(t, err)
+		require.NotEmpty(t, entries)
+
+		close(release)
+		require.NoError(t, backend.(io.Closer).Close())
+
+		// The spooled logs are delivered by the next flush.
+		backend, err = backends.NewHTTP(backends.HTTPOptions{
+			URL:      u,
+			SpoolDir: spoolDir,
+			Logger:   logger,
+		})
+		require.NoError(t, err)
+		require.NoError(t, backend.(io.Closer).Close())
+
+		require.Len(t, sink.events(t), count)
+		entries, err = os.ReadDir(spoolDir)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+
+	t.Run("OverflowBlocks", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		release := make(chan struct{})
+		sink := &httpSink{}
+		srv := httptest.NewServer(sink.handler(func() int {
+			<-release
+			return http.StatusOK
+		}))
+		defer srv.Close()
+		u, _ := url.Parse(srv.URL)
+
+		backend, err := backends.NewHTTP(backends.HTTPOptions{
+			URL:           u,
+			BatchSize:     1,
+			FlushInterval: time.Hour,
+			Logger:        slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}),
+		})
+		require.NoError(t, err)
+
+		// Fill the queue until an export gives up waiting for room.
+		exported := 0
+		for {
+			exportCtx, cancel := context.WithTimeout(ctx, testutil.IntervalMedium)
+			err := backend.Export(exportCtx, audittest.RandomLog(), audit.BackendDetails{})
+			cancel()
+			if err != nil {
+				require.ErrorIs(t, err, context.DeadlineExceeded)
+				break
+			}
+			exported++
+			require.Less(t, exported, 100, "export never blocked")
+		}
+
+		// Once the endpoint recovers, exports are queued again.
+		close(release)
+		require.NoError(t, backend.Export(ctx, audittest.RandomLog(), audit.BackendDetails{}))
+		exported++
+		require.NoError(t, backend.(io.Closer).Close())
+
+		require.Len(t, sink.events(t), exported)
+	})
+
+	t.Run("InvalidOptions", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := backends.NewHTTP(backends.HTTPOptions{})
+		require.Error(t, err)
+
+		u, _ := url.Parse("http://localhost")
+		_, err = backends.NewHTTP(backends.HTTPOptions{URL: u, Format: "xml"})
+		require.Error(t, err)
+
+		_, err = backends.ParseHTTPHeaders([]string{"no-colon"})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,218 @@
+package backends
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/enterprise/audit"
+)
+
+const (
+	// syslogPriority is facility authpriv (10) at severity informational (6),
+	// as defined in RFC 5424 section 6.2.1.
+	syslogPriority = 10*8 + 6
+	syslogAppName  = "coder"
+	syslogMsgID    = "audit"
+	// syslogTimeFormat is RFC 3339 limited to microsecond precision, as
+	// required by RFC 5424 section 6.2.3.
+	syslogTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+
+	syslogMaxBackoff   = 30 * time.Second
+	syslogWriteTimeout = 10 * time.Second
+	syslogCloseTimeout = 5 * time.Second
+)
+
+// SyslogOptions configures the syslog backend.
+type SyslogOptions struct {
+	// Address is the host:port of the syslog server.
+	Address string
+	// TLSConfig enables TLS when set.
+	TLSConfig *tls.Config
+	// Hostname is reported in each message. Defaults to os.Hostname.
+	Hostname string
+	// QueueSize is the number of messages buffered while the server is
+	// unreachable. Messages are dropped once the queue is full.
+	QueueSize int
+	// RetryBackoff is the initial delay between connection attempts.
+	RetryBackoff time.Duration
+	Logger       slog.Logger
+}
+
+type syslogBackend struct {
+	opts SyslogOptions
+	log  slog.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu     sync.RWMutex // Protects closed and sending on queue.
+	closed bool
+	queue  chan []byte
+}
+
+// NewSyslog returns a backend that streams audit logs to a syslog server over
+// TCP, optionally using TLS. Messages are formatted as RFC 5424 with a JSON
+// body and framed using octet counting (RFC 6587). Delivery is asynchronous so
+// an unreachable server never fails the request being audited.
+func NewSyslog(opts SyslogOptions) (audit.Backend, error) {
+	if opts.Address == "" {
+		return nil, xerrors.New("address is required")
+	}
+	if _, _, err := net.SplitHostPort(opts.Address); err != nil {
+		return nil, xerrors.Errorf("invalid address %q: %w", opts.Address, err)
+	}
+	if opts.Hostname == "" {
+		opts.Hostname, _ = os.Hostname()
+		if opts.Hostname == "" {
+			opts.Hostname = "-"
+		}
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 1024
+	}
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = time.Second
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b := &syslogBackend{
+		opts:   opts,
+		log:    opts.Logger.Named("syslog_audit"),
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+		queue:  make(chan []byte, opts.QueueSize),
+	}
+	go b.run()
+	return b, nil
+}
+
+func (*syslogBackend) Decision() audit.FilterDecision {
+	return audit.FilterDecisionExport
+}
+
+func (b *syslogBackend) Export(ctx context.Context, alog database.AuditLog, details audit.BackendDetails) error {
+	msg, err := b.format(alog, details)
+	if err != nil {
+		return err
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return xerrors.New("syslog backend is closed")
+	}
+	select {
+	case b.queue <- msg:
+	default:
+		b.log.Warn(ctx, "syslog queue is full, dropping audit log", slog.F("audit_log_id", alog.ID))
+	}
+	return nil
+}
+
+// format renders a framed RFC 5424 message.
+func (b *syslogBackend) format(alog database.AuditLog, details audit.BackendDetails) ([]byte, error) {
+	body, err := json.Marshal(NewEvent(alog, details))
+	if err != nil {
+		return nil, xerrors.Errorf("marshal audit log: %w", err)
+	}
+	msg := fmt.Sprintf("<%d>1 %s %s %s %d %s - %s",
+		syslogPriority,
+		alog.Time.UTC().Format(syslogTimeFormat),
+		b.opts.Hostname,
+		syslogAppName,
+		os.Getpid(),
+		syslogMsgID,
+		body,
+	)
+	return []byte(strconv.Itoa(len(msg)) + " " + msg), nil
+}
+
+func (b *syslogBackend) run() {
+	defer close(b.done)
+
+	var conn net.Conn
+	defer func() {
+		if conn != nil {
+			_ = conn.Close()
+		}
+	}()
+
+	backoff := b.opts.RetryBackoff
+	for msg := range b.queue {
+		for {
+			if conn == nil {
+				var err error
+				conn, err = b.dial()
+				if err != nil {
+					b.log.Warn(b.ctx, "connect to syslog server", slog.F("address", b.opts.Address), slog.Error(err))
+					select {
+					case <-b.ctx.Done():
+						return
+					case <-time.After(backoff):
+					}
+					backoff = min(backoff*2, syslogMaxBackoff)
+					continue
+				}
+				backoff = b.opts.RetryBackoff
+			}
+
+			_ = conn.SetWriteDeadline(time.Now().Add(syslogWriteTimeout))
+			_, err := conn.Write(msg)
+			if err == nil {
+				break
+			}
+			b.log.Warn(b.ctx, "write to syslog server", slog.F("address", b.opts.Address), slog.Error(err))
+			_ = conn.Close()
+			conn = nil
+			if b.ctx.Err() != nil {
+				return
+			}
+		}
+	}
+}
+
+func (b *syslogBackend) dial() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: syslogWriteTimeout}
+	if b.opts.TLSConfig != nil {
+		td := &tls.Dialer{NetDialer: dialer, Config: b.opts.TLSConfig}
+		return td.DialContext(b.ctx, "tcp", b.opts.Address)
+	}
+	return dialer.DialContext(b.ctx, "tcp", b.opts.Address)
+}
+
+// Close stops accepting audit logs and waits briefly for queued messages to be
+// delivered. Messages still queued after the timeout are dropped.
+func (b *syslogBackend) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	close(b.queue)
+	b.mu.Unlock()
+
+	timer := time.NewTimer(syslogCloseTimeout)
+	defer timer.Stop()
+	select {
+	case <-b.done:
+	case <-timer.C:
+		b.log.Warn(context.Background(), "timed out delivering queued audit logs to syslog server", slog.F("dropped", len(b.queue)))
+	}
+	b.cancel()
+	<-b.done
+	return nil
+}
@@ -0,0 +1,90 @@
+package backends_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/enterprise/audit"
+	"github.com/coder/coder/v2/enterprise/audit/audittest"
+	"github.com/coder/coder/v2/enterprise/audit/backends"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestSyslogBackend(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer ln.Close()
+
+		msgs := make(chan string, 2)
+		go func() {
+			conn, err := ln.Accept()
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer conn.Close()
+			r := bufio.NewReader(conn)
+			for {
+				// Octet-counting framing: "<length> <message>".
+				length, err := r.ReadString(' ')
+				if err != nil {
+					return
+				}
+				n, err := strconv.Atoi(strings.TrimSpace(length))
+				if !assert.NoError(t, err) {
+					return
+				}
+				buf := make([]byte, n)
+				_, err = io.ReadFull(r, buf)
+				if !assert.NoError(t, err) {
+					return
+				}
+				msgs <- string(buf)
+			}
+		}()
+
+		backend, err := backends.NewSyslog(backends.SyslogOptions{
+			Address:  ln.Addr().String(),
+			Hostname: "coder-test",
+			Logger:   slogtest.Make(t, nil),
+		})
+		require.NoError(t, err)
+		defer backend.(io.Closer).Close()
+
+		alog := audittest.RandomLog()
+		err = backend.Export(ctx, alog, audit.BackendDetails{Actor: &audit.Actor{Username: "alice"}})
+		require.NoError(t, err)
+
+		msg := testutil.RequireRecvCtx(ctx, t, msgs)
+		header := regexp.MustCompile(`^<86>1 \S+ coder-test coder \d+ audit - `)
+		require.Regexp(t, header, msg)
+
+		var ev backends.Event
+		require.NoError(t, json.Unmarshal([]byte(header.ReplaceAllString(msg, "")), &ev))
+		require.Equal(t, alog.ID, ev.ID)
+		require.Equal(t, string(alog.Action), ev.Action)
+		require.Equal(t, "alice", ev.Actor.Username)
+	})
+
+	t.Run("InvalidAddress", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := backends.NewSyslog(backends.SyslogOptions{Address: "localhost"})
+		require.Error(t, err)
+	})
+}
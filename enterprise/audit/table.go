@@ -27,6 +27,9 @@ var AuditActionMap = map[string][]codersdk.AuditAction{
 	"Group":           {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
 	"APIKey":          {codersdk.AuditActionLogin, codersdk.AuditActionLogout, codersdk.AuditActionRegister, codersdk.AuditActionCreate, codersdk.AuditActionDelete},
 	"License":         {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	// Session recordings are audited when they are watched, not when they are
+	// recorded.
+	"WorkspaceSessionRecording": {codersdk.AuditActionOpen},
 }
 
 type Action string
@@ -112,6 +115,11 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"require_active_version":            ActionTrack,
 		"deprecated":                        ActionTrack,
 		"max_port_sharing_level":            ActionTrack,
+		"session_recording_enabled":         ActionTrack,
+		"require_build_approval":            ActionTrack,
+		"build_approval_parameters":         ActionTrack,
+		"build_approval_group_id":           ActionTrack,
+		"build_approval_timeout":            ActionTrack,
 		"activity_bump":                     ActionTrack,
 	},
 	&database.TemplateVersion{}: {
@@ -198,18 +206,20 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"source":          ActionIgnore,
 	},
 	&database.APIKey{}: {
-		"id":               ActionIgnore,
-		"hashed_secret":    ActionIgnore,
-		"user_id":          ActionTrack,
-		"last_used":        ActionTrack,
-		"expires_at":       ActionTrack,
-		"created_at":       ActionTrack,
-		"updated_at":       ActionIgnore,
-		"login_type":       ActionIgnore,
-		"lifetime_seconds": ActionIgnore,
-		"ip_address":       ActionIgnore,
-		"scope":            ActionIgnore,
-		"token_name":       ActionIgnore,
+		"id":                ActionIgnore,
+		"hashed_secret":     ActionIgnore,
+		"user_id":           ActionTrack,
+		"last_used":         ActionTrack,
+		"expires_at":        ActionTrack,
+		"created_at":        ActionTrack,
+		"updated_at":        ActionIgnore,
+		"login_type":        ActionIgnore,
+		"lifetime_seconds":  ActionIgnore,
+		"ip_address":        ActionIgnore,
+		"scope":             ActionIgnore,
+		"token_name":        ActionIgnore,
+		"scope_permissions": ActionTrack,
+		"scope_allow_list":  ActionTrack,
 	},
 	&database.AuditOAuthConvertState{}: {
 		"created_at":      ActionTrack,
@@ -252,12 +262,14 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"version":             ActionTrack,
 	},
 	&database.OAuth2ProviderApp{}: {
-		"id":           ActionIgnore,
-		"created_at":   ActionIgnore,
-		"updated_at":   ActionIgnore,
-		"name":         ActionTrack,
-		"icon":         ActionTrack,
-		"callback_url": ActionTrack,
+		"id":                         ActionIgnore,
+		"created_at":                 ActionIgnore,
+		"updated_at":                 ActionIgnore,
+		"name":                       ActionTrack,
+		"icon":                       ActionTrack,
+		"callback_url":               ActionTrack,
+		"public_client":              ActionTrack,
+		"client_credentials_user_id": ActionTrack,
 	},
 	&database.OAuth2ProviderAppSecret{}: {
 		"id":             ActionIgnore,
@@ -289,6 +301,31 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"kind":               ActionTrack,
 		"enabled_by_default": ActionTrack,
 	},
+	&database.WorkspaceSessionRecording{}: {
+		"id":           ActionTrack,
+		"created_at":   ActionIgnore,
+		"updated_at":   ActionIgnore,
+		"ended_at":     ActionIgnore,
+		"workspace_id": ActionTrack,
+		"agent_id":     ActionTrack,
+		"user_id":      ActionTrack,
+		"type":         ActionTrack,
+		"size":         ActionIgnore,
+	},
+	&database.Secret{}: {
+		"id":              ActionTrack,
+		"created_at":      ActionIgnore,
+		"updated_at":      ActionIgnore,
+		"name":            ActionTrack,
+		"description":     ActionTrack,
+		"value":           ActionSecret, // Secret values must never be exposed in diffs.
+		"value_key_id":    ActionIgnore, // Internal implementation detail of database encryption.
+		"env_name":        ActionTrack,
+		"file_path":       ActionTrack,
+		"user_id":         ActionTrack,
+		"organization_id": ActionTrack,
+		"template_id":     ActionTrack,
+	},
 	&idpsync.OrganizationSyncSettings{}: {
 		"field":          ActionTrack,
 		"mapping":        ActionTrack,
@@ -0,0 +1,82 @@
+//go:build !slim
+
+package cli
+
+import (
+	"fmt"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/sloghuman"
+	agpl "github.com/coder/coder/v2/cli"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/terraform"
+	"github.com/coder/pretty"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) provisionerCache() *serpent.Command {
+	cmd := &serpent.Command{
+		Use:   "cache",
+		Short: "Manage the Terraform cache of provisioner daemons",
+		Handler: func(inv *serpent.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*serpent.Command{
+			r.provisionerCacheWarm(),
+		},
+	}
+
+	return cmd
+}
+
+func (*RootCmd) provisionerCacheWarm() *serpent.Command {
+	var cacheDir string
+	cmd := &serpent.Command{
+		Use:   "warm <directory> [directory...]",
+		Short: "Download the Terraform providers and modules of templates to the cache",
+		Long: "The cache directory can then be copied to provisioner daemons without " +
+			"network access, which use it instead of downloading the providers and modules.\n\n" +
+			agpl.FormatExamples(
+				agpl.Example{
+					Description: "Cache the providers and modules of two templates",
+					Command:     "coder provisioner cache warm ./docker ./kubernetes",
+				},
+			),
+		Middleware: serpent.Chain(
+			serpent.RequireRangeArgs(1, -1),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			logger := slog.Make(sloghuman.Sink(inv.Stderr)).Leveled(slog.LevelWarn)
+
+			for _, dir := range inv.Args {
+				err := terraform.WarmCache(ctx, terraform.WarmCacheOptions{
+					Directory: dir,
+					CachePath: cacheDir,
+					Logger:    logger,
+					Output:    inv.Stdout,
+				})
+				if err != nil {
+					return xerrors.Errorf("cache %q: %w", dir, err)
+				}
+				_, _ = fmt.Fprintf(inv.Stdout, "Cached the providers and modules of %s\n", pretty.Sprint(cliui.DefaultStyles.Keyword, dir))
+			}
+			return nil
+		},
+	}
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:          "cache-dir",
+			FlagShorthand: "c",
+			Env:           "CODER_CACHE_DIRECTORY",
+			Description:   "Directory to store cached data.",
+			Default:       codersdk.DefaultCacheDir(),
+			Value:         serpent.StringOf(&cacheDir),
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,24 @@
+//go:build slim
+
+package cli
+
+import (
+	agplcli "github.com/coder/coder/v2/cli"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) provisionerCache() *serpent.Command {
+	cmd := &serpent.Command{
+		Use:   "cache",
+		Short: "Manage the Terraform cache of provisioner daemons",
+		// We accept RawArgs so all commands and flags are accepted.
+		RawArgs: true,
+		Hidden:  true,
+		Handler: func(inv *serpent.Invocation) error {
+			agplcli.SlimUnsupported(inv.Stderr, "provisioner cache")
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -51,6 +51,8 @@ func (r *RootCmd) provisionerDaemonStart() *serpent.Command {
 
 		prometheusEnable  bool
 		prometheusAddress string
+
+		terraformCacheMaxSize int64
 	)
 	orgContext := agpl.NewOrganizationContext()
 	client := new(codersdk.Client)
@@ -173,6 +175,28 @@ func (r *RootCmd) provisionerDaemonStart() *serpent.Command {
 				return err
 			}
 
+			var (
+				metrics      *provisionerd.Metrics
+				cacheMetrics *terraform.CacheMetrics
+			)
+			if prometheusEnable {
+				logger.Info(ctx, "starting Prometheus endpoint", slog.F("address", prometheusAddress))
+
+				prometheusRegistry := prometheus.NewRegistry()
+				prometheusRegistry.MustRegister(collectors.NewGoCollector())
+				prometheusRegistry.MustRegister(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+
+				m := provisionerd.NewMetrics(prometheusRegistry)
+				m.Runner.NumDaemons.Set(float64(1)) // Set numDaemons to 1 as this is standalone mode.
+				metrics = &m
+				cacheMetrics = terraform.NewCacheMetrics(prometheusRegistry)
+
+				closeFunc := agpl.ServeHandler(ctx, logger, promhttp.InstrumentMetricHandler(
+					prometheusRegistry, promhttp.HandlerFor(prometheusRegistry, promhttp.HandlerOpts{}),
+				), prometheusAddress, "prometheus")
+				defer closeFunc()
+			}
+
 			terraformClient, terraformServer := drpc.MemTransportPipe()
 			go func() {
 				<-ctx.Done()
@@ -190,7 +214,9 @@ func (r *RootCmd) provisionerDaemonStart() *serpent.Command {
 						Logger:        logger.Named("terraform"),
 						WorkDirectory: tempDir,
 					},
-					CachePath: cacheDir,
+					CachePath:    cacheDir,
+					CacheMaxSize: terraformCacheMaxSize * 1024 * 1024,
+					CacheMetrics: cacheMetrics,
 				})
 				if err != nil && !xerrors.Is(err, context.Canceled) {
 					select {
@@ -200,24 +226,6 @@ func (r *RootCmd) provisionerDaemonStart() *serpent.Command {
 				}
 			}()
 
-			var metrics *provisionerd.Metrics
-			if prometheusEnable {
-				logger.Info(ctx, "starting Prometheus endpoint", slog.F("address", prometheusAddress))
-
-				prometheusRegistry := prometheus.NewRegistry()
-				prometheusRegistry.MustRegister(collectors.NewGoCollector())
-				prometheusRegistry.MustRegister(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
-
-				m := provisionerd.NewMetrics(prometheusRegistry)
-				m.Runner.NumDaemons.Set(float64(1)) // Set numDaemons to 1 as this is standalone mode.
-				metrics = &m
-
-				closeFunc := agpl.ServeHandler(ctx, logger, promhttp.InstrumentMetricHandler(
-					prometheusRegistry, promhttp.HandlerFor(prometheusRegistry, promhttp.HandlerOpts{}),
-				), prometheusAddress, "prometheus")
-				defer closeFunc()
-			}
-
 			logger.Info(ctx, "starting provisioner daemon", slog.F("tags", displayedTags), slog.F("name", name))
 
 			connector := provisionerd.LocalProvisioners{
@@ -296,6 +304,13 @@ func (r *RootCmd) provisionerDaemonStart() *serpent.Command {
 			Default:       codersdk.DefaultCacheDir(),
 			Value:         serpent.StringOf(&cacheDir),
 		},
+		{
+			Flag:        "terraform-cache-max-size",
+			Env:         "CODER_PROVISIONER_DAEMON_TERRAFORM_CACHE_MAX_SIZE",
+			Description: "The size in megabytes of the Terraform provider and module cache in the cache directory. The least recently used providers and modules are evicted once the cache exceeds this size. 0 means there is no limit.",
+			Default:     "10240",
+			Value:       serpent.Int64Of(&terraformCacheMaxSize),
+		},
 		{
 			Flag:          "tag",
 			FlagShorthand: "t",
@@ -4,11 +4,13 @@ package cli
 
 import (
 	"context"
+	"crypto/tls"
 	"database/sql"
 	"encoding/base64"
 	"errors"
 	"io"
 	"net/url"
+	"path/filepath"
 
 	"golang.org/x/xerrors"
 	"tailscale.com/derp"
@@ -75,11 +77,24 @@ func (r *RootCmd) Server(_ func()) *serpent.Command {
 			options.DERPServer.SetMeshKey(meshKey)
 		}
 
+		streamingBackends, err := auditStreamingBackends(options)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("configure audit log streaming: %w", err)
+		}
+		closeStreamingBackends := func() {
+			for _, b := range streamingBackends {
+				if c, ok := b.(io.Closer); ok {
+					_ = c.Close()
+				}
+			}
+		}
 		options.Auditor = audit.NewAuditor(
 			options.Database,
 			audit.DefaultFilter,
-			backends.NewPostgres(options.Database, true),
-			backends.NewSlog(options.Logger),
+			append([]audit.Backend{
+				backends.NewPostgres(options.Database, true),
+				backends.NewSlog(options.Logger),
+			}, streamingBackends...)...,
 		)
 
 		options.TrialGenerator = trialer.New(options.Database, "https://v2-licensor.coder.com/trial", coderd.Keys)
@@ -104,22 +119,40 @@ func (r *RootCmd) Server(_ func()) *serpent.Command {
 			for idx, ek := range encKeys {
 				dk, err := base64.StdEncoding.DecodeString(ek)
 				if err != nil {
+					closeStreamingBackends()
 					return nil, nil, xerrors.Errorf("decode external-token-encryption-key %d: %w", idx, err)
 				}
 				keys = append(keys, dk)
 			}
 			cs, err := dbcrypt.NewCiphers(keys...)
 			if err != nil {
+				closeStreamingBackends()
 				return nil, nil, xerrors.Errorf("initialize encryption: %w", err)
 			}
 			o.ExternalTokenEncryption = cs
 		}
+		keyProvider, err := keyProviderFromConfig(options.DeploymentValues.ExternalTokenEncryption)
+		if err != nil {
+			closeStreamingBackends()
+			return nil, nil, xerrors.Errorf("initialize external token encryption key provider: %w", err)
+		}
+		if keyProvider != nil {
+			o.ExternalTokenEncryptionKeyProvider = keyProvider
+			o.ExternalTokenEncryptionRotationInterval = options.DeploymentValues.ExternalTokenEncryption.RotationInterval.Value()
+		}
 
 		api, err := coderd.New(ctx, o)
 		if err != nil {
+			closeStreamingBackends()
 			return nil, nil, err
 		}
-		return api.AGPL, api, nil
+		return api.AGPL, closerFunc(func() error {
+			err := api.Close()
+			// Close after the API so audit logs from in-flight requests
+			// are flushed.
+			closeStreamingBackends()
+			return err
+		}), nil
 	})
 
 	cmd.AddSubcommands(
@@ -127,3 +160,81 @@ func (r *RootCmd) Server(_ func()) *serpent.Command {
 	)
 	return cmd
 }
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error { return f() }
+
+// auditStreamingBackends returns the audit backends which export to external
+// systems, as configured by the deployment.
+func auditStreamingBackends(options *agplcoderd.Options) ([]audit.Backend, error) {
+	var (
+		cfg    = options.DeploymentValues.AuditLogStreaming
+		logger = options.Logger.Named("audit")
+		out    []audit.Backend
+	)
+	closeAll := func() {
+		for _, b := range out {
+			if c, ok := b.(io.Closer); ok {
+				_ = c.Close()
+			}
+		}
+	}
+
+	if addr := cfg.Syslog.Address.String(); addr != "" {
+		var tlsConfig *tls.Config
+		if cfg.Syslog.TLS.Value() {
+			tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		b, err := backends.NewSyslog(backends.SyslogOptions{
+			Address:   addr,
+			TLSConfig: tlsConfig,
+			Logger:    logger,
+		})
+		if err != nil {
+			return nil, xerrors.Errorf("syslog: %w", err)
+		}
+		out = append(out, b)
+	}
+
+	if u := cfg.HTTP.URL.Value(); u != nil && u.String() != "" {
+		headers, err := backends.ParseHTTPHeaders(cfg.HTTP.Headers.Value())
+		if err != nil {
+			closeAll()
+			return nil, xerrors.Errorf("audit-log-http-headers: %w", err)
+		}
+		spoolDir := cfg.HTTP.SpoolDir.String()
+		if spoolDir == "" {
+			spoolDir = filepath.Join(options.DeploymentValues.CacheDir.String(), "audit-spool")
+		}
+		b, err := backends.NewHTTP(backends.HTTPOptions{
+			URL:           u,
+			Headers:       headers,
+			Format:        backends.HTTPFormat(cfg.HTTP.Format.String()),
+			BatchSize:     int(cfg.HTTP.BatchSize.Value()),
+			FlushInterval: cfg.HTTP.FlushInterval.Value(),
+			SpoolDir:      spoolDir,
+			Logger:        logger,
+		})
+		if err != nil {
+			closeAll()
+			return nil, xerrors.Errorf("http: %w", err)
+		}
+		out = append(out, b)
+	}
+
+	if path := cfg.File.Path.String(); path != "" {
+		b, err := backends.NewFile(backends.FileOptions{
+			Path:       path,
+			MaxSizeMB:  int(cfg.File.MaxSize.Value()),
+			MaxBackups: int(cfg.File.MaxBackups.Value()),
+		})
+		if err != nil {
+			closeAll()
+			return nil, xerrors.Errorf("file: %w", err)
+		}
+		out = append(out, b)
+	}
+
+	return out, nil
+}
@@ -12,6 +12,7 @@ import (
 	"cdr.dev/slog/sloggers/sloghuman"
 	"github.com/coder/coder/v2/cli"
 	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/awsiamrds"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/enterprise/dbcrypt"
@@ -32,6 +33,7 @@ func (r *RootCmd) dbcryptCmd() *serpent.Command {
 		r.dbcryptDecryptCmd(),
 		r.dbcryptDeleteCmd(),
 		r.dbcryptRotateCmd(),
+		r.dbcryptStatusCmd(),
 	)
 	return dbcryptCmd
 }
@@ -53,12 +55,19 @@ func (*RootCmd) dbcryptRotateCmd() *serpent.Command {
 				return err
 			}
 
-			ks := [][]byte{}
-			dk, err := base64.StdEncoding.DecodeString(flags.New)
+			provider, err := keyProviderFromConfig(flags.Deployment.ExternalTokenEncryption)
 			if err != nil {
-				return xerrors.Errorf("decode new key: %w", err)
+				return err
+			}
+
+			ks := [][]byte{}
+			if provider == nil {
+				dk, err := base64.StdEncoding.DecodeString(flags.New)
+				if err != nil {
+					return xerrors.Errorf("decode new key: %w", err)
+				}
+				ks = append(ks, dk)
 			}
-			ks = append(ks, dk)
 
 			for _, k := range flags.Old {
 				dk, err := base64.StdEncoding.DecodeString(k)
@@ -74,16 +83,22 @@ func (*RootCmd) dbcryptRotateCmd() *serpent.Command {
 			}
 
 			var act string
-			switch len(flags.Old) {
-			case 0:
+			switch {
+			case provider != nil:
+				act = "Data will be decrypted with all available keys and re-encrypted with a new data key."
+			case len(flags.Old) == 0:
 				act = "Data will be encrypted with the new key."
 			default:
 				act = "Data will be decrypted with all available keys and re-encrypted with new key."
 			}
 
+			newKey := flags.New
+			if provider != nil {
+				newKey = fmt.Sprintf("data key wrapped by %s", provider.Name())
+			}
 			msg := fmt.Sprintf("%s\n\n- New key: %s\n- Old keys: %s\n\nRotate external token encryption keys?\n",
 				act,
-				flags.New,
+				newKey,
 				strings.Join(flags.Old, ", "),
 			)
 			if _, err := cliui.Prompt(inv, cliui.PromptOptions{Text: msg, IsConfirm: true}); err != nil {
@@ -106,6 +121,18 @@ func (*RootCmd) dbcryptRotateCmd() *serpent.Command {
 				_ = sqlDB.Close()
 			}()
 			logger.Info(ctx, "connected to postgres")
+			if provider != nil {
+				// The data keys of the provider are rotated as well.
+				existing, err := dbcrypt.KeyProviderCiphers(ctx, database.New(sqlDB), provider)
+				if err != nil {
+					return xerrors.Errorf("unwrap data keys: %w", err)
+				}
+				dataKey, err := dbcrypt.NewDataKey(ctx, provider)
+				if err != nil {
+					return xerrors.Errorf("create data key: %w", err)
+				}
+				ciphers = append(append([]dbcrypt.Cipher{dataKey}, existing...), ciphers...)
+			}
 			if err := dbcrypt.Rotate(ctx, logger, sqlDB, ciphers); err != nil {
 				return xerrors.Errorf("rotate ciphers: %w", err)
 			}
@@ -134,6 +161,11 @@ func (*RootCmd) dbcryptDecryptCmd() *serpent.Command {
 				return err
 			}
 
+			provider, err := keyProviderFromConfig(flags.Deployment.ExternalTokenEncryption)
+			if err != nil {
+				return err
+			}
+
 			ks := make([][]byte, 0, len(flags.Keys))
 			for _, k := range flags.Keys {
 				dk, err := base64.StdEncoding.DecodeString(k)
@@ -171,6 +203,16 @@ func (*RootCmd) dbcryptDecryptCmd() *serpent.Command {
 				_ = sqlDB.Close()
 			}()
 			logger.Info(ctx, "connected to postgres")
+			if provider != nil {
+				existing, err := dbcrypt.KeyProviderCiphers(ctx, database.New(sqlDB), provider)
+				if err != nil {
+					return xerrors.Errorf("unwrap data keys: %w", err)
+				}
+				ciphers = append(existing, ciphers...)
+			}
+			if len(ciphers) == 0 {
+				return xerrors.Errorf("no keys to decrypt data with")
+			}
 			if err := dbcrypt.Decrypt(ctx, logger, sqlDB, ciphers); err != nil {
 				return xerrors.Errorf("rotate ciphers: %w", err)
 			}
@@ -201,6 +243,7 @@ func (*RootCmd) dbcryptDeleteCmd() *serpent.Command {
 			msg := `All encrypted data will be deleted from the database:
 - Encrypted user OAuth access and refresh tokens
 - Encrypted user Git authentication access and refresh tokens
+- Encrypted secrets
 
 Are you sure you want to continue?`
 			if _, err := cliui.Prompt(inv, cliui.PromptOptions{
@@ -238,11 +281,126 @@ Are you sure you want to continue?`
 	return cmd
 }
 
+func (*RootCmd) dbcryptStatusCmd() *serpent.Command {
+	var (
+		flags     statusFlags
+		formatter = cliui.NewOutputFormatter(
+			cliui.TableFormat([]dbcrypt.KeyStatus{}, []string{"number", "digest", "provider", "created at", "revoked at", "user links", "external auth links", "secrets"}),
+			cliui.JSONFormat(),
+		)
+	)
+	cmd := &serpent.Command{
+		Use:   "status",
+		Short: "Show database encryption keys and the number of tokens encrypted with each of them.",
+		Handler: func(inv *serpent.Invocation) error {
+			ctx, cancel := context.WithCancel(inv.Context())
+			defer cancel()
+			logger := slog.Make(sloghuman.Sink(inv.Stderr))
+			if ok, _ := inv.ParsedFlags().GetBool("verbose"); ok {
+				logger = logger.Leveled(slog.LevelDebug)
+			}
+
+			if err := flags.valid(); err != nil {
+				return err
+			}
+
+			var err error
+			sqlDriver := "postgres"
+			if codersdk.PostgresAuth(flags.PostgresAuth) == codersdk.PostgresAuthAWSIAMRDS {
+				sqlDriver, err = awsiamrds.Register(inv.Context(), sqlDriver)
+				if err != nil {
+					return xerrors.Errorf("register aws rds iam auth: %w", err)
+				}
+			}
+
+			sqlDB, err := cli.ConnectToPostgres(inv.Context(), logger, sqlDriver, flags.PostgresURL, nil)
+			if err != nil {
+				return xerrors.Errorf("connect to postgres: %w", err)
+			}
+			defer func() {
+				_ = sqlDB.Close()
+			}()
+			keys, err := dbcrypt.Status(ctx, sqlDB)
+			if err != nil {
+				return xerrors.Errorf("get key status: %w", err)
+			}
+			out, err := formatter.Format(ctx, keys)
+			if err != nil {
+				return xerrors.Errorf("display key status: %w", err)
+			}
+			_, _ = fmt.Fprintln(inv.Stdout, out)
+			return nil
+		},
+	}
+	flags.attach(&cmd.Options)
+	var formatOpts serpent.OptionSet
+	formatter.AttachOptions(&formatOpts)
+	for i := range formatOpts {
+		// -c is the shorthand of --config on the server command.
+		if formatOpts[i].FlagShorthand == "c" {
+			formatOpts[i].FlagShorthand = ""
+		}
+	}
+	cmd.Options = append(cmd.Options, formatOpts...)
+	return cmd
+}
+
+// keyProviderOptions returns the server options that configure the external
+// key provider, so that the dbcrypt commands can be run with the environment
+// of the server.
+func keyProviderOptions(vals *codersdk.DeploymentValues) serpent.OptionSet {
+	var opts serpent.OptionSet
+	for _, opt := range vals.Options() {
+		if opt.Group == nil || opt.Group.YAML != "externalTokenEncryption" || opt.Flag == "external-token-encryption-rotation-interval" {
+			continue
+		}
+		opt.Group = nil
+		opt.YAML = ""
+		opts = append(opts, opt)
+	}
+	return opts
+}
+
+// keyProviderFromConfig returns the configured external key provider, or nil
+// if none is configured.
+func keyProviderFromConfig(cfg codersdk.ExternalTokenEncryptionConfig) (dbcrypt.KeyProvider, error) {
+	switch codersdk.ExternalTokenEncryptionKeyProvider(cfg.KeyProvider.Value()) {
+	case "":
+		return nil, nil
+	case codersdk.ExternalTokenEncryptionKeyProviderVaultTransit:
+		if cfg.VaultAddress.String() == "" {
+			return nil, xerrors.New("--external-token-encryption-vault-address is required for the vault-transit key provider")
+		}
+		if cfg.VaultTokenFile.Value() == "" {
+			return nil, xerrors.New("--external-token-encryption-vault-token-file is required for the vault-transit key provider")
+		}
+		if cfg.VaultTransitKey.Value() == "" {
+			return nil, xerrors.New("--external-token-encryption-vault-transit-key is required for the vault-transit key provider")
+		}
+		return &dbcrypt.VaultTransit{
+			Address:   cfg.VaultAddress.Value(),
+			TokenFile: cfg.VaultTokenFile.Value(),
+			Namespace: cfg.VaultNamespace.Value(),
+			Mount:     cfg.VaultTransitMount.Value(),
+			Key:       cfg.VaultTransitKey.Value(),
+		}, nil
+	case codersdk.ExternalTokenEncryptionKeyProviderCommand:
+		command := strings.Fields(cfg.KeyCommand.Value())
+		if len(command) == 0 {
+			return nil, xerrors.New("--external-token-encryption-key-command is required for the command key provider")
+		}
+		return &dbcrypt.CommandKeyProvider{Command: command[0], Args: command[1:]}, nil
+	default:
+		return nil, xerrors.Errorf("unknown external token encryption key provider %q, must be one of %s", cfg.KeyProvider.Value(), strings.Join(codersdk.ExternalTokenEncryptionKeyProviders, ", "))
+	}
+}
+
 type rotateFlags struct {
 	PostgresURL  string
 	PostgresAuth string
 	New          string
 	Old          []string
+	Deployment   codersdk.DeploymentValues
 }
 
 func (f *rotateFlags) attach(opts *serpent.OptionSet) {
@@ -276,6 +434,7 @@ func (f *rotateFlags) attach(opts *serpent.OptionSet) {
 		},
 		cliui.SkipPromptOption(),
 	)
+	*opts = append(*opts, keyProviderOptions(&f.Deployment)...)
 }
 
 func (f *rotateFlags) valid() error {
@@ -283,13 +442,17 @@ func (f *rotateFlags) valid() error {
 		return xerrors.Errorf("no database configured")
 	}
 
-	if f.New == "" {
+	if f.Deployment.ExternalTokenEncryption.KeyProvider.Value() != "" {
+		if f.New != "" {
+			return xerrors.Errorf("a new key cannot be provided with a key provider, a new data key is created instead")
+		}
+	} else if f.New == "" {
 		return xerrors.Errorf("no new key provided")
 	}
 
 	if val, err := base64.StdEncoding.DecodeString(f.New); err != nil {
 		return xerrors.Errorf("new key must be base64-encoded")
-	} else if len(val) != 32 {
+	} else if f.New != "" && len(val) != 32 {
 		return xerrors.Errorf("new key must be exactly 32 bytes in length")
 	}
 
@@ -313,6 +476,7 @@ type decryptFlags struct {
 	PostgresURL  string
 	PostgresAuth string
 	Keys         []string
+	Deployment   codersdk.DeploymentValues
 }
 
 func (f *decryptFlags) attach(opts *serpent.OptionSet) {
@@ -340,6 +504,7 @@ func (f *decryptFlags) attach(opts *serpent.OptionSet) {
 		},
 		cliui.SkipPromptOption(),
 	)
+	*opts = append(*opts, keyProviderOptions(&f.Deployment)...)
 }
 
 func (f *decryptFlags) valid() error {
@@ -347,7 +512,7 @@ func (f *decryptFlags) valid() error {
 		return xerrors.Errorf("no database configured")
 	}
 
-	if len(f.Keys) == 0 {
+	if len(f.Keys) == 0 && f.Deployment.ExternalTokenEncryption.KeyProvider.Value() == "" {
 		return xerrors.Errorf("no keys provided")
 	}
 
@@ -396,3 +561,36 @@ func (f *deleteFlags) valid() error {
 
 	return nil
 }
+
+type statusFlags struct {
+	PostgresURL  string
+	PostgresAuth string
+}
+
+func (f *statusFlags) attach(opts *serpent.OptionSet) {
+	*opts = append(
+		*opts,
+		serpent.Option{
+			Flag:        "postgres-url",
+			Env:         "CODER_PG_CONNECTION_URL",
+			Description: "The connection URL for the Postgres database.",
+			Value:       serpent.StringOf(&f.PostgresURL),
+		},
+		serpent.Option{
+			Name:        "Postgres Connection Auth",
+			Description: "Type of auth to use when connecting to postgres.",
+			Flag:        "postgres-connection-auth",
+			Env:         "CODER_PG_CONNECTION_AUTH",
+			Default:     "password",
+			Value:       serpent.EnumOf(&f.PostgresAuth, codersdk.PostgresAuthDrivers...),
+		},
+	)
+}
+
+func (f *statusFlags) valid() error {
+	if f.PostgresURL == "" {
+		return xerrors.Errorf("no database configured")
+	}
+
+	return nil
+}
@@ -1,9 +1,14 @@
 package cli_test
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/google/uuid"
@@ -213,6 +218,107 @@ func TestServerDBCrypt(t *testing.T) {
 	}
 }
 
+// TestServerDBCryptKeyProvider tests encryption and decryption of user data
+// with data keys that are wrapped by a key provider.
+//
+// nolint: paralleltest // use of t.Setenv
+func TestServerDBCryptKeyProvider(t *testing.T) {
+	if !dbtestutil.WillUsePostgres() {
+		t.Skip("this test requires a postgres instance")
+	}
+	if runtime.GOOS == "windows" {
+		t.Skip("the key command is a shell script")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	connectionURL, err := dbtestutil.Open(t)
+	require.NoError(t, err)
+	t.Cleanup(func() { dbtestutil.DumpOnFailure(t, connectionURL) })
+
+	sqlDB, err := sql.Open("postgres", connectionURL)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = sqlDB.Close()
+	})
+	db := database.New(sqlDB)
+	users := genData(t, db)
+
+	// The key command wraps keys by prefixing them.
+	script := filepath.Join(t.TempDir(), "kms")
+	err = os.WriteFile(script, []byte(`#!/bin/sh
+case "$1" in
+wrap) { printf 'wrapped:'; base64 -d; } | base64 ;;
+unwrap) base64 -d | tail -c +9 | base64 ;;
+esac
+`), 0o700) // #nosec G306
+	require.NoError(t, err)
+	t.Setenv("CODER_EXTERNAL_TOKEN_ENCRYPTION_KEY_PROVIDER", "command")
+	t.Setenv("CODER_EXTERNAL_TOKEN_ENCRYPTION_KEY_COMMAND", script)
+	provider := &dbcrypt.CommandKeyProvider{Command: script}
+
+	t.Logf("Encrypting all data with a data key")
+	inv, _ := newCLI(t, "server", "dbcrypt", "rotate",
+		"--postgres-url", connectionURL,
+		"--yes",
+	)
+	pty := ptytest.New(t)
+	inv.Stdout = pty.Output()
+	err = inv.Run()
+	require.NoError(t, err)
+	require.NoError(t, pty.Close())
+
+	// Validate that all data has been encrypted with a wrapped data key.
+	keys, err := db.GetDBCryptKeys(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.Equal(t, "command", keys[0].KeyProvider.String)
+	ciphers, err := dbcrypt.KeyProviderCiphers(ctx, db, provider)
+	require.NoError(t, err)
+	require.Len(t, ciphers, 1)
+	for _, usr := range users {
+		requireEncryptedWithCipher(ctx, t, db, ciphers[0], usr.ID)
+	}
+
+	// The status shows the tokens encrypted with the data key.
+	inv, _ = newCLI(t, "server", "dbcrypt", "status",
+		"--postgres-url", connectionURL,
+		"--output", "json",
+	)
+	var stdout bytes.Buffer
+	inv.Stdout = &stdout
+	err = inv.Run()
+	require.NoError(t, err)
+	var status []dbcrypt.KeyStatus
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &status))
+	require.Len(t, status, 1)
+	require.Equal(t, ciphers[0].HexDigest(), status[0].Digest)
+	require.Equal(t, "command", status[0].Provider)
+	require.Nil(t, status[0].RevokedAt)
+	require.NotZero(t, status[0].UserLinks)
+	require.EqualValues(t, len(users), status[0].ExternalAuthLinks)
+
+	t.Logf("Decrypting all data with the data key")
+	inv, _ = newCLI(t, "server", "dbcrypt", "decrypt",
+		"--postgres-url", connectionURL,
+		"--yes",
+	)
+	pty = ptytest.New(t)
+	inv.Stdout = pty.Output()
+	err = inv.Run()
+	require.NoError(t, err)
+	require.NoError(t, pty.Close())
+
+	for _, usr := range users {
+		requireEncryptedWithCipher(ctx, t, db, &nullCipher{}, usr.ID)
+	}
+	keys, err = db.GetDBCryptKeys(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.Empty(t, keys[0].ActiveKeyDigest.String)
+}
+
 func genData(t *testing.T, db database.Store) []database.User {
 	t.Helper()
 	var users []database.User
@@ -89,15 +89,27 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 	// Database encryption is an enterprise feature, but as checking license entitlements
 	// depends on the database, we end up in a chicken-and-egg situation. To avoid this,
 	// we always enable it but only soft-enforce it.
-	if len(options.ExternalTokenEncryption) > 0 {
+	if len(options.ExternalTokenEncryption) > 0 || options.ExternalTokenEncryptionKeyProvider != nil {
 		var keyDigests []string
 		for _, cipher := range options.ExternalTokenEncryption {
 			keyDigests = append(keyDigests, cipher.HexDigest())
 		}
-		options.Logger.Info(ctx, "database encryption enabled", slog.F("keys", keyDigests))
+		fields := []any{slog.F("keys", keyDigests)}
+		if options.ExternalTokenEncryptionKeyProvider != nil {
+			fields = append(fields, slog.F("key_provider", options.ExternalTokenEncryptionKeyProvider.Name()))
+		}
+		options.Logger.Info(ctx, "database encryption enabled", fields...)
 	}
 
-	cryptDB, err := dbcrypt.New(ctx, options.Database, options.ExternalTokenEncryption...)
+	var cryptDB database.Store
+	if options.ExternalTokenEncryptionKeyProvider != nil {
+		cryptDB, err = dbcrypt.NewWithKeyProvider(ctx, options.Database, options.ExternalTokenEncryptionKeyProvider, options.ExternalTokenEncryption...)
+		if err == nil {
+			err = dbcrypt.StartKeyRotator(ctx, options.Logger, cryptDB, dbcrypt.WithKeyRotationInterval(options.ExternalTokenEncryptionRotationInterval))
+		}
+	} else {
+		cryptDB, err = dbcrypt.New(ctx, options.Database, options.ExternalTokenEncryption...)
+	}
 	if err != nil {
 		cancelFunc()
 		// If we fail to initialize the database, it's likely that the
@@ -418,6 +430,9 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 				r.Get("/", api.group)
 				r.Patch("/", api.patchGroup)
 				r.Delete("/", api.deleteGroup)
+				r.Get("/cost-budget", api.groupCostBudget)
+				r.Put("/cost-budget", api.putGroupCostBudget)
+				r.Delete("/cost-budget", api.deleteGroupCostBudget)
 			})
 		})
 		r.Route("/workspace-quota", func(r chi.Router) {
@@ -491,6 +506,14 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 				r.Patch("/{id}", api.scimPatchUser)
 				r.Put("/{id}", api.scimPutUser)
 			})
+			r.Route("/Groups", func(r chi.Router) {
+				r.Get("/", api.scimGetGroups)
+				r.Post("/", api.scimPostGroup)
+				r.Get("/{id}", api.scimGetGroup)
+				r.Patch("/{id}", api.scimPatchGroup)
+				r.Put("/{id}", api.scimPutGroup)
+				r.Delete("/{id}", api.scimDeleteGroup)
+			})
 			r.NotFound(func(w http.ResponseWriter, r *http.Request) {
 				u := r.URL.String()
 				httpapi.Write(r.Context(), w, http.StatusNotFound, codersdk.Response{
@@ -585,6 +608,13 @@ type Options struct {
 	SCIMAPIKey  []byte
 
 	ExternalTokenEncryption []dbcrypt.Cipher
+	// ExternalTokenEncryptionKeyProvider wraps the data keys that encrypt
+	// external tokens. ExternalTokenEncryption ciphers are then only used to
+	// decrypt existing tokens.
+	ExternalTokenEncryptionKeyProvider dbcrypt.KeyProvider
+	// ExternalTokenEncryptionRotationInterval is the age after which the data
+	// key is replaced. Zero disables rotation.
+	ExternalTokenEncryptionRotationInterval time.Duration
 
 	// Used for high availability.
 	ReplicaSyncUpdateInterval time.Duration
@@ -683,7 +713,7 @@ func (api *API) updateEntitlements(ctx context.Context) error {
 				codersdk.FeatureSCIM:                       len(api.SCIMAPIKey) != 0,
 				codersdk.FeatureMultipleExternalAuth:       len(api.ExternalAuthConfigs) > 1,
 				codersdk.FeatureTemplateRBAC:               api.RBAC,
-				codersdk.FeatureExternalTokenEncryption:    len(api.ExternalTokenEncryption) > 0,
+				codersdk.FeatureExternalTokenEncryption:    len(api.ExternalTokenEncryption) > 0 || api.ExternalTokenEncryptionKeyProvider != nil,
 				codersdk.FeatureExternalProvisionerDaemons: true,
 				codersdk.FeatureAdvancedTemplateScheduling: true,
 				codersdk.FeatureWorkspaceProxy:             true,
@@ -859,7 +889,7 @@ func (api *API) updateEntitlements(ctx context.Context) error {
 
 		// External token encryption is soft-enforced
 		featureExternalTokenEncryption := reloadedEntitlements.Features[codersdk.FeatureExternalTokenEncryption]
-		featureExternalTokenEncryption.Enabled = len(api.ExternalTokenEncryption) > 0
+		featureExternalTokenEncryption.Enabled = len(api.ExternalTokenEncryption) > 0 || api.ExternalTokenEncryptionKeyProvider != nil
 		if featureExternalTokenEncryption.Enabled && featureExternalTokenEncryption.Entitlement != codersdk.EntitlementEntitled {
 			msg := fmt.Sprintf("%s is enabled (due to setting external token encryption keys) but your license is not entitled to this feature.", codersdk.FeatureExternalTokenEncryption.Humanize())
 			api.Logger.Warn(ctx, msg)
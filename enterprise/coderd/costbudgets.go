@@ -0,0 +1,119 @@
+package coderd
+
+import (
+	"net/http"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get cost budget by group
+// @ID get-cost-budget-by-group
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param group path string true "Group id"
+// @Success 200 {object} codersdk.CostBudget
+// @Router /groups/{group}/cost-budget [get]
+func (api *API) groupCostBudget(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx   = r.Context()
+		group = httpmw.GroupParam(r)
+	)
+
+	budget, err := api.Database.GetCostBudgetByGroupID(ctx, group.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertCostBudget(budget))
+}
+
+// @Summary Upsert cost budget by group
+// @ID upsert-cost-budget-by-group
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Enterprise
+// @Param group path string true "Group id"
+// @Param request body codersdk.UpsertCostBudgetRequest true "Upsert cost budget request"
+// @Success 200 {object} codersdk.CostBudget
+// @Router /groups/{group}/cost-budget [put]
+func (api *API) putGroupCostBudget(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx   = r.Context()
+		group = httpmw.GroupParam(r)
+	)
+
+	var req codersdk.UpsertCostBudgetRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	now := dbtime.Now()
+	budget, err := api.Database.UpsertCostBudget(ctx, database.UpsertCostBudgetParams{
+		GroupID:                group.ID,
+		OrganizationID:         group.OrganizationID,
+		CreatedAt:              now,
+		UpdatedAt:              now,
+		MonthlyLimit:           req.MonthlyLimit,
+		NotifyThresholdPercent: req.NotifyThresholdPercent,
+		StopThresholdPercent:   req.StopThresholdPercent,
+	})
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertCostBudget(budget))
+}
+
+// @Summary Delete cost budget by group
+// @ID delete-cost-budget-by-group
+// @Security CoderSessionToken
+// @Tags Enterprise
+// @Param group path string true "Group id"
+// @Success 204
+// @Router /groups/{group}/cost-budget [delete]
+func (api *API) deleteGroupCostBudget(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx   = r.Context()
+		group = httpmw.GroupParam(r)
+	)
+
+	err := api.Database.DeleteCostBudgetByGroupID(ctx, group.ID)
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
+}
+
+func convertCostBudget(budget database.CostBudget) codersdk.CostBudget {
+	return codersdk.CostBudget{
+		GroupID:                budget.GroupID,
+		OrganizationID:         budget.OrganizationID,
+		CreatedAt:              budget.CreatedAt,
+		UpdatedAt:              budget.UpdatedAt,
+		MonthlyLimit:           budget.MonthlyLimit,
+		NotifyThresholdPercent: budget.NotifyThresholdPercent,
+		StopThresholdPercent:   budget.StopThresholdPercent,
+	}
+}
@@ -0,0 +1,120 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/v2/enterprise/coderd/license"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestGroupCostBudget(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		client, user := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureTemplateRBAC: 1,
+			},
+		}})
+		userAdminClient, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID, rbac.RoleUserAdmin())
+		ctx := testutil.Context(t, testutil.WaitLong)
+		group, err := userAdminClient.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "developers",
+		})
+		require.NoError(t, err)
+
+		_, err = userAdminClient.GroupCostBudget(ctx, group.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+		budget, err := userAdminClient.UpsertGroupCostBudget(ctx, group.ID, codersdk.UpsertCostBudgetRequest{
+			MonthlyLimit:           500,
+			NotifyThresholdPercent: 80,
+		})
+		require.NoError(t, err)
+		require.Equal(t, group.ID, budget.GroupID)
+		require.Equal(t, user.OrganizationID, budget.OrganizationID)
+		require.Equal(t, 500.0, budget.MonthlyLimit)
+		require.EqualValues(t, 80, budget.NotifyThresholdPercent)
+		require.EqualValues(t, 0, budget.StopThresholdPercent)
+
+		updated, err := userAdminClient.UpsertGroupCostBudget(ctx, group.ID, codersdk.UpsertCostBudgetRequest{
+			MonthlyLimit:           750,
+			NotifyThresholdPercent: 80,
+			StopThresholdPercent:   100,
+		})
+		require.NoError(t, err)
+		require.Equal(t, budget.CreatedAt, updated.CreatedAt)
+		require.Equal(t, 750.0, updated.MonthlyLimit)
+		require.EqualValues(t, 100, updated.StopThresholdPercent)
+
+		fetched, err := userAdminClient.GroupCostBudget(ctx, group.ID)
+		require.NoError(t, err)
+		require.Equal(t, updated, fetched)
+
+		err = userAdminClient.DeleteGroupCostBudget(ctx, group.ID)
+		require.NoError(t, err)
+		_, err = userAdminClient.GroupCostBudget(ctx, group.ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("InvalidLimit", func(t *testing.T) {
+		t.Parallel()
+
+		client, user := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureTemplateRBAC: 1,
+			},
+		}})
+		ctx := testutil.Context(t, testutil.WaitLong)
+		group, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "developers",
+		})
+		require.NoError(t, err)
+
+		_, err = client.UpsertGroupCostBudget(ctx, group.ID, codersdk.UpsertCostBudgetRequest{
+			MonthlyLimit: 0,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("MemberCannotUpdate", func(t *testing.T) {
+		t.Parallel()
+
+		client, user := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureTemplateRBAC: 1,
+			},
+		}})
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		group, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "developers",
+		})
+		require.NoError(t, err)
+		_, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+			AddUsers: []string{member.ID.String()},
+		})
+		require.NoError(t, err)
+
+		_, err = memberClient.UpsertGroupCostBudget(ctx, group.ID, codersdk.UpsertCostBudgetRequest{
+			MonthlyLimit: 10000,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
@@ -355,9 +355,11 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 		api.AGPL.UserQuietHoursScheduleStore,
 		api.DeploymentValues,
 		provisionerdserver.Options{
-			ExternalAuthConfigs: api.ExternalAuthConfigs,
-			OIDCConfig:          api.OIDCConfig,
-			Clock:               api.Clock,
+			ExternalAuthConfigs:       api.ExternalAuthConfigs,
+			OIDCConfig:                api.OIDCConfig,
+			Clock:                     api.Clock,
+			WorkspaceBuildCompletedFn: api.AGPL.WorkspaceBuildCompleted,
+			APIVersion:                apiVersion,
 		},
 		api.NotificationsEnqueuer,
 	)
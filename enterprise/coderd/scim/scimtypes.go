@@ -83,3 +83,28 @@ func (e HTTPError) MarshalJSON() ([]byte, error) {
 func (e HTTPError) Unwrap() error {
 	return e.scim
 }
+
+// ListResponse is the response to a SCIM query for multiple resources.
+// See: https://datatracker.ietf.org/doc/html/rfc7644#section-3.4.2
+type ListResponse[T any] struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []T      `json:"Resources"`
+}
+
+// PatchOp is the request body of a SCIM PATCH request.
+// See: https://datatracker.ietf.org/doc/html/rfc7644#section-3.5.2
+type PatchOp struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+type PatchOperation struct {
+	// Op is one of "add", "remove" or "replace". Some identity providers
+	// capitalize it, so it must be compared case-insensitively.
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
@@ -7,9 +7,11 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 	"github.com/imulab/go-scim/pkg/v2/handlerutil"
 	"github.com/imulab/go-scim/pkg/v2/spec"
 	"github.com/stretchr/testify/assert"
@@ -861,6 +863,222 @@ func TestScim(t *testing.T) {
 	})
 }
 
+//nolint:gocritic // SCIM authenticates via a special header and bypasses internal RBAC.
+func TestScimGroups(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) (*codersdk.Client, codersdk.CreateFirstUserResponse, *audit.MockAuditor, []byte) {
+		t.Helper()
+
+		scimAPIKey := []byte("hi")
+		mockAudit := audit.NewMock()
+		client, owner := coderdenttest.New(t, &coderdenttest.Options{
+			Options:      &coderdtest.Options{Auditor: mockAudit},
+			SCIMAPIKey:   scimAPIKey,
+			AuditLogging: true,
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				AccountID: "coolin",
+				Features: license.Features{
+					codersdk.FeatureSCIM:         1,
+					codersdk.FeatureAuditLog:     1,
+					codersdk.FeatureTemplateRBAC: 1,
+				},
+			},
+		})
+		mockAudit.ResetLogs()
+		return client, owner, mockAudit, scimAPIKey
+	}
+
+	decode := func(t *testing.T, res *http.Response, status int) coderd.SCIMGroup {
+		t.Helper()
+		defer res.Body.Close()
+		require.Equal(t, status, res.StatusCode)
+
+		var sGroup coderd.SCIMGroup
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&sGroup))
+		return sGroup
+	}
+
+	t.Run("noAuth", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, _, _, _ := setup(t)
+
+		res, err := client.Request(ctx, http.MethodGet, "/scim/v2/Groups", nil)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	})
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, owner, mockAudit, scimAPIKey := setup(t)
+		_, alice := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		_, bob := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		mockAudit.ResetLogs()
+
+		// Create a group with a display name which is not a valid group name.
+		res, err := client.Request(ctx, http.MethodPost, "/scim/v2/Groups", coderd.SCIMGroup{
+			DisplayName: "Platform Engineers",
+			Members:     []coderd.SCIMGroupMember{{Value: alice.ID.String()}},
+		}, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		sGroup := decode(t, res, http.StatusCreated)
+		assert.Equal(t, "Platform Engineers", sGroup.DisplayName)
+		require.Len(t, sGroup.Members, 1)
+		assert.Equal(t, alice.ID.String(), sGroup.Members[0].Value)
+
+		aLogs := mockAudit.AuditLogs()
+		require.Len(t, aLogs, 1)
+		assert.Equal(t, database.AuditActionCreate, aLogs[0].Action)
+		assert.Equal(t, database.ResourceTypeGroup, aLogs[0].ResourceType)
+		af := map[string]string{}
+		require.NoError(t, json.Unmarshal([]byte(aLogs[0].AdditionalFields), &af))
+		assert.Equal(t, coderd.SCIMAuditAdditionalFields, af)
+
+		group, err := client.Group(ctx, uuid.MustParse(sGroup.ID))
+		require.NoError(t, err)
+		assert.Equal(t, "Platform-Engineers", group.Name)
+		assert.Equal(t, "Platform Engineers", group.DisplayName)
+		assert.Equal(t, codersdk.GroupSourceSCIM, group.Source)
+
+		// The group can be found by its display name.
+		res, err = client.Request(ctx, http.MethodGet, "/scim/v2/Groups?filter="+url.QueryEscape(`displayName eq "Platform Engineers"`), nil, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		var list scim.ListResponse[coderd.SCIMGroup]
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&list))
+		_ = res.Body.Close()
+		require.Equal(t, 1, list.TotalResults)
+		assert.Equal(t, sGroup.ID, list.Resources[0].ID)
+
+		// Azure AD style member operations.
+		mockAudit.ResetLogs()
+		res, err = client.Request(ctx, http.MethodPatch, "/scim/v2/Groups/"+sGroup.ID, scim.PatchOp{
+			Operations: []scim.PatchOperation{
+				{Op: "Add", Path: "members", Value: json.RawMessage(`[{"value":"` + bob.ID.String() + `"}]`)},
+				{Op: "Remove", Path: "members", Value: json.RawMessage(`[{"value":"` + alice.ID.String() + `"}]`)},
+			},
+		}, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		sGroup = decode(t, res, http.StatusOK)
+		require.Len(t, sGroup.Members, 1)
+		assert.Equal(t, bob.ID.String(), sGroup.Members[0].Value)
+		aLogs = mockAudit.AuditLogs()
+		require.Len(t, aLogs, 1)
+		assert.Equal(t, database.AuditActionWrite, aLogs[0].Action)
+
+		// Okta style operations.
+		mockAudit.ResetLogs()
+		res, err = client.Request(ctx, http.MethodPatch, "/scim/v2/Groups/"+sGroup.ID, scim.PatchOp{
+			Operations: []scim.PatchOperation{
+				{Op: "replace", Value: json.RawMessage(`{"id":"` + sGroup.ID + `","displayName":"SRE"}`)},
+				{Op: "remove", Path: `members[value eq "` + bob.ID.String() + `"]`},
+			},
+		}, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		sGroup = decode(t, res, http.StatusOK)
+		assert.Equal(t, "SRE", sGroup.DisplayName)
+		assert.Empty(t, sGroup.Members)
+		require.Len(t, mockAudit.AuditLogs(), 1)
+
+		// No changes should not produce an audit log.
+		mockAudit.ResetLogs()
+		res, err = client.Request(ctx, http.MethodPut, "/scim/v2/Groups/"+sGroup.ID, coderd.SCIMGroup{
+			DisplayName: "SRE",
+		}, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		_ = decode(t, res, http.StatusOK)
+		require.Empty(t, mockAudit.AuditLogs())
+
+		res, err = client.Request(ctx, http.MethodPut, "/scim/v2/Groups/"+sGroup.ID, coderd.SCIMGroup{
+			DisplayName: "SRE",
+			Members:     []coderd.SCIMGroupMember{{Value: alice.ID.String()}, {Value: bob.ID.String()}},
+		}, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		sGroup = decode(t, res, http.StatusOK)
+		require.Len(t, sGroup.Members, 2)
+		require.Len(t, mockAudit.AuditLogs(), 1)
+
+		mockAudit.ResetLogs()
+		res, err = client.Request(ctx, http.MethodDelete, "/scim/v2/Groups/"+sGroup.ID, nil, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		_ = res.Body.Close()
+		require.Equal(t, http.StatusNoContent, res.StatusCode)
+		aLogs = mockAudit.AuditLogs()
+		require.Len(t, aLogs, 1)
+		assert.Equal(t, database.AuditActionDelete, aLogs[0].Action)
+
+		res, err = client.Request(ctx, http.MethodGet, "/scim/v2/Groups/"+sGroup.ID, nil, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		_ = res.Body.Close()
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("Duplicate", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, _, _, scimAPIKey := setup(t)
+
+		sGroup := coderd.SCIMGroup{DisplayName: "dupe"}
+		res, err := client.Request(ctx, http.MethodPost, "/scim/v2/Groups", sGroup, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		_ = decode(t, res, http.StatusCreated)
+
+		res, err = client.Request(ctx, http.MethodPost, "/scim/v2/Groups", sGroup, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		_ = res.Body.Close()
+		require.Equal(t, http.StatusConflict, res.StatusCode)
+	})
+
+	t.Run("EveryoneHidden", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, owner, _, scimAPIKey := setup(t)
+
+		res, err := client.Request(ctx, http.MethodGet, "/scim/v2/Groups", nil, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		var list scim.ListResponse[coderd.SCIMGroup]
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&list))
+		_ = res.Body.Close()
+		require.Zero(t, list.TotalResults)
+
+		res, err = client.Request(ctx, http.MethodPatch, "/scim/v2/Groups/"+owner.OrganizationID.String(), scim.PatchOp{
+			Operations: []scim.PatchOperation{{Op: "remove", Path: "members"}},
+		}, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		_ = res.Body.Close()
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("InvalidMember", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, _, _, scimAPIKey := setup(t)
+
+		res, err := client.Request(ctx, http.MethodPost, "/scim/v2/Groups", coderd.SCIMGroup{
+			DisplayName: "ghosts",
+			Members:     []coderd.SCIMGroupMember{{Value: uuid.NewString()}},
+		}, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		_ = res.Body.Close()
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+		// The group must not have been created.
+		res, err = client.Request(ctx, http.MethodGet, "/scim/v2/Groups", nil, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		var list scim.ListResponse[coderd.SCIMGroup]
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&list))
+		_ = res.Body.Close()
+		require.Zero(t, list.TotalResults)
+	})
+}
+
 func TestScimError(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,764 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/imulab/go-scim/pkg/v2/handlerutil"
+	"github.com/imulab/go-scim/pkg/v2/spec"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/util/slice"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/scim"
+)
+
+const (
+	scimGroupSchema        = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	scimListResponseSchema = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+)
+
+var (
+	// scimGroupFilterRegex matches the only filter identity providers use
+	// for groups, which is an exact match on the display name.
+	scimGroupFilterRegex = regexp.MustCompile(`(?i)^displayName\s+eq\s+"(.*)"$`)
+	// scimMemberPathRegex matches a path selecting a single group member,
+	// e.g. `members[value eq "<user id>"]`.
+	scimMemberPathRegex = regexp.MustCompile(`(?i)^members\[value\s+eq\s+"(.*)"\]$`)
+	scimGroupNameRegex  = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+)
+
+// SCIMGroup is the SCIM representation of a Coder group. Like SCIMUser, this
+// only includes the fields we need. Groups provisioned over SCIM always belong
+// to the default organization.
+type SCIMGroup struct {
+	Schemas     []string          `json:"schemas"`
+	ID          string            `json:"id"`
+	DisplayName string            `json:"displayName"`
+	Members     []SCIMGroupMember `json:"members"`
+	Meta        struct {
+		ResourceType string `json:"resourceType"`
+	} `json:"meta"`
+}
+
+type SCIMGroupMember struct {
+	// Value is the ID of the Coder user, as returned by the Users endpoint.
+	Value   string `json:"value" format:"uuid"`
+	Display string `json:"display,omitempty"`
+}
+
+// scimGroupState is the desired name and membership of a group.
+type scimGroupState struct {
+	DisplayName string
+	Members     []uuid.UUID
+}
+
+// scimGetGroups returns the groups of the default organization.
+//
+// @Summary SCIM 2.0: Get groups
+// @ID scim-get-groups
+// @Security Authorization
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param filter query string false "Filter, only 'displayName eq \"<name>\"' is supported"
+// @Param startIndex query int false "1-based index of the first result"
+// @Param count query int false "Maximum number of results"
+// @Success 200
+// @Router /scim/v2/Groups [get]
+func (api *API) scimGetGroups(rw http.ResponseWriter, r *http.Request) {
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+	//nolint:gocritic // SCIM operations are a system user
+	ctx := dbauthz.AsSystemRestricted(r.Context())
+
+	query := r.URL.Query()
+	startIndex, err := scimQueryInt(query.Get("startIndex"), 1)
+	if err != nil || startIndex < 1 {
+		// Per the spec, values less than 1 are interpreted as 1.
+		startIndex = 1
+	}
+	count, err := scimQueryInt(query.Get("count"), -1)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusBadRequest, spec.ErrInvalidValue.Type, xerrors.Errorf("invalid count: %w", err)))
+		return
+	}
+
+	var displayName *string
+	if filter := strings.TrimSpace(query.Get("filter")); filter != "" {
+		match := scimGroupFilterRegex.FindStringSubmatch(filter)
+		if match == nil {
+			_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusBadRequest, spec.ErrInvalidFilter.Type, xerrors.Errorf("unsupported filter %q", filter)))
+			return
+		}
+		displayName = &match[1]
+	}
+
+	org, err := api.Database.GetDefaultOrganization(ctx)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err) // internal error
+		return
+	}
+	rows, err := api.Database.GetGroups(ctx, database.GetGroupsParams{
+		OrganizationID: org.ID,
+	})
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err) // internal error
+		return
+	}
+
+	groups := make([]database.Group, 0, len(rows))
+	for _, row := range rows {
+		if row.Group.IsEveryone() {
+			continue
+		}
+		if displayName != nil && scimGroupDisplayName(row.Group) != *displayName {
+			continue
+		}
+		groups = append(groups, row.Group)
+	}
+
+	total := len(groups)
+	groups = groups[min(startIndex-1, total):]
+	if count >= 0 && count < len(groups) {
+		groups = groups[:count]
+	}
+
+	// Identity providers commonly exclude members when listing, since they
+	// may be very large. Avoid fetching them if they're not needed.
+	excludeMembers := strings.Contains(strings.ToLower(query.Get("excludedAttributes")), "members")
+	resources := make([]SCIMGroup, 0, len(groups))
+	for _, group := range groups {
+		var members []database.GroupMember
+		if !excludeMembers {
+			members, err = api.Database.GetGroupMembersByGroupID(ctx, group.ID)
+			if err != nil {
+				_ = handlerutil.WriteError(rw, err) // internal error
+				return
+			}
+		}
+		resources = append(resources, scimGroupFromDB(group, members))
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, scim.ListResponse[SCIMGroup]{
+		Schemas:      []string{scimListResponseSchema},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	})
+}
+
+// @Summary SCIM 2.0: Get group by ID
+// @ID scim-get-group-by-id
+// @Security Authorization
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Success 200 {object} coderd.SCIMGroup
+// @Failure 404
+// @Router /scim/v2/Groups/{id} [get]
+func (api *API) scimGetGroup(rw http.ResponseWriter, r *http.Request) {
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+	//nolint:gocritic // SCIM operations are a system user
+	ctx := dbauthz.AsSystemRestricted(r.Context())
+
+	group, ok := api.scimGroupParam(ctx, rw, r)
+	if !ok {
+		return
+	}
+	members, err := api.Database.GetGroupMembersByGroupID(ctx, group.ID)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err) // internal error
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, scimGroupFromDB(group, members))
+}
+
+// scimPostGroup creates a new group in the default organization.
+//
+// @Summary SCIM 2.0: Create new group
+// @ID scim-create-new-group
+// @Security Authorization
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param request body coderd.SCIMGroup true "New group"
+// @Success 201 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups [post]
+func (api *API) scimPostGroup(rw http.ResponseWriter, r *http.Request) {
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+	//nolint:gocritic // SCIM operations are a system user
+	ctx := dbauthz.AsSystemRestricted(r.Context())
+
+	org, err := api.Database.GetDefaultOrganization(ctx)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err) // internal error
+		return
+	}
+
+	var sGroup SCIMGroup
+	err = json.NewDecoder(r.Body).Decode(&sGroup)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusBadRequest, "invalidRequest", err))
+		return
+	}
+
+	state, err := scimGroupStateFrom(sGroup.DisplayName, sGroup.Members)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+	name, err := scimGroupName(state.DisplayName)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+	err = api.scimValidateMembers(ctx, org.ID, nil, state.Members)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	var group database.Group
+	err = api.Database.InTx(func(tx database.Store) error {
+		// InsertMissingGroups is used rather than InsertGroup because it
+		// allows setting the source of the group.
+		inserted, err := tx.InsertMissingGroups(ctx, database.InsertMissingGroupsParams{
+			OrganizationID: org.ID,
+			Source:         database.GroupSourceScim,
+			GroupNames:     []string{name},
+		})
+		if err != nil {
+			return xerrors.Errorf("insert group: %w", err)
+		}
+		if len(inserted) == 0 {
+			return scim.NewHTTPError(http.StatusConflict, spec.ErrUniqueness.Type, xerrors.Errorf("a group named %q already exists", name))
+		}
+
+		group, _, err = scimUpdateGroup(ctx, tx, inserted[0], state)
+		return err
+	}, nil)
+	if err != nil {
+		scimWriteError(rw, err)
+		return
+	}
+
+	members, err := api.Database.GetGroupMembersByGroupID(ctx, group.ID)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err) // internal error
+		return
+	}
+	api.scimAuditGroup(ctx, r, database.AuditActionCreate, http.StatusCreated, database.AuditableGroup{}, group.Auditable(members))
+
+	httpapi.Write(ctx, rw, http.StatusCreated, scimGroupFromDB(group, members))
+}
+
+// scimPatchGroup supports updating the display name and members of a group.
+//
+// @Summary SCIM 2.0: Update group
+// @ID scim-update-group
+// @Security Authorization
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Param request body scim.PatchOp true "Patch operations"
+// @Success 200 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups/{id} [patch]
+func (api *API) scimPatchGroup(rw http.ResponseWriter, r *http.Request) {
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+
+	var patch scim.PatchOp
+	err := json.NewDecoder(r.Body).Decode(&patch)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusBadRequest, "invalidRequest", err))
+		return
+	}
+
+	api.scimUpdateGroupHandler(rw, r, func(state *scimGroupState) error {
+		for _, op := range patch.Operations {
+			err := state.apply(op)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// scimPutGroup replaces the display name and members of a group.
+//
+// @Summary SCIM 2.0: Replace group
+// @ID scim-replace-group
+// @Security Authorization
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Param request body coderd.SCIMGroup true "Replace group request"
+// @Success 200 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups/{id} [put]
+func (api *API) scimPutGroup(rw http.ResponseWriter, r *http.Request) {
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+
+	var sGroup SCIMGroup
+	err := json.NewDecoder(r.Body).Decode(&sGroup)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusBadRequest, "invalidRequest", err))
+		return
+	}
+
+	api.scimUpdateGroupHandler(rw, r, func(state *scimGroupState) error {
+		replaced, err := scimGroupStateFrom(sGroup.DisplayName, sGroup.Members)
+		if err != nil {
+			return err
+		}
+		*state = replaced
+		return nil
+	})
+}
+
+// scimDeleteGroup deletes a group.
+//
+// @Summary SCIM 2.0: Delete group
+// @ID scim-delete-group
+// @Security Authorization
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Success 204
+// @Router /scim/v2/Groups/{id} [delete]
+func (api *API) scimDeleteGroup(rw http.ResponseWriter, r *http.Request) {
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+	//nolint:gocritic // SCIM operations are a system user
+	ctx := dbauthz.AsSystemRestricted(r.Context())
+
+	group, ok := api.scimGroupParam(ctx, rw, r)
+	if !ok {
+		return
+	}
+
+	members, err := api.Database.GetGroupMembersByGroupID(ctx, group.ID)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err) // internal error
+		return
+	}
+
+	//nolint:gocritic // Only SCIM may delete the groups it provisioned.
+	err = api.Database.DeleteGroupByID(dbauthz.AsSCIMGroupSync(r.Context()), group.ID)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err) // internal error
+		return
+	}
+	api.scimAuditGroup(ctx, r, database.AuditActionDelete, http.StatusNoContent, group.Auditable(members), database.AuditableGroup{})
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// scimUpdateGroupHandler is shared by the PATCH and PUT handlers. The update
+// function mutates the current state of the group into the desired state,
+// which is then applied to the database.
+func (api *API) scimUpdateGroupHandler(rw http.ResponseWriter, r *http.Request, update func(state *scimGroupState) error) {
+	//nolint:gocritic // SCIM operations are a system user
+	ctx := dbauthz.AsSystemRestricted(r.Context())
+
+	group, ok := api.scimGroupParam(ctx, rw, r)
+	if !ok {
+		return
+	}
+
+	members, err := api.Database.GetGroupMembersByGroupID(ctx, group.ID)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err) // internal error
+		return
+	}
+	oldGroup := group.Auditable(members)
+
+	state := scimGroupState{
+		DisplayName: scimGroupDisplayName(group),
+		Members:     scimMemberIDs(members),
+	}
+	err = update(&state)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+	err = api.scimValidateMembers(ctx, group.OrganizationID, scimMemberIDs(members), state.Members)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	var changed bool
+	err = api.Database.InTx(func(tx database.Store) error {
+		group, changed, err = scimUpdateGroup(ctx, tx, group, state)
+		return err
+	}, nil)
+	if err != nil {
+		scimWriteError(rw, err)
+		return
+	}
+
+	members, err = api.Database.GetGroupMembersByGroupID(ctx, group.ID)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err) // internal error
+		return
+	}
+	// Do not push an audit log if there is no change.
+	if changed {
+		api.scimAuditGroup(ctx, r, database.AuditActionWrite, http.StatusOK, oldGroup, group.Auditable(members))
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, scimGroupFromDB(group, members))
+}
+
+// scimAuditGroup audits a successful change to a group. Unlike users, there is
+// no user to attribute the change to, which audit.InitRequest requires, so the
+// change is audited as a background event performed by Coder.
+func (api *API) scimAuditGroup(ctx context.Context, r *http.Request, action database.AuditAction, status int, old, new database.AuditableGroup) {
+	orgID := new.OrganizationID
+	if action == database.AuditActionDelete {
+		orgID = old.OrganizationID
+	}
+
+	additionalFields, err := json.Marshal(SCIMAuditAdditionalFields)
+	if err != nil {
+		api.Logger.Warn(ctx, "marshal additional fields", slog.Error(err))
+		additionalFields = json.RawMessage("{}")
+	}
+
+	audit.BackgroundAudit(ctx, &audit.BackgroundAuditParams[database.AuditableGroup]{
+		Audit:            *api.AGPL.Auditor.Load(),
+		Log:              api.Logger,
+		RequestID:        httpmw.RequestID(r),
+		Status:           status,
+		Action:           action,
+		OrganizationID:   orgID,
+		IP:               r.RemoteAddr,
+		AdditionalFields: additionalFields,
+		Old:              old,
+		New:              new,
+	})
+}
+
+// scimGroupParam fetches the group from the "id" URL parameter. Only groups in
+// the default organization are exposed over SCIM, and the "Everyone" group is
+// excluded as its membership is implicit.
+func (api *API) scimGroupParam(ctx context.Context, rw http.ResponseWriter, r *http.Request) (database.Group, bool) {
+	notFound := func() {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusNotFound, spec.ErrNotFound.Type, xerrors.New("group not found")))
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		notFound()
+		return database.Group{}, false
+	}
+
+	group, err := api.Database.GetGroupByID(ctx, id)
+	if xerrors.Is(err, sql.ErrNoRows) {
+		notFound()
+		return database.Group{}, false
+	}
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err) // internal error
+		return database.Group{}, false
+	}
+
+	org, err := api.Database.GetDefaultOrganization(ctx)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err) // internal error
+		return database.Group{}, false
+	}
+	if group.OrganizationID != org.ID || group.IsEveryone() {
+		notFound()
+		return database.Group{}, false
+	}
+
+	return group, true
+}
+
+// scimUpdateGroup applies the desired state to the group, returning the
+// updated group and whether any changes were made. It should be called within
+// a transaction.
+func scimUpdateGroup(ctx context.Context, tx database.Store, group database.Group, state scimGroupState) (database.Group, bool, error) {
+	var changed bool
+
+	if state.DisplayName != scimGroupDisplayName(group) {
+		name, err := scimGroupName(state.DisplayName)
+		if err != nil {
+			return database.Group{}, false, err
+		}
+
+		displayName := state.DisplayName
+		if displayName == name {
+			displayName = ""
+		}
+		group, err = tx.UpdateGroupByID(ctx, database.UpdateGroupByIDParams{
+			ID:             group.ID,
+			Name:           name,
+			DisplayName:    displayName,
+			AvatarURL:      group.AvatarURL,
+			QuotaAllowance: group.QuotaAllowance,
+		})
+		if database.IsUniqueViolation(err) {
+			return database.Group{}, false, scim.NewHTTPError(http.StatusConflict, spec.ErrUniqueness.Type, xerrors.Errorf("a group named %q already exists", name))
+		}
+		if err != nil {
+			return database.Group{}, false, xerrors.Errorf("update group: %w", err)
+		}
+		changed = true
+	}
+
+	current, err := tx.GetGroupMembersByGroupID(ctx, group.ID)
+	if err != nil {
+		return database.Group{}, false, xerrors.Errorf("get group members: %w", err)
+	}
+	add, remove := slice.SymmetricDifference(scimMemberIDs(current), state.Members)
+
+	for _, userID := range add {
+		err = tx.InsertGroupMember(ctx, database.InsertGroupMemberParams{
+			GroupID: group.ID,
+			UserID:  userID,
+		})
+		if err != nil {
+			return database.Group{}, false, xerrors.Errorf("insert group member %q: %w", userID, err)
+		}
+	}
+	for _, userID := range remove {
+		err = tx.DeleteGroupMemberFromGroup(ctx, database.DeleteGroupMemberFromGroupParams{
+			UserID:  userID,
+			GroupID: group.ID,
+		})
+		if err != nil {
+			return database.Group{}, false, xerrors.Errorf("delete group member %q: %w", userID, err)
+		}
+	}
+
+	return group, changed || len(add) > 0 || len(remove) > 0, nil
+}
+
+// scimValidateMembers ensures that all users being added to a group are
+// members of the group's organization.
+func (api *API) scimValidateMembers(ctx context.Context, orgID uuid.UUID, current, desired []uuid.UUID) error {
+	add, _ := slice.SymmetricDifference(current, desired)
+	for _, userID := range add {
+		// TODO: It would be nice to enforce this at the schema level
+		// but unfortunately our org_members table does not have an ID.
+		_, err := database.ExpectOne(api.Database.OrganizationMembers(ctx, database.OrganizationMembersParams{
+			OrganizationID: orgID,
+			UserID:         userID,
+		}))
+		if xerrors.Is(err, sql.ErrNoRows) {
+			return scim.NewHTTPError(http.StatusBadRequest, spec.ErrInvalidValue.Type, xerrors.Errorf("user %q is not a member of the default organization", userID))
+		}
+		if err != nil {
+			return xerrors.Errorf("get organization member %q: %w", userID, err)
+		}
+	}
+	return nil
+}
+
+// apply applies a single PATCH operation to the state. The path syntaxes used
+// by Okta and Azure AD are supported.
+// See: https://datatracker.ietf.org/doc/html/rfc7644#section-3.5.2
+func (s *scimGroupState) apply(op scim.PatchOperation) error {
+	invalidValue := func(err error) error {
+		return scim.NewHTTPError(http.StatusBadRequest, spec.ErrInvalidValue.Type, err)
+	}
+	kind := strings.ToLower(op.Op)
+	path := strings.TrimSpace(op.Path)
+
+	switch {
+	case path == "" && (kind == "add" || kind == "replace"):
+		// Without a path, the value contains the attributes to modify.
+		var value struct {
+			DisplayName *string            `json:"displayName"`
+			Members     *[]SCIMGroupMember `json:"members"`
+		}
+		if err := json.Unmarshal(op.Value, &value); err != nil {
+			return invalidValue(xerrors.Errorf("decode value: %w", err))
+		}
+		if value.DisplayName != nil {
+			s.DisplayName = *value.DisplayName
+		}
+		if value.Members != nil {
+			ids, err := scimMemberValues(*value.Members)
+			if err != nil {
+				return err
+			}
+			if kind == "add" {
+				ids = append(s.Members, ids...)
+			}
+			s.Members = slice.Unique(ids)
+		}
+	case strings.EqualFold(path, "displayName") && (kind == "add" || kind == "replace"):
+		var displayName string
+		if err := json.Unmarshal(op.Value, &displayName); err != nil {
+			return invalidValue(xerrors.Errorf("decode displayName: %w", err))
+		}
+		s.DisplayName = displayName
+	case strings.EqualFold(path, "externalId"):
+		// The external ID is not stored, so there is nothing to change.
+	case strings.EqualFold(path, "members"):
+		var ids []uuid.UUID
+		if len(op.Value) > 0 && string(op.Value) != "null" {
+			var members []SCIMGroupMember
+			if err := json.Unmarshal(op.Value, &members); err != nil {
+				return invalidValue(xerrors.Errorf("decode members: %w", err))
+			}
+			var err error
+			ids, err = scimMemberValues(members)
+			if err != nil {
+				return err
+			}
+		}
+
+		switch kind {
+		case "add":
+			s.Members = slice.Unique(append(s.Members, ids...))
+		case "replace":
+			s.Members = slice.Unique(ids)
+		case "remove":
+			if ids == nil {
+				// Removing the attribute without a value removes all members.
+				s.Members = nil
+			} else {
+				s.Members = slice.Omit(s.Members, ids...)
+			}
+		default:
+			return invalidValue(xerrors.Errorf("unsupported operation %q", op.Op))
+		}
+	case scimMemberPathRegex.MatchString(path) && kind == "remove":
+		id, err := uuid.Parse(scimMemberPathRegex.FindStringSubmatch(path)[1])
+		if err != nil {
+			return invalidValue(xerrors.Errorf("member value must be a uuid: %w", err))
+		}
+		s.Members = slice.Omit(s.Members, id)
+	default:
+		return scim.NewHTTPError(http.StatusBadRequest, spec.ErrInvalidPath.Type, xerrors.Errorf("unsupported operation %q on path %q", op.Op, op.Path))
+	}
+
+	return nil
+}
+
+func scimGroupStateFrom(displayName string, members []SCIMGroupMember) (scimGroupState, error) {
+	if displayName == "" {
+		return scimGroupState{}, scim.NewHTTPError(http.StatusBadRequest, "invalidRequest", xerrors.New("displayName is required"))
+	}
+	ids, err := scimMemberValues(members)
+	if err != nil {
+		return scimGroupState{}, err
+	}
+	return scimGroupState{
+		DisplayName: displayName,
+		Members:     slice.Unique(ids),
+	}, nil
+}
+
+// scimGroupName converts a SCIM display name into a valid group name. Display
+// names commonly contain spaces, so invalid characters are replaced with
+// hyphens.
+func scimGroupName(displayName string) (string, error) {
+	name := displayName
+	if codersdk.GroupNameValid(name) != nil {
+		name = strings.Trim(scimGroupNameRegex.ReplaceAllString(name, "-"), "-")
+	}
+	if name == database.EveryoneGroup {
+		return "", scim.NewHTTPError(http.StatusBadRequest, spec.ErrInvalidValue.Type, xerrors.Errorf("%q is a reserved group name", name))
+	}
+	if err := codersdk.GroupNameValid(name); err != nil {
+		return "", scim.NewHTTPError(http.StatusBadRequest, spec.ErrInvalidValue.Type, xerrors.Errorf("invalid displayName %q: %w", displayName, err))
+	}
+	return name, nil
+}
+
+// scimGroupDisplayName is the inverse of scimGroupName.
+func scimGroupDisplayName(group database.Group) string {
+	if group.DisplayName != "" {
+		return group.DisplayName
+	}
+	return group.Name
+}
+
+func scimGroupFromDB(group database.Group, members []database.GroupMember) SCIMGroup {
+	sGroup := SCIMGroup{
+		Schemas:     []string{scimGroupSchema},
+		ID:          group.ID.String(),
+		DisplayName: scimGroupDisplayName(group),
+		Members:     make([]SCIMGroupMember, 0, len(members)),
+	}
+	sGroup.Meta.ResourceType = "Group"
+	for _, member := range members {
+		sGroup.Members = append(sGroup.Members, SCIMGroupMember{
+			Value:   member.UserID.String(),
+			Display: member.UserUsername,
+		})
+	}
+	return sGroup
+}
+
+func scimMemberIDs(members []database.GroupMember) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		ids = append(ids, member.UserID)
+	}
+	return ids
+}
+
+func scimMemberValues(members []SCIMGroupMember) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		id, err := uuid.Parse(member.Value)
+		if err != nil {
+			return nil, scim.NewHTTPError(http.StatusBadRequest, spec.ErrInvalidValue.Type, xerrors.Errorf("member value %q must be a uuid: %w", member.Value, err))
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// scimWriteError writes the error to the response. Errors are wrapped when
+// returned from a transaction, which handlerutil.WriteError does not handle.
+func scimWriteError(rw http.ResponseWriter, err error) {
+	var httpErr *scim.HTTPError
+	if xerrors.As(err, &httpErr) {
+		err = httpErr
+	}
+	_ = handlerutil.WriteError(rw, err)
+}
+
+func scimQueryInt(value string, def int) (int, error) {
+	if value == "" {
+		return def, nil
+	}
+	return strconv.Atoi(value)
+}
@@ -3,15 +3,17 @@ package dbcrypt
 import (
 	"context"
 	"database/sql"
+	"time"
 
+	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
 	"github.com/coder/coder/v2/coderd/database"
 )
 
-// Rotate rotates the database encryption keys by re-encrypting all user tokens
-// with the first cipher and revoking all other ciphers.
+// Rotate rotates the database encryption keys by re-encrypting all user tokens,
+// secrets and crypto keys with the first cipher and revoking all other ciphers.
 func Rotate(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Cipher) error {
 	db := database.New(sqlDB)
 	cryptDB, err := New(ctx, db, ciphers...)
@@ -24,6 +26,30 @@ func Rotate(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Ciphe
 		return xerrors.Errorf("get users: %w", err)
 	}
 	log.Info(ctx, "encrypting user tokens", slog.F("user_count", len(userIDs)))
+	if err := reencryptUserTokens(ctx, log, cryptDB, userIDs, ciphers[0].HexDigest()); err != nil {
+		return err
+	}
+	if err := reencryptSecrets(ctx, log, cryptDB, ciphers[0].HexDigest()); err != nil {
+		return err
+	}
+	if err := reencryptCryptoKeys(ctx, log, cryptDB, ciphers[0].HexDigest()); err != nil {
+		return err
+	}
+
+	// Revoke old keys
+	for _, c := range ciphers[1:] {
+		if err := db.RevokeDBCryptKey(ctx, c.HexDigest()); err != nil {
+			return xerrors.Errorf("revoke key: %w", err)
+		}
+		log.Info(ctx, "revoked unused key", slog.F("digest", c.HexDigest()))
+	}
+
+	return nil
+}
+
+// reencryptUserTokens encrypts the tokens of the given users with the primary
+// cipher of cryptDB, unless they are already encrypted with it.
+func reencryptUserTokens(ctx context.Context, log slog.Logger, cryptDB database.Store, userIDs []uuid.UUID, primary string) error {
 	for idx, uid := range userIDs {
 		err := cryptDB.InTx(func(cryptTx database.Store) error {
 			userLinks, err := cryptTx.GetUserLinksByUserID(ctx, uid)
@@ -31,8 +57,8 @@ func Rotate(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Ciphe
 				return xerrors.Errorf("get user links for user: %w", err)
 			}
 			for _, userLink := range userLinks {
-				if userLink.OAuthAccessTokenKeyID.String == ciphers[0].HexDigest() && userLink.OAuthRefreshTokenKeyID.String == ciphers[0].HexDigest() {
-					log.Debug(ctx, "skipping user link", slog.F("user_id", uid), slog.F("current", idx+1), slog.F("cipher", ciphers[0].HexDigest()))
+				if userLink.OAuthAccessTokenKeyID.String == primary && userLink.OAuthRefreshTokenKeyID.String == primary {
+					log.Debug(ctx, "skipping user link", slog.F("user_id", uid), slog.F("current", idx+1), slog.F("cipher", primary))
 					continue
 				}
 				if _, err := cryptTx.UpdateUserLink(ctx, database.UpdateUserLinkParams{
@@ -54,8 +80,8 @@ func Rotate(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Ciphe
 				return xerrors.Errorf("get git auth links for user: %w", err)
 			}
 			for _, externalAuthLink := range externalAuthLinks {
-				if externalAuthLink.OAuthAccessTokenKeyID.String == ciphers[0].HexDigest() && externalAuthLink.OAuthRefreshTokenKeyID.String == ciphers[0].HexDigest() {
-					log.Debug(ctx, "skipping external auth link", slog.F("user_id", uid), slog.F("current", idx+1), slog.F("cipher", ciphers[0].HexDigest()))
+				if externalAuthLink.OAuthAccessTokenKeyID.String == primary && externalAuthLink.OAuthRefreshTokenKeyID.String == primary {
+					log.Debug(ctx, "skipping external auth link", slog.F("user_id", uid), slog.F("current", idx+1), slog.F("cipher", primary))
 					continue
 				}
 				if _, err := cryptTx.UpdateExternalAuthLink(ctx, database.UpdateExternalAuthLinkParams{
@@ -79,21 +105,86 @@ func Rotate(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Ciphe
 		if err != nil {
 			return xerrors.Errorf("update user links: %w", err)
 		}
-		log.Debug(ctx, "encrypted user tokens", slog.F("user_id", uid), slog.F("current", idx+1), slog.F("cipher", ciphers[0].HexDigest()))
+		log.Debug(ctx, "encrypted user tokens", slog.F("user_id", uid), slog.F("current", idx+1), slog.F("cipher", primary))
 	}
+	return nil
+}
 
-	// Revoke old keys
-	for _, c := range ciphers[1:] {
-		if err := db.RevokeDBCryptKey(ctx, c.HexDigest()); err != nil {
-			return xerrors.Errorf("revoke key: %w", err)
+// reencryptSecrets encrypts the values of all secrets with the primary cipher
+// of cryptDB, unless they are already encrypted with it.
+func reencryptSecrets(ctx context.Context, log slog.Logger, cryptDB database.Store, primary string) error {
+	secrets, err := cryptDB.GetSecrets(ctx)
+	if err != nil {
+		return xerrors.Errorf("get secrets: %w", err)
+	}
+	log.Info(ctx, "encrypting secrets", slog.F("secret_count", len(secrets)))
+	for _, secret := range secrets {
+		if secret.ValueKeyID.String == primary {
+			log.Debug(ctx, "skipping secret", slog.F("secret_id", secret.ID), slog.F("cipher", primary))
+			continue
+		}
+		if err := updateSecretValue(ctx, cryptDB, secret); err != nil {
+			return err
 		}
-		log.Info(ctx, "revoked unused key", slog.F("digest", c.HexDigest()))
 	}
+	return nil
+}
 
+// updateSecretValue writes the value of a secret back unchanged, so that it is
+// encrypted with the primary cipher of the store, if any.
+func updateSecretValue(ctx context.Context, store database.Store, secret database.Secret) error {
+	_, err := store.UpdateSecretByID(ctx, database.UpdateSecretByIDParams{
+		ID:          secret.ID,
+		UpdatedAt:   secret.UpdatedAt,
+		Description: secret.Description,
+		Value:       secret.Value,
+		ValueKeyID:  sql.NullString{}, // dbcrypt will update as required
+		EnvName:     secret.EnvName,
+		FilePath:    secret.FilePath,
+	})
+	if err != nil {
+		return xerrors.Errorf("update secret id=%s: %w", secret.ID, err)
+	}
 	return nil
 }
 
-// Decrypt decrypts all user tokens and revokes all ciphers.
+// reencryptCryptoKeys encrypts the secrets of all crypto keys with the primary
+// cipher of cryptDB, unless they are already encrypted with it.
+func reencryptCryptoKeys(ctx context.Context, log slog.Logger, cryptDB database.Store, primary string) error {
+	keys, err := cryptDB.GetCryptoKeys(ctx)
+	if err != nil {
+		return xerrors.Errorf("get crypto keys: %w", err)
+	}
+	log.Info(ctx, "encrypting crypto keys", slog.F("crypto_key_count", len(keys)))
+	for _, key := range keys {
+		if key.SecretKeyID.String == primary {
+			log.Debug(ctx, "skipping crypto key", slog.F("feature", key.Feature), slog.F("sequence", key.Sequence), slog.F("cipher", primary))
+			continue
+		}
+		if err := updateCryptoKeySecret(ctx, cryptDB, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateCryptoKeySecret writes the secret of a crypto key back unchanged, so
+// that it is encrypted with the primary cipher of the store, if any.
+func updateCryptoKeySecret(ctx context.Context, store database.Store, key database.CryptoKey) error {
+	_, err := store.UpdateCryptoKeySecret(ctx, database.UpdateCryptoKeySecretParams{
+		Feature:     key.Feature,
+		Sequence:    key.Sequence,
+		Secret:      key.Secret,
+		SecretKeyID: sql.NullString{}, // dbcrypt will update as required
+	})
+	if err != nil {
+		return xerrors.Errorf("update crypto key feature=%s sequence=%d: %w", key.Feature, key.Sequence, err)
+	}
+	return nil
+}
+
+// Decrypt decrypts all user tokens, secrets and crypto keys, and revokes all
+// ciphers.
 func Decrypt(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Cipher) error {
 	db := database.New(sqlDB)
 	cdb, err := New(ctx, db, ciphers...)
@@ -107,7 +198,7 @@ func Decrypt(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Ciph
 	if !ok {
 		return xerrors.Errorf("developer error: dbcrypt.New did not return *dbCrypt")
 	}
-	cryptDB.primaryCipherDigest = ""
+	cryptDB.keys.setPrimary("")
 
 	userIDs, err := db.AllUserIDs(ctx)
 	if err != nil {
@@ -172,6 +263,34 @@ func Decrypt(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Ciph
 		log.Debug(ctx, "decrypted user tokens", slog.F("user_id", uid), slog.F("current", idx+1), slog.F("cipher", ciphers[0].HexDigest()))
 	}
 
+	secrets, err := cryptDB.GetSecrets(ctx)
+	if err != nil {
+		return xerrors.Errorf("get secrets: %w", err)
+	}
+	log.Info(ctx, "decrypting secrets", slog.F("secret_count", len(secrets)))
+	for _, secret := range secrets {
+		if !secret.ValueKeyID.Valid {
+			continue
+		}
+		if err := updateSecretValue(ctx, cryptDB, secret); err != nil {
+			return err
+		}
+	}
+
+	keys, err := cryptDB.GetCryptoKeys(ctx)
+	if err != nil {
+		return xerrors.Errorf("get crypto keys: %w", err)
+	}
+	log.Info(ctx, "decrypting crypto keys", slog.F("crypto_key_count", len(keys)))
+	for _, key := range keys {
+		if !key.SecretKeyID.Valid {
+			continue
+		}
+		if err := updateCryptoKeySecret(ctx, cryptDB, key); err != nil {
+			return err
+		}
+	}
+
 	// Revoke _all_ keys
 	for _, c := range ciphers {
 		if err := db.RevokeDBCryptKey(ctx, c.HexDigest()); err != nil {
@@ -183,6 +302,59 @@ func Decrypt(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Ciph
 	return nil
 }
 
+// KeyStatus describes a key in the dbcrypt_keys table and the number of user
+// tokens and secrets that are encrypted with it.
+type KeyStatus struct {
+	Number            int32      `json:"number" table:"number,default_sort"`
+	Digest            string     `json:"digest" table:"digest"`
+	Provider          string     `json:"provider" table:"provider"`
+	CreatedAt         time.Time  `json:"created_at" table:"created at"`
+	RevokedAt         *time.Time `json:"revoked_at,omitempty" table:"revoked at"`
+	UserLinks         int64      `json:"user_links" table:"user links"`
+	ExternalAuthLinks int64      `json:"external_auth_links" table:"external auth links"`
+	Secrets           int64      `json:"secrets" table:"secrets"`
+}
+
+// nolint: gosec
+const sqlCountEncryptedUserTokens = `
+SELECT
+	(SELECT COUNT(*) FROM user_links
+		WHERE oauth_access_token_key_id = $1 OR oauth_refresh_token_key_id = $1),
+	(SELECT COUNT(*) FROM external_auth_links
+		WHERE oauth_access_token_key_id = $1 OR oauth_refresh_token_key_id = $1),
+	(SELECT COUNT(*) FROM secrets
+		WHERE value_key_id = $1);
+`
+
+// Status returns the status of all database encryption keys. Keys that were
+// provided to Coder directly have an empty provider.
+func Status(ctx context.Context, sqlDB *sql.DB) ([]KeyStatus, error) {
+	keys, err := database.New(sqlDB).GetDBCryptKeys(ctx)
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		return nil, xerrors.Errorf("get db crypt keys: %w", err)
+	}
+	statuses := make([]KeyStatus, 0, len(keys))
+	for _, key := range keys {
+		status := KeyStatus{
+			Number:    key.Number,
+			Digest:    key.ActiveKeyDigest.String,
+			Provider:  key.KeyProvider.String,
+			CreatedAt: key.CreatedAt.Time,
+		}
+		if key.RevokedKeyDigest.Valid {
+			status.Digest = key.RevokedKeyDigest.String
+			revokedAt := key.RevokedAt.Time
+			status.RevokedAt = &revokedAt
+		}
+		err := sqlDB.QueryRowContext(ctx, sqlCountEncryptedUserTokens, status.Digest).Scan(&status.UserLinks, &status.ExternalAuthLinks, &status.Secrets)
+		if err != nil {
+			return nil, xerrors.Errorf("count tokens encrypted with key %d: %w", key.Number, err)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
 // nolint: gosec
 const sqlDeleteEncryptedUserTokens = `
 BEGIN;
@@ -192,10 +364,13 @@ DELETE FROM user_links
 DELETE FROM external_auth_links
 	WHERE oauth_access_token_key_id IS NOT NULL
 	OR oauth_refresh_token_key_id IS NOT NULL;
+DELETE FROM secrets
+	WHERE value_key_id IS NOT NULL;
 COMMIT;
 `
 
-// Delete deletes all user tokens and revokes all ciphers.
+// Delete deletes all encrypted user tokens and secrets, and revokes all
+// ciphers.
 // This is a destructive operation and should only be used
 // as a last resort, for example, if the database encryption key has been
 // lost.
@@ -205,7 +380,7 @@ func Delete(ctx context.Context, log slog.Logger, sqlDB *sql.DB) error {
 	if err != nil {
 		return xerrors.Errorf("delete user links: %w", err)
 	}
-	log.Info(ctx, "deleted encrypted user tokens")
+	log.Info(ctx, "deleted encrypted user tokens and secrets")
 
 	log.Info(ctx, "revoking all active keys")
 	keys, err := store.GetDBCryptKeys(ctx)
@@ -0,0 +1,58 @@
+package dbcrypt
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// CommandKeyProvider wraps data keys by running an external command. This
+// allows keys to be wrapped by any key management system with a client that
+// can be scripted, e.g. a KMIP server or an HSM accessed through PKCS#11.
+//
+// The command is run with "wrap" or "unwrap" appended to its arguments. It
+// receives the base64-encoded key on stdin, and must write the
+// base64-encoded result to stdout.
+type CommandKeyProvider struct {
+	Command string
+	Args    []string
+}
+
+var _ KeyProvider = &CommandKeyProvider{}
+
+func (*CommandKeyProvider) Name() string {
+	return "command"
+}
+
+func (c *CommandKeyProvider) WrapKey(ctx context.Context, key []byte) ([]byte, error) {
+	return c.run(ctx, "wrap", key)
+}
+
+func (c *CommandKeyProvider) UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	return c.run(ctx, "unwrap", wrapped)
+}
+
+func (c *CommandKeyProvider) run(ctx context.Context, operation string, input []byte) ([]byte, error) {
+	args := append(append([]string{}, c.Args...), operation)
+	// #nosec G204 - The command is configured by the operator.
+	cmd := exec.CommandContext(ctx, c.Command, args...)
+	cmd.Stdin = strings.NewReader(b64encode(input) + "\n")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		return nil, xerrors.Errorf("run %s %s: %w: %s", c.Command, operation, err, strings.TrimSpace(stderr.String()))
+	}
+	output, err := b64decode(strings.TrimSpace(stdout.String()))
+	if err != nil {
+		return nil, xerrors.Errorf("decode output of %s %s: %w", c.Command, operation, err)
+	}
+	if len(output) == 0 {
+		return nil, xerrors.Errorf("%s %s returned no output", c.Command, operation)
+	}
+	return output, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/base64"
+	"sync"
 
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
@@ -33,16 +34,13 @@ func (e *DecryptFailedError) Error() string {
 // New creates a database.Store wrapper that encrypts/decrypts values
 // stored at rest in the database.
 func New(ctx context.Context, db database.Store, ciphers ...Cipher) (database.Store, error) {
-	cm := make(map[string]Cipher)
-	for _, c := range ciphers {
-		cm[c.HexDigest()] = c
+	keys := newKeyring(nil, ciphers...)
+	if len(ciphers) > 0 {
+		keys.primary = ciphers[0].HexDigest()
 	}
 	dbc := &dbCrypt{
-		ciphers: cm,
-		Store:   db,
-	}
-	if len(ciphers) > 0 {
-		dbc.primaryCipherDigest = ciphers[0].HexDigest()
+		keys:  keys,
+		Store: db,
 	}
 	// nolint: gocritic // This is allowed.
 	authCtx := dbauthz.AsSystemRestricted(ctx)
@@ -52,20 +50,161 @@ func New(ctx context.Context, db database.Store, ciphers ...Cipher) (database.St
 	return dbc, nil
 }
 
+// NewWithKeyProvider is like New, but data is encrypted with a data key that
+// is wrapped by the provider. The newest active data key of the provider is
+// used, and a new one is created if there is none. The given ciphers are
+// only used to decrypt existing data, e.g. when migrating from static keys.
+//
+// Data keys created by other replicas, e.g. during key rotation, are
+// loaded from the database when they are first encountered.
+func NewWithKeyProvider(ctx context.Context, db database.Store, provider KeyProvider, ciphers ...Cipher) (database.Store, error) {
+	// nolint: gocritic // This is allowed.
+	authCtx := dbauthz.AsSystemRestricted(ctx)
+	var err error
+	// Another replica may insert a key with the same number when starting
+	// at the same time, in which case we use its key instead.
+	for i := 0; i < 3; i++ {
+		keys := newKeyring(provider, ciphers...)
+		err = keys.load(authCtx, db)
+		if err != nil {
+			return nil, xerrors.Errorf("load data keys: %w", err)
+		}
+		if keys.primary == "" {
+			c, err := NewDataKey(authCtx, provider)
+			if err != nil {
+				return nil, err
+			}
+			keys.add(c)
+			keys.primary = c.HexDigest()
+		}
+		dbc := &dbCrypt{
+			keys:  keys,
+			Store: db,
+		}
+		err = dbc.ensureEncryptedWithRetry(authCtx)
+		if err == nil {
+			return dbc, nil
+		}
+		if !database.IsUniqueViolation(err) {
+			break
+		}
+	}
+	return nil, xerrors.Errorf("ensure encrypted database fields: %w", err)
+}
+
 type dbCrypt struct {
-	// primaryCipherDigest is the digest of the primary cipher used for encrypting data.
-	primaryCipherDigest string
+	keys *keyring
+	database.Store
+}
+
+// keyring holds the ciphers of a dbCrypt. It is shared with the dbCrypt
+// instances of transactions.
+type keyring struct {
+	mu sync.RWMutex
+	// primary is the digest of the primary cipher used for encrypting data.
+	primary string
 	// ciphers is a map of cipher digests to ciphers.
 	ciphers map[string]Cipher
-	database.Store
+
+	// provider unwraps data keys that are stored in the database. It is nil
+	// if only static keys are used.
+	provider KeyProvider
+}
+
+func newKeyring(provider KeyProvider, ciphers ...Cipher) *keyring {
+	cm := make(map[string]Cipher)
+	for _, c := range ciphers {
+		cm[c.HexDigest()] = c
+	}
+	return &keyring{
+		ciphers:  cm,
+		provider: provider,
+	}
+}
+
+func (k *keyring) add(c Cipher) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.ciphers[c.HexDigest()] = c
+}
+
+func (k *keyring) setPrimary(digest string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.primary = digest
+}
+
+// primaryCipher returns the cipher used to encrypt data, if any.
+func (k *keyring) primaryCipher() (Cipher, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if k.primary == "" {
+		return nil, false
+	}
+	c, ok := k.ciphers[k.primary]
+	return c, ok
+}
+
+// cipher returns the cipher with the given digest. Unknown data keys of the
+// provider are loaded from the database.
+func (k *keyring) cipher(ctx context.Context, db database.Store, digest string) (Cipher, bool) {
+	k.mu.RLock()
+	c, ok := k.ciphers[digest]
+	k.mu.RUnlock()
+	if ok || k.provider == nil {
+		return c, ok
+	}
+	// nolint: gocritic // Loading keys is allowed.
+	if err := k.load(dbauthz.AsSystemRestricted(ctx), db); err != nil {
+		return nil, false
+	}
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	c, ok = k.ciphers[digest]
+	return c, ok
+}
+
+// load unwraps the active data keys of the provider that are not loaded
+// yet, and makes the newest one the primary cipher.
+func (k *keyring) load(ctx context.Context, db database.Store) error {
+	if k.provider == nil {
+		return nil
+	}
+	keys, err := db.GetDBCryptKeys(ctx)
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		return xerrors.Errorf("get keys: %w", err)
+	}
+	var newest string
+	for _, key := range keys {
+		if !key.ActiveKeyDigest.Valid || key.KeyProvider.String != k.provider.Name() {
+			continue
+		}
+		newest = key.ActiveKeyDigest.String
+		k.mu.RLock()
+		_, ok := k.ciphers[key.ActiveKeyDigest.String]
+		k.mu.RUnlock()
+		if ok {
+			continue
+		}
+		c, ok, err := unwrapKey(ctx, key, k.provider)
+		if err != nil {
+			return err
+		}
+		if ok {
+			k.add(c)
+		}
+	}
+	if newest != "" {
+		k.setPrimary(newest)
+	}
+	return nil
 }
 
 func (db *dbCrypt) InTx(function func(database.Store) error, txOpts *database.TxOptions) error {
 	return db.Store.InTx(func(s database.Store) error {
 		return function(&dbCrypt{
-			primaryCipherDigest: db.primaryCipherDigest,
-			ciphers:             db.ciphers,
-			Store:               s,
+			keys:  db.keys,
+			Store: s,
 		})
 	}, txOpts)
 }
@@ -83,7 +222,7 @@ func (db *dbCrypt) GetDBCryptKeys(ctx context.Context) ([]database.DBCryptKey, e
 			// has been revoked.
 			continue
 		}
-		if err := db.decryptField(&ks[i].Test, ks[i].ActiveKeyDigest); err != nil {
+		if err := db.decryptField(ctx, &ks[i].Test, ks[i].ActiveKeyDigest); err != nil {
 			return nil, err
 		}
 	}
@@ -112,10 +251,10 @@ func (db *dbCrypt) GetUserLinkByLinkedID(ctx context.Context, linkedID string) (
 	if err != nil {
 		return database.UserLink{}, err
 	}
-	if err := db.decryptField(&link.OAuthAccessToken, link.OAuthAccessTokenKeyID); err != nil {
+	if err := db.decryptField(ctx, &link.OAuthAccessToken, link.OAuthAccessTokenKeyID); err != nil {
 		return database.UserLink{}, err
 	}
-	if err := db.decryptField(&link.OAuthRefreshToken, link.OAuthRefreshTokenKeyID); err != nil {
+	if err := db.decryptField(ctx, &link.OAuthRefreshToken, link.OAuthRefreshTokenKeyID); err != nil {
 		return database.UserLink{}, err
 	}
 	return link, nil
@@ -127,10 +266,10 @@ func (db *dbCrypt) GetUserLinksByUserID(ctx context.Context, userID uuid.UUID) (
 		return nil, err
 	}
 	for idx := range links {
-		if err := db.decryptField(&links[idx].OAuthAccessToken, links[idx].OAuthAccessTokenKeyID); err != nil {
+		if err := db.decryptField(ctx, &links[idx].OAuthAccessToken, links[idx].OAuthAccessTokenKeyID); err != nil {
 			return nil, err
 		}
-		if err := db.decryptField(&links[idx].OAuthRefreshToken, links[idx].OAuthRefreshTokenKeyID); err != nil {
+		if err := db.decryptField(ctx, &links[idx].OAuthRefreshToken, links[idx].OAuthRefreshTokenKeyID); err != nil {
 			return nil, err
 		}
 	}
@@ -142,10 +281,10 @@ func (db *dbCrypt) GetUserLinkByUserIDLoginType(ctx context.Context, params data
 	if err != nil {
 		return database.UserLink{}, err
 	}
-	if err := db.decryptField(&link.OAuthAccessToken, link.OAuthAccessTokenKeyID); err != nil {
+	if err := db.decryptField(ctx, &link.OAuthAccessToken, link.OAuthAccessTokenKeyID); err != nil {
 		return database.UserLink{}, err
 	}
-	if err := db.decryptField(&link.OAuthRefreshToken, link.OAuthRefreshTokenKeyID); err != nil {
+	if err := db.decryptField(ctx, &link.OAuthRefreshToken, link.OAuthRefreshTokenKeyID); err != nil {
 		return database.UserLink{}, err
 	}
 	return link, nil
@@ -162,10 +301,10 @@ func (db *dbCrypt) InsertUserLink(ctx context.Context, params database.InsertUse
 	if err != nil {
 		return database.UserLink{}, err
 	}
-	if err := db.decryptField(&link.OAuthAccessToken, link.OAuthAccessTokenKeyID); err != nil {
+	if err := db.decryptField(ctx, &link.OAuthAccessToken, link.OAuthAccessTokenKeyID); err != nil {
 		return database.UserLink{}, err
 	}
-	if err := db.decryptField(&link.OAuthRefreshToken, link.OAuthRefreshTokenKeyID); err != nil {
+	if err := db.decryptField(ctx, &link.OAuthRefreshToken, link.OAuthRefreshTokenKeyID); err != nil {
 		return database.UserLink{}, err
 	}
 	return link, nil
@@ -182,10 +321,10 @@ func (db *dbCrypt) UpdateUserLink(ctx context.Context, params database.UpdateUse
 	if err != nil {
 		return database.UserLink{}, err
 	}
-	if err := db.decryptField(&link.OAuthAccessToken, link.OAuthAccessTokenKeyID); err != nil {
+	if err := db.decryptField(ctx, &link.OAuthAccessToken, link.OAuthAccessTokenKeyID); err != nil {
 		return database.UserLink{}, err
 	}
-	if err := db.decryptField(&link.OAuthRefreshToken, link.OAuthRefreshTokenKeyID); err != nil {
+	if err := db.decryptField(ctx, &link.OAuthRefreshToken, link.OAuthRefreshTokenKeyID); err != nil {
 		return database.UserLink{}, err
 	}
 	return link, nil
@@ -202,10 +341,10 @@ func (db *dbCrypt) InsertExternalAuthLink(ctx context.Context, params database.I
 	if err != nil {
 		return database.ExternalAuthLink{}, err
 	}
-	if err := db.decryptField(&link.OAuthAccessToken, link.OAuthAccessTokenKeyID); err != nil {
+	if err := db.decryptField(ctx, &link.OAuthAccessToken, link.OAuthAccessTokenKeyID); err != nil {
 		return database.ExternalAuthLink{}, err
 	}
-	if err := db.decryptField(&link.OAuthRefreshToken, link.OAuthRefreshTokenKeyID); err != nil {
+	if err := db.decryptField(ctx, &link.OAuthRefreshToken, link.OAuthRefreshTokenKeyID); err != nil {
 		return database.ExternalAuthLink{}, err
 	}
 	return link, nil
@@ -216,10 +355,10 @@ func (db *dbCrypt) GetExternalAuthLink(ctx context.Context, params database.GetE
 	if err != nil {
 		return database.ExternalAuthLink{}, err
 	}
-	if err := db.decryptField(&link.OAuthAccessToken, link.OAuthAccessTokenKeyID); err != nil {
+	if err := db.decryptField(ctx, &link.OAuthAccessToken, link.OAuthAccessTokenKeyID); err != nil {
 		return database.ExternalAuthLink{}, err
 	}
-	if err := db.decryptField(&link.OAuthRefreshToken, link.OAuthRefreshTokenKeyID); err != nil {
+	if err := db.decryptField(ctx, &link.OAuthRefreshToken, link.OAuthRefreshTokenKeyID); err != nil {
 		return database.ExternalAuthLink{}, err
 	}
 	return link, nil
@@ -231,10 +370,10 @@ func (db *dbCrypt) GetExternalAuthLinksByUserID(ctx context.Context, userID uuid
 		return nil, err
 	}
 	for idx := range links {
-		if err := db.decryptField(&links[idx].OAuthAccessToken, links[idx].OAuthAccessTokenKeyID); err != nil {
+		if err := db.decryptField(ctx, &links[idx].OAuthAccessToken, links[idx].OAuthAccessTokenKeyID); err != nil {
 			return nil, err
 		}
-		if err := db.decryptField(&links[idx].OAuthRefreshToken, links[idx].OAuthRefreshTokenKeyID); err != nil {
+		if err := db.decryptField(ctx, &links[idx].OAuthRefreshToken, links[idx].OAuthRefreshTokenKeyID); err != nil {
 			return nil, err
 		}
 	}
@@ -252,10 +391,10 @@ func (db *dbCrypt) UpdateExternalAuthLink(ctx context.Context, params database.U
 	if err != nil {
 		return database.ExternalAuthLink{}, err
 	}
-	if err := db.decryptField(&link.OAuthAccessToken, link.OAuthAccessTokenKeyID); err != nil {
+	if err := db.decryptField(ctx, &link.OAuthAccessToken, link.OAuthAccessTokenKeyID); err != nil {
 		return database.ExternalAuthLink{}, err
 	}
-	if err := db.decryptField(&link.OAuthRefreshToken, link.OAuthRefreshTokenKeyID); err != nil {
+	if err := db.decryptField(ctx, &link.OAuthRefreshToken, link.OAuthRefreshTokenKeyID); err != nil {
 		return database.ExternalAuthLink{}, err
 	}
 	return link, nil
@@ -282,7 +421,7 @@ func (db *dbCrypt) GetCryptoKeys(ctx context.Context) ([]database.CryptoKey, err
 		return nil, err
 	}
 	for i := range keys {
-		if err := db.decryptField(&keys[i].Secret.String, keys[i].SecretKeyID); err != nil {
+		if err := db.decryptField(ctx, &keys[i].Secret.String, keys[i].SecretKeyID); err != nil {
 			return nil, err
 		}
 	}
@@ -294,7 +433,7 @@ func (db *dbCrypt) GetLatestCryptoKeyByFeature(ctx context.Context, feature data
 	if err != nil {
 		return database.CryptoKey{}, err
 	}
-	if err := db.decryptField(&key.Secret.String, key.SecretKeyID); err != nil {
+	if err := db.decryptField(ctx, &key.Secret.String, key.SecretKeyID); err != nil {
 		return database.CryptoKey{}, err
 	}
 	return key, nil
@@ -305,7 +444,7 @@ func (db *dbCrypt) GetCryptoKeyByFeatureAndSequence(ctx context.Context, params
 	if err != nil {
 		return database.CryptoKey{}, err
 	}
-	if err := db.decryptField(&key.Secret.String, key.SecretKeyID); err != nil {
+	if err := db.decryptField(ctx, &key.Secret.String, key.SecretKeyID); err != nil {
 		return database.CryptoKey{}, err
 	}
 	return key, nil
@@ -319,7 +458,7 @@ func (db *dbCrypt) InsertCryptoKey(ctx context.Context, params database.InsertCr
 	if err != nil {
 		return database.CryptoKey{}, err
 	}
-	if err := db.decryptField(&key.Secret.String, key.SecretKeyID); err != nil {
+	if err := db.decryptField(ctx, &key.Secret.String, key.SecretKeyID); err != nil {
 		return database.CryptoKey{}, err
 	}
 	return key, nil
@@ -330,7 +469,21 @@ func (db *dbCrypt) UpdateCryptoKeyDeletesAt(ctx context.Context, arg database.Up
 	if err != nil {
 		return database.CryptoKey{}, err
 	}
-	if err := db.decryptField(&key.Secret.String, key.SecretKeyID); err != nil {
+	if err := db.decryptField(ctx, &key.Secret.String, key.SecretKeyID); err != nil {
+		return database.CryptoKey{}, err
+	}
+	return key, nil
+}
+
+func (db *dbCrypt) UpdateCryptoKeySecret(ctx context.Context, params database.UpdateCryptoKeySecretParams) (database.CryptoKey, error) {
+	if err := db.encryptField(&params.Secret.String, &params.SecretKeyID); err != nil {
+		return database.CryptoKey{}, err
+	}
+	key, err := db.Store.UpdateCryptoKeySecret(ctx, params)
+	if err != nil {
+		return database.CryptoKey{}, err
+	}
+	if err := db.decryptField(ctx, &key.Secret.String, key.SecretKeyID); err != nil {
 		return database.CryptoKey{}, err
 	}
 	return key, nil
@@ -343,7 +496,7 @@ func (db *dbCrypt) GetCryptoKeysByFeature(ctx context.Context, feature database.
 	}
 
 	for i := range keys {
-		if err := db.decryptField(&keys[i].Secret.String, keys[i].SecretKeyID); err != nil {
+		if err := db.decryptField(ctx, &keys[i].Secret.String, keys[i].SecretKeyID); err != nil {
 			return nil, err
 		}
 	}
@@ -351,9 +504,99 @@ func (db *dbCrypt) GetCryptoKeysByFeature(ctx context.Context, feature database.
 	return keys, nil
 }
 
+func (db *dbCrypt) InsertSecret(ctx context.Context, params database.InsertSecretParams) (database.Secret, error) {
+	if err := db.encryptField(&params.Value, &params.ValueKeyID); err != nil {
+		return database.Secret{}, err
+	}
+	secret, err := db.Store.InsertSecret(ctx, params)
+	if err != nil {
+		return database.Secret{}, err
+	}
+	if err := db.decryptField(ctx, &secret.Value, secret.ValueKeyID); err != nil {
+		return database.Secret{}, err
+	}
+	return secret, nil
+}
+
+func (db *dbCrypt) GetSecretByID(ctx context.Context, id uuid.UUID) (database.Secret, error) {
+	secret, err := db.Store.GetSecretByID(ctx, id)
+	if err != nil {
+		return database.Secret{}, err
+	}
+	if err := db.decryptField(ctx, &secret.Value, secret.ValueKeyID); err != nil {
+		return database.Secret{}, err
+	}
+	return secret, nil
+}
+
+func (db *dbCrypt) GetSecretByScopeAndName(ctx context.Context, params database.GetSecretByScopeAndNameParams) (database.Secret, error) {
+	secret, err := db.Store.GetSecretByScopeAndName(ctx, params)
+	if err != nil {
+		return database.Secret{}, err
+	}
+	if err := db.decryptField(ctx, &secret.Value, secret.ValueKeyID); err != nil {
+		return database.Secret{}, err
+	}
+	return secret, nil
+}
+
+func (db *dbCrypt) GetSecrets(ctx context.Context) ([]database.Secret, error) {
+	secrets, err := db.Store.GetSecrets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range secrets {
+		if err := db.decryptField(ctx, &secrets[i].Value, secrets[i].ValueKeyID); err != nil {
+			return nil, err
+		}
+	}
+	return secrets, nil
+}
+
+func (db *dbCrypt) GetSecretsByScope(ctx context.Context, params database.GetSecretsByScopeParams) ([]database.Secret, error) {
+	secrets, err := db.Store.GetSecretsByScope(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	for i := range secrets {
+		if err := db.decryptField(ctx, &secrets[i].Value, secrets[i].ValueKeyID); err != nil {
+			return nil, err
+		}
+	}
+	return secrets, nil
+}
+
+func (db *dbCrypt) GetSecretsForWorkspace(ctx context.Context, params database.GetSecretsForWorkspaceParams) ([]database.Secret, error) {
+	secrets, err := db.Store.GetSecretsForWorkspace(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	for i := range secrets {
+		if err := db.decryptField(ctx, &secrets[i].Value, secrets[i].ValueKeyID); err != nil {
+			return nil, err
+		}
+	}
+	return secrets, nil
+}
+
+func (db *dbCrypt) UpdateSecretByID(ctx context.Context, params database.UpdateSecretByIDParams) (database.Secret, error) {
+	if err := db.encryptField(&params.Value, &params.ValueKeyID); err != nil {
+		return database.Secret{}, err
+	}
+	secret, err := db.Store.UpdateSecretByID(ctx, params)
+	if err != nil {
+		return database.Secret{}, err
+	}
+	if err := db.decryptField(ctx, &secret.Value, secret.ValueKeyID); err != nil {
+		return database.Secret{}, err
+	}
+	return secret, nil
+}
+
 func (db *dbCrypt) encryptField(field *string, digest *sql.NullString) error {
 	// If no cipher is loaded, then we can't encrypt anything!
-	if db.ciphers == nil || db.primaryCipherDigest == "" {
+	primary, ok := db.keys.primaryCipher()
+	if !ok {
 		return nil
 	}
 
@@ -364,19 +607,19 @@ func (db *dbCrypt) encryptField(field *string, digest *sql.NullString) error {
 		return xerrors.Errorf("developer error: encryptField called with nil digest")
 	}
 
-	encrypted, err := db.ciphers[db.primaryCipherDigest].Encrypt([]byte(*field))
+	encrypted, err := primary.Encrypt([]byte(*field))
 	if err != nil {
 		return err
 	}
 	// Base64 is used to support UTF-8 encoding in PostgreSQL.
 	*field = b64encode(encrypted)
-	*digest = sql.NullString{String: db.primaryCipherDigest, Valid: true}
+	*digest = sql.NullString{String: primary.HexDigest(), Valid: true}
 	return nil
 }
 
 // decryptFields decrypts the given field using the key with the given digest.
 // If the value fails to decrypt, sql.ErrNoRows will be returned.
-func (db *dbCrypt) decryptField(field *string, digest sql.NullString) error {
+func (db *dbCrypt) decryptField(ctx context.Context, field *string, digest sql.NullString) error {
 	if field == nil {
 		return xerrors.Errorf("developer error: decryptField called with nil field")
 	}
@@ -386,7 +629,9 @@ func (db *dbCrypt) decryptField(field *string, digest sql.NullString) error {
 		return nil
 	}
 
-	key, ok := db.ciphers[digest.String]
+	// Keys are loaded with the underlying store, which is the transaction if
+	// we are in one.
+	key, ok := db.keys.cipher(ctx, db.Store, digest.String)
 	if !ok {
 		return &DecryptFailedError{
 			Inner: xerrors.Errorf("no cipher with digest %q", digest.String),
@@ -426,22 +671,26 @@ func (db *dbCrypt) ensureEncryptedWithRetry(ctx context.Context) error {
 }
 
 func (db *dbCrypt) ensureEncrypted(ctx context.Context) error {
+	primary, hasPrimary := db.keys.primaryCipher()
 	return db.InTx(func(s database.Store) error {
 		// Attempt to read the encrypted test fields of the currently active keys.
 		ks, err := s.GetDBCryptKeys(ctx)
 		if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
 			return err
 		}
+		if !hasPrimary {
+			return nil
+		}
 
 		var highestNumber int32
 		var activeCipherFound bool
 		for _, k := range ks {
 			// If our primary key has been revoked, then we can't do anything.
-			if k.RevokedKeyDigest.Valid && k.RevokedKeyDigest.String == db.primaryCipherDigest {
-				return xerrors.Errorf("primary encryption key %q has been revoked", db.primaryCipherDigest)
+			if k.RevokedKeyDigest.Valid && k.RevokedKeyDigest.String == primary.HexDigest() {
+				return xerrors.Errorf("primary encryption key %q has been revoked", primary.HexDigest())
 			}
 
-			if k.ActiveKeyDigest.Valid && k.ActiveKeyDigest.String == db.primaryCipherDigest {
+			if k.ActiveKeyDigest.Valid && k.ActiveKeyDigest.String == primary.HexDigest() {
 				activeCipherFound = true
 			}
 
@@ -450,15 +699,26 @@ func (db *dbCrypt) ensureEncrypted(ctx context.Context) error {
 			}
 		}
 
-		if activeCipherFound || len(db.ciphers) == 0 {
+		if activeCipherFound {
 			return nil
 		}
 
 		// If we get here, then we have a new key that we need to insert.
-		return db.InsertDBCryptKey(ctx, database.InsertDBCryptKeyParams{
-			Number:          highestNumber + 1,
-			ActiveKeyDigest: db.primaryCipherDigest,
-			Test:            testValue,
-		})
+		return s.InsertDBCryptKey(ctx, insertKeyParams(primary, highestNumber+1))
 	}, &database.TxOptions{Isolation: sql.LevelRepeatableRead})
 }
+
+// insertKeyParams returns the parameters to insert the key of a cipher. The
+// test value is encrypted by the dbCrypt it is inserted with.
+func insertKeyParams(c Cipher, number int32) database.InsertDBCryptKeyParams {
+	arg := database.InsertDBCryptKeyParams{
+		Number:          number,
+		ActiveKeyDigest: c.HexDigest(),
+		Test:            testValue,
+	}
+	if w, ok := c.(*wrappedCipher); ok {
+		arg.KeyProvider = sql.NullString{String: w.provider, Valid: true}
+		arg.WrappedKey = sql.NullString{String: b64encode(w.wrapped), Valid: true}
+	}
+	return arg
+}
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/lib/pq"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -18,6 +19,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbmock"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/testutil"
 )
 
 func TestUserLinks(t *testing.T) {
@@ -401,6 +403,101 @@ func TestExternalAuthLinks(t *testing.T) {
 	})
 }
 
+func TestSecrets(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("InsertSecret", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		user := dbgen.User(t, crypt, database.User{})
+		secret := dbgen.Secret(t, crypt, database.Secret{
+			UserID: uuid.NullUUID{UUID: user.ID, Valid: true},
+			Value:  "value",
+		})
+		require.Equal(t, "value", secret.Value)
+
+		secret, err := db.GetSecretByID(ctx, secret.ID)
+		require.NoError(t, err)
+		require.Equal(t, ciphers[0].HexDigest(), secret.ValueKeyID.String)
+		requireEncryptedEquals(t, ciphers[0], secret.Value, "value")
+	})
+
+	t.Run("UpdateSecretByID", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		user := dbgen.User(t, crypt, database.User{})
+		secret := dbgen.Secret(t, crypt, database.Secret{
+			UserID: uuid.NullUUID{UUID: user.ID, Valid: true},
+		})
+		updated, err := crypt.UpdateSecretByID(ctx, database.UpdateSecretByIDParams{
+			ID:      secret.ID,
+			Value:   "updated",
+			EnvName: secret.EnvName,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "updated", updated.Value)
+
+		secret, err = db.GetSecretByID(ctx, secret.ID)
+		require.NoError(t, err)
+		requireEncryptedEquals(t, ciphers[0], secret.Value, "updated")
+	})
+
+	t.Run("GetSecretsByScope", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("OK", func(t *testing.T) {
+			t.Parallel()
+			_, crypt, ciphers := setup(t)
+			user := dbgen.User(t, crypt, database.User{})
+			_ = dbgen.Secret(t, crypt, database.Secret{
+				UserID: uuid.NullUUID{UUID: user.ID, Valid: true},
+				Value:  "value",
+			})
+			secrets, err := crypt.GetSecretsByScope(ctx, database.GetSecretsByScopeParams{
+				UserID: uuid.NullUUID{UUID: user.ID, Valid: true},
+			})
+			require.NoError(t, err)
+			require.Len(t, secrets, 1)
+			require.Equal(t, "value", secrets[0].Value)
+			require.Equal(t, ciphers[0].HexDigest(), secrets[0].ValueKeyID.String)
+		})
+
+		t.Run("DecryptErr", func(t *testing.T) {
+			t.Parallel()
+			db, crypt, ciphers := setup(t)
+			user := dbgen.User(t, db, database.User{})
+			_ = dbgen.Secret(t, db, database.Secret{
+				UserID:     uuid.NullUUID{UUID: user.ID, Valid: true},
+				Value:      fakeBase64RandomData(t, 32),
+				ValueKeyID: sql.NullString{String: ciphers[0].HexDigest(), Valid: true},
+			})
+			_, err := crypt.GetSecretsByScope(ctx, database.GetSecretsByScopeParams{
+				UserID: uuid.NullUUID{UUID: user.ID, Valid: true},
+			})
+			require.Error(t, err, "expected an error")
+			var derr *DecryptFailedError
+			require.ErrorAs(t, err, &derr, "expected a decrypt error")
+		})
+	})
+
+	t.Run("GetSecretsForWorkspace", func(t *testing.T) {
+		t.Parallel()
+		_, crypt, _ := setup(t)
+		user := dbgen.User(t, crypt, database.User{})
+		_ = dbgen.Secret(t, crypt, database.Secret{
+			UserID: uuid.NullUUID{UUID: user.ID, Valid: true},
+			Value:  "value",
+		})
+		secrets, err := crypt.GetSecretsForWorkspace(ctx, database.GetSecretsForWorkspaceParams{
+			OwnerID: user.ID,
+		})
+		require.NoError(t, err)
+		require.Len(t, secrets, 1)
+		require.Equal(t, "value", secrets[0].Value)
+	})
+}
+
 func TestCryptoKeys(t *testing.T) {
 	t.Parallel()
 	ctx := context.Background()
@@ -740,18 +837,20 @@ func TestEncryptDecryptField(t *testing.T) {
 	t.Parallel()
 	t.Run("OK", func(t *testing.T) {
 		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
 		_, cryptDB, ciphers := setup(t)
 		field := "coder"
 		digest := sql.NullString{}
 		require.NoError(t, cryptDB.encryptField(&field, &digest))
 		require.Equal(t, ciphers[0].HexDigest(), digest.String)
 		requireEncryptedEquals(t, ciphers[0], field, "coder")
-		require.NoError(t, cryptDB.decryptField(&field, digest))
+		require.NoError(t, cryptDB.decryptField(ctx, &field, digest))
 		require.Equal(t, "coder", field)
 	})
 
 	t.Run("NoKeys", func(t *testing.T) {
 		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
 		// With no keys, encryption and decryption are both no-ops.
 		_, cryptDB := setupNoCiphers(t)
 		field := "coder"
@@ -759,12 +858,13 @@ func TestEncryptDecryptField(t *testing.T) {
 		require.NoError(t, cryptDB.encryptField(&field, &digest))
 		require.Empty(t, digest.String)
 		require.Equal(t, "coder", field)
-		require.NoError(t, cryptDB.decryptField(&field, digest))
+		require.NoError(t, cryptDB.decryptField(ctx, &field, digest))
 		require.Equal(t, "coder", field)
 	})
 
 	t.Run("MissingKey", func(t *testing.T) {
 		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
 		_, cryptDB, ciphers := setup(t)
 		field := "coder"
 		digest := sql.NullString{}
@@ -776,36 +876,39 @@ func TestEncryptDecryptField(t *testing.T) {
 
 		digest = sql.NullString{String: "missing", Valid: true}
 		var derr *DecryptFailedError
-		err = cryptDB.decryptField(&field, digest)
+		err = cryptDB.decryptField(ctx, &field, digest)
 		require.Error(t, err)
 		require.ErrorAs(t, err, &derr)
 	})
 
 	t.Run("CantEncryptOrDecryptNil", func(t *testing.T) {
 		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
 		_, cryptDB, _ := setup(t)
 		require.ErrorContains(t, cryptDB.encryptField(nil, nil), "developer error")
-		require.ErrorContains(t, cryptDB.decryptField(nil, sql.NullString{}), "developer error")
+		require.ErrorContains(t, cryptDB.decryptField(ctx, nil, sql.NullString{}), "developer error")
 	})
 
 	t.Run("EncryptEmptyString", func(t *testing.T) {
 		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
 		_, cryptDB, ciphers := setup(t)
 		field := ""
 		digest := sql.NullString{}
 		require.NoError(t, cryptDB.encryptField(&field, &digest))
 		requireEncryptedEquals(t, ciphers[0], field, "")
 		require.Equal(t, ciphers[0].HexDigest(), digest.String)
-		require.NoError(t, cryptDB.decryptField(&field, digest))
+		require.NoError(t, cryptDB.decryptField(ctx, &field, digest))
 		require.Empty(t, field)
 	})
 
 	t.Run("DecryptEmptyString", func(t *testing.T) {
 		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
 		_, cryptDB, ciphers := setup(t)
 		field := ""
 		digest := sql.NullString{String: ciphers[0].HexDigest(), Valid: true}
-		err := cryptDB.decryptField(&field, digest)
+		err := cryptDB.decryptField(ctx, &field, digest)
 		// Currently this has to fail because the ciphertext must at least
 		// have a nonce. This may need to be changed depending on future
 		// ciphers.
@@ -814,10 +917,11 @@ func TestEncryptDecryptField(t *testing.T) {
 
 	t.Run("InvalidBase64", func(t *testing.T) {
 		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
 		_, cryptDB, ciphers := setup(t)
 		field := "not valid base64"
 		digest := sql.NullString{String: ciphers[0].HexDigest(), Valid: true}
-		err := cryptDB.decryptField(&field, digest)
+		err := cryptDB.decryptField(ctx, &field, digest)
 		require.ErrorContains(t, err, "illegal base64 data")
 	})
 }
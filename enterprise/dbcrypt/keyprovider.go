@@ -0,0 +1,99 @@
+package dbcrypt
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"io"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+)
+
+// KeyProvider wraps and unwraps data keys with a key encryption key that is
+// held by an external key management system, so that the keys used to
+// encrypt data never have to be configured in plain text. Wrapped data keys
+// are stored in the dbcrypt_keys table.
+type KeyProvider interface {
+	// Name identifies the provider and its key encryption key. It is stored
+	// with every data key wrapped by the provider, and only data keys with
+	// the same name are unwrapped by it.
+	Name() string
+	// WrapKey encrypts a data key with the key encryption key.
+	WrapKey(ctx context.Context, key []byte) ([]byte, error)
+	// UnwrapKey decrypts a data key previously encrypted with WrapKey.
+	UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error)
+}
+
+// wrappedCipher is a cipher with a data key that was wrapped by a
+// KeyProvider.
+type wrappedCipher struct {
+	Cipher
+	provider string
+	wrapped  []byte
+}
+
+// NewDataKey generates a random data key and wraps it with the provider. The
+// wrapped key is stored in the database when the returned cipher is used as
+// the primary cipher.
+func NewDataKey(ctx context.Context, provider KeyProvider) (Cipher, error) {
+	key := make([]byte, 32)
+	_, err := io.ReadFull(rand.Reader, key)
+	if err != nil {
+		return nil, xerrors.Errorf("generate data key: %w", err)
+	}
+	wrapped, err := provider.WrapKey(ctx, key)
+	if err != nil {
+		return nil, xerrors.Errorf("wrap data key with %s: %w", provider.Name(), err)
+	}
+	c, err := cipherAES256(key)
+	if err != nil {
+		return nil, err
+	}
+	return &wrappedCipher{Cipher: c, provider: provider.Name(), wrapped: wrapped}, nil
+}
+
+// KeyProviderCiphers unwraps the active data keys of the provider that are
+// stored in the database. The newest key is returned first.
+func KeyProviderCiphers(ctx context.Context, db database.Store, provider KeyProvider) ([]Cipher, error) {
+	keys, err := db.GetDBCryptKeys(ctx)
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		return nil, xerrors.Errorf("get keys: %w", err)
+	}
+	var ciphers []Cipher
+	for i := len(keys) - 1; i >= 0; i-- {
+		c, ok, err := unwrapKey(ctx, keys[i], provider)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			ciphers = append(ciphers, c)
+		}
+	}
+	return ciphers, nil
+}
+
+// unwrapKey returns the cipher of a key in the dbcrypt_keys table. Keys that
+// are revoked or were not wrapped by the provider are skipped.
+func unwrapKey(ctx context.Context, key database.DBCryptKey, provider KeyProvider) (Cipher, bool, error) {
+	if !key.ActiveKeyDigest.Valid || !key.WrappedKey.Valid || key.KeyProvider.String != provider.Name() {
+		return nil, false, nil
+	}
+	wrapped, err := b64decode(key.WrappedKey.String)
+	if err != nil {
+		return nil, false, xerrors.Errorf("decode wrapped key %d: %w", key.Number, err)
+	}
+	unwrapped, err := provider.UnwrapKey(ctx, wrapped)
+	if err != nil {
+		return nil, false, xerrors.Errorf("unwrap key %d with %s: %w", key.Number, provider.Name(), err)
+	}
+	c, err := cipherAES256(unwrapped)
+	if err != nil {
+		return nil, false, xerrors.Errorf("key %d: %w", key.Number, err)
+	}
+	if c.HexDigest() != key.ActiveKeyDigest.String {
+		return nil, false, xerrors.Errorf("key %d was unwrapped by %s, but its digest %q does not match %q", key.Number, provider.Name(), c.HexDigest(), key.ActiveKeyDigest.String)
+	}
+	return &wrappedCipher{Cipher: c, provider: provider.Name(), wrapped: wrapped}, true, nil
+}
@@ -0,0 +1,347 @@
+package dbcrypt
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
+)
+
+func TestNewWithKeyProvider(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CreatesDataKey", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		rawDB, _ := dbtestutil.NewDB(t)
+		provider := &fakeKeyProvider{}
+
+		cryptDB, err := NewWithKeyProvider(ctx, rawDB, provider)
+		require.NoError(t, err)
+
+		// Then: a wrapped data key is stored.
+		keys, err := rawDB.GetDBCryptKeys(ctx)
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		require.Equal(t, provider.Name(), keys[0].KeyProvider.String)
+		require.True(t, keys[0].WrappedKey.Valid)
+
+		// And: data is encrypted with it.
+		user := dbgen.User(t, cryptDB, database.User{})
+		link := dbgen.UserLink(t, cryptDB, database.UserLink{
+			UserID:           user.ID,
+			OAuthAccessToken: "access",
+		})
+		require.Equal(t, keys[0].ActiveKeyDigest.String, link.OAuthAccessTokenKeyID.String)
+
+		// When: another replica starts with the same provider.
+		other, err := NewWithKeyProvider(ctx, rawDB, provider)
+		require.NoError(t, err)
+
+		// Then: it uses the same key.
+		keys, err = rawDB.GetDBCryptKeys(ctx)
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		got, err := other.GetUserLinkByLinkedID(ctx, link.LinkedID)
+		require.NoError(t, err)
+		require.Equal(t, "access", got.OAuthAccessToken)
+	})
+
+	t.Run("StaticKeys", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		rawDB, _ := dbtestutil.NewDB(t)
+		static := initCipher(t)
+
+		// Given: data encrypted with a static key.
+		staticDB, err := New(ctx, rawDB, static)
+		require.NoError(t, err)
+		user := dbgen.User(t, staticDB, database.User{})
+		link := dbgen.UserLink(t, staticDB, database.UserLink{
+			UserID:           user.ID,
+			OAuthAccessToken: "access",
+		})
+
+		// When: a key provider is configured with the static key.
+		cryptDB, err := NewWithKeyProvider(ctx, rawDB, &fakeKeyProvider{}, static)
+		require.NoError(t, err)
+
+		// Then: existing data can be decrypted, and new data is encrypted
+		// with the data key.
+		got, err := cryptDB.GetUserLinkByLinkedID(ctx, link.LinkedID)
+		require.NoError(t, err)
+		require.Equal(t, "access", got.OAuthAccessToken)
+		updated, err := cryptDB.UpdateUserLink(ctx, database.UpdateUserLinkParams{
+			UserID:           user.ID,
+			LoginType:        link.LoginType,
+			OAuthAccessToken: "updated",
+		})
+		require.NoError(t, err)
+		require.NotEqual(t, static.HexDigest(), updated.OAuthAccessTokenKeyID.String)
+	})
+
+	t.Run("WrongProvider", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		rawDB, _ := dbtestutil.NewDB(t)
+
+		_, err := NewWithKeyProvider(ctx, rawDB, &fakeKeyProvider{})
+		require.NoError(t, err)
+
+		// A data key of another provider cannot be decrypted.
+		_, err = NewWithKeyProvider(ctx, rawDB, &fakeKeyProvider{name: "other"})
+		var derr *DecryptFailedError
+		require.ErrorAs(t, err, &derr)
+	})
+}
+
+func TestKeyRotator(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	rawDB, _ := dbtestutil.NewDB(t)
+	provider := &fakeKeyProvider{}
+	store, err := NewWithKeyProvider(ctx, rawDB, provider)
+	require.NoError(t, err)
+	replica, err := NewWithKeyProvider(ctx, rawDB, provider)
+	require.NoError(t, err)
+	cryptDB, ok := store.(*dbCrypt)
+	require.True(t, ok)
+	user := dbgen.User(t, cryptDB, database.User{})
+	link := dbgen.UserLink(t, cryptDB, database.UserLink{
+		UserID:           user.ID,
+		OAuthAccessToken: "access",
+	})
+	first := link.OAuthAccessTokenKeyID.String
+
+	clock := quartz.NewMock(t)
+	clock.Set(time.Now())
+	r := &keyRotator{
+		db:       cryptDB,
+		logger:   slogtest.Make(t, nil),
+		clock:    clock,
+		interval: time.Hour,
+	}
+
+	// Nothing happens while the key is young.
+	require.NoError(t, r.rotate(ctx))
+	keys, err := rawDB.GetDBCryptKeys(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+
+	// A new key is created once the key is older than the interval.
+	clock.Advance(time.Hour)
+	require.NoError(t, r.rotate(ctx))
+	keys, err = rawDB.GetDBCryptKeys(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	second := keys[1].ActiveKeyDigest.String
+	primary, ok := cryptDB.keys.primaryCipher()
+	require.True(t, ok)
+	require.Equal(t, second, primary.HexDigest())
+
+	// The replica loads the new key when it encounters it.
+	updated, err := cryptDB.UpdateUserLink(ctx, database.UpdateUserLinkParams{
+		UserID:           user.ID,
+		LoginType:        link.LoginType,
+		OAuthAccessToken: "updated",
+	})
+	require.NoError(t, err)
+	require.Equal(t, second, updated.OAuthAccessTokenKeyID.String)
+	got, err := replica.GetUserLinkByLinkedID(ctx, link.LinkedID)
+	require.NoError(t, err)
+	require.Equal(t, "updated", got.OAuthAccessToken)
+
+	// Once all replicas had the chance to load the new key, the previous
+	// key is revoked. The new key was created at the real time, which is an
+	// hour behind the clock.
+	r.interval = 2 * time.Hour
+	clock.Advance(2 * keyRotationCheckInterval)
+	require.NoError(t, r.rotate(ctx))
+	keys, err = rawDB.GetDBCryptKeys(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	require.Equal(t, first, keys[0].RevokedKeyDigest.String)
+	require.Equal(t, second, keys[1].ActiveKeyDigest.String)
+}
+
+// TestKeyRotatorCryptoKeys ensures crypto keys are re-encrypted before the
+// previous data key is revoked. Their secret_key_id references the active data
+// keys, so Postgres refuses to revoke a key that is still in use.
+func TestKeyRotatorCryptoKeys(t *testing.T) {
+	t.Parallel()
+	if !dbtestutil.WillUsePostgres() {
+		t.Skip("this test requires a postgres instance")
+	}
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	rawDB, _ := dbtestutil.NewDB(t)
+	store, err := NewWithKeyProvider(ctx, rawDB, &fakeKeyProvider{})
+	require.NoError(t, err)
+	cryptDB, ok := store.(*dbCrypt)
+	require.True(t, ok)
+	key := dbgen.CryptoKey(t, cryptDB, database.CryptoKey{
+		Feature: database.CryptoKeyFeatureWorkloadIdentity,
+		Secret:  sql.NullString{String: "secret", Valid: true},
+	})
+	first := key.SecretKeyID.String
+	require.NotEmpty(t, first)
+
+	clock := quartz.NewMock(t)
+	clock.Set(time.Now())
+	r := &keyRotator{
+		db:       cryptDB,
+		logger:   slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}),
+		clock:    clock,
+		interval: time.Hour,
+	}
+
+	clock.Advance(time.Hour)
+	require.NoError(t, r.rotate(ctx))
+	r.interval = 2 * time.Hour
+	clock.Advance(2 * keyRotationCheckInterval)
+	require.NoError(t, r.rotate(ctx))
+
+	keys, err := rawDB.GetDBCryptKeys(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	require.Equal(t, first, keys[0].RevokedKeyDigest.String)
+	second := keys[1].ActiveKeyDigest.String
+
+	encrypted, err := rawDB.GetCryptoKeyByFeatureAndSequence(ctx, database.GetCryptoKeyByFeatureAndSequenceParams{
+		Feature:  key.Feature,
+		Sequence: key.Sequence,
+	})
+	require.NoError(t, err)
+	require.Equal(t, second, encrypted.SecretKeyID.String)
+	decrypted, err := cryptDB.GetCryptoKeyByFeatureAndSequence(ctx, database.GetCryptoKeyByFeatureAndSequenceParams{
+		Feature:  key.Feature,
+		Sequence: key.Sequence,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "secret", decrypted.Secret.String)
+}
+
+func TestVaultTransit(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		var req map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		var data map[string]string
+		switch r.URL.Path {
+		case "/v1/transit/encrypt/coder":
+			data = map[string]string{"ciphertext": "vault:v1:" + req["plaintext"]}
+		case "/v1/transit/decrypt/coder":
+			data = map[string]string{"plaintext": strings.TrimPrefix(req["ciphertext"], "vault:v1:")}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+	}))
+	t.Cleanup(srv.Close)
+	address, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("token\n"), 0o600))
+
+	vault := &VaultTransit{
+		Address:   address,
+		TokenFile: tokenFile,
+		Mount:     "transit",
+		Key:       "coder",
+	}
+	require.Equal(t, "vault-transit:transit/coder", vault.Name())
+	wrapped, err := vault.WrapKey(ctx, []byte("key"))
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(wrapped), "vault:v1:"))
+	key, err := vault.UnwrapKey(ctx, wrapped)
+	require.NoError(t, err)
+	require.Equal(t, "key", string(key))
+
+	require.NoError(t, os.WriteFile(tokenFile, []byte("wrong"), 0o600))
+	_, err = vault.WrapKey(ctx, []byte("key"))
+	require.ErrorContains(t, err, "permission denied")
+}
+
+func TestCommandKeyProvider(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("the test uses a shell script")
+	}
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	script := filepath.Join(t.TempDir(), "kms")
+	err := os.WriteFile(script, []byte(`#!/bin/sh
+[ "$1" = "--key=coder" ] || { echo "unexpected arguments: $*" >&2; exit 1; }
+case "$2" in
+wrap) { printf 'wrapped:'; base64 -d; } | base64 ;;
+unwrap) base64 -d | tail -c +9 | base64 ;;
+esac
+`), 0o700) // #nosec G306
+	require.NoError(t, err)
+
+	provider := &CommandKeyProvider{Command: script, Args: []string{"--key=coder"}}
+	wrapped, err := provider.WrapKey(ctx, []byte("data key"))
+	require.NoError(t, err)
+	require.NotEqual(t, "data key", string(wrapped))
+	key, err := provider.UnwrapKey(ctx, wrapped)
+	require.NoError(t, err)
+	require.Equal(t, "data key", string(key))
+
+	provider.Args = nil
+	_, err = provider.WrapKey(ctx, []byte("data key"))
+	require.ErrorContains(t, err, "unexpected arguments")
+}
+
+// fakeKeyProvider wraps keys by reversing them.
+type fakeKeyProvider struct {
+	name string
+}
+
+func (p *fakeKeyProvider) Name() string {
+	if p.name == "" {
+		return "fake"
+	}
+	return p.name
+}
+
+func (*fakeKeyProvider) WrapKey(_ context.Context, key []byte) ([]byte, error) {
+	return reverse(key), nil
+}
+
+func (p *fakeKeyProvider) UnwrapKey(_ context.Context, wrapped []byte) ([]byte, error) {
+	return reverse(wrapped), nil
+}
+
+func reverse(b []byte) []byte {
+	r := make([]byte, len(b))
+	for i := range b {
+		r[len(b)-1-i] = b[i]
+	}
+	return r
+}
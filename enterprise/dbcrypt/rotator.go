@@ -0,0 +1,221 @@
+package dbcrypt
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/quartz"
+)
+
+const (
+	// keyRotationCheckInterval is the interval at which data keys are
+	// checked for rotation. Every replica loads new data keys at this
+	// interval.
+	keyRotationCheckInterval = time.Minute * 10
+	// DefaultKeyRotationInterval is the default age of a data key after which
+	// it is replaced by a new one.
+	DefaultKeyRotationInterval = time.Hour * 24 * 30
+)
+
+// keyRotator is responsible for rotating the data keys of a key provider.
+type keyRotator struct {
+	db       *dbCrypt
+	logger   slog.Logger
+	clock    quartz.Clock
+	interval time.Duration
+}
+
+type KeyRotatorOption func(*keyRotator)
+
+func WithKeyRotatorClock(clock quartz.Clock) KeyRotatorOption {
+	return func(r *keyRotator) {
+		r.clock = clock
+	}
+}
+
+func WithKeyRotationInterval(interval time.Duration) KeyRotatorOption {
+	return func(r *keyRotator) {
+		r.interval = interval
+	}
+}
+
+// StartKeyRotator starts a background process that replaces the data key of
+// the store with a new one once it is older than the rotation interval. User
+// tokens, secrets and crypto keys are then re-encrypted with the new key, and
+// the previous keys, including static keys, are revoked. The store must be created with
+// NewWithKeyProvider. Canceling the provided context will stop the background
+// process.
+func StartKeyRotator(ctx context.Context, logger slog.Logger, store database.Store, opts ...KeyRotatorOption) error {
+	db, ok := store.(*dbCrypt)
+	if !ok || db.keys.provider == nil {
+		return xerrors.New("developer error: the store was not created with dbcrypt.NewWithKeyProvider")
+	}
+	// nolint:gocritic // The key rotator needs to read and update all user tokens, secrets and crypto keys.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	r := &keyRotator{
+		db:       db,
+		logger:   logger.Named("dbcrypt_rotator"),
+		clock:    quartz.NewReal(),
+		interval: DefaultKeyRotationInterval,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	go r.start(ctx)
+	return nil
+}
+
+func (r *keyRotator) start(ctx context.Context) {
+	r.clock.TickerFunc(ctx, keyRotationCheckInterval, func() error {
+		err := r.rotate(ctx)
+		if err != nil {
+			r.logger.Error(ctx, "failed to rotate database encryption keys", slog.Error(err))
+		}
+		return nil
+	}, "dbcrypt", "rotate")
+	r.logger.Debug(ctx, "ctx canceled, stopping database encryption key rotation")
+}
+
+// rotate creates a new data key if the primary one is too old. Once every
+// replica had the chance to load the newest key, data that is encrypted with
+// other keys is re-encrypted, and the other keys are revoked.
+func (r *keyRotator) rotate(ctx context.Context) error {
+	err := r.db.keys.load(ctx, r.db.Store)
+	if err != nil {
+		return xerrors.Errorf("load data keys: %w", err)
+	}
+	primary, ok := r.db.keys.primaryCipher()
+	if !ok {
+		return xerrors.New("no primary data key")
+	}
+	keys, err := r.db.Store.GetDBCryptKeys(ctx)
+	if err != nil {
+		return xerrors.Errorf("get keys: %w", err)
+	}
+
+	var (
+		primaryKey database.DBCryptKey
+		previous   []string
+	)
+	for _, key := range keys {
+		if !key.ActiveKeyDigest.Valid {
+			continue
+		}
+		if key.ActiveKeyDigest.String == primary.HexDigest() {
+			primaryKey = key
+			continue
+		}
+		previous = append(previous, key.ActiveKeyDigest.String)
+	}
+	if !primaryKey.ActiveKeyDigest.Valid {
+		return xerrors.Errorf("primary data key %q is not active", primary.HexDigest())
+	}
+
+	age := r.clock.Since(primaryKey.CreatedAt.Time)
+	if r.interval > 0 && age >= r.interval {
+		return r.newKey(ctx, primary.HexDigest())
+	}
+	// Replicas that have not loaded the primary key yet still encrypt data
+	// with the previous keys, which then could not be revoked.
+	if age < 2*keyRotationCheckInterval || len(previous) == 0 {
+		return nil
+	}
+
+	userIDs, err := r.db.Store.AllUserIDs(ctx)
+	if err != nil {
+		return xerrors.Errorf("get users: %w", err)
+	}
+	r.logger.Info(ctx, "re-encrypting user tokens with the newest data key",
+		slog.F("user_count", len(userIDs)),
+		slog.F("digest", primary.HexDigest()),
+	)
+	err = reencryptUserTokens(ctx, r.logger, r.db, userIDs, primary.HexDigest())
+	if err != nil {
+		return err
+	}
+	err = reencryptSecrets(ctx, r.logger, r.db, primary.HexDigest())
+	if err != nil {
+		return err
+	}
+	err = reencryptCryptoKeys(ctx, r.logger, r.db, primary.HexDigest())
+	if err != nil {
+		return err
+	}
+	for _, digest := range previous {
+		err := r.db.RevokeDBCryptKey(ctx, digest)
+		if err != nil {
+			// Tokens may have been encrypted with the key since they were
+			// re-encrypted. They are re-encrypted on the next check.
+			r.logger.Warn(ctx, "revoke previous data key", slog.F("digest", digest), slog.Error(err))
+			continue
+		}
+		r.logger.Info(ctx, "revoked previous data key", slog.F("digest", digest))
+	}
+	return nil
+}
+
+// newKey inserts a new data key, unless another replica has already
+// replaced the current primary key.
+func (r *keyRotator) newKey(ctx context.Context, current string) error {
+	var created Cipher
+	err := r.db.Store.InTx(func(tx database.Store) error {
+		ok, err := tx.TryAcquireLock(ctx, database.LockIDDBCryptKeyRotation)
+		if err != nil {
+			return xerrors.Errorf("acquire lock: %w", err)
+		}
+		if !ok {
+			return nil
+		}
+		keys, err := tx.GetDBCryptKeys(ctx)
+		if err != nil {
+			return xerrors.Errorf("get keys: %w", err)
+		}
+		var (
+			highestNumber int32
+			newest        string
+		)
+		for _, key := range keys {
+			highestNumber = max(highestNumber, key.Number)
+			if key.ActiveKeyDigest.Valid && key.KeyProvider.String == r.db.keys.provider.Name() {
+				newest = key.ActiveKeyDigest.String
+			}
+		}
+		if newest != current {
+			// Another replica created a newer key, which is loaded on the
+			// next check.
+			return nil
+		}
+
+		c, err := NewDataKey(ctx, r.db.keys.provider)
+		if err != nil {
+			return err
+		}
+		insert := &dbCrypt{keys: newKeyring(nil, c), Store: tx}
+		insert.keys.primary = c.HexDigest()
+		err = insert.InsertDBCryptKey(ctx, insertKeyParams(c, highestNumber+1))
+		if err != nil {
+			return xerrors.Errorf("insert data key: %w", err)
+		}
+		created = c
+		return nil
+	}, &database.TxOptions{
+		Isolation:    sql.LevelRepeatableRead,
+		TxIdentifier: "dbcrypt_rotate_key",
+	})
+	if err != nil {
+		return err
+	}
+	if created != nil {
+		r.db.keys.add(created)
+		r.db.keys.setPrimary(created.HexDigest())
+		r.logger.Info(ctx, "created new data key", slog.F("digest", created.HexDigest()))
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package dbcrypt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// VaultTransit wraps data keys with a key of the HashiCorp Vault transit
+// secrets engine. The key encryption key never leaves Vault, and rotating it
+// in Vault does not require data keys to be re-wrapped.
+type VaultTransit struct {
+	// Address is the URL of the Vault server.
+	Address *url.URL
+	// TokenFile is a file that contains the Vault token, e.g. one written by
+	// Vault Agent. It is read for every request so that the token can be
+	// renewed without restarting Coder.
+	TokenFile string
+	// Namespace is the Vault Enterprise namespace of the transit mount.
+	Namespace string
+	// Mount is the path the transit secrets engine is mounted at.
+	Mount string
+	// Key is the name of the transit key.
+	Key string
+	// HTTPClient is used to talk to Vault. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+var _ KeyProvider = &VaultTransit{}
+
+func (v *VaultTransit) Name() string {
+	return "vault-transit:" + path.Join(v.Mount, v.Key)
+}
+
+func (v *VaultTransit) WrapKey(ctx context.Context, key []byte) ([]byte, error) {
+	var res struct {
+		Ciphertext string `json:"ciphertext"`
+	}
+	err := v.request(ctx, "encrypt", map[string]string{"plaintext": b64encode(key)}, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Ciphertext == "" {
+		return nil, xerrors.New("vault returned an empty ciphertext")
+	}
+	return []byte(res.Ciphertext), nil
+}
+
+func (v *VaultTransit) UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	var res struct {
+		Plaintext string `json:"plaintext"`
+	}
+	err := v.request(ctx, "decrypt", map[string]string{"ciphertext": string(wrapped)}, &res)
+	if err != nil {
+		return nil, err
+	}
+	key, err := b64decode(res.Plaintext)
+	if err != nil {
+		return nil, xerrors.Errorf("decode plaintext: %w", err)
+	}
+	return key, nil
+}
+
+// request calls an endpoint of the transit secrets engine for the key, and
+// decodes the data of the response into res.
+func (v *VaultTransit) request(ctx context.Context, operation string, body any, res any) error {
+	token, err := os.ReadFile(v.TokenFile)
+	if err != nil {
+		return xerrors.Errorf("read vault token: %w", err)
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	u := v.Address.JoinPath("v1", v.Mount, operation, v.Key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Token", strings.TrimSpace(string(token)))
+	req.Header.Set("X-Vault-Request", "true")
+	if v.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.Namespace)
+	}
+
+	client := v.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("%s with vault: %w", operation, err)
+	}
+	defer resp.Body.Close()
+
+	var payload struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []string        `json:"errors"`
+	}
+	err = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&payload)
+	if resp.StatusCode != http.StatusOK {
+		msg := resp.Status
+		if len(payload.Errors) > 0 {
+			msg = fmt.Sprintf("%s: %s", msg, strings.Join(payload.Errors, ", "))
+		}
+		return xerrors.Errorf("%s with vault: %s", operation, msg)
+	}
+	if err != nil {
+		return xerrors.Errorf("decode vault response: %w", err)
+	}
+	err = json.Unmarshal(payload.Data, res)
+	if err != nil {
+		return xerrors.Errorf("decode vault response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,669 @@
+package terraform
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/spf13/afero"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/unhanger"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+)
+
+const (
+	// cacheLockFile is locked while a job uses the cache, as Terraform
+	// doesn't support concurrent access to its plugin cache directory.
+	cacheLockFile = ".cache.lock"
+	// moduleCacheDir holds the cached modules within the cache directory. It
+	// starts with a dot so it's never mistaken for a provider registry host.
+	moduleCacheDir = ".modules"
+	// moduleCacheMaxAge is how long cached modules are reused for. Version
+	// constraints matching several versions are resolved again afterwards.
+	moduleCacheMaxAge = 24 * time.Hour
+	// cacheEvictionGracePeriod protects recently used entries from being
+	// evicted, as the jobs that used them may still be running.
+	cacheEvictionGracePeriod = time.Hour
+)
+
+const (
+	cacheKindProvider = "provider"
+	cacheKindModule   = "module"
+)
+
+// CacheMetrics are the metrics of the Terraform provider and module cache.
+type CacheMetrics struct {
+	Requests  *prometheus.CounterVec
+	Evictions *prometheus.CounterVec
+	SizeBytes prometheus.Gauge
+}
+
+func NewCacheMetrics(reg prometheus.Registerer) *CacheMetrics {
+	auto := promauto.With(reg)
+
+	return &CacheMetrics{
+		Requests: auto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "coderd",
+			Subsystem: "provisionerd",
+			Name:      "terraform_cache_requests_total",
+			Help:      "The number of Terraform providers and module sets looked up in the cache.",
+		}, []string{"kind", "result"}),
+		Evictions: auto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "coderd",
+			Subsystem: "provisionerd",
+			Name:      "terraform_cache_evictions_total",
+			Help:      "The number of Terraform providers and module sets evicted from the cache.",
+		}, []string{"kind"}),
+		SizeBytes: auto.NewGauge(prometheus.GaugeOpts{
+			Namespace: "coderd",
+			Subsystem: "provisionerd",
+			Name:      "terraform_cache_size_bytes",
+			Help:      "The size of the Terraform provider and module cache in bytes.",
+		}),
+	}
+}
+
+// cache is the Terraform provider and module cache shared by the jobs of a
+// provisioner daemon, and by any other daemon using the same directory.
+//
+// Providers are cached by Terraform itself in the root of the directory,
+// using the layout of TF_PLUGIN_CACHE_DIR. Modules are cached in
+// moduleCacheDir, keyed by a hash of the module calls of a template.
+type cache struct {
+	path string
+	// maxSize is the size in bytes above which the least recently used
+	// entries are evicted. Zero disables eviction.
+	maxSize int64
+	metrics *CacheMetrics
+	logger  slog.Logger
+	now     func() time.Time
+}
+
+func newCache(path string, maxSize int64, metrics *CacheMetrics, logger slog.Logger) *cache {
+	if path == "" {
+		return nil
+	}
+	return &cache{
+		path:    path,
+		maxSize: maxSize,
+		metrics: metrics,
+		logger:  logger.Named("cache"),
+		now:     time.Now,
+	}
+}
+
+// lock acquires the cache lock, waiting for other jobs to release it.
+func (c *cache) lock(ctx context.Context) (func(), error) {
+	err := os.MkdirAll(c.path, 0o700)
+	if err != nil {
+		return nil, xerrors.Errorf("create cache directory: %w", err)
+	}
+	lockFilePath := filepath.Join(c.path, cacheLockFile)
+	lock := flock.New(lockFilePath)
+	ok, err := lock.TryLockContext(ctx, 100*time.Millisecond)
+	if !ok {
+		return nil, xerrors.Errorf("could not acquire flock for %v: %w", lockFilePath, err)
+	}
+	return func() { _ = lock.Close() }, nil
+}
+
+// cacheRun tracks the use of the cache by a single "terraform init".
+type cacheRun struct {
+	cache   *cache
+	workdir string
+	// providers are the cached provider directories before init.
+	providers map[string]struct{}
+	// moduleKey is empty if the modules of the workdir can't be cached.
+	moduleKey string
+	// moduleManifest is the restored manifest, if any.
+	moduleManifest []byte
+}
+
+// prepare must be called with the lock held, before "terraform init".
+// It removes stale providers and restores the cached modules of the workdir.
+func (c *cache) prepare(ctx context.Context, workdir string) (*cacheRun, error) {
+	err := CleanStaleTerraformPlugins(ctx, c.path, afero.NewOsFs(), c.now(), c.logger)
+	if err != nil {
+		return nil, xerrors.Errorf("clean stale Terraform plugins: %w", err)
+	}
+
+	providers, err := providerDirs(c.path)
+	if err != nil {
+		return nil, xerrors.Errorf("list cached providers: %w", err)
+	}
+	run := &cacheRun{
+		cache:     c,
+		workdir:   workdir,
+		providers: map[string]struct{}{},
+	}
+	for _, p := range providers {
+		run.providers[p] = struct{}{}
+	}
+
+	key, err := moduleCacheKey(workdir)
+	if err != nil {
+		// The template is invalid, which "terraform init" reports better.
+		c.logger.Debug(ctx, "unable to compute module cache key", slog.Error(err))
+		return run, nil
+	}
+	run.moduleKey = key
+	if key == "" {
+		return run, nil
+	}
+
+	entry := filepath.Join(c.path, moduleCacheDir, key)
+	manifestPath := filepath.Join(entry, "modules.json")
+	// The manifest is written when the entry is stored, and never modified.
+	info, err := os.Stat(manifestPath)
+	if err != nil {
+		if !xerrors.Is(err, fs.ErrNotExist) {
+			c.logger.Warn(ctx, "unable to read cached modules", slog.F("entry", entry), slog.Error(err))
+		}
+		return run, nil
+	}
+	if info.ModTime().Add(moduleCacheMaxAge).Before(c.now()) {
+		c.logger.Debug(ctx, "cached modules expired", slog.F("entry", entry))
+		return run, nil
+	}
+	manifest, err := os.ReadFile(manifestPath)
+	if err != nil {
+		c.logger.Warn(ctx, "unable to read cached modules", slog.F("entry", entry), slog.Error(err))
+		return run, nil
+	}
+	modulesDir := filepath.Dir(getModulesFilePath(workdir))
+	if _, err := os.Stat(modulesDir); err == nil {
+		// The workdir has modules of its own already.
+		return run, nil
+	}
+	err = copyDir(entry, modulesDir)
+	if err != nil {
+		c.logger.Warn(ctx, "unable to restore cached modules", slog.F("entry", entry), slog.Error(err))
+		_ = os.RemoveAll(modulesDir)
+		return run, nil
+	}
+	now := c.now()
+	_ = os.Chtimes(entry, now, now)
+	run.moduleManifest = manifest
+	c.logger.Debug(ctx, "restored cached modules", slog.F("entry", entry))
+	return run, nil
+}
+
+// complete must be called with the lock held, after "terraform init"
+// succeeded. It records cache hits, stores the modules of the workdir and
+// evicts entries above the size limit.
+func (r *cacheRun) complete(ctx context.Context) {
+	c := r.cache
+	now := c.now()
+
+	providers, err := providerDirs(filepath.Join(r.workdir, ".terraform", "providers"))
+	if err != nil {
+		c.logger.Warn(ctx, "unable to list providers", slog.Error(err))
+	}
+	for _, p := range providers {
+		cached := filepath.Join(c.path, p)
+		if _, err := os.Stat(cached); err != nil {
+			// Providers aren't cached when the plugin cache is disabled.
+			continue
+		}
+		_, hit := r.providers[p]
+		c.recordRequest(cacheKindProvider, hit)
+		// Terraform links to the cached provider without modifying it, so
+		// mark it as used for eviction and stale plugin cleanup.
+		_ = os.Chtimes(cached, now, now)
+	}
+
+	if r.moduleKey != "" {
+		err = r.storeModules(ctx)
+		if err != nil {
+			c.logger.Warn(ctx, "unable to cache modules", slog.Error(err))
+		}
+	}
+
+	err = c.evict(ctx)
+	if err != nil {
+		c.logger.Warn(ctx, "unable to evict cache entries", slog.Error(err))
+	}
+}
+
+func (r *cacheRun) storeModules(ctx context.Context) error {
+	c := r.cache
+	manifestPath := getModulesFilePath(r.workdir)
+	manifest, err := os.ReadFile(manifestPath)
+	if xerrors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return xerrors.Errorf("read modules file: %w", err)
+	}
+	if r.moduleManifest != nil {
+		hit := bytes.Equal(manifest, r.moduleManifest)
+		c.recordRequest(cacheKindModule, hit)
+		if hit {
+			return nil
+		}
+	} else {
+		c.recordRequest(cacheKindModule, false)
+	}
+
+	var modules modulesFile
+	err = json.Unmarshal(manifest, &modules)
+	if err != nil {
+		return xerrors.Errorf("unmarshal modules file: %w", err)
+	}
+	for _, m := range modules.Modules {
+		// Only registry modules are pinned to a version. Other remote
+		// sources, like Git branches, may change at any time.
+		if m.Key != "" && !isLocalModuleSource(m.Source) && m.Version == "" {
+			c.logger.Debug(ctx, "modules not cached, not all modules are versioned", slog.F("module", m.Key))
+			return nil
+		}
+	}
+
+	entries := filepath.Join(c.path, moduleCacheDir)
+	err = os.MkdirAll(entries, 0o700)
+	if err != nil {
+		return xerrors.Errorf("create module cache directory: %w", err)
+	}
+	tmp, err := os.MkdirTemp(entries, ".tmp-")
+	if err != nil {
+		return xerrors.Errorf("create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+	err = copyDir(filepath.Dir(manifestPath), tmp)
+	if err != nil {
+		return xerrors.Errorf("copy modules: %w", err)
+	}
+	entry := filepath.Join(entries, r.moduleKey)
+	err = os.RemoveAll(entry)
+	if err != nil {
+		return xerrors.Errorf("remove previous entry: %w", err)
+	}
+	err = os.Rename(tmp, entry)
+	if err != nil {
+		return xerrors.Errorf("rename entry: %w", err)
+	}
+	c.logger.Debug(ctx, "cached modules", slog.F("entry", entry))
+	return nil
+}
+
+func (c *cache) recordRequest(kind string, hit bool) {
+	if c.metrics == nil {
+		return
+	}
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	c.metrics.Requests.WithLabelValues(kind, result).Inc()
+}
+
+type cacheEntry struct {
+	kind     string
+	path     string
+	size     int64
+	lastUsed time.Time
+}
+
+// evict removes the least recently used entries until the cache fits in
+// maxSize, and reports the size of the cache.
+func (c *cache) evict(ctx context.Context) error {
+	entries, err := c.entries()
+	if err != nil {
+		return err
+	}
+	var size int64
+	for _, e := range entries {
+		size += e.size
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].lastUsed.Before(entries[j].lastUsed)
+	})
+	now := c.now()
+	for _, e := range entries {
+		if c.maxSize <= 0 || size <= c.maxSize {
+			break
+		}
+		if e.lastUsed.Add(cacheEvictionGracePeriod).After(now) {
+			c.logger.Warn(ctx, "cache exceeds its size limit, but all entries are in use",
+				slog.F("size", size), slog.F("max_size", c.maxSize))
+			break
+		}
+		c.logger.Info(ctx, "evicting cache entry", slog.F("path", e.path), slog.F("last_used", e.lastUsed))
+		err = os.RemoveAll(e.path)
+		if err != nil {
+			return xerrors.Errorf("remove %q: %w", e.path, err)
+		}
+		if e.kind == cacheKindProvider {
+			removeEmptyParents(c.path, e.path)
+		}
+		size -= e.size
+		if c.metrics != nil {
+			c.metrics.Evictions.WithLabelValues(e.kind).Inc()
+		}
+	}
+	if c.metrics != nil {
+		c.metrics.SizeBytes.Set(float64(size))
+	}
+	return nil
+}
+
+// entries returns the cached providers and module sets.
+func (c *cache) entries() ([]cacheEntry, error) {
+	var entries []cacheEntry
+	providers, err := providerDirs(c.path)
+	if err != nil {
+		return nil, xerrors.Errorf("list cached providers: %w", err)
+	}
+	for _, p := range providers {
+		e, err := newCacheEntry(cacheKindProvider, filepath.Join(c.path, p))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	modules, err := os.ReadDir(filepath.Join(c.path, moduleCacheDir))
+	if err != nil && !xerrors.Is(err, fs.ErrNotExist) {
+		return nil, xerrors.Errorf("list cached modules: %w", err)
+	}
+	for _, m := range modules {
+		if !m.IsDir() || strings.HasPrefix(m.Name(), ".") {
+			continue
+		}
+		e, err := newCacheEntry(cacheKindModule, filepath.Join(c.path, moduleCacheDir, m.Name()))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+func newCacheEntry(kind, dir string) (cacheEntry, error) {
+	e := cacheEntry{kind: kind, path: dir}
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			e.size += info.Size()
+		}
+		if info.ModTime().After(e.lastUsed) {
+			e.lastUsed = info.ModTime()
+		}
+		return nil
+	})
+	if err != nil {
+		return cacheEntry{}, xerrors.Errorf("walk %q: %w", dir, err)
+	}
+	if kind == cacheKindModule {
+		// Restoring modules only touches the entry directory.
+		info, err := os.Stat(dir)
+		if err != nil {
+			return cacheEntry{}, xerrors.Errorf("stat %q: %w", dir, err)
+		}
+		e.lastUsed = info.ModTime()
+	}
+	return e, nil
+}
+
+// providerDirs returns the provider directories, relative to dir, following
+// the layout <host>/<namespace>/<type>/<version>/<platform>.
+func providerDirs(dir string) ([]string, error) {
+	var dirs []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == dir && xerrors.Is(err, fs.ErrNotExist) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if p == dir {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		parts := strings.Split(rel, string(filepath.Separator))
+		if len(parts) == 5 {
+			// Terraform links to the plugin cache from workdirs.
+			if d.IsDir() || d.Type()&fs.ModeSymlink != 0 {
+				dirs = append(dirs, rel)
+			}
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		// Registry hosts are domain names. Skip anything else that may
+		// share the directory, like the module cache.
+		if len(parts) == 1 && (strings.HasPrefix(parts[0], ".") || !strings.Contains(parts[0], ".")) {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return dirs, err
+}
+
+// removeEmptyParents removes the empty parent directories of dir up to, but
+// excluding, root.
+func removeEmptyParents(root, dir string) {
+	for {
+		dir = filepath.Dir(dir)
+		if dir == root || !strings.HasPrefix(dir, root) {
+			return
+		}
+		if os.Remove(dir) != nil {
+			// The directory isn't empty.
+			return
+		}
+	}
+}
+
+// moduleCacheKey returns the key of the modules of the Terraform
+// configuration in workdir. It is derived from the source and version
+// constraint of every module call, following local modules. An empty key
+// means the configuration doesn't use remote modules.
+func moduleCacheKey(workdir string) (string, error) {
+	var calls []string
+	var walk func(dir, prefix string) error
+	walk = func(dir, prefix string) error {
+		mod, diags := tfconfig.LoadModule(dir)
+		if diags.HasErrors() {
+			return xerrors.Errorf("load module %q: %w", dir, diags.Err())
+		}
+		for name, call := range mod.ModuleCalls {
+			key := path.Join(prefix, name)
+			calls = append(calls, strings.Join([]string{key, call.Source, call.Version}, "\x00"))
+			if isLocalModuleSource(call.Source) {
+				err := walk(filepath.Join(dir, filepath.FromSlash(call.Source)), key)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	err := walk(workdir, "")
+	if err != nil {
+		return "", err
+	}
+
+	remote := false
+	for _, call := range calls {
+		source := strings.Split(call, "\x00")[1]
+		if !isLocalModuleSource(source) {
+			remote = true
+			break
+		}
+	}
+	if !remote {
+		return "", nil
+	}
+
+	sort.Strings(calls)
+	hash := sha256.New()
+	for _, call := range calls {
+		_, _ = io.WriteString(hash, call+"\n")
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func isLocalModuleSource(source string) bool {
+	return strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../")
+}
+
+// copyDir copies the files, directories and symbolic links in src to dst.
+func copyDir(src, dst string) error {
+	return filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0o700)
+		case info.Mode()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(p)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case info.Mode().IsRegular():
+			return copyFile(p, target, info.Mode().Perm())
+		default:
+			return nil
+		}
+	})
+}
+
+func copyFile(src, dst string, perm fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+type WarmCacheOptions struct {
+	// Directory contains the Terraform configuration to cache the providers
+	// and modules of, like a template.
+	Directory string
+	// CachePath is the cache directory of the provisioner daemons.
+	CachePath string
+	// BinaryPath specifies the "terraform" binary to use.
+	// If omitted, the $PATH will attempt to find it.
+	BinaryPath string
+	Logger     slog.Logger
+	// Output receives the output of "terraform init".
+	Output io.Writer
+}
+
+// WarmCache downloads the providers and modules of a Terraform configuration
+// to the cache, so that provisioner daemons without network access can be
+// seeded with a copy of it.
+func WarmCache(ctx context.Context, options WarmCacheOptions) error {
+	if options.CachePath == "" {
+		return xerrors.New("cache path is required")
+	}
+	if options.Output == nil {
+		options.Output = io.Discard
+	}
+	binaryPath := options.BinaryPath
+	if binaryPath == "" {
+		var err error
+		binaryPath, err = findOrInstallBinary(ctx, options.Logger, options.CachePath)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Initialize a copy of the configuration, so the directory isn't
+	// modified and modules it may have downloaded already aren't reused.
+	workdir, err := os.MkdirTemp("", "coder-terraform-cache-")
+	if err != nil {
+		return xerrors.Errorf("create workdir: %w", err)
+	}
+	defer os.RemoveAll(workdir)
+	err = copyDir(options.Directory, workdir)
+	if err != nil {
+		return xerrors.Errorf("copy %q: %w", options.Directory, err)
+	}
+	err = os.RemoveAll(filepath.Join(workdir, ".terraform"))
+	if err != nil {
+		return xerrors.Errorf("remove .terraform: %w", err)
+	}
+
+	s := &server{
+		execMut:     &sync.Mutex{},
+		binaryPath:  binaryPath,
+		cachePath:   options.CachePath,
+		cache:       newCache(options.CachePath, 0, nil, options.Logger),
+		logger:      options.Logger,
+		tracer:      trace.NewNoopTracerProvider().Tracer("noop"),
+		exitTimeout: unhanger.HungJobExitTimeout,
+	}
+	e := s.executor(workdir, database.ProvisionerJobTimingStageInit)
+	err = e.init(ctx, ctx, &writerLogSink{w: options.Output})
+	if err != nil {
+		return xerrors.Errorf("initialize terraform: %w", err)
+	}
+	return nil
+}
+
+// writerLogSink writes provisioner logs to w.
+type writerLogSink struct {
+	w io.Writer
+}
+
+func (s *writerLogSink) ProvisionLog(_ proto.LogLevel, output string) {
+	_, _ = fmt.Fprintln(s.w, output)
+}
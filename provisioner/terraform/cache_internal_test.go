@@ -0,0 +1,177 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	promtest "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	newTestCache := func(t *testing.T, maxSize int64) *cache {
+		t.Helper()
+		return newCache(t.TempDir(), maxSize, NewCacheMetrics(prometheus.NewRegistry()), testutil.Logger(t))
+	}
+
+	writeFile := func(t *testing.T, path, content string) {
+		t.Helper()
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+
+	newWorkdir := func(t *testing.T, source, version string) string {
+		t.Helper()
+		workdir := t.TempDir()
+		writeFile(t, filepath.Join(workdir, "main.tf"), `
+module "code-server" {
+  source  = "`+source+`"
+  version = "`+version+`"
+}
+`)
+		return workdir
+	}
+
+	// initModules mimics "terraform init" downloading the modules of the
+	// workdir created by newWorkdir.
+	initModules := func(t *testing.T, workdir, source, version string) {
+		t.Helper()
+		writeFile(t, filepath.Join(workdir, ".terraform", "modules", "code-server", "main.tf"), "# code-server")
+		writeFile(t, getModulesFilePath(workdir), `{"Modules":[`+
+			`{"Key":"","Source":"","Dir":"."},`+
+			`{"Key":"code-server","Source":"`+source+`","Version":"`+version+`","Dir":".terraform/modules/code-server"}]}`)
+	}
+
+	t.Run("Modules", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		c := newTestCache(t, 0)
+		const source = "registry.coder.com/modules/code-server/coder"
+
+		workdir := newWorkdir(t, source, "1.0.0")
+		run, err := c.prepare(ctx, workdir)
+		require.NoError(t, err)
+		require.NotEmpty(t, run.moduleKey)
+		require.Nil(t, run.moduleManifest)
+		initModules(t, workdir, source, "1.0.0")
+		run.complete(ctx)
+		require.Equal(t, 1.0, promtest.ToFloat64(c.metrics.Requests.WithLabelValues(cacheKindModule, "miss")))
+
+		// The modules of the same configuration are restored.
+		workdir = newWorkdir(t, source, "1.0.0")
+		run, err = c.prepare(ctx, workdir)
+		require.NoError(t, err)
+		require.NotNil(t, run.moduleManifest)
+		content, err := os.ReadFile(filepath.Join(workdir, ".terraform", "modules", "code-server", "main.tf"))
+		require.NoError(t, err)
+		require.Equal(t, "# code-server", string(content))
+		run.complete(ctx)
+		require.Equal(t, 1.0, promtest.ToFloat64(c.metrics.Requests.WithLabelValues(cacheKindModule, "hit")))
+
+		// Other versions are cached separately.
+		workdir = newWorkdir(t, source, "1.1.0")
+		run, err = c.prepare(ctx, workdir)
+		require.NoError(t, err)
+		require.Nil(t, run.moduleManifest)
+
+		// Expired modules aren't restored.
+		c.now = func() time.Time { return time.Now().Add(moduleCacheMaxAge + time.Minute) }
+		workdir = newWorkdir(t, source, "1.0.0")
+		run, err = c.prepare(ctx, workdir)
+		require.NoError(t, err)
+		require.Nil(t, run.moduleManifest)
+	})
+
+	t.Run("UnversionedModules", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		c := newTestCache(t, 0)
+		const source = "git::https://github.com/coder/modules.git//code-server"
+
+		workdir := newWorkdir(t, source, "")
+		run, err := c.prepare(ctx, workdir)
+		require.NoError(t, err)
+		require.NotEmpty(t, run.moduleKey)
+		initModules(t, workdir, source, "")
+		run.complete(ctx)
+
+		_, err = os.Stat(filepath.Join(c.path, moduleCacheDir, run.moduleKey))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("Providers", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		c := newTestCache(t, 0)
+		coder := filepath.Join("registry.terraform.io", "coder", "coder", "2.0.0", "linux_amd64")
+		docker := filepath.Join("registry.terraform.io", "kreuzwerker", "docker", "3.0.2", "linux_amd64")
+		writeFile(t, filepath.Join(c.path, coder, "terraform-provider-coder"), "coder")
+
+		workdir := t.TempDir()
+		run, err := c.prepare(ctx, workdir)
+		require.NoError(t, err)
+		require.Empty(t, run.moduleKey)
+
+		// Terraform downloads missing providers to the cache, and links the
+		// workdir to it.
+		writeFile(t, filepath.Join(c.path, docker, "terraform-provider-docker"), "docker")
+		for _, p := range []string{coder, docker} {
+			link := filepath.Join(workdir, ".terraform", "providers", p)
+			require.NoError(t, os.MkdirAll(filepath.Dir(link), 0o700))
+			require.NoError(t, os.Symlink(filepath.Join(c.path, p), link))
+		}
+		run.complete(ctx)
+
+		require.Equal(t, 1.0, promtest.ToFloat64(c.metrics.Requests.WithLabelValues(cacheKindProvider, "hit")))
+		require.Equal(t, 1.0, promtest.ToFloat64(c.metrics.Requests.WithLabelValues(cacheKindProvider, "miss")))
+		require.Equal(t, float64(len("coder")+len("docker")), promtest.ToFloat64(c.metrics.SizeBytes))
+	})
+
+	t.Run("Evict", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		c := newTestCache(t, 10)
+		now := time.Now()
+		addProvider := func(name string, lastUsed time.Time) string {
+			dir := filepath.Join(c.path, "registry.terraform.io", "coder", name, "1.0.0", "linux_amd64")
+			file := filepath.Join(dir, "terraform-provider-"+name)
+			writeFile(t, file, "12345")
+			require.NoError(t, os.Chtimes(file, lastUsed, lastUsed))
+			require.NoError(t, os.Chtimes(dir, lastUsed, lastUsed))
+			return dir
+		}
+		oldest := addProvider("oldest", now.Add(-3*time.Hour))
+		old := addProvider("old", now.Add(-2*time.Hour))
+		recent := addProvider("recent", now.Add(-time.Minute))
+		inUse := addProvider("inuse", now)
+
+		// The two least recently used providers are evicted, leaving the
+		// cache at its limit.
+		require.NoError(t, c.evict(ctx))
+		require.NoDirExists(t, oldest)
+		require.NoDirExists(t, filepath.Join(c.path, "registry.terraform.io", "coder", "oldest"))
+		require.NoDirExists(t, old)
+		require.DirExists(t, recent)
+		require.DirExists(t, inUse)
+		require.Equal(t, 2.0, promtest.ToFloat64(c.metrics.Evictions.WithLabelValues(cacheKindProvider)))
+		require.Equal(t, 10.0, promtest.ToFloat64(c.metrics.SizeBytes))
+
+		// Recently used providers are never evicted, as running jobs may use
+		// them.
+		c.maxSize = 1
+		require.NoError(t, c.evict(ctx))
+		require.DirExists(t, recent)
+		require.DirExists(t, inUse)
+	})
+}
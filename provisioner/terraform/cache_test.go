@@ -0,0 +1,47 @@
+//go:build linux
+
+package terraform_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/provisioner/terraform"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWarmCache(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitSuperLong)
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(`
+terraform {
+  required_providers {
+    coder = {
+      source  = "coder/coder"
+      version = "0.6.20"
+    }
+  }
+}
+`), 0o600)
+	require.NoError(t, err)
+
+	cachePath := t.TempDir()
+	err = terraform.WarmCache(ctx, terraform.WarmCacheOptions{
+		Directory: dir,
+		CachePath: cachePath,
+		Logger:    testutil.Logger(t),
+	})
+	require.NoError(t, err)
+
+	platform := fmt.Sprintf("%s_%s", runtime.GOOS, runtime.GOARCH)
+	require.DirExists(t, filepath.Join(cachePath, "registry.terraform.io", "coder", "coder", "0.6.20", platform))
+	// The directory itself isn't modified.
+	require.NoDirExists(t, filepath.Join(dir, ".terraform"))
+}
@@ -0,0 +1,150 @@
+package terraform
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	tfjson "github.com/hashicorp/terraform-json"
+
+	"github.com/coder/coder/v2/provisionersdk/proto"
+)
+
+// ConvertResourceChanges summarizes what applying a plan will do to each
+// managed resource, so users can review an update before it happens. Resources
+// that won't change are omitted. The values of attributes that Terraform marks
+// as sensitive are never included.
+func ConvertResourceChanges(changes []*tfjson.ResourceChange) []*proto.ResourceChange {
+	converted := make([]*proto.ResourceChange, 0, len(changes))
+	for _, rc := range changes {
+		if rc == nil || rc.Change == nil || rc.Mode != tfjson.ManagedResourceMode {
+			continue
+		}
+		action := resourceChangeAction(rc.Change.Actions)
+		if action == "" {
+			continue
+		}
+		change := &proto.ResourceChange{
+			Address: rc.Address,
+			Type:    rc.Type,
+			Name:    rc.Name,
+			Action:  action,
+		}
+		// Creates and deletes affect every attribute, so listing them adds
+		// noise without telling the user anything.
+		if action == "update" || action == "replace" {
+			change.Attributes = convertAttributeChanges(rc.Change)
+		}
+		converted = append(converted, change)
+	}
+	sort.Slice(converted, func(i, j int) bool {
+		return converted[i].Address < converted[j].Address
+	})
+	return converted
+}
+
+func resourceChangeAction(actions tfjson.Actions) string {
+	switch {
+	case actions.Replace():
+		return "replace"
+	case actions.Create():
+		return "create"
+	case actions.Update():
+		return "update"
+	case actions.Delete():
+		return "delete"
+	default:
+		// No-op, read and forget don't touch the underlying infrastructure.
+		return ""
+	}
+}
+
+// convertAttributeChanges compares the top-level attributes of a resource
+// before and after the change.
+func convertAttributeChanges(change *tfjson.Change) []*proto.AttributeChange {
+	before, _ := change.Before.(map[string]interface{})
+	after, _ := change.After.(map[string]interface{})
+	afterUnknown, _ := change.AfterUnknown.(map[string]interface{})
+	beforeSensitive, _ := change.BeforeSensitive.(map[string]interface{})
+	afterSensitive, _ := change.AfterSensitive.(map[string]interface{})
+
+	replaced := map[string]bool{}
+	for _, path := range change.ReplacePaths {
+		steps, ok := path.([]interface{})
+		if !ok || len(steps) == 0 {
+			continue
+		}
+		if key, ok := steps[0].(string); ok {
+			replaced[key] = true
+		}
+	}
+
+	keys := map[string]struct{}{}
+	for key := range before {
+		keys[key] = struct{}{}
+	}
+	for key := range after {
+		keys[key] = struct{}{}
+	}
+	for key := range afterUnknown {
+		keys[key] = struct{}{}
+	}
+
+	attributes := make([]*proto.AttributeChange, 0)
+	for key := range keys {
+		unknown := containsTrue(afterUnknown[key])
+		if !unknown && !replaced[key] && reflect.DeepEqual(before[key], after[key]) {
+			continue
+		}
+		attribute := &proto.AttributeChange{
+			Path:              key,
+			Sensitive:         containsTrue(beforeSensitive[key]) || containsTrue(afterSensitive[key]),
+			AfterUnknown:      unknown,
+			ForcesReplacement: replaced[key],
+		}
+		if !attribute.Sensitive {
+			attribute.Before = marshalAttributeValue(before[key])
+			if !unknown {
+				attribute.After = marshalAttributeValue(after[key])
+			}
+		}
+		attributes = append(attributes, attribute)
+	}
+	sort.Slice(attributes, func(i, j int) bool {
+		return attributes[i].Path < attributes[j].Path
+	})
+	return attributes
+}
+
+// containsTrue reports whether v, or any value nested inside of it, is true.
+// Terraform uses this shape for both after_unknown and the sensitive markers,
+// mirroring the structure of the attribute value.
+func containsTrue(v interface{}) bool {
+	switch v := v.(type) {
+	case bool:
+		return v
+	case map[string]interface{}:
+		for _, e := range v {
+			if containsTrue(e) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, e := range v {
+			if containsTrue(e) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func marshalAttributeValue(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		// Values come from Terraform's JSON output, so this should never
+		// happen.
+		return ""
+	}
+	return string(data)
+}
@@ -0,0 +1,126 @@
+package terraform_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/provisioner/terraform"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+)
+
+func TestConvertResourceChanges(t *testing.T) {
+	t.Parallel()
+
+	// A trimmed down version of `terraform show -json` for a plan that
+	// replaces a disk, updates a container and creates a new agent token.
+	const rawPlan = `{
+		"format_version": "1.2",
+		"resource_changes": [
+			{
+				"address": "docker_volume.home",
+				"mode": "managed",
+				"type": "docker_volume",
+				"name": "home",
+				"change": {
+					"actions": ["delete", "create"],
+					"before": {"name": "coder-home", "driver": "local", "id": "abc"},
+					"after": {"name": "coder-home-2", "driver": "local"},
+					"after_unknown": {"id": true},
+					"before_sensitive": {},
+					"after_sensitive": {},
+					"replace_paths": [["name"]]
+				}
+			},
+			{
+				"address": "docker_container.workspace[0]",
+				"mode": "managed",
+				"type": "docker_container",
+				"name": "workspace",
+				"change": {
+					"actions": ["update"],
+					"before": {"image": "ubuntu:22.04", "env": ["CODER_AGENT_TOKEN=old"], "hostname": "dev"},
+					"after": {"image": "ubuntu:24.04", "env": ["CODER_AGENT_TOKEN=new"], "hostname": "dev"},
+					"after_unknown": {},
+					"before_sensitive": {"env": [true]},
+					"after_sensitive": {"env": [true]}
+				}
+			},
+			{
+				"address": "coder_agent.main",
+				"mode": "managed",
+				"type": "coder_agent",
+				"name": "main",
+				"change": {
+					"actions": ["create"],
+					"before": null,
+					"after": {"os": "linux"},
+					"after_unknown": {"token": true}
+				}
+			},
+			{
+				"address": "coder_metadata.info",
+				"mode": "managed",
+				"type": "coder_metadata",
+				"name": "info",
+				"change": {
+					"actions": ["no-op"],
+					"before": {"hide": false},
+					"after": {"hide": false}
+				}
+			},
+			{
+				"address": "data.coder_workspace.me",
+				"mode": "data",
+				"type": "coder_workspace",
+				"name": "me",
+				"change": {
+					"actions": ["read"],
+					"before": null,
+					"after": {}
+				}
+			}
+		]
+	}`
+	var plan tfjson.Plan
+	require.NoError(t, json.Unmarshal([]byte(rawPlan), &plan))
+
+	changes := terraform.ConvertResourceChanges(plan.ResourceChanges)
+	require.Len(t, changes, 3)
+
+	// Changes are sorted by address.
+	agent := changes[0]
+	require.Equal(t, "coder_agent.main", agent.Address)
+	require.Equal(t, "create", agent.Action)
+	require.Empty(t, agent.Attributes, "attributes are not listed for creates")
+
+	container := changes[1]
+	require.Equal(t, "docker_container.workspace[0]", container.Address)
+	require.Equal(t, "docker_container", container.Type)
+	require.Equal(t, "workspace", container.Name)
+	require.Equal(t, "update", container.Action)
+	require.Equal(t, []*proto.AttributeChange{{
+		Path:      "env",
+		Sensitive: true,
+	}, {
+		Path:   "image",
+		Before: `"ubuntu:22.04"`,
+		After:  `"ubuntu:24.04"`,
+	}}, container.Attributes)
+
+	volume := changes[2]
+	require.Equal(t, "docker_volume.home", volume.Address)
+	require.Equal(t, "replace", volume.Action)
+	require.Equal(t, []*proto.AttributeChange{{
+		Path:         "id",
+		Before:       `"abc"`,
+		AfterUnknown: true,
+	}, {
+		Path:              "name",
+		Before:            `"coder-home"`,
+		After:             `"coder-home-2"`,
+		ForcesReplacement: true,
+	}}, volume.Attributes)
+}
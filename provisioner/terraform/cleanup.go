@@ -60,6 +60,12 @@ func CleanStaleTerraformPlugins(ctx context.Context, cachePath string, fs afero.
 			return err
 		}
 
+		// Skip directories that aren't provider registry hosts, like the
+		// module cache.
+		if info.IsDir() && path != cachePath && filepath.Dir(path) == cachePath && strings.HasPrefix(info.Name(), ".") {
+			return filepath.SkipDir
+		}
+
 		if !filterFunc(path, info) {
 			return nil
 		}
@@ -34,9 +34,9 @@ type executor struct {
 	server     *server
 	mut        *sync.Mutex
 	binaryPath string
-	// cachePath and workdir must not be used by multiple processes at once.
-	cachePath string
-	workdir   string
+	cachePath  string
+	// workdir must not be used by multiple processes at once.
+	workdir string
 	// used to capture execution times at various stages
 	timings *timingAggregator
 }
@@ -49,6 +49,10 @@ func (e *executor) basicEnv() []string {
 	// cache directory. It's unknown why this is.
 	if e.cachePath != "" && runtime.GOOS == "linux" {
 		env = append(env, "TF_PLUGIN_CACHE_DIR="+e.cachePath)
+		// Terraform only uses cached providers that are recorded in the
+		// dependency lock file by default, and templates rarely include it.
+		// The lock file of a job is discarded with its workdir.
+		env = append(env, "TF_PLUGIN_CACHE_MAY_BREAK_DEPENDENCY_LOCK_FILE=true")
 	}
 	return env
 }
@@ -216,6 +220,21 @@ func (e *executor) init(ctx, killCtx context.Context, logr logSink) error {
 	e.mut.Lock()
 	defer e.mut.Unlock()
 
+	var cacheRun *cacheRun
+	if e.server.cache != nil {
+		// Other jobs, including those of other provisioner daemons, may use
+		// the cache at the same time.
+		unlock, err := e.server.cache.lock(ctx)
+		if err != nil {
+			return xerrors.Errorf("lock cache: %w", err)
+		}
+		defer unlock()
+		cacheRun, err = e.server.cache.prepare(ctx, e.workdir)
+		if err != nil {
+			return xerrors.Errorf("prepare cache: %w", err)
+		}
+	}
+
 	outWriter, doneOut := logWriter(logr, proto.LogLevel_DEBUG)
 	errWriter, doneErr := logWriter(logr, proto.LogLevel_ERROR)
 	defer func() {
@@ -242,6 +261,9 @@ func (e *executor) init(ctx, killCtx context.Context, logr logSink) error {
 			return &textFileBusyError{exitErr: exitErr, stderr: errBuf.b.String()}
 		}
 	}
+	if err == nil && cacheRun != nil {
+		cacheRun.complete(ctx)
+	}
 	return err
 }
 
@@ -308,6 +330,7 @@ func (e *executor) plan(ctx, killCtx context.Context, env, vars []string, logr l
 		Resources:             state.Resources,
 		ExternalAuthProviders: state.ExternalAuthProviders,
 		Timings:               append(e.timings.aggregate(), graphTimings.aggregate()...),
+		ResourceChanges:       state.ResourceChanges,
 	}, nil
 }
 
@@ -360,6 +383,7 @@ func (e *executor) planResources(ctx, killCtx context.Context, planfilePath stri
 	if err != nil {
 		return nil, err
 	}
+	state.ResourceChanges = ConvertResourceChanges(plan.ResourceChanges)
 	return state, nil
 }
 
@@ -461,6 +485,7 @@ func (e *executor) apply(
 		Parameters:            state.Parameters,
 		Resources:             state.Resources,
 		ExternalAuthProviders: state.ExternalAuthProviders,
+		Snapshots:             state.Snapshots,
 		State:                 stateContent,
 		Timings:               e.timings.aggregate(),
 	}, nil
@@ -12,7 +12,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/spf13/afero"
 	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
@@ -100,11 +99,6 @@ func (s *server) Plan(
 		}
 	}
 
-	err := CleanStaleTerraformPlugins(sess.Context(), s.cachePath, afero.NewOsFs(), time.Now(), s.logger)
-	if err != nil {
-		return provisionersdk.PlanErrorf("unable to clean stale Terraform plugins: %s", err)
-	}
-
 	s.logger.Debug(ctx, "running initialization")
 
 	// The JSON output of `terraform init` doesn't include discrete fields for capturing timings of each plugin,
@@ -112,7 +106,7 @@ func (s *server) Plan(
 	initTimings := newTimingAggregator(database.ProvisionerJobTimingStageInit)
 	initTimings.ingest(createInitTimingsEvent(timingInitStart))
 
-	err = e.init(ctx, killCtx, sess)
+	err := e.init(ctx, killCtx, sess)
 
 	if err != nil {
 		initTimings.ingest(createInitTimingsEvent(timingInitErrored))
@@ -245,7 +239,7 @@ func provisionEnv(
 
 	env = append(env,
 		"CODER_AGENT_URL="+metadata.GetCoderUrl(),
-		"CODER_WORKSPACE_TRANSITION="+strings.ToLower(metadata.GetWorkspaceTransition().String()),
+		"CODER_WORKSPACE_TRANSITION="+workspaceTransitionEnv(metadata.GetWorkspaceTransition()),
 		"CODER_WORKSPACE_NAME="+metadata.GetWorkspaceName(),
 		"CODER_WORKSPACE_OWNER="+metadata.GetWorkspaceOwner(),
 		"CODER_WORKSPACE_OWNER_EMAIL="+metadata.GetWorkspaceOwnerEmail(),
@@ -263,6 +257,11 @@ func provisionEnv(
 		"CODER_WORKSPACE_TEMPLATE_VERSION="+metadata.GetTemplateVersion(),
 		"CODER_WORKSPACE_BUILD_ID="+metadata.GetWorkspaceBuildId(),
 	)
+	snapshotEnv, err := workspaceSnapshotEnv(metadata)
+	if err != nil {
+		return nil, err
+	}
+	env = append(env, snapshotEnv...)
 	for key, value := range provisionersdk.AgentScriptEnv() {
 		env = append(env, key+"="+value)
 	}
@@ -282,6 +281,43 @@ func provisionEnv(
 	return env, nil
 }
 
+// workspaceTransitionEnv returns the transition exposed to templates. Snapshot
+// and restore builds are presented as stop and start builds respectively, so
+// templates keep managing their resources with start_count as usual. Templates
+// which support snapshots can tell them apart using the snapshot environment.
+func workspaceTransitionEnv(transition proto.WorkspaceTransition) string {
+	switch transition {
+	case proto.WorkspaceTransition_SNAPSHOT:
+		transition = proto.WorkspaceTransition_STOP
+	case proto.WorkspaceTransition_RESTORE:
+		transition = proto.WorkspaceTransition_START
+	}
+	return strings.ToLower(transition.String())
+}
+
+// workspaceSnapshotEnv returns the environment used by coder_workspace_snapshot
+// resources to create or restore a snapshot.
+func workspaceSnapshotEnv(metadata *proto.Metadata) ([]string, error) {
+	transition := metadata.GetWorkspaceTransition()
+	if transition != proto.WorkspaceTransition_SNAPSHOT && transition != proto.WorkspaceTransition_RESTORE {
+		return nil, nil
+	}
+	artifacts := make(map[string]string, len(metadata.GetWorkspaceSnapshotArtifacts()))
+	for _, snapshot := range metadata.GetWorkspaceSnapshotArtifacts() {
+		artifacts[snapshot.GetName()] = snapshot.GetArtifact()
+	}
+	rawArtifacts, err := json.Marshal(artifacts)
+	if err != nil {
+		return nil, xerrors.Errorf("marshal snapshot artifacts: %w", err)
+	}
+	return []string{
+		"CODER_WORKSPACE_SNAPSHOT_TRANSITION=" + strings.ToLower(transition.String()),
+		"CODER_WORKSPACE_SNAPSHOT_ID=" + metadata.GetWorkspaceSnapshotId(),
+		"CODER_WORKSPACE_SNAPSHOT_NAME=" + metadata.GetWorkspaceSnapshotName(),
+		"CODER_WORKSPACE_SNAPSHOT_ARTIFACTS=" + string(rawArtifacts),
+	}, nil
+}
+
 // tfEnvSafeToPrint is the set of terraform environment variables that we are quite sure won't contain secrets,
 // and therefore it's ok to log their values
 var tfEnvSafeToPrint = map[string]bool{
@@ -3,6 +3,7 @@ package terraform
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/awalterschulze/gographviz"
@@ -129,10 +130,17 @@ type resourceMetadataItem struct {
 	IsNull    bool   `mapstructure:"is_null"`
 }
 
+// A mapping of attributes on the "coder_workspace_snapshot" resource.
+type workspaceSnapshotAttributes struct {
+	Artifact string `mapstructure:"artifact"`
+}
+
 type State struct {
 	Resources             []*proto.Resource
 	Parameters            []*proto.RichParameter
 	ExternalAuthProviders []*proto.ExternalAuthProviderResource
+	Snapshots             []*proto.Snapshot
+	ResourceChanges       []*proto.ResourceChange
 }
 
 var ErrInvalidTerraformAddr = xerrors.New("invalid terraform address")
@@ -606,7 +614,7 @@ func ConvertState(ctx context.Context, modules []*tfjson.StateModule, rawGraph s
 			if resource.Mode == tfjson.DataResourceMode {
 				continue
 			}
-			if resource.Type == "coder_script" || resource.Type == "coder_agent" || resource.Type == "coder_agent_instance" || resource.Type == "coder_app" || resource.Type == "coder_metadata" {
+			if resource.Type == "coder_script" || resource.Type == "coder_agent" || resource.Type == "coder_agent_instance" || resource.Type == "coder_app" || resource.Type == "coder_metadata" || resource.Type == "coder_workspace_snapshot" {
 				continue
 			}
 			label := convertAddressToLabel(resource.Address)
@@ -764,10 +772,40 @@ func ConvertState(ctx context.Context, modules []*tfjson.StateModule, rawGraph s
 		externalAuthProviders = append(externalAuthProviders, it)
 	}
 
+	// Templates opt in to workspace snapshots by declaring
+	// coder_workspace_snapshot resources, whose artifacts are stored by
+	// coderd and passed back to the template when the snapshot is restored.
+	snapshots := make([]*proto.Snapshot, 0)
+	for _, tfResources := range tfResourcesByLabel {
+		for _, resource := range tfResources {
+			if resource.Mode != tfjson.ManagedResourceMode || resource.Type != "coder_workspace_snapshot" {
+				continue
+			}
+			var attrs workspaceSnapshotAttributes
+			err = mapstructure.Decode(resource.AttributeValues, &attrs)
+			if err != nil {
+				return nil, xerrors.Errorf("decode workspace snapshot attributes: %w", err)
+			}
+			if slice.ContainsCompare(snapshots, &proto.Snapshot{Name: resource.Name}, func(a, b *proto.Snapshot) bool {
+				return a.Name == b.Name
+			}) {
+				return nil, xerrors.Errorf("coder_workspace_snapshot names must be unique but %q appears multiple times", resource.Name)
+			}
+			snapshots = append(snapshots, &proto.Snapshot{
+				Name:     resource.Name,
+				Artifact: attrs.Artifact,
+			})
+		}
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Name < snapshots[j].Name
+	})
+
 	return &State{
 		Resources:             resources,
 		Parameters:            parameters,
 		ExternalAuthProviders: externalAuthProviders,
+		Snapshots:             snapshots,
 	}, nil
 }
 
@@ -1128,3 +1128,63 @@ func sortExternalAuthProviders(providers []*proto.ExternalAuthProviderResource)
 		return strings.Compare(providers[i].Id, providers[j].Id) == -1
 	})
 }
+
+func TestWorkspaceSnapshots(t *testing.T) {
+	t.Parallel()
+
+	snapshot := func(module, name, artifact string) *tfjson.StateResource {
+		address := "coder_workspace_snapshot." + name
+		if module != "" {
+			address = module + "." + address
+		}
+		return &tfjson.StateResource{
+			Address: address,
+			Type:    "coder_workspace_snapshot",
+			Name:    name,
+			Mode:    tfjson.ManagedResourceMode,
+			AttributeValues: map[string]interface{}{
+				"artifact": artifact,
+			},
+		}
+	}
+	graph := `digraph {
+	compound = "true"
+	newrank = "true"
+	subgraph "root" {
+	}
+}`
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		ctx, logger := ctxAndLogger(t)
+
+		state, err := terraform.ConvertState(ctx, []*tfjson.StateModule{{
+			Resources: []*tfjson.StateResource{
+				snapshot("", "home", "snap-home"),
+				snapshot("", "data", "snap-data"),
+			},
+		}}, graph, logger)
+		require.NoError(t, err)
+		// Snapshots are not displayed as workspace resources.
+		require.Empty(t, state.Resources)
+		require.Len(t, state.Snapshots, 2)
+		require.Equal(t, "data", state.Snapshots[0].Name)
+		require.Equal(t, "snap-data", state.Snapshots[0].Artifact)
+		require.Equal(t, "home", state.Snapshots[1].Name)
+		require.Equal(t, "snap-home", state.Snapshots[1].Artifact)
+	})
+
+	t.Run("Duplicate", func(t *testing.T) {
+		t.Parallel()
+		ctx, logger := ctxAndLogger(t)
+
+		_, err := terraform.ConvertState(ctx, []*tfjson.StateModule{{
+			Resources: []*tfjson.StateResource{snapshot("", "home", "a")},
+			ChildModules: []*tfjson.StateModule{{
+				Address:   "module.other",
+				Resources: []*tfjson.StateResource{snapshot("module.other", "home", "b")},
+			}},
+		}}, graph, logger)
+		require.ErrorContains(t, err, "coder_workspace_snapshot names must be unique")
+	})
+}
@@ -24,9 +24,16 @@ type ServeOptions struct {
 	// BinaryPath specifies the "terraform" binary to use.
 	// If omitted, the $PATH will attempt to find it.
 	BinaryPath string
-	// CachePath must not be used by multiple processes at once.
+	// CachePath is the directory the Terraform binary, providers and modules
+	// are cached in. It may be shared by several provisioner daemons.
 	CachePath string
-	Tracer    trace.Tracer
+	// CacheMaxSize is the size in bytes above which the least recently used
+	// providers and modules are evicted from the cache. Zero disables
+	// eviction.
+	CacheMaxSize int64
+	// CacheMetrics are optional.
+	CacheMetrics *CacheMetrics
+	Tracer       trace.Tracer
 
 	// ExitTimeout defines how long we will wait for a running Terraform
 	// command to exit (cleanly) if the provision was stopped. This
@@ -85,29 +92,38 @@ func absoluteBinaryPath(ctx context.Context, logger slog.Logger) (string, error)
 	return absoluteBinary, nil
 }
 
+// findOrInstallBinary returns the "terraform" binary in the $PATH, or installs
+// it into cachePath if it's missing or too old.
+func findOrInstallBinary(ctx context.Context, logger slog.Logger, cachePath string) (string, error) {
+	absoluteBinary, err := absoluteBinaryPath(ctx, logger)
+	if err == nil {
+		return absoluteBinary, nil
+	}
+	// This is an early exit to prevent extra execution in case the context is canceled.
+	// It generally happens in unit tests since this method is asynchronous and
+	// the unit test kills the app before this is complete.
+	if xerrors.Is(err, context.Canceled) {
+		return "", xerrors.Errorf("absolute binary context canceled: %w", err)
+	}
+
+	logger.Warn(ctx, "no usable terraform binary found, downloading to cache dir",
+		slog.F("terraform_version", TerraformVersion.String()),
+		slog.F("cache_dir", cachePath))
+	binPath, err := Install(ctx, logger, cachePath, TerraformVersion)
+	if err != nil {
+		return "", xerrors.Errorf("install terraform: %w", err)
+	}
+	return binPath, nil
+}
+
 // Serve starts a dRPC server on the provided transport speaking Terraform provisioner.
 func Serve(ctx context.Context, options *ServeOptions) error {
 	if options.BinaryPath == "" {
-		absoluteBinary, err := absoluteBinaryPath(ctx, options.Logger)
+		binaryPath, err := findOrInstallBinary(ctx, options.Logger, options.CachePath)
 		if err != nil {
-			// This is an early exit to prevent extra execution in case the context is canceled.
-			// It generally happens in unit tests since this method is asynchronous and
-			// the unit test kills the app before this is complete.
-			if xerrors.Is(err, context.Canceled) {
-				return xerrors.Errorf("absolute binary context canceled: %w", err)
-			}
-
-			options.Logger.Warn(ctx, "no usable terraform binary found, downloading to cache dir",
-				slog.F("terraform_version", TerraformVersion.String()),
-				slog.F("cache_dir", options.CachePath))
-			binPath, err := Install(ctx, options.Logger, options.CachePath, TerraformVersion)
-			if err != nil {
-				return xerrors.Errorf("install terraform: %w", err)
-			}
-			options.BinaryPath = binPath
-		} else {
-			options.BinaryPath = absoluteBinary
+			return err
 		}
+		options.BinaryPath = binaryPath
 	}
 	if options.Tracer == nil {
 		options.Tracer = trace.NewNoopTracerProvider().Tracer("noop")
@@ -119,6 +135,7 @@ func Serve(ctx context.Context, options *ServeOptions) error {
 		execMut:     &sync.Mutex{},
 		binaryPath:  options.BinaryPath,
 		cachePath:   options.CachePath,
+		cache:       newCache(options.CachePath, options.CacheMaxSize, options.CacheMetrics, options.Logger),
 		logger:      options.Logger,
 		tracer:      options.Tracer,
 		exitTimeout: options.ExitTimeout,
@@ -129,6 +146,7 @@ type server struct {
 	execMut     *sync.Mutex
 	binaryPath  string
 	cachePath   string
+	cache       *cache
 	logger      slog.Logger
 	tracer      trace.Tracer
 	exitTimeout time.Duration
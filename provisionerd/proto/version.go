@@ -6,9 +6,17 @@ import "github.com/coder/coder/v2/apiversion"
 //
 // API v1.2:
 //   - Add support for `open_in` parameters in the workspace apps.
+//
+// API v1.3:
+//   - Add `snapshot` and `restore` workspace transitions, and support for
+//     reporting and restoring workspace snapshot artifacts.
+//
+// API v1.4:
+//   - Add a summary of resource changes to plan responses and completed jobs.
+//   - Add workspace state to template dry runs, for previewing updates.
 const (
 	CurrentMajor = 1
-	CurrentMinor = 2
+	CurrentMinor = 4
 )
 
 // CurrentVersion is the current provisionerd API version.
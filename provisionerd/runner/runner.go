@@ -650,6 +650,7 @@ type templateImportProvision struct {
 	Parameters            []*sdkproto.RichParameter
 	ExternalAuthProviders []*sdkproto.ExternalAuthProviderResource
 	Modules               []*sdkproto.Module
+	ResourceChanges       []*sdkproto.ResourceChange
 }
 
 // Performs a dry-run provision when importing a template.
@@ -742,6 +743,7 @@ func (r *Runner) runTemplateImportProvisionWithRichParameters(
 				Parameters:            c.Parameters,
 				ExternalAuthProviders: c.ExternalAuthProviders,
 				Modules:               c.Modules,
+				ResourceChanges:       c.ResourceChanges,
 			}, nil
 		default:
 			return nil, xerrors.Errorf("invalid message type %q received from provisioner",
@@ -763,7 +765,9 @@ func (r *Runner) runTemplateDryRun(ctx context.Context) (*proto.CompletedJob, *p
 	if metadata.WorkspaceName == "" {
 		metadata.WorkspaceName = "dryrun"
 	}
-	metadata.WorkspaceOwner = r.job.UserName
+	if metadata.WorkspaceOwner == "" {
+		metadata.WorkspaceOwner = r.job.UserName
+	}
 	if metadata.WorkspaceOwner == "" {
 		metadata.WorkspaceOwner = "dryrunner"
 	}
@@ -782,8 +786,10 @@ func (r *Runner) runTemplateDryRun(ctx context.Context) (*proto.CompletedJob, *p
 		metadata.WorkspaceOwnerId = id.String()
 	}
 
+	// State is only set when previewing an update to an existing workspace.
 	failedJob := r.configure(&sdkproto.Config{
 		TemplateSourceArchive: r.job.GetTemplateSourceArchive(),
+		State:                 r.job.GetTemplateDryRun().GetState(),
 	})
 	if failedJob != nil {
 		return nil, failedJob
@@ -803,8 +809,9 @@ func (r *Runner) runTemplateDryRun(ctx context.Context) (*proto.CompletedJob, *p
 		JobId: r.job.JobId,
 		Type: &proto.CompletedJob_TemplateDryRun_{
 			TemplateDryRun: &proto.CompletedJob_TemplateDryRun{
-				Resources: provision.Resources,
-				Modules:   provision.Modules,
+				Resources:       provision.Resources,
+				Modules:         provision.Modules,
+				ResourceChanges: provision.ResourceChanges,
 			},
 		},
 	}, nil
@@ -933,6 +940,12 @@ func (r *Runner) runWorkspaceBuild(ctx context.Context) (*proto.CompletedJob, *p
 		commitQuota = true
 	case sdkproto.WorkspaceTransition_DESTROY:
 		applyStage = "Destroying workspace"
+	case sdkproto.WorkspaceTransition_SNAPSHOT:
+		applyStage = "Snapshotting workspace"
+		commitQuota = true
+	case sdkproto.WorkspaceTransition_RESTORE:
+		applyStage = "Restoring workspace"
+		commitQuota = true
 	}
 
 	failedJob := r.configure(&sdkproto.Config{
@@ -1048,7 +1061,9 @@ func (r *Runner) runWorkspaceBuild(ctx context.Context) (*proto.CompletedJob, *p
 				// Modules are created on disk by `terraform init`, and that is only
 				// called by `plan`. `apply` does not modify them, so we can use the
 				// modules from the plan response.
-				Modules: planComplete.Modules,
+				Modules:         planComplete.Modules,
+				Snapshots:       applyComplete.Snapshots,
+				ResourceChanges: planComplete.ResourceChanges,
 			},
 		},
 	}, nil
@@ -35,6 +35,8 @@ export enum WorkspaceTransition {
   START = 0,
   STOP = 1,
   DESTROY = 2,
+  SNAPSHOT = 3,
+  RESTORE = 4,
   UNRECOGNIZED = -1,
 }
 
@@ -261,6 +263,9 @@ export interface Metadata {
   workspaceOwnerSshPrivateKey: string;
   workspaceBuildId: string;
   workspaceOwnerLoginType: string;
+  workspaceSnapshotId: string;
+  workspaceSnapshotName: string;
+  workspaceSnapshotArtifacts: Snapshot[];
 }
 
 /** Config represents execution configuration shared by all subsequent requests in the Session */
@@ -305,6 +310,34 @@ export interface PlanComplete {
   externalAuthProviders: ExternalAuthProviderResource[];
   timings: Timing[];
   modules: Module[];
+  resourceChanges: ResourceChange[];
+}
+
+/**
+ * ResourceChange describes what applying a plan will do to a single managed
+ * resource.  Resources that will not change are omitted.
+ */
+export interface ResourceChange {
+  address: string;
+  type: string;
+  name: string;
+  /** action is one of "create", "update", "replace" or "delete". */
+  action: string;
+  attributes: AttributeChange[];
+}
+
+/**
+ * AttributeChange describes a single attribute that differs between the
+ * current and planned state of a resource.  Values are JSON encoded, and are
+ * never set for sensitive attributes.
+ */
+export interface AttributeChange {
+  path: string;
+  before: string;
+  after: string;
+  sensitive: boolean;
+  afterUnknown: boolean;
+  forcesReplacement: boolean;
 }
 
 /**
@@ -323,6 +356,17 @@ export interface ApplyComplete {
   parameters: RichParameter[];
   externalAuthProviders: ExternalAuthProviderResource[];
   timings: Timing[];
+  snapshots: Snapshot[];
+}
+
+/**
+ * Snapshot is an artifact produced by a coder_workspace_snapshot resource,
+ * e.g. the ID of a cloud volume snapshot, which can later be used to restore
+ * the data into a workspace.
+ */
+export interface Snapshot {
+  name: string;
+  artifact: string;
 }
 
 export interface Timing {
@@ -866,6 +910,15 @@ export const Metadata = {
     if (message.workspaceOwnerLoginType !== "") {
       writer.uint32(146).string(message.workspaceOwnerLoginType);
     }
+    if (message.workspaceSnapshotId !== "") {
+      writer.uint32(154).string(message.workspaceSnapshotId);
+    }
+    if (message.workspaceSnapshotName !== "") {
+      writer.uint32(162).string(message.workspaceSnapshotName);
+    }
+    for (const v of message.workspaceSnapshotArtifacts) {
+      Snapshot.encode(v!, writer.uint32(170).fork()).ldelim();
+    }
     return writer;
   },
 };
@@ -959,6 +1012,54 @@ export const PlanComplete = {
     for (const v of message.modules) {
       Module.encode(v!, writer.uint32(58).fork()).ldelim();
     }
+    for (const v of message.resourceChanges) {
+      ResourceChange.encode(v!, writer.uint32(66).fork()).ldelim();
+    }
+    return writer;
+  },
+};
+
+export const ResourceChange = {
+  encode(message: ResourceChange, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    if (message.address !== "") {
+      writer.uint32(10).string(message.address);
+    }
+    if (message.type !== "") {
+      writer.uint32(18).string(message.type);
+    }
+    if (message.name !== "") {
+      writer.uint32(26).string(message.name);
+    }
+    if (message.action !== "") {
+      writer.uint32(34).string(message.action);
+    }
+    for (const v of message.attributes) {
+      AttributeChange.encode(v!, writer.uint32(42).fork()).ldelim();
+    }
+    return writer;
+  },
+};
+
+export const AttributeChange = {
+  encode(message: AttributeChange, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    if (message.path !== "") {
+      writer.uint32(10).string(message.path);
+    }
+    if (message.before !== "") {
+      writer.uint32(18).string(message.before);
+    }
+    if (message.after !== "") {
+      writer.uint32(26).string(message.after);
+    }
+    if (message.sensitive === true) {
+      writer.uint32(32).bool(message.sensitive);
+    }
+    if (message.afterUnknown === true) {
+      writer.uint32(40).bool(message.afterUnknown);
+    }
+    if (message.forcesReplacement === true) {
+      writer.uint32(48).bool(message.forcesReplacement);
+    }
     return writer;
   },
 };
@@ -992,6 +1093,21 @@ export const ApplyComplete = {
     for (const v of message.timings) {
       Timing.encode(v!, writer.uint32(50).fork()).ldelim();
     }
+    for (const v of message.snapshots) {
+      Snapshot.encode(v!, writer.uint32(58).fork()).ldelim();
+    }
+    return writer;
+  },
+};
+
+export const Snapshot = {
+  encode(message: Snapshot, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    if (message.name !== "") {
+      writer.uint32(10).string(message.name);
+    }
+    if (message.artifact !== "") {
+      writer.uint32(18).string(message.artifact);
+    }
     return writer;
   },
 };
@@ -985,6 +985,12 @@ type RenderOAuthAllowData struct {
 	CancelURI   string
 	RedirectURI string
 	Username    string
+	// UserCode is shown when authorizing a device, so the user can check it
+	// matches the code displayed on the device.
+	UserCode string
+	// DeviceCodeForm renders a form for entering a device's user code instead
+	// of the allow and cancel buttons. The form submits to RedirectURI.
+	DeviceCodeForm bool
 }
 
 // RenderOAuthAllowPage renders the static page for a user to "Allow" an create
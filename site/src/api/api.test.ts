@@ -179,7 +179,7 @@ describe("api.ts", () => {
 			await API.updateWorkspace(MockWorkspace);
 			expect(API.postWorkspaceBuild).toHaveBeenCalledWith(MockWorkspace.id, {
 				transition: "start",
-				template_version_id: MockTemplate.active_version_id,
+				active_version: true,
 				rich_parameter_values: [],
 			});
 		});
@@ -228,7 +228,7 @@ describe("api.ts", () => {
 			await API.updateWorkspace(MockWorkspace);
 			expect(API.postWorkspaceBuild).toHaveBeenCalledWith(MockWorkspace.id, {
 				transition: "start",
-				template_version_id: MockTemplate.active_version_id,
+				active_version: true,
 				rich_parameter_values: [],
 			});
 		});
@@ -2029,7 +2029,7 @@ class ApiMethods {
 
 		return this.postWorkspaceBuild(workspace.id, {
 			transition: "start",
-			template_version_id: activeVersionId,
+			active_version: true,
 			rich_parameter_values: newBuildParameters,
 		});
 	};
@@ -18,12 +18,25 @@ export interface APIKey {
 	readonly scope: APIKeyScope;
 	readonly token_name: string;
 	readonly lifetime_seconds: number;
+	readonly scope_permissions?: readonly APIKeyScopePermission[];
+	readonly scope_allow_list?: readonly string[];
 }
 
 // From codersdk/apikey.go
-export type APIKeyScope = "all" | "application_connect";
+export type APIKeyScope = "all" | "application_connect" | "custom";
 
-export const APIKeyScopes: APIKeyScope[] = ["all", "application_connect"];
+export const APIKeyScopes: APIKeyScope[] = [
+	"all",
+	"application_connect",
+	"custom",
+];
+
+// From codersdk/apikey.go
+export interface APIKeyScopePermission {
+	readonly resource_type: RBACResource;
+	readonly action: RBACAction;
+	readonly organization_id?: string;
+}
 
 // From codersdk/apikey.go
 export interface APIKeyWithOwner extends APIKey {
@@ -119,6 +132,7 @@ export type AuditAction =
 	| "delete"
 	| "login"
 	| "logout"
+	| "open"
 	| "register"
 	| "request_password_reset"
 	| "start"
@@ -130,6 +144,7 @@ export const AuditActions: AuditAction[] = [
 	"delete",
 	"login",
 	"logout",
+	"open",
 	"register",
 	"request_password_reset",
 	"start",
@@ -172,12 +187,42 @@ export interface AuditLog {
 	readonly user: User | null;
 }
 
+// From codersdk/deployment.go
+export interface AuditLogFileConfig {
+	readonly path: string;
+	readonly max_size: number;
+	readonly max_backups: number;
+}
+
+// From codersdk/deployment.go
+export interface AuditLogHTTPConfig {
+	readonly url: string;
+	readonly headers: string;
+	readonly format: string;
+	readonly batch_size: number;
+	readonly flush_interval: number;
+	readonly spool_dir: string;
+}
+
 // From codersdk/audit.go
 export interface AuditLogResponse {
 	readonly audit_logs: readonly AuditLog[];
 	readonly count: number;
 }
 
+// From codersdk/deployment.go
+export interface AuditLogStreamingConfig {
+	readonly syslog: AuditLogSyslogConfig;
+	readonly http: AuditLogHTTPConfig;
+	readonly file: AuditLogFileConfig;
+}
+
+// From codersdk/deployment.go
+export interface AuditLogSyslogConfig {
+	readonly address: string;
+	readonly tls: boolean;
+}
+
 // From codersdk/audit.go
 export interface AuditLogsRequest extends Pagination {
 	readonly q?: string;
@@ -258,12 +303,13 @@ export interface BuildInfoResponse {
 }
 
 // From codersdk/workspacebuilds.go
-export type BuildReason = "autostart" | "autostop" | "initiator";
+export type BuildReason = "autostart" | "autostop" | "initiator" | "scheduled";
 
 export const BuildReasons: BuildReason[] = [
 	"autostart",
 	"autostop",
 	"initiator",
+	"scheduled",
 ];
 
 // From codersdk/client.go
@@ -300,6 +346,66 @@ export interface ConvertLoginRequest {
 	readonly password: string;
 }
 
+// From codersdk/insights.go
+export interface CostBreakdown {
+	readonly id: string;
+	readonly name: string;
+	readonly cost: number;
+}
+
+// From codersdk/costbudgets.go
+export interface CostBudget {
+	readonly group_id: string;
+	readonly organization_id: string;
+	readonly created_at: string;
+	readonly updated_at: string;
+	readonly monthly_limit: number;
+	readonly notify_threshold_percent: number;
+	readonly stop_threshold_percent: number;
+}
+
+// From codersdk/insights.go
+export type CostInsightsGroupBy = "group" | "template" | "user";
+
+export const CostInsightsGroupBys: CostInsightsGroupBy[] = [
+	"group",
+	"template",
+	"user",
+];
+
+// From codersdk/insights.go
+export interface CostInsightsIntervalReport {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly interval: InsightsReportInterval;
+	readonly cost: number;
+}
+
+// From codersdk/insights.go
+export interface CostInsightsReport {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly template_ids: readonly string[];
+	readonly group_by: CostInsightsGroupBy;
+	readonly total_cost: number;
+	readonly breakdown: readonly CostBreakdown[];
+}
+
+// From codersdk/insights.go
+export interface CostInsightsRequest {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly template_ids: readonly string[];
+	readonly interval: InsightsReportInterval;
+	readonly group_by: CostInsightsGroupBy;
+}
+
+// From codersdk/insights.go
+export interface CostInsightsResponse {
+	readonly report: CostInsightsReport;
+	readonly interval_reports: readonly CostInsightsIntervalReport[];
+}
+
 // From codersdk/users.go
 export interface CreateFirstUserRequest {
 	readonly email: string;
@@ -354,6 +460,15 @@ export interface CreateProvisionerKeyResponse {
 	readonly key: string;
 }
 
+// From codersdk/secrets.go
+export interface CreateSecretRequest {
+	readonly name: string;
+	readonly description?: string;
+	readonly value: string;
+	readonly env_name?: string;
+	readonly file_path?: string;
+}
+
 // From codersdk/organizations.go
 export interface CreateTemplateRequest {
 	readonly name: string;
@@ -376,11 +491,22 @@ export interface CreateTemplateRequest {
 	readonly max_port_share_level: WorkspaceAgentPortShareLevel | null;
 }
 
+// From codersdk/templaterollouts.go
+export interface CreateTemplateRolloutRequest {
+	readonly template_version_id: string;
+	readonly percent: number;
+	readonly group_id?: string;
+	readonly min_builds: number;
+	readonly max_failure_rate: number;
+	readonly auto_promote: boolean;
+}
+
 // From codersdk/templateversions.go
 export interface CreateTemplateVersionDryRunRequest {
 	readonly workspace_name: string;
 	readonly rich_parameter_values: readonly WorkspaceBuildParameter[];
 	readonly user_variable_values?: readonly VariableValue[];
+	readonly workspace_id?: string;
 }
 
 // From codersdk/organizations.go
@@ -412,6 +538,8 @@ export interface CreateTokenRequest {
 	readonly lifetime: number;
 	readonly scope: APIKeyScope;
 	readonly token_name: string;
+	readonly scope_permissions?: readonly APIKeyScopePermission[];
+	readonly scope_allow_list?: readonly string[];
 }
 
 // From codersdk/users.go
@@ -429,6 +557,7 @@ export interface CreateUserRequestWithOrgs {
 export interface CreateWorkspaceBuildRequest {
 	readonly template_version_id?: string;
 	readonly transition: WorkspaceTransition;
+	readonly active_version?: boolean;
 	readonly dry_run?: boolean;
 	readonly state?: readonly string[];
 	readonly orphan?: boolean;
@@ -454,6 +583,17 @@ export interface CreateWorkspaceRequest {
 	readonly automatic_updates?: AutomaticUpdates;
 }
 
+// From codersdk/workspacescheduledactions.go
+export interface CreateWorkspaceScheduledActionRequest {
+	readonly action: WorkspaceScheduledActionType;
+	readonly schedule: string;
+}
+
+// From codersdk/workspacesnapshots.go
+export interface CreateWorkspaceSnapshotRequest {
+	readonly name: string;
+}
+
 // From codersdk/deployment.go
 export interface CryptoKey {
 	readonly feature: CryptoKeyFeature;
@@ -467,12 +607,14 @@ export interface CryptoKey {
 export type CryptoKeyFeature =
 	| "oidc_convert"
 	| "tailnet_resume"
+	| "workload_identity"
 	| "workspace_apps_api_key"
 	| "workspace_apps_token";
 
 export const CryptoKeyFeatures: CryptoKeyFeature[] = [
 	"oidc_convert",
 	"tailnet_resume",
+	"workload_identity",
 	"workspace_apps_api_key",
 	"workspace_apps_token",
 ];
@@ -625,6 +767,7 @@ export interface DeploymentValues {
 	readonly in_memory_database?: boolean;
 	readonly pg_connection_url?: string;
 	readonly pg_auth?: string;
+	readonly pubsub?: PubsubConfig;
 	readonly oauth2?: OAuth2Config;
 	readonly oidc?: OIDCConfig;
 	readonly telemetry?: TelemetryConfig;
@@ -640,6 +783,7 @@ export interface DeploymentValues {
 	readonly browser_only?: boolean;
 	readonly scim_api_key?: string;
 	readonly external_token_encryption_keys?: string;
+	readonly external_token_encryption?: ExternalTokenEncryptionConfig;
 	readonly provisioner?: ProvisionerConfig;
 	readonly rate_limit?: RateLimitConfig;
 	readonly experiments?: string;
@@ -665,6 +809,8 @@ export interface DeploymentValues {
 	readonly terms_of_service_url?: string;
 	readonly notifications?: NotificationsConfig;
 	readonly additional_csp_policy?: string;
+	readonly audit_log_streaming?: AuditLogStreamingConfig;
+	readonly retention?: RetentionConfig;
 	readonly config?: string;
 	readonly write_config?: boolean;
 	readonly address?: string;
@@ -819,6 +965,24 @@ export interface ExternalAuthUser {
 	readonly name: string;
 }
 
+// From codersdk/deployment.go
+export interface ExternalTokenEncryptionConfig {
+	readonly key_provider: string;
+	readonly vault_address: string;
+	readonly vault_token_file: string;
+	readonly vault_namespace: string;
+	readonly vault_transit_mount: string;
+	readonly vault_transit_key: string;
+	readonly key_command: string;
+	readonly rotation_interval: number;
+}
+
+// From codersdk/deployment.go
+export type ExternalTokenEncryptionKeyProvider = "command" | "vault-transit";
+
+export const ExternalTokenEncryptionKeyProviders: ExternalTokenEncryptionKeyProvider[] =
+	["command", "vault-transit"];
+
 // From codersdk/deployment.go
 export interface Feature {
 	readonly entitlement: Entitlement;
@@ -929,9 +1093,9 @@ export interface GroupArguments {
 }
 
 // From codersdk/groups.go
-export type GroupSource = "oidc" | "user";
+export type GroupSource = "oidc" | "scim" | "user";
 
-export const GroupSources: GroupSource[] = ["oidc", "user"];
+export const GroupSources: GroupSource[] = ["oidc", "scim", "user"];
 
 // From codersdk/idpsync.go
 export interface GroupSyncSettings {
@@ -1205,6 +1369,41 @@ export interface NetcheckReport {
 	readonly CaptivePortal: boolean | null;
 }
 
+// From codersdk/insights.go
+export type NetworkClientType = "agent" | "cli" | "coderd" | "wsproxy";
+
+export const NetworkClientTypes: NetworkClientType[] = [
+	"agent",
+	"cli",
+	"coderd",
+	"wsproxy",
+];
+
+// From codersdk/insights.go
+export interface NetworkInsightsRegion {
+	readonly region_id: number;
+	readonly region_code: string;
+	readonly region_name: string;
+	readonly clients: number;
+	readonly preferred_clients: number;
+	readonly latency_p50_ms: number;
+	readonly latency_p95_ms: number;
+	readonly direct_connections: number;
+	readonly relayed_connections: number;
+}
+
+// From codersdk/insights.go
+export interface NetworkInsightsRequest {
+	readonly client_types: readonly NetworkClientType[];
+}
+
+// From codersdk/insights.go
+export interface NetworkInsightsResponse {
+	readonly reported_after: string;
+	readonly client_types: readonly NetworkClientType[];
+	readonly regions: readonly NetworkInsightsRegion[];
+}
+
 // From codersdk/notifications.go
 export interface NotificationMethodsResponse {
 	readonly available: readonly string[];
@@ -1215,6 +1414,7 @@ export interface NotificationMethodsResponse {
 export interface NotificationPreference {
 	readonly id: string;
 	readonly disabled: boolean;
+	readonly method?: string;
 	readonly updated_at: string;
 }
 
@@ -1244,6 +1444,8 @@ export interface NotificationsConfig {
 	readonly dispatch_timeout: number;
 	readonly email: NotificationsEmailConfig;
 	readonly webhook: NotificationsWebhookConfig;
+	readonly slack: NotificationsSlackConfig;
+	readonly teams: NotificationsTeamsConfig;
 }
 
 // From codersdk/deployment.go
@@ -1279,6 +1481,18 @@ export interface NotificationsSettings {
 	readonly notifier_paused: boolean;
 }
 
+// From codersdk/deployment.go
+export interface NotificationsSlackConfig {
+	readonly webhook_url: string;
+	readonly bot_token: string;
+	readonly api_url: string;
+}
+
+// From codersdk/deployment.go
+export interface NotificationsTeamsConfig {
+	readonly webhook_url: string;
+}
+
 // From codersdk/deployment.go
 export interface NotificationsWebhookConfig {
 	readonly endpoint: string;
@@ -1291,11 +1505,33 @@ export interface OAuth2AppEndpoints {
 	readonly device_authorization: string;
 }
 
+// From codersdk/oauth2.go
+export interface OAuth2AuthorizationServerMetadata {
+	readonly issuer: string;
+	readonly authorization_endpoint: string;
+	readonly token_endpoint: string;
+	readonly device_authorization_endpoint: string;
+	readonly response_types_supported: readonly OAuth2ProviderResponseType[];
+	readonly grant_types_supported: readonly OAuth2ProviderGrantType[];
+	readonly code_challenge_methods_supported: readonly OAuth2PKCECodeChallengeMethod[];
+	readonly token_endpoint_auth_methods_supported: readonly string[];
+}
+
 // From codersdk/deployment.go
 export interface OAuth2Config {
 	readonly github: OAuth2GithubConfig;
 }
 
+// From codersdk/oauth2.go
+export interface OAuth2DeviceAuthorizationResponse {
+	readonly device_code: string;
+	readonly user_code: string;
+	readonly verification_uri: string;
+	readonly verification_uri_complete: string;
+	readonly expires_in: number;
+	readonly interval: number;
+}
+
 // From codersdk/deployment.go
 export interface OAuth2GithubConfig {
 	readonly client_id: string;
@@ -1307,12 +1543,21 @@ export interface OAuth2GithubConfig {
 	readonly enterprise_base_url: string;
 }
 
+// From codersdk/oauth2.go
+export type OAuth2PKCECodeChallengeMethod = "S256";
+
+export const OAuth2PKCECodeChallengeMethods: OAuth2PKCECodeChallengeMethod[] = [
+	"S256",
+];
+
 // From codersdk/oauth2.go
 export interface OAuth2ProviderApp {
 	readonly id: string;
 	readonly name: string;
 	readonly callback_url: string;
 	readonly icon: string;
+	readonly public_client: boolean;
+	readonly client_credentials_user_id?: string;
 	readonly endpoints: OAuth2AppEndpoints;
 }
 
@@ -1335,11 +1580,17 @@ export interface OAuth2ProviderAppSecretFull {
 }
 
 // From codersdk/oauth2.go
-export type OAuth2ProviderGrantType = "authorization_code" | "refresh_token";
+export type OAuth2ProviderGrantType =
+	| "authorization_code"
+	| "client_credentials"
+	| "refresh_token"
+	| "urn:ietf:params:oauth:grant-type:device_code";
 
 export const OAuth2ProviderGrantTypes: OAuth2ProviderGrantType[] = [
 	"authorization_code",
+	"client_credentials",
 	"refresh_token",
+	"urn:ietf:params:oauth:grant-type:device_code",
 ];
 
 // From codersdk/oauth2.go
@@ -1482,6 +1733,8 @@ export interface PostOAuth2ProviderAppRequest {
 	readonly name: string;
 	readonly callback_url: string;
 	readonly icon: string;
+	readonly public_client?: boolean;
+	readonly client_credentials_user_id?: string;
 }
 
 // From codersdk/workspaces.go
@@ -1518,6 +1771,9 @@ export interface ProvisionerConfig {
 	readonly daemon_poll_jitter: number;
 	readonly force_cancel_interval: number;
 	readonly daemon_psk: string;
+	readonly max_jobs_per_organization: number;
+	readonly max_jobs_per_user: number;
+	readonly terraform_cache_max_size: number;
 }
 
 // From codersdk/provisionerdaemons.go
@@ -1597,6 +1853,36 @@ export interface ProvisionerJobLog {
 	readonly output: string;
 }
 
+// From codersdk/provisionerdaemons.go
+export interface ProvisionerJobResourceAttributeChange {
+	readonly path: string;
+	readonly before?: string;
+	readonly after?: string;
+	readonly sensitive: boolean;
+	readonly after_unknown: boolean;
+	readonly forces_replacement: boolean;
+}
+
+// From codersdk/provisionerdaemons.go
+export interface ProvisionerJobResourceChange {
+	readonly job_id: string;
+	readonly address: string;
+	readonly resource_type: string;
+	readonly resource_name: string;
+	readonly action: ProvisionerJobResourceChangeAction;
+	readonly attributes: readonly ProvisionerJobResourceAttributeChange[];
+}
+
+// From codersdk/provisionerdaemons.go
+export type ProvisionerJobResourceChangeAction =
+	| "create"
+	| "delete"
+	| "replace"
+	| "update";
+
+export const ProvisionerJobResourceChangeActions: ProvisionerJobResourceChangeAction[] =
+	["create", "delete", "replace", "update"];
+
 // From codersdk/provisionerdaemons.go
 export type ProvisionerJobStatus =
 	| "canceled"
@@ -1699,6 +1985,32 @@ export const ProxyHealthStatuses: ProxyHealthStatus[] = [
 	"unregistered",
 ];
 
+// From codersdk/deployment.go
+export type PubsubBackend = "nats" | "postgres";
+
+export const PubsubBackends: PubsubBackend[] = ["nats", "postgres"];
+
+// From codersdk/deployment.go
+export interface PubsubConfig {
+	readonly backend: string;
+	readonly nats_url: string;
+	readonly nats_subject_prefix: string;
+}
+
+// From codersdk/deployment.go
+export interface PurgeReport {
+	readonly generated_at: string;
+	readonly tables: readonly PurgeReportTable[];
+}
+
+// From codersdk/deployment.go
+export interface PurgeReportTable {
+	readonly name: string;
+	readonly enabled: boolean;
+	readonly cutoff?: string;
+	readonly rows: number;
+}
+
 // From codersdk/workspaces.go
 export interface PutExtendWorkspaceRequest {
 	readonly deadline: string;
@@ -1709,6 +2021,8 @@ export interface PutOAuth2ProviderAppRequest {
 	readonly name: string;
 	readonly callback_url: string;
 	readonly icon: string;
+	readonly public_client?: boolean;
+	readonly client_credentials_user_id?: string;
 }
 
 // From codersdk/rbacresources_gen.go
@@ -1889,12 +2203,14 @@ export type ResourceType =
 	| "oauth2_provider_app_secret"
 	| "organization"
 	| "organization_member"
+	| "secret"
 	| "template"
 	| "template_version"
 	| "user"
 	| "workspace"
 	| "workspace_build"
-	| "workspace_proxy";
+	| "workspace_proxy"
+	| "workspace_session_recording";
 
 export const ResourceTypes: ResourceType[] = [
 	"api_key",
@@ -1913,12 +2229,14 @@ export const ResourceTypes: ResourceType[] = [
 	"oauth2_provider_app_secret",
 	"organization",
 	"organization_member",
+	"secret",
 	"template",
 	"template_version",
 	"user",
 	"workspace",
 	"workspace_build",
 	"workspace_proxy",
+	"workspace_session_recording",
 ];
 
 // From codersdk/client.go
@@ -1928,6 +2246,22 @@ export interface Response {
 	readonly validations?: readonly ValidationError[];
 }
 
+// From codersdk/deployment.go
+export interface RetentionConfig {
+	readonly audit_logs: number;
+	readonly workspace_agent_logs: number;
+	readonly provisioner_job_logs: number;
+	readonly workspace_build_states: number;
+	readonly notification_messages: number;
+	readonly workspace_agent_stats: number;
+}
+
+// From codersdk/workspacebuildapprovals.go
+export interface ReviewWorkspaceBuildApprovalRequest {
+	readonly approved: boolean;
+	readonly reason?: string;
+}
+
 // From codersdk/roles.go
 export interface Role {
 	readonly name: string;
@@ -1993,6 +2327,17 @@ export interface STUNReport {
 	readonly Error: string | null;
 }
 
+// From codersdk/secrets.go
+export interface Secret {
+	readonly id: string;
+	readonly name: string;
+	readonly description: string;
+	readonly env_name?: string;
+	readonly file_path?: string;
+	readonly created_at: string;
+	readonly updated_at: string;
+}
+
 // From serpent/serpent.go
 export type SerpentAnnotations = Record<string, string>;
 
@@ -2194,6 +2539,11 @@ export interface Template {
 	readonly time_til_dormant_autodelete_ms: number;
 	readonly require_active_version: boolean;
 	readonly max_port_share_level: WorkspaceAgentPortShareLevel;
+	readonly session_recording_enabled: boolean;
+	readonly require_build_approval: boolean;
+	readonly build_approval_parameters: readonly string[];
+	readonly build_approval_group_id?: string;
+	readonly build_approval_timeout_ms: number;
 }
 
 // From codersdk/templates.go
@@ -2335,6 +2685,40 @@ export type TemplateRole = "admin" | "" | "use";
 
 export const TemplateRoles: TemplateRole[] = ["admin", "", "use"];
 
+// From codersdk/templaterollouts.go
+export interface TemplateRollout {
+	readonly id: string;
+	readonly template_id: string;
+	readonly template_version_id: string;
+	readonly created_by: string;
+	readonly created_at: string;
+	readonly updated_at: string;
+	readonly completed_at?: string;
+	readonly status: TemplateRolloutStatus;
+	readonly status_reason: string;
+	readonly percent: number;
+	readonly group_id?: string;
+	readonly min_builds: number;
+	readonly max_failure_rate: number;
+	readonly auto_promote: boolean;
+	readonly builds: number;
+	readonly failed_builds: number;
+}
+
+// From codersdk/templaterollouts.go
+export type TemplateRolloutStatus =
+	| "canceled"
+	| "in_progress"
+	| "promoted"
+	| "rolled_back";
+
+export const TemplateRolloutStatuses: TemplateRolloutStatus[] = [
+	"canceled",
+	"in_progress",
+	"promoted",
+	"rolled_back",
+];
+
 // From codersdk/templates.go
 export interface TemplateUser extends User {
 	readonly role: TemplateRole;
@@ -2458,6 +2842,7 @@ export interface TraceConfig {
 	readonly honeycomb_api_key: string;
 	readonly capture_logs: boolean;
 	readonly data_dog: boolean;
+	readonly workspace_builds: boolean;
 }
 
 // From codersdk/templates.go
@@ -2504,6 +2889,14 @@ export interface UpdateOrganizationRequest {
 	readonly icon?: string;
 }
 
+// From codersdk/secrets.go
+export interface UpdateSecretRequest {
+	readonly description?: string;
+	readonly value?: string;
+	readonly env_name?: string;
+	readonly file_path?: string;
+}
+
 // From codersdk/users.go
 export interface UpdateRoles {
 	readonly roles: readonly string[];
@@ -2537,6 +2930,19 @@ export interface UpdateTemplateMeta {
 	readonly deprecation_message?: string;
 	readonly disable_everyone_group_access: boolean;
 	readonly max_port_share_level?: WorkspaceAgentPortShareLevel;
+	readonly session_recording_enabled?: boolean;
+	readonly require_build_approval?: boolean;
+	readonly build_approval_parameters?: readonly string[];
+	readonly build_approval_group_id?: string;
+	readonly build_approval_timeout_ms?: number;
+}
+
+// From codersdk/templaterollouts.go
+export interface UpdateTemplateRolloutRequest {
+	readonly percent?: number;
+	readonly group_id?: string;
+	readonly status?: TemplateRolloutStatus;
+	readonly reason?: string;
 }
 
 // From codersdk/users.go
@@ -2547,6 +2953,7 @@ export interface UpdateUserAppearanceSettingsRequest {
 // From codersdk/notifications.go
 export interface UpdateUserNotificationPreferences {
 	readonly template_disabled_map: Record<string, boolean>;
+	readonly template_method_map?: Record<string, string>;
 }
 
 // From codersdk/users.go
@@ -2602,6 +3009,13 @@ export interface UploadResponse {
 	readonly hash: string;
 }
 
+// From codersdk/costbudgets.go
+export interface UpsertCostBudgetRequest {
+	readonly monthly_limit: number;
+	readonly notify_threshold_percent: number;
+	readonly stop_threshold_percent: number;
+}
+
 // From codersdk/workspaceagentportshare.go
 export interface UpsertWorkspaceAgentPortShareRequest {
 	readonly agent_name: string;
@@ -2771,6 +3185,32 @@ export interface WebsocketReport extends BaseReport {
 	readonly code: number;
 }
 
+// From codersdk/workloadidentity.go
+export interface WorkloadIdentityJWK {
+	readonly kty: string;
+	readonly kid: string;
+	readonly use: string;
+	readonly alg: string;
+	readonly crv: string;
+	readonly x: string;
+	readonly y: string;
+}
+
+// From codersdk/workloadidentity.go
+export interface WorkloadIdentityJWKS {
+	readonly keys: readonly WorkloadIdentityJWK[];
+}
+
+// From codersdk/workloadidentity.go
+export interface WorkloadIdentityProviderMetadata {
+	readonly issuer: string;
+	readonly jwks_uri: string;
+	readonly response_types_supported: readonly string[];
+	readonly subject_types_supported: readonly string[];
+	readonly id_token_signing_alg_values_supported: readonly string[];
+	readonly claims_supported: readonly string[];
+}
+
 // From codersdk/workspaces.go
 export interface Workspace {
 	readonly id: string;
@@ -2839,6 +3279,41 @@ export interface WorkspaceAgent {
 	readonly startup_script_behavior: WorkspaceAgentStartupScriptBehavior;
 }
 
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentDevcontainer {
+	readonly name: string;
+	readonly local_folder: string;
+	readonly config_path: string;
+	readonly workspace_folder: string;
+	readonly container_id?: string;
+	readonly status: WorkspaceAgentDevcontainerStatus;
+	readonly error?: string;
+}
+
+// From codersdk/workspaceagents.go
+export type WorkspaceAgentDevcontainerStatus = "error" | "running" | "starting";
+
+export const WorkspaceAgentDevcontainerStatuses: WorkspaceAgentDevcontainerStatus[] = [
+	"error",
+	"running",
+	"starting",
+];
+
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentDevcontainersResponse {
+	readonly devcontainers: readonly WorkspaceAgentDevcontainer[];
+}
+
+// From codersdk/workspaceagentfiles.go
+export interface WorkspaceAgentFileInfo {
+	readonly name: string;
+	readonly path: string;
+	readonly size: number;
+	readonly mode: string;
+	readonly mod_time: string;
+	readonly is_dir: boolean;
+}
+
 // From codersdk/workspaceagents.go
 export interface WorkspaceAgentHealth {
 	readonly healthy: boolean;
@@ -2869,6 +3344,12 @@ export const WorkspaceAgentLifecycles: WorkspaceAgentLifecycle[] = [
 	"starting",
 ];
 
+// From codersdk/workspaceagentfiles.go
+export interface WorkspaceAgentListFilesResponse {
+	readonly path: string;
+	readonly files: readonly WorkspaceAgentFileInfo[];
+}
+
 // From codersdk/workspaceagents.go
 export interface WorkspaceAgentListeningPort {
 	readonly process_name: string;
@@ -2951,6 +3432,18 @@ export interface WorkspaceAgentPortShares {
 	readonly shares: readonly WorkspaceAgentPortShare[];
 }
 
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentProcessGroup {
+	readonly name: string;
+	readonly cpu_usage_usec: number;
+	readonly cpu_cores: number;
+	readonly cpu_limit_cores: number;
+	readonly memory_bytes: number;
+	readonly memory_limit_bytes: number;
+	readonly oom_kills: number;
+	readonly updated_at: string;
+}
+
 // From codersdk/workspaceagents.go
 export interface WorkspaceAgentScript {
 	readonly id: string;
@@ -3057,6 +3550,27 @@ export interface WorkspaceBuild {
 	readonly matched_provisioners?: MatchedProvisioners;
 }
 
+// From codersdk/workspacebuildapprovals.go
+export interface WorkspaceBuildApproval {
+	readonly workspace_build_id: string;
+	readonly workspace_id: string;
+	readonly status: WorkspaceBuildApprovalStatus;
+	readonly created_at: string;
+	readonly expires_at?: string;
+	readonly reviewer_id?: string;
+	readonly reviewed_at?: string;
+	readonly reason: string;
+}
+
+// From codersdk/workspacebuildapprovals.go
+export type WorkspaceBuildApprovalStatus = "approved" | "pending" | "rejected";
+
+export const WorkspaceBuildApprovalStatuses: WorkspaceBuildApprovalStatus[] = [
+	"approved",
+	"pending",
+	"rejected",
+];
+
 // From codersdk/workspacebuilds.go
 export interface WorkspaceBuildParameter {
 	readonly name: string;
@@ -3158,6 +3672,7 @@ export interface WorkspaceResource {
 	readonly agents?: readonly WorkspaceAgent[];
 	readonly metadata?: readonly WorkspaceResourceMetadata[];
 	readonly daily_cost: number;
+	readonly hourly_cost: number;
 }
 
 // From codersdk/workspacebuilds.go
@@ -3167,6 +3682,73 @@ export interface WorkspaceResourceMetadata {
 	readonly sensitive: boolean;
 }
 
+// From codersdk/workspacescheduledactions.go
+export interface WorkspaceScheduledAction {
+	readonly id: string;
+	readonly workspace_id: string;
+	readonly created_at: string;
+	readonly action: WorkspaceScheduledActionType;
+	readonly schedule: string;
+	readonly next_run_at: string;
+	readonly last_run_at?: string;
+}
+
+// From codersdk/workspacescheduledactions.go
+export type WorkspaceScheduledActionType =
+	| "restart"
+	| "start"
+	| "stop"
+	| "update";
+
+export const WorkspaceScheduledActionTypes: WorkspaceScheduledActionType[] = [
+	"restart",
+	"start",
+	"stop",
+	"update",
+];
+
+// From codersdk/workspacesessionrecordings.go
+export interface WorkspaceSessionRecording {
+	readonly id: string;
+	readonly workspace_id: string;
+	readonly agent_id: string;
+	readonly workspace_owner_id: string;
+	readonly type: WorkspaceSessionRecordingType;
+	readonly started_at: string;
+	readonly ended_at?: string;
+	readonly size: number;
+}
+
+// From codersdk/workspacesessionrecordings.go
+export type WorkspaceSessionRecordingType = "reconnecting_pty" | "ssh";
+
+export const WorkspaceSessionRecordingTypes: WorkspaceSessionRecordingType[] = [
+	"reconnecting_pty",
+	"ssh",
+];
+
+// From codersdk/workspacesnapshots.go
+export interface WorkspaceSnapshot {
+	readonly id: string;
+	readonly created_at: string;
+	readonly name: string;
+	readonly owner_id: string;
+	readonly template_id: string;
+	readonly workspace_id: string;
+	readonly build_id: string;
+	readonly status: WorkspaceSnapshotStatus;
+	readonly artifacts: Record<string, string>;
+}
+
+// From codersdk/workspacesnapshots.go
+export type WorkspaceSnapshotStatus = "failed" | "pending" | "ready";
+
+export const WorkspaceSnapshotStatuses: WorkspaceSnapshotStatus[] = [
+	"failed",
+	"pending",
+	"ready",
+];
+
 // From codersdk/workspacebuilds.go
 export type WorkspaceStatus =
 	| "canceled"
@@ -37,6 +37,7 @@ const nullDevice = {
 	hide: false,
 	icon: "",
 	daily_cost: 0,
+	hourly_cost: 0,
 } as const;
 
 const short = {
@@ -23,9 +23,11 @@ export const AuditLogDescription: FC<AuditLogDescriptionProps> = ({
 		target = "";
 	}
 
-	// This occurs when SCIM creates a user, or dormancy changes a users status.
+	// This occurs when SCIM creates a user or manages a group, or dormancy
+	// changes a users status.
 	if (
-		auditLog.resource_type === "user" &&
+		(auditLog.resource_type === "user" ||
+			auditLog.resource_type === "group") &&
 		auditLog.additional_fields?.automatic_actor === "coder"
 	) {
 		user = "Coder automatically";
@@ -112,7 +112,7 @@ export const mockNotificationsDeploymentOptions: SerpentOption[] = [
 	{
 		name: "Notifications: Method",
 		description:
-			"Which delivery method to use (available options: 'smtp', 'webhook').",
+			"Which delivery method to use (available options: 'smtp', 'webhook', 'slack', 'teams').",
 		flag: "notifications-method",
 		env: "CODER_NOTIFICATIONS_METHOD",
 		yaml: "method",
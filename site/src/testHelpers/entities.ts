@@ -790,6 +790,10 @@ export const MockTemplate: TypesGen.Template = {
 	deprecated: false,
 	deprecation_message: "",
 	max_port_share_level: "public",
+	session_recording_enabled: false,
+	require_build_approval: false,
+	build_approval_parameters: [],
+	build_approval_timeout_ms: 86400000,
 };
 
 export const MockTemplateVersionFiles: TemplateVersionFiles = {
@@ -1115,6 +1119,7 @@ export const MockWorkspaceResource: TypesGen.WorkspaceResource = {
 	icon: "",
 	metadata: [{ key: "size", value: "32GB", sensitive: false }],
 	daily_cost: 10,
+	hourly_cost: 0,
 };
 
 export const MockWorkspaceResourceSensitive: TypesGen.WorkspaceResource = {
@@ -1152,6 +1157,7 @@ export const MockWorkspaceVolumeResource: TypesGen.WorkspaceResource = {
 	hide: false,
 	icon: "",
 	daily_cost: 0,
+	hourly_cost: 0,
 };
 
 export const MockWorkspaceImageResource: TypesGen.WorkspaceResource = {
@@ -1164,6 +1170,7 @@ export const MockWorkspaceImageResource: TypesGen.WorkspaceResource = {
 	hide: false,
 	icon: "",
 	daily_cost: 0,
+	hourly_cost: 0,
 };
 
 export const MockWorkspaceContainerResource: TypesGen.WorkspaceResource = {
@@ -1176,6 +1183,7 @@ export const MockWorkspaceContainerResource: TypesGen.WorkspaceResource = {
 	hide: false,
 	icon: "",
 	daily_cost: 0,
+	hourly_cost: 0,
 };
 
 export const MockWorkspaceAutostartDisabled: TypesGen.UpdateWorkspaceAutostartRequest =
@@ -4003,10 +4011,11 @@ export const MockOAuth2ProviderApps: TypesGen.OAuth2ProviderApp[] = [
 		name: "foo",
 		callback_url: "http://localhost:3001",
 		icon: "/icon/github.svg",
+		public_client: false,
 		endpoints: {
 			authorization: "http://localhost:3001/oauth2/authorize",
 			token: "http://localhost:3001/oauth2/token",
-			device_authorization: "",
+			device_authorization: "http://localhost:3001/oauth2/device",
 		},
 	},
 ];
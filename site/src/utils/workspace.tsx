@@ -83,6 +83,7 @@ export const getDisplayWorkspaceBuildInitiatedBy = (
 			return build.initiator_name;
 		case "autostart":
 		case "autostop":
+		case "scheduled":
 			return "Coder";
 	}
 };
@@ -647,6 +647,26 @@ func (c *configMaps) fillPeerDiagnostics(d *PeerDiagnostics, peerID uuid.UUID) {
 	d.LastWireguardHandshake = ps.LastHandshake
 }
 
+// activePeers returns the address of each peer we completed a WireGuard
+// handshake with within the timeout, by their node ID.
+func (c *configMaps) activePeers(timeout time.Duration) map[tailcfg.NodeID]netip.Addr {
+	status := c.status()
+	c.L.Lock()
+	defer c.L.Unlock()
+	out := make(map[tailcfg.NodeID]netip.Addr)
+	for _, lc := range c.peers {
+		if lc.node == nil || len(lc.node.Addresses) == 0 {
+			continue
+		}
+		ps, ok := status.Peer[lc.node.Key]
+		if !ok || c.clock.Since(ps.LastHandshake) > timeout {
+			continue
+		}
+		out[lc.node.ID] = lc.node.Addresses[0].Addr()
+	}
+	return out
+}
+
 func (c *configMaps) knownPeerIDs() []uuid.UUID {
 	c.L.Lock()
 	defer c.L.Unlock()
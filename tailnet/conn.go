@@ -52,6 +52,7 @@ const (
 	WorkspaceAgentSSHPort             = 1
 	WorkspaceAgentReconnectingPTYPort = 2
 	WorkspaceAgentSpeedtestPort       = 3
+	WorkspaceAgentContainerSSHPort    = 5
 )
 
 // EnvMagicsockDebugLogging enables super-verbose logging for the magicsock
@@ -109,6 +110,10 @@ type Options struct {
 	ClientType proto.TelemetryEvent_ClientType
 	// TelemetrySink is optional.
 	TelemetrySink TelemetrySink
+	// NetworkReportInterval is how often the latency to each DERP region and
+	// the type of the connection to each active peer are sent to the
+	// TelemetrySink. Periodic network reports are disabled if zero.
+	NetworkReportInterval time.Duration
 	// DNSConfigurator is optional, and is passed to the underlying wireguard
 	// engine.
 	DNSConfigurator dns.OSConfigurator
@@ -300,6 +305,7 @@ func NewConn(options *Options) (conn *Conn, err error) {
 		wireguardEngine: wireguardEngine,
 		configMaps:      cfgMaps,
 		nodeUpdater:     nodeUp,
+		clientType:      options.ClientType,
 		telemetrySink:   options.TelemetrySink,
 		dnsConfigurator: options.DNSConfigurator,
 		telemetryStore:  telemetryStore,
@@ -326,6 +332,9 @@ func NewConn(options *Options) (conn *Conn, err error) {
 			server.telemetryStore.pingPeer(server)
 		})
 		go server.watchConnChange()
+		if options.NetworkReportInterval > 0 {
+			go server.watchNetworkReports(options.NetworkReportInterval)
+		}
 	} else {
 		server.wireguardEngine.SetNetInfoCallback(func(ni *tailcfg.NetInfo) {
 			server.mutex.Lock()
@@ -740,7 +749,7 @@ func (c *Conn) forwardTCP(src, dst netip.AddrPort) (handler func(net.Conn), opts
 		return nil, nil, false
 	}
 	// See: https://github.com/tailscale/tailscale/blob/c7cea825aea39a00aca71ea02bab7266afc03e7c/wgengine/netstack/netstack.go#L888
-	if dst.Port() == WorkspaceAgentSSHPort || dst.Port() == 22 {
+	if dst.Port() == WorkspaceAgentSSHPort || dst.Port() == WorkspaceAgentContainerSSHPort || dst.Port() == 22 {
 		opt := tcpip.KeepaliveIdleOption(72 * time.Hour)
 		opts = append(opts, &opt)
 	}
@@ -833,7 +842,12 @@ func (c *Conn) sendPingTelemetry(pr *ipnstate.PingResult) {
 		return
 	}
 	e := c.newTelemetryEvent()
+	c.setPingTelemetry(e, pr)
+	e.Status = proto.TelemetryEvent_CONNECTED
+	c.sendTelemetryBackground(e)
+}
 
+func (c *Conn) setPingTelemetry(e *proto.TelemetryEvent, pr *ipnstate.PingResult) {
 	latency := durationpb.New(time.Duration(pr.LatencySeconds * float64(time.Second)))
 	if pr.Endpoint != "" {
 		e.P2PLatency = latency
@@ -841,8 +855,62 @@ func (c *Conn) sendPingTelemetry(pr *ipnstate.PingResult) {
 	} else {
 		e.DerpLatency = latency
 	}
-	e.Status = proto.TelemetryEvent_CONNECTED
-	c.sendTelemetryBackground(e)
+}
+
+const (
+	// networkReportPeerTimeout is how recently a WireGuard handshake with a
+	// peer must have completed for it to be included in network reports.
+	// Handshakes happen at least every 2 minutes while traffic flows.
+	networkReportPeerTimeout = 3 * time.Minute
+	networkReportPingTimeout = 5 * time.Second
+)
+
+// Periodically send network reports, so the latency to each DERP region and
+// the type of the connections of long-lived clients, like agents, are known.
+func (c *Conn) watchNetworkReports(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.watchCtx.Done():
+			return
+		case <-ticker.C:
+		}
+		c.sendNetworkReportTelemetry(c.watchCtx)
+	}
+}
+
+// sendNetworkReportTelemetry pings each active peer, and sends an event with
+// the latest netcheck and the type of the connection for each of them. An
+// event without a peer is sent if there are no active peers, so the latency
+// of idle clients is reported too.
+func (c *Conn) sendNetworkReportTelemetry(ctx context.Context) {
+	if c.telemetrySink == nil {
+		return
+	}
+	peers := c.configMaps.activePeers(networkReportPeerTimeout)
+	if len(peers) == 0 {
+		e := c.newTelemetryEvent()
+		e.NodeIdRemote = 0
+		e.Status = proto.TelemetryEvent_CONNECTED
+		c.sendTelemetryBackground(e)
+		return
+	}
+	for nodeID, ip := range peers {
+		pingCtx, cancel := context.WithTimeout(ctx, networkReportPingTimeout)
+		_, _, pr, err := c.pingWithType(pingCtx, ip, tailcfg.PingDisco)
+		cancel()
+		if err != nil {
+			c.logger.Debug(ctx, "failed to ping peer for network report",
+				slog.F("node_id", nodeID), slog.Error(err))
+			continue
+		}
+		e := c.newTelemetryEvent()
+		e.NodeIdRemote = uint64(nodeID) //nolint:gosec // NodeIDs are always positive.
+		c.setPingTelemetry(e, pr)
+		e.Status = proto.TelemetryEvent_CONNECTED
+		c.sendTelemetryBackground(e)
+	}
 }
 
 // The returned telemetry event will not have it's status set.
@@ -100,6 +100,51 @@ func TestTailnet(t *testing.T) {
 		w2.Close()
 	})
 
+	t.Run("NetworkReports", func(t *testing.T) {
+		t.Parallel()
+		logger := testutil.Logger(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		sink := &chanTelemetrySink{events: make(chan *proto.TelemetryEvent, 16)}
+
+		w1IP := tailnet.TailscaleServicePrefix.RandomAddr()
+		w1, err := tailnet.NewConn(&tailnet.Options{
+			Addresses:             []netip.Prefix{netip.PrefixFrom(w1IP, 128)},
+			Logger:                logger.Named("w1"),
+			DERPMap:               derpMap,
+			ClientType:            proto.TelemetryEvent_AGENT,
+			TelemetrySink:         sink,
+			NetworkReportInterval: testutil.IntervalFast,
+		})
+		require.NoError(t, err)
+
+		w2, err := tailnet.NewConn(&tailnet.Options{
+			Addresses: []netip.Prefix{tailnet.TailscaleServicePrefix.RandomPrefix()},
+			Logger:    logger.Named("w2"),
+			DERPMap:   derpMap,
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			_ = w1.Close()
+			_ = w2.Close()
+		})
+		stitch(t, w2, w1)
+		stitch(t, w1, w2)
+		require.True(t, w2.AwaitReachable(ctx, w1IP))
+
+		// Once the peers completed a handshake, the connection to w2 is
+		// reported along with the latest netcheck of w1.
+		for {
+			event := testutil.RequireRecvCtx(ctx, t, sink.events)
+			require.Equal(t, proto.TelemetryEvent_AGENT, event.ClientType)
+			if event.NodeIdRemote == 0 {
+				continue
+			}
+			require.Equal(t, uint64(w2.Node().ID), event.NodeIdRemote)
+			require.True(t, event.P2PLatency != nil || event.DerpLatency != nil)
+			break
+		}
+	})
+
 	t.Run("ForcesWebSockets", func(t *testing.T) {
 		t.Parallel()
 		logger := testutil.Logger(t)
@@ -524,3 +569,14 @@ func TestCoderServicePrefix(t *testing.T) {
 	p = tailnet.CoderServicePrefix.PrefixFromUUID(u)
 	require.Equal(t, "fd60:627a:a42b:aaaa:aaaa:1234:5678:9abc/128", p.String())
 }
+
+type chanTelemetrySink struct {
+	events chan *proto.TelemetryEvent
+}
+
+func (s *chanTelemetrySink) SendTelemetryEvent(event *proto.TelemetryEvent) {
+	select {
+	case s.events <- event:
+	default:
+	}
+}
@@ -38,9 +38,20 @@ import (
 //     shipped in Coder v2.16.0, but we forgot to increment the API version. If
 //     you dial for API v2.2, you MAY be connected to a server that supports
 //     ScriptCompleted, but be prepared to process "unsupported" errors.)
+//
+// API v2.4:
+//   - Added support for session recordings via the UploadSessionRecording RPC
+//     on the Agent API, and the session_recording_enabled field of the
+//     Manifest.
+//   - Added the process_groups field to the Stats reported by the agent. Older
+//     servers ignore it.
+//   - Added the secrets field of the Manifest, which older agents ignore.
+//   - Added support for workload identity tokens via the
+//     GetWorkloadIdentityToken RPC on the Agent API.
+//   - No changes to the Tailnet API.
 const (
 	CurrentMajor = 2
-	CurrentMinor = 3
+	CurrentMinor = 4
 )
 
 var CurrentVersion = apiversion.New(CurrentMajor, CurrentMinor)
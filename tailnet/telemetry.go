@@ -25,6 +25,10 @@ const (
 	TelemetryApplicationVSCode    string = "vscode"
 )
 
+// DefaultNetworkReportInterval is how often long-lived clients, like agents and
+// the CLI, send network reports to coderd.
+const DefaultNetworkReportInterval = 5 * time.Minute
+
 // Responsible for storing and anonymizing networking telemetry state.
 type TelemetryStore struct {
 	mu       sync.Mutex